@@ -4,23 +4,36 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"github.com/dotandev/hintents/internal/cmd"
+	"github.com/dotandev/hintents/internal/crash"
+	"github.com/dotandev/hintents/internal/session"
 	"github.com/dotandev/hintents/internal/updater"
 )
 
 var Version = "dev"
 
 func main() {
+	// Write a crash report instead of a raw panic
+	defer crash.Recover()
+
 	// Set version in cmd package
 	cmd.Version = Version
+	crash.Version = Version
 
 	// Start update checker in background (non-blocking)
 	checker := updater.NewChecker(Version)
 	go checker.CheckForUpdates()
 
-	if err := cmd.Execute(); err != nil {
+	if err := cmd.Execute(session.NewManager(session.StatePath())); err != nil {
+		var exit *cmd.ExitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.Code)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,186 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package alert pages on-call responders through PagerDuty or Opsgenie
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultPagerDutyEndpoint is the PagerDuty Events API v2
+	DefaultPagerDutyEndpoint = "https://events.pagerduty.com/v2/enqueue"
+	// DefaultOpsgenieEndpoint is the Opsgenie Alert API
+	DefaultOpsgenieEndpoint = "https://api.opsgenie.com/v2/alerts"
+
+	defaultTimeout = 30 * time.Second
+	maxErrorBody   = 4 << 10
+)
+
+// Severity is how urgent an alert is
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityError    Severity = "error"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+)
+
+// Alert is one alert. Alerts with the same DedupKey are grouped into one
+// incident by the provider.
+type Alert struct {
+	DedupKey string
+	Summary  string
+	Severity Severity
+	// Source names what the alert is about, such as the watch target
+	Source  string
+	Details map[string]string
+}
+
+// Alerter sends alerts to a provider
+type Alerter interface {
+	Name() string
+	Send(ctx context.Context, a Alert) error
+}
+
+// Providers lists the supported provider names
+var Providers = []string{"pagerduty", "opsgenie"}
+
+// PagerDuty sends alerts as PagerDuty Events API v2 trigger events
+type PagerDuty struct {
+	name       string
+	routingKey string
+	endpoint   string
+	client     *http.Client
+}
+
+// NewPagerDuty returns an alerter called name for the integration with
+// routingKey. An empty endpoint means DefaultPagerDutyEndpoint.
+func NewPagerDuty(name, routingKey, endpoint string) (*PagerDuty, error) {
+	if routingKey == "" {
+		return nil, fmt.Errorf("alert %s: a PagerDuty routing key is required", name)
+	}
+	if endpoint == "" {
+		endpoint = DefaultPagerDutyEndpoint
+	}
+	return &PagerDuty{name: name, routingKey: routingKey, endpoint: endpoint, client: &http.Client{Timeout: defaultTimeout}}, nil
+}
+
+// Name returns the configured name of the alerter
+func (p *PagerDuty) Name() string { return p.name }
+
+// Send triggers an event deduplicated by a.DedupKey
+func (p *PagerDuty) Send(ctx context.Context, a Alert) error {
+	body := map[string]any{
+		"routing_key":  p.routingKey,
+		"event_action": "trigger",
+		"dedup_key":    a.DedupKey,
+		"payload": map[string]any{
+			"summary":        truncate(a.Summary, 1024),
+			"source":         a.Source,
+			"severity":       string(a.Severity),
+			"component":      "erst",
+			"custom_details": a.Details,
+		},
+	}
+	return post(ctx, p.client, "pagerduty", p.endpoint, nil, body)
+}
+
+// Opsgenie creates Opsgenie alerts
+type Opsgenie struct {
+	name     string
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+// NewOpsgenie returns an alerter called name using the API key of an
+// Opsgenie integration. An empty endpoint means DefaultOpsgenieEndpoint;
+// accounts in the EU use https://api.eu.opsgenie.com/v2/alerts.
+func NewOpsgenie(name, apiKey, endpoint string) (*Opsgenie, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("alert %s: an Opsgenie API key is required", name)
+	}
+	if endpoint == "" {
+		endpoint = DefaultOpsgenieEndpoint
+	}
+	return &Opsgenie{name: name, apiKey: apiKey, endpoint: endpoint, client: &http.Client{Timeout: defaultTimeout}}, nil
+}
+
+// Name returns the configured name of the alerter
+func (o *Opsgenie) Name() string { return o.name }
+
+// Send creates an alert whose alias is a.DedupKey, so Opsgenie counts
+// repeats instead of opening new alerts
+func (o *Opsgenie) Send(ctx context.Context, a Alert) error {
+	body := map[string]any{
+		"message":     truncate(a.Summary, 130),
+		"alias":       truncate(a.DedupKey, 512),
+		"description": a.Summary,
+		"source":      a.Source,
+		"priority":    opsgeniePriority(a.Severity),
+		"details":     a.Details,
+		"tags":        []string{"erst"},
+	}
+	header := http.Header{"Authorization": []string{"GenieKey " + o.apiKey}}
+	return post(ctx, o.client, "opsgenie", o.endpoint, header, body)
+}
+
+func opsgeniePriority(s Severity) string {
+	switch s {
+	case SeverityCritical:
+		return "P1"
+	case SeverityError:
+		return "P2"
+	case SeverityWarning:
+		return "P3"
+	default:
+		return "P5"
+	}
+}
+
+// post sends body as JSON to url
+func post(ctx context.Context, client *http.Client, provider, url string, header http.Header, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("%s: failed to encode alert: %w", provider, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", provider, err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if s := strings.TrimSpace(string(msg)); s != "" {
+		return fmt.Errorf("%s: %s: %s", provider, resp.Status, s)
+	}
+	return fmt.Errorf("%s: %s", provider, resp.Status)
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testAlert = Alert{
+	DedupKey: "erst/swap/drift/abc",
+	Summary:  "canary swap result changed: success -> error",
+	Severity: SeverityError,
+	Source:   "swap",
+	Details:  map[string]string{"network": "testnet"},
+}
+
+func capture(t *testing.T, status int, check func(r *http.Request)) (*httptest.Server, *map[string]any) {
+	t.Helper()
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		check(r)
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &body
+}
+
+func TestPagerDuty(t *testing.T) {
+	srv, body := capture(t, http.StatusAccepted, func(r *http.Request) {})
+	p, err := NewPagerDuty("oncall", "R0UT1NG", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Send(context.Background(), testAlert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := *body
+	if b["routing_key"] != "R0UT1NG" || b["event_action"] != "trigger" || b["dedup_key"] != testAlert.DedupKey {
+		t.Errorf("unexpected event %v", b)
+	}
+	payload := b["payload"].(map[string]any)
+	if payload["severity"] != "error" || payload["source"] != "swap" || payload["custom_details"].(map[string]any)["network"] != "testnet" {
+		t.Errorf("unexpected payload %v", payload)
+	}
+
+	if _, err := NewPagerDuty("oncall", "", ""); err == nil {
+		t.Error("expected a missing routing key to be rejected")
+	}
+}
+
+func TestOpsgenie(t *testing.T) {
+	srv, body := capture(t, http.StatusAccepted, func(r *http.Request) {
+		if r.Header.Get("Authorization") != "GenieKey k3y" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+	})
+	o, err := NewOpsgenie("ops", "k3y", srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Send(context.Background(), testAlert); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b := *body
+	if b["alias"] != testAlert.DedupKey || b["priority"] != "P2" || b["message"] != testAlert.Summary {
+		t.Errorf("unexpected alert %v", b)
+	}
+}
+
+func TestSendError(t *testing.T) {
+	srv, _ := capture(t, http.StatusBadRequest, func(r *http.Request) {})
+	p, _ := NewPagerDuty("oncall", "key", srv.URL)
+	if err := p.Send(context.Background(), testAlert); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("expected the status in the error, got %v", err)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate(strings.Repeat("a", 200), 130); len(got) != 130 || !strings.HasSuffix(got, "...") {
+		t.Errorf("unexpected truncation %q", got)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// KeyContention is how the transactions of a sample declared one ledger
+// entry in their footprints
+type KeyContention struct {
+	Key    string `json:"key"`
+	KeyXdr string `json:"key_xdr"`
+	// Readers and Writers count transactions declaring the entry read-only
+	// and read-write
+	Readers int `json:"readers"`
+	Writers int `json:"writers"`
+	// Written counts transactions whose metadata shows a write
+	Written int `json:"written"`
+	// Failed counts failed transactions declaring the entry
+	Failed int `json:"failed"`
+	// ContendedLedgers counts ledgers where more than one transaction
+	// declared the entry read-write, so they could not run in parallel
+	ContendedLedgers int `json:"contended_ledgers"`
+	// MaxWritersPerLedger is the most read-write declarations in one ledger
+	MaxWritersPerLedger int    `json:"max_writers_per_ledger"`
+	Suggestion          string `json:"suggestion,omitempty"`
+}
+
+// ContentionReport ranks the entries declared by a sample of transactions,
+// most contended first
+type ContentionReport struct {
+	Contract     string          `json:"contract,omitempty"`
+	FromLedger   uint32          `json:"from_ledger,omitempty"`
+	ToLedger     uint32          `json:"to_ledger,omitempty"`
+	Transactions int             `json:"transactions"`
+	Keys         []KeyContention `json:"keys"`
+}
+
+// AnalyzeContention aggregates the footprints of txs. Entries declared by a
+// single transaction are left out.
+func AnalyzeContention(txs []StateTx) (*ContentionReport, error) {
+	report := &ContentionReport{Transactions: len(txs), Keys: []KeyContention{}}
+
+	type keyStats struct {
+		key     xdr.LedgerKey
+		stats   KeyContention
+		writers map[uint32]int
+	}
+	keys := map[string]*keyStats{}
+	get := func(encoded string, key xdr.LedgerKey) *keyStats {
+		ks, ok := keys[encoded]
+		if !ok {
+			ks = &keyStats{key: key, stats: KeyContention{Key: DescribeLedgerKey(key), KeyXdr: encoded}, writers: map[uint32]int{}}
+			keys[encoded] = ks
+		}
+		return ks
+	}
+
+	for _, tx := range txs {
+		var env xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(tx.EnvelopeXdr, &env); err != nil {
+			return nil, fmt.Errorf("transaction %s: failed to decode envelope: %w", tx.Hash, err)
+		}
+		var footprint xdr.LedgerFootprint
+		if data := txmeta.SorobanData(env); data != nil {
+			footprint = data.Resources.Footprint
+		}
+		readOnly, err := keySet(footprint.ReadOnly)
+		if err != nil {
+			return nil, err
+		}
+		readWrite, err := keySet(footprint.ReadWrite)
+		if err != nil {
+			return nil, err
+		}
+		written := map[string]xdr.LedgerKey{}
+		if tx.ResultMetaXdr != "" {
+			meta, err := decodeTransactionMeta(tx.ResultMetaXdr)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %s: %w", tx.Hash, err)
+			}
+			written = writtenKeys(txmeta.OperationChanges(meta))
+		}
+
+		for encoded, key := range readOnly {
+			ks := get(encoded, key)
+			ks.stats.Readers++
+			if tx.Failed {
+				ks.stats.Failed++
+			}
+		}
+		for encoded, key := range readWrite {
+			ks := get(encoded, key)
+			ks.stats.Writers++
+			ks.writers[tx.Ledger]++
+			if _, ok := written[encoded]; ok {
+				ks.stats.Written++
+			}
+			if tx.Failed {
+				ks.stats.Failed++
+			}
+		}
+	}
+
+	for _, ks := range keys {
+		s := ks.stats
+		if s.Readers+s.Writers < 2 {
+			continue
+		}
+		for _, n := range ks.writers {
+			if n > 1 {
+				s.ContendedLedgers++
+			}
+			s.MaxWritersPerLedger = max(s.MaxWritersPerLedger, n)
+		}
+		s.Suggestion = contentionSuggestion(ks.key, s)
+		report.Keys = append(report.Keys, s)
+	}
+	sort.Slice(report.Keys, func(i, j int) bool {
+		a, b := report.Keys[i], report.Keys[j]
+		switch {
+		case a.ContendedLedgers != b.ContendedLedgers:
+			return a.ContendedLedgers > b.ContendedLedgers
+		case a.Writers != b.Writers:
+			return a.Writers > b.Writers
+		case a.Readers != b.Readers:
+			return a.Readers > b.Readers
+		}
+		return a.KeyXdr < b.KeyXdr
+	})
+	return report, nil
+}
+
+// contentionSuggestion advises how to shard an entry written by several
+// transactions in the same ledgers
+func contentionSuggestion(key xdr.LedgerKey, s KeyContention) string {
+	if s.ContendedLedgers == 0 {
+		return ""
+	}
+	switch key.Type {
+	case xdr.LedgerEntryTypeContractData:
+		if key.ContractData.Key.Type == xdr.ScValTypeScvLedgerKeyContractInstance {
+			return "instance storage is shared by every call; move frequently updated values to their own persistent or temporary keys"
+		}
+		return "one key written by many transactions; split it per account or into buckets, or aggregate updates off-chain"
+	case xdr.LedgerEntryTypeAccount, xdr.LedgerEntryTypeTrustline:
+		return "one account's balance is written by many transactions; spread the flow over several accounts"
+	}
+	return ""
+}
+
+// FootprintTouchesContract reports whether a transaction declares any
+// storage of contract, i.e. calls it directly or through another contract
+func FootprintTouchesContract(envelopeXdr, contract string) bool {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return false
+	}
+	data := txmeta.SorobanData(env)
+	if data == nil {
+		return false
+	}
+	footprint := data.Resources.Footprint
+	for _, key := range append(append([]xdr.LedgerKey{}, footprint.ReadOnly...), footprint.ReadWrite...) {
+		if key.Type != xdr.LedgerEntryTypeContractData {
+			continue
+		}
+		if addr, err := key.ContractData.Contract.String(); err == nil && addr == contract {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var contentionContract = xdr.ContractId{5}
+
+func contractDataKey(key xdr.ScVal) xdr.LedgerKey {
+	return xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contentionContract},
+		Key:        key,
+		Durability: xdr.ContractDataDurabilityPersistent,
+	}}
+}
+
+func symbolKey(s string) xdr.LedgerKey {
+	sym := xdr.ScSymbol(s)
+	return contractDataKey(xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+}
+
+func TestAnalyzeContention(t *testing.T) {
+	instance := contractDataKey(xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance})
+	counter := symbolKey("Counter")
+	counterEntry := &xdr.LedgerEntry{Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.ContractDataEntry{
+		Contract: counter.ContractData.Contract, Key: counter.ContractData.Key, Durability: counter.ContractData.Durability,
+		Val: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+	}}}
+
+	report, err := AnalyzeContention([]StateTx{
+		{Hash: "a", Ledger: 10, EnvelopeXdr: invokeEnvelope(t, []xdr.LedgerKey{instance}, []xdr.LedgerKey{counter}),
+			ResultMetaXdr: opMeta(t, xdr.LedgerEntryChanges{{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: counterEntry}})},
+		{Hash: "b", Ledger: 10, Failed: true, EnvelopeXdr: invokeEnvelope(t, []xdr.LedgerKey{instance}, []xdr.LedgerKey{counter})},
+		{Hash: "c", Ledger: 11, EnvelopeXdr: invokeEnvelope(t, []xdr.LedgerKey{instance}, []xdr.LedgerKey{symbolKey("Balance")})},
+		{Hash: "d", Ledger: 11, EnvelopeXdr: invokeEnvelope(t, []xdr.LedgerKey{codeKey(1)}, nil)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Transactions)
+	require.Len(t, report.Keys, 2, "entries declared once are left out")
+
+	hot := report.Keys[0]
+	assert.Equal(t, DescribeLedgerKey(counter), hot.Key)
+	assert.Equal(t, 2, hot.Writers)
+	assert.Equal(t, 1, hot.Written, "the failed transaction wrote nothing")
+	assert.Equal(t, 1, hot.Failed)
+	assert.Equal(t, 1, hot.ContendedLedgers)
+	assert.Equal(t, 2, hot.MaxWritersPerLedger)
+	assert.Contains(t, hot.Suggestion, "split it per account")
+
+	shared := report.Keys[1]
+	assert.Equal(t, DescribeLedgerKey(instance), shared.Key)
+	assert.Equal(t, 3, shared.Readers)
+	assert.Zero(t, shared.ContendedLedgers)
+	assert.Empty(t, shared.Suggestion, "read-only sharing does not conflict")
+
+	_, err = AnalyzeContention([]StateTx{{Hash: "e", EnvelopeXdr: "not xdr"}})
+	assert.ErrorContains(t, err, "transaction e")
+}
+
+func TestContentionSuggestion(t *testing.T) {
+	instance := contractDataKey(xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance})
+	assert.Contains(t, contentionSuggestion(instance, KeyContention{ContendedLedgers: 1}), "instance storage")
+	assert.Empty(t, contentionSuggestion(codeKey(1), KeyContention{ContendedLedgers: 1}))
+}
+
+func TestFootprintTouchesContract(t *testing.T) {
+	contract := strkey.MustEncode(strkey.VersionByteContract, contentionContract[:])
+	assert.True(t, FootprintTouchesContract(invokeEnvelope(t, nil, []xdr.LedgerKey{symbolKey("Counter")}), contract))
+	assert.False(t, FootprintTouchesContract(invokeEnvelope(t, []xdr.LedgerKey{codeKey(1)}, nil), contract))
+	assert.False(t, FootprintTouchesContract("not xdr", contract))
+}
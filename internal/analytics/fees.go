@@ -0,0 +1,281 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// FeeConfig holds the network's Soroban resource fee rates in stroops
+type FeeConfig struct {
+	// FeePerInstructionsIncrement is charged per 10,000 instructions
+	FeePerInstructionsIncrement int64 `json:"fee_per_instructions_increment"`
+	FeeReadLedgerEntry          int64 `json:"fee_read_ledger_entry"`
+	FeeWriteLedgerEntry         int64 `json:"fee_write_ledger_entry"`
+	FeeRead1KB                  int64 `json:"fee_read_1kb"`
+	FeeWrite1KB                 int64 `json:"fee_write_1kb"`
+	FeeHistorical1KB            int64 `json:"fee_historical_1kb"`
+	FeeTxSize1KB                int64 `json:"fee_tx_size_1kb"`
+	FeeContractEvents1KB        int64 `json:"fee_contract_events_1kb"`
+}
+
+// DefaultFeeConfig approximates the public network rates at the time of
+// writing. Use FeeConfigFromLedgerEntries for the live values.
+var DefaultFeeConfig = FeeConfig{
+	FeePerInstructionsIncrement: 25,
+	FeeReadLedgerEntry:          6250,
+	FeeWriteLedgerEntry:         10000,
+	FeeRead1KB:                  1786,
+	FeeWrite1KB:                 3500,
+	FeeHistorical1KB:            16235,
+	FeeTxSize1KB:                1624,
+	FeeContractEvents1KB:        10000,
+}
+
+// txBaseResultSize is added to the transaction size for the historical
+// data fee, covering the stored result
+const txBaseResultSize = 300
+
+const instructionsIncrement = 10000
+
+// LoadFeeConfig reads fee rates from a JSON file
+func LoadFeeConfig(path string) (FeeConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FeeConfig{}, fmt.Errorf("failed to read fee config: %w", err)
+	}
+	cfg := DefaultFeeConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return FeeConfig{}, fmt.Errorf("failed to parse fee config: %w", err)
+	}
+	return cfg, nil
+}
+
+// FeeConfigKeys returns the base64 ledger keys of the config settings that
+// hold fee rates, for fetching with getLedgerEntries
+func FeeConfigKeys() ([]string, error) {
+	ids := []xdr.ConfigSettingId{
+		xdr.ConfigSettingIdConfigSettingContractComputeV0,
+		xdr.ConfigSettingIdConfigSettingContractLedgerCostV0,
+		xdr.ConfigSettingIdConfigSettingContractHistoricalDataV0,
+		xdr.ConfigSettingIdConfigSettingContractEventsV0,
+		xdr.ConfigSettingIdConfigSettingContractBandwidthV0,
+		xdr.ConfigSettingIdConfigSettingContractLedgerCostExtV0,
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		key := xdr.LedgerKey{Type: xdr.LedgerEntryTypeConfigSetting, ConfigSetting: &xdr.LedgerKeyConfigSetting{ConfigSettingId: id}}
+		s, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode config setting key: %w", err)
+		}
+		keys = append(keys, s)
+	}
+	return keys, nil
+}
+
+// FeeConfigFromLedgerEntries overlays the rates found in fetched config
+// setting entries (base64 LedgerEntryData or LedgerEntry) on the defaults
+func FeeConfigFromLedgerEntries(entries map[string]string) FeeConfig {
+	cfg := DefaultFeeConfig
+	for _, raw := range entries {
+		var data xdr.LedgerEntryData
+		if err := xdr.SafeUnmarshalBase64(raw, &data); err != nil {
+			var entry xdr.LedgerEntry
+			if err := xdr.SafeUnmarshalBase64(raw, &entry); err != nil {
+				continue
+			}
+			data = entry.Data
+		}
+		cs, ok := data.GetConfigSetting()
+		if !ok {
+			continue
+		}
+		switch {
+		case cs.ContractCompute != nil:
+			cfg.FeePerInstructionsIncrement = int64(cs.ContractCompute.FeeRatePerInstructionsIncrement)
+		case cs.ContractLedgerCost != nil:
+			cfg.FeeReadLedgerEntry = int64(cs.ContractLedgerCost.FeeDiskReadLedgerEntry)
+			cfg.FeeWriteLedgerEntry = int64(cs.ContractLedgerCost.FeeWriteLedgerEntry)
+			cfg.FeeRead1KB = int64(cs.ContractLedgerCost.FeeDiskRead1Kb)
+		case cs.ContractLedgerCostExt != nil:
+			cfg.FeeWrite1KB = int64(cs.ContractLedgerCostExt.FeeWrite1Kb)
+		case cs.ContractHistoricalData != nil:
+			cfg.FeeHistorical1KB = int64(cs.ContractHistoricalData.FeeHistorical1Kb)
+		case cs.ContractEvents != nil:
+			cfg.FeeContractEvents1KB = int64(cs.ContractEvents.FeeContractEvents1Kb)
+		case cs.ContractBandwidth != nil:
+			cfg.FeeTxSize1KB = int64(cs.ContractBandwidth.FeeTxSize1Kb)
+		}
+	}
+	return cfg
+}
+
+// FeeComponent is one line of a fee breakdown. Declared is the fee of the
+// resources declared in the envelope, -1 for refundable components, which
+// share one declared budget. Required is the fee the simulated execution
+// needs for the same component, -1 when unknown.
+type FeeComponent struct {
+	Name       string `json:"name"`
+	Declared   int64  `json:"declared"`
+	Required   int64  `json:"required"`
+	Refundable bool   `json:"refundable,omitempty"`
+}
+
+// FeeBreakdown decomposes the fee of a Soroban transaction
+type FeeBreakdown struct {
+	// MaxFee is the fee bid in the envelope (inclusion fee plus the
+	// declared resource fee)
+	MaxFee int64 `json:"max_fee"`
+	// Charged is the fee actually charged, -1 when no result is available
+	Charged int64 `json:"charged"`
+	// DeclaredResourceFee is the resource fee set in the transaction data
+	DeclaredResourceFee int64 `json:"declared_resource_fee"`
+	// InclusionFeeBid is MaxFee minus the declared resource fee
+	InclusionFeeBid int64 `json:"inclusion_fee_bid"`
+	// InclusionFeeCharged, NonRefundableCharged, RefundableCharged and
+	// RentCharged come from the result metadata; -1 when unavailable
+	InclusionFeeCharged  int64 `json:"inclusion_fee_charged"`
+	NonRefundableCharged int64 `json:"non_refundable_charged"`
+	RefundableCharged    int64 `json:"refundable_charged"`
+	RentCharged          int64 `json:"rent_charged"`
+	// Components estimate the resource fee from the declared resources
+	// and the fee rates, and the fee the simulated usage needs
+	Components []FeeComponent `json:"components"`
+}
+
+// EstimatedNonRefundable sums the declared non-refundable component
+// estimates
+func (b *FeeBreakdown) EstimatedNonRefundable() int64 {
+	var total int64
+	for _, c := range b.Components {
+		if !c.Refundable {
+			total += c.Declared
+		}
+	}
+	return total
+}
+
+// SimulatedUsage is the resource usage observed when replaying the
+// transaction, as reported by the host's core metrics. Zero fields are
+// treated as unknown.
+type SimulatedUsage struct {
+	Instructions uint64
+	ReadEntries  uint64
+	WriteEntries uint64
+	ReadBytes    uint64
+	WriteBytes   uint64
+	EventBytes   uint64
+}
+
+// required returns the fee of a simulated amount, or -1 when the amount is
+// unknown
+func required(amount uint64, rate, unit int64) int64 {
+	if amount == 0 {
+		return -1
+	}
+	return feeFor(int64(amount), rate, unit)
+}
+
+// BreakdownFees decomposes the fee of a Soroban transaction. resultXdr and
+// resultMetaXdr may be empty for transactions not yet on chain. It returns
+// nil for classic transactions.
+func BreakdownFees(envelopeXdr, resultXdr, resultMetaXdr string, cfg FeeConfig, sim SimulatedUsage) (*FeeBreakdown, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	data := txmeta.SorobanData(env)
+	if data == nil {
+		return nil, nil
+	}
+	raw, err := env.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	txSize := int64(len(raw))
+
+	b := &FeeBreakdown{
+		MaxFee:               int64(env.Fee()),
+		Charged:              -1,
+		DeclaredResourceFee:  int64(data.ResourceFee),
+		InclusionFeeCharged:  -1,
+		NonRefundableCharged: -1,
+		RefundableCharged:    -1,
+		RentCharged:          -1,
+	}
+	if env.IsFeeBump() {
+		b.MaxFee = env.FeeBumpFee()
+	}
+	b.InclusionFeeBid = b.MaxFee - b.DeclaredResourceFee
+
+	res := data.Resources
+	readEntries := int64(len(res.Footprint.ReadOnly) + len(res.Footprint.ReadWrite))
+	writeEntries := int64(len(res.Footprint.ReadWrite))
+
+	// The envelope size is the same in the simulation, so the size based
+	// components need exactly what they declare
+	bandwidth := feeFor(txSize, cfg.FeeTxSize1KB, 1024)
+	historical := feeFor(txSize+txBaseResultSize, cfg.FeeHistorical1KB, 1024)
+	b.Components = []FeeComponent{
+		{Name: "instructions", Declared: feeFor(int64(res.Instructions), cfg.FeePerInstructionsIncrement, instructionsIncrement), Required: required(sim.Instructions, cfg.FeePerInstructionsIncrement, instructionsIncrement)},
+		{Name: "ledger read entries", Declared: readEntries * cfg.FeeReadLedgerEntry, Required: required(sim.ReadEntries, cfg.FeeReadLedgerEntry, 1)},
+		{Name: "ledger write entries", Declared: writeEntries * cfg.FeeWriteLedgerEntry, Required: required(sim.WriteEntries, cfg.FeeWriteLedgerEntry, 1)},
+		{Name: "ledger read bytes", Declared: feeFor(int64(res.DiskReadBytes), cfg.FeeRead1KB, 1024), Required: required(sim.ReadBytes, cfg.FeeRead1KB, 1024)},
+		{Name: "ledger write bytes", Declared: feeFor(int64(res.WriteBytes), cfg.FeeWrite1KB, 1024), Required: required(sim.WriteBytes, cfg.FeeWrite1KB, 1024)},
+		{Name: "bandwidth", Declared: bandwidth, Required: bandwidth},
+		{Name: "historical data", Declared: historical, Required: historical},
+		{Name: "contract events", Declared: -1, Required: required(sim.EventBytes, cfg.FeeContractEvents1KB, 1024), Refundable: true},
+	}
+
+	if resultXdr != "" {
+		var result xdr.TransactionResult
+		if err := xdr.SafeUnmarshalBase64(resultXdr, &result); err == nil {
+			b.Charged = int64(result.FeeCharged)
+		}
+	}
+	if resultMetaXdr != "" {
+		var rm xdr.TransactionResultMeta
+		if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &rm); err == nil {
+			if b.Charged < 0 {
+				b.Charged = int64(rm.Result.Result.FeeCharged)
+			}
+			if ext := sorobanMetaExt(rm.TxApplyProcessing); ext != nil {
+				b.NonRefundableCharged = int64(ext.TotalNonRefundableResourceFeeCharged)
+				b.RefundableCharged = int64(ext.TotalRefundableResourceFeeCharged)
+				b.RentCharged = int64(ext.RentFeeCharged)
+			}
+		}
+	}
+	if b.Charged >= 0 && b.NonRefundableCharged >= 0 {
+		b.InclusionFeeCharged = b.Charged - b.NonRefundableCharged - b.RefundableCharged
+	}
+	// Rent depends on the TTL extensions the execution made, which only
+	// the result metadata records
+	b.Components = append(b.Components, FeeComponent{Name: "rent", Declared: -1, Required: b.RentCharged, Refundable: true})
+	return b, nil
+}
+
+// feeFor computes ceil(amount * rate / unit), the rounding the network uses
+func feeFor(amount, rate, unit int64) int64 {
+	if amount <= 0 || rate <= 0 {
+		return 0
+	}
+	return (amount*rate + unit - 1) / unit
+}
+
+func sorobanMetaExt(tm xdr.TransactionMeta) *xdr.SorobanTransactionMetaExtV1 {
+	switch {
+	case tm.V4 != nil && tm.V4.SorobanMeta != nil:
+		return tm.V4.SorobanMeta.Ext.V1
+	case tm.V3 != nil && tm.V3.SorobanMeta != nil:
+		return tm.V3.SorobanMeta.Ext.V1
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sorobanEnvelope(t *testing.T, res xdr.SorobanResources, resourceFee int64) string {
+	t.Helper()
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(testSource),
+			Fee:           xdr.Uint32(100 + resourceFee),
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type:                 xdr.OperationTypeExtendFootprintTtl,
+				ExtendFootprintTtlOp: &xdr.ExtendFootprintTtlOp{ExtendTo: 1},
+			}}},
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{Resources: res, ResourceFee: xdr.Int64(resourceFee)}},
+		}},
+	}
+	s, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return s
+}
+
+func TestBreakdownFees(t *testing.T) {
+	res := xdr.SorobanResources{
+		Footprint:     xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{codeKey(1)}, ReadWrite: []xdr.LedgerKey{codeKey(2)}},
+		Instructions:  1_000_000,
+		DiskReadBytes: 2048,
+		WriteBytes:    1024,
+	}
+	cfg := FeeConfig{
+		FeePerInstructionsIncrement: 25,
+		FeeReadLedgerEntry:          10,
+		FeeWriteLedgerEntry:         100,
+		FeeRead1KB:                  1000,
+		FeeWrite1KB:                 3000,
+		FeeContractEvents1KB:        2048,
+	}
+
+	noResults := []xdr.OperationResult{}
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{
+			FeeCharged: 9000,
+			Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &noResults},
+		}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+			SorobanMeta: &xdr.SorobanTransactionMeta{ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid}, Ext: xdr.SorobanTransactionMetaExt{
+				V: 1, V1: &xdr.SorobanTransactionMetaExtV1{
+					TotalNonRefundableResourceFeeCharged: 7000,
+					TotalRefundableResourceFeeCharged:    1900,
+					RentFeeCharged:                       1500,
+				},
+			}},
+		}},
+	}
+	metaB64, err := xdr.MarshalBase64(meta)
+	require.NoError(t, err)
+
+	b, err := BreakdownFees(sorobanEnvelope(t, res, 20_000), "", metaB64, cfg, SimulatedUsage{
+		Instructions: 400_000, ReadEntries: 2, WriteEntries: 1, ReadBytes: 512, WriteBytes: 2048, EventBytes: 100,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, b)
+
+	assert.Equal(t, int64(20_100), b.MaxFee)
+	assert.Equal(t, int64(100), b.InclusionFeeBid)
+	assert.Equal(t, int64(9000), b.Charged)
+	assert.Equal(t, int64(100), b.InclusionFeeCharged)
+	assert.Equal(t, int64(1500), b.RentCharged)
+
+	byName := map[string]FeeComponent{}
+	for _, c := range b.Components {
+		byName[c.Name] = c
+	}
+	assert.Equal(t, FeeComponent{Name: "instructions", Declared: 2500, Required: 1000}, byName["instructions"])
+	assert.Equal(t, int64(20), byName["ledger read entries"].Declared)
+	assert.Equal(t, int64(100), byName["ledger write entries"].Declared)
+	assert.Equal(t, int64(2000), byName["ledger read bytes"].Declared)
+	assert.Equal(t, int64(3000), byName["ledger write bytes"].Declared)
+	assert.Equal(t, int64(0), byName["bandwidth"].Declared, "zero rates contribute nothing")
+
+	assert.Equal(t, int64(20), byName["ledger read entries"].Required)
+	assert.Equal(t, int64(100), byName["ledger write entries"].Required)
+	assert.Equal(t, int64(500), byName["ledger read bytes"].Required)
+	assert.Equal(t, int64(6000), byName["ledger write bytes"].Required)
+	assert.Equal(t, byName["historical data"].Declared, byName["historical data"].Required, "the envelope size does not change")
+	assert.Equal(t, FeeComponent{Name: "contract events", Declared: -1, Required: 200, Refundable: true}, byName["contract events"])
+	assert.Equal(t, FeeComponent{Name: "rent", Declared: -1, Required: 1500, Refundable: true}, byName["rent"])
+	assert.Equal(t, int64(2500+20+100+2000+3000), b.EstimatedNonRefundable(), "refundable components are not declared")
+}
+
+func TestBreakdownFees_ClassicTransaction(t *testing.T) {
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(testSource),
+			Fee:           100,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type:           xdr.OperationTypeBumpSequence,
+				BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 1},
+			}}},
+		}},
+	}
+	s, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+
+	b, err := BreakdownFees(s, "", "", DefaultFeeConfig, SimulatedUsage{})
+	require.NoError(t, err)
+	assert.Nil(t, b)
+}
+
+func TestFeeConfigFromLedgerEntries(t *testing.T) {
+	keys, err := FeeConfigKeys()
+	require.NoError(t, err)
+	assert.Len(t, keys, 6)
+
+	data := xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeConfigSetting,
+		ConfigSetting: &xdr.ConfigSettingEntry{
+			ConfigSettingId: xdr.ConfigSettingIdConfigSettingContractComputeV0,
+			ContractCompute: &xdr.ConfigSettingContractComputeV0{FeeRatePerInstructionsIncrement: 99},
+		},
+	}
+	raw, err := xdr.MarshalBase64(data)
+	require.NoError(t, err)
+
+	cfg := FeeConfigFromLedgerEntries(map[string]string{keys[0]: raw})
+	assert.Equal(t, int64(99), cfg.FeePerInstructionsIncrement)
+	assert.Equal(t, DefaultFeeConfig.FeeReadLedgerEntry, cfg.FeeReadLedgerEntry)
+
+	path := filepath.Join(t.TempDir(), "fees.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"fee_tx_size_1kb": 7}`), 0644))
+	loaded, err := LoadFeeConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), loaded.FeeTxSize1KB)
+	assert.Equal(t, DefaultFeeConfig.FeeWrite1KB, loaded.FeeWrite1KB)
+}
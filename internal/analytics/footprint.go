@@ -0,0 +1,248 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Footprint issue kinds
+const (
+	// IssueUndeclared is an entry accessed without being in the footprint
+	IssueUndeclared = "undeclared"
+	// IssueReadOnlyWritten is a read-only footprint entry that was written
+	IssueReadOnlyWritten = "read_only_written"
+	// IssueUnusedWrite is a read-write entry the transaction never wrote
+	IssueUnusedWrite = "unused_write"
+)
+
+// FootprintIssue is one difference between the declared footprint and the
+// state a transaction touched
+type FootprintIssue struct {
+	Kind string `json:"kind"`
+	// Key is the readable form of the ledger key
+	Key string `json:"key,omitempty"`
+	// KeyXdr is the base64 ledger key
+	KeyXdr string `json:"key_xdr,omitempty"`
+	Detail string `json:"detail"`
+}
+
+// FootprintCheck compares a Soroban transaction's declared footprint with
+// the entries its simulation accessed and the entries it wrote on chain.
+// Without the simulator's footprint, reads are unknown, so only written
+// entries are compared.
+type FootprintCheck struct {
+	ReadOnly  int `json:"read_only"`
+	ReadWrite int `json:"read_write"`
+	// Accessed counts the entries the simulation read or wrote
+	Accessed int `json:"accessed"`
+	Written  int `json:"written"`
+	// UnderDeclared entries were missing from the footprint or declared
+	// with too little access; they make the transaction fail
+	UnderDeclared []FootprintIssue `json:"under_declared,omitempty"`
+	// OverDeclared entries were declared read-write but never written;
+	// declaring them read-only, or dropping them, lowers the fee
+	OverDeclared []FootprintIssue `json:"over_declared,omitempty"`
+	// Notes explain checks that could not run
+	Notes []string `json:"notes,omitempty"`
+}
+
+// Mismatch reports whether any issue was found
+func (c *FootprintCheck) Mismatch() bool {
+	return len(c.UnderDeclared) > 0 || len(c.OverDeclared) > 0
+}
+
+// CheckFootprint compares the footprint declared in envelopeXdr with the
+// ledger keys the simulation accessed, from accessed, and the entries
+// written according to resultMetaXdr. Either may be missing. It returns nil
+// for transactions without an InvokeHostFunction operation.
+func CheckFootprint(envelopeXdr, resultMetaXdr string, accessed *simulator.Footprint) (*FootprintCheck, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	invoke := false
+	for _, op := range env.Operations() {
+		if op.Body.Type == xdr.OperationTypeInvokeHostFunction {
+			invoke = true
+		}
+	}
+	if !invoke {
+		return nil, nil
+	}
+
+	var footprint xdr.LedgerFootprint
+	if data := txmeta.SorobanData(env); data != nil {
+		footprint = data.Resources.Footprint
+	}
+	check := &FootprintCheck{ReadOnly: len(footprint.ReadOnly), ReadWrite: len(footprint.ReadWrite)}
+
+	readOnly, err := keySet(footprint.ReadOnly)
+	if err != nil {
+		return nil, err
+	}
+	readWrite, err := keySet(footprint.ReadWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	// Entries read and written, keyed by base64 XDR. A written entry is
+	// also read, so it only appears in writes.
+	reads := make(map[string]xdr.LedgerKey)
+	writes := make(map[string]xdr.LedgerKey)
+	if accessed == nil {
+		check.Notes = append(check.Notes, "the simulator did not report the entries it accessed; only written entries are compared")
+	} else {
+		if err := decodeKeys(accessed.ReadOnly, reads); err != nil {
+			return nil, err
+		}
+		if err := decodeKeys(accessed.ReadWrite, writes); err != nil {
+			return nil, err
+		}
+		check.Accessed = len(reads) + len(writes)
+	}
+
+	var written map[string]xdr.LedgerKey
+	if resultMetaXdr == "" {
+		check.Notes = append(check.Notes, "result metadata unavailable; written entries cannot be compared")
+	} else {
+		var rm xdr.TransactionResultMeta
+		if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &rm); err != nil {
+			return nil, fmt.Errorf("failed to decode result meta: %w", err)
+		}
+		written = writtenKeys(txmeta.OperationChanges(rm.TxApplyProcessing))
+		check.Written = len(written)
+		for encoded, key := range written {
+			writes[encoded] = key
+		}
+	}
+	for encoded := range writes {
+		delete(reads, encoded)
+	}
+
+	for _, encoded := range sortedKeys(writes) {
+		if _, ok := readWrite[encoded]; ok {
+			continue
+		}
+		key := writes[encoded]
+		if _, ok := readOnly[encoded]; ok {
+			check.UnderDeclared = append(check.UnderDeclared, FootprintIssue{
+				Kind: IssueReadOnlyWritten, Key: DescribeLedgerKey(key), KeyXdr: encoded,
+				Detail: "declared read-only but written; move it to the read-write footprint",
+			})
+			continue
+		}
+		check.UnderDeclared = append(check.UnderDeclared, FootprintIssue{
+			Kind: IssueUndeclared, Key: DescribeLedgerKey(key), KeyXdr: encoded,
+			Detail: "written but not declared; add it to the read-write footprint",
+		})
+	}
+	for _, encoded := range sortedKeys(reads) {
+		_, ro := readOnly[encoded]
+		_, rw := readWrite[encoded]
+		if ro || rw {
+			continue
+		}
+		check.UnderDeclared = append(check.UnderDeclared, FootprintIssue{
+			Kind: IssueUndeclared, Key: DescribeLedgerKey(reads[encoded]), KeyXdr: encoded,
+			Detail: "read but not declared; add it to the read-only footprint",
+		})
+	}
+
+	// A failed transaction rolls back its writes, so without the simulator's
+	// footprint unused entries are only meaningful when something was written
+	if accessed == nil && len(written) == 0 {
+		if resultMetaXdr != "" {
+			check.Notes = append(check.Notes, "no entries were written; unused read-write entries cannot be determined")
+		}
+		return check, nil
+	}
+	for _, encoded := range sortedKeys(readWrite) {
+		if _, ok := writes[encoded]; ok {
+			continue
+		}
+		check.OverDeclared = append(check.OverDeclared, FootprintIssue{
+			Kind: IssueUnusedWrite, Key: DescribeLedgerKey(readWrite[encoded]), KeyXdr: encoded,
+			Detail: "declared read-write but never written; declare it read-only to save write fees",
+		})
+	}
+	return check, nil
+}
+
+// decodeKeys adds the non-TTL base64 ledger keys in encoded to out
+func decodeKeys(encoded []string, out map[string]xdr.LedgerKey) error {
+	for _, e := range encoded {
+		var key xdr.LedgerKey
+		if err := xdr.SafeUnmarshalBase64(e, &key); err != nil {
+			return fmt.Errorf("failed to decode accessed ledger key: %w", err)
+		}
+		if key.Type == xdr.LedgerEntryTypeTtl {
+			continue
+		}
+		out[e] = key
+	}
+	return nil
+}
+
+// keySet indexes ledger keys by their base64 XDR
+func keySet(keys []xdr.LedgerKey) (map[string]xdr.LedgerKey, error) {
+	out := make(map[string]xdr.LedgerKey, len(keys))
+	for _, k := range keys {
+		raw, err := k.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode ledger key: %w", err)
+		}
+		out[base64.StdEncoding.EncodeToString(raw)] = k
+	}
+	return out, nil
+}
+
+// writtenKeys returns the non-TTL entries created, updated, removed or
+// restored by the operations, keyed by base64 XDR. TTL entries follow
+// their data entries and are never declared themselves.
+func writtenKeys(ops [][]xdr.LedgerEntryChange) map[string]xdr.LedgerKey {
+	out := make(map[string]xdr.LedgerKey)
+	for _, changes := range ops {
+		for _, c := range changes {
+			var key xdr.LedgerKey
+			var err error
+			switch c.Type {
+			case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+				key, err = c.Created.LedgerKey()
+			case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+				key, err = c.Updated.LedgerKey()
+			case xdr.LedgerEntryChangeTypeLedgerEntryRestored:
+				key, err = c.Restored.LedgerKey()
+			case xdr.LedgerEntryChangeTypeLedgerEntryRemoved:
+				key = *c.Removed
+			default:
+				continue
+			}
+			if err != nil || key.Type == xdr.LedgerEntryTypeTtl {
+				continue
+			}
+			raw, err := key.MarshalBinary()
+			if err != nil {
+				continue
+			}
+			out[base64.StdEncoding.EncodeToString(raw)] = key
+		}
+	}
+	return out
+}
+
+func sortedKeys(m map[string]xdr.LedgerKey) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
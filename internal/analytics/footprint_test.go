@@ -0,0 +1,144 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func invokeEnvelope(t *testing.T, readOnly, readWrite []xdr.LedgerKey) string {
+	t.Helper()
+	wasm := []byte{0}
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(testSource),
+			Fee:           100,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+					Type: xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm,
+					Wasm: &wasm,
+				}},
+			}}},
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadOnly: readOnly, ReadWrite: readWrite}},
+			}},
+		}},
+	}
+	s, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return s
+}
+
+func codeEntry(b byte) *xdr.LedgerEntry {
+	return &xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.ContractCodeEntry{Hash: xdr.Hash{b}, Code: []byte{0}},
+	}}
+}
+
+func opMeta(t *testing.T, changes xdr.LedgerEntryChanges) string {
+	t.Helper()
+	noResults := []xdr.OperationResult{}
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{
+			Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &noResults},
+		}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+			Operations:  []xdr.OperationMeta{{Changes: changes}},
+			SorobanMeta: &xdr.SorobanTransactionMeta{ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid}},
+		}},
+	}
+	s, err := xdr.MarshalBase64(meta)
+	require.NoError(t, err)
+	return s
+}
+
+func TestCheckFootprint(t *testing.T) {
+	readOnlyWritten, declared, unused := codeKey(1), codeKey(2), codeKey(3)
+	env := invokeEnvelope(t, []xdr.LedgerKey{readOnlyWritten}, []xdr.LedgerKey{declared, unused})
+	meta := opMeta(t, xdr.LedgerEntryChanges{
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: codeEntry(1)},
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: codeEntry(2)},
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: codeEntry(4)},
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: ttlEntry(t, declared, 5_000)},
+	})
+
+	check, err := CheckFootprint(env, meta, nil)
+	require.NoError(t, err)
+	require.NotNil(t, check)
+	assert.True(t, check.Mismatch())
+	assert.Equal(t, 3, check.Written, "TTL entries are not counted")
+
+	kinds := map[string]string{}
+	for _, issue := range check.UnderDeclared {
+		kinds[issue.Kind] = issue.Key
+	}
+	assert.Equal(t, DescribeLedgerKey(readOnlyWritten), kinds[IssueReadOnlyWritten])
+	assert.Equal(t, DescribeLedgerKey(codeKey(4)), kinds[IssueUndeclared])
+
+	require.Len(t, check.OverDeclared, 1)
+	assert.Equal(t, IssueUnusedWrite, check.OverDeclared[0].Kind)
+	assert.Equal(t, DescribeLedgerKey(unused), check.OverDeclared[0].Key)
+}
+
+func encodeKeys(t *testing.T, keys ...xdr.LedgerKey) []string {
+	t.Helper()
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		s, err := xdr.MarshalBase64(k)
+		require.NoError(t, err)
+		out[i] = s
+	}
+	return out
+}
+
+func TestCheckFootprint_Accessed(t *testing.T) {
+	read, missingRead, written, missingWrite := codeKey(1), codeKey(2), codeKey(3), codeKey(4)
+	env := invokeEnvelope(t, []xdr.LedgerKey{read}, []xdr.LedgerKey{written, codeKey(5)})
+
+	// The simulation failed, so nothing was written on chain
+	check, err := CheckFootprint(env, opMeta(t, nil), &simulator.Footprint{
+		ReadOnly:  encodeKeys(t, read, missingRead),
+		ReadWrite: encodeKeys(t, written, missingWrite),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, check.Accessed)
+
+	require.Len(t, check.UnderDeclared, 2)
+	assert.Equal(t, DescribeLedgerKey(missingWrite), check.UnderDeclared[0].Key)
+	assert.Contains(t, check.UnderDeclared[0].Detail, "written but not declared")
+	assert.Equal(t, DescribeLedgerKey(missingRead), check.UnderDeclared[1].Key)
+	assert.Contains(t, check.UnderDeclared[1].Detail, "read but not declared")
+
+	require.Len(t, check.OverDeclared, 1, "accessed entries show unused writes even when the transaction failed")
+	assert.Equal(t, DescribeLedgerKey(codeKey(5)), check.OverDeclared[0].Key)
+	assert.Empty(t, check.Notes)
+}
+
+func TestCheckFootprint_NoWrites(t *testing.T) {
+	env := invokeEnvelope(t, nil, []xdr.LedgerKey{codeKey(1)})
+
+	check, err := CheckFootprint(env, opMeta(t, nil), nil)
+	require.NoError(t, err)
+	assert.False(t, check.Mismatch(), "a rolled-back transaction cannot show unused writes")
+	assert.NotEmpty(t, check.Notes)
+
+	check, err = CheckFootprint(env, "", nil)
+	require.NoError(t, err)
+	assert.False(t, check.Mismatch())
+	assert.NotEmpty(t, check.Notes)
+}
+
+func TestCheckFootprint_NotSoroban(t *testing.T) {
+	check, err := CheckFootprint(extendEnvelope(t, 100, codeKey(1)), "", nil)
+	require.NoError(t, err)
+	assert.Nil(t, check)
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// AccountTx is a transaction in an account's history as it concerns the
+// account's sequence number
+type AccountTx struct {
+	Hash      string    `json:"hash"`
+	Source    string    `json:"source"`
+	Sequence  int64     `json:"sequence"`
+	Ledger    uint32    `json:"ledger"`
+	CreatedAt time.Time `json:"created_at"`
+	// Consumed is set when applying the transaction used up its sequence
+	// number, which failures of its preconditions do not
+	Consumed bool `json:"consumed"`
+	// BumpTo is the highest sequence number the transaction's successful
+	// bump_sequence operations set for the account, 0 if none
+	BumpTo int64 `json:"bump_to,omitempty"`
+	// order is the application order from the paging token
+	order int64
+}
+
+// AccountHistory converts Horizon transactions for the sequence analysis of
+// account
+func AccountHistory(account string, txs []hProtocol.Transaction) []AccountTx {
+	history := make([]AccountTx, 0, len(txs))
+	for _, tx := range txs {
+		order, _ := strconv.ParseInt(tx.PT, 10, 64)
+		atx := AccountTx{
+			Hash:      tx.Hash,
+			Source:    tx.Account,
+			Sequence:  tx.AccountSequence,
+			Ledger:    uint32(tx.Ledger),
+			CreatedAt: tx.LedgerCloseTime,
+			order:     order,
+		}
+		var result xdr.TransactionResult
+		if err := xdr.SafeUnmarshalBase64(tx.ResultXdr, &result); err == nil {
+			atx.Consumed = consumesSequence(result)
+		}
+		var env xdr.TransactionEnvelope
+		if tx.Successful && xdr.SafeUnmarshalBase64(tx.EnvelopeXdr, &env) == nil {
+			atx.BumpTo = bumpTo(env, account)
+		}
+		history = append(history, atx)
+	}
+	return history
+}
+
+// consumesSequence reports whether a transaction with this result used up
+// its sequence number. Only operation failures do; a transaction-level
+// failure is rejected before the number is taken.
+func consumesSequence(r xdr.TransactionResult) bool {
+	code := resultCode(r)
+	return code == xdr.TransactionResultCodeTxSuccess || code == xdr.TransactionResultCodeTxFailed
+}
+
+func resultCode(r xdr.TransactionResult) xdr.TransactionResultCode {
+	code := r.Result.Code
+	if inner := r.Result.InnerResultPair; inner != nil &&
+		(code == xdr.TransactionResultCodeTxFeeBumpInnerFailed || code == xdr.TransactionResultCodeTxFeeBumpInnerSuccess) {
+		return inner.Result.Result.Code
+	}
+	return code
+}
+
+func bumpTo(env xdr.TransactionEnvelope, account string) int64 {
+	txSource := env.SourceAccount().ToAccountId()
+	var highest int64
+	for _, op := range env.Operations() {
+		bump, ok := op.Body.GetBumpSequenceOp()
+		if !ok {
+			continue
+		}
+		source := txSource
+		if op.SourceAccount != nil {
+			source = op.SourceAccount.ToAccountId()
+		}
+		if source.Address() == account && int64(bump.BumpTo) > highest {
+			highest = int64(bump.BumpTo)
+		}
+	}
+	return highest
+}
+
+// SequenceContext explains a tx_bad_seq failure from the source account's
+// recent transactions
+type SequenceContext struct {
+	Source   string `json:"source"`
+	Sequence int64  `json:"sequence"`
+	// Expected is the sequence number the account needed next, 0 when its
+	// history does not show it
+	Expected int64 `json:"expected,omitempty"`
+	// Competing are earlier transactions that used the same sequence number
+	Competing []AccountTx `json:"competing,omitempty"`
+	// Bumps are earlier transactions whose bump_sequence skipped past it
+	Bumps []AccountTx `json:"bumps,omitempty"`
+	Note  string      `json:"note"`
+}
+
+// SequenceConflict returns a context for a transaction that failed with
+// tx_bad_seq, relating its sequence number to the history of its source
+// account, newest first as returned by AccountHistory. The transaction is
+// placed in history by hash, or else after everything before ledger. It
+// returns nil for other results.
+func SequenceConflict(envelopeXdr, resultXdr, hash string, ledger uint32, history []AccountTx) (*SequenceContext, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXdr, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	if resultCode(result) != xdr.TransactionResultCodeTxBadSeq {
+		return nil, nil
+	}
+
+	source := env.SourceAccount().ToAccountId()
+	sc := &SequenceContext{Source: source.Address(), Sequence: env.SeqNum()}
+
+	before := func(tx AccountTx) bool { return tx.Ledger < ledger }
+	for _, tx := range history {
+		if tx.Hash == hash {
+			order := tx.order
+			before = func(tx AccountTx) bool { return tx.order < order }
+			break
+		}
+	}
+
+	var last *AccountTx
+	for i := range history {
+		tx := history[i]
+		if !before(tx) || !tx.Consumed {
+			continue
+		}
+		if tx.Source == sc.Source {
+			if tx.Sequence == sc.Sequence {
+				sc.Competing = append(sc.Competing, tx)
+			}
+			if last == nil || tx.order > last.order {
+				last = &history[i]
+			}
+		}
+		if tx.BumpTo >= sc.Sequence {
+			sc.Bumps = append(sc.Bumps, tx)
+		}
+	}
+	if last != nil {
+		sc.Expected = last.Sequence + 1
+		if last.BumpTo >= sc.Expected {
+			sc.Expected = last.BumpTo + 1
+		}
+	}
+
+	sc.Note = sc.note(len(history))
+	return sc, nil
+}
+
+func (sc *SequenceContext) note(searched int) string {
+	switch {
+	case len(sc.Competing) > 0:
+		c := sc.Competing[0]
+		return fmt.Sprintf("Sequence number %d was already used by transaction %s in ledger %d (%s). Rebuild the transaction with the account's current sequence number.",
+			sc.Sequence, c.Hash, c.Ledger, c.CreatedAt.UTC().Format(time.RFC3339))
+	case len(sc.Bumps) > 0:
+		b := sc.Bumps[0]
+		return fmt.Sprintf("Transaction %s bumped the account's sequence number to %d in ledger %d, skipping past %d. Rebuild the transaction with the account's current sequence number.",
+			b.Hash, b.BumpTo, b.Ledger, sc.Sequence)
+	case sc.Expected != 0 && sc.Expected < sc.Sequence:
+		return fmt.Sprintf("The account's next sequence number was %d but the transaction used %d, so %d earlier transaction(s) never reached the ledger. Submit them first, or rebuild the transaction with sequence number %d.",
+			sc.Expected, sc.Sequence, sc.Sequence-sc.Expected, sc.Expected)
+	case sc.Expected != 0:
+		return fmt.Sprintf("The account's sequence number had already passed %d; the next valid one was %d. Rebuild the transaction with the account's current sequence number.",
+			sc.Sequence, sc.Expected)
+	}
+	return fmt.Sprintf("None of the account's last %d transactions explain the failure; the sequence number was consumed earlier or the account was created after the transaction was built.", searched)
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/toid"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var seqSource = keypair.MustRandom().Address()
+
+func seqEnvelope(t *testing.T, seq int64, ops ...xdr.Operation) string {
+	t.Helper()
+	if len(ops) == 0 {
+		ops = []xdr.Operation{{Body: xdr.OperationBody{Type: xdr.OperationTypeInflation}}}
+	}
+	s, err := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(seqSource),
+			Fee:           100,
+			SeqNum:        xdr.SequenceNumber(seq),
+			Operations:    ops,
+		}},
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func appliedResult(t *testing.T, code xdr.TransactionResultCode) string {
+	t.Helper()
+	s, err := xdr.MarshalBase64(xdr.TransactionResult{FeeCharged: 100, Result: xdr.TransactionResultResult{
+		Code: code, Results: &[]xdr.OperationResult{},
+	}})
+	require.NoError(t, err)
+	return s
+}
+
+func horizonTx(t *testing.T, hash string, ledger, index int32, seq int64, result string, ops ...xdr.Operation) hProtocol.Transaction {
+	t.Helper()
+	return hProtocol.Transaction{
+		Hash:            hash,
+		PT:              toid.New(ledger, index, 0).String(),
+		Successful:      result == appliedResult(t, xdr.TransactionResultCodeTxSuccess),
+		Ledger:          ledger,
+		LedgerCloseTime: time.Unix(1700000000, 0),
+		Account:         seqSource,
+		AccountSequence: seq,
+		EnvelopeXdr:     seqEnvelope(t, seq, ops...),
+		ResultXdr:       result,
+	}
+}
+
+func TestSequenceConflict_Competing(t *testing.T) {
+	badSeq := failedResult(t, xdr.TransactionResultCodeTxBadSeq)
+	history := AccountHistory(seqSource, []hProtocol.Transaction{
+		horizonTx(t, "ours", 100, 2, 11, badSeq),
+		horizonTx(t, "rival", 100, 1, 11, appliedResult(t, xdr.TransactionResultCodeTxFailed)),
+		horizonTx(t, "older", 99, 1, 10, appliedResult(t, xdr.TransactionResultCodeTxSuccess)),
+	})
+	assert.False(t, history[0].Consumed)
+	assert.True(t, history[1].Consumed, "an operation failure still uses the sequence number")
+
+	sc, err := SequenceConflict(seqEnvelope(t, 11), badSeq, "ours", 100, history)
+	require.NoError(t, err)
+	require.NotNil(t, sc)
+	assert.Equal(t, seqSource, sc.Source)
+	assert.Equal(t, int64(12), sc.Expected)
+	require.Len(t, sc.Competing, 1)
+	assert.Equal(t, "rival", sc.Competing[0].Hash)
+	assert.Equal(t, "Sequence number 11 was already used by transaction rival in ledger 100 (2023-11-14T22:13:20Z). Rebuild the transaction with the account's current sequence number.", sc.Note)
+}
+
+func TestSequenceConflict_Bump(t *testing.T) {
+	bump := xdr.Operation{Body: xdr.OperationBody{Type: xdr.OperationTypeBumpSequence, BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 50}}}
+	history := AccountHistory(seqSource, []hProtocol.Transaction{
+		horizonTx(t, "bumper", 99, 1, 10, appliedResult(t, xdr.TransactionResultCodeTxSuccess), bump),
+	})
+	require.Equal(t, int64(50), history[0].BumpTo)
+
+	sc, err := SequenceConflict(seqEnvelope(t, 11), failedResult(t, xdr.TransactionResultCodeTxBadSeq), "ours", 100, history)
+	require.NoError(t, err)
+	assert.Equal(t, int64(51), sc.Expected)
+	require.Len(t, sc.Bumps, 1)
+	assert.Contains(t, sc.Note, "Transaction bumper bumped the account's sequence number to 50 in ledger 99, skipping past 11")
+}
+
+func TestSequenceConflict_Gap(t *testing.T) {
+	history := AccountHistory(seqSource, []hProtocol.Transaction{
+		horizonTx(t, "later", 101, 1, 11, appliedResult(t, xdr.TransactionResultCodeTxSuccess)),
+		horizonTx(t, "older", 99, 1, 10, appliedResult(t, xdr.TransactionResultCodeTxSuccess)),
+	})
+
+	sc, err := SequenceConflict(seqEnvelope(t, 14), failedResult(t, xdr.TransactionResultCodeTxBadSeq), "ours", 100, history)
+	require.NoError(t, err)
+	assert.Empty(t, sc.Competing)
+	assert.Equal(t, int64(11), sc.Expected, "transactions after the failed one are ignored")
+	assert.Contains(t, sc.Note, "next sequence number was 11 but the transaction used 14, so 3 earlier transaction(s) never reached the ledger")
+
+	sc, err = SequenceConflict(seqEnvelope(t, 14), failedResult(t, xdr.TransactionResultCodeTxBadSeq), "ours", 100, nil)
+	require.NoError(t, err)
+	assert.Contains(t, sc.Note, "None of the account's last 0 transactions")
+}
+
+func TestSequenceConflict_OtherResults(t *testing.T) {
+	sc, err := SequenceConflict(seqEnvelope(t, 11), failedResult(t, xdr.TransactionResultCodeTxTooLate), "ours", 100, nil)
+	require.NoError(t, err)
+	assert.Nil(t, sc)
+
+	_, err = SequenceConflict("not xdr", failedResult(t, xdr.TransactionResultCodeTxBadSeq), "ours", 100, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,295 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Dependency kinds between two transactions touching the same entry
+const (
+	// DepWriteWrite is a later transaction overwriting an entry
+	DepWriteWrite = "write-write"
+	// DepWriteRead is a later transaction reading a written entry
+	DepWriteRead = "write-read"
+	// DepReadWrite is a later transaction writing an entry read before
+	DepReadWrite = "read-write"
+)
+
+// StateTx is a transaction to place in a state dependency graph.
+// ResultMetaXdr may be a TransactionMeta or a TransactionResultMeta, or
+// empty to fall back to the declared footprint.
+type StateTx struct {
+	Hash          string
+	Ledger        uint32
+	EnvelopeXdr   string
+	ResultMetaXdr string
+	// Failed marks a transaction that failed on chain
+	Failed bool
+}
+
+// StateNode is one transaction of the graph
+type StateNode struct {
+	Hash   string `json:"hash"`
+	Ledger uint32 `json:"ledger"`
+	Reads  int    `json:"reads"`
+	Writes int    `json:"writes"`
+}
+
+// StateEdge orders two transactions that touched the same entries, From
+// applied first
+type StateEdge struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Kinds []string `json:"kinds"`
+	// Keys are the readable shared entries
+	Keys []string `json:"keys"`
+}
+
+// StateHotspot is an entry several transactions touched, at least one of
+// them writing it
+type StateHotspot struct {
+	Key     string `json:"key"`
+	KeyXdr  string `json:"key_xdr"`
+	Readers int    `json:"readers"`
+	Writers int    `json:"writers"`
+}
+
+// StateGraph links transactions that read or wrote the same ledger entries
+type StateGraph struct {
+	Nodes []StateNode `json:"nodes"`
+	Edges []StateEdge `json:"edges"`
+	// Hotspots are sorted by writers, then readers, most contended first
+	Hotspots []StateHotspot `json:"hotspots"`
+}
+
+// BuildStateGraph links txs, given in application order. Each transaction
+// depends on the last earlier writer of every entry it touches, and a
+// writer also on the readers since that write. Written entries come from
+// the operation changes in the meta, so fee and sequence number updates
+// every transaction makes to its source account are left out; reads are
+// the rest of the Soroban footprint.
+func BuildStateGraph(txs []StateTx) (*StateGraph, error) {
+	g := &StateGraph{Nodes: []StateNode{}, Edges: []StateEdge{}, Hotspots: []StateHotspot{}}
+
+	type keyState struct {
+		key             xdr.LedgerKey
+		lastWriter      string
+		readers         []string
+		readBy, writeBy map[string]bool
+	}
+	keys := map[string]*keyState{}
+	var keyOrder []string
+	edges := map[[2]string]*StateEdge{}
+	var edgeOrder [][2]string
+	addEdge := func(from, to, kind string, key xdr.LedgerKey) {
+		if from == "" || from == to {
+			return
+		}
+		pair := [2]string{from, to}
+		e, ok := edges[pair]
+		if !ok {
+			e = &StateEdge{From: from, To: to}
+			edges[pair] = e
+			edgeOrder = append(edgeOrder, pair)
+		}
+		if !containsString(e.Kinds, kind) {
+			e.Kinds = append(e.Kinds, kind)
+		}
+		if desc := DescribeLedgerKey(key); !containsString(e.Keys, desc) {
+			e.Keys = append(e.Keys, desc)
+		}
+	}
+
+	touch := func(encoded string, key xdr.LedgerKey) *keyState {
+		ks, ok := keys[encoded]
+		if !ok {
+			ks = &keyState{key: key, readBy: map[string]bool{}, writeBy: map[string]bool{}}
+			keys[encoded] = ks
+			keyOrder = append(keyOrder, encoded)
+		}
+		return ks
+	}
+
+	for _, tx := range txs {
+		reads, writes, err := StateAccess(tx.EnvelopeXdr, tx.ResultMetaXdr)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %s: %w", tx.Hash, err)
+		}
+		g.Nodes = append(g.Nodes, StateNode{Hash: tx.Hash, Ledger: tx.Ledger, Reads: len(reads), Writes: len(writes)})
+
+		for _, encoded := range sortedKeys(reads) {
+			ks := touch(encoded, reads[encoded])
+			addEdge(ks.lastWriter, tx.Hash, DepWriteRead, ks.key)
+			ks.readers = append(ks.readers, tx.Hash)
+			ks.readBy[tx.Hash] = true
+		}
+		for _, encoded := range sortedKeys(writes) {
+			ks := touch(encoded, writes[encoded])
+			addEdge(ks.lastWriter, tx.Hash, DepWriteWrite, ks.key)
+			for _, reader := range ks.readers {
+				addEdge(reader, tx.Hash, DepReadWrite, ks.key)
+			}
+			ks.lastWriter, ks.readers = tx.Hash, nil
+			ks.writeBy[tx.Hash] = true
+		}
+	}
+
+	for _, k := range edgeOrder {
+		g.Edges = append(g.Edges, *edges[k])
+	}
+
+	for _, encoded := range keyOrder {
+		ks := keys[encoded]
+		touched := len(ks.writeBy)
+		for reader := range ks.readBy {
+			if !ks.writeBy[reader] {
+				touched++
+			}
+		}
+		if len(ks.writeBy) == 0 || touched < 2 {
+			continue
+		}
+		g.Hotspots = append(g.Hotspots, StateHotspot{
+			Key: DescribeLedgerKey(ks.key), KeyXdr: encoded, Readers: len(ks.readBy), Writers: len(ks.writeBy),
+		})
+	}
+	sort.SliceStable(g.Hotspots, func(i, j int) bool {
+		a, b := g.Hotspots[i], g.Hotspots[j]
+		if a.Writers != b.Writers {
+			return a.Writers > b.Writers
+		}
+		return a.Readers > b.Readers
+	})
+	return g, nil
+}
+
+// StateAccess returns the entries a transaction read and wrote, keyed by
+// base64 XDR. Without metadata the declared read-write footprint is taken
+// as written.
+func StateAccess(envelopeXdr, resultMetaXdr string) (map[string]xdr.LedgerKey, map[string]xdr.LedgerKey, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	var footprint xdr.LedgerFootprint
+	if data := txmeta.SorobanData(env); data != nil {
+		footprint = data.Resources.Footprint
+	}
+	readOnly, err := keySet(footprint.ReadOnly)
+	if err != nil {
+		return nil, nil, err
+	}
+	readWrite, err := keySet(footprint.ReadWrite)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	writes := readWrite
+	if resultMetaXdr != "" {
+		meta, err := decodeTransactionMeta(resultMetaXdr)
+		if err != nil {
+			return nil, nil, err
+		}
+		writes = writtenKeys(txmeta.OperationChanges(meta))
+	}
+
+	reads := map[string]xdr.LedgerKey{}
+	for _, set := range []map[string]xdr.LedgerKey{readOnly, readWrite} {
+		for encoded, key := range set {
+			if _, ok := writes[encoded]; !ok {
+				reads[encoded] = key
+			}
+		}
+	}
+	return reads, writes, nil
+}
+
+// decodeTransactionMeta accepts the TransactionMeta served by Horizon and
+// Soroban RPC as well as a TransactionResultMeta
+func decodeTransactionMeta(metaXdr string) (xdr.TransactionMeta, error) {
+	var meta xdr.TransactionMeta
+	if err := xdr.SafeUnmarshalBase64(metaXdr, &meta); err == nil {
+		return meta, nil
+	}
+	var rm xdr.TransactionResultMeta
+	if err := xdr.SafeUnmarshalBase64(metaXdr, &rm); err != nil {
+		return meta, fmt.Errorf("failed to decode result meta: %w", err)
+	}
+	return rm.TxApplyProcessing, nil
+}
+
+// DOT renders the graph in Graphviz DOT
+func (g *StateGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph state {\n  rankdir=LR;\n  node [shape=box];\n")
+	for i, n := range g.Nodes {
+		fmt.Fprintf(&b, "  t%d [label=%q];\n", i, nodeLabel(n))
+	}
+	index := g.nodeIndex()
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  t%d -> t%d [label=%q];\n", index[e.From], index[e.To], edgeLabel(e))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart
+func (g *StateGraph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		fmt.Fprintf(&b, "  t%d[\"%s\"]\n", i, escapeMermaid(nodeLabel(n)))
+	}
+	index := g.nodeIndex()
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  t%d -->|\"%s\"| t%d\n", index[e.From], escapeMermaid(edgeLabel(e)), index[e.To])
+	}
+	return b.String()
+}
+
+func (g *StateGraph) nodeIndex() map[string]int {
+	index := make(map[string]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n.Hash] = i
+	}
+	return index
+}
+
+func nodeLabel(n StateNode) string {
+	hash := n.Hash
+	if len(hash) > 12 {
+		hash = hash[:12]
+	}
+	if n.Ledger == 0 {
+		return hash
+	}
+	return fmt.Sprintf("%s (ledger %d)", hash, n.Ledger)
+}
+
+func edgeLabel(e StateEdge) string {
+	label := strings.Join(e.Kinds, ", ") + ": " + e.Keys[0]
+	if len(e.Keys) > 1 {
+		label += fmt.Sprintf(" +%d more", len(e.Keys)-1)
+	}
+	return label
+}
+
+func escapeMermaid(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "]", "#93;").Replace(s)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStateGraph(t *testing.T) {
+	// C carries a bare TransactionMeta, as Soroban RPC serves it
+	updated, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+		Operations: []xdr.OperationMeta{{Changes: xdr.LedgerEntryChanges{
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: codeEntry(1)},
+		}}},
+	}})
+	require.NoError(t, err)
+
+	g, err := BuildStateGraph([]StateTx{
+		{Hash: "aaaa", Ledger: 10, EnvelopeXdr: invokeEnvelope(t, nil, []xdr.LedgerKey{codeKey(1)}),
+			ResultMetaXdr: opMeta(t, xdr.LedgerEntryChanges{{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: codeEntry(1)}})},
+		{Hash: "bbbb", Ledger: 10, EnvelopeXdr: invokeEnvelope(t, []xdr.LedgerKey{codeKey(1)}, []xdr.LedgerKey{codeKey(2)}),
+			ResultMetaXdr: opMeta(t, xdr.LedgerEntryChanges{{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: codeEntry(2)}})},
+		{Hash: "cccc", Ledger: 11, EnvelopeXdr: invokeEnvelope(t, nil, []xdr.LedgerKey{codeKey(1)}), ResultMetaXdr: updated},
+		{Hash: "dddd", Ledger: 11, EnvelopeXdr: invokeEnvelope(t, []xdr.LedgerKey{codeKey(3)}, nil)},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, g.Nodes, 4)
+	assert.Equal(t, StateNode{Hash: "bbbb", Ledger: 10, Reads: 1, Writes: 1}, g.Nodes[1])
+
+	code1 := DescribeLedgerKey(codeKey(1))
+	assert.Equal(t, []StateEdge{
+		{From: "aaaa", To: "bbbb", Kinds: []string{DepWriteRead}, Keys: []string{code1}},
+		{From: "aaaa", To: "cccc", Kinds: []string{DepWriteWrite}, Keys: []string{code1}},
+		{From: "bbbb", To: "cccc", Kinds: []string{DepReadWrite}, Keys: []string{code1}},
+	}, g.Edges)
+
+	require.Len(t, g.Hotspots, 1, "entries touched by a single transaction are not contended")
+	assert.Equal(t, code1, g.Hotspots[0].Key)
+	assert.Equal(t, 2, g.Hotspots[0].Writers)
+	assert.Equal(t, 1, g.Hotspots[0].Readers)
+
+	dot := g.DOT()
+	assert.Contains(t, dot, "digraph state {")
+	assert.Contains(t, dot, `t0 [label="aaaa (ledger 10)"];`)
+	assert.Contains(t, dot, `t1 -> t2 [label="read-write: `+code1+`"];`)
+
+	mermaid := g.Mermaid()
+	assert.Contains(t, mermaid, "flowchart LR\n")
+	assert.Contains(t, mermaid, `t0 -->|"write-write: `+code1+`"| t2`)
+}
+
+func TestBuildStateGraphInvalid(t *testing.T) {
+	_, err := BuildStateGraph([]StateTx{{Hash: "aaaa", EnvelopeXdr: "not xdr"}})
+	assert.ErrorContains(t, err, "aaaa")
+
+	_, err = BuildStateGraph([]StateTx{{Hash: "bbbb", EnvelopeXdr: invokeEnvelope(t, nil, nil), ResultMetaXdr: "not xdr"}})
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ package analytics
 
 import (
 	"fmt"
+	"sort"
 )
 
 type StorageGrowthReport struct {
@@ -23,8 +24,13 @@ func PrintStorageReport(report *StorageGrowthReport, fee int64) {
 	fmt.Printf("Fee Impact: %d stroops\n\n", fee)
 
 	fmt.Println("Per-Key Changes:")
-	for key, delta := range report.PerKeyDelta {
-		if delta != 0 {
+	keys := make([]string, 0, len(report.PerKeyDelta))
+	for key := range report.PerKeyDelta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if delta := report.PerKeyDelta[key]; delta != 0 {
 			fmt.Printf("  %s: %+d bytes\n", key, delta)
 		}
 	}
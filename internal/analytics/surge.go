@@ -0,0 +1,112 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// SurgeUsage is the ledger capacity usage at or above which fee bids
+// compete for inclusion
+const SurgeUsage = 0.9
+
+// LedgerLoad is the fee market state of one closed ledger
+type LedgerLoad struct {
+	Sequence uint32 `json:"sequence"`
+	BaseFee  int64  `json:"base_fee"`
+	// Operations counts every operation in the transaction set, including
+	// those of failed transactions
+	Operations   int64 `json:"operations"`
+	MaxTxSetSize int64 `json:"max_tx_set_size"`
+}
+
+// Usage is the fraction of the ledger's operation capacity that was used
+func (l LedgerLoad) Usage() float64 {
+	if l.MaxTxSetSize <= 0 {
+		return 0
+	}
+	return float64(l.Operations) / float64(l.MaxTxSetSize)
+}
+
+// Surging reports whether the ledger was full enough for surge pricing
+func (l LedgerLoad) Surging() bool {
+	return l.Usage() >= SurgeUsage
+}
+
+// FeeContext relates a failed transaction to the fee market of its ledger
+type FeeContext struct {
+	Ledger LedgerLoad `json:"ledger"`
+	// BidPerOp is the inclusion fee bid per operation in stroops, which
+	// excludes the declared resource fee of Soroban transactions
+	BidPerOp   int64  `json:"bid_per_op"`
+	ResultCode string `json:"result_code"`
+	Note       string `json:"note"`
+}
+
+// FeeCompetition returns a context when a failure is consistent with fee
+// competition at ledger l, such as tx_insufficient_fee during surge pricing
+// or tx_too_late after waiting in a congested queue. It returns nil for
+// successful transactions and for failures unrelated to fees.
+func FeeCompetition(envelopeXdr, resultXdr string, l LedgerLoad) (*FeeContext, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXdr, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+
+	code := result.Result.Code
+	if code == xdr.TransactionResultCodeTxFeeBumpInnerFailed && result.Result.InnerResultPair != nil {
+		code = result.Result.InnerResultPair.Result.Result.Code
+	}
+
+	ctx := &FeeContext{Ledger: l, BidPerOp: inclusionBidPerOp(env), ResultCode: code.String()}
+	usage := l.Usage() * 100
+
+	switch code {
+	case xdr.TransactionResultCodeTxInsufficientFee:
+		switch {
+		case l.Surging():
+			ctx.Note = fmt.Sprintf("Ledger %d was at %.0f%% of its %d operation capacity, so surge pricing was in effect. The bid of %d stroops per operation was outbid by competing transactions; resubmit with a higher fee or use a fee bump.",
+				l.Sequence, usage, l.MaxTxSetSize, ctx.BidPerOp)
+		case l.BaseFee > 0 && ctx.BidPerOp < l.BaseFee:
+			ctx.Note = fmt.Sprintf("The bid of %d stroops per operation is below the %d stroop base fee of ledger %d.",
+				ctx.BidPerOp, l.BaseFee, l.Sequence)
+		default:
+			return nil, nil
+		}
+	case xdr.TransactionResultCodeTxTooLate:
+		if !l.Surging() {
+			return nil, nil
+		}
+		ctx.Note = fmt.Sprintf("Ledger %d was at %.0f%% of its %d operation capacity. With a bid of %d stroops per operation the transaction likely waited in the queue under surge pricing until its time bounds expired.",
+			l.Sequence, usage, l.MaxTxSetSize, ctx.BidPerOp)
+	default:
+		return nil, nil
+	}
+	return ctx, nil
+}
+
+// inclusionBidPerOp divides the inclusion part of the fee bid by the number
+// of operations, counting the outer transaction of a fee bump as one
+func inclusionBidPerOp(env xdr.TransactionEnvelope) int64 {
+	fee := int64(env.Fee())
+	ops := int64(len(env.Operations()))
+	if env.IsFeeBump() {
+		fee = env.FeeBumpFee()
+		ops++
+	}
+	if data := txmeta.SorobanData(env); data != nil {
+		fee -= int64(data.ResourceFee)
+	}
+	if ops == 0 {
+		return fee
+	}
+	return fee / ops
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func failedResult(t *testing.T, code xdr.TransactionResultCode) string {
+	t.Helper()
+	s, err := xdr.MarshalBase64(xdr.TransactionResult{FeeCharged: 100, Result: xdr.TransactionResultResult{Code: code}})
+	require.NoError(t, err)
+	return s
+}
+
+func TestFeeCompetition(t *testing.T) {
+	env := sorobanEnvelope(t, xdr.SorobanResources{}, 20_000)
+	full := LedgerLoad{Sequence: 500, BaseFee: 100, Operations: 1000, MaxTxSetSize: 1000}
+	quiet := LedgerLoad{Sequence: 500, BaseFee: 100, Operations: 120, MaxTxSetSize: 1000}
+
+	ctx, err := FeeCompetition(env, failedResult(t, xdr.TransactionResultCodeTxInsufficientFee), full)
+	require.NoError(t, err)
+	require.NotNil(t, ctx)
+	assert.Equal(t, int64(100), ctx.BidPerOp, "resource fee is not part of the inclusion bid")
+	assert.Contains(t, ctx.Note, "surge pricing")
+	assert.Contains(t, ctx.Note, "100%")
+
+	ctx, err = FeeCompetition(env, failedResult(t, xdr.TransactionResultCodeTxTooLate), full)
+	require.NoError(t, err)
+	require.NotNil(t, ctx)
+	assert.Contains(t, ctx.Note, "time bounds")
+
+	ctx, err = FeeCompetition(env, failedResult(t, xdr.TransactionResultCodeTxTooLate), quiet)
+	require.NoError(t, err)
+	assert.Nil(t, ctx, "expiry in a quiet ledger is not fee related")
+
+	ctx, err = FeeCompetition(env, failedResult(t, xdr.TransactionResultCodeTxBadSeq), full)
+	require.NoError(t, err)
+	assert.Nil(t, ctx)
+
+	lowBid := quiet
+	lowBid.BaseFee = 200
+	ctx, err = FeeCompetition(env, failedResult(t, xdr.TransactionResultCodeTxInsufficientFee), lowBid)
+	require.NoError(t, err)
+	require.NotNil(t, ctx)
+	assert.Contains(t, ctx.Note, "below the 200 stroop base fee")
+}
+
+func TestLedgerLoad_Usage(t *testing.T) {
+	assert.Equal(t, 0.0, LedgerLoad{}.Usage())
+	assert.True(t, LedgerLoad{Operations: 950, MaxTxSetSize: 1000}.Surging())
+	assert.False(t, LedgerLoad{Operations: 500, MaxTxSetSize: 1000}.Surging())
+}
@@ -0,0 +1,257 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// TTLChange is the lifetime change of one footprint entry
+type TTLChange struct {
+	Key          string `json:"key"`
+	OldLiveUntil uint32 `json:"old_live_until,omitempty"`
+	NewLiveUntil uint32 `json:"new_live_until"`
+	// Remaining is the number of ledgers the entry lives past the
+	// transaction's ledger, 0 when the ledger is unknown
+	Remaining uint32 `json:"remaining,omitempty"`
+}
+
+// FootprintOpReport describes one ExtendFootprintTTL or RestoreFootprint
+// operation
+type FootprintOpReport struct {
+	OpIndex int    `json:"op_index"`
+	Kind    string `json:"kind"`
+	// ExtendTo is the requested extension in ledgers for extend operations
+	ExtendTo uint32      `json:"extend_to,omitempty"`
+	Changed  []TTLChange `json:"changed"`
+	// Unchanged lists footprint entries whose TTL did not change: already
+	// live long enough for an extension, or not archived for a restore
+	Unchanged []string `json:"unchanged,omitempty"`
+	// RentFee is the rent charged in stroops, -1 when the metadata does
+	// not report it
+	RentFee  int64    `json:"rent_fee"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+const (
+	KindExtend  = "extend"
+	KindRestore = "restore"
+)
+
+// AnalyzeFootprintOps reports the entries extended or restored by a
+// transaction. ledger is the sequence the transaction was applied in, or 0
+// if unknown. minTTL is the number of ledgers the caller wants entries to
+// stay live; 0 uses the extension each operation requested.
+func AnalyzeFootprintOps(envelopeXdr, resultMetaXdr string, ledger, minTTL uint32) ([]FootprintOpReport, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+
+	var reports []FootprintOpReport
+	for i, op := range env.Operations() {
+		switch op.Body.Type {
+		case xdr.OperationTypeExtendFootprintTtl:
+			reports = append(reports, FootprintOpReport{OpIndex: i, Kind: KindExtend, ExtendTo: uint32(op.Body.ExtendFootprintTtlOp.ExtendTo), RentFee: -1})
+		case xdr.OperationTypeRestoreFootprint:
+			reports = append(reports, FootprintOpReport{OpIndex: i, Kind: KindRestore, RentFee: -1})
+		}
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+
+	var footprint xdr.LedgerFootprint
+	if data := txmeta.SorobanData(env); data != nil {
+		footprint = data.Resources.Footprint
+	}
+
+	var meta xdr.TransactionMeta
+	haveMeta := false
+	if resultMetaXdr != "" {
+		var rm xdr.TransactionResultMeta
+		if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &rm); err != nil {
+			return nil, fmt.Errorf("failed to decode result meta: %w", err)
+		}
+		meta = rm.TxApplyProcessing
+		haveMeta = true
+	}
+
+	rentFee := int64(-1)
+	var opChanges [][]xdr.LedgerEntryChange
+	if haveMeta {
+		rentFee = rentFeeCharged(meta)
+		opChanges = txmeta.OperationChanges(meta)
+	}
+
+	for i := range reports {
+		r := &reports[i]
+		// A Soroban transaction has exactly one operation, so the whole
+		// rent fee belongs to it
+		r.RentFee = rentFee
+
+		keys := footprint.ReadOnly
+		if r.Kind == KindRestore {
+			keys = footprint.ReadWrite
+		}
+
+		var changes []xdr.LedgerEntryChange
+		if r.OpIndex < len(opChanges) {
+			changes = opChanges[r.OpIndex]
+		}
+		old, updated := ttlChanges(changes)
+
+		for _, key := range keys {
+			hash, err := keyHash(key)
+			if err != nil {
+				return nil, err
+			}
+			newTTL, ok := updated[hash]
+			if !ok {
+				r.Unchanged = append(r.Unchanged, DescribeLedgerKey(key))
+				continue
+			}
+			c := TTLChange{Key: DescribeLedgerKey(key), OldLiveUntil: old[hash], NewLiveUntil: newTTL}
+			if ledger > 0 && newTTL > ledger {
+				c.Remaining = newTTL - ledger
+			}
+			r.Changed = append(r.Changed, c)
+		}
+
+		r.Warnings = footprintWarnings(r, haveMeta, ledger, minTTL)
+	}
+	return reports, nil
+}
+
+func footprintWarnings(r *FootprintOpReport, haveMeta bool, ledger, minTTL uint32) []string {
+	var warnings []string
+	if !haveMeta {
+		return append(warnings, "result metadata unavailable; TTL changes cannot be shown")
+	}
+	if len(r.Changed) == 0 && len(r.Unchanged) > 0 {
+		if r.Kind == KindExtend {
+			warnings = append(warnings, "no entry was extended: every entry already lives beyond the requested extension, so the operation only paid fees")
+		} else {
+			warnings = append(warnings, "no entry was restored: none of the footprint entries was archived")
+		}
+	}
+
+	want := minTTL
+	if want == 0 && r.Kind == KindExtend {
+		want = r.ExtendTo
+	}
+	if ledger == 0 || want == 0 {
+		return warnings
+	}
+	for _, c := range r.Changed {
+		if c.Remaining < want {
+			warnings = append(warnings, fmt.Sprintf("%s lives only %d ledgers after this transaction, %d short of the intended %d; the network may cap TTLs at its maximum entry lifetime",
+				c.Key, c.Remaining, want-c.Remaining, want))
+		}
+	}
+	return warnings
+}
+
+// ttlChanges returns the previous and new live-until ledger of every TTL
+// entry touched by changes, keyed by hex key hash
+func ttlChanges(changes []xdr.LedgerEntryChange) (map[string]uint32, map[string]uint32) {
+	old := map[string]uint32{}
+	updated := map[string]uint32{}
+	for _, c := range changes {
+		var entry *xdr.LedgerEntry
+		switch c.Type {
+		case xdr.LedgerEntryChangeTypeLedgerEntryState:
+			entry = c.State
+		case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+			entry = c.Created
+		case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+			entry = c.Updated
+		case xdr.LedgerEntryChangeTypeLedgerEntryRestored:
+			entry = c.Restored
+		}
+		if entry == nil || entry.Data.Type != xdr.LedgerEntryTypeTtl || entry.Data.Ttl == nil {
+			continue
+		}
+		hash := hex.EncodeToString(entry.Data.Ttl.KeyHash[:])
+		if c.Type == xdr.LedgerEntryChangeTypeLedgerEntryState {
+			old[hash] = uint32(entry.Data.Ttl.LiveUntilLedgerSeq)
+		} else {
+			updated[hash] = uint32(entry.Data.Ttl.LiveUntilLedgerSeq)
+		}
+	}
+	return old, updated
+}
+
+// keyHash is the hex sha256 of a ledger key, which TTL entries refer to
+func keyHash(key xdr.LedgerKey) (string, error) {
+	raw, err := key.MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode ledger key: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// DescribeLedgerKey renders a footprint key for display
+func DescribeLedgerKey(key xdr.LedgerKey) string {
+	switch key.Type {
+	case xdr.LedgerEntryTypeContractData:
+		cd := key.ContractData
+		contract, _ := cd.Contract.String()
+		if cd.Key.Type == xdr.ScValTypeScvLedgerKeyContractInstance {
+			return fmt.Sprintf("instance of %s", contract)
+		}
+		return fmt.Sprintf("%s data %s (%s)", contract, scValKind(cd.Key), durabilityName(cd.Durability))
+	case xdr.LedgerEntryTypeContractCode:
+		return fmt.Sprintf("code %s", hex.EncodeToString(key.ContractCode.Hash[:]))
+	case xdr.LedgerEntryTypeAccount:
+		return fmt.Sprintf("account %s", key.Account.AccountId.Address())
+	case xdr.LedgerEntryTypeTrustline:
+		return fmt.Sprintf("trustline %s %s", key.TrustLine.AccountId.Address(), trustLineAssetName(key.TrustLine.Asset))
+	}
+	return key.Type.String()
+}
+
+func trustLineAssetName(a xdr.TrustLineAsset) string {
+	if a.Type == xdr.AssetTypeAssetTypePoolShare && a.LiquidityPoolId != nil {
+		return "pool " + hex.EncodeToString(a.LiquidityPoolId[:])
+	}
+	return a.ToAsset().StringCanonical()
+}
+
+func scValKind(v xdr.ScVal) string {
+	switch v.Type {
+	case xdr.ScValTypeScvSymbol:
+		return string(*v.Sym)
+	case xdr.ScValTypeScvVec:
+		if v.Vec != nil && *v.Vec != nil && len(**v.Vec) > 0 {
+			first := (**v.Vec)[0]
+			if first.Type == xdr.ScValTypeScvSymbol {
+				return string(*first.Sym) + "(…)"
+			}
+		}
+	}
+	return v.Type.String()
+}
+
+func durabilityName(d xdr.ContractDataDurability) string {
+	if d == xdr.ContractDataDurabilityTemporary {
+		return "temporary"
+	}
+	return "persistent"
+}
+
+// rentFeeCharged returns the rent fee reported by Soroban metadata, or -1
+func rentFeeCharged(tm xdr.TransactionMeta) int64 {
+	ext := sorobanMetaExt(tm)
+	if ext == nil {
+		return -1
+	}
+	return int64(ext.RentFeeCharged)
+}
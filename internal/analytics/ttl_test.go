@@ -0,0 +1,112 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package analytics
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSource = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func codeKey(b byte) xdr.LedgerKey {
+	return xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{b}}}
+}
+
+func ttlEntry(t *testing.T, key xdr.LedgerKey, liveUntil uint32) *xdr.LedgerEntry {
+	t.Helper()
+	raw, err := key.MarshalBinary()
+	require.NoError(t, err)
+	return &xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeTtl,
+		Ttl:  &xdr.TtlEntry{KeyHash: sha256.Sum256(raw), LiveUntilLedgerSeq: xdr.Uint32(liveUntil)},
+	}}
+}
+
+func extendEnvelope(t *testing.T, extendTo uint32, keys ...xdr.LedgerKey) string {
+	t.Helper()
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(testSource),
+			Fee:           100,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type:                 xdr.OperationTypeExtendFootprintTtl,
+				ExtendFootprintTtlOp: &xdr.ExtendFootprintTtlOp{ExtendTo: xdr.Uint32(extendTo)},
+			}}},
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadOnly: keys}},
+			}},
+		}},
+	}
+	s, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return s
+}
+
+func TestAnalyzeFootprintOps_Extend(t *testing.T) {
+	extended, capped, untouched := codeKey(1), codeKey(2), codeKey(3)
+
+	changes := xdr.LedgerEntryChanges{
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: ttlEntry(t, extended, 1_100)},
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: ttlEntry(t, extended, 11_000)},
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: ttlEntry(t, capped, 1_100)},
+		{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: ttlEntry(t, capped, 6_000)},
+	}
+	noResults := []xdr.OperationResult{}
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{
+			Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &noResults},
+		}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{{Changes: changes}},
+			SorobanMeta: &xdr.SorobanTransactionMeta{ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid}, Ext: xdr.SorobanTransactionMetaExt{
+				V: 1, V1: &xdr.SorobanTransactionMetaExtV1{RentFeeCharged: 4242},
+			}},
+		}}}
+	metaB64, err := xdr.MarshalBase64(meta)
+	require.NoError(t, err)
+
+	reports, err := AnalyzeFootprintOps(extendEnvelope(t, 10_000, extended, capped, untouched), metaB64, 1_000, 0)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+
+	r := reports[0]
+	assert.Equal(t, KindExtend, r.Kind)
+	assert.Equal(t, int64(4242), r.RentFee)
+	require.Len(t, r.Changed, 2)
+	assert.Equal(t, TTLChange{Key: DescribeLedgerKey(extended), OldLiveUntil: 1_100, NewLiveUntil: 11_000, Remaining: 10_000}, r.Changed[0])
+	assert.Equal(t, []string{DescribeLedgerKey(untouched)}, r.Unchanged)
+
+	require.Len(t, r.Warnings, 1)
+	assert.True(t, strings.HasPrefix(r.Warnings[0], DescribeLedgerKey(capped)+" lives only 5000 ledgers"), r.Warnings[0])
+
+	reports, err = AnalyzeFootprintOps(extendEnvelope(t, 10_000, extended), metaB64, 1_000, 20_000)
+	require.NoError(t, err)
+	assert.Len(t, reports[0].Warnings, 1, "an explicit minimum TTL overrides the requested extension")
+}
+
+func TestAnalyzeFootprintOps_NoFootprintOps(t *testing.T) {
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(testSource),
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type:           xdr.OperationTypeBumpSequence,
+				BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 1},
+			}}},
+		}},
+	}
+	s, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+
+	reports, err := AnalyzeFootprintOps(s, "", 0, 0)
+	require.NoError(t, err)
+	assert.Empty(t, reports)
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/objstore"
+)
+
+// ArchivePrefix returns where the artifacts of a run are archived:
+// <network>/<tx-hash>/<YYYY-MM-DD>/<UTC timestamp>
+func ArchivePrefix(network, txHash string, created time.Time) string {
+	if network == "" {
+		network = "unknown"
+	}
+	t := created.UTC()
+	return path.Join(network, txHash, t.Format("2006-01-02"), t.Format(stampLayout))
+}
+
+// Archive uploads the artifacts listed in m, followed by its index, from the
+// directory at dir to s. It returns the prefix they were stored under.
+func Archive(ctx context.Context, s objstore.Store, dir string, m *Manifest) (string, error) {
+	prefix := ArchivePrefix(m.Network, m.TxHash, m.CreatedAt)
+	for _, a := range m.Artifacts {
+		data, err := os.ReadFile(filepath.Join(dir, a.Name))
+		if err != nil {
+			return "", fmt.Errorf("failed to read artifact %s: %w", a.Name, err)
+		}
+		if err := s.Put(ctx, path.Join(prefix, a.Name), data, contentType(a.Name)); err != nil {
+			return "", fmt.Errorf("failed to archive %s: %w", a.Name, err)
+		}
+	}
+	index, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal artifact index: %w", err)
+	}
+	// The index goes last, so a run is only listed once all its files are stored
+	if err := s.Put(ctx, path.Join(prefix, IndexName), index, "application/json"); err != nil {
+		return "", fmt.Errorf("failed to archive %s: %w", IndexName, err)
+	}
+	return prefix, nil
+}
+
+// ArchivedRuns returns the prefixes of the archived runs of txHash on
+// network, oldest first. A non-empty date (YYYY-MM-DD) limits them to runs
+// of that day.
+func ArchivedRuns(ctx context.Context, s objstore.Store, network, txHash, date string) ([]string, error) {
+	search := path.Join(network, txHash) + "/"
+	if date != "" {
+		search += date + "/"
+	}
+	keys, err := s.List(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	var runs []string
+	for _, key := range keys {
+		if prefix, ok := strings.CutSuffix(key, "/"+IndexName); ok {
+			runs = append(runs, prefix)
+		}
+	}
+	return runs, nil
+}
+
+// Fetch downloads the archived run at prefix into dest, checking every
+// file against the SHA-256 recorded in its index
+func Fetch(ctx context.Context, s objstore.Store, prefix, dest string) (*Manifest, error) {
+	index, err := get(ctx, s, path.Join(prefix, IndexName))
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(index, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse archived index: %w", err)
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", dest, err)
+	}
+	for _, a := range m.Artifacts {
+		if a.Name != filepath.Base(a.Name) {
+			return nil, fmt.Errorf("archived index names an invalid file %q", a.Name)
+		}
+		data, err := get(ctx, s, path.Join(prefix, a.Name))
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); got != a.SHA256 {
+			return nil, fmt.Errorf("archived %s is corrupt: sha256 %s, index records %s", a.Name, got, a.SHA256)
+		}
+		if err := os.WriteFile(filepath.Join(dest, a.Name), data, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", a.Name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dest, IndexName), index, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", IndexName, err)
+	}
+	return &m, nil
+}
+
+func get(ctx context.Context, s objstore.Store, key string) ([]byte, error) {
+	rc, err := s.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, rc); err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", s.Location(key), err)
+	}
+	return buf.Bytes(), nil
+}
+
+func contentType(name string) string {
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
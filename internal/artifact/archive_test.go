@@ -0,0 +1,87 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/objstore"
+)
+
+func TestArchivePrefix(t *testing.T) {
+	at := time.Date(2026, 1, 5, 3, 4, 5, 0, time.UTC)
+	if got := ArchivePrefix("testnet", "abc", at); got != "testnet/abc/2026-01-05/20260105T030405Z" {
+		t.Errorf("ArchivePrefix = %q", got)
+	}
+	if got := ArchivePrefix("", "abc", at); !strings.HasPrefix(got, "unknown/abc/") {
+		t.Errorf("ArchivePrefix without network = %q", got)
+	}
+}
+
+func TestArchiveAndFetch(t *testing.T) {
+	ctx := context.Background()
+	store := &objstore.Local{Dir: t.TempDir()}
+
+	var prefixes []string
+	for i, created := range []time.Time{
+		time.Date(2026, 1, 5, 1, 0, 0, 0, time.UTC),
+		time.Date(2026, 1, 6, 1, 0, 0, 0, time.UTC),
+	} {
+		dir, err := Create(t.TempDir(), "abc", false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dir.Manifest.Network = "testnet"
+		dir.Manifest.CreatedAt = created
+		if _, err := dir.Write("report.html", KindReport, "", []byte("<p>run "+string(rune('1'+i))+"</p>")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dir.Close(); err != nil {
+			t.Fatal(err)
+		}
+		prefix, err := Archive(ctx, store, dir.Path, &dir.Manifest)
+		if err != nil {
+			t.Fatalf("Archive: %v", err)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	runs, err := ArchivedRuns(ctx, store, "testnet", "abc", "")
+	if err != nil || !reflect.DeepEqual(runs, prefixes) {
+		t.Fatalf("ArchivedRuns = %v, %v; want %v", runs, err, prefixes)
+	}
+	runs, err = ArchivedRuns(ctx, store, "testnet", "abc", "2026-01-05")
+	if err != nil || !reflect.DeepEqual(runs, prefixes[:1]) {
+		t.Errorf("ArchivedRuns by date = %v, %v", runs, err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "abc")
+	m, err := Fetch(ctx, store, prefixes[1], dest)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	if m.TxHash != "abc" || len(m.Artifacts) != 1 {
+		t.Errorf("manifest = %+v", m)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dest, "report.html")); string(data) != "<p>run 2</p>" {
+		t.Errorf("report.html = %q", data)
+	}
+	if _, err := LoadManifest(dest); err != nil {
+		t.Errorf("fetched directory has no index: %v", err)
+	}
+
+	// A tampered object fails the checksum
+	if err := store.Put(ctx, prefixes[0]+"/report.html", []byte("tampered"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Fetch(ctx, store, prefixes[0], t.TempDir()); err == nil || !strings.Contains(err.Error(), "corrupt") {
+		t.Errorf("expected a checksum error, got %v", err)
+	}
+}
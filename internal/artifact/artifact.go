@@ -0,0 +1,177 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package artifact collects the files produced by one run, such as reports,
+// traces and snapshots, in a per-transaction directory described by an
+// index.json manifest.
+package artifact
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// IndexName is the manifest written to every artifact directory
+const IndexName = "index.json"
+
+// Artifact kinds
+const (
+	KindReport     = "report"
+	KindTrace      = "trace"
+	KindFlamegraph = "flamegraph"
+	KindSnapshot   = "snapshot"
+	KindTokenFlow  = "tokenflow"
+)
+
+// Artifact describes one file in the directory
+type Artifact struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	Description string `json:"description,omitempty"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+// Manifest is the index of an artifact directory
+type Manifest struct {
+	TxHash      string     `json:"tx_hash"`
+	Network     string     `json:"network,omitempty"`
+	Command     string     `json:"command,omitempty"`
+	ErstVersion string     `json:"erst_version,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Artifacts   []Artifact `json:"artifacts"`
+}
+
+// Dir is the output directory of one transaction
+type Dir struct {
+	Path     string
+	Manifest Manifest
+}
+
+// Create prepares <root>/<txHash> for the artifacts of a run. A directory
+// left by an earlier run is kept and the new run goes to
+// <root>/<txHash>-<UTC timestamp> instead, unless force is set, in which
+// case the earlier artifacts are deleted first.
+func Create(root, txHash string, force bool) (*Dir, error) {
+	if txHash == "" {
+		return nil, fmt.Errorf("an artifact directory requires a transaction hash")
+	}
+	path := filepath.Join(root, filepath.Base(txHash))
+	if !isEmptyDir(path) {
+		if force {
+			if !exists(filepath.Join(path, IndexName)) {
+				return nil, fmt.Errorf("refusing to overwrite %s: it has no %s and was not written by erst", path, IndexName)
+			}
+			if err := os.RemoveAll(path); err != nil {
+				return nil, fmt.Errorf("failed to remove earlier artifacts: %w", err)
+			}
+		} else {
+			path = UniquePath(path, false)
+		}
+	}
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+	return &Dir{
+		Path:     path,
+		Manifest: Manifest{TxHash: txHash, CreatedAt: now().UTC(), Artifacts: []Artifact{}},
+	}, nil
+}
+
+// isEmptyDir reports whether path is missing or an empty directory
+func isEmptyDir(path string) bool {
+	entries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return true
+	}
+	return err == nil && len(entries) == 0
+}
+
+// File returns the path an artifact called name is written to
+func (d *Dir) File(name string) string {
+	return filepath.Join(d.Path, name)
+}
+
+// Write stores data as the artifact name and records it in the manifest
+func (d *Dir) Write(name, kind, description string, data []byte) (string, error) {
+	path := d.File(name)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	d.record(name, kind, description, data)
+	return path, nil
+}
+
+// WriteFunc stores what fn writes as the artifact name
+func (d *Dir) WriteFunc(name, kind, description string, fn func(w io.Writer) error) (string, error) {
+	var buf bytes.Buffer
+	if err := fn(&buf); err != nil {
+		return "", fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return d.Write(name, kind, description, buf.Bytes())
+}
+
+// Add records a file that was written to File(name) by other means
+func (d *Dir) Add(name, kind, description string) error {
+	data, err := os.ReadFile(d.File(name))
+	if err != nil {
+		return fmt.Errorf("failed to read artifact %s: %w", name, err)
+	}
+	d.record(name, kind, description, data)
+	return nil
+}
+
+func (d *Dir) record(name, kind, description string, data []byte) {
+	sum := sha256.Sum256(data)
+	a := Artifact{
+		Name:        name,
+		Kind:        kind,
+		Description: description,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}
+	for i := range d.Manifest.Artifacts {
+		if d.Manifest.Artifacts[i].Name == name {
+			d.Manifest.Artifacts[i] = a
+			return
+		}
+	}
+	d.Manifest.Artifacts = append(d.Manifest.Artifacts, a)
+}
+
+// Close writes the index manifest and returns its path
+func (d *Dir) Close() (string, error) {
+	sort.Slice(d.Manifest.Artifacts, func(i, j int) bool {
+		return d.Manifest.Artifacts[i].Name < d.Manifest.Artifacts[j].Name
+	})
+	data, err := json.MarshalIndent(d.Manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal artifact index: %w", err)
+	}
+	path := d.File(IndexName)
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write artifact index: %w", err)
+	}
+	return path, nil
+}
+
+// LoadManifest reads the index of the artifact directory at path
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(path, IndexName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact index: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse artifact index: %w", err)
+	}
+	return &m, nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDir(t *testing.T) {
+	root := t.TempDir()
+	d, err := Create(root, "abc123", false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "abc123"), d.Path)
+	d.Manifest.Network = "testnet"
+
+	path, err := d.Write("trace.json", KindTrace, "Execution trace", []byte(`{}`))
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	_, err = d.WriteFunc("tokenflow.csv", KindTokenFlow, "", func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, "a,b")
+		return err
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(d.File("snapshot.json"), []byte(`{"ledgerEntries":[]}`), 0644))
+	require.NoError(t, d.Add("snapshot.json", KindSnapshot, "Ledger state"))
+
+	// Rewriting an artifact replaces its entry
+	_, err = d.Write("trace.json", KindTrace, "Execution trace", []byte(`{"states":[]}`))
+	require.NoError(t, err)
+
+	_, err = d.Close()
+	require.NoError(t, err)
+
+	m, err := LoadManifest(d.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", m.TxHash)
+	assert.Equal(t, "testnet", m.Network)
+	require.Len(t, m.Artifacts, 3)
+	assert.Equal(t, "snapshot.json", m.Artifacts[0].Name)
+	assert.Equal(t, "tokenflow.csv", m.Artifacts[1].Name)
+	assert.Equal(t, int64(len(`{"states":[]}`)), m.Artifacts[2].Size)
+	assert.Len(t, m.Artifacts[2].SHA256, 64)
+
+	_, err = Create(root, "", false)
+	assert.Error(t, err)
+}
+
+func TestCreateKeepsEarlierRun(t *testing.T) {
+	root := t.TempDir()
+	now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	first, err := Create(root, "abc123", false)
+	require.NoError(t, err)
+	_, err = first.Write("trace.json", KindTrace, "", []byte(`{}`))
+	require.NoError(t, err)
+	_, err = first.Close()
+	require.NoError(t, err)
+
+	second, err := Create(root, "abc123", false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "abc123-20250102T030405Z"), second.Path)
+	assert.FileExists(t, first.File("trace.json"))
+
+	forced, err := Create(root, "abc123", true)
+	require.NoError(t, err)
+	assert.Equal(t, first.Path, forced.Path)
+	assert.NoFileExists(t, first.File("trace.json"), "force replaces the earlier artifacts")
+
+	// Directories erst did not write are never deleted
+	other := filepath.Join(root, "def456")
+	require.NoError(t, os.MkdirAll(other, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(other, "notes.txt"), []byte("keep"), 0644))
+	_, err = Create(root, "def456", true)
+	assert.Error(t, err)
+	assert.FileExists(t, filepath.Join(other, "notes.txt"))
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// stampLayout is the UTC timestamp added to names that would collide
+const stampLayout = "20060102T150405Z"
+
+// now is replaced in tests
+var now = time.Now
+
+// UniquePath returns path when nothing exists there or force is set.
+// Otherwise it returns a free sibling name with a UTC timestamp inserted
+// before the extension, such as <tx>-20250102T030405Z.trace.json.
+func UniquePath(path string, force bool) string {
+	if force || !exists(path) {
+		return path
+	}
+	// Cleaning drops a trailing separator, so an existing directory gets a
+	// sibling rather than a name inside it
+	dir, base := filepath.Split(filepath.Clean(path))
+	stem, ext := base, ""
+	// A leading dot starts a hidden name rather than an extension
+	if len(base) > 1 {
+		if i := strings.IndexByte(base[1:], '.'); i >= 0 {
+			stem, ext = base[:i+1], base[i+1:]
+		}
+	}
+	stamped := stem + "-" + now().UTC().Format(stampLayout)
+	candidate := filepath.Join(dir, stamped+ext)
+	for n := 2; exists(candidate); n++ {
+		candidate = filepath.Join(dir, fmt.Sprintf("%s-%d%s", stamped, n, ext))
+	}
+	return candidate
+}
+
+// Describe builds the manifest entry of a file written outside an artifact
+// directory; its name is the path
+func Describe(path, kind, description string) (Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to read artifact %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return Artifact{
+		Name:        path,
+		Kind:        kind,
+		Description: description,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
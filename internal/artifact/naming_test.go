@@ -0,0 +1,55 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package artifact
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniquePath(t *testing.T) {
+	dir := t.TempDir()
+	now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	path := filepath.Join(dir, "abc.trace.json")
+	assert.Equal(t, path, UniquePath(path, false), "free paths are used as is")
+
+	require.NoError(t, os.WriteFile(path, []byte("{}"), 0644))
+	assert.Equal(t, path, UniquePath(path, true))
+
+	stamped := filepath.Join(dir, "abc-20250102T030405Z.trace.json")
+	assert.Equal(t, stamped, UniquePath(path, false))
+
+	require.NoError(t, os.WriteFile(stamped, []byte("{}"), 0644))
+	assert.Equal(t, filepath.Join(dir, "abc-20250102T030405Z-2.trace.json"), UniquePath(path, false))
+
+	hidden := filepath.Join(dir, ".profile")
+	require.NoError(t, os.WriteFile(hidden, nil, 0644))
+	assert.Equal(t, filepath.Join(dir, ".profile-20250102T030405Z"), UniquePath(hidden, false))
+
+	out := filepath.Join(dir, "out")
+	require.NoError(t, os.Mkdir(out, 0755))
+	assert.Equal(t, filepath.Join(dir, "out-20250102T030405Z"), UniquePath(out+string(filepath.Separator), false), "a trailing separator leaves no base name")
+	assert.NotPanics(t, func() { UniquePath(string(filepath.Separator), false) })
+}
+
+func TestDescribe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc.flamegraph.svg")
+	require.NoError(t, os.WriteFile(path, []byte("<svg/>"), 0644))
+
+	a, err := Describe(path, KindFlamegraph, "Flamegraph")
+	require.NoError(t, err)
+	assert.Equal(t, path, a.Name)
+	assert.Equal(t, int64(6), a.Size)
+	assert.Len(t, a.SHA256, 64)
+
+	_, err = Describe(filepath.Join(t.TempDir(), "missing"), KindTrace, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,421 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package audit keeps an append-only log of every erst command: who ran
+// it, when, with which arguments and transactions, and how it ended. Each
+// entry carries the hash of the one before it, so edits and deletions show
+// up when the log is exported.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/platform"
+)
+
+const (
+	// DefaultMaxSize is the size at which the active log is rotated
+	DefaultMaxSize = 10 << 20
+
+	activeName = "audit.jsonl"
+	lockName   = "audit.lock"
+)
+
+// Outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeError   = "error"
+)
+
+// Entry is one audited command
+type Entry struct {
+	Seq        int64             `json:"seq"`
+	Time       time.Time         `json:"time"`
+	User       string            `json:"user"`
+	Host       string            `json:"host,omitempty"`
+	Command    string            `json:"command"`
+	Args       []string          `json:"args,omitempty"`
+	Flags      map[string]string `json:"flags,omitempty"`
+	TxHashes   []string          `json:"tx_hashes,omitempty"`
+	Network    string            `json:"network,omitempty"`
+	Outcome    string            `json:"outcome"`
+	Error      string            `json:"error,omitempty"`
+	DurationMs int64             `json:"duration_ms"`
+	Version    string            `json:"version"`
+	PrevHash   string            `json:"prev_hash"`
+	Hash       string            `json:"hash"`
+}
+
+// ComputeHash hashes e with its Hash field cleared
+func (e Entry) ComputeHash() (string, error) {
+	e.Hash = ""
+	data, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Log appends entries to audit.jsonl under Dir. Once the file reaches
+// MaxSize it is renamed to audit-<timestamp>.jsonl and a new one started;
+// with MaxFiles above zero only that many rotated files are kept.
+type Log struct {
+	Dir      string
+	MaxSize  int64
+	MaxFiles int
+	Getenv   func(string) string
+	Now      func() time.Time
+}
+
+// NewLog returns the audit log in the erst data directory. ERST_AUDIT_MAX_SIZE
+// (bytes) and ERST_AUDIT_MAX_FILES override the rotation defaults.
+func NewLog() (*Log, error) {
+	dir, err := platform.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	l := &Log{
+		Dir:     filepath.Join(dir, "audit"),
+		MaxSize: DefaultMaxSize,
+		Getenv:  os.Getenv,
+		Now:     time.Now,
+	}
+	if v := l.Getenv("ERST_AUDIT_MAX_SIZE"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid ERST_AUDIT_MAX_SIZE %q", v)
+		}
+		l.MaxSize = n
+	}
+	if v := l.Getenv("ERST_AUDIT_MAX_FILES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid ERST_AUDIT_MAX_FILES %q", v)
+		}
+		l.MaxFiles = n
+	}
+	return l, nil
+}
+
+// Disabled reports whether ERST_AUDIT turns the log off
+func (l *Log) Disabled() bool {
+	switch strings.ToLower(l.Getenv("ERST_AUDIT")) {
+	case "0", "off", "false", "no":
+		return true
+	}
+	return false
+}
+
+func (l *Log) activePath() string { return filepath.Join(l.Dir, activeName) }
+
+// CurrentUser names who runs erst: ERST_AUDIT_USER, then the OS account
+func (l *Log) CurrentUser() string {
+	if u := l.Getenv("ERST_AUDIT_USER"); u != "" {
+		return u
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if u := l.Getenv("USER"); u != "" {
+		return u
+	}
+	return "unknown"
+}
+
+// Append fills in the sequence number, time, user, host and hash chain of
+// e and writes it, rotating the active file first when it is full. A lock
+// file keeps concurrent erst processes from chaining to the same entry.
+func (l *Log) Append(e Entry) (Entry, error) {
+	if err := os.MkdirAll(l.Dir, 0700); err != nil {
+		return e, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+	unlock, err := l.lock()
+	if err != nil {
+		return e, err
+	}
+	defer unlock()
+
+	last, size, err := lastEntry(l.activePath())
+	if err != nil {
+		return e, err
+	}
+	if last == nil {
+		// The chain continues from the newest rotated file
+		if files, err := l.rotated(); err == nil && len(files) > 0 {
+			if last, _, err = lastEntry(files[len(files)-1]); err != nil {
+				return e, err
+			}
+		}
+	}
+	if size >= l.MaxSize && l.MaxSize > 0 {
+		if err := l.rotate(); err != nil {
+			return e, err
+		}
+	}
+
+	e.Seq = 1
+	if last != nil {
+		e.Seq = last.Seq + 1
+		e.PrevHash = last.Hash
+	}
+	if e.Time.IsZero() {
+		e.Time = l.Now().UTC()
+	}
+	if e.User == "" {
+		e.User = l.CurrentUser()
+	}
+	if e.Host == "" {
+		e.Host, _ = os.Hostname()
+	}
+	if e.Hash, err = e.ComputeHash(); err != nil {
+		return e, fmt.Errorf("failed to hash audit entry: %w", err)
+	}
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return e, err
+	}
+	f, err := os.OpenFile(l.activePath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return e, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return e, fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return e, nil
+}
+
+// lock takes the lock guarding the read of the last entry through the
+// write of the next one. It is a separate file because rotation renames
+// the active one.
+func (l *Log) lock() (func(), error) {
+	f, err := os.OpenFile(filepath.Join(l.Dir, lockName), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit lock: %w", err)
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
+
+// rotate renames the active file and prunes old rotated files
+func (l *Log) rotate() error {
+	name := "audit-" + l.Now().UTC().Format("20060102T150405.000000000Z") + ".jsonl"
+	if err := os.Rename(l.activePath(), filepath.Join(l.Dir, name)); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	if l.MaxFiles <= 0 {
+		return nil
+	}
+	files, err := l.rotated()
+	if err != nil {
+		return err
+	}
+	for len(files) > l.MaxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return fmt.Errorf("failed to prune audit log: %w", err)
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// rotated returns the rotated files, oldest first
+func (l *Log) rotated() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(l.Dir, "audit-*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// Files returns every log file, oldest first
+func (l *Log) Files() ([]string, error) {
+	files, err := l.rotated()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(l.activePath()); err == nil {
+		files = append(files, l.activePath())
+	}
+	return files, nil
+}
+
+// tailSize is how much of a log file is read to find its last entry
+const tailSize = 256 << 10
+
+// lastEntry returns the final entry of path and the file's size
+func lastEntry(path string) (*Entry, int64, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	size := info.Size()
+	offset := size - tailSize
+	if offset < 0 {
+		offset = 0
+	}
+	data := make([]byte, size-offset)
+	if _, err := f.ReadAt(data, offset); err != nil && err != io.EOF {
+		return nil, 0, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+	for i := len(lines) - 1; i >= 0; i-- {
+		var e Entry
+		if json.Unmarshal(lines[i], &e) == nil {
+			return &e, size, nil
+		}
+	}
+	return nil, size, nil
+}
+
+// Filter selects entries to read
+type Filter struct {
+	Since  time.Time
+	Until  time.Time
+	TxHash string
+	User   string
+}
+
+func (f Filter) match(e Entry) bool {
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	if f.User != "" && e.User != f.User {
+		return false
+	}
+	if f.TxHash != "" {
+		for _, h := range e.TxHashes {
+			if strings.EqualFold(h, f.TxHash) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Problem is a break in the hash chain
+type Problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Seq     int64  `json:"seq,omitempty"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s:%d: %s", filepath.Base(p.File), p.Line, p.Message)
+}
+
+// Read returns the entries matching filter, oldest first, and every break
+// in the hash chain across all files. The chain is checked over the whole
+// log, not only the matching entries.
+func (l *Log) Read(filter Filter) ([]Entry, []Problem, error) {
+	files, err := l.Files()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []Entry
+	var problems []Problem
+	var prev *Entry
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 4<<20)
+		line := 0
+		for scanner.Scan() {
+			line++
+			var e Entry
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				problems = append(problems, Problem{File: path, Line: line, Message: "unreadable entry"})
+				continue
+			}
+			for _, msg := range chainProblems(prev, e) {
+				problems = append(problems, Problem{File: path, Line: line, Seq: e.Seq, Message: msg})
+			}
+			entry := e
+			prev = &entry
+			if filter.match(e) {
+				entries = append(entries, e)
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read audit log %s: %w", path, err)
+		}
+	}
+	return entries, problems, nil
+}
+
+// VerifyChain checks the hash chain of entries read in order from source,
+// such as a database table. Line is the position of an entry in the list.
+func VerifyChain(source string, entries []Entry) []Problem {
+	var problems []Problem
+	for i, e := range entries {
+		var prev *Entry
+		if i > 0 {
+			prev = &entries[i-1]
+		}
+		for _, msg := range chainProblems(prev, e) {
+			problems = append(problems, Problem{File: source, Line: i + 1, Seq: e.Seq, Message: msg})
+		}
+	}
+	return problems
+}
+
+// chainProblems describes what is wrong with e and its link to prev, the
+// entry before it. The first entry has no prev, since it may follow a
+// pruned file.
+func chainProblems(prev *Entry, e Entry) []string {
+	var out []string
+	if hash, err := e.ComputeHash(); err != nil || hash != e.Hash {
+		out = append(out, "entry was modified")
+	}
+	if prev != nil {
+		switch {
+		case e.PrevHash != prev.Hash:
+			out = append(out, fmt.Sprintf("chain broken after entry %d", prev.Seq))
+		case e.Seq != prev.Seq+1:
+			out = append(out, fmt.Sprintf("entries %d to %d are missing", prev.Seq+1, e.Seq-1))
+		}
+	}
+	return out
+}
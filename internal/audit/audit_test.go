@@ -0,0 +1,218 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testLog(t *testing.T) *Log {
+	t.Helper()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	env := map[string]string{"ERST_AUDIT_USER": "oncall"}
+	return &Log{
+		Dir:     t.TempDir(),
+		MaxSize: DefaultMaxSize,
+		Getenv:  func(k string) string { return env[k] },
+		Now: func() time.Time {
+			now = now.Add(time.Minute)
+			return now
+		},
+	}
+}
+
+func TestAppendChainsEntries(t *testing.T) {
+	l := testLog(t)
+	first, err := l.Append(Entry{Command: "erst debug", TxHashes: []string{"ab"}, Outcome: OutcomeSuccess})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := l.Append(Entry{Command: "erst session save", Outcome: OutcomeSuccess})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Seq != 1 || second.Seq != 2 {
+		t.Errorf("seq = %d, %d; want 1, 2", first.Seq, second.Seq)
+	}
+	if first.PrevHash != "" || second.PrevHash != first.Hash {
+		t.Errorf("second entry does not chain to the first")
+	}
+	if first.User != "oncall" {
+		t.Errorf("user = %q, want ERST_AUDIT_USER", first.User)
+	}
+
+	entries, problems, err := l.Read(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || len(problems) != 0 {
+		t.Fatalf("got %d entries and problems %v", len(entries), problems)
+	}
+
+	entries, _, _ = l.Read(Filter{TxHash: "AB"})
+	if len(entries) != 1 || entries[0].Command != "erst debug" {
+		t.Errorf("tx filter returned %+v", entries)
+	}
+	entries, _, _ = l.Read(Filter{Since: second.Time})
+	if len(entries) != 1 || entries[0].Seq != 2 {
+		t.Errorf("since filter returned %+v", entries)
+	}
+}
+
+func TestReadDetectsTampering(t *testing.T) {
+	l := testLog(t)
+	for _, c := range []string{"erst debug", "erst trace", "erst report"} {
+		if _, err := l.Append(Entry{Command: c, Outcome: OutcomeSuccess}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(l.Dir, activeName)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+
+	// Edit the first entry and drop the second
+	edited := strings.Replace(lines[0], "erst debug", "erst version", 1)
+	if err := os.WriteFile(path, []byte(edited+"\n"+lines[2]+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, problems, err := l.Read(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("problems = %v, want a modified entry and a broken chain", problems)
+	}
+	if !strings.Contains(problems[0].Message, "modified") || !strings.Contains(problems[1].Message, "chain broken") {
+		t.Errorf("unexpected problems %v", problems)
+	}
+}
+
+func TestRotation(t *testing.T) {
+	l := testLog(t)
+	l.MaxSize = 1
+	l.MaxFiles = 2
+	for i := 0; i < 5; i++ {
+		if _, err := l.Append(Entry{Command: "erst debug", Outcome: OutcomeSuccess}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := l.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("files = %v, want 2 rotated and the active one", files)
+	}
+
+	entries, problems, err := l.Read(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("pruning should not break the chain: %v", problems)
+	}
+	if len(entries) != 3 || entries[0].Seq != 3 || entries[2].Seq != 5 {
+		t.Errorf("entries = %+v, want seq 3 to 5", entries)
+	}
+}
+
+func TestConcurrentAppend(t *testing.T) {
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// a Log per writer, as separate erst processes would have
+			l := &Log{Dir: dir, MaxSize: DefaultMaxSize, Getenv: func(string) string { return "" }, Now: time.Now}
+			for j := 0; j < 10; j++ {
+				if _, err := l.Append(Entry{Command: "erst debug", Outcome: OutcomeSuccess}); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	l := &Log{Dir: dir, Getenv: func(string) string { return "" }}
+	entries, problems, err := l.Read(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("concurrent appends broke the chain: %v", problems)
+	}
+	if len(entries) != 80 {
+		t.Fatalf("got %d entries, want 80", len(entries))
+	}
+	for i, e := range entries {
+		if e.Seq != int64(i+1) {
+			t.Fatalf("entry %d has seq %d", i, e.Seq)
+		}
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	l := &Log{Getenv: func(k string) string {
+		if k == "ERST_AUDIT" {
+			return "off"
+		}
+		return ""
+	}}
+	if !l.Disabled() {
+		t.Error("ERST_AUDIT=off should disable the log")
+	}
+}
+
+func TestExport(t *testing.T) {
+	entries := []Entry{{
+		Seq: 1, Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), User: "oncall",
+		Command: "erst debug", Args: []string{"ab"}, Flags: map[string]string{"network": "testnet", "anchor": "acme"},
+		Outcome: OutcomeSuccess, Hash: "h1",
+	}}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, FormatCSV, entries, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "--anchor=acme --network=testnet") {
+		t.Errorf("csv = %q", buf.String())
+	}
+
+	buf.Reset()
+	problems := []Problem{{File: "audit.jsonl", Line: 2, Message: "entry was modified"}}
+	if err := Export(&buf, FormatJSON, entries, problems); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Entries  []Entry   `json:"entries"`
+		Verified bool      `json:"verified"`
+		Problems []Problem `json:"problems"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Verified || len(doc.Problems) != 1 || len(doc.Entries) != 1 {
+		t.Errorf("json export = %+v", doc)
+	}
+
+	if err := Export(&buf, "xml", entries, nil); err == nil {
+		t.Error("unknown format should fail")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats
+const (
+	FormatJSONL = "jsonl"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+)
+
+// Formats lists the supported export formats
+var Formats = []string{FormatJSONL, FormatJSON, FormatCSV}
+
+// Export writes entries to w in the given format. JSON output also
+// carries the chain problems so an export is self-contained evidence.
+func Export(w io.Writer, format string, entries []Entry, problems []Problem) error {
+	switch format {
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		for _, e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	case FormatJSON:
+		if entries == nil {
+			entries = []Entry{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Entries  []Entry   `json:"entries"`
+			Verified bool      `json:"verified"`
+			Problems []Problem `json:"problems,omitempty"`
+		}{entries, len(problems) == 0, problems})
+	case FormatCSV:
+		return writeCSV(w, entries)
+	}
+	return fmt.Errorf("unknown export format %q (supported: %s)", format, strings.Join(Formats, ", "))
+}
+
+func writeCSV(w io.Writer, entries []Entry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"seq", "time", "user", "host", "command", "args", "flags", "tx_hashes", "network", "outcome", "error", "duration_ms", "version", "hash"}); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{
+			strconv.FormatInt(e.Seq, 10),
+			e.Time.UTC().Format(time.RFC3339),
+			e.User,
+			e.Host,
+			e.Command,
+			strings.Join(e.Args, " "),
+			formatFlags(e.Flags),
+			strings.Join(e.TxHashes, " "),
+			e.Network,
+			e.Outcome,
+			e.Error,
+			strconv.FormatInt(e.DurationMs, 10),
+			e.Version,
+			e.Hash,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatFlags renders flags as sorted --name=value pairs
+func formatFlags(flags map[string]string) string {
+	names := make([]string, 0, len(flags))
+	for name := range flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = "--" + name + "=" + flags[name]
+	}
+	return strings.Join(parts, " ")
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
 )
 
 // Config holds cache configuration
@@ -225,6 +226,9 @@ func (m *Manager) Clean(force bool) (*CleanupStatus, error) {
 	fmt.Printf("Maximum size: %s\n", formatBytes(m.config.MaxSizeBytes))
 
 	if !force {
+		if !platform.Interactive() {
+			return status, fmt.Errorf("refusing to clean the cache without confirmation in a non-interactive session; pass --force")
+		}
 		fmt.Print("\nThis will delete the oldest cached files. Continue? (yes/no): ")
 		var response string
 		if _, err := fmt.Scanln(&response); err != nil {
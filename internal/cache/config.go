@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
 )
 
 // GlobalConfig holds the global cache configuration
@@ -33,11 +34,11 @@ func DefaultGlobalConfig() GlobalConfig {
 
 // getConfigPath returns the path to the config file
 func getConfigPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	dir, err := platform.DataDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
+		return "", err
 	}
-	return filepath.Join(homeDir, ".erst", "config.json"), nil
+	return filepath.Join(dir, "config.json"), nil
 }
 
 // LoadConfig loads the cache configuration from disk
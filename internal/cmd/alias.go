@@ -0,0 +1,292 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// maxAliasDepth bounds how many aliases may expand into one another
+const maxAliasDepth = 10
+
+// userAlias is an alias and where it is defined
+type userAlias struct {
+	Expansion string
+	Source    string
+}
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Define shortcuts for erst commands",
+	Long: `Aliases expand into an erst command line before it is parsed, so a team can
+standardize invocations without wrapper scripts:
+
+  erst alias set dbg-t 'debug --network testnet --format json'
+  erst dbg-t abc123...        # runs: erst debug --network testnet --format json abc123...
+
+An alias must be the first argument. Arguments after it are appended to the
+expansion, unless the expansion refers to them as $1 to $9, or $@ for all of
+them, which makes it a macro:
+
+  erst alias set audit-tx 'security --session $1 --fail-on high'
+
+Aliases saved with 'erst alias set' apply to the current user. Lines such as
+alias.dbg-t = "debug --network testnet" in .erst.toml define aliases for
+everyone working in a project; a saved alias of the same name wins. An alias
+cannot replace a built-in command.`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <expansion>",
+	Short: "Save an alias",
+	Example: `  erst alias set dbg-t 'debug --network testnet --format json'
+  erst alias set audit-tx 'security --session $1 --fail-on high'`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, expansion := args[0], args[1]
+		if isBuiltinCommand(name) {
+			return fmt.Errorf("%q is a built-in command and cannot be an alias", name)
+		}
+		words, err := splitCommandLine(expansion)
+		if err != nil {
+			return fmt.Errorf("invalid expansion: %w", err)
+		}
+		if len(words) == 0 {
+			return fmt.Errorf("expansion of %s is empty", name)
+		}
+		if words[0] == "erst" {
+			return fmt.Errorf("leave out the leading 'erst': erst alias set %s '%s'", name, strings.Join(words[1:], " "))
+		}
+		if err := config.SetAlias(name, expansion); err != nil {
+			return err
+		}
+		fmt.Printf("Alias %s saved: erst %s\n", name, expansion)
+		return nil
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases and where they are defined",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		aliases, err := loadAliases()
+		if err != nil {
+			return err
+		}
+		if len(aliases) == 0 {
+			fmt.Println("No aliases defined. Add one with: erst alias set <name> <expansion>")
+			return nil
+		}
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			a := aliases[name]
+			fmt.Printf("%s = %s  (%s)\n", name, a.Expansion, a.Source)
+		}
+		return nil
+	},
+}
+
+var aliasRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove a saved alias",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.RemoveAlias(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Alias %s removed\n", args[0])
+		return nil
+	},
+}
+
+// loadAliases returns the aliases of the TOML configuration, overridden by
+// those saved with 'erst alias set'
+func loadAliases() (map[string]userAlias, error) {
+	aliases := make(map[string]userAlias)
+	if cfg, err := config.Load(); err != nil {
+		logger.Logger.Debug("Ignoring aliases in the TOML configuration", "error", err)
+	} else {
+		for name, expansion := range cfg.Aliases {
+			aliases[name] = userAlias{Expansion: expansion, Source: ".erst.toml"}
+		}
+	}
+
+	saved, err := config.LoadAliases()
+	if err != nil {
+		return nil, err
+	}
+	path, _ := config.GetAliasConfigPath()
+	for name, expansion := range saved.Aliases {
+		aliases[name] = userAlias{Expansion: expansion, Source: path}
+	}
+	return aliases, nil
+}
+
+// isBuiltinCommand reports whether name is a command of erst or one of its
+// cobra aliases
+func isBuiltinCommand(name string) bool {
+	switch name {
+	case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandAliases replaces a leading alias in args with its expansion,
+// repeatedly when it expands into another alias
+func expandAliases(args []string, aliases map[string]userAlias) ([]string, error) {
+	seen := make(map[string]bool)
+	for depth := 0; len(args) > 0; depth++ {
+		name := args[0]
+		a, ok := aliases[name]
+		if !ok || isBuiltinCommand(name) {
+			return args, nil
+		}
+		if seen[name] || depth >= maxAliasDepth {
+			return nil, fmt.Errorf("alias %s expands into itself", name)
+		}
+		seen[name] = true
+
+		words, err := splitCommandLine(a.Expansion)
+		if err != nil {
+			return nil, fmt.Errorf("alias %s: %w", name, err)
+		}
+		expanded, err := substituteAliasArgs(words, args[1:])
+		if err != nil {
+			return nil, fmt.Errorf("alias %s: %w", name, err)
+		}
+		logger.Logger.Debug("Expanded alias", "alias", name, "args", expanded)
+		args = expanded
+	}
+	return args, nil
+}
+
+// substituteAliasArgs fills $1 to $9 and $@ in words with args. Arguments
+// beyond the highest one referred to are appended.
+func substituteAliasArgs(words, args []string) ([]string, error) {
+	used := 0
+	all := false
+	var out []string
+	for _, w := range words {
+		if w == "$@" {
+			out = append(out, args...)
+			all = true
+			continue
+		}
+		var b strings.Builder
+		for i := 0; i < len(w); i++ {
+			if w[i] == '$' && i+1 < len(w) && w[i+1] >= '1' && w[i+1] <= '9' {
+				n, _ := strconv.Atoi(w[i+1 : i+2])
+				if n > len(args) {
+					return nil, fmt.Errorf("needs at least %d argument(s), got %d", n, len(args))
+				}
+				b.WriteString(args[n-1])
+				used = max(used, n)
+				i++
+				continue
+			}
+			b.WriteByte(w[i])
+		}
+		out = append(out, b.String())
+	}
+	if !all {
+		out = append(out, args[used:]...)
+	}
+	return out, nil
+}
+
+// splitCommandLine splits s into words like a POSIX shell, honoring single
+// and double quotes and backslash escapes, without any other expansion
+func splitCommandLine(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				cur.WriteByte(c)
+			}
+		case quote == '"':
+			switch {
+			case c == '"':
+				quote = 0
+			case c == '\\' && i+1 < len(s) && strings.IndexByte(`"\$`, s[i+1]) >= 0:
+				i++
+				cur.WriteByte(s[i])
+			default:
+				cur.WriteByte(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == '\\':
+			if i+1 == len(s) {
+				return nil, fmt.Errorf("trailing backslash")
+			}
+			i++
+			cur.WriteByte(s[i])
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// commandLineArgs returns the arguments erst was started with, with a
+// leading alias expanded
+func commandLineArgs() ([]string, error) {
+	args := os.Args[1:]
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(args[0]) {
+		return args, nil
+	}
+	aliases, err := loadAliases()
+	if err != nil {
+		return nil, err
+	}
+	return expandAliases(args, aliases)
+}
+
+func init() {
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasRemoveCmd)
+	rootCmd.AddCommand(aliasCmd)
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	words, err := splitCommandLine(`debug --network testnet  --note 'two words' "say \"hi\"" a\ b`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"debug", "--network", "testnet", "--note", "two words", `say "hi"`, "a b"}, words)
+
+	words, err = splitCommandLine(`--empty ''`)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"--empty", ""}, words)
+
+	_, err = splitCommandLine(`debug 'open`)
+	assert.ErrorContains(t, err, "unterminated")
+}
+
+func TestExpandAliases(t *testing.T) {
+	aliases := map[string]userAlias{
+		"dbg-t":    {Expansion: "debug --network testnet --format json"},
+		"audit-tx": {Expansion: "security --session $1 --fail-on high"},
+		"all":      {Expansion: "session list $@ --limit 5"},
+		"chain":    {Expansion: "dbg-t --verbose"},
+		"loop-a":   {Expansion: "loop-b"},
+		"loop-b":   {Expansion: "loop-a x"},
+		"debug":    {Expansion: "version"},
+	}
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"dbg-t", "abc"}, []string{"debug", "--network", "testnet", "--format", "json", "abc"}},
+		{[]string{"audit-tx", "s1", "--format", "json"}, []string{"security", "--session", "s1", "--fail-on", "high", "--format", "json"}},
+		{[]string{"all", "a", "b"}, []string{"session", "list", "a", "b", "--limit", "5"}},
+		{[]string{"chain", "abc"}, []string{"debug", "--network", "testnet", "--format", "json", "--verbose", "abc"}},
+		{[]string{"debug", "abc"}, []string{"debug", "abc"}},
+		{[]string{"unknown"}, []string{"unknown"}},
+	}
+	for _, tt := range tests {
+		got, err := expandAliases(tt.args, aliases)
+		require.NoError(t, err, tt.args)
+		assert.Equal(t, tt.want, got, tt.args)
+	}
+
+	_, err := expandAliases([]string{"loop-a"}, aliases)
+	assert.ErrorContains(t, err, "expands into itself")
+
+	_, err = expandAliases([]string{"audit-tx"}, aliases)
+	assert.ErrorContains(t, err, "needs at least 1 argument(s)")
+}
+
+func TestAliasCommands(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+
+	require.NoError(t, aliasSetCmd.RunE(aliasSetCmd, []string{"dbg-t", "debug --network testnet"}))
+	assert.ErrorContains(t, aliasSetCmd.RunE(aliasSetCmd, []string{"debug", "version"}), "built-in command")
+	assert.ErrorContains(t, aliasSetCmd.RunE(aliasSetCmd, []string{"x", "erst debug"}), "leave out the leading 'erst'")
+	assert.ErrorContains(t, aliasSetCmd.RunE(aliasSetCmd, []string{"Bad", "debug"}), "alias name")
+
+	aliases, err := loadAliases()
+	require.NoError(t, err)
+	assert.Equal(t, "debug --network testnet", aliases["dbg-t"].Expansion)
+
+	require.NoError(t, aliasRemoveCmd.RunE(aliasRemoveCmd, []string{"dbg-t"}))
+	assert.ErrorContains(t, aliasRemoveCmd.RunE(aliasRemoveCmd, []string{"dbg-t"}), "not found")
+
+	saved, err := config.LoadAliases()
+	require.NoError(t, err)
+	assert.Empty(t, saved.Aliases)
+}
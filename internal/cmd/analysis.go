@@ -0,0 +1,331 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/tokenflow"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+// sessionAnalysis is what the follow-up analysis commands need from a
+// stored session
+type sessionAnalysis struct {
+	Session    *session.SessionData
+	Source     string
+	Simulation *simulator.SimulationResponse
+}
+
+// loadSessionAnalysis resolves the session id, or the current session, and
+// the simulation of its latest run or of the named checkpoint
+func loadSessionAnalysis(ctx context.Context, id, checkpoint string) (*sessionAnalysis, error) {
+	data, err := resolveSession(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &sessionAnalysis{Session: data, Source: "latest run"}
+	a.Simulation, err = data.ToSimulationResponse()
+	if checkpoint != "" {
+		run, findErr := data.FindRun(checkpoint)
+		if findErr != nil {
+			return nil, findErr
+		}
+		a.Source = "checkpoint " + run.Name
+		a.Simulation, err = run.ToSimulationResponse()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("session %s: %w", data.ID, err)
+	}
+	return a, nil
+}
+
+// transaction returns the stored transaction as the network returned it
+func (a *sessionAnalysis) transaction() *rpc.TransactionResponse {
+	return &rpc.TransactionResponse{
+		EnvelopeXdr:   a.Session.EnvelopeXdr,
+		ResultXdr:     a.Session.ResultXdr,
+		ResultMetaXdr: a.Session.ResultMetaXdr,
+	}
+}
+
+// passphrase returns the passphrase of the session's network, or "" when the
+// network is not configured
+func (a *sessionAnalysis) passphrase() string {
+	networks, err := configuredNetworks([]string{a.Session.Network})
+	if err != nil || len(networks) == 0 {
+		return ""
+	}
+	return networks[0].NetworkPassphrase
+}
+
+// printSecurityFindings prints the findings of a security analysis, verified
+// risks marked with [!]
+func printSecurityFindings(findings []security.Finding) {
+	for _, finding := range findings {
+		visualizer.Record("finding", string(finding.Severity), string(finding.Type), finding.Title)
+	}
+	if len(findings) == 0 {
+		fmt.Printf("%s No security issues detected\n", visualizer.Success())
+		return
+	}
+
+	verifiedCount := 0
+	heuristicCount := 0
+	for _, finding := range findings {
+		if finding.Type == security.FindingVerifiedRisk {
+			verifiedCount++
+		} else {
+			heuristicCount++
+		}
+	}
+
+	if verifiedCount > 0 {
+		fmt.Printf("\n[!]  VERIFIED SECURITY RISKS: %d\n", verifiedCount)
+	}
+	if heuristicCount > 0 {
+		fmt.Printf("* HEURISTIC WARNINGS: %d\n", heuristicCount)
+	}
+
+	fmt.Printf("\nFindings:\n")
+	for i, finding := range findings {
+		icon := "*"
+		if finding.Type == security.FindingVerifiedRisk {
+			icon = "[!]"
+		}
+		fmt.Printf("%d. %s [%s] %s - %s\n", i+1, icon, finding.Type, finding.Severity, finding.Title)
+		fmt.Printf("   %s\n", finding.Description)
+		if finding.Evidence != "" {
+			fmt.Printf("   Evidence: %s\n", finding.Evidence)
+		}
+		if finding.Score != nil {
+			fmt.Printf("   Score: %.1f (%s)\n", finding.Score.Value, finding.Score.Vector)
+		}
+		if finding.Rule != "" {
+			fmt.Printf("   Rule: %s\n", finding.Rule)
+		}
+		if finding.ID != "" {
+			fmt.Printf("   ID: %s\n", finding.ID)
+		}
+	}
+}
+
+// printTokenFlows prints the transfers of report, their approximate value
+// and a Mermaid chart
+func printTokenFlows(report *tokenflow.Report) {
+	fmt.Printf("\nToken Flow Summary:\n")
+	for _, line := range report.SummaryLines() {
+		fmt.Printf("  %s\n", line)
+	}
+	if total, ok := report.TotalUSD(); ok {
+		fmt.Printf("  Approximate value moved: ~%s\n", tokenflow.FormatUSD(total))
+	}
+	if visualizer.Accessible() {
+		fmt.Printf("\nToken flow chart omitted in accessible mode; the summary above lists every transfer.\n")
+	} else {
+		fmt.Printf("\nToken Flow Chart (Mermaid):\n")
+		fmt.Println(report.MermaidFlowchart())
+	}
+}
+
+// printBalanceVerification compares report with the balance changes in
+// resultMetaXdr and returns the mismatches it printed
+func printBalanceVerification(report *tokenflow.Report, resultMetaXdr, passphrase string) []tokenflow.Discrepancy {
+	discrepancies, err := tokenflow.VerifyBalances(report, resultMetaXdr, passphrase)
+	if err != nil {
+		logger.Logger.Warn("Balance verification failed", "error", err)
+		return nil
+	}
+	for _, op := range report.Unmodeled {
+		fmt.Printf("\n%s Balance Verification: operation %d (%s) is not modeled by the token flow and was not checked\n", visualizer.Warning(), op.OpIndex, op.Type)
+	}
+	if len(discrepancies) == 0 {
+		fmt.Printf("\n%s Balance Verification: token flow matches ledger changes\n", visualizer.Success())
+		return nil
+	}
+	fmt.Printf("\n%s Balance Verification: %d mismatch(es) between token flow and ledger changes\n", visualizer.Warning(), len(discrepancies))
+	for _, d := range discrepancies {
+		fmt.Printf("  %s\n", d)
+	}
+	return discrepancies
+}
+
+// analysisInput is the transaction a standalone analysis runs against
+type analysisInput struct {
+	// Source describes where the transaction came from, for headings
+	Source        string `json:"source"`
+	TxHash        string `json:"tx_hash,omitempty"`
+	Network       string `json:"network,omitempty"`
+	SessionID     string `json:"session_id,omitempty"`
+	EnvelopeXdr   string `json:"-"`
+	ResultMetaXdr string `json:"-"`
+	Passphrase    string `json:"-"`
+
+	// Simulation is the stored simulation, nil for fetched or raw input
+	Simulation    *simulator.SimulationResponse  `json:"-"`
+	TokenMetadata map[string]tokenflow.TokenMeta `json:"-"`
+	// LedgerEntries are the entries recorded with a session, if any
+	LedgerEntries map[string]string `json:"-"`
+	// Session is the stored session, nil for fetched or raw input
+	Session *session.SessionData `json:"-"`
+}
+
+// sessionJSON returns the input as session JSON, as external scanners
+// receive it
+func (in *analysisInput) sessionJSON() ([]byte, error) {
+	data := in.Session
+	if data == nil {
+		data = &session.SessionData{
+			Network:       in.Network,
+			TxHash:        in.TxHash,
+			EnvelopeXdr:   in.EnvelopeXdr,
+			ResultMetaXdr: in.ResultMetaXdr,
+		}
+	}
+	return json.Marshal(data)
+}
+
+// analysisSource holds the input flags shared by erst security and erst
+// tokenflow
+type analysisSource struct {
+	session    string
+	checkpoint string
+	envelope   string
+	resultMeta string
+	network    string
+	rpcURL     string
+	format     string
+	formats    []string
+}
+
+// register adds the input flags and a --format flag accepting formats, the
+// first being the default
+func (f *analysisSource) register(cmd *cobra.Command, formats ...string) {
+	f.formats = formats
+	cmd.Flags().StringVar(&f.session, "session", "", "Analyze this stored session")
+	cmd.Flags().StringVar(&f.checkpoint, "checkpoint", "", "Session checkpoint to analyze instead of the latest run")
+	cmd.Flags().StringVar(&f.envelope, "envelope", "", "Transaction envelope to analyze, as base64 or @file with XDR or Stellar Lab/SDK JSON")
+	cmd.Flags().StringVar(&f.resultMeta, "result-meta", "", "Result meta XDR of --envelope, as base64 or @file")
+	cmd.Flags().StringVarP(&f.network, "network", "n", string(rpc.Mainnet), "Stellar network of the transaction (testnet, mainnet, futurenet)")
+	cmd.Flags().StringVar(&f.rpcURL, "rpc-url", "", "Custom Horizon RPC URL")
+	cmd.Flags().StringVar(&f.format, "format", formats[0], "Output format: "+strings.Join(formats, ", "))
+}
+
+// checkFormat rejects an unsupported --format value
+func (f *analysisSource) checkFormat() error {
+	for _, known := range f.formats {
+		if f.format == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported format %q (supported: %s)", f.format, strings.Join(f.formats, ", "))
+}
+
+// load resolves the input: a transaction hash or session ID argument,
+// --session, or an --envelope and --result-meta pair. Without any, the
+// current session is used.
+func (f *analysisSource) load(cmd *cobra.Command, args []string) (*analysisInput, error) {
+	given := 0
+	for _, set := range []bool{len(args) > 0, f.session != "", f.envelope != ""} {
+		if set {
+			given++
+		}
+	}
+	if given > 1 {
+		return nil, fmt.Errorf("give one of a transaction hash or session ID, --session, or --envelope")
+	}
+	if f.resultMeta != "" && f.envelope == "" {
+		return nil, fmt.Errorf("--result-meta requires --envelope")
+	}
+	if f.checkpoint != "" && f.envelope != "" {
+		return nil, fmt.Errorf("--checkpoint applies to sessions only")
+	}
+
+	switch {
+	case f.envelope != "":
+		return f.loadXDR()
+	case len(args) > 0 && rpc.ValidateTransactionHash(args[0]) == nil:
+		if f.checkpoint != "" {
+			return nil, fmt.Errorf("--checkpoint applies to sessions only")
+		}
+		return f.fetch(cmd.Context(), args[0])
+	case len(args) > 0:
+		return f.loadSession(cmd.Context(), args[0])
+	}
+	return f.loadSession(cmd.Context(), f.session)
+}
+
+func (f *analysisSource) loadSession(ctx context.Context, id string) (*analysisInput, error) {
+	a, err := loadSessionAnalysis(ctx, id, f.checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := sessionLedgerEntries(a.Session, f.checkpoint)
+	if err != nil {
+		logger.Logger.Debug("Session has no ledger entries", "session", a.Session.ID, "error", err)
+	}
+	return &analysisInput{
+		Source:        fmt.Sprintf("session %s, %s", a.Session.ID, a.Source),
+		TxHash:        a.Session.TxHash,
+		Network:       a.Session.Network,
+		SessionID:     a.Session.ID,
+		EnvelopeXdr:   a.Session.EnvelopeXdr,
+		ResultMetaXdr: a.Session.ResultMetaXdr,
+		Passphrase:    a.passphrase(),
+		Simulation:    a.Simulation,
+		TokenMetadata: a.Session.TokenMetadata,
+		LedgerEntries: entries,
+		Session:       a.Session,
+	}, nil
+}
+
+func (f *analysisSource) fetch(ctx context.Context, txHash string) (*analysisInput, error) {
+	opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(f.network))}
+	if f.rpcURL != "" {
+		opts = append(opts, rpc.WithHorizonURL(f.rpcURL))
+	}
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	resp, err := client.GetTransaction(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+	return &analysisInput{
+		Source:        "transaction " + txHash,
+		TxHash:        txHash,
+		Network:       f.network,
+		EnvelopeXdr:   resp.EnvelopeXdr,
+		ResultMetaXdr: resp.ResultMetaXdr,
+		Passphrase:    client.Config.NetworkPassphrase,
+	}, nil
+}
+
+func (f *analysisSource) loadXDR() (*analysisInput, error) {
+	envelope, err := readEnvelopeArg(f.envelope)
+	if err != nil {
+		return nil, err
+	}
+	in := &analysisInput{Source: "envelope XDR", Network: f.network, EnvelopeXdr: envelope}
+	if f.resultMeta != "" {
+		if in.ResultMetaXdr, err = readXDRArg(f.resultMeta); err != nil {
+			return nil, err
+		}
+	}
+	if networks, err := configuredNetworks([]string{f.network}); err == nil {
+		in.Passphrase = networks[0].NetworkPassphrase
+	}
+	return in, nil
+}
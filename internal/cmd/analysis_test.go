@@ -0,0 +1,113 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func saveAnalysisSession(t *testing.T) {
+	t.Helper()
+	t.Setenv("ERST_HOME", t.TempDir())
+	saved := sessions
+	sessions = session.NewManager("")
+	t.Cleanup(func() { sessions = saved })
+
+	ctx := context.Background()
+	first := &session.SessionData{Network: "testnet", TxHash: "abcd"}
+	require.NoError(t, first.AddRun(session.Run{Name: session.DefaultRunName, SimResponseJSON: `{"status":"error","error":"HostError: trap"}`}))
+	_, err := recordCheckpoint(ctx, "s1", first)
+	require.NoError(t, err)
+
+	next := &session.SessionData{Network: "testnet", TxHash: "abcd"}
+	require.NoError(t, next.AddRun(session.Run{Name: "fixed", SimResponseJSON: `{"status":"success"}`}))
+	_, err = recordCheckpoint(ctx, "s1", next)
+	require.NoError(t, err)
+}
+
+func TestLoadSessionAnalysis(t *testing.T) {
+	saveAnalysisSession(t)
+	ctx := context.Background()
+
+	a, err := loadSessionAnalysis(ctx, "s1", "")
+	require.NoError(t, err)
+	assert.Equal(t, "latest run", a.Source)
+	assert.Equal(t, "success", a.Simulation.Status)
+	assert.Equal(t, "Test SDF Network ; September 2015", a.passphrase())
+
+	a, err = loadSessionAnalysis(ctx, "s1", session.DefaultRunName)
+	require.NoError(t, err)
+	assert.Equal(t, "checkpoint original", a.Source)
+	assert.Equal(t, "error", a.Simulation.Status)
+
+	_, err = loadSessionAnalysis(ctx, "s1", "missing")
+	assert.Error(t, err)
+	_, err = loadSessionAnalysis(ctx, "", "")
+	assert.ErrorIs(t, err, session.ErrNoActiveSession)
+}
+
+func TestLoadExecutionTraceFromSession(t *testing.T) {
+	saveAnalysisSession(t)
+	traceSessionFlag, traceCheckpoint = "s1", session.DefaultRunName
+	t.Cleanup(func() { traceSessionFlag, traceCheckpoint = "", "" })
+	traceCmd.SetContext(context.Background())
+
+	tr, err := loadExecutionTrace(traceCmd, "")
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", tr.TransactionHash)
+	require.Len(t, tr.States, 1)
+	assert.Equal(t, "HostError: trap", tr.States[0].Error)
+
+	_, err = loadExecutionTrace(traceCmd, "tx.trace.json")
+	assert.ErrorContains(t, err, "not both")
+
+	traceSessionFlag = ""
+	_, err = loadExecutionTrace(traceCmd, "")
+	assert.ErrorContains(t, err, "--session <id>")
+}
+
+func TestAnalysisSourceLoad(t *testing.T) {
+	saveAnalysisSession(t)
+	cmd := &cobra.Command{}
+	cmd.SetContext(context.Background())
+
+	var f analysisSource
+	f.register(cmd, "text", "json")
+	assert.NoError(t, f.checkFormat())
+	f.format = "yaml"
+	assert.ErrorContains(t, f.checkFormat(), "supported: text, json")
+
+	in, err := f.load(cmd, []string{"s1"})
+	require.NoError(t, err)
+	assert.Equal(t, "session s1, latest run", in.Source)
+	assert.Equal(t, "s1", in.SessionID)
+	assert.NotNil(t, in.Simulation)
+	assert.NotEmpty(t, in.Passphrase)
+
+	dir := t.TempDir()
+	envelope := filepath.Join(dir, "tx.xdr")
+	require.NoError(t, os.WriteFile(envelope, []byte("AAAA\n"), 0644))
+	f = analysisSource{envelope: "@" + envelope, resultMeta: "BBBB", network: "testnet"}
+	in, err = f.load(cmd, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "AAAA", in.EnvelopeXdr)
+	assert.Equal(t, "BBBB", in.ResultMetaXdr)
+	assert.Equal(t, "Test SDF Network ; September 2015", in.Passphrase)
+	assert.Nil(t, in.Simulation)
+
+	_, err = f.load(cmd, []string{"s1"})
+	assert.ErrorContains(t, err, "give one of")
+	_, err = (&analysisSource{resultMeta: "BBBB"}).load(cmd, nil)
+	assert.ErrorContains(t, err, "requires --envelope")
+	_, err = (&analysisSource{envelope: "AAAA", checkpoint: "original"}).load(cmd, nil)
+	assert.ErrorContains(t, err, "sessions only")
+}
@@ -0,0 +1,176 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/spf13/cobra"
+)
+
+// currentAPIVersion is the version of the JSON output of this erst. Bump it,
+// and add an apiVersion entry for the previous one, whenever a field of any
+// command's JSON output is renamed, removed or changes meaning.
+const currentAPIVersion = 2
+
+// apiVersion describes how to produce the JSON output of an older version
+type apiVersion struct {
+	// Deprecated is shown as a warning when the version is requested, for
+	// example "it will be removed in erst 2.0"
+	Deprecated string
+	// Downgrade converts the output of command (its path, such as
+	// "erst security") from the next version to this one. It is only called
+	// for commands it lists in Commands.
+	Commands  []string
+	Downgrade func(command string, doc any) any
+}
+
+// apiVersions lists the supported versions other than the current one
+var apiVersions = map[int]apiVersion{
+	// Version 2 raises security findings from the fields of typed simulation
+	// events and names the event each came from in event_id
+	1: {
+		Commands:  []string{"erst security", "erst security history"},
+		Downgrade: func(_ string, doc any) any { return dropKey(doc, "event_id") },
+	},
+}
+
+// APIVersionFlag is the output version requested with --api-version, 0 for
+// the current one
+var APIVersionFlag int
+
+// supportedAPIVersions returns the versions that can be requested, oldest
+// first
+func supportedAPIVersions() []int {
+	versions := []int{currentAPIVersion}
+	for v := range apiVersions {
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// resolveAPIVersion returns the requested output version, from --api-version
+// or ERST_API_VERSION
+func resolveAPIVersion() (int, error) {
+	version := APIVersionFlag
+	if version == 0 {
+		if env := os.Getenv("ERST_API_VERSION"); env != "" {
+			v, err := strconv.Atoi(env)
+			if err != nil {
+				return 0, fmt.Errorf("invalid ERST_API_VERSION %q", env)
+			}
+			version = v
+		}
+	}
+	if version == 0 || version == currentAPIVersion {
+		return currentAPIVersion, nil
+	}
+
+	if _, ok := apiVersions[version]; !ok {
+		var names []string
+		for _, v := range supportedAPIVersions() {
+			names = append(names, strconv.Itoa(v))
+		}
+		return 0, fmt.Errorf("unsupported API version %d (supported: %s)", version, strings.Join(names, ", "))
+	}
+	return version, nil
+}
+
+// checkAPIVersion validates the requested output version before a command
+// runs, warning once when it is deprecated
+func checkAPIVersion() error {
+	version, err := resolveAPIVersion()
+	if err != nil {
+		return err
+	}
+	if info := apiVersions[version]; info.Deprecated != "" {
+		logger.Logger.Warn(fmt.Sprintf("API version %d is deprecated: %s", version, info.Deprecated), "current", currentAPIVersion)
+	}
+	return nil
+}
+
+// writeJSON prints v as indented JSON on stdout, in the output version
+// requested for the running command
+func writeJSON(cmd *cobra.Command, v any) error {
+	version, err := resolveAPIVersion()
+	if err != nil {
+		return err
+	}
+	doc, err := downgradeJSON(cmd.CommandPath(), v, version)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// downgradeJSON converts v, the current output of command, to version. It
+// returns v itself when no conversion applies, so that field order is kept.
+func downgradeJSON(command string, v any, version int) (any, error) {
+	var doc any
+	converted := false
+	for from := currentAPIVersion - 1; from >= version; from-- {
+		info, ok := apiVersions[from]
+		if !ok || info.Downgrade == nil || !containsString(info.Commands, command) {
+			continue
+		}
+		if !converted {
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(raw, &doc); err != nil {
+				return nil, err
+			}
+			converted = true
+		}
+		doc = info.Downgrade(command, doc)
+	}
+	if !converted {
+		return v, nil
+	}
+	return doc, nil
+}
+
+// dropKey removes key from every object within doc
+func dropKey(doc any, key string) any {
+	switch v := doc.(type) {
+	case map[string]any:
+		delete(v, key)
+		for k, child := range v {
+			v[k] = dropKey(child, key)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = dropKey(child, key)
+		}
+	}
+	return doc
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.PersistentFlags().IntVar(
+		&APIVersionFlag,
+		"api-version",
+		0,
+		"Version of the JSON output to produce, for scripts that pin field names (also ERST_API_VERSION; default the latest)",
+	)
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveAPIVersion(t *testing.T) {
+	defer func() { APIVersionFlag = 0 }()
+
+	v, err := resolveAPIVersion()
+	require.NoError(t, err)
+	assert.Equal(t, currentAPIVersion, v)
+
+	APIVersionFlag = currentAPIVersion
+	v, err = resolveAPIVersion()
+	require.NoError(t, err)
+	assert.Equal(t, currentAPIVersion, v)
+
+	APIVersionFlag = currentAPIVersion + 1
+	_, err = resolveAPIVersion()
+	assert.ErrorContains(t, err, "unsupported API version")
+
+	APIVersionFlag = 0
+	t.Setenv("ERST_API_VERSION", "one")
+	_, err = resolveAPIVersion()
+	assert.ErrorContains(t, err, "invalid ERST_API_VERSION")
+}
+
+func TestDowngradeJSON(t *testing.T) {
+	// Pretend the current output renamed "tx" to "tx_hash", and an older
+	// version before that named it "hash"
+	saved := apiVersions
+	defer func() { apiVersions = saved }()
+	rename := func(from, to string) func(string, any) any {
+		return func(_ string, doc any) any {
+			m := doc.(map[string]any)
+			m[to] = m[from]
+			delete(m, from)
+			return m
+		}
+	}
+	apiVersions = map[int]apiVersion{
+		currentAPIVersion - 1: {Commands: []string{"erst demo"}, Downgrade: rename("tx_hash", "tx")},
+		currentAPIVersion - 2: {Commands: []string{"erst demo"}, Downgrade: rename("tx", "hash"), Deprecated: "it will be removed"},
+	}
+
+	type output struct {
+		TxHash string `json:"tx_hash"`
+	}
+	current := output{TxHash: "abc"}
+
+	doc, err := downgradeJSON("erst demo", current, currentAPIVersion)
+	require.NoError(t, err)
+	assert.Equal(t, current, doc, "the current version is written unchanged")
+
+	doc, err = downgradeJSON("erst demo", current, currentAPIVersion-1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"tx": "abc"}, doc)
+
+	doc, err = downgradeJSON("erst demo", current, currentAPIVersion-2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"hash": "abc"}, doc, "downgrades are applied in turn")
+
+	doc, err = downgradeJSON("erst other", current, currentAPIVersion-2)
+	require.NoError(t, err)
+	assert.Equal(t, current, doc, "commands whose output did not change are unaffected")
+
+	APIVersionFlag = currentAPIVersion - 2
+	defer func() { APIVersionFlag = 0 }()
+	require.NoError(t, checkAPIVersion())
+	assert.Equal(t, []int{currentAPIVersion - 2, currentAPIVersion - 1, currentAPIVersion}, supportedAPIVersions())
+}
+
+func TestDowngradeSecurityEventIDs(t *testing.T) {
+	current := map[string]any{"findings": []any{
+		map[string]any{"title": "Contract Panic/Trap", "event_id": "e2"},
+	}}
+	doc, err := downgradeJSON("erst security", current, 1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"findings": []any{
+		map[string]any{"title": "Contract Panic/Trap"},
+	}}, doc)
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/dotandev/hintents/internal/artifact"
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/objstore"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/spf13/cobra"
+)
+
+var (
+	artifactsLocationFlag  string
+	artifactsNetworkFlag   string
+	artifactsDateFlag      string
+	artifactsOutputDirFlag string
+	artifactsListFlag      bool
+)
+
+var artifactsCmd = &cobra.Command{
+	Use:   "artifacts",
+	Short: "Work with archived debug artifacts",
+	Long: `With "archive" configured in config.json, every 'erst debug' run uploads its
+reports, trace, flamegraph and ledger snapshot to object storage under
+<network>/<tx-hash>/<YYYY-MM-DD>/<timestamp>/, next to an index.json manifest
+with the SHA-256 of each file, for compliance retention:
+
+  {"archive": {"location": "s3://audit-bucket/erst"}}
+
+The location is s3://bucket/prefix, gs://bucket/prefix or a directory.
+Credentials come from the same environment variables as snapshot locations
+(AWS_ACCESS_KEY_ID, GOOGLE_OAUTH_ACCESS_TOKEN, ...).`,
+}
+
+var artifactsFetchCmd = &cobra.Command{
+	Use:   "fetch <tx-hash>",
+	Short: "Download archived artifacts of a transaction",
+	Long: `Download the latest archived debug run of a transaction to
+<output-dir>/<tx-hash>, verifying every file against its recorded SHA-256.
+Use --date to pick the latest run of a given day and --list to see every
+archived run instead.`,
+	Example: `  erst artifacts fetch abc123... --network testnet
+  erst artifacts fetch abc123... --date 2026-01-05 --output-dir ./evidence
+  erst artifacts fetch abc123... --list`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		txHash := args[0]
+		if err := rpc.ValidateTransactionHash(txHash); err != nil {
+			return fmt.Errorf("invalid transaction hash: %w", err)
+		}
+		store, err := archiveStore(artifactsLocationFlag)
+		if err != nil {
+			return err
+		}
+		if store == nil {
+			return fmt.Errorf("no archive configured: add {\"archive\": {\"location\": \"s3://bucket/prefix\"}} to %s or pass --location", generalConfigPath())
+		}
+
+		runs, err := artifact.ArchivedRuns(cmd.Context(), store, artifactsNetworkFlag, txHash, artifactsDateFlag)
+		if err != nil {
+			return err
+		}
+		if len(runs) == 0 {
+			return fmt.Errorf("no archived runs of %s on %s", txHash, artifactsNetworkFlag)
+		}
+		if artifactsListFlag {
+			for _, run := range runs {
+				fmt.Println(store.Location(run))
+			}
+			return nil
+		}
+
+		latest := runs[len(runs)-1]
+		dest := artifact.UniquePath(filepath.Join(artifactsOutputDirFlag, txHash), false)
+		m, err := artifact.Fetch(cmd.Context(), store, latest, dest)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Fetched %d artifact(s) of the run of %s from %s to %s\n",
+			len(m.Artifacts), m.CreatedAt.Format("2006-01-02 15:04:05 UTC"), store.Location(latest), dest)
+		return nil
+	},
+}
+
+// archiveStore opens location, or the configured archive when it is empty.
+// It returns nil when neither is set.
+func archiveStore(location string) (objstore.Store, error) {
+	if location == "" {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Archive == nil || cfg.Archive.Location == "" {
+			return nil, nil
+		}
+		location = cfg.Archive.Location
+	}
+	return objstore.Open(location)
+}
+
+// archiveArtifacts uploads an artifact directory to the configured archive
+// and returns where it went
+func archiveArtifacts(ctx context.Context, store objstore.Store, dir *artifact.Dir) (string, error) {
+	prefix, err := artifact.Archive(ctx, store, dir.Path, &dir.Manifest)
+	if err != nil {
+		return "", err
+	}
+	return store.Location(path.Join(prefix, artifact.IndexName)), nil
+}
+
+func init() {
+	artifactsFetchCmd.Flags().StringVar(&artifactsLocationFlag, "location", "", "Archive location (default: archive.location in config.json)")
+	artifactsFetchCmd.Flags().StringVarP(&artifactsNetworkFlag, "network", "n", string(rpc.Mainnet), "Network the transaction was debugged on")
+	artifactsFetchCmd.Flags().StringVar(&artifactsDateFlag, "date", "", "Only consider runs of this day (YYYY-MM-DD)")
+	artifactsFetchCmd.Flags().StringVar(&artifactsOutputDirFlag, "output-dir", ".", "Directory to download into")
+	artifactsFetchCmd.Flags().BoolVar(&artifactsListFlag, "list", false, "List the archived runs instead of downloading")
+	artifactsCmd.AddCommand(artifactsFetchCmd)
+	rootCmd.AddCommand(artifactsCmd)
+}
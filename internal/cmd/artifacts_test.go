@@ -0,0 +1,63 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/artifact"
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveAndFetchArtifacts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("ERST_HOME", home)
+	archiveDir := t.TempDir()
+	data, err := json.Marshal(config.Config{Archive: &config.ArchiveConfig{Location: archiveDir}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.json"), data, 0600))
+
+	txHash := strings.Repeat("ab", 32)
+	dir, err := artifact.Create(t.TempDir(), txHash, false)
+	require.NoError(t, err)
+	dir.Manifest.Network = "testnet"
+	_, err = dir.Write("report.html", artifact.KindReport, "", []byte("<p>report</p>"))
+	require.NoError(t, err)
+	_, err = dir.Close()
+	require.NoError(t, err)
+
+	store, err := archiveStore("")
+	require.NoError(t, err)
+	require.NotNil(t, store)
+	location, err := archiveArtifacts(context.Background(), store, dir)
+	require.NoError(t, err)
+	assert.Contains(t, location, filepath.Join(archiveDir, "testnet", txHash))
+
+	out := t.TempDir()
+	artifactsNetworkFlag, artifactsOutputDirFlag = "testnet", out
+	t.Cleanup(func() { artifactsNetworkFlag, artifactsOutputDirFlag = "mainnet", "." })
+	require.NoError(t, artifactsFetchCmd.RunE(artifactsFetchCmd, []string{txHash}))
+
+	got, err := os.ReadFile(filepath.Join(out, txHash, "report.html"))
+	require.NoError(t, err)
+	assert.Equal(t, "<p>report</p>", string(got))
+
+	artifactsNetworkFlag = "mainnet"
+	assert.ErrorContains(t, artifactsFetchCmd.RunE(artifactsFetchCmd, []string{txHash}), "no archived runs")
+}
+
+func TestArchiveStoreUnconfigured(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	store, err := archiveStore("")
+	require.NoError(t, err)
+	assert.Nil(t, store)
+	assert.ErrorContains(t, artifactsFetchCmd.RunE(artifactsFetchCmd, []string{strings.Repeat("ab", 32)}), "no archive configured")
+}
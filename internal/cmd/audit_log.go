@@ -0,0 +1,250 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/audit"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	auditFormatFlag string
+	auditOutputFlag string
+	auditSinceFlag  string
+	auditUntilFlag  string
+	auditTxFlag     string
+	auditUserFlag   string
+
+	auditStorageFlag string
+)
+
+// commandArgs are the positional arguments of the running command
+var commandArgs []string
+
+var (
+	txHashArgRe    = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+	secretFlagRe   = regexp.MustCompile(`(?i)token|secret|password|passwd|key|auth|credential`)
+	auditSkipNames = map[string]bool{"help": true, "completion": true, "__complete": true, "__completeNoDesc": true}
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Export the audit log of erst commands",
+	Long: `Every erst command is recorded in an append-only audit log in the erst data
+directory: who ran it and on which host, when, the command with its arguments
+and changed flags, the transactions and network involved, and whether it
+succeeded. Values of flags that look like credentials are not recorded.
+
+Each entry carries the SHA-256 hash of the entry before it, so a modified,
+removed or reordered entry is reported when the log is exported.
+
+The log rotates at 10 MiB (ERST_AUDIT_MAX_SIZE, in bytes). Rotated files are
+kept unless ERST_AUDIT_MAX_FILES limits how many remain. ERST_AUDIT_USER
+overrides the recorded user name and ERST_AUDIT=off disables the log.
+
+Available subcommands:
+  export - Write audit entries as JSON Lines, JSON or CSV
+  verify - Check the hash chain of the local log or of the serve database`,
+}
+
+var auditExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export audit log entries",
+	Long: `Write the audit log, optionally narrowed to a time range, transaction or user,
+to stdout or a file. The whole hash chain is verified first; any break is
+reported on stderr and in the "problems" field of --format json, and the
+command exits with an error after writing the export.`,
+	Example: `  erst audit export --format csv --output incident-4711.csv
+  erst audit export --since 2026-03-01 --until 2026-03-08 --format json
+  erst audit export --tx 5c0a1b2c...`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := audit.Filter{TxHash: auditTxFlag, User: auditUserFlag}
+		var err error
+		if filter.Since, err = parseAuditTime(auditSinceFlag); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		if filter.Until, err = parseAuditTime(auditUntilFlag); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+
+		log, err := audit.NewLog()
+		if err != nil {
+			return err
+		}
+		entries, problems, err := log.Read(filter)
+		if err != nil {
+			return err
+		}
+
+		var w io.Writer = os.Stdout
+		if auditOutputFlag != "" {
+			f, err := os.OpenFile(auditOutputFlag, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", auditOutputFlag, err)
+			}
+			defer f.Close()
+			w = f
+		}
+		if err := audit.Export(w, auditFormatFlag, entries, problems); err != nil {
+			return fmt.Errorf("failed to export audit log: %w", err)
+		}
+		if auditOutputFlag != "" {
+			fmt.Fprintf(os.Stderr, "Exported %d audit entries to %s\n", len(entries), auditOutputFlag)
+		}
+
+		if len(problems) > 0 {
+			for _, p := range problems {
+				fmt.Fprintf(os.Stderr, "audit log: %s\n", p)
+			}
+			return fmt.Errorf("audit log failed verification: %d problem(s)", len(problems))
+		}
+		return nil
+	},
+}
+
+var auditVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the hash chain of the audit log",
+	Long: `Check that no audit entry was modified, removed or reordered. Without flags
+the local audit log is checked. With --storage the audit log that erst serve
+keeps in its database is checked instead, across every replica.
+
+Each break is printed on stderr and the command exits with an error.`,
+	Example: `  erst audit verify
+  erst audit verify --storage postgres://erst@db.internal:5432/erst`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var n int
+		var problems []audit.Problem
+		source := "audit log"
+		if auditStorageFlag != "" {
+			db, err := openStorage(cmd.Context(), auditStorageFlag)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			if n, problems, err = db.VerifyAudit(cmd.Context()); err != nil {
+				return err
+			}
+			source = "serve audit log"
+		} else {
+			log, err := audit.NewLog()
+			if err != nil {
+				return err
+			}
+			entries, found, err := log.Read(audit.Filter{})
+			if err != nil {
+				return err
+			}
+			n, problems = len(entries), found
+		}
+
+		if len(problems) > 0 {
+			for _, p := range problems {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", source, p)
+			}
+			return fmt.Errorf("%s failed verification: %d problem(s)", source, len(problems))
+		}
+		fmt.Printf("Verified %d entries in the %s\n", n, source)
+		return nil
+	},
+}
+
+// parseAuditTime accepts RFC 3339 timestamps and plain dates
+func parseAuditTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
+
+// auditEntry describes a finished command for the audit log
+func auditEntry(cmd *cobra.Command, args []string, started time.Time, runErr error) audit.Entry {
+	e := audit.Entry{
+		Command: cmd.CommandPath(),
+		Args:    args,
+		Outcome: audit.OutcomeSuccess,
+		Version: Version,
+	}
+	if !started.IsZero() {
+		e.DurationMs = time.Since(started).Milliseconds()
+	}
+	if runErr != nil {
+		e.Outcome = audit.OutcomeError
+		e.Error = firstLine(runErr.Error(), 500)
+	}
+
+	seen := make(map[string]bool)
+	addHash := func(v string) {
+		if txHashArgRe.MatchString(v) && !seen[strings.ToLower(v)] {
+			seen[strings.ToLower(v)] = true
+			e.TxHashes = append(e.TxHashes, v)
+		}
+	}
+	for _, a := range args {
+		addHash(a)
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if e.Flags == nil {
+			e.Flags = make(map[string]string)
+		}
+		value := f.Value.String()
+		if secretFlagRe.MatchString(f.Name) {
+			value = "<redacted>"
+		}
+		e.Flags[f.Name] = value
+		addHash(value)
+	})
+	if f := cmd.Flags().Lookup("network"); f != nil {
+		e.Network = f.Value.String()
+	}
+	return e
+}
+
+// recordAudit appends the finished command to the audit log. Errors are
+// only logged: auditing must never fail a command.
+func recordAudit(cmd *cobra.Command, args []string, started time.Time, runErr error) {
+	if cmd == nil || auditSkipNames[cmd.Name()] {
+		return
+	}
+	log, err := audit.NewLog()
+	if err != nil {
+		logger.Logger.Debug("Audit log unavailable", "error", err)
+		return
+	}
+	if log.Disabled() {
+		return
+	}
+	if _, err := log.Append(auditEntry(cmd, args, started, runErr)); err != nil {
+		logger.Logger.Warn("Failed to write audit log", "error", err)
+	}
+}
+
+func init() {
+	auditExportCmd.Flags().StringVar(&auditFormatFlag, "format", audit.FormatJSONL, "Export format: jsonl, json or csv")
+	auditExportCmd.Flags().StringVarP(&auditOutputFlag, "output", "o", "", "Write to this file instead of stdout")
+	auditExportCmd.Flags().StringVar(&auditSinceFlag, "since", "", "Only entries at or after this time (RFC 3339 or YYYY-MM-DD)")
+	auditExportCmd.Flags().StringVar(&auditUntilFlag, "until", "", "Only entries before this time (RFC 3339 or YYYY-MM-DD)")
+	auditExportCmd.Flags().StringVar(&auditTxFlag, "tx", "", "Only entries involving this transaction hash")
+	auditExportCmd.Flags().StringVar(&auditUserFlag, "user", "", "Only entries recorded for this user")
+
+	auditVerifyCmd.Flags().StringVar(&auditStorageFlag, "storage", "", "Check the audit log in this serve database (postgres:// URL or SQLite path) instead of the local one")
+
+	auditCmd.AddCommand(auditExportCmd)
+	auditCmd.AddCommand(auditVerifyCmd)
+	rootCmd.AddCommand(auditCmd)
+}
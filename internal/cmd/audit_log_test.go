@@ -0,0 +1,91 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/audit"
+	"github.com/dotandev/hintents/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEntry(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	c := &cobra.Command{Use: "debug"}
+	c.Flags().String("network", "mainnet", "")
+	c.Flags().String("rpc-token", "", "")
+	c.Flags().String("anchor", "", "")
+	require.NoError(t, c.ParseFlags([]string{"--network", "testnet", "--rpc-token", "s3cret", "--anchor", "acme"}))
+
+	e := auditEntry(c, []string{hash}, time.Now().Add(-time.Second), errors.New("simulation failed\nwith details"))
+	assert.Equal(t, "debug", e.Command)
+	assert.Equal(t, []string{hash}, e.TxHashes)
+	assert.Equal(t, "testnet", e.Network)
+	assert.Equal(t, "<redacted>", e.Flags["rpc-token"])
+	assert.Equal(t, "acme", e.Flags["anchor"])
+	assert.Equal(t, audit.OutcomeError, e.Outcome)
+	assert.Equal(t, "simulation failed", e.Error)
+	assert.GreaterOrEqual(t, e.DurationMs, int64(1000))
+}
+
+func TestRecordAudit(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	t.Setenv("ERST_AUDIT_USER", "oncall")
+	c := &cobra.Command{Use: "trace"}
+	recordAudit(c, []string{"trace.json"}, time.Now(), nil)
+	recordAudit(&cobra.Command{Use: "help"}, nil, time.Now(), nil)
+
+	log, err := audit.NewLog()
+	require.NoError(t, err)
+	entries, problems, err := log.Read(audit.Filter{})
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "oncall", entries[0].User)
+	assert.Equal(t, []string{"trace.json"}, entries[0].Args)
+
+	t.Setenv("ERST_AUDIT", "off")
+	recordAudit(c, nil, time.Now(), nil)
+	entries, _, err = log.Read(audit.Filter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestParseAuditTime(t *testing.T) {
+	got, err := parseAuditTime("2026-03-01")
+	require.NoError(t, err)
+	assert.Equal(t, 2026, got.Year())
+	_, err = parseAuditTime("yesterday")
+	assert.Error(t, err)
+}
+
+func TestAuditVerify(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	ctx := context.Background()
+	auditVerifyCmd.SetContext(ctx)
+	recordAudit(&cobra.Command{Use: "trace"}, nil, time.Now(), nil)
+	require.NoError(t, auditVerifyCmd.RunE(auditVerifyCmd, nil))
+
+	dsn := filepath.Join(t.TempDir(), "serve.db")
+	db, err := storage.Open(ctx, storage.Config{DSN: dsn})
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.AppendAudit(ctx, audit.Entry{User: "key:payments/abc", Command: "serve debug"})
+	require.NoError(t, err)
+
+	auditStorageFlag = dsn
+	defer func() { auditStorageFlag = "" }()
+	require.NoError(t, auditVerifyCmd.RunE(auditVerifyCmd, nil))
+
+	auditStorageFlag = "mysql://db/erst"
+	assert.Error(t, auditVerifyCmd.RunE(auditVerifyCmd, nil))
+}
@@ -72,18 +72,13 @@ Examples:
 		reporter := authtrace.NewDetailedReporter(trace)
 
 		if authJSONOutputFlag {
-			jsonStr, err := reporter.GenerateJSONString()
-			if err != nil {
-				return err
-			}
-			fmt.Println(jsonStr)
-		} else {
-			fmt.Println(reporter.GenerateReport())
-			if authDetailedFlag {
-				printDetailedAnalysis(reporter)
-			}
+			return writeJSON(cmd, trace)
 		}
 
+		fmt.Println(reporter.GenerateReport())
+		if authDetailedFlag {
+			printDetailedAnalysis(reporter)
+		}
 		return nil
 	},
 }
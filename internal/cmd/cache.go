@@ -7,8 +7,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/dotandev/hintents/internal/cache"
+	"github.com/dotandev/hintents/internal/platform"
+	"github.com/dotandev/hintents/internal/visualizer"
 	"github.com/spf13/cobra"
 )
 
@@ -18,11 +21,11 @@ var (
 
 // getCacheDir returns the default cache directory
 func getCacheDir() string {
-	homeDir, err := os.UserHomeDir()
+	dir, err := platform.DataDir()
 	if err != nil {
-		homeDir = "."
+		dir = ".erst"
 	}
-	return filepath.Join(homeDir, ".erst", "cache")
+	return filepath.Join(dir, "cache")
 }
 
 var cacheCmd = &cobra.Command{
@@ -78,6 +81,11 @@ var cacheStatusCmd = &cobra.Command{
 		fmt.Printf("Files cached: %d\n", len(files))
 		fmt.Printf("Maximum size: %s\n", formatBytes(cache.DefaultConfig().MaxSizeBytes))
 
+		visualizer.Record("directory", cacheDir)
+		visualizer.Record("size", strconv.FormatInt(size, 10))
+		visualizer.Record("files", strconv.Itoa(len(files)))
+		visualizer.Record("max_size", strconv.FormatInt(cache.DefaultConfig().MaxSizeBytes, 10))
+
 		if size > cache.DefaultConfig().MaxSizeBytes {
 			fmt.Printf("\n[!]  Cache size exceeds maximum limit. Run 'erst cache clean' to free space.\n")
 		}
@@ -143,6 +151,9 @@ var cacheClearCmd = &cobra.Command{
 
 		// Get confirmation unless force flag is set
 		if !cacheForceFlag {
+			if !platform.Interactive() {
+				return fmt.Errorf("refusing to clear the cache without confirmation in a non-interactive session; pass --force")
+			}
 			fmt.Printf("This will delete ALL cached files in %s\n", cacheDir)
 			fmt.Print("Are you sure? (yes/no): ")
 			var response string
@@ -184,6 +195,7 @@ func formatBytes(bytes int64) string {
 
 func init() {
 	// Add subcommands to cache command
+	supportPorcelain(cacheStatusCmd)
 	cacheCmd.AddCommand(cacheStatusCmd)
 	cacheCmd.AddCommand(cacheCleanCmd)
 	cacheCmd.AddCommand(cacheClearCmd)
@@ -0,0 +1,212 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/compare"
+	"github.com/dotandev/hintents/internal/hooks"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compareSimAFlag    string
+	compareSimBFlag    string
+	compareSimJSONFlag bool
+)
+
+// simulatorBuild names one of the binaries compared by compare-sim
+type simulatorBuild struct {
+	Label  string `json:"label"`
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// simCompareReport is the --json output of compare-sim
+type simCompareReport struct {
+	TxHash           string                          `json:"tx_hash"`
+	Network          string                          `json:"network"`
+	Simulators       []simulatorBuild                `json:"simulators"`
+	StatusMatch      bool                            `json:"status_match"`
+	ReturnValueMatch bool                            `json:"return_value_match"`
+	Events           compare.Summary                 `json:"events"`
+	Results          []*simulator.SimulationResponse `json:"results"`
+}
+
+// Differs reports whether the two simulators disagree on the outcome.
+// Resource usage is expected to move between host versions and does not
+// count.
+func (r *simCompareReport) Differs() bool {
+	return !r.StatusMatch || !r.ReturnValueMatch || r.Events.Changed()
+}
+
+var compareSimCmd = &cobra.Command{
+	Use:   "compare-sim --sim-a <path> --sim-b <path> <tx-hash>",
+	Short: "Replay a transaction with two simulator builds and diff the results",
+	Long: `Replay the same transaction, with the same ledger state, through two erst-sim
+binaries and compare their status, return value, events, logs and resource
+usage. Use it to validate a soroban-env-host upgrade before adopting it: build
+erst-sim against the new host, then compare it with the current build on
+transactions that matter to you.
+
+The command exits with an error when the simulators disagree on the status,
+return value or events. CPU and memory differences are reported but expected
+between host versions. The post-compare hooks of the config file get the
+--json report.`,
+	Example: `  erst compare-sim --sim-a ./erst-sim-v21 --sim-b ./erst-sim-v22 <tx-hash> --network testnet
+  erst compare-sim --sim-a ./erst-sim-old --sim-b ./target/release/erst-sim <tx-hash> --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if compareSimAFlag == "" || compareSimBFlag == "" {
+			return fmt.Errorf("flags --sim-a and --sim-b are required")
+		}
+		txHash := args[0]
+		if err := rpc.ValidateTransactionHash(txHash); err != nil {
+			return fmt.Errorf("invalid transaction hash format: %w", err)
+		}
+
+		builds := simulatorBuilds(compareSimAFlag, compareSimBFlag)
+		runners := make([]simulator.RunnerInterface, len(builds))
+		for i := range builds {
+			runner, err := simulator.NewRunner(builds[i].Path, false)
+			if err != nil {
+				return fmt.Errorf("failed to initialize simulator %s: %w", builds[i].Label, err)
+			}
+			runners[i] = runner
+			if builds[i].SHA256, err = simulator.BinaryDigest(runner.BinaryPath); err != nil {
+				logger.Logger.Warn("Failed to hash simulator binary", "path", runner.BinaryPath, "error", err)
+			}
+		}
+
+		opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(networkFlag))}
+		if rpcURLFlag != "" {
+			opts = append(opts, rpc.WithHorizonURL(rpcURLFlag))
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		ctx := cmd.Context()
+		fmt.Fprintf(os.Stderr, "Fetching transaction %s on %s...\n", txHash, networkFlag)
+		req, err := replayRequest(ctx, client, txHash)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Replaying with %s and %s...\n", builds[0].Label, builds[1].Label)
+		results := runSimulators(runners, req)
+
+		report := newSimCompareReport(txHash, networkFlag, builds, results[0], results[1])
+		if compareSimJSONFlag {
+			if err := writeJSON(cmd, report); err != nil {
+				return err
+			}
+		} else {
+			for i, b := range builds {
+				fmt.Printf("Simulator %s: %s (sha256 %s)\n", b.Label, b.Path, b.SHA256)
+				writeSimulationResult(os.Stdout, b.Label, results[i])
+			}
+			diffResults(results[0], results[1], builds[0].Label, builds[1].Label, compare.DefaultLogFilter())
+			if results[0].ReturnValue != results[1].ReturnValue {
+				fmt.Printf("\nReturn values differ:\n  %s: %s\n  %s: %s\n", builds[0].Label, results[0].ReturnValue, builds[1].Label, results[1].ReturnValue)
+			}
+		}
+
+		runPostCompareHooks(ctx, hooks.Payload{TxHash: txHash, Network: networkFlag}, report)
+
+		if report.Differs() {
+			return fmt.Errorf("simulators %s and %s disagree on transaction %s", builds[0].Label, builds[1].Label, txHash)
+		}
+		return nil
+	},
+}
+
+// simulatorBuilds labels the two binaries by file name, falling back to A
+// and B when the names are the same
+func simulatorBuilds(a, b string) []simulatorBuild {
+	labelA, labelB := filepath.Base(a), filepath.Base(b)
+	if labelA == labelB {
+		labelA, labelB = "A", "B"
+	}
+	return []simulatorBuild{{Label: labelA, Path: a}, {Label: labelB, Path: b}}
+}
+
+// replayRequest builds the simulation request replaying txHash with the
+// ledger state it ran against
+func replayRequest(ctx context.Context, client *rpc.Client, txHash string) (*simulator.SimulationRequest, error) {
+	resp, err := client.GetTransaction(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+	entries, err := rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+	if err != nil {
+		logger.Logger.Warn("Failed to extract ledger entries from metadata, fetching from network", "error", err)
+		keys, keyErr := extractLedgerKeys(resp.ResultMetaXdr)
+		if keyErr != nil {
+			return nil, fmt.Errorf("failed to extract ledger keys: %w", keyErr)
+		}
+		if entries, err = client.GetLedgerEntries(ctx, keys); err != nil {
+			return nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+		}
+	}
+	return &simulator.SimulationRequest{
+		EnvelopeXdr:   resp.EnvelopeXdr,
+		ResultMetaXdr: resp.ResultMetaXdr,
+		LedgerEntries: entries,
+	}, nil
+}
+
+// runSimulators runs req through every runner concurrently. A simulation
+// that fails becomes an error result, so one simulator rejecting the
+// transaction is a difference to report rather than a failure.
+func runSimulators(runners []simulator.RunnerInterface, req *simulator.SimulationRequest) []*simulator.SimulationResponse {
+	results := make([]*simulator.SimulationResponse, len(runners))
+	var wg sync.WaitGroup
+	for i, runner := range runners {
+		// Runners add their protocol settings to the request
+		r := *req
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := runner.Run(&r)
+			if err != nil {
+				resp = &simulator.SimulationResponse{Status: "error", Error: err.Error()}
+			}
+			results[i] = resp
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func newSimCompareReport(txHash, network string, builds []simulatorBuild, a, b *simulator.SimulationResponse) *simCompareReport {
+	return &simCompareReport{
+		TxHash:           txHash,
+		Network:          network,
+		Simulators:       builds,
+		StatusMatch:      a.Status == b.Status,
+		ReturnValueMatch: a.ReturnValue == b.ReturnValue,
+		Events:           compare.Summarize(compare.Events(a.Events, b.Events)),
+		Results:          []*simulator.SimulationResponse{a, b},
+	}
+}
+
+func init() {
+	compareSimCmd.Flags().StringVar(&compareSimAFlag, "sim-a", "", "Path to the first erst-sim binary, such as the current build")
+	compareSimCmd.Flags().StringVar(&compareSimBFlag, "sim-b", "", "Path to the second erst-sim binary, such as one built against a new soroban-env-host")
+	compareSimCmd.Flags().BoolVar(&compareSimJSONFlag, "json", false, "Output the comparison as JSON")
+	compareSimCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
+	compareSimCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Horizon RPC URL")
+	compareSimCmd.Flags().BoolVar(&noHooksFlag, "no-hooks", false, "Do not run the post-compare hooks of the config file")
+
+	rootCmd.AddCommand(compareSimCmd)
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatorBuildLabels(t *testing.T) {
+	builds := simulatorBuilds("/opt/erst-sim-v21", "./target/erst-sim-v22")
+	assert.Equal(t, "erst-sim-v21", builds[0].Label)
+	assert.Equal(t, "erst-sim-v22", builds[1].Label)
+
+	builds = simulatorBuilds("/old/erst-sim", "/new/erst-sim")
+	assert.Equal(t, []string{"A", "B"}, []string{builds[0].Label, builds[1].Label})
+	assert.Equal(t, "/new/erst-sim", builds[1].Path)
+}
+
+func TestRunSimulatorsKeepsFailures(t *testing.T) {
+	ok := new(MockRunner)
+	ok.On("Run", mock.Anything).Return(&simulator.SimulationResponse{Status: "success", ReturnValue: "AAAAAQ=="}, nil)
+	failing := new(MockRunner)
+	failing.On("Run", mock.Anything).Return((*simulator.SimulationResponse)(nil), errors.New("simulation error: HostError"))
+
+	req := &simulator.SimulationRequest{EnvelopeXdr: "AAAA"}
+	results := runSimulators([]simulator.RunnerInterface{ok, failing}, req)
+	require.Len(t, results, 2)
+	assert.Equal(t, "success", results[0].Status)
+	assert.Equal(t, "error", results[1].Status)
+	assert.Contains(t, results[1].Error, "HostError")
+
+	report := newSimCompareReport("abcd", "testnet", simulatorBuilds("a", "b"), results[0], results[1])
+	assert.False(t, report.StatusMatch)
+	assert.True(t, report.Differs())
+}
+
+func TestSimCompareReportIgnoresResources(t *testing.T) {
+	a := &simulator.SimulationResponse{Status: "success", BudgetUsage: &simulator.BudgetUsage{CPUInstructions: 100}}
+	b := &simulator.SimulationResponse{Status: "success", BudgetUsage: &simulator.BudgetUsage{CPUInstructions: 120}}
+	report := newSimCompareReport("abcd", "testnet", simulatorBuilds("a", "b"), a, b)
+	assert.False(t, report.Differs())
+
+	b.Events = []simulator.Event{{Type: "contract", Topics: []string{"transfer"}}}
+	report = newSimCompareReport("abcd", "testnet", simulatorBuilds("a", "b"), a, b)
+	assert.True(t, report.Differs())
+}
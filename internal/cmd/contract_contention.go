@@ -0,0 +1,115 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+var (
+	contractContentionLast    int
+	contractContentionLedgers uint32
+	contractContentionTop     int
+	contractContentionJSON    bool
+)
+
+var contractContentionCmd = &cobra.Command{
+	Use:   "contention <contract-id>",
+	Short: "Find the contract's most contended ledger keys",
+	Long: `Aggregate the footprints of recent transactions that touch a contract's
+storage, whether they call it directly or through another contract, and
+rank the ledger keys they declared by contention: how often several
+transactions declared the same key read-write in one ledger, and so could
+not be applied in parallel, then by how many transactions wrote it.
+
+Keys written from many transactions in the same ledgers come with a hint
+on how to shard them, such as moving counters out of instance storage or
+splitting a global key per account.`,
+	Example: `  erst contract contention CDLZ... --network testnet
+  erst contract contention CDLZ... --ledgers 17280 --last 2000 --top 20 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contract := args[0]
+		if !strkey.IsValidContractAddress(contract) {
+			return fmt.Errorf("invalid contract address %s", contract)
+		}
+
+		opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(networkFlag))}
+		if rpcURLFlag != "" {
+			opts = append(opts, rpc.WithSorobanURL(rpcURLFlag))
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		var txs []analytics.StateTx
+		from, to, err := scanRecentLedgers(cmd.Context(), client, contractContentionLedgers, func(tx rpc.LedgerTransaction) bool {
+			if analytics.FootprintTouchesContract(tx.EnvelopeXdr, contract) {
+				txs = append(txs, analytics.StateTx{
+					Hash: tx.Hash, Ledger: tx.Ledger, EnvelopeXdr: tx.EnvelopeXdr, ResultMetaXdr: tx.ResultMetaXdr,
+					Failed: tx.Status != "SUCCESS",
+				})
+			}
+			return len(txs) < contractContentionLast
+		})
+		if err != nil {
+			return err
+		}
+
+		report, err := analytics.AnalyzeContention(txs)
+		if err != nil {
+			return err
+		}
+		report.Contract, report.FromLedger, report.ToLedger = contract, from, to
+		if contractContentionTop > 0 && len(report.Keys) > contractContentionTop {
+			report.Keys = report.Keys[:contractContentionTop]
+		}
+
+		if contractContentionJSON {
+			return writeJSON(cmd, report)
+		}
+		writeContention(os.Stdout, report)
+		return nil
+	},
+}
+
+func writeContention(w io.Writer, r *analytics.ContentionReport) {
+	if len(r.Keys) == 0 {
+		fmt.Fprintf(w, "No shared keys among %d transaction(s) touching %s in ledgers %d-%d\n", r.Transactions, r.Contract, r.FromLedger, r.ToLedger)
+		return
+	}
+
+	table := visualizer.NewTable("KEY", "CONTENDED LEDGERS", "MAX/LEDGER", "WRITERS", "WRITTEN", "READERS", "FAILED").AlignRight(1, 2, 3, 4, 5, 6)
+	for _, k := range r.Keys {
+		table.AddRow(k.Key, strconv.Itoa(k.ContendedLedgers), strconv.Itoa(k.MaxWritersPerLedger), strconv.Itoa(k.Writers),
+			strconv.Itoa(k.Written), strconv.Itoa(k.Readers), strconv.Itoa(k.Failed))
+		if k.Suggestion != "" {
+			table.AddNote(k.Suggestion)
+		}
+	}
+	table.Render(w)
+	fmt.Fprintf(w, "\n%d transaction(s) touching %s in ledgers %d-%d\n", r.Transactions, r.Contract, r.FromLedger, r.ToLedger)
+}
+
+func init() {
+	contractContentionCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
+	contractContentionCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Soroban RPC URL")
+	contractContentionCmd.Flags().IntVar(&contractContentionLast, "last", 500, "Number of transactions to aggregate at most")
+	contractContentionCmd.Flags().Uint32Var(&contractContentionLedgers, "ledgers", 720, "Number of recent ledgers to scan at most")
+	contractContentionCmd.Flags().IntVar(&contractContentionTop, "top", 10, "Number of keys to show (0 = all)")
+	contractContentionCmd.Flags().BoolVar(&contractContentionJSON, "json", false, "Output as JSON")
+
+	supportPorcelain(contractContentionCmd)
+	contractCmd.AddCommand(contractContentionCmd)
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteContention(t *testing.T) {
+	var buf bytes.Buffer
+	writeContention(&buf, &analytics.ContentionReport{Contract: "CABC", FromLedger: 10, ToLedger: 20, Transactions: 1})
+	assert.Equal(t, "No shared keys among 1 transaction(s) touching CABC in ledgers 10-20\n", buf.String())
+
+	buf.Reset()
+	writeContention(&buf, &analytics.ContentionReport{Contract: "CABC", FromLedger: 10, ToLedger: 20, Transactions: 5, Keys: []analytics.KeyContention{{
+		Key: "CABC data Counter (persistent)", Writers: 4, Written: 3, ContendedLedgers: 2, MaxWritersPerLedger: 3,
+		Suggestion: "one key written by many transactions",
+	}}})
+	out := buf.String()
+	assert.Contains(t, out, "CABC data Counter (persistent)")
+	assert.Contains(t, out, "one key written by many transactions")
+	assert.Contains(t, out, "5 transaction(s) touching CABC in ledgers 10-20")
+}
@@ -0,0 +1,182 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dotandev/hintents/internal/contracthistory"
+	"github.com/dotandev/hintents/internal/corpus"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+var (
+	contractFailuresLast    int
+	contractFailuresLedgers uint32
+	contractFailuresBuckets int
+	contractFailuresCorpus  string
+	contractFailuresDir     string
+	contractFailuresHTML    string
+	contractFailuresJSON    bool
+)
+
+var contractFailuresCmd = &cobra.Command{
+	Use:   "failures [contract-id]",
+	Short: "Summarize failure rates per contract function",
+	Long: `Group a contract's recent invocations by function and show how often each
+one failed, most failing first, with the most frequent failure result.
+
+Invocations come from the network, scanned back from the latest ledger like
+'erst contract history', or from a corpus with --corpus. With a corpus the
+contract ID is optional and every contract called by its transactions is
+summarized.
+
+--html writes a heatmap with one row per function and one column per time
+bucket, shaded by failure rate, to spot when an entry point started failing.
+Corpus entries are placed by the close time of their ledger; --html fails
+for entries pinned without one.`,
+	Example: `  erst contract failures CDLZ... --network testnet
+  erst contract failures CDLZ... --last 1000 --ledgers 17280 --html failures.html
+  erst contract failures --corpus regressions --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var contract string
+		if len(args) == 1 {
+			contract = args[0]
+			if !strkey.IsValidContractAddress(contract) {
+				return fmt.Errorf("invalid contract address %s", contract)
+			}
+		} else if contractFailuresCorpus == "" {
+			return fmt.Errorf("a contract ID is required unless --corpus is set")
+		}
+
+		var invocations []contracthistory.Invocation
+		dated := true
+		if contractFailuresCorpus != "" {
+			c, err := corpus.Open(contractFailuresDir, contractFailuresCorpus)
+			if err != nil {
+				return err
+			}
+			var undated int
+			invocations, undated = corpusInvocations(c, contract)
+			if undated > 0 {
+				if contractFailuresHTML != "" {
+					return fmt.Errorf("%d entries of corpus %s have no ledger close time to place them in the heatmap; re-add them with 'erst corpus add' or drop --html", undated, c.Name)
+				}
+				logger.Logger.Warn("Corpus entries without a ledger close time; the summary has no time range", "corpus", c.Name, "entries", undated)
+				dated = false
+			}
+		} else {
+			opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(networkFlag))}
+			if rpcURLFlag != "" {
+				opts = append(opts, rpc.WithSorobanURL(rpcURLFlag))
+			}
+			client, err := rpc.NewClient(opts...)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			report, err := scanContractHistory(cmd.Context(), client, contract, nil, contractFailuresLast, contractFailuresLedgers)
+			if err != nil {
+				return err
+			}
+			invocations = report.Invocations
+		}
+
+		buckets := contractFailuresBuckets
+		if !dated {
+			buckets = 1
+		}
+		summary := contracthistory.SummarizeFailures(invocations, buckets)
+		if !dated {
+			summary.From, summary.To, summary.BucketSeconds = time.Time{}, time.Time{}, 0
+		}
+		if contractFailuresHTML != "" {
+			f, err := os.Create(contractFailuresHTML)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", contractFailuresHTML, err)
+			}
+			defer f.Close()
+			if err := contracthistory.RenderHeatmap(f, summary); err != nil {
+				return err
+			}
+		}
+
+		if contractFailuresJSON {
+			return writeJSON(cmd, summary)
+		}
+		writeFailureSummary(os.Stdout, summary)
+		if contractFailuresHTML != "" {
+			fmt.Printf("Heatmap written to %s\n", contractFailuresHTML)
+		}
+		return nil
+	},
+}
+
+// corpusInvocations returns the invocations of contract, or of every
+// contract when it is empty, in a corpus, timed by the close of their
+// ledger, and how many entries have no close time. Entries only record
+// whether they failed, so invocations carry no result code.
+func corpusInvocations(c *corpus.Corpus, contract string) ([]contracthistory.Invocation, int) {
+	var invocations []contracthistory.Invocation
+	undated := 0
+	for _, e := range c.Entries {
+		status := "SUCCESS"
+		if e.ExpectedStatus == corpus.StatusError {
+			status = "FAILED"
+		}
+		invs, err := contracthistory.FromTransaction(contract, rpc.LedgerTransaction{
+			Hash:        e.Hash,
+			Status:      status,
+			CreatedAt:   e.ClosedAt.Unix(),
+			EnvelopeXdr: e.EnvelopeXdr,
+		}, nil)
+		if err != nil {
+			logger.Logger.Warn("Skipping undecodable corpus entry", "hash", e.Hash, "error", err)
+			continue
+		}
+		if e.ClosedAt.IsZero() && len(invs) > 0 {
+			undated++
+		}
+		invocations = append(invocations, invs...)
+	}
+	return invocations, undated
+}
+
+func writeFailureSummary(w io.Writer, s *contracthistory.FailureSummary) {
+	if len(s.Functions) == 0 {
+		fmt.Fprintln(w, "No invocations found")
+		return
+	}
+
+	table := visualizer.NewTable("CONTRACT", "FUNCTION", "CALLS", "FAILED", "RATE", "TOP RESULT").AlignRight(2, 3, 4)
+	for _, f := range s.Functions {
+		table.AddRow(f.Contract, f.Function, strconv.Itoa(f.Calls), strconv.Itoa(f.Failed),
+			fmt.Sprintf("%.1f%%", f.FailureRate*100), f.TopResult())
+	}
+	table.Render(w)
+	fmt.Fprintf(w, "\n%d invocation(s) of %d function(s)\n", s.Invocations, len(s.Functions))
+}
+
+func init() {
+	contractFailuresCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
+	contractFailuresCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Soroban RPC URL")
+	contractFailuresCmd.Flags().IntVar(&contractFailuresLast, "last", 500, "Number of invocations to summarize at most")
+	contractFailuresCmd.Flags().Uint32Var(&contractFailuresLedgers, "ledgers", 17280, "Number of recent ledgers to scan at most")
+	contractFailuresCmd.Flags().IntVar(&contractFailuresBuckets, "buckets", 24, "Number of time buckets in the heatmap")
+	contractFailuresCmd.Flags().StringVar(&contractFailuresCorpus, "corpus", "", "Read invocations from this corpus instead of the network")
+	contractFailuresCmd.Flags().StringVar(&contractFailuresDir, "dir", corpus.DefaultRoot, "Directory holding corpora")
+	contractFailuresCmd.Flags().StringVar(&contractFailuresHTML, "html", "", "Also write an HTML heatmap to this file")
+	contractFailuresCmd.Flags().BoolVar(&contractFailuresJSON, "json", false, "Output as JSON")
+
+	supportPorcelain(contractFailuresCmd)
+	contractCmd.AddCommand(contractFailuresCmd)
+}
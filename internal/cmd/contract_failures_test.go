@@ -0,0 +1,59 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/contracthistory"
+	"github.com/dotandev/hintents/internal/corpus"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpusInvocations(t *testing.T) {
+	id := xdr.ContractId{7}
+	contract := strkey.MustEncode(strkey.VersionByteContract, id[:])
+	c, err := corpus.OpenOrCreate(t.TempDir(), "prod")
+	require.NoError(t, err)
+	at := time.Unix(1700000000, 0).UTC()
+	require.NoError(t, c.Add(corpus.Entry{Hash: "aa", EnvelopeXdr: historyEnvelope(t, id, "deposit"), ExpectedStatus: corpus.StatusError, ClosedAt: at}, nil))
+	require.NoError(t, c.Add(corpus.Entry{Hash: "bb", EnvelopeXdr: historyEnvelope(t, id, "deposit"), ExpectedStatus: corpus.StatusSuccess, ClosedAt: at}, nil))
+	require.NoError(t, c.Add(corpus.Entry{Hash: "cc", EnvelopeXdr: historyEnvelope(t, xdr.ContractId{8}, "init"), ExpectedStatus: corpus.StatusSuccess, ClosedAt: at}, nil))
+
+	invs, undated := corpusInvocations(c, contract)
+	require.Len(t, invs, 2)
+	assert.Zero(t, undated)
+	assert.Equal(t, contracthistory.StatusFailed, invs[0].Status)
+	assert.Equal(t, contracthistory.StatusSuccess, invs[1].Status)
+	assert.Equal(t, at, invs[0].CreatedAt, "timed by the ledger close, not when the entry was added")
+
+	invs, _ = corpusInvocations(c, "")
+	assert.Len(t, invs, 3, "every contract without an ID")
+
+	require.NoError(t, c.Add(corpus.Entry{Hash: "dd", EnvelopeXdr: historyEnvelope(t, id, "deposit"), ExpectedStatus: corpus.StatusSuccess}, nil))
+	_, undated = corpusInvocations(c, contract)
+	assert.Equal(t, 1, undated)
+}
+
+func TestWriteFailureSummary(t *testing.T) {
+	var buf bytes.Buffer
+	writeFailureSummary(&buf, contracthistory.SummarizeFailures(nil, 1))
+	assert.Equal(t, "No invocations found\n", buf.String())
+
+	buf.Reset()
+	writeFailureSummary(&buf, contracthistory.SummarizeFailures([]contracthistory.Invocation{
+		{Contract: "CABC", Function: "withdraw", Status: contracthistory.StatusFailed, Result: "invoke_host_function_trapped"},
+		{Contract: "CABC", Function: "withdraw", Status: contracthistory.StatusSuccess},
+	}, 1))
+	out := buf.String()
+	assert.Contains(t, out, "withdraw")
+	assert.Contains(t, out, "50.0%")
+	assert.Contains(t, out, "invoke_host_function_trapped")
+	assert.Contains(t, out, "2 invocation(s) of 1 function(s)")
+}
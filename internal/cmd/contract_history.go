@@ -0,0 +1,247 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dotandev/hintents/internal/contracthistory"
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// historyScanChunk is how many ledgers are fetched at a time while scanning
+// back for invocations
+const historyScanChunk = 100
+
+// historyCallWidth truncates calls in the table; --json has them in full
+const historyCallWidth = 60
+
+var (
+	contractHistoryLast    int
+	contractHistoryLedgers uint32
+	contractHistoryJSON    bool
+)
+
+var contractCmd = &cobra.Command{
+	Use:   "contract",
+	Short: "Inspect a deployed contract",
+	Long: `Inspect a deployed Soroban contract.
+
+Available subcommands:
+  history    - List the contract's recent invocations
+  failures   - Summarize failure rates per contract function
+  contention - Find the contract's most contended ledger keys`,
+}
+
+var contractHistoryCmd = &cobra.Command{
+	Use:   "history <contract-id>",
+	Short: "List a contract's recent invocations",
+	Long: `List the most recent transactions that called a contract directly, newest
+first, with their status, caller, the function and arguments, the declared
+resources and the fee charged. Arguments are named after the parameters in
+the contract's spec, read from its deployed WASM.
+
+Ledgers are scanned back from the latest one through Soroban RPC until
+--last invocations are found or --ledgers ledgers have been scanned. Pick
+a failed transaction from the list and run 'erst debug <tx-hash>' on it.`,
+	Example: `  erst contract history CDLZ... --network testnet
+  erst contract history CDLZ... --last 50 --ledgers 17280 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contract := args[0]
+		if !strkey.IsValidContractAddress(contract) {
+			return fmt.Errorf("invalid contract address %s", contract)
+		}
+
+		opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(networkFlag))}
+		if rpcURLFlag != "" {
+			opts = append(opts, rpc.WithSorobanURL(rpcURLFlag))
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		ctx := cmd.Context()
+		spec, err := loadContractSpec(ctx, client, contract)
+		if err != nil {
+			logger.Logger.Warn("Contract spec unavailable, arguments will not be named", "contract", contract, "error", err)
+		}
+
+		report, err := scanContractHistory(ctx, client, contract, spec, contractHistoryLast, contractHistoryLedgers)
+		if err != nil {
+			return err
+		}
+
+		if contractHistoryJSON {
+			return writeJSON(cmd, report)
+		}
+		writeContractHistory(os.Stdout, report)
+		return nil
+	},
+}
+
+// scanContractHistory collects the latest last invocations of contract
+// within ledgers ledgers
+func scanContractHistory(ctx context.Context, client *rpc.Client, contract string, spec *contractspec.Spec, last int, ledgers uint32) (*contracthistory.Report, error) {
+	report := &contracthistory.Report{Contract: contract, Invocations: []contracthistory.Invocation{}}
+	from, to, err := scanRecentLedgers(ctx, client, ledgers, func(tx rpc.LedgerTransaction) bool {
+		invs, err := contracthistory.FromTransaction(contract, tx, spec)
+		if err != nil {
+			logger.Logger.Warn("Skipping undecodable transaction", "hash", tx.Hash, "error", err)
+			return true
+		}
+		for j := len(invs) - 1; j >= 0 && len(report.Invocations) < last; j-- {
+			report.Invocations = append(report.Invocations, invs[j])
+		}
+		return len(report.Invocations) < last
+	})
+	if err != nil {
+		return nil, err
+	}
+	report.FromLedger, report.ToLedger = from, to
+	return report, nil
+}
+
+// scanRecentLedgers passes the transactions of the latest ledgers ledgers to
+// visit, newest first, fetching a chunk at a time until visit returns false.
+// It returns the range of ledgers scanned.
+func scanRecentLedgers(ctx context.Context, client *rpc.Client, ledgers uint32, visit func(rpc.LedgerTransaction) bool) (uint32, uint32, error) {
+	latest, err := client.GetLatestLedgerSequence(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get latest ledger: %w", err)
+	}
+	oldest := uint32(1)
+	if latest > ledgers {
+		oldest = latest - ledgers + 1
+	}
+
+	from := latest
+	for end := latest; end >= oldest; {
+		start := oldest
+		if end-oldest >= historyScanChunk {
+			start = end - historyScanChunk + 1
+		}
+		logger.Logger.Debug("Scanning ledgers", "start", start, "end", end)
+		txs, err := client.GetLedgerTransactions(ctx, start, end)
+		if err != nil {
+			return 0, 0, err
+		}
+		from = start
+		for i := len(txs) - 1; i >= 0; i-- {
+			if !visit(txs[i]) {
+				return from, latest, nil
+			}
+		}
+		if start == oldest {
+			break
+		}
+		end = start - 1
+	}
+	return from, latest, nil
+}
+
+// loadContractSpec reads the spec from the WASM a contract instance runs
+func loadContractSpec(ctx context.Context, client *rpc.Client, contract string) (*contractspec.Spec, error) {
+	raw, err := strkey.Decode(strkey.VersionByteContract, contract)
+	if err != nil {
+		return nil, fmt.Errorf("invalid contract address %s: %w", contract, err)
+	}
+	var id xdr.ContractId
+	copy(id[:], raw)
+
+	instanceKey, err := rpc.EncodeLedgerKey(xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id},
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries, err := client.GetLedgerEntries(ctx, []string{instanceKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contract instance: %w", err)
+	}
+	var data xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(entries[instanceKey], &data); err != nil || data.ContractData == nil {
+		return nil, fmt.Errorf("contract instance of %s not found", contract)
+	}
+	instance, ok := data.ContractData.Val.GetInstance()
+	if !ok || instance.Executable.WasmHash == nil {
+		return nil, fmt.Errorf("%s does not run WASM code", contract)
+	}
+
+	hash := *instance.Executable.WasmHash
+	codeKey, err := rpc.EncodeLedgerKey(xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: hash},
+	})
+	if err != nil {
+		return nil, err
+	}
+	codes, err := client.GetLedgerEntries(ctx, []string{codeKey})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch contract code: %w", err)
+	}
+	code, ok := contractCodeEntries(codes)[hex.EncodeToString(hash[:])]
+	if !ok {
+		return nil, fmt.Errorf("contract code %x not found", hash[:])
+	}
+	return contractspec.Parse(code)
+}
+
+func writeContractHistory(w io.Writer, report *contracthistory.Report) {
+	if len(report.Invocations) == 0 {
+		fmt.Fprintf(w, "No invocations of %s in ledgers %d-%d\n", report.Contract, report.FromLedger, report.ToLedger)
+		return
+	}
+
+	table := visualizer.NewTable("LEDGER", "TIME", "STATUS", "CALLER", "CALL", "INSTRUCTIONS", "FEE", "TRANSACTION").AlignRight(5, 6)
+	for _, inv := range report.Invocations {
+		status := inv.Status
+		if inv.Result != "" {
+			status += " (" + inv.Result + ")"
+		}
+		call := inv.Call
+		if len(call) > historyCallWidth {
+			call = call[:historyCallWidth-3] + "..."
+		}
+		table.AddRow(strconv.FormatUint(uint64(inv.Ledger), 10), inv.CreatedAt.UTC().Format(time.DateTime), status,
+			inv.Caller, call, strconv.FormatUint(uint64(inv.Instructions), 10), strconv.FormatInt(inv.FeeCharged, 10), inv.TxHash)
+	}
+	table.Render(w)
+
+	fmt.Fprintf(w, "\n%d invocation(s) of %s in ledgers %d-%d, %d failed\n",
+		len(report.Invocations), report.Contract, report.FromLedger, report.ToLedger, report.Failed())
+	if report.Failed() > 0 {
+		fmt.Fprintln(w, "Run 'erst debug <tx-hash>' to investigate a failed invocation.")
+	}
+}
+
+func init() {
+	contractHistoryCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
+	contractHistoryCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Soroban RPC URL")
+	contractHistoryCmd.Flags().IntVar(&contractHistoryLast, "last", 20, "Number of invocations to list")
+	contractHistoryCmd.Flags().Uint32Var(&contractHistoryLedgers, "ledgers", 720, "Number of recent ledgers to scan at most")
+	contractHistoryCmd.Flags().BoolVar(&contractHistoryJSON, "json", false, "Output as JSON")
+
+	supportPorcelain(contractHistoryCmd)
+	contractCmd.AddCommand(contractHistoryCmd)
+	rootCmd.AddCommand(contractCmd)
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/contracthistory"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func historyEnvelope(t *testing.T, id xdr.ContractId, function string) string {
+	t.Helper()
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(keypair.MustRandom().Address()),
+			Fee:           100,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+					Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+					InvokeContract: &xdr.InvokeContractArgs{
+						ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id},
+						FunctionName:    xdr.ScSymbol(function),
+					},
+				}},
+			}}},
+		}},
+	}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return b64
+}
+
+func TestScanContractHistory(t *testing.T) {
+	id := xdr.ContractId{7}
+	contract := strkey.MustEncode(strkey.VersionByteContract, id[:])
+	var starts []float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch req.Method {
+		case "getLatestLedger":
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"sequence":1000}}`)
+		case "getTransactions":
+			start, _ := req.Params["startLedger"].(float64)
+			starts = append(starts, start)
+			var txs []rpc.LedgerTransaction
+			if start == 901 {
+				txs = []rpc.LedgerTransaction{
+					{Hash: "old", Status: "SUCCESS", Ledger: 950, EnvelopeXdr: historyEnvelope(t, id, "init")},
+					{Hash: "other", Status: "SUCCESS", Ledger: 960, EnvelopeXdr: historyEnvelope(t, xdr.ContractId{8}, "init")},
+					{Hash: "new", Status: "SUCCESS", Ledger: 990, EnvelopeXdr: historyEnvelope(t, id, "deposit")},
+				}
+			}
+			result, _ := json.Marshal(map[string]interface{}{"transactions": txs})
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":%s}`, result)
+		}
+	}))
+	defer server.Close()
+
+	client, err := rpc.NewClient(rpc.WithNetwork(rpc.Testnet), rpc.WithSorobanURL(server.URL))
+	require.NoError(t, err)
+
+	report, err := scanContractHistory(context.Background(), client, contract, nil, 5, 150)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{901, 851}, starts, "scans back a chunk at a time down to the oldest ledger")
+	assert.Equal(t, uint32(851), report.FromLedger)
+	assert.Equal(t, uint32(1000), report.ToLedger)
+	require.Len(t, report.Invocations, 2)
+	assert.Equal(t, "new", report.Invocations[0].TxHash, "newest first")
+	assert.Equal(t, "deposit()", report.Invocations[0].Call)
+	assert.Equal(t, "old", report.Invocations[1].TxHash)
+}
+
+func TestWriteContractHistory(t *testing.T) {
+	var buf bytes.Buffer
+	writeContractHistory(&buf, &contracthistory.Report{Contract: "CABC", FromLedger: 10, ToLedger: 20})
+	assert.Equal(t, "No invocations of CABC in ledgers 10-20\n", buf.String())
+
+	buf.Reset()
+	writeContractHistory(&buf, &contracthistory.Report{Contract: "CABC", FromLedger: 10, ToLedger: 20, Invocations: []contracthistory.Invocation{{
+		TxHash: "aa", Ledger: 15, CreatedAt: time.Unix(1700000000, 0), Status: contracthistory.StatusFailed,
+		Result: "invoke_host_function_trapped", Caller: "GABC", Call: "deposit(amount: 5)", Instructions: 5000, FeeCharged: 321,
+	}}})
+	out := buf.String()
+	assert.Contains(t, out, "failed (invoke_host_function_trapped)")
+	assert.Contains(t, out, "deposit(amount: 5)")
+	assert.Contains(t, out, "1 invocation(s) of CABC in ledgers 10-20, 1 failed")
+	assert.Contains(t, out, "erst debug <tx-hash>")
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/spf13/cobra"
+)
+
+var (
+	convertTypeFlag   string
+	convertFromFlag   string
+	convertToFlag     string
+	convertOutputFlag string
+)
+
+var convertCmd = &cobra.Command{
+	Use:   "convert [value | @file | -]",
+	Short: "Convert an XDR value between base64, hex, binary and JSON",
+	Long: `Convert a value of a supported XDR type between base64, hex, raw binary XDR
+and the stellar-xdr JSON used by Stellar Lab and stellar-cli.
+
+Pass the value as an argument, as @path to read it from a file, or as - or
+nothing to read it from stdin. Binary input must come from a file or stdin.
+With --from auto the input encoding is detected by trying JSON, hex, base64
+and binary in that order; the first that decodes a whole value wins. Pass
+--from when a value is valid in more than one encoding.
+
+Transaction envelopes may also be given as any JSON that 'erst diffxdr'
+accepts, such as an RPC response with an envelopeXdr field.
+
+Supported types: ` + strings.Join(decoder.XDRTypes(), ", "),
+	Example: `  erst convert AAAAAgAAAAA... --to json
+  erst convert @tx.json --to base64
+  erst convert --type sc-val '{"u32": 7}' --to hex
+  erst convert @meta.xdr --type transaction-meta --from binary --to json
+  pbpaste | erst convert --to binary -o tx.bin`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		arg := "-"
+		if len(args) == 1 {
+			arg = args[0]
+		}
+		in, err := readConvertInput(arg, cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		out, _, err := decoder.ConvertXDR(convertTypeFlag, in, convertFromFlag, convertToFlag)
+		if err != nil {
+			return err
+		}
+		if convertOutputFlag != "" {
+			if err := os.WriteFile(convertOutputFlag, out, 0644); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+			return nil
+		}
+		_, err = cmd.OutOrStdout().Write(out)
+		return err
+	},
+}
+
+// readConvertInput returns the bytes of a convert argument: the argument
+// itself, the contents of the file named by @path, or stdin for -
+func readConvertInput(arg string, stdin io.Reader) ([]byte, error) {
+	if arg == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return data, nil
+	}
+	if path, ok := strings.CutPrefix(arg, "@"); ok {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read XDR file: %w", err)
+		}
+		return data, nil
+	}
+	return []byte(arg), nil
+}
+
+func init() {
+	convertCmd.Flags().StringVarP(&convertTypeFlag, "type", "t", "transaction-envelope", "XDR type of the value")
+	convertCmd.Flags().StringVar(&convertFromFlag, "from", decoder.EncodingAuto, "Input encoding: auto, "+strings.Join(decoder.Encodings(), ", "))
+	convertCmd.Flags().StringVar(&convertToFlag, "to", decoder.EncodingJSON, "Output encoding: "+strings.Join(decoder.Encodings(), ", "))
+	convertCmd.Flags().StringVarP(&convertOutputFlag, "output", "o", "", "Write the result to a file instead of stdout")
+	rootCmd.AddCommand(convertCmd)
+}
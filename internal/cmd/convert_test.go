@@ -0,0 +1,50 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadConvertInput(t *testing.T) {
+	got, err := readConvertInput("AAAAAQ==", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("AAAAAQ=="), got)
+
+	got, err = readConvertInput("-", strings.NewReader("from stdin"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("from stdin"), got)
+
+	// Files are read as-is so binary XDR is not altered
+	path := filepath.Join(t.TempDir(), "val.bin")
+	require.NoError(t, os.WriteFile(path, []byte{0, 0, 0, 1, '\n'}, 0600))
+	got, err = readConvertInput("@"+path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 1, '\n'}, got)
+
+	_, err = readConvertInput("@"+filepath.Join(t.TempDir(), "missing"), nil)
+	assert.ErrorContains(t, err, "failed to read XDR file")
+}
+
+func TestConvertCommand(t *testing.T) {
+	var out bytes.Buffer
+	convertCmd.SetOut(&out)
+	convertCmd.SetIn(strings.NewReader(`{"u32": 7}`))
+	defer convertCmd.SetOut(nil)
+	defer convertCmd.SetIn(nil)
+	convertTypeFlag, convertFromFlag, convertToFlag = "sc-val", "auto", "hex"
+	defer func() {
+		convertTypeFlag, convertFromFlag, convertToFlag = "transaction-envelope", "auto", "json"
+	}()
+
+	require.NoError(t, convertCmd.RunE(convertCmd, nil))
+	assert.Equal(t, "0000000300000007\n", out.String())
+}
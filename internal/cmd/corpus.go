@@ -0,0 +1,430 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/corpus"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	corpusDirFlag     string
+	corpusExpectFlag  string
+	corpusNoteFlag    string
+	corpusWasmFlag    string
+	corpusJSONFlag    bool
+	corpusJUnitFlag   string
+	corpusResumeFlag  bool
+	corpusStorageDSN  string
+	corpusSessionFlag string
+	corpusSandbox     sandboxFlags
+)
+
+var corpusCmd = &cobra.Command{
+	Use:   "corpus",
+	Short: "Manage transaction corpora used as replay regression suites",
+	Long: `A corpus is a named set of transactions with pinned ledger snapshots. You
+can replay a corpus offline as a regression suite, for example in CI before
+deploying new contract code.
+
+Corpora are stored under --dir (default .erst/corpus in the current directory)
+so they can be committed next to your contract sources.
+
+With --storage (or ERST_STORAGE_DSN, or "storage.dsn" in config.json), add
+and remove also save each corpus definition in the shared erst serve
+database, and list includes the corpora saved there. Ledger snapshots stay
+under --dir.
+
+Available subcommands:
+  add     - Pin one or more transactions, or a debugged session, into a corpus
+  remove  - Remove a transaction from a corpus
+  list    - List corpora, or the entries of one corpus
+  run     - Replay a corpus and report a pass/fail matrix
+
+add and run save a checkpoint after each transaction. If a batch is
+interrupted, rerun the same command with --resume to skip the transactions
+that already completed.`,
+	Example: `  erst corpus add payments 5c0a... 9f1b... --network testnet
+  erst corpus add payments --session 5c0a...-1712345678
+  erst corpus add payments 5c0a... 9f1b... --network testnet --resume
+  erst corpus list payments
+  erst corpus run payments --wasm ./target/new.wasm --junit corpus.xml`,
+}
+
+var corpusAddCmd = &cobra.Command{
+	Use:   "add <corpus> [tx-hash]...",
+	Short: "Pin transactions and their ledger state into a corpus",
+	Long: `Fetch each transaction and pin it with its ledger state into the corpus.
+
+Without transaction hashes, the transaction of a debugged session is pinned
+instead, from the data saved with it: the one named by --session, else the
+current session.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) > 1 && corpusSessionFlag != "" {
+			return fmt.Errorf("--session cannot be combined with transaction hashes")
+		}
+		if corpusExpectFlag != "" && corpusExpectFlag != corpus.StatusSuccess && corpusExpectFlag != corpus.StatusError {
+			return fmt.Errorf("invalid --expect %q: must be success or error", corpusExpectFlag)
+		}
+
+		c, err := corpus.OpenOrCreate(corpusDirFlag, args[0])
+		if err != nil {
+			return err
+		}
+		if len(args) == 1 {
+			return addCorpusSession(cmd.Context(), c, corpusSessionFlag)
+		}
+
+		opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(networkFlag))}
+		if rpcURLFlag != "" {
+			opts = append(opts, rpc.WithHorizonURL(rpcURLFlag))
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		for _, hash := range args[1:] {
+			if err := rpc.ValidateTransactionHash(hash); err != nil {
+				return fmt.Errorf("invalid transaction hash %s: %w", hash, err)
+			}
+		}
+
+		cp, err := openCorpusCheckpoint(c, corpus.OpAdd, networkFlag+"|"+corpusExpectFlag+"|"+corpusNoteFlag)
+		if err != nil {
+			return err
+		}
+
+		for _, hash := range args[1:] {
+			if cp.IsDone(hash) {
+				visualizer.Infof("Skipping %s: already added before the interruption\n", hash)
+				continue
+			}
+
+			resp, err := client.GetTransaction(cmd.Context(), hash)
+			if err != nil {
+				return fmt.Errorf("failed to fetch transaction %s: %w%s", hash, err, resumeHint)
+			}
+
+			ledger, err := rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+			if err != nil {
+				logger.Logger.Warn("Failed to extract ledger entries from metadata, fetching from network", "hash", hash, "error", err)
+				keys, keyErr := extractLedgerKeys(resp.ResultMetaXdr)
+				if keyErr != nil {
+					return fmt.Errorf("failed to extract ledger keys: %w", keyErr)
+				}
+				if ledger, err = client.GetLedgerEntries(cmd.Context(), keys); err != nil {
+					return fmt.Errorf("failed to fetch ledger entries for %s: %w%s", hash, err, resumeHint)
+				}
+			}
+
+			expected := corpusExpectFlag
+			if expected == "" {
+				expected = corpus.StatusFromResult(resp.ResultXdr)
+			}
+
+			if err := c.Add(corpus.Entry{
+				Hash:           hash,
+				Network:        networkFlag,
+				EnvelopeXdr:    resp.EnvelopeXdr,
+				ResultMetaXdr:  resp.ResultMetaXdr,
+				ExpectedStatus: expected,
+				Note:           corpusNoteFlag,
+				ClosedAt:       resp.LedgerCloseTime,
+			}, ledger); err != nil {
+				return err
+			}
+			if err := cp.MarkDone(hash, nil); err != nil {
+				return err
+			}
+			fmt.Printf("Added %s to %s (%d ledger entries, expect %s)\n", hash, c.Name, len(ledger), expected)
+		}
+		if err := shareCorpus(cmd.Context(), c); err != nil {
+			return err
+		}
+		return cp.Finish()
+	},
+}
+
+var corpusRemoveCmd = &cobra.Command{
+	Use:   "remove <corpus> <tx-hash>",
+	Short: "Remove a transaction from a corpus",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := corpus.Open(corpusDirFlag, args[0])
+		if err != nil {
+			return err
+		}
+		if err := c.Remove(args[1]); err != nil {
+			return err
+		}
+		if err := shareCorpus(cmd.Context(), c); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s from %s\n", args[1], c.Name)
+		return nil
+	},
+}
+
+var corpusListCmd = &cobra.Command{
+	Use:   "list [corpus]",
+	Short: "List corpora, or the entries of one corpus",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := openStorage(cmd.Context(), corpusStorageDSN)
+		if err != nil {
+			return err
+		}
+		if db != nil {
+			defer db.Close()
+		}
+
+		if len(args) == 0 {
+			names, err := corpus.List(corpusDirFlag)
+			if err != nil {
+				return err
+			}
+			if db != nil {
+				shared, err := db.Corpora(cmd.Context())
+				if err != nil {
+					return err
+				}
+				for _, name := range shared {
+					if !slices.Contains(names, name) {
+						names = append(names, name)
+					}
+				}
+				sort.Strings(names)
+			}
+			if len(names) == 0 {
+				fmt.Printf("No corpora found in %s\n", corpusDirFlag)
+				return nil
+			}
+			for _, name := range names {
+				fmt.Println(name)
+			}
+			return nil
+		}
+
+		c, err := corpus.Open(corpusDirFlag, args[0])
+		if errors.Is(err, corpus.ErrNotFound) && db != nil {
+			c, err = db.Corpus(cmd.Context(), args[0])
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Corpus %s: %d transactions\n\n", c.Name, len(c.Entries))
+		table := visualizer.NewTable("HASH", "NETWORK", "EXPECT", "ADDED")
+		for _, e := range c.Entries {
+			table.AddRow(e.Hash, e.Network, e.ExpectedStatus, e.AddedAt.Format("2006-01-02"))
+		}
+		table.Render(os.Stdout)
+		return nil
+	},
+}
+
+var corpusRunCmd = &cobra.Command{
+	Use:   "run <corpus>",
+	Short: "Replay every transaction in a corpus and report pass/fail",
+	Long: `Replay every pinned transaction against its snapshot. An entry passes when
+the simulated status matches its expected status, which is recorded from the
+on-chain result when the entry is added.
+
+With --wasm, the candidate code replaces the invoked contract's code before
+each replay. The command exits non-zero if any entry fails.
+
+--sim-cpu-limit, --sim-memory-limit and --sim-output-limit cap each replay's
+simulator process. An entry stopped at a limit fails with the breached
+resource recorded in its result instead of stalling the batch. An entry whose
+simulator failed to run or crashed fails with the actual status "infra",
+whatever its expected status.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := corpus.Open(corpusDirFlag, args[0])
+		if err != nil {
+			return err
+		}
+
+		runner, err := simulator.NewRunner("", false)
+		if err != nil {
+			return fmt.Errorf("failed to initialize simulator runner: %w", err)
+		}
+		runner.Limits = runner.Limits.Tighten(corpusSandbox.limits())
+
+		var opts corpus.RunOptions
+		params := ""
+		if corpusWasmFlag != "" {
+			code, err := os.ReadFile(corpusWasmFlag)
+			if err != nil {
+				return fmt.Errorf("failed to read WASM file: %w", err)
+			}
+			sum := sha256.Sum256(code)
+			params = hex.EncodeToString(sum[:])
+			opts.Prepare = func(e corpus.Entry, ledger map[string]string) error {
+				contractID, err := getContractIDFromEnvelope(e.EnvelopeXdr)
+				if err != nil {
+					return err
+				}
+				return injectNewCode(ledger, *contractID, code)
+			}
+		}
+
+		if opts.Checkpoint, err = openCorpusCheckpoint(c, corpus.OpRun, params); err != nil {
+			return err
+		}
+		m, err := c.Run(cmd.Context(), runner, opts)
+		if err != nil {
+			return err
+		}
+		if cmd.Context().Err() == nil {
+			if err := opts.Checkpoint.Finish(); err != nil {
+				return err
+			}
+		}
+
+		if corpusJUnitFlag != "" {
+			f, err := os.Create(corpusJUnitFlag)
+			if err != nil {
+				return fmt.Errorf("failed to create JUnit report: %w", err)
+			}
+			defer f.Close()
+			if err := m.WriteJUnit(f); err != nil {
+				return err
+			}
+		}
+
+		if corpusJSONFlag {
+			if err := writeJSON(cmd, m); err != nil {
+				return err
+			}
+		} else {
+			printCorpusMatrix(m)
+		}
+
+		if !m.OK() {
+			return fmt.Errorf("%d of %d corpus entries failed", m.Failed, len(m.Results))
+		}
+		return nil
+	},
+}
+
+func printCorpusMatrix(m *corpus.Matrix) {
+	table := visualizer.NewTable("RESULT", "HASH", "NETWORK", "EXPECT", "ACTUAL")
+	for _, r := range m.Results {
+		mark := "PASS"
+		if !r.Pass {
+			mark = "FAIL"
+		}
+		actual := r.Actual
+		if actual == "" {
+			actual = "-"
+		}
+		table.AddRow(mark, r.Hash, r.Network, r.Expected, actual)
+		if !r.Pass && r.Error != "" {
+			table.AddNote(r.Error)
+		}
+	}
+	table.Render(os.Stdout)
+	fmt.Printf("\n%d passed, %d failed\n", m.Passed, m.Failed)
+	if m.Resumed > 0 {
+		fmt.Printf("%d results were taken from the checkpoint of the interrupted run\n", m.Resumed)
+	}
+}
+
+// addCorpusSession pins the transaction of the session id, or of the
+// current session, into c
+func addCorpusSession(ctx context.Context, c *corpus.Corpus, id string) error {
+	data, err := resolveSession(ctx, id)
+	if err != nil {
+		return err
+	}
+	if data.EnvelopeXdr == "" || data.ResultMetaXdr == "" {
+		return fmt.Errorf("session %s has no transaction envelope and meta to pin; add %s by hash instead", data.ID, data.TxHash)
+	}
+
+	ledger, err := rpc.ExtractLedgerEntriesFromMeta(data.ResultMetaXdr)
+	if err != nil {
+		return fmt.Errorf("failed to extract ledger entries from session %s: %w", data.ID, err)
+	}
+
+	expected := corpusExpectFlag
+	if expected == "" {
+		expected = corpus.StatusFromResult(data.ResultXdr)
+	}
+	if err := c.Add(corpus.Entry{
+		Hash:           data.TxHash,
+		Network:        data.Network,
+		EnvelopeXdr:    data.EnvelopeXdr,
+		ResultMetaXdr:  data.ResultMetaXdr,
+		ExpectedStatus: expected,
+		Note:           corpusNoteFlag,
+	}, ledger); err != nil {
+		return err
+	}
+	fmt.Printf("Added %s from session %s to %s (%d ledger entries, expect %s)\n", data.TxHash, data.ID, c.Name, len(ledger), expected)
+	return shareCorpus(ctx, c)
+}
+
+const resumeHint = "\nCompleted transactions are checkpointed; rerun with --resume to continue"
+
+// openCorpusCheckpoint resumes the checkpoint of an interrupted op batch
+// with --resume, and starts a fresh one otherwise
+// shareCorpus saves the definition of c in the storage database, when one
+// is configured
+func shareCorpus(ctx context.Context, c *corpus.Corpus) error {
+	db, err := openStorage(ctx, corpusStorageDSN)
+	if err != nil || db == nil {
+		return err
+	}
+	defer db.Close()
+	return db.PutCorpus(ctx, c)
+}
+
+func openCorpusCheckpoint(c *corpus.Corpus, op, params string) (*corpus.Checkpoint, error) {
+	if corpusResumeFlag {
+		return c.ResumeCheckpoint(op, params)
+	}
+	return c.NewCheckpoint(op, params)
+}
+
+func init() {
+	corpusCmd.PersistentFlags().StringVar(&corpusDirFlag, "dir", corpus.DefaultRoot, "Directory holding corpora")
+	corpusCmd.PersistentFlags().StringVar(&corpusStorageDSN, "storage", "", "Database corpus definitions are shared in: postgres:// URL or SQLite path (default $ERST_STORAGE_DSN)")
+
+	corpusAddCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
+	corpusAddCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Horizon RPC URL")
+	corpusAddCmd.Flags().StringVar(&corpusExpectFlag, "expect", "", "Expected replay status (success or error; default: on-chain result)")
+	corpusAddCmd.Flags().StringVar(&corpusNoteFlag, "note", "", "Free-form note stored with the entries")
+	corpusAddCmd.Flags().StringVar(&corpusSessionFlag, "session", "", "Pin the transaction of this session (default: the current session)")
+
+	corpusRunCmd.Flags().StringVar(&corpusWasmFlag, "wasm", "", "Candidate WASM to replay instead of the deployed code")
+	corpusRunCmd.Flags().BoolVar(&corpusJSONFlag, "json", false, "Output the matrix as JSON")
+	corpusRunCmd.Flags().StringVar(&corpusJUnitFlag, "junit", "", "Also write a JUnit XML report to this path")
+	corpusSandbox.register(corpusRunCmd, simulator.Limits{})
+
+	corpusAddCmd.Flags().BoolVar(&corpusResumeFlag, "resume", false, "Skip transactions added before an interrupted run")
+	corpusRunCmd.Flags().BoolVar(&corpusResumeFlag, "resume", false, "Reuse results of entries replayed before an interrupted run")
+
+	supportPorcelain(corpusListCmd, corpusRunCmd)
+	corpusCmd.AddCommand(corpusAddCmd)
+	corpusCmd.AddCommand(corpusRemoveCmd)
+	corpusCmd.AddCommand(corpusListCmd)
+	corpusCmd.AddCommand(corpusRunCmd)
+
+	rootCmd.AddCommand(corpusCmd)
+}
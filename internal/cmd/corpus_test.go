@@ -0,0 +1,48 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/corpus"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCorpusSession(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	t.Setenv("ERST_STORAGE_DSN", "")
+	saved := sessions
+	sessions = session.NewManager("")
+	t.Cleanup(func() { sessions = saved })
+
+	meta, err := xdr.MarshalBase64(xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{
+			Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &[]xdr.OperationResult{}},
+		}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 1, V1: &xdr.TransactionMetaV1{}},
+	})
+	require.NoError(t, err)
+
+	c, err := corpus.OpenOrCreate(filepath.Join(t.TempDir(), "corpus"), "payments")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, addCorpusSession(ctx, c, ""), session.ErrNoActiveSession)
+
+	SetCurrentSession(&session.SessionData{ID: "partial", TxHash: "abcd"})
+	assert.ErrorContains(t, addCorpusSession(ctx, c, ""), "add abcd by hash instead")
+
+	SetCurrentSession(&session.SessionData{ID: "s1", Network: "testnet", TxHash: "abcd", EnvelopeXdr: "AAAA", ResultMetaXdr: meta})
+	require.NoError(t, addCorpusSession(ctx, c, ""))
+	require.Len(t, c.Entries, 1)
+	assert.Equal(t, "abcd", c.Entries[0].Hash)
+	assert.Equal(t, "testnet", c.Entries[0].Network)
+	assert.Equal(t, "AAAA", c.Entries[0].EnvelopeXdr)
+}
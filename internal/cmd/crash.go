@@ -0,0 +1,184 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/crash"
+	"github.com/dotandev/hintents/internal/platform"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+const crashIssueURL = "https://github.com/dotandev/hintents/issues/new"
+
+var (
+	crashEndpointFlag string
+	crashYesFlag      bool
+)
+
+var crashCmd = &cobra.Command{
+	Use:   "crash",
+	Short: "Review and report local crash reports",
+	Long: `When erst panics or the simulator crashes, a crash report is written under the
+erst data directory and its path is printed. Reports contain the stack trace,
+the simulator's stderr, metadata about the simulation request (sizes and
+ledger numbers, never the XDR itself) and the platform. Keys, hashes, XDR,
+URL credentials and query strings and the home directory are redacted.
+
+Nothing is sent automatically.
+
+Available subcommands:
+  list   - List saved crash reports
+  report - Review a crash report and send it to the maintainers`,
+}
+
+var crashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved crash reports",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bundles, err := crash.List()
+		if err != nil {
+			return err
+		}
+		if len(bundles) == 0 {
+			fmt.Println("No crash reports.")
+			return nil
+		}
+		table := visualizer.NewTable("ID", "KIND", "VERSION", "MESSAGE")
+		for _, b := range bundles {
+			table.AddRow(b.ID, b.Kind, b.Version, firstLine(b.Message, 60))
+		}
+		table.Render(os.Stdout)
+		return nil
+	},
+}
+
+var crashReportCmd = &cobra.Command{
+	Use:   "report [id]",
+	Short: "Review a crash report and send it to the maintainers",
+	Long: `Print a crash report (the most recent one when no ID is given) and, after
+confirmation, send it. With --endpoint or ERST_CRASH_ENDPOINT the report is
+POSTed as JSON. Otherwise a link to open a prefilled GitHub issue is printed,
+and the report file can be attached to it.`,
+	Example: `  erst crash report
+  erst crash report 20250102-030405-a1b2c3 --yes --endpoint https://crash.example.org/erst`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var b *crash.Bundle
+		if len(args) == 1 {
+			var err error
+			if b, err = crash.Load(args[0]); err != nil {
+				return err
+			}
+		} else {
+			bundles, err := crash.List()
+			if err != nil {
+				return err
+			}
+			if len(bundles) == 0 {
+				return fmt.Errorf("no crash reports found")
+			}
+			b = bundles[0]
+		}
+
+		data, err := json.MarshalIndent(b, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+
+		endpoint := crashEndpointFlag
+		if endpoint == "" {
+			endpoint = os.Getenv("ERST_CRASH_ENDPOINT")
+		}
+		if !crashYesFlag && !confirm("\nSend this crash report?") {
+			fmt.Println("Not sent.")
+			return nil
+		}
+
+		if endpoint == "" {
+			dir, err := crash.Dir()
+			if err != nil {
+				return err
+			}
+			fmt.Printf("\nOpen this link to file an issue, and attach %s:\n%s\n",
+				filepath.Join(dir, b.ID+".json"), crashIssueLink(b))
+			return nil
+		}
+
+		hc := &http.Client{Timeout: 15 * time.Second}
+		req, err := http.NewRequestWithContext(cmd.Context(), http.MethodPost, endpoint, bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("invalid crash endpoint: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := hc.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send crash report: %w", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("crash endpoint returned %s", resp.Status)
+		}
+		fmt.Printf("Crash report %s sent. Thank you!\n", b.ID)
+		return nil
+	},
+}
+
+// crashIssueLink builds a GitHub new-issue URL summarizing b
+func crashIssueLink(b *crash.Bundle) string {
+	body := fmt.Sprintf("Crash report `%s` (%s)\n\n- erst %s\n- %s/%s, %s\n\n```\n%s\n```\n\nPlease attach the crash report file.",
+		b.ID, b.Kind, b.Version, b.Environment.OS, b.Environment.Arch, b.Environment.GoVersion, firstLine(b.Message, 500))
+	q := url.Values{}
+	q.Set("title", "Crash: "+firstLine(b.Message, 80))
+	q.Set("body", body)
+	q.Set("labels", "crash")
+	return crashIssueURL + "?" + q.Encode()
+}
+
+func firstLine(s string, max int) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if len(s) > max {
+		s = s[:max] + "..."
+	}
+	return s
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no. It answers no
+// without asking when nobody can respond, such as in CI.
+func confirm(question string) bool {
+	if !platform.Interactive() {
+		fmt.Printf("%s Not asking in a non-interactive session; answering no.\n", question)
+		return false
+	}
+	fmt.Printf("%s [y/N] ", question)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
+
+func init() {
+	crashReportCmd.Flags().StringVar(&crashEndpointFlag, "endpoint", "", "POST the report to this URL instead of printing an issue link")
+	crashReportCmd.Flags().BoolVarP(&crashYesFlag, "yes", "y", false, "Send without asking for confirmation")
+	supportPorcelain(crashListCmd)
+	crashCmd.AddCommand(crashListCmd, crashReportCmd)
+	rootCmd.AddCommand(crashCmd)
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/dotandev/hintents/internal/dap"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	dapNetwork string
+	dapRPCURL  string
+	dapListen  string
+)
+
+var dapCmd = &cobra.Command{
+	Use:   "dap",
+	Short: "Run a Debug Adapter Protocol server for editor integration",
+	Long: `Serve the Debug Adapter Protocol (DAP) so VS Code and other editors can
+attach to an erst replay. Editors can set breakpoints on contract functions,
+step between contract calls, and inspect storage, budget and call stacks.
+
+By default the adapter talks over stdin/stdout. Use --listen to accept TCP
+connections instead (VS Code "debugServer" mode).
+
+Launch configuration attributes:
+  hash           Transaction hash to replay
+  network        testnet, mainnet or futurenet (defaults to --network)
+  rpcUrl         Custom Horizon RPC URL
+  envelopeXdr    Replay a raw envelope instead of fetching by hash
+  resultMetaXdr  Result meta used to seed ledger state for envelopeXdr
+  sourceMap      Debug WASM or JSON source map for source locations
+  stopOnEntry    Pause before the first contract call`,
+	Example: `  # .vscode/launch.json
+  {"type": "erst", "request": "launch", "hash": "<tx-hash>", "network": "testnet"}
+
+  # Serve over TCP
+  erst dap --listen 127.0.0.1:4711`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runner, err := simulator.NewRunner("", false)
+		if err != nil {
+			return fmt.Errorf("failed to create simulator: %w", err)
+		}
+		launch := dapLauncher(runner)
+
+		if dapListen == "" {
+			return dap.NewServer(launch).Serve(cmd.Context(), os.Stdin, os.Stdout)
+		}
+
+		ln, err := net.Listen("tcp", dapListen)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", dapListen, err)
+		}
+		defer ln.Close()
+		fmt.Fprintf(os.Stderr, "DAP server listening on %s\n", ln.Addr())
+
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return fmt.Errorf("failed to accept connection: %w", err)
+			}
+			go func() {
+				defer conn.Close()
+				if err := dap.NewServer(launch).Serve(cmd.Context(), conn, conn); err != nil {
+					logger.Logger.Error("DAP session failed", "error", err)
+				}
+			}()
+		}
+	},
+}
+
+// dapLauncher builds a simulation request from launch arguments and starts
+// a step session
+func dapLauncher(runner *simulator.Runner) dap.Launcher {
+	clientFor := newClientFactory(dapNetwork, dapRPCURL)
+
+	return func(ctx context.Context, args dap.LaunchArgs) (dap.Session, error) {
+		req := &simulator.SimulationRequest{
+			EnvelopeXdr:   args.EnvelopeXdr,
+			ResultMetaXdr: args.ResultMetaXdr,
+		}
+
+		if req.EnvelopeXdr == "" {
+			if err := rpc.ValidateTransactionHash(args.Hash); err != nil {
+				return nil, fmt.Errorf("invalid transaction hash: %w", err)
+			}
+
+			var client *rpc.Client
+			var err error
+			if args.RPCURL != "" {
+				network := args.Network
+				if network == "" {
+					network = dapNetwork
+				}
+				client, err = rpc.NewClient(rpc.WithNetwork(rpc.Network(network)), rpc.WithHorizonURL(args.RPCURL))
+			} else {
+				client, err = clientFor(args.Network)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			resp, err := client.GetTransaction(ctx, args.Hash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+			}
+			req.EnvelopeXdr = resp.EnvelopeXdr
+			req.ResultMetaXdr = resp.ResultMetaXdr
+		}
+
+		if req.ResultMetaXdr != "" {
+			entries, err := rpc.ExtractLedgerEntriesFromMeta(req.ResultMetaXdr)
+			if err != nil {
+				logger.Logger.Warn("Failed to extract ledger entries from metadata", "error", err)
+			}
+			req.LedgerEntries = entries
+		}
+
+		return runner.StartStep(req)
+	}
+}
+
+func init() {
+	dapCmd.Flags().StringVarP(&dapNetwork, "network", "n", string(rpc.Mainnet), "Default network (testnet, mainnet, futurenet)")
+	dapCmd.Flags().StringVar(&dapRPCURL, "rpc-url", "", "Custom Horizon RPC URL for the default network")
+	dapCmd.Flags().StringVar(&dapListen, "listen", "", "Serve over TCP at this address instead of stdio")
+
+	rootCmd.AddCommand(dapCmd)
+}
@@ -8,23 +8,38 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/dotandev/hintents/internal/artifact"
+	"github.com/dotandev/hintents/internal/compare"
 	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/deploy"
 	"github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/explain"
+	"github.com/dotandev/hintents/internal/golden"
+	"github.com/dotandev/hintents/internal/hooks"
+	"github.com/dotandev/hintents/internal/horizonxref"
 	"github.com/dotandev/hintents/internal/localization"
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
+	"github.com/dotandev/hintents/internal/price"
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/security"
 	"github.com/dotandev/hintents/internal/session"
 	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/dotandev/hintents/internal/sourcemap"
 	"github.com/dotandev/hintents/internal/telemetry"
 	"github.com/dotandev/hintents/internal/tokenflow"
+	"github.com/dotandev/hintents/internal/trace"
 	"github.com/dotandev/hintents/internal/visualizer"
 	"github.com/dotandev/hintents/internal/watch"
 
@@ -41,15 +56,33 @@ var (
 	otlpExporterURL    string
 	generateTrace      bool
 	traceOutputFile    string
-	snapshotFlag       string
+	snapshotFlag       []string
+	fetchMissingFlag   bool
 	compareNetworkFlag string
 	verbose            bool
 	wasmPath           string
 	args               []string
 	noCacheFlag        bool
+	outputDirFlag      string
+	forceFlag          bool
 	demoMode           bool
 	watchFlag          bool
 	watchTimeoutFlag   int
+	explainFlag        bool
+	sourceMapFlag      string
+	stepFlag           bool
+	goldenFlag         string
+	logIgnoreFlag      []string
+	logStripAddrFlag   bool
+	noLogFilterFlag    bool
+	verifyGoldenFlag   string
+	priceSourceFlag    string
+	expectWasmFlag     string
+	minTTLFlag         uint32
+	feeConfigFlag      string
+	checkpointFlag     string
+	sessionTargetFlag  string
+	horizonXrefFlag    bool
 )
 
 // DebugCommand holds dependencies for the debug command
@@ -120,10 +153,10 @@ func (d *DebugCommand) runDebug(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	fmt.Printf("Debugging transaction: %s\n", txHash)
-	fmt.Printf("Network: %s\n", networkFlag)
+	visualizer.Infof("%s\n", localization.Format("debug.debugging_tx", localization.Args{"hash": txHash}))
+	visualizer.Infof("%s\n", localization.Format("debug.network", localization.Args{"network": networkFlag}))
 	if rpcURLFlag != "" {
-		fmt.Printf("RPC URL: %s\n", rpcURLFlag)
+		visualizer.Infof("%s\n", localization.Format("debug.rpc_url", localization.Args{"url": rpcURLFlag}))
 	}
 
 	// Fetch transaction details
@@ -132,7 +165,7 @@ func (d *DebugCommand) runDebug(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to fetch transaction: %w", err)
 	}
 
-	fmt.Printf("Transaction fetched successfully. Envelope size: %d bytes\n", len(resp.EnvelopeXdr))
+	visualizer.Infof("%s\n", localization.Format("debug.tx_fetched", localization.Args{"size": len(resp.EnvelopeXdr)}))
 
 	// TODO: Use d.Runner for simulation when ready
 	// simReq := &simulator.SimulationRequest{
@@ -172,6 +205,9 @@ Local WASM Replay Mode:
   # Debug and save the session
   erst debug abc123...def789 && erst session save
 
+  # Explain why a transaction failed in plain English
+  erst debug --explain abc123...def789
+
   # Compare execution across networks
   erst debug --network testnet --compare-network mainnet <tx-hash>
 
@@ -212,6 +248,13 @@ Local WASM Replay Mode:
 				return fmt.Errorf("invalid compare-network: %s. Must be one of: testnet, mainnet, futurenet", compareNetworkFlag)
 			}
 		}
+
+		if stepFlag && (compareNetworkFlag != "" || WindowFlag > 0) {
+			return fmt.Errorf("--step cannot be combined with --compare-network or --window")
+		}
+		if _, err := sessionContextFromFlags(); err != nil {
+			return err
+		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, cmdArgs []string) error {
@@ -234,6 +277,9 @@ Local WASM Replay Mode:
 		// Network transaction replay mode
 		ctx := cmd.Context()
 		txHash := cmdArgs[0]
+		if err := runHooks(ctx, hooks.Payload{Event: hooks.PreDebug, TxHash: txHash, Network: networkFlag, CompareNetwork: compareNetworkFlag}); err != nil {
+			return err
+		}
 
 		// Initialize OpenTelemetry if enabled
 		if tracingEnabled {
@@ -284,17 +330,22 @@ Local WASM Replay Mode:
 
 		if noCacheFlag {
 			client.CacheEnabled = false
-			fmt.Println("🚫 Cache disabled by --no-cache flag")
+			visualizer.Infof("🚫 Cache disabled by --no-cache flag\n")
 		}
 
-		fmt.Printf("Debugging transaction: %s\n", txHash)
-		fmt.Printf("Primary Network: %s\n", networkFlag)
+		visualizer.Infof("%s\n", localization.Format("debug.debugging_tx", localization.Args{"hash": txHash}))
+		visualizer.Infof("%s\n", localization.Format("debug.primary_network", localization.Args{"network": networkFlag}))
+		var logFilter *compare.LogFilter
 		if compareNetworkFlag != "" {
-			fmt.Printf("Comparing against Network: %s\n", compareNetworkFlag)
+			visualizer.Infof("%s\n", localization.Format("debug.compare_network", localization.Args{"network": compareNetworkFlag}))
+			if logFilter, err = newLogFilter(); err != nil {
+				return err
+			}
 		}
 
 		// Fetch transaction details
 		if watchFlag {
+			ideEvents.Progress("watching", "Waiting for transaction "+txHash)
 			spinner := watch.NewSpinner()
 			poller := watch.NewPoller(watch.PollerConfig{
 				InitialInterval: 1 * time.Second,
@@ -325,18 +376,35 @@ Local WASM Replay Mode:
 			spinner.StopWithMessage("Transaction found! Starting debug...")
 		}
 
-		fmt.Printf("Fetching transaction: %s\n", txHash)
+		visualizer.Infof("%s\n", localization.Format("debug.fetching_tx", localization.Args{"hash": txHash}))
+		ideEvents.Progress("fetching", "Fetching transaction "+txHash)
 		resp, err := client.GetTransaction(ctx, txHash)
 		if err != nil {
 			return fmt.Errorf(localization.Get("error.fetch_transaction"), err)
 		}
 
-		fmt.Printf("Transaction fetched successfully. Envelope size: %d bytes\n", len(resp.EnvelopeXdr))
-
-		// Extract ledger keys for replay
-		keys, err := extractLedgerKeys(resp.ResultMetaXdr)
-		if err != nil {
-			return fmt.Errorf("failed to extract ledger keys: %w", err)
+		visualizer.Infof("%s\n", localization.Format("debug.tx_fetched", localization.Args{"size": len(resp.EnvelopeXdr)}))
+		if resp.EnvelopeXdr == "" {
+			return fmt.Errorf("transaction %s has no envelope XDR; nothing to replay", txHash)
+		}
+		avail := checkAvailability(resp)
+		avail.printWarning()
+
+		// Extract ledger keys for replay, predicting them from the envelope
+		// footprint when meta is missing or unreadable
+		var keys []string
+		if avail.HasMeta {
+			if keys, err = extractLedgerKeys(resp.ResultMetaXdr); err != nil {
+				logger.Logger.Warn("Failed to extract ledger keys from metadata, predicting footprint", "error", err)
+				avail.Missing = append(avail.Missing, "readable result meta XDR")
+				avail.HasMeta = false
+			}
+		}
+		if !avail.HasMeta {
+			if keys, err = rpc.PredictFootprint(resp.EnvelopeXdr); err != nil {
+				return fmt.Errorf("failed to predict footprint: %w", err)
+			}
+			avail.note("ledger state fetched for %d keys predicted from the envelope footprint instead of meta", len(keys))
 		}
 
 		// Initialize Simulator Runner
@@ -355,11 +423,22 @@ Local WASM Replay Mode:
 			}
 		}
 
+		var srcMap *sourcemap.Map
+		if sourceMapFlag != "" {
+			srcMap, err = sourcemap.Load(sourceMapFlag)
+			if err != nil {
+				return err
+			}
+		}
+
 		var lastSimResp *simulator.SimulationResponse
+		var lastLedger map[string]string
+		var lastCompare *compareReport
+		goldenReport := golden.NewReport(txHash)
 
 		for _, ts := range timestamps {
 			if len(timestamps) > 1 {
-				fmt.Printf("\n--- Simulating at Timestamp: %d ---\n", ts)
+				visualizer.Infof("\n%s\n", localization.Format("debug.simulating_at", localization.Args{"timestamp": strconv.FormatInt(ts, 10)}))
 			}
 
 			var simResp *simulator.SimulationResponse
@@ -367,16 +446,18 @@ Local WASM Replay Mode:
 
 			if compareNetworkFlag == "" {
 				// Single Network Run
-				if snapshotFlag != "" {
-					snap, err := snapshot.Load(snapshotFlag)
+				if len(snapshotFlag) > 0 {
+					layers, err := loadSnapshotLayers(ctx, client, keys)
 					if err != nil {
-						return fmt.Errorf("failed to load snapshot: %w", err)
+						return err
 					}
-					ledgerEntries = snap.ToMap()
-					fmt.Printf("Loaded %d ledger entries from snapshot\n", len(ledgerEntries))
+					ledgerEntries = layers.Entries
 				} else {
 					// Try to extract from metadata first, fall back to fetching
-					ledgerEntries, err = rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+					err = fmt.Errorf("result meta unavailable")
+					if avail.HasMeta {
+						ledgerEntries, err = rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+					}
 					if err != nil {
 						logger.Logger.Warn("Failed to extract ledger entries from metadata, fetching from network", "error", err)
 						ledgerEntries, err = client.GetLedgerEntries(ctx, keys)
@@ -388,96 +469,77 @@ Local WASM Replay Mode:
 					}
 				}
 
-				fmt.Printf("Running simulation on %s...\n", networkFlag)
+				visualizer.Infof("%s\n", localization.Format("debug.running_simulation", localization.Args{"network": networkFlag}))
+				ideEvents.Progress("simulating", "Running simulation on "+networkFlag)
 				simReq := &simulator.SimulationRequest{
 					EnvelopeXdr:   resp.EnvelopeXdr,
 					ResultMetaXdr: resp.ResultMetaXdr,
 					LedgerEntries: ledgerEntries,
 					Timestamp:     ts,
+					Profile:       ProfileFlag,
 				}
 
-				simResp, err = runner.Run(simReq)
-				if err != nil {
-					return fmt.Errorf("simulation failed: %w", err)
-				}
-				printSimulationResult(networkFlag, simResp)
-			} else {
-				// Comparison Run
-				var wg sync.WaitGroup
-				var primaryResult, compareResult *simulator.SimulationResponse
-				var primaryErr, compareErr error
-
-				wg.Add(2)
-				go func() {
-					defer wg.Done()
-					var entries map[string]string
-					var extractErr error
-					entries, extractErr = rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
-					if extractErr != nil {
-						entries, extractErr = client.GetLedgerEntries(ctx, keys)
-						if extractErr != nil {
-							primaryErr = extractErr
-							return
-						}
-					}
-					primaryResult, primaryErr = runner.Run(&simulator.SimulationRequest{
-						EnvelopeXdr:   resp.EnvelopeXdr,
-						ResultMetaXdr: resp.ResultMetaXdr,
-						LedgerEntries: entries,
-						Timestamp:     ts,
-					})
-				}()
-
-				go func() {
-					defer wg.Done()
-					compareOpts := []rpc.ClientOption{
-						rpc.WithNetwork(rpc.Network(compareNetworkFlag)),
-						rpc.WithToken(rpcTokenFlag),
+				if stepFlag {
+					simResp, err = runStepDebugger(runner, simReq)
+					if err != nil {
+						return err
 					}
-					compareClient, clientErr := rpc.NewClient(compareOpts...)
-					if clientErr != nil {
-						compareErr = fmt.Errorf("failed to create compare client: %w", clientErr)
-						return
+					if simResp == nil {
+						return nil
 					}
-					if noCacheFlag {
-						compareClient.CacheEnabled = false
+				} else {
+					simResp, err = runner.Run(simReq)
+					if err != nil {
+						return fmt.Errorf("simulation failed: %w", err)
 					}
-
-					compareResp, txErr := compareClient.GetTransaction(ctx, txHash)
-					if txErr != nil {
-						compareErr = fmt.Errorf("failed to fetch transaction from %s: %w", compareNetworkFlag, txErr)
-						return
+				}
+				lastLedger = ledgerEntries
+				printSimulationResult(networkFlag, simResp)
+				printSourceTrace(simResp, srcMap)
+				ideEvents.Result("simulation", map[string]interface{}{"network": networkFlag, "timestamp": ts, "response": simResp})
+				goldenReport.AddSimulation(networkFlag, simResp)
+			} else {
+				// Comparison Run. Both sides replay on-chain state, so their
+				// results are reused across runs with identical inputs.
+				ideEvents.Progress("simulating", fmt.Sprintf("Running simulation on %s and %s", networkFlag, compareNetworkFlag))
+				var compareRunner simulator.RunnerInterface = runner
+				var cached *simulator.CachingRunner
+				if !noCacheFlag {
+					if cached, err = simulator.NewCachingRunner(runner); err != nil {
+						return err
 					}
-
-					entries, extractErr := rpc.ExtractLedgerEntriesFromMeta(compareResp.ResultMetaXdr)
-					if extractErr != nil {
-						entries, extractErr = compareClient.GetLedgerEntries(ctx, keys)
-						if extractErr != nil {
-							compareErr = extractErr
-							return
-						}
+					compareRunner = cached
+				}
+				jobs := compareJobs(client, resp, txHash, keys, ts, func(w io.Writer, network string, res *simulator.SimulationResponse) {
+					writeSimulationResult(w, network, res)
+					if network == networkFlag {
+						writeSourceTrace(w, res, srcMap)
 					}
-
-					compareResult, compareErr = runner.Run(&simulator.SimulationRequest{
-						EnvelopeXdr:   resp.EnvelopeXdr,
-						ResultMetaXdr: compareResp.ResultMetaXdr,
-						LedgerEntries: entries,
-						Timestamp:     ts,
-					})
-				}()
-
-				wg.Wait()
-				if primaryErr != nil {
-					return fmt.Errorf("primary network error: %w", primaryErr)
+				})
+				runs, err := runNetworks(ctx, compareRunner, jobs)
+				if err != nil {
+					return fmt.Errorf("compare run failed on %w", err)
 				}
-				if compareErr != nil {
-					return fmt.Errorf("compare network error: %w", compareErr)
+				if cached != nil {
+					if hits, _ := cached.Stats(); hits > 0 {
+						visualizer.Infof("Reused %d cached simulation result(s); pass --no-cache to run them again\n", hits)
+					}
 				}
 
+				// Merge in a fixed order once both networks finished
+				for _, run := range runs {
+					recordSimulationResult(run.network, run.resp)
+					_, _ = os.Stdout.Write(run.out.Bytes())
+				}
+				primaryResult, compareResult := runs[0].resp, runs[1].resp
 				simResp = primaryResult // Use primary for further analysis
-				printSimulationResult(networkFlag, primaryResult)
-				printSimulationResult(compareNetworkFlag, compareResult)
-				diffResults(primaryResult, compareResult, networkFlag, compareNetworkFlag)
+				lastLedger = runs[0].req.LedgerEntries
+				diffResults(primaryResult, compareResult, networkFlag, compareNetworkFlag, logFilter)
+				lastCompare = newCompareReport(txHash, networkFlag, compareNetworkFlag, primaryResult, compareResult)
+				ideEvents.Result("simulation", map[string]interface{}{"network": networkFlag, "timestamp": ts, "response": primaryResult})
+				ideEvents.Result("simulation", map[string]interface{}{"network": compareNetworkFlag, "timestamp": ts, "response": compareResult})
+				goldenReport.AddSimulation(networkFlag, primaryResult)
+				goldenReport.AddSimulation(compareNetworkFlag, compareResult)
 			}
 			lastSimResp = simResp
 		}
@@ -486,59 +548,150 @@ Local WASM Replay Mode:
 			return fmt.Errorf("no simulation results generated")
 		}
 
+		if err := printDeployment(resp.EnvelopeXdr, client.Config.NetworkPassphrase); err != nil {
+			return err
+		}
+		if avail.HasMeta {
+			printFootprintTTL(resp)
+		} else {
+			avail.skip("Footprint TTL report", "result meta")
+		}
+		printFootprintCheck(resp, lastSimResp)
+		var produced []artifact.Artifact
+		var reportPath string
+		if generateTrace && (outputDirFlag == "" || traceOutputFile != "") {
+			path, err := writeExecutionTrace(txHash, resp, lastSimResp, lastLedger)
+			if err != nil {
+				return err
+			}
+			produced = appendProduced(produced, path, artifact.KindTrace, "Execution trace")
+			reportPath = path
+		}
+		printFeeBreakdown(ctx, client, resp, lastSimResp)
+		if !avail.HasResult && !avail.HasMeta {
+			avail.note("fee breakdown shows declared resources only; charged fees are unknown")
+		}
+		if avail.HasResult {
+			printFeeContext(ctx, client, resp)
+			printSequenceContext(ctx, client, txHash, resp)
+		} else {
+			avail.skip("Fee competition context", "result XDR")
+		}
+
 		// Analysis: Security
-		fmt.Printf("\n=== Security Analysis ===\n")
+		if !avail.HasMeta {
+			avail.note("security analysis used simulation events and logs only")
+		}
+		fmt.Printf("\n%s\n", visualizer.Heading("Security Analysis"))
+		ideEvents.Progress("analyzing", "Running security analysis")
 		secDetector := security.NewDetector()
-		findings := secDetector.Analyze(resp.EnvelopeXdr, resp.ResultMetaXdr, lastSimResp.Events, lastSimResp.Logs)
-		if len(findings) == 0 {
-			fmt.Printf("%s No security issues detected\n", visualizer.Success())
+		if weights, err := securityWeights(); err != nil {
+			logger.Logger.Warn("Ignoring invalid security weights", "error", err)
 		} else {
-			verifiedCount := 0
-			heuristicCount := 0
-
-			for _, finding := range findings {
-				if finding.Type == security.FindingVerifiedRisk {
-					verifiedCount++
-				} else {
-					heuristicCount++
-				}
+			secDetector.SetWeights(weights)
+		}
+		findings := secDetector.AnalyzeSimulation(resp.EnvelopeXdr, resp.ResultMetaXdr, lastSimResp)
+		for _, finding := range findings {
+			ideEvents.Finding(finding)
+		}
+		printSecurityFindings(findings)
+
+		if explainFlag {
+			fmt.Printf("\n%s\n", visualizer.Heading("Explanation"))
+			paragraphs := explain.Explain(explain.Input{
+				EnvelopeXdr:   resp.EnvelopeXdr,
+				ResultXdr:     resp.ResultXdr,
+				ResultMetaXdr: resp.ResultMetaXdr,
+				Simulation:    lastSimResp,
+				Findings:      findings,
+
+				NetworkPassphrase: client.Config.NetworkPassphrase,
+				LedgerSequence:    resp.Ledger,
+				LedgerCloseTime:   resp.LedgerCloseTime,
+			})
+			for _, paragraph := range paragraphs {
+				fmt.Printf("%s\n\n", paragraph)
 			}
+			ideEvents.Result("explanation", paragraphs)
+		}
 
-			if verifiedCount > 0 {
-				fmt.Printf("\n[!]  VERIFIED SECURITY RISKS: %d\n", verifiedCount)
+		goldenReport.AddFindings(findings)
+		if err := handleGolden(goldenReport); err != nil {
+			return err
+		}
+
+		// Analysis: Token Flows
+		var flows *tokenflow.Report
+		tokenMeta := tokenflow.NewMetadataResolver(func(contractID, function string) (xdr.ScVal, error) {
+			return simulator.InvokeContract(runner, "", contractID, function, lastLedger)
+		}, nil)
+		if !avail.HasMeta {
+			avail.skip("Token flows and balance verification", "result meta")
+		} else if report, err := tokenflow.BuildReport(resp.EnvelopeXdr, resp.ResultMetaXdr); err == nil && len(report.Agg) > 0 {
+			flows = report
+			report.ApplyMetadata(tokenMeta)
+			if priceFn, err := newPriceFunc(ctx); err != nil {
+				logger.Logger.Warn("Price source unavailable, token flows will not be valued", "error", err)
+			} else if priceFn != nil {
+				report.ApplyPrices(priceFn)
 			}
-			if heuristicCount > 0 {
-				fmt.Printf("* HEURISTIC WARNINGS: %d\n", heuristicCount)
+			printTokenFlows(report)
+			ideEvents.Result("token_flow", map[string]interface{}{"summary": report.SummaryLines(), "mermaid": report.MermaidFlowchart()})
+
+			for _, d := range printBalanceVerification(report, resp.ResultMetaXdr, client.Config.NetworkPassphrase) {
+				ideEvents.Finding(map[string]interface{}{"kind": "balance_mismatch", "holder": d.Holder, "token": d.Token, "expected": d.Expected.String(), "actual": d.Actual.String()})
 			}
+		}
 
-			fmt.Printf("\nFindings:\n")
-			for i, finding := range findings {
-				icon := "*"
-				if finding.Type == security.FindingVerifiedRisk {
-					icon = "[!]"
-				}
-				fmt.Printf("%d. %s [%s] %s - %s\n", i+1, icon, finding.Type, finding.Severity, finding.Title)
-				fmt.Printf("   %s\n", finding.Description)
-				if finding.Evidence != "" {
-					fmt.Printf("   Evidence: %s\n", finding.Evidence)
+		if horizonXrefFlag {
+			xref := horizonxref.Input{
+				EnvelopeXdr:       resp.EnvelopeXdr,
+				Simulation:        lastSimResp,
+				Flows:             flows,
+				NetworkPassphrase: client.Config.NetworkPassphrase,
+			}
+			// A transaction with no token flows still has balances to compare
+			if flows == nil && avail.HasMeta {
+				if report, err := tokenflow.BuildReport(resp.EnvelopeXdr, resp.ResultMetaXdr); err == nil {
+					xref.Flows = report
 				}
 			}
+			printHorizonCrossReference(ctx, client, txHash, xref)
 		}
 
-		// Analysis: Token Flows
-		if report, err := tokenflow.BuildReport(resp.EnvelopeXdr, resp.ResultMetaXdr); err == nil && len(report.Agg) > 0 {
-			fmt.Printf("\nToken Flow Summary:\n")
-			for _, line := range report.SummaryLines() {
-				fmt.Printf("  %s\n", line)
+		var artifactDir string
+		if outputDirFlag != "" {
+			dir, err := writeDebugArtifacts(outputDirFlag, txHash, resp, lastSimResp, lastLedger, findings, flows)
+			if err != nil {
+				return err
+			}
+			artifactDir = dir.Path
+			reportPath = filepath.Join(dir.Path, artifact.IndexName)
+			produced = append(produced, dir.Manifest.Artifacts...)
+			fmt.Printf("\nArtifacts written to %s (%d files, indexed in %s)\n", dir.Path, len(dir.Manifest.Artifacts), artifact.IndexName)
+			visualizer.Record("artifacts", dir.Path)
+			archiveDebugArtifacts(ctx, dir)
+		} else {
+			if lastSimResp.Flamegraph != "" {
+				path := artifact.UniquePath(txHash+".flamegraph.svg", forceFlag)
+				if err := os.WriteFile(path, []byte(lastSimResp.Flamegraph), 0644); err != nil {
+					return fmt.Errorf("failed to write flamegraph: %w", err)
+				}
+				produced = appendProduced(produced, path, artifact.KindFlamegraph, "CPU and memory flamegraph")
+				fmt.Printf("\nFlamegraph written to %s\n", path)
 			}
-			fmt.Printf("\nToken Flow Chart (Mermaid):\n")
-			fmt.Println(report.MermaidFlowchart())
+			archiveDebugRun(ctx, txHash, resp, lastSimResp, lastLedger, findings, flows)
 		}
 
+		avail.printSummary()
+
 		// Session Management
 		simReq := &simulator.SimulationRequest{
 			EnvelopeXdr:   resp.EnvelopeXdr,
 			ResultMetaXdr: resp.ResultMetaXdr,
+			LedgerEntries: lastLedger,
+			Timestamp:     timestamps[len(timestamps)-1],
+			Profile:       ProfileFlag,
 		}
 		simReqJSON, err := json.Marshal(simReq)
 		if err != nil {
@@ -549,6 +702,24 @@ Local WASM Replay Mode:
 			fmt.Printf("Warning: failed to serialize simulation results: %v\n", err)
 		}
 
+		appContext, err := sessionContextFromFlags()
+		if err != nil {
+			return err
+		}
+		run := session.Run{
+			Name:            checkpointFlag,
+			CreatedAt:       time.Now(),
+			Description:     describeRun(),
+			SimRequestJSON:  string(simReqJSON),
+			SimResponseJSON: string(simRespJSON),
+			OutputDir:       artifactDir,
+			Artifacts:       produced,
+		}
+		// Comparison runs replay requests the session does not keep, so
+		// only single-network runs can be verified later
+		if compareNetworkFlag == "" {
+			run.Fingerprint = fingerprintRun(runner, simReq, lastSimResp)
+		}
 		sessionData := &session.SessionData{
 			ID:              session.GenerateID(txHash),
 			CreatedAt:       time.Now(),
@@ -562,16 +733,100 @@ Local WASM Replay Mode:
 			ResultMetaXdr:   resp.ResultMetaXdr,
 			SimRequestJSON:  string(simReqJSON),
 			SimResponseJSON: string(simRespJSON),
+			TokenMetadata:   tokenMeta.Resolved(),
 			ErstVersion:     Version,
 			SchemaVersion:   session.SchemaVersion,
+			Partial:         avail.Partial(),
+			SkippedAnalyses: avail.Skipped,
+			Context:         appContext,
+		}
+		if err := sessionData.AddRun(run); err != nil {
+			return err
+		}
+		if sessionTargetFlag != "" {
+			stored, err := recordCheckpoint(ctx, sessionTargetFlag, sessionData)
+			if err != nil {
+				return err
+			}
+			SetCurrentSession(stored)
+			if err := sessions.Select(stored.ID); err != nil {
+				logger.Logger.Warn("Failed to record current session", "error", err)
+			}
+			fmt.Printf("\nCheckpoint %q recorded in session %s (%d checkpoints)\n", run.Name, stored.ID, len(stored.Runs))
+			visualizer.Record("checkpoint", stored.ID, run.Name)
+			ideEvents.Result("checkpoint", map[string]string{"session": stored.ID, "name": run.Name})
+			runPostDebugHooks(ctx, stored, reportPath, artifactDir, lastCompare)
+			return nil
 		}
 		SetCurrentSession(sessionData)
 		fmt.Printf("\nSession created: %s\n", sessionData.ID)
-		fmt.Printf("Run 'erst session save' to persist this session.\n")
+		visualizer.Record("session", sessionData.ID)
+		ideEvents.Result("session", map[string]string{"id": sessionData.ID, "tx_hash": txHash, "network": networkFlag})
+		visualizer.Infof("Run 'erst session save' to persist this session.\n")
+		runPostDebugHooks(ctx, sessionData, reportPath, artifactDir, lastCompare)
 		return nil
 	},
 }
 
+// loadSnapshotLayers stacks the --snapshot files in order and, with
+// --fetch-missing, fills keys none of them provide from the network
+func loadSnapshotLayers(ctx context.Context, client *rpc.Client, keys []string) (*snapshot.Layers, error) {
+	layers, err := snapshot.LoadLayers(ctx, snapshotFlag, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load snapshot: %w", err)
+	}
+
+	if missing := layers.Missing(keys); len(missing) > 0 {
+		if fetchMissingFlag {
+			fetched, err := client.GetLedgerEntries(ctx, missing)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch ledger entries missing from snapshots: %w", err)
+			}
+			layers.Fill("network:"+networkFlag, fetched)
+		} else {
+			logger.Logger.Warn("Ledger keys missing from snapshots; use --fetch-missing to fetch them", "count", len(missing))
+		}
+	}
+
+	if visualizer.Quiet() {
+		return layers, nil
+	}
+	if len(snapshotFlag) == 1 && !fetchMissingFlag {
+		fmt.Printf("Loaded %d ledger entries from snapshot\n", len(layers.Entries))
+		return layers, nil
+	}
+	counts := layers.CountByOrigin()
+	fmt.Printf("Loaded %d ledger entries from %d snapshot layers (%d overridden)\n", len(layers.Entries), len(snapshotFlag), layers.Overridden)
+	table := visualizer.NewTable("LAYER", "ENTRIES").AlignRight(1)
+	for _, loc := range snapshotFlag {
+		table.AddRow(loc, strconv.Itoa(counts[loc]))
+	}
+	if fetchMissingFlag {
+		table.AddRow("network:"+networkFlag, strconv.Itoa(counts["network:"+networkFlag]))
+	}
+	table.Render(os.Stdout)
+	return layers, nil
+}
+
+// describeRun summarizes the flags that make this run an experiment rather
+// than a plain replay
+func describeRun() string {
+	var parts []string
+	if len(snapshotFlag) > 0 {
+		parts = append(parts, "snapshot="+strings.Join(snapshotFlag, "+"))
+	}
+	if fetchMissingFlag {
+		parts = append(parts, "fetch-missing")
+	}
+	if expectWasmFlag != "" {
+		parts = append(parts, "expect-wasm="+expectWasmFlag)
+	}
+	if compareNetworkFlag != "" {
+		parts = append(parts, "compare-network="+compareNetworkFlag)
+	}
+	return strings.Join(parts, ", ")
+}
+
 // runDemoMode prints sample output without network/WASM - for testing color detection.
 func runDemoMode(cmdArgs []string) error {
 	txHash := "5c0a1234567890abcdef1234567890abcdef1234567890abcdef1234567890ab"
@@ -579,20 +834,20 @@ func runDemoMode(cmdArgs []string) error {
 		txHash = cmdArgs[0]
 	}
 
-	fmt.Printf("Fetching transaction: %s\n", txHash)
-	fmt.Printf("Transaction fetched successfully. Envelope size: 256 bytes\n")
-	fmt.Printf("\n--- Result for %s ---\n", networkFlag)
-	fmt.Printf("Status: success\n")
-	fmt.Printf("\nResource Usage:\n")
-	fmt.Printf("  CPU Instructions: 12345\n")
-	fmt.Printf("  Memory Bytes: 1024\n")
-	fmt.Printf("  Operations: 5\n")
-	fmt.Printf("\nEvents: 2, Logs: 3\n")
-	fmt.Printf("\n=== Security Analysis ===\n")
-	fmt.Printf("%s No security issues detected\n", visualizer.Success())
-	fmt.Printf("\nToken Flow Summary:\n")
-	fmt.Printf("  %s XLM transferred\n", visualizer.Symbol("arrow_r"))
-	fmt.Printf("\nSession ready. Use 'erst session save' to persist.\n")
+	fmt.Println(localization.Format("debug.fetching_tx", localization.Args{"hash": txHash}))
+	fmt.Println(localization.Format("debug.tx_fetched", localization.Args{"size": 256}))
+	fmt.Printf("\n%s\n", localization.Format("result.header", localization.Args{"network": networkFlag}))
+	fmt.Println(localization.Format("result.status", localization.Args{"status": "success"}))
+	fmt.Printf("\n%s\n", localization.Format("result.resource_usage", nil))
+	fmt.Println(localization.Format("demo.cpu", localization.Args{"used": 12345}))
+	fmt.Println(localization.Format("demo.memory", localization.Args{"used": 1024}))
+	fmt.Println(localization.Format("result.operations", localization.Args{"count": 5}))
+	fmt.Printf("\n%s\n", localization.Format("result.summary", localization.Args{"events": 2, "logs": 3}))
+	fmt.Printf("\n%s\n", visualizer.Heading(localization.Format("security.heading", nil)))
+	fmt.Println(localization.Format("security.no_issues", localization.Args{"mark": visualizer.Success()}))
+	fmt.Printf("\n%s\n", localization.Format("tokenflow.heading", nil))
+	fmt.Println(localization.Format("demo.xlm_transferred", localization.Args{"arrow": visualizer.Symbol("arrow_r")}))
+	fmt.Printf("\n%s\n", localization.Format("demo.session_ready", nil))
 	return nil
 }
 
@@ -746,147 +1001,740 @@ func extractLedgerKeys(metaXdr string) ([]string, error) {
 	for k := range keysMap {
 		res = append(res, k)
 	}
+	sort.Strings(res)
 	return res, nil
 }
 
 func printSimulationResult(network string, res *simulator.SimulationResponse) {
-	fmt.Printf("\n--- Result for %s ---\n", network)
-	fmt.Printf("Status: %s\n", res.Status)
+	recordSimulationResult(network, res)
+	writeSimulationResult(os.Stdout, network, res)
+}
+
+// recordSimulationResult emits the porcelain records of a simulation result
+func recordSimulationResult(network string, res *simulator.SimulationResponse) {
+	visualizer.Record("status", network, res.Status)
 	if res.Error != "" {
-		fmt.Printf("Error: %s\n", res.Error)
+		visualizer.Record("error", network, res.Error)
 	}
-
-	// Display budget usage if available
 	if res.BudgetUsage != nil {
-		fmt.Printf("\nResource Usage:\n")
+		visualizer.Record("cpu_instructions", network, strconv.FormatUint(res.BudgetUsage.CPUInstructions, 10))
+		visualizer.Record("memory_bytes", network, strconv.FormatUint(res.BudgetUsage.MemoryBytes, 10))
+	}
+	for _, phase := range res.Metering {
+		visualizer.Record("metering", network, phase.Phase, strconv.FormatUint(phase.CPUInsns, 10), strconv.FormatUint(phase.MemBytes, 10))
+	}
+	visualizer.Record("events", network, strconv.Itoa(len(res.Events)))
+	visualizer.Record("logs", network, strconv.Itoa(len(res.Logs)))
+}
 
-		// CPU usage with percentage and warning indicator
-		cpuIndicator := ""
-		if res.BudgetUsage.CPUUsagePercent >= 95.0 {
-			cpuIndicator = " [!]  CRITICAL"
-		} else if res.BudgetUsage.CPUUsagePercent >= 80.0 {
-			cpuIndicator = " [!]  WARNING"
+// writeMetering lists the host's metering per phase, with its other
+// counters in name order
+func writeMetering(w io.Writer, phases []simulator.MeterPhase) {
+	if len(phases) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", localization.Format("result.metering", nil))
+	for _, phase := range phases {
+		fmt.Fprintln(w, localization.Format("result.meter_phase", localization.Args{
+			"phase": phase.Phase, "cpu": phase.CPUInsns, "mem": phase.MemBytes,
+		}))
+		names := make([]string, 0, len(phase.Metrics))
+		for name := range phase.Metrics {
+			names = append(names, name)
 		}
-		fmt.Printf("  CPU Instructions: %d / %d (%.2f%%)%s\n",
-			res.BudgetUsage.CPUInstructions,
-			res.BudgetUsage.CPULimit,
-			res.BudgetUsage.CPUUsagePercent,
-			cpuIndicator)
-
-		// Memory usage with percentage and warning indicator
-		memIndicator := ""
-		if res.BudgetUsage.MemoryUsagePercent >= 95.0 {
-			memIndicator = " [!]  CRITICAL"
-		} else if res.BudgetUsage.MemoryUsagePercent >= 80.0 {
-			memIndicator = " [!]  WARNING"
+		sort.Strings(names)
+		counters := make([]string, len(names))
+		for i, name := range names {
+			counters[i] = fmt.Sprintf("%s=%d", name, phase.Metrics[name])
+		}
+		if len(counters) > 0 {
+			fmt.Fprintf(w, "      %s\n", strings.Join(counters, " "))
 		}
-		fmt.Printf("  Memory Bytes: %d / %d (%.2f%%)%s\n",
-			res.BudgetUsage.MemoryBytes,
-			res.BudgetUsage.MemoryLimit,
-			res.BudgetUsage.MemoryUsagePercent,
-			memIndicator)
+	}
+}
+
+func writeSimulationResult(w io.Writer, network string, res *simulator.SimulationResponse) {
+	fmt.Fprintf(w, "\n%s\n", localization.Format("result.header", localization.Args{"network": network}))
+	fmt.Fprintln(w, localization.Format("result.status", localization.Args{"status": res.Status}))
+	if res.Error != "" {
+		fmt.Fprintln(w, localization.Format("result.error", localization.Args{"error": res.Error}))
+	}
 
-		fmt.Printf("  Operations: %d\n", res.BudgetUsage.OperationsCount)
+	// Display budget usage if available
+	if res.BudgetUsage != nil {
+		fmt.Fprintf(w, "\n%s\n", localization.Format("result.resource_usage", nil))
+		fmt.Fprintln(w, localization.Format("result.cpu", localization.Args{
+			"used":    res.BudgetUsage.CPUInstructions,
+			"limit":   res.BudgetUsage.CPULimit,
+			"percent": res.BudgetUsage.CPUUsagePercent / 100,
+			"level":   usageLevel(res.BudgetUsage.CPUUsagePercent),
+		}))
+		fmt.Fprintln(w, localization.Format("result.memory", localization.Args{
+			"used":    res.BudgetUsage.MemoryBytes,
+			"limit":   res.BudgetUsage.MemoryLimit,
+			"percent": res.BudgetUsage.MemoryUsagePercent / 100,
+			"level":   usageLevel(res.BudgetUsage.MemoryUsagePercent),
+		}))
+		fmt.Fprintln(w, localization.Format("result.operations", localization.Args{"count": res.BudgetUsage.OperationsCount}))
 	}
+	writeMetering(w, res.Metering)
 
 	// Display diagnostic events with details
 	if len(res.DiagnosticEvents) > 0 {
-		fmt.Printf("\nDiagnostic Events: %d\n", len(res.DiagnosticEvents))
+		fmt.Fprintf(w, "\n%s\n", localization.Format("result.diagnostic_events", localization.Args{"count": len(res.DiagnosticEvents)}))
 		for i, event := range res.DiagnosticEvents {
 			if i < 10 { // Show first 10 events
-				fmt.Printf("  [%d] Type: %s", i+1, event.EventType)
+				line := localization.Format("result.event_type", localization.Args{"index": strconv.Itoa(i + 1), "type": event.EventType})
 				if event.ContractID != nil {
-					fmt.Printf(", Contract: %s", *event.ContractID)
+					line += localization.Format("result.event_contract", localization.Args{"contract": *event.ContractID})
 				}
-				fmt.Printf("\n")
+				fmt.Fprintln(w, line)
 				if len(event.Topics) > 0 {
-					fmt.Printf("      Topics: %v\n", event.Topics)
+					fmt.Fprintln(w, localization.Format("result.event_topics", localization.Args{"topics": fmt.Sprint(event.Topics)}))
 				}
 				if event.Data != "" && len(event.Data) < 100 {
-					fmt.Printf("      Data: %s\n", event.Data)
+					fmt.Fprintln(w, localization.Format("result.event_data", localization.Args{"data": event.Data}))
 				}
 			}
 		}
 		if len(res.DiagnosticEvents) > 10 {
-			fmt.Printf("  ... and %d more events\n", len(res.DiagnosticEvents)-10)
+			fmt.Fprintln(w, localization.Format("result.more_events", localization.Args{"count": len(res.DiagnosticEvents) - 10}))
 		}
 	} else {
-		fmt.Printf("\nEvents: %d\n", len(res.Events))
+		fmt.Fprintf(w, "\n%s\n", localization.Format("result.events", localization.Args{"count": len(res.Events)}))
 	}
 
 	// Display logs
 	if len(res.Logs) > 0 {
-		fmt.Printf("\nLogs: %d\n", len(res.Logs))
+		fmt.Fprintf(w, "\n%s\n", localization.Format("result.logs", localization.Args{"count": len(res.Logs)}))
 		for i, log := range res.Logs {
 			if i < 5 { // Show first 5 logs
-				fmt.Printf("  - %s\n", log)
+				fmt.Fprintf(w, "  - %s\n", log)
 			}
 		}
 		if len(res.Logs) > 5 {
-			fmt.Printf("  ... and %d more logs\n", len(res.Logs)-5)
+			fmt.Fprintln(w, localization.Format("result.more_logs", localization.Args{"count": len(res.Logs) - 5}))
+		}
+	}
+	fmt.Fprintln(w, localization.Format("result.summary", localization.Args{"events": len(res.Events), "logs": len(res.Logs)}))
+}
+
+// usageLevel selects the warning shown next to a budget usage percentage
+func usageLevel(percent float64) string {
+	switch {
+	case percent >= 95.0:
+		return "critical"
+	case percent >= 80.0:
+		return "warning"
+	}
+	return "ok"
+}
+
+// handleGolden writes or verifies the canonical report when requested
+func handleGolden(r *golden.Report) error {
+	if goldenFlag != "" {
+		if err := golden.Write(goldenFlag, r); err != nil {
+			return err
+		}
+		fmt.Printf("\nGolden report written to %s\n", goldenFlag)
+	}
+
+	if verifyGoldenFlag != "" {
+		diff, err := golden.Verify(verifyGoldenFlag, r)
+		if err != nil {
+			return err
+		}
+		if len(diff) > 0 {
+			fmt.Printf("\n%s\n", visualizer.Heading(fmt.Sprintf("Golden Mismatch (%s)", verifyGoldenFlag)))
+			for _, line := range diff {
+				fmt.Println(line)
+			}
+			return fmt.Errorf("report differs from golden file %s", verifyGoldenFlag)
+		}
+		fmt.Printf("\n%s Report matches golden file %s\n", visualizer.Success(), verifyGoldenFlag)
+	}
+	return nil
+}
+
+// runStepDebugger drives the simulation interactively. It returns a nil
+// response when the user aborts before execution finishes.
+func runStepDebugger(runner *simulator.Runner, req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+	if !platform.Interactive() {
+		return nil, fmt.Errorf("the step debugger needs an interactive terminal")
+	}
+	session, err := runner.StartStep(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start step session: %w", err)
+	}
+	defer session.Close()
+
+	if err := trace.NewStepDebugger(session).Start(); err != nil {
+		return nil, fmt.Errorf("step session failed: %w", err)
+	}
+
+	res := session.Result()
+	if res != nil && res.Status == "error" {
+		return nil, fmt.Errorf("simulation failed: %s", res.Error)
+	}
+	return res, nil
+}
+
+// printSourceTrace prints a mini stack trace of the failing contract calls,
+// annotated with source locations when a source map is loaded
+func printSourceTrace(res *simulator.SimulationResponse, m *sourcemap.Map) {
+	writeSourceTrace(os.Stdout, res, m)
+}
+
+func writeSourceTrace(w io.Writer, res *simulator.SimulationResponse, m *sourcemap.Map) {
+	if res.SourceLocation != "" {
+		fmt.Fprintf(w, "\nFailed at: %s\n", res.SourceLocation)
+	}
+	if m == nil || res.Status == "success" {
+		return
+	}
+
+	frames := sourcemap.TraceFromBacktrace(res.Error, m)
+	if len(frames) == 0 {
+		frames = sourcemap.StackTrace(res.DiagnosticEvents, m)
+	}
+	if len(frames) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\nStack trace:\n")
+	for _, f := range frames {
+		fmt.Fprintf(w, "  %s\n", f)
+	}
+}
+
+// newLogFilter builds the log diff filter from the defaults, the general
+// config file and the command-line patterns. --no-log-filter only leaves
+// out the defaults.
+func newLogFilter() (*compare.LogFilter, error) {
+	var patterns []string
+	if !noLogFilterFlag {
+		patterns = append(patterns, compare.DefaultLogIgnorePatterns...)
+	}
+	if cfg, err := config.LoadConfig(); err == nil {
+		patterns = append(patterns, cfg.LogDiffIgnore...)
+	}
+	patterns = append(patterns, logIgnoreFlag...)
+	return compare.NewLogFilter(patterns, true, logStripAddrFlag)
+}
+
+// printDeployment shows the WASM uploads and contract creations of a
+// transaction with their derived contract IDs, and checks them against
+// --expect-wasm when set
+func printDeployment(envelopeXdr, passphrase string) error {
+	a, err := deploy.Analyze(envelopeXdr, passphrase)
+	if err != nil {
+		logger.Logger.Warn("Failed to analyze deployment", "error", err)
+		return nil
+	}
+	if a.Empty() {
+		return nil
+	}
+
+	fmt.Printf("\n%s\n", visualizer.Heading("Deployment"))
+	for _, u := range a.Uploads {
+		fmt.Printf("Operation #%d: upload WASM %s (%d bytes)\n", u.OpIndex, u.Hash, u.Size)
+		if u.Size > deploy.DefaultMaxWasmSize {
+			fmt.Printf("  %s exceeds the usual %d-byte contract size limit\n", visualizer.Warning(), deploy.DefaultMaxWasmSize)
+		}
+	}
+	for _, c := range a.Creations {
+		fmt.Printf("Operation #%d: create contract %s\n", c.OpIndex, c.ContractID)
+		if c.Deployer != "" {
+			fmt.Printf("  Derived from deployer %s and salt %s\n", c.Deployer, c.Salt)
+		} else {
+			fmt.Printf("  Stellar Asset Contract for %s\n", c.Asset)
+		}
+		if c.WasmHash != "" {
+			source := "previously uploaded"
+			if c.UploadedInTx {
+				source = "uploaded in this transaction"
+			}
+			fmt.Printf("  WASM %s (%s)\n", c.WasmHash, source)
+		}
+		if c.ConstructorArgs > 0 {
+			fmt.Printf("  Constructor arguments: %d\n", c.ConstructorArgs)
+		}
+	}
+	ideEvents.Result("deployment", a)
+
+	if expectWasmFlag == "" {
+		return nil
+	}
+	code, err := os.ReadFile(expectWasmFlag)
+	if err != nil {
+		return fmt.Errorf("failed to read WASM file: %w", err)
+	}
+	mismatches := a.VerifyWasm(code)
+	if len(mismatches) == 0 {
+		fmt.Printf("%s Deployed code matches %s (%s)\n", visualizer.Success(), expectWasmFlag, deploy.WasmHash(code))
+		return nil
+	}
+	for _, m := range mismatches {
+		fmt.Printf("%s %s\n", visualizer.Warning(), m)
+		ideEvents.Finding(map[string]interface{}{"kind": "wasm_mismatch", "op_index": m.OpIndex, "expected": m.Expected, "actual": m.Actual})
+	}
+	return nil
+}
+
+// printFootprintTTL shows the entries extended or restored by
+// ExtendFootprintTTL and RestoreFootprint operations
+func printFootprintTTL(resp *rpc.TransactionResponse) {
+	reports, err := analytics.AnalyzeFootprintOps(resp.EnvelopeXdr, resp.ResultMetaXdr, resp.Ledger, minTTLFlag)
+	if err != nil {
+		logger.Logger.Warn("Failed to analyze footprint operations", "error", err)
+		return
+	}
+	for _, r := range reports {
+		if r.Kind == analytics.KindExtend {
+			fmt.Printf("\n%s\n", visualizer.Heading(fmt.Sprintf("Footprint TTL: Operation #%d extends to %d ledgers", r.OpIndex, r.ExtendTo)))
+		} else {
+			fmt.Printf("\n%s\n", visualizer.Heading(fmt.Sprintf("Footprint TTL: Operation #%d restores entries", r.OpIndex)))
+		}
+		for _, c := range r.Changed {
+			line := fmt.Sprintf("  %s: live until %d -> %d", c.Key, c.OldLiveUntil, c.NewLiveUntil)
+			if c.Remaining > 0 {
+				line += fmt.Sprintf(" (%d ledgers left)", c.Remaining)
+			}
+			fmt.Println(line)
+		}
+		for _, key := range r.Unchanged {
+			fmt.Printf("  %s: unchanged\n", key)
+		}
+		if r.RentFee >= 0 {
+			fmt.Printf("  Rent fee charged: %d stroops\n", r.RentFee)
+		}
+		for _, w := range r.Warnings {
+			fmt.Printf("  %s %s\n", visualizer.Warning(), w)
+		}
+		ideEvents.Result("footprint_ttl", r)
+	}
+}
+
+// writeExecutionTrace saves the replay as an execution trace to
+// --trace-output, or <tx-hash>.trace.json, and returns the path written.
+// An existing default file is kept and a timestamped name used instead; an
+// existing --trace-output file is only replaced with --force.
+func writeExecutionTrace(txHash string, resp *rpc.TransactionResponse, sim *simulator.SimulationResponse, entries map[string]string) (string, error) {
+	t := buildExecutionTrace(txHash, resp, sim, entries)
+	path := traceOutputFile
+	if path == "" {
+		path = artifact.UniquePath(txHash+".trace.json", forceFlag)
+	} else if !forceFlag {
+		if _, err := os.Stat(path); err == nil {
+			return "", fmt.Errorf("trace output %s already exists; use --force to overwrite it", path)
 		}
 	}
-	fmt.Printf("Events: %d, Logs: %d\n", len(res.Events), len(res.Logs))
+	data, err := t.ToJSON()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode trace: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write trace: %w", err)
+	}
+	fmt.Printf("\nExecution trace written to %s (%d steps, %d storage accesses)\n", path, len(t.States), len(t.StorageAccesses))
+	return path, nil
+}
+
+// appendProduced records a file written outside --output-dir so the session
+// checkpoint lists it
+func appendProduced(produced []artifact.Artifact, path, kind, description string) []artifact.Artifact {
+	a, err := artifact.Describe(path, kind, description)
+	if err != nil {
+		logger.Logger.Warn("Failed to record artifact", "path", path, "error", err)
+		return produced
+	}
+	return append(produced, a)
+}
+
+// buildExecutionTrace records the replay with one state per diagnostic
+// event and its storage accesses: those the simulator observed when it
+// reports them, otherwise those derived from the footprint and result meta
+func buildExecutionTrace(txHash string, resp *rpc.TransactionResponse, sim *simulator.SimulationResponse, entries map[string]string) *trace.ExecutionTrace {
+	t := trace.NewExecutionTrace(txHash, 0)
+	for _, ev := range sim.DiagnosticEvents {
+		state := trace.ExecutionState{Operation: ev.EventType}
+		if ev.ContractID != nil {
+			state.ContractID = *ev.ContractID
+		}
+		if len(ev.Topics) > 0 {
+			state.Function = ev.Topics[0]
+		}
+		t.AddState(state)
+	}
+	if sim.Error != "" {
+		t.AddState(trace.ExecutionState{Operation: "error", Error: sim.Error})
+	}
+
+	var (
+		accesses []trace.StorageAccess
+		err      error
+	)
+	// A simulator that reports its footprint records storage accesses too,
+	// so an empty list means the contract touched no contract data
+	if sim.Footprint != nil {
+		accesses, err = trace.StorageFromSimulation(sim.StorageAccesses)
+	} else {
+		accesses, err = trace.StorageFromMeta(resp.EnvelopeXdr, resp.ResultMetaXdr, entries)
+	}
+	if err != nil {
+		logger.Logger.Warn("Failed to record storage accesses", "error", err)
+	}
+	t.RecordStorage(accesses...)
+	t.EndTime = time.Now()
+	return t
+}
+
+// printFootprintCheck flags entries missing from or needlessly declared in
+// the Soroban footprint
+func printFootprintCheck(resp *rpc.TransactionResponse, sim *simulator.SimulationResponse) {
+	var accessed *simulator.Footprint
+	if sim != nil {
+		accessed = sim.Footprint
+	}
+	check, err := analytics.CheckFootprint(resp.EnvelopeXdr, resp.ResultMetaXdr, accessed)
+	if err != nil {
+		logger.Logger.Warn("Failed to check footprint", "error", err)
+		return
+	}
+	if check == nil {
+		return
+	}
+
+	fmt.Printf("\n%s\n", visualizer.Heading("Footprint Check"))
+	fmt.Printf("Declared: %d read-only, %d read-write; accessed: %d; written: %d\n", check.ReadOnly, check.ReadWrite, check.Accessed, check.Written)
+	if !check.Mismatch() {
+		fmt.Printf("%s Footprint matches the entries accessed\n", visualizer.Success())
+	}
+	if len(check.UnderDeclared) > 0 {
+		fmt.Printf("%s Under-declared (causes failures):\n", visualizer.Error())
+		for _, issue := range check.UnderDeclared {
+			printFootprintIssue(issue)
+		}
+	}
+	if len(check.OverDeclared) > 0 {
+		fmt.Printf("%s Over-declared (wastes fees):\n", visualizer.Warning())
+		for _, issue := range check.OverDeclared {
+			printFootprintIssue(issue)
+		}
+	}
+	for _, n := range check.Notes {
+		fmt.Printf("  Note: %s\n", n)
+	}
+	ideEvents.Result("footprint_check", check)
 }
 
-func diffResults(res1, res2 *simulator.SimulationResponse, net1, net2 string) {
-	fmt.Printf("\n=== Comparison: %s vs %s ===\n", net1, net2)
+func printFootprintIssue(issue analytics.FootprintIssue) {
+	fmt.Printf("  - %s: %s\n", issue.Key, issue.Detail)
+	if verbose {
+		fmt.Printf("    key: %s\n", issue.KeyXdr)
+	}
+}
+
+// printFeeBreakdown decomposes the fee of a Soroban transaction into its
+// inclusion, resource and rent parts and compares the declared resources
+// with what the simulated replay needed
+func printFeeBreakdown(ctx context.Context, client *rpc.Client, resp *rpc.TransactionResponse, sim *simulator.SimulationResponse) {
+	cfg := analytics.DefaultFeeConfig
+	if feeConfigFlag != "" {
+		loaded, err := analytics.LoadFeeConfig(feeConfigFlag)
+		if err != nil {
+			logger.Logger.Warn("Failed to load fee config, using defaults", "error", err)
+		} else {
+			cfg = loaded
+		}
+	} else if keys, err := analytics.FeeConfigKeys(); err == nil {
+		if entries, err := client.GetLedgerEntries(ctx, keys); err == nil {
+			cfg = analytics.FeeConfigFromLedgerEntries(entries)
+		} else {
+			logger.Logger.Debug("Failed to fetch network fee config, using defaults", "error", err)
+		}
+	}
+
+	usage := simulatedUsage(sim)
+
+	b, err := analytics.BreakdownFees(resp.EnvelopeXdr, resp.ResultXdr, resp.ResultMetaXdr, cfg, usage)
+	if err != nil {
+		logger.Logger.Warn("Failed to break down fees", "error", err)
+		return
+	}
+	if b == nil {
+		return
+	}
+
+	fmt.Printf("\n%s\n", visualizer.Heading("Fee Breakdown"))
+	fmt.Printf("Max fee bid:     %d stroops (inclusion %d + resource %d)\n", b.MaxFee, b.InclusionFeeBid, b.DeclaredResourceFee)
+	if b.Charged >= 0 {
+		fmt.Printf("Fee charged:     %d stroops\n", b.Charged)
+	}
+	if b.NonRefundableCharged >= 0 {
+		fmt.Printf("  Inclusion:     %d\n", b.InclusionFeeCharged)
+		fmt.Printf("  Non-refundable resources: %d\n", b.NonRefundableCharged)
+		fmt.Printf("  Refundable:    %d (rent %d, events and other %d)\n", b.RefundableCharged, b.RentCharged, b.RefundableCharged-b.RentCharged)
+	}
+
+	fmt.Printf("Estimated non-refundable components from declared resources:\n")
+	for _, c := range b.Components {
+		if c.Refundable {
+			continue
+		}
+		line := fmt.Sprintf("  %-22s %8d", c.Name, c.Declared)
+		if c.Required >= 0 {
+			line += fmt.Sprintf("  (simulation needs %d", c.Required)
+			if c.Declared > c.Required {
+				line += fmt.Sprintf(", %d over-declared", c.Declared-c.Required)
+			} else if c.Declared < c.Required {
+				line += fmt.Sprintf(", %d under-declared", c.Required-c.Declared)
+			}
+			line += ")"
+		}
+		fmt.Println(line)
+	}
+	fmt.Printf("  %-22s %8d\n", "total", b.EstimatedNonRefundable())
+	fmt.Printf("Refundable components:\n")
+	for _, c := range b.Components {
+		if !c.Refundable {
+			continue
+		}
+		switch {
+		case c.Required < 0:
+			fmt.Printf("  %-22s %8s\n", c.Name, "unknown")
+		case c.Name == "rent":
+			fmt.Printf("  %-22s %8d  (charged on chain)\n", c.Name, c.Required)
+		default:
+			fmt.Printf("  %-22s %8d  (simulation needs)\n", c.Name, c.Required)
+		}
+	}
+	ideEvents.Result("fee_breakdown", b)
+}
+
+// simulatedUsage collects the resource usage of a replay from its budget
+// and the host's core metrics, summed over every metering phase
+func simulatedUsage(sim *simulator.SimulationResponse) analytics.SimulatedUsage {
+	var usage analytics.SimulatedUsage
+	if sim == nil {
+		return usage
+	}
+	if sim.BudgetUsage != nil {
+		usage.Instructions = sim.BudgetUsage.CPUInstructions
+	}
+	for _, phase := range sim.Metering {
+		usage.ReadEntries += phase.Metrics["read_entry"]
+		usage.WriteEntries += phase.Metrics["write_entry"]
+		usage.ReadBytes += phase.Metrics["ledger_read_byte"]
+		usage.WriteBytes += phase.Metrics["ledger_write_byte"]
+		usage.EventBytes += phase.Metrics["emit_event_byte"]
+	}
+	return usage
+}
+
+// printFeeContext annotates failures consistent with fee competition at the
+// transaction's ledger
+func printFeeContext(ctx context.Context, client *rpc.Client, resp *rpc.TransactionResponse) {
+	if resp.Ledger == 0 || resp.ResultXdr == "" {
+		return
+	}
+	header, err := client.GetLedgerHeader(ctx, resp.Ledger)
+	if err != nil {
+		logger.Logger.Debug("Failed to fetch ledger for fee context", "ledger", resp.Ledger, "error", err)
+		return
+	}
+
+	fc, err := analytics.FeeCompetition(resp.EnvelopeXdr, resp.ResultXdr, ledgerLoad(header))
+	if err != nil {
+		logger.Logger.Warn("Failed to analyze fee context", "error", err)
+		return
+	}
+	if fc == nil {
+		return
+	}
+
+	fmt.Printf("\n%s\n", visualizer.Heading("Fee Context"))
+	fmt.Printf("%s failed with %s at ledger %d (%.0f%% capacity, base fee %d stroops)\n",
+		visualizer.Warning(), fc.ResultCode, fc.Ledger.Sequence, fc.Ledger.Usage()*100, fc.Ledger.BaseFee)
+	fmt.Println(fc.Note)
+	fmt.Println("Run 'erst fees history' to see current fee levels.")
+	ideEvents.Result("fee_context", fc)
+}
+
+// sequenceHistoryLimit is how many of the source account's transactions
+// are searched for the cause of a tx_bad_seq failure
+const sequenceHistoryLimit = 100
+
+// printSequenceContext explains a tx_bad_seq failure with the transactions
+// of the source account that consumed or skipped its sequence number
+func printSequenceContext(ctx context.Context, client *rpc.Client, txHash string, resp *rpc.TransactionResponse) {
+	if resp.ResultXdr == "" {
+		return
+	}
+	// Decode first so that only tx_bad_seq failures query the history
+	sc, err := analytics.SequenceConflict(resp.EnvelopeXdr, resp.ResultXdr, txHash, resp.Ledger, nil)
+	if err != nil || sc == nil {
+		return
+	}
+	txs, err := client.GetAccountHistory(ctx, sc.Source, resp.Ledger, sequenceHistoryLimit)
+	if err != nil {
+		logger.Logger.Debug("Failed to fetch account history for sequence context", "account", sc.Source, "error", err)
+		return
+	}
+	sc, err = analytics.SequenceConflict(resp.EnvelopeXdr, resp.ResultXdr, txHash, resp.Ledger, analytics.AccountHistory(sc.Source, txs))
+	if err != nil {
+		logger.Logger.Warn("Failed to analyze sequence context", "error", err)
+		return
+	}
+
+	fmt.Printf("\n%s\n", visualizer.Heading("Sequence Context"))
+	writeSequenceContext(os.Stdout, sc)
+	ideEvents.Result("sequence_context", sc)
+}
+
+func writeSequenceContext(w io.Writer, sc *analytics.SequenceContext) {
+	fmt.Fprintf(w, "%s failed with tx_bad_seq using sequence number %d of %s\n", visualizer.Warning(), sc.Sequence, sc.Source)
+	if txs := append(append([]analytics.AccountTx{}, sc.Competing...), sc.Bumps...); len(txs) > 0 {
+		table := visualizer.NewTable("Transaction", "Source", "Sequence", "Bump To", "Ledger", "Time")
+		for _, tx := range txs {
+			bump := "-"
+			if tx.BumpTo != 0 {
+				bump = strconv.FormatInt(tx.BumpTo, 10)
+			}
+			table.AddRow(tx.Hash, tx.Source, strconv.FormatInt(tx.Sequence, 10), bump,
+				strconv.FormatUint(uint64(tx.Ledger), 10), tx.CreatedAt.UTC().Format(time.RFC3339))
+		}
+		table.Render(w)
+	}
+	fmt.Fprintln(w, sc.Note)
+}
+
+// newPriceFunc builds a token price lookup from --price-source,
+// ERST_PRICE_SOURCE or the general config file. It returns nil when no
+// source is configured.
+func newPriceFunc(ctx context.Context) (tokenflow.PriceFunc, error) {
+	source := priceSourceFlag
+	if source == "" {
+		source = os.Getenv("ERST_PRICE_SOURCE")
+	}
+	if source == "" {
+		if cfg, err := config.LoadConfig(); err == nil {
+			source = cfg.PriceSource
+		}
+	}
+	if source == "" {
+		return nil, nil
+	}
+
+	provider, err := price.NewProvider(source)
+	if err != nil {
+		return nil, err
+	}
+	return func(t tokenflow.Token) (float64, bool) {
+		assets := []string{t.ID, t.Symbol}
+		if t.Symbol == "SAC" {
+			assets = assets[:1]
+		}
+		for _, asset := range assets {
+			if asset == "" {
+				continue
+			}
+			v, ok, err := provider.USDPrice(ctx, asset)
+			if err != nil {
+				logger.Logger.Warn("Failed to fetch token price", "asset", asset, "error", err)
+				continue
+			}
+			if ok {
+				return v, true
+			}
+		}
+		return 0, false
+	}, nil
+}
+
+func diffResults(res1, res2 *simulator.SimulationResponse, net1, net2 string, logFilter *compare.LogFilter) {
+	fmt.Printf("\n%s\n", visualizer.Heading(localization.Format("compare.header", localization.Args{"left": net1, "right": net2})))
 
 	if res1.Status != res2.Status {
-		fmt.Printf("Status Mismatch: %s (%s) vs %s (%s)\n", res1.Status, net1, res2.Status, net2)
+		fmt.Println(localization.Format("compare.status_mismatch", localization.Args{
+			"leftStatus": res1.Status, "left": net1, "rightStatus": res2.Status, "right": net2,
+		}))
 	} else {
-		fmt.Printf("Status Match: %s\n", res1.Status)
+		fmt.Println(localization.Format("compare.status_match", localization.Args{"status": res1.Status}))
 	}
 
 	// Compare diagnostic events if available
 	if len(res1.DiagnosticEvents) > 0 && len(res2.DiagnosticEvents) > 0 {
 		if len(res1.DiagnosticEvents) != len(res2.DiagnosticEvents) {
-			fmt.Printf("[DIFF] Diagnostic events count mismatch: %d vs %d\n",
-				len(res1.DiagnosticEvents), len(res2.DiagnosticEvents))
+			fmt.Println(localization.Format("compare.diagnostic_count", localization.Args{
+				"left": len(res1.DiagnosticEvents), "right": len(res2.DiagnosticEvents),
+			}))
 		}
 	} else if len(res1.Events) != len(res2.Events) {
-		fmt.Printf("[DIFF] Events count mismatch: %d vs %d\n", len(res1.Events), len(res2.Events))
+		fmt.Println(localization.Format("compare.events_count", localization.Args{"left": len(res1.Events), "right": len(res2.Events)}))
 	}
 
 	// Compare budget usage if available
 	if res1.BudgetUsage != nil && res2.BudgetUsage != nil {
 		if res1.BudgetUsage.CPUInstructions != res2.BudgetUsage.CPUInstructions {
-			fmt.Printf("[DIFF] CPU instructions: %d vs %d\n",
-				res1.BudgetUsage.CPUInstructions, res2.BudgetUsage.CPUInstructions)
+			fmt.Println(localization.Format("compare.cpu", localization.Args{
+				"left": res1.BudgetUsage.CPUInstructions, "right": res2.BudgetUsage.CPUInstructions,
+			}))
 		}
 		if res1.BudgetUsage.MemoryBytes != res2.BudgetUsage.MemoryBytes {
-			fmt.Printf("[DIFF] Memory bytes: %d vs %d\n",
-				res1.BudgetUsage.MemoryBytes, res2.BudgetUsage.MemoryBytes)
+			fmt.Println(localization.Format("compare.memory", localization.Args{
+				"left": res1.BudgetUsage.MemoryBytes, "right": res2.BudgetUsage.MemoryBytes,
+			}))
 		}
 	}
 
-	// Compare Events
-	fmt.Println("\nEvent Diff:")
-	maxEvents := len(res1.Events)
-	if len(res2.Events) > maxEvents {
-		maxEvents = len(res2.Events)
-	}
-
-	for i := 0; i < maxEvents; i++ {
-		var ev1, ev2 string
-		if i < len(res1.Events) {
-			ev1 = res1.Events[i]
-		} else {
-			ev1 = "<missing>"
+	// Compare Logs
+	fmt.Printf("\n%s\n", localization.Format("compare.log_diff", nil))
+	if logDiffs := compare.Logs(res1.LogStrings(), res2.LogStrings(), logFilter); len(logDiffs) == 0 {
+		fmt.Println(localization.Format("compare.no_differences", nil))
+	} else {
+		for _, d := range logDiffs {
+			net := net1
+			mark := "-"
+			if d.Kind == compare.Added {
+				net, mark = net2, "+"
+			}
+			fmt.Printf("  %s (%s) %s\n", mark, net, d.Line)
 		}
+	}
 
-		if i < len(res2.Events) {
-			ev2 = res2.Events[i]
-		} else {
-			ev2 = "<missing>"
-		}
+	// Compare Events
+	diffs := compare.Events(res1.Events, res2.Events)
+	sum := compare.Summarize(diffs)
+	fmt.Printf("\n%s\n", localization.Format("compare.event_diff", nil))
+	if !sum.Changed() {
+		fmt.Println(localization.Format("compare.events_match", localization.Args{"count": sum.Unchanged}))
+		return
+	}
 
-		if ev1 != ev2 {
-			fmt.Printf("  [%d] MISMATCH:\n", i)
-			fmt.Printf("    %s: %s\n", net1, ev1)
-			fmt.Printf("    %s: %s\n", net2, ev2)
+	for _, d := range diffs {
+		switch d.Kind {
+		case compare.Removed:
+			fmt.Println(localization.Format("compare.only_on", localization.Args{
+				"mark": "-", "index": strconv.Itoa(d.Left.Index), "network": net1, "event": d.Left.String(),
+			}))
+		case compare.Added:
+			fmt.Println(localization.Format("compare.only_on", localization.Args{
+				"mark": "+", "index": strconv.Itoa(d.Right.Index), "network": net2, "event": d.Right.String(),
+			}))
+		case compare.Modified:
+			fmt.Println(localization.Format("compare.modified", localization.Args{
+				"left": strconv.Itoa(d.Left.Index), "right": strconv.Itoa(d.Right.Index),
+			}))
+			for _, f := range d.Fields {
+				fmt.Printf("      %s: %s -> %s\n", f.Field, f.Old, f.New)
+			}
 		}
 	}
+	fmt.Println(localization.Format("compare.summary", localization.Args{
+		"unchanged": sum.Unchanged, "modified": sum.Modified,
+		"removed": sum.Removed, "left": net1, "added": sum.Added, "right": net2,
+	}))
 }
 
 func init() {
@@ -896,16 +1744,38 @@ func init() {
 	debugCmd.Flags().BoolVar(&tracingEnabled, "tracing", false, "Enable tracing")
 	debugCmd.Flags().StringVar(&otlpExporterURL, "otlp-url", "http://localhost:4318", "OTLP URL")
 	debugCmd.Flags().BoolVar(&generateTrace, "generate-trace", false, "Generate trace file")
-	debugCmd.Flags().StringVar(&traceOutputFile, "trace-output", "", "Trace output file")
-	debugCmd.Flags().StringVar(&snapshotFlag, "snapshot", "", "Load state from JSON snapshot file")
+	debugCmd.Flags().StringVar(&traceOutputFile, "trace-output", "", "Trace output file (default: <tx-hash>.trace.json)")
+	debugCmd.Flags().StringVar(&outputDirFlag, "output-dir", "", "Write every artifact of the run to <dir>/<tx-hash> with an index.json manifest")
+	debugCmd.Flags().BoolVar(&forceFlag, "force", false, "Overwrite artifacts of an earlier run instead of writing timestamped copies")
+	debugCmd.Flags().StringArrayVar(&snapshotFlag, "snapshot", nil, "Load state from a snapshot: a local path or an https://, s3:// or gs:// URL (repeatable; later snapshots override earlier ones)")
+	debugCmd.Flags().BoolVar(&fetchMissingFlag, "fetch-missing", false, "Fetch ledger entries that no --snapshot provides from the network")
 	debugCmd.Flags().StringVar(&compareNetworkFlag, "compare-network", "", "Network to compare against (testnet, mainnet, futurenet)")
+	debugCmd.Flags().StringArrayVar(&logIgnoreFlag, "log-ignore", nil, "Regex for log lines to ignore in the compare log diff (repeatable)")
+	debugCmd.Flags().BoolVar(&logStripAddrFlag, "log-strip-addresses", false, "Mask account, contract and hash values in the compare log diff")
+	debugCmd.Flags().BoolVar(&noLogFilterFlag, "no-log-filter", false, "Diff logs without the default ignore patterns (--log-ignore, log_diff_ignore and --log-strip-addresses still apply)")
 	debugCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
 	debugCmd.Flags().StringVar(&wasmPath, "wasm", "", "Path to local WASM file for local replay (no network required)")
 	debugCmd.Flags().StringSliceVar(&args, "args", []string{}, "Mock arguments for local replay (JSON array of strings)")
-	debugCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Disable local ledger state caching")
+	debugCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Disable local ledger state and simulation result caching")
 	debugCmd.Flags().BoolVar(&demoMode, "demo", false, "Print sample output (no network) - for testing color detection")
 	debugCmd.Flags().BoolVar(&watchFlag, "watch", false, "Poll for transaction on-chain before debugging")
 	debugCmd.Flags().IntVar(&watchTimeoutFlag, "watch-timeout", 30, "Timeout in seconds for watch mode")
-
+	debugCmd.Flags().BoolVar(&explainFlag, "explain", false, "Explain the failure in plain English")
+	debugCmd.Flags().StringVar(&sourceMapFlag, "source-map", "", "Contract WASM with debug symbols or JSON source map for source-level stack traces")
+	debugCmd.Flags().StringVar(&goldenFlag, "golden", "", "Write a canonical report to this golden file")
+	debugCmd.Flags().StringVar(&verifyGoldenFlag, "verify-golden", "", "Fail if the canonical report differs from this golden file")
+	debugCmd.Flags().StringVar(&feeConfigFlag, "fee-config", "", "JSON file with Soroban fee rates (default: fetched from the network)")
+	debugCmd.Flags().Uint32Var(&minTTLFlag, "min-ttl", 0, "Ledgers extended or restored entries should stay live (default: the requested extension)")
+	debugCmd.Flags().StringVar(&expectWasmFlag, "expect-wasm", "", "Local WASM build that uploaded or deployed code must match")
+	debugCmd.Flags().StringVar(&priceSourceFlag, "price-source", "", "CSV file or HTTP endpoint with USD prices for valuing token flows")
+	debugCmd.Flags().BoolVar(&stepFlag, "step", false, "Pause at each contract call boundary in an interactive step debugger")
+	debugCmd.Flags().StringVar(&checkpointFlag, "checkpoint", session.DefaultRunName, "Name this simulation run as a session checkpoint")
+	debugCmd.Flags().BoolVar(&noHooksFlag, "no-hooks", false, "Do not run the pre-debug, post-debug and post-compare hooks of the config file")
+	debugCmd.Flags().BoolVar(&horizonXrefFlag, "horizon", false, "Show Horizon's operations and effects and flag where they disagree with erst's analysis")
+	debugCmd.Flags().StringVar(&sessionTargetFlag, "session", "", "Record the run as a checkpoint in this saved session, creating it if needed")
+	addSessionContextFlags(debugCmd)
+
+	supportPorcelain(debugCmd)
+	supportIDEJSON(debugCmd)
 	rootCmd.AddCommand(debugCmd)
 }
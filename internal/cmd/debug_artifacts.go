@@ -0,0 +1,141 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dotandev/hintents/internal/artifact"
+	"github.com/dotandev/hintents/internal/objstore"
+	"github.com/dotandev/hintents/internal/report"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/dotandev/hintents/internal/tokenflow"
+)
+
+// writeDebugArtifacts writes the report, trace, flamegraph, ledger snapshot
+// and token flows of a debug run to <root>/<tx-hash> and indexes them. See
+// artifact.Create for what happens when an earlier run left that directory.
+func writeDebugArtifacts(root, txHash string, resp *rpc.TransactionResponse, sim *simulator.SimulationResponse, ledger map[string]string, findings []security.Finding, flows *tokenflow.Report) (*artifact.Dir, error) {
+	dir, err := artifact.Create(root, txHash, forceFlag)
+	if err != nil {
+		return nil, err
+	}
+	dir.Manifest.Network = networkFlag
+	dir.Manifest.Command = "erst debug"
+	dir.Manifest.ErstVersion = Version
+
+	t := buildExecutionTrace(txHash, resp, sim, ledger)
+	traceJSON, err := t.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode trace: %w", err)
+	}
+	if _, err := dir.Write("trace.json", artifact.KindTrace, "Execution trace for 'erst trace' and 'erst report'", traceJSON); err != nil {
+		return nil, err
+	}
+
+	builder := buildTraceReport(t)
+	for _, f := range findings {
+		builder.AddIssue(string(f.Type), string(f.Severity), f.Title+": "+f.Description, "", "")
+	}
+	rep := builder.Build()
+	if sim.Status != "" {
+		rep.Summary.Status = sim.Status
+	}
+	reportJSON, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if _, err := dir.Write("report.json", artifact.KindReport, "Run summary, steps and security findings", reportJSON); err != nil {
+		return nil, err
+	}
+	reportHTML, err := report.NewHTMLRenderer().Render(rep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	if _, err := dir.Write("report.html", artifact.KindReport, "HTML version of report.json", reportHTML); err != nil {
+		return nil, err
+	}
+
+	if sim.Flamegraph != "" {
+		if _, err := dir.Write("flamegraph.svg", artifact.KindFlamegraph, "CPU and memory flamegraph (--profile)", []byte(sim.Flamegraph)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(ledger) > 0 {
+		if err := snapshot.Save(dir.File("snapshot.json"), snapshot.FromMap(ledger)); err != nil {
+			return nil, err
+		}
+		if err := dir.Add("snapshot.json", artifact.KindSnapshot, "Ledger state used for the replay, for --snapshot"); err != nil {
+			return nil, err
+		}
+	}
+
+	if flows != nil && len(flows.Raw) > 0 {
+		if _, err := dir.WriteFunc("tokenflow.csv", artifact.KindTokenFlow, "Token movements, one row each", func(w io.Writer) error {
+			return flows.WriteCSV(w)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := dir.Close(); err != nil {
+		return nil, err
+	}
+	return dir, nil
+}
+
+// archiveDebugArtifacts uploads an artifact directory when an archive is
+// configured. Archival failures are reported but do not fail the run.
+func archiveDebugArtifacts(ctx context.Context, dir *artifact.Dir) {
+	store, err := archiveStore("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: artifacts not archived: %v\n", err)
+		return
+	}
+	if store != nil {
+		uploadDebugArtifacts(ctx, store, dir)
+	}
+}
+
+// archiveDebugRun archives the artifacts of a run without --output-dir by
+// writing them to a temporary directory first
+func archiveDebugRun(ctx context.Context, txHash string, resp *rpc.TransactionResponse, sim *simulator.SimulationResponse, ledger map[string]string, findings []security.Finding, flows *tokenflow.Report) {
+	store, err := archiveStore("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: artifacts not archived: %v\n", err)
+		return
+	}
+	if store == nil {
+		return
+	}
+	root, err := os.MkdirTemp("", "erst-archive-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: artifacts not archived: %v\n", err)
+		return
+	}
+	defer os.RemoveAll(root)
+	dir, err := writeDebugArtifacts(root, txHash, resp, sim, ledger, findings, flows)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: artifacts not archived: %v\n", err)
+		return
+	}
+	uploadDebugArtifacts(ctx, store, dir)
+}
+
+func uploadDebugArtifacts(ctx context.Context, store objstore.Store, dir *artifact.Dir) {
+	location, err := archiveArtifacts(ctx, store, dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: artifacts not archived: %v\n", err)
+		return
+	}
+	fmt.Printf("Artifacts archived to %s\n", location)
+}
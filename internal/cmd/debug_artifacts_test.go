@@ -0,0 +1,55 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/artifact"
+	"github.com/dotandev/hintents/internal/report"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/tokenflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDebugArtifacts(t *testing.T) {
+	contract := "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	sim := &simulator.SimulationResponse{
+		Status:     "error",
+		Error:      "HostError: trap",
+		Flamegraph: "<svg></svg>",
+		DiagnosticEvents: []simulator.DiagnosticEvent{
+			{EventType: "contract", ContractID: &contract, Topics: []string{"transfer"}},
+		},
+	}
+	findings := []security.Finding{{Type: security.FindingVerifiedRisk, Severity: security.SeverityHigh, Title: "Overflow", Description: "amount wrapped"}}
+	flows := &tokenflow.Report{Raw: []tokenflow.Transfer{{From: "GA", To: "GB", Token: tokenflow.Token{Symbol: "XLM"}, Amount: big.NewInt(10_000_000), Kind: tokenflow.KindTransfer}}}
+
+	dir, err := writeDebugArtifacts(t.TempDir(), "abc123", &rpc.TransactionResponse{}, sim, map[string]string{"key": "entry"}, findings, flows)
+	require.NoError(t, err)
+
+	m, err := artifact.LoadManifest(dir.Path)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", m.TxHash)
+	var names []string
+	for _, a := range m.Artifacts {
+		names = append(names, a.Name)
+		assert.FileExists(t, dir.File(a.Name))
+	}
+	assert.Equal(t, []string{"flamegraph.svg", "report.html", "report.json", "snapshot.json", "tokenflow.csv", "trace.json"}, names)
+
+	data, err := os.ReadFile(dir.File("report.json"))
+	require.NoError(t, err)
+	var rep report.Report
+	require.NoError(t, json.Unmarshal(data, &rep))
+	assert.Equal(t, "error", rep.Summary.Status)
+	require.Len(t, rep.Analytics.RiskAssessment.Issues, 1)
+	assert.Equal(t, "Overflow: amount wrapped", rep.Analytics.RiskAssessment.Issues[0].Description)
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"golang.org/x/sync/errgroup"
+)
+
+// networkJob replays the transaction on one network. prepare fetches the
+// network's state and builds the request; render writes the human-readable
+// result.
+type networkJob struct {
+	network string
+	prepare func(ctx context.Context) (*simulator.SimulationRequest, error)
+	render  func(w io.Writer, resp *simulator.SimulationResponse)
+}
+
+// networkRun is the outcome of one networkJob
+type networkRun struct {
+	network string
+	// req is the request the network was replayed with
+	req  *simulator.SimulationRequest
+	resp *simulator.SimulationResponse
+	// out buffers the rendered result so concurrent networks never
+	// interleave their output
+	out bytes.Buffer
+}
+
+// runNetworks runs jobs concurrently and returns their results in the order
+// of jobs. The first failure cancels the remaining jobs and is returned,
+// prefixed with the failing network.
+func runNetworks(ctx context.Context, runner simulator.RunnerInterface, jobs []networkJob) ([]*networkRun, error) {
+	runs := make([]*networkRun, len(jobs))
+	g, gctx := errgroup.WithContext(ctx)
+	for i, job := range jobs {
+		run := &networkRun{network: job.network}
+		runs[i] = run
+		g.Go(func() error {
+			req, err := job.prepare(gctx)
+			if err != nil {
+				return fmt.Errorf("%s: %w", job.network, err)
+			}
+			if err := gctx.Err(); err != nil {
+				return fmt.Errorf("%s: %w", job.network, err)
+			}
+			run.req = req
+			if run.resp, err = runner.Run(req); err != nil {
+				return fmt.Errorf("%s: %w", job.network, err)
+			}
+			if job.render != nil {
+				job.render(&run.out, run.resp)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// compareJobs builds the jobs replaying resp on the primary network with
+// client, and on the --compare-network with its own client
+func compareJobs(client *rpc.Client, resp *rpc.TransactionResponse, txHash string, keys []string, ts int64, render func(w io.Writer, network string, res *simulator.SimulationResponse)) []networkJob {
+	primary := networkJob{
+		network: networkFlag,
+		prepare: func(ctx context.Context) (*simulator.SimulationRequest, error) {
+			entries, err := rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+			if err != nil {
+				if entries, err = client.GetLedgerEntries(ctx, keys); err != nil {
+					return nil, err
+				}
+			}
+			return &simulator.SimulationRequest{
+				EnvelopeXdr:   resp.EnvelopeXdr,
+				ResultMetaXdr: resp.ResultMetaXdr,
+				LedgerEntries: entries,
+				Timestamp:     ts,
+			}, nil
+		},
+	}
+
+	compare := networkJob{
+		network: compareNetworkFlag,
+		prepare: func(ctx context.Context) (*simulator.SimulationRequest, error) {
+			compareClient, err := rpc.NewClient(
+				rpc.WithNetwork(rpc.Network(compareNetworkFlag)),
+				rpc.WithToken(rpcTokenFlag),
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create compare client: %w", err)
+			}
+			if noCacheFlag {
+				compareClient.CacheEnabled = false
+			}
+
+			compareResp, err := compareClient.GetTransaction(ctx, txHash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+			}
+			entries, err := rpc.ExtractLedgerEntriesFromMeta(compareResp.ResultMetaXdr)
+			if err != nil {
+				if entries, err = compareClient.GetLedgerEntries(ctx, keys); err != nil {
+					return nil, err
+				}
+			}
+			return &simulator.SimulationRequest{
+				EnvelopeXdr:   resp.EnvelopeXdr,
+				ResultMetaXdr: compareResp.ResultMetaXdr,
+				LedgerEntries: entries,
+				Timestamp:     ts,
+			}, nil
+		},
+	}
+
+	jobs := []networkJob{primary, compare}
+	for i := range jobs {
+		network := jobs[i].network
+		jobs[i].render = func(w io.Writer, res *simulator.SimulationResponse) { render(w, network, res) }
+	}
+	return jobs
+}
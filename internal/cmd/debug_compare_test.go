@@ -0,0 +1,112 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests exercise concurrent replays; CI runs them with -race.
+
+func echoRunner() *simulator.MockRunner {
+	return simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		return &simulator.SimulationResponse{Status: "success", Error: req.EnvelopeXdr}, nil
+	})
+}
+
+func renderLines(w io.Writer, resp *simulator.SimulationResponse) {
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(w, "%s %d\n", resp.Error, i)
+	}
+}
+
+func TestRunNetworks_DeterministicOrder(t *testing.T) {
+	// The primary finishes last, yet stays first with unmixed output
+	compareDone := make(chan struct{})
+	jobs := []networkJob{
+		{
+			network: "mainnet",
+			prepare: func(ctx context.Context) (*simulator.SimulationRequest, error) {
+				<-compareDone
+				return &simulator.SimulationRequest{EnvelopeXdr: "mainnet"}, nil
+			},
+			render: renderLines,
+		},
+		{
+			network: "testnet",
+			prepare: func(ctx context.Context) (*simulator.SimulationRequest, error) {
+				defer close(compareDone)
+				return &simulator.SimulationRequest{EnvelopeXdr: "testnet"}, nil
+			},
+			render: renderLines,
+		},
+	}
+
+	runs, err := runNetworks(context.Background(), echoRunner(), jobs)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	for i, network := range []string{"mainnet", "testnet"} {
+		assert.Equal(t, network, runs[i].network)
+		assert.Equal(t, network, runs[i].resp.Error)
+		assert.Equal(t, network, runs[i].req.EnvelopeXdr, "the request is kept for the session and artifacts")
+		var want string
+		for j := 0; j < 50; j++ {
+			want += fmt.Sprintf("%s %d\n", network, j)
+		}
+		assert.Equal(t, want, runs[i].out.String())
+	}
+}
+
+func TestRunNetworks_FailureCancelsOthers(t *testing.T) {
+	canceled := make(chan error, 1)
+	jobs := []networkJob{
+		{
+			network: "mainnet",
+			prepare: func(ctx context.Context) (*simulator.SimulationRequest, error) {
+				<-ctx.Done()
+				canceled <- ctx.Err()
+				return nil, ctx.Err()
+			},
+		},
+		{
+			network: "testnet",
+			prepare: func(ctx context.Context) (*simulator.SimulationRequest, error) {
+				return nil, errors.New("transaction not found")
+			},
+		},
+	}
+
+	runs, err := runNetworks(context.Background(), echoRunner(), jobs)
+	assert.Nil(t, runs)
+	assert.EqualError(t, err, "testnet: transaction not found")
+	assert.ErrorIs(t, <-canceled, context.Canceled)
+}
+
+func TestRunNetworks_RunnerError(t *testing.T) {
+	runner := simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		if req.EnvelopeXdr == "testnet" {
+			return nil, errors.New("simulator crashed")
+		}
+		return &simulator.SimulationResponse{Status: "success"}, nil
+	})
+	jobs := []networkJob{
+		{network: "mainnet", prepare: func(context.Context) (*simulator.SimulationRequest, error) {
+			return &simulator.SimulationRequest{EnvelopeXdr: "mainnet"}, nil
+		}},
+		{network: "testnet", prepare: func(context.Context) (*simulator.SimulationRequest, error) {
+			return &simulator.SimulationRequest{EnvelopeXdr: "testnet"}, nil
+		}},
+	}
+
+	_, err := runNetworks(context.Background(), runner, jobs)
+	assert.EqualError(t, err, "testnet: simulator crashed")
+}
@@ -4,12 +4,15 @@
 package cmd
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/stellar/go-stellar-sdk/xdr"
 	"github.com/stretchr/testify/assert"
@@ -194,7 +197,7 @@ func TestMockRunner_ImplementsInterface(t *testing.T) {
 	}
 	expectedResp := &simulator.SimulationResponse{
 		Status: "success",
-		Events: []string{"test-event"},
+		Events: simulator.NewEvents("test-event"),
 	}
 
 	mockRunner.On("Run", req).Return(expectedResp, nil)
@@ -290,3 +293,62 @@ func TestExtractLedgerKeys(t *testing.T) {
 	}
 	assert.True(t, found, "Key not found in extracted keys")
 }
+
+func TestCheckAvailability(t *testing.T) {
+	full := checkAvailability(&rpc.TransactionResponse{EnvelopeXdr: "E", ResultXdr: "R", ResultMetaXdr: "M"})
+	assert.False(t, full.Partial())
+
+	partial := checkAvailability(&rpc.TransactionResponse{EnvelopeXdr: "E", ResultXdr: "R"})
+	assert.True(t, partial.Partial())
+	assert.True(t, partial.HasResult)
+	assert.False(t, partial.HasMeta)
+	assert.Equal(t, []string{"result meta XDR"}, partial.Missing)
+
+	partial.skip("Token flows", "result meta")
+	assert.Equal(t, []string{"Token flows (needs result meta)"}, partial.Skipped)
+}
+
+func TestWriteMetering(t *testing.T) {
+	var buf bytes.Buffer
+	writeMetering(&buf, nil)
+	assert.Empty(t, buf.String())
+
+	writeMetering(&buf, []simulator.MeterPhase{{
+		Phase:    "transfer",
+		CPUInsns: 1234567,
+		MemBytes: 89012,
+		Metrics:  map[string]uint64{"write_entry": 1, "read_entry": 3},
+	}})
+	assert.Contains(t, buf.String(), "      read_entry=3 write_entry=1\n")
+}
+
+func TestWriteSequenceContext(t *testing.T) {
+	var buf bytes.Buffer
+	writeSequenceContext(&buf, &analytics.SequenceContext{
+		Source:    "GABC",
+		Sequence:  11,
+		Competing: []analytics.AccountTx{{Hash: "rival", Source: "GABC", Sequence: 11, Ledger: 100, Consumed: true}},
+		Note:      "Sequence number 11 was already used by transaction rival",
+	})
+	out := buf.String()
+	assert.Contains(t, out, "tx_bad_seq using sequence number 11 of GABC")
+	assert.Contains(t, out, "rival")
+	assert.Contains(t, out, "Sequence number 11 was already used")
+}
+
+func TestNewLogFilterNoDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("ERST_HOME", home)
+	assert.NoError(t, os.WriteFile(filepath.Join(home, "config.json"), []byte(`{"log_diff_ignore": ["^from config"]}`), 0600))
+	defer func() { noLogFilterFlag, logIgnoreFlag, logStripAddrFlag = false, nil, false }()
+	noLogFilterFlag, logIgnoreFlag, logStripAddrFlag = true, []string{"^from flag"}, true
+
+	f, err := newLogFilter()
+	assert.NoError(t, err)
+	assert.True(t, f.StripAddresses)
+	assert.Equal(t, []string{"Loaded 3 Ledger Entries"}, f.Apply([]string{
+		"Loaded 3 Ledger Entries",
+		"from config",
+		"from flag",
+	}), "only the default patterns are turned off")
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffXDRTypeFlag string
+	diffXDRJSONFlag bool
+)
+
+var diffXDRCmd = &cobra.Command{
+	Use:   "diffxdr <xdr-a> <xdr-b>",
+	Short: "Show the field-level differences between two XDR values",
+	Long: `Decode two base64 XDR values of the same type and list every field that
+differs, with paths such as
+v1.tx.operations[0].body.invokeHostFunctionOp.auth[1].credentials. Useful for
+comparing envelopes built by different SDKs for the same call.
+
+Pass each value as base64, or as @path to read it from a file. Transaction
+envelopes may also be JSON exported by Stellar Lab, stellar-cli or an SDK.
+
+Supported types: ` + strings.Join(decoder.XDRTypes(), ", "),
+	Example: `  erst diffxdr AAAAAgAAAAA... AAAAAgAAAAB...
+  erst diffxdr @js-sdk.xdr @rust-sdk.xdr
+  erst diffxdr --type soroban-transaction-data @a.xdr @b.xdr --json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		readArg := readXDRArg
+		if diffXDRTypeFlag == "transaction-envelope" {
+			readArg = readEnvelopeArg
+		}
+		a, err := readArg(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := readArg(args[1])
+		if err != nil {
+			return err
+		}
+		diffs, err := decoder.DiffXDRBase64(diffXDRTypeFlag, a, b)
+		if err != nil {
+			return err
+		}
+
+		if diffXDRJSONFlag {
+			if diffs == nil {
+				diffs = []decoder.FieldDiff{}
+			}
+			return writeJSON(cmd, diffs)
+		}
+
+		for _, d := range diffs {
+			visualizer.Record("diff", d.Path, d.Kind, d.Old, d.New)
+		}
+		if len(diffs) == 0 {
+			fmt.Printf("The two %s values are identical.\n", diffXDRTypeFlag)
+			return nil
+		}
+		fmt.Printf("%d field(s) differ:\n\n", len(diffs))
+		table := visualizer.NewTable("Path", "Change", "A", "B")
+		for _, d := range diffs {
+			table.AddRow(d.Path, d.Kind, d.Old, d.New)
+		}
+		table.Render(os.Stdout)
+		return nil
+	},
+}
+
+// readEnvelopeArg reads an envelope argument like readXDRArg, converting
+// transaction JSON from Stellar Lab, stellar-cli or an SDK to base64
+func readEnvelopeArg(arg string) (string, error) {
+	s, err := readXDRArg(arg)
+	if err != nil || !decoder.IsJSON(s) {
+		return s, err
+	}
+	return decoder.EnvelopeFromJSON([]byte(s))
+}
+
+// readXDRArg returns a base64 argument, or the contents of the file named
+// by an @path argument
+func readXDRArg(arg string) (string, error) {
+	path, ok := strings.CutPrefix(arg, "@")
+	if !ok {
+		return strings.TrimSpace(arg), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read XDR file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func init() {
+	diffXDRCmd.Flags().StringVarP(&diffXDRTypeFlag, "type", "t", "transaction-envelope", "XDR type of both values")
+	diffXDRCmd.Flags().BoolVar(&diffXDRJSONFlag, "json", false, "Output the differences as JSON")
+	supportPorcelain(diffXDRCmd)
+	rootCmd.AddCommand(diffXDRCmd)
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadXDRArg(t *testing.T) {
+	got, err := readXDRArg(" AAAAAQ== ")
+	require.NoError(t, err)
+	assert.Equal(t, "AAAAAQ==", got)
+
+	path := filepath.Join(t.TempDir(), "a.xdr")
+	require.NoError(t, os.WriteFile(path, []byte("AAAAAA==\n"), 0644))
+	got, err = readXDRArg("@" + path)
+	require.NoError(t, err)
+	assert.Equal(t, "AAAAAA==", got)
+
+	_, err = readXDRArg("@" + filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
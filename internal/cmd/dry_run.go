@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/dotandev/hintents/internal/decoder"
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/spf13/cobra"
@@ -32,7 +33,8 @@ var dryRunCmd = &cobra.Command{
 	Long: `Replay a local transaction envelope (not yet on chain) against current network state.
 
 This command:
-  1) Loads a base64-encoded TransactionEnvelope XDR from a local file
+  1) Loads a TransactionEnvelope from a local file, as base64 XDR or as JSON
+     exported by Stellar Lab or an SDK
   2) Fetches required ledger entries from the configured Soroban RPC
   3) Replays the transaction locally via the Rust simulator
   4) Prints an estimated required fee based on the observed resource usage
@@ -70,6 +72,11 @@ func runDryRun(cmd *cobra.Command, args []string) error {
 	if envXdrB64 == "" {
 		return fmt.Errorf("tx file is empty")
 	}
+	if decoder.IsJSON(envXdrB64) {
+		if envXdrB64, err = decoder.EnvelopeFromJSON(b); err != nil {
+			return err
+		}
+	}
 
 	// Validate envelope is parseable
 	envBytes, err := base64.StdEncoding.DecodeString(envXdrB64)
@@ -0,0 +1,115 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/envsize"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	envSizeNetworkFlag string
+	envSizeRPCURLFlag  string
+	envSizeLimitFlag   int
+	envSizeJSONFlag    bool
+)
+
+var envSizeCmd = &cobra.Command{
+	Use:   "envsize <tx-hash | envelope-xdr | @file>",
+	Short: "Break down the size of an envelope and suggest how to shrink it",
+	Long: `Break down the encoded size of a transaction envelope by component
+(signatures, authorization entries, arguments, contract code and footprint
+keys) and compare it with the network's transaction size limit.
+
+erst also suggests reductions where it can tell the bytes are not needed:
+
+  - footprint keys listed twice, or in readOnly as well as readWrite
+  - repeated signatures or authorization entries
+  - contract code carrying name, producer or debug sections
+  - single arguments over 4 KiB
+
+Pass a transaction hash to fetch the envelope from the network, or the
+envelope itself as base64 or @path. Files may also hold transaction JSON
+exported by Stellar Lab, stellar-cli or an SDK. The command exits with an error when the
+envelope is over the limit.`,
+	Example: `  erst envsize @envelope.xdr
+  erst envsize 5c0a1b...e9 --network testnet --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if envSizeLimitFlag <= 0 {
+			return fmt.Errorf("--limit must be positive")
+		}
+		envelopeXdr, err := envelopeArg(cmd, args[0], envSizeNetworkFlag, envSizeRPCURLFlag)
+		if err != nil {
+			return err
+		}
+		report, err := envsize.Analyze(envelopeXdr)
+		if err != nil {
+			return err
+		}
+		report.Limit = envSizeLimitFlag
+
+		if envSizeJSONFlag {
+			if err := writeJSON(cmd, report); err != nil {
+				return err
+			}
+		} else {
+			visualizer.Record("size", strconv.Itoa(report.Bytes), strconv.Itoa(report.Limit))
+			for _, c := range report.Components {
+				visualizer.Record("component", c.Name, strconv.Itoa(c.Bytes), strconv.Itoa(c.Count))
+			}
+			for _, s := range report.Suggestions {
+				visualizer.Record("suggestion", s.ID, s.Path, strconv.Itoa(s.Savings), s.Message)
+			}
+			printEnvSize(os.Stdout, report)
+		}
+
+		if report.OverLimit() {
+			return fmt.Errorf("envelope is %d bytes, over the %d byte limit", report.Bytes, report.Limit)
+		}
+		return nil
+	},
+}
+
+func printEnvSize(w io.Writer, report *envsize.Report) {
+	fmt.Fprintln(w, visualizer.Heading("Envelope Size"))
+	fmt.Fprintf(w, "Total: %d of %d bytes (%.1f%%)\n", report.Bytes, report.Limit, 100*float64(report.Bytes)/float64(report.Limit))
+	for _, c := range report.Components {
+		fmt.Fprintf(w, "  %-22s %8d bytes  %5.1f%%  (%d)\n", c.Name, c.Bytes, 100*float64(c.Bytes)/float64(report.Bytes), c.Count)
+	}
+
+	fmt.Fprintf(w, "\n%s\n", visualizer.Heading("Suggestions"))
+	if len(report.Suggestions) == 0 {
+		fmt.Fprintf(w, "%s No reductions found\n", visualizer.Success())
+		return
+	}
+	for _, s := range report.Suggestions {
+		fmt.Fprintf(w, "%s [%s] %s\n", visualizer.Symbol("pin"), s.ID, s.Message)
+		if s.Path != "" {
+			fmt.Fprintf(w, "    at    %s\n", s.Path)
+		}
+		if s.Savings > 0 {
+			fmt.Fprintf(w, "    saves %d bytes\n", s.Savings)
+		}
+	}
+	if savings := report.Savings(); savings > 0 {
+		fmt.Fprintf(w, "\nApplying every suggestion saves %d bytes\n", savings)
+	}
+}
+
+func init() {
+	envSizeCmd.Flags().StringVarP(&envSizeNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to fetch a transaction hash from (testnet, mainnet, futurenet)")
+	envSizeCmd.Flags().StringVar(&envSizeRPCURLFlag, "rpc-url", "", "Custom Horizon RPC URL")
+	envSizeCmd.Flags().IntVar(&envSizeLimitFlag, "limit", envsize.DefaultLimit, "Transaction size limit in bytes to compare against")
+	envSizeCmd.Flags().BoolVar(&envSizeJSONFlag, "json", false, "Output the report as JSON")
+	supportPorcelain(envSizeCmd)
+	rootCmd.AddCommand(envSizeCmd)
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/envsize"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintEnvSize(t *testing.T) {
+	report := &envsize.Report{
+		Bytes: 400,
+		Limit: 1000,
+		Components: []envsize.Component{
+			{Name: envsize.ComponentReadOnly, Bytes: 300, Count: 6},
+			{Name: envsize.ComponentOther, Bytes: 100, Count: 1},
+		},
+		Suggestions: []envsize.Suggestion{
+			{ID: "duplicate-footprint-key", Path: "v1.tx.ext.sorobanData.resources.footprint.readOnly[5]", Message: "listed twice", Savings: 50},
+		},
+	}
+
+	var buf bytes.Buffer
+	printEnvSize(&buf, report)
+	out := buf.String()
+	assert.Contains(t, out, "Total: 400 of 1000 bytes (40.0%)")
+	assert.Contains(t, out, "footprint read-only")
+	assert.Contains(t, out, "75.0%")
+	assert.Contains(t, out, "[duplicate-footprint-key] listed twice")
+	assert.Contains(t, out, "saves 50 bytes")
+	assert.Contains(t, out, "Applying every suggestion saves 50 bytes")
+}
+
+func TestPrintEnvSizeWithoutSuggestions(t *testing.T) {
+	var buf bytes.Buffer
+	printEnvSize(&buf, &envsize.Report{Bytes: 10, Limit: 100, Components: []envsize.Component{{Name: envsize.ComponentOther, Bytes: 10, Count: 1}}})
+	assert.Contains(t, buf.String(), "No reductions found")
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/dotandev/hintents/internal/xdrdoc"
+	"github.com/spf13/cobra"
+)
+
+var explainXdrPathJSON bool
+
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain protocol concepts",
+	Long: `Explain Stellar protocol concepts without leaving the terminal.
+
+Available subcommands:
+  xdr-path - Document an XDR type or field`,
+}
+
+var explainXdrPathCmd = &cobra.Command{
+	Use:   "xdr-path [path]",
+	Short: "Document an XDR type or field",
+	Long: `Print what an XDR field means and the limits that apply to it, from a
+reference embedded in erst. The path is a type name followed by fields,
+each leading into the field's type, e.g.
+SorobanTransactionData.resources.instructions. Names are matched ignoring
+case and underscores. A type name alone lists its fields; no path lists the
+documented types.`,
+	Example: `  erst explain xdr-path SorobanTransactionData.resources.instructions
+  erst explain xdr-path Transaction.cond.v2.minSeqAge
+  erst explain xdr-path LedgerFootprint --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			types, err := xdrdoc.Types()
+			if err != nil {
+				return err
+			}
+			if explainXdrPathJSON {
+				return writeJSON(cmd, types)
+			}
+			table := visualizer.NewTable("TYPE", "DESCRIPTION")
+			for _, t := range types {
+				table.AddRow(t.Name, firstSentence(t.Doc))
+			}
+			table.Render(os.Stdout)
+			return nil
+		}
+
+		entry, err := xdrdoc.Lookup(args[0])
+		if err != nil {
+			return err
+		}
+		if explainXdrPathJSON {
+			return writeJSON(cmd, entry)
+		}
+		writeXdrDoc(os.Stdout, entry)
+		return nil
+	},
+}
+
+func writeXdrDoc(w io.Writer, e *xdrdoc.Entry) {
+	if f := e.Field; f != nil {
+		fmt.Fprintf(w, "%s (%s)\n\n%s\n", e.Path, f.Type, f.Doc)
+		if f.Limits != "" {
+			fmt.Fprintf(w, "\nLimits: %s\n", f.Limits)
+		}
+		return
+	}
+
+	fmt.Fprintf(w, "%s\n\n%s\n\n", e.Type.Name, e.Type.Doc)
+	table := visualizer.NewTable("FIELD", "TYPE", "DESCRIPTION")
+	for _, f := range e.Type.Fields {
+		table.AddRow(f.Name, f.Type, firstSentence(f.Doc))
+	}
+	table.Render(w)
+	fmt.Fprintf(w, "\nRun 'erst explain xdr-path %s.<field>' for details and limits.\n", e.Type.Name)
+}
+
+// firstSentence shortens a description for table cells
+func firstSentence(s string) string {
+	for i := 0; i < len(s); {
+		j := strings.Index(s[i:], ". ")
+		if j < 0 {
+			break
+		}
+		end := i + j + 1
+		if !strings.HasSuffix(s[:end], "i.e.") && !strings.HasSuffix(s[:end], "e.g.") {
+			return s[:end]
+		}
+		i = end
+	}
+	return s
+}
+
+func init() {
+	explainXdrPathCmd.Flags().BoolVar(&explainXdrPathJSON, "json", false, "Output as JSON")
+
+	supportPorcelain(explainXdrPathCmd)
+	explainCmd.AddCommand(explainXdrPathCmd)
+	rootCmd.AddCommand(explainCmd)
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/xdrdoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteXdrDoc(t *testing.T) {
+	entry, err := xdrdoc.Lookup("SorobanTransactionData.resources.instructions")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	writeXdrDoc(&buf, entry)
+	out := buf.String()
+	assert.Contains(t, out, "SorobanTransactionData.resources.instructions (uint32)\n\nCPU instructions")
+	assert.Contains(t, out, "Limits: Bounded by the network's txMaxInstructions")
+
+	entry, err = xdrdoc.Lookup("LedgerFootprint")
+	require.NoError(t, err)
+	buf.Reset()
+	writeXdrDoc(&buf, entry)
+	out = buf.String()
+	assert.Contains(t, out, "readWrite")
+	assert.Contains(t, out, "erst explain xdr-path LedgerFootprint.<field>")
+}
+
+func TestFirstSentence(t *testing.T) {
+	assert.Equal(t, "One.", firstSentence("One. Two."))
+	assert.Equal(t, "Read, i.e. loaded.", firstSentence("Read, i.e. loaded. More."))
+	assert.Equal(t, "No period", firstSentence("No period"))
+}
@@ -4,46 +4,46 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 
-	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/dotandev/hintents/internal/snapshot"
 	"github.com/spf13/cobra"
 )
 
-var exportSnapshotFlag string
+var (
+	exportSnapshotFlag string
+	exportFormatFlag   string
+	exportSessionFlag  string
+)
 
 var exportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "Export data from the current session",
-	Long:  `Export debugging data, such as state snapshots, from the currently active session.`,
+	Long: `Export debugging data, such as state snapshots, from the currently active session.
+
+--session exports from a saved session instead of the current one.`,
+	Example: `  erst export --snapshot state.json
+  erst export --snapshot state.json --session abc123`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if exportSnapshotFlag == "" {
 			return fmt.Errorf("must specify --snapshot <file>")
 		}
 
-		// Get current session
-		data := GetCurrentSession()
-		if data == nil {
-			return fmt.Errorf("no active session. Run 'erst debug <tx-hash>' first")
-		}
-
-		// Unwrap simulation request to get ledger entries
-		var simReq simulator.SimulationRequest
-		if err := json.Unmarshal([]byte(data.SimRequestJSON), &simReq); err != nil {
-			return fmt.Errorf("failed to parse session data: %w", err)
+		data, err := resolveSession(cmd.Context(), exportSessionFlag)
+		if err != nil {
+			return err
 		}
 
-		if len(simReq.LedgerEntries) == 0 {
-			fmt.Println("Warning: No ledger entries found in the current session.")
+		entries, err := sessionLedgerEntries(data, "")
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
 		}
 
 		// Convert to snapshot
-		snap := snapshot.FromMap(simReq.LedgerEntries)
+		snap := snapshot.FromMap(entries)
 
 		// Save
-		if err := snapshot.Save(exportSnapshotFlag, snap); err != nil {
+		if err := saveSnapshot(exportSnapshotFlag, snap, exportFormatFlag); err != nil {
 			return fmt.Errorf("failed to save snapshot: %w", err)
 		}
 
@@ -53,6 +53,8 @@ var exportCmd = &cobra.Command{
 }
 
 func init() {
-	exportCmd.Flags().StringVar(&exportSnapshotFlag, "snapshot", "", "Output file for JSON snapshot")
+	exportCmd.Flags().StringVar(&exportSnapshotFlag, "snapshot", "", "Output file for the snapshot")
+	exportCmd.Flags().StringVar(&exportFormatFlag, "format", snapshotFormatJSON, "Snapshot format: json or v2")
+	exportCmd.Flags().StringVar(&exportSessionFlag, "session", "", "Session to export from (default: the current session)")
 	rootCmd.AddCommand(exportCmd)
 }
@@ -0,0 +1,141 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	feesLedgersFlag uint
+	feesJSONFlag    bool
+)
+
+var feesCmd = &cobra.Command{
+	Use:   "fees",
+	Short: "Inspect the network fee market",
+	Long: `Inspect recent fee statistics and ledger congestion for a network.
+
+Available subcommands:
+  history - Show recent fee percentiles and per-ledger capacity usage`,
+}
+
+var feesHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent fee stats and surge pricing for a network",
+	Long: `Fetch recent fee statistics and the latest closed ledgers for the selected
+network. Ledgers at or above 90% of their operation capacity are marked as
+surging: fee bids compete for inclusion and low bids may be rejected with
+tx_insufficient_fee or expire in the queue.`,
+	Example: `  erst fees history --network testnet
+  erst fees history --ledgers 50 --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(networkFlag))}
+		if rpcURLFlag != "" {
+			opts = append(opts, rpc.WithHorizonURL(rpcURLFlag))
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		stats, err := client.GetFeeStats(cmd.Context())
+		if err != nil {
+			return err
+		}
+		ledgers, err := client.GetRecentLedgers(cmd.Context(), feesLedgersFlag)
+		if err != nil {
+			return err
+		}
+
+		out := feesHistoryOutput{Network: networkFlag, Stats: stats, Ledgers: make([]feesLedger, 0, len(ledgers))}
+		for _, l := range ledgers {
+			load := ledgerLoad(l)
+			out.Ledgers = append(out.Ledgers, feesLedger{LedgerLoad: load, ClosedAt: l.CloseTime.UTC().Format("15:04:05"), Usage: load.Usage(), Surging: load.Surging()})
+		}
+
+		if feesJSONFlag {
+			return writeJSON(cmd, out)
+		}
+		printFeesHistory(out)
+		return nil
+	},
+}
+
+type feesLedger struct {
+	analytics.LedgerLoad
+	ClosedAt string  `json:"closed_at"`
+	Usage    float64 `json:"usage"`
+	Surging  bool    `json:"surging"`
+}
+
+type feesHistoryOutput struct {
+	Network string        `json:"network"`
+	Stats   *rpc.FeeStats `json:"stats"`
+	Ledgers []feesLedger  `json:"ledgers"`
+}
+
+// ledgerLoad converts a ledger header into the fee market view used by
+// surge analysis
+func ledgerLoad(l *rpc.LedgerHeaderResponse) analytics.LedgerLoad {
+	return analytics.LedgerLoad{
+		Sequence:     l.Sequence,
+		BaseFee:      int64(l.BaseFee),
+		Operations:   int64(l.TxSetOperationCount),
+		MaxTxSetSize: int64(l.MaxTxSetSize),
+	}
+}
+
+func printFeesHistory(out feesHistoryOutput) {
+	s := out.Stats
+	fmt.Printf("Network: %s (last ledger %d)\n", out.Network, s.LastLedger)
+	fmt.Printf("Base fee: %d stroops, capacity usage: %.0f%%\n\n", s.LastLedgerBaseFee, s.CapacityUsage*100)
+
+	dist := visualizer.NewTable("PER OPERATION (stroops)", "MIN", "MODE", "P50", "P90", "P99", "MAX").AlignRight(1, 2, 3, 4, 5, 6)
+	addDistribution := func(name string, d rpc.FeeDistribution) {
+		dist.AddRow(name, fmt.Sprint(d.Min), fmt.Sprint(d.Mode), fmt.Sprint(d.P50), fmt.Sprint(d.P90), fmt.Sprint(d.P99), fmt.Sprint(d.Max))
+	}
+	addDistribution("Fee charged", s.FeeCharged)
+	addDistribution("Max fee bid", s.MaxFee)
+	if s.SorobanInclusionFee != nil {
+		addDistribution("Soroban inclusion fee", *s.SorobanInclusionFee)
+	}
+	dist.Render(os.Stdout)
+
+	if len(out.Ledgers) == 0 {
+		return
+	}
+	fmt.Println()
+	ledgers := visualizer.NewTable("LEDGER", "CLOSED", "OPS/MAX", "CAPACITY", "BASE FEE", "SURGE").AlignRight(3, 4)
+	surging := 0
+	for _, l := range out.Ledgers {
+		mark := ""
+		if l.Surging {
+			mark = "surge"
+			surging++
+		}
+		ledgers.AddRow(fmt.Sprint(l.Sequence), l.ClosedAt, fmt.Sprintf("%d/%d", l.Operations, l.MaxTxSetSize),
+			fmt.Sprintf("%.0f%%", l.Usage*100), fmt.Sprint(l.BaseFee), mark)
+	}
+	ledgers.Render(os.Stdout)
+	fmt.Printf("\n%d of %d recent ledgers were surging\n", surging, len(out.Ledgers))
+}
+
+func init() {
+	feesHistoryCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
+	feesHistoryCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Horizon RPC URL")
+	feesHistoryCmd.Flags().UintVar(&feesLedgersFlag, "ledgers", 20, "Number of recent ledgers to show")
+	feesHistoryCmd.Flags().BoolVar(&feesJSONFlag, "json", false, "Output as JSON")
+
+	supportPorcelain(feesHistoryCmd)
+	feesCmd.AddCommand(feesHistoryCmd)
+	rootCmd.AddCommand(feesCmd)
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dotandev/hintents/internal/compare"
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/hooks"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+var noHooksFlag bool
+
+// compareReport is the file passed to post-compare hooks
+type compareReport struct {
+	TxHash      string                                   `json:"tx_hash"`
+	Networks    []string                                 `json:"networks"`
+	StatusMatch bool                                     `json:"status_match"`
+	Events      compare.Summary                          `json:"events"`
+	Results     map[string]*simulator.SimulationResponse `json:"results"`
+}
+
+// newCompareReport summarizes the comparison of left on net1 with right on net2
+func newCompareReport(txHash, net1, net2 string, left, right *simulator.SimulationResponse) *compareReport {
+	return &compareReport{
+		TxHash:      txHash,
+		Networks:    []string{net1, net2},
+		StatusMatch: left.Status == right.Status,
+		Events:      compare.Summarize(compare.Events(left.Events, right.Events)),
+		Results:     map[string]*simulator.SimulationResponse{net1: left, net2: right},
+	}
+}
+
+// configuredHooks returns the hooks for event in the config file, or none
+// when --no-hooks is set
+func configuredHooks(event hooks.Event) ([]hooks.Hook, error) {
+	if noHooksFlag {
+		return nil, nil
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	var out []hooks.Hook
+	for _, hc := range cfg.Hooks {
+		h := hooks.Hook{
+			Event:   hooks.Event(hc.Event),
+			Command: hc.Command,
+			Args:    hc.Args,
+			Timeout: time.Duration(hc.TimeoutSeconds) * time.Second,
+		}
+		if err := h.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid hook in config: %w", err)
+		}
+		if h.Event == event {
+			out = append(out, h)
+		}
+	}
+	return out, nil
+}
+
+// runHooks runs the configured hooks for p.Event
+func runHooks(ctx context.Context, p hooks.Payload) error {
+	hs, err := configuredHooks(p.Event)
+	if err != nil || len(hs) == 0 {
+		return err
+	}
+	logger.Logger.Debug("Running hooks", "event", p.Event, "count", len(hs))
+	return hooks.RunAll(ctx, hs, p, os.Stdout)
+}
+
+// runPostDebugHooks runs the post-debug hooks for data and, after a
+// comparison, the post-compare hooks. The command already succeeded, so
+// hook failures are reported as warnings.
+func runPostDebugHooks(ctx context.Context, data *session.SessionData, reportPath, outputDir string, cmp *compareReport) {
+	if err := postDebugHooks(ctx, data, reportPath, outputDir, cmp); err != nil {
+		logger.Logger.Warn("Hook failed", "error", err)
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+func postDebugHooks(ctx context.Context, data *session.SessionData, reportPath, outputDir string, cmp *compareReport) error {
+	post, err := configuredHooks(hooks.PostDebug)
+	if err != nil {
+		return err
+	}
+	var postCompare []hooks.Hook
+	if cmp != nil {
+		if postCompare, err = configuredHooks(hooks.PostCompare); err != nil {
+			return err
+		}
+	}
+	if len(post)+len(postCompare) == 0 {
+		return nil
+	}
+
+	sessionFile, err := writeSessionFile(data)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(filepath.Dir(sessionFile))
+	p := hooks.Payload{
+		Event:       hooks.PostDebug,
+		TxHash:      data.TxHash,
+		Network:     data.Network,
+		SessionID:   data.ID,
+		SessionFile: sessionFile,
+		ReportPath:  reportPath,
+		OutputDir:   outputDir,
+	}
+	postErr := hooks.RunAll(ctx, post, p, os.Stdout)
+	if len(postCompare) == 0 {
+		return postErr
+	}
+
+	p.CompareNetwork = cmp.Networks[1]
+	return errors.Join(postErr, runCompareHooks(ctx, postCompare, p, filepath.Dir(sessionFile), cmp))
+}
+
+// runPostCompareHooks runs the post-compare hooks of a comparison outside
+// erst debug, with report as its comparison report. The comparison already
+// ran, so hook failures are reported as warnings.
+func runPostCompareHooks(ctx context.Context, p hooks.Payload, report any) {
+	if err := postCompareHooks(ctx, p, report); err != nil {
+		logger.Logger.Warn("Hook failed", "error", err)
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+func postCompareHooks(ctx context.Context, p hooks.Payload, report any) error {
+	hs, err := configuredHooks(hooks.PostCompare)
+	if err != nil || len(hs) == 0 {
+		return err
+	}
+	dir, err := os.MkdirTemp("", "erst-hook-")
+	if err != nil {
+		return fmt.Errorf("failed to create comparison report: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	return runCompareHooks(ctx, hs, p, dir, report)
+}
+
+// runCompareHooks writes report to dir and runs the post-compare hooks hs
+// with it as the report
+func runCompareHooks(ctx context.Context, hs []hooks.Hook, p hooks.Payload, dir string, report any) error {
+	p.Event = hooks.PostCompare
+	p.ReportPath = filepath.Join(dir, "compare.json")
+	raw, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode comparison report: %w", err)
+	}
+	if err := os.WriteFile(p.ReportPath, raw, 0600); err != nil {
+		return fmt.Errorf("failed to write comparison report: %w", err)
+	}
+	return hooks.RunAll(ctx, hs, p, os.Stdout)
+}
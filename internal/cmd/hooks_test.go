@@ -0,0 +1,117 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/hooks"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeHookConfig(t *testing.T, hs ...config.HookConfig) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("ERST_HOME", home)
+	data, err := json.Marshal(config.Config{Hooks: hs})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.json"), data, 0600))
+}
+
+func TestPostDebugHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script hooks")
+	}
+	out := t.TempDir()
+	hook := filepath.Join(t.TempDir(), "hook.sh")
+	// Copy the session and report, which are removed after the hooks ran
+	require.NoError(t, os.WriteFile(hook, []byte(`#!/bin/sh
+cp "$ERST_SESSION_FILE" "`+out+`/$ERST_HOOK_EVENT.session"
+cp "$ERST_REPORT_PATH" "`+out+`/$ERST_HOOK_EVENT.report"
+`), 0755))
+	writeHookConfig(t,
+		config.HookConfig{Event: "post-debug", Command: hook},
+		config.HookConfig{Event: "post-compare", Command: hook},
+	)
+
+	report := filepath.Join(t.TempDir(), "abc.trace.json")
+	require.NoError(t, os.WriteFile(report, []byte("{}"), 0600))
+	data := &session.SessionData{ID: "s1", TxHash: "abc", Network: "testnet"}
+	left := &simulator.SimulationResponse{Status: "success"}
+	right := &simulator.SimulationResponse{Status: "error"}
+	cmp := newCompareReport("abc", "testnet", "mainnet", left, right)
+
+	require.NoError(t, postDebugHooks(context.Background(), data, report, "", cmp))
+
+	var got session.SessionData
+	raw, err := os.ReadFile(filepath.Join(out, "post-debug.session"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &got))
+	assert.Equal(t, "s1", got.ID)
+
+	raw, err = os.ReadFile(filepath.Join(out, "post-debug.report"))
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(raw))
+
+	var gotCmp compareReport
+	raw, err = os.ReadFile(filepath.Join(out, "post-compare.report"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &gotCmp))
+	assert.Equal(t, []string{"testnet", "mainnet"}, gotCmp.Networks)
+	assert.False(t, gotCmp.StatusMatch)
+}
+
+func TestRunHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script hooks")
+	}
+	writeHookConfig(t, config.HookConfig{Event: "pre-debug", Command: "false"})
+	err := runHooks(context.Background(), hooks.Payload{Event: hooks.PreDebug, TxHash: "abc"})
+	assert.ErrorContains(t, err, "pre-debug hook false failed")
+
+	noHooksFlag = true
+	defer func() { noHooksFlag = false }()
+	assert.NoError(t, runHooks(context.Background(), hooks.Payload{Event: hooks.PreDebug}))
+
+	noHooksFlag = false
+	writeHookConfig(t, config.HookConfig{Event: "on-crash", Command: "true"})
+	err = runHooks(context.Background(), hooks.Payload{Event: hooks.PreDebug})
+	assert.ErrorContains(t, err, "unknown hook event")
+}
+
+func TestPostCompareHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script hooks")
+	}
+	out := filepath.Join(t.TempDir(), "compare.json")
+	hook := filepath.Join(t.TempDir(), "hook.sh")
+	require.NoError(t, os.WriteFile(hook, []byte(`#!/bin/sh
+cp "$ERST_REPORT_PATH" "`+out+`"
+`), 0755))
+	writeHookConfig(t, config.HookConfig{Event: "post-compare", Command: hook})
+
+	report := &checkpointCompareReport{TxHash: "abc", SessionID: "s1", Checkpoints: []string{"original", "fixed"}}
+	require.NoError(t, postCompareHooks(context.Background(), hooks.Payload{TxHash: "abc", SessionID: "s1"}, report))
+
+	var got checkpointCompareReport
+	raw, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &got))
+	assert.Equal(t, []string{"original", "fixed"}, got.Checkpoints)
+
+	noHooksFlag = true
+	defer func() { noHooksFlag = false }()
+	require.NoError(t, os.Remove(out))
+	require.NoError(t, postCompareHooks(context.Background(), hooks.Payload{}, report))
+	assert.NoFileExists(t, out)
+}
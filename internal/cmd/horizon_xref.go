@@ -0,0 +1,87 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/horizonxref"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+)
+
+// printHorizonCrossReference fetches Horizon's operations and effects of a
+// transaction and prints them with where they disagree with in
+func printHorizonCrossReference(ctx context.Context, client *rpc.Client, txHash string, in horizonxref.Input) {
+	ops, err := client.GetTransactionOperations(ctx, txHash)
+	if err != nil {
+		logger.Logger.Warn("Horizon cross-reference skipped", "error", err)
+		fmt.Printf("\n%s Horizon cross-reference skipped: %v\n", visualizer.Warning(), err)
+		return
+	}
+	effs, err := client.GetTransactionEffects(ctx, txHash)
+	if err != nil {
+		logger.Logger.Warn("Horizon cross-reference skipped", "error", err)
+		fmt.Printf("\n%s Horizon cross-reference skipped: %v\n", visualizer.Warning(), err)
+		return
+	}
+	report, err := horizonxref.Compare(ops, effs, in)
+	if err != nil {
+		logger.Logger.Warn("Horizon cross-reference failed", "error", err)
+		return
+	}
+
+	fmt.Printf("\n%s\n", visualizer.Heading("Horizon Cross-Reference"))
+	writeHorizonReport(os.Stdout, report)
+	for _, d := range report.Discrepancies {
+		visualizer.Record("horizon_discrepancy", d.Kind, strconv.Itoa(d.OpIndex), d.Message)
+	}
+	ideEvents.Result("horizon", report)
+}
+
+func writeHorizonReport(w io.Writer, report *horizonxref.Report) {
+	if len(report.Operations) > 0 {
+		fmt.Fprintln(w, "Operations:")
+		table := visualizer.NewTable("#", "Type", "Source", "Successful")
+		for _, op := range report.Operations {
+			table.AddRow(strconv.Itoa(op.Index), op.Type, op.Source, strconv.FormatBool(op.Successful))
+		}
+		table.Render(w)
+	}
+
+	var balances []horizonxref.Effect
+	for _, e := range report.Effects {
+		if e.IsBalance() {
+			balances = append(balances, e)
+		}
+	}
+	if len(balances) > 0 {
+		fmt.Fprintf(w, "\nBalance effects (%d of %d effects):\n", len(balances), len(report.Effects))
+		table := visualizer.NewTable("Op", "Effect", "Holder", "Asset", "Amount")
+		for _, e := range balances {
+			op := "-"
+			if e.OpIndex >= 0 {
+				op = strconv.Itoa(e.OpIndex)
+			}
+			table.AddRow(op, e.Type, e.Holder, e.Asset, e.Amount)
+		}
+		table.Render(w)
+	} else if len(report.Effects) > 0 {
+		fmt.Fprintf(w, "\n%d effect(s), none moving balances\n", len(report.Effects))
+	}
+
+	if len(report.Discrepancies) == 0 {
+		fmt.Fprintf(w, "\n%s Horizon agrees with erst's analysis\n", visualizer.Success())
+		return
+	}
+	fmt.Fprintf(w, "\n%s %d discrepancy(ies) between Horizon and erst:\n", visualizer.Warning(), len(report.Discrepancies))
+	for _, d := range report.Discrepancies {
+		fmt.Fprintf(w, "  [%s] %s\n", d.Kind, d.Message)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/horizonxref"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteHorizonReport(t *testing.T) {
+	report := &horizonxref.Report{
+		Operations: []horizonxref.Operation{{Index: 0, Type: "invoke_host_function", Source: "GABC", Successful: true}},
+		Effects: []horizonxref.Effect{
+			{OpIndex: 0, Type: "contract_credited", Holder: "CDEF", Asset: "USDC:GISSUER", Amount: "5.0000000"},
+			{OpIndex: 0, Type: "account_sequence_bumped", Holder: "GABC"},
+		},
+		Discrepancies: []horizonxref.Discrepancy{
+			{Kind: horizonxref.KindBalance, OpIndex: -1, Message: "Horizon's effects change the USDC:GISSUER balance of CDEF by +5.0000000 but erst's token flow shows 0.0000000"},
+		},
+	}
+
+	var buf bytes.Buffer
+	writeHorizonReport(&buf, report)
+	out := buf.String()
+	assert.Contains(t, out, "invoke_host_function")
+	assert.Contains(t, out, "Balance effects (1 of 2 effects)")
+	assert.Contains(t, out, "contract_credited")
+	assert.NotContains(t, out, "account_sequence_bumped")
+	assert.Contains(t, out, "1 discrepancy(ies) between Horizon and erst")
+	assert.Contains(t, out, "[balance] Horizon's effects change")
+}
+
+func TestWriteHorizonReportAgreement(t *testing.T) {
+	var buf bytes.Buffer
+	writeHorizonReport(&buf, &horizonxref.Report{
+		Operations: []horizonxref.Operation{{Type: "payment", Successful: true}},
+		Effects:    []horizonxref.Effect{{Type: "account_sequence_bumped"}},
+	})
+	assert.Contains(t, buf.String(), "1 effect(s), none moving balances")
+	assert.Contains(t, buf.String(), "Horizon agrees with erst's analysis")
+}
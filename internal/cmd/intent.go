@@ -0,0 +1,179 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/intent"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	intentNetworkFlag  string
+	intentRPCURLFlag   string
+	intentRPCTokenFlag string
+	intentOutFlag      string
+	intentJSONFlag     bool
+)
+
+var intentCmd = &cobra.Command{
+	Use:   "intent <intent.yaml>",
+	Short: "Build and dry-run a contract call from a YAML/JSON intent",
+	Long: `Construct an InvokeHostFunction transaction from a high-level intent,
+simulate it to compute its footprint, resources and fee, and output the
+unsigned transaction XDR ready for signing.
+
+An intent names the source account, contract, function and arguments. Each
+argument is a typed SCVal such as {type: i128, value: "1000"}; see
+docs/CLI.md for the full list of types.
+
+  source: GABC...
+  contract: CDEF...
+  function: transfer
+  args:
+    - {type: address, value: GABC...}
+    - {type: address, value: GXYZ...}
+    - {type: i128, value: "10000000"}
+  timeout: 300
+
+The sequence number is fetched from the network unless the intent sets one.`,
+	Example: `  erst intent transfer.yaml --network testnet
+  erst intent transfer.json --out tx.xdr`,
+	Args: cobra.ExactArgs(1),
+	RunE: runIntent,
+}
+
+// intentOutput is the --json form of an intent dry run
+type intentOutput struct {
+	EnvelopeXdr    string `json:"envelope_xdr"`
+	Fee            uint32 `json:"fee"`
+	ResourceFee    int64  `json:"resource_fee"`
+	Instructions   uint32 `json:"instructions"`
+	ReadOnly       int    `json:"footprint_read_only"`
+	ReadWrite      int    `json:"footprint_read_write"`
+	AuthEntries    int    `json:"auth_entries"`
+	LatestLedger   uint32 `json:"latest_ledger,omitempty"`
+	SequenceNumber int64  `json:"sequence"`
+}
+
+func runIntent(cmd *cobra.Command, args []string) error {
+	in, err := intent.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	network := intentNetworkFlag
+	if network == "" {
+		network = in.Network
+	}
+	if network == "" {
+		network = string(rpc.Testnet)
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(network)),
+		rpc.WithToken(intentRPCTokenFlag),
+	}
+	if intentRPCURLFlag != "" {
+		opts = append(opts, rpc.WithHorizonURL(intentRPCURLFlag))
+	}
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	ctx := cmd.Context()
+	if in.Sequence == 0 {
+		seq, err := client.GetAccountSequence(ctx, in.Source)
+		if err != nil {
+			return err
+		}
+		in.Sequence = seq + 1
+	}
+
+	env, err := in.Build(time.Now())
+	if err != nil {
+		return err
+	}
+	envXdr, err := xdr.MarshalBase64(env)
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	sim, err := client.SimulateTransaction(ctx, envXdr)
+	if err != nil {
+		return fmt.Errorf("simulation failed: %w", err)
+	}
+	if sim.Result.Error != "" {
+		return fmt.Errorf("simulation failed: %s", sim.Result.Error)
+	}
+
+	preflight := intent.Preflight{
+		TransactionData: sim.Result.TransactionData,
+		MinResourceFee:  sim.Result.MinResourceFee,
+	}
+	if len(sim.Result.Results) > 0 {
+		preflight.Auth = sim.Result.Results[0].Auth
+	}
+	resourceFee, err := intent.Apply(&env, preflight)
+	if err != nil {
+		return err
+	}
+
+	unsigned, err := xdr.MarshalBase64(env)
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %w", err)
+	}
+
+	data := env.V1.Tx.Ext.SorobanData
+	out := intentOutput{
+		EnvelopeXdr:    unsigned,
+		Fee:            uint32(env.V1.Tx.Fee),
+		ResourceFee:    resourceFee,
+		Instructions:   uint32(data.Resources.Instructions),
+		ReadOnly:       len(data.Resources.Footprint.ReadOnly),
+		ReadWrite:      len(data.Resources.Footprint.ReadWrite),
+		AuthEntries:    len(preflight.Auth),
+		LatestLedger:   sim.Result.LatestLedger,
+		SequenceNumber: in.Sequence,
+	}
+
+	if intentOutFlag != "" {
+		if err := os.WriteFile(intentOutFlag, []byte(unsigned+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write transaction: %w", err)
+		}
+	}
+
+	if intentJSONFlag {
+		return writeJSON(cmd, out)
+	}
+
+	fmt.Printf("Intent: %s.%s from %s on %s\n", in.Contract, in.Function, in.Source, network)
+	fmt.Printf("Sequence: %d\n", out.SequenceNumber)
+	fmt.Printf("Footprint: %d read-only, %d read-write entries\n", out.ReadOnly, out.ReadWrite)
+	fmt.Printf("Instructions: %d\n", out.Instructions)
+	fmt.Printf("Auth entries: %d\n", out.AuthEntries)
+	fmt.Printf("Fee (stroops): %d (resource fee %d)\n", out.Fee, out.ResourceFee)
+	if intentOutFlag != "" {
+		fmt.Printf("Unsigned transaction written to %s\n", intentOutFlag)
+		return nil
+	}
+	fmt.Printf("\nUnsigned transaction XDR:\n%s\n", unsigned)
+	return nil
+}
+
+func init() {
+	intentCmd.Flags().StringVarP(&intentNetworkFlag, "network", "n", "", "Stellar network to use (default: the intent's network, or testnet)")
+	intentCmd.Flags().StringVar(&intentRPCURLFlag, "rpc-url", "", "Custom Horizon RPC URL to use")
+	intentCmd.Flags().StringVar(&intentRPCTokenFlag, "rpc-token", "", "RPC authentication token (can also use ERST_RPC_TOKEN env var)")
+	intentCmd.Flags().StringVarP(&intentOutFlag, "out", "o", "", "Write the unsigned transaction XDR to this file")
+	intentCmd.Flags().BoolVar(&intentJSONFlag, "json", false, "Output the result as JSON")
+
+	rootCmd.AddCommand(intentCmd)
+}
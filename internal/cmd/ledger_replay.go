@@ -0,0 +1,148 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/ledgerreplay"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ledgerReplayJSON    bool
+	ledgerReplaySandbox sandboxFlags
+)
+
+var ledgerCmd = &cobra.Command{
+	Use:   "ledger",
+	Short: "Inspect a whole ledger",
+	Long: `Inspect a whole ledger.
+
+Available subcommands:
+  replay - Simulate a ledger's Soroban transactions and compare with the chain`,
+}
+
+var ledgerReplayCmd = &cobra.Command{
+	Use:   "replay <sequence>",
+	Short: "Simulate a ledger's Soroban transactions and compare with the chain",
+	Long: `Fetch every transaction applied in a ledger through Soroban RPC, simulate
+the Soroban ones locally in application order and compare each simulated
+outcome with its on-chain result. Use it to check simulator fidelity across
+a real workload, for example after a protocol upgrade.
+
+Each transaction is simulated against the state it was applied on, taken
+from the first recorded state of every entry in its own meta. Meta only
+records the entries a transaction changed, so its read-only footprint
+entries come from the meta of an earlier transaction in the ledger that
+changed them. The rest are fetched at their current state, which may be
+newer than the ledger, and a transaction simulated with fetched entries is
+marked low confidence: when it differs from the chain it is reported but not
+counted as a mismatch.
+
+The command exits non-zero when any transaction's outcome differs or it
+could not be simulated.`,
+	Example: `  erst ledger replay 51234567 --network testnet
+  erst ledger replay 51234567 --json --sim-cpu-limit 30s`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		seq, err := strconv.ParseUint(args[0], 10, 32)
+		if err != nil || seq == 0 {
+			return fmt.Errorf("invalid ledger sequence %q", args[0])
+		}
+
+		opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(networkFlag))}
+		if rpcURLFlag != "" {
+			opts = append(opts, rpc.WithSorobanURL(rpcURLFlag))
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		runner, err := simulator.NewRunner("", false)
+		if err != nil {
+			return fmt.Errorf("failed to initialize simulator runner: %w", err)
+		}
+		runner.Limits = runner.Limits.Tighten(ledgerReplaySandbox.limits())
+
+		ledger := uint32(seq)
+		txs, err := client.GetLedgerTransactions(cmd.Context(), ledger, ledger)
+		if err != nil {
+			return err
+		}
+
+		report := ledgerreplay.Replay(cmd.Context(), runner, ledger, txs, client.GetLedgerEntries)
+		if ledgerReplayJSON {
+			if err := writeJSON(cmd, report); err != nil {
+				return err
+			}
+		} else {
+			writeLedgerReplay(os.Stdout, report)
+		}
+
+		if !report.OK() {
+			return fmt.Errorf("%d of %d Soroban transactions did not match", report.Mismatched+report.Errored, report.Soroban)
+		}
+		return nil
+	},
+}
+
+func writeLedgerReplay(w io.Writer, r *ledgerreplay.Report) {
+	if r.Soroban == 0 {
+		fmt.Fprintf(w, "No Soroban transactions in ledger %d (%d transaction(s))\n", r.Ledger, r.Transactions)
+		return
+	}
+
+	table := visualizer.NewTable("ORDER", "RESULT", "HASH", "ON-CHAIN", "SIMULATED").AlignRight(0)
+	for _, res := range r.Results {
+		mark := "MATCH"
+		switch {
+		case res.Actual == "":
+			mark = "ERROR"
+		case !res.Match && res.LowConfidence:
+			mark = "LOW CONFIDENCE"
+		case !res.Match:
+			mark = "MISMATCH"
+		}
+		actual := res.Actual
+		if actual == "" {
+			actual = "-"
+		}
+		table.AddRow(strconv.Itoa(res.ApplicationOrder), mark, res.Hash, res.Expected, actual)
+		if !res.Match && res.Error != "" {
+			table.AddNote(res.Error)
+		}
+		for _, d := range res.Differences {
+			table.AddNote(d)
+		}
+		if !res.Match && res.LowConfidence {
+			table.AddNote(fmt.Sprintf("simulated with %d entry(ies) fetched at their current state", res.Fetched))
+		}
+	}
+	table.Render(w)
+
+	fmt.Fprintf(w, "\nLedger %d: %d Soroban of %d transaction(s), %d matched, %d mismatched, %d low confidence, %d could not be simulated\n",
+		r.Ledger, r.Soroban, r.Transactions, r.Matched, r.Mismatched, r.LowConfidence, r.Errored)
+	if !r.OK() {
+		fmt.Fprintln(w, "Run 'erst debug <tx-hash>' on a transaction to compare its simulation in detail.")
+	}
+}
+
+func init() {
+	ledgerReplayCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
+	ledgerReplayCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Soroban RPC URL")
+	ledgerReplayCmd.Flags().BoolVar(&ledgerReplayJSON, "json", false, "Output as JSON")
+	ledgerReplaySandbox.register(ledgerReplayCmd, simulator.Limits{})
+
+	supportPorcelain(ledgerReplayCmd)
+	ledgerCmd.AddCommand(ledgerReplayCmd)
+	rootCmd.AddCommand(ledgerCmd)
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/ledgerreplay"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteLedgerReplay(t *testing.T) {
+	var buf bytes.Buffer
+	writeLedgerReplay(&buf, &ledgerreplay.Report{Ledger: 50, Transactions: 3})
+	assert.Equal(t, "No Soroban transactions in ledger 50 (3 transaction(s))\n", buf.String())
+
+	buf.Reset()
+	writeLedgerReplay(&buf, &ledgerreplay.Report{Ledger: 50, Transactions: 4, Soroban: 4, Matched: 1, Mismatched: 1, LowConfidence: 1, Errored: 1, Results: []ledgerreplay.Result{
+		{Hash: "aa", ApplicationOrder: 1, Expected: "success", Actual: "success", Match: true},
+		{Hash: "bb", ApplicationOrder: 2, Expected: "error", Actual: "success"},
+		{Hash: "cc", ApplicationOrder: 3, Expected: "success", Error: "cpu limit exceeded"},
+		{Hash: "dd", ApplicationOrder: 4, Expected: "success", Actual: "success", Differences: []string{"0 contract event(s), on-chain 1"}},
+		{Hash: "ee", ApplicationOrder: 5, Expected: "error", Actual: "success", Fetched: 2, LowConfidence: true},
+	}})
+	out := buf.String()
+	assert.Contains(t, out, "MATCH")
+	assert.Contains(t, out, "MISMATCH")
+	assert.Contains(t, out, "ERROR")
+	assert.Contains(t, out, "cpu limit exceeded")
+	assert.Contains(t, out, "0 contract event(s), on-chain 1")
+	assert.Contains(t, out, "LOW CONFIDENCE")
+	assert.Contains(t, out, "simulated with 2 entry(ies) fetched at their current state")
+	assert.Contains(t, out, "Ledger 50: 4 Soroban of 4 transaction(s), 1 matched, 1 mismatched, 1 low confidence, 1 could not be simulated")
+	assert.Contains(t, out, "erst debug <tx-hash>")
+}
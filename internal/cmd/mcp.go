@@ -0,0 +1,117 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/mcp"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mcpNetwork string
+	mcpRPCURL  string
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol tool server over stdio",
+	Long: `Expose erst operations as Model Context Protocol (MCP) tools so AI assistants
+can triage failed transactions automatically.
+
+The server speaks newline-delimited JSON-RPC 2.0 on stdin/stdout. Logs are
+written to stderr. Available tools:
+  debug_transaction    Fetch, replay and analyze a transaction by hash
+  decode_xdr           Decode envelope, result, ledger entry or diagnostic event XDR
+  simulate             Run the simulator on a raw envelope
+  list_sessions        List saved debug sessions, filtered by anchor context
+  get_session          Load a saved debug session
+  set_session_context  Attach the anchor, SEP flow and customer reference to a session`,
+	Example: `  # Register with an MCP client configuration
+  {"command": "erst", "args": ["mcp", "--network", "testnet"]}`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(mcpNetwork) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+		default:
+			return fmt.Errorf("invalid network: %s. Must be one of: testnet, mainnet, futurenet", mcpNetwork)
+		}
+
+		runner, err := simulator.NewRunner("", false)
+		if err != nil {
+			return fmt.Errorf("failed to create simulator: %w", err)
+		}
+
+		deps := mcp.Deps{
+			ClientFor: newClientFactory(mcpNetwork, mcpRPCURL),
+			Runner:    runner,
+		}
+
+		store, err := session.NewStore()
+		if err != nil {
+			logger.Logger.Warn("Session tools disabled", "error", err)
+		} else {
+			defer store.Close()
+			deps.Sessions = store
+		}
+
+		srv := mcp.NewServer("erst", Version)
+		mcp.RegisterErstTools(srv, deps)
+
+		logger.Logger.Info("MCP server ready", "network", mcpNetwork, "tools", len(srv.Tools()))
+		return srv.Serve(cmd.Context(), os.Stdin, os.Stdout)
+	},
+}
+
+// newClientFactory returns a cached RPC client per network. rpcURL applies
+// only to the default network.
+func newClientFactory(defaultNetwork, rpcURL string) func(network string) (*rpc.Client, error) {
+	var mu sync.Mutex
+	clients := make(map[string]*rpc.Client)
+
+	return func(network string) (*rpc.Client, error) {
+		if network == "" {
+			network = defaultNetwork
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if client, ok := clients[network]; ok {
+			return client, nil
+		}
+
+		switch rpc.Network(network) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+		default:
+			return nil, fmt.Errorf("invalid network: %s. Must be one of: testnet, mainnet, futurenet", network)
+		}
+
+		opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(network))}
+		if rpcURL != "" && network == defaultNetwork {
+			opts = append(opts, rpc.WithHorizonURL(rpcURL))
+		}
+
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create RPC client: %w", err)
+		}
+		clients[network] = client
+		return client, nil
+	}
+}
+
+func init() {
+	mcpCmd.Flags().StringVarP(&mcpNetwork, "network", "n", string(rpc.Mainnet), "Default network (testnet, mainnet, futurenet)")
+	mcpCmd.Flags().StringVar(&mcpRPCURL, "rpc-url", "", "Custom Horizon RPC URL for the default network")
+
+	rootCmd.AddCommand(mcpCmd)
+}
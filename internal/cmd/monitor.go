@@ -0,0 +1,241 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dotandev/hintents/internal/alert"
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/monitor"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	monitorOnceFlag   bool
+	monitorWatchFlag  []string
+	monitorStorageDSN string
+)
+
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "Re-simulate canary transactions and alert when their result changes",
+	Long: `Monitor mode replays canary transactions from saved sessions on a schedule.
+It alerts through PagerDuty or Opsgenie when a canary's result differs from
+the previous check, such as a transaction that starts failing after a
+contract upgrade, or when a verified security risk appears that was not
+there before. The first check compares against the result recorded with the
+session.
+
+Watches and alert channels are declared under "monitor" in config.json in
+the erst data directory. Alerts carry deduplication keys per watch and
+change, so a canary that keeps failing the same way raises one incident.
+Each check is recorded in the monitor history in the data directory.
+
+With --storage (or ERST_STORAGE_DSN, or "storage.dsn" in config.json), watch
+schedules are shared through the erst serve database: a monitor with watches
+in config.json saves them there, replacing the stored set, and a monitor
+without any checks the stored watches. Alert channels stay in each
+config.json, since they hold integration keys.
+
+With "monitor.digest" configured, a running monitor also emails a daily or
+weekly digest of failures, drift and new risks; see 'erst monitor digest'.`,
+	Example: `  erst monitor
+  erst monitor --once --watch swap-canary`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := openStorage(cmd.Context(), monitorStorageDSN)
+		if err != nil {
+			return err
+		}
+		if db != nil {
+			defer db.Close()
+		}
+		watches, err := monitorWatches(cmd.Context(), db, monitorWatchFlag)
+		if err != nil {
+			return err
+		}
+		store, err := monitor.NewStore()
+		if err != nil {
+			return err
+		}
+		runner, err := simulator.NewRunner("", false)
+		if err != nil {
+			return fmt.Errorf("failed to initialize simulator: %w", err)
+		}
+		m := &monitor.Monitor{Runner: runner, Source: monitorSource, Store: store}
+
+		if monitorOnceFlag {
+			return checkWatches(cmd.Context(), m, watches)
+		}
+
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if cfg, err := config.LoadConfig(); err == nil && digestConfig(cfg) != nil {
+			go runDigests(ctx, store, digestConfig(cfg))
+		}
+		fmt.Printf("Monitoring %d watch(es); press Ctrl+C to stop\n", len(watches))
+		m.Run(ctx, watches)
+		return nil
+	},
+}
+
+// checkWatches checks each watch once and prints what it found
+func checkWatches(ctx context.Context, m *monitor.Monitor, watches []monitor.Watch) error {
+	failed := 0
+	for _, w := range watches {
+		events, err := m.Check(ctx, w)
+		for _, e := range events {
+			fmt.Printf("%s\t%s\t%s\n", w.Name, e.Kind, e.Summary)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", w.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d watch(es) failed", failed, len(watches))
+	}
+	return nil
+}
+
+// monitorWatches returns the configured watches with their alerters,
+// limited to names when any are given. With db, configured watches are
+// saved there, and the stored ones are used when none are configured.
+func monitorWatches(ctx context.Context, db *storage.DB, names []string) ([]monitor.Watch, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	mc := cfg.Monitor
+	if mc == nil {
+		mc = &config.MonitorConfig{}
+	}
+	configured := mc.Watches
+	if db != nil {
+		if len(configured) > 0 {
+			err = db.ReplaceWatches(ctx, configured)
+		} else {
+			configured, err = db.Watches(ctx)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(configured) == 0 {
+		return nil, fmt.Errorf("no watches configured: add a \"monitor\" object to %s", generalConfigPath())
+	}
+
+	alerters := make(map[string]alert.Alerter)
+	for _, ac := range mc.Alerts {
+		a, err := newAlerter(ac)
+		if err != nil {
+			return nil, err
+		}
+		alerters[ac.Name] = a
+	}
+
+	var watches []monitor.Watch
+	found := make(map[string]bool)
+	for _, wc := range configured {
+		if wc.Name == "" || wc.Session == "" {
+			return nil, fmt.Errorf("every watch needs a name and a session")
+		}
+		if len(names) > 0 && !containsString(names, wc.Name) {
+			continue
+		}
+		found[wc.Name] = true
+		w := monitor.Watch{
+			Name:         wc.Name,
+			Session:      wc.Session,
+			Network:      wc.Network,
+			Interval:     time.Duration(wc.IntervalSeconds) * time.Second,
+			RefreshState: wc.RefreshState,
+		}
+		for _, name := range wc.Alerts {
+			a, ok := alerters[name]
+			if !ok {
+				return nil, fmt.Errorf("watch %s: unknown alert channel %q", wc.Name, name)
+			}
+			w.Alerters = append(w.Alerters, a)
+		}
+		watches = append(watches, w)
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("unknown watch %q", name)
+		}
+	}
+	return watches, nil
+}
+
+// newAlerter returns the alerter of an alert channel
+func newAlerter(ac config.AlertConfig) (alert.Alerter, error) {
+	switch ac.Provider {
+	case "pagerduty":
+		return alert.NewPagerDuty(ac.Name, ac.RoutingKey, ac.Endpoint)
+	case "opsgenie":
+		return alert.NewOpsgenie(ac.Name, ac.APIKey, ac.Endpoint)
+	default:
+		return nil, fmt.Errorf("alert %s: unknown provider %q (use %s)", ac.Name, ac.Provider, strings.Join(alert.Providers, " or "))
+	}
+}
+
+// monitorSource replays the transaction of the watch's session, against
+// the current ledger state when RefreshState is set
+func monitorSource(ctx context.Context, w monitor.Watch) (*simulator.SimulationRequest, *simulator.SimulationResponse, error) {
+	data, err := loadSession(ctx, w.Session)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := data.ToSimulationRequest()
+	if err != nil {
+		return nil, nil, fmt.Errorf("session %s: %w", data.ID, err)
+	}
+	recorded, err := data.ToSimulationResponse()
+	if err != nil {
+		recorded = nil
+	}
+	if !w.RefreshState {
+		return req, recorded, nil
+	}
+
+	network := w.Network
+	if network == "" {
+		network = data.Network
+	}
+	client, err := rpc.NewClient(rpc.WithNetwork(rpc.Network(network)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	keys := make([]string, 0, len(req.LedgerEntries))
+	for k := range req.LedgerEntries {
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		if keys, err = rpc.PredictFootprint(data.EnvelopeXdr); err != nil {
+			return nil, nil, fmt.Errorf("failed to predict footprint: %w", err)
+		}
+	}
+	if req.LedgerEntries, err = client.GetLedgerEntries(ctx, keys); err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+	return req, recorded, nil
+}
+
+func init() {
+	monitorCmd.Flags().BoolVar(&monitorOnceFlag, "once", false, "Check each watch once and exit, for cron jobs")
+	monitorCmd.Flags().StringArrayVar(&monitorWatchFlag, "watch", nil, "Only check this watch (repeatable)")
+	monitorCmd.Flags().StringVar(&monitorStorageDSN, "storage", "", "Database watch schedules are shared in: postgres:// URL or SQLite path (default $ERST_STORAGE_DSN)")
+	rootCmd.AddCommand(monitorCmd)
+}
@@ -0,0 +1,190 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/mail"
+	"github.com/dotandev/hintents/internal/monitor"
+	"github.com/spf13/cobra"
+)
+
+// digestCheckInterval is how often a running monitor checks whether a
+// digest is due
+const digestCheckInterval = time.Hour
+
+var (
+	digestPeriodFlag string
+	digestDryRunFlag bool
+	digestOutputFlag string
+)
+
+var monitorDigestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Email a summary of recent monitor checks",
+	Long: `Send the monitor digest now: the failures, drift and new verified security
+risks found by the checks of the last day or week, as an HTML email rendered
+like erst reports, with a plain text alternative.
+
+The recipients and SMTP server are configured under "monitor.digest" in
+config.json; ERST_SMTP_PASSWORD overrides the stored password. A running
+'erst monitor' sends the digest by itself once per period; this command is
+for sending it from cron instead, or previewing it with --dry-run.`,
+	Example: `  erst monitor digest
+  erst monitor digest --period weekly
+  erst monitor digest --dry-run --output digest.html`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return err
+		}
+		dc := digestConfig(cfg)
+		if dc == nil && !digestDryRunFlag {
+			return fmt.Errorf("no digest configured: add a \"digest\" object under \"monitor\" in %s", generalConfigPath())
+		}
+
+		periodName := digestPeriodFlag
+		if periodName == "" && dc != nil {
+			periodName = dc.Period
+		}
+		period, err := digestPeriod(periodName)
+		if err != nil {
+			return err
+		}
+		store, err := monitor.NewStore()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		msg, err := buildDigestMessage(store, period, now.Add(-period.Duration()), now)
+		if err != nil {
+			return err
+		}
+		if digestOutputFlag != "" {
+			if err := os.WriteFile(digestOutputFlag, []byte(msg.HTML), 0600); err != nil {
+				return fmt.Errorf("failed to write digest: %w", err)
+			}
+			fmt.Printf("Digest written to %s\n", digestOutputFlag)
+		}
+		if digestDryRunFlag {
+			if digestOutputFlag == "" {
+				fmt.Printf("Subject: %s\n\n%s", msg.Subject, msg.Text)
+			}
+			return nil
+		}
+
+		if err := sendDigest(dc, msg); err != nil {
+			return err
+		}
+		if err := store.SetDigestSent(period, now); err != nil {
+			return err
+		}
+		fmt.Printf("Digest sent to %d recipient(s)\n", len(msg.To))
+		return nil
+	},
+}
+
+// digestConfig returns the digest configuration, if there is one
+func digestConfig(cfg *config.Config) *config.DigestConfig {
+	if cfg.Monitor == nil {
+		return nil
+	}
+	return cfg.Monitor.Digest
+}
+
+// digestPeriod parses a configured period, defaulting to daily
+func digestPeriod(name string) (monitor.Period, error) {
+	if name == "" {
+		return monitor.Daily, nil
+	}
+	return monitor.ParsePeriod(name)
+}
+
+// buildDigestMessage renders the digest of the monitor history between
+// from and to
+func buildDigestMessage(store *monitor.Store, period monitor.Period, from, to time.Time) (mail.Message, error) {
+	events, err := store.Events(from)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	d := monitor.BuildDigest(period, events, from, to)
+	html, err := d.Report().ExportHTML()
+	if err != nil {
+		return mail.Message{}, fmt.Errorf("failed to render digest: %w", err)
+	}
+	return mail.Message{Subject: d.Subject(), Text: d.Text(), HTML: string(html)}, nil
+}
+
+// sendDigest emails msg to the configured recipients
+func sendDigest(dc *config.DigestConfig, msg mail.Message) error {
+	if len(dc.To) == 0 {
+		return fmt.Errorf("digest has no recipients: set monitor.digest.to")
+	}
+	sender, err := mail.NewSMTP(mail.Config{
+		Host:     dc.SMTP.Host,
+		Port:     dc.SMTP.Port,
+		Username: dc.SMTP.Username,
+		Password: getenvDefault("ERST_SMTP_PASSWORD", dc.SMTP.Password),
+		From:     dc.SMTP.From,
+	})
+	if err != nil {
+		return err
+	}
+	msg.To = dc.To
+	return sender.Send(msg)
+}
+
+// runDigests sends the configured digest each time a period has passed,
+// until ctx is done
+func runDigests(ctx context.Context, store *monitor.Store, dc *config.DigestConfig) {
+	period, err := digestPeriod(dc.Period)
+	if err != nil {
+		logger.Logger.Warn("Monitor digest disabled", "error", err)
+		return
+	}
+	ticker := time.NewTicker(digestCheckInterval)
+	defer ticker.Stop()
+	for {
+		if err := sendDueDigest(store, dc, period, time.Now()); err != nil {
+			logger.Logger.Warn("Failed to send monitor digest", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendDueDigest sends the digest of period if one is due at now
+func sendDueDigest(store *monitor.Store, dc *config.DigestConfig, period monitor.Period, now time.Time) error {
+	from, due, err := store.DueDigest(period, now)
+	if err != nil || !due {
+		return err
+	}
+	msg, err := buildDigestMessage(store, period, from, now)
+	if err != nil {
+		return err
+	}
+	if err := sendDigest(dc, msg); err != nil {
+		return err
+	}
+	logger.Logger.Info("Sent monitor digest", "period", period, "recipients", len(dc.To))
+	return store.SetDigestSent(period, now)
+}
+
+func init() {
+	monitorDigestCmd.Flags().StringVar(&digestPeriodFlag, "period", "", "Period to summarize: daily or weekly (default: the configured period)")
+	monitorDigestCmd.Flags().BoolVar(&digestDryRunFlag, "dry-run", false, "Print the digest instead of sending it")
+	monitorDigestCmd.Flags().StringVar(&digestOutputFlag, "output", "", "Also write the HTML digest to this file")
+	monitorCmd.AddCommand(monitorDigestCmd)
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/mail"
+	"github.com/dotandev/hintents/internal/monitor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDigestMessage(t *testing.T) {
+	store := &monitor.Store{Dir: t.TempDir()}
+	now := time.Now()
+	require.NoError(t, store.Append(
+		monitor.Event{Time: now.Add(-2 * time.Hour), Watch: "swap", Kind: monitor.KindCheck, Status: "error"},
+		monitor.Event{Time: now.Add(-2 * time.Hour), Watch: "swap", Kind: monitor.KindDrift, Status: "error", Summary: "swap now fails"},
+	))
+
+	msg, err := buildDigestMessage(store, monitor.Daily, now.Add(-24*time.Hour), now)
+	require.NoError(t, err)
+	assert.Contains(t, msg.Subject, "1 failure(s), 1 drift")
+	assert.Contains(t, msg.Text, "swap now fails")
+	assert.Contains(t, msg.HTML, "<html")
+	assert.Contains(t, msg.HTML, "swap now fails")
+}
+
+func TestSendDigestRequiresRecipients(t *testing.T) {
+	err := sendDigest(&config.DigestConfig{SMTP: config.SMTPConfig{Host: "smtp.example.com", From: "erst@example.com"}}, mail.Message{Subject: "digest"})
+	assert.ErrorContains(t, err, "no recipients")
+
+	err = sendDigest(&config.DigestConfig{To: []string{"a@example.com"}}, mail.Message{Subject: "digest"})
+	assert.ErrorContains(t, err, "smtp host is required")
+}
+
+func TestSendDueDigestWaitsForPeriod(t *testing.T) {
+	store := &monitor.Store{Dir: t.TempDir()}
+	dc := &config.DigestConfig{To: []string{"a@example.com"}}
+
+	// The first call only starts the window, so no mail is attempted
+	require.NoError(t, sendDueDigest(store, dc, monitor.Daily, time.Now()))
+	_, ok, err := store.DigestSent(monitor.Daily)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestDigestPeriod(t *testing.T) {
+	p, err := digestPeriod("")
+	require.NoError(t, err)
+	assert.Equal(t, monitor.Daily, p)
+
+	_, err = digestPeriod("monthly")
+	assert.Error(t, err)
+}
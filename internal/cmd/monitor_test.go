@@ -0,0 +1,94 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMonitorConfig(t *testing.T, mc *config.MonitorConfig) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("ERST_HOME", home)
+	data, err := json.Marshal(config.Config{Monitor: mc})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.json"), data, 0600))
+}
+
+func TestMonitorWatches(t *testing.T) {
+	writeMonitorConfig(t, &config.MonitorConfig{
+		Watches: []config.WatchConfig{
+			{Name: "swap", Session: "s1", IntervalSeconds: 300, Alerts: []string{"oncall", "ops"}},
+			{Name: "mint", Session: "s2"},
+		},
+		Alerts: []config.AlertConfig{
+			{Name: "oncall", Provider: "pagerduty", RoutingKey: "key"},
+			{Name: "ops", Provider: "opsgenie", APIKey: "key"},
+		},
+	})
+
+	watches, err := monitorWatches(context.Background(), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, watches, 2)
+	assert.Equal(t, 5*time.Minute, watches[0].Interval)
+	require.Len(t, watches[0].Alerters, 2)
+	assert.Equal(t, "ops", watches[0].Alerters[1].Name())
+
+	watches, err = monitorWatches(context.Background(), nil, []string{"mint"})
+	require.NoError(t, err)
+	require.Len(t, watches, 1)
+	assert.Equal(t, "s2", watches[0].Session)
+
+	_, err = monitorWatches(context.Background(), nil, []string{"burn"})
+	assert.ErrorContains(t, err, `unknown watch "burn"`)
+}
+
+func TestMonitorWatchesShared(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.Open(ctx, storage.Config{DSN: filepath.Join(t.TempDir(), "erst.db")})
+	require.NoError(t, err)
+	defer db.Close()
+
+	writeMonitorConfig(t, &config.MonitorConfig{
+		Watches: []config.WatchConfig{{Name: "swap", Session: "s1", Alerts: []string{"oncall"}}},
+		Alerts:  []config.AlertConfig{{Name: "oncall", Provider: "pagerduty", RoutingKey: "key"}},
+	})
+	_, err = monitorWatches(ctx, db, nil)
+	require.NoError(t, err)
+
+	// A replica without watches of its own checks the shared ones
+	writeMonitorConfig(t, &config.MonitorConfig{Alerts: []config.AlertConfig{{Name: "oncall", Provider: "pagerduty", RoutingKey: "key"}}})
+	watches, err := monitorWatches(ctx, db, nil)
+	require.NoError(t, err)
+	require.Len(t, watches, 1)
+	assert.Equal(t, "s1", watches[0].Session)
+	assert.Len(t, watches[0].Alerters, 1)
+}
+
+func TestMonitorWatchesInvalid(t *testing.T) {
+	writeMonitorConfig(t, nil)
+	_, err := monitorWatches(context.Background(), nil, nil)
+	assert.ErrorContains(t, err, "no watches configured")
+
+	writeMonitorConfig(t, &config.MonitorConfig{Watches: []config.WatchConfig{{Name: "swap", Session: "s1", Alerts: []string{"pager"}}}})
+	_, err = monitorWatches(context.Background(), nil, nil)
+	assert.ErrorContains(t, err, `unknown alert channel "pager"`)
+
+	writeMonitorConfig(t, &config.MonitorConfig{
+		Watches: []config.WatchConfig{{Name: "swap", Session: "s1"}},
+		Alerts:  []config.AlertConfig{{Name: "x", Provider: "sms"}},
+	})
+	_, err = monitorWatches(context.Background(), nil, nil)
+	assert.ErrorContains(t, err, `unknown provider "sms"`)
+}
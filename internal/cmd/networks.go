@@ -0,0 +1,157 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	networksSamplesFlag int
+	networksTimeoutFlag time.Duration
+	networksJSONFlag    bool
+	networksOnlyFlag    []string
+)
+
+var networksCmd = &cobra.Command{
+	Use:   "networks",
+	Short: "Inspect the configured Stellar networks",
+	Long: `Inspect the built-in networks (testnet, mainnet, futurenet) and any custom
+networks saved in networks.json.
+
+Available subcommands:
+  status - Probe each network's endpoints for freshness and latency`,
+}
+
+var networksStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Probe the Horizon and Soroban RPC endpoints of each network",
+	Long: `Probe the Horizon and Soroban RPC endpoints of every configured network and
+print the latest ledger each reports, how far that ledger trails the wall
+clock, and the median (p50) request latency. Use it to pick a healthy endpoint
+before starting a long batch job. Lag is shown as "-" for Soroban RPC servers
+that do not report ledger close times.`,
+	Example: `  erst networks status
+  erst networks status --network testnet --samples 5
+  erst networks status --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		networks, err := configuredNetworks(networksOnlyFlag)
+		if err != nil {
+			return err
+		}
+
+		hc := &http.Client{Timeout: networksTimeoutFlag}
+		results := make([][]rpc.EndpointStatus, len(networks))
+		var wg sync.WaitGroup
+		for i, n := range networks {
+			wg.Add(1)
+			go func(i int, n rpc.NetworkConfig) {
+				defer wg.Done()
+				results[i] = rpc.ProbeNetwork(cmd.Context(), hc, n, networksSamplesFlag)
+			}(i, n)
+		}
+		wg.Wait()
+
+		statuses := make([]rpc.EndpointStatus, 0, 2*len(networks))
+		for _, r := range results {
+			statuses = append(statuses, r...)
+		}
+
+		if networksJSONFlag {
+			return writeJSON(cmd, statuses)
+		}
+		printNetworkStatus(statuses)
+		return nil
+	},
+}
+
+// configuredNetworks returns the built-in networks followed by custom ones
+// sorted by name, limited to only when it is non-empty
+func configuredNetworks(only []string) ([]rpc.NetworkConfig, error) {
+	networks := []rpc.NetworkConfig{rpc.TestnetConfig, rpc.MainnetConfig, rpc.FuturenetConfig}
+
+	custom, err := config.LoadCustomNetworks()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(custom.Networks))
+	for name := range custom.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		n := custom.Networks[name]
+		n.Name = name
+		networks = append(networks, n)
+	}
+
+	if len(only) == 0 {
+		return networks, nil
+	}
+	var out []rpc.NetworkConfig
+	for _, name := range only {
+		found := false
+		for _, n := range networks {
+			if n.Name == name {
+				out = append(out, n)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown network %q", name)
+		}
+	}
+	return out, nil
+}
+
+func printNetworkStatus(statuses []rpc.EndpointStatus) {
+	table := visualizer.NewTable("NETWORK", "ENDPOINT", "LEDGER", "LAG", "P50", "STATUS", "URL").AlignRight(3, 4)
+	for _, s := range statuses {
+		ledger, lag, p50 := "-", "-", "-"
+		if s.LatestLedger > 0 {
+			ledger = fmt.Sprintf("%d", s.LatestLedger)
+		}
+		if !s.ClosedAt.IsZero() {
+			lag = s.Lag.Round(time.Second).String()
+		}
+		if s.P50 > 0 {
+			p50 = s.P50.Round(time.Millisecond).String()
+		}
+
+		state := "ok"
+		switch {
+		case s.Error != "":
+			state = "down"
+		case s.Failed > 0:
+			state = fmt.Sprintf("%d/%d", s.Samples-s.Failed, s.Samples)
+		}
+		table.AddRow(s.Network, s.Kind, ledger, lag, p50, state, s.URL)
+		if s.Error != "" {
+			table.AddNote(s.Error)
+		}
+	}
+	table.Render(os.Stdout)
+}
+
+func init() {
+	networksStatusCmd.Flags().IntVar(&networksSamplesFlag, "samples", 3, "Requests per endpoint used to measure latency")
+	networksStatusCmd.Flags().DurationVar(&networksTimeoutFlag, "timeout", 10*time.Second, "Timeout for each request")
+	networksStatusCmd.Flags().BoolVar(&networksJSONFlag, "json", false, "Output as JSON")
+	networksStatusCmd.Flags().StringArrayVarP(&networksOnlyFlag, "network", "n", nil, "Only probe this network (repeatable)")
+	supportPorcelain(networksStatusCmd)
+	networksCmd.AddCommand(networksStatusCmd)
+	rootCmd.AddCommand(networksCmd)
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+)
+
+// dataAvailability records which parts of a fetched transaction are missing,
+// as some RPC providers omit result or meta XDR, and which analyses were
+// skipped because of it
+type dataAvailability struct {
+	HasResult bool     `json:"has_result"`
+	HasMeta   bool     `json:"has_meta"`
+	Missing   []string `json:"missing,omitempty"`
+	Skipped   []string `json:"skipped,omitempty"`
+	Notes     []string `json:"notes,omitempty"`
+}
+
+func checkAvailability(resp *rpc.TransactionResponse) *dataAvailability {
+	a := &dataAvailability{HasResult: resp.ResultXdr != "", HasMeta: resp.ResultMetaXdr != ""}
+	if !a.HasResult {
+		a.Missing = append(a.Missing, "result XDR")
+	}
+	if !a.HasMeta {
+		a.Missing = append(a.Missing, "result meta XDR")
+	}
+	return a
+}
+
+// Partial reports whether any transaction data was missing
+func (a *dataAvailability) Partial() bool {
+	return len(a.Missing) > 0
+}
+
+func (a *dataAvailability) skip(analysis, needs string) {
+	a.Skipped = append(a.Skipped, fmt.Sprintf("%s (needs %s)", analysis, needs))
+}
+
+func (a *dataAvailability) note(format string, args ...interface{}) {
+	a.Notes = append(a.Notes, fmt.Sprintf(format, args...))
+}
+
+func (a *dataAvailability) printWarning() {
+	if !a.Partial() {
+		return
+	}
+	fmt.Printf("%s Transaction data is incomplete: missing %s. Continuing with reduced analysis.\n",
+		visualizer.Warning(), strings.Join(a.Missing, " and "))
+}
+
+func (a *dataAvailability) printSummary() {
+	if !a.Partial() {
+		return
+	}
+	fmt.Printf("\n%s\n", visualizer.Heading("Partial Data"))
+	fmt.Printf("Missing from the RPC response: %s\n", strings.Join(a.Missing, ", "))
+	for _, n := range a.Notes {
+		fmt.Printf("  - %s\n", n)
+	}
+	if len(a.Skipped) > 0 {
+		fmt.Printf("Skipped analyses:\n")
+		for _, s := range a.Skipped {
+			fmt.Printf("  - %s\n", s)
+		}
+	}
+	ideEvents.Result("partial_data", a)
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
+	"github.com/dotandev/hintents/internal/plugin"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/spf13/cobra"
+)
+
+// reservedPluginNames are erst-* programs that are not plugins
+var reservedPluginNames = map[string]bool{
+	"sim": true, // the simulator
+}
+
+// ExitError is returned when a plugin exits non-zero. The plugin has
+// reported its own error, so only the exit code is passed on.
+type ExitError struct {
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+var pluginCmd = &cobra.Command{
+	Use:   "plugin",
+	Short: "List executable plugins",
+	Long: `Plugins add subcommands to erst without changing it. Any executable named
+erst-<name> on PATH runs as 'erst <name>', with the remaining arguments
+passed through, like kubectl and git plugins. Built-in commands take
+precedence over plugins of the same name.
+
+A plugin receives its context as JSON in ERST_PLUGIN_CONTEXT: the erst
+version and binary, the data directory and configuration file, the network
+and RPC URL, the requested --api-version and, when there is a current
+session, its ID and a JSON copy of it in a temporary file. The main fields
+are also set as ERST_BIN, ERST_DATA_DIR, ERST_CONFIG, ERST_NETWORK,
+ERST_RPC_URL, ERST_API_VERSION, ERST_SESSION_ID and ERST_SESSION_FILE.`,
+}
+
+var pluginListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the plugins found on PATH",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		plugins := plugin.DiscoverExecutables(os.Getenv("PATH"))
+		shown := 0
+		for _, p := range plugins {
+			switch {
+			case reservedPluginNames[p.Name]:
+				continue
+			case isBuiltinCommand(p.Name):
+				fmt.Printf("%s\t%s  (shadowed by the built-in command)\n", p.Name, p.Path)
+			default:
+				fmt.Printf("%s\t%s\n", p.Name, p.Path)
+			}
+			shown++
+		}
+		if shown == 0 {
+			fmt.Println("No plugins found. Plugins are executables named erst-<name> on PATH.")
+		}
+		return nil
+	},
+}
+
+// runPlugin runs the plugin named by the first argument, if there is one.
+// It reports whether args named a plugin.
+func runPlugin(ctx context.Context, args []string) (bool, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(args[0]) || reservedPluginNames[args[0]] {
+		return false, nil
+	}
+	p, ok := plugin.FindExecutable(args[0], os.Getenv("PATH"))
+	if !ok {
+		return false, nil
+	}
+
+	pc, cleanup, err := pluginContext(ctx)
+	if err != nil {
+		return true, err
+	}
+	defer cleanup()
+
+	logger.Logger.Debug("Running plugin", "plugin", p.Name, "path", p.Path)
+	err = plugin.RunExecutable(ctx, p, args[1:], pc, os.Stdin, os.Stdout, os.Stderr)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return true, &ExitError{Code: exitErr.ExitCode()}
+	}
+	return true, err
+}
+
+// pluginContext describes erst's environment for a plugin. The returned
+// function removes the temporary session file.
+func pluginContext(ctx context.Context) (plugin.ExecContext, func(), error) {
+	cleanup := func() {}
+	version, err := resolveAPIVersion()
+	if err != nil {
+		return plugin.ExecContext{}, cleanup, err
+	}
+	pc := plugin.ExecContext{
+		ContextVersion: plugin.ContextVersion,
+		APIVersion:     version,
+		ErstVersion:    Version,
+	}
+	if exe, err := os.Executable(); err == nil {
+		pc.ErstPath = exe
+	}
+	if dir, err := platform.DataDir(); err == nil {
+		pc.DataDir = dir
+	}
+	if path, err := config.GetGeneralConfigPath(); err == nil {
+		pc.ConfigPath = path
+	}
+	if cfg, err := config.Load(); err == nil {
+		pc.Network = string(cfg.Network)
+		pc.RPCURL = cfg.RpcUrl
+	}
+
+	id := sessions.CurrentID()
+	if id == "" {
+		return pc, cleanup, nil
+	}
+	pc.SessionID = id
+	data, err := loadSession(ctx, id)
+	if err != nil {
+		logger.Logger.Warn("The plugin will not get the current session", "session", id, "error", err)
+		return pc, cleanup, nil
+	}
+	path, err := writeSessionFile(data)
+	if err != nil {
+		return pc, cleanup, err
+	}
+	pc.SessionFile = path
+	return pc, func() { os.RemoveAll(filepath.Dir(path)) }, nil
+}
+
+// writeSessionFile writes data as JSON to a new temporary directory
+func writeSessionFile(data *session.SessionData) (string, error) {
+	dir, err := os.MkdirTemp("", "erst-plugin-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create session file: %w", err)
+	}
+	raw, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to encode session: %w", err)
+	}
+	path := filepath.Join(dir, "session.json")
+	if err := os.WriteFile(path, raw, 0600); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to write session file: %w", err)
+	}
+	return path, nil
+}
+
+func init() {
+	pluginCmd.AddCommand(pluginListCmd)
+	rootCmd.AddCommand(pluginCmd)
+}
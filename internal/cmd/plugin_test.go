@@ -0,0 +1,55 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugin")
+	}
+	home, bin := t.TempDir(), t.TempDir()
+	t.Setenv("ERST_HOME", home)
+	t.Setenv("PATH", bin)
+	out := filepath.Join(t.TempDir(), "context.json")
+	script := "#!/bin/sh\nprintf '%s' \"$ERST_PLUGIN_CONTEXT\" > " + out + "\nexit $1\n"
+	for _, name := range []string{"erst-hello", "erst-debug", "erst-sim"} {
+		require.NoError(t, os.WriteFile(filepath.Join(bin, name), []byte(script), 0755))
+	}
+
+	ran, err := runPlugin(context.Background(), []string{"hello", "0"})
+	assert.True(t, ran)
+	require.NoError(t, err)
+
+	var pc plugin.ExecContext
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &pc))
+	assert.Equal(t, plugin.ContextVersion, pc.ContextVersion)
+	assert.Equal(t, currentAPIVersion, pc.APIVersion)
+	assert.Equal(t, home, pc.DataDir)
+
+	ran, err = runPlugin(context.Background(), []string{"hello", "4"})
+	assert.True(t, ran)
+	var exitErr *ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 4, exitErr.Code)
+
+	for _, args := range [][]string{{"debug"}, {"sim"}, {"missing"}, {"--help"}, nil} {
+		ran, err = runPlugin(context.Background(), args)
+		assert.False(t, ran, args)
+		assert.NoError(t, err, args)
+	}
+}
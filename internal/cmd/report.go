@@ -62,51 +62,7 @@ func reportExec(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to parse trace: %w", err)
 	}
 
-	builder := report.NewBuilder("Execution Trace Report")
-	builder.WithTransactionHash(executionTrace.TransactionHash)
-
-	// Build summary
-	totalSteps := len(executionTrace.States)
-	errorCount := countErrors(executionTrace.States)
-	successRate := calculateSuccessRate(executionTrace.States)
-
-	duration := executionTrace.EndTime.Sub(executionTrace.StartTime).String()
-	builder.SetSummary("success", duration, totalSteps, errorCount, countContracts(executionTrace.States), successRate)
-
-	// Add execution steps
-	for i, state := range executionTrace.States {
-		op := state.Operation
-		if state.ContractID != "" && state.Function != "" {
-			op = state.ContractID + "::" + state.Function
-		}
-
-		status := "success"
-		if state.Error != "" {
-			status = "error"
-		}
-
-		builder.AddExecutionStep(i, op, status, state.Error)
-	}
-
-	// Analyze for findings
-	if errorCount > 0 {
-		builder.AddKeyFinding(fmt.Sprintf("%d errors detected during execution", errorCount))
-	}
-
-	contractCount := countContracts(executionTrace.States)
-	builder.AddKeyFinding(fmt.Sprintf("%d unique contracts called", contractCount))
-
-	// Risk assessment
-	riskLevel := assessRisk(executionTrace.States)
-	builder.SetRiskAssessment(riskLevel, calculateRiskScore(executionTrace.States))
-
-	// Metadata
-	builder.SetMetadata("execution_trace", "1.0.0", map[string]string{
-		"generated_by": "erst",
-		"timestamp":    time.Now().Format(time.RFC3339),
-	})
-
-	generatedReport := builder.Build()
+	generatedReport := buildTraceReport(executionTrace).Build()
 
 	exporter, err := report.NewExporter(reportOutput)
 	if err != nil {
@@ -154,6 +110,55 @@ func reportExec(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// buildTraceReport summarizes an execution trace as a report
+func buildTraceReport(executionTrace *trace.ExecutionTrace) *report.Builder {
+	builder := report.NewBuilder("Execution Trace Report")
+	builder.WithTransactionHash(executionTrace.TransactionHash)
+
+	// Build summary
+	totalSteps := len(executionTrace.States)
+	errorCount := countErrors(executionTrace.States)
+	successRate := calculateSuccessRate(executionTrace.States)
+
+	duration := executionTrace.EndTime.Sub(executionTrace.StartTime).String()
+	builder.SetSummary("success", duration, totalSteps, errorCount, countContracts(executionTrace.States), successRate)
+
+	// Add execution steps
+	for i, state := range executionTrace.States {
+		op := state.Operation
+		if state.ContractID != "" && state.Function != "" {
+			op = state.ContractID + "::" + state.Function
+		}
+
+		status := "success"
+		if state.Error != "" {
+			status = "error"
+		}
+
+		builder.AddExecutionStep(i, op, status, state.Error)
+	}
+
+	// Analyze for findings
+	if errorCount > 0 {
+		builder.AddKeyFinding(fmt.Sprintf("%d errors detected during execution", errorCount))
+	}
+
+	contractCount := countContracts(executionTrace.States)
+	builder.AddKeyFinding(fmt.Sprintf("%d unique contracts called", contractCount))
+
+	// Risk assessment
+	riskLevel := assessRisk(executionTrace.States)
+	builder.SetRiskAssessment(riskLevel, calculateRiskScore(executionTrace.States))
+
+	// Metadata
+	builder.SetMetadata("execution_trace", "1.0.0", map[string]string{
+		"generated_by": "erst",
+		"timestamp":    time.Now().Format(time.RFC3339),
+	})
+
+	return builder
+}
+
 func countErrors(states []trace.ExecutionState) int {
 	count := 0
 	for _, state := range states {
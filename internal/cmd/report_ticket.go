@@ -0,0 +1,248 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/explain"
+	"github.com/dotandev/hintents/internal/report"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/dotandev/hintents/internal/ticket"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ticketProviderFlag   string
+	ticketCheckpointFlag string
+	ticketTitleFlag      string
+	ticketLabelFlag      []string
+	ticketDryRunFlag     bool
+	ticketJSONFlag       bool
+)
+
+var reportTicketCmd = &cobra.Command{
+	Use:   "ticket [session-id]",
+	Short: "File an issue in Jira or Linear from a session's diagnosis",
+	Long: `Create an issue in Jira or Linear about the transaction of a saved session,
+or the current one. The issue describes the failure, the explanation and the
+security findings, and has the HTML report and a snapshot of the ledger
+entries the transaction touches attached, from which it can be replayed
+with 'erst debug --snapshot'.
+
+Credentials are read from the "jira" or "linear" object of config.json in the
+erst data directory; ERST_JIRA_API_TOKEN and ERST_LINEAR_API_KEY override the
+stored token. --dry-run prints the issue without creating it.`,
+	Example: `  erst report ticket abc123 --provider jira
+  erst report ticket --provider linear --title "Swap fails on testnet"
+  erst report ticket abc123 --provider jira --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id := ""
+		if len(args) == 1 {
+			id = args[0]
+		}
+		a, err := loadSessionAnalysis(cmd.Context(), id, ticketCheckpointFlag)
+		if err != nil {
+			return err
+		}
+
+		var provider ticket.Provider
+		var labels []string
+		if !ticketDryRunFlag {
+			if provider, labels, err = ticketProvider(ticketProviderFlag); err != nil {
+				return err
+			}
+		} else if !containsString(ticket.Providers, ticketProviderFlag) {
+			return fmt.Errorf("unknown provider %q (use %s)", ticketProviderFlag, strings.Join(ticket.Providers, " or "))
+		}
+
+		issue := buildTicketIssue(a, ticketTitleFlag)
+		issue.Labels = append(labels, ticketLabelFlag...)
+		attachments, err := ticketAttachments(a)
+		if err != nil {
+			return err
+		}
+
+		if ticketDryRunFlag {
+			fmt.Printf("Title: %s\n\n%s\n\nAttachments:\n", issue.Title, issue.Markdown())
+			for _, att := range attachments {
+				fmt.Printf("  %s (%d bytes)\n", att.Name, len(att.Data))
+			}
+			return nil
+		}
+
+		created, err := provider.Create(cmd.Context(), issue, attachments)
+		if err != nil {
+			return err
+		}
+		if ticketJSONFlag {
+			return writeJSON(cmd, created)
+		}
+		fmt.Printf("Created %s issue %s: %s\n", provider.Name(), created.Key, created.URL)
+		return nil
+	},
+}
+
+// ticketProvider returns the named provider configured in config.json and
+// the labels to apply
+func ticketProvider(name string) (ticket.Provider, []string, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	switch name {
+	case "jira":
+		if cfg.Jira == nil {
+			return nil, nil, fmt.Errorf("jira is not configured: add a \"jira\" object to %s", generalConfigPath())
+		}
+		c := ticket.JiraConfig{
+			URL:       cfg.Jira.URL,
+			Email:     cfg.Jira.Email,
+			APIToken:  getenvDefault("ERST_JIRA_API_TOKEN", cfg.Jira.APIToken),
+			Project:   cfg.Jira.Project,
+			IssueType: cfg.Jira.IssueType,
+		}
+		p, err := ticket.NewJira(c)
+		return p, cfg.Jira.Labels, err
+	case "linear":
+		if cfg.Linear == nil {
+			return nil, nil, fmt.Errorf("linear is not configured: add a \"linear\" object to %s", generalConfigPath())
+		}
+		c := ticket.LinearConfig{
+			APIKey:   getenvDefault("ERST_LINEAR_API_KEY", cfg.Linear.APIKey),
+			TeamID:   cfg.Linear.TeamID,
+			LabelIDs: cfg.Linear.LabelIDs,
+		}
+		p, err := ticket.NewLinear(c)
+		return p, nil, err
+	default:
+		return nil, nil, fmt.Errorf("unknown provider %q (use %s)", name, strings.Join(ticket.Providers, " or "))
+	}
+}
+
+func generalConfigPath() string {
+	path, err := config.GetGeneralConfigPath()
+	if err != nil {
+		return "config.json"
+	}
+	return path
+}
+
+func getenvDefault(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// buildTicketIssue describes the session's failure, its explanation and
+// security findings
+func buildTicketIssue(a *sessionAnalysis, title string) ticket.Issue {
+	data, sim := a.Session, a.Simulation
+	if title == "" {
+		title = fmt.Sprintf("Transaction %s failed on %s", shortHash(data.TxHash), data.Network)
+		if sim.Status == "success" {
+			title = fmt.Sprintf("Transaction %s on %s", shortHash(data.TxHash), data.Network)
+		}
+		if sim.Error != "" {
+			title += ": " + firstLine(sim.Error, 120)
+		}
+	}
+
+	summary := ticket.Section{Heading: "Summary", Bullets: []string{
+		"Transaction: " + data.TxHash,
+		"Network: " + data.Network,
+		"Session: " + data.ID + " (" + a.Source + ")",
+		"Simulation status: " + sim.Status,
+	}}
+	if sim.Error != "" {
+		summary.Bullets = append(summary.Bullets, "Error: "+firstLine(sim.Error, 500))
+	}
+	sections := []ticket.Section{summary}
+
+	findings := security.NewDetector().AnalyzeSimulation(data.EnvelopeXdr, data.ResultMetaXdr, sim)
+	paragraphs := explain.Explain(explain.Input{
+		EnvelopeXdr:   data.EnvelopeXdr,
+		ResultXdr:     data.ResultXdr,
+		ResultMetaXdr: data.ResultMetaXdr,
+		Simulation:    sim,
+		Findings:      findings,
+
+		NetworkPassphrase: a.passphrase(),
+	})
+	if len(paragraphs) > 0 {
+		sections = append(sections, ticket.Section{Heading: "Diagnosis", Paragraphs: paragraphs})
+	}
+	if len(findings) > 0 {
+		s := ticket.Section{Heading: "Security findings"}
+		for _, f := range findings {
+			s.Bullets = append(s.Bullets, fmt.Sprintf("[%s] %s: %s", f.Severity, f.Title, firstLine(f.Description, 200)))
+		}
+		sections = append(sections, s)
+	}
+	sections = append(sections, ticket.Section{Heading: "Reproduce", Paragraphs: []string{
+		fmt.Sprintf("erst debug %s --network %s --snapshot %s", data.TxHash, data.Network, data.ID+".snapshot.json"),
+	}})
+	return ticket.Issue{Title: title, Sections: sections}
+}
+
+// ticketAttachments returns the HTML report and the snapshot of the
+// session
+func ticketAttachments(a *sessionAnalysis) ([]ticket.Attachment, error) {
+	entries, err := sessionLedgerEntries(a.Session, ticketCheckpointFlag)
+	if err != nil {
+		return nil, err
+	}
+	tr := buildExecutionTrace(a.Session.TxHash, a.transaction(), a.Simulation, entries)
+	html, err := report.NewHTMLRenderer().Render(buildTraceReport(tr).Build())
+	if err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	snap, err := json.MarshalIndent(snapshot.FromMap(minimizeEntries(a.Session.EnvelopeXdr, entries)), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	return []ticket.Attachment{
+		{Name: a.Session.ID + ".report.html", ContentType: "text/html", Data: html},
+		{Name: a.Session.ID + ".snapshot.json", ContentType: "application/json", Data: snap},
+	}, nil
+}
+
+// minimizeEntries keeps the entries in the footprint of the envelope:
+// those its accounts and Soroban footprint name. All entries are kept when
+// the footprint cannot be predicted or names none of them.
+func minimizeEntries(envelopeXdr string, entries map[string]string) map[string]string {
+	keys, err := rpc.PredictFootprint(envelopeXdr)
+	if err != nil {
+		return entries
+	}
+	kept := make(map[string]string)
+	for _, k := range keys {
+		if v, ok := entries[k]; ok {
+			kept[k] = v
+		}
+	}
+	if len(kept) == 0 {
+		return entries
+	}
+	return kept
+}
+
+func init() {
+	reportTicketCmd.Flags().StringVar(&ticketProviderFlag, "provider", "", "Issue tracker: jira or linear")
+	reportTicketCmd.Flags().StringVar(&ticketCheckpointFlag, "checkpoint", "", "Use this checkpoint instead of the latest run")
+	reportTicketCmd.Flags().StringVar(&ticketTitleFlag, "title", "", "Issue title (default: derived from the failure)")
+	reportTicketCmd.Flags().StringArrayVar(&ticketLabelFlag, "label", nil, "Label to add to the Jira issue (repeatable)")
+	reportTicketCmd.Flags().BoolVar(&ticketDryRunFlag, "dry-run", false, "Print the issue instead of creating it")
+	reportTicketCmd.Flags().BoolVar(&ticketJSONFlag, "json", false, "Print the created issue as JSON")
+	_ = reportTicketCmd.MarkFlagRequired("provider")
+	reportCmd.AddCommand(reportTicketCmd)
+}
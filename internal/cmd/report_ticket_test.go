@@ -0,0 +1,63 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportTicket(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("ERST_HOME", home)
+	saved := sessions
+	sessions = session.NewManager("")
+	t.Cleanup(func() { sessions = saved })
+
+	data := &session.SessionData{Network: "testnet", TxHash: "abcdef0123456789"}
+	require.NoError(t, data.AddRun(session.Run{
+		Name:            session.DefaultRunName,
+		SimRequestJSON:  `{"envelope_xdr":"","result_meta_xdr":"","ledger_entries":{"k1":"v1"}}`,
+		SimResponseJSON: `{"status":"error","error":"HostError: Error(Contract, #3)","logs":["attempt to add with overflow"]}`,
+	}))
+	_, err := recordCheckpoint(context.Background(), "s1", data)
+	require.NoError(t, err)
+
+	a, err := loadSessionAnalysis(context.Background(), "s1", "")
+	require.NoError(t, err)
+	issue := buildTicketIssue(a, "")
+	assert.Equal(t, "Transaction abcdef...6789 failed on testnet: HostError: Error(Contract, #3)", issue.Title)
+	md := issue.Markdown()
+	assert.Contains(t, md, "- Session: s1 (latest run)")
+	assert.Contains(t, md, "### Reproduce")
+
+	attachments, err := ticketAttachments(a)
+	require.NoError(t, err)
+	require.Len(t, attachments, 2)
+	assert.Equal(t, "s1.report.html", attachments[0].Name)
+	assert.Contains(t, string(attachments[1].Data), `"k1"`)
+
+	_, _, err = ticketProvider("jira")
+	assert.ErrorContains(t, err, "jira is not configured")
+	_, _, err = ticketProvider("github")
+	assert.ErrorContains(t, err, "unknown provider")
+
+	raw, err := json.Marshal(config.Config{Linear: &config.LinearConfig{TeamID: "team"}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.json"), raw, 0600))
+	_, _, err = ticketProvider("linear")
+	assert.ErrorContains(t, err, "API key is required")
+	t.Setenv("ERST_LINEAR_API_KEY", "lin_api_key")
+	p, _, err := ticketProvider("linear")
+	require.NoError(t, err)
+	assert.Equal(t, "linear", p.Name())
+}
@@ -4,17 +4,86 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/idejson"
 	"github.com/dotandev/hintents/internal/localization"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/visualizer"
 	"github.com/spf13/cobra"
 )
 
 // Global flag variables
 var (
-	TimestampFlag int64
-	WindowFlag    int64
-	ProfileFlag   bool
+	TimestampFlag  int64
+	WindowFlag     int64
+	ProfileFlag    bool
+	IDEJSONFlag    bool
+	AccessibleFlag bool
+	QuietFlag      bool
+	PorcelainFlag  bool
 )
 
+// commandStarted is when the running command passed flag parsing, used to
+// time it for usage reporting
+var commandStarted time.Time
+
+// ideEvents receives machine-readable events when --ide-json is set. It is
+// nil otherwise, and all its methods are no-ops on nil.
+var ideEvents *idejson.Emitter
+
+// porcelainAnnotation marks commands that write their results as porcelain
+// records; the others reject --porcelain
+const porcelainAnnotation = "erst/porcelain"
+
+// supportPorcelain marks cmds as writing their results as porcelain records
+func supportPorcelain(cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[porcelainAnnotation] = "true"
+	}
+}
+
+// ideJSONAnnotation marks commands that report their progress and results
+// as --ide-json events; the others reject --ide-json
+const ideJSONAnnotation = "erst/ide-json"
+
+// supportIDEJSON marks cmds as emitting --ide-json events
+func supportIDEJSON(cmds ...*cobra.Command) {
+	for _, c := range cmds {
+		if c.Annotations == nil {
+			c.Annotations = map[string]string{}
+		}
+		c.Annotations[ideJSONAnnotation] = "true"
+	}
+}
+
+// outputModes returns whether cmd writes porcelain records and runs quietly.
+// In CI, when neither --porcelain nor --quiet was given, commands default
+// to quiet and those supporting porcelain to porcelain, unless --json or
+// --ide-json already chose the output.
+func outputModes(cmd *cobra.Command, ci string) (porcelain, quiet bool) {
+	porcelain, quiet = PorcelainFlag, QuietFlag
+	if ci == "" || flagChanged(cmd, "porcelain") || flagChanged(cmd, "quiet") {
+		return porcelain, quiet
+	}
+	porcelain = cmd.Annotations[porcelainAnnotation] != "" && !IDEJSONFlag && !flagChanged(cmd, "json")
+	return porcelain, true
+}
+
+func flagChanged(cmd *cobra.Command, name string) bool {
+	f := cmd.Flags().Lookup(name)
+	return f != nil && f.Changed
+}
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "erst",
@@ -39,6 +108,51 @@ Examples:
 
 Get started with 'erst debug --help' or visit the documentation.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		commandStarted = time.Now()
+		commandArgs = args
+		if AccessibleFlag {
+			visualizer.SetAccessible(true)
+		}
+		ci := platform.DetectCI(os.Getenv)
+		porcelain, quiet := outputModes(cmd, ci)
+		if porcelain {
+			if cmd.Annotations[porcelainAnnotation] == "" {
+				return fmt.Errorf("%s does not support --porcelain", cmd.CommandPath())
+			}
+			if IDEJSONFlag {
+				return fmt.Errorf("--porcelain and --ide-json cannot be used together")
+			}
+			if flagChanged(cmd, "json") {
+				return fmt.Errorf("--porcelain and --json cannot be used together")
+			}
+			// Reserve stdout for porcelain records; human-oriented output
+			// moves to stderr
+			visualizer.SetPorcelain(os.Stdout)
+			os.Stdout = os.Stderr
+		}
+		if quiet {
+			visualizer.SetQuiet(true)
+		}
+		if err := checkAPIVersion(); err != nil {
+			return err
+		}
+		if ci != "" {
+			logger.Logger.Debug("CI environment detected; prompts and spinners are disabled", "ci", ci, "porcelain", porcelain)
+		}
+		if visualizer.Quiet() && logger.GetLevel() < slog.LevelWarn {
+			logger.SetLevel(slog.LevelWarn)
+		}
+		if IDEJSONFlag {
+			if cmd.Annotations[ideJSONAnnotation] == "" {
+				return fmt.Errorf("%s does not support --ide-json", cmd.CommandPath())
+			}
+			// Reserve stdout for the event stream; human-oriented output
+			// moves to stderr
+			ideEvents = idejson.New(os.Stdout, cmd.CommandPath())
+			os.Stdout = os.Stderr
+			logger.Logger = slog.New(idejson.LogHandler(logger.Logger.Handler(), ideEvents))
+			ideEvents.Start(args)
+		}
 		return localization.LoadTranslations()
 	},
 	SilenceUsage:  true,
@@ -47,8 +161,26 @@ Get started with 'erst debug --help' or visit the documentation.`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute() error {
-	return rootCmd.Execute()
+// Commands track their sessions in m.
+func Execute(m *session.Manager) error {
+	sessions = m
+	args, err := commandLineArgs()
+	if err != nil {
+		return err
+	}
+	if ran, err := runPlugin(context.Background(), args); ran {
+		return err
+	}
+	rootCmd.SetArgs(args)
+	cmd, err := rootCmd.ExecuteC()
+	recordUsage(cmd, commandStarted, err)
+	recordAudit(cmd, commandArgs, commandStarted, err)
+	if err != nil {
+		ideEvents.Error(err)
+	} else {
+		ideEvents.End()
+	}
+	return err
 }
 
 func init() {
@@ -74,5 +206,34 @@ func init() {
 		"Enable CPU/Memory profiling and generate a flamegraph SVG",
 	)
 
+	rootCmd.PersistentFlags().BoolVar(
+		&IDEJSONFlag,
+		"ide-json",
+		false,
+		"Emit newline-delimited JSON events on stdout for editor integrations (erst debug)",
+	)
+
+	rootCmd.PersistentFlags().BoolVarP(
+		&QuietFlag,
+		"quiet",
+		"q",
+		false,
+		"Suppress spinners, progress messages and info logs",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&PorcelainFlag,
+		"porcelain",
+		false,
+		"Stable tab-separated output on stdout for scripts, on commands that support it; implies --quiet",
+	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&AccessibleFlag,
+		"accessible",
+		false,
+		"Screen-reader friendly output: no color, box drawing or wide tables (also ERST_ACCESSIBLE=1)",
+	)
+
 	// Register commands
 }
@@ -0,0 +1,72 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPorcelainRejectedWhereUnsupported(t *testing.T) {
+	t.Setenv("CI", "false")
+	PorcelainFlag = true
+	defer func() { PorcelainFlag = false }()
+
+	err := rootCmd.PersistentPreRunE(cacheClearCmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support --porcelain")
+
+	for _, c := range []*cobra.Command{debugCmd, sessionListCmd, cacheStatusCmd, networksStatusCmd} {
+		assert.NotEmpty(t, c.Annotations[porcelainAnnotation], c.CommandPath())
+	}
+}
+
+func TestIDEJSONRejectedWhereUnsupported(t *testing.T) {
+	t.Setenv("CI", "false")
+	IDEJSONFlag = true
+	defer func() { IDEJSONFlag = false }()
+
+	err := rootCmd.PersistentPreRunE(sessionListCmd, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support --ide-json")
+	assert.Nil(t, ideEvents)
+	assert.NotEmpty(t, debugCmd.Annotations[ideJSONAnnotation])
+}
+
+func TestOutputModesInCI(t *testing.T) {
+	newCmd := func(porcelain bool, args ...string) *cobra.Command {
+		c := &cobra.Command{Use: "x"}
+		c.Flags().Bool("porcelain", false, "")
+		c.Flags().Bool("quiet", false, "")
+		c.Flags().Bool("json", false, "")
+		if porcelain {
+			supportPorcelain(c)
+		}
+		require.NoError(t, c.ParseFlags(args))
+		return c
+	}
+
+	porcelain, quiet := outputModes(newCmd(true), "")
+	assert.False(t, porcelain)
+	assert.False(t, quiet, "nothing changes outside CI")
+
+	porcelain, quiet = outputModes(newCmd(true), "GitHub Actions")
+	assert.True(t, porcelain, "commands that support it default to porcelain")
+	assert.True(t, quiet)
+
+	porcelain, quiet = outputModes(newCmd(false), "GitHub Actions")
+	assert.False(t, porcelain, "other commands would reject porcelain")
+	assert.True(t, quiet)
+
+	porcelain, quiet = outputModes(newCmd(true, "--json"), "GitHub Actions")
+	assert.False(t, porcelain, "--json already chose the output")
+	assert.True(t, quiet)
+
+	porcelain, quiet = outputModes(newCmd(true, "--quiet=false"), "GitHub Actions")
+	assert.False(t, porcelain, "an explicit flag turns the defaults off")
+	assert.False(t, quiet)
+}
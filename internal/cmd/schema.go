@@ -0,0 +1,97 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dotandev/hintents/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print and validate against the JSON Schemas of erst's payloads",
+	Long: `erst publishes JSON Schemas, generated from its Go types, for the payloads it
+exchanges with other tools:
+
+  simulation-request   input written to the erst-sim simulator
+  simulation-response  output the simulator prints
+  execution-trace      trace files read by 'erst trace'
+
+The simulator, the web UI and third-party tools can use them to check that
+their payloads interoperate with this version of erst.`,
+}
+
+var schemaPrintCmd = &cobra.Command{
+	Use:   "print [name]",
+	Short: "Print a schema, or list the schemas",
+	Example: `  erst schema print
+  erst schema print simulation-request > simulation-request.schema.json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		if len(args) == 0 {
+			for _, name := range schema.Names() {
+				fmt.Fprintln(out, name)
+			}
+			return nil
+		}
+		data, err := schema.Published(args[0])
+		if err != nil {
+			return err
+		}
+		_, err = out.Write(data)
+		return err
+	},
+}
+
+var schemaValidateCmd = &cobra.Command{
+	Use:   "validate <name> <file>",
+	Short: "Validate a JSON document against a schema",
+	Long: `Check a JSON document against one of the published schemas and list every
+mismatch by its JSON pointer. Use - to read the document from stdin. The
+command fails when the document does not match.`,
+	Example: `  erst schema validate simulation-response response.json
+  erst-sim < request.json | erst schema validate simulation-response -`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, err := schema.Load(args[0])
+		if err != nil {
+			return err
+		}
+
+		var data []byte
+		if args[1] == "-" {
+			data, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			data, err = os.ReadFile(args[1])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", args[1], err)
+		}
+
+		errs, err := schema.Validate(s, data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[1], err)
+		}
+		out := cmd.OutOrStdout()
+		if len(errs) == 0 {
+			fmt.Fprintf(out, "%s is a valid %s\n", args[1], args[0])
+			return nil
+		}
+		for _, e := range errs {
+			fmt.Fprintf(out, "  %s\n", e.Error())
+		}
+		return fmt.Errorf("%s does not match schema %s: %d error(s)", args[1], args[0], len(errs))
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaPrintCmd)
+	schemaCmd.AddCommand(schemaValidateCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
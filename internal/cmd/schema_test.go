@@ -0,0 +1,51 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaPrint(t *testing.T) {
+	var out bytes.Buffer
+	schemaPrintCmd.SetOut(&out)
+	defer schemaPrintCmd.SetOut(nil)
+
+	require.NoError(t, schemaPrintCmd.RunE(schemaPrintCmd, nil))
+	assert.Equal(t, "execution-trace\nsimulation-request\nsimulation-response\n", out.String())
+
+	out.Reset()
+	require.NoError(t, schemaPrintCmd.RunE(schemaPrintCmd, []string{"simulation-request"}))
+	assert.Contains(t, out.String(), `"envelope_xdr"`)
+
+	assert.ErrorContains(t, schemaPrintCmd.RunE(schemaPrintCmd, []string{"nope"}), "unknown schema")
+}
+
+func TestSchemaValidate(t *testing.T) {
+	var out bytes.Buffer
+	schemaValidateCmd.SetOut(&out)
+	defer schemaValidateCmd.SetOut(nil)
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "req.json")
+	require.NoError(t, os.WriteFile(valid, []byte(`{"envelope_xdr":"AAAA","result_meta_xdr":"","ledger_entries":{"k":"v"}}`), 0644))
+	require.NoError(t, schemaValidateCmd.RunE(schemaValidateCmd, []string{"simulation-request", valid}))
+	assert.Contains(t, out.String(), "is a valid simulation-request")
+
+	out.Reset()
+	schemaValidateCmd.SetIn(strings.NewReader(`{"envelope_xdr":1,"ledger_entries":{"k":2}}`))
+	defer schemaValidateCmd.SetIn(nil)
+	err := schemaValidateCmd.RunE(schemaValidateCmd, []string{"simulation-request", "-"})
+	assert.ErrorContains(t, err, "3 error(s)")
+	assert.Contains(t, out.String(), `missing required property "result_meta_xdr"`)
+	assert.Contains(t, out.String(), "/envelope_xdr: expected string, got integer")
+	assert.Contains(t, out.String(), "/ledger_entries/k: expected string, got integer")
+}
@@ -0,0 +1,128 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/sdkcompat"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sdkCheckNetworkFlag string
+	sdkCheckRPCURLFlag  string
+	sdkCheckJSONFlag    bool
+)
+
+var sdkCheckCmd = &cobra.Command{
+	Use:   "sdkcheck <tx-hash | envelope-xdr | @file>",
+	Short: "Guess which client built an envelope and report encoding pitfalls",
+	Long: `Inspect a transaction envelope for traces of the client that built it and for
+encoding mistakes clients commonly make:
+
+  - SCVal maps with unsorted or repeated keys
+  - Soroban transactions without SorobanTransactionData, or with a fee below
+    the resource fee
+  - ledger keys listed twice in the footprint
+  - authorization entries for muxed accounts, unsigned or expiring at ledger 0
+  - payments to muxed accounts that also carry an ID memo
+
+The builder guess is a heuristic based on preconditions, fees and envelope
+format; treat it as a starting point for triage. Pass a transaction hash to
+fetch the envelope from the network, or the envelope itself as base64 or
+@path. Files may also hold transaction JSON exported by Stellar Lab,
+stellar-cli or an SDK.`,
+	Example: `  erst sdkcheck 5c0a1b...e9 --network testnet
+  erst sdkcheck @envelope.xdr --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		envelopeXdr, err := sdkCheckEnvelope(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		report, err := sdkcompat.Analyze(envelopeXdr)
+		if err != nil {
+			return err
+		}
+
+		if sdkCheckJSONFlag {
+			return writeJSON(cmd, report)
+		}
+
+		visualizer.Record("builder", report.Builder, report.Confidence)
+		for _, is := range report.Issues {
+			visualizer.Record("issue", is.Severity, is.ID, is.Path, is.Message)
+		}
+		printSDKCheck(report)
+		return nil
+	},
+}
+
+// sdkCheckEnvelope returns the envelope named by arg, fetching it when arg
+// is a transaction hash
+func sdkCheckEnvelope(cmd *cobra.Command, arg string) (string, error) {
+	return envelopeArg(cmd, arg, sdkCheckNetworkFlag, sdkCheckRPCURLFlag)
+}
+
+// envelopeArg returns the envelope named by a <tx-hash | envelope-xdr |
+// @file> argument, fetching it from network when arg is a transaction hash
+func envelopeArg(cmd *cobra.Command, arg, network, rpcURL string) (string, error) {
+	if strings.HasPrefix(arg, "@") || rpc.ValidateTransactionHash(arg) != nil {
+		return readEnvelopeArg(arg)
+	}
+	opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(network))}
+	if rpcURL != "" {
+		opts = append(opts, rpc.WithHorizonURL(rpcURL))
+	}
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to create client: %w", err)
+	}
+	resp, err := client.GetTransaction(cmd.Context(), arg)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+	return resp.EnvelopeXdr, nil
+}
+
+func printSDKCheck(report *sdkcompat.Report) {
+	fmt.Println(visualizer.Heading("Envelope Builder"))
+	fmt.Printf("Likely built by: %s (confidence: %s)\n", report.Builder, report.Confidence)
+	for _, c := range report.Candidates {
+		fmt.Printf("  %-64s score %d: %s\n", c.Builder, c.Score, strings.Join(c.Signals, "; "))
+	}
+
+	fmt.Printf("\n%s\n", visualizer.Heading("Encoding Issues"))
+	if len(report.Issues) == 0 {
+		fmt.Printf("%s No known pitfalls found\n", visualizer.Success())
+		return
+	}
+	for _, is := range report.Issues {
+		symbol := visualizer.Symbol("pin")
+		switch is.Severity {
+		case sdkcompat.SeverityError:
+			symbol = visualizer.Error()
+		case sdkcompat.SeverityWarning:
+			symbol = visualizer.Warning()
+		}
+		fmt.Printf("%s [%s] %s\n", symbol, is.ID, is.Message)
+		if is.Path != "" {
+			fmt.Printf("    at  %s\n", is.Path)
+		}
+		fmt.Printf("    fix %s\n", is.Fix)
+	}
+	fmt.Printf("\n%s\n", report.Summary())
+}
+
+func init() {
+	sdkCheckCmd.Flags().StringVarP(&sdkCheckNetworkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to fetch a transaction hash from (testnet, mainnet, futurenet)")
+	sdkCheckCmd.Flags().StringVar(&sdkCheckRPCURLFlag, "rpc-url", "", "Custom Horizon RPC URL")
+	sdkCheckCmd.Flags().BoolVar(&sdkCheckJSONFlag, "json", false, "Output the report as JSON")
+	supportPorcelain(sdkCheckCmd)
+	rootCmd.AddCommand(sdkCheckCmd)
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSDKCheckEnvelopeReadsXDRWithoutNetwork(t *testing.T) {
+	got, err := sdkCheckEnvelope(sdkCheckCmd, "AAAAAgAAAAA=")
+	require.NoError(t, err)
+	assert.Equal(t, "AAAAAgAAAAA=", got)
+
+	path := filepath.Join(t.TempDir(), "env.xdr")
+	require.NoError(t, os.WriteFile(path, []byte("AAAAAgAAAAA=\n"), 0644))
+	got, err = sdkCheckEnvelope(sdkCheckCmd, "@"+path)
+	require.NoError(t, err)
+	assert.Equal(t, "AAAAAgAAAAA=", got)
+}
+
+func TestReadEnvelopeArgConvertsJSON(t *testing.T) {
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"),
+			Fee:           100,
+			SeqNum:        1,
+		}},
+	}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "result.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"signedTxXdr": "`+b64+`"}`), 0644))
+	got, err := readEnvelopeArg("@" + path)
+	require.NoError(t, err)
+	assert.Equal(t, b64, got)
+
+	got, err = readEnvelopeArg(b64)
+	require.NoError(t, err)
+	assert.Equal(t, b64, got)
+}
@@ -0,0 +1,280 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	securitySource       analysisSource
+	securityBaselineFlag string
+	securityNoBaseline   bool
+	securityFailOnFlag   string
+	securityAcceptFlag   string
+	securityWeightsFlag  string
+	securitySortFlag     string
+)
+
+// securityWeights returns the scoring weights from --weights,
+// ERST_SECURITY_WEIGHTS or the config file, else the defaults
+func securityWeights() (security.Weights, error) {
+	spec := securityWeightsFlag
+	if spec == "" {
+		spec = os.Getenv("ERST_SECURITY_WEIGHTS")
+	}
+	if spec == "" {
+		if cfg, err := config.LoadConfig(); err == nil {
+			spec = cfg.SecurityWeights
+		}
+	}
+	if spec == "" {
+		return security.DefaultWeights(), nil
+	}
+	return security.ParseWeights(spec)
+}
+
+// loadSecurityBaseline returns the baseline named by --baseline, else
+// .erstignore in the working directory if it exists, else nil
+func loadSecurityBaseline() (*security.Baseline, error) {
+	if securityNoBaseline {
+		return nil, nil
+	}
+	path := securityBaselineFlag
+	if path == "" {
+		if _, err := os.Stat(security.DefaultBaselineFile); err != nil {
+			return nil, nil
+		}
+		path = security.DefaultBaselineFile
+	}
+	return security.LoadBaseline(path)
+}
+
+// acceptFindings appends findings to the baseline file with justification
+func acceptFindings(findings []security.Finding, justification string) error {
+	path := securityBaselineFlag
+	if path == "" {
+		path = security.DefaultBaselineFile
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open baseline: %w", err)
+	}
+	if err := security.AppendBaseline(f, findings, justification, time.Now()); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Added %d finding(s) to %s\n", len(findings), path)
+	return nil
+}
+
+var securityCmd = &cobra.Command{
+	Use:   "security [tx-hash | session-id]",
+	Short: "Run the security analysis on a transaction, session or raw XDR",
+	Long: `Run the security detector on its own, without a full debug run. The input is
+one of:
+
+  - a transaction hash, fetched from --network
+  - a session ID, or --session, analyzing the stored transaction and
+    simulation without refetching; --checkpoint selects a run other than the
+    latest
+  - --envelope with an optional --result-meta, as base64 or @file
+
+Without any, the current session is analyzed. Findings based on simulation
+events and logs need a session; fetched and raw input is checked from its XDR
+alone. --format json prints the findings for scripts and CI.
+
+Accepted risks are suppressed by a baseline file, .erstignore in the working
+directory unless --baseline names another. Each line holds a finding ID and
+the justification for accepting it:
+
+  # Treasury payouts are reviewed by hand
+  F-3f2a9c1d7e4b  large transfers from the treasury are expected
+
+--fail-on makes the command fail when a finding at or above that severity is
+not suppressed, so CI fails on new findings only. --accept "<reason>"
+appends the current unsuppressed findings to the baseline.
+
+Each finding is scored from 0.0 to 10.0 on a CVSS-like vector of impact (I),
+likelihood (L) and the value of the assets touched (A), each rated N, L, M or
+H, e.g. I:H/L:M/A:H. --weights changes how much each factor counts, and
+--sort score lists the highest scores first.
+
+--wasm inspects contract code as well: floating-point instructions, loops
+without an exit condition, panic messages carrying build paths and large data
+sections. --wasm-from-ledger inspects the code of every contract in the
+transaction's footprint, taken from the session's recorded ledger entries or
+fetched from --network.
+
+External scanners configured under "scanners" in the config file, or given
+with --scanner name=command, run with the session JSON on stdin and print
+findings JSON on stdout. Their findings are merged in with rules namespaced
+by the scanner name, e.g. acme/unchecked-call. A scanner that fails is
+reported as a warning, and fails the command when --fail-on is set.`,
+	Example: `  erst security 5c0a1b...e9 --network testnet
+  erst security --session abc123 --checkpoint override-a
+  erst security --envelope @tx.xdr --result-meta @meta.xdr --format json
+  erst session resume abc123 && erst security
+  erst security abc123 --fail-on medium
+  erst security abc123 --accept "reviewed in audit 2025-03"
+  erst security abc123 --sort score --weights impact=2,likelihood=1,asset=1
+  erst security abc123 --wasm-from-ledger
+  erst security abc123 --scanner acme="./acme-scan --strict"
+  erst security --envelope @tx.xdr --wasm target/wasm32-unknown-unknown/release/token.wasm`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := securitySource.checkFormat(); err != nil {
+			return err
+		}
+		if securityNoBaseline && securityAcceptFlag != "" {
+			return fmt.Errorf("--accept cannot be combined with --no-baseline")
+		}
+		var failOn security.Severity
+		if securityFailOnFlag != "" {
+			sev, err := security.ParseSeverity(securityFailOnFlag)
+			if err != nil {
+				return err
+			}
+			failOn = sev
+		}
+		if securitySortFlag != "severity" && securitySortFlag != "score" {
+			return fmt.Errorf("unsupported sort %q (use severity or score)", securitySortFlag)
+		}
+		weights, err := securityWeights()
+		if err != nil {
+			return err
+		}
+		baseline, err := loadSecurityBaseline()
+		if err != nil {
+			return err
+		}
+		scanners, err := securityScanners()
+		if err != nil {
+			return err
+		}
+		in, err := securitySource.load(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		detector := security.NewDetector()
+		detector.SetWeights(weights)
+		var findings []security.Finding
+		if in.Simulation != nil {
+			findings = detector.AnalyzeSimulation(in.EnvelopeXdr, in.ResultMetaXdr, in.Simulation)
+		} else {
+			findings = detector.Analyze(in.EnvelopeXdr, in.ResultMetaXdr, nil, nil)
+		}
+		modules, err := securityWASM(cmd.Context(), in)
+		if err != nil {
+			return err
+		}
+		if len(modules) > 0 {
+			wasmFindings, err := analyzeWASM(detector, modules)
+			if err != nil {
+				return err
+			}
+			findings = append(findings, wasmFindings...)
+			security.SortFindings(findings)
+		}
+		external, scannerErrors, err := runScanners(cmd.Context(), scanners, in, weights)
+		if err != nil {
+			return err
+		}
+		if len(external) > 0 {
+			findings = append(findings, external...)
+			security.SortFindings(findings)
+		}
+
+		var suppressed []security.Suppressed
+		var unused []security.Suppression
+		if baseline != nil {
+			unused = baseline.Unused(findings)
+			findings, suppressed = baseline.Apply(findings)
+		}
+		if securitySortFlag == "score" {
+			security.SortByScore(findings)
+		}
+
+		if securitySource.format == "json" {
+			if findings == nil {
+				findings = []security.Finding{}
+			}
+			if err := writeJSON(cmd, struct {
+				*analysisInput
+				Findings       []security.Finding     `json:"findings"`
+				Suppressed     []security.Suppressed  `json:"suppressed,omitempty"`
+				UnusedBaseline []security.Suppression `json:"unused_baseline,omitempty"`
+				ScannerErrors  []string               `json:"scanner_errors,omitempty"`
+			}{in, findings, suppressed, unused, scannerErrors}); err != nil {
+				return err
+			}
+		} else {
+			fmt.Printf("%s\n", visualizer.Heading("Security Analysis"))
+			fmt.Printf("Source: %s\n\n", in.Source)
+			if in.ResultMetaXdr == "" {
+				fmt.Printf("Note: no result meta; only the envelope and any simulation output were checked\n\n")
+			}
+			printSecurityFindings(findings)
+			if len(suppressed) > 0 {
+				fmt.Printf("\n%d finding(s) suppressed by %s:\n", len(suppressed), baseline.Path)
+				for _, s := range suppressed {
+					fmt.Printf("  %s %s - %s\n", s.ID, s.Title, s.Justification)
+					visualizer.Record("suppressed", s.ID, s.Justification)
+				}
+			}
+			for _, u := range unused {
+				fmt.Printf("%s %s:%d suppresses %s, which was not found\n", visualizer.Warning(), baseline.Path, u.Line, u.ID)
+			}
+			for _, e := range scannerErrors {
+				fmt.Printf("%s %s\n", visualizer.Warning(), e)
+			}
+		}
+
+		if securityAcceptFlag != "" && len(findings) > 0 {
+			return acceptFindings(findings, securityAcceptFlag)
+		}
+		if failOn != "" {
+			if len(scannerErrors) > 0 {
+				return fmt.Errorf("%d external scanner(s) failed", len(scannerErrors))
+			}
+			failing := 0
+			for _, f := range findings {
+				if f.Severity.AtLeast(failOn) {
+					failing++
+				}
+			}
+			if failing > 0 {
+				return fmt.Errorf("%d unsuppressed finding(s) at or above %s", failing, failOn)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	securitySource.register(securityCmd, "text", "json")
+	securityCmd.Flags().StringVar(&securityBaselineFlag, "baseline", "", "Baseline of accepted findings (default: .erstignore if present)")
+	securityCmd.Flags().BoolVar(&securityNoBaseline, "no-baseline", false, "Report every finding, ignoring the baseline")
+	securityCmd.Flags().StringVar(&securityFailOnFlag, "fail-on", "", "Fail when an unsuppressed finding is at or above this severity (high, medium, low, info)")
+	securityCmd.Flags().StringVar(&securityAcceptFlag, "accept", "", "Append the unsuppressed findings to the baseline with this justification")
+	securityCmd.Flags().StringVar(&securityWeightsFlag, "weights", "", "Scoring weights as impact=N,likelihood=N,asset=N (default: ERST_SECURITY_WEIGHTS or config)")
+	securityCmd.Flags().StringVar(&securitySortFlag, "sort", "severity", "Order findings by severity or score")
+	securityCmd.Flags().StringSliceVar(&securityWasmFlag, "wasm", nil, "Also inspect this contract WASM file (repeatable)")
+	securityCmd.Flags().BoolVar(&securityWasmLedgerFlag, "wasm-from-ledger", false, "Also inspect the WASM of the contracts in the transaction footprint")
+	securityCmd.Flags().StringArrayVar(&securityScannerFlag, "scanner", nil, "Also run this external scanner, as name=command (repeatable)")
+	securityCmd.Flags().BoolVar(&securityNoScanners, "no-scanners", false, "Skip the external scanners in the config file")
+	supportPorcelain(securityCmd)
+	rootCmd.AddCommand(securityCmd)
+}
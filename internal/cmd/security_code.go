@@ -0,0 +1,167 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	securityWasmFlag       []string
+	securityWasmLedgerFlag bool
+)
+
+// contractWASM is contract code and the name findings report it under
+type contractWASM struct {
+	name string
+	code []byte
+}
+
+// securityWASM returns the modules given with --wasm and, with
+// --wasm-from-ledger, the code of every contract in the transaction's
+// footprint: from the session's recorded ledger entries where possible,
+// else fetched from the transaction's network. Code that cannot be found
+// either way is an error, so a clean report always covers every contract.
+func securityWASM(ctx context.Context, in *analysisInput) ([]contractWASM, error) {
+	var modules []contractWASM
+	for _, path := range securityWasmFlag {
+		code, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read WASM file: %w", err)
+		}
+		modules = append(modules, contractWASM{name: filepath.Base(path), code: code})
+	}
+	if !securityWasmLedgerFlag {
+		return modules, nil
+	}
+
+	keys, err := contractCodeKeys(in.EnvelopeXdr)
+	if err != nil {
+		return nil, err
+	}
+	found := contractCodeEntries(in.LedgerEntries)
+	var missing []string
+	for hash, key := range keys {
+		if _, ok := found[hash]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	network := in.Network
+	if network == "" {
+		network = securitySource.network
+	}
+	if len(missing) > 0 {
+		opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(network))}
+		if networks, err := configuredNetworks([]string{network}); err == nil {
+			opts = []rpc.ClientOption{rpc.WithNetworkConfig(networks[0])}
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client: %w", err)
+		}
+		fetched, err := client.GetLedgerEntries(ctx, missing)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch contract code: %w", err)
+		}
+		for hash, code := range contractCodeEntries(fetched) {
+			found[hash] = code
+		}
+	}
+	var unfetched []string
+	for hash := range keys {
+		if _, ok := found[hash]; !ok {
+			unfetched = append(unfetched, hash)
+		}
+	}
+	if len(unfetched) > 0 {
+		sort.Strings(unfetched)
+		return nil, fmt.Errorf("contract code not found on %s for WASM hash(es) %s", network, strings.Join(unfetched, ", "))
+	}
+
+	hashes := make([]string, 0, len(found))
+	for hash := range found {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	for _, hash := range hashes {
+		modules = append(modules, contractWASM{name: "wasm " + hash[:16], code: found[hash]})
+	}
+	return modules, nil
+}
+
+// contractCodeKeys returns the contract code ledger keys in the Soroban
+// footprint of an envelope, by WASM hash
+func contractCodeKeys(envelopeXdr string) (map[string]string, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	var ext xdr.TransactionExt
+	switch env.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		ext = env.V1.Tx.Ext
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		ext = env.FeeBump.Tx.InnerTx.V1.Tx.Ext
+	}
+	keys := make(map[string]string)
+	data, ok := ext.GetSorobanData()
+	if !ok {
+		return keys, nil
+	}
+	for _, key := range append(append([]xdr.LedgerKey(nil), data.Resources.Footprint.ReadOnly...), data.Resources.Footprint.ReadWrite...) {
+		code, ok := key.GetContractCode()
+		if !ok {
+			continue
+		}
+		encoded, err := rpc.EncodeLedgerKey(key)
+		if err != nil {
+			return nil, err
+		}
+		keys[hex.EncodeToString(code.Hash[:])] = encoded
+	}
+	return keys, nil
+}
+
+// contractCodeEntries returns the code in ledger entries (base64
+// LedgerEntryData or LedgerEntry), by WASM hash
+func contractCodeEntries(entries map[string]string) map[string][]byte {
+	codes := make(map[string][]byte)
+	for _, raw := range entries {
+		var data xdr.LedgerEntryData
+		if err := xdr.SafeUnmarshalBase64(raw, &data); err != nil || data.Type != xdr.LedgerEntryTypeContractCode {
+			var entry xdr.LedgerEntry
+			if err := xdr.SafeUnmarshalBase64(raw, &entry); err != nil {
+				continue
+			}
+			data = entry.Data
+		}
+		if code, ok := data.GetContractCode(); ok {
+			codes[hex.EncodeToString(code.Hash[:])] = code.Code
+		}
+	}
+	return codes
+}
+
+// analyzeWASM runs the static checks on each module
+func analyzeWASM(detector *security.Detector, modules []contractWASM) ([]security.Finding, error) {
+	var findings []security.Finding
+	for _, m := range modules {
+		found, err := detector.AnalyzeWASM(m.name, m.code)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, found...)
+	}
+	return findings, nil
+}
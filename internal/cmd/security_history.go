@@ -0,0 +1,167 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+var (
+	historyContractFlag string
+	historyNetworkFlag  string
+	historyFormatFlag   string
+	historyLimitFlag    int
+)
+
+// contractHistory analyzes every stored session that touches contract,
+// optionally on one network only, and tracks the findings across them
+func contractHistory(ctx context.Context, contract, network string, limit int) (*security.History, error) {
+	store, err := session.NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session store: %w", err)
+	}
+	defer store.Close()
+
+	stored, err := store.List(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	weights, err := securityWeights()
+	if err != nil {
+		return nil, err
+	}
+
+	var observations []security.Observation
+	for _, data := range stored {
+		if network != "" && data.Network != network {
+			continue
+		}
+		touched := false
+		for _, c := range security.TouchedContracts(data.EnvelopeXdr) {
+			if c == contract {
+				touched = true
+				break
+			}
+		}
+		if !touched {
+			continue
+		}
+
+		detector := security.NewDetector()
+		detector.SetWeights(weights)
+		var findings []security.Finding
+		if sim, err := data.ToSimulationResponse(); err == nil {
+			findings = detector.AnalyzeSimulation(data.EnvelopeXdr, data.ResultMetaXdr, sim)
+		} else {
+			findings = detector.Analyze(data.EnvelopeXdr, data.ResultMetaXdr, nil, nil)
+		}
+		observations = append(observations, security.Observation{
+			SessionID: data.ID,
+			TxHash:    data.TxHash,
+			At:        data.CreatedAt,
+			Findings:  findings,
+		})
+	}
+	return security.BuildHistory(observations), nil
+}
+
+var securityHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Track security findings across the stored sessions of a contract",
+	Long: `Re-run the security analysis on every stored session whose transaction
+invokes the contract or has it in its footprint, oldest first, and follow each
+finding ID across them. Each session lists the findings it introduced and the
+ones that recurred. Each finding is reported as:
+
+  new        first seen in the latest session
+  recurring  seen in the latest session and before it
+  resolved   seen before but not in the latest session
+
+The current detector rules are applied to old sessions too, so a new rule
+shows its findings across the whole history.`,
+	Example: `  erst security history --contract CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC
+  erst security history --contract CDLZ...CYSC --network testnet --format json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := strkey.Decode(strkey.VersionByteContract, historyContractFlag); err != nil {
+			return fmt.Errorf("--contract must be a contract address (C...): %w", err)
+		}
+		if historyFormatFlag != "text" && historyFormatFlag != "json" {
+			return fmt.Errorf("unsupported format %q (supported: text, json)", historyFormatFlag)
+		}
+
+		history, err := contractHistory(cmd.Context(), historyContractFlag, historyNetworkFlag, historyLimitFlag)
+		if err != nil {
+			return err
+		}
+
+		if historyFormatFlag == "json" {
+			return writeJSON(cmd, struct {
+				Contract string `json:"contract"`
+				*security.History
+			}{historyContractFlag, history})
+		}
+
+		fmt.Printf("%s\n", visualizer.Heading("Security History"))
+		fmt.Printf("Contract: %s\n", historyContractFlag)
+		if len(history.Observations) == 0 {
+			fmt.Printf("No stored sessions touch this contract\n")
+			return nil
+		}
+
+		fmt.Printf("\nSessions (%d):\n", len(history.Observations))
+		for _, obs := range history.Observations {
+			fmt.Printf("  %s  %-16s tx %s  %d new, %d recurring\n",
+				obs.At.Local().Format("2006-01-02 15:04"), obs.SessionID, shortHash(obs.TxHash), len(obs.New), len(obs.Recurring))
+		}
+
+		if len(history.Findings) == 0 {
+			fmt.Printf("\n%s No security issues detected in any session\n", visualizer.Success())
+			return nil
+		}
+		fmt.Printf("\nFindings:\n")
+		for _, f := range history.Findings {
+			var when string
+			switch f.Status {
+			case security.StatusNew:
+				when = fmt.Sprintf("first seen in %s", f.FirstSession)
+			case security.StatusRecurring:
+				when = fmt.Sprintf("in %d sessions since %s", f.Occurrences, f.FirstSeen.Local().Format("2006-01-02"))
+			default:
+				when = fmt.Sprintf("last seen in %s on %s", f.LastSession, f.LastSeen.Local().Format("2006-01-02"))
+			}
+			fmt.Printf("  %-9s  %s  %-6s  %s (%s)\n", strings.ToUpper(f.Status), f.ID, f.Severity, f.Title, when)
+			visualizer.Record("history", f.Status, f.ID, f.Title)
+		}
+		fmt.Printf("\n%d new, %d recurring, %d resolved\n",
+			history.Count(security.StatusNew), history.Count(security.StatusRecurring), history.Count(security.StatusResolved))
+		return nil
+	},
+}
+
+// shortHash abbreviates a transaction hash for tables
+func shortHash(hash string) string {
+	if len(hash) <= 12 {
+		return hash
+	}
+	return hash[:6] + "..." + hash[len(hash)-4:]
+}
+
+func init() {
+	securityHistoryCmd.Flags().StringVar(&historyContractFlag, "contract", "", "Contract address (C...) to track")
+	securityHistoryCmd.Flags().StringVarP(&historyNetworkFlag, "network", "n", "", "Only include sessions on this network")
+	securityHistoryCmd.Flags().StringVar(&historyFormatFlag, "format", "text", "Output format: text, json")
+	securityHistoryCmd.Flags().IntVar(&historyLimitFlag, "limit", session.DefaultMaxSessions, "Maximum number of recent sessions to scan")
+	_ = securityHistoryCmd.MarkFlagRequired("contract")
+	supportPorcelain(securityHistoryCmd)
+	securityCmd.AddCommand(securityHistoryCmd)
+}
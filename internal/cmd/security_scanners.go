@@ -0,0 +1,80 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/security"
+)
+
+var (
+	securityScannerFlag []string
+	securityNoScanners  bool
+)
+
+// securityScanners returns the scanners in the config file, unless
+// --no-scanners is set, followed by those given with --scanner; a flag
+// replaces a configured scanner of the same name
+func securityScanners() ([]security.Scanner, error) {
+	var scanners []security.Scanner
+	index := make(map[string]int)
+	add := func(s security.Scanner) {
+		if i, ok := index[s.Name]; ok {
+			scanners[i] = s
+			return
+		}
+		index[s.Name] = len(scanners)
+		scanners = append(scanners, s)
+	}
+
+	if cfg, err := config.LoadConfig(); err == nil && !securityNoScanners {
+		for _, sc := range cfg.Scanners {
+			s := security.Scanner{
+				Name:    sc.Name,
+				Command: sc.Command,
+				Args:    sc.Args,
+				Timeout: time.Duration(sc.TimeoutSeconds) * time.Second,
+			}
+			if err := s.Validate(); err != nil {
+				return nil, err
+			}
+			add(s)
+		}
+	}
+	for _, spec := range securityScannerFlag {
+		s, err := security.ParseScanner(spec)
+		if err != nil {
+			return nil, err
+		}
+		add(s)
+	}
+	return scanners, nil
+}
+
+// runScanners runs each scanner on the input and merges their findings. A
+// scanner that fails is reported in the returned errors, not fatal, so the
+// other results are still shown.
+func runScanners(ctx context.Context, scanners []security.Scanner, in *analysisInput, weights security.Weights) ([]security.Finding, []string, error) {
+	if len(scanners) == 0 {
+		return nil, nil, nil
+	}
+	input, err := in.sessionJSON()
+	if err != nil {
+		return nil, nil, err
+	}
+	var findings []security.Finding
+	var failures []string
+	for _, s := range scanners {
+		found, err := security.RunScanner(ctx, s, input, weights)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		findings = append(findings, found...)
+	}
+	return findings, failures, nil
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityBaselineGate(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	saved := sessions
+	sessions = session.NewManager("")
+	t.Cleanup(func() { sessions = saved })
+
+	data := &session.SessionData{Network: "testnet", TxHash: "abcd"}
+	require.NoError(t, data.AddRun(session.Run{Name: session.DefaultRunName, SimResponseJSON: `{"status":"error","logs":["attempt to add with overflow"]}`}))
+	_, err := recordCheckpoint(context.Background(), "s1", data)
+	require.NoError(t, err)
+
+	baseline := filepath.Join(t.TempDir(), "baseline")
+	securityBaselineFlag, securityFailOnFlag = baseline, "high"
+	t.Cleanup(func() { securityBaselineFlag, securityFailOnFlag, securityAcceptFlag = "", "", "" })
+	securityCmd.SetContext(context.Background())
+
+	// No baseline file yet: --baseline must exist
+	assert.ErrorContains(t, securityCmd.RunE(securityCmd, []string{"s1"}), "failed to open baseline")
+
+	require.NoError(t, os.WriteFile(baseline, []byte("# accepted risks\n"), 0644))
+	assert.ErrorContains(t, securityCmd.RunE(securityCmd, []string{"s1"}), "1 unsuppressed finding(s) at or above HIGH")
+
+	securityAcceptFlag = "overflow is handled by the caller"
+	require.NoError(t, securityCmd.RunE(securityCmd, []string{"s1"}))
+	content, err := os.ReadFile(baseline)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "overflow is handled by the caller")
+
+	securityAcceptFlag = ""
+	assert.NoError(t, securityCmd.RunE(securityCmd, []string{"s1"}), "accepted findings no longer fail the gate")
+}
+
+func TestSecurityWeights(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	t.Setenv("ERST_SECURITY_WEIGHTS", "")
+	t.Cleanup(func() { securityWeightsFlag = "" })
+
+	w, err := securityWeights()
+	require.NoError(t, err)
+	assert.Equal(t, security.DefaultWeights(), w)
+
+	t.Setenv("ERST_SECURITY_WEIGHTS", "impact=1,likelihood=0,asset=0")
+	w, err = securityWeights()
+	require.NoError(t, err)
+	assert.Equal(t, security.Weights{Impact: 1}, w)
+
+	securityWeightsFlag = "asset=3"
+	w, err = securityWeights()
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, w.Asset, "--weights overrides the environment")
+
+	securityWeightsFlag = "impact=lots"
+	_, err = securityWeights()
+	assert.ErrorContains(t, err, "invalid weight")
+}
+
+func TestContractHistory(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	ctx := context.Background()
+
+	id := xdr.ContractId{7}
+	addr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}
+	contract, err := addr.String()
+	require.NoError(t, err)
+	source := xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: source.ToMuxedAccount(),
+			Fee:           100,
+			SeqNum:        1,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+					Type:           xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+					InvokeContract: &xdr.InvokeContractArgs{ContractAddress: addr, FunctionName: "withdraw"},
+				}},
+			}}},
+		}},
+	}
+	raw, err := env.MarshalBinary()
+	require.NoError(t, err)
+	envelope := base64.StdEncoding.EncodeToString(raw)
+
+	store, err := session.NewStore()
+	require.NoError(t, err)
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, s := range []struct {
+		id, network, envelope, logs string
+	}{
+		{"early", "testnet", envelope, `["set_admin called"]`},
+		{"late", "testnet", envelope, `["set_admin called","attempt to add with overflow"]`},
+		{"other-network", "mainnet", envelope, `["attempt to add with overflow"]`},
+		{"unrelated", "testnet", "", `["attempt to add with overflow"]`},
+	} {
+		require.NoError(t, store.Save(ctx, &session.SessionData{
+			ID: s.id, CreatedAt: start.Add(time.Duration(i) * time.Hour), Status: "saved",
+			Network: s.network, TxHash: s.id, EnvelopeXdr: s.envelope,
+			SimResponseJSON: `{"status":"success","logs":` + s.logs + `}`,
+		}))
+	}
+	require.NoError(t, store.Close())
+
+	history, err := contractHistory(ctx, contract, "testnet", 100)
+	require.NoError(t, err)
+	require.Len(t, history.Observations, 2)
+	assert.Equal(t, "early", history.Observations[0].SessionID)
+	require.Len(t, history.Findings, 2)
+	assert.Equal(t, security.StatusNew, history.Findings[0].Status)
+	assert.Equal(t, "Integer Overflow/Underflow Detected", history.Findings[0].Title)
+	assert.Equal(t, security.StatusRecurring, history.Findings[1].Status)
+	assert.Equal(t, 2, history.Findings[1].Occurrences)
+
+	all, err := contractHistory(ctx, contract, "", 100)
+	require.NoError(t, err)
+	assert.Len(t, all.Observations, 3, "without --network every network is included")
+}
+
+func TestSecurityWASMFromSession(t *testing.T) {
+	// A module with one function that pushes and drops f64.const 0
+	code := []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00,
+		0x01, 0x04, 0x01, 0x60, 0x00, 0x00,
+		0x03, 0x02, 0x01, 0x00,
+		0x0A, 0x0E, 0x01, 0x0C, 0x00, 0x44, 0, 0, 0, 0, 0, 0, 0, 0, 0x1A, 0x0B}
+	hash := xdr.Hash(sha256.Sum256(code))
+
+	codeKey := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: hash}}
+	key, err := rpc.EncodeLedgerKey(codeKey)
+	require.NoError(t, err)
+	entry, err := rpc.EncodeLedgerEntry(xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.ContractCodeEntry{Hash: hash, Code: code},
+	}})
+	require.NoError(t, err)
+
+	source := xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	env := xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+		SourceAccount: source.ToMuxedAccount(),
+		Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{codeKey}}},
+		}},
+	}}}
+	envelope, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { securityWasmFlag, securityWasmLedgerFlag = nil, false })
+	in := &analysisInput{EnvelopeXdr: envelope, LedgerEntries: map[string]string{key: entry}}
+
+	modules, err := securityWASM(context.Background(), in)
+	require.NoError(t, err)
+	assert.Empty(t, modules, "code is only inspected on request")
+
+	securityWasmLedgerFlag = true
+	modules, err = securityWASM(context.Background(), in)
+	require.NoError(t, err)
+	require.Len(t, modules, 1)
+	assert.Equal(t, "wasm "+hex.EncodeToString(hash[:8]), modules[0].name)
+
+	findings, err := analyzeWASM(security.NewDetector(), modules)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "Floating-Point Code in WASM", findings[0].Title)
+
+	securityWasmLedgerFlag = false
+	securityWasmFlag = []string{filepath.Join(t.TempDir(), "missing.wasm")}
+	_, err = securityWASM(context.Background(), in)
+	assert.ErrorContains(t, err, "failed to read WASM file")
+
+	// Code missing from the session is fetched from the session's network,
+	// and code the network does not have either is an error
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ Method string }
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		methods = append(methods, req.Method)
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"entries":[],"latestLedger":1}}`)
+	}))
+	defer server.Close()
+	t.Setenv("ERST_HOME", t.TempDir())
+	require.NoError(t, config.AddCustomNetwork("local", rpc.NetworkConfig{
+		Name: "local", NetworkPassphrase: "Local Network", SorobanRPCURL: server.URL,
+	}))
+	securityWasmFlag, securityWasmLedgerFlag = nil, true
+	_, err = securityWASM(context.Background(), &analysisInput{EnvelopeXdr: envelope, Network: "local"})
+	assert.ErrorContains(t, err, "contract code not found on local for WASM hash(es) "+hex.EncodeToString(hash[:]))
+	assert.Equal(t, []string{"getLedgerEntries"}, methods)
+}
+
+func TestSecurityScanners(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script scanner")
+	}
+	home := t.TempDir()
+	t.Setenv("ERST_HOME", home)
+	t.Cleanup(func() { securityScannerFlag, securityNoScanners = nil, false })
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good")
+	require.NoError(t, os.WriteFile(good, []byte("#!/bin/sh\ncat >/dev/null\necho '[{\"rule\":\"r1\",\"severity\":\"MEDIUM\",\"title\":\"Custom\"}]'\n"), 0755))
+	bad := filepath.Join(dir, "bad")
+	require.NoError(t, os.WriteFile(bad, []byte("#!/bin/sh\nexit 1\n"), 0755))
+
+	cfg, err := json.Marshal(config.Config{Scanners: []config.ScannerConfig{
+		{Name: "internal", Command: bad},
+		{Name: "vendor", Command: good, TimeoutSeconds: 5},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.json"), cfg, 0600))
+
+	scanners, err := securityScanners()
+	require.NoError(t, err)
+	require.Len(t, scanners, 2)
+	assert.Equal(t, 5*time.Second, scanners[1].Timeout)
+
+	in := &analysisInput{Network: "testnet", EnvelopeXdr: "AAAA"}
+	findings, failures, err := runScanners(context.Background(), scanners, in, security.DefaultWeights())
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "vendor/r1", findings[0].Rule)
+	require.Len(t, failures, 1)
+	assert.Contains(t, failures[0], "scanner internal failed")
+
+	// A flag replaces the configured scanner of the same name
+	securityScannerFlag = []string{"internal=" + good}
+	scanners, err = securityScanners()
+	require.NoError(t, err)
+	require.Len(t, scanners, 2)
+	assert.Equal(t, good, scanners[0].Command)
+
+	securityNoScanners = true
+	scanners, err = securityScanners()
+	require.NoError(t, err)
+	require.Len(t, scanners, 1, "--no-scanners skips the configured scanners only")
+}
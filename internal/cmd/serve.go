@@ -0,0 +1,368 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/server"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	servePort       string
+	serveGRPCPort   string
+	serveNetwork    string
+	serveRPCURL     string
+	serveTokens     []string
+	servePolicyFile string
+	servePublic     bool
+	serveRateLimit  int
+	serveMaxBody    int64
+	serveSimTimeout time.Duration
+	serveSimMemory  uint64
+	serveSandbox    sandboxFlags
+
+	serveSlackSecret string
+	serveSlackRole   string
+	servePublicURL   string
+
+	serveStorageDSN string
+	serveAPIKeys    bool
+
+	serveDrainDelay   time.Duration
+	serveDrainTimeout time.Duration
+
+	servePool server.PoolConfig
+)
+
+// sandboxFlags are the simulator process limits of serve and batch commands
+type sandboxFlags struct {
+	cpu      time.Duration
+	memoryMB uint64
+	outputMB int64
+}
+
+// register adds --sim-cpu-limit, --sim-memory-limit and --sim-output-limit
+// to cmd
+func (f *sandboxFlags) register(cmd *cobra.Command, defaults simulator.Limits) {
+	cmd.Flags().DurationVar(&f.cpu, "sim-cpu-limit", defaults.CPUTime, "CPU time each simulator process may use (0 = unlimited)")
+	cmd.Flags().Uint64Var(&f.memoryMB, "sim-memory-limit", defaults.MemoryBytes>>20, "Memory in MB each simulator process may use (0 = unlimited)")
+	cmd.Flags().Int64Var(&f.outputMB, "sim-output-limit", defaults.OutputBytes>>20, "Output in MB each simulator process may write (0 = unlimited)")
+}
+
+func (f sandboxFlags) limits() simulator.Limits {
+	return simulator.Limits{CPUTime: f.cpu, MemoryBytes: f.memoryMB << 20, OutputBytes: f.outputMB << 20}
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Start the REST API server",
+	Long: `Start an HTTP server exposing erst debugging over a REST API for team use.
+
+Callers authenticate with bearer tokens, each bound to a role. The role selects the
+output policy enforced when responses are rendered:
+  - admin:     full output, including raw XDR
+  - developer: full analysis, raw XDR omitted
+  - analyst:   addresses redacted, raw XDR and host logs omitted, including
+               the log events of job streams
+
+Policies can be overridden per role with --policy-file. When no tokens are
+configured, authentication is disabled and every caller is treated as admin.
+
+Every simulation runs in its own erst-sim process limited to --sim-cpu-limit
+of CPU time, --sim-memory-limit of memory and --sim-output-limit of output.
+A process over a limit is killed and the request fails with a
+"limit_exceeded" object naming the resource, instead of affecting the host.
+
+Public demo mode (--public) hardens the server for anonymous community hosting:
+per-IP rate limiting, request body caps, simulation time and memory quotas,
+and no on-disk caching of fetched ledger state. Anonymous callers in public
+mode receive the developer policy.
+
+Endpoints:
+  GET  /               Embedded web UI
+  GET  /health         Liveness check
+  GET  /healthz        Liveness probe
+  GET  /readyz         Readiness probe: simulator, RPC and database checks
+  GET  /openapi.json   OpenAPI 3.1 description of this API ('erst serve openapi')
+  POST /api/v1/debug   Debug a transaction: {"hash": "<tx-hash>", "network": "testnet"}
+  POST /api/v1/jobs    Start the same debug run in the background, returns a job ID
+  GET  /api/v1/jobs/{id}         Poll job phase and final result
+  GET  /api/v1/jobs/{id}/stream  WebSocket of phase, log and partial result events
+  GET  /api/v1/jobs/{id}/events  Page through the job's recorded events
+  GET  /api/v1/sessions          Page through stored sessions (with --storage)
+  GET  /api/v1/sessions/{id}     One stored session
+  POST /api/v1/keys              Create a tenant API key (with --api-keys)
+  GET  /api/v1/keys              List API keys
+  DELETE /api/v1/keys/{id}       Revoke an API key
+  GET  /api/v1/keys/{id}/usage   Daily usage of an API key
+
+Every JSON response accepts ?fields=status,findings,simulation.status to keep
+only the named fields. Listings return {"items": [...], "next_cursor": "..."};
+pass ?cursor=<next_cursor> for the next page and ?limit= (up to 500) to size it.
+
+POST /api/v1/debug accepts an Idempotency-Key header: a retry with the same
+key waits for or reuses the first request's result instead of simulating again.
+
+WebSocket clients that cannot set headers may pass the token as ?access_token=.
+
+gRPC: with --grpc-port the same debug, job, simulate and session operations are
+served as erst.v1.DebugService (proto/erst/v1/debug.proto) on a second port.
+Callers send the bearer token in the "authorization" metadata key and get the
+same role-based output. gRPC is not available in public mode.
+
+Debug runs wait in a queue for one of --workers-min to --workers-max simulator
+workers; workers beyond the minimum start while jobs are waiting and stop after
+a minute idle. When --queue-size jobs are already waiting, or a caller (an API
+token, or a client IP without authentication) already has --tenant-concurrency
+jobs queued or running, requests are rejected with 429 and a Retry-After
+estimate, so one busy client cannot starve the others.
+
+On SIGTERM the server drains: /readyz answers 503 for --drain-delay so load
+balancers stop routing to it, then the listener closes and in-flight requests
+and background jobs get up to --drain-timeout to finish.
+
+Slack: with --slack-signing-secret (or SLACK_SIGNING_SECRET), a Slack app's
+slash command can point at POST /slack/commands. '/erst debug <hash> [network]'
+replies at once, runs the debug in the background and posts a summary to the
+channel with a link to the full HTML report at GET /slack/reports/{id}.
+Requests are verified with Slack's signing secret instead of a bearer token,
+and replies and reports use the --slack-role output policy. Set --public-url
+when the server sits behind a proxy so report links point at the right host.
+
+Storage: with --storage (or ERST_STORAGE_DSN, or "storage.dsn" in config.json)
+every debug run is kept as a session and an audit entry in a database that all
+replicas share, along with API keys and their usage. Use a
+postgres:// URL for multi-replica deployments; a file path opens SQLite for a
+single instance. Migrations run at startup. Postgres needs a binary built with
+-tags postgres; the connection pool is tuned in config.json. Storage is not
+available in public mode.
+
+API keys: with --api-keys, tenant API keys kept in storage are accepted as
+bearer tokens next to --token values, and anonymous callers are rejected. Each
+key has a tenant, a role and scopes (debug, jobs, sessions, keys); keys of a
+tenant share its --tenant-concurrency quota, and their requests, simulations
+and RPC calls are counted per day for cost attribution. Manage keys with
+'erst serve keys' or the /api/v1/keys endpoints (admin tokens, or keys with
+the keys scope for their own tenant).`,
+	Example: `  erst serve --port 8080 --network testnet
+  erst serve --token s3cret:admin --token t0ken:analyst
+  erst serve --token t0ken:analyst --policy-file policies.json
+  erst serve --public --rate-limit 5 --sim-timeout 20s
+  erst serve --port 8080 --grpc-port 9090
+  erst serve --sim-cpu-limit 30s --sim-memory-limit 1024
+  erst serve --slack-signing-secret $SLACK_SIGNING_SECRET --public-url https://erst.example.com
+  erst serve --storage postgres://erst@db.internal:5432/erst?sslmode=require
+  erst serve --storage erst.db --api-keys --token s3cret:admin`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch rpc.Network(serveNetwork) {
+		case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+		default:
+			return fmt.Errorf("invalid network: %s. Must be one of: testnet, mainnet, futurenet", serveNetwork)
+		}
+
+		tokens := make(map[string]server.Role, len(serveTokens))
+		for _, spec := range serveTokens {
+			token, role, err := server.ParseTokenSpec(spec)
+			if err != nil {
+				return err
+			}
+			tokens[token] = role
+		}
+
+		policies, err := server.LoadPolicies(servePolicyFile)
+		if err != nil {
+			return err
+		}
+
+		slackRole, err := server.ParseRole(serveSlackRole)
+		if err != nil {
+			return fmt.Errorf("invalid --slack-role: %w", err)
+		}
+		slackSecret := serveSlackSecret
+		if slackSecret == "" {
+			slackSecret = os.Getenv("SLACK_SIGNING_SECRET")
+		}
+
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		store, err := openServeStorage(ctx)
+		if err != nil {
+			return err
+		}
+		if store != nil {
+			defer store.Close()
+		}
+		if serveAPIKeys && store == nil {
+			return fmt.Errorf("--api-keys needs --storage (or ERST_STORAGE_DSN) to keep the keys in")
+		}
+
+		limits := server.DefaultPublicLimits()
+		limits.RequestsPerMinute = serveRateLimit
+		limits.MaxBodyBytes = serveMaxBody
+		limits.SimTimeout = serveSimTimeout
+		limits.SimMaxMemoryBytes = serveSimMemory
+
+		srv, err := server.NewServer(server.Config{
+			Network:  serveNetwork,
+			GRPCPort: serveGRPCPort,
+			APIKeys:  serveAPIKeys,
+			RPCURL:   serveRPCURL,
+			Tokens:   tokens,
+			Policies: policies,
+			Public:   servePublic,
+			Limits:   limits,
+			Sandbox:  serveSandbox.limits(),
+			Slack: server.SlackConfig{
+				SigningSecret: slackSecret,
+				PublicURL:     servePublicURL,
+				Role:          slackRole,
+			},
+			Storage: store,
+			// Runs are tracked apart from the CLI's current session
+			Sessions:     session.NewManager(""),
+			DrainDelay:   serveDrainDelay,
+			DrainTimeout: serveDrainTimeout,
+			Pool:         servePool,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create server: %w", err)
+		}
+
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-sigChan
+			fmt.Println("\nReceived interrupt signal, shutting down...")
+			cancel()
+		}()
+
+		fmt.Printf("Starting ERST REST server on port %s\n", servePort)
+		if serveGRPCPort != "" {
+			fmt.Printf("gRPC API on port %s\n", serveGRPCPort)
+		}
+		fmt.Printf("Network: %s\n", serveNetwork)
+		fmt.Printf("Simulator sandbox: %s\n", serveSandbox.limits())
+		fmt.Printf("Workers: %d-%d, queue %d\n", servePool.MinWorkers, servePool.MaxWorkers, servePool.QueueSize)
+		if servePublic {
+			fmt.Printf("Public mode: %d req/min per IP, %s simulation timeout\n", limits.RequestsPerMinute, limits.SimTimeout)
+		}
+		if serveAPIKeys {
+			fmt.Printf("Authentication: enabled (%d tokens and tenant API keys)\n", len(tokens))
+		} else if len(tokens) > 0 {
+			fmt.Printf("Authentication: enabled (%d tokens)\n", len(tokens))
+		} else {
+			fmt.Println("Authentication: disabled")
+		}
+		if slackSecret != "" {
+			fmt.Printf("Slack slash command: POST /slack/commands (%s policy)\n", slackRole)
+		}
+		if store != nil {
+			fmt.Printf("Storage: %s (schema v%d)\n", store.Dialect(), storage.SchemaVersion())
+		}
+
+		return srv.Start(ctx, servePort)
+	},
+}
+
+var serveOpenAPICmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Print the OpenAPI document of the REST API",
+	Long: `Print the OpenAPI 3.1 document describing the REST API, generated from the
+server's routes and the Go types of its request and response bodies. A running
+server serves the same document at GET /openapi.json. Feed it to a client
+generator instead of reverse-engineering the endpoints.`,
+	Example: `  erst serve openapi > erst-openapi.json
+  npx openapi-typescript erst-openapi.json -o erst-api.d.ts`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := server.OpenAPI()
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+// openServeStorage opens the database named by --storage, ERST_STORAGE_DSN or
+// the config file, in that order. It returns nil when none is configured.
+func openServeStorage(ctx context.Context) (*storage.DB, error) {
+	return openStorage(ctx, serveStorageDSN)
+}
+
+// openStorage opens the database named by dsn, ERST_STORAGE_DSN or the
+// config file, in that order. It returns nil when none is configured.
+func openStorage(ctx context.Context, dsn string) (*storage.DB, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	sc := storage.Config{DSN: dsn}
+	configured := ""
+	if cfg.Storage != nil {
+		configured = cfg.Storage.DSN
+		sc.MaxOpenConns = cfg.Storage.MaxOpenConns
+		sc.MaxIdleConns = cfg.Storage.MaxIdleConns
+		sc.ConnMaxLifetime = time.Duration(cfg.Storage.ConnMaxLifetimeSeconds) * time.Second
+	}
+	if sc.DSN == "" {
+		sc.DSN = getenvDefault("ERST_STORAGE_DSN", configured)
+	}
+	if sc.DSN == "" {
+		return nil, nil
+	}
+	db, err := storage.Open(ctx, sc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage: %w", err)
+	}
+	return db, nil
+}
+
+func init() {
+	serveCmd.Flags().StringVarP(&servePort, "port", "p", "8080", "Port to listen on")
+	serveCmd.Flags().StringVar(&serveGRPCPort, "grpc-port", "", "Port for the gRPC API (disabled when empty)")
+	serveCmd.Flags().StringVarP(&serveNetwork, "network", "n", string(rpc.Mainnet), "Stellar network to use (testnet, mainnet, futurenet)")
+	serveCmd.Flags().StringVar(&serveRPCURL, "rpc-url", "", "Custom Horizon RPC URL to use")
+	serveCmd.Flags().StringArrayVar(&serveTokens, "token", nil, "API token and role as <token>:<role> (repeatable)")
+	serveCmd.Flags().StringVar(&servePolicyFile, "policy-file", "", "JSON file overriding per-role output policies")
+
+	defaults := server.DefaultPublicLimits()
+	serveCmd.Flags().BoolVar(&servePublic, "public", false, "Enable hardened public demo mode")
+	serveCmd.Flags().IntVar(&serveRateLimit, "rate-limit", defaults.RequestsPerMinute, "Requests per minute per client IP (public mode)")
+	serveCmd.Flags().Int64Var(&serveMaxBody, "max-body", defaults.MaxBodyBytes, "Maximum request body size in bytes (public mode)")
+	serveCmd.Flags().DurationVar(&serveSimTimeout, "sim-timeout", defaults.SimTimeout, "Maximum simulation time per request (public mode)")
+	serveCmd.Flags().Uint64Var(&serveSimMemory, "sim-memory", defaults.SimMaxMemoryBytes, "Memory in bytes each simulator process may use (public mode)")
+	serveSandbox.register(serveCmd, server.DefaultSandboxLimits())
+
+	serveCmd.Flags().StringVar(&serveSlackSecret, "slack-signing-secret", "", "Slack app signing secret enabling POST /slack/commands (default $SLACK_SIGNING_SECRET)")
+	serveCmd.Flags().StringVar(&serveSlackRole, "slack-role", string(server.RoleAnalyst), "Output policy role for Slack replies and reports")
+	serveCmd.Flags().StringVar(&servePublicURL, "public-url", "", "Base URL of the server used in Slack report links")
+
+	pool := server.DefaultPoolConfig()
+	serveCmd.Flags().IntVar(&servePool.MinWorkers, "workers-min", pool.MinWorkers, "Simulator workers kept running")
+	serveCmd.Flags().IntVar(&servePool.MaxWorkers, "workers-max", pool.MaxWorkers, "Maximum simulator workers started under load")
+	serveCmd.Flags().IntVar(&servePool.QueueSize, "queue-size", pool.QueueSize, "Debug runs that may wait for a worker before requests get 429")
+	serveCmd.Flags().IntVar(&servePool.TenantLimit, "tenant-concurrency", 0, "Queued and running debug runs allowed per API token or client IP (0 = unlimited)")
+	serveCmd.Flags().DurationVar(&serveDrainDelay, "drain-delay", server.DefaultDrainDelay, "How long /readyz fails before the listener closes on shutdown")
+	serveCmd.Flags().DurationVar(&serveDrainTimeout, "drain-timeout", server.DefaultDrainTimeout, "Maximum wait for in-flight requests and jobs on shutdown")
+	serveCmd.Flags().StringVar(&serveStorageDSN, "storage", "", "Database for sessions and audit entries: postgres:// URL or SQLite path (default $ERST_STORAGE_DSN)")
+	serveCmd.Flags().BoolVar(&serveAPIKeys, "api-keys", false, "Accept tenant API keys kept in --storage (see 'erst serve keys')")
+
+	serveCmd.AddCommand(serveOpenAPICmd)
+	rootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,208 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dotandev/hintents/internal/server"
+	"github.com/dotandev/hintents/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	keysTenantFlag string
+	keysNameFlag   string
+	keysRoleFlag   string
+	keysScopeFlag  []string
+	keysSinceFlag  string
+)
+
+var serveKeysCmd = &cobra.Command{
+	Use:   "keys",
+	Short: "Manage tenant API keys for erst serve",
+	Long: `Create, list and revoke the tenant API keys accepted by 'erst serve --api-keys',
+and report what each key used. Keys live in the serve storage database
+(--storage, ERST_STORAGE_DSN or config.json), so every replica sees them.
+
+Each key belongs to a tenant, has a role selecting its output policy, and is
+limited to scopes: debug (start debug runs and simulations), jobs (read job
+status and events), sessions (read stored sessions) and keys (manage the keys
+of its own tenant). Keys of one tenant share its --tenant-concurrency quota.
+
+Available subcommands:
+  create - Create a key and print its secret
+  list   - List keys
+  revoke - Revoke a key
+  usage  - Show daily requests, simulations and RPC calls of a key`,
+}
+
+var serveKeysCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create an API key",
+	Long: `Create an API key and print its secret. The secret is not stored and cannot
+be shown again; only its hash is kept.`,
+	Example: `  erst serve keys create --tenant payments --name ci --role developer
+  erst serve keys create --tenant risk --name dashboards --scope sessions`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := openKeysStorage(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		role, err := server.ParseRole(keysRoleFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --role: %w", err)
+		}
+		key, secret, err := server.CreateAPIKey(cmd.Context(), db, keysTenantFlag, keysNameFlag, role, keysScopeFlag)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Created API key %s for tenant %s (%s; %s)\n", key.ID, key.Tenant, key.Role, strings.Join(key.Scopes, ", "))
+		fmt.Fprintf(out, "Key: %s\n", secret)
+		fmt.Fprintln(out, "Store it now: it cannot be shown again.")
+		return nil
+	},
+}
+
+var serveKeysListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List API keys",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := openKeysStorage(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		keys, err := db.APIKeys(cmd.Context(), keysTenantFlag)
+		if err != nil {
+			return err
+		}
+		if len(keys) == 0 {
+			fmt.Fprintln(cmd.OutOrStdout(), "No API keys")
+			return nil
+		}
+
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tTENANT\tNAME\tROLE\tSCOPES\tCREATED\tLAST USED\tSTATUS")
+		for _, k := range keys {
+			status := "active"
+			if k.Revoked() {
+				status = "revoked " + k.RevokedAt.Format(time.DateOnly)
+			}
+			used := "never"
+			if k.LastUsedAt != nil {
+				used = k.LastUsedAt.Format(time.DateTime)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", k.ID, k.Tenant, k.Name, k.Role,
+				strings.Join(k.Scopes, ","), k.CreatedAt.Format(time.DateOnly), used, status)
+		}
+		return tw.Flush()
+	},
+}
+
+var serveKeysRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>",
+	Short: "Revoke an API key",
+	Long: `Revoke an API key. Running servers stop accepting it within 30 seconds; its
+usage history is kept.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		db, err := openKeysStorage(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		key, err := db.RevokeAPIKey(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Revoked API key %s (%s/%s)\n", key.ID, key.Tenant, key.Name)
+		return nil
+	},
+}
+
+var serveKeysUsageCmd = &cobra.Command{
+	Use:   "usage <id>",
+	Short: "Show the usage of an API key",
+	Long: `Show the requests, simulations and RPC calls of an API key per UTC day, for
+cost attribution. Counts cover every replica sharing the storage database.`,
+	Example: `  erst serve keys usage 3f9a0c12e4b7 --since 2026-03-01`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		since := time.Now().UTC().AddDate(0, 0, -30)
+		if keysSinceFlag != "" {
+			t, err := time.Parse(time.DateOnly, keysSinceFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			since = t
+		}
+
+		db, err := openKeysStorage(cmd)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		key, err := db.APIKey(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		days, err := db.UsageSince(cmd.Context(), key.ID, since)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "API key %s (%s/%s) since %s\n", key.ID, key.Tenant, key.Name, since.Format(time.DateOnly))
+		tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(tw, "DAY\tREQUESTS\tSIMULATIONS\tRPC CALLS\t")
+		var total storage.Usage
+		for _, d := range days {
+			total = total.Add(d.Usage)
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t\n", d.Day, d.Requests, d.Simulations, d.RPCCalls)
+		}
+		fmt.Fprintf(tw, "total\t%d\t%d\t%d\t\n", total.Requests, total.Simulations, total.RPCCalls)
+		return tw.Flush()
+	},
+}
+
+// openKeysStorage opens the serve storage database, which key management
+// cannot do without
+func openKeysStorage(cmd *cobra.Command) (*storage.DB, error) {
+	db, err := openServeStorage(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	if db == nil {
+		return nil, fmt.Errorf("API keys are kept in the serve storage database: set --storage or ERST_STORAGE_DSN")
+	}
+	return db, nil
+}
+
+func init() {
+	serveKeysCmd.PersistentFlags().StringVar(&serveStorageDSN, "storage", "", "Database holding the keys: postgres:// URL or SQLite path (default $ERST_STORAGE_DSN)")
+
+	serveKeysCreateCmd.Flags().StringVar(&keysTenantFlag, "tenant", "", "Tenant the key belongs to (required)")
+	serveKeysCreateCmd.Flags().StringVar(&keysNameFlag, "name", "", "Label for the key, such as the service using it")
+	serveKeysCreateCmd.Flags().StringVar(&keysRoleFlag, "role", string(server.RoleAnalyst), "Output policy role: admin, developer or analyst")
+	serveKeysCreateCmd.Flags().StringSliceVar(&keysScopeFlag, "scope", nil, "Scope to grant: debug, jobs, sessions or keys (repeatable; default debug, jobs and sessions)")
+	_ = serveKeysCreateCmd.MarkFlagRequired("tenant")
+
+	serveKeysListCmd.Flags().StringVar(&keysTenantFlag, "tenant", "", "Only keys of this tenant")
+	serveKeysUsageCmd.Flags().StringVar(&keysSinceFlag, "since", "", "First day to report, YYYY-MM-DD (default 30 days ago)")
+
+	serveKeysCmd.AddCommand(serveKeysCreateCmd, serveKeysListCmd, serveKeysRevokeCmd, serveKeysUsageCmd)
+	serveCmd.AddCommand(serveKeysCmd)
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runKeysCmd(t *testing.T, cmd *cobra.Command, args ...string) string {
+	t.Helper()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetContext(context.Background())
+	defer cmd.SetOut(nil)
+	require.NoError(t, cmd.RunE(cmd, args))
+	return out.String()
+}
+
+func TestServeKeys(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	serveStorageDSN = filepath.Join(t.TempDir(), "keys.db")
+	keysTenantFlag, keysNameFlag, keysRoleFlag, keysScopeFlag = "payments", "ci", "developer", []string{"debug"}
+	defer func() {
+		serveStorageDSN, keysTenantFlag, keysNameFlag, keysRoleFlag, keysScopeFlag = "", "", "", "analyst", nil
+	}()
+
+	out := runKeysCmd(t, serveKeysCreateCmd)
+	m := regexp.MustCompile(`Created API key (\w+) for tenant payments \(developer; debug\)\nKey: erst_\w+`).FindStringSubmatch(out)
+	require.NotNil(t, m, out)
+	id := m[1]
+
+	out = runKeysCmd(t, serveKeysListCmd)
+	assert.Contains(t, out, id)
+	assert.Contains(t, out, "active")
+
+	out = runKeysCmd(t, serveKeysUsageCmd, id)
+	assert.Regexp(t, `total\s+0\s+0\s+0`, out)
+
+	out = runKeysCmd(t, serveKeysRevokeCmd, id)
+	assert.Contains(t, out, "Revoked API key "+id)
+	assert.Contains(t, runKeysCmd(t, serveKeysListCmd), "revoked")
+
+	keysRoleFlag = "root"
+	assert.Error(t, serveKeysCreateCmd.RunE(serveKeysCreateCmd, nil))
+}
+
+func TestServeKeysNeedStorage(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	t.Setenv("ERST_STORAGE_DSN", "")
+	serveKeysListCmd.SetContext(context.Background())
+	err := serveKeysListCmd.RunE(serveKeysListCmd, nil)
+	assert.ErrorContains(t, err, "--storage")
+}
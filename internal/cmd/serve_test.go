@@ -0,0 +1,51 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/server"
+	"github.com/dotandev/hintents/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenServeStorage(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("ERST_HOME", home)
+	t.Setenv("ERST_STORAGE_DSN", "")
+
+	db, err := openServeStorage(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, db, "no storage is configured")
+
+	path := filepath.Join(home, "serve.db")
+	require.NoError(t, os.WriteFile(filepath.Join(home, "config.json"),
+		[]byte(`{"storage": {"dsn": "`+path+`", "max_open_conns": 4}}`), 0600))
+	db, err = openServeStorage(context.Background())
+	require.NoError(t, err)
+	require.NotNil(t, db)
+	assert.Equal(t, storage.SQLite, db.Dialect())
+	db.Close()
+
+	t.Setenv("ERST_STORAGE_DSN", "mysql://db/erst")
+	_, err = openServeStorage(context.Background())
+	assert.Error(t, err, "the environment overrides the config file")
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	var out bytes.Buffer
+	serveOpenAPICmd.SetOut(&out)
+	defer serveOpenAPICmd.SetOut(nil)
+	require.NoError(t, serveOpenAPICmd.RunE(serveOpenAPICmd, nil))
+
+	want, err := server.OpenAPI()
+	require.NoError(t, err)
+	assert.Equal(t, string(want), out.String())
+}
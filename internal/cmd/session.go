@@ -4,29 +4,67 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"time"
 
+	"github.com/dotandev/hintents/internal/logger"
 	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/visualizer"
 	"github.com/spf13/cobra"
 )
 
 var (
-	sessionIDFlag string
+	sessionIDFlag                  string
+	sessionSelectFlag              string
+	sessionListIncludeArchivedFlag bool
 )
 
-// currentSessionData holds the active session context from debug command
-var currentSessionData *session.SessionData
+// sessions tracks the sessions this process works on. Execute replaces it
+// with the manager main hands in; until then nothing outlives the process.
+var sessions = session.NewManager("")
 
-// SetCurrentSession stores the active session for later saving
+// SetCurrentSession makes data the active session for later saving
 func SetCurrentSession(data *session.SessionData) {
-	currentSessionData = data
+	if err := sessions.Activate(data); err != nil {
+		logger.Logger.Warn("Failed to activate session", "error", err)
+	}
 }
 
-// GetCurrentSession returns the active session if any
+// GetCurrentSession returns the active session of this process if any
 func GetCurrentSession() *session.SessionData {
-	return currentSessionData
+	return sessions.Current()
+}
+
+// resolveSession returns the session a follow-up command targets: the one
+// named by id, else the current one, which may have been selected by an
+// earlier 'erst session resume' or 'erst session save'
+func resolveSession(ctx context.Context, id string) (*session.SessionData, error) {
+	if id == "" {
+		if data := sessions.Current(); data != nil {
+			return data, nil
+		}
+	}
+	store, err := session.NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session store: %w", err)
+	}
+	defer store.Close()
+
+	data, err := sessions.Resolve(ctx, store, id)
+	switch {
+	case errors.Is(err, session.ErrNoActiveSession):
+		return nil, fmt.Errorf("%w. Run 'erst debug <tx-hash>' or 'erst session resume <id>' first, or pass --session <id>", err)
+	case err != nil:
+		return nil, fmt.Errorf("session '%s' not found or failed to load: %w", id, err)
+	}
+	if data.SchemaVersion > session.SchemaVersion {
+		return nil, fmt.Errorf("session was created with a newer version of erst (schema v%d > v%d). Please upgrade erst", data.SchemaVersion, session.SchemaVersion)
+	}
+	return data, nil
 }
 
 var sessionCmd = &cobra.Command{
@@ -45,7 +83,11 @@ Available subcommands:
   save    - Save current session to disk
   resume  - Restore a saved session
   list    - View all saved sessions
-  delete  - Remove a saved session`,
+  delete  - Remove a saved session
+  context - Attach anchor, SEP flow and customer reference
+  report  - Summarize sessions by anchor and SEP flow
+  verify  - Replay a session and check its fingerprint
+  archive - Move old sessions into compressed archive files`,
 	Example: `  # Save current debug session
   erst session save
 
@@ -64,30 +106,46 @@ var sessionSaveCmd = &cobra.Command{
 	Short: "Save the current debugging session",
 	Long: `Save the current debug session state to disk for later resumption.
 
-You must run 'erst debug <tx-hash>' first to create an active session.
-The session ID can be auto-generated or specified with --id flag.`,
+The current session is saved: the one created by 'erst debug <tx-hash>' or
+resumed with 'erst session resume'. --session saves another active or stored
+session instead. The session ID can be auto-generated or specified with --id
+flag. The saved session becomes current for later commands.`,
 	Example: `  # Save with auto-generated ID
   erst session save
 
   # Save with custom ID
-  erst session save --id my-debug-session`,
+  erst session save --id my-debug-session
+
+  # Save with anchor context
+  erst session save --anchor testanchor.stellar.org --sep-flow sep24 --customer-ref W-1042
+
+  # Save a session other than the current one
+  erst session save --session abc123`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
 
 		// Check if we have an active session
-		data := GetCurrentSession()
-		if data == nil {
-			return fmt.Errorf("Error: no active session to save. Run 'erst debug <tx-hash>' first")
+		data, err := resolveSession(ctx, sessionSelectFlag)
+		if err != nil {
+			return fmt.Errorf("Error: nothing to save: %w", err)
 		}
 
 		// Generate or use provided ID
-		if sessionIDFlag != "" {
-			data.ID = sessionIDFlag
+		if sessionIDFlag != "" && sessionIDFlag != data.ID {
+			copied := *data
+			copied.ID = sessionIDFlag
+			data = &copied
 		} else if data.ID == "" {
 			data.ID = session.GenerateID(data.TxHash)
 		}
 
+		appContext, err := sessionContextFromFlags()
+		if err != nil {
+			return err
+		}
+		data.Context = data.Context.Merge(appContext)
+
 		data.Status = "saved"
 		data.LastAccessAt = time.Now()
 
@@ -108,11 +166,20 @@ The session ID can be auto-generated or specified with --id flag.`,
 		if err := store.Save(ctx, data); err != nil {
 			return fmt.Errorf("Error: failed to save session: %w", err)
 		}
+		if err := sessions.Activate(data); err != nil {
+			return err
+		}
+		if err := sessions.Select(data.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 
 		fmt.Printf("Session saved: %s\n", data.ID)
 		fmt.Printf("  Transaction: %s\n", data.TxHash)
 		fmt.Printf("  Network: %s\n", data.Network)
 		fmt.Printf("  Created: %s\n", data.CreatedAt.Format(time.RFC3339))
+		if !data.Context.IsZero() {
+			fmt.Printf("  Context: %s\n", data.Context)
+		}
 
 		return nil
 	},
@@ -124,6 +191,10 @@ var sessionResumeCmd = &cobra.Command{
 	Long: `Resume a previously saved debug session by ID. This restores all transaction data,
 simulation results, and analysis context from the saved session.
 
+The resumed session becomes current: later commands such as 'erst export'
+use it until another session is resumed or saved. Pass --session <id> to
+them to target a different one.
+
 Use 'erst session list' to see available sessions.`,
 	Example: `  # Resume a session
   erst session resume abc123
@@ -159,9 +230,14 @@ Use 'erst session list' to see available sessions.`,
 			return fmt.Errorf("Error: session was created with a newer version of erst (schema v%d > v%d). Please upgrade erst", data.SchemaVersion, session.SchemaVersion)
 		}
 
-		// Update status and make it current
+		// Update status and make it current for this and later invocations
 		data.Status = "resumed"
-		SetCurrentSession(data)
+		if err := sessions.Activate(data); err != nil {
+			return err
+		}
+		if err := sessions.Select(data.ID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 
 		// Display session info
 		fmt.Printf("Session resumed: %s\n", data.ID)
@@ -169,6 +245,15 @@ Use 'erst session list' to see available sessions.`,
 		fmt.Printf("  Network: %s\n", data.Network)
 		fmt.Printf("  Created: %s\n", data.CreatedAt.Format(time.RFC3339))
 		fmt.Printf("  Last accessed: %s\n", data.LastAccessAt.Format(time.RFC3339))
+		if !data.Context.IsZero() {
+			fmt.Printf("  Context: %s\n", data.Context)
+		}
+		if data.Partial {
+			fmt.Printf("  Partial: transaction data was incomplete\n")
+			for _, skipped := range data.SkippedAnalyses {
+				fmt.Printf("    skipped %s\n", skipped)
+			}
+		}
 
 		// Show transaction envelope info
 		if data.EnvelopeXdr != "" {
@@ -203,12 +288,26 @@ var sessionListCmd = &cobra.Command{
 	Short: "List all saved debugging sessions",
 	Long: `List all saved debug sessions, ordered by most recently accessed.
 
-Displays session ID, network, last access time, and transaction hash.`,
+Displays session ID, network, last access time, and transaction hash, plus
+the anchor, SEP flow and customer reference when any session has them.
+--anchor, --sep-flow and --customer-ref only list matching sessions.
+--include-archived also lists sessions moved to archive files by
+'erst session archive', with a Status column telling them apart.`,
 	Example: `  # List all sessions
-  erst session list`,
+  erst session list
+
+  # Include archived sessions
+  erst session list --include-archived
+
+  # List SEP-24 sessions of one anchor
+  erst session list --anchor testanchor.stellar.org --sep-flow sep24`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx := cmd.Context()
+		filter, err := sessionContextFromFlags()
+		if err != nil {
+			return err
+		}
 
 		// Open session store
 		store, err := session.NewStore()
@@ -223,10 +322,20 @@ Displays session ID, network, last access time, and transaction hash.`,
 		}
 
 		// List sessions
-		sessions, err := store.List(ctx, 50)
+		sessions, err := store.ListByContext(ctx, filter, 50)
 		if err != nil {
 			return fmt.Errorf("Error: failed to list sessions: %w", err)
 		}
+		if sessionListIncludeArchivedFlag {
+			archived, err := store.ListArchived(ctx, filter, 50)
+			if err != nil {
+				return fmt.Errorf("Error: failed to list archived sessions: %w", err)
+			}
+			sessions = append(sessions, archived...)
+			sort.SliceStable(sessions, func(i, j int) bool {
+				return sessions[i].LastAccessAt.After(sessions[j].LastAccessAt)
+			})
+		}
 
 		if len(sessions) == 0 {
 			fmt.Println("No saved sessions found.")
@@ -234,17 +343,37 @@ Displays session ID, network, last access time, and transaction hash.`,
 		}
 
 		fmt.Printf("Saved sessions (%d):\n\n", len(sessions))
-		fmt.Printf("%-20s %-12s %-20s %-66s\n", "ID", "Network", "Last Accessed", "Transaction Hash")
-		fmt.Println("--------------------------------------------------------------------------------")
-
+		withContext := false
+		for _, s := range sessions {
+			withContext = withContext || !s.Context.IsZero()
+		}
+		// Porcelain rows always carry every column so scripts can rely on them
+		withStatus := sessionListIncludeArchivedFlag || visualizer.Porcelain()
+		withContext = withContext || visualizer.Porcelain()
+		headers := []string{"ID", "Network", "Last Accessed", "Transaction Hash"}
+		if withStatus {
+			headers = append(headers, "Status")
+		}
+		if withContext {
+			headers = append(headers, "Anchor", "SEP Flow", "Customer Ref")
+		}
+		table := visualizer.NewTable(headers...)
 		for _, s := range sessions {
 			lastAccess := s.LastAccessAt.Format("2006-01-02 15:04")
 			txHash := s.TxHash
 			if len(txHash) > 64 {
 				txHash = txHash[:64] + "..."
 			}
-			fmt.Printf("%-20s %-12s %-20s %-66s\n", s.ID, s.Network, lastAccess, txHash)
+			row := []string{s.ID, s.Network, lastAccess, txHash}
+			if withStatus {
+				row = append(row, s.Status)
+			}
+			if withContext {
+				row = append(row, orDash(s.Context.Anchor), orDash(s.Context.SEPFlow), orDash(s.Context.CustomerRef))
+			}
+			table.AddRow(row...)
 		}
+		table.Render(os.Stdout)
 
 		return nil
 	},
@@ -275,6 +404,9 @@ Use 'erst session list' to see available sessions.`,
 			return fmt.Errorf("Error: failed to delete session '%s': %w", sessionID, err)
 		}
 
+		if err := sessions.Release(sessionID); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
 		fmt.Printf("Session deleted: %s\n", sessionID)
 		return nil
 	},
@@ -282,7 +414,12 @@ Use 'erst session list' to see available sessions.`,
 
 func init() {
 	sessionSaveCmd.Flags().StringVar(&sessionIDFlag, "id", "", "Custom session ID (default: auto-generated)")
+	sessionSaveCmd.Flags().StringVar(&sessionSelectFlag, "session", "", "Session to save (default: the current session)")
+	addSessionContextFlags(sessionSaveCmd)
+	addSessionContextFlags(sessionListCmd)
+	sessionListCmd.Flags().BoolVar(&sessionListIncludeArchivedFlag, "include-archived", false, "Also list sessions moved to archive files")
 
+	supportPorcelain(sessionListCmd)
 	sessionCmd.AddCommand(sessionSaveCmd)
 	sessionCmd.AddCommand(sessionResumeCmd)
 	sessionCmd.AddCommand(sessionListCmd)
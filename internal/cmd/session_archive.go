@@ -0,0 +1,87 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/spf13/cobra"
+)
+
+var sessionArchiveOlderThanFlag string
+
+var sessionArchiveCmd = &cobra.Command{
+	Use:   "archive --older-than <age>",
+	Short: "Move old sessions into compressed archive files",
+	Long: `Move sessions created more than --older-than ago out of the session database
+into a zstd-compressed archive file in the erst data directory, freeing the
+primary store without deleting them.
+
+Archived sessions stay indexed: 'erst session list --include-archived' lists
+them, and 'erst session resume' and other commands that take a session ID
+read them from the archive. Saving an archived session makes it live again.
+Deleting one removes it from the index, and the archive file once every
+session in it is deleted.
+
+Automatic cleanup still deletes sessions nobody accessed for 30 days; archive
+sessions you want to keep before then.`,
+	Example: `  erst session archive --older-than 90d
+  erst session archive --older-than 72h`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if sessionArchiveOlderThanFlag == "" {
+			return fmt.Errorf("flag --older-than is required")
+		}
+		age, err := parseAge(sessionArchiveOlderThanFlag)
+		if err != nil {
+			return err
+		}
+		dir, err := session.ArchiveDir()
+		if err != nil {
+			return err
+		}
+
+		store, err := session.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to open session store: %w", err)
+		}
+		defer store.Close()
+
+		path, n, err := store.Archive(cmd.Context(), dir, time.Now().Add(-age))
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			fmt.Printf("No sessions older than %s to archive.\n", sessionArchiveOlderThanFlag)
+			return nil
+		}
+		fmt.Printf("Archived %d session(s) to %s\n", n, path)
+		return nil
+	},
+}
+
+// parseAge parses a duration that may also be given in days, such as 90d
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q: use a number of days such as 90d, or a duration such as 72h", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q: use a number of days such as 90d, or a duration such as 72h", s)
+	}
+	return d, nil
+}
+
+func init() {
+	sessionArchiveCmd.Flags().StringVar(&sessionArchiveOlderThanFlag, "older-than", "", "Archive sessions created longer ago than this (e.g. 90d, 72h)")
+	sessionCmd.AddCommand(sessionArchiveCmd)
+}
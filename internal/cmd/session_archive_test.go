@@ -0,0 +1,27 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAge(t *testing.T) {
+	d, err := parseAge("90d")
+	require.NoError(t, err)
+	assert.Equal(t, 90*24*time.Hour, d)
+
+	d, err = parseAge("72h")
+	require.NoError(t, err)
+	assert.Equal(t, 72*time.Hour, d)
+
+	for _, bad := range []string{"d", "-3d", "soon", "-1h"} {
+		_, err := parseAge(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionAnchorFlag      string
+	sessionSEPFlowFlag     string
+	sessionCustomerRefFlag string
+	sessionClearCtxFlag    bool
+	sessionReportJSONFlag  bool
+)
+
+// addSessionContextFlags registers --anchor, --sep-flow and --customer-ref
+// on cmd
+func addSessionContextFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&sessionAnchorFlag, "anchor", "", "Anchor the session belongs to, e.g. the anchor's home domain")
+	cmd.Flags().StringVar(&sessionSEPFlowFlag, "sep-flow", "", "SEP flow of the transaction (sep6, sep10, sep12, sep24, sep31, sep38)")
+	cmd.Flags().StringVar(&sessionCustomerRefFlag, "customer-ref", "", "Customer or transfer reference from the anchor's records")
+}
+
+// sessionContextFromFlags validates the session context flags
+func sessionContextFromFlags() (session.Context, error) {
+	return session.NewContext(sessionAnchorFlag, sessionSEPFlowFlag, sessionCustomerRefFlag)
+}
+
+var sessionContextCmd = &cobra.Command{
+	Use:   "context <session-id>",
+	Short: "Show or set the anchor, SEP flow and customer reference of a session",
+	Long: `Attach application context to a saved session so business details stay next to
+the technical data. Anchor operators can record which anchor, SEP flow (for
+example a SEP-24 withdrawal) and customer reference a transaction belongs to,
+then filter 'erst session list' and 'erst session report' by them.
+
+Without flags the current context is printed. Given flags replace only their
+own field; --clear removes the existing context first.`,
+	Example: `  erst session context abc123 --anchor testanchor.stellar.org --sep-flow sep24 --customer-ref W-1042
+  erst session context abc123
+  erst session context abc123 --clear`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		update, err := sessionContextFromFlags()
+		if err != nil {
+			return err
+		}
+
+		store, err := session.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to open session store: %w", err)
+		}
+		defer store.Close()
+
+		data, err := store.Load(ctx, args[0])
+		if err != nil {
+			return fmt.Errorf("session '%s' not found or failed to load: %w", args[0], err)
+		}
+
+		if sessionClearCtxFlag || !update.IsZero() {
+			if sessionClearCtxFlag {
+				data.Context = session.Context{}
+			}
+			data.Context = data.Context.Merge(update)
+			if err := store.Save(ctx, data); err != nil {
+				return fmt.Errorf("failed to save session: %w", err)
+			}
+			if _, active := sessions.Get(data.ID); active {
+				if err := sessions.Put(data); err != nil {
+					return err
+				}
+			}
+		}
+
+		printSessionContext(data.Context)
+		visualizer.Record("context", data.ID, data.Context.Anchor, data.Context.SEPFlow, data.Context.CustomerRef)
+		return nil
+	},
+}
+
+var sessionReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize saved sessions by anchor and SEP flow",
+	Long: `Group saved sessions by anchor and SEP flow and show, for each group, how many
+sessions it has, how many of their latest simulations failed, how many had
+incomplete transaction data and how many distinct customer references they
+cover. Sessions without context are grouped under "-".`,
+	Example: `  erst session report
+  erst session report --anchor testanchor.stellar.org
+  erst session report --sep-flow sep24 --json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		filter, err := sessionContextFromFlags()
+		if err != nil {
+			return err
+		}
+
+		store, err := session.NewStore()
+		if err != nil {
+			return fmt.Errorf("failed to open session store: %w", err)
+		}
+		defer store.Close()
+
+		sessions, err := store.ListByContext(ctx, filter, session.DefaultMaxSessions)
+		if err != nil {
+			return fmt.Errorf("failed to list sessions: %w", err)
+		}
+		summary := session.SummarizeContexts(sessions)
+
+		if sessionReportJSONFlag {
+			return writeJSON(cmd, summary)
+		}
+		if len(summary) == 0 {
+			fmt.Println("No saved sessions found.")
+			return nil
+		}
+
+		table := visualizer.NewTable("Anchor", "SEP Flow", "Sessions", "Failed", "Partial", "Customers", "Last Accessed")
+		for _, g := range summary {
+			table.AddRow(orDash(g.Anchor), orDash(g.SEPFlow), strconv.Itoa(g.Sessions), strconv.Itoa(g.Failed),
+				strconv.Itoa(g.Partial), strconv.Itoa(g.Customers), g.LastAccessAt.Format("2006-01-02 15:04"))
+		}
+		table.Render(os.Stdout)
+		return nil
+	},
+}
+
+func printSessionContext(c session.Context) {
+	if c.IsZero() {
+		fmt.Println("No context attached.")
+		return
+	}
+	fmt.Printf("  Anchor: %s\n", orDash(c.Anchor))
+	fmt.Printf("  SEP flow: %s\n", orDash(c.SEPFlow))
+	fmt.Printf("  Customer reference: %s\n", orDash(c.CustomerRef))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	addSessionContextFlags(sessionContextCmd)
+	sessionContextCmd.Flags().BoolVar(&sessionClearCtxFlag, "clear", false, "Remove the existing context before applying the flags")
+	addSessionContextFlags(sessionReportCmd)
+	sessionReportCmd.Flags().BoolVar(&sessionReportJSONFlag, "json", false, "Print the summary as JSON")
+
+	supportPorcelain(sessionContextCmd, sessionReportCmd)
+	sessionCmd.AddCommand(sessionContextCmd)
+	sessionCmd.AddCommand(sessionReportCmd)
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setContextFlags(t *testing.T, anchor, flow, ref string) {
+	t.Helper()
+	sessionAnchorFlag, sessionSEPFlowFlag, sessionCustomerRefFlag = anchor, flow, ref
+	t.Cleanup(func() { sessionAnchorFlag, sessionSEPFlowFlag, sessionCustomerRefFlag = "", "", "" })
+}
+
+func TestSessionContextFromFlags(t *testing.T) {
+	setContextFlags(t, "testanchor.stellar.org", "SEP-24", "W-1042")
+	c, err := sessionContextFromFlags()
+	require.NoError(t, err)
+	assert.Equal(t, session.Context{Anchor: "testanchor.stellar.org", SEPFlow: "sep24", CustomerRef: "W-1042"}, c)
+
+	setContextFlags(t, "", "withdrawal", "")
+	_, err = sessionContextFromFlags()
+	assert.ErrorContains(t, err, "unknown SEP flow")
+}
+
+func TestRecordCheckpointKeepsContext(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	ctx := context.Background()
+
+	first := &session.SessionData{TxHash: "abcd", Context: session.Context{Anchor: "acme", SEPFlow: "sep24"}}
+	require.NoError(t, first.AddRun(session.Run{Name: session.DefaultRunName, SimResponseJSON: "{}"}))
+	_, err := recordCheckpoint(ctx, "s1", first)
+	require.NoError(t, err)
+
+	next := &session.SessionData{TxHash: "abcd", Context: session.Context{CustomerRef: "W-1042"}}
+	require.NoError(t, next.AddRun(session.Run{Name: "retry", SimResponseJSON: "{}"}))
+	updated, err := recordCheckpoint(ctx, "s1", next)
+	require.NoError(t, err)
+	assert.Equal(t, session.Context{Anchor: "acme", SEPFlow: "sep24", CustomerRef: "W-1042"}, updated.Context)
+}
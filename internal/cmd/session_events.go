@@ -0,0 +1,90 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var (
+	sessionEventsTopics     []string
+	sessionEventsContract   string
+	sessionEventsType       string
+	sessionEventsCheckpoint string
+	sessionEventsJSON       bool
+)
+
+var sessionEventsCmd = &cobra.Command{
+	Use:   "events <session-id>",
+	Short: "Filter the events of a stored simulation",
+	Long: `Query the decoded events recorded in a saved session without rerunning the
+simulation. --topic matches any topic of an event as a case-insensitive substring
+and may be repeated; an event must match every given topic. The latest
+checkpoint is used unless --checkpoint names another.`,
+	Example: `  erst session events abc123 --topic transfer
+  erst session events abc123 --topic transfer --contract CDLZ... --json
+  erst session events abc123 --type diagnostic --checkpoint original`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := loadSession(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		source := "latest run"
+		resp, err := data.ToSimulationResponse()
+		if sessionEventsCheckpoint != "" {
+			run, findErr := data.FindRun(sessionEventsCheckpoint)
+			if findErr != nil {
+				return findErr
+			}
+			source = "checkpoint " + run.Name
+			resp, err = run.ToSimulationResponse()
+		}
+		if err != nil {
+			return fmt.Errorf("session %s: %w", data.ID, err)
+		}
+
+		filter := simulator.EventFilter{
+			ContractID: sessionEventsContract,
+			Type:       sessionEventsType,
+			Topics:     sessionEventsTopics,
+		}
+		events := filter.Filter(resp.Events)
+
+		if sessionEventsJSON {
+			if events == nil {
+				events = []simulator.Event{}
+			}
+			return writeJSON(cmd, events)
+		}
+
+		fmt.Printf("Events in %s (%s): %d of %d match\n", data.ID, source, len(events), len(resp.Events))
+		for _, e := range events {
+			if !e.Typed() {
+				fmt.Printf("  [%d] %s\n", e.Index, e.Raw)
+				continue
+			}
+			fmt.Printf("  [%d] %s %s\n", e.Index, e.Type, e.ContractID)
+			fmt.Printf("      topics: %s\n", strings.Join(e.Topics, ", "))
+			if e.Data != "" {
+				fmt.Printf("      data:   %s\n", e.Data)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	sessionEventsCmd.Flags().StringArrayVar(&sessionEventsTopics, "topic", nil, "Only events with a topic containing this text (repeatable)")
+	sessionEventsCmd.Flags().StringVar(&sessionEventsContract, "contract", "", "Only events emitted by this contract")
+	sessionEventsCmd.Flags().StringVar(&sessionEventsType, "type", "", "Only events of this type: contract, system or diagnostic")
+	sessionEventsCmd.Flags().StringVar(&sessionEventsCheckpoint, "checkpoint", "", "Query this checkpoint instead of the latest run")
+	sessionEventsCmd.Flags().BoolVar(&sessionEventsJSON, "json", false, "Output matching events as JSON")
+	sessionCmd.AddCommand(sessionEventsCmd)
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dotandev/hintents/internal/compare"
+	"github.com/dotandev/hintents/internal/hooks"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var sessionRunsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "Inspect the simulation checkpoints recorded in a session",
+	Long: `A session keeps every simulation of its transaction as a named checkpoint.
+Record runs with 'erst debug <tx-hash> --session <id> --checkpoint <name>', for
+example before and after a state override, then compare them.`,
+	Example: `  erst session runs list abc123
+  erst session runs diff abc123 original fixed-wasm`,
+}
+
+var sessionRunsListCmd = &cobra.Command{
+	Use:   "list <session-id>",
+	Short: "List the checkpoints of a session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := loadSession(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		if len(data.Runs) == 0 {
+			fmt.Printf("Session %s has no recorded checkpoints.\n", data.ID)
+			return nil
+		}
+
+		fmt.Printf("Checkpoints in %s (%d):\n\n", data.ID, len(data.Runs))
+		table := visualizer.NewTable("Name", "Created", "Status", "Artifacts", "Description")
+		for _, run := range data.Runs {
+			status := "unknown"
+			if resp, err := run.ToSimulationResponse(); err == nil {
+				status = resp.Status
+			}
+			table.AddRow(run.Name, run.CreatedAt.Local().Format("2006-01-02 15:04"), status, describeArtifacts(run), run.Description)
+		}
+		table.Render(os.Stdout)
+		return nil
+	},
+}
+
+// describeArtifacts summarizes the files a checkpoint produced
+func describeArtifacts(run session.Run) string {
+	switch {
+	case len(run.Artifacts) == 0:
+		return "-"
+	case run.OutputDir != "":
+		return fmt.Sprintf("%d in %s", len(run.Artifacts), run.OutputDir)
+	case len(run.Artifacts) == 1:
+		return run.Artifacts[0].Name
+	}
+	return fmt.Sprintf("%d files", len(run.Artifacts))
+}
+
+var sessionRunsDiffCmd = &cobra.Command{
+	Use:   "diff <session-id> <checkpoint-a> <checkpoint-b>",
+	Short: "Compare the simulation results of two checkpoints",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := loadSession(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+
+		a, err := data.FindRun(args[1])
+		if err != nil {
+			return err
+		}
+		b, err := data.FindRun(args[2])
+		if err != nil {
+			return err
+		}
+		resA, err := a.ToSimulationResponse()
+		if err != nil {
+			return fmt.Errorf("checkpoint %s: %w", a.Name, err)
+		}
+		resB, err := b.ToSimulationResponse()
+		if err != nil {
+			return fmt.Errorf("checkpoint %s: %w", b.Name, err)
+		}
+
+		logFilter, err := newLogFilter()
+		if err != nil {
+			return err
+		}
+		diffResults(resA, resB, a.Name, b.Name, logFilter)
+
+		report := &checkpointCompareReport{
+			TxHash:      data.TxHash,
+			SessionID:   data.ID,
+			Checkpoints: []string{a.Name, b.Name},
+			StatusMatch: resA.Status == resB.Status,
+			Events:      compare.Summarize(compare.Events(resA.Events, resB.Events)),
+			Results:     []*simulator.SimulationResponse{resA, resB},
+		}
+		runPostCompareHooks(cmd.Context(), hooks.Payload{TxHash: data.TxHash, Network: data.Network, SessionID: data.ID}, report)
+		return nil
+	},
+}
+
+// checkpointCompareReport is the file passed to post-compare hooks after
+// session runs diff
+type checkpointCompareReport struct {
+	TxHash      string                          `json:"tx_hash"`
+	SessionID   string                          `json:"session_id"`
+	Checkpoints []string                        `json:"checkpoints"`
+	StatusMatch bool                            `json:"status_match"`
+	Events      compare.Summary                 `json:"events"`
+	Results     []*simulator.SimulationResponse `json:"results"`
+}
+
+// loadSession opens the store and loads a saved session by ID
+func loadSession(ctx context.Context, id string) (*session.SessionData, error) {
+	store, err := session.NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("Error: failed to open session store: %w", err)
+	}
+	defer store.Close()
+
+	data, err := store.Load(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("Error: session '%s' not found or failed to load: %w", id, err)
+	}
+	if data.SchemaVersion > session.SchemaVersion {
+		return nil, fmt.Errorf("Error: session was created with a newer version of erst (schema v%d > v%d). Please upgrade erst", data.SchemaVersion, session.SchemaVersion)
+	}
+	return data, nil
+}
+
+// recordCheckpoint adds the latest run of current to the saved session id,
+// which must be for the same transaction. If no session has that ID yet,
+// current is saved under it.
+func recordCheckpoint(ctx context.Context, id string, current *session.SessionData) (*session.SessionData, error) {
+	if len(current.Runs) == 0 {
+		return nil, fmt.Errorf("no simulation run to record")
+	}
+	run := current.Runs[len(current.Runs)-1]
+
+	store, err := session.NewStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session store: %w", err)
+	}
+	defer store.Close()
+
+	data, err := store.Load(ctx, id)
+	switch {
+	case errors.Is(err, session.ErrNotFound):
+		data = current
+		data.ID = id
+	case err != nil:
+		return nil, fmt.Errorf("session '%s' failed to load: %w", id, err)
+	case data.TxHash != current.TxHash:
+		return nil, fmt.Errorf("session %s is for transaction %s, not %s", id, data.TxHash, current.TxHash)
+	default:
+		if err := data.AddRun(run); err != nil {
+			return nil, err
+		}
+		data.Context = data.Context.Merge(current.Context)
+	}
+
+	data.Status = "saved"
+	data.LastAccessAt = time.Now()
+	if err := store.Save(ctx, data); err != nil {
+		return nil, fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	return data, nil
+}
+
+func init() {
+	supportPorcelain(sessionRunsListCmd)
+	sessionRunsCmd.AddCommand(sessionRunsListCmd)
+	sessionRunsDiffCmd.Flags().BoolVar(&noHooksFlag, "no-hooks", false, "Do not run the post-compare hooks of the config file")
+	sessionRunsCmd.AddCommand(sessionRunsDiffCmd)
+	sessionCmd.AddCommand(sessionRunsCmd)
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/artifact"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordCheckpoint(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	ctx := context.Background()
+
+	first := &session.SessionData{Network: "testnet", TxHash: "abcd"}
+	require.NoError(t, first.AddRun(session.Run{Name: session.DefaultRunName, SimResponseJSON: `{"status":"error"}`}))
+	created, err := recordCheckpoint(ctx, "s1", first)
+	require.NoError(t, err)
+	assert.Equal(t, "s1", created.ID)
+
+	next := &session.SessionData{Network: "testnet", TxHash: "abcd"}
+	require.NoError(t, next.AddRun(session.Run{Name: "override-a", SimResponseJSON: `{"status":"success"}`}))
+	updated, err := recordCheckpoint(ctx, "s1", next)
+	require.NoError(t, err)
+	assert.Len(t, updated.Runs, 2)
+
+	loaded, err := loadSession(ctx, "s1")
+	require.NoError(t, err)
+	require.Len(t, loaded.Runs, 2)
+	assert.Equal(t, "override-a", loaded.Runs[1].Name)
+	assert.Equal(t, `{"status":"success"}`, loaded.SimResponseJSON)
+
+	other := &session.SessionData{TxHash: "other"}
+	require.NoError(t, other.AddRun(session.Run{Name: "x", SimResponseJSON: "{}"}))
+	_, err = recordCheckpoint(ctx, "s1", other)
+	assert.ErrorContains(t, err, "is for transaction abcd")
+}
+
+func TestDescribeArtifacts(t *testing.T) {
+	assert.Equal(t, "-", describeArtifacts(session.Run{}))
+	assert.Equal(t, "abcd.trace.json", describeArtifacts(session.Run{Artifacts: []artifact.Artifact{{Name: "abcd.trace.json"}}}))
+	assert.Equal(t, "2 in out/abcd", describeArtifacts(session.Run{
+		OutputDir: "out/abcd",
+		Artifacts: []artifact.Artifact{{Name: "trace.json"}, {Name: "report.json"}},
+	}))
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSession(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	ctx := context.Background()
+	saved := sessions
+	sessions = session.NewManager("")
+	t.Cleanup(func() { sessions = saved })
+
+	_, err := resolveSession(ctx, "")
+	assert.ErrorContains(t, err, "--session <id>")
+
+	store, err := session.NewStore()
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, &session.SessionData{ID: "stored", TxHash: "abcd"}))
+	require.NoError(t, store.Close())
+
+	SetCurrentSession(&session.SessionData{ID: "live", TxHash: "ef01"})
+	data, err := resolveSession(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "live", data.ID)
+
+	data, err = resolveSession(ctx, "stored")
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", data.TxHash)
+	assert.Equal(t, "live", GetCurrentSession().ID, "targeting a session does not make it current")
+
+	_, err = resolveSession(ctx, "missing")
+	assert.ErrorContains(t, err, "session 'missing' not found")
+}
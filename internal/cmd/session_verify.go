@@ -0,0 +1,123 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/spf13/cobra"
+)
+
+var sessionVerifyCheckpointFlag string
+
+var sessionVerifyCmd = &cobra.Command{
+	Use:   "verify <session-id>",
+	Short: "Replay a session and check it reproduces its fingerprint",
+	Long: `Replay the simulation stored in a session and compare the result with the
+fingerprint recorded when it was created: hashes of the envelope, result meta,
+every ledger entry, the simulator binary, the cost model and the simulation
+result. A matching fingerprint proves this machine reproduces the recorded
+result exactly; a mismatch names what differs.
+
+Fingerprints are recorded by 'erst debug' for single-network runs. Sessions
+saved by older releases, or from --compare-network runs, cannot be verified.`,
+	Example: `  erst session verify abc123
+  erst session verify abc123 --checkpoint fixed-wasm`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := loadSession(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		run := session.Run{
+			Name:            "latest run",
+			SimRequestJSON:  data.SimRequestJSON,
+			SimResponseJSON: data.SimResponseJSON,
+			Fingerprint:     data.Fingerprint,
+		}
+		if sessionVerifyCheckpointFlag != "" {
+			found, err := data.FindRun(sessionVerifyCheckpointFlag)
+			if err != nil {
+				return err
+			}
+			run = *found
+			run.Name = "checkpoint " + run.Name
+		}
+		if run.Fingerprint == nil {
+			return fmt.Errorf("session %s has no fingerprint for its %s; replay it with 'erst debug' to record one", data.ID, run.Name)
+		}
+
+		runner, err := simulator.NewRunner("", false)
+		if err != nil {
+			return fmt.Errorf("failed to initialize simulator: %w", err)
+		}
+		digest, err := simulator.BinaryDigest(runner.BinaryPath)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Replaying the %s of session %s...\n", run.Name, data.ID)
+		replayed, err := replayFingerprint(runner, &run, digest)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Recorded fingerprint: %s\n", run.Fingerprint.Digest())
+		fmt.Printf("Replayed fingerprint: %s\n", replayed.Digest())
+		diffs := run.Fingerprint.Diff(replayed)
+		if len(diffs) == 0 {
+			fmt.Println("Fingerprint matches: the replay reproduces the recorded result.")
+			return nil
+		}
+
+		fmt.Println("Fingerprint mismatch; these differ:")
+		for _, d := range diffs {
+			fmt.Printf("  - %s\n", d)
+		}
+		if !slices.Contains(diffs, "result") {
+			fmt.Println("The result still matches the recorded one.")
+		}
+		return fmt.Errorf("session %s does not reproduce: %s differ", data.ID, strings.Join(diffs, ", "))
+	},
+}
+
+// replayFingerprint runs the stored request of run again and fingerprints
+// the replay, taking simulatorDigest as the simulator's identity
+func replayFingerprint(runner simulator.RunnerInterface, run *session.Run, simulatorDigest string) (*session.Fingerprint, error) {
+	req, err := run.ToSimulationRequest()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := runner.Run(req)
+	if err != nil {
+		return nil, fmt.Errorf("replay failed: %w", err)
+	}
+	return session.NewFingerprint(req, resp, simulatorDigest)
+}
+
+// fingerprintRun fingerprints a debug run for the session. Sessions are
+// still recorded when the fingerprint cannot be computed.
+func fingerprintRun(runner *simulator.Runner, req *simulator.SimulationRequest, resp *simulator.SimulationResponse) *session.Fingerprint {
+	digest, err := simulator.BinaryDigest(runner.BinaryPath)
+	if err != nil {
+		logger.Logger.Warn("Session recorded without a fingerprint", "error", err)
+		return nil
+	}
+	f, err := session.NewFingerprint(req, resp, digest)
+	if err != nil {
+		logger.Logger.Warn("Session recorded without a fingerprint", "error", err)
+		return nil
+	}
+	return f
+}
+
+func init() {
+	sessionVerifyCmd.Flags().StringVar(&sessionVerifyCheckpointFlag, "checkpoint", "", "Verify this checkpoint instead of the latest run")
+	sessionCmd.AddCommand(sessionVerifyCmd)
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayFingerprint(t *testing.T) {
+	req := &simulator.SimulationRequest{EnvelopeXdr: "AAAA", LedgerEntries: map[string]string{"k": "v"}}
+	recorded, err := session.NewFingerprint(req, &simulator.SimulationResponse{Status: "success"}, "sim")
+	require.NoError(t, err)
+	run := &session.Run{
+		Name:           session.DefaultRunName,
+		SimRequestJSON: `{"envelope_xdr":"AAAA","ledger_entries":{"k":"v"}}`,
+		Fingerprint:    recorded,
+	}
+
+	runner := new(MockRunner)
+	runner.On("Run", mock.Anything).Return(&simulator.SimulationResponse{Status: "success"}, nil).Once()
+	replayed, err := replayFingerprint(runner, run, "sim")
+	require.NoError(t, err)
+	assert.Empty(t, recorded.Diff(replayed))
+	assert.Equal(t, recorded.Digest(), replayed.Digest())
+
+	runner.On("Run", mock.Anything).Return(&simulator.SimulationResponse{Status: "error", Error: "trapped"}, nil).Once()
+	replayed, err = replayFingerprint(runner, run, "other-sim")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"simulator", "result"}, recorded.Diff(replayed))
+	runner.AssertExpectations(t)
+}
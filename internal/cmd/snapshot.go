@@ -0,0 +1,141 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/spf13/cobra"
+)
+
+// Snapshot file formats accepted by --format
+const (
+	snapshotFormatJSON = "json"
+	snapshotFormatV2   = "v2"
+)
+
+var (
+	snapshotConvertFormat   string
+	snapshotFromSessionOut  string
+	snapshotFromSessionCkpt string
+	snapshotFromSessionFmt  string
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Inspect and convert ledger state snapshots",
+	Long: `Work with the ledger state snapshots used by 'erst debug --snapshot'.
+
+Snapshots come in two formats: the soroban-cli compatible JSON format, and
+the v2 format, which stores entries in zstd-compressed chunks with an index so
+that only the entries a transaction touches are decompressed. Every command
+that reads snapshots accepts both.`,
+}
+
+var snapshotConvertCmd = &cobra.Command{
+	Use:   "convert <input> <output>",
+	Short: "Convert a snapshot between the JSON and v2 formats",
+	Example: `  erst snapshot convert state.json state.snap
+  erst snapshot convert state.snap state.json --format json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snap, err := snapshot.LoadFrom(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		if err := saveSnapshot(args[1], snap, snapshotConvertFormat); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %d entries to %s (%s)\n", len(snap.LedgerEntries), args[1], snapshotConvertFormat)
+		return nil
+	},
+}
+
+var snapshotFromSessionCmd = &cobra.Command{
+	Use:   "from-session <session-id>",
+	Short: "Write the ledger entries of a saved session to a snapshot",
+	Long: `Extract the ledger entries a saved session was simulated against into a
+standalone snapshot, which can then be edited, layered and replayed with
+'erst debug --snapshot'. The latest checkpoint is used unless --checkpoint
+names another. Sessions recorded before ledger entries were stored fall back
+to the entries in the transaction's result metadata.`,
+	Example: `  erst snapshot from-session abc123
+  erst snapshot from-session abc123 --checkpoint original -o base.snap --format v2`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := loadSession(cmd.Context(), args[0])
+		if err != nil {
+			return err
+		}
+		entries, err := sessionLedgerEntries(data, snapshotFromSessionCkpt)
+		if err != nil {
+			return err
+		}
+
+		out := snapshotFromSessionOut
+		if out == "" {
+			out = data.ID + ".snapshot.json"
+			if snapshotFromSessionFmt == snapshotFormatV2 {
+				out = data.ID + ".snap"
+			}
+		}
+		snap := snapshot.FromMap(entries)
+		if err := saveSnapshot(out, snap, snapshotFromSessionFmt); err != nil {
+			return err
+		}
+		fmt.Printf("Snapshot of session %s written to %s (%d entries)\n", data.ID, out, len(snap.LedgerEntries))
+		return nil
+	},
+}
+
+// sessionLedgerEntries returns the ledger entries recorded for a session
+// run, the latest unless checkpoint names one, falling back to the entries
+// in the session's result metadata
+func sessionLedgerEntries(data *session.SessionData, checkpoint string) (map[string]string, error) {
+	req, err := data.ToSimulationRequest()
+	if checkpoint != "" {
+		run, findErr := data.FindRun(checkpoint)
+		if findErr != nil {
+			return nil, findErr
+		}
+		req, err = run.ToSimulationRequest()
+	}
+	if err == nil && len(req.LedgerEntries) > 0 {
+		return req.LedgerEntries, nil
+	}
+
+	if data.ResultMetaXdr == "" {
+		return nil, fmt.Errorf("session %s has no recorded ledger entries or result metadata", data.ID)
+	}
+	entries, err := rpc.ExtractLedgerEntriesFromMeta(data.ResultMetaXdr)
+	if err != nil {
+		return nil, fmt.Errorf("session %s: failed to extract ledger entries from result metadata: %w", data.ID, err)
+	}
+	return entries, nil
+}
+
+// saveSnapshot writes snap to path in the named format
+func saveSnapshot(path string, snap *snapshot.Snapshot, format string) error {
+	switch format {
+	case snapshotFormatJSON:
+		return snapshot.Save(path, snap)
+	case snapshotFormatV2:
+		return snapshot.SaveChunked(path, snap)
+	default:
+		return fmt.Errorf("unknown snapshot format %q: expected %s or %s", format, snapshotFormatJSON, snapshotFormatV2)
+	}
+}
+
+func init() {
+	snapshotConvertCmd.Flags().StringVar(&snapshotConvertFormat, "format", snapshotFormatV2, "Output format: json or v2")
+	snapshotFromSessionCmd.Flags().StringVarP(&snapshotFromSessionOut, "output", "o", "", "Output file (default <session-id>.snapshot.json, or .snap for v2)")
+	snapshotFromSessionCmd.Flags().StringVar(&snapshotFromSessionCkpt, "checkpoint", "", "Use this checkpoint instead of the latest run")
+	snapshotFromSessionCmd.Flags().StringVar(&snapshotFromSessionFmt, "format", snapshotFormatJSON, "Output format: json or v2")
+	snapshotCmd.AddCommand(snapshotConvertCmd)
+	snapshotCmd.AddCommand(snapshotFromSessionCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
@@ -0,0 +1,57 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func simRequestJSON(t *testing.T, entries map[string]string) string {
+	raw, err := json.Marshal(simulator.SimulationRequest{LedgerEntries: entries})
+	require.NoError(t, err)
+	return string(raw)
+}
+
+func TestSessionLedgerEntries(t *testing.T) {
+	data := &session.SessionData{ID: "s1"}
+	require.NoError(t, data.AddRun(session.Run{Name: session.DefaultRunName, SimRequestJSON: simRequestJSON(t, map[string]string{"k1": "v1"})}))
+	require.NoError(t, data.AddRun(session.Run{Name: "override-a", SimRequestJSON: simRequestJSON(t, map[string]string{"k1": "v2", "k2": "v3"})}))
+
+	latest, err := sessionLedgerEntries(data, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"k1": "v2", "k2": "v3"}, latest)
+
+	original, err := sessionLedgerEntries(data, session.DefaultRunName)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"k1": "v1"}, original)
+
+	_, err = sessionLedgerEntries(data, "missing")
+	assert.ErrorContains(t, err, `checkpoint "missing" not found`)
+
+	_, err = sessionLedgerEntries(&session.SessionData{ID: "empty"}, "")
+	assert.ErrorContains(t, err, "no recorded ledger entries")
+}
+
+func TestSaveSnapshotFormats(t *testing.T) {
+	dir := t.TempDir()
+	snap := snapshot.FromMap(map[string]string{"k": "v"})
+
+	for _, format := range []string{snapshotFormatJSON, snapshotFormatV2} {
+		path := filepath.Join(dir, "state."+format)
+		require.NoError(t, saveSnapshot(path, snap, format))
+		loaded, err := snapshot.Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "v", loaded.ToMap()["k"], format)
+	}
+
+	assert.ErrorContains(t, saveSnapshot(filepath.Join(dir, "x"), snap, "yaml"), "unknown snapshot format")
+}
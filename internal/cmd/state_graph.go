@@ -0,0 +1,177 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/dotandev/hintents/internal/corpus"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	stateGraphLedger uint32
+	stateGraphCorpus string
+	stateGraphDir    string
+	stateGraphFormat string
+)
+
+var stateGraphCmd = &cobra.Command{
+	Use:   "state-graph [tx-hash...]",
+	Short: "Graph which transactions read and wrote the same ledger entries",
+	Long: `Build a dependency graph of a set of transactions: an edge from one
+transaction to a later one that read an entry it wrote, overwrote it, or
+wrote an entry it read. Use it to find ordering and contention problems
+when many transactions hit the same contract state.
+
+The transactions are, in application order, either:
+  - the hashes given as arguments, an incident, ordered by ledger
+  - every transaction in a ledger with --ledger
+  - the entries of a corpus with --corpus, in corpus order
+
+Written entries come from each transaction's metadata and reads from its
+Soroban footprint. --format selects text, with the most contended entries,
+dot for Graphviz, mermaid for Markdown, or json.`,
+	Example: `  erst state-graph 5c0a... 9e1f... 77ab... --network testnet
+  erst state-graph --ledger 51234567 --format dot | dot -Tsvg > deps.svg
+  erst state-graph --corpus regressions --format mermaid`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		sources := 0
+		for _, set := range []bool{len(args) > 0, stateGraphLedger != 0, stateGraphCorpus != ""} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return fmt.Errorf("give transaction hashes, --ledger or --corpus")
+		}
+		switch stateGraphFormat {
+		case "text", "dot", "mermaid", "json":
+		default:
+			return fmt.Errorf("invalid --format %q: must be text, dot, mermaid or json", stateGraphFormat)
+		}
+		if visualizer.Porcelain() && stateGraphFormat != "text" {
+			return fmt.Errorf("--porcelain only applies to --format text")
+		}
+
+		var txs []analytics.StateTx
+		if stateGraphCorpus != "" {
+			c, err := corpus.Open(stateGraphDir, stateGraphCorpus)
+			if err != nil {
+				return err
+			}
+			for _, e := range c.Entries {
+				txs = append(txs, analytics.StateTx{Hash: e.Hash, EnvelopeXdr: e.EnvelopeXdr, ResultMetaXdr: e.ResultMetaXdr})
+			}
+		} else {
+			opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(networkFlag))}
+			if rpcURLFlag != "" {
+				opts = append(opts, rpc.WithSorobanURL(rpcURLFlag))
+			}
+			client, err := rpc.NewClient(opts...)
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+			if stateGraphLedger != 0 {
+				txs, err = ledgerStateTxs(cmd.Context(), client, stateGraphLedger)
+			} else {
+				txs, err = incidentStateTxs(cmd.Context(), client, args)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		g, err := analytics.BuildStateGraph(txs)
+		if err != nil {
+			return err
+		}
+		switch stateGraphFormat {
+		case "json":
+			return writeJSON(cmd, g)
+		case "dot":
+			fmt.Print(g.DOT())
+		case "mermaid":
+			fmt.Print(g.Mermaid())
+		default:
+			writeStateGraph(os.Stdout, g)
+		}
+		return nil
+	},
+}
+
+func ledgerStateTxs(ctx context.Context, client *rpc.Client, ledger uint32) ([]analytics.StateTx, error) {
+	ledgerTxs, err := client.GetLedgerTransactions(ctx, ledger, ledger)
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]analytics.StateTx, 0, len(ledgerTxs))
+	for _, tx := range ledgerTxs {
+		txs = append(txs, analytics.StateTx{Hash: tx.Hash, Ledger: tx.Ledger, EnvelopeXdr: tx.EnvelopeXdr, ResultMetaXdr: tx.ResultMetaXdr})
+	}
+	return txs, nil
+}
+
+// incidentStateTxs fetches hashes and orders them by ledger, keeping the
+// given order within a ledger
+func incidentStateTxs(ctx context.Context, client *rpc.Client, hashes []string) ([]analytics.StateTx, error) {
+	txs := make([]analytics.StateTx, 0, len(hashes))
+	for _, hash := range hashes {
+		if err := rpc.ValidateTransactionHash(hash); err != nil {
+			return nil, fmt.Errorf("invalid transaction hash %s: %w", hash, err)
+		}
+		resp, err := client.GetTransaction(ctx, hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch transaction %s: %w", hash, err)
+		}
+		txs = append(txs, analytics.StateTx{Hash: hash, Ledger: resp.Ledger, EnvelopeXdr: resp.EnvelopeXdr, ResultMetaXdr: resp.ResultMetaXdr})
+	}
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].Ledger < txs[j].Ledger })
+	return txs, nil
+}
+
+func writeStateGraph(w io.Writer, g *analytics.StateGraph) {
+	if len(g.Edges) == 0 {
+		fmt.Fprintf(w, "No shared ledger entries between %d transaction(s)\n", len(g.Nodes))
+		return
+	}
+
+	fmt.Fprintln(w, visualizer.Heading("Dependencies"))
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  %s -> %s  %s: %s\n", e.From, e.To, strings.Join(e.Kinds, ", "), strings.Join(e.Keys, "; "))
+		visualizer.Record("dependency", e.From, e.To, strings.Join(e.Kinds, ","), strings.Join(e.Keys, "; "))
+	}
+
+	if len(g.Hotspots) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, visualizer.Heading("Contended entries"))
+		table := visualizer.NewTable("ENTRY", "WRITERS", "READERS").AlignRight(1, 2)
+		for _, h := range g.Hotspots {
+			table.AddRow(h.Key, strconv.Itoa(h.Writers), strconv.Itoa(h.Readers))
+		}
+		table.Render(w)
+	}
+	fmt.Fprintf(w, "\n%d transaction(s), %d dependencies\n", len(g.Nodes), len(g.Edges))
+}
+
+func init() {
+	stateGraphCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
+	stateGraphCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Soroban RPC URL")
+	stateGraphCmd.Flags().Uint32Var(&stateGraphLedger, "ledger", 0, "Graph every transaction in this ledger")
+	stateGraphCmd.Flags().StringVar(&stateGraphCorpus, "corpus", "", "Graph the transactions of this corpus")
+	stateGraphCmd.Flags().StringVar(&stateGraphDir, "dir", corpus.DefaultRoot, "Directory holding corpora")
+	stateGraphCmd.Flags().StringVar(&stateGraphFormat, "format", "text", "Output format: text, dot, mermaid or json")
+
+	supportPorcelain(stateGraphCmd)
+	rootCmd.AddCommand(stateGraphCmd)
+}
@@ -0,0 +1,29 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/analytics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteStateGraph(t *testing.T) {
+	var buf bytes.Buffer
+	writeStateGraph(&buf, &analytics.StateGraph{Nodes: make([]analytics.StateNode, 2)})
+	assert.Equal(t, "No shared ledger entries between 2 transaction(s)\n", buf.String())
+
+	buf.Reset()
+	writeStateGraph(&buf, &analytics.StateGraph{
+		Nodes:    make([]analytics.StateNode, 2),
+		Edges:    []analytics.StateEdge{{From: "aa", To: "bb", Kinds: []string{analytics.DepWriteWrite}, Keys: []string{"instance of CABC"}}},
+		Hotspots: []analytics.StateHotspot{{Key: "instance of CABC", Writers: 2}},
+	})
+	out := buf.String()
+	assert.Contains(t, out, "aa -> bb  write-write: instance of CABC")
+	assert.Contains(t, out, "Contended entries")
+	assert.Contains(t, out, "2 transaction(s), 1 dependencies")
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var telemetryEndpointFlag string
+
+const telemetryDisclosure = `When enabled, erst records one event per command with:
+  - the command name (for example "erst debug"), without arguments or flags
+  - how long it ran, in milliseconds
+  - success, or a coarse failure class such as "network" or "timeout"
+  - the erst version, operating system and CPU architecture
+  - the UTC date, without a time of day
+
+It never records transaction hashes, account or contract addresses, file
+paths, error messages or any identifier for you or your machine. Events are
+queued in the erst data directory and sent in batches. Set DO_NOT_TRACK=1 or
+ERST_TELEMETRY=off to disable reporting for a single environment.`
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Manage opt-in anonymous usage reporting",
+	Long: `Manage anonymous usage reporting, which helps maintainers see which commands
+are used and which fail. Reporting is off until you run 'erst telemetry on'.
+
+` + telemetryDisclosure + `
+
+Available subcommands:
+  on     - Start reporting usage
+  off    - Stop reporting and delete queued events
+  status - Show whether reporting is on and what is queued`,
+}
+
+var telemetryOnCmd = &cobra.Command{
+	Use:   "on",
+	Short: "Opt in to anonymous usage reporting",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		usage, err := telemetry.NewUsage()
+		if err != nil {
+			return err
+		}
+		if err := usage.SetEnabled(true, telemetryEndpointFlag); err != nil {
+			return err
+		}
+		fmt.Println("Usage reporting is on. Thank you!")
+		fmt.Println()
+		fmt.Println(telemetryDisclosure)
+		if usage.Endpoint() == "" {
+			fmt.Println("\nNo endpoint is configured in this build, so events stay in the local queue.")
+		}
+		return nil
+	},
+}
+
+var telemetryOffCmd = &cobra.Command{
+	Use:   "off",
+	Short: "Opt out of usage reporting and delete queued events",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		usage, err := telemetry.NewUsage()
+		if err != nil {
+			return err
+		}
+		if err := usage.SetEnabled(false, ""); err != nil {
+			return err
+		}
+		fmt.Println("Usage reporting is off. Queued events were deleted.")
+		return nil
+	},
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the usage reporting setting and queued events",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		usage, err := telemetry.NewUsage()
+		if err != nil {
+			return err
+		}
+		settings, err := usage.Settings()
+		if err != nil {
+			return err
+		}
+
+		state := "off (default)"
+		switch {
+		case usage.DisabledByEnv():
+			state = "off (DO_NOT_TRACK or ERST_TELEMETRY)"
+		case settings.Enabled:
+			state = "on"
+		case !settings.UpdatedAt.IsZero():
+			state = "off"
+		}
+		fmt.Printf("Usage reporting: %s\n", state)
+		if !settings.UpdatedAt.IsZero() {
+			fmt.Printf("Changed:         %s\n", settings.UpdatedAt.Local().Format("2006-01-02 15:04"))
+		}
+		endpoint := usage.Endpoint()
+		if endpoint == "" {
+			endpoint = "none configured"
+		}
+		fmt.Printf("Endpoint:        %s\n", endpoint)
+
+		events, err := usage.Queued()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Queued events:   %d\n", len(events))
+		if len(events) > 0 {
+			last := events[len(events)-1]
+			fmt.Printf("Latest:          %s, %dms, %s (%s %s/%s, %s)\n",
+				last.Command, last.DurationMs, last.Outcome, last.Version, last.OS, last.Arch, last.Day)
+		}
+		return nil
+	},
+}
+
+// recordUsage queues a usage event for the command that ran, when the user
+// opted in. Errors are only logged: reporting must never fail a command.
+func recordUsage(cmd *cobra.Command, started time.Time, runErr error) {
+	if cmd == nil || started.IsZero() || strings.HasPrefix(cmd.CommandPath(), telemetryCmd.CommandPath()) {
+		return
+	}
+	usage, err := telemetry.NewUsage()
+	if err != nil || !usage.Enabled() {
+		return
+	}
+	ev := usage.NewEvent(cmd.CommandPath(), Version, time.Since(started), runErr)
+	if err := usage.Record(context.Background(), ev); err != nil {
+		logger.Logger.Debug("Failed to record usage", "error", err)
+	}
+}
+
+func init() {
+	telemetryOnCmd.Flags().StringVar(&telemetryEndpointFlag, "endpoint", "", "Send events to this URL instead of the default endpoint")
+	telemetryCmd.AddCommand(telemetryOnCmd, telemetryOffCmd, telemetryStatusCmd)
+	rootCmd.AddCommand(telemetryCmd)
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/tokenflow"
+	"github.com/spf13/cobra"
+)
+
+var tokenflowSource analysisSource
+
+var tokenflowCmd = &cobra.Command{
+	Use:   "tokenflow [tx-hash | session-id]",
+	Short: "Show the token flows of a transaction, session or raw XDR",
+	Long: `Build the token flow report on its own, without a full debug run: every
+transfer, mint and burn, their approximate value, a Mermaid chart, and a check
+of the flows against the ledger balance changes. The input is one of:
+
+  - a transaction hash, fetched from --network
+  - a session ID, or --session, reusing the stored transaction and token
+    metadata without refetching
+  - --envelope with --result-meta, as base64 or @file
+
+Without any, the current session is used. --format selects text, json, csv
+(one row per movement) or mermaid (the chart only). Flows are valued in USD
+when --price-source, ERST_PRICE_SOURCE or price_source in the config names a
+price source.`,
+	Example: `  erst tokenflow 5c0a1b...e9 --network testnet
+  erst tokenflow abc123 --format csv > flows.csv
+  erst tokenflow --envelope @tx.xdr --result-meta @meta.xdr --format json
+  erst session resume abc123 && erst tokenflow --format mermaid`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := tokenflowSource.checkFormat(); err != nil {
+			return err
+		}
+		in, err := tokenflowSource.load(cmd, args)
+		if err != nil {
+			return err
+		}
+		if in.ResultMetaXdr == "" {
+			return fmt.Errorf("%s has no result meta; token flows cannot be built", in.Source)
+		}
+
+		report, err := tokenflow.BuildReport(in.EnvelopeXdr, in.ResultMetaXdr)
+		if err != nil {
+			return fmt.Errorf("failed to build token flow report: %w", err)
+		}
+		report.ApplyMetadata(tokenflow.NewMetadataResolver(nil, in.TokenMetadata))
+		if priceFn, err := newPriceFunc(cmd.Context()); err != nil {
+			logger.Logger.Warn("Price source unavailable, token flows will not be valued", "error", err)
+		} else if priceFn != nil {
+			report.ApplyPrices(priceFn)
+		}
+
+		switch tokenflowSource.format {
+		case "json":
+			return writeJSON(cmd, report.JSON())
+		case "csv":
+			return report.WriteCSV(os.Stdout)
+		case "mermaid":
+			fmt.Println(report.MermaidFlowchart())
+			return nil
+		}
+
+		fmt.Printf("Source: %s\n", in.Source)
+		if len(report.Agg) == 0 {
+			fmt.Println("No token transfers found.")
+			return nil
+		}
+		printTokenFlows(report)
+		if in.Passphrase != "" {
+			printBalanceVerification(report, in.ResultMetaXdr, in.Passphrase)
+		} else {
+			fmt.Printf("\nBalance verification skipped: network %q is not configured\n", in.Network)
+		}
+		return nil
+	},
+}
+
+func init() {
+	tokenflowSource.register(tokenflowCmd, "text", "json", "csv", "mermaid")
+	tokenflowCmd.Flags().StringVar(&priceSourceFlag, "price-source", "", "CSV file or HTTP endpoint with USD prices for valuing token flows")
+	rootCmd.AddCommand(tokenflowCmd)
+}
@@ -4,19 +4,65 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
+	"github.com/dotandev/hintents/internal/session"
 	"github.com/dotandev/hintents/internal/trace"
+	"github.com/dotandev/hintents/internal/visualizer"
 	"github.com/spf13/cobra"
 )
 
 var (
-	traceFile string
+	traceFile          string
+	traceStorageKey    string
+	traceStorageCtr    string
+	traceStorageOp     string
+	traceStorageAsJSON bool
+	traceSessionFlag   string
+	traceCheckpoint    string
 )
 
+// loadExecutionTrace reads the trace file of args, or builds the trace of a
+// stored session when --session is given or no file is named
+func loadExecutionTrace(cmd *cobra.Command, filename string) (*trace.ExecutionTrace, error) {
+	if filename == "" || traceSessionFlag != "" {
+		if filename != "" {
+			return nil, fmt.Errorf("give either a trace file or --session, not both")
+		}
+		a, err := loadSessionAnalysis(cmd.Context(), traceSessionFlag, traceCheckpoint)
+		if errors.Is(err, session.ErrNoActiveSession) {
+			return nil, fmt.Errorf("trace file required. Use: erst trace <file>, --file <file> or --session <id>")
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries, err := sessionLedgerEntries(a.Session, traceCheckpoint)
+		if err != nil {
+			logger.Logger.Warn("Storage accesses will lack prior values", "error", err)
+		}
+		return buildExecutionTrace(a.Session.TxHash, a.transaction(), a.Simulation, entries), nil
+	}
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("trace file not found: %s", filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trace file: %w", err)
+	}
+	executionTrace, err := trace.FromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse trace file: %w", err)
+	}
+	return executionTrace, nil
+}
+
 var traceCmd = &cobra.Command{
-	Use:   "trace <trace-file>",
+	Use:   "trace [trace-file]",
 	Short: "Interactive trace navigation and debugging",
 	Long: `Launch an interactive trace viewer for bi-directional navigation through execution traces.
 
@@ -26,43 +72,99 @@ The trace viewer allows you to:
 - Reconstruct state at any point
 - View memory and host state changes
 
+Without a file, the trace is rebuilt from a stored session: the one named by
+--session, else the current session. --checkpoint selects a run other than
+the latest.
+
 Example:
   erst trace execution.json
-  erst trace --file debug_trace.json`,
+  erst trace --file debug_trace.json
+  erst trace --session abc123 --checkpoint original`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		var filename string
+		filename := traceFile
 		if len(args) > 0 {
 			filename = args[0]
-		} else if traceFile != "" {
-			filename = traceFile
-		} else {
-			return fmt.Errorf("trace file required. Use: erst trace <file> or --file <file>")
+		}
+		executionTrace, err := loadExecutionTrace(cmd, filename)
+		if err != nil {
+			return err
 		}
 
-		// Check if file exists
-		if _, err := os.Stat(filename); os.IsNotExist(err) {
-			return fmt.Errorf("trace file not found: %s", filename)
+		// Start interactive viewer
+		if !platform.Interactive() {
+			return fmt.Errorf("the trace viewer needs an interactive terminal; use 'erst trace storage' for non-interactive output")
 		}
+		viewer := trace.NewInteractiveViewer(executionTrace)
+		return viewer.Start()
+	},
+}
 
-		// Load trace from file
-		data, err := os.ReadFile(filename)
+var traceStorageCmd = &cobra.Command{
+	Use:   "storage [trace-file]",
+	Short: "Show contract data reads and writes recorded in a trace",
+	Long: `List the contract data accesses recorded in an execution trace written by
+'erst debug --generate-trace', answering which contract's storage a key belongs
+to and what value the transaction saw. Values are shown as a SHA-256 hash of the
+value XDR; --json includes the value itself. Without a file, the accesses of a
+stored session are listed.
+
+Accesses are derived, not observed: reads are the footprint entries the host
+loaded for the transaction, and writes and deletes come from the result
+metadata. They are not attributed to execution steps.`,
+	Example: `  erst trace storage tx.trace.json
+  erst trace storage --session abc123 --contract CDLZ...
+  erst trace storage tx.trace.json --key AAAADwAAAAdCYWxhbmNl --op read
+  erst trace storage tx.trace.json --contract CDLZ... --json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var filename string
+		if len(args) > 0 {
+			filename = args[0]
+		}
+		executionTrace, err := loadExecutionTrace(cmd, filename)
 		if err != nil {
-			return fmt.Errorf("failed to read trace file: %w", err)
+			return err
 		}
 
-		executionTrace, err := trace.FromJSON(data)
-		if err != nil {
-			return fmt.Errorf("failed to parse trace file: %w", err)
+		accesses := executionTrace.FindStorage(trace.StorageQuery{
+			Key:        traceStorageKey,
+			ContractID: traceStorageCtr,
+			Op:         traceStorageOp,
+		})
+		if traceStorageAsJSON {
+			return writeJSON(cmd, accesses)
 		}
 
-		// Start interactive viewer
-		viewer := trace.NewInteractiveViewer(executionTrace)
-		return viewer.Start()
+		if len(accesses) == 0 {
+			fmt.Println("No matching storage accesses recorded.")
+			return nil
+		}
+		table := visualizer.NewTable("Op", "Source", "Contract", "Durability", "Value", "Key")
+		for _, a := range accesses {
+			value := "(none)"
+			if len(a.ValueHash) >= 16 {
+				value = a.ValueHash[:16]
+			}
+			table.AddRow(a.Op, a.Source, a.ContractID, a.Durability, value, a.Key)
+		}
+		table.Render(os.Stdout)
+		if !visualizer.Porcelain() {
+			fmt.Println("\nReads are footprint entries loaded for the transaction; writes and deletes come from the result metadata.")
+		}
+		return nil
 	},
 }
 
 func init() {
 	traceCmd.Flags().StringVarP(&traceFile, "file", "f", "", "Trace file to load")
+	traceCmd.PersistentFlags().StringVar(&traceSessionFlag, "session", "", "Rebuild the trace from this stored session (default: the current session)")
+	traceCmd.PersistentFlags().StringVar(&traceCheckpoint, "checkpoint", "", "Session checkpoint to trace instead of the latest run")
+	traceStorageCmd.Flags().StringVar(&traceStorageKey, "key", "", "Only accesses whose key (base64 XDR) contains this text")
+	traceStorageCmd.Flags().StringVar(&traceStorageCtr, "contract", "", "Only accesses to this contract's storage")
+	traceStorageCmd.Flags().StringVar(&traceStorageOp, "op", "", "Only this operation: read, write or delete")
+	traceStorageCmd.Flags().BoolVar(&traceStorageAsJSON, "json", false, "Output as JSON")
+	supportPorcelain(traceStorageCmd)
+	traceCmd.AddCommand(traceStorageCmd)
 	rootCmd.AddCommand(traceCmd)
 }
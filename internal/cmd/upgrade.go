@@ -11,6 +11,7 @@ import (
 
 	"github.com/dotandev/hintents/internal/rpc"
 	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
 	"github.com/spf13/cobra"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
@@ -40,7 +41,7 @@ Example:
 		if err != nil {
 			return fmt.Errorf("failed to read WASM file: %w", err)
 		}
-		fmt.Printf("Loaded new WASM code: %d bytes\n", len(newWasmBytes))
+		visualizer.Infof("Loaded new WASM code: %d bytes\n", len(newWasmBytes))
 
 		// 2. Setup Client
 		opts := []rpc.ClientOption{
@@ -56,7 +57,7 @@ Example:
 		}
 
 		// 3. Fetch Transaction
-		fmt.Printf("Fetching transaction: %s from %s\n", txHash, networkFlag)
+		visualizer.Infof("Fetching transaction: %s from %s\n", txHash, networkFlag)
 		resp, err := client.GetTransaction(cmd.Context(), txHash)
 		if err != nil {
 			return fmt.Errorf("failed to fetch transaction: %w", err)
@@ -98,7 +99,7 @@ Example:
 			LedgerEntries: entries,
 		}
 
-		fmt.Println("Running simulation with upgraded code...")
+		visualizer.Infof("Running simulation with upgraded code...\n")
 		result, err := runner.Run(simReq)
 		if err != nil {
 			return fmt.Errorf("simulation failed: %w", err)
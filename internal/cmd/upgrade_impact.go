@@ -0,0 +1,222 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/dotandev/hintents/internal/impact"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/spf13/cobra"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+var (
+	impactContract    string
+	impactWasm        string
+	impactLast        int
+	impactLookback    uint32
+	impactConcurrency int
+	impactThreshold   float64
+	impactJSON        bool
+	impactAllowErrors bool
+)
+
+var upgradeImpactCmd = &cobra.Command{
+	Use:   "upgrade-impact --contract <C...> --wasm <path>",
+	Short: "Replay a contract's recent transactions against new WASM before deploying",
+	Long: `Replay the most recent transactions of a contract twice: once with the
+deployed code and once with a candidate WASM. Then report how many would change
+status, emit different events, or use noticeably different resources.
+
+Run this as a pre-deploy safety check. The command exits with an error if any
+transaction would change status, or could not be replayed unless
+--allow-errors is given.
+
+Baseline replays with the deployed code are cached by their exact inputs, so
+rerunning after changing only the candidate WASM skips them. The cache keeps
+the most recently used 256 MB of results. Use --no-cache to replay
+everything.`,
+	Example: `  erst upgrade-impact --contract CDLZ... --wasm ./target/new.wasm --last 100 --network testnet
+  erst upgrade-impact --contract CDLZ... --wasm ./new.wasm --json > impact.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if impactContract == "" || impactWasm == "" {
+			return fmt.Errorf("flags --contract and --wasm are required")
+		}
+		raw, err := strkey.Decode(strkey.VersionByteContract, impactContract)
+		if err != nil {
+			return fmt.Errorf("invalid contract address %s: %w", impactContract, err)
+		}
+		var contractID xdr.Hash
+		copy(contractID[:], raw)
+
+		code, err := os.ReadFile(impactWasm)
+		if err != nil {
+			return fmt.Errorf("failed to read WASM file: %w", err)
+		}
+
+		opts := []rpc.ClientOption{rpc.WithNetwork(rpc.Network(networkFlag))}
+		if rpcURLFlag != "" {
+			opts = append(opts, rpc.WithHorizonURL(rpcURLFlag))
+		}
+		client, err := rpc.NewClient(opts...)
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+
+		runner, err := simulator.NewRunner("", false)
+		if err != nil {
+			return fmt.Errorf("failed to initialize simulator runner: %w", err)
+		}
+
+		ctx := cmd.Context()
+		fmt.Fprintf(os.Stderr, "Finding the last %d transactions of %s on %s...\n", impactLast, impactContract, networkFlag)
+		hashes, err := client.GetContractTransactions(ctx, impactContract, impactLast, impactLookback)
+		if err != nil {
+			return err
+		}
+		if len(hashes) == 0 {
+			return fmt.Errorf("no recent transactions found for %s", impactContract)
+		}
+		fmt.Fprintf(os.Stderr, "Replaying %d transactions against %s (%d bytes)...\n", len(hashes), impactWasm, len(code))
+
+		var baseline simulator.RunnerInterface = runner
+		var cached *simulator.CachingRunner
+		if !noCacheFlag {
+			if cached, err = simulator.NewCachingRunner(runner); err != nil {
+				return err
+			}
+			baseline = cached
+		}
+
+		replay := impactReplayer(client, baseline, runner, contractID, code)
+		report := impact.Run(ctx, impactContract, hashes, impactConcurrency, impactThreshold, replay)
+		if cached != nil {
+			if hits, _ := cached.Stats(); hits > 0 {
+				fmt.Fprintf(os.Stderr, "Reused %d cached baseline simulations\n", hits)
+			}
+		}
+
+		if impactJSON {
+			if err := writeJSON(cmd, report); err != nil {
+				return err
+			}
+		} else {
+			printImpactReport(report)
+		}
+
+		if report.StatusChanged > 0 {
+			return fmt.Errorf("%d of %d transactions would change status after the upgrade", report.StatusChanged, report.Replayed)
+		}
+		if report.ReplayErrors > 0 && !impactAllowErrors {
+			return fmt.Errorf("%d of %d transactions could not be replayed; pass --allow-errors to accept a partial check", report.ReplayErrors, report.Total)
+		}
+		return nil
+	},
+}
+
+// impactReplayer replays a transaction with deployed state on baseline and
+// again with the candidate code injected on runner
+func impactReplayer(client *rpc.Client, baseline, runner simulator.RunnerInterface, contractID xdr.Hash, code []byte) impact.ReplayFunc {
+	return func(ctx context.Context, hash string) (impact.Outcome, impact.Outcome, error) {
+		resp, err := client.GetTransaction(ctx, hash)
+		if err != nil {
+			return impact.Outcome{}, impact.Outcome{}, fmt.Errorf("failed to fetch transaction: %w", err)
+		}
+
+		entries, err := rpc.ExtractLedgerEntriesFromMeta(resp.ResultMetaXdr)
+		if err != nil {
+			logger.Logger.Warn("Failed to extract ledger entries from metadata, fetching from network", "hash", hash, "error", err)
+			keys, keyErr := extractLedgerKeys(resp.ResultMetaXdr)
+			if keyErr != nil {
+				return impact.Outcome{}, impact.Outcome{}, fmt.Errorf("failed to extract ledger keys: %w", keyErr)
+			}
+			if entries, err = client.GetLedgerEntries(ctx, keys); err != nil {
+				return impact.Outcome{}, impact.Outcome{}, fmt.Errorf("failed to fetch ledger entries: %w", err)
+			}
+		}
+
+		upgradedEntries := make(map[string]string, len(entries)+1)
+		for k, v := range entries {
+			upgradedEntries[k] = v
+		}
+		if err := injectNewCode(upgradedEntries, contractID, code); err != nil {
+			return impact.Outcome{}, impact.Outcome{}, fmt.Errorf("failed to inject new code: %w", err)
+		}
+
+		run := func(runner simulator.RunnerInterface, entries map[string]string) impact.Outcome {
+			res, err := runner.Run(&simulator.SimulationRequest{
+				EnvelopeXdr:   resp.EnvelopeXdr,
+				ResultMetaXdr: resp.ResultMetaXdr,
+				LedgerEntries: entries,
+			})
+			return impact.Outcome{Response: res, Err: err}
+		}
+
+		return run(baseline, entries), run(runner, upgradedEntries), nil
+	}
+}
+
+func printImpactReport(r *impact.Report) {
+	fmt.Printf("\n%s\n", visualizer.Heading(fmt.Sprintf("Upgrade Impact: %s", r.Contract)))
+	for _, line := range r.SummaryLines() {
+		fmt.Printf("  %s\n", line)
+	}
+
+	var changed []impact.Change
+	for _, c := range r.Changes {
+		if c.Changed() || c.ReplayError != "" {
+			changed = append(changed, c)
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	fmt.Printf("\nAffected transactions:\n")
+	for _, c := range changed {
+		switch {
+		case c.ReplayError != "":
+			fmt.Printf("  %s  replay failed: %s\n", c.Hash, c.ReplayError)
+		case c.StatusChanged:
+			fmt.Printf("  %s  status %s -> %s", c.Hash, c.BaselineStatus, c.UpgradedStatus)
+			if c.UpgradedError != "" {
+				fmt.Printf(" (%s)", c.UpgradedError)
+			}
+			fmt.Println()
+		default:
+			fmt.Printf("  %s", c.Hash)
+			if c.EventsChanged {
+				fmt.Printf("  events changed")
+			}
+			if c.ResourceChanged {
+				fmt.Printf("  cpu %+d (%+.1f%%)", c.CPUDelta, c.CPUChange*100)
+			}
+			fmt.Println()
+		}
+	}
+}
+
+func init() {
+	upgradeImpactCmd.Flags().StringVar(&impactContract, "contract", "", "Contract address (C...) to analyze")
+	upgradeImpactCmd.Flags().StringVar(&impactWasm, "wasm", "", "Path to the candidate WASM file")
+	upgradeImpactCmd.Flags().IntVar(&impactLast, "last", 100, "Number of recent transactions to replay")
+	upgradeImpactCmd.Flags().Uint32Var(&impactLookback, "lookback", rpc.DefaultHistoryLookback, "Number of ledgers to scan for recent transactions")
+	upgradeImpactCmd.Flags().IntVar(&impactConcurrency, "concurrency", 4, "Number of transactions to replay in parallel")
+	upgradeImpactCmd.Flags().Float64Var(&impactThreshold, "resource-threshold", impact.DefaultResourceThreshold, "Relative CPU/memory change counted as a resource change")
+	upgradeImpactCmd.Flags().BoolVar(&impactJSON, "json", false, "Output the report as JSON")
+	upgradeImpactCmd.Flags().BoolVar(&impactAllowErrors, "allow-errors", false, "Exit successfully when some transactions could not be replayed")
+	upgradeImpactCmd.Flags().BoolVar(&noCacheFlag, "no-cache", false, "Replay baselines even when a cached result exists")
+	upgradeImpactCmd.Flags().StringVarP(&networkFlag, "network", "n", string(rpc.Mainnet), "Stellar network to use")
+	upgradeImpactCmd.Flags().StringVar(&rpcURLFlag, "rpc-url", "", "Custom Horizon RPC URL")
+
+	rootCmd.AddCommand(upgradeImpactCmd)
+}
@@ -4,7 +4,6 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
 	"runtime/debug"
 	"time"
@@ -24,6 +23,10 @@ type VersionInfo struct {
 	CommitSHA string `json:"commit_sha"`
 	BuildDate string `json:"build_date"`
 	GoVersion string `json:"go_version"`
+	// APIVersion is the current version of the JSON output and APIVersions
+	// those --api-version accepts
+	APIVersion  int   `json:"api_version"`
+	APIVersions []int `json:"api_versions"`
 }
 
 // versionCmd represents the version command
@@ -32,21 +35,20 @@ var versionCmd = &cobra.Command{
 	Short: "Show version information",
 	Long:  "Display detailed build information including version, commit hash, and build date",
 	Args:  cobra.NoArgs,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		jsonOutput, _ := cmd.Flags().GetBool("json")
 
 		info := getVersionInfo()
 
 		if jsonOutput {
-			output, _ := json.MarshalIndent(info, "", "  ")
-			fmt.Println(string(output))
-		} else {
-			fmt.Printf("Erst Version: %s\n", info.Version)
-			fmt.Printf("Commit SHA:   %s\n", info.CommitSHA)
-			fmt.Printf("Build Date:   %s\n", info.BuildDate)
-			fmt.Printf("Go Version:   %s\n", info.GoVersion)
+			return writeJSON(cmd, info)
 		}
+		fmt.Printf("Erst Version: %s\n", info.Version)
+		fmt.Printf("Commit SHA:   %s\n", info.CommitSHA)
+		fmt.Printf("Build Date:   %s\n", info.BuildDate)
+		fmt.Printf("Go Version:   %s\n", info.GoVersion)
 		fmt.Printf("erst version %s\n", Version)
+		return nil
 	},
 }
 
@@ -56,6 +58,9 @@ func getVersionInfo() VersionInfo {
 		CommitSHA: CommitSHA,
 		BuildDate: BuildDate,
 		GoVersion: "unknown",
+
+		APIVersion:  currentAPIVersion,
+		APIVersions: supportedAPIVersions(),
 	}
 
 	// Use runtime/debug as fallback
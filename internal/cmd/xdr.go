@@ -55,6 +55,10 @@ func xdrExec(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("unsupported XDR type: %s (use: ledger-entry, diagnostic-event)", xdrType)
 	}
 
+	if decoder.FormatType(xdrFormat) == decoder.FormatJSON {
+		return writeJSON(cmd, output)
+	}
+
 	formatter := decoder.NewXDRFormatter(decoder.FormatType(xdrFormat))
 	result, err := formatter.Format(output)
 	if err != nil {
@@ -0,0 +1,191 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package compare aligns the results of two simulations and reports their
+// differences semantically rather than line by line.
+package compare
+
+import (
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// ChangeKind classifies one entry of an event diff
+type ChangeKind string
+
+const (
+	Unchanged ChangeKind = "unchanged"
+	Added     ChangeKind = "added"
+	Removed   ChangeKind = "removed"
+	Modified  ChangeKind = "modified"
+)
+
+// FieldChange is a single field that differs between two aligned events
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// EventDiff is one aligned pair of events. Left is nil for added events and
+// Right is nil for removed ones.
+type EventDiff struct {
+	Kind   ChangeKind       `json:"kind"`
+	Left   *simulator.Event `json:"left,omitempty"`
+	Right  *simulator.Event `json:"right,omitempty"`
+	Fields []FieldChange    `json:"fields,omitempty"`
+}
+
+// Summary counts the entries of a diff by kind
+type Summary struct {
+	Unchanged int `json:"unchanged"`
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Modified  int `json:"modified"`
+}
+
+// Changed reports whether any event differs
+func (s Summary) Changed() bool {
+	return s.Added+s.Removed+s.Modified > 0
+}
+
+// Events aligns two event streams with a longest common subsequence, so a
+// single inserted event does not mark every later event as changed. Within
+// each unaligned gap, events of the same shape (type, contract and first
+// topic) are paired as modified and reported field by field.
+func Events(a, b []simulator.Event) []EventDiff {
+	lcs := LCSTable(len(a), len(b), func(i, j int) bool { return a[i].Equal(b[j]) })
+
+	var out, removed, added []EventDiff
+	flush := func() {
+		out = append(out, pairGap(removed, added)...)
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i].Equal(b[j]):
+			flush()
+			out = append(out, EventDiff{Kind: Unchanged, Left: &a[i], Right: &b[j]})
+			i++
+			j++
+		case j >= len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, EventDiff{Kind: Removed, Left: &a[i]})
+			i++
+		default:
+			added = append(added, EventDiff{Kind: Added, Right: &b[j]})
+			j++
+		}
+	}
+	flush()
+	return out
+}
+
+// pairGap matches removed and added events of the same shape, in order,
+// and returns the gap in a stable order
+func pairGap(removed, added []EventDiff) []EventDiff {
+	used := make([]bool, len(added))
+	var out []EventDiff
+	for _, r := range removed {
+		match := -1
+		for k, a := range added {
+			if !used[k] && sameShape(*r.Left, *a.Right) {
+				match = k
+				break
+			}
+		}
+		if match < 0 {
+			out = append(out, r)
+			continue
+		}
+		used[match] = true
+		right := added[match].Right
+		out = append(out, EventDiff{Kind: Modified, Left: r.Left, Right: right, Fields: fieldChanges(*r.Left, *right)})
+	}
+	for k, a := range added {
+		if !used[k] {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+func sameShape(a, b simulator.Event) bool {
+	if !a.Typed() || !b.Typed() {
+		return false
+	}
+	if a.Type != b.Type || a.ContractID != b.ContractID {
+		return false
+	}
+	if len(a.Topics) == 0 || len(b.Topics) == 0 {
+		return len(a.Topics) == len(b.Topics)
+	}
+	return a.Topics[0] == b.Topics[0]
+}
+
+func fieldChanges(a, b simulator.Event) []FieldChange {
+	var fields []FieldChange
+	add := func(field, old, new string) {
+		if old != new {
+			fields = append(fields, FieldChange{Field: field, Old: old, New: new})
+		}
+	}
+
+	n := len(a.Topics)
+	if len(b.Topics) > n {
+		n = len(b.Topics)
+	}
+	for i := 0; i < n; i++ {
+		add(fmt.Sprintf("topic[%d]", i), at(a.Topics, i), at(b.Topics, i))
+	}
+	add("data", a.Data, b.Data)
+	return fields
+}
+
+func at(s []string, i int) string {
+	if i < len(s) {
+		return s[i]
+	}
+	return "<missing>"
+}
+
+// Summarize counts the entries of a diff by kind
+func Summarize(diffs []EventDiff) Summary {
+	var s Summary
+	for _, d := range diffs {
+		switch d.Kind {
+		case Unchanged:
+			s.Unchanged++
+		case Added:
+			s.Added++
+		case Removed:
+			s.Removed++
+		case Modified:
+			s.Modified++
+		}
+	}
+	return s
+}
+
+// LCSTable returns the longest common subsequence lengths of two sequences,
+// where cell [i][j] covers the suffixes starting at i and j
+func LCSTable(n, m int, equal func(i, j int) bool) [][]int {
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if equal(i, j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	return lcs
+}
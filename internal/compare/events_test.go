@@ -0,0 +1,50 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ev(i int, name, data string) simulator.Event {
+	return simulator.Event{Index: i, Type: "contract", ContractID: "CABC", Topics: []string{name, "GFROM"}, Data: data}
+}
+
+func TestEvents_InsertionDoesNotShiftEverything(t *testing.T) {
+	a := []simulator.Event{ev(0, "approve", "1"), ev(1, "transfer", "100"), ev(2, "burn", "5")}
+	b := []simulator.Event{ev(0, "approve", "1"), ev(1, "mint", "7"), ev(2, "transfer", "100"), ev(3, "burn", "5")}
+
+	diffs := Events(a, b)
+	sum := Summarize(diffs)
+	assert.Equal(t, Summary{Unchanged: 3, Added: 1}, sum)
+	require.Len(t, diffs, 4)
+	assert.Equal(t, Added, diffs[1].Kind)
+	assert.Equal(t, "mint", diffs[1].Right.Topics[0])
+}
+
+func TestEvents_FieldLevelChanges(t *testing.T) {
+	a := []simulator.Event{ev(0, "transfer", "100"), ev(1, "burn", "5")}
+	changed := ev(0, "transfer", "250")
+	changed.Topics[1] = "GOTHER"
+	b := []simulator.Event{changed, ev(1, "burn", "5")}
+
+	diffs := Events(a, b)
+	require.Len(t, diffs, 2)
+	assert.Equal(t, Modified, diffs[0].Kind)
+	assert.Equal(t, []FieldChange{
+		{Field: "topic[1]", Old: "GFROM", New: "GOTHER"},
+		{Field: "data", Old: "100", New: "250"},
+	}, diffs[0].Fields)
+	assert.Equal(t, Unchanged, diffs[1].Kind)
+}
+
+func TestEvents_UntypedFallsBackToText(t *testing.T) {
+	diffs := Events(simulator.NewEvents("a", "b"), simulator.NewEvents("a", "c"))
+	assert.Equal(t, Summary{Unchanged: 1, Added: 1, Removed: 1}, Summarize(diffs))
+	assert.False(t, Summarize(Events(nil, nil)).Changed())
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultLogIgnorePatterns drop host diagnostic lines that differ between
+// runs of the same transaction without indicating a behavior change.
+// Resource usage is compared separately from the budget report.
+var DefaultLogIgnorePatterns = []string{
+	`^Host Initialized with Budget:`,
+	`^CPU Instructions Used: \d+$`,
+	`^Memory Bytes Used: \d+$`,
+	`^Loaded \d+ Ledger Entries$`,
+}
+
+var (
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	addressPattern   = regexp.MustCompile(`\b(M[A-Z2-7]{68}|[GC][A-Z2-7]{55}|[0-9a-fA-F]{64})\b`)
+)
+
+// LogFilter removes known-noisy lines from logs before they are diffed
+type LogFilter struct {
+	ignore          []*regexp.Regexp
+	StripTimestamps bool
+	StripAddresses  bool
+}
+
+// NewLogFilter compiles the ignore patterns into a filter
+func NewLogFilter(patterns []string, stripTimestamps, stripAddresses bool) (*LogFilter, error) {
+	f := &LogFilter{StripTimestamps: stripTimestamps, StripAddresses: stripAddresses}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log ignore pattern %q: %w", p, err)
+		}
+		f.ignore = append(f.ignore, re)
+	}
+	return f, nil
+}
+
+// DefaultLogFilter ignores the default patterns and strips timestamps, but
+// keeps addresses, which usually matter when comparing networks
+func DefaultLogFilter() *LogFilter {
+	f, _ := NewLogFilter(DefaultLogIgnorePatterns, true, false)
+	return f
+}
+
+// Apply drops ignored lines and normalizes the rest. A nil filter returns
+// the lines unchanged.
+func (f *LogFilter) Apply(lines []string) []string {
+	if f == nil {
+		return lines
+	}
+	out := make([]string, 0, len(lines))
+next:
+	for _, line := range lines {
+		for _, re := range f.ignore {
+			if re.MatchString(line) {
+				continue next
+			}
+		}
+		if f.StripTimestamps {
+			line = timestampPattern.ReplaceAllString(line, "<timestamp>")
+		}
+		if f.StripAddresses {
+			line = addressPattern.ReplaceAllString(line, "<address>")
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// LogDiff is a log line present on only one side
+type LogDiff struct {
+	Kind ChangeKind `json:"kind"`
+	Line string     `json:"line"`
+}
+
+// Logs filters both logs and returns the lines that differ, aligned with a
+// longest common subsequence
+func Logs(a, b []string, f *LogFilter) []LogDiff {
+	x, y := f.Apply(a), f.Apply(b)
+	lcs := LCSTable(len(x), len(y), func(i, j int) bool { return x[i] == y[j] })
+
+	var out []LogDiff
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			i++
+			j++
+		case j >= len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			out = append(out, LogDiff{Kind: Removed, Line: x[i]})
+			i++
+		default:
+			out = append(out, LogDiff{Kind: Added, Line: y[j]})
+			j++
+		}
+	}
+	return out
+}
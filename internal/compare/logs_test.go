@@ -0,0 +1,42 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package compare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogs_DefaultFilterDropsNoise(t *testing.T) {
+	a := []string{
+		"Host Initialized with Budget: Budget { cpu: 100 }",
+		"Executing InvokeHostFunction...",
+		"CPU Instructions Used: 1200",
+		"checked at 2025-01-02T03:04:05Z",
+	}
+	b := []string{
+		"Host Initialized with Budget: Budget { cpu: 900 }",
+		"Executing InvokeHostFunction...",
+		"CPU Instructions Used: 1800",
+		"checked at 2025-02-03T00:00:00.123Z",
+		"Result: Err(Contract(3))",
+	}
+
+	assert.Len(t, Logs(a, b, nil), 7, "an unfiltered diff reports every noisy line")
+	assert.Equal(t, []LogDiff{{Kind: Added, Line: "Result: Err(Contract(3))"}}, Logs(a, b, DefaultLogFilter()))
+}
+
+func TestLogFilter_CustomPatternsAndAddresses(t *testing.T) {
+	f, err := NewLogFilter([]string{`^nonce=`}, false, true)
+	require.NoError(t, err)
+
+	acct := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	out := f.Apply([]string{"nonce=42", "transfer from " + acct})
+	assert.Equal(t, []string{"transfer from <address>"}, out)
+
+	_, err = NewLogFilter([]string{"("}, false, false)
+	assert.Error(t, err)
+}
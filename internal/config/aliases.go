@@ -0,0 +1,109 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+var aliasNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// AliasConfig holds the command aliases saved with 'erst alias set'
+type AliasConfig struct {
+	Aliases map[string]string `json:"aliases"`
+}
+
+// ValidateAliasName checks that name can be used as an alias
+func ValidateAliasName(name string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return fmt.Errorf("alias name %q must be lowercase letters, digits, '-' or '_'", name)
+	}
+	return nil
+}
+
+// GetAliasConfigPath returns the path to the alias configuration file
+func GetAliasConfigPath() (string, error) {
+	configDir, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "aliases.json"), nil
+}
+
+// LoadAliases loads the saved aliases from disk
+func LoadAliases() (*AliasConfig, error) {
+	configPath, err := GetAliasConfigPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return &AliasConfig{Aliases: make(map[string]string)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alias file: %w", err)
+	}
+
+	var config AliasConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse alias file: %w", err)
+	}
+	if config.Aliases == nil {
+		config.Aliases = make(map[string]string)
+	}
+	return &config, nil
+}
+
+// SaveAliases saves aliases to disk
+func SaveAliases(config *AliasConfig) error {
+	configPath, err := GetAliasConfigPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal aliases: %w", err)
+	}
+
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write alias file: %w", err)
+	}
+	return nil
+}
+
+// SetAlias adds or replaces an alias
+func SetAlias(name, expansion string) error {
+	if err := ValidateAliasName(name); err != nil {
+		return err
+	}
+	aliases, err := LoadAliases()
+	if err != nil {
+		return err
+	}
+	aliases.Aliases[name] = expansion
+	return SaveAliases(aliases)
+}
+
+// RemoveAlias removes a saved alias
+func RemoveAlias(name string) error {
+	aliases, err := LoadAliases()
+	if err != nil {
+		return err
+	}
+	if _, exists := aliases.Aliases[name]; !exists {
+		return fmt.Errorf("alias '%s' not found", name)
+	}
+	delete(aliases.Aliases, name)
+	return SaveAliases(aliases)
+}
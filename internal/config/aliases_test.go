@@ -0,0 +1,32 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import "testing"
+
+func TestSetAndRemoveAlias(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+
+	if err := SetAlias("dbg-t", "debug --network testnet"); err != nil {
+		t.Fatalf("Failed to set alias: %v", err)
+	}
+	aliases, err := LoadAliases()
+	if err != nil {
+		t.Fatalf("Failed to load aliases: %v", err)
+	}
+	if got := aliases.Aliases["dbg-t"]; got != "debug --network testnet" {
+		t.Errorf("unexpected alias %q", got)
+	}
+
+	if err := SetAlias("Dbg", "debug"); err == nil {
+		t.Error("expected an error for an invalid alias name")
+	}
+
+	if err := RemoveAlias("dbg-t"); err != nil {
+		t.Fatalf("Failed to remove alias: %v", err)
+	}
+	if err := RemoveAlias("dbg-t"); err == nil {
+		t.Error("expected an error when removing a missing alias")
+	}
+}
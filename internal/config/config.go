@@ -8,7 +8,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+
+	"github.com/dotandev/hintents/internal/platform"
 )
 
 type Network string
@@ -35,6 +38,139 @@ type Config struct {
 	LogLevel      string  `json:"log_level,omitempty"`
 	CachePath     string  `json:"cache_path,omitempty"`
 	RPCToken      string  `json:"rpc_token,omitempty"`
+	// LogDiffIgnore holds extra regex patterns for lines dropped from log diffs
+	LogDiffIgnore []string `json:"log_diff_ignore,omitempty"`
+	// PriceSource is a CSV file or HTTP endpoint used to value token flows
+	PriceSource string `json:"price_source,omitempty"`
+	// SimulatorManifest is a JSON file pinning erst-sim checksums per platform
+	SimulatorManifest string `json:"simulator_manifest,omitempty"`
+	// SimulatorMaxMemoryMB, SimulatorMaxCPUSeconds and SimulatorMaxOutputMB
+	// cap each simulator run (0 = unlimited)
+	SimulatorMaxMemoryMB   int `json:"simulator_max_memory_mb,omitempty"`
+	SimulatorMaxCPUSeconds int `json:"simulator_max_cpu_seconds,omitempty"`
+	SimulatorMaxOutputMB   int `json:"simulator_max_output_mb,omitempty"`
+	// SecurityWeights weights the factors of security finding scores, as
+	// impact=2,likelihood=1,asset=1
+	SecurityWeights string `json:"security_weights,omitempty"`
+	// Scanners are external analyzers run by erst security
+	Scanners []ScannerConfig `json:"scanners,omitempty"`
+	// Hooks are scripts run before and after commands
+	Hooks []HookConfig `json:"hooks,omitempty"`
+	// Jira and Linear are the trackers erst report ticket files issues in
+	Jira   *JiraConfig   `json:"jira,omitempty"`
+	Linear *LinearConfig `json:"linear,omitempty"`
+	// Monitor declares the canaries erst monitor re-simulates
+	Monitor *MonitorConfig `json:"monitor,omitempty"`
+	// Archive uploads the artifacts of every debug run to object storage
+	Archive *ArchiveConfig `json:"archive,omitempty"`
+	// Storage is the shared database erst serve persists to
+	Storage *StorageConfig `json:"storage,omitempty"`
+	// Aliases are the alias.<name> entries of the TOML file; aliases saved
+	// with 'erst alias set' live in aliases.json
+	Aliases map[string]string `json:"-"`
+}
+
+// ScannerConfig declares an external security scanner: a command that reads
+// session JSON on stdin and prints findings JSON on stdout
+type ScannerConfig struct {
+	Name           string   `json:"name"`
+	Command        string   `json:"command"`
+	Args           []string `json:"args,omitempty"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+}
+
+// HookConfig declares a script run at a hook event such as pre-debug,
+// post-debug or post-compare
+type HookConfig struct {
+	Event          string   `json:"event"`
+	Command        string   `json:"command"`
+	Args           []string `json:"args,omitempty"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+}
+
+// JiraConfig holds the Jira site and credentials. ERST_JIRA_API_TOKEN
+// overrides the token.
+type JiraConfig struct {
+	URL       string   `json:"url"`
+	Email     string   `json:"email"`
+	APIToken  string   `json:"api_token,omitempty"`
+	Project   string   `json:"project"`
+	IssueType string   `json:"issue_type,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+}
+
+// LinearConfig holds the Linear team and credentials. ERST_LINEAR_API_KEY
+// overrides the key.
+type LinearConfig struct {
+	APIKey   string   `json:"api_key,omitempty"`
+	TeamID   string   `json:"team_id"`
+	LabelIDs []string `json:"label_ids,omitempty"`
+}
+
+// MonitorConfig declares the watches of erst monitor and the alert
+// channels they page
+type MonitorConfig struct {
+	Watches []WatchConfig `json:"watches"`
+	Alerts  []AlertConfig `json:"alerts,omitempty"`
+	// Digest emails a summary of the checks every day or week
+	Digest *DigestConfig `json:"digest,omitempty"`
+}
+
+// WatchConfig is a canary: a saved session re-simulated every
+// IntervalSeconds. Alerts names the alert channels it pages.
+type WatchConfig struct {
+	Name            string   `json:"name"`
+	Session         string   `json:"session"`
+	Network         string   `json:"network,omitempty"`
+	IntervalSeconds int      `json:"interval_seconds,omitempty"`
+	RefreshState    bool     `json:"refresh_state,omitempty"`
+	Alerts          []string `json:"alerts,omitempty"`
+}
+
+// AlertConfig is a PagerDuty or Opsgenie alert channel
+type AlertConfig struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	// RoutingKey is the PagerDuty integration key
+	RoutingKey string `json:"routing_key,omitempty"`
+	// APIKey is the Opsgenie integration key
+	APIKey   string `json:"api_key,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// ArchiveConfig is where debug artifacts are archived: s3://bucket/prefix,
+// gs://bucket/prefix or a directory
+type ArchiveConfig struct {
+	Location string `json:"location"`
+}
+
+// StorageConfig is the database erst serve keeps sessions, corpus
+// definitions, monitor watches and audit entries in. DSN is a
+// postgres:// URL or a SQLite file path; the pool settings apply to
+// Postgres.
+type StorageConfig struct {
+	DSN                    string `json:"dsn"`
+	MaxOpenConns           int    `json:"max_open_conns,omitempty"`
+	MaxIdleConns           int    `json:"max_idle_conns,omitempty"`
+	ConnMaxLifetimeSeconds int    `json:"conn_max_lifetime_seconds,omitempty"`
+}
+
+// DigestConfig sends the monitor digest to To through an SMTP server.
+// Period is "daily" (the default) or "weekly".
+type DigestConfig struct {
+	Period string     `json:"period,omitempty"`
+	To     []string   `json:"to"`
+	SMTP   SMTPConfig `json:"smtp"`
+}
+
+// SMTPConfig is an outgoing mail server. Port defaults to 587 with
+// STARTTLS; port 465 uses TLS from the start.
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	From     string `json:"from"`
 }
 
 var defaultConfig = &Config{
@@ -42,7 +178,17 @@ var defaultConfig = &Config{
 	Network:       NetworkTestnet,
 	SimulatorPath: "",
 	LogLevel:      "info",
-	CachePath:     filepath.Join(os.ExpandEnv("$HOME"), ".erst", "cache"),
+	CachePath:     defaultCachePath(),
+}
+
+// defaultCachePath uses the per-platform data directory; $HOME is not set
+// on Windows
+func defaultCachePath() string {
+	dir, err := platform.DataDir()
+	if err != nil {
+		dir = ".erst"
+	}
+	return filepath.Join(dir, "cache")
 }
 
 // GetGeneralConfigPath returns the path to the general configuration file
@@ -88,6 +234,10 @@ func Load() (*Config, error) {
 		LogLevel:      getEnv("ERST_LOG_LEVEL", defaultConfig.LogLevel),
 		CachePath:     getEnv("ERST_CACHE_PATH", defaultConfig.CachePath),
 		RPCToken:      getEnv("ERST_RPC_TOKEN", ""),
+		PriceSource:   getEnv("ERST_PRICE_SOURCE", ""),
+
+		SimulatorManifest: getEnv("ERST_SIM_MANIFEST", ""),
+		SecurityWeights:   getEnv("ERST_SECURITY_WEIGHTS", ""),
 	}
 
 	if err := cfg.loadFromFile(); err != nil {
@@ -104,7 +254,7 @@ func Load() (*Config, error) {
 func (c *Config) loadFromFile() error {
 	paths := []string{
 		".erst.toml",
-		filepath.Join(os.ExpandEnv("$HOME"), ".erst.toml"),
+		filepath.Join(homeDir(), ".erst.toml"),
 		"/etc/erst/config.toml",
 	}
 
@@ -160,12 +310,48 @@ func (c *Config) parseTOML(content string) error {
 			c.CachePath = value
 		case "rpc_token":
 			c.RPCToken = value
+		case "price_source":
+			c.PriceSource = value
+		case "simulator_manifest":
+			c.SimulatorManifest = value
+		case "simulator_max_memory_mb":
+			if n, err := strconv.Atoi(value); err == nil {
+				c.SimulatorMaxMemoryMB = n
+			}
+		case "simulator_max_cpu_seconds":
+			if n, err := strconv.Atoi(value); err == nil {
+				c.SimulatorMaxCPUSeconds = n
+			}
+		case "simulator_max_output_mb":
+			if n, err := strconv.Atoi(value); err == nil {
+				c.SimulatorMaxOutputMB = n
+			}
+		case "security_weights":
+			c.SecurityWeights = value
+		case "log_diff_ignore":
+			c.LogDiffIgnore = append(c.LogDiffIgnore, value)
+		default:
+			if name, ok := strings.CutPrefix(key, "alias."); ok {
+				if c.Aliases == nil {
+					c.Aliases = make(map[string]string)
+				}
+				c.Aliases[name] = unquote(strings.TrimSpace(parts[1]))
+			}
 		}
 	}
 
 	return nil
 }
 
+// unquote removes one pair of matching quotes around s, leaving quotes
+// inside it alone
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
 // SaveConfig saves the configuration to disk (JSON format)
 func SaveConfig(config *Config) error {
 	configPath, err := GetGeneralConfigPath()
@@ -226,6 +412,11 @@ func (c *Config) String() string {
 	)
 }
 
+func homeDir() string {
+	home, _ := os.UserHomeDir()
+	return home
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
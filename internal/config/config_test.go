@@ -208,6 +208,53 @@ cache_path = "/custom/cache"`,
 	}
 }
 
+func TestParseTOML_LogDiffIgnore(t *testing.T) {
+	cfg := &Config{}
+	content := `log_diff_ignore = "^fee charged"
+log_diff_ignore = 'nonce=\d+'`
+	if err := cfg.parseTOML(content); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.LogDiffIgnore) != 2 || cfg.LogDiffIgnore[0] != "^fee charged" || cfg.LogDiffIgnore[1] != `nonce=\d+` {
+		t.Errorf("unexpected LogDiffIgnore: %v", cfg.LogDiffIgnore)
+	}
+}
+
+func TestParseTOML_PriceSource(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parseTOML(`price_source = "https://prices.example.com/{asset}"`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.PriceSource != "https://prices.example.com/{asset}" {
+		t.Errorf("unexpected PriceSource: %q", cfg.PriceSource)
+	}
+}
+
+func TestParseTOML_SecurityWeights(t *testing.T) {
+	cfg := &Config{}
+	if err := cfg.parseTOML(`security_weights = "impact=2,likelihood=1,asset=1"`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SecurityWeights != "impact=2,likelihood=1,asset=1" {
+		t.Errorf("unexpected SecurityWeights: %q", cfg.SecurityWeights)
+	}
+}
+
+func TestParseTOML_Aliases(t *testing.T) {
+	cfg := &Config{}
+	err := cfg.parseTOML(`alias.dbg-t = "debug --network testnet --note 'a b'"
+alias.sec = 'security --session $1'`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := cfg.Aliases["dbg-t"]; got != "debug --network testnet --note 'a b'" {
+		t.Errorf("unexpected dbg-t alias: %q", got)
+	}
+	if got := cfg.Aliases["sec"]; got != "security --session $1" {
+		t.Errorf("unexpected sec alias: %q", got)
+	}
+}
+
 func TestLoadFromEnvironment(t *testing.T) {
 	// Save original env vars
 	origRpc := os.Getenv("ERST_RPC_URL")
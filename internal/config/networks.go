@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/dotandev/hintents/internal/platform"
 	"github.com/dotandev/hintents/internal/rpc"
 )
 
@@ -19,11 +20,7 @@ type CustomNetworkConfig struct {
 
 // GetConfigPath returns the path to the erst configuration directory
 func GetConfigPath() (string, error) {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return "", fmt.Errorf("failed to get home directory: %w", err)
-	}
-	return filepath.Join(home, ".erst"), nil
+	return platform.DataDir()
 }
 
 // GetNetworkConfigPath returns the path to the network configuration file
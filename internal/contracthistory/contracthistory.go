@@ -0,0 +1,169 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package contracthistory lists the recent direct invocations of a contract
+// with their outcome and resources, as an overview before debugging one of
+// them.
+package contracthistory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/contractspec"
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Invocation statuses
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// Invocation is one call of the contract by an InvokeHostFunction
+// operation
+type Invocation struct {
+	Contract  string    `json:"contract"`
+	TxHash    string    `json:"tx_hash"`
+	Ledger    uint32    `json:"ledger"`
+	CreatedAt time.Time `json:"created_at"`
+	Status    string    `json:"status"`
+	// Result is the result code of a failed transaction or operation
+	Result   string `json:"result,omitempty"`
+	Caller   string `json:"caller"`
+	Function string `json:"function"`
+	// Call is the function with its arguments, named after the
+	// contract's spec when it is known
+	Call string `json:"call"`
+	// Instructions, ReadBytes and WriteBytes are the transaction's declared
+	// Soroban resources
+	Instructions uint32 `json:"instructions"`
+	ReadBytes    uint32 `json:"read_bytes"`
+	WriteBytes   uint32 `json:"write_bytes"`
+	FeeCharged   int64  `json:"fee_charged"`
+}
+
+// Report is the recent invocations of a contract, newest first
+type Report struct {
+	Contract string `json:"contract"`
+	// FromLedger and ToLedger are the range of ledgers that was scanned
+	FromLedger  uint32       `json:"from_ledger"`
+	ToLedger    uint32       `json:"to_ledger"`
+	Invocations []Invocation `json:"invocations"`
+}
+
+// Failed returns how many of the invocations failed
+func (r *Report) Failed() int {
+	n := 0
+	for _, inv := range r.Invocations {
+		if inv.Status == StatusFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// FromTransaction returns the invocations of contractID in a transaction,
+// in operation order, or of any contract when contractID is empty. spec may
+// be nil.
+func FromTransaction(contractID string, tx rpc.LedgerTransaction, spec *contractspec.Spec) ([]Invocation, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(tx.EnvelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope of %s: %w", tx.Hash, err)
+	}
+
+	var invocations []Invocation
+	for i, op := range env.Operations() {
+		args, contract, ok := invokedContract(op, contractID)
+		if !ok {
+			continue
+		}
+		caller := env.SourceAccount().ToAccountId()
+		if op.SourceAccount != nil {
+			caller = op.SourceAccount.ToAccountId()
+		}
+		inv := Invocation{
+			Contract:  contract,
+			TxHash:    tx.Hash,
+			Ledger:    tx.Ledger,
+			CreatedAt: time.Unix(tx.CreatedAt, 0).UTC(),
+			Status:    StatusSuccess,
+			Caller:    caller.Address(),
+			Function:  string(args.FunctionName),
+			Call:      spec.FormatCall(string(args.FunctionName), args.Args),
+		}
+		if tx.Status != "SUCCESS" {
+			inv.Status = StatusFailed
+			inv.Result = failureCode(tx.ResultXdr, i)
+		}
+		invocations = append(invocations, inv)
+	}
+	if len(invocations) == 0 {
+		return nil, nil
+	}
+
+	var resources xdr.SorobanResources
+	if data := txmeta.SorobanData(env); data != nil {
+		resources = data.Resources
+	}
+	var result xdr.TransactionResult
+	feeCharged := int64(0)
+	if xdr.SafeUnmarshalBase64(tx.ResultXdr, &result) == nil {
+		feeCharged = int64(result.FeeCharged)
+	}
+	for i := range invocations {
+		invocations[i].Instructions = uint32(resources.Instructions)
+		invocations[i].ReadBytes = uint32(resources.DiskReadBytes)
+		invocations[i].WriteBytes = uint32(resources.WriteBytes)
+		invocations[i].FeeCharged = feeCharged
+	}
+	return invocations, nil
+}
+
+// invokedContract returns the arguments and contract of op when it calls
+// contractID directly, or any contract when contractID is empty
+func invokedContract(op xdr.Operation, contractID string) (xdr.InvokeContractArgs, string, bool) {
+	invoke, ok := op.Body.GetInvokeHostFunctionOp()
+	if !ok {
+		return xdr.InvokeContractArgs{}, "", false
+	}
+	args, ok := invoke.HostFunction.GetInvokeContract()
+	if !ok {
+		return xdr.InvokeContractArgs{}, "", false
+	}
+	addr, err := args.ContractAddress.String()
+	if err != nil || (contractID != "" && addr != contractID) {
+		return xdr.InvokeContractArgs{}, "", false
+	}
+	return args, addr, true
+}
+
+// failureCode names why a transaction failed: the result of operation
+// opIndex when an operation failed, else the transaction result
+func failureCode(resultXdr string, opIndex int) string {
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXdr, &result); err != nil {
+		return ""
+	}
+	inner := result.Result
+	if pair, ok := result.Result.GetInnerResultPair(); ok {
+		inner = xdr.TransactionResultResult{Code: pair.Result.Result.Code, Results: pair.Result.Result.Results}
+	}
+	if inner.Code != xdr.TransactionResultCodeTxFailed {
+		return decoder.DecodeTransactionResultCode(inner.Code).Code
+	}
+	results, _ := inner.GetResults()
+	if opIndex >= len(results) {
+		return decoder.DecodeTransactionResultCode(inner.Code).Code
+	}
+	if tr, ok := results[opIndex].GetTr(); ok {
+		if r, ok := tr.GetInvokeHostFunctionResult(); ok {
+			return decoder.SnakeCase(strings.TrimPrefix(r.Code.String(), "InvokeHostFunctionResultCode"))
+		}
+	}
+	return decoder.DecodeOperationResultCode(results[opIndex].Code).Code
+}
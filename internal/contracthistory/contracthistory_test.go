@@ -0,0 +1,98 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contracthistory
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	caller     = keypair.MustRandom().Address()
+	contractID = xdr.ContractId{1, 2, 3}
+	contract   = strkey.MustEncode(strkey.VersionByteContract, contractID[:])
+)
+
+func invokeEnvelope(t *testing.T, target xdr.ContractId, function string) string {
+	t.Helper()
+	amount := xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: new(xdr.Uint32)}
+	*amount.U32 = 7
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(caller),
+			Fee:           1000,
+			SeqNum:        1,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+					Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+					InvokeContract: &xdr.InvokeContractArgs{
+						ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &target},
+						FunctionName:    xdr.ScSymbol(function),
+						Args:            []xdr.ScVal{amount},
+					},
+				}},
+			}}},
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Instructions: 5000, DiskReadBytes: 200, WriteBytes: 100},
+			}},
+		}},
+	}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return b64
+}
+
+func trappedResult(t *testing.T) string {
+	t.Helper()
+	results := []xdr.OperationResult{{Code: xdr.OperationResultCodeOpInner, Tr: &xdr.OperationResultTr{
+		Type:                     xdr.OperationTypeInvokeHostFunction,
+		InvokeHostFunctionResult: &xdr.InvokeHostFunctionResult{Code: xdr.InvokeHostFunctionResultCodeInvokeHostFunctionTrapped},
+	}}}
+	b64, err := xdr.MarshalBase64(xdr.TransactionResult{FeeCharged: 321, Result: xdr.TransactionResultResult{
+		Code: xdr.TransactionResultCodeTxFailed, Results: &results,
+	}})
+	require.NoError(t, err)
+	return b64
+}
+
+func TestFromTransaction(t *testing.T) {
+	invs, err := FromTransaction(contract, rpc.LedgerTransaction{
+		Hash: "aa", Status: "FAILED", Ledger: 100, CreatedAt: 1700000000,
+		EnvelopeXdr: invokeEnvelope(t, contractID, "deposit"), ResultXdr: trappedResult(t),
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, invs, 1)
+	inv := invs[0]
+	assert.Equal(t, StatusFailed, inv.Status)
+	assert.Equal(t, "invoke_host_function_trapped", inv.Result)
+	assert.Equal(t, caller, inv.Caller)
+	assert.Equal(t, "deposit", inv.Function)
+	assert.Equal(t, "deposit(7)", inv.Call)
+	assert.Equal(t, uint32(5000), inv.Instructions)
+	assert.Equal(t, uint32(200), inv.ReadBytes)
+	assert.Equal(t, int64(321), inv.FeeCharged)
+	assert.Equal(t, int64(1700000000), inv.CreatedAt.Unix())
+
+	report := &Report{Invocations: invs}
+	assert.Equal(t, 1, report.Failed())
+}
+
+func TestFromTransactionOtherContract(t *testing.T) {
+	invs, err := FromTransaction(contract, rpc.LedgerTransaction{
+		Hash: "bb", Status: "SUCCESS", EnvelopeXdr: invokeEnvelope(t, xdr.ContractId{9}, "deposit"),
+	}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, invs)
+
+	_, err = FromTransaction(contract, rpc.LedgerTransaction{Hash: "cc", EnvelopeXdr: "not xdr"}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contracthistory
+
+import (
+	"sort"
+	"time"
+)
+
+// Bucket is the calls of a function in one time slice of a summary
+type Bucket struct {
+	Start  time.Time `json:"start"`
+	Calls  int       `json:"calls"`
+	Failed int       `json:"failed"`
+}
+
+// FailureRate is the fraction of calls in the bucket that failed
+func (b Bucket) FailureRate() float64 {
+	return rate(b.Failed, b.Calls)
+}
+
+// FunctionFailures is how often one entry point of a contract failed
+type FunctionFailures struct {
+	Contract    string  `json:"contract"`
+	Function    string  `json:"function"`
+	Calls       int     `json:"calls"`
+	Failed      int     `json:"failed"`
+	FailureRate float64 `json:"failure_rate"`
+	// Results counts the failures by result code
+	Results map[string]int `json:"results,omitempty"`
+	// Buckets split the calls over the summary's time range, oldest first
+	Buckets []Bucket `json:"buckets"`
+}
+
+// TopResult returns the most frequent failure result code, if any
+func (f FunctionFailures) TopResult() string {
+	top, count := "", 0
+	for code, n := range f.Results {
+		if n > count || (n == count && code < top) {
+			top, count = code, n
+		}
+	}
+	return top
+}
+
+// FailureSummary is the failure rate of each function invoked in a set of
+// invocations, most failing first
+type FailureSummary struct {
+	From          time.Time          `json:"from"`
+	To            time.Time          `json:"to"`
+	BucketSeconds int64              `json:"bucket_seconds"`
+	Invocations   int                `json:"invocations"`
+	Functions     []FunctionFailures `json:"functions"`
+}
+
+// SummarizeFailures groups invocations by contract and function, splitting
+// the calls of each into buckets equal slices of the time they span
+func SummarizeFailures(invs []Invocation, buckets int) *FailureSummary {
+	if buckets < 1 {
+		buckets = 1
+	}
+	s := &FailureSummary{Invocations: len(invs), Functions: []FunctionFailures{}}
+	for i, inv := range invs {
+		if i == 0 || inv.CreatedAt.Before(s.From) {
+			s.From = inv.CreatedAt
+		}
+		if i == 0 || inv.CreatedAt.After(s.To) {
+			s.To = inv.CreatedAt
+		}
+	}
+	span := s.To.Sub(s.From)
+	width := span / time.Duration(buckets)
+	if width <= 0 {
+		width = time.Second
+	}
+	s.BucketSeconds = int64((width + time.Second - 1) / time.Second)
+
+	type key struct{ contract, function string }
+	byFunction := map[key]*FunctionFailures{}
+	for _, inv := range invs {
+		k := key{inv.Contract, inv.Function}
+		f, ok := byFunction[k]
+		if !ok {
+			f = &FunctionFailures{Contract: inv.Contract, Function: inv.Function, Buckets: make([]Bucket, buckets)}
+			for i := range f.Buckets {
+				f.Buckets[i].Start = s.From.Add(time.Duration(i) * width)
+			}
+			byFunction[k] = f
+		}
+
+		b := buckets - 1
+		if span > 0 {
+			b = min(int(int64(inv.CreatedAt.Sub(s.From))*int64(buckets)/int64(span)), buckets-1)
+		}
+		f.Calls++
+		f.Buckets[b].Calls++
+		if inv.Status == StatusFailed {
+			f.Failed++
+			f.Buckets[b].Failed++
+			if inv.Result != "" {
+				if f.Results == nil {
+					f.Results = map[string]int{}
+				}
+				f.Results[inv.Result]++
+			}
+		}
+	}
+
+	for _, f := range byFunction {
+		f.FailureRate = rate(f.Failed, f.Calls)
+		s.Functions = append(s.Functions, *f)
+	}
+	sort.Slice(s.Functions, func(i, j int) bool {
+		a, b := s.Functions[i], s.Functions[j]
+		switch {
+		case a.FailureRate != b.FailureRate:
+			return a.FailureRate > b.FailureRate
+		case a.Failed != b.Failed:
+			return a.Failed > b.Failed
+		case a.Contract != b.Contract:
+			return a.Contract < b.Contract
+		}
+		return a.Function < b.Function
+	})
+	return s
+}
+
+func rate(failed, calls int) float64 {
+	if calls == 0 {
+		return 0
+	}
+	return float64(failed) / float64(calls)
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contracthistory
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func call(function, status, result string, at int64) Invocation {
+	return Invocation{Contract: contract, Function: function, Status: status, Result: result, CreatedAt: time.Unix(at, 0).UTC()}
+}
+
+func TestSummarizeFailures(t *testing.T) {
+	invs := []Invocation{
+		call("deposit", StatusSuccess, "", 0),
+		call("deposit", StatusFailed, "invoke_host_function_trapped", 30),
+		call("withdraw", StatusFailed, "invoke_host_function_trapped", 60),
+		call("withdraw", StatusFailed, "invoke_host_function_resource_limit_exceeded", 90),
+		call("withdraw", StatusFailed, "invoke_host_function_trapped", 120),
+		call("withdraw", StatusSuccess, "", 120),
+		call("balance", StatusSuccess, "", 100),
+	}
+	s := SummarizeFailures(invs, 4)
+	assert.Equal(t, 7, s.Invocations)
+	assert.Equal(t, int64(30), s.BucketSeconds)
+	require.Len(t, s.Functions, 3)
+
+	withdraw := s.Functions[0]
+	assert.Equal(t, "withdraw", withdraw.Function, "highest failure rate first")
+	assert.Equal(t, 4, withdraw.Calls)
+	assert.Equal(t, 3, withdraw.Failed)
+	assert.InDelta(t, 0.75, withdraw.FailureRate, 1e-9)
+	assert.Equal(t, "invoke_host_function_trapped", withdraw.TopResult())
+	require.Len(t, withdraw.Buckets, 4)
+	assert.Equal(t, Bucket{Start: time.Unix(60, 0).UTC(), Calls: 1, Failed: 1}, withdraw.Buckets[2])
+	assert.Equal(t, 3, withdraw.Buckets[3].Calls, "the latest calls fall in the last bucket")
+
+	assert.Equal(t, "deposit", s.Functions[1].Function)
+	assert.Equal(t, "balance", s.Functions[2].Function)
+	assert.Empty(t, s.Functions[2].TopResult())
+}
+
+func TestSummarizeFailuresEmpty(t *testing.T) {
+	s := SummarizeFailures(nil, 0)
+	assert.Empty(t, s.Functions)
+
+	var buf bytes.Buffer
+	require.NoError(t, RenderHeatmap(&buf, s))
+	assert.Contains(t, buf.String(), "0 invocation(s)")
+}
+
+func TestRenderHeatmap(t *testing.T) {
+	s := SummarizeFailures([]Invocation{
+		call("<script>", StatusFailed, "invoke_host_function_trapped", 0),
+		call("<script>", StatusSuccess, "", 60),
+	}, 2)
+
+	var buf bytes.Buffer
+	require.NoError(t, RenderHeatmap(&buf, s))
+	out := buf.String()
+	assert.Contains(t, out, "&lt;script&gt;")
+	assert.NotContains(t, out, "<script>")
+	assert.Contains(t, out, "50.0%")
+	assert.Contains(t, out, "background: rgb(255, 50, 80)", "fully failed bucket is red")
+	assert.Contains(t, out, "background: rgb(80, 200, 80)", "healthy bucket is green")
+	assert.Contains(t, out, "1/1 failed")
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contracthistory
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"text/template"
+	"time"
+)
+
+// RenderHeatmap writes s as a standalone HTML page with one row per
+// function and one cell per bucket, shaded by failure rate
+func RenderHeatmap(w io.Writer, s *FailureSummary) error {
+	tmpl, err := template.New("heatmap").Funcs(template.FuncMap{
+		"escapeHTML": html.EscapeString,
+		"formatTime": func(t time.Time) string { return t.UTC().Format("2006-01-02 15:04") },
+		"percent":    func(r float64) string { return fmt.Sprintf("%.1f%%", r*100) },
+		"cellColor":  cellColor,
+	}).Parse(heatmapTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	if err := tmpl.Execute(w, s); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	return nil
+}
+
+// cellColor shades a bucket from green, no failures, to red, all failed;
+// empty buckets are grey
+func cellColor(b Bucket) string {
+	if b.Calls == 0 {
+		return "#eeeeee"
+	}
+	r := b.FailureRate()
+	return fmt.Sprintf("rgb(%d, %d, 80)", 80+int(175*r), 200-int(150*r))
+}
+
+const heatmapTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<title>Contract failure heatmap</title>
+	<style>
+		body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; color: #333; margin: 30px; }
+		h1 { color: #667eea; margin-bottom: 5px; }
+		.meta { color: #666; margin-bottom: 20px; }
+		table { border-collapse: collapse; }
+		th, td { padding: 6px 10px; text-align: left; border-bottom: 1px solid #e0e0e0; white-space: nowrap; }
+		th.bucket { font-size: 0.75em; font-weight: normal; color: #666; writing-mode: vertical-rl; }
+		td.num { text-align: right; }
+		td.cell { width: 24px; min-width: 24px; padding: 0; border: 1px solid #fff; }
+		code { font-size: 0.85em; }
+	</style>
+</head>
+<body>
+	<h1>Contract failure heatmap</h1>
+	<div class="meta">{{ .Invocations }} invocation(s) from {{ formatTime .From }} to {{ formatTime .To }} UTC, {{ .BucketSeconds }}s per column</div>
+	<table>
+		<thead>
+			<tr>
+				<th>Contract</th>
+				<th>Function</th>
+				<th>Calls</th>
+				<th>Failed</th>
+				<th>Rate</th>
+				<th>Top result</th>
+				{{ if .Functions }}{{ range (index .Functions 0).Buckets }}<th class="bucket">{{ formatTime .Start }}</th>{{ end }}{{ end }}
+			</tr>
+		</thead>
+		<tbody>
+			{{ range .Functions }}
+			<tr>
+				<td><code>{{ escapeHTML .Contract }}</code></td>
+				<td>{{ escapeHTML .Function }}</td>
+				<td class="num">{{ .Calls }}</td>
+				<td class="num">{{ .Failed }}</td>
+				<td class="num">{{ percent .FailureRate }}</td>
+				<td>{{ escapeHTML .TopResult }}</td>
+				{{ range .Buckets }}<td class="cell" style="background: {{ cellColor . }}" title="{{ formatTime .Start }}: {{ .Failed }}/{{ .Calls }} failed"></td>{{ end }}
+			</tr>
+			{{ end }}
+		</tbody>
+	</table>
+</body>
+</html>
+`
@@ -0,0 +1,71 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package contractspec reads the interface a Soroban contract embeds in its
+// Wasm, so that calls can be shown with the names the contract author gave
+// them.
+package contractspec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/wasm"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// sectionName is the Wasm custom section holding the spec entries
+const sectionName = "contractspecv0"
+
+// Spec is a contract's functions
+type Spec struct {
+	funcs map[string]xdr.ScSpecFunctionV0
+}
+
+// Parse reads the spec of a contract from its Wasm. A module without a spec
+// section gives an empty Spec.
+func Parse(code []byte) (*Spec, error) {
+	section, err := wasm.CustomSection(code, sectionName)
+	if err != nil {
+		return nil, err
+	}
+	s := &Spec{funcs: map[string]xdr.ScSpecFunctionV0{}}
+	dec := xdr.NewBytesDecoder()
+	for len(section) > 0 {
+		var entry xdr.ScSpecEntry
+		n, err := dec.DecodeBytes(&entry, section)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode spec entry: %w", err)
+		}
+		section = section[n:]
+		if fn, ok := entry.GetFunctionV0(); ok {
+			s.funcs[string(fn.Name)] = fn
+		}
+	}
+	return s, nil
+}
+
+// Function returns the declaration of a contract function
+func (s *Spec) Function(name string) (xdr.ScSpecFunctionV0, bool) {
+	if s == nil {
+		return xdr.ScSpecFunctionV0{}, false
+	}
+	fn, ok := s.funcs[name]
+	return fn, ok
+}
+
+// FormatCall renders a call as name(arg, ...), naming each argument after
+// its parameter when the spec declares the function. A nil Spec formats
+// without names.
+func (s *Spec) FormatCall(name string, args []xdr.ScVal) string {
+	fn, declared := s.Function(name)
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = decoder.FormatScVal(arg)
+		if declared && i < len(fn.Inputs) {
+			parts[i] = fn.Inputs[i].Name + ": " + parts[i]
+		}
+	}
+	return name + "(" + strings.Join(parts, ", ") + ")"
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package contractspec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wasmWithSection builds a module holding one custom section
+func wasmWithSection(t *testing.T, name string, body []byte) []byte {
+	t.Helper()
+	var payload []byte
+	payload = binary.AppendUvarint(payload, uint64(len(name)))
+	payload = append(payload, name...)
+	payload = append(payload, body...)
+
+	module := append([]byte("\x00asm"), 1, 0, 0, 0)
+	module = append(module, 0)
+	module = binary.AppendUvarint(module, uint64(len(payload)))
+	return append(module, payload...)
+}
+
+func specSection(t *testing.T, entries ...xdr.ScSpecEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, e := range entries {
+		_, err := xdr.Marshal(&buf, e)
+		require.NoError(t, err)
+	}
+	return buf.Bytes()
+}
+
+func TestParse(t *testing.T) {
+	transfer := xdr.ScSpecEntry{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{
+		Name: "transfer",
+		Inputs: []xdr.ScSpecFunctionInputV0{
+			{Name: "to", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeAddress}},
+			{Name: "amount", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeI128}},
+		},
+	}}
+	errs := xdr.ScSpecEntry{Kind: xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0, UdtErrorEnumV0: &xdr.ScSpecUdtErrorEnumV0{
+		Name:  "Error",
+		Cases: []xdr.ScSpecUdtErrorEnumCaseV0{{Name: "InsufficientBalance", Value: 3}},
+	}}
+
+	spec, err := Parse(wasmWithSection(t, "contractspecv0", specSection(t, transfer, errs)))
+	require.NoError(t, err)
+
+	fn, ok := spec.Function("transfer")
+	require.True(t, ok)
+	assert.Len(t, fn.Inputs, 2)
+	_, ok = spec.Function("Error")
+	assert.False(t, ok, "only functions are kept")
+
+	amount := xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Lo: 50}}
+	sym := xdr.ScSymbol("x")
+	other := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+	assert.Equal(t, "transfer(to: x, amount: 50)", spec.FormatCall("transfer", []xdr.ScVal{other, amount}))
+	assert.Equal(t, "mint(x, 50)", spec.FormatCall("mint", []xdr.ScVal{other, amount}))
+
+	var none *Spec
+	assert.Equal(t, "transfer()", none.FormatCall("transfer", nil))
+}
+
+func TestParseWithoutSpec(t *testing.T) {
+	spec, err := Parse(wasmWithSection(t, "name", []byte{0}))
+	require.NoError(t, err)
+	_, ok := spec.Function("transfer")
+	assert.False(t, ok)
+
+	_, err = Parse([]byte("not wasm"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package corpus
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Batch operations that can be checkpointed
+const (
+	OpAdd = "add"
+	OpRun = "run"
+)
+
+// Checkpoint records the progress of an add or run batch so an interrupted
+// batch can resume without redoing completed transactions. It is stored
+// next to the corpus manifest and removed when the batch completes.
+type Checkpoint struct {
+	Operation string `json:"operation"`
+	// Params identifies the inputs the batch was started with; a checkpoint
+	// is only resumed with the same inputs
+	Params    string            `json:"params,omitempty"`
+	StartedAt time.Time         `json:"started_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Done      []string          `json:"done"`
+	Results   map[string]Result `json:"results,omitempty"`
+
+	path string
+	done map[string]bool
+}
+
+func (c *Corpus) checkpointPath(op string) string {
+	return filepath.Join(c.dir, ".checkpoint-"+op+".json")
+}
+
+// NewCheckpoint starts a checkpoint for op, replacing any previous one
+func (c *Corpus) NewCheckpoint(op, params string) (*Checkpoint, error) {
+	now := time.Now().UTC()
+	cp := &Checkpoint{
+		Operation: op,
+		Params:    params,
+		StartedAt: now,
+		UpdatedAt: now,
+		Done:      []string{},
+		path:      c.checkpointPath(op),
+		done:      map[string]bool{},
+	}
+	return cp, cp.save()
+}
+
+// ResumeCheckpoint loads the checkpoint left by an interrupted op batch. It
+// starts a new checkpoint when none exists and fails when the existing one
+// was made with different params.
+func (c *Corpus) ResumeCheckpoint(op, params string) (*Checkpoint, error) {
+	data, err := os.ReadFile(c.checkpointPath(op))
+	if os.IsNotExist(err) {
+		return c.NewCheckpoint(op, params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	if cp.Params != params {
+		return nil, fmt.Errorf("the %s checkpoint of corpus %s was made with different options; run without --resume to start over", op, c.Name)
+	}
+	cp.path = c.checkpointPath(op)
+	cp.done = make(map[string]bool, len(cp.Done))
+	for _, h := range cp.Done {
+		cp.done[h] = true
+	}
+	return &cp, nil
+}
+
+// IsDone reports whether hash was completed before the batch was interrupted
+func (cp *Checkpoint) IsDone(hash string) bool {
+	return cp.done[hash]
+}
+
+// Result returns the recorded run result for hash
+func (cp *Checkpoint) Result(hash string) (Result, bool) {
+	r, ok := cp.Results[hash]
+	return r, ok
+}
+
+// MarkDone records hash as completed, with its result for run batches, and
+// persists the checkpoint
+func (cp *Checkpoint) MarkDone(hash string, r *Result) error {
+	if !cp.done[hash] {
+		cp.done[hash] = true
+		cp.Done = append(cp.Done, hash)
+	}
+	if r != nil {
+		if cp.Results == nil {
+			cp.Results = map[string]Result{}
+		}
+		cp.Results[hash] = *r
+	}
+	cp.UpdatedAt = time.Now().UTC()
+	return cp.save()
+}
+
+// Finish removes the checkpoint once the batch has completed
+func (cp *Checkpoint) Finish() error {
+	if err := os.Remove(cp.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove checkpoint: %w", err)
+	}
+	return nil
+}
+
+func (cp *Checkpoint) save() error {
+	if err := os.MkdirAll(filepath.Dir(cp.path), 0755); err != nil {
+		return fmt.Errorf("failed to create corpus directory: %w", err)
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	// Write then rename so an interruption never leaves a truncated file
+	tmp := cp.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, cp.path); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	return nil
+}
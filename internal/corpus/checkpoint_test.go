@@ -0,0 +1,75 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package corpus
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint_ResumeAdd(t *testing.T) {
+	c, err := OpenOrCreate(t.TempDir(), "audit")
+	require.NoError(t, err)
+
+	cp, err := c.NewCheckpoint(OpAdd, "testnet")
+	require.NoError(t, err)
+	require.NoError(t, cp.MarkDone("aa", nil))
+
+	resumed, err := c.ResumeCheckpoint(OpAdd, "testnet")
+	require.NoError(t, err)
+	assert.True(t, resumed.IsDone("aa"))
+	assert.False(t, resumed.IsDone("bb"))
+
+	_, err = c.ResumeCheckpoint(OpAdd, "mainnet")
+	assert.Error(t, err, "options must match the interrupted batch")
+
+	require.NoError(t, resumed.Finish())
+	_, err = os.Stat(c.checkpointPath(OpAdd))
+	assert.True(t, os.IsNotExist(err))
+
+	fresh, err := c.ResumeCheckpoint(OpAdd, "mainnet")
+	require.NoError(t, err, "resuming without a checkpoint starts a new one")
+	assert.False(t, fresh.IsDone("aa"))
+}
+
+func TestCheckpoint_ResumeRun(t *testing.T) {
+	c, err := OpenOrCreate(t.TempDir(), "suite")
+	require.NoError(t, err)
+	for _, h := range []string{"aa", "bb", "cc"} {
+		require.NoError(t, c.Add(Entry{Hash: h, Network: "testnet", EnvelopeXdr: "ENV"}, nil))
+	}
+
+	// The first run is interrupted after two entries
+	ctx, cancel := context.WithCancel(context.Background())
+	var replayed []string
+	runner := simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		replayed = append(replayed, req.EnvelopeXdr)
+		if len(replayed) == 2 {
+			cancel()
+		}
+		return &simulator.SimulationResponse{Status: "success"}, nil
+	})
+	cp, err := c.NewCheckpoint(OpRun, "")
+	require.NoError(t, err)
+	m, err := c.Run(ctx, runner, RunOptions{Checkpoint: cp})
+	require.NoError(t, err)
+	assert.Equal(t, 1, m.Failed, "the canceled entry fails")
+
+	replayed = nil
+	cp, err = c.ResumeCheckpoint(OpRun, "")
+	require.NoError(t, err)
+	m, err = c.Run(context.Background(), runner, RunOptions{Checkpoint: cp})
+	require.NoError(t, err)
+
+	assert.Len(t, replayed, 2, "only the canceled and remaining entries replay")
+	assert.Equal(t, 1, m.Resumed)
+	assert.Equal(t, 3, m.Passed)
+	assert.True(t, m.Results[0].Resumed)
+	assert.False(t, m.Results[1].Resumed)
+}
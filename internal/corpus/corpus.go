@@ -0,0 +1,216 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package corpus manages named sets of transactions with pinned ledger
+// snapshots that are replayed together as a regression suite.
+//
+// A corpus lives in <root>/<name>/ as a corpus.json manifest plus one
+// snapshot file per transaction under snapshots/, so it can be committed
+// alongside contract sources and replayed offline in CI.
+package corpus
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/dotandev/hintents/internal/snapshot"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// DefaultRoot is the corpus directory used when none is given
+const DefaultRoot = ".erst/corpus"
+
+const manifestName = "corpus.json"
+
+// Expected outcomes
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// StatusInfra is the actual outcome of an entry whose replay failed to run,
+// e.g. because the simulator is missing or crashed. No entry expects it, so
+// it always fails the run.
+const StatusInfra = "infra"
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ErrNotFound is returned when a corpus or entry does not exist
+var ErrNotFound = errors.New("not found")
+
+// Entry is one pinned transaction
+type Entry struct {
+	Hash           string    `json:"hash"`
+	Network        string    `json:"network"`
+	EnvelopeXdr    string    `json:"envelope_xdr"`
+	ResultMetaXdr  string    `json:"result_meta_xdr"`
+	Snapshot       string    `json:"snapshot"`
+	ExpectedStatus string    `json:"expected_status"`
+	Note           string    `json:"note,omitempty"`
+	AddedAt        time.Time `json:"added_at"`
+	// ClosedAt is when the ledger that included the transaction closed.
+	// Entries added by older releases or pinned from a session lack it.
+	ClosedAt time.Time `json:"closed_at,omitzero"`
+}
+
+// Corpus is a named regression suite
+type Corpus struct {
+	Name    string  `json:"name"`
+	Entries []Entry `json:"entries"`
+
+	dir string
+}
+
+// Open loads the corpus name under root
+func Open(root, name string) (*Corpus, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(root, name)
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("corpus %q %w", name, ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to read corpus manifest: %w", err)
+	}
+
+	var c Corpus
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse corpus manifest: %w", err)
+	}
+	c.Name = name
+	c.dir = dir
+	return &c, nil
+}
+
+// OpenOrCreate loads the corpus, starting an empty one if it does not exist
+func OpenOrCreate(root, name string) (*Corpus, error) {
+	c, err := Open(root, name)
+	if errors.Is(err, ErrNotFound) {
+		return &Corpus{Name: name, Entries: []Entry{}, dir: filepath.Join(root, name)}, nil
+	}
+	return c, err
+}
+
+// List returns the names of all corpora under root
+func List(root string) ([]string, error) {
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read corpus directory: %w", err)
+	}
+
+	var names []string
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(root, d.Name(), manifestName)); err == nil {
+			names = append(names, d.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Add pins a transaction with its ledger state, replacing any existing entry
+// for the same hash
+func (c *Corpus) Add(e Entry, ledger map[string]string) error {
+	if e.Hash == "" || e.EnvelopeXdr == "" {
+		return errors.New("entry requires a hash and an envelope")
+	}
+	if e.AddedAt.IsZero() {
+		e.AddedAt = time.Now().UTC()
+	}
+	e.Snapshot = filepath.Join("snapshots", e.Hash+".json")
+
+	if err := os.MkdirAll(filepath.Join(c.dir, "snapshots"), 0755); err != nil {
+		return fmt.Errorf("failed to create corpus directory: %w", err)
+	}
+	if err := snapshot.Save(filepath.Join(c.dir, e.Snapshot), snapshot.FromMap(ledger)); err != nil {
+		return err
+	}
+
+	c.Entries = append(c.remove(e.Hash), e)
+	return c.save()
+}
+
+// Remove drops a transaction and its snapshot
+func (c *Corpus) Remove(hash string) error {
+	kept := c.remove(hash)
+	if len(kept) == len(c.Entries) {
+		return fmt.Errorf("transaction %s %w in corpus %s", hash, ErrNotFound, c.Name)
+	}
+	c.Entries = kept
+
+	if err := os.Remove(filepath.Join(c.dir, "snapshots", hash+".json")); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+	return c.save()
+}
+
+// LoadSnapshot returns the pinned ledger state of an entry
+func (c *Corpus) LoadSnapshot(e Entry) (map[string]string, error) {
+	snap, err := snapshot.Load(filepath.Join(c.dir, e.Snapshot))
+	if err != nil {
+		return nil, err
+	}
+	return snap.ToMap(), nil
+}
+
+func (c *Corpus) remove(hash string) []Entry {
+	kept := make([]Entry, 0, len(c.Entries))
+	for _, e := range c.Entries {
+		if e.Hash != hash {
+			kept = append(kept, e)
+		}
+	}
+	return kept
+}
+
+func (c *Corpus) save() error {
+	sort.Slice(c.Entries, func(i, j int) bool { return c.Entries[i].Hash < c.Entries[j].Hash })
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create corpus directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal corpus manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(c.dir, manifestName), data, 0644); err != nil {
+		return fmt.Errorf("failed to write corpus manifest: %w", err)
+	}
+	return nil
+}
+
+// StatusFromResult derives the expected outcome from an on-chain result
+func StatusFromResult(resultXdr string) string {
+	var res xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXdr, &res); err != nil {
+		return StatusSuccess
+	}
+	switch res.Result.Code {
+	case xdr.TransactionResultCodeTxSuccess, xdr.TransactionResultCodeTxFeeBumpInnerSuccess:
+		return StatusSuccess
+	default:
+		return StatusError
+	}
+}
+
+func validateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid corpus name %q: use letters, digits, '.', '_' or '-'", name)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package corpus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorpus_AddRemoveList(t *testing.T) {
+	root := t.TempDir()
+
+	_, err := Open(root, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = Open(root, "../escape")
+	assert.Error(t, err)
+
+	c, err := OpenOrCreate(root, "payments")
+	require.NoError(t, err)
+	require.NoError(t, c.Add(Entry{Hash: "bb", Network: "testnet", EnvelopeXdr: "ENV", ExpectedStatus: StatusSuccess}, map[string]string{"k": "v"}))
+	require.NoError(t, c.Add(Entry{Hash: "aa", Network: "testnet", EnvelopeXdr: "ENV"}, nil))
+	require.NoError(t, c.Add(Entry{Hash: "bb", Network: "mainnet", EnvelopeXdr: "ENV2"}, map[string]string{"k": "v2"}))
+
+	reopened, err := Open(root, "payments")
+	require.NoError(t, err)
+	require.Len(t, reopened.Entries, 2)
+	assert.Equal(t, "aa", reopened.Entries[0].Hash, "entries are sorted by hash")
+	assert.Equal(t, "mainnet", reopened.Entries[1].Network, "re-adding replaces the entry")
+
+	ledger, err := reopened.LoadSnapshot(reopened.Entries[1])
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"k": "v2"}, ledger)
+
+	require.NoError(t, reopened.Remove("aa"))
+	assert.ErrorIs(t, reopened.Remove("aa"), ErrNotFound)
+
+	names, err := List(root)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"payments"}, names)
+}
+
+func TestCorpus_Run(t *testing.T) {
+	c, err := OpenOrCreate(t.TempDir(), "suite")
+	require.NoError(t, err)
+	require.NoError(t, c.Add(Entry{Hash: "ok", Network: "testnet", EnvelopeXdr: "PASS", ExpectedStatus: StatusSuccess}, nil))
+	require.NoError(t, c.Add(Entry{Hash: "regressed", Network: "testnet", EnvelopeXdr: "TRAP", ExpectedStatus: StatusSuccess}, nil))
+	require.NoError(t, c.Add(Entry{Hash: "expected-fail", Network: "testnet", EnvelopeXdr: "TRAP", ExpectedStatus: StatusError}, nil))
+
+	runner := simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		if req.LedgerEntries["injected"] != "yes" {
+			return nil, errors.New("prepare hook was not applied")
+		}
+		if req.EnvelopeXdr == "TRAP" {
+			return nil, &simulator.SimulationError{Message: "trap"}
+		}
+		return &simulator.SimulationResponse{Status: "success"}, nil
+	})
+
+	m, err := c.Run(context.Background(), runner, RunOptions{
+		Prepare: func(e Entry, ledger map[string]string) error {
+			ledger["injected"] = "yes"
+			return nil
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, m.Passed)
+	assert.Equal(t, 1, m.Failed)
+	assert.False(t, m.OK())
+	for _, r := range m.Results {
+		assert.Equal(t, r.Hash != "regressed", r.Pass, r.Hash)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, m.WriteJUnit(&buf))
+	assert.Contains(t, buf.String(), `<testsuite name="erst-corpus-suite" tests="3" failures="1">`)
+	assert.Contains(t, buf.String(), `expected success, got error`)
+}
+
+func TestCorpus_RunInfraFailure(t *testing.T) {
+	c, err := OpenOrCreate(t.TempDir(), "suite")
+	require.NoError(t, err)
+	require.NoError(t, c.Add(Entry{Hash: "expected-fail", Network: "testnet", EnvelopeXdr: "TRAP", ExpectedStatus: StatusError}, nil))
+
+	runner := simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		return nil, errors.New("simulator execution failed: exec: \"erst-sim\": executable file not found")
+	})
+
+	m, err := c.Run(context.Background(), runner, RunOptions{})
+	require.NoError(t, err)
+	require.Len(t, m.Results, 1)
+	assert.False(t, m.OK(), "an entry expecting an error must not pass when the simulator never ran")
+	assert.Equal(t, StatusInfra, m.Results[0].Actual)
+}
+
+func TestCorpus_RunLimitExceeded(t *testing.T) {
+	c, err := OpenOrCreate(t.TempDir(), "suite")
+	require.NoError(t, err)
+	require.NoError(t, c.Add(Entry{Hash: "runaway", Network: "testnet", EnvelopeXdr: "LOOP", ExpectedStatus: StatusError}, nil))
+
+	runner := simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		return nil, &simulator.LimitError{Resource: simulator.ResourceCPU, Limit: "1s", Message: "simulator exceeded its CPU time limit of 1s"}
+	})
+
+	m, err := c.Run(context.Background(), runner, RunOptions{})
+	require.NoError(t, err)
+	require.Len(t, m.Results, 1)
+
+	r := m.Results[0]
+	assert.False(t, r.Pass, "a limit breach never counts as an expected error")
+	require.NotNil(t, r.LimitExceeded)
+	assert.Equal(t, simulator.ResourceCPU, r.LimitExceeded.Resource)
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package corpus
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// RunOptions customize a corpus run
+type RunOptions struct {
+	// Prepare may rewrite the pinned ledger state before replay, e.g. to
+	// inject candidate contract code
+	Prepare func(e Entry, ledger map[string]string) error
+	// Checkpoint, when set, supplies results completed by an interrupted
+	// run and records each new result as it completes
+	Checkpoint *Checkpoint
+}
+
+// Result is the outcome of replaying one entry
+type Result struct {
+	Hash     string `json:"hash"`
+	Network  string `json:"network"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Pass     bool   `json:"pass"`
+	Error    string `json:"error,omitempty"`
+	// LimitExceeded is set when the replay was stopped at a simulator
+	// resource limit; such entries fail whatever their expected status
+	LimitExceeded *simulator.LimitError `json:"limit_exceeded,omitempty"`
+	Duration      time.Duration         `json:"duration_ns"`
+	// Resumed is set when the result was taken from a checkpoint
+	Resumed bool `json:"resumed,omitempty"`
+}
+
+// Matrix is the pass/fail outcome of a whole corpus run
+type Matrix struct {
+	Corpus  string   `json:"corpus"`
+	Passed  int      `json:"passed"`
+	Failed  int      `json:"failed"`
+	Resumed int      `json:"resumed,omitempty"`
+	Results []Result `json:"results"`
+}
+
+// OK reports whether every entry passed
+func (m *Matrix) OK() bool {
+	return m.Failed == 0
+}
+
+// Run replays every entry in order against its pinned snapshot. With a
+// checkpoint, entries it already holds are not replayed again, and an error
+// saving the checkpoint is returned alongside the matrix.
+func (c *Corpus) Run(ctx context.Context, runner simulator.RunnerInterface, opts RunOptions) (*Matrix, error) {
+	m := &Matrix{Corpus: c.Name, Results: make([]Result, 0, len(c.Entries))}
+	var cpErr error
+
+	for _, e := range c.Entries {
+		if cp := opts.Checkpoint; cp != nil {
+			if r, ok := cp.Result(e.Hash); ok && cp.IsDone(e.Hash) {
+				r.Resumed = true
+				m.add(r)
+				m.Resumed++
+				continue
+			}
+		}
+
+		start := time.Now()
+		r := Result{Hash: e.Hash, Network: e.Network, Expected: e.ExpectedStatus}
+		if r.Expected == "" {
+			r.Expected = StatusSuccess
+		}
+
+		if ctx.Err() != nil {
+			r.Error = ctx.Err().Error()
+		} else if actual, err := c.replay(runner, e, opts); err != nil && actual == "" {
+			r.Error = err.Error()
+			errors.As(err, &r.LimitExceeded)
+		} else {
+			r.Actual = actual
+			if err != nil {
+				r.Error = err.Error()
+			}
+			r.Pass = r.Actual == r.Expected
+		}
+		r.Duration = time.Since(start)
+		m.add(r)
+
+		// Entries skipped because the run was canceled are not completed
+		if opts.Checkpoint != nil && ctx.Err() == nil && cpErr == nil {
+			cpErr = opts.Checkpoint.MarkDone(e.Hash, &r)
+		}
+	}
+
+	return m, cpErr
+}
+
+func (m *Matrix) add(r Result) {
+	if r.Pass {
+		m.Passed++
+	} else {
+		m.Failed++
+	}
+	m.Results = append(m.Results, r)
+}
+
+// replay returns the simulated status. A simulator-reported failure yields
+// StatusError together with the failure message and any other runner error
+// StatusInfra. Errors before the simulator ran, and a resource limit
+// stopping it, mean the entry could not be replayed and return an empty
+// status.
+func (c *Corpus) replay(runner simulator.RunnerInterface, e Entry, opts RunOptions) (string, error) {
+	ledger, err := c.LoadSnapshot(e)
+	if err != nil {
+		return "", err
+	}
+	if opts.Prepare != nil {
+		if err := opts.Prepare(e, ledger); err != nil {
+			return "", fmt.Errorf("failed to prepare ledger state: %w", err)
+		}
+	}
+
+	resp, err := runner.Run(&simulator.SimulationRequest{
+		EnvelopeXdr:   e.EnvelopeXdr,
+		ResultMetaXdr: e.ResultMetaXdr,
+		LedgerEntries: ledger,
+	})
+	var limitErr *simulator.LimitError
+	if errors.As(err, &limitErr) {
+		return "", err
+	}
+	var simErr *simulator.SimulationError
+	if errors.As(err, &simErr) {
+		return StatusError, err
+	}
+	if err != nil {
+		return StatusInfra, err
+	}
+	if resp.Status == "error" {
+		return StatusError, fmt.Errorf("%s", resp.Error)
+	}
+	return StatusSuccess, nil
+}
+
+type junitSuite struct {
+	XMLName  xml.Name    `xml:"testsuite"`
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name    string        `xml:"name,attr"`
+	Class   string        `xml:"classname,attr"`
+	Time    string        `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the matrix as a JUnit XML report for CI systems
+func (m *Matrix) WriteJUnit(w io.Writer) error {
+	suite := junitSuite{Name: "erst-corpus-" + m.Corpus, Tests: len(m.Results), Failures: m.Failed}
+	for _, r := range m.Results {
+		tc := junitCase{Name: r.Hash, Class: m.Corpus + "." + r.Network, Time: fmt.Sprintf("%.3f", r.Duration.Seconds())}
+		if !r.Pass {
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("expected %s, got %s", r.Expected, orUnknown(r.Actual)),
+				Text:    r.Error,
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return fmt.Errorf("failed to encode JUnit report: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "no result"
+	}
+	return s
+}
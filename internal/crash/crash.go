@@ -0,0 +1,231 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package crash writes local crash bundles when erst panics or the
+// simulator crashes, so they can be reviewed and optionally reported.
+package crash
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/platform"
+)
+
+// Version is recorded in every bundle; main sets it at startup
+var Version = "dev"
+
+// Bundle kinds
+const (
+	KindPanic     = "panic"
+	KindSimulator = "simulator"
+)
+
+// Bundle is one crash report. Every free-text field is redacted before it
+// is written.
+type Bundle struct {
+	ID          string            `json:"id"`
+	Kind        string            `json:"kind"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Version     string            `json:"version"`
+	Command     []string          `json:"command"`
+	Message     string            `json:"message"`
+	Stack       string            `json:"stack,omitempty"`
+	Stderr      string            `json:"stderr,omitempty"`
+	Request     map[string]string `json:"request,omitempty"`
+	Environment Environment       `json:"environment"`
+}
+
+// Environment describes the machine erst ran on, without identifying it
+type Environment struct {
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	GoVersion string `json:"go_version"`
+	NumCPU    int    `json:"num_cpu"`
+}
+
+// New creates a bundle for the running process
+func New(kind, message string) *Bundle {
+	b := &Bundle{
+		ID:        newID(),
+		Kind:      kind,
+		CreatedAt: time.Now().UTC(),
+		Version:   Version,
+		Message:   Redact(message),
+		Environment: Environment{
+			OS:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			GoVersion: runtime.Version(),
+			NumCPU:    runtime.NumCPU(),
+		},
+	}
+	for _, arg := range os.Args {
+		b.Command = append(b.Command, Redact(arg))
+	}
+	return b
+}
+
+func newID() string {
+	var buf [3]byte
+	_, _ = rand.Read(buf[:])
+	return time.Now().UTC().Format("20060102-150405") + "-" + hex.EncodeToString(buf[:])
+}
+
+// Dir returns the directory crash bundles are written to
+func Dir() (string, error) {
+	dir, err := platform.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "crashes"), nil
+}
+
+// Save writes the bundle and returns its path
+func Save(b *Bundle) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create crash directory: %w", err)
+	}
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, b.ID+".json")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return path, nil
+}
+
+// Load reads the bundle with the given ID
+func Load(id string) (*Bundle, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, filepath.Base(id)+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("crash report %q not found", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crash report: %w", err)
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse crash report %s: %w", id, err)
+	}
+	return &b, nil
+}
+
+// List returns all saved bundles, newest first
+func List() ([]*Bundle, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list crash reports: %w", err)
+	}
+
+	var bundles []*Bundle
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		b, err := Load(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			continue
+		}
+		bundles = append(bundles, b)
+	}
+	sort.Slice(bundles, func(i, j int) bool { return bundles[i].CreatedAt.After(bundles[j].CreatedAt) })
+	return bundles, nil
+}
+
+// Recover turns a panic into a crash bundle and exits with status 2. Use it
+// as the first deferred call in main.
+func Recover() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	b := New(KindPanic, fmt.Sprint(r))
+	b.Stack = Redact(string(debug.Stack()))
+
+	fmt.Fprintf(os.Stderr, "\nerst crashed: %s\n", b.Message)
+	if path, err := Save(b); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to save a crash report (%v). Stack trace:\n%s\n", err, b.Stack)
+	} else {
+		fmt.Fprintf(os.Stderr, "A crash report was saved to %s\n", path)
+		fmt.Fprintf(os.Stderr, "Review it, then send it to the maintainers with: erst crash report %s\n", b.ID)
+	}
+	os.Exit(2)
+}
+
+// SaveSimulatorCrash records a crash of the simulator process. request
+// holds metadata about the simulation request, never the XDR itself.
+func SaveSimulatorCrash(message, stderr string, request map[string]string) (*Bundle, string, error) {
+	b := New(KindSimulator, message)
+	b.Stderr = Redact(stderr)
+	if len(request) > 0 {
+		b.Request = make(map[string]string, len(request))
+		for k, v := range request {
+			b.Request[k] = Redact(v)
+		}
+	}
+	path, err := Save(b)
+	return b, path, err
+}
+
+var (
+	// Stellar strkeys: accounts, secrets, contracts, muxed accounts and others
+	strkeyRe = regexp.MustCompile(`\b(?:[GSCTPXB][A-Z2-7]{55}|M[A-Z2-7]{68})\b`)
+	hashRe   = regexp.MustCompile(`\b[0-9a-fA-F]{64}\b`)
+	// Long base64 runs are XDR blobs or tokens
+	base64Re = regexp.MustCompile(`[A-Za-z0-9+/]{80,}={0,2}`)
+	urlRe    = regexp.MustCompile(`https?://[^\s"'<>]+`)
+	secretRe = regexp.MustCompile(`(?i)\b([A-Z0-9_]*(?:token|secret|password|passwd|apikey|api_key|key))(\s*[=:]\s*)("[^"]*"|\S+)`)
+)
+
+// Redact removes keys, hashes, XDR blobs, URL credentials and query
+// strings, secret-looking assignments and the home directory from s
+func Redact(s string) string {
+	s = urlRe.ReplaceAllStringFunc(s, func(raw string) string {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return "<redacted:url>"
+		}
+		u.User = nil
+		if u.RawQuery != "" {
+			u.RawQuery = "redacted"
+		}
+		u.Fragment = ""
+		return u.String()
+	})
+	s = secretRe.ReplaceAllString(s, "$1$2<redacted>")
+	s = strkeyRe.ReplaceAllStringFunc(s, func(k string) string { return "<redacted:" + k[:1] + ">" })
+	s = hashRe.ReplaceAllString(s, "<redacted:hash>")
+	s = base64Re.ReplaceAllString(s, "<redacted:xdr>")
+	if home, err := os.UserHomeDir(); err == nil && len(home) > 1 {
+		s = strings.ReplaceAll(s, home, "~")
+	}
+	return s
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package crash
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	account := "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	secret := "SCZANGBA5YHTNYVVV4C3U252E2B6P6F5T3U6MM63WBSBZATAQI3EBTQ4"
+	hash := strings.Repeat("ab", 32)
+	xdr := strings.Repeat("AAAAAgAAAAB", 10) + "=="
+
+	in := "tx " + hash + " from " + account + " signed " + secret +
+		" xdr " + xdr + " url https://user:pw@rpc.example.org/path?apikey=1 ERST_SNAPSHOT_TOKEN=abc123"
+	out := Redact(in)
+
+	for _, leaked := range []string{account, secret, hash, xdr, "user:pw", "apikey=1", "abc123"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("redacted output still contains %q: %s", leaked, out)
+		}
+	}
+	for _, kept := range []string{"<redacted:G>", "<redacted:S>", "<redacted:hash>", "<redacted:xdr>", "https://rpc.example.org/path?redacted", "ERST_SNAPSHOT_TOKEN=<redacted>"} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("expected %q in %s", kept, out)
+		}
+	}
+}
+
+func TestSaveLoadList(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+
+	first := New(KindPanic, "runtime error: index out of range")
+	first.Stack = "goroutine 1"
+	if _, err := Save(first); err != nil {
+		t.Fatal(err)
+	}
+	second, path, err := SaveSimulatorCrash("exit status 101", "thread 'main' panicked at src/lib.rs", map[string]string{"ledger_entries": "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, second.ID+".json") {
+		t.Errorf("unexpected path %s", path)
+	}
+
+	loaded, err := Load(second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Kind != KindSimulator || loaded.Request["ledger_entries"] != "3" || loaded.Environment.OS == "" {
+		t.Errorf("unexpected bundle: %+v", loaded)
+	}
+
+	bundles, err := List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bundles) != 2 {
+		t.Fatalf("listed %d bundles, want 2", len(bundles))
+	}
+
+	if _, err := Load("missing"); err == nil {
+		t.Error("expected error for a missing report")
+	}
+}
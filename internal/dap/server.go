@@ -0,0 +1,389 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package dap implements a Debug Adapter Protocol server over the simulator
+// step protocol, so editors can set breakpoints on contract functions and
+// inspect call stacks, storage and budget during a replay.
+package dap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/sourcemap"
+)
+
+const threadID = 1
+
+// Variable references for the two scopes exposed per stop
+const (
+	storageRef = 1
+	budgetRef  = 2
+)
+
+// Session is a live step session, implemented by *simulator.StepSession
+type Session interface {
+	Current() *simulator.StepEvent
+	Done() bool
+	Result() *simulator.SimulationResponse
+	Step() (*simulator.StepEvent, error)
+	ContinueTo(breakpoints map[string]bool) (*simulator.StepEvent, error)
+	Inspect() (*simulator.StepEvent, error)
+	Close() error
+}
+
+// Launcher starts a step session for a launch request
+type Launcher func(ctx context.Context, args LaunchArgs) (Session, error)
+
+// Server handles one debug adapter connection
+type Server struct {
+	launch Launcher
+
+	mu  sync.Mutex
+	w   io.Writer
+	seq int
+
+	session     Session
+	srcMap      *sourcemap.Map
+	stopOnEntry bool
+	breakpoints map[string]bool
+}
+
+// NewServer creates a debug adapter that starts sessions with launch
+func NewServer(launch Launcher) *Server {
+	return &Server{launch: launch, breakpoints: make(map[string]bool)}
+}
+
+// Serve processes requests from r until disconnect, EOF or ctx is cancelled
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.w = w
+	br := bufio.NewReader(r)
+	defer s.closeSession()
+
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		msg, err := ReadMessage(br)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if msg.Type != "request" {
+			continue
+		}
+
+		if done := s.handle(ctx, msg); done {
+			return nil
+		}
+	}
+}
+
+// handle dispatches one request and returns true when the client disconnected
+func (s *Server) handle(ctx context.Context, req *Message) bool {
+	logger.Logger.Debug("DAP request", "command", req.Command, "seq", req.Seq)
+
+	switch req.Command {
+	case "initialize":
+		s.respond(req, Capabilities{
+			SupportsConfigurationDoneRequest: true,
+			SupportsFunctionBreakpoints:      true,
+			SupportsTerminateRequest:         true,
+		})
+		s.event("initialized", nil)
+
+	case "launch":
+		var args LaunchArgs
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			s.fail(req, fmt.Errorf("invalid launch arguments: %w", err))
+			return false
+		}
+		if err := s.startSession(ctx, args); err != nil {
+			s.fail(req, err)
+			return false
+		}
+		s.respond(req, nil)
+
+	case "setFunctionBreakpoints":
+		var args struct {
+			Breakpoints []struct {
+				Name string `json:"name"`
+			} `json:"breakpoints"`
+		}
+		_ = json.Unmarshal(req.Arguments, &args)
+
+		s.breakpoints = make(map[string]bool)
+		bps := make([]Breakpoint, 0, len(args.Breakpoints))
+		for i, bp := range args.Breakpoints {
+			s.breakpoints[bp.Name] = true
+			bps = append(bps, Breakpoint{ID: i + 1, Verified: true})
+		}
+		s.respond(req, map[string]interface{}{"breakpoints": bps})
+
+	case "setBreakpoints":
+		// Line breakpoints need offset-level stepping, which the simulator
+		// does not expose; steer users to function breakpoints instead
+		var args struct {
+			Breakpoints []json.RawMessage `json:"breakpoints"`
+		}
+		_ = json.Unmarshal(req.Arguments, &args)
+		bps := make([]Breakpoint, len(args.Breakpoints))
+		for i := range bps {
+			bps[i] = Breakpoint{Verified: false, Message: "erst supports function breakpoints only"}
+		}
+		s.respond(req, map[string]interface{}{"breakpoints": bps})
+
+	case "configurationDone":
+		s.respond(req, nil)
+		if s.session == nil {
+			return false
+		}
+		if s.stopOnEntry {
+			s.stopped("entry")
+			return false
+		}
+		s.resume(s.session.ContinueTo(s.breakpoints))
+
+	case "threads":
+		s.respond(req, map[string]interface{}{
+			"threads": []map[string]interface{}{{"id": threadID, "name": "transaction"}},
+		})
+
+	case "stackTrace":
+		frames := s.stackFrames()
+		s.respond(req, map[string]interface{}{"stackFrames": frames, "totalFrames": len(frames)})
+
+	case "scopes":
+		s.respond(req, map[string]interface{}{"scopes": []Scope{
+			{Name: "Storage", VariablesReference: storageRef},
+			{Name: "Budget", VariablesReference: budgetRef},
+		}})
+
+	case "variables":
+		var args struct {
+			VariablesReference int `json:"variablesReference"`
+		}
+		_ = json.Unmarshal(req.Arguments, &args)
+		vars, err := s.variables(args.VariablesReference)
+		if err != nil {
+			s.fail(req, err)
+			return false
+		}
+		s.respond(req, map[string]interface{}{"variables": vars})
+
+	case "continue":
+		if !s.requireSession(req) {
+			return false
+		}
+		s.respond(req, map[string]interface{}{"allThreadsContinued": true})
+		s.resume(s.session.ContinueTo(s.breakpoints))
+
+	case "next", "stepIn":
+		if !s.requireSession(req) {
+			return false
+		}
+		s.respond(req, nil)
+		s.resume(s.session.Step())
+
+	case "stepOut":
+		if !s.requireSession(req) {
+			return false
+		}
+		s.respond(req, nil)
+		s.resume(s.stepOut())
+
+	case "disconnect", "terminate":
+		s.closeSession()
+		s.respond(req, nil)
+		if req.Command == "terminate" {
+			s.event("terminated", nil)
+			return false
+		}
+		return true
+
+	default:
+		s.fail(req, fmt.Errorf("unsupported request: %s", req.Command))
+	}
+
+	return false
+}
+
+func (s *Server) startSession(ctx context.Context, args LaunchArgs) error {
+	if args.SourceMap != "" {
+		m, err := sourcemap.Load(args.SourceMap)
+		if err != nil {
+			return err
+		}
+		s.srcMap = m
+	}
+
+	session, err := s.launch(ctx, args)
+	if err != nil {
+		return err
+	}
+	s.session = session
+	s.stopOnEntry = args.StopOnEntry
+	return nil
+}
+
+func (s *Server) requireSession(req *Message) bool {
+	if s.session == nil || s.session.Done() {
+		s.fail(req, errors.New("no running debug session"))
+		return false
+	}
+	return true
+}
+
+// stepOut steps until the current frame returns
+func (s *Server) stepOut() (*simulator.StepEvent, error) {
+	depth := 0
+	if cur := s.session.Current(); cur != nil && cur.Frame != nil {
+		depth = cur.Frame.Depth
+	}
+	for {
+		ev, err := s.session.Step()
+		if err != nil || ev.Type == simulator.StepEventResult {
+			return ev, err
+		}
+		if ev.Frame == nil || ev.Frame.Depth < depth || (ev.Reason == simulator.PauseReturn && ev.Frame.Depth <= depth) {
+			return ev, nil
+		}
+	}
+}
+
+// resume reports the outcome of a step or continue as DAP events
+func (s *Server) resume(ev *simulator.StepEvent, err error) {
+	if err != nil {
+		s.output("stderr", fmt.Sprintf("simulator error: %v\n", err))
+		s.event("terminated", nil)
+		return
+	}
+
+	if ev.Type == simulator.StepEventResult {
+		exitCode := 0
+		if ev.Response != nil && ev.Response.Status == "error" {
+			exitCode = 1
+			s.output("stderr", fmt.Sprintf("simulation error: %s\n", ev.Response.Error))
+		} else {
+			s.output("console", "simulation finished successfully\n")
+		}
+		s.event("exited", map[string]interface{}{"exitCode": exitCode})
+		s.event("terminated", nil)
+		return
+	}
+
+	reason := "step"
+	if ev.Reason == simulator.PauseCall && ev.Frame != nil && s.breakpoints[ev.Frame.Function] {
+		reason = "function breakpoint"
+	}
+	s.stopped(reason)
+}
+
+func (s *Server) stopped(reason string) {
+	s.event("stopped", map[string]interface{}{
+		"reason":            reason,
+		"threadId":          threadID,
+		"allThreadsStopped": true,
+	})
+}
+
+func (s *Server) stackFrames() []StackFrame {
+	frames := []StackFrame{}
+	if s.session == nil {
+		return frames
+	}
+	cur := s.session.Current()
+	if cur == nil {
+		return frames
+	}
+
+	for i := len(cur.Stack) - 1; i >= 0; i-- {
+		f := cur.Stack[i]
+		sf := StackFrame{ID: len(frames) + 1, Name: f.Function}
+		if s.srcMap != nil {
+			if loc, ok := s.srcMap.Function(f.Function); ok {
+				sf.Source = &Source{Name: loc.File, Path: loc.File}
+				sf.Line = loc.Line
+				sf.Column = loc.Column
+			}
+		}
+		frames = append(frames, sf)
+	}
+	return frames
+}
+
+func (s *Server) variables(ref int) ([]Variable, error) {
+	if s.session == nil || s.session.Done() {
+		return []Variable{}, nil
+	}
+	state, err := s.session.Inspect()
+	if err != nil {
+		return nil, err
+	}
+
+	vars := []Variable{}
+	switch ref {
+	case storageRef:
+		for _, e := range state.Storage {
+			vars = append(vars, Variable{Name: e.Key, Value: e.Value, Type: e.Durability})
+		}
+		sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	case budgetRef:
+		if b := state.Budget; b != nil {
+			vars = append(vars,
+				Variable{Name: "cpu_instructions", Value: strconv.FormatUint(b.CPUInstructions, 10)},
+				Variable{Name: "memory_bytes", Value: strconv.FormatUint(b.MemoryBytes, 10)},
+				Variable{Name: "cpu_usage_percent", Value: fmt.Sprintf("%.2f", b.CPUUsagePercent)},
+				Variable{Name: "memory_usage_percent", Value: fmt.Sprintf("%.2f", b.MemoryUsagePercent)},
+			)
+		}
+	}
+	return vars, nil
+}
+
+func (s *Server) closeSession() {
+	if s.session != nil {
+		_ = s.session.Close()
+		s.session = nil
+	}
+}
+
+func (s *Server) respond(req *Message, body interface{}) {
+	ok := true
+	s.send(&Message{Type: "response", RequestSeq: req.Seq, Command: req.Command, Success: &ok, Body: body})
+}
+
+func (s *Server) fail(req *Message, err error) {
+	ok := false
+	s.send(&Message{Type: "response", RequestSeq: req.Seq, Command: req.Command, Success: &ok, ErrMessage: err.Error()})
+}
+
+func (s *Server) event(name string, body interface{}) {
+	s.send(&Message{Type: "event", Event: name, Body: body})
+}
+
+func (s *Server) output(category, text string) {
+	s.event("output", map[string]interface{}{"category": category, "output": text})
+}
+
+func (s *Server) send(msg *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	msg.Seq = s.seq
+	if err := WriteMessage(s.w, msg); err != nil {
+		logger.Logger.Error("Failed to write DAP message", "error", err)
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package dap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSession struct {
+	events []*simulator.StepEvent
+	pos    int
+	closed bool
+}
+
+func (f *fakeSession) Current() *simulator.StepEvent { return f.events[f.pos] }
+func (f *fakeSession) Done() bool                    { return f.Current().Type == simulator.StepEventResult }
+func (f *fakeSession) Result() *simulator.SimulationResponse {
+	return f.Current().Response
+}
+
+func (f *fakeSession) Step() (*simulator.StepEvent, error) {
+	f.pos++
+	return f.Current(), nil
+}
+
+func (f *fakeSession) ContinueTo(bps map[string]bool) (*simulator.StepEvent, error) {
+	for {
+		ev, _ := f.Step()
+		if ev.Type == simulator.StepEventResult || (ev.Reason == simulator.PauseCall && bps[ev.Frame.Function]) {
+			return ev, nil
+		}
+	}
+}
+
+func (f *fakeSession) Inspect() (*simulator.StepEvent, error) {
+	return &simulator.StepEvent{
+		Type:    simulator.StepEventState,
+		Budget:  &simulator.BudgetUsage{CPUInstructions: 900, MemoryBytes: 64},
+		Storage: []simulator.StorageEntry{{Key: "Balance", Value: "10", Durability: "Persistent"}},
+	}, nil
+}
+
+func (f *fakeSession) Close() error {
+	f.closed = true
+	return nil
+}
+
+func newFakeSession() *fakeSession {
+	transfer := simulator.StepFrame{Function: "transfer", Depth: 1}
+	spend := simulator.StepFrame{Function: "spend_balance", Depth: 2}
+	return &fakeSession{events: []*simulator.StepEvent{
+		{Type: simulator.StepEventPaused, Reason: simulator.PauseStart},
+		{Type: simulator.StepEventPaused, Reason: simulator.PauseCall, Frame: &transfer, Stack: []simulator.StepFrame{transfer}},
+		{Type: simulator.StepEventPaused, Reason: simulator.PauseCall, Frame: &spend, Stack: []simulator.StepFrame{transfer, spend}},
+		{Type: simulator.StepEventPaused, Reason: simulator.PauseReturn, Frame: &spend, Stack: []simulator.StepFrame{transfer, spend}},
+		{Type: simulator.StepEventPaused, Reason: simulator.PauseReturn, Frame: &transfer, Stack: []simulator.StepFrame{transfer}},
+		{Type: simulator.StepEventResult, Response: &simulator.SimulationResponse{Status: "error", Error: "HostError"}},
+	}}
+}
+
+func request(t *testing.T, buf *bytes.Buffer, seq int, command string, args interface{}) {
+	t.Helper()
+	msg := &Message{Seq: seq, Type: "request", Command: command}
+	if args != nil {
+		raw, err := json.Marshal(args)
+		require.NoError(t, err)
+		msg.Arguments = raw
+	}
+	require.NoError(t, WriteMessage(buf, msg))
+}
+
+func readAll(t *testing.T, out *bytes.Buffer) []*Message {
+	t.Helper()
+	r := bufio.NewReader(out)
+	var msgs []*Message
+	for {
+		msg, err := ReadMessage(r)
+		if err != nil {
+			return msgs
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+func find(msgs []*Message, typ, name string) []*Message {
+	var out []*Message
+	for _, m := range msgs {
+		if m.Type == typ && (m.Command == name || m.Event == name) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func TestServer_BreakpointSession(t *testing.T) {
+	dir := t.TempDir()
+	mapPath := filepath.Join(dir, "map.json")
+	require.NoError(t, os.WriteFile(mapPath, []byte(`{"functions":{"spend_balance":{"file":"src/balance.rs","line":12}}}`), 0644))
+
+	session := newFakeSession()
+	var launched LaunchArgs
+	srv := NewServer(func(ctx context.Context, args LaunchArgs) (Session, error) {
+		launched = args
+		return session, nil
+	})
+
+	var in bytes.Buffer
+	request(t, &in, 1, "initialize", map[string]string{"adapterID": "erst"})
+	request(t, &in, 2, "launch", LaunchArgs{Hash: "abc", Network: "testnet", SourceMap: mapPath})
+	request(t, &in, 3, "setFunctionBreakpoints", map[string]interface{}{"breakpoints": []map[string]string{{"name": "spend_balance"}}})
+	request(t, &in, 4, "configurationDone", nil)
+	request(t, &in, 5, "stackTrace", map[string]int{"threadId": 1})
+	request(t, &in, 6, "variables", map[string]int{"variablesReference": storageRef})
+	request(t, &in, 7, "stepOut", map[string]int{"threadId": 1})
+	request(t, &in, 8, "continue", map[string]int{"threadId": 1})
+	request(t, &in, 9, "disconnect", nil)
+
+	var out bytes.Buffer
+	require.NoError(t, srv.Serve(context.Background(), &in, &out))
+
+	msgs := readAll(t, &out)
+	assert.Equal(t, "testnet", launched.Network)
+	assert.True(t, session.closed)
+
+	require.Len(t, find(msgs, "event", "initialized"), 1)
+
+	stops := find(msgs, "event", "stopped")
+	require.Len(t, stops, 2)
+	assert.Equal(t, "function breakpoint", stops[0].Body.(map[string]interface{})["reason"])
+	assert.Equal(t, "step", stops[1].Body.(map[string]interface{})["reason"])
+
+	trace := find(msgs, "response", "stackTrace")
+	require.Len(t, trace, 1)
+	frames := trace[0].Body.(map[string]interface{})["stackFrames"].([]interface{})
+	require.Len(t, frames, 2)
+	top := frames[0].(map[string]interface{})
+	assert.Equal(t, "spend_balance", top["name"])
+	assert.Equal(t, float64(12), top["line"])
+	assert.Equal(t, "transfer", frames[1].(map[string]interface{})["name"])
+
+	vars := find(msgs, "response", "variables")[0].Body.(map[string]interface{})["variables"].([]interface{})
+	require.Len(t, vars, 1)
+	assert.Equal(t, "Balance", vars[0].(map[string]interface{})["name"])
+
+	exited := find(msgs, "event", "exited")
+	require.Len(t, exited, 1)
+	assert.Equal(t, float64(1), exited[0].Body.(map[string]interface{})["exitCode"])
+	assert.Len(t, find(msgs, "event", "terminated"), 1)
+
+	for _, m := range find(msgs, "response", "disconnect") {
+		assert.True(t, *m.Success)
+	}
+}
+
+func TestServer_UnsupportedAndNoSession(t *testing.T) {
+	srv := NewServer(func(ctx context.Context, args LaunchArgs) (Session, error) {
+		return newFakeSession(), nil
+	})
+
+	var in bytes.Buffer
+	request(t, &in, 1, "next", nil)
+	request(t, &in, 2, "evaluate", nil)
+
+	var out bytes.Buffer
+	require.NoError(t, srv.Serve(context.Background(), &in, &out))
+
+	msgs := readAll(t, &out)
+	require.Len(t, msgs, 2)
+	for _, m := range msgs {
+		assert.False(t, *m.Success)
+		assert.NotEmpty(t, m.ErrMessage)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"time"
 
 	_ "modernc.org/sqlite"
+
+	"github.com/dotandev/hintents/internal/platform"
 )
 
 // Session represents a debugging session result
@@ -34,11 +36,10 @@ type Store struct {
 
 // InitDB initializes the SQLite database
 func InitDB() (*Store, error) {
-	home, err := os.UserHomeDir()
+	dir, err := platform.DataDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home dir: %w", err)
+		return nil, err
 	}
-	dir := filepath.Join(home, ".erst")
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create data dir: %w", err)
 	}
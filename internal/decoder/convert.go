@@ -0,0 +1,387 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// XDR encodings ConvertXDR reads and writes
+const (
+	EncodingAuto   = "auto"
+	EncodingBase64 = "base64"
+	EncodingHex    = "hex"
+	EncodingBinary = "binary"
+	EncodingJSON   = "json"
+)
+
+// Encodings lists the encodings ConvertXDR writes, in the order auto
+// detection tries them when reading
+func Encodings() []string {
+	return []string{EncodingJSON, EncodingHex, EncodingBase64, EncodingBinary}
+}
+
+// ConvertXDR re-encodes a value of the named XDR type from one encoding to
+// another and returns the result with the input encoding, which is detected
+// when from is EncodingAuto. JSON is the stellar-xdr JSON used by Stellar
+// Lab and stellar-cli.
+func ConvertXDR(typeName string, in []byte, from, to string) ([]byte, string, error) {
+	v, detected, err := DecodeXDR(typeName, in, from)
+	if err != nil {
+		return nil, "", err
+	}
+	out, err := EncodeXDR(v, to)
+	if err != nil {
+		return nil, "", err
+	}
+	return out, detected, nil
+}
+
+// DecodeXDR decodes in as a value of the named type and returns it with the
+// encoding it was read from. With EncodingAuto each encoding is tried in the
+// order Encodings lists them and the first that decodes a whole value wins.
+func DecodeXDR(typeName string, in []byte, from string) (xdr.DecoderFrom, string, error) {
+	newValue, ok := xdrTypes[typeName]
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported XDR type %q (use: %s)", typeName, strings.Join(XDRTypes(), ", "))
+	}
+	if from != EncodingAuto {
+		v := newValue()
+		if err := decodeAs(typeName, v, in, from); err != nil {
+			return nil, "", fmt.Errorf("failed to decode %s as %s: %w", typeName, from, err)
+		}
+		return v, from, nil
+	}
+	for _, enc := range Encodings() {
+		v := newValue()
+		if decodeAs(typeName, v, in, enc) == nil {
+			return v, enc, nil
+		}
+	}
+	return nil, "", fmt.Errorf("input is not a %s in any of %s", typeName, strings.Join(Encodings(), ", "))
+}
+
+func decodeAs(typeName string, v xdr.DecoderFrom, in []byte, enc string) error {
+	text := strings.TrimSpace(string(in))
+	switch enc {
+	case EncodingBase64:
+		return UnmarshalBase64(text, v)
+	case EncodingHex:
+		raw, err := hex.DecodeString(strings.Join(strings.Fields(text), ""))
+		if err != nil {
+			return fmt.Errorf("invalid hex: %w", err)
+		}
+		return unmarshalBinary(raw, v)
+	case EncodingBinary:
+		return unmarshalBinary(in, v)
+	case EncodingJSON:
+		if !json.Valid([]byte(text)) {
+			return fmt.Errorf("invalid JSON")
+		}
+		if typeName == "transaction-envelope" && IsJSON(text) {
+			b64, err := EnvelopeFromJSON([]byte(text))
+			if err != nil {
+				return err
+			}
+			return UnmarshalBase64(b64, v)
+		}
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.UseNumber()
+		var doc interface{}
+		if err := dec.Decode(&doc); err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		return fromXDRJSON("", reflect.ValueOf(v).Elem(), doc)
+	}
+	return fmt.Errorf("unsupported encoding %q (use: %s)", enc, strings.Join(Encodings(), ", "))
+}
+
+func unmarshalBinary(raw []byte, v xdr.DecoderFrom) error {
+	n, err := xdr.NewBytesDecoder().DecodeBytes(v, raw)
+	if err != nil {
+		return err
+	}
+	if n != len(raw) {
+		return fmt.Errorf("input not fully consumed. expected to read: %d, actual: %d", len(raw), n)
+	}
+	return nil
+}
+
+// EncodeXDR encodes a decoded XDR value. Base64, hex and JSON output end
+// with a newline; binary output is the raw XDR.
+func EncodeXDR(v interface{}, to string) ([]byte, error) {
+	if to == EncodingJSON {
+		var buf bytes.Buffer
+		if err := toXDRJSON(&buf, reflect.ValueOf(v)); err != nil {
+			return nil, err
+		}
+		var out bytes.Buffer
+		if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+			return nil, fmt.Errorf("failed to format JSON: %w", err)
+		}
+		out.WriteByte('\n')
+		return out.Bytes(), nil
+	}
+
+	var raw bytes.Buffer
+	if _, err := xdr.Marshal(&raw, v); err != nil {
+		return nil, fmt.Errorf("failed to encode XDR: %w", err)
+	}
+	switch to {
+	case EncodingBinary:
+		return raw.Bytes(), nil
+	case EncodingBase64:
+		return []byte(base64.StdEncoding.EncodeToString(raw.Bytes()) + "\n"), nil
+	case EncodingHex:
+		return []byte(hex.EncodeToString(raw.Bytes()) + "\n"), nil
+	}
+	return nil, fmt.Errorf("unsupported encoding %q (use: %s)", to, strings.Join(Encodings(), ", "))
+}
+
+// toXDRJSON writes v in the stellar-xdr JSON form fromXDRJSON reads
+func toXDRJSON(buf *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+	switch t {
+	case muxedAccountType, accountIDType, publicKeyType, scAddressType:
+		s, err := strkeyOf(v)
+		if err != nil {
+			return err
+		}
+		return writeJSONValue(buf, s)
+	case assetCode4Type, assetCode12Type:
+		code := make([]byte, t.Len())
+		reflect.Copy(reflect.ValueOf(code), v)
+		return writeJSONValue(buf, string(bytes.TrimRight(code, "\x00")))
+	case int128Type, uint128Type:
+		return writeJSONValue(buf, int128String(v))
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return toXDRJSON(buf, v.Elem())
+	case reflect.Struct:
+		if _, ok := t.MethodByName("SwitchFieldName"); ok {
+			return unionToXDRJSON(buf, v)
+		}
+		buf.WriteByte('{')
+		first := true
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			_ = writeJSONValue(buf, SnakeCase(f.Name))
+			buf.WriteByte(':')
+			if err := toXDRJSON(buf, v.Field(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return writeJSONValue(buf, hex.EncodeToString(v.Bytes()))
+		}
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := toXDRJSON(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case reflect.Array:
+		b := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(b), v)
+		return writeJSONValue(buf, hex.EncodeToString(b))
+	case reflect.String:
+		return writeJSONValue(buf, v.String())
+	case reflect.Bool:
+		return writeJSONValue(buf, v.Bool())
+	case reflect.Int32:
+		if _, ok := t.MethodByName("ValidEnum"); ok {
+			return writeJSONValue(buf, enumName(v))
+		}
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+		return nil
+	case reflect.Uint32:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+		return nil
+	case reflect.Int64:
+		// 64-bit integers are strings so JavaScript reads them exactly
+		return writeJSONValue(buf, strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint64:
+		return writeJSONValue(buf, strconv.FormatUint(v.Uint(), 10))
+	}
+	return fmt.Errorf("unsupported type %s", t)
+}
+
+// unionToXDRJSON writes a union as "variant" when its arm is void and as
+// {"variant": value} otherwise
+func unionToXDRJSON(buf *bytes.Buffer, v reflect.Value) error {
+	switchName := v.MethodByName("SwitchFieldName").Call(nil)[0].String()
+	sw := v.FieldByName(switchName)
+	var variant string
+	var disc int32
+	if _, ok := sw.Type().MethodByName("ValidEnum"); ok {
+		variant, disc = enumName(sw), int32(sw.Int())
+	} else {
+		if sw.CanInt() {
+			disc = int32(sw.Int())
+		} else {
+			disc = int32(sw.Uint())
+		}
+		variant = fmt.Sprintf("v%d", disc)
+	}
+
+	out := v.MethodByName("ArmForSwitch").Call([]reflect.Value{reflect.ValueOf(disc)})
+	arm, ok := out[0].String(), out[1].Bool()
+	if !ok {
+		return fmt.Errorf("%s has no variant %d", v.Type().Name(), disc)
+	}
+	if arm == "" {
+		return writeJSONValue(buf, variant)
+	}
+	buf.WriteByte('{')
+	_ = writeJSONValue(buf, variant)
+	buf.WriteByte(':')
+	if err := toXDRJSON(buf, v.FieldByName(arm)); err != nil {
+		return err
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+var (
+	enumPrefixes   = map[reflect.Type]string{}
+	enumPrefixesMu sync.Mutex
+)
+
+// enumName returns the stellar-xdr JSON name of an enum value: its Go name
+// without the prefix shared by every value of the type, in snake_case, such
+// as tx_v0 for EnvelopeTypeEnvelopeTypeTxV0
+func enumName(v reflect.Value) string {
+	goName := v.Interface().(fmt.Stringer).String()
+	if goName == "" {
+		return strconv.FormatInt(v.Int(), 10)
+	}
+	prefix := enumPrefix(v.Type())
+	return SnakeCase(strings.TrimPrefix(goName, prefix))
+}
+
+// enumPrefix returns the longest run of whole CamelCase words that starts
+// the Go name of every value of enum type t, leaving at least one word
+func enumPrefix(t reflect.Type) string {
+	enumPrefixesMu.Lock()
+	defer enumPrefixesMu.Unlock()
+	if p, ok := enumPrefixes[t]; ok {
+		return p
+	}
+
+	var common []string
+	found := false
+	e := reflect.New(t).Elem()
+	for n := int32(-128); n <= 1024; n++ {
+		e.SetInt(int64(n))
+		name := e.Interface().(fmt.Stringer).String()
+		if name == "" {
+			continue
+		}
+		words := camelWords(name)
+		words = words[:len(words)-1]
+		if !found {
+			common, found = words, true
+			continue
+		}
+		i := 0
+		for i < len(common) && i < len(words) && common[i] == words[i] {
+			i++
+		}
+		common = common[:i]
+	}
+	p := strings.Join(common, "")
+	enumPrefixes[t] = p
+	return p
+}
+
+// camelWords splits a Go name into words at each upper-case letter; digits
+// stay with the word before them
+func camelWords(s string) []string {
+	var words []string
+	start := 0
+	for i, r := range s {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, s[start:i])
+			start = i
+		}
+	}
+	return append(words, s[start:])
+}
+
+// SnakeCase converts a Go name such as InvokeHostFunctionTrapped into
+// invoke_host_function_trapped
+func SnakeCase(s string) string {
+	return strings.ToLower(strings.Join(camelWords(s), "_"))
+}
+
+// strkeyOf returns the strkey of an account, public key or address value
+func strkeyOf(v reflect.Value) (string, error) {
+	switch x := v.Interface().(type) {
+	case xdr.MuxedAccount:
+		return x.GetAddress()
+	case xdr.AccountId:
+		return x.GetAddress()
+	case xdr.PublicKey:
+		id := xdr.AccountId(x)
+		return id.GetAddress()
+	case xdr.ScAddress:
+		return x.String()
+	}
+	return "", fmt.Errorf("unsupported address type %s", v.Type())
+}
+
+// int128String returns the decimal value of 128-bit integer parts
+func int128String(v reflect.Value) string {
+	lo := new(big.Int).SetUint64(v.FieldByName("Lo").Uint())
+	hiField := v.FieldByName("Hi")
+	var hi *big.Int
+	if hiField.Kind() == reflect.Int64 {
+		hi = big.NewInt(hiField.Int())
+	} else {
+		hi = new(big.Int).SetUint64(hiField.Uint())
+	}
+	return hi.Lsh(hi, 64).Add(hi, lo).String()
+}
+
+func writeJSONValue(buf *bytes.Buffer, x interface{}) error {
+	b, err := json.Marshal(x)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertXDRRoundTrip(t *testing.T) {
+	b64 := invokeEnvelope(t, 100, nil)
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	require.NoError(t, err)
+
+	js, detected, err := ConvertXDR("transaction-envelope", []byte(b64), EncodingAuto, EncodingJSON)
+	require.NoError(t, err)
+	assert.Equal(t, EncodingBase64, detected)
+
+	for _, enc := range Encodings() {
+		out, _, err := ConvertXDR("transaction-envelope", js, EncodingJSON, enc)
+		require.NoError(t, err, enc)
+		back, detected, err := ConvertXDR("transaction-envelope", out, EncodingAuto, EncodingBase64)
+		require.NoError(t, err, enc)
+		assert.Equal(t, enc, detected)
+		assert.Equal(t, b64+"\n", string(back), enc)
+	}
+
+	out, _, err := ConvertXDR("transaction-envelope", []byte(b64), EncodingBase64, EncodingBinary)
+	require.NoError(t, err)
+	assert.Equal(t, raw, out)
+	out, _, err = ConvertXDR("transaction-envelope", []byte(b64), EncodingBase64, EncodingHex)
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(raw)+"\n", string(out))
+}
+
+func TestEncodeXDRJSON(t *testing.T) {
+	var env xdr.TransactionEnvelope
+	require.NoError(t, UnmarshalBase64(invokeEnvelope(t, 100, nil), &env))
+	out, err := EncodeXDR(&env, EncodingJSON)
+	require.NoError(t, err)
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &doc))
+	tx := doc["tx"].(map[string]interface{})["tx"].(map[string]interface{})
+	assert.Equal(t, diffSource, tx["source_account"])
+	assert.Equal(t, "7", tx["seq_num"], "64-bit integers are strings")
+	assert.Equal(t, float64(100), tx["fee"])
+	assert.Equal(t, "none", tx["memo"])
+	body := tx["operations"].([]interface{})[0].(map[string]interface{})["body"].(map[string]interface{})
+	invoke := body["invoke_host_function"].(map[string]interface{})["host_function"].(map[string]interface{})["invoke_contract"].(map[string]interface{})
+	assert.True(t, strings.HasPrefix(invoke["contract_address"].(string), "C"))
+	assert.Equal(t, "transfer", invoke["function_name"])
+}
+
+func TestConvertScValJSON(t *testing.T) {
+	val := xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: -1, Lo: xdr.Uint64(1<<64 - 5)}}
+	b64, err := xdr.MarshalBase64(val)
+	require.NoError(t, err)
+
+	out, _, err := ConvertXDR("sc-val", []byte(b64), EncodingBase64, EncodingJSON)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"i128": "-5"}`, string(out))
+
+	back, _, err := ConvertXDR("sc-val", []byte(`"void"`), EncodingAuto, EncodingBase64)
+	require.NoError(t, err)
+	assert.Equal(t, "AAAAAQ==\n", string(back))
+}
+
+func TestEnumName(t *testing.T) {
+	for v, want := range map[interface{}]string{
+		xdr.EnvelopeTypeEnvelopeTypeTxV0:                    "tx_v0",
+		xdr.ScValTypeScvLedgerKeyContractInstance:           "ledger_key_contract_instance",
+		xdr.ContractDataDurabilityPersistent:                "persistent",
+		xdr.OperationTypeInvokeHostFunction:                 "invoke_host_function",
+		xdr.ScAddressTypeScAddressTypeMuxedAccount:          "muxed_account",
+		xdr.SorobanCredentialsTypeSorobanCredentialsAddress: "address",
+	} {
+		js, err := EncodeXDR(v, EncodingJSON)
+		require.NoError(t, err)
+		assert.Equal(t, `"`+want+`"`+"\n", string(js))
+	}
+}
+
+func TestConvertXDRErrors(t *testing.T) {
+	_, _, err := ConvertXDR("no-such-type", []byte("AAAA"), EncodingAuto, EncodingJSON)
+	assert.ErrorContains(t, err, "unsupported XDR type")
+
+	_, _, err = ConvertXDR("sc-val", []byte("not xdr"), EncodingAuto, EncodingJSON)
+	assert.ErrorContains(t, err, "input is not a sc-val")
+
+	_, _, err = ConvertXDR("sc-val", []byte(`{"u32": "x"}`), EncodingJSON, EncodingBase64)
+	assert.ErrorContains(t, err, "u32: expected an unsigned integer")
+
+	_, _, err = ConvertXDR("sc-val", []byte("AAAAAQ=="), EncodingBase64, "yaml")
+	assert.ErrorContains(t, err, `unsupported encoding "yaml"`)
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Field change kinds
+const (
+	DiffChanged = "changed"
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+)
+
+// FieldDiff is one difference between two XDR values. Path names the field
+// with the SDK's field names in lowerCamelCase, for example
+// v1.tx.operations[0].body.invokeHostFunctionOp.auth[1].credentials.
+type FieldDiff struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+// xdrTypes creates an empty value of each named XDR type that DiffXDRBase64
+// and ConvertXDR accept
+var xdrTypes = map[string]func() xdr.DecoderFrom{
+	"transaction-envelope":     func() xdr.DecoderFrom { return &xdr.TransactionEnvelope{} },
+	"transaction-result":       func() xdr.DecoderFrom { return &xdr.TransactionResult{} },
+	"transaction-meta":         func() xdr.DecoderFrom { return &xdr.TransactionMeta{} },
+	"ledger-entry":             func() xdr.DecoderFrom { return &xdr.LedgerEntry{} },
+	"ledger-key":               func() xdr.DecoderFrom { return &xdr.LedgerKey{} },
+	"diagnostic-event":         func() xdr.DecoderFrom { return &xdr.DiagnosticEvent{} },
+	"soroban-transaction-data": func() xdr.DecoderFrom { return &xdr.SorobanTransactionData{} },
+	"soroban-auth-entry":       func() xdr.DecoderFrom { return &xdr.SorobanAuthorizationEntry{} },
+	"sc-val":                   func() xdr.DecoderFrom { return &xdr.ScVal{} },
+}
+
+// XDRTypes lists the type names DiffXDRBase64 and ConvertXDR accept
+func XDRTypes() []string {
+	names := make([]string, 0, len(xdrTypes))
+	for name := range xdrTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DiffXDRBase64 decodes two base64 XDR values of the named type and returns
+// their field-level differences in field order
+func DiffXDRBase64(typeName, a, b string) ([]FieldDiff, error) {
+	newValue, ok := xdrTypes[typeName]
+	if !ok {
+		return nil, fmt.Errorf("unsupported XDR type %q (use: %s)", typeName, strings.Join(XDRTypes(), ", "))
+	}
+	va, vb := newValue(), newValue()
+	if err := UnmarshalBase64(a, va); err != nil {
+		return nil, fmt.Errorf("failed to decode first %s: %w", typeName, err)
+	}
+	if err := UnmarshalBase64(b, vb); err != nil {
+		return nil, fmt.Errorf("failed to decode second %s: %w", typeName, err)
+	}
+	return DiffValues(va, vb), nil
+}
+
+// DiffValues compares two decoded XDR values of the same type
+func DiffValues(a, b interface{}) []FieldDiff {
+	var diffs []FieldDiff
+	walkDiff("", reflect.ValueOf(a), reflect.ValueOf(b), &diffs)
+	return diffs
+}
+
+func walkDiff(path string, a, b reflect.Value, diffs *[]FieldDiff) {
+	if sa, ok := leafString(a); ok {
+		if sb, _ := leafString(b); sa != sb {
+			*diffs = append(*diffs, FieldDiff{Path: path, Kind: DiffChanged, Old: sa, New: sb})
+		}
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		switch {
+		case a.IsNil() && b.IsNil():
+		case a.IsNil():
+			*diffs = append(*diffs, FieldDiff{Path: path, Kind: DiffAdded, New: summarize(b.Elem())})
+		case b.IsNil():
+			*diffs = append(*diffs, FieldDiff{Path: path, Kind: DiffRemoved, Old: summarize(a.Elem())})
+		default:
+			walkDiff(path, a.Elem(), b.Elem(), diffs)
+		}
+
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			walkDiff(joinPath(path, lowerFirst(f.Name)), a.Field(i), b.Field(i), diffs)
+		}
+
+	case reflect.Slice, reflect.Array:
+		n := a.Len()
+		if b.Len() < n {
+			n = b.Len()
+		}
+		for i := 0; i < n; i++ {
+			walkDiff(fmt.Sprintf("%s[%d]", path, i), a.Index(i), b.Index(i), diffs)
+		}
+		for i := n; i < a.Len(); i++ {
+			*diffs = append(*diffs, FieldDiff{Path: fmt.Sprintf("%s[%d]", path, i), Kind: DiffRemoved, Old: summarize(a.Index(i))})
+		}
+		for i := n; i < b.Len(); i++ {
+			*diffs = append(*diffs, FieldDiff{Path: fmt.Sprintf("%s[%d]", path, i), Kind: DiffAdded, New: summarize(b.Index(i))})
+		}
+
+	case reflect.Map:
+		// XDR has no maps; compare anything else by value
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*diffs = append(*diffs, FieldDiff{Path: path, Kind: DiffChanged, Old: fmt.Sprint(a.Interface()), New: fmt.Sprint(b.Interface())})
+		}
+	}
+}
+
+type addressGetter interface {
+	GetAddress() (string, error)
+}
+
+type addressStringer interface {
+	String() (string, error)
+}
+
+// leafString renders values compared as a whole: scalars, enums, opaque
+// bytes and anything with a strkey address
+func leafString(v reflect.Value) (string, bool) {
+	if !v.IsValid() {
+		return "", false
+	}
+	if v.Kind() == reflect.Struct {
+		var iface interface{}
+		if v.CanAddr() {
+			iface = v.Addr().Interface()
+		} else {
+			iface = v.Interface()
+		}
+		if g, ok := iface.(addressGetter); ok {
+			if s, err := g.GetAddress(); err == nil {
+				return s, true
+			}
+		}
+		if g, ok := iface.(addressStringer); ok {
+			if s, err := g.String(); err == nil {
+				return s, true
+			}
+		}
+		return "", false
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return "", false
+		}
+		buf := make([]byte, v.Len())
+		for i := range buf {
+			buf[i] = byte(v.Index(i).Uint())
+		}
+		return hex.EncodeToString(buf), true
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// Enums render their names
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String(), true
+		}
+		return fmt.Sprint(v.Interface()), true
+	}
+	return "", false
+}
+
+// summarize describes an added or removed value: leaves by value,
+// composites by type name
+func summarize(v reflect.Value) string {
+	if s, ok := leafString(v); ok {
+		return s
+	}
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v.Type().Name()
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffSource = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+// invokeEnvelope returns an envelope invoking transfer with the given auth
+// entries
+func invokeEnvelope(t *testing.T, fee uint32, auth []xdr.SorobanAuthorizationEntry) string {
+	t.Helper()
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: xdr.MustMuxedAddress(diffSource),
+				Fee:           xdr.Uint32(fee),
+				SeqNum:        7,
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypeInvokeHostFunction,
+						InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+							HostFunction: xdr.HostFunction{
+								Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+								InvokeContract: &xdr.InvokeContractArgs{
+									ContractAddress: diffContract(),
+									FunctionName:    "transfer",
+								},
+							},
+							Auth: auth,
+						},
+					},
+				}},
+			},
+		},
+	}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return b64
+}
+
+func diffContract() xdr.ScAddress {
+	contract := xdr.ContractId{1, 2, 3}
+	return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract}
+}
+
+func sourceAuth() xdr.SorobanAuthorizationEntry {
+	return xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{
+			Function: xdr.SorobanAuthorizedFunction{
+				Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+				ContractFn: &xdr.InvokeContractArgs{ContractAddress: diffContract(), FunctionName: "transfer"},
+			},
+		},
+	}
+}
+
+func addressAuth() xdr.SorobanAuthorizationEntry {
+	entry := sourceAuth()
+	entry.Credentials = xdr.SorobanCredentials{
+		Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+		Address: &xdr.SorobanAddressCredentials{
+			Address:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: xdr.MustAddressPtr(diffSource)},
+			Nonce:     42,
+			Signature: xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+		},
+	}
+	return entry
+}
+
+func TestDiffXDRBase64(t *testing.T) {
+	a := invokeEnvelope(t, 100, []xdr.SorobanAuthorizationEntry{sourceAuth(), sourceAuth()})
+	b := invokeEnvelope(t, 200, []xdr.SorobanAuthorizationEntry{sourceAuth(), addressAuth(), sourceAuth()})
+
+	diffs, err := DiffXDRBase64("transaction-envelope", a, b)
+	require.NoError(t, err)
+
+	byPath := make(map[string]FieldDiff)
+	for _, d := range diffs {
+		byPath[d.Path] = d
+	}
+	assert.Equal(t, FieldDiff{Path: "v1.tx.fee", Kind: DiffChanged, Old: "100", New: "200"}, byPath["v1.tx.fee"])
+
+	creds := "v1.tx.operations[0].body.invokeHostFunctionOp.auth[1].credentials"
+	assert.Equal(t, DiffChanged, byPath[creds+".type"].Kind)
+	assert.Equal(t, "SorobanCredentialsTypeSorobanCredentialsAddress", byPath[creds+".type"].New)
+	assert.Equal(t, FieldDiff{Path: creds + ".address", Kind: DiffAdded, New: "SorobanAddressCredentials"}, byPath[creds+".address"])
+
+	extra := "v1.tx.operations[0].body.invokeHostFunctionOp.auth[2]"
+	assert.Equal(t, DiffAdded, byPath[extra].Kind)
+	assert.Len(t, diffs, 4)
+
+	same, err := DiffXDRBase64("transaction-envelope", a, a)
+	require.NoError(t, err)
+	assert.Empty(t, same)
+}
+
+func TestDiffRendersAddresses(t *testing.T) {
+	a := addressAuth()
+	b := addressAuth()
+	other := "GAAZI4TCR3TY5OJHCTJC2A4QSY6CJWJH5IAJTGKIN2ER7LBNVKOCCWN7"
+	b.Credentials.Address.Address.AccountId = xdr.MustAddressPtr(other)
+
+	diffs := DiffValues(&a, &b)
+	require.Len(t, diffs, 1)
+	assert.Equal(t, FieldDiff{Path: "credentials.address.address", Kind: DiffChanged, Old: diffSource, New: other}, diffs[0])
+}
+
+func TestDiffXDRBase64Errors(t *testing.T) {
+	_, err := DiffXDRBase64("bogus", "", "")
+	assert.ErrorContains(t, err, "transaction-envelope")
+
+	_, err = DiffXDRBase64("sc-val", "AAAAAQ==", "not base64")
+	assert.ErrorContains(t, err, "second sc-val")
+}
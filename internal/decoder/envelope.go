@@ -14,13 +14,15 @@ type DecodedEnvelope struct {
 	Source     string
 	Fee        int64
 	Operations []xdr.Operation
-	InnerTx    *DecodedEnvelope // for FeeBump
+	// Preconditions are nil when the transaction has none
+	Preconditions *Preconditions
+	InnerTx       *DecodedEnvelope // for FeeBump
 }
 
 func AnalyzeEnvelope(b64 string) (*DecodedEnvelope, error) {
 	var env xdr.TransactionEnvelope
 
-	if err := xdr.SafeUnmarshalBase64(b64, &env); err != nil {
+	if err := UnmarshalBase64(b64, &env); err != nil {
 		return nil, err
 	}
 
@@ -49,14 +51,19 @@ func decodeV0(tx xdr.TransactionV0) (*DecodedEnvelope, error) {
 		Source:     source.Address(),
 		Fee:        int64(tx.Fee),
 		Operations: tx.Operations,
+
+		Preconditions: DecodePreconditions(xdr.NewPreconditionsWithTimeBounds(tx.TimeBounds)),
 	}, nil
 }
+
 func decodeV1(tx xdr.Transaction) (*DecodedEnvelope, error) {
 	return &DecodedEnvelope{
 		Type:       "TransactionV1",
 		Source:     tx.SourceAccount.Address(),
 		Fee:        int64(tx.Fee),
 		Operations: tx.Operations,
+
+		Preconditions: DecodePreconditions(tx.Cond),
 	}, nil
 }
 
@@ -0,0 +1,65 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+)
+
+// MuxedAccount is a SEP-23 muxed (M...) address split into the G... account
+// that holds the balance and the ID that tells its users apart, such as an
+// exchange's deposit ID
+type MuxedAccount struct {
+	Address string
+	Account string
+	ID      uint64
+}
+
+// ParseMuxedAddress splits an M... address. It reports false for any other
+// address.
+func ParseMuxedAddress(addr string) (MuxedAccount, bool) {
+	if !strings.HasPrefix(addr, "M") {
+		return MuxedAccount{}, false
+	}
+	m, err := strkey.DecodeMuxedAccount(addr)
+	if err != nil {
+		return MuxedAccount{}, false
+	}
+	account, err := m.AccountID()
+	if err != nil {
+		return MuxedAccount{}, false
+	}
+	return MuxedAccount{Address: addr, Account: account, ID: m.ID()}, true
+}
+
+// MuxAddress builds the M... address of account with the given ID
+func MuxAddress(account string, id uint64) (string, error) {
+	var m strkey.MuxedAccount
+	if err := m.SetAccountID(account); err != nil {
+		return "", fmt.Errorf("invalid account %s: %w", account, err)
+	}
+	m.SetID(id)
+	return m.Address()
+}
+
+// BaseAccount returns the G... account behind a muxed address, and any
+// other address unchanged
+func BaseAccount(addr string) string {
+	if m, ok := ParseMuxedAddress(addr); ok {
+		return m.Account
+	}
+	return addr
+}
+
+// FormatAccount renders an address for display; muxed addresses also show
+// the underlying account and ID
+func FormatAccount(addr string) string {
+	if m, ok := ParseMuxedAddress(addr); ok {
+		return fmt.Sprintf("%s (%s id %d)", m.Address, m.Account, m.ID)
+	}
+	return addr
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	muxedBase = "GA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVSGZ"
+	muxedAddr = "MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK"
+)
+
+func TestParseMuxedAddress(t *testing.T) {
+	m, ok := ParseMuxedAddress(muxedAddr)
+	require.True(t, ok)
+	assert.Equal(t, muxedBase, m.Account)
+	assert.Equal(t, uint64(9223372036854775808), m.ID)
+
+	addr, err := MuxAddress(muxedBase, 1234)
+	require.NoError(t, err)
+	m, ok = ParseMuxedAddress(addr)
+	require.True(t, ok)
+	assert.Equal(t, uint64(1234), m.ID)
+	assert.Equal(t, muxedBase, BaseAccount(addr))
+
+	_, ok = ParseMuxedAddress(muxedBase)
+	assert.False(t, ok)
+	_, ok = ParseMuxedAddress("MNOTANADDRESS")
+	assert.False(t, ok)
+	_, err = MuxAddress("CBAD", 1)
+	assert.Error(t, err)
+}
+
+func TestFormatAccount(t *testing.T) {
+	addr, err := MuxAddress(muxedBase, 42)
+	require.NoError(t, err)
+	assert.Equal(t, addr+" ("+muxedBase+" id 42)", FormatAccount(addr))
+	assert.Equal(t, muxedBase, FormatAccount(muxedBase))
+	assert.Contains(t, maskAccount(addr), "(GA7Q…VSGZ id 42)")
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Preconditions are the decoded validity conditions of a transaction. Zero
+// values mean the condition is not set.
+type Preconditions struct {
+	// MinTime and MaxTime bound the close time of the ledger that applies
+	// the transaction; MaxTime is inclusive
+	MinTime *time.Time `json:"min_time,omitempty"`
+	MaxTime *time.Time `json:"max_time,omitempty"`
+	// MinLedger and MaxLedger bound its sequence; MaxLedger is exclusive
+	MinLedger uint32 `json:"min_ledger,omitempty"`
+	MaxLedger uint32 `json:"max_ledger,omitempty"`
+	// MinSeqNum relaxes the sequence check: the source account's sequence
+	// may be anywhere from MinSeqNum up to the transaction's
+	MinSeqNum *int64 `json:"min_seq_num,omitempty"`
+	// MinSeqAge and MinSeqLedgerGap are how long ago, in time and ledgers,
+	// the source account's sequence number must have last changed
+	MinSeqAge       time.Duration `json:"min_seq_age,omitempty"`
+	MinSeqLedgerGap uint32        `json:"min_seq_ledger_gap,omitempty"`
+	// ExtraSigners are strkeys of signers whose signature is required in
+	// addition to the source account's
+	ExtraSigners []string `json:"extra_signers,omitempty"`
+}
+
+// DecodePreconditions decodes the preconditions of a transaction. It
+// returns nil when there are none.
+func DecodePreconditions(cond xdr.Preconditions) *Preconditions {
+	var p *Preconditions
+	switch cond.Type {
+	case xdr.PreconditionTypePrecondTime:
+		p = decodeTimeBounds(cond.TimeBounds, &Preconditions{})
+	case xdr.PreconditionTypePrecondV2:
+		v2 := cond.V2
+		p = decodeTimeBounds(v2.TimeBounds, &Preconditions{
+			MinSeqAge:       time.Duration(v2.MinSeqAge) * time.Second,
+			MinSeqLedgerGap: uint32(v2.MinSeqLedgerGap),
+		})
+		if v2.LedgerBounds != nil {
+			p.MinLedger = uint32(v2.LedgerBounds.MinLedger)
+			p.MaxLedger = uint32(v2.LedgerBounds.MaxLedger)
+		}
+		if v2.MinSeqNum != nil {
+			n := int64(*v2.MinSeqNum)
+			p.MinSeqNum = &n
+		}
+		for _, signer := range v2.ExtraSigners {
+			p.ExtraSigners = append(p.ExtraSigners, signer.Address())
+		}
+	default:
+		return nil
+	}
+	// Time bounds of 0 to 0 are no bounds at all
+	if p.IsZero() {
+		return nil
+	}
+	return p
+}
+
+func decodeTimeBounds(tb *xdr.TimeBounds, p *Preconditions) *Preconditions {
+	if tb == nil {
+		return p
+	}
+	if tb.MinTime != 0 {
+		t := time.Unix(int64(tb.MinTime), 0).UTC()
+		p.MinTime = &t
+	}
+	if tb.MaxTime != 0 {
+		t := time.Unix(int64(tb.MaxTime), 0).UTC()
+		p.MaxTime = &t
+	}
+	return p
+}
+
+// IsZero reports whether no condition is set
+func (p *Preconditions) IsZero() bool {
+	return p.MinTime == nil && p.MaxTime == nil && p.MinLedger == 0 && p.MaxLedger == 0 &&
+		p.MinSeqNum == nil && p.MinSeqAge == 0 && p.MinSeqLedgerGap == 0 && len(p.ExtraSigners) == 0
+}
+
+// Lines describes each condition that is set, one per line
+func (p *Preconditions) Lines() []string {
+	var lines []string
+	switch {
+	case p.MinTime != nil && p.MaxTime != nil:
+		lines = append(lines, fmt.Sprintf("Valid from %s until %s", p.MinTime.Format(time.RFC3339), p.MaxTime.Format(time.RFC3339)))
+	case p.MinTime != nil:
+		lines = append(lines, fmt.Sprintf("Valid from %s, no expiry", p.MinTime.Format(time.RFC3339)))
+	case p.MaxTime != nil:
+		lines = append(lines, fmt.Sprintf("Valid until %s", p.MaxTime.Format(time.RFC3339)))
+	}
+	switch {
+	case p.MinLedger != 0 && p.MaxLedger != 0:
+		lines = append(lines, fmt.Sprintf("Ledgers %d to %d (exclusive)", p.MinLedger, p.MaxLedger))
+	case p.MinLedger != 0:
+		lines = append(lines, fmt.Sprintf("Ledger %d or later", p.MinLedger))
+	case p.MaxLedger != 0:
+		lines = append(lines, fmt.Sprintf("Before ledger %d", p.MaxLedger))
+	}
+	if p.MinSeqNum != nil {
+		lines = append(lines, fmt.Sprintf("Source sequence number at least %d", *p.MinSeqNum))
+	}
+	if p.MinSeqAge != 0 {
+		lines = append(lines, fmt.Sprintf("Source sequence number unchanged for at least %s", p.MinSeqAge))
+	}
+	if p.MinSeqLedgerGap != 0 {
+		lines = append(lines, fmt.Sprintf("Source sequence number unchanged for at least %d ledgers", p.MinSeqLedgerGap))
+	}
+	for _, signer := range p.ExtraSigners {
+		lines = append(lines, "Extra signer "+signer)
+	}
+	return lines
+}
+
+// SignerHint returns the signature hint a signature by the signer carries
+func SignerHint(signer xdr.SignerKey) xdr.SignatureHint {
+	var key []byte
+	switch signer.Type {
+	case xdr.SignerKeyTypeSignerKeyTypeEd25519:
+		key = signer.Ed25519[:]
+	case xdr.SignerKeyTypeSignerKeyTypePreAuthTx:
+		key = signer.PreAuthTx[:]
+	case xdr.SignerKeyTypeSignerKeyTypeHashX:
+		key = signer.HashX[:]
+	case xdr.SignerKeyTypeSignerKeyTypeEd25519SignedPayload:
+		// The hint of a signed payload is the key's hint XORed with the
+		// payload's last four bytes, zero-padded when shorter
+		sp := signer.Ed25519SignedPayload
+		var hint, payloadHint xdr.SignatureHint
+		copy(hint[:], sp.Ed25519[len(sp.Ed25519)-4:])
+		if n := len(sp.Payload); n >= 4 {
+			copy(payloadHint[:], sp.Payload[n-4:])
+		} else {
+			copy(payloadHint[:], sp.Payload)
+		}
+		for i := range hint {
+			hint[i] ^= payloadHint[i]
+		}
+		return hint
+	}
+	var hint xdr.SignatureHint
+	if len(key) >= 4 {
+		copy(hint[:], key[len(key)-4:])
+	}
+	return hint
+}
+
+// EnvelopePreconditions decodes the preconditions of an envelope's
+// transaction, or of the inner transaction of a fee bump
+func EnvelopePreconditions(env xdr.TransactionEnvelope) *Preconditions {
+	switch env.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTxV0:
+		return DecodePreconditions(xdr.NewPreconditionsWithTimeBounds(env.V0.Tx.TimeBounds))
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		return DecodePreconditions(env.V1.Tx.Cond)
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		if inner := env.FeeBump.Tx.InnerTx.V1; inner != nil {
+			return DecodePreconditions(inner.Tx.Cond)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePreconditionsV2(t *testing.T) {
+	signer := keypair.MustRandom()
+	var key xdr.SignerKey
+	require.NoError(t, key.SetAddress(signer.Address()))
+	minSeq := xdr.SequenceNumber(40)
+
+	p := DecodePreconditions(xdr.Preconditions{
+		Type: xdr.PreconditionTypePrecondV2,
+		V2: &xdr.PreconditionsV2{
+			TimeBounds:      &xdr.TimeBounds{MinTime: 1700000000},
+			LedgerBounds:    &xdr.LedgerBounds{MinLedger: 100, MaxLedger: 200},
+			MinSeqNum:       &minSeq,
+			MinSeqAge:       90,
+			MinSeqLedgerGap: 5,
+			ExtraSigners:    []xdr.SignerKey{key},
+		},
+	})
+	require.NotNil(t, p)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), *p.MinTime)
+	assert.Nil(t, p.MaxTime)
+	assert.Equal(t, uint32(100), p.MinLedger)
+	assert.Equal(t, uint32(200), p.MaxLedger)
+	assert.Equal(t, int64(40), *p.MinSeqNum)
+	assert.Equal(t, 90*time.Second, p.MinSeqAge)
+	assert.Equal(t, uint32(5), p.MinSeqLedgerGap)
+	assert.Equal(t, []string{signer.Address()}, p.ExtraSigners)
+
+	assert.Equal(t, []string{
+		"Valid from 2023-11-14T22:13:20Z, no expiry",
+		"Ledgers 100 to 200 (exclusive)",
+		"Source sequence number at least 40",
+		"Source sequence number unchanged for at least 1m30s",
+		"Source sequence number unchanged for at least 5 ledgers",
+		"Extra signer " + signer.Address(),
+	}, p.Lines())
+}
+
+func TestDecodePreconditionsEmpty(t *testing.T) {
+	assert.Nil(t, DecodePreconditions(xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone}))
+	assert.Nil(t, DecodePreconditions(xdr.Preconditions{Type: xdr.PreconditionTypePrecondV2, V2: &xdr.PreconditionsV2{}}))
+	assert.Nil(t, DecodePreconditions(xdr.NewPreconditionsWithTimeBounds(&xdr.TimeBounds{})))
+
+	p := DecodePreconditions(xdr.NewPreconditionsWithTimeBounds(&xdr.TimeBounds{MaxTime: 1700000000}))
+	require.NotNil(t, p)
+	assert.Equal(t, []string{"Valid until 2023-11-14T22:13:20Z"}, p.Lines())
+}
+
+func TestSignerHint(t *testing.T) {
+	kp := keypair.MustRandom()
+	var key xdr.SignerKey
+	require.NoError(t, key.SetAddress(kp.Address()))
+	sig, err := kp.SignDecorated([]byte("payload"))
+	require.NoError(t, err)
+	assert.Equal(t, sig.Hint, SignerHint(key))
+
+	ed := *key.Ed25519
+	payload := xdr.SignerKey{
+		Type:                 xdr.SignerKeyTypeSignerKeyTypeEd25519SignedPayload,
+		Ed25519SignedPayload: &xdr.SignerKeyEd25519SignedPayload{Ed25519: ed, Payload: []byte{1, 2}},
+	}
+	want := sig.Hint
+	want[0] ^= 1
+	want[1] ^= 2
+	assert.Equal(t, want, SignerHint(payload))
+}
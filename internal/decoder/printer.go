@@ -14,6 +14,13 @@ func PrintEnvelope(d *DecodedEnvelope) {
 	fmt.Println("Source Account:", maskAccount(d.Source))
 	fmt.Println("Fee:", d.Fee)
 
+	if d.Preconditions != nil {
+		fmt.Println("Preconditions:")
+		for _, line := range d.Preconditions.Lines() {
+			fmt.Println("  " + line)
+		}
+	}
+
 	if len(d.Operations) > 0 {
 		fmt.Println("Operations:")
 		for i, op := range d.Operations {
@@ -52,7 +59,16 @@ func printOperation(i int, op xdr.Operation) {
 	}
 }
 
+// maskAccount shortens an address; muxed addresses keep their account and
+// ID visible
 func maskAccount(addr string) string {
+	if m, ok := ParseMuxedAddress(addr); ok {
+		return fmt.Sprintf("%s (%s id %d)", mask(m.Address), mask(m.Account), m.ID)
+	}
+	return mask(addr)
+}
+
+func mask(addr string) string {
 	if len(addr) < 8 {
 		return addr
 	}
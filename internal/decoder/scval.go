@@ -0,0 +1,103 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// FormatScVal renders a contract value on one line: numbers in decimal,
+// addresses as strkeys, bytes in hex, vectors in brackets and maps in
+// braces
+func FormatScVal(v xdr.ScVal) string {
+	switch v.Type {
+	case xdr.ScValTypeScvBool:
+		return strconv.FormatBool(v.MustB())
+	case xdr.ScValTypeScvVoid:
+		return "void"
+	case xdr.ScValTypeScvU32:
+		return strconv.FormatUint(uint64(v.MustU32()), 10)
+	case xdr.ScValTypeScvI32:
+		return strconv.FormatInt(int64(v.MustI32()), 10)
+	case xdr.ScValTypeScvU64:
+		return strconv.FormatUint(uint64(v.MustU64()), 10)
+	case xdr.ScValTypeScvI64:
+		return strconv.FormatInt(int64(v.MustI64()), 10)
+	case xdr.ScValTypeScvTimepoint:
+		return strconv.FormatUint(uint64(v.MustTimepoint()), 10)
+	case xdr.ScValTypeScvDuration:
+		return strconv.FormatUint(uint64(v.MustDuration()), 10)
+	case xdr.ScValTypeScvU128:
+		p := v.MustU128()
+		return joinWords(false, uint64(p.Hi), uint64(p.Lo)).String()
+	case xdr.ScValTypeScvI128:
+		p := v.MustI128()
+		return joinWords(true, uint64(p.Hi), uint64(p.Lo)).String()
+	case xdr.ScValTypeScvU256:
+		p := v.MustU256()
+		return joinWords(false, uint64(p.HiHi), uint64(p.HiLo), uint64(p.LoHi), uint64(p.LoLo)).String()
+	case xdr.ScValTypeScvI256:
+		p := v.MustI256()
+		return joinWords(true, uint64(p.HiHi), uint64(p.HiLo), uint64(p.LoHi), uint64(p.LoLo)).String()
+	case xdr.ScValTypeScvBytes:
+		return "0x" + hex.EncodeToString(v.MustBytes())
+	case xdr.ScValTypeScvString:
+		return strconv.Quote(string(v.MustStr()))
+	case xdr.ScValTypeScvSymbol:
+		return string(v.MustSym())
+	case xdr.ScValTypeScvAddress:
+		addr := v.MustAddress()
+		if s, err := addr.String(); err == nil {
+			return s
+		}
+		return "address"
+	case xdr.ScValTypeScvVec:
+		vec, _ := v.GetVec()
+		if vec == nil {
+			return "[]"
+		}
+		items := make([]string, len(*vec))
+		for i, item := range *vec {
+			items[i] = FormatScVal(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case xdr.ScValTypeScvMap:
+		m, _ := v.GetMap()
+		if m == nil {
+			return "{}"
+		}
+		items := make([]string, len(*m))
+		for i, e := range *m {
+			items[i] = FormatScVal(e.Key) + ": " + FormatScVal(e.Val)
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	case xdr.ScValTypeScvError:
+		e := v.MustError()
+		if code, ok := e.GetContractCode(); ok {
+			return fmt.Sprintf("Error(Contract, #%d)", code)
+		}
+		return fmt.Sprintf("Error(%s)", strings.TrimPrefix(e.Type.String(), "ScErrorType"))
+	}
+	return strings.TrimPrefix(v.Type.String(), "ScValTypeScv")
+}
+
+// joinWords assembles a big-endian two's complement integer from 64-bit
+// words
+func joinWords(signed bool, words ...uint64) *big.Int {
+	n := new(big.Int)
+	for _, w := range words {
+		n.Lsh(n, 64)
+		n.Or(n, new(big.Int).SetUint64(w))
+	}
+	if signed && len(words) > 0 && words[0]&(1<<63) != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(64*len(words))))
+	}
+	return n
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatScVal(t *testing.T) {
+	accountID := xdr.MustAddress(muxedBase)
+	addr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountID}
+	sym := xdr.ScSymbol("balance")
+	str := xdr.ScString("hi")
+	bytes := xdr.ScBytes{0xde, 0xad}
+	vec := &xdr.ScVec{{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, {Type: xdr.ScValTypeScvBytes, Bytes: &bytes}}
+	m := &xdr.ScMap{{Key: xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}, Val: xdr.ScVal{Type: xdr.ScValTypeScvBool, B: new(bool)}}}
+	code := xdr.Uint32(3)
+
+	tests := []struct {
+		v    xdr.ScVal
+		want string
+	}{
+		{xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &addr}, muxedBase},
+		{xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: 0, Lo: 1_000_000}}, "1000000"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: -1, Lo: ^xdr.Uint64(0)}}, "-1"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &xdr.UInt128Parts{Hi: 1, Lo: 0}}, "18446744073709551616"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec}, "[balance, 0xdead]"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &m}, `{"hi": false}`},
+		{xdr.ScVal{Type: xdr.ScValTypeScvVoid}, "void"},
+		{xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &code}}, "Error(Contract, #3)"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FormatScVal(tt.v))
+	}
+}
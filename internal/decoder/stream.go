@@ -0,0 +1,146 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// streamChunk is the size of the base64 window; a multiple of 4 so every
+// full window decodes on its own
+const streamChunk = 64 * 1024
+
+// StreamDecoder decodes base64 XDR without materializing a second copy of
+// the text. Base64 is decoded in fixed-size windows into a binary buffer
+// that is reused between calls, and the XDR decoder reads from that buffer
+// directly. This keeps multi-megabyte envelopes, such as WASM uploads, to a
+// single allocation of the binary size that is amortized across decodes.
+//
+// A StreamDecoder is not safe for concurrent use; decoded values never
+// alias its buffers.
+type StreamDecoder struct {
+	raw     []byte
+	scratch []byte
+	text    strings.Reader
+	bin     *xdr.BytesDecoder
+}
+
+// NewStreamDecoder returns a decoder with empty reusable buffers
+func NewStreamDecoder() *StreamDecoder {
+	return &StreamDecoder{
+		scratch: make([]byte, streamChunk),
+		bin:     xdr.NewBytesDecoder(),
+	}
+}
+
+var streamDecoders = sync.Pool{New: func() any { return NewStreamDecoder() }}
+
+// DecodeBase64 decodes the base64 XDR in s into dest. Whitespace, such as
+// line breaks in pasted or file-based XDR, is ignored.
+func (d *StreamDecoder) DecodeBase64(s string, dest xdr.DecoderFrom) error {
+	d.raw = slices.Grow(d.raw[:0], base64.StdEncoding.DecodedLen(len(s)))
+	d.text.Reset(s)
+	return d.decode(&d.text, dest)
+}
+
+// DecodeBase64Reader decodes base64 XDR streamed from r into dest, so a
+// file holding an envelope never has to be read into memory as text
+func (d *StreamDecoder) DecodeBase64Reader(r io.Reader, dest xdr.DecoderFrom) error {
+	d.raw = d.raw[:0]
+	return d.decode(r, dest)
+}
+
+func (d *StreamDecoder) decode(r io.Reader, dest xdr.DecoderFrom) error {
+	if err := d.readBase64(r); err != nil {
+		return err
+	}
+	n, err := d.bin.DecodeBytes(dest, d.raw)
+	if err != nil {
+		return err
+	}
+	if n != len(d.raw) {
+		return fmt.Errorf("input not fully consumed. expected to read: %d, actual: %d", len(d.raw), n)
+	}
+	return nil
+}
+
+// readBase64 appends the binary form of the base64 text in r to d.raw
+func (d *StreamDecoder) readBase64(r io.Reader) error {
+	pending := 0
+	padded := false
+	for {
+		n, readErr := r.Read(d.scratch[pending:])
+		n = stripSpace(d.scratch[pending : pending+n])
+		if n > 0 && padded {
+			return errors.New("illegal base64 data: data after padding")
+		}
+		pending += n
+
+		// Decode whole quads and keep any remainder for the next read
+		quads := pending / 4 * 4
+		if quads > 0 {
+			start := len(d.raw)
+			d.raw = slices.Grow(d.raw, quads/4*3)
+			written, err := base64.StdEncoding.Decode(d.raw[start:start+quads/4*3], d.scratch[:quads])
+			if err != nil {
+				var corrupt base64.CorruptInputError
+				if errors.As(err, &corrupt) {
+					err = fmt.Errorf("illegal base64 data at input byte %d", start/3*4+int(corrupt))
+				}
+				return err
+			}
+			d.raw = d.raw[:start+written]
+			padded = d.scratch[quads-1] == '='
+			pending = copy(d.scratch, d.scratch[quads:pending])
+		}
+
+		if readErr == io.EOF {
+			if pending != 0 {
+				return errors.New("illegal base64 data: truncated input")
+			}
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// stripSpace removes ASCII whitespace from b in place and returns the new length
+func stripSpace(b []byte) int {
+	if bytes.IndexAny(b, " \t\r\n") < 0 {
+		return len(b)
+	}
+	n := 0
+	for _, c := range b {
+		switch c {
+		case ' ', '\t', '\r', '\n':
+		default:
+			b[n] = c
+			n++
+		}
+	}
+	return n
+}
+
+// UnmarshalBase64 decodes base64 XDR into dest using a pooled StreamDecoder
+func UnmarshalBase64(s string, dest xdr.DecoderFrom) error {
+	d := streamDecoders.Get().(*StreamDecoder)
+	defer streamDecoders.Put(d)
+	err := d.DecodeBase64(s, dest)
+	// Don't let one giant envelope pin its buffer in the pool
+	if cap(d.raw) > 16*1024*1024 {
+		d.raw = nil
+	}
+	return err
+}
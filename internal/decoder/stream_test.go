@@ -0,0 +1,121 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadEnvelope returns a base64 envelope uploading wasmSize bytes of code
+func uploadEnvelope(t testing.TB, wasmSize int) string {
+	code := make([]byte, wasmSize)
+	for i := range code {
+		code[i] = byte(i * 31)
+	}
+	source := xdr.MustMuxedAddress(keypair.MustRandom().Address())
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: source,
+				Fee:           100,
+				SeqNum:        1,
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypeInvokeHostFunction,
+						InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+							HostFunction: xdr.HostFunction{
+								Type: xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm,
+								Wasm: &code,
+							},
+						},
+					},
+				}},
+			},
+		},
+	}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return b64
+}
+
+func TestStreamDecoder_MatchesSafeUnmarshal(t *testing.T) {
+	b64 := uploadEnvelope(t, 100_000)
+
+	var want, got xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(b64, &want))
+
+	d := NewStreamDecoder()
+	require.NoError(t, d.DecodeBase64(b64, &got))
+	assert.Equal(t, want, got)
+
+	// The buffer is reused; earlier results must not change
+	var other xdr.TransactionEnvelope
+	require.NoError(t, d.DecodeBase64(uploadEnvelope(t, 10), &other))
+	assert.Equal(t, want, got)
+
+	// Line-wrapped text streamed from a reader
+	var wrapped strings.Builder
+	for i := 0; i < len(b64); i += 76 {
+		wrapped.WriteString(b64[i:min(i+76, len(b64))])
+		wrapped.WriteString("\r\n")
+	}
+	var streamed xdr.TransactionEnvelope
+	require.NoError(t, d.DecodeBase64Reader(strings.NewReader(wrapped.String()), &streamed))
+	assert.Equal(t, want, streamed)
+}
+
+func TestStreamDecoder_Errors(t *testing.T) {
+	b64 := uploadEnvelope(t, 1000)
+	d := NewStreamDecoder()
+	var env xdr.TransactionEnvelope
+
+	assert.Error(t, d.DecodeBase64(b64[:len(b64)-3], &env), "truncated base64")
+	assert.Error(t, d.DecodeBase64(b64[:len(b64)-400], &env), "truncated XDR")
+	assert.Error(t, d.DecodeBase64(b64+"AAAA", &env), "data after padding or trailing XDR")
+	assert.Error(t, d.DecodeBase64("AA!A", &env), "illegal character")
+	assert.Error(t, d.DecodeBase64("", &env))
+}
+
+func TestAnalyzeEnvelope_Upload(t *testing.T) {
+	decoded, err := AnalyzeEnvelope(uploadEnvelope(t, 5000))
+	require.NoError(t, err)
+	assert.Equal(t, "TransactionV1", decoded.Type)
+	require.Len(t, decoded.Operations, 1)
+	assert.Len(t, *decoded.Operations[0].Body.InvokeHostFunctionOp.HostFunction.Wasm, 5000)
+}
+
+// Compare with: go test ./internal/decoder -bench Envelope -benchmem
+func BenchmarkDecodeEnvelope_SafeUnmarshalBase64(b *testing.B) {
+	b64 := uploadEnvelope(b, 4<<20)
+	b.SetBytes(int64(len(b64)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var env xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(b64, &env); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeEnvelope_StreamDecoder(b *testing.B) {
+	b64 := uploadEnvelope(b, 4<<20)
+	d := NewStreamDecoder()
+	b.SetBytes(int64(len(b64)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var env xdr.TransactionEnvelope
+		if err := d.DecodeBase64(b64, &env); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,472 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// envelopeXDRKeys are the fields that carry a base64 envelope in RPC and
+// Horizon responses, wallet results and SDK objects, in the order they are
+// tried
+var envelopeXDRKeys = []string{
+	"envelope_xdr", "envelopeXdr", "signedTxXdr", "signed_envelope_xdr",
+	"tx_xdr", "txXdr", "transaction_xdr", "transactionXdr", "xdr", "transaction", "envelope",
+}
+
+// IsJSON reports whether s looks like a JSON object rather than base64 XDR
+func IsJSON(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), "{")
+}
+
+// EnvelopeFromJSON converts a JSON transaction into a base64 envelope. It
+// accepts:
+//   - the stellar-xdr JSON of a TransactionEnvelope or Transaction, as shown
+//     by Stellar Lab, 'stellar tx decode --output json' and the SDKs' toJSON
+//     (stellar-xdr-json in JS, to_json in Python)
+//   - any JSON object carrying the envelope as base64 under a field such as
+//     envelope_xdr, envelopeXdr, signedTxXdr or xdr, including JSON-RPC
+//     requests and responses
+func EnvelopeFromJSON(raw []byte) (string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return "", fmt.Errorf("invalid transaction JSON: %w", err)
+	}
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("transaction JSON must be an object")
+	}
+
+	if b64, ok := findEnvelopeXDR(obj); ok {
+		return b64, nil
+	}
+
+	var env xdr.TransactionEnvelope
+	switch {
+	case isXDRJSONEnvelope(obj):
+		if err := fromXDRJSON("", reflect.ValueOf(&env).Elem(), obj); err != nil {
+			return "", err
+		}
+	case obj["source_account"] != nil && obj["operations"] != nil:
+		// A bare Transaction becomes an unsigned v1 envelope
+		var tx xdr.Transaction
+		if err := fromXDRJSON("", reflect.ValueOf(&tx).Elem(), obj); err != nil {
+			return "", err
+		}
+		env = xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &xdr.TransactionV1Envelope{Tx: tx}}
+	default:
+		return "", fmt.Errorf("unrecognized transaction JSON: expected stellar-xdr JSON of a transaction envelope or an object with an envelope_xdr field")
+	}
+	b64, err := xdr.MarshalBase64(env)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	return b64, nil
+}
+
+// isXDRJSONEnvelope reports whether obj is the stellar-xdr JSON of a
+// TransactionEnvelope: one of its variants wrapping a transaction
+func isXDRJSONEnvelope(obj map[string]interface{}) bool {
+	if len(obj) != 1 {
+		return false
+	}
+	for _, key := range []string{"tx", "tx_v0", "tx_fee_bump"} {
+		if inner, ok := obj[key].(map[string]interface{}); ok && inner["tx"] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// findEnvelopeXDR looks for a base64 envelope in obj and the objects nested
+// in it
+func findEnvelopeXDR(obj map[string]interface{}) (string, bool) {
+	for _, key := range envelopeXDRKeys {
+		s, ok := obj[key].(string)
+		if !ok {
+			continue
+		}
+		s = strings.TrimSpace(s)
+		var env xdr.TransactionEnvelope
+		if UnmarshalBase64(s, &env) == nil {
+			return s, true
+		}
+	}
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if nested, ok := obj[key].(map[string]interface{}); ok {
+			if s, ok := findEnvelopeXDR(nested); ok {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+var (
+	muxedAccountType = reflect.TypeOf(xdr.MuxedAccount{})
+	accountIDType    = reflect.TypeOf(xdr.AccountId{})
+	publicKeyType    = reflect.TypeOf(xdr.PublicKey{})
+	scAddressType    = reflect.TypeOf(xdr.ScAddress{})
+	assetCode4Type   = reflect.TypeOf(xdr.AssetCode4{})
+	assetCode12Type  = reflect.TypeOf(xdr.AssetCode12{})
+	int128Type       = reflect.TypeOf(xdr.Int128Parts{})
+	uint128Type      = reflect.TypeOf(xdr.UInt128Parts{})
+)
+
+// fromXDRJSON sets v from its stellar-xdr JSON form: structs are objects
+// with snake_case fields, unions are a variant name or a one-key object,
+// 64-bit integers may be strings, opaque data is hex and accounts and
+// addresses are strkeys
+func fromXDRJSON(path string, v reflect.Value, in interface{}) error {
+	t := v.Type()
+	switch t {
+	case muxedAccountType, accountIDType, publicKeyType, scAddressType:
+		s, ok := in.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a strkey address", jsonPath(path))
+		}
+		addr, err := parseStrkey(t, s)
+		if err != nil {
+			return fmt.Errorf("%s: %w", jsonPath(path), err)
+		}
+		v.Set(addr)
+		return nil
+	case assetCode4Type, assetCode12Type:
+		s, ok := in.(string)
+		if !ok || len(s) > t.Len() {
+			return fmt.Errorf("%s: expected an asset code of at most %d characters", jsonPath(path), t.Len())
+		}
+		reflect.Copy(v, reflect.ValueOf([]byte(s)))
+		return nil
+	case int128Type, uint128Type:
+		if _, ok := in.(map[string]interface{}); !ok {
+			return set128(path, v, in)
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		if in == nil {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		p := reflect.New(t.Elem())
+		if err := fromXDRJSON(path, p.Elem(), in); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	case reflect.Struct:
+		if _, ok := t.MethodByName("SwitchFieldName"); ok {
+			return unionFromXDRJSON(path, v, in)
+		}
+		obj, ok := in.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object for %s", jsonPath(path), t.Name())
+		}
+		fields := make(map[string]int, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			fields[normalizeName(t.Field(i).Name)] = i
+		}
+		for key, val := range obj {
+			i, ok := fields[normalizeName(key)]
+			if !ok {
+				return fmt.Errorf("%s: unknown field %q in %s", jsonPath(path), key, t.Name())
+			}
+			if err := fromXDRJSON(joinPath(path, key), v.Field(i), val); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			b, err := hexString(path, in)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(b)
+			return nil
+		}
+		items, ok := in.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an array", jsonPath(path))
+		}
+		s := reflect.MakeSlice(t, len(items), len(items))
+		for i, item := range items {
+			if err := fromXDRJSON(fmt.Sprintf("%s[%d]", path, i), s.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Array:
+		b, err := hexString(path, in)
+		if err != nil {
+			return err
+		}
+		if len(b) != t.Len() {
+			return fmt.Errorf("%s: expected %d bytes of hex, got %d", jsonPath(path), t.Len(), len(b))
+		}
+		reflect.Copy(v, reflect.ValueOf(b))
+		return nil
+	case reflect.String:
+		s, ok := in.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a string", jsonPath(path))
+		}
+		v.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := in.(bool)
+		if !ok {
+			return fmt.Errorf("%s: expected a boolean", jsonPath(path))
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int32:
+		if _, ok := t.MethodByName("ValidEnum"); ok {
+			name, ok := in.(string)
+			if !ok {
+				return fmt.Errorf("%s: expected a %s name", jsonPath(path), t.Name())
+			}
+			n, ok := enumValue(t, name)
+			if !ok {
+				return fmt.Errorf("%s: unknown %s %q", jsonPath(path), t.Name(), name)
+			}
+			v.SetInt(int64(n))
+			return nil
+		}
+		fallthrough
+	case reflect.Int64:
+		n, err := strconv.ParseInt(numberString(in), 10, t.Bits())
+		if err != nil {
+			return fmt.Errorf("%s: expected an integer: %w", jsonPath(path), err)
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(numberString(in), 10, t.Bits())
+		if err != nil {
+			return fmt.Errorf("%s: expected an unsigned integer: %w", jsonPath(path), err)
+		}
+		v.SetUint(n)
+		return nil
+	}
+	return fmt.Errorf("%s: unsupported type %s", jsonPath(path), t)
+}
+
+// unionFromXDRJSON sets a union from "variant" or {"variant": value}
+func unionFromXDRJSON(path string, v reflect.Value, in interface{}) error {
+	var variant string
+	var value interface{}
+	switch x := in.(type) {
+	case string:
+		variant = x
+	case map[string]interface{}:
+		if len(x) != 1 {
+			return fmt.Errorf("%s: expected a single %s variant", jsonPath(path), v.Type().Name())
+		}
+		for k, val := range x {
+			variant, value = k, val
+		}
+	default:
+		return fmt.Errorf("%s: expected a %s variant", jsonPath(path), v.Type().Name())
+	}
+
+	switchName := v.MethodByName("SwitchFieldName").Call(nil)[0].String()
+	sw := v.FieldByName(switchName)
+	var disc int32
+	if _, ok := sw.Type().MethodByName("ValidEnum"); ok {
+		n, ok := enumValue(sw.Type(), variant)
+		if !ok {
+			return fmt.Errorf("%s: unknown %s variant %q", jsonPath(path), v.Type().Name(), variant)
+		}
+		disc = n
+	} else {
+		n, err := strconv.ParseInt(strings.TrimPrefix(variant, "v"), 10, 32)
+		if err != nil {
+			return fmt.Errorf("%s: unknown %s variant %q", jsonPath(path), v.Type().Name(), variant)
+		}
+		disc = int32(n)
+	}
+
+	out := v.MethodByName("ArmForSwitch").Call([]reflect.Value{reflect.ValueOf(disc)})
+	arm, ok := out[0].String(), out[1].Bool()
+	if !ok {
+		return fmt.Errorf("%s: %s has no variant %q", jsonPath(path), v.Type().Name(), variant)
+	}
+	sw.SetInt(int64(disc))
+	if arm == "" {
+		if value != nil {
+			return fmt.Errorf("%s: variant %q takes no value", jsonPath(path), variant)
+		}
+		return nil
+	}
+	return fromXDRJSON(joinPath(path, variant), v.FieldByName(arm), value)
+}
+
+// enumValue finds the value of enum type t whose Go name ends with name,
+// such as EnvelopeTypeEnvelopeTypeTxV0 for tx_v0. The shortest match wins.
+func enumValue(t reflect.Type, name string) (int32, bool) {
+	want := normalizeName(name)
+	e := reflect.New(t).Elem()
+	best, found := int32(0), false
+	bestLen := 0
+	for n := int32(-128); n <= 1024; n++ {
+		e.SetInt(int64(n))
+		goName := normalizeName(e.Interface().(fmt.Stringer).String())
+		if goName == "" || !strings.HasSuffix(goName, want) {
+			continue
+		}
+		if !found || len(goName) < bestLen {
+			best, bestLen, found = n, len(goName), true
+		}
+	}
+	return best, found
+}
+
+// parseStrkey converts a G, M, C, L or B strkey to the account or address type t
+func parseStrkey(t reflect.Type, s string) (reflect.Value, error) {
+	switch t {
+	case muxedAccountType:
+		m, err := xdr.AddressToMuxedAccount(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid account %q: %w", s, err)
+		}
+		return reflect.ValueOf(m), nil
+	case accountIDType, publicKeyType:
+		id, err := xdr.AddressToAccountId(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid account %q: %w", s, err)
+		}
+		return reflect.ValueOf(id).Convert(t), nil
+	}
+
+	var addr xdr.ScAddress
+	switch {
+	case strings.HasPrefix(s, "G"):
+		id, err := xdr.AddressToAccountId(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid account %q: %w", s, err)
+		}
+		addr = xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &id}
+	case strings.HasPrefix(s, "M"):
+		m, err := xdr.AddressToMuxedAccount(s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid muxed account %q: %w", s, err)
+		}
+		med := m.MustMed25519()
+		muxed := xdr.MuxedEd25519Account{Id: med.Id, Ed25519: med.Ed25519}
+		addr = xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeMuxedAccount, MuxedAccount: &muxed}
+	case strings.HasPrefix(s, "C"):
+		raw, err := strkey.Decode(strkey.VersionByteContract, s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid contract address %q: %w", s, err)
+		}
+		var id xdr.ContractId
+		copy(id[:], raw)
+		addr = xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}
+	case strings.HasPrefix(s, "L"):
+		raw, err := strkey.Decode(strkey.VersionByteLiquidityPool, s)
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid liquidity pool address %q: %w", s, err)
+		}
+		var id xdr.PoolId
+		copy(id[:], raw)
+		addr = xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeLiquidityPool, LiquidityPoolId: &id}
+	case strings.HasPrefix(s, "B"):
+		var id xdr.ClaimableBalanceId
+		if err := id.DecodeFromStrkey(s); err != nil {
+			return reflect.Value{}, fmt.Errorf("invalid claimable balance address %q: %w", s, err)
+		}
+		addr = xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeClaimableBalance, ClaimableBalanceId: &id}
+	default:
+		return reflect.Value{}, fmt.Errorf("unsupported address %q", s)
+	}
+	return reflect.ValueOf(addr), nil
+}
+
+var (
+	two64  = new(big.Int).Lsh(big.NewInt(1), 64)
+	two128 = new(big.Int).Lsh(big.NewInt(1), 128)
+)
+
+// set128 sets 128-bit integer parts from a decimal string or number
+func set128(path string, v reflect.Value, in interface{}) error {
+	n, ok := new(big.Int).SetString(numberString(in), 10)
+	if !ok {
+		return fmt.Errorf("%s: expected a 128-bit integer", jsonPath(path))
+	}
+	if n.Sign() < 0 {
+		if v.Type() == uint128Type {
+			return fmt.Errorf("%s: expected an unsigned 128-bit integer", jsonPath(path))
+		}
+		n.Add(n, two128)
+	}
+	if n.Cmp(two128) >= 0 {
+		return fmt.Errorf("%s: integer out of range", jsonPath(path))
+	}
+	lo := new(big.Int).Mod(n, two64).Uint64()
+	hi := new(big.Int).Rsh(n, 64).Uint64()
+	v.FieldByName("Lo").SetUint(lo)
+	if hiField := v.FieldByName("Hi"); hiField.Kind() == reflect.Int64 {
+		hiField.SetInt(int64(hi))
+	} else {
+		hiField.SetUint(hi)
+	}
+	return nil
+}
+
+func hexString(path string, in interface{}) ([]byte, error) {
+	s, ok := in.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected hex data", jsonPath(path))
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid hex data: %w", jsonPath(path), err)
+	}
+	return b, nil
+}
+
+func numberString(in interface{}) string {
+	switch x := in.(type) {
+	case json.Number:
+		return x.String()
+	case string:
+		return x
+	}
+	return fmt.Sprint(in)
+}
+
+// normalizeName makes snake_case JSON names and Go CamelCase names
+// comparable
+func normalizeName(s string) string {
+	return strings.ToLower(strings.ReplaceAll(s, "_", ""))
+}
+
+func jsonPath(path string) string {
+	if path == "" {
+		return "top level"
+	}
+	return path
+}
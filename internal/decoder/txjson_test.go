@@ -0,0 +1,144 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package decoder
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testContract(t *testing.T) string {
+	t.Helper()
+	c, err := strkey.Encode(strkey.VersionByteContract, make([]byte, 32))
+	require.NoError(t, err)
+	return c
+}
+
+// labEnvelopeJSON is a transaction envelope as Stellar Lab and
+// 'stellar tx decode --output json' show it
+func labEnvelopeJSON(t *testing.T) string {
+	contract := testContract(t)
+	return fmt.Sprintf(`{
+  "tx": {
+    "tx": {
+      "source_account": %[1]q,
+      "fee": 1100,
+      "seq_num": "4294967297",
+      "cond": {"time": {"min_time": "0", "max_time": 1700000000}},
+      "memo": {"text": "hello"},
+      "operations": [{
+        "source_account": null,
+        "body": {"invoke_host_function": {
+          "host_function": {"invoke_contract": {
+            "contract_address": %[2]q,
+            "function_name": "transfer",
+            "args": [{"address": %[1]q}, {"i128": "-5"}, {"u32": 7}, {"vec": [{"symbol": "a"}]}, "void", {"bytes": "deadbeef"}]
+          }},
+          "auth": []
+        }}
+      }],
+      "ext": {"v1": {
+        "ext": "v0",
+        "resources": {
+          "footprint": {
+            "read_only": [{"contract_data": {"contract": %[2]q, "key": "ledger_key_contract_instance", "durability": "persistent"}}],
+            "read_write": []
+          },
+          "instructions": 1000,
+          "disk_read_bytes": 0,
+          "write_bytes": 0
+        },
+        "resource_fee": "1000"
+      }}
+    },
+    "signatures": [{"hint": "01020304", "signature": "%[3]s"}]
+  }
+}`, diffSource, contract, fmt.Sprintf("%0128x", 1))
+}
+
+func TestEnvelopeFromXDRJSON(t *testing.T) {
+	b64, err := EnvelopeFromJSON([]byte(labEnvelopeJSON(t)))
+	require.NoError(t, err)
+
+	env, err := DecodeEnvelope(b64)
+	require.NoError(t, err)
+	require.Equal(t, xdr.EnvelopeTypeEnvelopeTypeTx, env.Type)
+	tx := env.V1.Tx
+	assert.Equal(t, diffSource, tx.SourceAccount.Address())
+	assert.Equal(t, xdr.Uint32(1100), tx.Fee)
+	assert.Equal(t, xdr.SequenceNumber(4294967297), tx.SeqNum)
+	assert.Equal(t, xdr.TimePoint(1700000000), tx.Cond.MustTimeBounds().MaxTime)
+	assert.Equal(t, "hello", tx.Memo.MustText())
+	require.Len(t, env.V1.Signatures, 1)
+	assert.Equal(t, xdr.SignatureHint{1, 2, 3, 4}, env.V1.Signatures[0].Hint)
+
+	invoke := tx.Operations[0].Body.MustInvokeHostFunctionOp().HostFunction.MustInvokeContract()
+	assert.Equal(t, xdr.ScSymbol("transfer"), invoke.FunctionName)
+	require.Len(t, invoke.Args, 6)
+	assert.Equal(t, xdr.ScAddressTypeScAddressTypeAccount, invoke.Args[0].MustAddress().Type)
+	i128 := invoke.Args[1].MustI128()
+	assert.Equal(t, xdr.Int64(-1), i128.Hi)
+	assert.Equal(t, xdr.Uint64(1<<64-5), i128.Lo)
+	assert.Equal(t, xdr.Uint32(7), invoke.Args[2].MustU32())
+	assert.Equal(t, xdr.ScSymbol("a"), (*invoke.Args[3].MustVec())[0].MustSym())
+	assert.Equal(t, xdr.ScValTypeScvVoid, invoke.Args[4].Type)
+	assert.Equal(t, xdr.ScBytes{0xde, 0xad, 0xbe, 0xef}, invoke.Args[5].MustBytes())
+
+	data := tx.Ext.MustSorobanData()
+	assert.Equal(t, xdr.Int64(1000), data.ResourceFee)
+	key := data.Resources.Footprint.ReadOnly[0].MustContractData()
+	assert.Equal(t, xdr.ScValTypeScvLedgerKeyContractInstance, key.Key.Type)
+	assert.Equal(t, xdr.ContractDataDurabilityPersistent, key.Durability)
+}
+
+func TestEnvelopeFromBareTransactionJSON(t *testing.T) {
+	b64, err := EnvelopeFromJSON([]byte(fmt.Sprintf(`{
+  "source_account": %q, "fee": 100, "seq_num": 1, "cond": "none", "memo": "none",
+  "operations": [{"source_account": null, "body": {"bump_sequence": {"bump_to": "5"}}}],
+  "ext": "v0"
+}`, diffSource)))
+	require.NoError(t, err)
+	env, err := DecodeEnvelope(b64)
+	require.NoError(t, err)
+	assert.Empty(t, env.V1.Signatures)
+	assert.Equal(t, xdr.SequenceNumber(5), env.V1.Tx.Operations[0].Body.MustBumpSequenceOp().BumpTo)
+}
+
+func TestEnvelopeFromWrappedXDR(t *testing.T) {
+	b64 := invokeEnvelope(t, 100, nil)
+	for _, doc := range []string{
+		fmt.Sprintf(`{"hash": "abc", "envelope_xdr": %q}`, b64),
+		fmt.Sprintf(`{"signedTxXdr": %q, "signerAddress": "G..."}`, b64),
+		fmt.Sprintf(`{"jsonrpc": "2.0", "method": "sendTransaction", "params": {"transaction": %q}}`, b64),
+		fmt.Sprintf(`{"jsonrpc": "2.0", "result": {"status": "SUCCESS", "envelopeXdr": %q}}`, b64),
+	} {
+		got, err := EnvelopeFromJSON([]byte(doc))
+		require.NoError(t, err, doc)
+		assert.Equal(t, b64, got)
+	}
+}
+
+func TestEnvelopeFromJSONErrors(t *testing.T) {
+	_, err := EnvelopeFromJSON([]byte(`{"foo": 1}`))
+	assert.ErrorContains(t, err, "unrecognized transaction JSON")
+
+	_, err = EnvelopeFromJSON([]byte(`[1]`))
+	assert.Error(t, err)
+
+	_, err = EnvelopeFromJSON([]byte(fmt.Sprintf(`{"tx": {"tx": {"source_account": %q, "fees": 1}, "signatures": []}}`, diffSource)))
+	assert.ErrorContains(t, err, `tx.tx: unknown field "fees"`)
+
+	_, err = EnvelopeFromJSON([]byte(`{"tx": {"tx": {"memo": {"bogus": 1}}, "signatures": []}}`))
+	assert.ErrorContains(t, err, `unknown Memo variant "bogus"`)
+}
+
+func TestIsJSON(t *testing.T) {
+	assert.True(t, IsJSON("  {\"tx\": {}}"))
+	assert.False(t, IsJSON("AAAAAgAAAAA="))
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"text/tabwriter"
 
 	"github.com/stellar/go-stellar-sdk/xdr"
@@ -148,22 +149,27 @@ func formatTransactionEnvelopeTable(env *xdr.TransactionEnvelope) (string, error
 			_, _ = fmt.Fprintf(w, "Fee:\t%d\n", tx.Fee)
 			_, _ = fmt.Fprintf(w, "Sequence Num:\t%d\n", tx.SeqNum)
 			_, _ = fmt.Fprintf(w, "Operations:\t%d\n", len(tx.Operations))
+			writePreconditions(w, DecodePreconditions(xdr.NewPreconditionsWithTimeBounds(tx.TimeBounds)))
 		}
 
 	case xdr.EnvelopeTypeEnvelopeTypeTx:
 		if env.V1 != nil {
 			tx := env.V1.Tx
-			_, _ = fmt.Fprintf(w, "Source Account:\t%s\n", tx.SourceAccount.Address())
+			_, _ = fmt.Fprintf(w, "Source Account:\t%s\n", FormatAccount(tx.SourceAccount.Address()))
 			_, _ = fmt.Fprintf(w, "Fee:\t%d\n", tx.Fee)
 			_, _ = fmt.Fprintf(w, "Sequence Num:\t%d\n", tx.SeqNum)
 			_, _ = fmt.Fprintf(w, "Operations:\t%d\n", len(tx.Operations))
+			writePreconditions(w, DecodePreconditions(tx.Cond))
 		}
 
 	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
 		if env.FeeBump != nil {
 			feeBump := env.FeeBump.Tx
-			_, _ = fmt.Fprintf(w, "Fee Source:\t%s\n", feeBump.FeeSource.Address())
+			_, _ = fmt.Fprintf(w, "Fee Source:\t%s\n", FormatAccount(feeBump.FeeSource.Address()))
 			_, _ = fmt.Fprintf(w, "Fee:\t%d\n", feeBump.Fee)
+			if inner := feeBump.InnerTx.V1; inner != nil {
+				writePreconditions(w, DecodePreconditions(inner.Tx.Cond))
+			}
 		}
 	}
 
@@ -171,6 +177,16 @@ func formatTransactionEnvelopeTable(env *xdr.TransactionEnvelope) (string, error
 	return buf.String(), nil
 }
 
+// writePreconditions adds a row for each condition in p, if any
+func writePreconditions(w io.Writer, p *Preconditions) {
+	if p == nil {
+		return
+	}
+	for _, line := range p.Lines() {
+		_, _ = fmt.Fprintf(w, "Precondition:\t%s\n", line)
+	}
+}
+
 func formatDiagnosticEventTable(event *xdr.DiagnosticEvent) (string, error) {
 	var buf bytes.Buffer
 	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
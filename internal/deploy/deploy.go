@@ -0,0 +1,193 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package deploy inspects contract deployment transactions: WASM uploads
+// and contract creations. It derives the resulting contract IDs from the
+// deployer and salt, and checks uploaded or referenced code against a local
+// WASM build.
+package deploy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// DefaultMaxWasmSize is the contract code size limit in bytes at the time of
+// writing. The real limit is a network setting.
+const DefaultMaxWasmSize = 128 * 1024
+
+// Upload is an upload-wasm host function
+type Upload struct {
+	OpIndex int    `json:"op_index"`
+	Hash    string `json:"hash"`
+	Size    int    `json:"size"`
+}
+
+// Creation is a create-contract host function
+type Creation struct {
+	OpIndex int `json:"op_index"`
+	// Deployer and Salt are set for contracts created from an address
+	Deployer string `json:"deployer,omitempty"`
+	Salt     string `json:"salt,omitempty"`
+	// Asset is set for Stellar Asset Contracts
+	Asset string `json:"asset,omitempty"`
+	// WasmHash is the code the contract runs, empty for asset contracts
+	WasmHash        string `json:"wasm_hash,omitempty"`
+	ContractID      string `json:"contract_id"`
+	ConstructorArgs int    `json:"constructor_args,omitempty"`
+	// UploadedInTx is true when the same transaction uploads WasmHash
+	UploadedInTx bool `json:"uploaded_in_tx,omitempty"`
+}
+
+// Analysis lists the deployment host functions of a transaction
+type Analysis struct {
+	Uploads   []Upload   `json:"uploads,omitempty"`
+	Creations []Creation `json:"creations,omitempty"`
+}
+
+// Empty reports whether the transaction deploys nothing
+func (a *Analysis) Empty() bool {
+	return len(a.Uploads) == 0 && len(a.Creations) == 0
+}
+
+// Analyze decodes the deployment host functions of a base64 envelope.
+// passphrase is needed to derive contract IDs.
+func Analyze(envelopeXdr, passphrase string) (*Analysis, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+
+	a := &Analysis{}
+	uploaded := map[string]bool{}
+	for i, op := range env.Operations() {
+		hf, ok := op.Body.GetInvokeHostFunctionOp()
+		if !ok {
+			continue
+		}
+
+		switch hf.HostFunction.Type {
+		case xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm:
+			code := *hf.HostFunction.Wasm
+			hash := WasmHash(code)
+			uploaded[hash] = true
+			a.Uploads = append(a.Uploads, Upload{OpIndex: i, Hash: hash, Size: len(code)})
+		case xdr.HostFunctionTypeHostFunctionTypeCreateContract:
+			args := hf.HostFunction.CreateContract
+			c, err := newCreation(i, args.ContractIdPreimage, args.Executable, passphrase)
+			if err != nil {
+				return nil, err
+			}
+			a.Creations = append(a.Creations, c)
+		case xdr.HostFunctionTypeHostFunctionTypeCreateContractV2:
+			args := hf.HostFunction.CreateContractV2
+			c, err := newCreation(i, args.ContractIdPreimage, args.Executable, passphrase)
+			if err != nil {
+				return nil, err
+			}
+			c.ConstructorArgs = len(args.ConstructorArgs)
+			a.Creations = append(a.Creations, c)
+		}
+	}
+
+	for i := range a.Creations {
+		a.Creations[i].UploadedInTx = uploaded[a.Creations[i].WasmHash]
+	}
+	return a, nil
+}
+
+func newCreation(opIndex int, preimage xdr.ContractIdPreimage, exec xdr.ContractExecutable, passphrase string) (Creation, error) {
+	c := Creation{OpIndex: opIndex}
+
+	switch preimage.Type {
+	case xdr.ContractIdPreimageTypeContractIdPreimageFromAddress:
+		deployer, err := preimage.FromAddress.Address.String()
+		if err != nil {
+			return c, fmt.Errorf("invalid deployer address: %w", err)
+		}
+		c.Deployer = deployer
+		c.Salt = hex.EncodeToString(preimage.FromAddress.Salt[:])
+	case xdr.ContractIdPreimageTypeContractIdPreimageFromAsset:
+		c.Asset = preimage.FromAsset.StringCanonical()
+	}
+
+	if exec.Type == xdr.ContractExecutableTypeContractExecutableWasm && exec.WasmHash != nil {
+		c.WasmHash = hex.EncodeToString(exec.WasmHash[:])
+	}
+
+	id, err := ContractID(preimage, passphrase)
+	if err != nil {
+		return c, err
+	}
+	c.ContractID = id
+	return c, nil
+}
+
+// ContractID derives the address of a contract created from preimage on the
+// network identified by passphrase: sha256 of the HashIDPreimage holding
+// the network ID and the preimage
+func ContractID(preimage xdr.ContractIdPreimage, passphrase string) (string, error) {
+	networkID := xdr.Hash(sha256.Sum256([]byte(passphrase)))
+	full := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeContractId,
+		ContractId: &xdr.HashIdPreimageContractId{
+			NetworkId:          networkID,
+			ContractIdPreimage: preimage,
+		},
+	}
+	raw, err := full.MarshalBinary()
+	if err != nil {
+		return "", fmt.Errorf("failed to encode contract ID preimage: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return strkey.Encode(strkey.VersionByteContract, sum[:])
+}
+
+// WasmHash returns the hex sha256 of contract code, the key it is stored
+// under on the ledger
+func WasmHash(code []byte) string {
+	sum := sha256.Sum256(code)
+	return hex.EncodeToString(sum[:])
+}
+
+// Mismatch describes deployed code that differs from the local build
+type Mismatch struct {
+	OpIndex  int
+	Kind     string
+	Expected string
+	Actual   string
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("operation #%d %s hash %s does not match local WASM %s", m.OpIndex, m.Kind, shortHash(m.Actual), shortHash(m.Expected))
+}
+
+// VerifyWasm checks every upload and WASM creation against local code and
+// returns the mismatches
+func (a *Analysis) VerifyWasm(local []byte) []Mismatch {
+	want := WasmHash(local)
+
+	var out []Mismatch
+	for _, u := range a.Uploads {
+		if u.Hash != want {
+			out = append(out, Mismatch{OpIndex: u.OpIndex, Kind: "upload", Expected: want, Actual: u.Hash})
+		}
+	}
+	for _, c := range a.Creations {
+		if c.WasmHash != "" && c.WasmHash != want {
+			out = append(out, Mismatch{OpIndex: c.OpIndex, Kind: "create", Expected: want, Actual: c.WasmHash})
+		}
+	}
+	return out
+}
+
+func shortHash(h string) string {
+	if len(h) > 12 {
+		return h[:12] + "…"
+	}
+	return h
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package deploy
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDeployer = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func hostFnOp(hf xdr.HostFunction) xdr.Operation {
+	return xdr.Operation{Body: xdr.OperationBody{
+		Type:                 xdr.OperationTypeInvokeHostFunction,
+		InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: hf},
+	}}
+}
+
+func envelope(t *testing.T, ops ...xdr.Operation) string {
+	t.Helper()
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(testDeployer),
+			Fee:           100,
+			Operations:    ops,
+		}},
+	}
+	s, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return s
+}
+
+func TestAnalyze_UploadAndCreate(t *testing.T) {
+	code := []byte("\x00asm fake contract")
+	hash := xdr.Hash(sha256.Sum256(code))
+	deployer, err := xdr.AddressToAccountId(testDeployer)
+	require.NoError(t, err)
+
+	upload := hostFnOp(xdr.HostFunction{Type: xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm, Wasm: &code})
+	create := hostFnOp(xdr.HostFunction{
+		Type: xdr.HostFunctionTypeHostFunctionTypeCreateContractV2,
+		CreateContractV2: &xdr.CreateContractArgsV2{
+			ContractIdPreimage: xdr.ContractIdPreimage{
+				Type: xdr.ContractIdPreimageTypeContractIdPreimageFromAddress,
+				FromAddress: &xdr.ContractIdPreimageFromAddress{
+					Address: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &deployer},
+					Salt:    xdr.Uint256{1},
+				},
+			},
+			Executable:      xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &hash},
+			ConstructorArgs: []xdr.ScVal{{Type: xdr.ScValTypeScvVoid}},
+		},
+	})
+
+	a, err := Analyze(envelope(t, upload, create), network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	require.Len(t, a.Uploads, 1)
+	require.Len(t, a.Creations, 1)
+	assert.Equal(t, WasmHash(code), a.Uploads[0].Hash)
+	assert.Equal(t, len(code), a.Uploads[0].Size)
+
+	c := a.Creations[0]
+	assert.Equal(t, 1, c.OpIndex)
+	assert.Equal(t, testDeployer, c.Deployer)
+	assert.Equal(t, "01"+strings.Repeat("00", 31), c.Salt)
+	assert.True(t, c.UploadedInTx)
+	assert.Equal(t, 1, c.ConstructorArgs)
+	assert.True(t, strkey.IsValidContractAddress(c.ContractID))
+
+	other, err := Analyze(envelope(t, upload, create), network.PublicNetworkPassphrase)
+	require.NoError(t, err)
+	assert.NotEqual(t, c.ContractID, other.Creations[0].ContractID, "contract IDs depend on the network")
+
+	assert.Empty(t, a.VerifyWasm(code))
+	mismatches := a.VerifyWasm([]byte("other"))
+	require.Len(t, mismatches, 2)
+	assert.Equal(t, "upload", mismatches[0].Kind)
+	assert.Equal(t, "create", mismatches[1].Kind)
+}
+
+func TestContractID_MatchesAssetContract(t *testing.T) {
+	asset := xdr.MustNewCreditAsset("USDC", testDeployer)
+	want, err := asset.ContractID(network.TestNetworkPassphrase)
+	require.NoError(t, err)
+
+	got, err := ContractID(xdr.ContractIdPreimage{
+		Type:      xdr.ContractIdPreimageTypeContractIdPreimageFromAsset,
+		FromAsset: &asset,
+	}, network.TestNetworkPassphrase)
+	require.NoError(t, err)
+	assert.Equal(t, strkey.MustEncode(strkey.VersionByteContract, want[:]), got)
+}
@@ -0,0 +1,307 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package envsize breaks down the encoded size of a transaction envelope by
+// component and suggests ways to shrink it, for transactions that hit the
+// network's transaction size limit.
+package envsize
+
+import (
+	"encoding"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/wasm"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// DefaultLimit is the maximum transaction size in bytes on mainnet and
+// testnet (tx_max_size_bytes)
+const DefaultLimit = 132096
+
+// largeArgBytes is the size from which a single argument is worth
+// reporting
+const largeArgBytes = 4096
+
+// Components of an envelope, in the order they are reported
+const (
+	ComponentSignatures = "signatures"
+	ComponentAuth       = "auth entries"
+	ComponentArgs       = "arguments"
+	ComponentCode       = "contract code"
+	ComponentReadOnly   = "footprint read-only"
+	ComponentReadWrite  = "footprint read-write"
+	ComponentOperations = "other operation data"
+	ComponentOther      = "other"
+)
+
+// Component is the encoded size of one part of the envelope
+type Component struct {
+	Name  string `json:"name"`
+	Bytes int    `json:"bytes"`
+	Count int    `json:"count"`
+}
+
+// Suggestion is a change that makes the envelope smaller. Path uses the
+// field names of erst diffxdr.
+type Suggestion struct {
+	ID      string `json:"id"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+	// Savings is the number of bytes the change saves, when known
+	Savings int `json:"savings,omitempty"`
+}
+
+// Report is the result of Analyze
+type Report struct {
+	Bytes       int          `json:"bytes"`
+	Limit       int          `json:"limit"`
+	Components  []Component  `json:"components"`
+	Suggestions []Suggestion `json:"suggestions"`
+}
+
+// OverLimit reports whether the envelope is larger than the limit
+func (r *Report) OverLimit() bool {
+	return r.Bytes > r.Limit
+}
+
+// Savings is the number of bytes all suggestions together would save
+func (r *Report) Savings() int {
+	total := 0
+	for _, s := range r.Suggestions {
+		total += s.Savings
+	}
+	return total
+}
+
+// Analyze decodes a base64 envelope and breaks down its size
+func Analyze(envelopeXdr string) (*Report, error) {
+	var env xdr.TransactionEnvelope
+	if err := decoder.UnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	return AnalyzeEnvelope(env)
+}
+
+// AnalyzeEnvelope breaks down the size of env against DefaultLimit
+func AnalyzeEnvelope(env xdr.TransactionEnvelope) (*Report, error) {
+	raw, err := env.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	a := &analysis{sizes: make(map[string]*Component)}
+
+	switch env.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTxV0:
+		v0 := env.MustV0()
+		a.signatures("v0", v0.Signatures)
+		a.operations("v0.tx", v0.Tx.Operations)
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		v1 := env.MustV1()
+		a.signatures("v1", v1.Signatures)
+		a.transaction("v1.tx", v1.Tx)
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		fb := env.MustFeeBump()
+		a.signatures("feeBump", fb.Signatures)
+		inner := fb.Tx.InnerTx.MustV1()
+		a.signatures("feeBump.tx.innerTx.v1", inner.Signatures)
+		a.transaction("feeBump.tx.innerTx.v1.tx", inner.Tx)
+	}
+	return a.report(len(raw)), nil
+}
+
+type analysis struct {
+	sizes       map[string]*Component
+	suggestions []Suggestion
+}
+
+func (a *analysis) add(name string, n int) {
+	c, ok := a.sizes[name]
+	if !ok {
+		c = &Component{Name: name}
+		a.sizes[name] = c
+	}
+	c.Bytes += n
+	c.Count++
+}
+
+func (a *analysis) suggest(s Suggestion) {
+	a.suggestions = append(a.suggestions, s)
+}
+
+func (a *analysis) report(total int) *Report {
+	r := &Report{Bytes: total, Limit: DefaultLimit, Suggestions: a.suggestions}
+	counted := 0
+	for _, name := range []string{ComponentSignatures, ComponentAuth, ComponentArgs, ComponentCode, ComponentReadOnly, ComponentReadWrite, ComponentOperations} {
+		if c, ok := a.sizes[name]; ok {
+			r.Components = append(r.Components, *c)
+			counted += c.Bytes
+		}
+	}
+	// Everything else: source account, fee, sequence number, preconditions,
+	// memo, resource limits and the XDR framing around the components
+	r.Components = append(r.Components, Component{Name: ComponentOther, Bytes: max(total-counted, 0), Count: 1})
+	if r.Suggestions == nil {
+		r.Suggestions = []Suggestion{}
+	}
+	return r
+}
+
+func (a *analysis) signatures(path string, sigs []xdr.DecoratedSignature) {
+	seen := make(map[string]bool)
+	for i, sig := range sigs {
+		raw := encode(sig)
+		a.add(ComponentSignatures, len(raw))
+		if seen[string(raw)] {
+			a.suggest(Suggestion{
+				ID: "duplicate-signature", Path: fmt.Sprintf("%s.signatures[%d]", path, i),
+				Message: "the envelope carries the same signature twice; drop the copy",
+				Savings: len(raw),
+			})
+		}
+		seen[string(raw)] = true
+	}
+}
+
+func (a *analysis) transaction(path string, tx xdr.Transaction) {
+	a.operations(path, tx.Operations)
+	if data, ok := tx.Ext.GetSorobanData(); ok {
+		a.footprint(path+".ext.sorobanData.resources.footprint", data.Resources.Footprint)
+	}
+}
+
+func (a *analysis) operations(path string, ops []xdr.Operation) {
+	for i, op := range ops {
+		opSize := len(encode(op))
+		opPath := fmt.Sprintf("%s.operations[%d]", path, i)
+		if invoke, ok := op.Body.GetInvokeHostFunctionOp(); ok {
+			opSize -= a.invoke(opPath+".body.invokeHostFunctionOp", invoke)
+		}
+		a.add(ComponentOperations, opSize)
+	}
+}
+
+// invoke counts the arguments, contract code and auth entries of op and
+// returns the bytes it counted
+func (a *analysis) invoke(path string, op xdr.InvokeHostFunctionOp) int {
+	counted := 0
+	fn := op.HostFunction
+	switch fn.Type {
+	case xdr.HostFunctionTypeHostFunctionTypeInvokeContract:
+		counted += a.args(path+".hostFunction.invokeContract.args", fn.MustInvokeContract().Args)
+	case xdr.HostFunctionTypeHostFunctionTypeCreateContractV2:
+		counted += a.args(path+".hostFunction.createContractV2.constructorArgs", fn.MustCreateContractV2().ConstructorArgs)
+	case xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm:
+		wasm := fn.MustWasm()
+		n := len(encode(xdr.HostFunction{Type: fn.Type, Wasm: &wasm})) - 4
+		a.add(ComponentCode, n)
+		counted += n
+		if strip := strippableSections(wasm); strip > 0 {
+			a.suggest(Suggestion{
+				ID: "unoptimized-wasm", Path: path + ".hostFunction.wasm",
+				Message: "the contract code has debug or name sections the network does not need; build with 'stellar contract build' and run 'stellar contract optimize' or 'wasm-opt -Oz --strip-debug'",
+				Savings: strip,
+			})
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, entry := range op.Auth {
+		raw := encode(entry)
+		a.add(ComponentAuth, len(raw))
+		counted += len(raw)
+		if seen[string(raw)] {
+			a.suggest(Suggestion{
+				ID: "duplicate-auth-entry", Path: fmt.Sprintf("%s.auth[%d]", path, i),
+				Message: "the authorization entry repeats an earlier one; the host needs each entry once",
+				Savings: len(raw),
+			})
+		}
+		seen[string(raw)] = true
+	}
+	return counted
+}
+
+func (a *analysis) args(path string, args []xdr.ScVal) int {
+	counted := 0
+	for i, arg := range args {
+		n := len(encode(arg))
+		a.add(ComponentArgs, n)
+		counted += n
+		if n >= largeArgBytes {
+			a.suggest(Suggestion{
+				ID: "large-argument", Path: fmt.Sprintf("%s[%d]", path, i),
+				Message: fmt.Sprintf("the argument encodes to %d bytes; pass a hash of the data, store it in contract storage over several transactions, or split the call", n),
+			})
+		}
+	}
+	return counted
+}
+
+func (a *analysis) footprint(path string, fp xdr.LedgerFootprint) {
+	readWrite := make(map[string]bool)
+	for _, key := range fp.ReadWrite {
+		readWrite[string(encode(key))] = true
+	}
+
+	seen := make(map[string]string)
+	for _, list := range []struct {
+		name      string
+		component string
+		keys      []xdr.LedgerKey
+	}{{"readOnly", ComponentReadOnly, fp.ReadOnly}, {"readWrite", ComponentReadWrite, fp.ReadWrite}} {
+		for i, key := range list.keys {
+			raw := string(encode(key))
+			keyPath := fmt.Sprintf("%s.%s[%d]", path, list.name, i)
+			a.add(list.component, len(raw))
+			if list.name == "readOnly" && readWrite[raw] {
+				a.suggest(Suggestion{
+					ID: "read-only-key-also-written", Path: keyPath,
+					Message: "the ledger key is also in readWrite, which covers reads; drop it from readOnly",
+					Savings: len(raw),
+				})
+				continue
+			}
+			if first, dup := seen[raw]; dup {
+				a.suggest(Suggestion{
+					ID: "duplicate-footprint-key", Path: keyPath,
+					Message: "the ledger key is already listed at " + first,
+					Savings: len(raw),
+				})
+				continue
+			}
+			seen[raw] = keyPath
+		}
+	}
+}
+
+func encode(v encoding.BinaryMarshaler) []byte {
+	raw, err := v.MarshalBinary()
+	if err != nil {
+		return nil
+	}
+	return raw
+}
+
+// keptSections are custom Wasm sections the Soroban host reads
+var keptSections = map[string]bool{
+	"contractspecv0":    true,
+	"contractenvmetav0": true,
+	"contractmetav0":    true,
+}
+
+// strippableSections returns the total size of the custom sections of a
+// Wasm module that the host ignores, such as name, producers and DWARF
+// debug sections
+func strippableSections(code []byte) int {
+	// A malformed module, such as one whose custom section name length runs
+	// past the section or overflows int, still counts the sections before it
+	sections, _ := wasm.Sections(code)
+	total := 0
+	for _, s := range sections {
+		if s.ID == wasm.SectionCustom && !keptSections[s.Name] {
+			total += s.Size
+		}
+	}
+	return total
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package envsize
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccount = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func contractKey(b byte) xdr.LedgerKey {
+	id := xdr.ContractId{b}
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id},
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+		},
+	}
+}
+
+func envelope(fn xdr.HostFunction, auth []xdr.SorobanAuthorizationEntry, fp xdr.LedgerFootprint, sigs []xdr.DecoratedSignature) xdr.TransactionEnvelope {
+	tx := xdr.Transaction{
+		SourceAccount: xdr.MustMuxedAddress(testAccount),
+		Fee:           1100,
+		SeqNum:        1,
+		Operations: []xdr.Operation{{Body: xdr.OperationBody{
+			Type:                 xdr.OperationTypeInvokeHostFunction,
+			InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: fn, Auth: auth},
+		}}},
+		Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+			Resources:   xdr.SorobanResources{Footprint: fp},
+			ResourceFee: 1000,
+		}},
+	}
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1:   &xdr.TransactionV1Envelope{Tx: tx, Signatures: sigs},
+	}
+}
+
+func invoke(args ...xdr.ScVal) xdr.HostFunction {
+	id := xdr.ContractId{1}
+	return xdr.HostFunction{
+		Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+		InvokeContract: &xdr.InvokeContractArgs{
+			ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id},
+			FunctionName:    "set",
+			Args:            args,
+		},
+	}
+}
+
+func bytesVal(n int) xdr.ScVal {
+	b := xdr.ScBytes(make([]byte, n))
+	return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}
+}
+
+func component(r *Report, name string) Component {
+	for _, c := range r.Components {
+		if c.Name == name {
+			return c
+		}
+	}
+	return Component{}
+}
+
+func suggestionIDs(r *Report) []string {
+	var ids []string
+	for _, s := range r.Suggestions {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+func TestAnalyzeComponentsAddUpToTotal(t *testing.T) {
+	sig := xdr.DecoratedSignature{Hint: xdr.SignatureHint{1, 2, 3, 4}, Signature: make([]byte, 64)}
+	auth := xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{Function: xdr.SorobanAuthorizedFunction{
+			Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+			ContractFn: invoke(bytesVal(8)).InvokeContract,
+		}},
+	}
+	fp := xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{contractKey(1)}, ReadWrite: []xdr.LedgerKey{contractKey(2)}}
+	env := envelope(invoke(bytesVal(100)), []xdr.SorobanAuthorizationEntry{auth}, fp, []xdr.DecoratedSignature{sig})
+
+	r, err := AnalyzeEnvelope(env)
+	require.NoError(t, err)
+
+	raw, err := env.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, len(raw), r.Bytes)
+	assert.Equal(t, DefaultLimit, r.Limit)
+	assert.False(t, r.OverLimit())
+
+	sum := 0
+	for _, c := range r.Components {
+		sum += c.Bytes
+	}
+	assert.Equal(t, r.Bytes, sum)
+
+	assert.Equal(t, Component{Name: ComponentSignatures, Bytes: 72, Count: 1}, component(r, ComponentSignatures))
+	assert.Equal(t, 108, component(r, ComponentArgs).Bytes)
+	assert.Equal(t, 1, component(r, ComponentAuth).Count)
+	assert.Equal(t, component(r, ComponentReadOnly).Bytes, component(r, ComponentReadWrite).Bytes)
+	assert.Empty(t, r.Suggestions)
+}
+
+func TestAnalyzeSuggestsFootprintReductions(t *testing.T) {
+	fp := xdr.LedgerFootprint{
+		ReadOnly:  []xdr.LedgerKey{contractKey(1), contractKey(2), contractKey(1)},
+		ReadWrite: []xdr.LedgerKey{contractKey(2)},
+	}
+	r, err := AnalyzeEnvelope(envelope(invoke(), nil, fp, nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"read-only-key-also-written", "duplicate-footprint-key"}, suggestionIDs(r))
+	assert.Equal(t, "v1.tx.ext.sorobanData.resources.footprint.readOnly[1]", r.Suggestions[0].Path)
+	assert.Contains(t, r.Suggestions[1].Message, "readOnly[0]")
+	keySize := component(r, ComponentReadOnly).Bytes / 3
+	assert.Equal(t, 2*keySize, r.Savings())
+}
+
+func TestAnalyzeSuggestsLargeArgumentsAndDuplicates(t *testing.T) {
+	sig := xdr.DecoratedSignature{Hint: xdr.SignatureHint{1, 2, 3, 4}, Signature: make([]byte, 64)}
+	r, err := AnalyzeEnvelope(envelope(invoke(bytesVal(largeArgBytes)), nil, xdr.LedgerFootprint{}, []xdr.DecoratedSignature{sig, sig}))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"duplicate-signature", "large-argument"}, suggestionIDs(r))
+	assert.Equal(t, "v1.signatures[1]", r.Suggestions[0].Path)
+	assert.Equal(t, 72, r.Suggestions[0].Savings)
+	assert.Equal(t, "v1.tx.operations[0].body.invokeHostFunctionOp.hostFunction.invokeContract.args[0]", r.Suggestions[1].Path)
+}
+
+func TestAnalyzeWasmUpload(t *testing.T) {
+	// A module with a name section (stripped) and a contract spec (kept)
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	wasm = append(wasm, 0x00, 0x09, 0x04, 'n', 'a', 'm', 'e', 1, 2, 3, 4)
+	wasm = append(wasm, 0x00, 0x10, 0x0e)
+	wasm = append(wasm, []byte("contractspecv0")...)
+	wasm = append(wasm, 0x00)
+	fn := xdr.HostFunction{Type: xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm, Wasm: &wasm}
+
+	r, err := AnalyzeEnvelope(envelope(fn, nil, xdr.LedgerFootprint{}, nil))
+	require.NoError(t, err)
+
+	// Length prefix plus the code padded to four bytes
+	assert.Equal(t, 4+(len(wasm)+3)/4*4, component(r, ComponentCode).Bytes)
+	require.Len(t, r.Suggestions, 1)
+	assert.Equal(t, "unoptimized-wasm", r.Suggestions[0].ID)
+	assert.Equal(t, 11, r.Suggestions[0].Savings)
+}
+
+func TestStrippableSectionsHugeNameLength(t *testing.T) {
+	// A name section, then a custom section whose name length overflows int
+	wasm := []byte("\x00asm\x01\x00\x00\x00")
+	wasm = append(wasm, 0x00, 0x06, 0x04, 'n', 'a', 'm', 'e', 1)
+	wasm = append(wasm, 0x00, 0x06, 0xFF, 0xFF, 0xFF, 0xFF, 0x0F, 'x')
+
+	assert.Equal(t, 8, strippableSections(wasm))
+}
+
+func TestAnalyzeRejectsInvalidXDR(t *testing.T) {
+	_, err := Analyze("not-xdr")
+	assert.Error(t, err)
+}
@@ -18,6 +18,7 @@ var (
 	ErrMarshalFailed        = errors.New("failed to marshal request")
 	ErrUnmarshalFailed      = errors.New("failed to unmarshal response")
 	ErrSimulationLogicError = errors.New("simulation logic error")
+	ErrResourceLimit        = errors.New("simulator resource limit exceeded")
 )
 
 // Wrap functions for consistent error wrapping
@@ -0,0 +1,464 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package explain turns the structured outcome of a debug run into a few
+// plain-English paragraphs. Generation is rule based and deterministic: the
+// same inputs always produce the same text.
+package explain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/deploy"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// BaseReserveStroops is the network base reserve used for minimum balance
+// math (0.5 XLM on all public networks)
+const BaseReserveStroops int64 = 5_000_000
+
+const stroopsPerXLM = 10_000_000
+
+// Input is everything the explainer may draw on. Any field can be empty.
+type Input struct {
+	EnvelopeXdr   string
+	ResultXdr     string
+	ResultMetaXdr string
+	Simulation    *simulator.SimulationResponse
+	Findings      []security.Finding
+	// NetworkPassphrase is used to derive the IDs of deployed contracts
+	NetworkPassphrase string
+	// LedgerSequence and LedgerCloseTime describe the ledger that applied
+	// the transaction; preconditions are only checked when they are set
+	LedgerSequence  uint32
+	LedgerCloseTime time.Time
+}
+
+// facts is the decoded view of Input shared by all rules
+type facts struct {
+	in       Input
+	env      *xdr.TransactionEnvelope
+	result   *xdr.TransactionResult
+	source   string
+	accounts map[string]xdr.AccountEntry // pre-transaction state by address
+}
+
+// rule produces zero or more paragraphs from the decoded facts
+type rule func(f *facts) []string
+
+var rules = []rule{
+	outcomeRule,
+	preconditionRule,
+	balanceRule,
+	operationRule,
+	contractErrorRule,
+	deployRule,
+	budgetRule,
+	findingsRule,
+}
+
+// Explain returns a short list of paragraphs describing why the transaction
+// behaved the way it did
+func Explain(in Input) []string {
+	f := decode(in)
+
+	var out []string
+	for _, r := range rules {
+		out = append(out, r(f)...)
+	}
+	return out
+}
+
+func decode(in Input) *facts {
+	f := &facts{in: in, accounts: make(map[string]xdr.AccountEntry)}
+
+	if in.EnvelopeXdr != "" {
+		var env xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(in.EnvelopeXdr, &env); err == nil {
+			f.env = &env
+			src := env.SourceAccount().ToAccountId()
+			f.source = src.Address()
+		}
+	}
+
+	if in.ResultXdr != "" {
+		var res xdr.TransactionResult
+		if err := xdr.SafeUnmarshalBase64(in.ResultXdr, &res); err == nil {
+			f.result = &res
+		}
+	}
+
+	if in.ResultMetaXdr != "" {
+		if raw, err := base64.StdEncoding.DecodeString(in.ResultMetaXdr); err == nil {
+			var meta xdr.TransactionResultMeta
+			if err := xdr.SafeUnmarshal(raw, &meta); err == nil {
+				collectAccounts(f.accounts, meta.FeeProcessing)
+				collectAccounts(f.accounts, txmeta.Changes(meta.TxApplyProcessing))
+			}
+		}
+	}
+
+	return f
+}
+
+// collectAccounts records the first observed state of every account entry
+func collectAccounts(into map[string]xdr.AccountEntry, changes xdr.LedgerEntryChanges) {
+	for _, c := range changes {
+		if c.Type != xdr.LedgerEntryChangeTypeLedgerEntryState || c.State == nil {
+			continue
+		}
+		acc, ok := c.State.Data.GetAccount()
+		if !ok {
+			continue
+		}
+		addr := acc.AccountId.Address()
+		if _, seen := into[addr]; !seen {
+			into[addr] = acc
+		}
+	}
+}
+
+// FormatXLM renders a stroop amount as XLM without trailing zeros
+func FormatXLM(stroops int64) string {
+	sign := ""
+	if stroops < 0 {
+		sign = "-"
+		stroops = -stroops
+	}
+	s := fmt.Sprintf("%d.%07d", stroops/stroopsPerXLM, stroops%stroopsPerXLM)
+	s = strings.TrimRight(s, "0")
+	if strings.HasSuffix(s, ".") {
+		s += "0"
+	}
+	return sign + s + " XLM"
+}
+
+// minimumBalance is the reserve an account must keep given its subentries
+// and sponsorships
+func minimumBalance(acc xdr.AccountEntry) int64 {
+	entries := 2 + int64(acc.NumSubEntries) + int64(acc.NumSponsoring()) - int64(acc.NumSponsored())
+	return entries * BaseReserveStroops
+}
+
+func shortAddr(addr string) string {
+	if len(addr) <= 12 {
+		return addr
+	}
+	return addr[:4] + "..." + addr[len(addr)-4:]
+}
+
+func outcomeRule(f *facts) []string {
+	if f.result != nil {
+		info := decoder.DecodeTransactionResultCode(f.result.Result.Code)
+		if f.result.Result.Code == xdr.TransactionResultCodeTxSuccess || f.result.Result.Code == xdr.TransactionResultCodeTxFeeBumpInnerSuccess {
+			return []string{fmt.Sprintf("The transaction succeeded on-chain and was charged a fee of %s.", FormatXLM(int64(f.result.FeeCharged)))}
+		}
+		return []string{fmt.Sprintf("The transaction failed with %s (%s). %s. It was still charged a fee of %s.",
+			info.Code, strings.ToLower(info.Description), info.Explanation, FormatXLM(int64(f.result.FeeCharged)))}
+	}
+
+	if sim := f.in.Simulation; sim != nil {
+		if sim.Status == "success" {
+			return []string{"The simulated replay completed successfully."}
+		}
+		if sim.Error != "" {
+			return []string{fmt.Sprintf("The simulated replay failed: %s.", strings.TrimSuffix(sim.Error, "."))}
+		}
+		return []string{"The simulated replay failed without an error message."}
+	}
+
+	return nil
+}
+
+// balanceRule explains reserve and fee shortfalls with the actual numbers
+func balanceRule(f *facts) []string {
+	if f.result == nil || f.env == nil {
+		return nil
+	}
+
+	var paragraphs []string
+	acc, known := f.accounts[f.source]
+	fee := int64(f.env.Fee())
+
+	if f.result.Result.Code == xdr.TransactionResultCodeTxInsufficientBalance {
+		if !known {
+			return []string{fmt.Sprintf("The source account %s could not cover the %s fee on top of its minimum balance. Its pre-transaction balance is not available in the result metadata.",
+				shortAddr(f.source), FormatXLM(fee))}
+		}
+		minBal := minimumBalance(acc)
+		needed := minBal + fee
+		paragraphs = append(paragraphs, fmt.Sprintf("The source account %s had %s but needed %s: a minimum balance of %s for %d subentries plus the %s fee, leaving it %s short.",
+			shortAddr(f.source), FormatXLM(int64(acc.Balance)), FormatXLM(needed),
+			FormatXLM(minBal), acc.NumSubEntries, FormatXLM(fee), FormatXLM(needed-int64(acc.Balance))))
+		return paragraphs
+	}
+
+	if f.result.Result.Code != xdr.TransactionResultCodeTxFailed || f.result.Result.Results == nil {
+		return nil
+	}
+
+	ops := f.env.Operations()
+	for i, opRes := range *f.result.Result.Results {
+		if i >= len(ops) || opRes.Tr == nil {
+			continue
+		}
+		op := ops[i]
+		src := f.source
+		if op.SourceAccount != nil {
+			id := op.SourceAccount.ToAccountId()
+			src = id.Address()
+		}
+
+		var amount int64
+		switch {
+		case opRes.Tr.PaymentResult != nil && opRes.Tr.PaymentResult.Code == xdr.PaymentResultCodePaymentUnderfunded:
+			if op.Body.PaymentOp == nil || op.Body.PaymentOp.Asset.Type != xdr.AssetTypeAssetTypeNative {
+				continue
+			}
+			amount = int64(op.Body.PaymentOp.Amount)
+		case opRes.Tr.CreateAccountResult != nil && opRes.Tr.CreateAccountResult.Code == xdr.CreateAccountResultCodeCreateAccountUnderfunded:
+			amount = int64(op.Body.CreateAccountOp.StartingBalance)
+		default:
+			continue
+		}
+
+		acc, ok := f.accounts[src]
+		if !ok {
+			paragraphs = append(paragraphs, fmt.Sprintf("Operation %d tried to send %s from %s, which did not have enough spendable XLM above its minimum balance.",
+				i+1, FormatXLM(amount), shortAddr(src)))
+			continue
+		}
+		minBal := minimumBalance(acc)
+		spendable := int64(acc.Balance) - minBal
+		if src == f.source {
+			spendable -= fee
+		}
+		paragraphs = append(paragraphs, fmt.Sprintf("Operation %d tried to send %s from %s, which had %s with a minimum balance of %s, so only %s was spendable.",
+			i+1, FormatXLM(amount), shortAddr(src), FormatXLM(int64(acc.Balance)), FormatXLM(minBal), FormatXLM(max(spendable, 0))))
+	}
+
+	return paragraphs
+}
+
+// operationRule names each failed operation and what its result code means
+func operationRule(f *facts) []string {
+	if f.result == nil || f.result.Result.Code != xdr.TransactionResultCodeTxFailed || f.result.Result.Results == nil {
+		return nil
+	}
+
+	var ops []xdr.Operation
+	if f.env != nil {
+		ops = f.env.Operations()
+	}
+
+	var paragraphs []string
+	for i, opRes := range *f.result.Result.Results {
+		opName := "operation"
+		if i < len(ops) {
+			opName = decoder.SnakeCase(strings.TrimPrefix(ops[i].Body.Type.String(), "OperationType"))
+		}
+
+		if opRes.Code != xdr.OperationResultCodeOpInner {
+			info := decoder.DecodeOperationResultCode(opRes.Code)
+			paragraphs = append(paragraphs, fmt.Sprintf("Operation %d (%s) failed with %s: %s.", i+1, opName, info.Code, info.Explanation))
+			continue
+		}
+
+		code, explanation, failed := innerResult(opRes.Tr)
+		if !failed {
+			continue
+		}
+		if explanation == "" {
+			paragraphs = append(paragraphs, fmt.Sprintf("Operation %d (%s) failed with %s.", i+1, opName, code))
+		} else {
+			paragraphs = append(paragraphs, fmt.Sprintf("Operation %d (%s) failed with %s: %s.", i+1, opName, code, explanation))
+		}
+	}
+	return paragraphs
+}
+
+// innerResult reports the operation-specific code and whether it is a failure
+func innerResult(tr *xdr.OperationResultTr) (string, string, bool) {
+	if tr == nil {
+		return "", "", false
+	}
+
+	switch {
+	case tr.PaymentResult != nil:
+		info := decoder.DecodePaymentResultCode(tr.PaymentResult.Code)
+		return info.Code, info.Explanation, tr.PaymentResult.Code != xdr.PaymentResultCodePaymentSuccess
+	case tr.CreateAccountResult != nil:
+		info := decoder.DecodeCreateAccountResultCode(tr.CreateAccountResult.Code)
+		return info.Code, info.Explanation, tr.CreateAccountResult.Code != xdr.CreateAccountResultCodeCreateAccountSuccess
+	case tr.InvokeHostFunctionResult != nil:
+		code := tr.InvokeHostFunctionResult.Code
+		return resultCodeName(code.String()), invokeExplanations[code], code != xdr.InvokeHostFunctionResultCodeInvokeHostFunctionSuccess
+	case tr.ExtendFootprintTtlResult != nil:
+		code := tr.ExtendFootprintTtlResult.Code
+		return resultCodeName(code.String()), "", code != xdr.ExtendFootprintTtlResultCodeExtendFootprintTtlSuccess
+	case tr.RestoreFootprintResult != nil:
+		code := tr.RestoreFootprintResult.Code
+		return resultCodeName(code.String()), "", code != xdr.RestoreFootprintResultCodeRestoreFootprintSuccess
+	}
+	return "", "", false
+}
+
+// resultCodeName turns a generated enum name such as
+// InvokeHostFunctionResultCodeInvokeHostFunctionTrapped into the protocol
+// spelling invoke_host_function_trapped
+func resultCodeName(name string) string {
+	if i := strings.Index(name, "ResultCode"); i >= 0 {
+		name = name[i+len("ResultCode"):]
+	}
+	return decoder.SnakeCase(name)
+}
+
+var invokeExplanations = map[xdr.InvokeHostFunctionResultCode]string{
+	xdr.InvokeHostFunctionResultCodeInvokeHostFunctionMalformed:                 "the host function invocation was malformed",
+	xdr.InvokeHostFunctionResultCodeInvokeHostFunctionTrapped:                   "the contract trapped, either by panicking, returning an error or failing an auth check",
+	xdr.InvokeHostFunctionResultCodeInvokeHostFunctionResourceLimitExceeded:     "execution exceeded the CPU, memory or I/O resources declared in the transaction",
+	xdr.InvokeHostFunctionResultCodeInvokeHostFunctionEntryArchived:             "the footprint references a ledger entry that is archived and must be restored first",
+	xdr.InvokeHostFunctionResultCodeInvokeHostFunctionInsufficientRefundableFee: "the refundable fee did not cover rent and event costs",
+}
+
+// contractErrorRule surfaces errors raised by contracts in diagnostic events
+func contractErrorRule(f *facts) []string {
+	sim := f.in.Simulation
+	if sim == nil {
+		return nil
+	}
+
+	var paragraphs []string
+	for _, ev := range sim.DiagnosticEvents {
+		if len(ev.Topics) == 0 || !strings.Contains(strings.ToLower(ev.Topics[0]), "error") {
+			continue
+		}
+		who := "The host"
+		if ev.ContractID != nil && *ev.ContractID != "" {
+			who = "Contract " + shortAddr(*ev.ContractID)
+		}
+		detail := strings.Join(ev.Topics[1:], ", ")
+		if detail == "" {
+			detail = ev.Topics[0]
+		}
+		p := fmt.Sprintf("%s raised %s", who, detail)
+		if ev.Data != "" {
+			p += fmt.Sprintf(" with data %s", ev.Data)
+		}
+		paragraphs = append(paragraphs, p+".")
+
+		// The first error is the root cause; later ones are usually propagation
+		break
+	}
+	return paragraphs
+}
+
+// deployRule diagnoses failed WASM uploads and contract creations: code over
+// the size limit, an address already taken by an earlier deployment with the
+// same deployer and salt, and creations referencing code never uploaded
+func deployRule(f *facts) []string {
+	if f.in.EnvelopeXdr == "" || !f.failed() {
+		return nil
+	}
+	a, err := deploy.Analyze(f.in.EnvelopeXdr, f.in.NetworkPassphrase)
+	if err != nil || a.Empty() {
+		return nil
+	}
+
+	errText := strings.ToLower(f.errorText())
+	var paragraphs []string
+	for _, u := range a.Uploads {
+		if u.Size > deploy.DefaultMaxWasmSize {
+			paragraphs = append(paragraphs, fmt.Sprintf("Operation %d uploads a %d-byte WASM, above the usual %d-byte contract size limit. Build with optimizations (e.g. stellar contract optimize) or split the contract.",
+				u.OpIndex+1, u.Size, deploy.DefaultMaxWasmSize))
+		}
+	}
+	for _, c := range a.Creations {
+		origin := "asset " + c.Asset
+		if c.Deployer != "" {
+			origin = fmt.Sprintf("deployer %s and salt %s", shortAddr(c.Deployer), shortAddr(c.Salt))
+		}
+		switch {
+		case strings.Contains(errText, "existingvalue") || strings.Contains(errText, "already exists"):
+			paragraphs = append(paragraphs, fmt.Sprintf("Operation %d tried to create contract %s, derived from %s, but that contract already exists. The same deployer and salt always produce the same address, so deploy with a new salt.",
+				c.OpIndex+1, shortAddr(c.ContractID), origin))
+		case c.WasmHash != "" && !c.UploadedInTx && strings.Contains(errText, "missingvalue"):
+			paragraphs = append(paragraphs, fmt.Sprintf("Operation %d creates contract %s from WASM %s, which is not installed on the network. Upload the WASM before creating the contract.",
+				c.OpIndex+1, shortAddr(c.ContractID), shortAddr(c.WasmHash)))
+		}
+	}
+	return paragraphs
+}
+
+// failed reports whether the transaction or its simulated replay failed
+func (f *facts) failed() bool {
+	if f.result != nil {
+		code := f.result.Result.Code
+		return code != xdr.TransactionResultCodeTxSuccess && code != xdr.TransactionResultCodeTxFeeBumpInnerSuccess
+	}
+	return f.in.Simulation != nil && f.in.Simulation.Status != "success"
+}
+
+// errorText joins the simulator error with the topics and data of every
+// diagnostic event, for matching host error codes
+func (f *facts) errorText() string {
+	sim := f.in.Simulation
+	if sim == nil {
+		return ""
+	}
+	parts := []string{sim.Error}
+	for _, ev := range sim.DiagnosticEvents {
+		parts = append(parts, ev.Topics...)
+		parts = append(parts, ev.Data)
+	}
+	return strings.Join(parts, " ")
+}
+
+// budgetRule calls out resource exhaustion with concrete usage figures
+func budgetRule(f *facts) []string {
+	sim := f.in.Simulation
+	if sim == nil || sim.BudgetUsage == nil {
+		return nil
+	}
+	b := sim.BudgetUsage
+
+	var paragraphs []string
+	describe := func(kind string, used, limit uint64, pct float64, unit string) {
+		switch {
+		case limit > 0 && used >= limit:
+			paragraphs = append(paragraphs, fmt.Sprintf("The contract exhausted its %s budget: it used %d of %d %s (%.1f%%). Raise the declared resource limit or reduce the work done per call.",
+				kind, used, limit, unit, pct))
+		case pct >= 90:
+			paragraphs = append(paragraphs, fmt.Sprintf("%s usage was close to the limit at %d of %d %s (%.1f%%), so small input changes may make this call fail.",
+				strings.ToUpper(kind[:1])+kind[1:], used, limit, unit, pct))
+		}
+	}
+	describe("CPU", b.CPUInstructions, b.CPULimit, b.CPUUsagePercent, "instructions")
+	describe("memory", b.MemoryBytes, b.MemoryLimit, b.MemoryUsagePercent, "bytes")
+	return paragraphs
+}
+
+func findingsRule(f *facts) []string {
+	var verified []string
+	for _, finding := range f.in.Findings {
+		if finding.Type == security.FindingVerifiedRisk {
+			verified = append(verified, finding.Title)
+		}
+	}
+	if len(verified) == 0 {
+		return nil
+	}
+
+	noun := "risk"
+	if len(verified) > 1 {
+		noun = "risks"
+	}
+	return []string{fmt.Sprintf("Security analysis also verified %d %s: %s.", len(verified), noun, strings.Join(verified, "; "))}
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package explain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSource = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func encode(t *testing.T, v interface{}) string {
+	t.Helper()
+	s, err := xdr.MarshalBase64(v)
+	require.NoError(t, err)
+	return s
+}
+
+func paymentEnvelope(t *testing.T, amount int64) string {
+	t.Helper()
+	src := xdr.MustMuxedAddress(testSource)
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{
+			Tx: xdr.Transaction{
+				SourceAccount: src,
+				Fee:           100,
+				Operations: []xdr.Operation{{
+					Body: xdr.OperationBody{
+						Type: xdr.OperationTypePayment,
+						PaymentOp: &xdr.PaymentOp{
+							Destination: src,
+							Asset:       xdr.MustNewNativeAsset(),
+							Amount:      xdr.Int64(amount),
+						},
+					},
+				}},
+			},
+		},
+	}
+	return encode(t, env)
+}
+
+func accountMeta(t *testing.T, balance int64, subentries uint32) string {
+	t.Helper()
+	noResults := []xdr.OperationResult{}
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{
+			Result: xdr.TransactionResult{
+				Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &noResults},
+			},
+		},
+		FeeProcessing: xdr.LedgerEntryChanges{{
+			Type: xdr.LedgerEntryChangeTypeLedgerEntryState,
+			State: &xdr.LedgerEntry{
+				Data: xdr.LedgerEntryData{
+					Type: xdr.LedgerEntryTypeAccount,
+					Account: &xdr.AccountEntry{
+						AccountId:     xdr.MustAddress(testSource),
+						Balance:       xdr.Int64(balance),
+						NumSubEntries: xdr.Uint32(subentries),
+					},
+				},
+			},
+		}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}},
+	}
+	return encode(t, meta)
+}
+
+func TestExplain_InsufficientBalance(t *testing.T) {
+	result := xdr.TransactionResult{
+		FeeCharged: 100,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxInsufficientBalance},
+	}
+
+	paragraphs := Explain(Input{
+		EnvelopeXdr:   paymentEnvelope(t, 10),
+		ResultXdr:     encode(t, result),
+		ResultMetaXdr: accountMeta(t, 32_000_000, 6),
+	})
+	require.Len(t, paragraphs, 2)
+
+	assert.Contains(t, paragraphs[0], "tx_insufficient_balance")
+	// (2 + 6) * 0.5 XLM reserve + 0.00001 XLM fee
+	assert.Contains(t, paragraphs[1], "had 3.2 XLM but needed 4.00001 XLM")
+	assert.Contains(t, paragraphs[1], "6 subentries")
+}
+
+func TestExplain_V4Meta(t *testing.T) {
+	// The account is only recorded before the transaction's own changes
+	var meta xdr.TransactionResultMeta
+	require.NoError(t, xdr.SafeUnmarshalBase64(accountMeta(t, 32_000_000, 6), &meta))
+	meta.TxApplyProcessing = xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{TxChangesBefore: meta.FeeProcessing}}
+	meta.FeeProcessing = nil
+
+	paragraphs := Explain(Input{
+		EnvelopeXdr:   paymentEnvelope(t, 10),
+		ResultXdr:     encode(t, xdr.TransactionResult{FeeCharged: 100, Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxInsufficientBalance}}),
+		ResultMetaXdr: encode(t, meta),
+	})
+	require.Len(t, paragraphs, 2)
+	assert.Contains(t, paragraphs[1], "had 3.2 XLM but needed 4.00001 XLM")
+}
+
+func TestExplain_PaymentUnderfunded(t *testing.T) {
+	opResults := []xdr.OperationResult{{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type:          xdr.OperationTypePayment,
+			PaymentResult: &xdr.PaymentResult{Code: xdr.PaymentResultCodePaymentUnderfunded},
+		},
+	}}
+	result := xdr.TransactionResult{
+		FeeCharged: 100,
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxFailed,
+			Results: &opResults,
+		},
+	}
+
+	paragraphs := Explain(Input{
+		EnvelopeXdr:   paymentEnvelope(t, 50_000_000),
+		ResultXdr:     encode(t, result),
+		ResultMetaXdr: accountMeta(t, 30_000_000, 0),
+	})
+
+	text := strings.Join(paragraphs, "\n")
+	assert.Contains(t, text, "tried to send 5.0 XLM")
+	assert.Contains(t, text, "minimum balance of 1.0 XLM")
+	assert.Contains(t, text, "only 1.99999 XLM was spendable")
+	assert.Contains(t, text, "Operation 1 (payment) failed with payment_underfunded")
+}
+
+func TestExplain_SimulationOnly(t *testing.T) {
+	contract := "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	paragraphs := Explain(Input{
+		Simulation: &simulator.SimulationResponse{
+			Status: "error",
+			Error:  "HostError: Error(Contract, #3)",
+			DiagnosticEvents: []simulator.DiagnosticEvent{
+				{EventType: "diagnostic", Topics: []string{"fn_call"}},
+				{EventType: "diagnostic", ContractID: &contract, Topics: []string{"error", "Error(Contract, #3)"}, Data: "\"insufficient allowance\""},
+			},
+			BudgetUsage: &simulator.BudgetUsage{
+				CPUInstructions: 100_000_000,
+				CPULimit:        100_000_000,
+				CPUUsagePercent: 100,
+			},
+		},
+		Findings: []security.Finding{
+			{Type: security.FindingVerifiedRisk, Title: "Integer overflow"},
+			{Type: security.FindingHeuristicWarn, Title: "Ignored"},
+		},
+	})
+
+	require.Len(t, paragraphs, 4)
+	assert.Equal(t, "The simulated replay failed: HostError: Error(Contract, #3).", paragraphs[0])
+	assert.Contains(t, paragraphs[1], "Contract CDLZ...CYSC raised Error(Contract, #3)")
+	assert.Contains(t, paragraphs[2], "exhausted its CPU budget")
+	assert.Equal(t, "Security analysis also verified 1 risk: Integer overflow.", paragraphs[3])
+}
+
+func TestExplain_DuplicateDeployment(t *testing.T) {
+	deployer := xdr.MustAddress(testSource)
+	hash := xdr.Hash{1}
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(testSource),
+			Fee:           100,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+					Type: xdr.HostFunctionTypeHostFunctionTypeCreateContract,
+					CreateContract: &xdr.CreateContractArgs{
+						ContractIdPreimage: xdr.ContractIdPreimage{
+							Type: xdr.ContractIdPreimageTypeContractIdPreimageFromAddress,
+							FromAddress: &xdr.ContractIdPreimageFromAddress{
+								Address: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &deployer},
+							},
+						},
+						Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &hash},
+					},
+				}},
+			}}},
+		}},
+	}
+
+	paragraphs := Explain(Input{
+		EnvelopeXdr:       encode(t, env),
+		Simulation:        &simulator.SimulationResponse{Status: "error", Error: "HostError: Error(Storage, ExistingValue)"},
+		NetworkPassphrase: "Test SDF Network ; September 2015",
+	})
+
+	require.Len(t, paragraphs, 2)
+	assert.Contains(t, paragraphs[1], "Operation 1 tried to create contract C")
+	assert.Contains(t, paragraphs[1], "already exists")
+	assert.Contains(t, paragraphs[1], "deploy with a new salt")
+}
+
+func TestExplain_Deterministic(t *testing.T) {
+	in := Input{EnvelopeXdr: paymentEnvelope(t, 1), Simulation: &simulator.SimulationResponse{Status: "success"}}
+	assert.Equal(t, Explain(in), Explain(in))
+	assert.Equal(t, []string{"The simulated replay completed successfully."}, Explain(in))
+}
+
+func TestResultCodeName(t *testing.T) {
+	assert.Equal(t, "invoke_host_function_trapped", resultCodeName(xdr.InvokeHostFunctionResultCodeInvokeHostFunctionTrapped.String()))
+	assert.Equal(t, "extend_footprint_ttl_malformed", resultCodeName(xdr.ExtendFootprintTtlResultCodeExtendFootprintTtlMalformed.String()))
+}
+
+func TestFormatXLM(t *testing.T) {
+	assert.Equal(t, "3.2 XLM", FormatXLM(32_000_000))
+	assert.Equal(t, "0.0000001 XLM", FormatXLM(1))
+	assert.Equal(t, "5.0 XLM", FormatXLM(50_000_000))
+	assert.Equal(t, "-1.5 XLM", FormatXLM(-15_000_000))
+}
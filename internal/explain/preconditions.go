@@ -0,0 +1,135 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package explain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// preconditionCodes are the results of a transaction whose preconditions
+// did not hold
+var preconditionCodes = map[xdr.TransactionResultCode]bool{
+	xdr.TransactionResultCodeTxTooEarly:          true,
+	xdr.TransactionResultCodeTxTooLate:           true,
+	xdr.TransactionResultCodeTxBadSeq:            true,
+	xdr.TransactionResultCodeTxBadMinSeqAgeOrGap: true,
+	xdr.TransactionResultCodeTxBadAuth:           true,
+	xdr.TransactionResultCodeTxBadAuthExtra:      true,
+}
+
+// preconditionRule checks the preconditions of a transaction that failed
+// them, or of a simulated one, against the ledger that applied it and the
+// source account's state before it
+func preconditionRule(f *facts) []string {
+	if f.env == nil || (f.result != nil && !preconditionCodes[resultCode(f.result)]) {
+		return nil
+	}
+
+	var paragraphs []string
+	p := decoder.EnvelopePreconditions(*f.env)
+	closeTime, ledger := f.in.LedgerCloseTime, f.in.LedgerSequence
+
+	if p != nil && !closeTime.IsZero() {
+		if p.MinTime != nil && closeTime.Before(*p.MinTime) {
+			paragraphs = append(paragraphs, fmt.Sprintf("The transaction is not valid before %s, but the ledger that applied it closed at %s, %s too early.",
+				formatTime(*p.MinTime), formatTime(closeTime), p.MinTime.Sub(closeTime)))
+		}
+		if p.MaxTime != nil && closeTime.After(*p.MaxTime) {
+			paragraphs = append(paragraphs, fmt.Sprintf("The transaction expired at %s, but the ledger that applied it closed at %s, %s too late.",
+				formatTime(*p.MaxTime), formatTime(closeTime), closeTime.Sub(*p.MaxTime)))
+		}
+	}
+
+	if p != nil && ledger != 0 {
+		if p.MinLedger != 0 && ledger < p.MinLedger {
+			paragraphs = append(paragraphs, fmt.Sprintf("The transaction is not valid before ledger %d, but was applied in ledger %d.", p.MinLedger, ledger))
+		}
+		if p.MaxLedger != 0 && ledger >= p.MaxLedger {
+			paragraphs = append(paragraphs, fmt.Sprintf("The transaction is only valid before ledger %d, but was applied in ledger %d.", p.MaxLedger, ledger))
+		}
+	}
+
+	if acc, known := f.accounts[f.source]; known {
+		paragraphs = append(paragraphs, sequenceViolations(f, p, acc)...)
+	}
+
+	if p != nil {
+		for _, signer := range p.ExtraSigners {
+			if !signedBy(f.env.Signatures(), signer) {
+				paragraphs = append(paragraphs, fmt.Sprintf("The transaction requires a signature from extra signer %s, but none of its signatures match it.", shortAddr(signer)))
+			}
+		}
+	}
+
+	return paragraphs
+}
+
+// sequenceViolations compares the transaction's sequence conditions with
+// the source account as it was before the transaction
+func sequenceViolations(f *facts, p *decoder.Preconditions, acc xdr.AccountEntry) []string {
+	var paragraphs []string
+	txSeq, accSeq := f.env.SeqNum(), int64(acc.SeqNum)
+
+	switch {
+	case p != nil && p.MinSeqNum != nil:
+		if accSeq < *p.MinSeqNum || accSeq >= txSeq {
+			paragraphs = append(paragraphs, fmt.Sprintf("The transaction uses sequence number %d and requires the source account's sequence number to be from %d up to %d, but it was %d.",
+				txSeq, *p.MinSeqNum, txSeq-1, accSeq))
+		}
+	case txSeq != accSeq+1:
+		paragraphs = append(paragraphs, fmt.Sprintf("The transaction uses sequence number %d, but the source account's sequence number was %d, so the next valid one was %d.",
+			txSeq, accSeq, accSeq+1))
+	}
+	if p == nil {
+		return paragraphs
+	}
+
+	if closeTime := f.in.LedgerCloseTime; p.MinSeqAge != 0 && !closeTime.IsZero() {
+		changed := time.Unix(int64(acc.SeqTime()), 0).UTC()
+		if age := closeTime.Sub(changed); age < p.MinSeqAge {
+			paragraphs = append(paragraphs, fmt.Sprintf("The source account's sequence number must be unchanged for at least %s, but it last changed at %s, only %s before the ledger closed.",
+				p.MinSeqAge, formatTime(changed), age))
+		}
+	}
+	if ledger := f.in.LedgerSequence; p.MinSeqLedgerGap != 0 && ledger != 0 {
+		changed := uint32(acc.SeqLedger())
+		if ledger < changed+p.MinSeqLedgerGap {
+			paragraphs = append(paragraphs, fmt.Sprintf("The source account's sequence number must be unchanged for at least %d ledgers, but it last changed in ledger %d, only %d ledgers before ledger %d.",
+				p.MinSeqLedgerGap, changed, ledger-min(changed, ledger), ledger))
+		}
+	}
+	return paragraphs
+}
+
+// signedBy reports whether any signature carries the signer's hint
+func signedBy(sigs []xdr.DecoratedSignature, signer string) bool {
+	var key xdr.SignerKey
+	if err := key.SetAddress(signer); err != nil {
+		return true
+	}
+	hint := decoder.SignerHint(key)
+	for _, sig := range sigs {
+		if sig.Hint == hint {
+			return true
+		}
+	}
+	return false
+}
+
+// resultCode is the code of the transaction, or of the inner transaction of
+// a failed fee bump
+func resultCode(r *xdr.TransactionResult) xdr.TransactionResultCode {
+	if inner, ok := r.Result.GetInnerResultPair(); ok && r.Result.Code == xdr.TransactionResultCodeTxFeeBumpInnerFailed {
+		return inner.Result.Result.Code
+	}
+	return r.Result.Code
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package explain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func preconditionEnvelope(t *testing.T, seq int64, cond xdr.PreconditionsV2) string {
+	t.Helper()
+	return encode(t, xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(testSource),
+			Fee:           100,
+			SeqNum:        xdr.SequenceNumber(seq),
+			Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondV2, V2: &cond},
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type:           xdr.OperationTypeBumpSequence,
+				BumpSequenceOp: &xdr.BumpSequenceOp{BumpTo: 0},
+			}}},
+		}},
+	})
+}
+
+// seqMeta records the source account at sequence number seq, last changed
+// at seqTime in ledger seqLedger
+func seqMeta(t *testing.T, seq int64, seqTime int64, seqLedger uint32) string {
+	t.Helper()
+	acc := xdr.AccountEntry{
+		AccountId: xdr.MustAddress(testSource),
+		Balance:   100_000_000,
+		SeqNum:    xdr.SequenceNumber(seq),
+		Ext: xdr.AccountEntryExt{V: 1, V1: &xdr.AccountEntryExtensionV1{
+			Ext: xdr.AccountEntryExtensionV1Ext{V: 2, V2: &xdr.AccountEntryExtensionV2{
+				Ext: xdr.AccountEntryExtensionV2Ext{V: 3, V3: &xdr.AccountEntryExtensionV3{
+					SeqLedger: xdr.Uint32(seqLedger),
+					SeqTime:   xdr.TimePoint(seqTime),
+				}},
+			}},
+		}},
+	}
+	noResults := []xdr.OperationResult{}
+	return encode(t, xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{
+			Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &noResults},
+		}},
+		FeeProcessing: xdr.LedgerEntryChanges{{
+			Type:  xdr.LedgerEntryChangeTypeLedgerEntryState,
+			State: &xdr.LedgerEntry{Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeAccount, Account: &acc}},
+		}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{}},
+	})
+}
+
+func failedResult(t *testing.T, code xdr.TransactionResultCode) string {
+	t.Helper()
+	return encode(t, xdr.TransactionResult{FeeCharged: 100, Result: xdr.TransactionResultResult{Code: code}})
+}
+
+func TestPreconditionRule_TimeAndLedgerBounds(t *testing.T) {
+	closed := time.Unix(1700000000, 0).UTC()
+	out := preconditionRule(decode(Input{
+		EnvelopeXdr: preconditionEnvelope(t, 11, xdr.PreconditionsV2{
+			TimeBounds:   &xdr.TimeBounds{MinTime: xdr.TimePoint(closed.Unix() + 30)},
+			LedgerBounds: &xdr.LedgerBounds{MaxLedger: 500},
+		}),
+		ResultXdr:       failedResult(t, xdr.TransactionResultCodeTxTooEarly),
+		ResultMetaXdr:   seqMeta(t, 10, 0, 0),
+		LedgerSequence:  500,
+		LedgerCloseTime: closed,
+	}))
+	require.Len(t, out, 2)
+	assert.Equal(t, "The transaction is not valid before 2023-11-14T22:13:50Z, but the ledger that applied it closed at 2023-11-14T22:13:20Z, 30s too early.", out[0])
+	assert.Equal(t, "The transaction is only valid before ledger 500, but was applied in ledger 500.", out[1])
+}
+
+func TestPreconditionRule_Sequence(t *testing.T) {
+	out := preconditionRule(decode(Input{
+		EnvelopeXdr:   preconditionEnvelope(t, 12, xdr.PreconditionsV2{}),
+		ResultXdr:     failedResult(t, xdr.TransactionResultCodeTxBadSeq),
+		ResultMetaXdr: seqMeta(t, 10, 0, 0),
+	}))
+	assert.Equal(t, []string{"The transaction uses sequence number 12, but the source account's sequence number was 10, so the next valid one was 11."}, out)
+
+	minSeq := xdr.SequenceNumber(8)
+	out = preconditionRule(decode(Input{
+		EnvelopeXdr:   preconditionEnvelope(t, 12, xdr.PreconditionsV2{MinSeqNum: &minSeq}),
+		ResultXdr:     failedResult(t, xdr.TransactionResultCodeTxBadSeq),
+		ResultMetaXdr: seqMeta(t, 10, 0, 0),
+	}))
+	assert.Empty(t, out, "a sequence number from the minimum up to the transaction's is valid")
+}
+
+func TestPreconditionRule_SequenceAgeAndGap(t *testing.T) {
+	closed := time.Unix(1700000000, 0).UTC()
+	out := preconditionRule(decode(Input{
+		EnvelopeXdr:     preconditionEnvelope(t, 11, xdr.PreconditionsV2{MinSeqAge: 120, MinSeqLedgerGap: 10}),
+		ResultXdr:       failedResult(t, xdr.TransactionResultCodeTxBadMinSeqAgeOrGap),
+		ResultMetaXdr:   seqMeta(t, 10, closed.Unix()-60, 995),
+		LedgerSequence:  1000,
+		LedgerCloseTime: closed,
+	}))
+	require.Len(t, out, 2)
+	assert.Contains(t, out[0], "unchanged for at least 2m0s, but it last changed at 2023-11-14T22:12:20Z, only 1m0s before")
+	assert.Contains(t, out[1], "unchanged for at least 10 ledgers, but it last changed in ledger 995, only 5 ledgers before ledger 1000")
+}
+
+func TestPreconditionRule_ExtraSigners(t *testing.T) {
+	var key xdr.SignerKey
+	signer := keypair.MustRandom().Address()
+	require.NoError(t, key.SetAddress(signer))
+
+	out := preconditionRule(decode(Input{
+		EnvelopeXdr:   preconditionEnvelope(t, 11, xdr.PreconditionsV2{ExtraSigners: []xdr.SignerKey{key}}),
+		ResultXdr:     failedResult(t, xdr.TransactionResultCodeTxBadAuth),
+		ResultMetaXdr: seqMeta(t, 10, 0, 0),
+	}))
+	require.Len(t, out, 1)
+	assert.Contains(t, out[0], "requires a signature from extra signer "+shortAddr(signer))
+}
+
+func TestPreconditionRule_SkipsOtherFailures(t *testing.T) {
+	out := preconditionRule(decode(Input{
+		EnvelopeXdr:   preconditionEnvelope(t, 12, xdr.PreconditionsV2{}),
+		ResultXdr:     failedResult(t, xdr.TransactionResultCodeTxInsufficientFee),
+		ResultMetaXdr: seqMeta(t, 10, 0, 0),
+	}))
+	assert.Empty(t, out)
+}
@@ -0,0 +1,198 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package golden writes canonical debug reports to disk and verifies later
+// runs against them, so behavior changes in monitored transactions show up
+// as reviewable diffs.
+package golden
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/compare"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// Scrubbed replaces volatile values in canonical output
+const Scrubbed = "<scrubbed>"
+
+// volatileKeys are dropped from golden output because they change between
+// otherwise identical runs
+var volatileKeys = map[string]bool{
+	"ts":             true,
+	"timestamp":      true,
+	"duration":       true,
+	"duration_ns":    true,
+	"session_id":     true,
+	"erst_version":   true,
+	"last_access_at": true,
+	"flamegraph":     true,
+}
+
+// Simulation is the canonical form of one simulator run
+type Simulation struct {
+	Network         string   `json:"network"`
+	Status          string   `json:"status"`
+	Error           string   `json:"error,omitempty"`
+	Events          []string `json:"events"`
+	CPUInstructions uint64   `json:"cpu_instructions,omitempty"`
+	MemoryBytes     uint64   `json:"memory_bytes,omitempty"`
+}
+
+// Report is the canonical form of a debug or compare run
+type Report struct {
+	TxHash      string             `json:"tx_hash"`
+	Simulations []Simulation       `json:"simulations"`
+	Findings    []security.Finding `json:"findings"`
+}
+
+// NewReport starts a report for a transaction
+func NewReport(txHash string) *Report {
+	return &Report{TxHash: txHash, Simulations: []Simulation{}, Findings: []security.Finding{}}
+}
+
+// AddSimulation records a simulator response for a network
+func (r *Report) AddSimulation(network string, res *simulator.SimulationResponse) {
+	sim := Simulation{Network: network, Events: []string{}}
+	if res != nil {
+		sim.Status = res.Status
+		sim.Error = res.Error
+		if res.Events != nil {
+			sim.Events = res.EventStrings()
+		}
+		if res.BudgetUsage != nil {
+			sim.CPUInstructions = res.BudgetUsage.CPUInstructions
+			sim.MemoryBytes = res.BudgetUsage.MemoryBytes
+		}
+	}
+	r.Simulations = append(r.Simulations, sim)
+}
+
+// AddFindings records security findings
+func (r *Report) AddFindings(findings []security.Finding) {
+	for _, f := range findings {
+		if f.ID == "" {
+			f.ID = security.FindingID(f)
+		}
+		r.Findings = append(r.Findings, f)
+	}
+}
+
+// Canonical renders v as indented JSON with map keys sorted, findings in a
+// stable order and volatile fields scrubbed
+func Canonical(v interface{}) ([]byte, error) {
+	if r, ok := v.(*Report); ok {
+		sorted := *r
+		sorted.Findings = append([]security.Finding(nil), r.Findings...)
+		security.SortFindings(sorted.Findings)
+		v = &sorted
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report: %w", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(scrub(generic)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func scrub(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, val := range t {
+			if volatileKeys[k] || strings.HasSuffix(k, "_at") {
+				t[k] = Scrubbed
+				continue
+			}
+			t[k] = scrub(val)
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = scrub(t[i])
+		}
+	}
+	return v
+}
+
+// Write stores the canonical form of v at path
+func Write(path string, v interface{}) error {
+	data, err := Canonical(v)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create golden directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write golden file: %w", err)
+	}
+	return nil
+}
+
+// Verify compares the canonical form of v with the golden file at path and
+// returns a line diff, empty when they match
+func Verify(path string, v interface{}) ([]string, error) {
+	want, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read golden file: %w", err)
+	}
+	got, err := Canonical(v)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(want, got) {
+		return nil, nil
+	}
+	return Diff(string(want), string(got)), nil
+}
+
+// Diff returns a minimal line diff of a and b, with "-" lines only in a and
+// "+" lines only in b
+func Diff(a, b string) []string {
+	x := strings.Split(strings.TrimSuffix(a, "\n"), "\n")
+	y := strings.Split(strings.TrimSuffix(b, "\n"), "\n")
+
+	lcs := compare.LCSTable(len(x), len(y), func(i, j int) bool { return x[i] == y[j] })
+
+	var out []string
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "- "+x[i])
+			i++
+		default:
+			out = append(out, "+ "+y[j])
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		out = append(out, "- "+x[i])
+	}
+	for ; j < len(y); j++ {
+		out = append(out, "+ "+y[j])
+	}
+	return out
+}
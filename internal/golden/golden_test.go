@@ -0,0 +1,57 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package golden
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleReport(findings ...security.Finding) *Report {
+	r := NewReport("abc")
+	r.AddSimulation("testnet", &simulator.SimulationResponse{
+		Status:      "success",
+		Events:      simulator.NewEvents("transfer"),
+		BudgetUsage: &simulator.BudgetUsage{CPUInstructions: 100},
+	})
+	r.AddFindings(findings)
+	return r
+}
+
+func TestCanonical_StableAndScrubbed(t *testing.T) {
+	a := security.Finding{Severity: security.SeverityHigh, Title: "A"}
+	b := security.Finding{Severity: security.SeverityLow, Title: "B"}
+
+	first, err := Canonical(sampleReport(a, b))
+	require.NoError(t, err)
+	second, err := Canonical(sampleReport(b, a))
+	require.NoError(t, err)
+	assert.Equal(t, string(first), string(second), "finding order must not affect output")
+
+	out, err := Canonical(map[string]interface{}{"created_at": "2025-01-01", "nested": []interface{}{map[string]interface{}{"duration": 5, "keep": 1}}})
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"created_at": "<scrubbed>"`)
+	assert.Contains(t, string(out), `"duration": "<scrubbed>"`)
+	assert.Contains(t, string(out), `"keep": 1`)
+}
+
+func TestWriteVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goldens", "abc.json")
+	require.NoError(t, Write(path, sampleReport()))
+
+	diff, err := Verify(path, sampleReport())
+	require.NoError(t, err)
+	assert.Empty(t, diff)
+
+	changed := sampleReport()
+	changed.Simulations[0].Status = "error"
+	diff, err = Verify(path, changed)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`-       "status": "success"`, `+       "status": "error"`}, diff)
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package hooks runs user scripts at fixed points of erst commands, so
+// notifications, ticket creation or archival can be added without waiting
+// for built-in integrations.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a hook run when none is configured
+const DefaultTimeout = 60 * time.Second
+
+// Event is a point at which hooks run
+type Event string
+
+const (
+	// PreDebug runs before erst debug fetches the transaction. A failing
+	// pre-debug hook stops the command.
+	PreDebug Event = "pre-debug"
+	// PostDebug runs after erst debug created its session
+	PostDebug Event = "post-debug"
+	// PostCompare runs after a comparison: erst debug --compare-network,
+	// following PostDebug, erst compare-sim and erst session runs diff
+	PostCompare Event = "post-compare"
+)
+
+// Events lists the known events
+var Events = []Event{PreDebug, PostDebug, PostCompare}
+
+// ParseEvent returns the event called name
+func ParseEvent(name string) (Event, error) {
+	for _, e := range Events {
+		if string(e) == name {
+			return e, nil
+		}
+	}
+	names := make([]string, len(Events))
+	for i, e := range Events {
+		names[i] = string(e)
+	}
+	return "", fmt.Errorf("unknown hook event %q (use %s)", name, strings.Join(names, ", "))
+}
+
+// Pre reports whether e runs before the work of its command
+func (e Event) Pre() bool {
+	return strings.HasPrefix(string(e), "pre-")
+}
+
+// Hook is a command run at an event
+type Hook struct {
+	Event   Event
+	Command string
+	Args    []string
+	// Timeout bounds a run; zero means DefaultTimeout
+	Timeout time.Duration
+}
+
+// Payload describes the run a hook is called for. It is passed as JSON on
+// stdin, and its fields also as ERST_* environment variables.
+type Payload struct {
+	Event          Event  `json:"event"`
+	TxHash         string `json:"tx_hash,omitempty"`
+	Network        string `json:"network,omitempty"`
+	CompareNetwork string `json:"compare_network,omitempty"`
+	SessionID      string `json:"session_id,omitempty"`
+	// SessionFile is a JSON copy of the session, removed after the hooks ran
+	SessionFile string `json:"session_file,omitempty"`
+	// ReportPath is the main report of the run: the trace or artifact index
+	// after debug, the comparison report after compare
+	ReportPath string `json:"report_path,omitempty"`
+	OutputDir  string `json:"output_dir,omitempty"`
+}
+
+// Environ returns the environment variables describing p
+func (p Payload) Environ() []string {
+	var env []string
+	for _, v := range []struct{ name, value string }{
+		{"ERST_HOOK_EVENT", string(p.Event)},
+		{"ERST_TX_HASH", p.TxHash},
+		{"ERST_NETWORK", p.Network},
+		{"ERST_COMPARE_NETWORK", p.CompareNetwork},
+		{"ERST_SESSION_ID", p.SessionID},
+		{"ERST_SESSION_FILE", p.SessionFile},
+		{"ERST_REPORT_PATH", p.ReportPath},
+		{"ERST_OUTPUT_DIR", p.OutputDir},
+	} {
+		if v.value != "" {
+			env = append(env, v.name+"="+v.value)
+		}
+	}
+	return env
+}
+
+// Validate checks the event and command of h
+func (h Hook) Validate() error {
+	if _, err := ParseEvent(string(h.Event)); err != nil {
+		return err
+	}
+	if strings.TrimSpace(h.Command) == "" {
+		return fmt.Errorf("%s hook has no command", h.Event)
+	}
+	return nil
+}
+
+// Run runs h with p, writing its output to out
+func Run(ctx context.Context, h Hook, p Payload, out io.Writer) error {
+	if err := h.Validate(); err != nil {
+		return err
+	}
+	input, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook payload: %w", err)
+	}
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.Command, h.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.Env = append(os.Environ(), p.Environ()...)
+	// Children of the hook may hold its output open after it is killed
+	cmd.WaitDelay = 2 * time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%s hook %s timed out after %s", h.Event, h.Command, timeout)
+		}
+		return fmt.Errorf("%s hook %s failed: %w", h.Event, h.Command, err)
+	}
+	return nil
+}
+
+// RunAll runs the hooks for p.Event in order. Before a command, the first
+// failure stops the remaining hooks and is returned; after it, every hook
+// runs and all failures are returned.
+func RunAll(ctx context.Context, hooks []Hook, p Payload, out io.Writer) error {
+	var errs []error
+	for _, h := range hooks {
+		if h.Event != p.Event {
+			continue
+		}
+		if err := Run(ctx, h, p, out); err != nil {
+			if p.Event.Pre() {
+				return err
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
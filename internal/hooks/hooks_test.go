@@ -0,0 +1,107 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func script(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script hooks")
+	}
+	path := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseEvent(t *testing.T) {
+	for _, e := range Events {
+		if got, err := ParseEvent(string(e)); err != nil || got != e {
+			t.Errorf("ParseEvent(%q) = %q, %v", e, got, err)
+		}
+	}
+	if _, err := ParseEvent("post-everything"); err == nil || !strings.Contains(err.Error(), "pre-debug, post-debug, post-compare") {
+		t.Errorf("expected the known events in the error, got %v", err)
+	}
+	if !PreDebug.Pre() || PostDebug.Pre() {
+		t.Error("only pre-debug runs before its command")
+	}
+}
+
+func TestRun(t *testing.T) {
+	path := script(t, `cat
+echo
+echo "$ERST_HOOK_EVENT $ERST_TX_HASH $ERST_SESSION_FILE"
+`)
+	var out bytes.Buffer
+	p := Payload{Event: PostDebug, TxHash: "abc", SessionFile: "/tmp/s.json"}
+	if err := Run(context.Background(), Hook{Event: PostDebug, Command: path}, p, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var got Payload
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil || got != p {
+		t.Errorf("unexpected payload %q: %v", lines[0], err)
+	}
+	if lines[1] != "post-debug abc /tmp/s.json" {
+		t.Errorf("unexpected environment %q", lines[1])
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	path := script(t, "exec sleep 5\n")
+	err := Run(context.Background(), Hook{Event: PostDebug, Command: path, Timeout: 50 * time.Millisecond}, Payload{Event: PostDebug}, &bytes.Buffer{})
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestRunAll(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "log")
+	ok := script(t, "echo \"$ERST_HOOK_EVENT\" >> "+log+"\n")
+	fail := script(t, "exit 1\n")
+
+	hs := []Hook{
+		{Event: PreDebug, Command: fail},
+		{Event: PreDebug, Command: ok},
+		{Event: PostDebug, Command: fail},
+		{Event: PostDebug, Command: ok},
+		{Event: PostCompare, Command: ok},
+	}
+	if err := RunAll(context.Background(), hs, Payload{Event: PreDebug}, &bytes.Buffer{}); err == nil {
+		t.Error("expected the failing pre-debug hook to fail")
+	}
+	if err := RunAll(context.Background(), hs, Payload{Event: PostDebug}, &bytes.Buffer{}); err == nil {
+		t.Error("expected the failing post-debug hook to be reported")
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "post-debug\n" {
+		t.Errorf("pre-debug should stop at the first failure and post-debug run every hook, got %q", data)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := (Hook{Event: "on-crash", Command: "x"}).Validate(); err == nil {
+		t.Error("expected an unknown event to be rejected")
+	}
+	if err := (Hook{Event: PostDebug, Command: " "}).Validate(); err == nil {
+		t.Error("expected a missing command to be rejected")
+	}
+}
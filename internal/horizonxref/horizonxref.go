@@ -0,0 +1,368 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package horizonxref cross-references Horizon's record of a transaction,
+// its operations and effects, with erst's own decoding, simulation and
+// token flow analysis, so that disagreements between the two surface
+// ingestion bugs and misreadings on either side.
+package horizonxref
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/tokenflow"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/base"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/effects"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/operations"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Discrepancy kinds
+const (
+	KindMissing         = "missing"
+	KindStatus          = "status"
+	KindOperationCount  = "operation-count"
+	KindOperationType   = "operation-type"
+	KindOperationSource = "operation-source"
+	KindBalance         = "balance"
+)
+
+// nativeAsset names native XLM in Effect.Asset and in balance comparisons
+const nativeAsset = "native"
+
+// Operation is Horizon's record of one operation
+type Operation struct {
+	Index      int    `json:"index"`
+	ID         string `json:"id"`
+	Type       string `json:"type"`
+	Source     string `json:"source_account"`
+	Successful bool   `json:"transaction_successful"`
+	typeI      int32
+}
+
+// Effect is one effect Horizon recorded. Holder, Asset and Amount are set
+// for balance effects: Asset is "native" or CODE:ISSUER and Amount is a
+// decimal with seven places.
+type Effect struct {
+	OpIndex int    `json:"op_index"`
+	Type    string `json:"type"`
+	Holder  string `json:"holder"`
+	Asset   string `json:"asset,omitempty"`
+	Amount  string `json:"amount,omitempty"`
+	// debit is set for account_debited and contract_debited effects
+	debit bool
+	asset base.Asset
+}
+
+// Discrepancy is a disagreement between Horizon and erst. OpIndex is -1
+// when it concerns the whole transaction.
+type Discrepancy struct {
+	Kind    string `json:"kind"`
+	OpIndex int    `json:"op_index"`
+	Message string `json:"message"`
+}
+
+// Report is Horizon's record of a transaction and where it disagrees with
+// erst
+type Report struct {
+	Operations    []Operation   `json:"operations"`
+	Effects       []Effect      `json:"effects"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+// Input is erst's own view of the transaction
+type Input struct {
+	EnvelopeXdr string
+	// Simulation is the local replay, nil to skip the status check
+	Simulation *simulator.SimulationResponse
+	// Flows are the token movements erst found, nil to skip the balance
+	// check
+	Flows             *tokenflow.Report
+	NetworkPassphrase string
+}
+
+// Compare builds a report from Horizon's operations and effects of a
+// transaction and checks them against in
+func Compare(ops []operations.Operation, effs []effects.Effect, in Input) (*Report, error) {
+	r := &Report{Operations: []Operation{}, Effects: []Effect{}, Discrepancies: []Discrepancy{}}
+	opIndex := map[int64]int{}
+	for i, op := range ops {
+		b := op.GetBase()
+		r.Operations = append(r.Operations, Operation{
+			Index: i, ID: b.ID, Type: b.Type, Source: b.SourceAccount,
+			Successful: b.TransactionSuccessful, typeI: b.TypeI,
+		})
+		if id, err := strconv.ParseInt(b.ID, 10, 64); err == nil {
+			opIndex[id] = i
+		}
+	}
+	for _, e := range effs {
+		r.Effects = append(r.Effects, newEffect(e, opIndex))
+	}
+
+	env, err := decoder.DecodeEnvelope(in.EnvelopeXdr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	if len(r.Operations) == 0 {
+		r.add(KindMissing, -1, "Horizon has no operations for this transaction; it may not be ingested yet or its history may be pruned")
+		return r, nil
+	}
+	r.checkStatus(in.Simulation)
+	r.checkOperations(*env)
+	if in.Flows != nil && r.Operations[0].Successful {
+		if err := r.checkBalances(in.Flows, in.NetworkPassphrase); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+func (r *Report) add(kind string, op int, format string, args ...interface{}) {
+	r.Discrepancies = append(r.Discrepancies, Discrepancy{Kind: kind, OpIndex: op, Message: fmt.Sprintf(format, args...)})
+}
+
+// newEffect flattens a Horizon effect, attributing it to an operation by
+// the operation ID its own ID starts with
+func newEffect(e effects.Effect, opIndex map[int64]int) Effect {
+	out := Effect{OpIndex: -1, Type: e.GetType(), Holder: e.GetAccount()}
+	if id, _, ok := strings.Cut(e.GetID(), "-"); ok {
+		if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+			if i, ok := opIndex[n]; ok {
+				out.OpIndex = i
+			}
+		}
+	}
+	switch x := e.(type) {
+	case effects.AccountCredited:
+		out.setBalance(x.Asset, x.Amount, false)
+	case effects.AccountDebited:
+		out.setBalance(x.Asset, x.Amount, true)
+	case effects.ContractCredited:
+		out.Holder = x.Contract
+		out.setBalance(x.Asset, x.Amount, false)
+	case effects.ContractDebited:
+		out.Holder = x.Contract
+		out.setBalance(x.Asset, x.Amount, true)
+	}
+	return out
+}
+
+func (e *Effect) setBalance(a base.Asset, amount string, debit bool) {
+	e.asset, e.Amount, e.debit = a, amount, debit
+	if a.Type == nativeAsset {
+		e.Asset = nativeAsset
+		return
+	}
+	e.Asset = a.Code + ":" + a.Issuer
+}
+
+// IsBalance reports whether the effect moves an asset balance
+func (e Effect) IsBalance() bool {
+	return e.Asset != ""
+}
+
+// checkStatus compares Horizon's outcome with the local replay
+func (r *Report) checkStatus(sim *simulator.SimulationResponse) {
+	if sim == nil {
+		return
+	}
+	horizonOK := r.Operations[0].Successful
+	simOK := sim.Status == "success"
+	switch {
+	case horizonOK && !simOK:
+		msg := "Horizon records the transaction as successful but the local simulation failed"
+		if sim.Error != "" {
+			msg += ": " + sim.Error
+		}
+		r.add(KindStatus, -1, "%s", msg)
+	case !horizonOK && simOK:
+		r.add(KindStatus, -1, "Horizon records the transaction as failed but the local simulation succeeded")
+	}
+}
+
+// checkOperations compares Horizon's operations with the envelope's
+func (r *Report) checkOperations(env xdr.TransactionEnvelope) {
+	envOps := env.Operations()
+	if len(envOps) != len(r.Operations) {
+		r.add(KindOperationCount, -1, "Horizon lists %d operation(s) but the envelope has %d", len(r.Operations), len(envOps))
+	}
+	txSource := env.SourceAccount().ToAccountId().Address()
+	for i, op := range r.Operations {
+		if i >= len(envOps) {
+			break
+		}
+		envOp := envOps[i]
+		if op.typeI != int32(envOp.Body.Type) {
+			r.add(KindOperationType, i, "Horizon records operation %d as %s but the envelope has %s", i, op.Type, operations.TypeNames[envOp.Body.Type])
+		}
+		source := txSource
+		if envOp.SourceAccount != nil {
+			source = envOp.SourceAccount.ToAccountId().Address()
+		}
+		if op.Source != "" && op.Source != source {
+			r.add(KindOperationSource, i, "Horizon records the source of operation %d as %s but the envelope has %s", i, op.Source, source)
+		}
+	}
+}
+
+// balanceKey is a holder's balance of one asset
+type balanceKey struct {
+	holder string
+	asset  string
+}
+
+// checkBalances compares the net balance changes of Horizon's effects with
+// those of erst's token flows. Only XLM and the Stellar assets Horizon
+// reports are compared, and only for payments and contract invocations,
+// since erst does not trace other classic operations or non-SAC tokens.
+// Issuers are skipped: their balance of their own asset is not tracked.
+func (r *Report) checkBalances(flows *tokenflow.Report, passphrase string) error {
+	horizon := map[balanceKey]*big.Int{}
+	// assets maps the SAC contract of each asset to its name
+	assets := map[string]string{nativeAsset: nativeAsset}
+	issuers := map[balanceKey]bool{}
+	nativeSAC := ""
+	if passphrase != "" {
+		if id, err := (xdr.Asset{Type: xdr.AssetTypeAssetTypeNative}).ContractID(passphrase); err == nil {
+			nativeSAC, _ = strkey.Encode(strkey.VersionByteContract, id[:])
+		}
+	}
+
+	for _, e := range r.Effects {
+		if !e.IsBalance() || e.OpIndex < 0 {
+			continue
+		}
+		opType := r.Operations[e.OpIndex].Type
+		if opType != "invoke_host_function" && !(opType == "payment" && e.Asset == nativeAsset) {
+			continue
+		}
+		token := nativeAsset
+		if e.Asset != nativeAsset {
+			if passphrase == "" {
+				continue
+			}
+			contract, err := sacAddress(e.asset, passphrase)
+			if err != nil {
+				return err
+			}
+			token = contract
+			assets[contract] = e.Asset
+			issuers[balanceKey{holder: e.asset.Issuer, asset: token}] = true
+		}
+		amt, err := parseAmount(e.Amount)
+		if err != nil {
+			return fmt.Errorf("invalid amount in Horizon %s effect: %w", e.Type, err)
+		}
+		if e.debit {
+			amt.Neg(amt)
+		}
+		addDelta(horizon, balanceKey{holder: decoder.BaseAccount(e.Holder), asset: token}, amt)
+	}
+
+	local := map[balanceKey]*big.Int{}
+	for _, t := range flows.Raw {
+		if t.Amount == nil {
+			continue
+		}
+		token := t.Token.ID
+		if token == "" || token == nativeSAC {
+			token = nativeAsset
+		}
+		if _, ok := assets[token]; !ok {
+			continue
+		}
+		if t.Kind != tokenflow.KindMint {
+			addDelta(local, balanceKey{holder: decoder.BaseAccount(t.From), asset: token}, new(big.Int).Neg(t.Amount))
+		}
+		addDelta(local, balanceKey{holder: decoder.BaseAccount(t.To), asset: token}, t.Amount)
+	}
+
+	keys := map[balanceKey]bool{}
+	for k := range horizon {
+		keys[k] = true
+	}
+	for k := range local {
+		keys[k] = true
+	}
+	sorted := make([]balanceKey, 0, len(keys))
+	for k := range keys {
+		if !issuers[k] {
+			sorted = append(sorted, k)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].asset != sorted[j].asset {
+			return sorted[i].asset < sorted[j].asset
+		}
+		return sorted[i].holder < sorted[j].holder
+	})
+	for _, k := range sorted {
+		h, l := horizon[k], local[k]
+		if h == nil {
+			h = new(big.Int)
+		}
+		if l == nil {
+			l = new(big.Int)
+		}
+		if h.Cmp(l) != 0 {
+			r.add(KindBalance, -1, "Horizon's effects change the %s balance of %s by %s but erst's token flow shows %s",
+				assets[k.asset], k.holder, formatAmount(h), formatAmount(l))
+		}
+	}
+	return nil
+}
+
+func addDelta(m map[balanceKey]*big.Int, k balanceKey, amt *big.Int) {
+	if m[k] == nil {
+		m[k] = new(big.Int)
+	}
+	m[k].Add(m[k], amt)
+	if m[k].Sign() == 0 {
+		delete(m, k)
+	}
+}
+
+// sacAddress returns the Stellar Asset Contract of a Horizon asset
+func sacAddress(a base.Asset, passphrase string) (string, error) {
+	asset, err := xdr.BuildAsset(a.Type, a.Issuer, a.Code)
+	if err != nil {
+		return "", fmt.Errorf("invalid Horizon asset %s:%s: %w", a.Code, a.Issuer, err)
+	}
+	id, err := asset.ContractID(passphrase)
+	if err != nil {
+		return "", fmt.Errorf("failed to derive contract of %s:%s: %w", a.Code, a.Issuer, err)
+	}
+	return strkey.Encode(strkey.VersionByteContract, id[:])
+}
+
+var stroopsPerUnit = big.NewRat(10_000_000, 1)
+
+// parseAmount converts a Horizon amount such as "12.5000000" to stroops
+func parseAmount(s string) (*big.Int, error) {
+	rat, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a number", s)
+	}
+	rat.Mul(rat, stroopsPerUnit)
+	if !rat.IsInt() {
+		return nil, fmt.Errorf("%q has more than seven decimal places", s)
+	}
+	return new(big.Int).Set(rat.Num()), nil
+}
+
+// formatAmount renders stroops as a signed decimal with seven places
+func formatAmount(stroops *big.Int) string {
+	sign := ""
+	if stroops.Sign() > 0 {
+		sign = "+"
+	}
+	return sign + new(big.Rat).SetFrac(stroops, big.NewInt(10_000_000)).FloatString(7)
+}
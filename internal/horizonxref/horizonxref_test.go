@@ -0,0 +1,137 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package horizonxref
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/tokenflow"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/network"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/base"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/effects"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/operations"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	alice = keypair.MustRandom().Address()
+	bob   = keypair.MustRandom().Address()
+)
+
+// paymentEnvelope pays bob 1 XLM from alice
+func paymentEnvelope(t *testing.T) string {
+	t.Helper()
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(alice),
+			Fee:           100,
+			SeqNum:        1,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type:      xdr.OperationTypePayment,
+				PaymentOp: &xdr.PaymentOp{Destination: xdr.MustMuxedAddress(bob), Asset: xdr.MustNewNativeAsset(), Amount: 10_000_000},
+			}}},
+		}},
+	}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return b64
+}
+
+func paymentOp(successful bool) operations.Operation {
+	return operations.Payment{Base: operations.Base{
+		ID: "12884905985", TransactionSuccessful: successful,
+		SourceAccount: alice, Type: "payment", TypeI: int32(xdr.OperationTypePayment),
+	}}
+}
+
+func nativeEffects(amount string) []effects.Effect {
+	native := base.Asset{Type: "native"}
+	return []effects.Effect{
+		effects.AccountCredited{Base: effects.Base{ID: "0012884905985-0000000001", Account: bob, Type: "account_credited"}, Asset: native, Amount: amount},
+		effects.AccountDebited{Base: effects.Base{ID: "0012884905985-0000000002", Account: alice, Type: "account_debited"}, Asset: native, Amount: amount},
+	}
+}
+
+func paymentFlows() *tokenflow.Report {
+	return &tokenflow.Report{Raw: []tokenflow.Transfer{{
+		From: alice, To: bob, Token: tokenflow.Token{Symbol: "XLM"}, Amount: big.NewInt(10_000_000), Kind: tokenflow.KindTransfer,
+	}}}
+}
+
+func TestCompareAgrees(t *testing.T) {
+	r, err := Compare([]operations.Operation{paymentOp(true)}, nativeEffects("1.0000000"), Input{
+		EnvelopeXdr:       paymentEnvelope(t),
+		Simulation:        &simulator.SimulationResponse{Status: "success"},
+		Flows:             paymentFlows(),
+		NetworkPassphrase: network.TestNetworkPassphrase,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, r.Discrepancies)
+	require.Len(t, r.Effects, 2)
+	assert.Equal(t, 0, r.Effects[0].OpIndex)
+	assert.Equal(t, "native", r.Effects[0].Asset)
+	assert.True(t, r.Effects[1].IsBalance())
+}
+
+func TestCompareFlagsBalanceMismatch(t *testing.T) {
+	r, err := Compare([]operations.Operation{paymentOp(true)}, nativeEffects("2.0000000"), Input{
+		EnvelopeXdr:       paymentEnvelope(t),
+		Flows:             paymentFlows(),
+		NetworkPassphrase: network.TestNetworkPassphrase,
+	})
+	require.NoError(t, err)
+	require.Len(t, r.Discrepancies, 2)
+	messages := map[string]bool{}
+	for _, d := range r.Discrepancies {
+		assert.Equal(t, KindBalance, d.Kind)
+		messages[d.Message] = true
+	}
+	assert.True(t, messages["Horizon's effects change the native balance of "+bob+" by +2.0000000 but erst's token flow shows +1.0000000"])
+	assert.True(t, messages["Horizon's effects change the native balance of "+alice+" by -2.0000000 but erst's token flow shows -1.0000000"])
+}
+
+func TestCompareFlagsStatusAndOperations(t *testing.T) {
+	op := operations.CreateAccount{Base: operations.Base{
+		ID: "12884905985", TransactionSuccessful: false,
+		SourceAccount: bob, Type: "create_account", TypeI: int32(xdr.OperationTypeCreateAccount),
+	}}
+	r, err := Compare([]operations.Operation{op, paymentOp(false)}, nil, Input{
+		EnvelopeXdr: paymentEnvelope(t),
+		Simulation:  &simulator.SimulationResponse{Status: "success"},
+		Flows:       paymentFlows(),
+	})
+	require.NoError(t, err)
+
+	kinds := map[string]int{}
+	for _, d := range r.Discrepancies {
+		kinds[d.Kind]++
+	}
+	assert.Equal(t, map[string]int{KindStatus: 1, KindOperationCount: 1, KindOperationType: 1, KindOperationSource: 1}, kinds,
+		"balances of a failed transaction are not compared")
+}
+
+func TestCompareWithoutHorizonRecord(t *testing.T) {
+	r, err := Compare(nil, nil, Input{EnvelopeXdr: paymentEnvelope(t)})
+	require.NoError(t, err)
+	require.Len(t, r.Discrepancies, 1)
+	assert.Equal(t, KindMissing, r.Discrepancies[0].Kind)
+
+	_, err = Compare(nil, nil, Input{EnvelopeXdr: "not xdr"})
+	assert.Error(t, err)
+}
+
+func TestParseAmount(t *testing.T) {
+	n, err := parseAmount("12.5000000")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(125_000_000), n)
+	_, err = parseAmount("0.00000001")
+	assert.Error(t, err)
+	assert.Equal(t, "+0.0000001", formatAmount(big.NewInt(1)))
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package idejson implements the --ide-json machine interface: a stable
+// newline-delimited JSON event stream that editor extensions and wrappers
+// consume instead of parsing human-oriented output.
+//
+// Every line is one Event. A run always begins with a "start" event and
+// ends with either "end" or "error". Fields are only ever added within a
+// protocol version; removals or renames bump Version.
+package idejson
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Version is the protocol version carried in every event
+const Version = 1
+
+// Event types
+const (
+	EventStart    = "start"
+	EventProgress = "progress"
+	EventLog      = "log"
+	EventFinding  = "finding"
+	EventResult   = "result"
+	EventError    = "error"
+	EventEnd      = "end"
+)
+
+// Event is one line of the stream
+type Event struct {
+	V       int         `json:"v"`
+	Seq     int         `json:"seq"`
+	Type    string      `json:"type"`
+	Command string      `json:"command"`
+	Time    time.Time   `json:"ts"`
+	Phase   string      `json:"phase,omitempty"`
+	Level   string      `json:"level,omitempty"`
+	Kind    string      `json:"kind,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Emitter writes events for one command invocation. All methods are safe
+// for concurrent use and are no-ops on a nil Emitter, so call sites do not
+// need to check whether --ide-json is active.
+type Emitter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	command string
+	seq     int
+	now     func() time.Time
+}
+
+// New creates an emitter writing to w
+func New(w io.Writer, command string) *Emitter {
+	return &Emitter{w: w, command: command, now: time.Now}
+}
+
+// Start announces the command and its arguments
+func (e *Emitter) Start(args []string) {
+	e.emit(Event{Type: EventStart, Data: map[string]interface{}{"args": args}})
+}
+
+// Progress reports entry into a phase of a long-running command
+func (e *Emitter) Progress(phase, message string) {
+	e.emit(Event{Type: EventProgress, Phase: phase, Message: message})
+}
+
+// Log forwards a diagnostic message
+func (e *Emitter) Log(level, message string) {
+	e.emit(Event{Type: EventLog, Level: level, Message: message})
+}
+
+// Finding reports one analysis finding as soon as it is known
+func (e *Emitter) Finding(finding interface{}) {
+	e.emit(Event{Type: EventFinding, Data: finding})
+}
+
+// Result reports a named result payload. A command may emit several, e.g.
+// one "simulation" result per network and one "explanation".
+func (e *Emitter) Result(kind string, data interface{}) {
+	e.emit(Event{Type: EventResult, Kind: kind, Data: data})
+}
+
+// Error reports a terminal failure
+func (e *Emitter) Error(err error) {
+	e.emit(Event{Type: EventError, Message: err.Error()})
+}
+
+// End reports successful completion
+func (e *Emitter) End() {
+	e.emit(Event{Type: EventEnd})
+}
+
+func (e *Emitter) emit(ev Event) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.seq++
+	ev.V = Version
+	ev.Seq = e.seq
+	ev.Command = e.command
+	ev.Time = e.now().UTC()
+
+	line, err := json.Marshal(ev)
+	if err != nil {
+		line, _ = json.Marshal(Event{V: Version, Seq: ev.Seq, Type: EventLog, Command: e.command, Time: ev.Time, Level: "error", Message: "failed to encode event: " + err.Error()})
+	}
+	_, _ = e.w.Write(append(line, '\n'))
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package idejson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitter_Stream(t *testing.T) {
+	var buf bytes.Buffer
+	e := New(&buf, "erst debug")
+	e.now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	e.Start([]string{"abc"})
+	e.Progress("fetching", "Fetching transaction")
+	e.Finding(map[string]string{"title": "Unauthorized transfer"})
+	e.Result("simulation", map[string]string{"status": "success"})
+	e.Error(errors.New("boom"))
+
+	var events []Event
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var ev Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &ev), "every line must be a standalone JSON object")
+		events = append(events, ev)
+	}
+
+	require.Len(t, events, 5)
+	types := []string{EventStart, EventProgress, EventFinding, EventResult, EventError}
+	for i, ev := range events {
+		assert.Equal(t, types[i], ev.Type)
+		assert.Equal(t, i+1, ev.Seq)
+		assert.Equal(t, Version, ev.V)
+		assert.Equal(t, "erst debug", ev.Command)
+	}
+	assert.Equal(t, "fetching", events[1].Phase)
+	assert.Equal(t, "simulation", events[3].Kind)
+	assert.Equal(t, "boom", events[4].Message)
+}
+
+func TestEmitter_NilIsNoop(t *testing.T) {
+	var e *Emitter
+	assert.NotPanics(t, func() {
+		e.Start(nil)
+		e.Progress("x", "y")
+		e.End()
+	})
+}
+
+func TestLogHandler_ForwardsRecords(t *testing.T) {
+	var stream, text bytes.Buffer
+	e := New(&stream, "erst debug")
+	log := slog.New(LogHandler(slog.NewTextHandler(&text, &slog.HandlerOptions{Level: slog.LevelInfo}), e))
+
+	log.Debug("hidden")
+	log.With("network", "testnet").Warn("Failed to fetch", "attempt", 2)
+
+	var ev Event
+	require.NoError(t, json.Unmarshal(bytes.TrimSpace(stream.Bytes()), &ev), "expected exactly one event")
+	assert.Equal(t, EventLog, ev.Type)
+	assert.Equal(t, "warn", ev.Level)
+	assert.Equal(t, "Failed to fetch network=testnet attempt=2", ev.Message)
+	assert.Contains(t, text.String(), "Failed to fetch", "records still reach the wrapped handler")
+}
@@ -0,0 +1,52 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package idejson
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// logHandler forwards every record its wrapped handler accepts to an
+// emitter as a log event, after handing it to the wrapped handler
+type logHandler struct {
+	next  slog.Handler
+	e     *Emitter
+	attrs []slog.Attr
+}
+
+// LogHandler wraps next so that log records also reach e as log events,
+// with their attributes appended to the message as key=value pairs
+func LogHandler(next slog.Handler, e *Emitter) slog.Handler {
+	return &logHandler{next: next, e: e}
+}
+
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+	var b strings.Builder
+	b.WriteString(record.Message)
+	write := func(a slog.Attr) bool {
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value)
+		return true
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	record.Attrs(write)
+	h.e.Log(strings.ToLower(record.Level.String()), b.String())
+	return h.next.Handle(ctx, record)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{next: h.next.WithAttrs(attrs), e: h.e, attrs: append(append([]slog.Attr{}, h.attrs...), attrs...)}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{next: h.next.WithGroup(name), e: h.e, attrs: h.attrs}
+}
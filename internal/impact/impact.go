@@ -0,0 +1,217 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package impact compares replays of a contract's recent transactions
+// against its current and a candidate WASM, summarizing which would change
+// status, events or resource usage after an upgrade.
+package impact
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// DefaultResourceThreshold is the relative CPU/memory change treated as a
+// resource change
+const DefaultResourceThreshold = 0.05
+
+// Outcome is one replay result. Err is set when the simulator reported a
+// failed execution.
+type Outcome struct {
+	Response *simulator.SimulationResponse
+	Err      error
+}
+
+// Status returns "success" or "error"
+func (o Outcome) Status() string {
+	if o.Err != nil || o.Response == nil || o.Response.Status == "error" {
+		return "error"
+	}
+	return "success"
+}
+
+// ReplayFunc replays a transaction with the current and upgraded code. A
+// returned error means the transaction could not be replayed at all.
+type ReplayFunc func(ctx context.Context, hash string) (baseline, upgraded Outcome, err error)
+
+// Change describes how one transaction behaves after the upgrade
+type Change struct {
+	Hash            string  `json:"hash"`
+	BaselineStatus  string  `json:"baseline_status,omitempty"`
+	UpgradedStatus  string  `json:"upgraded_status,omitempty"`
+	StatusChanged   bool    `json:"status_changed"`
+	EventsChanged   bool    `json:"events_changed"`
+	ResourceChanged bool    `json:"resource_changed"`
+	CPUDelta        int64   `json:"cpu_delta"`
+	MemDelta        int64   `json:"mem_delta"`
+	CPUChange       float64 `json:"cpu_change"`
+	UpgradedError   string  `json:"upgraded_error,omitempty"`
+	ReplayError     string  `json:"replay_error,omitempty"`
+}
+
+// Changed reports whether the upgrade affects this transaction
+func (c Change) Changed() bool {
+	return c.StatusChanged || c.EventsChanged || c.ResourceChanged
+}
+
+// Report summarizes the impact over all replayed transactions
+type Report struct {
+	Contract        string   `json:"contract"`
+	Total           int      `json:"total"`
+	Replayed        int      `json:"replayed"`
+	Unchanged       int      `json:"unchanged"`
+	StatusChanged   int      `json:"status_changed"`
+	NewFailures     int      `json:"new_failures"`
+	EventsChanged   int      `json:"events_changed"`
+	ResourceChanged int      `json:"resource_changed"`
+	ReplayErrors    int      `json:"replay_errors"`
+	Changes         []Change `json:"changes"`
+}
+
+// Safe reports whether every transaction was replayed and none changed
+// status. A transaction that could not be replayed was not checked.
+func (r *Report) Safe() bool {
+	return r.StatusChanged == 0 && r.ReplayErrors == 0
+}
+
+// Compare classifies the difference between two replays of hash
+func Compare(hash string, baseline, upgraded Outcome, threshold float64) Change {
+	c := Change{
+		Hash:           hash,
+		BaselineStatus: baseline.Status(),
+		UpgradedStatus: upgraded.Status(),
+	}
+	c.StatusChanged = c.BaselineStatus != c.UpgradedStatus
+	if upgraded.Err != nil {
+		c.UpgradedError = upgraded.Err.Error()
+	}
+
+	if baseline.Response != nil && upgraded.Response != nil {
+		c.EventsChanged = !sameEvents(baseline.Response.Events, upgraded.Response.Events)
+
+		if b, u := baseline.Response.BudgetUsage, upgraded.Response.BudgetUsage; b != nil && u != nil {
+			c.CPUDelta = int64(u.CPUInstructions) - int64(b.CPUInstructions)
+			c.MemDelta = int64(u.MemoryBytes) - int64(b.MemoryBytes)
+			c.CPUChange = relative(c.CPUDelta, b.CPUInstructions)
+			memChange := relative(c.MemDelta, b.MemoryBytes)
+			c.ResourceChanged = abs(c.CPUChange) > threshold || abs(memChange) > threshold
+		}
+	}
+
+	return c
+}
+
+// Run replays every hash with up to concurrency workers and summarizes the
+// result. Changes keep the order of hashes.
+func Run(ctx context.Context, contract string, hashes []string, concurrency int, threshold float64, replay ReplayFunc) *Report {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	changes := make([]Change, len(hashes))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, hash := range hashes {
+		wg.Add(1)
+		go func(i int, hash string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				changes[i] = Change{Hash: hash, ReplayError: ctx.Err().Error()}
+				return
+			}
+			baseline, upgraded, err := replay(ctx, hash)
+			if err != nil {
+				changes[i] = Change{Hash: hash, ReplayError: err.Error()}
+				return
+			}
+			changes[i] = Compare(hash, baseline, upgraded, threshold)
+		}(i, hash)
+	}
+	wg.Wait()
+
+	return Summarize(contract, changes)
+}
+
+// Summarize counts the changes
+func Summarize(contract string, changes []Change) *Report {
+	r := &Report{Contract: contract, Total: len(changes), Changes: changes}
+	for _, c := range changes {
+		if c.ReplayError != "" {
+			r.ReplayErrors++
+			continue
+		}
+		r.Replayed++
+		if !c.Changed() {
+			r.Unchanged++
+		}
+		if c.StatusChanged {
+			r.StatusChanged++
+			if c.UpgradedStatus == "error" {
+				r.NewFailures++
+			}
+		}
+		if c.EventsChanged {
+			r.EventsChanged++
+		}
+		if c.ResourceChanged {
+			r.ResourceChanged++
+		}
+	}
+	return r
+}
+
+// SummaryLines renders the report for the terminal
+func (r *Report) SummaryLines() []string {
+	lines := []string{
+		fmt.Sprintf("Transactions analyzed: %d (%d replayed, %d could not be replayed)", r.Total, r.Replayed, r.ReplayErrors),
+		fmt.Sprintf("Unchanged:              %d", r.Unchanged),
+		fmt.Sprintf("Status changed:         %d (%d new failures)", r.StatusChanged, r.NewFailures),
+		fmt.Sprintf("Events changed:         %d", r.EventsChanged),
+		fmt.Sprintf("Resource usage changed: %d", r.ResourceChanged),
+	}
+	return lines
+}
+
+func sameEvents(a, b []simulator.Event) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	x := append([]simulator.Event(nil), a...)
+	y := append([]simulator.Event(nil), b...)
+	byText := func(s []simulator.Event) func(i, j int) bool {
+		return func(i, j int) bool { return s[i].String() < s[j].String() }
+	}
+	sort.Slice(x, byText(x))
+	sort.Slice(y, byText(y))
+	for i := range x {
+		if !x[i].Equal(y[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func relative(delta int64, base uint64) float64 {
+	if base == 0 {
+		if delta == 0 {
+			return 0
+		}
+		return 1
+	}
+	return float64(delta) / float64(base)
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package impact
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ok(cpu uint64, events ...string) Outcome {
+	return Outcome{Response: &simulator.SimulationResponse{
+		Status:      "success",
+		Events:      simulator.NewEvents(events...),
+		BudgetUsage: &simulator.BudgetUsage{CPUInstructions: cpu, MemoryBytes: 1000},
+	}}
+}
+
+func TestCompare(t *testing.T) {
+	c := Compare("a", ok(1000, "x", "y"), ok(1010, "y", "x"), DefaultResourceThreshold)
+	assert.False(t, c.Changed(), "reordered events and a 1% CPU change are not an impact")
+
+	c = Compare("b", ok(1000, "x"), ok(2000, "x", "z"), DefaultResourceThreshold)
+	assert.True(t, c.EventsChanged)
+	assert.True(t, c.ResourceChanged)
+	assert.Equal(t, int64(1000), c.CPUDelta)
+	assert.InDelta(t, 1.0, c.CPUChange, 0.001)
+
+	c = Compare("c", ok(1000), Outcome{Err: errors.New("simulation error: trap")}, DefaultResourceThreshold)
+	assert.True(t, c.StatusChanged)
+	assert.Equal(t, "error", c.UpgradedStatus)
+	assert.Contains(t, c.UpgradedError, "trap")
+}
+
+func TestRun(t *testing.T) {
+	replay := func(ctx context.Context, hash string) (Outcome, Outcome, error) {
+		switch hash {
+		case "same":
+			return ok(100), ok(100), nil
+		case "breaks":
+			return ok(100), Outcome{Err: errors.New("trap")}, nil
+		default:
+			return Outcome{}, Outcome{}, errors.New("not found")
+		}
+	}
+
+	r := Run(context.Background(), "CABC", []string{"same", "breaks", "missing"}, 2, DefaultResourceThreshold, replay)
+	require.Len(t, r.Changes, 3)
+	assert.Equal(t, "missing", r.Changes[2].Hash, "changes keep input order")
+	assert.Equal(t, 2, r.Replayed)
+	assert.Equal(t, 1, r.Unchanged)
+	assert.Equal(t, 1, r.NewFailures)
+	assert.Equal(t, 1, r.ReplayErrors)
+	assert.False(t, r.Safe())
+
+	r = Run(context.Background(), "CABC", []string{"missing"}, 1, DefaultResourceThreshold, replay)
+	assert.Equal(t, 0, r.StatusChanged)
+	assert.False(t, r.Safe(), "a run whose replays all failed checked nothing")
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package intent turns a high-level description of a contract call into an
+// unsigned InvokeHostFunction transaction, so transactions can be authored
+// and dry-run with erst before they are signed and submitted.
+package intent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBaseFee is the inclusion fee in stroops used when the intent sets none
+const DefaultBaseFee = 100
+
+// Intent describes a single contract invocation
+type Intent struct {
+	Network  string `json:"network,omitempty"`
+	Source   string `json:"source"`
+	Contract string `json:"contract"`
+	Function string `json:"function"`
+	Args     []Arg  `json:"args,omitempty"`
+	// Fee is the inclusion fee in stroops; the simulated resource fee is
+	// added on top
+	Fee uint32 `json:"fee,omitempty"`
+	// Sequence is the transaction sequence number; 0 means the next
+	// sequence of the source account
+	Sequence int64  `json:"sequence,omitempty"`
+	Memo     string `json:"memo,omitempty"`
+	// Timeout is the validity window in seconds; 0 leaves the transaction
+	// without time bounds
+	Timeout int64 `json:"timeout,omitempty"`
+}
+
+// Load reads an intent from a YAML or JSON file
+func Load(path string) (*Intent, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read intent file: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a YAML or JSON intent. JSON is valid YAML, so both are read
+// through the YAML decoder and then mapped onto the JSON field names.
+func Parse(data []byte) (*Intent, error) {
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to parse intent: %w", err)
+	}
+	raw, err := json.Marshal(generic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse intent: %w", err)
+	}
+
+	var in Intent
+	if err := json.Unmarshal(raw, &in); err != nil {
+		return nil, fmt.Errorf("failed to parse intent: %w", err)
+	}
+	if err := in.Validate(); err != nil {
+		return nil, err
+	}
+	return &in, nil
+}
+
+// Validate checks the required fields
+func (in *Intent) Validate() error {
+	if in.Source == "" {
+		return fmt.Errorf("intent is missing a source account")
+	}
+	if in.Contract == "" {
+		return fmt.Errorf("intent is missing a contract")
+	}
+	if in.Function == "" {
+		return fmt.Errorf("intent is missing a function")
+	}
+	if len(in.Memo) > 28 {
+		return fmt.Errorf("memo is longer than 28 bytes")
+	}
+	return nil
+}
+
+// Build constructs the unsigned transaction envelope. now anchors the time
+// bounds when the intent has a timeout.
+func (in *Intent) Build(now time.Time) (xdr.TransactionEnvelope, error) {
+	source, err := xdr.AddressToMuxedAccount(in.Source)
+	if err != nil {
+		return xdr.TransactionEnvelope{}, fmt.Errorf("invalid source account: %w", err)
+	}
+	contract, err := parseAddress(in.Contract)
+	if err != nil {
+		return xdr.TransactionEnvelope{}, err
+	}
+	if contract.Type != xdr.ScAddressTypeScAddressTypeContract {
+		return xdr.TransactionEnvelope{}, fmt.Errorf("contract must be a C... address")
+	}
+
+	args := make([]xdr.ScVal, 0, len(in.Args))
+	for i, a := range in.Args {
+		v, err := a.ToScVal()
+		if err != nil {
+			return xdr.TransactionEnvelope{}, fmt.Errorf("args[%d]: %w", i, err)
+		}
+		args = append(args, v)
+	}
+
+	fee := in.Fee
+	if fee == 0 {
+		fee = DefaultBaseFee
+	}
+
+	cond := xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone}
+	if in.Timeout > 0 {
+		cond = xdr.Preconditions{
+			Type: xdr.PreconditionTypePrecondTime,
+			TimeBounds: &xdr.TimeBounds{
+				MinTime: 0,
+				MaxTime: xdr.TimePoint(now.Unix() + in.Timeout),
+			},
+		}
+	}
+
+	memo := xdr.Memo{Type: xdr.MemoTypeMemoNone}
+	if in.Memo != "" {
+		memo, err = xdr.NewMemo(xdr.MemoTypeMemoText, in.Memo)
+		if err != nil {
+			return xdr.TransactionEnvelope{}, fmt.Errorf("invalid memo: %w", err)
+		}
+	}
+
+	op := xdr.Operation{Body: xdr.OperationBody{
+		Type: xdr.OperationTypeInvokeHostFunction,
+		InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+			HostFunction: xdr.HostFunction{
+				Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+				InvokeContract: &xdr.InvokeContractArgs{
+					ContractAddress: contract,
+					FunctionName:    xdr.ScSymbol(in.Function),
+					Args:            args,
+				},
+			},
+		},
+	}}
+
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: source,
+			Fee:           xdr.Uint32(fee),
+			SeqNum:        xdr.SequenceNumber(in.Sequence),
+			Cond:          cond,
+			Memo:          memo,
+			Operations:    []xdr.Operation{op},
+		}},
+	}, nil
+}
+
+// Preflight is the part of a simulation result needed to finish the
+// transaction
+type Preflight struct {
+	// TransactionData is the base64 SorobanTransactionData with the
+	// footprint and resource limits
+	TransactionData string
+	// MinResourceFee is the resource fee in stroops
+	MinResourceFee string
+	// Auth holds base64 SorobanAuthorizationEntry values to attach
+	Auth []string
+}
+
+// Apply attaches the simulated footprint, resources and authorization
+// entries to env, raises its fee by the resource fee and returns the
+// resource fee
+func Apply(env *xdr.TransactionEnvelope, p Preflight) (int64, error) {
+	if env.V1 == nil || len(env.V1.Tx.Operations) != 1 {
+		return 0, fmt.Errorf("expected a transaction with one operation")
+	}
+	tx := &env.V1.Tx
+
+	var data xdr.SorobanTransactionData
+	if err := xdr.SafeUnmarshalBase64(p.TransactionData, &data); err != nil {
+		return 0, fmt.Errorf("failed to decode transaction data: %w", err)
+	}
+
+	resourceFee := int64(data.ResourceFee)
+	if p.MinResourceFee != "" {
+		fee, err := strconv.ParseInt(p.MinResourceFee, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid resource fee %q: %w", p.MinResourceFee, err)
+		}
+		resourceFee = fee
+	}
+	total := int64(tx.Fee) + resourceFee
+	if total > int64(^uint32(0)) {
+		return 0, fmt.Errorf("total fee %d exceeds the maximum transaction fee", total)
+	}
+
+	auth := make([]xdr.SorobanAuthorizationEntry, 0, len(p.Auth))
+	for i, a := range p.Auth {
+		var entry xdr.SorobanAuthorizationEntry
+		if err := xdr.SafeUnmarshalBase64(a, &entry); err != nil {
+			return 0, fmt.Errorf("failed to decode auth entry %d: %w", i, err)
+		}
+		auth = append(auth, entry)
+	}
+
+	tx.Ext = xdr.TransactionExt{V: 1, SorobanData: &data}
+	tx.Fee = xdr.Uint32(total)
+	tx.Operations[0].Body.InvokeHostFunctionOp.Auth = auth
+
+	return resourceFee, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package intent
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSource   = "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"
+	testContract = "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4"
+)
+
+const yamlIntent = `
+network: testnet
+source: ` + testSource + `
+contract: ` + testContract + `
+function: transfer
+args:
+  - {type: address, value: ` + testSource + `}
+  - {type: i128, value: "-5"}
+  - type: vec
+    value:
+      - {type: u32, value: 7}
+      - {type: symbol, value: hello}
+  - type: map
+    value:
+      - key: {type: string, value: k}
+        val: {type: bytes, value: "0xbeef"}
+sequence: 42
+timeout: 60
+memo: test
+`
+
+func TestParseAndBuild(t *testing.T) {
+	in, err := Parse([]byte(yamlIntent))
+	require.NoError(t, err)
+	assert.Equal(t, "transfer", in.Function)
+	require.Len(t, in.Args, 4)
+
+	now := time.Unix(1_700_000_000, 0)
+	env, err := in.Build(now)
+	require.NoError(t, err)
+
+	tx := env.V1.Tx
+	assert.Equal(t, xdr.SequenceNumber(42), tx.SeqNum)
+	assert.Equal(t, xdr.Uint32(DefaultBaseFee), tx.Fee)
+	assert.Equal(t, xdr.TimePoint(now.Unix()+60), tx.Cond.TimeBounds.MaxTime)
+	assert.Equal(t, "test", *tx.Memo.Text)
+
+	call := tx.Operations[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract
+	assert.Equal(t, xdr.ScSymbol("transfer"), call.FunctionName)
+	assert.Equal(t, xdr.ScValTypeScvAddress, call.Args[0].Type)
+	assert.Equal(t, xdr.Int64(-1), call.Args[1].I128.Hi)
+	assert.Equal(t, xdr.Uint64(^uint64(0)-4), call.Args[1].I128.Lo)
+	assert.Len(t, **call.Args[2].Vec, 2)
+	assert.Equal(t, xdr.ScBytes{0xbe, 0xef}, *(**call.Args[3].Map)[0].Val.Bytes)
+
+	_, err = xdr.MarshalBase64(env)
+	require.NoError(t, err)
+}
+
+func TestParse_JSONAndValidation(t *testing.T) {
+	in, err := Parse([]byte(`{"source": "` + testSource + `", "contract": "` + testContract + `", "function": "decimals"}`))
+	require.NoError(t, err)
+	assert.Empty(t, in.Args)
+
+	_, err = Parse([]byte(`{"source": "` + testSource + `", "function": "decimals"}`))
+	assert.ErrorContains(t, err, "contract")
+}
+
+func TestArg_Errors(t *testing.T) {
+	cases := []string{
+		`{"type": "u32", "value": -1}`,
+		`{"type": "i128", "value": "170141183460469231731687303715884105728"}`,
+		`{"type": "bytes", "value": "zz"}`,
+		`{"type": "address", "value": "XYZ"}`,
+		`{"type": "float", "value": 1}`,
+		`{"type": "bool"}`,
+	}
+	for _, c := range cases {
+		var a Arg
+		require.NoError(t, json.Unmarshal([]byte(c), &a))
+		_, err := a.ToScVal()
+		assert.Error(t, err, c)
+	}
+}
+
+func TestApply(t *testing.T) {
+	in := &Intent{Source: testSource, Contract: testContract, Function: "f", Fee: 200}
+	env, err := in.Build(time.Now())
+	require.NoError(t, err)
+
+	data := xdr.SorobanTransactionData{
+		Resources:   xdr.SorobanResources{Instructions: 1000},
+		ResourceFee: 5000,
+	}
+	dataB64, err := xdr.MarshalBase64(data)
+	require.NoError(t, err)
+
+	fee, err := Apply(&env, Preflight{TransactionData: dataB64, MinResourceFee: "6000"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(6000), fee)
+	assert.Equal(t, xdr.Uint32(6200), env.V1.Tx.Fee)
+	assert.Equal(t, int32(1), env.V1.Tx.Ext.V)
+	assert.Equal(t, xdr.Uint32(1000), env.V1.Tx.Ext.SorobanData.Resources.Instructions)
+
+	_, err = Apply(&env, Preflight{TransactionData: dataB64, Auth: []string{"not-xdr"}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,230 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package intent
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Arg is the JSON form of an SCVal argument, e.g.
+//
+//	{"type": "i128", "value": "1000000"}
+//	{"type": "address", "value": "GABC..."}
+//	{"type": "vec", "value": [{"type": "u32", "value": 1}]}
+//	{"type": "map", "value": [{"key": {...}, "val": {...}}]}
+//
+// Supported types are bool, void, u32, i32, u64, i64, u128, i128, symbol,
+// string, bytes (hex), address, vec and map.
+type Arg struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type mapEntry struct {
+	Key Arg `json:"key"`
+	Val Arg `json:"val"`
+}
+
+var (
+	maxU64  = new(big.Int).SetUint64(^uint64(0))
+	two64   = new(big.Int).Lsh(big.NewInt(1), 64)
+	two127  = new(big.Int).Lsh(big.NewInt(1), 127)
+	two128  = new(big.Int).Lsh(big.NewInt(1), 128)
+	negI128 = new(big.Int).Neg(two127)
+)
+
+// ToScVal converts the argument to an XDR SCVal
+func (a Arg) ToScVal() (xdr.ScVal, error) {
+	switch strings.ToLower(a.Type) {
+	case "void":
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	case "bool":
+		var b bool
+		if err := a.decode(&b); err != nil {
+			return xdr.ScVal{}, err
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, nil
+	case "u32":
+		n, err := a.integer(big.NewInt(0), big.NewInt(1<<32-1))
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		v := xdr.Uint32(n.Uint64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}, nil
+	case "i32":
+		n, err := a.integer(big.NewInt(-1<<31), big.NewInt(1<<31-1))
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		v := xdr.Int32(n.Int64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &v}, nil
+	case "u64":
+		n, err := a.integer(big.NewInt(0), maxU64)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		v := xdr.Uint64(n.Uint64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &v}, nil
+	case "i64":
+		n, err := a.integer(big.NewInt(-1<<63), big.NewInt(1<<63-1))
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		v := xdr.Int64(n.Int64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &v}, nil
+	case "u128":
+		n, err := a.integer(big.NewInt(0), new(big.Int).Sub(two128, big.NewInt(1)))
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		hi, lo := split128(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &xdr.UInt128Parts{Hi: xdr.Uint64(hi), Lo: xdr.Uint64(lo)}}, nil
+	case "i128":
+		n, err := a.integer(negI128, new(big.Int).Sub(two127, big.NewInt(1)))
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		hi, lo := split128(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &xdr.Int128Parts{Hi: xdr.Int64(hi), Lo: xdr.Uint64(lo)}}, nil
+	case "symbol":
+		var s string
+		if err := a.decode(&s); err != nil {
+			return xdr.ScVal{}, err
+		}
+		sym := xdr.ScSymbol(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, nil
+	case "string":
+		var s string
+		if err := a.decode(&s); err != nil {
+			return xdr.ScVal{}, err
+		}
+		str := xdr.ScString(s)
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}, nil
+	case "bytes":
+		var s string
+		if err := a.decode(&s); err != nil {
+			return xdr.ScVal{}, err
+		}
+		raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("invalid hex bytes: %w", err)
+		}
+		b := xdr.ScBytes(raw)
+		return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}, nil
+	case "address":
+		var s string
+		if err := a.decode(&s); err != nil {
+			return xdr.ScVal{}, err
+		}
+		addr, err := parseAddress(s)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &addr}, nil
+	case "vec":
+		var items []Arg
+		if err := a.decode(&items); err != nil {
+			return xdr.ScVal{}, err
+		}
+		vec := make(xdr.ScVec, 0, len(items))
+		for i, item := range items {
+			v, err := item.ToScVal()
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("vec[%d]: %w", i, err)
+			}
+			vec = append(vec, v)
+		}
+		p := &vec
+		return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &p}, nil
+	case "map":
+		var entries []mapEntry
+		if err := a.decode(&entries); err != nil {
+			return xdr.ScVal{}, err
+		}
+		m := make(xdr.ScMap, 0, len(entries))
+		for i, e := range entries {
+			k, err := e.Key.ToScVal()
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("map[%d].key: %w", i, err)
+			}
+			v, err := e.Val.ToScVal()
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("map[%d].val: %w", i, err)
+			}
+			m = append(m, xdr.ScMapEntry{Key: k, Val: v})
+		}
+		p := &m
+		return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &p}, nil
+	case "":
+		return xdr.ScVal{}, fmt.Errorf("argument is missing a type")
+	default:
+		return xdr.ScVal{}, fmt.Errorf("unsupported argument type %q", a.Type)
+	}
+}
+
+func (a Arg) decode(v interface{}) error {
+	if len(a.Value) == 0 {
+		return fmt.Errorf("%s argument is missing a value", a.Type)
+	}
+	if err := json.Unmarshal(a.Value, v); err != nil {
+		return fmt.Errorf("invalid %s value %s: %w", a.Type, string(a.Value), err)
+	}
+	return nil
+}
+
+// integer accepts JSON numbers and decimal strings, so values beyond the
+// float64 range can be written exactly
+func (a Arg) integer(min, max *big.Int) (*big.Int, error) {
+	if len(a.Value) == 0 {
+		return nil, fmt.Errorf("%s argument is missing a value", a.Type)
+	}
+	text := strings.Trim(string(a.Value), `"`)
+	n, ok := new(big.Int).SetString(text, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s value %s", a.Type, string(a.Value))
+	}
+	if n.Cmp(min) < 0 || n.Cmp(max) > 0 {
+		return nil, fmt.Errorf("%s value %s is out of range", a.Type, text)
+	}
+	return n, nil
+}
+
+// split128 returns the high and low 64 bits of the two's complement form of n
+func split128(n *big.Int) (uint64, uint64) {
+	x := new(big.Int).Set(n)
+	if x.Sign() < 0 {
+		x.Add(x, two128)
+	}
+	lo := new(big.Int).Mod(x, two64).Uint64()
+	hi := new(big.Int).Rsh(x, 64).Uint64()
+	return hi, lo
+}
+
+func parseAddress(s string) (xdr.ScAddress, error) {
+	switch {
+	case strings.HasPrefix(s, "G"):
+		id, err := xdr.AddressToAccountId(s)
+		if err != nil {
+			return xdr.ScAddress{}, fmt.Errorf("invalid account address: %w", err)
+		}
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &id}, nil
+	case strings.HasPrefix(s, "C"):
+		raw, err := strkey.Decode(strkey.VersionByteContract, s)
+		if err != nil {
+			return xdr.ScAddress{}, fmt.Errorf("invalid contract address: %w", err)
+		}
+		var id xdr.ContractId
+		copy(id[:], raw)
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}, nil
+	default:
+		return xdr.ScAddress{}, fmt.Errorf("unsupported address %q", s)
+	}
+}
@@ -0,0 +1,353 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ledgerreplay re-simulates the Soroban transactions of a ledger
+// against the state each one was applied on and compares the simulated
+// outcome, return value and contract events with the on-chain result, as a
+// check of simulator fidelity.
+package ledgerreplay
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Replay outcomes
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// FetchFunc returns the current value of ledger entries by base64 key, for
+// footprint entries the transaction meta does not carry
+type FetchFunc func(ctx context.Context, keys []string) (map[string]string, error)
+
+// Result is the outcome of replaying one transaction
+type Result struct {
+	Hash             string `json:"hash"`
+	ApplicationOrder int    `json:"application_order"`
+	Expected         string `json:"expected"`
+	Actual           string `json:"actual,omitempty"`
+	Match            bool   `json:"match"`
+	// Differences lists where a simulation that reached the on-chain
+	// status still diverged from the meta: return value or contract events
+	Differences []string `json:"differences,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	// Fetched counts footprint entries read from the network at its
+	// current state because no meta in the ledger recorded them
+	Fetched int `json:"fetched,omitempty"`
+	// LowConfidence is set when entries were fetched, so a difference may
+	// come from state newer than the ledger rather than the simulator
+	LowConfidence bool          `json:"low_confidence,omitempty"`
+	Duration      time.Duration `json:"duration_ns"`
+}
+
+// Report is the replay of every Soroban transaction in a ledger
+type Report struct {
+	Ledger       uint32 `json:"ledger"`
+	Transactions int    `json:"transactions"`
+	Soroban      int    `json:"soroban"`
+	Matched      int    `json:"matched"`
+	Mismatched   int    `json:"mismatched"`
+	// LowConfidence counts low-confidence results that differed from the
+	// chain. They are not counted as mismatched.
+	LowConfidence int      `json:"low_confidence"`
+	Errored       int      `json:"errored"`
+	Results       []Result `json:"results"`
+}
+
+// OK reports whether every simulated outcome matched the chain
+func (r *Report) OK() bool {
+	return r.Mismatched == 0 && r.Errored == 0
+}
+
+// Replay simulates the Soroban transactions among txs, all from ledger, in
+// application order. Footprint entries missing from a transaction's meta
+// are taken from the meta of an earlier transaction that changed them, then
+// from fetch. fetch may be nil, in which case the rest are left out of the
+// simulated state.
+func Replay(ctx context.Context, runner simulator.RunnerInterface, ledger uint32, txs []rpc.LedgerTransaction, fetch FetchFunc) *Report {
+	report := &Report{Ledger: ledger, Transactions: len(txs), Results: []Result{}}
+	// The state left by the transactions applied so far, "" once removed
+	state := map[string]string{}
+	for _, tx := range txs {
+		if env, ok := sorobanEnvelope(tx); ok {
+			report.add(replayTx(ctx, runner, tx, env, state, fetch))
+		}
+		// A transaction whose meta cannot be read leaves nothing to carry
+		_ = PostState(tx.ResultMetaXdr, state)
+	}
+	return report
+}
+
+func (r *Report) add(res Result) {
+	r.Soroban++
+	switch {
+	case res.Actual == "":
+		r.Errored++
+	case res.Match:
+		r.Matched++
+	case res.LowConfidence:
+		r.LowConfidence++
+	default:
+		r.Mismatched++
+	}
+	r.Results = append(r.Results, res)
+}
+
+// replayTx simulates one Soroban transaction on state, the entries changed
+// earlier in its ledger
+func replayTx(ctx context.Context, runner simulator.RunnerInterface, tx rpc.LedgerTransaction, env xdr.TransactionEnvelope, state map[string]string, fetch FetchFunc) Result {
+	start := time.Now()
+	r := Result{Hash: tx.Hash, ApplicationOrder: tx.ApplicationOrder, Expected: StatusError}
+	if tx.Status == "SUCCESS" {
+		r.Expected = StatusSuccess
+	}
+	if ctx.Err() != nil {
+		r.Error = ctx.Err().Error()
+	} else {
+		r.Actual, r.Differences, r.Fetched, r.Error = replay(ctx, runner, tx, env, state, fetch)
+	}
+	r.Match = r.Actual != "" && r.Actual == r.Expected && len(r.Differences) == 0
+	r.LowConfidence = r.Fetched > 0
+	r.Duration = time.Since(start)
+	return r
+}
+
+// replay returns the simulated status, how a successful simulation
+// diverged from the meta and the number of fetched entries, or an error
+// message when the transaction could not be simulated
+func replay(ctx context.Context, runner simulator.RunnerInterface, tx rpc.LedgerTransaction, env xdr.TransactionEnvelope, state map[string]string, fetch FetchFunc) (string, []string, int, string) {
+	entries, footprint, err := PriorState(env, tx.ResultMetaXdr)
+	if err != nil {
+		return "", nil, 0, err.Error()
+	}
+	var missing []string
+	for _, k := range footprint {
+		v, ok := state[k]
+		switch {
+		case !ok:
+			missing = append(missing, k)
+		case v != "":
+			entries[k] = v
+		}
+	}
+	fetched := 0
+	if len(missing) > 0 && fetch != nil {
+		current, err := fetch(ctx, missing)
+		if err != nil {
+			return "", nil, 0, fmt.Sprintf("failed to fetch footprint entries: %v", err)
+		}
+		for k, v := range current {
+			entries[k] = v
+			fetched++
+		}
+	}
+
+	resp, err := runner.Run(&simulator.SimulationRequest{
+		EnvelopeXdr:    tx.EnvelopeXdr,
+		ResultMetaXdr:  tx.ResultMetaXdr,
+		LedgerEntries:  entries,
+		Timestamp:      tx.CreatedAt,
+		LedgerSequence: tx.Ledger,
+	})
+	var simErr *simulator.SimulationError
+	if errors.As(err, &simErr) {
+		return StatusError, nil, fetched, simErr.Message
+	}
+	// Resource limits and simulator failures say nothing about the
+	// transaction, so they are not counted as a mismatch
+	if err != nil {
+		return "", nil, fetched, err.Error()
+	}
+	if resp.Status == "error" {
+		return StatusError, nil, fetched, resp.Error
+	}
+	if tx.Status != "SUCCESS" {
+		return StatusSuccess, nil, fetched, ""
+	}
+
+	var meta xdr.TransactionMeta
+	if err := xdr.SafeUnmarshalBase64(tx.ResultMetaXdr, &meta); err != nil {
+		return "", nil, fetched, fmt.Sprintf("failed to decode transaction meta: %v", err)
+	}
+	return StatusSuccess, Compare(resp, meta), fetched, ""
+}
+
+// Compare lists how a successful simulation diverged from the meta of the
+// successful transaction: its return value and the contract events of
+// successful calls, by count, contract and number of topics. Topic and data
+// values are not compared since the simulator only reports them as text.
+func Compare(resp *simulator.SimulationResponse, meta xdr.TransactionMeta) []string {
+	var diffs []string
+	if want, ok := txmeta.ReturnValue(meta); ok {
+		expected, err := xdr.MarshalBase64(want)
+		if err == nil && resp.ReturnValue != expected {
+			diffs = append(diffs, fmt.Sprintf("return value %s, on-chain %s", valueOrNone(resp.ReturnValue), expected))
+		}
+	}
+
+	var onChain []xdr.ContractEvent
+	for _, events := range txmeta.OperationEvents(meta) {
+		for _, e := range events {
+			if e.Type == xdr.ContractEventTypeContract {
+				onChain = append(onChain, e)
+			}
+		}
+	}
+	var simulated []simulator.DiagnosticEvent
+	for _, e := range resp.DiagnosticEvents {
+		if e.EventType == "contract" && e.InSuccessfulContractCall {
+			simulated = append(simulated, e)
+		}
+	}
+	if len(simulated) != len(onChain) {
+		return append(diffs, fmt.Sprintf("%d contract event(s), on-chain %d", len(simulated), len(onChain)))
+	}
+	for i, want := range onChain {
+		got := simulated[i]
+		if want.ContractId != nil && (got.ContractID == nil || !strings.Contains(strings.ToLower(*got.ContractID), hex.EncodeToString(want.ContractId[:]))) {
+			diffs = append(diffs, fmt.Sprintf("contract event %d emitted by another contract", i))
+			continue
+		}
+		if body, ok := want.Body.GetV0(); ok && len(body.Topics) != len(got.Topics) {
+			diffs = append(diffs, fmt.Sprintf("contract event %d has %d topic(s), on-chain %d", i, len(got.Topics), len(body.Topics)))
+		}
+	}
+	return diffs
+}
+
+func valueOrNone(v string) string {
+	if v == "" {
+		return "none"
+	}
+	return v
+}
+
+// PriorState returns the ledger entries a transaction was applied on: the
+// first recorded state of every entry in its meta, by base64 key. Meta only
+// records entries the transaction changed, so footprint keys absent from it,
+// which includes every read-only entry, are returned as missing.
+func PriorState(env xdr.TransactionEnvelope, resultMetaXdr string) (map[string]string, []string, error) {
+	var meta xdr.TransactionMeta
+	if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &meta); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode transaction meta: %w", err)
+	}
+
+	entries := map[string]string{}
+	seen := map[string]bool{}
+	for _, c := range txmeta.Changes(meta) {
+		var entry xdr.LedgerEntry
+		switch c.Type {
+		case xdr.LedgerEntryChangeTypeLedgerEntryState:
+			entry = *c.State
+		case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+			entry = *c.Created
+		default:
+			continue
+		}
+		key, err := entry.LedgerKey()
+		if err != nil {
+			continue
+		}
+		k, err := rpc.EncodeLedgerKey(key)
+		if err != nil || seen[k] {
+			continue
+		}
+		// An entry first seen being created did not exist before
+		seen[k] = true
+		if c.Type == xdr.LedgerEntryChangeTypeLedgerEntryState {
+			if entries[k], err = rpc.EncodeLedgerEntry(entry); err != nil {
+				return nil, nil, fmt.Errorf("failed to encode ledger entry: %w", err)
+			}
+		}
+	}
+
+	var missing []string
+	if data := txmeta.SorobanData(env); data != nil {
+		footprint := data.Resources.Footprint
+		for _, key := range append(append([]xdr.LedgerKey{}, footprint.ReadOnly...), footprint.ReadWrite...) {
+			k, err := rpc.EncodeLedgerKey(key)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !seen[k] {
+				seen[k] = true
+				missing = append(missing, k)
+			}
+		}
+	}
+	return entries, missing, nil
+}
+
+// PostState records in state the value every entry changed by a transaction
+// was left with, by base64 key, or "" for entries it removed
+func PostState(resultMetaXdr string, state map[string]string) error {
+	var meta xdr.TransactionMeta
+	if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &meta); err != nil {
+		return fmt.Errorf("failed to decode transaction meta: %w", err)
+	}
+
+	changes := txmeta.Changes(meta)
+	switch {
+	case meta.V4 != nil:
+		changes = append(changes, meta.V4.TxChangesAfter...)
+	case meta.V3 != nil:
+		changes = append(changes, meta.V3.TxChangesAfter...)
+	case meta.V2 != nil:
+		changes = append(changes, meta.V2.TxChangesAfter...)
+	}
+	for _, c := range changes {
+		var (
+			key   xdr.LedgerKey
+			entry *xdr.LedgerEntry
+			err   error
+		)
+		switch c.Type {
+		case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+			entry = c.Created
+		case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+			entry = c.Updated
+		case xdr.LedgerEntryChangeTypeLedgerEntryRestored:
+			entry = c.Restored
+		case xdr.LedgerEntryChangeTypeLedgerEntryRemoved:
+			key = *c.Removed
+		default:
+			continue
+		}
+		if entry != nil {
+			if key, err = entry.LedgerKey(); err != nil {
+				continue
+			}
+		}
+		k, err := rpc.EncodeLedgerKey(key)
+		if err != nil {
+			continue
+		}
+		state[k] = ""
+		if entry != nil {
+			if state[k], err = rpc.EncodeLedgerEntry(*entry); err != nil {
+				return fmt.Errorf("failed to encode ledger entry: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// sorobanEnvelope decodes tx when it carries Soroban resources
+func sorobanEnvelope(tx rpc.LedgerTransaction) (xdr.TransactionEnvelope, bool) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(tx.EnvelopeXdr, &env); err != nil {
+		return env, false
+	}
+	return env, txmeta.SorobanData(env) != nil
+}
@@ -0,0 +1,314 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package ledgerreplay
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/keypair"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	source     = keypair.MustRandom().Address()
+	contractID = xdr.ContractId{4}
+	codeKey    = xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{1}}}
+	dataKey    = xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+		Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+		Durability: xdr.ContractDataDurabilityPersistent,
+	}}
+)
+
+func sorobanEnvelopeXdr(t *testing.T) string {
+	t.Helper()
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(source),
+			Fee:           1000,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+					Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+					InvokeContract: &xdr.InvokeContractArgs{
+						ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID},
+						FunctionName:    "bump",
+					},
+				}},
+			}}},
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{
+					ReadOnly:  []xdr.LedgerKey{codeKey},
+					ReadWrite: []xdr.LedgerKey{dataKey},
+				}},
+			}},
+		}},
+	}
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	return b64
+}
+
+func accountEntry(balance xdr.Int64) xdr.LedgerEntry {
+	return xdr.LedgerEntry{Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.AccountEntry{
+		AccountId: xdr.MustAddress(source), Balance: balance,
+	}}}
+}
+
+func dataEntry(v uint32) xdr.LedgerEntry {
+	val := xdr.Uint32(v)
+	return xdr.LedgerEntry{Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.ContractDataEntry{
+		Contract:   dataKey.ContractData.Contract,
+		Key:        dataKey.ContractData.Key,
+		Durability: xdr.ContractDataDurabilityPersistent,
+		Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &val},
+	}}}
+}
+
+func metaXdr(t *testing.T) string {
+	t.Helper()
+	before, after := accountEntry(100), accountEntry(90)
+	oldData, newData := dataEntry(1), dataEntry(2)
+	meta := xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+		TxChangesBefore: xdr.LedgerEntryChanges{
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &before},
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &after},
+		},
+		Operations: []xdr.OperationMeta{{Changes: xdr.LedgerEntryChanges{
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &after},
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &oldData},
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &newData},
+		}}},
+	}}
+	b64, err := xdr.MarshalBase64(meta)
+	require.NoError(t, err)
+	return b64
+}
+
+func encodeKey(t *testing.T, key xdr.LedgerKey) string {
+	t.Helper()
+	k, err := rpc.EncodeLedgerKey(key)
+	require.NoError(t, err)
+	return k
+}
+
+func TestPriorState(t *testing.T) {
+	var env xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(sorobanEnvelopeXdr(t), &env))
+
+	entries, missing, err := PriorState(env, metaXdr(t))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	account, err := rpc.EncodeLedgerEntry(accountEntry(100))
+	require.NoError(t, err)
+	accountKey := encodeKey(t, xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(source)}})
+	assert.Equal(t, account, entries[accountKey], "the state before fees and sequence are charged")
+
+	data, err := rpc.EncodeLedgerEntry(dataEntry(1))
+	require.NoError(t, err)
+	assert.Equal(t, data, entries[encodeKey(t, dataKey)])
+
+	assert.Equal(t, []string{encodeKey(t, codeKey)}, missing, "read-only code is not in the meta")
+
+	_, _, err = PriorState(env, "not xdr")
+	assert.Error(t, err)
+}
+
+func TestReplay(t *testing.T) {
+	soroban := sorobanEnvelopeXdr(t)
+	classic, err := xdr.MarshalBase64(xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &xdr.TransactionV1Envelope{
+		Tx: xdr.Transaction{SourceAccount: xdr.MustMuxedAddress(source), Operations: []xdr.Operation{{Body: xdr.OperationBody{
+			Type: xdr.OperationTypeBumpSequence, BumpSequenceOp: &xdr.BumpSequenceOp{},
+		}}}},
+	}})
+	require.NoError(t, err)
+	meta := metaXdr(t)
+
+	txs := []rpc.LedgerTransaction{
+		{Hash: "classic", Status: "SUCCESS", EnvelopeXdr: classic},
+		{Hash: "match", Status: "SUCCESS", Ledger: 50, CreatedAt: 1700000000, ApplicationOrder: 2, EnvelopeXdr: soroban, ResultMetaXdr: meta},
+		{Hash: "mismatch", Status: "FAILED", EnvelopeXdr: soroban, ResultMetaXdr: meta},
+		{Hash: "crash", Status: "SUCCESS", EnvelopeXdr: soroban, ResultMetaXdr: meta},
+	}
+
+	var requests []*simulator.SimulationRequest
+	runner := simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		requests = append(requests, req)
+		if len(requests) == 3 {
+			return nil, &simulator.LimitError{Resource: "cpu", Message: "cpu limit exceeded"}
+		}
+		return &simulator.SimulationResponse{Status: "success"}, nil
+	})
+	var fetched []string
+	fetch := func(_ context.Context, keys []string) (map[string]string, error) {
+		fetched = append(fetched, keys...)
+		if len(fetched) > 1 {
+			// Later transactions run without it, so they are not low confidence
+			return nil, nil
+		}
+		return map[string]string{keys[0]: "code"}, nil
+	}
+
+	report := Replay(context.Background(), runner, 50, txs, fetch)
+	assert.Equal(t, 4, report.Transactions)
+	assert.Equal(t, 3, report.Soroban, "classic transactions are skipped")
+	assert.Equal(t, 1, report.Matched)
+	assert.Equal(t, 1, report.Mismatched)
+	assert.Equal(t, 1, report.Errored)
+	assert.False(t, report.OK())
+
+	require.Len(t, report.Results, 3)
+	assert.True(t, report.Results[0].Match)
+	assert.Equal(t, 2, report.Results[0].ApplicationOrder)
+	assert.Equal(t, 1, report.Results[0].Fetched)
+	assert.True(t, report.Results[0].LowConfidence)
+	assert.False(t, report.Results[1].LowConfidence)
+	assert.Equal(t, StatusError, report.Results[1].Expected)
+	assert.Equal(t, StatusSuccess, report.Results[1].Actual)
+	assert.Empty(t, report.Results[2].Actual)
+	assert.NotEmpty(t, report.Results[2].Error)
+
+	assert.Equal(t, uint32(50), requests[0].LedgerSequence)
+	assert.Equal(t, int64(1700000000), requests[0].Timestamp)
+	assert.Equal(t, "code", requests[0].LedgerEntries[encodeKey(t, codeKey)])
+	assert.Len(t, fetched, 3)
+}
+
+func TestReplayCarriesState(t *testing.T) {
+	soroban := sorobanEnvelopeXdr(t)
+	code := xdr.LedgerEntry{Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.ContractCodeEntry{
+		Hash: codeKey.ContractCode.Hash, Code: []byte("\x00asm"),
+	}}}
+	deploy, err := xdr.MarshalBase64(xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+		Operations: []xdr.OperationMeta{{Changes: xdr.LedgerEntryChanges{
+			{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &code},
+		}}},
+	}})
+	require.NoError(t, err)
+	txs := []rpc.LedgerTransaction{
+		{Hash: "deploy", Status: "SUCCESS", EnvelopeXdr: soroban, ResultMetaXdr: deploy},
+		{Hash: "call", Status: "SUCCESS", EnvelopeXdr: soroban, ResultMetaXdr: metaXdr(t)},
+	}
+
+	var requests []*simulator.SimulationRequest
+	runner := simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		requests = append(requests, req)
+		return &simulator.SimulationResponse{Status: "success"}, nil
+	})
+	var fetched []string
+	fetch := func(_ context.Context, keys []string) (map[string]string, error) {
+		fetched = append(fetched, keys...)
+		return nil, nil
+	}
+
+	report := Replay(context.Background(), runner, 50, txs, fetch)
+	require.Len(t, requests, 2)
+	want, err := rpc.EncodeLedgerEntry(code)
+	require.NoError(t, err)
+	assert.Equal(t, want, requests[1].LedgerEntries[encodeKey(t, codeKey)], "code deployed earlier in the ledger")
+	assert.Equal(t, []string{encodeKey(t, dataKey)}, fetched, "only the first transaction fetches")
+	assert.Zero(t, report.Results[1].Fetched)
+}
+
+func TestReplayLowConfidence(t *testing.T) {
+	txs := []rpc.LedgerTransaction{{Hash: "h", Status: "FAILED", EnvelopeXdr: sorobanEnvelopeXdr(t), ResultMetaXdr: metaXdr(t)}}
+	runner := simulator.NewMockRunner(func(*simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		return &simulator.SimulationResponse{Status: "success"}, nil
+	})
+	fetch := func(_ context.Context, keys []string) (map[string]string, error) {
+		return map[string]string{keys[0]: "code"}, nil
+	}
+
+	report := Replay(context.Background(), runner, 50, txs, fetch)
+	require.Len(t, report.Results, 1)
+	assert.True(t, report.Results[0].LowConfidence)
+	assert.False(t, report.Results[0].Match)
+	assert.Equal(t, 1, report.LowConfidence)
+	assert.Zero(t, report.Mismatched, "a difference on fetched state is not a mismatch")
+	assert.True(t, report.OK())
+}
+
+func TestReplayFetchError(t *testing.T) {
+	runner := simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		t.Fatal("not simulated without its state")
+		return nil, nil
+	})
+	report := Replay(context.Background(), runner, 50, []rpc.LedgerTransaction{
+		{Hash: "aa", Status: "SUCCESS", EnvelopeXdr: sorobanEnvelopeXdr(t), ResultMetaXdr: metaXdr(t)},
+	}, func(context.Context, []string) (map[string]string, error) { return nil, errors.New("offline") })
+	require.Len(t, report.Results, 1)
+	assert.Contains(t, report.Results[0].Error, "offline")
+	assert.Equal(t, 1, report.Errored)
+}
+
+func TestReplaySimulationError(t *testing.T) {
+	errs := []error{&simulator.SimulationError{Message: "contract trapped"}, errors.New("simulator execution failed")}
+	runner := simulator.NewMockRunner(func(req *simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+		err := errs[0]
+		errs = errs[1:]
+		return nil, err
+	})
+	soroban, meta := sorobanEnvelopeXdr(t), metaXdr(t)
+	report := Replay(context.Background(), runner, 50, []rpc.LedgerTransaction{
+		{Hash: "trap", Status: "FAILED", EnvelopeXdr: soroban, ResultMetaXdr: meta},
+		{Hash: "infra", Status: "FAILED", EnvelopeXdr: soroban, ResultMetaXdr: meta},
+	}, nil)
+	require.Len(t, report.Results, 2)
+	assert.True(t, report.Results[0].Match, "a simulation error reproduces a failure")
+	assert.Equal(t, "contract trapped", report.Results[0].Error)
+	assert.Empty(t, report.Results[1].Actual, "a broken simulator is not a mismatch")
+	assert.Equal(t, 1, report.Matched)
+	assert.Equal(t, 1, report.Errored)
+}
+
+func TestCompare(t *testing.T) {
+	ret := xdr.Uint32(7)
+	retVal := xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &ret}
+	retB64, err := xdr.MarshalBase64(retVal)
+	require.NoError(t, err)
+	event := xdr.ContractEvent{
+		Type:       xdr.ContractEventTypeContract,
+		ContractId: &contractID,
+		Body: xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{
+			Topics: []xdr.ScVal{retVal, retVal},
+			Data:   retVal,
+		}},
+	}
+	meta := xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{SorobanMeta: &xdr.SorobanTransactionMeta{
+		ReturnValue: retVal,
+		Events:      []xdr.ContractEvent{event},
+	}}}
+
+	id := "ContractId(Hash(" + hex.EncodeToString(contractID[:]) + "))"
+	matching := &simulator.SimulationResponse{Status: "success", ReturnValue: retB64, DiagnosticEvents: []simulator.DiagnosticEvent{
+		{EventType: "diagnostic", Topics: []string{"fn_call"}},
+		{EventType: "contract", ContractID: &id, Topics: []string{"a"}, InSuccessfulContractCall: false},
+		{EventType: "contract", ContractID: &id, Topics: []string{"U32(7)", "U32(7)"}, InSuccessfulContractCall: true},
+	}}
+	assert.Empty(t, Compare(matching, meta), "diagnostic events and events of reverted calls are ignored")
+
+	other := "ContractId(Hash(" + hex.EncodeToString(make([]byte, 32)) + "))"
+	diverged := &simulator.SimulationResponse{Status: "success", DiagnosticEvents: []simulator.DiagnosticEvent{
+		{EventType: "contract", ContractID: &other, Topics: []string{"U32(7)"}, InSuccessfulContractCall: true},
+	}}
+	diffs := Compare(diverged, meta)
+	require.Len(t, diffs, 2)
+	assert.Equal(t, "return value none, on-chain "+retB64, diffs[0])
+	assert.Equal(t, "contract event 0 emitted by another contract", diffs[1])
+
+	diverged.DiagnosticEvents[0].ContractID = &id
+	assert.Contains(t, Compare(diverged, meta), "contract event 0 has 1 topic(s), on-chain 2")
+
+	diverged.DiagnosticEvents = nil
+	assert.Contains(t, Compare(diverged, meta), "0 contract event(s), on-chain 1")
+}
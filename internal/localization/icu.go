@@ -0,0 +1,364 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package localization
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Args holds the named arguments of an ICU message
+type Args map[string]interface{}
+
+// FormatMessage formats an ICU MessageFormat template for lang. Supported
+// arguments are {name}, {name, number[, style]}, {name, date[, style]},
+// {name, time[, style]}, {name, plural, ...} with =N selectors, offset: and
+// #, and {name, select, ...}. Apostrophes quote literal braces as in ICU.
+func FormatMessage(lang Language, template string, args Args) (string, error) {
+	nodes, err := parseMessage(template)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := formatNodes(&b, lang, nodes, args, nil); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ValidateMessage reports whether template is well-formed ICU MessageFormat
+func ValidateMessage(template string) error {
+	_, err := parseMessage(template)
+	return err
+}
+
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	argNode
+	pluralNode
+	selectNode
+	hashNode
+)
+
+type msgNode struct {
+	kind   nodeKind
+	text   string
+	name   string
+	typ    string
+	style  string
+	offset float64
+	cases  map[string][]msgNode
+}
+
+type msgParser struct {
+	src []rune
+	pos int
+}
+
+func parseMessage(template string) ([]msgNode, error) {
+	p := &msgParser{src: []rune(template)}
+	nodes, err := p.message(false, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid message %q: %w", template, err)
+	}
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("invalid message %q: unexpected '}' at %d", template, p.pos)
+	}
+	return nodes, nil
+}
+
+// message parses text and arguments until an unmatched '}' or the end.
+// inPlural enables '#' substitution.
+func (p *msgParser) message(nested, inPlural bool) ([]msgNode, error) {
+	var nodes []msgNode
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, msgNode{kind: textNode, text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\'':
+			p.pos++
+			if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+				text.WriteRune('\'')
+				p.pos++
+				continue
+			}
+			if p.pos < len(p.src) && (p.src[p.pos] == '{' || p.src[p.pos] == '}' || p.src[p.pos] == '#') {
+				// Quoted literal up to the next single apostrophe
+				for p.pos < len(p.src) {
+					if p.src[p.pos] == '\'' {
+						if p.pos+1 < len(p.src) && p.src[p.pos+1] == '\'' {
+							text.WriteRune('\'')
+							p.pos += 2
+							continue
+						}
+						p.pos++
+						break
+					}
+					text.WriteRune(p.src[p.pos])
+					p.pos++
+				}
+				continue
+			}
+			text.WriteRune('\'')
+		case c == '{':
+			flush()
+			p.pos++
+			n, err := p.argument()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		case c == '}':
+			if !nested {
+				return nil, fmt.Errorf("unexpected '}' at %d", p.pos)
+			}
+			flush()
+			return nodes, nil
+		case c == '#' && inPlural:
+			flush()
+			nodes = append(nodes, msgNode{kind: hashNode})
+			p.pos++
+		default:
+			text.WriteRune(c)
+			p.pos++
+		}
+	}
+	if nested {
+		return nil, fmt.Errorf("unclosed '{'")
+	}
+	flush()
+	return nodes, nil
+}
+
+// argument parses the body of {...} after the opening brace
+func (p *msgParser) argument() (msgNode, error) {
+	name := p.token()
+	if name == "" {
+		return msgNode{}, fmt.Errorf("missing argument name at %d", p.pos)
+	}
+	p.skipSpace()
+	if p.consume('}') {
+		return msgNode{kind: argNode, name: name}, nil
+	}
+	if !p.consume(',') {
+		return msgNode{}, fmt.Errorf("expected ',' or '}' after %q", name)
+	}
+
+	typ := p.token()
+	p.skipSpace()
+	switch typ {
+	case "number", "date", "time":
+		style := ""
+		if p.consume(',') {
+			start := p.pos
+			for p.pos < len(p.src) && p.src[p.pos] != '}' {
+				p.pos++
+			}
+			style = strings.TrimSpace(string(p.src[start:p.pos]))
+		}
+		if !p.consume('}') {
+			return msgNode{}, fmt.Errorf("unclosed argument %q", name)
+		}
+		return msgNode{kind: argNode, name: name, typ: typ, style: style}, nil
+	case "plural", "select":
+		if !p.consume(',') {
+			return msgNode{}, fmt.Errorf("expected ',' after %s", typ)
+		}
+		n := msgNode{kind: selectNode, name: name, cases: make(map[string][]msgNode)}
+		if typ == "plural" {
+			n.kind = pluralNode
+		}
+		for {
+			p.skipSpace()
+			if p.consume('}') {
+				break
+			}
+			key := p.token()
+			if key == "" {
+				return msgNode{}, fmt.Errorf("missing selector in %s %q", typ, name)
+			}
+			if n.kind == pluralNode && strings.HasPrefix(key, "offset:") {
+				off, err := strconv.ParseFloat(strings.TrimPrefix(key, "offset:"), 64)
+				if err != nil {
+					return msgNode{}, fmt.Errorf("invalid plural offset %q", key)
+				}
+				n.offset = off
+				continue
+			}
+			p.skipSpace()
+			if !p.consume('{') {
+				return msgNode{}, fmt.Errorf("expected '{' after selector %q", key)
+			}
+			body, err := p.message(true, n.kind == pluralNode)
+			if err != nil {
+				return msgNode{}, err
+			}
+			p.pos++ // closing brace of the case
+			n.cases[key] = body
+		}
+		if _, ok := n.cases["other"]; !ok {
+			return msgNode{}, fmt.Errorf("%s %q has no 'other' case", typ, name)
+		}
+		return n, nil
+	default:
+		return msgNode{}, fmt.Errorf("unknown argument type %q", typ)
+	}
+}
+
+func (p *msgParser) token() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ',' || c == '{' || c == '}' || c == ' ' || c == '\t' || c == '\n' {
+			break
+		}
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *msgParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+func (p *msgParser) consume(c rune) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// pluralValue is the number a '#' inside a plural case stands for
+type pluralValue struct {
+	n float64
+}
+
+func formatNodes(b *strings.Builder, lang Language, nodes []msgNode, args Args, hash *pluralValue) error {
+	for _, n := range nodes {
+		switch n.kind {
+		case textNode:
+			b.WriteString(n.text)
+		case hashNode:
+			if hash != nil {
+				b.WriteString(FormatNumber(lang, hash.n, ""))
+			}
+		case argNode:
+			v, ok := args[n.name]
+			if !ok {
+				return fmt.Errorf("missing argument %q", n.name)
+			}
+			s, err := formatArg(lang, n, v)
+			if err != nil {
+				return err
+			}
+			b.WriteString(s)
+		case pluralNode:
+			v, ok := args[n.name]
+			if !ok {
+				return fmt.Errorf("missing argument %q", n.name)
+			}
+			num, ok := toFloat(v)
+			if !ok {
+				return fmt.Errorf("argument %q is not a number", n.name)
+			}
+			body, found := n.cases["="+strconv.FormatFloat(num, 'f', -1, 64)]
+			if !found {
+				body, found = n.cases[string(pluralCategory(lang, num-n.offset, v))]
+			}
+			if !found {
+				body = n.cases["other"]
+			}
+			if err := formatNodes(b, lang, body, args, &pluralValue{n: num - n.offset}); err != nil {
+				return err
+			}
+		case selectNode:
+			v, ok := args[n.name]
+			if !ok {
+				return fmt.Errorf("missing argument %q", n.name)
+			}
+			body, found := n.cases[fmt.Sprint(v)]
+			if !found {
+				body = n.cases["other"]
+			}
+			if err := formatNodes(b, lang, body, args, hash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func formatArg(lang Language, n msgNode, v interface{}) (string, error) {
+	switch n.typ {
+	case "":
+		if t, ok := v.(time.Time); ok {
+			return FormatDate(lang, t, "short") + " " + FormatTime(lang, t, "short"), nil
+		}
+		if num, ok := toFloat(v); ok {
+			return FormatNumber(lang, num, ""), nil
+		}
+		return fmt.Sprint(v), nil
+	case "number":
+		num, ok := toFloat(v)
+		if !ok {
+			return "", fmt.Errorf("argument %q is not a number", n.name)
+		}
+		return FormatNumber(lang, num, n.style), nil
+	case "date", "time":
+		t, ok := v.(time.Time)
+		if !ok {
+			return "", fmt.Errorf("argument %q is not a time", n.name)
+		}
+		if n.typ == "date" {
+			return FormatDate(lang, t, n.style), nil
+		}
+		return FormatTime(lang, t, n.style), nil
+	}
+	return "", fmt.Errorf("unknown argument type %q", n.typ)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
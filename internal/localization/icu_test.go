@@ -0,0 +1,126 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package localization
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     Language
+		template string
+		args     Args
+		want     string
+	}{
+		{"simple", English, "Network: {network}", Args{"network": "testnet"}, "Network: testnet"},
+		{"number grouping en", English, "{n, number, integer} bytes", Args{"n": 1234567}, "1,234,567 bytes"},
+		{"number grouping es", Spanish, "{n, number, integer} bytes", Args{"n": 1234567}, "1.234.567 bytes"},
+		{"es min grouping", Spanish, "{n, number}", Args{"n": 1234}, "1234"},
+		{"decimal es", Spanish, "{n, number}", Args{"n": 12345.5}, "12.345,5"},
+		{"percent en", English, "{p, number, ::percent .00}", Args{"p": 0.8512}, "85.12%"},
+		{"percent es", Spanish, "{p, number, percent}", Args{"p": 0.5}, "50\u00a0%"},
+		{"plural one", English, "{n, plural, one {# byte} other {# bytes}}", Args{"n": 1}, "1 byte"},
+		{"plural other", English, "{n, plural, one {# byte} other {# bytes}}", Args{"n": 2048}, "2,048 bytes"},
+		{"plural decimal is other", English, "{n, plural, one {# item} other {# items}}", Args{"n": 1.5}, "1.5 items"},
+		{"plural exact", English, "{n, plural, =0 {none} one {one} other {#}}", Args{"n": 0}, "none"},
+		{"plural offset", English, "{n, plural, offset:1 =1 {just you} one {you and # other} other {you and # others}}", Args{"n": 3}, "you and 2 others"},
+		{"plural es many", Spanish, "{n, plural, one {# evento} many {# de eventos} other {# eventos}}", Args{"n": 2000000}, "2.000.000 de eventos"},
+		{"plural zh", Chinese, "{n, plural, one {one} other {# 个}}", Args{"n": 1}, "1 个"},
+		{"select", Spanish, "{g, select, female {la autora} male {el autor} other {la persona autora}}", Args{"g": "female"}, "la autora"},
+		{"select other", English, "{g, select, female {her} other {their}}", Args{"g": "unknown"}, "their"},
+		{"select empty case", English, "x{l, select, warn { !} other {}}", Args{"l": "ok"}, "x"},
+		{"quoted braces", English, "'{literal}' {n}", Args{"n": "v"}, "{literal} v"},
+		{"apostrophe", English, "it''s {n}", Args{"n": "here"}, "it's here"},
+		{"date medium en", English, "{t, date}", Args{"t": time.Date(2025, 3, 4, 15, 4, 5, 0, time.UTC)}, "Mar 4, 2025"},
+		{"date long es", Spanish, "{t, date, long}", Args{"t": time.Date(2025, 3, 4, 15, 4, 5, 0, time.UTC)}, "4 de marzo de 2025"},
+		{"date zh", Chinese, "{t, date}", Args{"t": time.Date(2025, 3, 4, 15, 4, 5, 0, time.UTC)}, "2025年3月4日"},
+		{"time en", English, "{t, time, short}", Args{"t": time.Date(2025, 3, 4, 15, 4, 5, 0, time.UTC)}, "3:04 PM"},
+		{"time es", Spanish, "{t, time}", Args{"t": time.Date(2025, 3, 4, 15, 4, 5, 0, time.UTC)}, "15:04:05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatMessage(tt.lang, tt.template, tt.args)
+			if err != nil {
+				t.Fatalf("FormatMessage: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatMessageErrors(t *testing.T) {
+	for _, tmpl := range []string{
+		"{unclosed",
+		"stray }",
+		"{n, plural, one {x}}",
+		"{n, bogus}",
+		"{n, plural, one x}",
+	} {
+		if err := ValidateMessage(tmpl); err == nil {
+			t.Errorf("expected %q to be rejected", tmpl)
+		}
+	}
+
+	if _, err := FormatMessage(English, "{missing}", nil); err == nil {
+		t.Error("expected missing argument error")
+	}
+	if _, err := FormatMessage(English, "{n, number}", Args{"n": "x"}); err == nil {
+		t.Error("expected non-numeric argument error")
+	}
+}
+
+func TestLocalizerFormat(t *testing.T) {
+	loc := New()
+	_ = loc.RegisterMessages(English, map[string]string{
+		"count": "{n, plural, one {# event} other {# events}}",
+		"bad":   "{n",
+	})
+	_ = loc.RegisterMessages(Spanish, map[string]string{"count": "{n, plural, one {# evento} other {# eventos}}"})
+
+	_ = loc.SetLanguage(Spanish)
+	if got := loc.Format("count", Args{"n": 1500}); got != "1500 eventos" {
+		t.Errorf("Spanish = %q", got)
+	}
+
+	// Chinese falls back to the English message and English rules
+	_ = loc.SetLanguage(Chinese)
+	if got := loc.Format("count", Args{"n": 1}); got != "1 event" {
+		t.Errorf("fallback = %q", got)
+	}
+
+	if got := loc.Format("bad", Args{"n": 1}); got != "{n" {
+		t.Errorf("malformed message should render raw, got %q", got)
+	}
+	if got := loc.Format("unknown", nil); got != "unknown" {
+		t.Errorf("unknown key = %q", got)
+	}
+}
+
+// Every catalog message must parse, and translations must cover every
+// English key so no output falls back mid-sentence
+func TestCatalogs(t *testing.T) {
+	catalogs := map[Language]map[string]string{
+		English: EnglishMessages,
+		Spanish: SpanishMessages,
+		Chinese: ChineseMessages,
+	}
+	for lang, messages := range catalogs {
+		for key, msg := range messages {
+			if err := ValidateMessage(msg); err != nil {
+				t.Errorf("%s %s: %v", lang, key, err)
+			}
+		}
+		for key := range EnglishMessages {
+			if _, ok := messages[key]; !ok {
+				t.Errorf("%s is missing %s", lang, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package localization
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PluralCategory is a CLDR plural category
+type PluralCategory string
+
+const (
+	PluralZero  PluralCategory = "zero"
+	PluralOne   PluralCategory = "one"
+	PluralTwo   PluralCategory = "two"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// localeData holds the CLDR number and date conventions of a language
+type localeData struct {
+	decimal string
+	group   string
+	// minGrouping is the number of digits above the first group needed
+	// before separators are used: 2 in Spanish, so 1234 stays ungrouped
+	minGrouping int
+	percent     string
+	plural      func(n float64, i int64, v int) PluralCategory
+	shortMonths []string
+	longMonths  []string
+	date        map[string]func(t time.Time, l *localeData) string
+	timeFmt     map[string]string
+}
+
+var locales = map[Language]*localeData{
+	English: {
+		decimal: ".", group: ",", minGrouping: 1, percent: "%s%%",
+		plural: func(n float64, i int64, v int) PluralCategory {
+			if i == 1 && v == 0 {
+				return PluralOne
+			}
+			return PluralOther
+		},
+		shortMonths: []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		longMonths:  []string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		date: map[string]func(time.Time, *localeData) string{
+			"short": func(t time.Time, _ *localeData) string { return t.Format("1/2/06") },
+			"medium": func(t time.Time, l *localeData) string {
+				return fmt.Sprintf("%s %d, %d", l.shortMonths[t.Month()-1], t.Day(), t.Year())
+			},
+			"long": func(t time.Time, l *localeData) string {
+				return fmt.Sprintf("%s %d, %d", l.longMonths[t.Month()-1], t.Day(), t.Year())
+			},
+		},
+		timeFmt: map[string]string{"short": "3:04 PM", "medium": "3:04:05 PM", "long": "3:04:05 PM MST"},
+	},
+	Spanish: {
+		decimal: ",", group: ".", minGrouping: 2, percent: "%s\u00a0%%",
+		plural: func(n float64, i int64, v int) PluralCategory {
+			if n == 1 {
+				return PluralOne
+			}
+			if v == 0 && i != 0 && i%1000000 == 0 {
+				return PluralMany
+			}
+			return PluralOther
+		},
+		shortMonths: []string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		longMonths:  []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		date: map[string]func(time.Time, *localeData) string{
+			"short": func(t time.Time, _ *localeData) string { return t.Format("2/1/06") },
+			"medium": func(t time.Time, l *localeData) string {
+				return fmt.Sprintf("%d %s %d", t.Day(), l.shortMonths[t.Month()-1], t.Year())
+			},
+			"long": func(t time.Time, l *localeData) string {
+				return fmt.Sprintf("%d de %s de %d", t.Day(), l.longMonths[t.Month()-1], t.Year())
+			},
+		},
+		timeFmt: map[string]string{"short": "15:04", "medium": "15:04:05", "long": "15:04:05 MST"},
+	},
+	Chinese: {
+		decimal: ".", group: ",", minGrouping: 1, percent: "%s%%",
+		plural: func(float64, int64, int) PluralCategory { return PluralOther },
+		date: map[string]func(time.Time, *localeData) string{
+			"short": func(t time.Time, _ *localeData) string { return t.Format("2006/1/2") },
+			"medium": func(t time.Time, _ *localeData) string {
+				return fmt.Sprintf("%d年%d月%d日", t.Year(), t.Month(), t.Day())
+			},
+			"long": func(t time.Time, _ *localeData) string {
+				return fmt.Sprintf("%d年%d月%d日", t.Year(), t.Month(), t.Day())
+			},
+		},
+		timeFmt: map[string]string{"short": "15:04", "medium": "15:04:05", "long": "15:04:05 MST"},
+	},
+}
+
+func locale(lang Language) *localeData {
+	if l, ok := locales[lang]; ok {
+		return l
+	}
+	return locales[English]
+}
+
+// pluralCategory selects the CLDR cardinal category of n. raw is the
+// original argument, used to tell integers from decimals.
+func pluralCategory(lang Language, n float64, raw interface{}) PluralCategory {
+	abs := math.Abs(n)
+	v := 0
+	if _, isFloat := raw.(float64); isFloat || abs != math.Trunc(abs) {
+		s := strconv.FormatFloat(abs, 'f', -1, 64)
+		if dot := strings.IndexByte(s, '.'); dot >= 0 {
+			v = len(s) - dot - 1
+		}
+	}
+	return locale(lang).plural(abs, int64(abs), v)
+}
+
+// FormatNumber formats n with the separators of lang. style is empty (up
+// to three fraction digits), "integer", "percent", or an ICU skeleton such
+// as "::.00" or "::percent .0".
+func FormatNumber(lang Language, n float64, style string) string {
+	l := locale(lang)
+	minFrac, maxFrac, percent := 0, 3, false
+
+	switch style {
+	case "":
+	case "integer":
+		maxFrac = 0
+	case "percent":
+		maxFrac, percent = 0, true
+	default:
+		for _, tok := range strings.Fields(strings.TrimPrefix(style, "::")) {
+			switch {
+			case tok == "percent":
+				percent = true
+			case strings.HasPrefix(tok, "."):
+				minFrac = strings.Count(tok, "0")
+				maxFrac = minFrac + strings.Count(tok, "#")
+			}
+		}
+	}
+	if percent {
+		n *= 100
+	}
+
+	s := strconv.FormatFloat(math.Abs(n), 'f', maxFrac, 64)
+	intPart, frac := s, ""
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		intPart, frac = s[:dot], s[dot+1:]
+	}
+	for len(frac) > minFrac && strings.HasSuffix(frac, "0") {
+		frac = frac[:len(frac)-1]
+	}
+
+	out := groupDigits(intPart, l)
+	if frac != "" {
+		out += l.decimal + frac
+	}
+	if n < 0 && strings.Trim(out, "0"+l.decimal+l.group) != "" {
+		out = "-" + out
+	}
+	if percent {
+		out = fmt.Sprintf(l.percent, out)
+	}
+	return out
+}
+
+func groupDigits(digits string, l *localeData) string {
+	if len(digits) < 4+l.minGrouping-1 {
+		return digits
+	}
+	var b strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		b.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(l.group)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
+// FormatDate formats the date of t in lang's short, medium (default) or
+// long style
+func FormatDate(lang Language, t time.Time, style string) string {
+	l := locale(lang)
+	f, ok := l.date[style]
+	if !ok {
+		f = l.date["medium"]
+	}
+	return f(t, l)
+}
+
+// FormatTime formats the time of day of t in lang's short, medium
+// (default) or long style
+func FormatTime(lang Language, t time.Time, style string) string {
+	l := locale(lang)
+	layout, ok := l.timeFmt[style]
+	if !ok {
+		layout = l.timeFmt["medium"]
+	}
+	return t.Format(layout)
+}
@@ -136,6 +136,34 @@ func (l *Localizer) TranslateForLang(lang Language, key string, args ...interfac
 	return template
 }
 
+// Format renders the ICU message stored under key with named arguments in
+// the current language, falling back to the default language's message.
+// A malformed message or missing argument yields the raw template so output
+// is never lost.
+func (l *Localizer) Format(key string, args Args) string {
+	return l.FormatForLang(l.GetLanguage(), key, args)
+}
+
+// FormatForLang renders the ICU message stored under key for lang
+func (l *Localizer) FormatForLang(lang Language, key string, args Args) string {
+	l.mu.RLock()
+	template, ok := l.messages[lang][key]
+	if !ok {
+		lang = l.defaultLang
+		template, ok = l.messages[lang][key]
+	}
+	l.mu.RUnlock()
+	if !ok {
+		return key
+	}
+
+	out, err := FormatMessage(lang, template, args)
+	if err != nil {
+		return template
+	}
+	return out
+}
+
 var globalLocalizer = New()
 
 func Get(key string) string {
@@ -146,6 +174,16 @@ func Translate(key string, args ...interface{}) string {
 	return globalLocalizer.Translate(key, args...)
 }
 
+// Format renders an ICU message with the global localizer
+func Format(key string, args Args) string {
+	return globalLocalizer.Format(key, args)
+}
+
+// CurrentLanguage returns the language of the global localizer
+func CurrentLanguage() Language {
+	return globalLocalizer.GetLanguage()
+}
+
 func SetLanguage(lang Language) error {
 	return globalLocalizer.SetLanguage(lang)
 }
@@ -45,6 +45,61 @@ var EnglishMessages = map[string]string{
 	"validation.model_required":   "gas model file path cannot be empty",
 	"validation.model_file_read":  "failed to read gas model file: %w",
 	"validation.json_parse_error": "failed to parse gas model JSON: %w",
+
+	// ICU messages, rendered with Format
+	"debug.debugging_tx":       "Debugging transaction: {hash}",
+	"debug.network":            "Network: {network}",
+	"debug.rpc_url":            "RPC URL: {url}",
+	"debug.primary_network":    "Primary Network: {network}",
+	"debug.compare_network":    "Comparing against Network: {network}",
+	"debug.fetching_tx":        "Fetching transaction: {hash}",
+	"debug.tx_fetched":         "Transaction fetched successfully. Envelope size: {size, plural, one {# byte} other {# bytes}}",
+	"debug.simulating_at":      "--- Simulating at Timestamp: {timestamp} ---",
+	"debug.running_simulation": "Running simulation on {network}...",
+
+	"result.header":            "--- Result for {network} ---",
+	"result.status":            "Status: {status}",
+	"result.error":             "Error: {error}",
+	"result.resource_usage":    "Resource Usage:",
+	"result.cpu":               "  CPU Instructions: {used, number, integer} / {limit, number, integer} ({percent, number, ::percent .00}){level, select, critical { [!]  CRITICAL} warning { [!]  WARNING} other {}}",
+	"result.memory":            "  Memory Bytes: {used, number, integer} / {limit, number, integer} ({percent, number, ::percent .00}){level, select, critical { [!]  CRITICAL} warning { [!]  WARNING} other {}}",
+	"result.operations":        "  Operations: {count, number, integer}",
+	"result.metering":          "Host Metering:",
+	"result.meter_phase":       "  {phase}: CPU {cpu, number, integer} instructions, memory {mem, number, integer} bytes",
+	"result.diagnostic_events": "Diagnostic Events: {count, number, integer}",
+	"result.event_type":        "  [{index}] Type: {type}",
+	"result.event_contract":    ", Contract: {contract}",
+	"result.event_topics":      "      Topics: {topics}",
+	"result.event_data":        "      Data: {data}",
+	"result.more_events":       "  ... and {count, plural, one {# more event} other {# more events}}",
+	"result.events":            "Events: {count, number, integer}",
+	"result.logs":              "Logs: {count, number, integer}",
+	"result.more_logs":         "  ... and {count, plural, one {# more log} other {# more logs}}",
+	"result.summary":           "Events: {events, number, integer}, Logs: {logs, number, integer}",
+
+	"compare.header":           "Comparison: {left} vs {right}",
+	"compare.status_mismatch":  "Status Mismatch: {leftStatus} ({left}) vs {rightStatus} ({right})",
+	"compare.status_match":     "Status Match: {status}",
+	"compare.diagnostic_count": "[DIFF] Diagnostic events count mismatch: {left, number, integer} vs {right, number, integer}",
+	"compare.events_count":     "[DIFF] Events count mismatch: {left, number, integer} vs {right, number, integer}",
+	"compare.cpu":              "[DIFF] CPU instructions: {left, number, integer} vs {right, number, integer}",
+	"compare.memory":           "[DIFF] Memory bytes: {left, number, integer} vs {right, number, integer}",
+	"compare.log_diff":         "Log Diff:",
+	"compare.no_differences":   "  No differences",
+	"compare.event_diff":       "Event Diff:",
+	"compare.events_match":     "  {count, plural, =0 {No events to compare} one {The only event matches} other {All # events match}}",
+	"compare.only_on":          "  {mark} [{index}] only on {network}: {event}",
+	"compare.modified":         "  ~ [{left} -> {right}] modified:",
+	"compare.summary":          "  {unchanged, number, integer} unchanged, {modified, number, integer} modified, {removed, number, integer} only on {left}, {added, number, integer} only on {right}",
+
+	"security.heading":   "Security Analysis",
+	"security.no_issues": "{mark} No security issues detected",
+	"tokenflow.heading":  "Token Flow Summary:",
+
+	"demo.cpu":             "  CPU Instructions: {used, number, integer}",
+	"demo.memory":          "  Memory Bytes: {used, number, integer}",
+	"demo.xlm_transferred": "  {arrow} XLM transferred",
+	"demo.session_ready":   "Session ready. Use 'erst session save' to persist.",
 }
 
 var SpanishMessages = map[string]string{
@@ -89,6 +144,61 @@ var SpanishMessages = map[string]string{
 	"validation.model_required":   "la ruta del archivo de modelo de gas no puede estar vacía",
 	"validation.model_file_read":  "error al leer archivo de modelo de gas: %w",
 	"validation.json_parse_error": "error al analizar JSON del modelo de gas: %w",
+
+	// ICU messages, rendered with Format
+	"debug.debugging_tx":       "Depurando transacción: {hash}",
+	"debug.network":            "Red: {network}",
+	"debug.rpc_url":            "URL de RPC: {url}",
+	"debug.primary_network":    "Red principal: {network}",
+	"debug.compare_network":    "Comparando con la red: {network}",
+	"debug.fetching_tx":        "Obteniendo transacción: {hash}",
+	"debug.tx_fetched":         "Transacción obtenida correctamente. Tamaño de la envolvente: {size, plural, one {# byte} other {# bytes}}",
+	"debug.simulating_at":      "--- Simulando en la marca de tiempo: {timestamp} ---",
+	"debug.running_simulation": "Ejecutando simulación en {network}...",
+
+	"result.header":            "--- Resultado para {network} ---",
+	"result.status":            "Estado: {status}",
+	"result.error":             "Error: {error}",
+	"result.resource_usage":    "Uso de recursos:",
+	"result.cpu":               "  Instrucciones de CPU: {used, number, integer} / {limit, number, integer} ({percent, number, ::percent .00}){level, select, critical { [!]  CRÍTICO} warning { [!]  ADVERTENCIA} other {}}",
+	"result.memory":            "  Bytes de memoria: {used, number, integer} / {limit, number, integer} ({percent, number, ::percent .00}){level, select, critical { [!]  CRÍTICO} warning { [!]  ADVERTENCIA} other {}}",
+	"result.operations":        "  Operaciones: {count, number, integer}",
+	"result.metering":          "Medición del host:",
+	"result.meter_phase":       "  {phase}: CPU {cpu, number, integer} instrucciones, memoria {mem, number, integer} bytes",
+	"result.diagnostic_events": "Eventos de diagnóstico: {count, number, integer}",
+	"result.event_type":        "  [{index}] Tipo: {type}",
+	"result.event_contract":    ", Contrato: {contract}",
+	"result.event_topics":      "      Temas: {topics}",
+	"result.event_data":        "      Datos: {data}",
+	"result.more_events":       "  ... y {count, plural, one {# evento más} other {# eventos más}}",
+	"result.events":            "Eventos: {count, number, integer}",
+	"result.logs":              "Registros: {count, number, integer}",
+	"result.more_logs":         "  ... y {count, plural, one {# registro más} other {# registros más}}",
+	"result.summary":           "Eventos: {events, number, integer}, Registros: {logs, number, integer}",
+
+	"compare.header":           "Comparación: {left} frente a {right}",
+	"compare.status_mismatch":  "Estados distintos: {leftStatus} ({left}) frente a {rightStatus} ({right})",
+	"compare.status_match":     "Estados iguales: {status}",
+	"compare.diagnostic_count": "[DIFF] Número de eventos de diagnóstico distinto: {left, number, integer} frente a {right, number, integer}",
+	"compare.events_count":     "[DIFF] Número de eventos distinto: {left, number, integer} frente a {right, number, integer}",
+	"compare.cpu":              "[DIFF] Instrucciones de CPU: {left, number, integer} frente a {right, number, integer}",
+	"compare.memory":           "[DIFF] Bytes de memoria: {left, number, integer} frente a {right, number, integer}",
+	"compare.log_diff":         "Diferencias de registros:",
+	"compare.no_differences":   "  Sin diferencias",
+	"compare.event_diff":       "Diferencias de eventos:",
+	"compare.events_match":     "  {count, plural, =0 {No hay eventos que comparar} one {El único evento coincide} other {Los # eventos coinciden}}",
+	"compare.only_on":          "  {mark} [{index}] solo en {network}: {event}",
+	"compare.modified":         "  ~ [{left} -> {right}] modificado:",
+	"compare.summary":          "  {unchanged, number, integer} sin cambios, {modified, plural, one {# modificado} other {# modificados}}, {removed, number, integer} solo en {left}, {added, number, integer} solo en {right}",
+
+	"security.heading":   "Análisis de seguridad",
+	"security.no_issues": "{mark} No se detectaron problemas de seguridad",
+	"tokenflow.heading":  "Resumen del flujo de tokens:",
+
+	"demo.cpu":             "  Instrucciones de CPU: {used, number, integer}",
+	"demo.memory":          "  Bytes de memoria: {used, number, integer}",
+	"demo.xlm_transferred": "  {arrow} XLM transferidos",
+	"demo.session_ready":   "Sesión lista. Use 'erst session save' para guardarla.",
 }
 
 var ChineseMessages = map[string]string{
@@ -133,6 +243,61 @@ var ChineseMessages = map[string]string{
 	"validation.model_required":   "gas 模型文件路径不能为空",
 	"validation.model_file_read":  "读取 gas 模型文件失败: %w",
 	"validation.json_parse_error": "解析 gas 模型 JSON 失败: %w",
+
+	// ICU messages, rendered with Format
+	"debug.debugging_tx":       "正在调试交易: {hash}",
+	"debug.network":            "网络: {network}",
+	"debug.rpc_url":            "RPC URL: {url}",
+	"debug.primary_network":    "主网络: {network}",
+	"debug.compare_network":    "对比网络: {network}",
+	"debug.fetching_tx":        "正在获取交易: {hash}",
+	"debug.tx_fetched":         "交易获取成功。交易包大小: {size, number, integer} 字节",
+	"debug.simulating_at":      "--- 在时间戳 {timestamp} 进行模拟 ---",
+	"debug.running_simulation": "正在 {network} 上运行模拟...",
+
+	"result.header":            "--- {network} 的结果 ---",
+	"result.status":            "状态: {status}",
+	"result.error":             "错误: {error}",
+	"result.resource_usage":    "资源使用:",
+	"result.cpu":               "  CPU 指令: {used, number, integer} / {limit, number, integer} ({percent, number, ::percent .00}){level, select, critical { [!]  严重} warning { [!]  警告} other {}}",
+	"result.memory":            "  内存字节: {used, number, integer} / {limit, number, integer} ({percent, number, ::percent .00}){level, select, critical { [!]  严重} warning { [!]  警告} other {}}",
+	"result.operations":        "  操作数: {count, number, integer}",
+	"result.metering":          "主机计量:",
+	"result.meter_phase":       "  {phase}: CPU {cpu, number, integer} 条指令, 内存 {mem, number, integer} 字节",
+	"result.diagnostic_events": "诊断事件: {count, number, integer}",
+	"result.event_type":        "  [{index}] 类型: {type}",
+	"result.event_contract":    ", 合约: {contract}",
+	"result.event_topics":      "      主题: {topics}",
+	"result.event_data":        "      数据: {data}",
+	"result.more_events":       "  ... 还有 {count, number, integer} 个事件",
+	"result.events":            "事件: {count, number, integer}",
+	"result.logs":              "日志: {count, number, integer}",
+	"result.more_logs":         "  ... 还有 {count, number, integer} 条日志",
+	"result.summary":           "事件: {events, number, integer}, 日志: {logs, number, integer}",
+
+	"compare.header":           "对比: {left} 与 {right}",
+	"compare.status_mismatch":  "状态不一致: {leftStatus} ({left}) 与 {rightStatus} ({right})",
+	"compare.status_match":     "状态一致: {status}",
+	"compare.diagnostic_count": "[DIFF] 诊断事件数量不一致: {left, number, integer} 与 {right, number, integer}",
+	"compare.events_count":     "[DIFF] 事件数量不一致: {left, number, integer} 与 {right, number, integer}",
+	"compare.cpu":              "[DIFF] CPU 指令: {left, number, integer} 与 {right, number, integer}",
+	"compare.memory":           "[DIFF] 内存字节: {left, number, integer} 与 {right, number, integer}",
+	"compare.log_diff":         "日志差异:",
+	"compare.no_differences":   "  无差异",
+	"compare.event_diff":       "事件差异:",
+	"compare.events_match":     "  {count, plural, =0 {没有可对比的事件} other {全部 # 个事件一致}}",
+	"compare.only_on":          "  {mark} [{index}] 仅在 {network}: {event}",
+	"compare.modified":         "  ~ [{left} -> {right}] 已修改:",
+	"compare.summary":          "  {unchanged, number, integer} 个未变, {modified, number, integer} 个已修改, {removed, number, integer} 个仅在 {left}, {added, number, integer} 个仅在 {right}",
+
+	"security.heading":   "安全分析",
+	"security.no_issues": "{mark} 未发现安全问题",
+	"tokenflow.heading":  "代币流摘要:",
+
+	"demo.cpu":             "  CPU 指令: {used, number, integer}",
+	"demo.memory":          "  内存字节: {used, number, integer}",
+	"demo.xlm_transferred": "  {arrow} 已转移 XLM",
+	"demo.session_ready":   "会话已就绪。使用 'erst session save' 保存。",
 }
 
 func LoadTranslations() error {
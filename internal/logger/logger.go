@@ -68,6 +68,10 @@ func SetLevel(lvl slog.Level) {
 	level.Set(lvl)
 }
 
+func GetLevel() slog.Level {
+	return level.Level()
+}
+
 func SetOutput(w io.Writer, useJSON bool) {
 	mu.Lock()
 	defer mu.Unlock()
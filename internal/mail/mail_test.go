@@ -0,0 +1,101 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package mail
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuild(t *testing.T) {
+	data, err := Build("erst@example.com", Message{
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "Digest – 3 changes",
+		Text:    "plain body",
+		HTML:    "<p>html body</p>",
+	}, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	m, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(m.Header.Get("Subject"))
+	if err != nil || subject != "Digest – 3 changes" {
+		t.Errorf("subject = %q (%v)", subject, err)
+	}
+	if got := m.Header.Get("To"); got != "a@example.com, b@example.com" {
+		t.Errorf("To = %q", got)
+	}
+
+	_, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("content type: %v", err)
+	}
+	r := multipart.NewReader(m.Body, params["boundary"])
+	var bodies []string
+	for {
+		p, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		body, _ := io.ReadAll(p)
+		bodies = append(bodies, p.Header.Get("Content-Type")+": "+string(body))
+	}
+	want := []string{"text/plain; charset=utf-8: plain body", "text/html; charset=utf-8: <p>html body</p>"}
+	if len(bodies) != 2 || bodies[0] != want[0] || bodies[1] != want[1] {
+		t.Errorf("parts = %q", bodies)
+	}
+}
+
+func TestBuildRejectsHeaderInjection(t *testing.T) {
+	if _, err := Build("erst@example.com", Message{To: []string{"a@example.com\r\nBcc: x@example.com"}}, time.Now()); err == nil {
+		t.Error("expected an address with a newline to be rejected")
+	}
+}
+
+func TestSMTPSend(t *testing.T) {
+	if _, err := NewSMTP(Config{From: "erst@example.com"}); err == nil {
+		t.Error("expected a missing host to be rejected")
+	}
+
+	s, err := NewSMTP(Config{Host: "smtp.example.com", Username: "u", Password: "p", From: "erst@example.com"})
+	if err != nil {
+		t.Fatalf("NewSMTP: %v", err)
+	}
+	var gotAddr string
+	var gotTo []string
+	var gotAuth smtp.Auth
+	s.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotAuth, gotTo = addr, a, to
+		return nil
+	}
+	if err := s.Send(Message{To: []string{"a@example.com"}, Subject: "s", Text: "t"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" || gotAuth == nil || len(gotTo) != 1 {
+		t.Errorf("addr=%q auth=%v to=%v", gotAddr, gotAuth, gotTo)
+	}
+
+	if err := s.Send(Message{Subject: "s"}); err == nil {
+		t.Error("expected a message without recipients to be rejected")
+	}
+
+	s.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("535 bad credentials") }
+	if err := s.Send(Message{To: []string{"a@example.com"}}); err == nil || !strings.Contains(err.Error(), "535") {
+		t.Errorf("expected the server error, got %v", err)
+	}
+}
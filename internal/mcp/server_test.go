@@ -0,0 +1,177 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSessions struct {
+	sessions []*session.SessionData
+}
+
+func (f *fakeSessions) List(ctx context.Context, limit int) ([]*session.SessionData, error) {
+	return f.sessions, nil
+}
+
+func (f *fakeSessions) ListByContext(ctx context.Context, filter session.Context, limit int) ([]*session.SessionData, error) {
+	var out []*session.SessionData
+	for _, s := range f.sessions {
+		if s.Context.Matches(filter) {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSessions) Save(ctx context.Context, data *session.SessionData) error {
+	for i, s := range f.sessions {
+		if s.ID == data.ID {
+			f.sessions[i] = data
+			return nil
+		}
+	}
+	f.sessions = append(f.sessions, data)
+	return nil
+}
+
+func (f *fakeSessions) Load(ctx context.Context, id string) (*session.SessionData, error) {
+	for _, s := range f.sessions {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, assert.AnError
+}
+
+func newTestServer() *Server {
+	srv := NewServer("erst", "test")
+	RegisterErstTools(srv, Deps{
+		ClientFor: func(string) (*rpc.Client, error) { return nil, assert.AnError },
+		Runner:    simulator.NewDefaultMockRunner(),
+		Sessions: &fakeSessions{sessions: []*session.SessionData{
+			{ID: "s1", TxHash: "abc", Network: "testnet", CreatedAt: time.Unix(0, 0).UTC()},
+		}},
+	})
+	return srv
+}
+
+func roundTrip(t *testing.T, srv *Server, lines ...string) []map[string]interface{} {
+	t.Helper()
+
+	var out strings.Builder
+	require.NoError(t, srv.Serve(context.Background(), strings.NewReader(strings.Join(lines, "\n")), &out))
+
+	var responses []map[string]interface{}
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &m))
+		responses = append(responses, m)
+	}
+	return responses
+}
+
+func TestServe_InitializeAndList(t *testing.T) {
+	responses := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"nope"}`,
+	)
+	require.Len(t, responses, 3, "notifications must not be answered")
+
+	initResult := responses[0]["result"].(map[string]interface{})
+	assert.Equal(t, ProtocolVersion, initResult["protocolVersion"])
+
+	tools := responses[1]["result"].(map[string]interface{})["tools"].([]interface{})
+	var names []string
+	for _, tool := range tools {
+		m := tool.(map[string]interface{})
+		names = append(names, m["name"].(string))
+		assert.Equal(t, "object", m["inputSchema"].(map[string]interface{})["type"])
+	}
+	assert.Equal(t, []string{"debug_transaction", "decode_xdr", "get_session", "list_sessions", "set_session_context", "simulate"}, names)
+
+	assert.Equal(t, float64(codeMethodNotFound), responses[2]["error"].(map[string]interface{})["code"])
+}
+
+func TestServe_ToolCalls(t *testing.T) {
+	responses := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"simulate","arguments":{"envelope_xdr":"AAAA"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_sessions","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"debug_transaction","arguments":{"hash":"bad"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"missing"}}`,
+	)
+	require.Len(t, responses, 4)
+
+	text := func(i int) (string, bool) {
+		result := responses[i]["result"].(map[string]interface{})
+		content := result["content"].([]interface{})[0].(map[string]interface{})
+		isErr, _ := result["isError"].(bool)
+		return content["text"].(string), isErr
+	}
+
+	out, isErr := text(0)
+	assert.False(t, isErr)
+	assert.Contains(t, out, `"status": "success"`)
+
+	out, isErr = text(1)
+	assert.False(t, isErr)
+	assert.Contains(t, out, `"id": "s1"`)
+
+	out, isErr = text(2)
+	assert.True(t, isErr, "tool failures are reported in the result")
+	assert.Contains(t, out, "invalid transaction hash")
+
+	assert.Equal(t, float64(codeInvalidParams), responses[3]["error"].(map[string]interface{})["code"])
+}
+
+func TestSessionContextTools(t *testing.T) {
+	responses := roundTrip(t, newTestServer(),
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"set_session_context","arguments":{"id":"s1","anchor":"acme","sep_flow":"SEP-24"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"list_sessions","arguments":{"sep_flow":"sep24"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"list_sessions","arguments":{"anchor":"other"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"set_session_context","arguments":{"id":"s1","sep_flow":"sep99"}}}`,
+	)
+	require.Len(t, responses, 4)
+
+	text := func(i int) (string, bool) {
+		result := responses[i]["result"].(map[string]interface{})
+		content := result["content"].([]interface{})[0].(map[string]interface{})
+		isErr, _ := result["isError"].(bool)
+		return content["text"].(string), isErr
+	}
+
+	out, isErr := text(0)
+	assert.False(t, isErr)
+	assert.Contains(t, out, `"sep_flow": "sep24"`)
+
+	out, _ = text(1)
+	assert.Contains(t, out, `"id": "s1"`)
+	assert.Contains(t, out, `"anchor": "acme"`)
+
+	out, _ = text(2)
+	assert.NotContains(t, out, `"id": "s1"`)
+
+	out, isErr = text(3)
+	assert.True(t, isErr)
+	assert.Contains(t, out, "unknown SEP flow")
+}
+
+func TestDecodeXDR_Unsupported(t *testing.T) {
+	_, err := decodeXDR(context.Background(), json.RawMessage(`{"xdr":"AAAA","type":"bogus"}`))
+	assert.Error(t, err)
+}
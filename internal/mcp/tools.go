@@ -0,0 +1,330 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// SessionStore is the subset of the session store used by the session tools
+type SessionStore interface {
+	List(ctx context.Context, limit int) ([]*session.SessionData, error)
+	ListByContext(ctx context.Context, filter session.Context, limit int) ([]*session.SessionData, error)
+	Load(ctx context.Context, sessionID string) (*session.SessionData, error)
+	Save(ctx context.Context, data *session.SessionData) error
+}
+
+// Deps are the erst components the tools operate on
+type Deps struct {
+	// ClientFor returns an RPC client for a network name ("" for the default)
+	ClientFor func(network string) (*rpc.Client, error)
+	Runner    simulator.RunnerInterface
+	Sessions  SessionStore
+}
+
+// RegisterErstTools adds the debug, decode, simulate and session tools
+func RegisterErstTools(s *Server, deps Deps) {
+	s.Register(Tool{
+		Name:        "debug_transaction",
+		Description: "Fetch a Stellar transaction by hash, replay it in the simulator and return its status, decoded result code, events, logs and security findings. Use this first when triaging a failed transaction.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"hash":    stringProp("64-character hex transaction hash"),
+			"network": enumProp("Network to fetch from (defaults to the server network)", "mainnet", "testnet", "futurenet"),
+		}, "hash"),
+		Handler: deps.debugTransaction,
+	})
+
+	s.Register(Tool{
+		Name:        "decode_xdr",
+		Description: "Decode a base64 XDR value into JSON. Supported types: transaction_envelope, transaction_result, ledger_entry, diagnostic_event.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"xdr":  stringProp("Base64-encoded XDR"),
+			"type": enumProp("XDR type of the value", "transaction_envelope", "transaction_result", "ledger_entry", "diagnostic_event"),
+		}, "xdr", "type"),
+		Handler: decodeXDR,
+	})
+
+	s.Register(Tool{
+		Name:        "simulate",
+		Description: "Run the erst simulator on an envelope with optional result meta and ledger entries, returning status, events, logs and budget usage.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"envelope_xdr":    stringProp("Base64 TransactionEnvelope XDR"),
+			"result_meta_xdr": stringProp("Base64 TransactionMeta XDR used to seed ledger state"),
+			"ledger_entries": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Map of base64 LedgerKey XDR to base64 LedgerEntry XDR",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"protocol_version": map[string]interface{}{
+				"type":        "integer",
+				"description": "Protocol version to simulate under",
+			},
+		}, "envelope_xdr"),
+		Handler: deps.simulate,
+	})
+
+	if deps.Sessions == nil {
+		return
+	}
+
+	s.Register(Tool{
+		Name:        "list_sessions",
+		Description: "List saved erst debug sessions, most recently accessed first, optionally only those of an anchor, SEP flow or customer reference.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum number of sessions to return",
+				"default":     20,
+			},
+			"anchor":       stringProp("Only sessions attached to this anchor"),
+			"sep_flow":     stringProp("Only sessions of this SEP flow, e.g. sep24"),
+			"customer_ref": stringProp("Only sessions with this customer reference"),
+		}),
+		Handler: deps.listSessions,
+	})
+
+	s.Register(Tool{
+		Name:        "get_session",
+		Description: "Load a saved debug session including its transaction XDR and stored simulation response.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"id": stringProp("Session ID"),
+		}, "id"),
+		Handler: deps.getSession,
+	})
+
+	s.Register(Tool{
+		Name:        "set_session_context",
+		Description: "Attach the anchor, SEP flow and customer reference a saved debug session belongs to. Omitted fields keep their value.",
+		InputSchema: objectSchema(map[string]interface{}{
+			"id":           stringProp("Session ID"),
+			"anchor":       stringProp("Anchor the session belongs to"),
+			"sep_flow":     stringProp("SEP flow of the transaction: sep6, sep10, sep12, sep24, sep31 or sep38"),
+			"customer_ref": stringProp("Customer or transfer reference from the anchor's records"),
+		}, "id"),
+		Handler: deps.setSessionContext,
+	})
+}
+
+func objectSchema(props map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": props,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringProp(desc string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": desc}
+}
+
+func enumProp(desc string, values ...string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": desc, "enum": values}
+}
+
+// DebugOutput is the result of the debug_transaction tool
+type DebugOutput struct {
+	Hash        string                        `json:"hash"`
+	Network     string                        `json:"network"`
+	Status      string                        `json:"status"`
+	Error       string                        `json:"error,omitempty"`
+	ResultCode  string                        `json:"result_code,omitempty"`
+	Simulation  *simulator.SimulationResponse `json:"simulation,omitempty"`
+	Findings    []security.Finding            `json:"findings"`
+	EnvelopeXdr string                        `json:"envelope_xdr"`
+}
+
+func (d Deps) debugTransaction(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		Hash    string `json:"hash"`
+		Network string `json:"network"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if err := rpc.ValidateTransactionHash(args.Hash); err != nil {
+		return nil, fmt.Errorf("invalid transaction hash: %w", err)
+	}
+
+	client, err := d.ClientFor(args.Network)
+	if err != nil {
+		return nil, err
+	}
+
+	txResp, err := client.GetTransaction(ctx, args.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+
+	out := &DebugOutput{
+		Hash:        args.Hash,
+		Network:     string(client.Network),
+		EnvelopeXdr: txResp.EnvelopeXdr,
+		Findings:    []security.Finding{},
+	}
+	if code, err := decoder.DecodeResultXDR(txResp.ResultXdr); err == nil {
+		out.ResultCode = code
+	}
+
+	entries, _ := rpc.ExtractLedgerEntriesFromMeta(txResp.ResultMetaXdr)
+	simResp, err := d.Runner.Run(&simulator.SimulationRequest{
+		EnvelopeXdr:   txResp.EnvelopeXdr,
+		ResultMetaXdr: txResp.ResultMetaXdr,
+		LedgerEntries: entries,
+	})
+	if err != nil {
+		out.Status = "error"
+		out.Error = err.Error()
+		return out, nil
+	}
+
+	out.Status = simResp.Status
+	out.Error = simResp.Error
+	out.Simulation = simResp
+	out.Findings = security.NewDetector().AnalyzeSimulation(txResp.EnvelopeXdr, txResp.ResultMetaXdr, simResp)
+	return out, nil
+}
+
+func decodeXDR(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		XDR  string `json:"xdr"`
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+
+	var target interface{}
+	switch args.Type {
+	case "transaction_envelope":
+		target = &xdr.TransactionEnvelope{}
+	case "transaction_result":
+		text, err := decoder.DecodeResultXDR(args.XDR)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]string{"summary": text}, nil
+	case "ledger_entry":
+		target = &xdr.LedgerEntry{}
+	case "diagnostic_event":
+		target = &xdr.DiagnosticEvent{}
+	default:
+		return nil, fmt.Errorf("unsupported XDR type: %s", args.Type)
+	}
+
+	if err := xdr.SafeUnmarshalBase64(args.XDR, target); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", args.Type, err)
+	}
+	return target, nil
+}
+
+func (d Deps) simulate(_ context.Context, raw json.RawMessage) (interface{}, error) {
+	var req simulator.SimulationRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if req.EnvelopeXdr == "" {
+		return nil, fmt.Errorf("envelope_xdr is required")
+	}
+	return d.Runner.Run(&req)
+}
+
+func (d Deps) listSessions(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	args := struct {
+		Limit       int    `json:"limit"`
+		Anchor      string `json:"anchor"`
+		SEPFlow     string `json:"sep_flow"`
+		CustomerRef string `json:"customer_ref"`
+	}{Limit: 20}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	filter, err := session.NewContext(args.Anchor, args.SEPFlow, args.CustomerRef)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := d.Sessions.ListByContext(ctx, filter, args.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	type summary struct {
+		ID      string           `json:"id"`
+		TxHash  string           `json:"tx_hash"`
+		Network string           `json:"network"`
+		Status  string           `json:"status"`
+		Created string           `json:"created_at"`
+		Context *session.Context `json:"context,omitempty"`
+	}
+	out := make([]summary, 0, len(sessions))
+	for _, s := range sessions {
+		item := summary{
+			ID:      s.ID,
+			TxHash:  s.TxHash,
+			Network: s.Network,
+			Status:  s.Status,
+			Created: s.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		}
+		if !s.Context.IsZero() {
+			c := s.Context
+			item.Context = &c
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func (d Deps) setSessionContext(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID          string `json:"id"`
+		Anchor      string `json:"anchor"`
+		SEPFlow     string `json:"sep_flow"`
+		CustomerRef string `json:"customer_ref"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if args.ID == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	update, err := session.NewContext(args.Anchor, args.SEPFlow, args.CustomerRef)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := d.Sessions.Load(ctx, args.ID)
+	if err != nil {
+		return nil, err
+	}
+	data.Context = data.Context.Merge(update)
+	if err := d.Sessions.Save(ctx, data); err != nil {
+		return nil, err
+	}
+	return data.Context, nil
+}
+
+func (d Deps) getSession(ctx context.Context, raw json.RawMessage) (interface{}, error) {
+	var args struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &args); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %w", err)
+	}
+	if args.ID == "" {
+		return nil, fmt.Errorf("id is required")
+	}
+	return d.Sessions.Load(ctx, args.ID)
+}
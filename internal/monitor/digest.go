@@ -0,0 +1,258 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/report"
+)
+
+const digestName = "digest.json"
+
+// Period is how often a digest is sent
+type Period string
+
+const (
+	Daily  Period = "daily"
+	Weekly Period = "weekly"
+)
+
+// ParsePeriod accepts "daily" or "weekly"
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(strings.ToLower(strings.TrimSpace(s))); p {
+	case Daily, Weekly:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown digest period %q (use daily or weekly)", s)
+	}
+}
+
+// Duration is the time a digest of the period covers
+func (p Period) Duration() time.Duration {
+	if p == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// WatchDigest summarizes the checks of one watch
+type WatchDigest struct {
+	Watch    string
+	Checks   int
+	Failures int
+	// LastStatus is the result of the latest check
+	LastStatus string
+	Drift      []Event
+	NewRisks   []Event
+	Errors     []Event
+}
+
+// Digest summarizes the monitor history of a period
+type Digest struct {
+	Period  Period
+	From    time.Time
+	To      time.Time
+	Watches []WatchDigest
+}
+
+// BuildDigest summarizes the events between from and to, by watch
+func BuildDigest(period Period, events []Event, from, to time.Time) *Digest {
+	byWatch := make(map[string]*WatchDigest)
+	for _, e := range events {
+		if e.Time.Before(from) || !e.Time.Before(to) {
+			continue
+		}
+		wd, ok := byWatch[e.Watch]
+		if !ok {
+			wd = &WatchDigest{Watch: e.Watch}
+			byWatch[e.Watch] = wd
+		}
+		switch e.Kind {
+		case KindCheck:
+			wd.Checks++
+			wd.LastStatus = e.Status
+			if e.Status != "success" {
+				wd.Failures++
+			}
+		case KindDrift:
+			wd.Drift = append(wd.Drift, e)
+		case KindNewRisk:
+			wd.NewRisks = append(wd.NewRisks, e)
+		case KindError:
+			wd.Errors = append(wd.Errors, e)
+			wd.LastStatus = KindError
+		}
+	}
+
+	d := &Digest{Period: period, From: from, To: to}
+	for _, wd := range byWatch {
+		d.Watches = append(d.Watches, *wd)
+	}
+	sort.Slice(d.Watches, func(i, j int) bool { return d.Watches[i].Watch < d.Watches[j].Watch })
+	return d
+}
+
+// totals returns the number of checks, failed checks, drift events and new
+// risks across all watches
+func (d *Digest) totals() (checks, failures, drift, risks int) {
+	for _, wd := range d.Watches {
+		checks += wd.Checks
+		failures += wd.Failures + len(wd.Errors)
+		drift += len(wd.Drift)
+		risks += len(wd.NewRisks)
+	}
+	return checks, failures, drift, risks
+}
+
+// Subject is the email subject of the digest
+func (d *Digest) Subject() string {
+	_, failures, drift, risks := d.totals()
+	return fmt.Sprintf("erst monitor %s digest %s: %d failure(s), %d drift, %d new risk(s)",
+		d.Period, d.To.Format("2006-01-02"), failures, drift, risks)
+}
+
+// Text renders the digest as plain text
+func (d *Digest) Text() string {
+	checks, failures, drift, risks := d.totals()
+	var b strings.Builder
+	fmt.Fprintf(&b, "erst monitor %s digest, %s to %s\n\n", d.Period, d.From.Format(time.RFC3339), d.To.Format(time.RFC3339))
+	fmt.Fprintf(&b, "%d check(s) of %d watch(es): %d failure(s), %d drift, %d new risk(s)\n", checks, len(d.Watches), failures, drift, risks)
+	for _, wd := range d.Watches {
+		fmt.Fprintf(&b, "\n%s: %d check(s), %d failure(s), last %s\n", wd.Watch, wd.Checks, wd.Failures+len(wd.Errors), wd.LastStatus)
+		for _, group := range [][]Event{wd.NewRisks, wd.Drift, wd.Errors} {
+			for _, e := range group {
+				fmt.Fprintf(&b, "  %s  %s\n", e.Time.Format(time.RFC3339), eventLine(e))
+			}
+		}
+	}
+	if len(d.Watches) == 0 {
+		b.WriteString("\nNo checks ran in this period.\n")
+	}
+	return b.String()
+}
+
+// Report renders the digest with the standard erst report layout
+func (d *Digest) Report() *report.Builder {
+	checks, failures, drift, risks := d.totals()
+	b := report.NewBuilder(fmt.Sprintf("erst monitor %s digest", d.Period))
+
+	status := "success"
+	if failures+drift+risks > 0 {
+		status = "error"
+	}
+	successRate := 100.0
+	if checks > 0 {
+		successRate = float64(checks-min(failures, checks)) / float64(checks) * 100
+	}
+	b.SetSummary(status, d.Period.Duration().String(), checks, failures, len(d.Watches), successRate)
+	b.AddKeyFinding(fmt.Sprintf("%d check(s) of %d watch(es) from %s to %s", checks, len(d.Watches), d.From.Format(time.RFC3339), d.To.Format(time.RFC3339)))
+	if failures+drift+risks == 0 {
+		b.AddKeyFinding("No failures, drift or new risks")
+	}
+
+	for i, wd := range d.Watches {
+		stepStatus := "success"
+		if wd.LastStatus != "success" {
+			stepStatus = "error"
+		}
+		b.AddExecutionStep(i, wd.Watch, stepStatus,
+			fmt.Sprintf("%d check(s), %d failure(s), %d drift, %d new risk(s)", wd.Checks, wd.Failures+len(wd.Errors), len(wd.Drift), len(wd.NewRisks)))
+		for _, e := range wd.NewRisks {
+			b.AddIssue(KindNewRisk, e.Severity, e.Summary, wd.Watch, e.FindingID)
+		}
+		for _, e := range wd.Drift {
+			b.AddIssue(KindDrift, "MEDIUM", eventLine(e), wd.Watch, e.Time.Format(time.RFC3339))
+		}
+		for _, e := range wd.Errors {
+			b.AddWarning(fmt.Sprintf("%s %s", e.Time.Format(time.RFC3339), eventLine(e)))
+		}
+	}
+	if risks > 0 {
+		b.SetRiskAssessment("HIGH", 75)
+	} else if failures+drift > 0 {
+		b.SetRiskAssessment("MEDIUM", 50)
+	}
+	b.SetMetadata("erst monitor", "", map[string]string{
+		"period": string(d.Period),
+		"from":   d.From.Format(time.RFC3339),
+		"to":     d.To.Format(time.RFC3339),
+	})
+	return b
+}
+
+func eventLine(e Event) string {
+	if e.Error != "" {
+		return e.Summary + ": " + e.Error
+	}
+	return e.Summary
+}
+
+// DigestSent returns when the last digest of period was sent
+func (s *Store) DigestSent(period Period) (time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sent, err := s.loadDigests()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	t, ok := sent[period]
+	return t, ok, nil
+}
+
+// SetDigestSent records that the digest of period covering up to t was sent
+func (s *Store) SetDigestSent(period Period, t time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sent, err := s.loadDigests()
+	if err != nil {
+		return err
+	}
+	sent[period] = t
+	data, err := json.MarshalIndent(sent, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode digest state: %w", err)
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create monitor directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir, digestName), data, 0600); err != nil {
+		return fmt.Errorf("failed to write digest state: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) loadDigests() (map[Period]time.Time, error) {
+	sent := make(map[Period]time.Time)
+	data, err := os.ReadFile(filepath.Join(s.Dir, digestName))
+	if os.IsNotExist(err) {
+		return sent, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read digest state: %w", err)
+	}
+	if err := json.Unmarshal(data, &sent); err != nil {
+		return nil, fmt.Errorf("failed to parse digest state: %w", err)
+	}
+	return sent, nil
+}
+
+// DueDigest returns the start of the next digest of period and whether it
+// is due at now. The first window starts the first time it is asked for.
+func (s *Store) DueDigest(period Period, now time.Time) (time.Time, bool, error) {
+	last, ok, err := s.DigestSent(period)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !ok {
+		return now, false, s.SetDigestSent(period, now)
+	}
+	return last, !now.Before(last.Add(period.Duration())), nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildDigest(t *testing.T) {
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	at := func(h int) time.Time { return from.Add(time.Duration(h) * time.Hour) }
+	events := []Event{
+		{Time: from.Add(-time.Hour), Watch: "swap", Kind: KindCheck, Status: "error"},
+		{Time: at(1), Watch: "swap", Kind: KindCheck, Status: "success"},
+		{Time: at(2), Watch: "swap", Kind: KindCheck, Status: "error", Error: "trapped"},
+		{Time: at(2), Watch: "swap", Kind: KindDrift, Status: "error", Error: "trapped", Summary: "swap now fails"},
+		{Time: at(3), Watch: "mint", Kind: KindCheck, Status: "success"},
+		{Time: at(3), Watch: "mint", Kind: KindNewRisk, Summary: "new verified risk on mint: Reentrancy", FindingID: "f1", Severity: "HIGH"},
+		{Time: at(4), Watch: "mint", Kind: KindError, Summary: "check of mint failed", Error: "rpc down"},
+		{Time: to, Watch: "mint", Kind: KindCheck, Status: "error"},
+	}
+
+	d := BuildDigest(Daily, events, from, to)
+	if len(d.Watches) != 2 || d.Watches[0].Watch != "mint" || d.Watches[1].Watch != "swap" {
+		t.Fatalf("unexpected watches: %+v", d.Watches)
+	}
+	mint, swap := d.Watches[0], d.Watches[1]
+	if mint.Checks != 1 || len(mint.NewRisks) != 1 || len(mint.Errors) != 1 || mint.LastStatus != KindError {
+		t.Errorf("unexpected mint digest: %+v", mint)
+	}
+	if swap.Checks != 2 || swap.Failures != 1 || len(swap.Drift) != 1 || swap.LastStatus != "error" {
+		t.Errorf("unexpected swap digest: %+v", swap)
+	}
+
+	if got := d.Subject(); got != "erst monitor daily digest 2026-01-06: 2 failure(s), 1 drift, 1 new risk(s)" {
+		t.Errorf("subject = %q", got)
+	}
+	text := d.Text()
+	for _, want := range []string{"3 check(s) of 2 watch(es)", "swap now fails: trapped", "Reentrancy", "rpc down"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text digest is missing %q:\n%s", want, text)
+		}
+	}
+
+	html, err := d.Report().ExportHTML()
+	if err != nil {
+		t.Fatalf("ExportHTML: %v", err)
+	}
+	for _, want := range []string{"erst monitor daily digest", "swap now fails", "Reentrancy"} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("HTML digest is missing %q", want)
+		}
+	}
+
+	empty := BuildDigest(Weekly, nil, from, to)
+	if !strings.Contains(empty.Text(), "No checks ran") {
+		t.Errorf("unexpected empty digest: %s", empty.Text())
+	}
+}
+
+func TestDueDigest(t *testing.T) {
+	s := &Store{Dir: t.TempDir()}
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+
+	from, due, err := s.DueDigest(Daily, start)
+	if err != nil || due || !from.Equal(start) {
+		t.Fatalf("first call: from=%v due=%v err=%v", from, due, err)
+	}
+	if _, due, _ := s.DueDigest(Daily, start.Add(23*time.Hour)); due {
+		t.Error("expected no digest before a day has passed")
+	}
+	from, due, err = s.DueDigest(Daily, start.Add(24*time.Hour))
+	if err != nil || !due || !from.Equal(start) {
+		t.Errorf("after a day: from=%v due=%v err=%v", from, due, err)
+	}
+	if _, due, _ := s.DueDigest(Weekly, start.Add(24*time.Hour)); due {
+		t.Error("expected periods to be tracked separately")
+	}
+
+	if err := s.SetDigestSent(Daily, start.Add(24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, due, _ := s.DueDigest(Daily, start.Add(25*time.Hour)); due {
+		t.Error("expected the window to restart after sending")
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	if p, err := ParsePeriod(" Weekly "); err != nil || p != Weekly {
+		t.Errorf("got %q, %v", p, err)
+	}
+	if _, err := ParsePeriod("hourly"); err == nil {
+		t.Error("expected an unknown period to be rejected")
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package monitor re-simulates canary transactions on a schedule and alerts
+// when their result changes or a new verified security risk appears
+package monitor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/alert"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// DefaultInterval is how often a watch is checked when none is configured
+const DefaultInterval = 15 * time.Minute
+
+// Kinds of events
+const (
+	// KindCheck records every completed check
+	KindCheck = "check"
+	// KindDrift is a canary whose result differs from the previous check
+	KindDrift = "drift"
+	// KindNewRisk is a verified security risk not seen before
+	KindNewRisk = "new_risk"
+	// KindError is a check that could not run
+	KindError = "error"
+)
+
+// Watch is a canary transaction to re-simulate
+type Watch struct {
+	Name string
+	// Session is the saved session whose transaction is replayed
+	Session string
+	Network string
+	// Interval defaults to DefaultInterval
+	Interval time.Duration
+	// RefreshState replays against the current ledger state instead of the
+	// state recorded with the session
+	RefreshState bool
+	Alerters     []alert.Alerter
+}
+
+// Event is something a check found
+type Event struct {
+	Time      time.Time `json:"time"`
+	Watch     string    `json:"watch"`
+	Kind      string    `json:"kind"`
+	Status    string    `json:"status,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Summary   string    `json:"summary"`
+	FindingID string    `json:"finding_id,omitempty"`
+	Severity  string    `json:"severity,omitempty"`
+	// Fingerprint is the result a drift event changed to
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// Source returns the request replayed for w and the result recorded with
+// it, which is the baseline of the first check. The recorded result may be
+// nil.
+type Source func(ctx context.Context, w Watch) (*simulator.SimulationRequest, *simulator.SimulationResponse, error)
+
+// Monitor checks watches
+type Monitor struct {
+	Runner simulator.RunnerInterface
+	Source Source
+	Store  *Store
+	Now    func() time.Time
+}
+
+func (m *Monitor) now() time.Time {
+	if m.Now != nil {
+		return m.Now()
+	}
+	return time.Now()
+}
+
+// Check re-simulates w once, records what changed since the previous check
+// and alerts on drift and new verified risks. Events whose alerts fail are
+// kept pending in the watch state and retried on the next check, so a
+// failed delivery is not lost once the new state is saved. The events are
+// returned even when alerting fails.
+func (m *Monitor) Check(ctx context.Context, w Watch) ([]Event, error) {
+	now := m.now()
+	resp, req, recorded, err := m.simulate(ctx, w)
+	if err != nil {
+		ev := Event{Time: now, Watch: w.Name, Kind: KindError, Error: err.Error(), Summary: fmt.Sprintf("check of %s failed", w.Name)}
+		if appendErr := m.Store.Append(ev); appendErr != nil {
+			return nil, errors.Join(err, appendErr)
+		}
+		return []Event{ev}, err
+	}
+
+	prev, known, err := m.Store.State(w.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !known && recorded != nil {
+		prev = WatchState{
+			Fingerprint: Fingerprint(recorded),
+			Status:      recorded.Status,
+			Error:       recorded.Error,
+			RiskIDs:     riskIDs(verifiedRisks(req, recorded)),
+		}
+		known = true
+	}
+
+	next := WatchState{Fingerprint: Fingerprint(resp), Status: resp.Status, Error: resp.Error, LastCheck: now}
+	events := []Event{{Time: now, Watch: w.Name, Kind: KindCheck, Status: resp.Status, Error: resp.Error, Summary: fmt.Sprintf("%s: %s", w.Name, resp.Status)}}
+	if known && prev.Fingerprint != next.Fingerprint {
+		events = append(events, Event{
+			Time:        now,
+			Watch:       w.Name,
+			Kind:        KindDrift,
+			Status:      resp.Status,
+			Error:       resp.Error,
+			Summary:     driftSummary(w.Name, prev, next),
+			Fingerprint: next.Fingerprint,
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range prev.RiskIDs {
+		seen[id] = true
+	}
+	risks := verifiedRisks(req, resp)
+	next.RiskIDs = riskIDs(risks)
+	for _, f := range risks {
+		if known && !seen[f.ID] {
+			events = append(events, Event{
+				Time:      now,
+				Watch:     w.Name,
+				Kind:      KindNewRisk,
+				Status:    resp.Status,
+				Summary:   fmt.Sprintf("new verified risk on %s: %s", w.Name, f.Title),
+				FindingID: f.ID,
+				Severity:  string(f.Severity),
+			})
+		}
+	}
+
+	pending, alertErr := m.alert(ctx, w, append(prev.Pending, events...))
+	next.Pending = pending
+	if err := m.Store.SetState(w.Name, next); err != nil {
+		return nil, errors.Join(alertErr, err)
+	}
+	if err := m.Store.Append(events...); err != nil {
+		return nil, errors.Join(alertErr, err)
+	}
+	return events, alertErr
+}
+
+// simulate replays the request of w
+func (m *Monitor) simulate(ctx context.Context, w Watch) (*simulator.SimulationResponse, *simulator.SimulationRequest, *simulator.SimulationResponse, error) {
+	req, recorded, err := m.Source(ctx, w)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	resp, err := m.Runner.Run(req)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("simulation failed: %w", err)
+	}
+	return resp, req, recorded, nil
+}
+
+// alert sends the drift and new risk events of a check to the alerters of
+// w, deduplicated per watch and change. It returns the events that at least
+// one alerter failed to deliver.
+func (m *Monitor) alert(ctx context.Context, w Watch, events []Event) ([]Event, error) {
+	var (
+		failed []Event
+		errs   []error
+	)
+	for _, e := range events {
+		a := alert.Alert{
+			Summary: e.Summary,
+			Source:  w.Name,
+			Details: map[string]string{"watch": w.Name, "session": w.Session, "network": w.Network, "status": e.Status},
+		}
+		switch e.Kind {
+		case KindDrift:
+			a.DedupKey = fmt.Sprintf("erst/%s/drift/%s", w.Name, e.Fingerprint[:min(16, len(e.Fingerprint))])
+			a.Severity = alert.SeverityWarning
+			if e.Status == "error" {
+				a.Severity = alert.SeverityError
+				a.Details["error"] = e.Error
+			}
+		case KindNewRisk:
+			a.DedupKey = fmt.Sprintf("erst/%s/risk/%s", w.Name, e.FindingID)
+			a.Severity = alert.SeverityError
+			if e.Severity == string(security.SeverityHigh) {
+				a.Severity = alert.SeverityCritical
+			}
+			a.Details["finding_id"] = e.FindingID
+			a.Details["severity"] = e.Severity
+		default:
+			continue
+		}
+		delivered := true
+		for _, al := range w.Alerters {
+			if err := al.Send(ctx, a); err != nil {
+				errs = append(errs, fmt.Errorf("alert %s: %w", al.Name(), err))
+				delivered = false
+			}
+		}
+		if !delivered {
+			failed = append(failed, e)
+		}
+	}
+	return failed, errors.Join(errs...)
+}
+
+// Run checks each watch now and then at its interval until ctx is done
+func (m *Monitor) Run(ctx context.Context, watches []Watch) {
+	var wg sync.WaitGroup
+	for _, w := range watches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			interval := w.Interval
+			if interval <= 0 {
+				interval = DefaultInterval
+			}
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				events, err := m.Check(ctx, w)
+				if err != nil {
+					logger.Logger.Warn("Monitor check failed", "watch", w.Name, "error", err)
+				}
+				for _, e := range events {
+					if e.Kind != KindCheck {
+						logger.Logger.Info("Monitor event", "watch", w.Name, "kind", e.Kind, "summary", e.Summary)
+					}
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Fingerprint identifies the outcome of a simulation: its status, error,
+// events and return value. Logs and resource usage are left out, as they
+// vary between otherwise identical runs.
+func Fingerprint(resp *simulator.SimulationResponse) string {
+	data, _ := json.Marshal(struct {
+		Status      string   `json:"status"`
+		Error       string   `json:"error"`
+		Events      []string `json:"events"`
+		ReturnValue string   `json:"return_value"`
+	}{resp.Status, resp.Error, resp.EventStrings(), resp.ReturnValue})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func driftSummary(name string, prev, next WatchState) string {
+	if prev.Status != next.Status {
+		return fmt.Sprintf("canary %s result changed: %s -> %s", name, prev.Status, next.Status)
+	}
+	if prev.Error != next.Error {
+		return fmt.Sprintf("canary %s error changed: %s", name, next.Error)
+	}
+	return fmt.Sprintf("canary %s result changed (events or return value)", name)
+}
+
+// verifiedRisks returns the verified risks the detector finds in resp
+func verifiedRisks(req *simulator.SimulationRequest, resp *simulator.SimulationResponse) []security.Finding {
+	var risks []security.Finding
+	for _, f := range security.NewDetector().AnalyzeSimulation(req.EnvelopeXdr, req.ResultMetaXdr, resp) {
+		if f.Type != security.FindingVerifiedRisk {
+			continue
+		}
+		if f.ID == "" {
+			f.ID = security.FindingID(f)
+		}
+		risks = append(risks, f)
+	}
+	return risks
+}
+
+func riskIDs(findings []security.Finding) []string {
+	ids := make([]string, 0, len(findings))
+	for _, f := range findings {
+		ids = append(ids, f.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,163 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/alert"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+type fakeRunner struct {
+	resp *simulator.SimulationResponse
+	err  error
+}
+
+func (r *fakeRunner) Run(*simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+	return r.resp, r.err
+}
+
+type fakeAlerter struct {
+	sent []alert.Alert
+	err  error
+}
+
+func (a *fakeAlerter) Name() string { return "fake" }
+
+func (a *fakeAlerter) Send(_ context.Context, al alert.Alert) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.sent = append(a.sent, al)
+	return nil
+}
+
+func newTestMonitor(t *testing.T, recorded *simulator.SimulationResponse) (*Monitor, *fakeRunner) {
+	t.Helper()
+	runner := &fakeRunner{}
+	now := time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)
+	return &Monitor{
+		Runner: runner,
+		Source: func(context.Context, Watch) (*simulator.SimulationRequest, *simulator.SimulationResponse, error) {
+			return &simulator.SimulationRequest{}, recorded, nil
+		},
+		Store: &Store{Dir: t.TempDir()},
+		Now:   func() time.Time { return now },
+	}, runner
+}
+
+func kinds(events []Event) []string {
+	var out []string
+	for _, e := range events {
+		out = append(out, e.Kind)
+	}
+	return out
+}
+
+func TestCheckDriftAndRisks(t *testing.T) {
+	m, runner := newTestMonitor(t, &simulator.SimulationResponse{Status: "success"})
+	pager := &fakeAlerter{}
+	w := Watch{Name: "swap", Session: "s1", Alerters: []alert.Alerter{pager}}
+
+	// Same result as recorded: nothing to report
+	runner.resp = &simulator.SimulationResponse{Status: "success"}
+	events, err := m.Check(context.Background(), w)
+	if err != nil || len(events) != 1 || events[0].Kind != KindCheck {
+		t.Fatalf("unexpected events %v, %v", kinds(events), err)
+	}
+
+	// The canary starts failing with an overflow, a verified risk
+	runner.resp = &simulator.SimulationResponse{Status: "error", Error: "HostError", Logs: []simulator.LogEntry{{Message: "attempt to add with overflow"}}}
+	events, err = m.Check(context.Background(), w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := kinds(events); len(got) != 3 || got[1] != KindDrift || got[2] != KindNewRisk {
+		t.Fatalf("unexpected events %v", got)
+	}
+	if events[1].Summary != "canary swap result changed: success -> error" {
+		t.Errorf("unexpected summary %q", events[1].Summary)
+	}
+	if len(pager.sent) != 2 || pager.sent[0].Severity != alert.SeverityError || pager.sent[1].Severity != alert.SeverityCritical {
+		t.Fatalf("unexpected alerts %+v", pager.sent)
+	}
+
+	// Failing the same way again raises nothing new
+	events, err = m.Check(context.Background(), w)
+	if err != nil || len(events) != 1 || len(pager.sent) != 2 {
+		t.Errorf("unexpected repeat events %v, %d alerts, %v", kinds(events), len(pager.sent), err)
+	}
+
+	history, err := m.Store.Events(time.Time{})
+	if err != nil || len(history) != 5 {
+		t.Errorf("expected 5 events in the history, got %d: %v", len(history), err)
+	}
+}
+
+func TestCheckRetriesFailedAlerts(t *testing.T) {
+	m, runner := newTestMonitor(t, &simulator.SimulationResponse{Status: "success"})
+	pager := &fakeAlerter{err: errors.New("pager unreachable")}
+	w := Watch{Name: "swap", Alerters: []alert.Alerter{pager}}
+
+	runner.resp = &simulator.SimulationResponse{Status: "error", Error: "HostError"}
+	if _, err := m.Check(context.Background(), w); err == nil {
+		t.Fatal("expected the alert failure to be reported")
+	}
+	st, _, err := m.Store.State("swap")
+	if err != nil || st.Status != "error" || len(st.Pending) != 1 || st.Pending[0].Kind != KindDrift {
+		t.Fatalf("expected the new state saved with the drift alert pending, got %+v, %v", st, err)
+	}
+
+	// The result does not change again, but the undelivered drift alert is
+	// retried and then cleared
+	pager.err = nil
+	events, err := m.Check(context.Background(), w)
+	if err != nil || len(events) != 1 {
+		t.Fatalf("unexpected events %v, %v", kinds(events), err)
+	}
+	if len(pager.sent) != 1 || pager.sent[0].Summary != "canary swap result changed: success -> error" {
+		t.Fatalf("expected the pending alert delivered, got %+v", pager.sent)
+	}
+	if st, _, _ := m.Store.State("swap"); len(st.Pending) != 0 {
+		t.Errorf("expected no pending alerts, got %+v", st.Pending)
+	}
+}
+
+func TestCheckWithoutBaseline(t *testing.T) {
+	m, runner := newTestMonitor(t, nil)
+	runner.resp = &simulator.SimulationResponse{Status: "error", Logs: []simulator.LogEntry{{Message: "attempt to add with overflow"}}}
+	events, err := m.Check(context.Background(), Watch{Name: "swap"})
+	if err != nil || len(events) != 1 {
+		t.Errorf("the first check without a recorded result only sets the baseline, got %v, %v", kinds(events), err)
+	}
+	st, ok, err := m.Store.State("swap")
+	if err != nil || !ok || st.Status != "error" || len(st.RiskIDs) != 1 {
+		t.Errorf("unexpected state %+v, %v, %v", st, ok, err)
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	m, runner := newTestMonitor(t, nil)
+	runner.err = errors.New("erst-sim not found")
+	events, err := m.Check(context.Background(), Watch{Name: "swap"})
+	if err == nil || len(events) != 1 || events[0].Kind != KindError {
+		t.Errorf("expected an error event, got %v, %v", kinds(events), err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := &simulator.SimulationResponse{Status: "success", Logs: []simulator.LogEntry{{Message: "one"}}}
+	b := &simulator.SimulationResponse{Status: "success", Logs: []simulator.LogEntry{{Message: "two"}}}
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Error("logs should not change the fingerprint")
+	}
+	b.ReturnValue = "AAAAAQ=="
+	if Fingerprint(a) == Fingerprint(b) {
+		t.Error("the return value should change the fingerprint")
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/platform"
+)
+
+const (
+	stateName  = "state.json"
+	eventsName = "events.jsonl"
+)
+
+// WatchState is what the monitor remembers about a watch between checks
+type WatchState struct {
+	Fingerprint string    `json:"fingerprint"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+	RiskIDs     []string  `json:"risk_ids,omitempty"`
+	LastCheck   time.Time `json:"last_check"`
+	// Pending are drift and new risk events whose alerts could not be
+	// delivered, retried on the next check
+	Pending []Event `json:"pending,omitempty"`
+}
+
+// Store keeps the state of each watch in state.json and the history of
+// checks in events.jsonl under Dir
+type Store struct {
+	Dir string
+	mu  sync.Mutex
+}
+
+// NewStore returns the store in the monitor directory of the erst data
+// directory
+func NewStore() (*Store, error) {
+	dir, err := platform.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{Dir: filepath.Join(dir, "monitor")}, nil
+}
+
+func (s *Store) loadStates() (map[string]WatchState, error) {
+	states := make(map[string]WatchState)
+	data, err := os.ReadFile(filepath.Join(s.Dir, stateName))
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read monitor state: %w", err)
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to parse monitor state: %w", err)
+	}
+	return states, nil
+}
+
+// State returns the state of the watch called name, if it was checked
+// before
+func (s *Store) State(name string) (WatchState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.loadStates()
+	if err != nil {
+		return WatchState{}, false, err
+	}
+	st, ok := states[name]
+	return st, ok, nil
+}
+
+// SetState records the state of the watch called name
+func (s *Store) SetState(name string, st WatchState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.loadStates()
+	if err != nil {
+		return err
+	}
+	states[name] = st
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode monitor state: %w", err)
+	}
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create monitor directory: %w", err)
+	}
+	tmp := filepath.Join(s.Dir, stateName+".tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write monitor state: %w", err)
+	}
+	return os.Rename(tmp, filepath.Join(s.Dir, stateName))
+}
+
+// Append adds events to the history
+func (s *Store) Append(events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(s.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create monitor directory: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(s.Dir, eventsName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open monitor history: %w", err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("failed to write monitor history: %w", err)
+		}
+	}
+	return nil
+}
+
+// Events returns the events recorded at or after since, oldest first
+func (s *Store) Events(since time.Time) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(filepath.Join(s.Dir, eventsName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open monitor history: %w", err)
+	}
+	defer f.Close()
+
+	var events []Event
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 4<<20)
+	for sc.Scan() {
+		var e Event
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("failed to parse monitor history: %w", err)
+		}
+		if !e.Time.Before(since) {
+			events = append(events, e)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read monitor history: %w", err)
+	}
+	return events, nil
+}
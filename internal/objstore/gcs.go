@@ -0,0 +1,122 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+)
+
+// GCS stores objects in a Cloud Storage bucket through the JSON API.
+// GOOGLE_OAUTH_ACCESS_TOKEN is sent as a bearer token and
+// STORAGE_EMULATOR_HOST points it at an emulator.
+type GCS struct {
+	Bucket string
+	Prefix string
+	Client *http.Client
+	Getenv func(string) string
+}
+
+// NewGCS returns a store for bucket reading the process environment
+func NewGCS(bucket, prefix string) *GCS {
+	return &GCS{Bucket: bucket, Prefix: prefix, Client: defaultHTTPClient(), Getenv: os.Getenv}
+}
+
+func (g *GCS) base() string {
+	if host := g.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		base := strings.TrimSuffix(host, "/")
+		if !strings.Contains(base, "://") {
+			base = "http://" + base
+		}
+		return base
+	}
+	return "https://storage.googleapis.com"
+}
+
+func (g *GCS) do(ctx context.Context, method, target string, body []byte, contentType string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if token := g.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return do(g.Client, req)
+}
+
+// Put uploads data as key with a simple media upload
+func (g *GCS) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	q := url.Values{"uploadType": {"media"}, "name": {joinKey(g.Prefix, key)}}
+	target := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", g.base(), url.PathEscape(g.Bucket), q.Encode())
+	rc, err := g.do(ctx, http.MethodPost, target, data, contentType)
+	if err != nil {
+		return err
+	}
+	return rc.Close()
+}
+
+// Get downloads key
+func (g *GCS) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	target := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", g.base(), url.PathEscape(g.Bucket), url.PathEscape(joinKey(g.Prefix, key)))
+	return g.do(ctx, http.MethodGet, target, nil, "")
+}
+
+type gcsListResult struct {
+	Items []struct {
+		Name string `json:"name"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// List returns the keys under prefix
+func (g *GCS) List(ctx context.Context, prefix string) ([]string, error) {
+	full := joinKey(g.Prefix, prefix)
+	var keys []string
+	token := ""
+	for {
+		q := url.Values{"prefix": {full}}
+		if token != "" {
+			q.Set("pageToken", token)
+		}
+		target := fmt.Sprintf("%s/storage/v1/b/%s/o?%s", g.base(), url.PathEscape(g.Bucket), q.Encode())
+		rc, err := g.do(ctx, http.MethodGet, target, nil, "")
+		if err != nil {
+			return nil, err
+		}
+		var page gcsListResult
+		err = json.NewDecoder(rc).Decode(&page)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse GCS listing: %w", err)
+		}
+		for _, item := range page.Items {
+			keys = append(keys, strings.TrimPrefix(strings.TrimPrefix(item.Name, g.Prefix), "/"))
+		}
+		if page.NextPageToken == "" {
+			break
+		}
+		token = page.NextPageToken
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Location returns the gs:// URL of key
+func (g *GCS) Location(key string) string {
+	return "gs://" + g.Bucket + "/" + joinKey(g.Prefix, key)
+}
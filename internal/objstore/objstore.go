@@ -0,0 +1,180 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package objstore reads and writes objects in S3, Google Cloud Storage or
+// a local directory, addressed by a location such as s3://bucket/prefix
+package objstore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Store holds objects under a prefix. Keys are slash-separated and relative
+// to the prefix.
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns the keys under prefix, sorted
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Location returns the URL of a key, for messages
+	Location(key string) string
+}
+
+// Open returns the store at location: s3://bucket/prefix, gs://bucket/prefix,
+// file:///dir or a local directory path. S3 and GCS credentials come from
+// the same environment variables as snapshot locations.
+func Open(location string) (Store, error) {
+	i := strings.Index(location, "://")
+	if i <= 1 {
+		return &Local{Dir: location}, nil
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage location %q: %w", location, err)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	switch strings.ToLower(u.Scheme) {
+	case "s3":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid S3 location %q: expected s3://bucket/prefix", location)
+		}
+		return NewS3(u.Host, prefix), nil
+	case "gs":
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid GCS location %q: expected gs://bucket/prefix", location)
+		}
+		return NewGCS(u.Host, prefix), nil
+	case "file":
+		return &Local{Dir: filepath.FromSlash(u.Host + u.Path)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported storage location scheme %q (use s3://, gs:// or a directory)", u.Scheme)
+	}
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
+
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Timeout: 5 * time.Minute}
+}
+
+// do performs req and returns the body of a successful response
+func do(client *http.Client, req *http.Request) (io.ReadCloser, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, redact(req.URL), err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s: %s", req.Method, redact(req.URL), resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// redact drops query strings, which may carry credentials, from URLs shown
+// in errors
+func redact(u *url.URL) string {
+	c := *u
+	c.RawQuery = ""
+	c.User = nil
+	return c.String()
+}
+
+func firstEnv(getenv func(string) string, names ...string) string {
+	for _, name := range names {
+		if v := getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Local stores objects as files under Dir
+type Local struct {
+	Dir string
+}
+
+func (l *Local) path(key string) (string, error) {
+	clean := path.Clean("/" + key)
+	if clean == "/" || clean != "/"+key {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(l.Dir, filepath.FromSlash(clean[1:])), nil
+}
+
+// Put writes data to the file of key
+func (l *Local) Put(_ context.Context, key string, data []byte, _ string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(p), err)
+	}
+	if err := os.WriteFile(p, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", p, err)
+	}
+	return nil
+}
+
+// Get opens the file of key
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", p, err)
+	}
+	return f, nil
+}
+
+// List walks the files under prefix
+func (l *Local) List(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(l.Dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == l.Dir {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(l.Dir, p)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", l.Dir, err)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Location returns the file path of key
+func (l *Local) Location(key string) string {
+	return filepath.Join(l.Dir, filepath.FromSlash(key))
+}
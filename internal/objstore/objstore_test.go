@@ -0,0 +1,231 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package objstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readAll(t *testing.T, s Store, key string) string {
+	t.Helper()
+	rc, err := s.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%q): %v", key, err)
+	}
+	defer rc.Close()
+	data, _ := io.ReadAll(rc)
+	return string(data)
+}
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		location string
+		want     Store
+	}{
+		{"/var/erst", &Local{Dir: "/var/erst"}},
+		{"file:///var/erst", &Local{Dir: "/var/erst"}},
+		{"s3://bucket/a/b/", &S3{Bucket: "bucket", Prefix: "a/b"}},
+		{"gs://bucket", &GCS{Bucket: "bucket"}},
+	}
+	for _, tt := range tests {
+		got, err := Open(tt.location)
+		if err != nil {
+			t.Fatalf("Open(%q): %v", tt.location, err)
+		}
+		switch g := got.(type) {
+		case *S3:
+			w := tt.want.(*S3)
+			if g.Bucket != w.Bucket || g.Prefix != w.Prefix {
+				t.Errorf("Open(%q) = %+v", tt.location, g)
+			}
+		case *GCS:
+			w := tt.want.(*GCS)
+			if g.Bucket != w.Bucket || g.Prefix != w.Prefix {
+				t.Errorf("Open(%q) = %+v", tt.location, g)
+			}
+		default:
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Open(%q) = %+v", tt.location, got)
+			}
+		}
+	}
+	for _, bad := range []string{"ftp://host/x", "s3:///x"} {
+		if _, err := Open(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	s := &Local{Dir: t.TempDir()}
+
+	keys, err := (&Local{Dir: s.Dir + "/missing"}).List(ctx, "")
+	if err != nil || len(keys) != 0 {
+		t.Errorf("listing a missing directory: %v %v", keys, err)
+	}
+
+	for _, key := range []string{"testnet/abc/2026-01-05/report.html", "testnet/abc/2026-01-06/report.html", "mainnet/abc/x"} {
+		if err := s.Put(ctx, key, []byte(key), "text/html"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	keys, err = s.List(ctx, "testnet/abc/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"testnet/abc/2026-01-05/report.html", "testnet/abc/2026-01-06/report.html"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("List = %v", keys)
+	}
+	if got := readAll(t, s, "mainnet/abc/x"); got != "mainnet/abc/x" {
+		t.Errorf("Get = %q", got)
+	}
+	if err := s.Put(ctx, "../escape", nil, ""); err == nil {
+		t.Error("expected a key leaving the directory to be rejected")
+	}
+}
+
+// fakeBucket is an in-memory S3 or GCS server
+type fakeBucket struct {
+	mu      sync.Mutex
+	objects map[string]string
+	auth    []string
+}
+
+func (b *fakeBucket) put(key, body, auth string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = body
+	b.auth = append(b.auth, auth)
+}
+
+func (b *fakeBucket) list(prefix string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var keys []string
+	for k := range b.objects {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestS3(t *testing.T) {
+	b := &fakeBucket{objects: map[string]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/archive/")
+		switch {
+		case r.Method == http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			b.put(key, string(body), r.Header.Get("Authorization"))
+		case r.URL.Query().Get("list-type") == "2":
+			keys := b.list(r.URL.Query().Get("prefix"))
+			// Page through the listing one key at a time
+			start := 0
+			if tok := r.URL.Query().Get("continuation-token"); tok != "" {
+				fmt.Sscan(tok, &start)
+			}
+			fmt.Fprint(w, "<ListBucketResult>")
+			if start < len(keys) {
+				fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", keys[start])
+			}
+			if start+1 < len(keys) {
+				fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%d</NextContinuationToken>", start+1)
+			}
+			fmt.Fprint(w, "</ListBucketResult>")
+		default:
+			body, ok := b.objects[key]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, body)
+		}
+	}))
+	defer srv.Close()
+
+	env := map[string]string{"AWS_ENDPOINT_URL": srv.URL, "AWS_ACCESS_KEY_ID": "AKID", "AWS_SECRET_ACCESS_KEY": "secret"}
+	s := &S3{Bucket: "archive", Prefix: "erst", Client: srv.Client(), Getenv: func(k string) string { return env[k] }, Now: time.Now}
+	ctx := context.Background()
+
+	for _, key := range []string{"testnet/abc/1/index.json", "testnet/abc/1/report.html", "testnet/other/1/index.json"} {
+		if err := s.Put(ctx, key, []byte("body of "+key), "application/json"); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	if _, ok := b.objects["erst/testnet/abc/1/index.json"]; !ok {
+		t.Errorf("objects = %v", b.objects)
+	}
+	if !strings.HasPrefix(b.auth[0], "AWS4-HMAC-SHA256 Credential=AKID/") {
+		t.Errorf("Authorization = %q", b.auth[0])
+	}
+
+	keys, err := s.List(ctx, "testnet/abc/")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if want := []string{"testnet/abc/1/index.json", "testnet/abc/1/report.html"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("List = %v", keys)
+	}
+	if got := readAll(t, s, "testnet/abc/1/report.html"); got != "body of testnet/abc/1/report.html" {
+		t.Errorf("Get = %q", got)
+	}
+	if got := s.Location("x"); got != "s3://archive/erst/x" {
+		t.Errorf("Location = %q", got)
+	}
+}
+
+func TestGCS(t *testing.T) {
+	b := &fakeBucket{objects: map[string]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/upload/storage/v1/b/archive/o":
+			body, _ := io.ReadAll(r.Body)
+			b.put(r.URL.Query().Get("name"), string(body), r.Header.Get("Authorization"))
+		case r.URL.Path == "/storage/v1/b/archive/o":
+			var out gcsListResult
+			for _, k := range b.list(r.URL.Query().Get("prefix")) {
+				out.Items = append(out.Items, struct {
+					Name string `json:"name"`
+				}{k})
+			}
+			json.NewEncoder(w).Encode(out)
+		default:
+			name := strings.TrimPrefix(r.URL.Path, "/storage/v1/b/archive/o/")
+			fmt.Fprint(w, b.objects[name])
+		}
+	}))
+	defer srv.Close()
+
+	env := map[string]string{"STORAGE_EMULATOR_HOST": srv.URL, "GOOGLE_OAUTH_ACCESS_TOKEN": "ya29.token"}
+	s := &GCS{Bucket: "archive", Client: srv.Client(), Getenv: func(k string) string { return env[k] }}
+	ctx := context.Background()
+
+	if err := s.Put(ctx, "mainnet/abc/1/trace.json", []byte("{}"), "application/json"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if b.auth[0] != "Bearer ya29.token" {
+		t.Errorf("Authorization = %q", b.auth[0])
+	}
+	keys, err := s.List(ctx, "mainnet/")
+	if err != nil || !reflect.DeepEqual(keys, []string{"mainnet/abc/1/trace.json"}) {
+		t.Errorf("List = %v, %v", keys, err)
+	}
+	if got := readAll(t, s, "mainnet/abc/1/trace.json"); got != "{}" {
+		t.Errorf("Get = %q", got)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package objstore
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3 stores objects in an S3 bucket. Credentials and region come from the
+// standard AWS environment variables; AWS_ENDPOINT_URL_S3 or
+// AWS_ENDPOINT_URL selects an S3-compatible service with path-style
+// addressing.
+type S3 struct {
+	Bucket string
+	Prefix string
+	Client *http.Client
+	Getenv func(string) string
+	Now    func() time.Time
+}
+
+// NewS3 returns a store for bucket reading the process environment
+func NewS3(bucket, prefix string) *S3 {
+	return &S3{Bucket: bucket, Prefix: prefix, Client: defaultHTTPClient(), Getenv: os.Getenv, Now: time.Now}
+}
+
+func (s *S3) region() string {
+	if region := firstEnv(s.Getenv, "AWS_REGION", "AWS_DEFAULT_REGION"); region != "" {
+		return region
+	}
+	return "us-east-1"
+}
+
+// url returns the URL of an object, or of the bucket when key is empty
+func (s *S3) url(key string) string {
+	if endpoint := firstEnv(s.Getenv, "AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"); endpoint != "" {
+		return strings.TrimSuffix(endpoint, "/") + "/" + s.Bucket + "/" + EscapeKey(key)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.Bucket, s.region(), EscapeKey(key))
+}
+
+func (s *S3) do(ctx context.Context, method, target string, body []byte, contentType string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	accessKey := s.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := s.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey != "" && secretKey != "" {
+		creds := Credentials{AccessKey: accessKey, SecretKey: secretKey, SessionToken: s.Getenv("AWS_SESSION_TOKEN")}
+		SignV4(req, creds, s.region(), "s3", sha256Hex(body), s.Now())
+	}
+	return do(s.Client, req)
+}
+
+// Put uploads data as key
+func (s *S3) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	rc, err := s.do(ctx, http.MethodPut, s.url(joinKey(s.Prefix, key)), data, contentType)
+	if err != nil {
+		return err
+	}
+	return rc.Close()
+}
+
+// Get downloads key
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.do(ctx, http.MethodGet, s.url(joinKey(s.Prefix, key)), nil, "")
+}
+
+type s3ListResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the keys under prefix with ListObjectsV2
+func (s *S3) List(ctx context.Context, prefix string) ([]string, error) {
+	full := joinKey(s.Prefix, prefix)
+	var keys []string
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {full}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		rc, err := s.do(ctx, http.MethodGet, s.url("")+"?"+q.Encode(), nil, "")
+		if err != nil {
+			return nil, err
+		}
+		var page s3ListResult
+		err = xml.NewDecoder(rc).Decode(&page)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse S3 listing: %w", err)
+		}
+		for _, c := range page.Contents {
+			keys = append(keys, strings.TrimPrefix(strings.TrimPrefix(c.Key, s.Prefix), "/"))
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Location returns the s3:// URL of key
+func (s *S3) Location(key string) string {
+	return "s3://" + s.Bucket + "/" + joinKey(s.Prefix, key)
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package platform
+
+import (
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// ciProviders maps an environment variable set by a CI system to its name,
+// checked in order
+var ciProviders = []struct {
+	env  string
+	name string
+}{
+	{"GITHUB_ACTIONS", "GitHub Actions"},
+	{"GITLAB_CI", "GitLab CI"},
+	{"CIRCLECI", "CircleCI"},
+	{"BUILDKITE", "Buildkite"},
+	{"TF_BUILD", "Azure Pipelines"},
+	{"JENKINS_URL", "Jenkins"},
+	{"TEAMCITY_VERSION", "TeamCity"},
+	{"BITBUCKET_BUILD_NUMBER", "Bitbucket Pipelines"},
+	{"DRONE", "Drone"},
+	{"TRAVIS", "Travis CI"},
+	{"CODEBUILD_BUILD_ID", "AWS CodeBuild"},
+}
+
+// DetectCI returns the name of the CI system the process runs in, or an
+// empty string. CI=false or CI=0 turns detection off.
+func DetectCI(getenv func(string) string) string {
+	ci := strings.ToLower(getenv("CI"))
+	if ci == "false" || ci == "0" {
+		return ""
+	}
+	for _, p := range ciProviders {
+		if getenv(p.env) != "" {
+			return p.name
+		}
+	}
+	if ci != "" {
+		return "CI"
+	}
+	return ""
+}
+
+// InCI reports whether erst runs in a CI environment
+func InCI() bool {
+	return DetectCI(os.Getenv) != ""
+}
+
+// Interactive reports whether a person can answer prompts: not in CI, and
+// both stdin and stdout are terminals
+func Interactive() bool {
+	if InCI() {
+		return false
+	}
+	return isTerminal(os.Stdin) && isTerminal(os.Stdout)
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
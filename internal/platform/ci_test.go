@@ -0,0 +1,34 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package platform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func envFrom(vars map[string]string) func(string) string {
+	return func(k string) string { return vars[k] }
+}
+
+func TestDetectCI(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"none", nil, ""},
+		{"github actions", map[string]string{"CI": "true", "GITHUB_ACTIONS": "true"}, "GitHub Actions"},
+		{"provider without CI", map[string]string{"GITLAB_CI": "true"}, "GitLab CI"},
+		{"generic", map[string]string{"CI": "1"}, "CI"},
+		{"disabled", map[string]string{"CI": "false", "GITHUB_ACTIONS": "true"}, ""},
+		{"disabled with zero", map[string]string{"CI": "0"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectCI(envFrom(tt.env)))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package platform isolates OS and architecture specific behavior: binary
+// names, executable checks and data directories. Every helper takes the
+// target platform explicitly so Windows and ARM paths can be tested from any
+// CI runner.
+package platform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Platform is an operating system and CPU architecture pair using Go's
+// GOOS and GOARCH names
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// Supported lists the platforms erst and erst-sim are released for
+var Supported = []Platform{
+	{OS: "linux", Arch: "amd64"},
+	{OS: "linux", Arch: "arm64"},
+	{OS: "darwin", Arch: "amd64"},
+	{OS: "darwin", Arch: "arm64"},
+	{OS: "windows", Arch: "amd64"},
+}
+
+// Current returns the platform erst is running on
+func Current() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// String returns the "<os>-<arch>" key used in release assets and manifests
+func (p Platform) String() string {
+	return p.OS + "-" + p.Arch
+}
+
+// IsSupported reports whether p is a released platform
+func (p Platform) IsSupported() bool {
+	for _, s := range Supported {
+		if s == p {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWindows reports whether p is a Windows platform
+func (p Platform) IsWindows() bool {
+	return p.OS == "windows"
+}
+
+// ExecutableName returns the file name of the executable base on p, e.g.
+// erst-sim.exe on Windows
+func (p Platform) ExecutableName(base string) string {
+	if p.IsWindows() && !strings.EqualFold(filepath.Ext(base), ".exe") {
+		return base + ".exe"
+	}
+	return base
+}
+
+// windowsExecutableExts are the extensions Windows runs directly
+var windowsExecutableExts = map[string]bool{".exe": true, ".com": true, ".bat": true, ".cmd": true}
+
+// IsExecutable reports whether info describes a file p can execute. Windows
+// has no execute bit, so the extension decides there.
+func (p Platform) IsExecutable(path string, info os.FileInfo) bool {
+	if info == nil || info.IsDir() {
+		return false
+	}
+	if p.IsWindows() {
+		return windowsExecutableExts[strings.ToLower(filepath.Ext(path))]
+	}
+	return info.Mode()&0111 != 0
+}
+
+// IsExecutable reports whether path is an executable file on the current
+// platform
+func IsExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return Current().IsExecutable(path, info)
+}
+
+// Dirs resolves per-user directories. Getenv and HomeDir are seams for
+// tests; DefaultDirs uses the real environment.
+type Dirs struct {
+	Platform Platform
+	Getenv   func(string) string
+	HomeDir  func() (string, error)
+	// Exists reports whether a path exists, used to keep an existing
+	// ~/.erst directory in place
+	Exists func(string) bool
+}
+
+// DefaultDirs resolves directories for the current platform and user
+func DefaultDirs() Dirs {
+	return Dirs{
+		Platform: Current(),
+		Getenv:   os.Getenv,
+		HomeDir:  os.UserHomeDir,
+		Exists: func(path string) bool {
+			_, err := os.Stat(path)
+			return err == nil
+		},
+	}
+}
+
+// DataDir returns the directory erst keeps its state in. ERST_HOME
+// overrides it. On Unix, including macOS, it is ~/.erst. On Windows it is
+// %LOCALAPPDATA%\erst, unless a ~/.erst directory from an earlier release
+// already exists.
+func (d Dirs) DataDir() (string, error) {
+	if dir := d.Getenv("ERST_HOME"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := d.HomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	legacy := filepath.Join(home, ".erst")
+
+	if d.Platform.IsWindows() {
+		if local := d.Getenv("LOCALAPPDATA"); local != "" && !d.Exists(legacy) {
+			return filepath.Join(local, "erst"), nil
+		}
+	}
+	return legacy, nil
+}
+
+// DataDir returns the erst data directory for the current user
+func DataDir() (string, error) {
+	return DefaultDirs().DataDir()
+}
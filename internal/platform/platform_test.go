@@ -0,0 +1,94 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package platform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	windows     = Platform{OS: "windows", Arch: "amd64"}
+	linuxARM    = Platform{OS: "linux", Arch: "arm64"}
+	darwinARM   = Platform{OS: "darwin", Arch: "arm64"}
+	unsupported = Platform{OS: "plan9", Arch: "386"}
+)
+
+func TestPlatform_Names(t *testing.T) {
+	assert.Equal(t, "erst-sim.exe", windows.ExecutableName("erst-sim"))
+	assert.Equal(t, "erst-sim.EXE", windows.ExecutableName("erst-sim.EXE"))
+	assert.Equal(t, "erst-sim", linuxARM.ExecutableName("erst-sim"))
+	assert.Equal(t, "darwin-arm64", darwinARM.String())
+
+	assert.True(t, windows.IsSupported())
+	assert.True(t, linuxARM.IsSupported())
+	assert.False(t, unsupported.IsSupported())
+	assert.True(t, Current().String() != "")
+}
+
+func TestPlatform_IsExecutable(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "erst-sim")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\n"), 0755))
+	plain := filepath.Join(dir, "notes.txt")
+	require.NoError(t, os.WriteFile(plain, []byte("x"), 0644))
+
+	scriptInfo, err := os.Stat(script)
+	require.NoError(t, err)
+	plainInfo, err := os.Stat(plain)
+	require.NoError(t, err)
+	dirInfo, err := os.Stat(dir)
+	require.NoError(t, err)
+
+	assert.True(t, linuxARM.IsExecutable(script, scriptInfo))
+	assert.False(t, linuxARM.IsExecutable(plain, plainInfo))
+	assert.False(t, linuxARM.IsExecutable(dir, dirInfo))
+
+	assert.False(t, windows.IsExecutable(script, scriptInfo), "Windows ignores the execute bit")
+	assert.True(t, windows.IsExecutable(`C:\tools\erst-sim.exe`, plainInfo))
+	assert.True(t, windows.IsExecutable(`C:\tools\run.CMD`, plainInfo))
+}
+
+func TestDirs_DataDir(t *testing.T) {
+	env := map[string]string{}
+	existing := map[string]bool{}
+	dirs := func(p Platform) Dirs {
+		return Dirs{
+			Platform: p,
+			Getenv:   func(k string) string { return env[k] },
+			HomeDir:  func() (string, error) { return "home", nil },
+			Exists:   func(path string) bool { return existing[path] },
+		}
+	}
+
+	dir, err := dirs(darwinARM).DataDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("home", ".erst"), dir)
+
+	env["LOCALAPPDATA"] = "appdata"
+	dir, err = dirs(windows).DataDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("appdata", "erst"), dir)
+
+	existing[filepath.Join("home", ".erst")] = true
+	dir, err = dirs(windows).DataDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("home", ".erst"), dir, "an existing ~/.erst is kept")
+
+	env["ERST_HOME"] = "custom"
+	dir, err = dirs(linuxARM).DataDir()
+	require.NoError(t, err)
+	assert.Equal(t, "custom", dir)
+
+	failing := dirs(linuxARM)
+	env["ERST_HOME"] = ""
+	failing.HomeDir = func() (string, error) { return "", errors.New("no home") }
+	_, err = failing.DataDir()
+	assert.Error(t, err)
+}
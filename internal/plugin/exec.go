@@ -0,0 +1,178 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// ExecutablePrefix starts the file name of every executable plugin: the
+// plugin "lint" is the program erst-lint on PATH
+const ExecutablePrefix = "erst-"
+
+// ContextVersion is the version of the ExecContext passed to plugins
+const ContextVersion = 1
+
+// Executable is an executable plugin found on PATH
+type Executable struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// DiscoverExecutables returns the executable plugins in the directories of
+// pathList, sorted by name. When a name appears in several directories, the
+// first one on the path wins, as it does for the shell.
+func DiscoverExecutables(pathList string) []Executable {
+	seen := make(map[string]bool)
+	var found []Executable
+	for _, dir := range filepath.SplitList(pathList) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := executableName(e.Name())
+			if !ok || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			found = append(found, Executable{Name: name, Path: path})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Name < found[j].Name })
+	return found
+}
+
+// FindExecutable returns the executable plugin called name in pathList
+func FindExecutable(name, pathList string) (Executable, bool) {
+	for _, e := range DiscoverExecutables(pathList) {
+		if e.Name == name {
+			return e, true
+		}
+	}
+	return Executable{}, false
+}
+
+// executableName returns the plugin name of a file name on PATH
+func executableName(file string) (string, bool) {
+	name, ok := strings.CutPrefix(file, ExecutablePrefix)
+	if !ok {
+		return "", false
+	}
+	if runtime.GOOS == "windows" {
+		ext := filepath.Ext(name)
+		if !isWindowsExecutableExt(ext) {
+			return "", false
+		}
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name, name != ""
+}
+
+func isWindowsExecutableExt(ext string) bool {
+	exts := os.Getenv("PATHEXT")
+	if exts == "" {
+		exts = ".com;.exe;.bat;.cmd"
+	}
+	for _, e := range filepath.SplitList(exts) {
+		if e != "" && strings.EqualFold(e, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0111 != 0
+}
+
+// ExecContext is what erst tells a plugin about its environment. It is
+// passed as JSON in ERST_PLUGIN_CONTEXT, and its main fields also as
+// separate environment variables.
+type ExecContext struct {
+	ContextVersion int `json:"context_version"`
+	// APIVersion is the JSON output version requested with --api-version,
+	// which plugins that print JSON should honor
+	APIVersion  int    `json:"api_version"`
+	ErstVersion string `json:"erst_version"`
+	// ErstPath is the erst binary, for plugins that call back into erst
+	ErstPath   string `json:"erst_path,omitempty"`
+	DataDir    string `json:"data_dir,omitempty"`
+	ConfigPath string `json:"config_path,omitempty"`
+	Network    string `json:"network,omitempty"`
+	RPCURL     string `json:"rpc_url,omitempty"`
+	// SessionID is the current session, and SessionFile a JSON copy of it
+	// that is removed when the plugin exits
+	SessionID   string `json:"session_id,omitempty"`
+	SessionFile string `json:"session_file,omitempty"`
+}
+
+// Environ returns the environment variables describing c
+func (c ExecContext) Environ() ([]string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode plugin context: %w", err)
+	}
+	env := []string{
+		"ERST_PLUGIN_CONTEXT=" + string(data),
+		fmt.Sprintf("ERST_API_VERSION=%d", c.APIVersion),
+	}
+	for name, value := range map[string]string{
+		"ERST_BIN":          c.ErstPath,
+		"ERST_DATA_DIR":     c.DataDir,
+		"ERST_CONFIG":       c.ConfigPath,
+		"ERST_NETWORK":      c.Network,
+		"ERST_RPC_URL":      c.RPCURL,
+		"ERST_SESSION_ID":   c.SessionID,
+		"ERST_SESSION_FILE": c.SessionFile,
+	} {
+		if value != "" {
+			env = append(env, name+"="+value)
+		}
+	}
+	sort.Strings(env[2:])
+	return env, nil
+}
+
+// RunExecutable runs the plugin e with args and the context c, connected to
+// the given standard streams. A plugin that exits non-zero returns an
+// *exec.ExitError.
+func RunExecutable(ctx context.Context, e Executable, args []string, c ExecContext, stdin io.Reader, stdout, stderr io.Writer) error {
+	env, err := c.Environ()
+	if err != nil {
+		return err
+	}
+	cmd := exec.CommandContext(ctx, e.Path, args...)
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return err
+		}
+		return fmt.Errorf("failed to run plugin %s: %w", e.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func writeExecutable(t *testing.T, dir, name, script string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), mode); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestDiscoverExecutables(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("relies on executable permission bits")
+	}
+	first, second := t.TempDir(), t.TempDir()
+	lint := writeExecutable(t, first, "erst-lint", "", 0755)
+	writeExecutable(t, second, "erst-lint", "", 0755)
+	writeExecutable(t, second, "erst-audit-ext", "", 0755)
+	writeExecutable(t, second, "erst-notes", "", 0644)
+	writeExecutable(t, second, "other-tool", "", 0755)
+	if err := os.Mkdir(filepath.Join(second, "erst-dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	found := DiscoverExecutables(strings.Join([]string{first, "", filepath.Join(first, "missing"), second}, string(os.PathListSeparator)))
+	if len(found) != 2 || found[0].Name != "audit-ext" || found[1].Name != "lint" {
+		t.Fatalf("unexpected plugins %+v", found)
+	}
+	if found[1].Path != lint {
+		t.Errorf("expected the first lint on the path, got %s", found[1].Path)
+	}
+
+	if _, ok := FindExecutable("notes", second); ok {
+		t.Error("a file that is not executable is not a plugin")
+	}
+}
+
+func TestExecContextEnviron(t *testing.T) {
+	env, err := ExecContext{ContextVersion: ContextVersion, APIVersion: 1, ErstVersion: "v1.2.3", SessionID: "s1"}.Environ()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		`ERST_PLUGIN_CONTEXT={"context_version":1,"api_version":1,"erst_version":"v1.2.3","session_id":"s1"}`,
+		"ERST_API_VERSION=1",
+		"ERST_SESSION_ID=s1",
+	}
+	if strings.Join(env, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected environment:\n%s", strings.Join(env, "\n"))
+	}
+}
+
+func TestRunExecutable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script plugin")
+	}
+	dir := t.TempDir()
+	path := writeExecutable(t, dir, "erst-echo", `echo "$ERST_PLUGIN_CONTEXT"
+echo "args: $*"
+exit $1
+`, 0755)
+	e := Executable{Name: "echo", Path: path}
+
+	var out bytes.Buffer
+	if err := RunExecutable(context.Background(), e, []string{"0", "x"}, ExecContext{ContextVersion: ContextVersion, Network: "testnet"}, nil, &out, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	var got ExecContext
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil || got.Network != "testnet" {
+		t.Errorf("unexpected context %q: %v", lines[0], err)
+	}
+	if lines[1] != "args: 0 x" {
+		t.Errorf("unexpected args line %q", lines[1])
+	}
+
+	err := RunExecutable(context.Background(), e, []string{"3"}, ExecContext{}, nil, &out, &out)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("expected exit status 3, got %v", err)
+	}
+}
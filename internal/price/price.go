@@ -0,0 +1,198 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package price provides approximate USD prices for assets so token flow
+// reports can show the financial significance of a transaction. Prices are
+// indicative only and are never used for anything but display.
+package price
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Provider returns the USD price of one whole unit of an asset. Assets are
+// identified by contract ID (C...) or by symbol, e.g. "XLM". ok is false
+// when the provider has no price for the asset.
+type Provider interface {
+	USDPrice(ctx context.Context, asset string) (price float64, ok bool, err error)
+}
+
+// NewProvider creates a provider from a source string: an http(s) URL for
+// HTTPProvider or a path to a CSV file for CSVProvider
+func NewProvider(source string) (Provider, error) {
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		return NewHTTPProvider(source)
+	}
+	return LoadCSV(source)
+}
+
+// CSVProvider serves prices from a static table with rows of the form
+// "asset,usd_price". A header row and lines starting with # are ignored.
+type CSVProvider struct {
+	prices map[string]float64
+}
+
+// LoadCSV reads a price table from a CSV file
+func LoadCSV(path string) (*CSVProvider, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open price file: %w", err)
+	}
+	defer f.Close()
+	return ParseCSV(f)
+}
+
+// ParseCSV reads a price table from r
+func ParseCSV(r io.Reader) (*CSVProvider, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	p := &CSVProvider{prices: make(map[string]float64)}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read price file: %w", err)
+		}
+		if len(record) < 2 {
+			return nil, fmt.Errorf("price file line %d: expected asset,usd_price", line)
+		}
+		asset := strings.TrimSpace(record[0])
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("price file line %d: invalid price %q", line, record[1])
+		}
+		p.prices[normalize(asset)] = value
+	}
+	return p, nil
+}
+
+// USDPrice implements Provider
+func (p *CSVProvider) USDPrice(_ context.Context, asset string) (float64, bool, error) {
+	v, ok := p.prices[normalize(asset)]
+	return v, ok, nil
+}
+
+// HTTPProvider fetches prices from an HTTP endpoint. The URL may contain an
+// {asset} placeholder; otherwise the asset is passed as the "asset" query
+// parameter. The endpoint must answer with JSON like {"usd": 0.12} and may
+// return 404 for unknown assets. Responses are cached per asset.
+type HTTPProvider struct {
+	endpoint   string
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*float64
+}
+
+// NewHTTPProvider creates a provider for the given endpoint
+func NewHTTPProvider(endpoint string) (*HTTPProvider, error) {
+	if _, err := url.Parse(endpoint); err != nil {
+		return nil, fmt.Errorf("invalid price endpoint: %w", err)
+	}
+	return &HTTPProvider{
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      make(map[string]*float64),
+	}, nil
+}
+
+// USDPrice implements Provider
+func (p *HTTPProvider) USDPrice(ctx context.Context, asset string) (float64, bool, error) {
+	key := normalize(asset)
+
+	p.mu.Lock()
+	cached, hit := p.cache[key]
+	p.mu.Unlock()
+	if hit {
+		if cached == nil {
+			return 0, false, nil
+		}
+		return *cached, true, nil
+	}
+
+	value, ok, err := p.fetch(ctx, asset)
+	if err != nil {
+		return 0, false, err
+	}
+
+	p.mu.Lock()
+	if ok {
+		p.cache[key] = &value
+	} else {
+		p.cache[key] = nil
+	}
+	p.mu.Unlock()
+	return value, ok, nil
+}
+
+func (p *HTTPProvider) fetch(ctx context.Context, asset string) (float64, bool, error) {
+	target := p.endpoint
+	if strings.Contains(target, "{asset}") {
+		target = strings.ReplaceAll(target, "{asset}", url.PathEscape(asset))
+	} else {
+		u, err := url.Parse(target)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid price endpoint: %w", err)
+		}
+		q := u.Query()
+		q.Set("asset", asset)
+		u.RawQuery = q.Encode()
+		target = u.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create price request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to fetch price: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, fmt.Errorf("price endpoint returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		USD *float64 `json:"usd"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, false, fmt.Errorf("failed to decode price response: %w", err)
+	}
+	if body.USD == nil {
+		return 0, false, nil
+	}
+	return *body.USD, true, nil
+}
+
+// normalize makes symbol lookups case-insensitive; contract IDs are
+// already upper case
+func normalize(asset string) string {
+	return strings.ToUpper(strings.TrimSpace(asset))
+}
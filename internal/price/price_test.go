@@ -0,0 +1,84 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package price
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVProvider(t *testing.T) {
+	p, err := ParseCSV(strings.NewReader("asset,usd\n# stablecoins\nXLM,0.12\nusdc, 1\n"))
+	require.NoError(t, err)
+
+	v, ok, err := p.USDPrice(context.Background(), "xlm")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 0.12, v)
+
+	v, ok, _ = p.USDPrice(context.Background(), "USDC")
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, v)
+
+	_, ok, _ = p.USDPrice(context.Background(), "BTC")
+	assert.False(t, ok)
+
+	_, err = ParseCSV(strings.NewReader("XLM,0.12\nUSDC,abc\n"))
+	assert.Error(t, err)
+}
+
+func TestNewProvider_CSVFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.csv")
+	require.NoError(t, os.WriteFile(path, []byte("XLM,0.1\n"), 0644))
+
+	p, err := NewProvider(path)
+	require.NoError(t, err)
+	_, ok, _ := p.USDPrice(context.Background(), "XLM")
+	assert.True(t, ok)
+
+	_, err = NewProvider(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.Error(t, err)
+}
+
+func TestHTTPProvider(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/price/XLM":
+			_, _ = w.Write([]byte(`{"usd": 0.11}`))
+		case "/price/BROKEN":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	p, err := NewProvider(srv.URL + "/price/{asset}")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		v, ok, err := p.USDPrice(context.Background(), "XLM")
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, 0.11, v)
+	}
+	assert.Equal(t, 1, requests, "prices are cached")
+
+	_, ok, err := p.USDPrice(context.Background(), "UNKNOWN")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = p.USDPrice(context.Background(), "BROKEN")
+	assert.Error(t, err)
+}
@@ -10,13 +10,18 @@ import (
 	"strings"
 	"text/template"
 	"time"
+
+	"github.com/dotandev/hintents/internal/visualizer"
 )
 
 type HTMLRenderer struct {
+	// Accessible renders wide tables as labeled lists and drops color-only
+	// styling, for screen readers
+	Accessible bool
 }
 
 func NewHTMLRenderer() *HTMLRenderer {
-	return &HTMLRenderer{}
+	return &HTMLRenderer{Accessible: visualizer.Accessible()}
 }
 
 func (r *HTMLRenderer) Render(report *Report) ([]byte, error) {
@@ -25,6 +30,7 @@ func (r *HTMLRenderer) Render(report *Report) ([]byte, error) {
 		"escapeHTML":  escapeHTML,
 		"statusClass": statusClass,
 		"riskColor":   riskColor,
+		"accessible":  func() bool { return r.Accessible },
 	}).Parse(htmlTemplate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse template: %w", err)
@@ -166,6 +172,19 @@ const htmlTemplate = `<!DOCTYPE html>
 			{{ if .TransactionHash }}<p><strong>Transaction Hash:</strong> <code>{{ .TransactionHash }}</code></p>{{ end }}
 			{{ if .Steps }}
 			<h3>Execution Steps</h3>
+			{{ if accessible }}
+			<ol>
+				{{ range .Steps }}
+				<li><dl>
+					<dt>Step</dt><dd>{{ .Index }}</dd>
+					<dt>Operation</dt><dd>{{ .Operation }}</dd>
+					<dt>Contract/Function</dt><dd>{{ if .ContractID }}{{ .ContractID }}::{{ .Function }}{{ else }}{{ .Function }}{{ end }}</dd>
+					<dt>Status</dt><dd>{{ .Status }}</dd>
+					<dt>Details</dt><dd>{{ .Details }}</dd>
+				</dl></li>
+				{{ end }}
+			</ol>
+			{{ else }}
 			<table>
 				<thead>
 					<tr><th>#</th><th>Operation</th><th>Contract/Function</th><th>Status</th><th>Details</th></tr>
@@ -183,6 +202,7 @@ const htmlTemplate = `<!DOCTYPE html>
 				</tbody>
 			</table>
 			{{ end }}
+			{{ end }}
 			{{ if .ErrorTrace }}
 			<h3>Error Trace</h3>
 			<div class="alert alert-danger">{{ range .ErrorTrace }}<div>{{ escapeHTML . }}</div>{{ end }}</div>
@@ -221,9 +241,25 @@ const htmlTemplate = `<!DOCTYPE html>
 		<section id="risks">
 			<h2>Risk Assessment</h2>
 			{{ with .Analytics.RiskAssessment }}
+			{{ if accessible }}
+			<p><strong>Risk level:</strong> {{ .Level }} ({{ printf "%.1f" .Score }} out of 100)</p>
+			{{ else }}
 			<div class="risk-score" style="background: {{ riskColor .Level }}; color: white;">{{ .Level }} ({{ printf "%.1f" .Score }}/100)</div>
+			{{ end }}
 			{{ if .Issues }}
 			<h3>Detected Issues</h3>
+			{{ if accessible }}
+			<ol>
+				{{ range .Issues }}
+				<li><dl>
+					<dt>Type</dt><dd>{{ .Type }}</dd>
+					<dt>Severity</dt><dd>{{ .Severity }}</dd>
+					<dt>Description</dt><dd>{{ .Description }}</dd>
+					<dt>Location</dt><dd>{{ if .Location }}{{ .Location }}{{ else }}none{{ end }}</dd>
+				</dl></li>
+				{{ end }}
+			</ol>
+			{{ else }}
 			<table>
 				<thead><tr><th>Type</th><th>Severity</th><th>Description</th><th>Location</th></tr></thead>
 				<tbody>
@@ -238,11 +274,16 @@ const htmlTemplate = `<!DOCTYPE html>
 				</tbody>
 			</table>
 			{{ end }}
+			{{ end }}
 			{{ if .Warnings }}
 			<h3>Warnings</h3>
+			{{ if accessible }}
+			<ul>{{ range .Warnings }}<li>{{ escapeHTML . }}</li>{{ end }}</ul>
+			{{ else }}
 			<div class="alert alert-warning">{{ range .Warnings }}<div>• {{ escapeHTML . }}</div>{{ end }}</div>
 			{{ end }}
 			{{ end }}
+			{{ end }}
 		</section>
 		<section id="metadata">
 			<h2>Report Information</h2>
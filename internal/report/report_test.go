@@ -204,6 +204,34 @@ func TestMetadata(t *testing.T) {
 	}
 }
 
+func TestAccessibleHTMLRendering(t *testing.T) {
+	report := NewBuilder("Accessible Report").
+		AddExecutionStep(0, "invoke", "error", "trapped").
+		AddIssue("auth", "high", "missing signature", "CABC", "").
+		AddWarning("fee bid is low").
+		SetRiskAssessment("high", 72).
+		Build()
+
+	renderer := NewHTMLRenderer()
+	renderer.Accessible = true
+	html, err := renderer.Render(report)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	htmlStr := string(html)
+	for _, want := range []string{"<dt>Operation</dt><dd>invoke</dd>", "<dt>Severity</dt><dd>high</dd>", "<li>fee bid is low</li>", "<strong>Risk level:</strong>"} {
+		if !strings.Contains(htmlStr, want) {
+			t.Errorf("expected %q in accessible HTML", want)
+		}
+	}
+	for _, unwanted := range []string{"<th>Operation</th>", "<th>Severity</th>", "•", "style=\"background:"} {
+		if strings.Contains(htmlStr, unwanted) {
+			t.Errorf("unexpected %q in accessible HTML", unwanted)
+		}
+	}
+}
+
 func TestHTMLEscaping(t *testing.T) {
 	report := NewBuilder("Test Report").
 		AddKeyFinding("<script>alert('xss')</script>").
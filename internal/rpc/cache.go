@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
 
@@ -28,7 +29,8 @@ func HashLedgerKey(key xdr.LedgerKey) (string, error) {
 }
 
 const (
-	CacheDirName    = ".erst/cache"
+	// CacheDirName is the cache directory inside the erst data directory
+	CacheDirName    = "cache"
 	FilePerm        = 0600
 	DirPerm         = 0700
 	DefaultCacheTTL = 24 * time.Hour
@@ -44,12 +46,12 @@ type CachedEntry struct {
 
 // GetCachePath returns the path to the cache directory, creating it if necessary
 func GetCachePath() (string, error) {
-	home, err := os.UserHomeDir()
+	dir, err := platform.DataDir()
 	if err != nil {
-		return "", fmt.Errorf("failed to get user home directory: %w", err)
+		return "", err
 	}
 
-	path := filepath.Join(home, CacheDirName)
+	path := filepath.Join(dir, CacheDirName)
 	if err := os.MkdirAll(path, DirPerm); err != nil {
 		return "", fmt.Errorf("failed to create cache directory: %w", err)
 	}
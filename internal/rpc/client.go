@@ -488,7 +488,7 @@ func (c *Client) GetLedgerEntries(ctx context.Context, keys []string) (map[strin
 }
 
 func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []string) (map[string]string, error) {
-	logger.Logger.Debug("Fetching ledger entries", "count", len(keysToFetch), "url", c.HorizonURL)
+	logger.Logger.Debug("Fetching ledger entries", "count", len(keysToFetch), "url", c.SorobanURL)
 	reqBody := GetLedgerEntriesRequest{
 		Jsonrpc: "2.0",
 		ID:      1,
@@ -501,7 +501,8 @@ func (c *Client) getLedgerEntriesAttempt(ctx context.Context, keysToFetch []stri
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	targetURL := c.HorizonURL
+	// getLedgerEntries is a Soroban RPC method; Horizon does not serve it
+	targetURL := c.SorobanURL
 	if c.Network == Testnet && targetURL == "" {
 		targetURL = TestnetSorobanURL
 	} else if c.Network == Mainnet && targetURL == "" {
@@ -593,6 +594,17 @@ func (c *Client) GetAccountTransactions(ctx context.Context, account string, lim
 	return summaries, nil
 }
 
+// GetAccountSequence returns the current sequence number of an account
+func (c *Client) GetAccountSequence(ctx context.Context, account string) (int64, error) {
+	logger.Logger.Debug("Fetching account sequence", "account", account)
+
+	acc, err := c.Horizon.AccountDetail(horizonclient.AccountRequest{AccountID: account})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch account %s: %w", account, err)
+	}
+	return acc.Sequence, nil
+}
+
 func getTransactionStatus(tx hProtocol.Transaction) string {
 	if tx.Successful {
 		return "success"
@@ -615,7 +627,14 @@ type SimulateTransactionResponse struct {
 		// We only need minimal pieces for fee/budget estimation.
 		MinResourceFee  string `json:"minResourceFee,omitempty"`
 		TransactionData string `json:"transactionData,omitempty"`
-		Cost            struct {
+		LatestLedger    uint32 `json:"latestLedger,omitempty"`
+		// Error is set when the host function failed in simulation
+		Error   string `json:"error,omitempty"`
+		Results []struct {
+			Auth []string `json:"auth,omitempty"`
+			Xdr  string   `json:"xdr,omitempty"`
+		} `json:"results,omitempty"`
+		Cost struct {
 			CpuInsns  int64 `json:"cpuInsns,omitempty"`
 			MemBytes  int64 `json:"memBytes,omitempty"`
 			CpuInsns_ int64 `json:"cpu_insns,omitempty"`
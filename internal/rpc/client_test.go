@@ -5,7 +5,11 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -292,3 +296,22 @@ func TestGetTransaction_Timeout(t *testing.T) {
 	_, err := c.GetTransaction(ctx, "timeout")
 	assert.Error(t, err)
 }
+
+func TestGetLedgerEntriesUsesSorobanURL(t *testing.T) {
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GetLedgerEntriesRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		method = req.Method
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"result":{"entries":[],"latestLedger":1}}`)
+	}))
+	defer server.Close()
+
+	c, err := NewClient(WithNetwork(Testnet), WithCacheEnabled(false),
+		WithHorizonURL("http://127.0.0.1:1"), WithSorobanURL(server.URL))
+	assert.NoError(t, err)
+	entries, err := c.GetLedgerEntries(context.Background(), []string{"AAAA"})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+	assert.Equal(t, "getLedgerEntries", method)
+}
@@ -0,0 +1,161 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// DefaultHistoryLookback is how many ledgers GetContractTransactions scans
+// back from the latest ledger (about one day at 5s ledgers)
+const DefaultHistoryLookback uint32 = 17280
+
+// eventsPageLimit is the maximum page size accepted by Soroban RPC getEvents
+const eventsPageLimit = 10000
+
+type sorobanRPCRequest struct {
+	Jsonrpc string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+type sorobanRPCResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type getEventsResult struct {
+	Events []struct {
+		Ledger uint32 `json:"ledger"`
+		TxHash string `json:"txHash"`
+	} `json:"events"`
+	Cursor string `json:"cursor"`
+}
+
+// sorobanCall performs one JSON-RPC call against the Soroban RPC endpoint
+func (c *Client) sorobanCall(ctx context.Context, method string, params, out interface{}) error {
+	body, err := json.Marshal(sorobanRPCRequest{Jsonrpc: "2.0", ID: 1, Method: method, Params: params})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.SorobanURL, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request to %s: %w", c.SorobanURL, err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s request to %s failed: unexpected status %s", method, c.SorobanURL, resp.Status)
+	}
+
+	var rpcResp sorobanRPCResponse
+	if err := json.Unmarshal(respBytes, &rpcResp); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("rpc error: %s (code %d)", rpcResp.Error.Message, rpcResp.Error.Code)
+	}
+
+	if err := json.Unmarshal(rpcResp.Result, out); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", method, err)
+	}
+	return nil
+}
+
+// GetLatestLedgerSequence returns the sequence of the newest ledger known to
+// Soroban RPC
+func (c *Client) GetLatestLedgerSequence(ctx context.Context) (uint32, error) {
+	var res struct {
+		Sequence uint32 `json:"sequence"`
+	}
+	if err := c.sorobanCall(ctx, "getLatestLedger", nil, &res); err != nil {
+		return 0, err
+	}
+	return res.Sequence, nil
+}
+
+// GetContractTransactions returns the hashes of up to limit of the most
+// recent transactions that emitted events from contractID, newest first.
+// Only the last lookback ledgers are scanned; Soroban RPC does not retain
+// events beyond its retention window anyway.
+func (c *Client) GetContractTransactions(ctx context.Context, contractID string, limit int, lookback uint32) ([]string, error) {
+	logger.Logger.Debug("Fetching contract transactions", "contract", contractID, "limit", limit)
+
+	if lookback == 0 {
+		lookback = DefaultHistoryLookback
+	}
+
+	latest, err := c.GetLatestLedgerSequence(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest ledger: %w", err)
+	}
+	start := uint32(1)
+	if latest > lookback {
+		start = latest - lookback
+	}
+
+	filters := []map[string]interface{}{{"type": "contract", "contractIds": []string{contractID}}}
+
+	// Events are returned oldest first, so collect the whole window and keep
+	// the tail
+	var hashes []string
+	seen := make(map[string]bool)
+	cursor := ""
+	for {
+		params := map[string]interface{}{"filters": filters}
+		if cursor == "" {
+			params["startLedger"] = start
+			params["pagination"] = map[string]interface{}{"limit": eventsPageLimit}
+		} else {
+			params["pagination"] = map[string]interface{}{"limit": eventsPageLimit, "cursor": cursor}
+		}
+
+		var page getEventsResult
+		if err := c.sorobanCall(ctx, "getEvents", params, &page); err != nil {
+			return nil, fmt.Errorf("failed to fetch contract events: %w", err)
+		}
+
+		for _, ev := range page.Events {
+			if ev.TxHash != "" && !seen[ev.TxHash] {
+				seen[ev.TxHash] = true
+				hashes = append(hashes, ev.TxHash)
+			}
+		}
+
+		if len(page.Events) < eventsPageLimit || page.Cursor == "" || page.Cursor == cursor {
+			break
+		}
+		cursor = page.Cursor
+	}
+
+	out := make([]string, 0, limit)
+	for i := len(hashes) - 1; i >= 0 && (limit <= 0 || len(out) < limit); i-- {
+		out = append(out, hashes[i])
+	}
+
+	logger.Logger.Debug("Contract transactions retrieved", "count", len(out))
+	return out, nil
+}
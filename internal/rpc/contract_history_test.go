@@ -0,0 +1,78 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetContractTransactions(t *testing.T) {
+	var startLedger float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("bad request body: %v", err)
+			return
+		}
+
+		switch req.Method {
+		case "getLatestLedger":
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"sequence":20000}}`))
+		case "getEvents":
+			startLedger, _ = req.Params["startLedger"].(float64)
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"events":[
+				{"ledger":19000,"txHash":"aa"},
+				{"ledger":19001,"txHash":"bb"},
+				{"ledger":19001,"txHash":"bb"},
+				{"ledger":19500,"txHash":"cc"}
+			],"cursor":"x"}}`))
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithNetwork(Testnet), WithSorobanURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	hashes, err := client.GetContractTransactions(context.Background(), "CCONTRACT", 2, 1000)
+	if err != nil {
+		t.Fatalf("GetContractTransactions failed: %v", err)
+	}
+
+	if want := []string{"cc", "bb"}; !reflect.DeepEqual(hashes, want) {
+		t.Errorf("expected newest-first unique hashes %v, got %v", want, hashes)
+	}
+	if startLedger != 19000 {
+		t.Errorf("expected scan to start at ledger 19000, got %v", startLedger)
+	}
+}
+
+func TestSorobanCallHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>bad gateway</html>"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithNetwork(Testnet), WithSorobanURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	_, err = client.GetContractTransactions(context.Background(), "CCONTRACT", 2, 1000)
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("expected the HTTP status in the error, got %v", err)
+	}
+}
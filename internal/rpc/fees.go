@@ -0,0 +1,138 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+)
+
+// FeeDistribution summarizes fees in stroops over recent ledgers
+type FeeDistribution struct {
+	Min  int64 `json:"min"`
+	Mode int64 `json:"mode"`
+	P10  int64 `json:"p10"`
+	P50  int64 `json:"p50"`
+	P90  int64 `json:"p90"`
+	P99  int64 `json:"p99"`
+	Max  int64 `json:"max"`
+}
+
+// FeeStats is the recent fee market of a network
+type FeeStats struct {
+	LastLedger        uint32  `json:"last_ledger"`
+	LastLedgerBaseFee int64   `json:"last_ledger_base_fee"`
+	CapacityUsage     float64 `json:"capacity_usage"`
+	// FeeCharged is the per-operation fee charged to recent transactions
+	FeeCharged FeeDistribution `json:"fee_charged"`
+	// MaxFee is the per-operation fee recent transactions bid
+	MaxFee FeeDistribution `json:"max_fee"`
+	// SorobanInclusionFee is the inclusion fee of recent Soroban
+	// transactions, nil when Soroban RPC did not report it
+	SorobanInclusionFee *FeeDistribution `json:"soroban_inclusion_fee,omitempty"`
+}
+
+func fromHorizonDistribution(d hProtocol.FeeDistribution) FeeDistribution {
+	return FeeDistribution{Min: d.Min, Mode: d.Mode, P10: d.P10, P50: d.P50, P90: d.P90, P99: d.P99, Max: d.Max}
+}
+
+// sorobanFeeDistribution is the getFeeStats wire format, which encodes
+// numbers as strings
+type sorobanFeeDistribution struct {
+	Max  string `json:"max"`
+	Min  string `json:"min"`
+	Mode string `json:"mode"`
+	P10  string `json:"p10"`
+	P50  string `json:"p50"`
+	P90  string `json:"p90"`
+	P99  string `json:"p99"`
+}
+
+func (d sorobanFeeDistribution) parse() (FeeDistribution, error) {
+	var out FeeDistribution
+	fields := []struct {
+		raw string
+		dst *int64
+	}{
+		{d.Min, &out.Min}, {d.Mode, &out.Mode}, {d.P10, &out.P10}, {d.P50, &out.P50},
+		{d.P90, &out.P90}, {d.P99, &out.P99}, {d.Max, &out.Max},
+	}
+	for _, f := range fields {
+		if f.raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(f.raw, 10, 64)
+		if err != nil {
+			return FeeDistribution{}, fmt.Errorf("invalid fee value %q: %w", f.raw, err)
+		}
+		*f.dst = v
+	}
+	return out, nil
+}
+
+// GetFeeStats fetches recent fee statistics from Horizon and, when
+// available, Soroban inclusion fees from Soroban RPC
+func (c *Client) GetFeeStats(ctx context.Context) (*FeeStats, error) {
+	logger.Logger.Debug("Fetching fee stats", "network", c.Network)
+
+	hs, err := c.Horizon.FeeStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch fee stats: %w", err)
+	}
+
+	stats := &FeeStats{
+		LastLedger:        hs.LastLedger,
+		LastLedgerBaseFee: hs.LastLedgerBaseFee,
+		CapacityUsage:     hs.LedgerCapacityUsage,
+		FeeCharged:        fromHorizonDistribution(hs.FeeCharged),
+		MaxFee:            fromHorizonDistribution(hs.MaxFee),
+	}
+
+	if c.SorobanURL != "" {
+		soroban, err := c.GetSorobanInclusionFees(ctx)
+		if err != nil {
+			logger.Logger.Debug("Soroban fee stats unavailable", "error", err)
+		} else {
+			stats.SorobanInclusionFee = soroban
+		}
+	}
+	return stats, nil
+}
+
+// GetSorobanInclusionFees fetches the inclusion fee distribution of recent
+// Soroban transactions via getFeeStats
+func (c *Client) GetSorobanInclusionFees(ctx context.Context) (*FeeDistribution, error) {
+	var result struct {
+		SorobanInclusionFee sorobanFeeDistribution `json:"sorobanInclusionFee"`
+	}
+	if err := c.sorobanCall(ctx, "getFeeStats", nil, &result); err != nil {
+		return nil, err
+	}
+	d, err := result.SorobanInclusionFee.parse()
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// GetRecentLedgers fetches the latest limit closed ledgers, newest first
+func (c *Client) GetRecentLedgers(ctx context.Context, limit uint) ([]*LedgerHeaderResponse, error) {
+	logger.Logger.Debug("Fetching recent ledgers", "limit", limit, "network", c.Network)
+
+	page, err := c.Horizon.Ledgers(horizonclient.LedgerRequest{Order: horizonclient.OrderDesc, Limit: limit})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recent ledgers: %w", err)
+	}
+
+	ledgers := make([]*LedgerHeaderResponse, 0, len(page.Embedded.Records))
+	for _, l := range page.Embedded.Records {
+		ledgers = append(ledgers, FromHorizonLedger(l))
+	}
+	return ledgers, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetSorobanInclusionFees(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{
+			"sorobanInclusionFee":{"max":"5000","min":"100","mode":"100","p10":"100","p50":"150","p90":"900","p99":"4000","transactionCount":"42","ledgerCount":50},
+			"inclusionFee":{"max":"100","min":"100","mode":"100"},
+			"latestLedger":123
+		}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithNetwork(Testnet), WithSorobanURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	fees, err := client.GetSorobanInclusionFees(context.Background())
+	if err != nil {
+		t.Fatalf("GetSorobanInclusionFees failed: %v", err)
+	}
+	want := FeeDistribution{Min: 100, Mode: 100, P10: 100, P50: 150, P90: 900, P99: 4000, Max: 5000}
+	if *fees != want {
+		t.Errorf("expected %+v, got %+v", want, *fees)
+	}
+}
+
+func TestSorobanFeeDistribution_Invalid(t *testing.T) {
+	if _, err := (sorobanFeeDistribution{P50: "abc"}).parse(); err == nil {
+		t.Error("expected error for non-numeric fee")
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// PredictFootprint returns the base64 ledger keys a transaction is expected
+// to read, derived from the envelope alone: the declared Soroban footprint
+// plus the transaction, fee and operation source accounts. It is used when
+// result metadata is not available to extract touched entries from.
+func PredictFootprint(envelopeXdr string) ([]string, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+
+	seen := make(map[string]struct{})
+	add := func(k xdr.LedgerKey) error {
+		encoded, err := EncodeLedgerKey(k)
+		if err != nil {
+			return fmt.Errorf("failed to encode ledger key: %w", err)
+		}
+		seen[encoded] = struct{}{}
+		return nil
+	}
+	addAccount := func(m xdr.MuxedAccount) error {
+		var k xdr.LedgerKey
+		if err := k.SetAccount(m.ToAccountId()); err != nil {
+			return err
+		}
+		return add(k)
+	}
+
+	if err := addAccount(env.SourceAccount()); err != nil {
+		return nil, err
+	}
+	if env.IsFeeBump() {
+		if err := addAccount(env.FeeBumpAccount()); err != nil {
+			return nil, err
+		}
+	}
+	for _, op := range env.Operations() {
+		if op.SourceAccount != nil {
+			if err := addAccount(*op.SourceAccount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var data *xdr.SorobanTransactionData
+	switch {
+	case env.V1 != nil:
+		data = env.V1.Tx.Ext.SorobanData
+	case env.FeeBump != nil && env.FeeBump.Tx.InnerTx.V1 != nil:
+		data = env.FeeBump.Tx.InnerTx.V1.Tx.Ext.SorobanData
+	}
+	if data != nil {
+		for _, k := range data.Resources.Footprint.ReadOnly {
+			if err := add(k); err != nil {
+				return nil, err
+			}
+		}
+		for _, k := range data.Resources.Footprint.ReadWrite {
+			if err := add(k); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
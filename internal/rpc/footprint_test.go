@@ -0,0 +1,60 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestPredictFootprint(t *testing.T) {
+	source := xdr.MustMuxedAddress("GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF")
+	var code xdr.Hash
+	code[0] = 7
+	codeKey := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: code}}
+
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: source,
+			Fee:           100,
+			Operations: []xdr.Operation{{
+				SourceAccount: &source,
+				Body: xdr.OperationBody{
+					Type:                 xdr.OperationTypeExtendFootprintTtl,
+					ExtendFootprintTtlOp: &xdr.ExtendFootprintTtlOp{ExtendTo: 1},
+				},
+			}},
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{codeKey}}},
+			}},
+		}},
+	}
+	envXdr, err := xdr.MarshalBase64(env)
+	if err != nil {
+		t.Fatalf("failed to encode envelope: %v", err)
+	}
+
+	keys, err := PredictFootprint(envXdr)
+	if err != nil {
+		t.Fatalf("PredictFootprint failed: %v", err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected source account and contract code keys (deduplicated), got %d", len(keys))
+	}
+
+	want, _ := EncodeLedgerKey(codeKey)
+	found := false
+	for _, k := range keys {
+		found = found || k == want
+	}
+	if !found {
+		t.Errorf("expected footprint key %s in %v", want, keys)
+	}
+
+	if _, err := PredictFootprint("not-xdr"); err == nil {
+		t.Error("expected error for invalid envelope")
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/stellar/go-stellar-sdk/clients/horizonclient"
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/effects"
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/operations"
+	"github.com/stellar/go-stellar-sdk/toid"
+)
+
+// historyPageSize is the largest page Horizon serves
+const historyPageSize = 200
+
+// GetTransactionOperations fetches Horizon's record of the operations of a
+// transaction, in order, including those of failed transactions
+func (c *Client) GetTransactionOperations(ctx context.Context, hash string) ([]operations.Operation, error) {
+	logger.Logger.Debug("Fetching transaction operations", "hash", hash, "url", c.HorizonURL)
+
+	var ops []operations.Operation
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := c.Horizon.Operations(horizonclient.OperationRequest{
+			ForTransaction: hash,
+			IncludeFailed:  true,
+			Order:          horizonclient.OrderAsc,
+			Cursor:         cursor,
+			Limit:          historyPageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch operations of %s: %w", hash, err)
+		}
+		records := page.Embedded.Records
+		ops = append(ops, records...)
+		if len(records) < historyPageSize {
+			return ops, nil
+		}
+		cursor = records[len(records)-1].PagingToken()
+	}
+}
+
+// GetTransactionEffects fetches the effects Horizon recorded for a
+// transaction, in order
+func (c *Client) GetTransactionEffects(ctx context.Context, hash string) ([]effects.Effect, error) {
+	logger.Logger.Debug("Fetching transaction effects", "hash", hash, "url", c.HorizonURL)
+
+	var effs []effects.Effect
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := c.Horizon.Effects(horizonclient.EffectRequest{
+			ForTransaction: hash,
+			Order:          horizonclient.OrderAsc,
+			Cursor:         cursor,
+			Limit:          historyPageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch effects of %s: %w", hash, err)
+		}
+		records := page.Embedded.Records
+		effs = append(effs, records...)
+		if len(records) < historyPageSize {
+			return effs, nil
+		}
+		cursor = records[len(records)-1].PagingToken()
+	}
+}
+
+// GetAccountHistory fetches up to limit of the most recent transactions of
+// an account applied up to and including ledger, newest first, including
+// failed ones. A zero ledger starts from the latest transaction.
+func (c *Client) GetAccountHistory(ctx context.Context, account string, ledger uint32, limit int) ([]hProtocol.Transaction, error) {
+	logger.Logger.Debug("Fetching account history", "account", account, "ledger", ledger, "url", c.HorizonURL)
+
+	var txs []hProtocol.Transaction
+	cursor := ""
+	if ledger != 0 {
+		cursor = toid.New(int32(ledger)+1, 0, 0).String()
+	}
+	for len(txs) < limit {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := c.Horizon.Transactions(horizonclient.TransactionRequest{
+			ForAccount:    account,
+			IncludeFailed: true,
+			Order:         horizonclient.OrderDesc,
+			Cursor:        cursor,
+			Limit:         uint(min(limit-len(txs), historyPageSize)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch transactions of %s: %w", account, err)
+		}
+		records := page.Embedded.Records
+		txs = append(txs, records...)
+		if len(records) < historyPageSize {
+			break
+		}
+		cursor = records[len(records)-1].PagingToken()
+	}
+	return txs, nil
+}
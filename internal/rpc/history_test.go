@@ -0,0 +1,107 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/protocols/horizon/effects"
+)
+
+func TestGetTransactionOperationsAndEffects(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/transactions/abc/operations"):
+			fmt.Fprint(w, `{"_embedded":{"records":[
+				{"id":"12884905985","paging_token":"12884905985","transaction_successful":true,
+				 "source_account":"GA","type":"payment","type_i":1,"asset_type":"native","amount":"1.0000000","from":"GA","to":"GB"}
+			]}}`)
+		case strings.HasSuffix(r.URL.Path, "/transactions/abc/effects"):
+			fmt.Fprint(w, `{"_embedded":{"records":[
+				{"id":"0012884905985-0000000001","paging_token":"12884905985-1","account":"GB","type":"account_credited","type_i":2,"asset_type":"native","amount":"1.0000000"},
+				{"id":"0012884905985-0000000002","paging_token":"12884905985-2","account":"GA","type":"account_debited","type_i":3,"asset_type":"native","amount":"1.0000000"}
+			]}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithNetwork(Testnet), WithHorizonURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	ops, err := client.GetTransactionOperations(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("GetTransactionOperations failed: %v", err)
+	}
+	if len(ops) != 1 || ops[0].GetType() != "payment" || !ops[0].IsTransactionSuccessful() {
+		t.Fatalf("unexpected operations: %+v", ops)
+	}
+	if !strings.Contains(queries[0], "include_failed=true") {
+		t.Errorf("expected failed transactions to be included, got query %q", queries[0])
+	}
+
+	effs, err := client.GetTransactionEffects(context.Background(), "abc")
+	if err != nil {
+		t.Fatalf("GetTransactionEffects failed: %v", err)
+	}
+	if len(effs) != 2 {
+		t.Fatalf("expected 2 effects, got %d", len(effs))
+	}
+	credited, ok := effs[0].(effects.AccountCredited)
+	if !ok || credited.Amount != "1.0000000" || credited.Account != "GB" {
+		t.Errorf("unexpected first effect: %#v", effs[0])
+	}
+
+	if _, err := client.GetTransactionEffects(context.Background(), "missing"); err == nil {
+		t.Error("expected an error for an unknown transaction")
+	}
+}
+
+func TestGetAccountHistory(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/accounts/GA/transactions") {
+			http.NotFound(w, r)
+			return
+		}
+		queries = append(queries, r.URL.RawQuery)
+		fmt.Fprint(w, `{"_embedded":{"records":[
+			{"id":"a","paging_token":"429496733696","hash":"a","ledger":100,"source_account":"GA","source_account_sequence":"11","successful":false},
+			{"id":"b","paging_token":"425201766400","hash":"b","ledger":99,"source_account":"GA","source_account_sequence":"10","successful":true}
+		]}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithNetwork(Testnet), WithHorizonURL(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	txs, err := client.GetAccountHistory(context.Background(), "GA", 100, 10)
+	if err != nil {
+		t.Fatalf("GetAccountHistory failed: %v", err)
+	}
+	if len(txs) != 2 || txs[0].Hash != "a" || txs[1].AccountSequence != 10 {
+		t.Fatalf("unexpected transactions: %+v", txs)
+	}
+	for _, want := range []string{"cursor=433791696896", "order=desc", "include_failed=true", "limit=10"} {
+		if !strings.Contains(queries[0], want) {
+			t.Errorf("expected %q in query %q", want, queries[0])
+		}
+	}
+
+	if _, err := client.GetAccountHistory(context.Background(), "GB", 0, 10); err == nil {
+		t.Error("expected an error for an unknown account")
+	}
+}
@@ -47,6 +47,9 @@ type LedgerHeaderResponse struct {
 	SuccessfulTxCount int32 // Number of successful transactions
 	FailedTxCount     int32 // Number of failed transactions
 	OperationCount    int32 // Total operations in ledger
+	// TxSetOperationCount includes operations of failed transactions and
+	// is what counts against MaxTxSetSize
+	TxSetOperationCount int32
 }
 
 // FromHorizonLedger converts a Horizon ledger response to our internal structure.
@@ -59,6 +62,11 @@ func FromHorizonLedger(hl hProtocol.Ledger) *LedgerHeaderResponse {
 		failedTxCount = *hl.FailedTransactionCount
 	}
 
+	txSetOps := hl.OperationCount
+	if hl.TxSetOperationCount != nil {
+		txSetOps = *hl.TxSetOperationCount
+	}
+
 	return &LedgerHeaderResponse{
 		Sequence:          uint32(hl.Sequence),
 		Hash:              hl.Hash,
@@ -74,6 +82,8 @@ func FromHorizonLedger(hl hProtocol.Ledger) *LedgerHeaderResponse {
 		SuccessfulTxCount: hl.SuccessfulTransactionCount,
 		FailedTxCount:     failedTxCount,
 		OperationCount:    hl.OperationCount,
+
+		TxSetOperationCount: txSetOps,
 	}
 }
 
@@ -3,13 +3,21 @@
 
 package rpc
 
-import hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+import (
+	"time"
+
+	hProtocol "github.com/stellar/go-stellar-sdk/protocols/horizon"
+)
 
 // TransactionResponse holds the XDR data for a transaction
 type TransactionResponse struct {
 	EnvelopeXdr   string
 	ResultXdr     string
 	ResultMetaXdr string
+	// Ledger is the sequence of the ledger that included the transaction
+	Ledger uint32
+	// LedgerCloseTime is when that ledger closed
+	LedgerCloseTime time.Time
 }
 
 // ParseTransactionResponse converts a Horizon transaction into a TransactionResponse
@@ -18,6 +26,9 @@ func ParseTransactionResponse(tx hProtocol.Transaction) *TransactionResponse {
 		EnvelopeXdr:   tx.EnvelopeXdr,
 		ResultXdr:     tx.ResultXdr,
 		ResultMetaXdr: tx.ResultMetaXdr,
+		Ledger:        uint32(tx.Ledger),
+
+		LedgerCloseTime: tx.LedgerCloseTime,
 	}
 }
 
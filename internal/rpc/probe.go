@@ -0,0 +1,196 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoint kinds reported by probes
+const (
+	EndpointHorizon    = "horizon"
+	EndpointSorobanRPC = "soroban-rpc"
+)
+
+// EndpointStatus is the health of one network endpoint as seen by a probe
+type EndpointStatus struct {
+	Network string `json:"network"`
+	Kind    string `json:"kind"`
+	URL     string `json:"url"`
+	// LatestLedger is the newest ledger the endpoint reported
+	LatestLedger uint32 `json:"latest_ledger,omitempty"`
+	// ClosedAt is when that ledger closed, zero when the endpoint does not
+	// report close times
+	ClosedAt time.Time `json:"closed_at,omitempty"`
+	// Lag is how far ClosedAt trails the wall clock
+	Lag time.Duration `json:"lag_ns,omitempty"`
+	// P50 is the median latency of the successful samples
+	P50     time.Duration `json:"p50_ns,omitempty"`
+	Samples int           `json:"samples"`
+	Failed  int           `json:"failed"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// Healthy reports whether every sample succeeded
+func (s EndpointStatus) Healthy() bool {
+	return s.Error == "" && s.Failed == 0
+}
+
+type probeSample struct {
+	ledger   uint32
+	closedAt time.Time
+}
+
+// ProbeNetwork probes the Horizon and Soroban RPC endpoints of a network
+// concurrently, taking samples requests of each
+func ProbeNetwork(ctx context.Context, hc *http.Client, cfg NetworkConfig, samples int) []EndpointStatus {
+	var out []EndpointStatus
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	probe := func(f func(context.Context, *http.Client, string, int) EndpointStatus, url string) {
+		defer wg.Done()
+		s := f(ctx, hc, url, samples)
+		s.Network = cfg.Name
+		mu.Lock()
+		out = append(out, s)
+		mu.Unlock()
+	}
+	if cfg.HorizonURL != "" {
+		wg.Add(1)
+		go probe(ProbeHorizon, cfg.HorizonURL)
+	}
+	if cfg.SorobanRPCURL != "" {
+		wg.Add(1)
+		go probe(ProbeSorobanRPC, cfg.SorobanRPCURL)
+	}
+	wg.Wait()
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Kind < out[j].Kind })
+	return out
+}
+
+// ProbeHorizon samples the root resource of a Horizon server, which reports
+// the latest ingested ledger and its close time
+func ProbeHorizon(ctx context.Context, hc *http.Client, url string, samples int) EndpointStatus {
+	return runProbe(EndpointHorizon, url, samples, func() (probeSample, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return probeSample{}, err
+		}
+		req.Header.Set("Accept", "application/json")
+
+		var root struct {
+			LatestLedger   uint32    `json:"history_latest_ledger"`
+			LatestClosedAt time.Time `json:"history_latest_ledger_closed_at"`
+		}
+		if err := doProbe(hc, req, &root); err != nil {
+			return probeSample{}, err
+		}
+		return probeSample{ledger: root.LatestLedger, closedAt: root.LatestClosedAt}, nil
+	})
+}
+
+// ProbeSorobanRPC samples getLatestLedger on a Soroban RPC server. Close
+// times are only available from servers that include closeTime.
+func ProbeSorobanRPC(ctx context.Context, hc *http.Client, url string, samples int) EndpointStatus {
+	body, _ := json.Marshal(sorobanRPCRequest{Jsonrpc: "2.0", ID: 1, Method: "getLatestLedger"})
+	return runProbe(EndpointSorobanRPC, url, samples, func() (probeSample, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return probeSample{}, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		var resp sorobanRPCResponse
+		if err := doProbe(hc, req, &resp); err != nil {
+			return probeSample{}, err
+		}
+		if resp.Error != nil {
+			return probeSample{}, fmt.Errorf("rpc error: %s (code %d)", resp.Error.Message, resp.Error.Code)
+		}
+		var result struct {
+			Sequence  uint32          `json:"sequence"`
+			CloseTime json.RawMessage `json:"closeTime"`
+		}
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			return probeSample{}, fmt.Errorf("failed to decode getLatestLedger result: %w", err)
+		}
+		s := probeSample{ledger: result.Sequence}
+		if secs, err := strconv.ParseInt(strings.Trim(string(result.CloseTime), `"`), 10, 64); err == nil && secs > 0 {
+			s.closedAt = time.Unix(secs, 0).UTC()
+		}
+		return s, nil
+	})
+}
+
+func runProbe(kind, url string, samples int, sample func() (probeSample, error)) EndpointStatus {
+	if samples < 1 {
+		samples = 1
+	}
+	status := EndpointStatus{Kind: kind, URL: url, Samples: samples}
+
+	var latencies []time.Duration
+	var lastErr error
+	for i := 0; i < samples; i++ {
+		start := time.Now()
+		s, err := sample()
+		elapsed := time.Since(start)
+		if err != nil {
+			status.Failed++
+			lastErr = err
+			continue
+		}
+		latencies = append(latencies, elapsed)
+		if s.ledger >= status.LatestLedger {
+			status.LatestLedger = s.ledger
+			if !s.closedAt.IsZero() {
+				status.ClosedAt = s.closedAt
+			}
+		}
+	}
+
+	if len(latencies) == 0 {
+		status.Error = lastErr.Error()
+		return status
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	status.P50 = latencies[(len(latencies)-1)/2]
+	if !status.ClosedAt.IsZero() {
+		status.Lag = time.Since(status.ClosedAt)
+		if status.Lag < 0 {
+			status.Lag = 0
+		}
+	}
+	return status
+}
+
+func doProbe(hc *http.Client, req *http.Request, out interface{}) error {
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
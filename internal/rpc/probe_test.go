@@ -0,0 +1,100 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbeNetwork(t *testing.T) {
+	closed := time.Now().Add(-10 * time.Second).UTC()
+	horizon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"history_latest_ledger": 1200, "history_latest_ledger_closed_at": %q}`, closed.Format(time.RFC3339))
+	}))
+	defer horizon.Close()
+
+	soroban := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":{"id":"abc","protocolVersion":22,"sequence":1201,"closeTime":"%d"}}`, closed.Unix())
+	}))
+	defer soroban.Close()
+
+	cfg := NetworkConfig{Name: "local", HorizonURL: horizon.URL, SorobanRPCURL: soroban.URL}
+	statuses := ProbeNetwork(context.Background(), http.DefaultClient, cfg, 3)
+	if len(statuses) != 2 {
+		t.Fatalf("got %d statuses, want 2", len(statuses))
+	}
+
+	h, s := statuses[0], statuses[1]
+	if h.Kind != EndpointHorizon || s.Kind != EndpointSorobanRPC {
+		t.Fatalf("unexpected order: %s, %s", h.Kind, s.Kind)
+	}
+	if h.Network != "local" || h.LatestLedger != 1200 || s.LatestLedger != 1201 {
+		t.Errorf("ledgers = %d, %d", h.LatestLedger, s.LatestLedger)
+	}
+	for _, st := range statuses {
+		if !st.Healthy() {
+			t.Errorf("%s unhealthy: %+v", st.Kind, st)
+		}
+		if st.Lag < 9*time.Second || st.Lag > time.Minute {
+			t.Errorf("%s lag = %v, want about 10s", st.Kind, st.Lag)
+		}
+		if st.P50 <= 0 {
+			t.Errorf("%s p50 not measured", st.Kind)
+		}
+	}
+}
+
+func TestProbeSorobanRPCWithoutCloseTime(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"id":"abc","protocolVersion":22,"sequence":77}}`))
+	}))
+	defer srv.Close()
+
+	st := ProbeSorobanRPC(context.Background(), http.DefaultClient, srv.URL, 1)
+	if !st.Healthy() || st.LatestLedger != 77 {
+		t.Fatalf("unexpected status: %+v", st)
+	}
+	if !st.ClosedAt.IsZero() || st.Lag != 0 {
+		t.Errorf("expected unknown lag, got %v", st.Lag)
+	}
+}
+
+func TestProbeFailures(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, "overloaded", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"history_latest_ledger": 5}`))
+	}))
+	defer srv.Close()
+
+	st := ProbeHorizon(context.Background(), http.DefaultClient, srv.URL, 2)
+	if st.Failed != 1 || st.Error != "" || st.Healthy() {
+		t.Errorf("partial failure: %+v", st)
+	}
+
+	srv.Close()
+	st = ProbeHorizon(context.Background(), http.DefaultClient, srv.URL, 2)
+	if st.Error == "" || st.Failed != 2 {
+		t.Errorf("expected endpoint down, got %+v", st)
+	}
+
+	rpcErr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`))
+	}))
+	defer rpcErr.Close()
+	st = ProbeSorobanRPC(context.Background(), http.DefaultClient, rpcErr.URL, 1)
+	if !strings.Contains(st.Error, "method not found") {
+		t.Errorf("error = %q", st.Error)
+	}
+}
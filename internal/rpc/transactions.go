@@ -0,0 +1,66 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// transactionsPageLimit is the maximum page size accepted by Soroban RPC
+// getTransactions
+const transactionsPageLimit = 200
+
+// LedgerTransaction is a transaction as listed by Soroban RPC
+// getTransactions
+type LedgerTransaction struct {
+	Hash             string `json:"txHash"`
+	Status           string `json:"status"`
+	Ledger           uint32 `json:"ledger"`
+	CreatedAt        int64  `json:"createdAt"`
+	ApplicationOrder int    `json:"applicationOrder"`
+	EnvelopeXdr      string `json:"envelopeXdr"`
+	ResultXdr        string `json:"resultXdr"`
+	ResultMetaXdr    string `json:"resultMetaXdr"`
+}
+
+type getTransactionsResult struct {
+	Transactions []LedgerTransaction `json:"transactions"`
+	Cursor       string              `json:"cursor"`
+}
+
+// GetLedgerTransactions returns every transaction applied in ledgers start
+// to end inclusive, in application order
+func (c *Client) GetLedgerTransactions(ctx context.Context, start, end uint32) ([]LedgerTransaction, error) {
+	logger.Logger.Debug("Fetching ledger transactions", "start", start, "end", end)
+
+	var txs []LedgerTransaction
+	cursor := ""
+	for {
+		params := map[string]interface{}{}
+		if cursor == "" {
+			params["startLedger"] = start
+			params["pagination"] = map[string]interface{}{"limit": transactionsPageLimit}
+		} else {
+			params["pagination"] = map[string]interface{}{"limit": transactionsPageLimit, "cursor": cursor}
+		}
+
+		var page getTransactionsResult
+		if err := c.sorobanCall(ctx, "getTransactions", params, &page); err != nil {
+			return nil, fmt.Errorf("failed to fetch transactions from ledger %d: %w", start, err)
+		}
+		for _, tx := range page.Transactions {
+			if tx.Ledger > end {
+				return txs, nil
+			}
+			txs = append(txs, tx)
+		}
+		if len(page.Transactions) < transactionsPageLimit || page.Cursor == "" || page.Cursor == cursor {
+			return txs, nil
+		}
+		cursor = page.Cursor
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetLedgerTransactions(t *testing.T) {
+	var startLedger float64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "getTransactions" {
+			t.Errorf("unexpected request %s: %v", req.Method, err)
+			return
+		}
+		startLedger, _ = req.Params["startLedger"].(float64)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"transactions":[
+			{"txHash":"aa","status":"SUCCESS","ledger":100,"createdAt":1700000000,"applicationOrder":1},
+			{"txHash":"bb","status":"FAILED","ledger":101,"createdAt":1700000005,"applicationOrder":1},
+			{"txHash":"cc","status":"SUCCESS","ledger":102,"createdAt":1700000010,"applicationOrder":1}
+		],"cursor":"x"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(WithNetwork(Testnet), WithSorobanURL(server.URL))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	txs, err := client.GetLedgerTransactions(context.Background(), 100, 101)
+	if err != nil {
+		t.Fatalf("GetLedgerTransactions failed: %v", err)
+	}
+	if startLedger != 100 {
+		t.Errorf("expected scan to start at ledger 100, got %v", startLedger)
+	}
+	if len(txs) != 2 || txs[1].Hash != "bb" || txs[1].Status != "FAILED" || txs[1].CreatedAt != 1700000005 {
+		t.Errorf("expected the transactions of ledgers 100 and 101, got %+v", txs)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/trace"
+)
+
+// baseID prefixes the $id of every published schema
+const baseID = "https://github.com/dotandev/hintents/schemas/"
+
+//go:embed schemas/*.json
+var published embed.FS
+
+type entry struct {
+	value       any
+	title       string
+	description string
+}
+
+var registry = map[string]entry{
+	"simulation-request": {
+		value:       simulator.SimulationRequest{},
+		title:       "SimulationRequest",
+		description: "Input written to the erst-sim simulator on stdin",
+	},
+	"simulation-response": {
+		value:       simulator.SimulationResponse{},
+		title:       "SimulationResponse",
+		description: "Output the erst-sim simulator prints on stdout",
+	},
+	"execution-trace": {
+		value:       trace.ExecutionTrace{},
+		title:       "ExecutionTrace",
+		description: "Execution trace file read by 'erst trace'",
+	},
+}
+
+// nestedCategorizedEvent is the form of a categorized event the Rust
+// simulator prints
+type nestedCategorizedEvent struct {
+	Category string                    `json:"category"`
+	Event    simulator.DiagnosticEvent `json:"event"`
+}
+
+// NewPayloadGenerator returns a generator that knows the older encodings
+// erst still accepts for simulator payloads
+func NewPayloadGenerator() *Generator {
+	g := NewGenerator()
+	// Older simulators and sessions encode events and logs as plain strings
+	g.AcceptString(simulator.Event{}, simulator.LogEntry{})
+	g.Accept(simulator.CategorizedEvent{}, nestedCategorizedEvent{})
+	return g
+}
+
+// Names returns the names of the published schemas
+func Names() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generate builds the named schema from the current Go types and returns it
+// as indented JSON
+func Generate(name string) ([]byte, error) {
+	e, ok := registry[name]
+	if !ok {
+		return nil, unknown(name)
+	}
+	s := NewPayloadGenerator().Generate(e.value, baseID+name+".json", e.title, e.description)
+	out, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode schema %s: %w", name, err)
+	}
+	return append(out, '\n'), nil
+}
+
+// Published returns the named schema as embedded in the binary
+func Published(name string) ([]byte, error) {
+	if _, ok := registry[name]; !ok {
+		return nil, unknown(name)
+	}
+	return published.ReadFile("schemas/" + name + ".json")
+}
+
+// Load returns the named published schema
+func Load(name string) (*Schema, error) {
+	data, err := Published(name)
+	if err != nil {
+		return nil, err
+	}
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse schema %s: %w", name, err)
+	}
+	return &s, nil
+}
+
+func unknown(name string) error {
+	return fmt.Errorf("unknown schema %q (available: %s)", name, strings.Join(Names(), ", "))
+}
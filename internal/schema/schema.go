@@ -0,0 +1,292 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schema generates JSON Schemas from the Go types erst exchanges
+// with the simulator and other tools, and validates payloads against them.
+package schema
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema used by the generated schemas
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 Types              `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Types is the type keyword; it is written as a string when there is one
+type Types []string
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*t = Types{one}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(t))
+}
+
+// Generator builds schemas from Go types by reflection. Struct fields follow
+// encoding/json: fields without omitempty are required, and nullable when
+// they are pointers, slices or maps. Fields with omitempty are optional and
+// accept null, which decodes the same as an absent field.
+type Generator struct {
+	// RefPrefix is where references to struct schemas point
+	RefPrefix string
+
+	alternatives map[reflect.Type][]reflect.Type
+	defs         map[string]*Schema
+	names        map[reflect.Type]string
+}
+
+// NewGenerator creates a generator
+func NewGenerator() *Generator {
+	return &Generator{RefPrefix: "#/$defs/", alternatives: make(map[reflect.Type][]reflect.Type)}
+}
+
+// AcceptString marks the types of values as also accepting a plain string,
+// for types whose UnmarshalJSON takes an older string encoding
+func (g *Generator) AcceptString(values ...any) {
+	for _, v := range values {
+		g.Accept(v, "")
+	}
+}
+
+// Accept marks the type of v as also accepting the encoding of the type of
+// alt, for types whose UnmarshalJSON takes another form
+func (g *Generator) Accept(v, alt any) {
+	t := reflect.TypeOf(v)
+	g.alternatives[t] = append(g.alternatives[t], reflect.TypeOf(alt))
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generate returns the schema of the type of v. Nested struct types are
+// placed in $defs.
+func (g *Generator) Generate(v any, id, title, description string) *Schema {
+	g.defs = make(map[string]*Schema)
+	g.names = make(map[reflect.Type]string)
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	root := g.structSchema(t)
+	root.Draft = Draft
+	root.ID = id
+	root.Title = title
+	root.Description = description
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+// Schema returns the schema of the type of v for use inside a larger
+// document, such as an OpenAPI description. Struct types become references
+// and their schemas accumulate in Defs across calls.
+func (g *Generator) Schema(v any) *Schema {
+	if g.defs == nil {
+		g.defs = make(map[string]*Schema)
+		g.names = make(map[reflect.Type]string)
+	}
+	return g.typeSchema(reflect.TypeOf(v))
+}
+
+// Defs returns the struct schemas referenced by the schemas returned so far
+func (g *Generator) Defs() map[string]*Schema {
+	return g.defs
+}
+
+func (g *Generator) typeSchema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: Types{"boolean"}}
+	case reflect.String:
+		return &Schema{Type: Types{"string"}}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		bits := float64(t.Bits())
+		return &Schema{Type: Types{"integer"}, Minimum: ptr(-math.Pow(2, bits-1)), Maximum: ptr(math.Pow(2, bits-1) - 1)}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: Types{"integer"}}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: Types{"integer"}, Minimum: ptr(0), Maximum: ptr(math.Pow(2, float64(t.Bits())) - 1)}
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: Types{"integer"}, Minimum: ptr(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: Types{"number"}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{"string"}, Format: "byte"}
+		}
+		return &Schema{Type: Types{"array"}, Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		s := &Schema{Type: Types{"object"}}
+		if elem := g.typeSchema(t.Elem()); !isEmpty(elem) {
+			s.AdditionalProperties = elem
+		}
+		return s
+	case reflect.Struct:
+		ref := &Schema{Ref: g.RefPrefix + g.define(t)}
+		alts := g.alternatives[t]
+		if len(alts) == 0 {
+			return ref
+		}
+		// The Go encoding comes last, which is what validation errors are
+		// reported against
+		forms := &Schema{}
+		for _, alt := range alts {
+			forms.AnyOf = append(forms.AnyOf, g.typeSchema(alt))
+		}
+		forms.AnyOf = append(forms.AnyOf, ref)
+		return forms
+	}
+	// interface{} and anything else accepts any value
+	return &Schema{}
+}
+
+// define adds the schema of struct t to $defs and returns its name
+func (g *Generator) define(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := t.Name()
+	if name == "" {
+		name = "Anonymous"
+	}
+	if _, taken := g.defs[name]; taken {
+		name = pkgName(t) + "." + name
+	}
+	g.names[t] = name
+	g.defs[name] = &Schema{}
+	*g.defs[name] = *g.structSchema(t)
+	return name
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: Types{"object"}, Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+	sort.Strings(s.Required)
+	return s
+}
+
+func (g *Generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				g.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := g.typeSchema(ft)
+		switch {
+		case hasOption(opts, "omitempty"):
+			prop = orNull(prop)
+		case nullable(ft):
+			s.Required = append(s.Required, name)
+			prop = orNull(prop)
+		default:
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+func hasOption(opts, want string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == want {
+			return true
+		}
+	}
+	return false
+}
+
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
+// orNull extends s to also accept null
+func orNull(s *Schema) *Schema {
+	if isEmpty(s) {
+		return s
+	}
+	if s.Ref != "" || len(s.AnyOf) > 0 {
+		return &Schema{AnyOf: []*Schema{s, {Type: Types{"null"}}}}
+	}
+	out := *s
+	out.Type = append(append(Types{}, s.Type...), "null")
+	return &out
+}
+
+func isEmpty(s *Schema) bool {
+	return reflect.DeepEqual(s, &Schema{})
+}
+
+func pkgName(t reflect.Type) string {
+	path := t.PkgPath()
+	if i := strings.LastIndexByte(path, '/'); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
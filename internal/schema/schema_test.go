@@ -0,0 +1,204 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/authtrace"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/trace"
+)
+
+var update = flag.Bool("update", false, "rewrite the published schemas from the Go types")
+
+// TestPublishedSchemasUpToDate fails when a Go type changed without the
+// published schema being regenerated. Run
+//
+//	go test ./internal/schema -run TestPublishedSchemasUpToDate -update
+//
+// to regenerate them.
+func TestPublishedSchemasUpToDate(t *testing.T) {
+	for _, name := range Names() {
+		generated, err := Generate(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if *update {
+			if err := os.WriteFile(filepath.Join("schemas", name+".json"), generated, 0644); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			continue
+		}
+		published, err := Published(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !bytes.Equal(generated, published) {
+			t.Errorf("schemas/%s.json is out of date; rerun with -update", name)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	type inner struct {
+		Count uint32 `json:"count"`
+	}
+	type sample struct {
+		Name     string            `json:"name"`
+		Note     string            `json:"note,omitempty"`
+		Tags     []string          `json:"tags"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		Inner    *inner            `json:"inner,omitempty"`
+		At       time.Time         `json:"at"`
+		Any      interface{}       `json:"any"`
+		Skipped  string            `json:"-"`
+		internal string
+	}
+
+	s := NewGenerator().Generate(sample{}, "id", "Sample", "")
+	if got, want := s.Required, []string{"any", "at", "name", "tags"}; !equal(got, want) {
+		t.Errorf("required = %v, want %v", got, want)
+	}
+	if len(s.Properties) != 7 {
+		t.Errorf("expected 7 properties, got %d", len(s.Properties))
+	}
+	if got := s.Properties["tags"].Type; !equal(got, []string{"array", "null"}) {
+		t.Errorf("a slice without omitempty should be nullable, got %v", got)
+	}
+	if got := s.Properties["labels"].AdditionalProperties; got == nil || got.Type[0] != "string" {
+		t.Errorf("unexpected map values %+v", got)
+	}
+	if s.Properties["at"].Format != "date-time" {
+		t.Errorf("expected time.Time to be a date-time")
+	}
+	if inner := s.Properties["inner"]; len(inner.AnyOf) != 2 || inner.AnyOf[0].Ref != "#/$defs/inner" || inner.AnyOf[1].Type[0] != "null" {
+		t.Errorf("expected an optional field to be a nullable $ref, got %+v", inner)
+	}
+	count := s.Defs["inner"].Properties["count"]
+	if *count.Minimum != 0 || *count.Maximum != 4294967295 {
+		t.Errorf("unexpected uint32 bounds %v..%v", *count.Minimum, *count.Maximum)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s, err := Load("simulation-response")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	version := uint32(22)
+	resp := simulator.SimulationResponse{
+		Status:          "success",
+		Events:          []simulator.Event{{Index: 0, Type: "contract", Topics: []string{"transfer"}}},
+		AuthTrace:       &authtrace.AuthTrace{Success: true},
+		ProtocolVersion: &version,
+	}
+	data, _ := json.Marshal(resp)
+	if errs, err := Validate(s, data); err != nil || len(errs) != 0 {
+		t.Errorf("expected a valid response, got %v, %v", errs, err)
+	}
+
+	// Older simulators emitted events and logs as strings
+	legacy := `{"status":"error","error":"trap","events":["contract:C... transfer"],"logs":["host log"]}`
+	if errs, _ := Validate(s, []byte(legacy)); len(errs) != 0 {
+		t.Errorf("expected legacy events to be valid, got %v", errs)
+	}
+
+	bad := `{"events":[{"index":"0"}],"budget_usage":{"cpu_instructions":-1},"protocol_version":4294967296}`
+	errs, err := Validate(s, []byte(bad))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		`missing required property "status"`,
+		`/budget_usage: missing required property "cpu_limit"`,
+		`/budget_usage/cpu_instructions: -1 is less than the minimum 0`,
+		`/events/0/index: expected integer, got string`,
+		`/protocol_version: 4294967296 is greater than the maximum 4294967295`,
+	}
+	got := make(map[string]bool)
+	for _, e := range errs {
+		got[e.Error()] = true
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("missing error %q in %v", w, errs)
+		}
+	}
+
+	if _, err := Validate(s, []byte("{")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestValidateTrace(t *testing.T) {
+	s, err := Load("execution-trace")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tr := trace.NewExecutionTrace("abc", 5)
+	tr.AddState(trace.ExecutionState{Operation: "invoke", HostState: map[string]interface{}{"k": 1}})
+	data, err := tr.ToJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if errs, _ := Validate(s, data); len(errs) != 0 {
+		t.Errorf("expected a valid trace, got %v", errs)
+	}
+	if errs, _ := Validate(s, []byte(`{"transaction_hash":"abc","start_time":"yesterday"}`)); len(errs) == 0 {
+		t.Error("expected errors for an invalid trace")
+	}
+}
+
+// TestValidateRecordedPayloads checks that the published schemas accept the
+// payloads recorded from erst-sim releases
+func TestValidateRecordedPayloads(t *testing.T) {
+	for kind, name := range map[string]string{"request": "simulation-request", "response": "simulation-response"} {
+		s, err := Load(name)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		files, _ := filepath.Glob(filepath.Join("..", "simulator", "testdata", "compat", "*", kind+"*.json"))
+		if len(files) == 0 {
+			t.Fatalf("no recorded %s payloads", kind)
+		}
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if errs, err := Validate(s, data); err != nil || len(errs) != 0 {
+				t.Errorf("%s: %v %v", path, errs, err)
+			}
+		}
+	}
+}
+
+func TestUnknownSchema(t *testing.T) {
+	if _, err := Generate("nope"); err == nil {
+		t.Error("expected an error")
+	}
+	if _, err := Load("nope"); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ValidationError is a value that does not match its schema
+type ValidationError struct {
+	// Path is a JSON pointer to the value, "" for the document itself
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks the JSON document data against s and returns every
+// mismatch, sorted by path. The error is set only when data is not JSON.
+func Validate(s *Schema, data []byte) ([]ValidationError, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	v := &validator{root: s}
+	v.check(s, doc, "")
+	sort.SliceStable(v.errs, func(i, j int) bool { return v.errs[i].Path < v.errs[j].Path })
+	return v.errs, nil
+}
+
+type validator struct {
+	root *Schema
+	errs []ValidationError
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) resolve(ref string) (*Schema, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	def, ok := v.root.Defs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown $ref %q", ref)
+	}
+	return def, nil
+}
+
+func (v *validator) check(s *Schema, value any, path string) {
+	if s.Ref != "" {
+		def, err := v.resolve(s.Ref)
+		if err != nil {
+			v.fail(path, "%v", err)
+			return
+		}
+		v.check(def, value, path)
+		return
+	}
+
+	if len(s.AnyOf) > 0 {
+		for _, alt := range s.AnyOf {
+			trial := &validator{root: v.root}
+			trial.check(alt, value, path)
+			if len(trial.errs) == 0 {
+				return
+			}
+		}
+		// Report against the last alternative that is not null, which is
+		// the Go encoding and what a producer almost always meant
+		v.check(s.AnyOf[preferred(s.AnyOf)], value, path)
+		return
+	}
+
+	if len(s.Type) > 0 && !v.checkType(s.Type, value, path) {
+		return
+	}
+
+	switch val := value.(type) {
+	case json.Number:
+		f, _ := val.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			v.fail(path, "%s is less than the minimum %s", val, formatNumber(*s.Minimum))
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			v.fail(path, "%s is greater than the maximum %s", val, formatNumber(*s.Maximum))
+		}
+	case string:
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, val); err != nil {
+				v.fail(path, "%q is not an RFC 3339 date-time", val)
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range val {
+				v.check(s.Items, item, fmt.Sprintf("%s/%d", path, i))
+			}
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				v.fail(path, "missing required property %q", name)
+			}
+		}
+		for name, item := range val {
+			child := path + "/" + escapePointer(name)
+			if prop, ok := s.Properties[name]; ok {
+				v.check(prop, item, child)
+			} else if s.AdditionalProperties != nil {
+				v.check(s.AdditionalProperties, item, child)
+			}
+		}
+	}
+}
+
+// preferred returns the index of the alternative to report errors against
+// when none matches
+func preferred(alts []*Schema) int {
+	for i := len(alts) - 1; i > 0; i-- {
+		if len(alts[i].Type) != 1 || alts[i].Type[0] != "null" {
+			return i
+		}
+	}
+	return 0
+}
+
+func (v *validator) checkType(types Types, value any, path string) bool {
+	got := jsonType(value)
+	for _, t := range types {
+		if t == got || (t == "number" && got == "integer") {
+			return true
+		}
+	}
+	v.fail(path, "expected %s, got %s", strings.Join(types, " or "), got)
+	return false
+}
+
+func jsonType(value any) string {
+	switch val := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "integer"
+		}
+		if f, err := val.Float64(); err == nil && f == math.Trunc(f) && !strings.ContainsAny(val.String(), ".eE") {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+func formatNumber(f float64) string {
+	return fmt.Sprintf("%.0f", f)
+}
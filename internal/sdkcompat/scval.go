@@ -0,0 +1,120 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package sdkcompat
+
+import (
+	"bytes"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// compareScVal orders values the way the Soroban host does: by type first,
+// then by value. Maps must be sorted by key in this order.
+func compareScVal(a, b xdr.ScVal) int {
+	if a.Type != b.Type {
+		return cmpInt(int64(a.Type), int64(b.Type))
+	}
+	switch a.Type {
+	case xdr.ScValTypeScvBool:
+		return cmpBool(a.MustB(), b.MustB())
+	case xdr.ScValTypeScvU32:
+		return cmpUint(uint64(a.MustU32()), uint64(b.MustU32()))
+	case xdr.ScValTypeScvI32:
+		return cmpInt(int64(a.MustI32()), int64(b.MustI32()))
+	case xdr.ScValTypeScvU64:
+		return cmpUint(uint64(a.MustU64()), uint64(b.MustU64()))
+	case xdr.ScValTypeScvI64:
+		return cmpInt(int64(a.MustI64()), int64(b.MustI64()))
+	case xdr.ScValTypeScvTimepoint:
+		return cmpUint(uint64(a.MustTimepoint()), uint64(b.MustTimepoint()))
+	case xdr.ScValTypeScvDuration:
+		return cmpUint(uint64(a.MustDuration()), uint64(b.MustDuration()))
+	case xdr.ScValTypeScvU128:
+		x, y := a.MustU128(), b.MustU128()
+		if c := cmpUint(uint64(x.Hi), uint64(y.Hi)); c != 0 {
+			return c
+		}
+		return cmpUint(uint64(x.Lo), uint64(y.Lo))
+	case xdr.ScValTypeScvI128:
+		x, y := a.MustI128(), b.MustI128()
+		if c := cmpInt(int64(x.Hi), int64(y.Hi)); c != 0 {
+			return c
+		}
+		return cmpUint(uint64(x.Lo), uint64(y.Lo))
+	case xdr.ScValTypeScvBytes:
+		return bytes.Compare(a.MustBytes(), b.MustBytes())
+	case xdr.ScValTypeScvString:
+		return bytes.Compare([]byte(a.MustStr()), []byte(b.MustStr()))
+	case xdr.ScValTypeScvSymbol:
+		return bytes.Compare([]byte(a.MustSym()), []byte(b.MustSym()))
+	case xdr.ScValTypeScvVec:
+		x, y := scVec(a), scVec(b)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareScVal(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return cmpInt(int64(len(x)), int64(len(y)))
+	case xdr.ScValTypeScvMap:
+		x, y := scMap(a), scMap(b)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareScVal(x[i].Key, y[i].Key); c != 0 {
+				return c
+			}
+			if c := compareScVal(x[i].Val, y[i].Val); c != 0 {
+				return c
+			}
+		}
+		return cmpInt(int64(len(x)), int64(len(y)))
+	}
+	// Remaining types (256-bit integers, addresses and others) order by
+	// their big-endian encoding
+	ra, _ := a.MarshalBinary()
+	rb, _ := b.MarshalBinary()
+	return bytes.Compare(ra, rb)
+}
+
+func scVec(v xdr.ScVal) xdr.ScVec {
+	if vec, ok := v.GetVec(); ok && vec != nil {
+		return *vec
+	}
+	return nil
+}
+
+func scMap(v xdr.ScVal) xdr.ScMap {
+	if m, ok := v.GetMap(); ok && m != nil {
+		return *m
+	}
+	return nil
+}
+
+func cmpInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func cmpUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func cmpBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case !a:
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,415 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sdkcompat inspects a transaction envelope for traces of the client
+// that built it and for encoding pitfalls that clients commonly get wrong,
+// such as unsorted SCVal maps or a missing Soroban transaction extension.
+package sdkcompat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Issue severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Builders a fingerprint can point to
+const (
+	BuilderStellarCLI  = "stellar-cli or a Rust client"
+	BuilderTimeoutSDK  = "a client SDK with a transaction timeout (JS, Python, Java, Go)"
+	BuilderInfiniteSDK = "a client SDK with an infinite timeout"
+	BuilderLegacySDK   = "an SDK released before protocol 13"
+	BuilderUnknown     = "unknown"
+)
+
+// defaultBaseFee is the inclusion fee SDKs and stellar-cli use by default
+const defaultBaseFee = 100
+
+// Issue is one encoding problem. Path uses the field names of erst diffxdr.
+type Issue struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+	Fix      string `json:"fix"`
+}
+
+// Candidate is a possible builder with the signals that point to it
+type Candidate struct {
+	Builder string   `json:"builder"`
+	Score   int      `json:"score"`
+	Signals []string `json:"signals"`
+}
+
+// Report is the result of Analyze. The builder is a heuristic guess.
+type Report struct {
+	Builder    string      `json:"builder"`
+	Confidence string      `json:"confidence"`
+	Candidates []Candidate `json:"candidates,omitempty"`
+	Issues     []Issue     `json:"issues"`
+}
+
+// HasErrors reports whether any issue would make the network reject the
+// transaction
+func (r *Report) HasErrors() bool {
+	for _, is := range r.Issues {
+		if is.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary describes the report in one line
+func (r *Report) Summary() string {
+	counts := map[string]int{}
+	for _, is := range r.Issues {
+		counts[is.Severity]++
+	}
+	var parts []string
+	for _, sev := range []string{SeverityError, SeverityWarning, SeverityInfo} {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s(s)", counts[sev], sev))
+		}
+	}
+	if len(parts) == 0 {
+		return "no issues"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Analyze decodes a base64 envelope and reports its likely builder and
+// encoding issues
+func Analyze(envelopeXdr string) (*Report, error) {
+	var env xdr.TransactionEnvelope
+	if err := decoder.UnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	return AnalyzeEnvelope(env), nil
+}
+
+// AnalyzeEnvelope reports the likely builder and encoding issues of env
+func AnalyzeEnvelope(env xdr.TransactionEnvelope) *Report {
+	a := &analysis{scores: make(map[string]*Candidate)}
+
+	switch env.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTxV0:
+		a.signal(BuilderLegacySDK, 3, "legacy v0 envelope")
+		a.issue(Issue{
+			ID: "legacy-envelope", Severity: SeverityWarning, Path: "v0",
+			Message: "the envelope uses the pre-protocol 13 TransactionV0 format",
+			Fix:     "upgrade the SDK; v0 envelopes cannot carry muxed accounts, preconditions or Soroban data",
+		})
+		tx := env.MustV0().Tx
+		a.checkSignatures("v0", len(env.MustV0().Signatures))
+		a.checkTimeBounds("v0.tx.timeBounds", tx.TimeBounds, false)
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		v1 := env.MustV1()
+		a.checkSignatures("v1", len(v1.Signatures))
+		a.checkTransaction("v1.tx", v1.Tx)
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		fb := env.MustFeeBump()
+		a.checkSignatures("feeBump", len(fb.Signatures))
+		inner := fb.Tx.InnerTx.MustV1()
+		a.checkTransaction("feeBump.tx.innerTx.v1.tx", inner.Tx)
+	}
+	return a.report()
+}
+
+type analysis struct {
+	scores map[string]*Candidate
+	issues []Issue
+}
+
+func (a *analysis) signal(builder string, weight int, signal string) {
+	c, ok := a.scores[builder]
+	if !ok {
+		c = &Candidate{Builder: builder}
+		a.scores[builder] = c
+	}
+	c.Score += weight
+	c.Signals = append(c.Signals, signal)
+}
+
+func (a *analysis) issue(is Issue) {
+	a.issues = append(a.issues, is)
+}
+
+func (a *analysis) report() *Report {
+	r := &Report{Builder: BuilderUnknown, Confidence: "none", Issues: a.issues}
+	for _, c := range a.scores {
+		r.Candidates = append(r.Candidates, *c)
+	}
+	sort.Slice(r.Candidates, func(i, j int) bool {
+		if r.Candidates[i].Score != r.Candidates[j].Score {
+			return r.Candidates[i].Score > r.Candidates[j].Score
+		}
+		return r.Candidates[i].Builder < r.Candidates[j].Builder
+	})
+	if len(r.Candidates) > 0 {
+		top := r.Candidates[0]
+		r.Builder = top.Builder
+		r.Confidence = "low"
+		if top.Score >= 3 && (len(r.Candidates) == 1 || r.Candidates[1].Score < top.Score) {
+			r.Confidence = "medium"
+		}
+	}
+	if r.Issues == nil {
+		r.Issues = []Issue{}
+	}
+	return r
+}
+
+func (a *analysis) checkSignatures(path string, n int) {
+	if n == 0 {
+		a.issue(Issue{
+			ID: "unsigned", Severity: SeverityInfo, Path: path + ".signatures",
+			Message: "the envelope carries no signatures",
+			Fix:     "sign the transaction before submitting it",
+		})
+	}
+}
+
+func (a *analysis) checkTransaction(path string, tx xdr.Transaction) {
+	soroban := 0
+	for _, op := range tx.Operations {
+		if isSorobanOp(op.Body.Type) {
+			soroban++
+		}
+	}
+
+	a.checkPreconditions(path+".cond", tx.Cond, soroban > 0)
+
+	data, hasData := tx.Ext.GetSorobanData()
+	switch {
+	case soroban > 0 && !hasData:
+		a.issue(Issue{
+			ID: "missing-soroban-data", Severity: SeverityError, Path: path + ".ext",
+			Message: "the transaction invokes Soroban but has no SorobanTransactionData (ext v0)",
+			Fix:     "simulate the transaction and attach its footprint and resource fee before signing (assembleTransaction in JS, prepare_transaction in Python)",
+		})
+	case soroban == 0 && hasData:
+		a.issue(Issue{
+			ID: "unexpected-soroban-data", Severity: SeverityError, Path: path + ".ext",
+			Message: "the transaction has SorobanTransactionData but no Soroban operation",
+			Fix:     "only set ext v1 on transactions with one InvokeHostFunction, ExtendFootprintTtl or RestoreFootprint operation",
+		})
+	}
+	if soroban > 0 && len(tx.Operations) > 1 {
+		a.issue(Issue{
+			ID: "multiple-soroban-ops", Severity: SeverityError, Path: path + ".operations",
+			Message: fmt.Sprintf("a Soroban transaction must contain exactly one operation, found %d", len(tx.Operations)),
+			Fix:     "submit each contract call as its own transaction",
+		})
+	}
+	if hasData {
+		a.checkSorobanData(path, tx, data)
+	}
+
+	memoID := tx.Memo.Type == xdr.MemoTypeMemoId
+	for i, op := range tx.Operations {
+		opPath := fmt.Sprintf("%s.operations[%d]", path, i)
+		if memoID {
+			a.checkMuxedWithMemo(opPath, op)
+		}
+		if invoke, ok := op.Body.GetInvokeHostFunctionOp(); ok {
+			a.checkInvoke(opPath+".body.invokeHostFunctionOp", invoke)
+		}
+	}
+}
+
+func (a *analysis) checkPreconditions(path string, cond xdr.Preconditions, soroban bool) {
+	switch cond.Type {
+	case xdr.PreconditionTypePrecondNone:
+		if soroban {
+			a.signal(BuilderStellarCLI, 2, "no preconditions on a Soroban transaction")
+		}
+		a.checkTimeBounds(path, nil, soroban)
+	case xdr.PreconditionTypePrecondTime:
+		a.checkTimeBounds(path+".timeBounds", cond.TimeBounds, soroban)
+	case xdr.PreconditionTypePrecondV2:
+		a.checkTimeBounds(path+".v2.timeBounds", cond.V2.TimeBounds, soroban)
+	}
+}
+
+func (a *analysis) checkTimeBounds(path string, tb *xdr.TimeBounds, soroban bool) {
+	if tb == nil || tb.MaxTime == 0 {
+		if tb != nil {
+			a.signal(BuilderInfiniteSDK, 2, "time bounds with max time 0")
+		}
+		a.issue(Issue{
+			ID: "no-expiry", Severity: SeverityInfo, Path: path,
+			Message: "the transaction has no maximum time and stays valid until its sequence number is used",
+			Fix:     "set a timeout when building the transaction so stale submissions expire",
+		})
+		return
+	}
+	if tb.MinTime == 0 {
+		a.signal(BuilderTimeoutSDK, 2, "time bounds of [0, now+timeout]")
+	}
+}
+
+func (a *analysis) checkSorobanData(path string, tx xdr.Transaction, data xdr.SorobanTransactionData) {
+	if data.ResourceFee > 0 && int64(tx.Fee) < int64(data.ResourceFee) {
+		a.issue(Issue{
+			ID: "fee-below-resource-fee", Severity: SeverityError, Path: path + ".fee",
+			Message: fmt.Sprintf("the fee %d does not cover the resource fee %d", tx.Fee, data.ResourceFee),
+			Fix:     "set the fee to the inclusion fee plus the resource fee from simulation; some SDKs reset the fee when the transaction is rebuilt",
+		})
+	}
+	if int64(tx.Fee) == int64(data.ResourceFee)+defaultBaseFee {
+		a.signal(BuilderStellarCLI, 1, "default 100 stroop inclusion fee on top of the resource fee")
+		a.signal(BuilderTimeoutSDK, 1, "default 100 stroop inclusion fee on top of the resource fee")
+	}
+
+	fp := data.Resources.Footprint
+	seen := make(map[string]string)
+	for _, list := range []struct {
+		name string
+		keys []xdr.LedgerKey
+	}{{"readOnly", fp.ReadOnly}, {"readWrite", fp.ReadWrite}} {
+		for i, key := range list.keys {
+			raw, err := key.MarshalBinaryBase64()
+			if err != nil {
+				continue
+			}
+			keyPath := fmt.Sprintf("%s.ext.sorobanData.resources.footprint.%s[%d]", path, list.name, i)
+			if first, ok := seen[raw]; ok {
+				a.issue(Issue{
+					ID: "duplicate-footprint-key", Severity: SeverityError, Path: keyPath,
+					Message: "the ledger key is already listed at " + first,
+					Fix:     "list each key once, in readWrite if the transaction writes it; use the footprint returned by simulation unchanged",
+				})
+				continue
+			}
+			seen[raw] = keyPath
+		}
+	}
+}
+
+func (a *analysis) checkMuxedWithMemo(path string, op xdr.Operation) {
+	var dest xdr.MuxedAccount
+	switch op.Body.Type {
+	case xdr.OperationTypePayment:
+		dest = op.Body.MustPaymentOp().Destination
+	case xdr.OperationTypePathPaymentStrictReceive:
+		dest = op.Body.MustPathPaymentStrictReceiveOp().Destination
+	case xdr.OperationTypePathPaymentStrictSend:
+		dest = op.Body.MustPathPaymentStrictSendOp().Destination
+	default:
+		return
+	}
+	if dest.Type == xdr.CryptoKeyTypeKeyTypeMuxedEd25519 {
+		a.issue(Issue{
+			ID: "muxed-with-memo-id", Severity: SeverityWarning, Path: path + ".body",
+			Message: "the payment goes to a muxed account and the transaction also has an ID memo",
+			Fix:     "identify the recipient with either the muxed (M...) address or the memo, not both; receivers may read only one of them",
+		})
+	}
+}
+
+func (a *analysis) checkInvoke(path string, op xdr.InvokeHostFunctionOp) {
+	switch op.HostFunction.Type {
+	case xdr.HostFunctionTypeHostFunctionTypeInvokeContract:
+		args := op.HostFunction.MustInvokeContract().Args
+		for i, arg := range args {
+			a.checkScVal(fmt.Sprintf("%s.hostFunction.invokeContract.args[%d]", path, i), arg)
+		}
+	case xdr.HostFunctionTypeHostFunctionTypeCreateContractV2:
+		for i, arg := range op.HostFunction.MustCreateContractV2().ConstructorArgs {
+			a.checkScVal(fmt.Sprintf("%s.hostFunction.createContractV2.constructorArgs[%d]", path, i), arg)
+		}
+	}
+
+	for i, entry := range op.Auth {
+		entryPath := fmt.Sprintf("%s.auth[%d]", path, i)
+		if creds, ok := entry.Credentials.GetAddress(); ok {
+			a.checkAuthCredentials(entryPath+".credentials.address", creds)
+		}
+		a.checkInvocation(entryPath+".rootInvocation", entry.RootInvocation)
+	}
+}
+
+func (a *analysis) checkAuthCredentials(path string, creds xdr.SorobanAddressCredentials) {
+	if creds.Address.Type == xdr.ScAddressTypeScAddressTypeMuxedAccount {
+		a.issue(Issue{
+			ID: "muxed-auth-address", Severity: SeverityError, Path: path + ".address",
+			Message: "the authorization entry is for a muxed account",
+			Fix:     "authorize with the underlying G... account; muxed IDs cannot sign Soroban authorizations",
+		})
+	}
+	if creds.Signature.Type == xdr.ScValTypeScvVoid {
+		a.issue(Issue{
+			ID: "unsigned-auth-entry", Severity: SeverityWarning, Path: path + ".signature",
+			Message: "the address authorization entry has no signature",
+			Fix:     "sign the entry with the address's key (authorizeEntry in JS) before submitting",
+		})
+	}
+	if creds.SignatureExpirationLedger == 0 {
+		a.issue(Issue{
+			ID: "zero-auth-expiration", Severity: SeverityError, Path: path + ".signatureExpirationLedger",
+			Message: "the authorization entry expires at ledger 0",
+			Fix:     "set the signature expiration ledger to a ledger after the current one when signing the entry",
+		})
+	}
+}
+
+func (a *analysis) checkInvocation(path string, inv xdr.SorobanAuthorizedInvocation) {
+	if fn, ok := inv.Function.GetContractFn(); ok {
+		for i, arg := range fn.Args {
+			a.checkScVal(fmt.Sprintf("%s.function.contractFn.args[%d]", path, i), arg)
+		}
+	}
+	for i, sub := range inv.SubInvocations {
+		a.checkInvocation(fmt.Sprintf("%s.subInvocations[%d]", path, i), sub)
+	}
+}
+
+// checkScVal reports maps anywhere in v whose keys are not strictly
+// ascending, which the host rejects
+func (a *analysis) checkScVal(path string, v xdr.ScVal) {
+	switch v.Type {
+	case xdr.ScValTypeScvVec:
+		for i, item := range scVec(v) {
+			a.checkScVal(fmt.Sprintf("%s.vec[%d]", path, i), item)
+		}
+	case xdr.ScValTypeScvMap:
+		m := scMap(v)
+		for i := 1; i < len(m); i++ {
+			switch c := compareScVal(m[i-1].Key, m[i].Key); {
+			case c == 0:
+				a.issue(Issue{
+					ID: "duplicate-map-key", Severity: SeverityError, Path: fmt.Sprintf("%s.map[%d].key", path, i),
+					Message: "the SCVal map repeats a key",
+					Fix:     "build maps from a dictionary so each key appears once",
+				})
+			case c > 0:
+				a.issue(Issue{
+					ID: "unsorted-map", Severity: SeverityError, Path: fmt.Sprintf("%s.map[%d].key", path, i),
+					Message: "the SCVal map keys are not sorted; the host rejects non-canonical maps",
+					Fix:     "sort map entries by key before encoding (nativeToScVal sorts them in JS; hand-built ScMap values must be sorted explicitly)",
+				})
+			}
+		}
+		for i, entry := range m {
+			a.checkScVal(fmt.Sprintf("%s.map[%d].val", path, i), entry.Val)
+		}
+	}
+}
+
+func isSorobanOp(t xdr.OperationType) bool {
+	switch t {
+	case xdr.OperationTypeInvokeHostFunction, xdr.OperationTypeExtendFootprintTtl, xdr.OperationTypeRestoreFootprint:
+		return true
+	}
+	return false
+}
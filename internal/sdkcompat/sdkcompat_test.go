@@ -0,0 +1,171 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package sdkcompat
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAccount = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func sym(s string) xdr.ScVal {
+	v := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+}
+
+func i32(n int32) xdr.ScVal {
+	v := xdr.Int32(n)
+	return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &v}
+}
+
+func scMapOf(entries ...xdr.ScMapEntry) xdr.ScVal {
+	m := xdr.ScMap(entries)
+	pm := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &pm}
+}
+
+func contractAddress() xdr.ScAddress {
+	id := xdr.ContractId{1}
+	return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}
+}
+
+func invokeTx(args []xdr.ScVal, auth []xdr.SorobanAuthorizationEntry) xdr.Transaction {
+	return xdr.Transaction{
+		SourceAccount: xdr.MustMuxedAddress(testAccount),
+		Fee:           1100,
+		SeqNum:        1,
+		Operations: []xdr.Operation{{Body: xdr.OperationBody{
+			Type: xdr.OperationTypeInvokeHostFunction,
+			InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+				HostFunction: xdr.HostFunction{
+					Type:           xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+					InvokeContract: &xdr.InvokeContractArgs{ContractAddress: contractAddress(), FunctionName: "set", Args: args},
+				},
+				Auth: auth,
+			},
+		}}},
+		Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{ResourceFee: 1000}},
+	}
+}
+
+func envelope(tx xdr.Transaction) xdr.TransactionEnvelope {
+	return xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1:   &xdr.TransactionV1Envelope{Tx: tx},
+	}
+}
+
+func issueIDs(r *Report) map[string]string {
+	ids := make(map[string]string)
+	for _, is := range r.Issues {
+		ids[is.ID] = is.Path
+	}
+	return ids
+}
+
+func TestAnalyzeStellarCLIStyleEnvelope(t *testing.T) {
+	muxed := xdr.MustMuxedAddress("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK")
+	auth := xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+			Address: &xdr.SorobanAddressCredentials{
+				Address:                   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeMuxedAccount, MuxedAccount: muxedAccountPtr(muxed)},
+				SignatureExpirationLedger: 100,
+				Signature:                 xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			},
+		},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{Function: xdr.SorobanAuthorizedFunction{
+			Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+			ContractFn: &xdr.InvokeContractArgs{ContractAddress: contractAddress(), FunctionName: "set"},
+		}},
+	}
+	unsorted := scMapOf(
+		xdr.ScMapEntry{Key: sym("b"), Val: i32(1)},
+		xdr.ScMapEntry{Key: sym("aa"), Val: i32(2)},
+	)
+
+	r := AnalyzeEnvelope(envelope(invokeTx([]xdr.ScVal{unsorted}, []xdr.SorobanAuthorizationEntry{auth})))
+
+	assert.Equal(t, BuilderStellarCLI, r.Builder)
+	ids := issueIDs(r)
+	assert.Equal(t, "v1.tx.operations[0].body.invokeHostFunctionOp.hostFunction.invokeContract.args[0].map[1].key", ids["unsorted-map"])
+	assert.Equal(t, "v1.tx.operations[0].body.invokeHostFunctionOp.auth[0].credentials.address.address", ids["muxed-auth-address"])
+	assert.Contains(t, ids, "unsigned-auth-entry")
+	assert.Contains(t, ids, "no-expiry")
+	assert.Contains(t, ids, "unsigned")
+	assert.NotContains(t, ids, "zero-auth-expiration")
+	assert.True(t, r.HasErrors())
+}
+
+func muxedAccountPtr(m xdr.MuxedAccount) *xdr.MuxedEd25519Account {
+	med := m.MustMed25519()
+	return &xdr.MuxedEd25519Account{Id: med.Id, Ed25519: med.Ed25519}
+}
+
+func TestAnalyzeMissingSorobanData(t *testing.T) {
+	tx := invokeTx(nil, nil)
+	tx.Ext = xdr.TransactionExt{V: 0}
+	tx.Cond = xdr.Preconditions{Type: xdr.PreconditionTypePrecondTime, TimeBounds: &xdr.TimeBounds{MaxTime: 1700000000}}
+	env := envelope(tx)
+	env.V1.Signatures = []xdr.DecoratedSignature{{}}
+
+	b64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+	r, err := Analyze(b64)
+	require.NoError(t, err)
+
+	assert.Equal(t, BuilderTimeoutSDK, r.Builder)
+	assert.Equal(t, map[string]string{"missing-soroban-data": "v1.tx.ext"}, issueIDs(r))
+}
+
+func TestAnalyzeFeeAndFootprint(t *testing.T) {
+	key := xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{AccountId: xdr.MustAddress(testAccount)}}
+	tx := invokeTx([]xdr.ScVal{scMapOf(
+		xdr.ScMapEntry{Key: i32(-5), Val: i32(0)},
+		xdr.ScMapEntry{Key: i32(3), Val: i32(0)},
+		xdr.ScMapEntry{Key: i32(3), Val: i32(0)},
+	)}, nil)
+	tx.Fee = 500
+	tx.Ext.SorobanData.Resources.Footprint = xdr.LedgerFootprint{
+		ReadOnly:  []xdr.LedgerKey{key},
+		ReadWrite: []xdr.LedgerKey{key},
+	}
+
+	ids := issueIDs(AnalyzeEnvelope(envelope(tx)))
+	assert.Equal(t, "v1.tx.fee", ids["fee-below-resource-fee"])
+	assert.Equal(t, "v1.tx.ext.sorobanData.resources.footprint.readWrite[0]", ids["duplicate-footprint-key"])
+	assert.Contains(t, ids, "duplicate-map-key")
+	assert.NotContains(t, ids, "unsorted-map", "negative keys sort before positive ones")
+}
+
+func TestAnalyzeMuxedPaymentWithMemoID(t *testing.T) {
+	dest := xdr.MustMuxedAddress("MA7QYNF7SOWQ3GLR2BGMZEHXAVIRZA4KVWLTJJFC7MGXUA74P7UJVAAAAAAAAAAAAAJLK")
+	id := xdr.Uint64(7)
+	tx := xdr.Transaction{
+		SourceAccount: xdr.MustMuxedAddress(testAccount),
+		Fee:           100,
+		Memo:          xdr.Memo{Type: xdr.MemoTypeMemoId, Id: &id},
+		Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondTime, TimeBounds: &xdr.TimeBounds{MaxTime: 1700000000}},
+		Operations: []xdr.Operation{{Body: xdr.OperationBody{
+			Type:      xdr.OperationTypePayment,
+			PaymentOp: &xdr.PaymentOp{Destination: dest, Asset: xdr.MustNewNativeAsset(), Amount: 10},
+		}}},
+	}
+	r := AnalyzeEnvelope(envelope(tx))
+	ids := issueIDs(r)
+	assert.Equal(t, "v1.tx.operations[0].body", ids["muxed-with-memo-id"])
+	assert.False(t, r.HasErrors())
+	assert.Equal(t, "1 warning(s), 1 info(s)", r.Summary())
+}
+
+func TestCompareScVal(t *testing.T) {
+	assert.Negative(t, compareScVal(sym("aa"), sym("b")))
+	assert.Negative(t, compareScVal(i32(-1), i32(1)))
+	assert.Positive(t, compareScVal(sym("a"), i32(1)), "types order before values")
+	assert.Zero(t, compareScVal(scMapOf(xdr.ScMapEntry{Key: sym("a"), Val: i32(1)}), scMapOf(xdr.ScMapEntry{Key: sym("a"), Val: i32(1)})))
+}
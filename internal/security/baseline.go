@@ -0,0 +1,179 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultBaselineFile is the baseline erst picks up from the working
+// directory when none is named
+const DefaultBaselineFile = ".erstignore"
+
+// Suppression accepts the risk of one finding. Every suppression carries a
+// justification so the baseline doubles as an audit record.
+type Suppression struct {
+	ID            string `json:"id"`
+	Justification string `json:"justification"`
+	Line          int    `json:"line"`
+}
+
+// Baseline is a set of accepted findings, read from a file with one
+// suppression per line:
+//
+//	# comment
+//	F-3f2a9c1d7e4b  treasury payouts are reviewed by hand
+//
+// The first field is the stable finding ID; the rest of the line is the
+// justification.
+type Baseline struct {
+	Path         string
+	Suppressions []Suppression
+}
+
+// LoadBaseline reads the baseline at path
+func LoadBaseline(path string) (*Baseline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open baseline: %w", err)
+	}
+	defer f.Close()
+	b, err := ParseBaseline(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	b.Path = path
+	return b, nil
+}
+
+// ParseBaseline reads baseline lines from r. A suppression without a
+// justification, or one repeating an ID, is an error.
+func ParseBaseline(r io.Reader) (*Baseline, error) {
+	b := &Baseline{}
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		id, justification := text, ""
+		if i := strings.IndexFunc(text, unicode.IsSpace); i >= 0 {
+			id, justification = text[:i], strings.TrimSpace(text[i:])
+		}
+		if !strings.HasPrefix(id, "F-") {
+			return nil, fmt.Errorf("line %d: %q is not a finding ID", line, id)
+		}
+		if justification == "" {
+			return nil, fmt.Errorf("line %d: %s needs a justification", line, id)
+		}
+		if prev, ok := seen[id]; ok {
+			return nil, fmt.Errorf("line %d: %s is already suppressed on line %d", line, id, prev)
+		}
+		seen[id] = line
+		b.Suppressions = append(b.Suppressions, Suppression{ID: id, Justification: justification, Line: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Suppressed is a finding the baseline accepted
+type Suppressed struct {
+	Finding
+	Justification string `json:"justification"`
+}
+
+// Apply splits findings into those the baseline does not cover and those
+// it suppresses
+func (b *Baseline) Apply(findings []Finding) (kept []Finding, suppressed []Suppressed) {
+	byID := make(map[string]Suppression, len(b.Suppressions))
+	for _, s := range b.Suppressions {
+		byID[s.ID] = s
+	}
+	for _, f := range findings {
+		id := f.ID
+		if id == "" {
+			id = FindingID(f)
+		}
+		if s, ok := byID[id]; ok {
+			suppressed = append(suppressed, Suppressed{Finding: f, Justification: s.Justification})
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept, suppressed
+}
+
+// Unused returns the suppressions that match none of findings, e.g. for
+// issues that were fixed
+func (b *Baseline) Unused(findings []Finding) []Suppression {
+	present := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		if f.ID != "" {
+			present[f.ID] = true
+		} else {
+			present[FindingID(f)] = true
+		}
+	}
+	var unused []Suppression
+	for _, s := range b.Suppressions {
+		if !present[s.ID] {
+			unused = append(unused, s)
+		}
+	}
+	return unused
+}
+
+// AppendBaseline writes a suppression for every finding to w, each with
+// the given justification, under a dated comment
+func AppendBaseline(w io.Writer, findings []Finding, justification string, now time.Time) error {
+	justification = strings.Join(strings.Fields(justification), " ")
+	if justification == "" {
+		return fmt.Errorf("a justification is required")
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "# Added %s\n", now.UTC().Format("2006-01-02")); err != nil {
+		return err
+	}
+	for _, f := range findings {
+		id := f.ID
+		if id == "" {
+			id = FindingID(f)
+		}
+		if _, err := fmt.Fprintf(w, "# %s [%s] %s\n%s %s\n", f.Severity, f.Type, f.Title, id, justification); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AtLeast reports whether s is as severe as min or more
+func (s Severity) AtLeast(min Severity) bool {
+	rs, ok := severityRank[s]
+	if !ok {
+		return false
+	}
+	return rs <= severityRank[min]
+}
+
+// ParseSeverity accepts a severity name in any case
+func ParseSeverity(s string) (Severity, error) {
+	sev := Severity(strings.ToUpper(strings.TrimSpace(s)))
+	if _, ok := severityRank[sev]; !ok {
+		return "", fmt.Errorf("unknown severity %q (use high, medium, low or info)", s)
+	}
+	return sev, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBaseline(t *testing.T) {
+	b, err := ParseBaseline(strings.NewReader("# accepted risks\n\nF-aaaaaaaaaaaa  treasury payouts are expected\nF-bbbbbbbbbbbb\tknown false positive\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(b.Suppressions) != 2 {
+		t.Fatalf("expected 2 suppressions, got %d", len(b.Suppressions))
+	}
+	if s := b.Suppressions[0]; s.ID != "F-aaaaaaaaaaaa" || s.Justification != "treasury payouts are expected" || s.Line != 3 {
+		t.Errorf("unexpected suppression %+v", s)
+	}
+	if s := b.Suppressions[1]; s.ID != "F-bbbbbbbbbbbb" || s.Justification != "known false positive" {
+		t.Errorf("unexpected suppression %+v", s)
+	}
+
+	for input, want := range map[string]string{
+		"F-aaaaaaaaaaaa\n": "needs a justification",
+		"overflow ok\n":    "not a finding ID",
+		"F-aaaaaaaaaaaa one\nF-aaaaaaaaaaaa two\n":       "already suppressed on line 1",
+		"# only comments\nF-cccccccccccc  fine\n\n# x\n": "",
+	} {
+		_, err := ParseBaseline(strings.NewReader(input))
+		if want == "" {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", input, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+func TestBaselineApply(t *testing.T) {
+	accepted := Finding{Type: FindingHeuristicWarn, Severity: SeverityMedium, Title: "Large transfer"}
+	accepted.ID = FindingID(accepted)
+	fresh := Finding{Type: FindingVerifiedRisk, Severity: SeverityHigh, Title: "Overflow"}
+	fresh.ID = FindingID(fresh)
+
+	var buf bytes.Buffer
+	if err := AppendBaseline(&buf, []Finding{accepted}, "  reviewed\tby hand ", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "# Added 2025-03-01\n") || !strings.Contains(buf.String(), accepted.ID+" reviewed by hand\n") {
+		t.Errorf("unexpected baseline:\n%s", buf.String())
+	}
+	buf.WriteString("F-000000000000  fixed long ago\n")
+
+	b, err := ParseBaseline(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kept, suppressed := b.Apply([]Finding{accepted, fresh})
+	if len(kept) != 1 || kept[0].ID != fresh.ID {
+		t.Errorf("expected only the new finding to remain, got %+v", kept)
+	}
+	if len(suppressed) != 1 || suppressed[0].Justification != "reviewed by hand" {
+		t.Errorf("unexpected suppressed findings %+v", suppressed)
+	}
+	if unused := b.Unused([]Finding{accepted, fresh}); len(unused) != 1 || unused[0].ID != "F-000000000000" {
+		t.Errorf("unexpected unused suppressions %+v", unused)
+	}
+
+	if err := AppendBaseline(&buf, []Finding{fresh}, " ", time.Now()); err == nil {
+		t.Error("expected an error for an empty justification")
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	if !SeverityHigh.AtLeast(SeverityMedium) || SeverityLow.AtLeast(SeverityMedium) || !SeverityMedium.AtLeast(SeverityMedium) {
+		t.Error("unexpected severity ordering")
+	}
+	if sev, err := ParseSeverity("medium"); err != nil || sev != SeverityMedium {
+		t.Errorf("ParseSeverity(medium) = %q, %v", sev, err)
+	}
+	if _, err := ParseSeverity("critical"); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+}
@@ -4,11 +4,15 @@
 package security
 
 import (
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/simulator"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
 
@@ -32,32 +36,65 @@ const (
 
 // Finding represents a security vulnerability or warning
 type Finding struct {
+	ID string `json:"id"`
+	// Rule names the external scanner rule that reported the finding, as
+	// scanner/rule; it is empty for erst's own checks
+	Rule        string      `json:"rule,omitempty"`
 	Type        FindingType `json:"type"`
 	Severity    Severity    `json:"severity"`
 	Title       string      `json:"title"`
 	Description string      `json:"description"`
 	Evidence    string      `json:"evidence,omitempty"`
+	// EventID is the ID of the simulation event the finding was raised on
+	EventID string `json:"event_id,omitempty"`
+	Score   *Score `json:"score,omitempty"`
 }
 
 // Detector analyzes transactions for security vulnerabilities
 type Detector struct {
 	findings []Finding
+	weights  Weights
+	// exposure is the largest amount one operation of the analyzed
+	// transaction moves, nil when it moves none
+	exposure *big.Int
 }
 
-// NewDetector creates a new security detector
+// NewDetector creates a new security detector scoring with DefaultWeights
 func NewDetector() *Detector {
 	return &Detector{
 		findings: make([]Finding, 0),
+		weights:  DefaultWeights(),
 	}
 }
 
-// Analyze performs security checks on transaction data
+// SetWeights changes the weights later findings are scored with
+func (d *Detector) SetWeights(w Weights) {
+	d.weights = w
+}
+
+// Analyze performs security checks on transaction data, with events given
+// in their textual form
 func (d *Detector) Analyze(envelopeXdr, resultMetaXdr string, events []string, logs []string) []Finding {
+	return d.analyze(envelopeXdr, simulator.NewEvents(events...), logs)
+}
+
+// AnalyzeSimulation runs the checks against a simulator response, matching
+// on the fields of its typed events
+func (d *Detector) AnalyzeSimulation(envelopeXdr, resultMetaXdr string, resp *simulator.SimulationResponse) []Finding {
+	if resp == nil {
+		return d.analyze(envelopeXdr, nil, nil)
+	}
+	return d.analyze(envelopeXdr, resp.Events, resp.LogStrings())
+}
+
+func (d *Detector) analyze(envelopeXdr string, events []simulator.Event, logs []string) []Finding {
 	d.findings = make([]Finding, 0)
+	d.exposure = nil
 
 	// Decode envelope
 	envelope, err := decodeEnvelope(envelopeXdr)
 	if err == nil {
+		d.exposure = exposure(envelope)
 		d.checkLargeValueTransfers(envelope)
 		d.checkReentrancyPatterns(envelope, events)
 	}
@@ -65,8 +102,9 @@ func (d *Detector) Analyze(envelopeXdr, resultMetaXdr string, events []string, l
 	// Check patterns that don't require envelope
 	d.checkIntegerOverflow(events, logs)
 	d.checkSuspiciousEvents(events)
-	d.checkAuthorizationBypass(events, logs)
+	d.checkAuthorizationBypass(logs)
 
+	SortFindings(d.findings)
 	return d.findings
 }
 
@@ -75,10 +113,48 @@ func (d *Detector) GetFindings() []Finding {
 	return d.findings
 }
 
-func (d *Detector) addFinding(finding Finding) {
+func (d *Detector) addFinding(finding Finding, v Vector) {
+	finding.ID = FindingID(finding)
+	finding.Score = d.weights.Score(v)
 	d.findings = append(d.findings, finding)
 }
 
+// FindingID derives a stable identifier from the content of a finding, so
+// the same issue keeps its ID across runs and machines
+func FindingID(f Finding) string {
+	fields := []string{string(f.Type), string(f.Severity), f.Title, f.Evidence}
+	if f.Rule != "" {
+		fields = append(fields, f.Rule)
+	}
+	h := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return "F-" + hex.EncodeToString(h[:6])
+}
+
+var severityRank = map[Severity]int{
+	SeverityHigh:   0,
+	SeverityMedium: 1,
+	SeverityLow:    2,
+	SeverityInfo:   3,
+}
+
+// SortFindings orders findings canonically: most severe first, then by
+// title, evidence and ID
+func SortFindings(findings []Finding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if ra, rb := severityRank[a.Severity], severityRank[b.Severity]; ra != rb {
+			return ra < rb
+		}
+		if a.Title != b.Title {
+			return a.Title < b.Title
+		}
+		if a.Evidence != b.Evidence {
+			return a.Evidence < b.Evidence
+		}
+		return a.ID < b.ID
+	})
+}
+
 // checkLargeValueTransfers detects unusually large value transfers
 func (d *Detector) checkLargeValueTransfers(envelope xdr.TransactionEnvelope) {
 	const largeTransferThreshold = 1000000 * 10000000 // 1M XLM in stroops
@@ -95,7 +171,7 @@ func (d *Detector) checkLargeValueTransfers(envelope xdr.TransactionEnvelope) {
 					Title:       "Large Value Transfer Detected",
 					Description: fmt.Sprintf("Transfer of %d stroops (%.2f XLM) detected. Verify recipient address.", payment.Amount, float64(payment.Amount)/10000000.0),
 					Evidence:    fmt.Sprintf("Destination: %s", payment.Destination.Address()),
-				})
+				}, Vector{Impact: RatingHigh, Likelihood: RatingLow, Asset: assetRating(big.NewInt(int64(payment.Amount)))})
 			}
 		case xdr.OperationTypeInvokeHostFunction:
 			// Check for large amounts in contract invocations
@@ -128,7 +204,7 @@ func (d *Detector) checkContractValueTransfer(op xdr.Operation) {
 						Title:       "Large Contract Value Transfer",
 						Description: fmt.Sprintf("Contract invocation with large amount: %s", amount.String()),
 						Evidence:    "Review contract address and function parameters",
-					})
+					}, Vector{Impact: RatingMedium, Likelihood: RatingLow, Asset: assetRating(amount)})
 				}
 			}
 		}
@@ -136,7 +212,7 @@ func (d *Detector) checkContractValueTransfer(op xdr.Operation) {
 }
 
 // checkReentrancyPatterns detects potential reentrancy vulnerabilities
-func (d *Detector) checkReentrancyPatterns(envelope xdr.TransactionEnvelope, events []string) {
+func (d *Detector) checkReentrancyPatterns(envelope xdr.TransactionEnvelope, events []simulator.Event) {
 	ops := extractOperations(envelope)
 
 	// Count contract invocations
@@ -151,7 +227,7 @@ func (d *Detector) checkReentrancyPatterns(envelope xdr.TransactionEnvelope, eve
 	if invocationCount > 1 {
 		hasStateChange := false
 		for _, event := range events {
-			if strings.Contains(event, "contract_data") || strings.Contains(event, "write") {
+			if eventHas(event, "contract_data", "write") {
 				hasStateChange = true
 				break
 			}
@@ -164,13 +240,28 @@ func (d *Detector) checkReentrancyPatterns(envelope xdr.TransactionEnvelope, eve
 				Title:       "Potential Reentrancy Pattern",
 				Description: fmt.Sprintf("Transaction contains %d contract invocations with state changes. Verify reentrancy guards are in place.", invocationCount),
 				Evidence:    "Multiple contract calls with storage modifications detected",
-			})
+			}, Vector{Impact: RatingHigh, Likelihood: RatingMedium, Asset: assetRating(d.exposure)})
 		}
 	}
 }
 
-// checkIntegerOverflow detects potential integer overflow issues
-func (d *Detector) checkIntegerOverflow(events []string, logs []string) {
+// checkIntegerOverflow detects potential integer overflow issues, from host
+// arithmetic errors and from the debug logs
+func (d *Detector) checkIntegerOverflow(events []simulator.Event, logs []string) {
+	for _, event := range events {
+		if event.Typed() && isErrorEvent(event) && eventHas(event, "ArithDomain") {
+			d.addFinding(Finding{
+				Type:        FindingVerifiedRisk,
+				Severity:    SeverityHigh,
+				Title:       "Integer Overflow/Underflow Detected",
+				Description: "Arithmetic operation failed, indicating potential overflow or underflow",
+				Evidence:    event.String(),
+				EventID:     event.ID,
+			}, Vector{Impact: RatingHigh, Likelihood: RatingHigh, Asset: assetRating(d.exposure)})
+			return
+		}
+	}
+
 	overflowKeywords := []string{"overflow", "underflow"}
 	arithmeticKeywords := []string{"checked_add", "checked_sub", "checked_mul", "checked_div", "arithmetic"}
 
@@ -186,7 +277,7 @@ func (d *Detector) checkIntegerOverflow(events []string, logs []string) {
 					Title:       "Integer Overflow/Underflow Detected",
 					Description: "Arithmetic operation failed, indicating potential overflow or underflow",
 					Evidence:    log,
-				})
+				}, Vector{Impact: RatingHigh, Likelihood: RatingHigh, Asset: assetRating(d.exposure)})
 				return
 			}
 		}
@@ -200,44 +291,82 @@ func (d *Detector) checkIntegerOverflow(events []string, logs []string) {
 					Title:       "Integer Overflow/Underflow Detected",
 					Description: "Arithmetic operation failed, indicating potential overflow or underflow",
 					Evidence:    log,
-				})
+				}, Vector{Impact: RatingHigh, Likelihood: RatingHigh, Asset: assetRating(d.exposure)})
 				return
 			}
 		}
 	}
 }
 
-// checkSuspiciousEvents analyzes diagnostic events for suspicious patterns
-func (d *Detector) checkSuspiciousEvents(events []string) {
+// checkSuspiciousEvents analyzes diagnostic events for suspicious patterns.
+// Typed events are matched on the host error they carry; untyped ones, from
+// older simulators and sessions, on their rendering.
+func (d *Detector) checkSuspiciousEvents(events []simulator.Event) {
 	for _, event := range events {
-		eventLower := strings.ToLower(event)
+		var authFailed, trapped bool
+		if event.Typed() {
+			failed := isErrorEvent(event)
+			authFailed = failed && eventHas(event, "Auth")
+			trapped = failed && eventHas(event, "WasmVm", "panic", "trap", "unreachable")
+		} else {
+			authFailed = eventHas(event, "auth") && eventHas(event, "fail", "invalid")
+			trapped = eventHas(event, "panic", "trap")
+		}
 
-		// Check for authorization failures
-		if strings.Contains(eventLower, "auth") && (strings.Contains(eventLower, "fail") || strings.Contains(eventLower, "invalid")) {
+		if authFailed {
 			d.addFinding(Finding{
 				Type:        FindingVerifiedRisk,
 				Severity:    SeverityHigh,
 				Title:       "Authorization Failure",
 				Description: "Contract authorization check failed",
-				Evidence:    event,
-			})
+				Evidence:    event.String(),
+				EventID:     event.ID,
+			}, Vector{Impact: RatingMedium, Likelihood: RatingHigh, Asset: assetRating(d.exposure)})
 		}
 
-		// Check for panic/trap events
-		if strings.Contains(eventLower, "panic") || strings.Contains(eventLower, "trap") {
+		if trapped {
 			d.addFinding(Finding{
 				Type:        FindingVerifiedRisk,
 				Severity:    SeverityHigh,
 				Title:       "Contract Panic/Trap",
 				Description: "Contract execution panicked or trapped",
-				Evidence:    event,
-			})
+				Evidence:    event.String(),
+				EventID:     event.ID,
+			}, Vector{Impact: RatingLow, Likelihood: RatingHigh, Asset: assetRating(d.exposure)})
 		}
 	}
 }
 
+// isErrorEvent reports whether the host emitted e for an error: its first
+// topic is the error symbol
+func isErrorEvent(e simulator.Event) bool {
+	if len(e.Topics) == 0 {
+		return false
+	}
+	topic := strings.ToLower(e.Topics[0])
+	return topic == "error" || strings.Contains(topic, "(error)")
+}
+
+// eventHas reports whether a topic or the data of e contains one of words,
+// ignoring case. Untyped events are searched by their raw rendering.
+func eventHas(e simulator.Event, words ...string) bool {
+	fields := append([]string{e.Data}, e.Topics...)
+	if !e.Typed() {
+		fields = []string{e.Raw}
+	}
+	for _, field := range fields {
+		field = strings.ToLower(field)
+		for _, w := range words {
+			if strings.Contains(field, strings.ToLower(w)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // checkAuthorizationBypass detects potential authorization bypass attempts
-func (d *Detector) checkAuthorizationBypass(events []string, logs []string) {
+func (d *Detector) checkAuthorizationBypass(logs []string) {
 	hasAuthCheck := false
 	hasPrivilegedOp := false
 
@@ -259,7 +388,7 @@ func (d *Detector) checkAuthorizationBypass(events []string, logs []string) {
 			Title:       "Potential Authorization Bypass",
 			Description: "Privileged operation detected without corresponding authorization check",
 			Evidence:    "Review contract authorization logic",
-		})
+		}, Vector{Impact: RatingHigh, Likelihood: RatingMedium, Asset: assetRating(d.exposure)})
 	}
 }
 
@@ -287,6 +416,53 @@ func extractOperations(envelope xdr.TransactionEnvelope) []xdr.Operation {
 	return nil
 }
 
+// Amounts, in stroops or 7-decimal token units, at which the asset rating of
+// a finding rises to medium and high
+var (
+	mediumExposure = big.NewInt(1000 * 10000000)
+	highExposure   = big.NewInt(100000 * 10000000)
+)
+
+// assetRating rates the value at risk from the amount moved. A transaction
+// that moves no amount is rated low rather than none, since the contracts it
+// calls may still hold value.
+func assetRating(amount *big.Int) Rating {
+	switch {
+	case amount == nil || amount.CmpAbs(mediumExposure) < 0:
+		return RatingLow
+	case amount.CmpAbs(highExposure) < 0:
+		return RatingMedium
+	default:
+		return RatingHigh
+	}
+}
+
+// exposure returns the largest amount one operation of envelope moves: a
+// payment amount or a 128-bit contract argument, nil when there is none
+func exposure(envelope xdr.TransactionEnvelope) *big.Int {
+	var largest *big.Int
+	consider := func(amount *big.Int) {
+		if amount != nil && (largest == nil || amount.CmpAbs(largest) > 0) {
+			largest = new(big.Int).Abs(amount)
+		}
+	}
+	for _, op := range extractOperations(envelope) {
+		switch op.Body.Type {
+		case xdr.OperationTypePayment:
+			consider(big.NewInt(int64(op.Body.PaymentOp.Amount)))
+		case xdr.OperationTypeInvokeHostFunction:
+			hostFn := op.Body.InvokeHostFunctionOp
+			if hostFn == nil || hostFn.HostFunction.InvokeContract == nil {
+				continue
+			}
+			for _, arg := range hostFn.HostFunction.InvokeContract.Args {
+				consider(extractAmount(arg))
+			}
+		}
+	}
+	return largest
+}
+
 func extractAmount(val xdr.ScVal) *big.Int {
 	switch val.Type {
 	case xdr.ScValTypeScvI128:
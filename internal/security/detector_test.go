@@ -6,6 +6,8 @@ package security
 import (
 	"strings"
 	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
 )
 
 func TestDetector_LargeValueTransfer(t *testing.T) {
@@ -101,6 +103,34 @@ func TestDetector_ContractPanic(t *testing.T) {
 	}
 }
 
+func TestDetector_TypedEvents(t *testing.T) {
+	errorTopic := "Symbol(ScSymbol(StringM(error)))"
+	resp := &simulator.SimulationResponse{Events: []simulator.Event{
+		// A contract event whose fields merely mention auth and failing
+		{ID: "e0", Type: "contract", ContractID: "CTOKEN", Topics: []string{"Symbol(set_auth)"}, Data: "String(failover)"},
+		{ID: "e1", Type: "diagnostic", Topics: []string{errorTopic, "Error(Auth, InvalidAction)"}, Data: "String(signature mismatch)"},
+		{ID: "e2", Type: "diagnostic", Topics: []string{errorTopic, "Error(WasmVm, InvalidAction)"}},
+		{ID: "e3", Type: "diagnostic", Topics: []string{errorTopic, "Error(Object, ArithDomain)"}},
+	}}
+
+	byTitle := map[string]Finding{}
+	for _, f := range NewDetector().AnalyzeSimulation("", "", resp) {
+		byTitle[f.Title] = f
+	}
+	if len(byTitle) != 3 {
+		t.Fatalf("expected 3 findings, got %+v", byTitle)
+	}
+	for title, id := range map[string]string{
+		"Authorization Failure":               "e1",
+		"Contract Panic/Trap":                 "e2",
+		"Integer Overflow/Underflow Detected": "e3",
+	} {
+		if f, ok := byTitle[title]; !ok || f.EventID != id {
+			t.Errorf("expected %q on event %s, got %+v", title, id, f)
+		}
+	}
+}
+
 func TestDetector_ReentrancyPattern(t *testing.T) {
 	detector := NewDetector()
 
@@ -199,3 +229,28 @@ func TestDetector_MultipleFindings(t *testing.T) {
 		t.Error("Expected at least one heuristic warning")
 	}
 }
+
+func TestSortFindings_StableIDs(t *testing.T) {
+	low := Finding{Type: FindingHeuristicWarn, Severity: SeverityLow, Title: "B"}
+	high := Finding{Type: FindingVerifiedRisk, Severity: SeverityHigh, Title: "A"}
+	medium := Finding{Type: FindingHeuristicWarn, Severity: SeverityMedium, Title: "A"}
+
+	findings := []Finding{low, medium, high}
+	SortFindings(findings)
+	if findings[0].Severity != SeverityHigh || findings[1].Severity != SeverityMedium || findings[2].Severity != SeverityLow {
+		t.Fatalf("unexpected order: %+v", findings)
+	}
+
+	if FindingID(high) != FindingID(high) {
+		t.Error("expected FindingID to be deterministic")
+	}
+	if FindingID(high) == FindingID(medium) {
+		t.Error("expected different findings to have different IDs")
+	}
+
+	for _, f := range NewDetector().Analyze("", "", []string{"panic"}, nil) {
+		if f.ID != FindingID(f) {
+			t.Errorf("finding %q has ID %q, want %q", f.Title, f.ID, FindingID(f))
+		}
+	}
+}
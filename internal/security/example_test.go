@@ -33,6 +33,6 @@ func Example() {
 
 	// Output:
 	// Found 2 security issues:
-	// 1. [VERIFIED_RISK] HIGH - Integer Overflow/Underflow Detected
-	// 2. [VERIFIED_RISK] HIGH - Contract Panic/Trap
+	// 1. [VERIFIED_RISK] HIGH - Contract Panic/Trap
+	// 2. [VERIFIED_RISK] HIGH - Integer Overflow/Underflow Detected
 }
@@ -0,0 +1,200 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultScannerTimeout bounds a scanner run when none is configured
+	DefaultScannerTimeout = 60 * time.Second
+	// maxScannerOutput caps what is read from a scanner's stdout
+	maxScannerOutput = 16 << 20
+)
+
+var scannerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Scanner is an external analyzer. It is run with the session JSON on
+// stdin and prints its findings as JSON on stdout:
+//
+//	{"findings": [{"rule": "unchecked-call", "severity": "HIGH",
+//	  "title": "...", "description": "...", "evidence": "...",
+//	  "type": "HEURISTIC_WARNING", "vector": "I:H/L:M/A:M"}]}
+//
+// A bare array of findings is accepted too. Rules are namespaced with the
+// scanner name, so "unchecked-call" from scanner "acme" becomes
+// "acme/unchecked-call".
+type Scanner struct {
+	Name    string
+	Command string
+	Args    []string
+	// Timeout bounds a run; zero means DefaultScannerTimeout
+	Timeout time.Duration
+}
+
+// Validate checks the name and command of s
+func (s Scanner) Validate() error {
+	if !scannerNamePattern.MatchString(s.Name) {
+		return fmt.Errorf("scanner name %q must be lowercase letters, digits, '-' or '_'", s.Name)
+	}
+	if strings.TrimSpace(s.Command) == "" {
+		return fmt.Errorf("scanner %s has no command", s.Name)
+	}
+	return nil
+}
+
+// ParseScanner reads a scanner given as name=command; the command is split
+// on spaces into the program and its arguments
+func ParseScanner(spec string) (Scanner, error) {
+	name, command, ok := strings.Cut(spec, "=")
+	if !ok {
+		return Scanner{}, fmt.Errorf("invalid scanner %q (use name=command)", spec)
+	}
+	fields := strings.Fields(command)
+	s := Scanner{Name: strings.TrimSpace(name)}
+	if len(fields) > 0 {
+		s.Command, s.Args = fields[0], fields[1:]
+	}
+	return s, s.Validate()
+}
+
+// externalFinding is a finding as a scanner reports it
+type externalFinding struct {
+	Rule        string `json:"rule"`
+	Type        string `json:"type"`
+	Severity    string `json:"severity"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Evidence    string `json:"evidence"`
+	Vector      string `json:"vector"`
+}
+
+// RunScanner runs s with input on stdin and returns its findings, scored
+// with weights when they carry a vector
+func RunScanner(ctx context.Context, s Scanner, input []byte, weights Weights) ([]Finding, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultScannerTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, s.Command, s.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	stdout := &limitedBuffer{max: maxScannerOutput}
+	var stderr bytes.Buffer
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	// Children of the scanner may hold its output open after it is killed
+	cmd.WaitDelay = 2 * time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("scanner %s timed out after %s", s.Name, timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("scanner %s failed: %w: %s", s.Name, err, lastLine(msg))
+		}
+		return nil, fmt.Errorf("scanner %s failed: %w", s.Name, err)
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("scanner %s output exceeds %d MiB", s.Name, maxScannerOutput>>20)
+	}
+	return parseScannerOutput(s.Name, stdout.Bytes(), weights)
+}
+
+// parseScannerOutput converts the output of scanner name into findings
+func parseScannerOutput(name string, out []byte, weights Weights) ([]Finding, error) {
+	out = bytes.TrimSpace(out)
+	if len(out) == 0 {
+		return nil, nil
+	}
+	var raw []externalFinding
+	if out[0] == '[' {
+		if err := json.Unmarshal(out, &raw); err != nil {
+			return nil, fmt.Errorf("scanner %s: invalid output: %w", name, err)
+		}
+	} else {
+		var wrapped struct {
+			Findings []externalFinding `json:"findings"`
+		}
+		if err := json.Unmarshal(out, &wrapped); err != nil {
+			return nil, fmt.Errorf("scanner %s: invalid output: %w", name, err)
+		}
+		raw = wrapped.Findings
+	}
+
+	findings := make([]Finding, 0, len(raw))
+	for i, r := range raw {
+		if r.Rule == "" || r.Title == "" {
+			return nil, fmt.Errorf("scanner %s: finding %d needs a rule and a title", name, i)
+		}
+		sev, err := ParseSeverity(r.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("scanner %s: finding %d: %w", name, i, err)
+		}
+		f := Finding{
+			Rule:        name + "/" + r.Rule,
+			Type:        FindingHeuristicWarn,
+			Severity:    sev,
+			Title:       r.Title,
+			Description: r.Description,
+			Evidence:    r.Evidence,
+		}
+		switch FindingType(strings.ToUpper(r.Type)) {
+		case FindingVerifiedRisk:
+			f.Type = FindingVerifiedRisk
+		case FindingHeuristicWarn, "":
+		default:
+			return nil, fmt.Errorf("scanner %s: finding %d: unknown type %q", name, i, r.Type)
+		}
+		if r.Vector != "" {
+			v, err := ParseVector(r.Vector)
+			if err != nil {
+				return nil, fmt.Errorf("scanner %s: finding %d: %w", name, i, err)
+			}
+			f.Score = weights.Score(v)
+		}
+		f.ID = FindingID(f)
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+func lastLine(s string) string {
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
+
+// limitedBuffer keeps the first max bytes written to it
+type limitedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeScanner(t *testing.T, script string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scanner")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatalf("failed to write scanner: %v", err)
+	}
+	return path
+}
+
+func TestRunScanner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script scanner")
+	}
+	// The scanner echoes the network it was given back as evidence
+	bin := writeScanner(t, `net=$(sed -n 's/.*"network":"\([^"]*\)".*/\1/p')
+echo '{"findings":[{"rule":"unchecked-call","severity":"high","title":"Unchecked call","evidence":"'$net'","vector":"I:H/L:M/A:M"}]}'
+`)
+	findings, err := RunScanner(context.Background(), Scanner{Name: "acme", Command: bin}, []byte(`{"network":"testnet"}`), DefaultWeights())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(findings) != 1 {
+		t.Fatalf("expected 1 finding, got %d", len(findings))
+	}
+	f := findings[0]
+	if f.Rule != "acme/unchecked-call" || f.Severity != SeverityHigh || f.Type != FindingHeuristicWarn || f.Evidence != "testnet" {
+		t.Errorf("unexpected finding %+v", f)
+	}
+	if f.Score == nil || f.Score.Vector != "I:H/L:M/A:M" {
+		t.Errorf("unexpected score %+v", f.Score)
+	}
+	if f.ID == "" || f.ID == FindingID(Finding{Type: f.Type, Severity: f.Severity, Title: f.Title, Evidence: f.Evidence}) {
+		t.Errorf("expected the rule to be part of the ID, got %q", f.ID)
+	}
+
+	failing := writeScanner(t, "echo 'license expired' >&2\nexit 3\n")
+	if _, err := RunScanner(context.Background(), Scanner{Name: "acme", Command: failing}, nil, DefaultWeights()); err == nil || !strings.Contains(err.Error(), "license expired") {
+		t.Errorf("expected the scanner's stderr in the error, got %v", err)
+	}
+
+	slow := writeScanner(t, "exec sleep 5\n")
+	if _, err := RunScanner(context.Background(), Scanner{Name: "acme", Command: slow, Timeout: 100 * time.Millisecond}, nil, DefaultWeights()); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("expected a timeout, got %v", err)
+	}
+}
+
+func TestParseScannerOutput(t *testing.T) {
+	findings, err := parseScannerOutput("acme", []byte(`[{"rule":"r1","severity":"LOW","title":"T","type":"verified_risk"}]`), DefaultWeights())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Type != FindingVerifiedRisk || findings[0].Score != nil {
+		t.Errorf("unexpected findings %+v", findings)
+	}
+	if findings, err := parseScannerOutput("acme", []byte("  \n"), DefaultWeights()); err != nil || len(findings) != 0 {
+		t.Errorf("expected no findings for empty output, got %v, %v", findings, err)
+	}
+
+	for out, want := range map[string]string{
+		`not json`:                         "invalid output",
+		`[{"rule":"r","severity":"HIGH"}]`: "needs a rule and a title",
+		`[{"rule":"r","title":"T","severity":"urgent"}]`:              "unknown severity",
+		`[{"rule":"r","title":"T","severity":"HIGH","type":"other"}]`: "unknown type",
+		`[{"rule":"r","title":"T","severity":"HIGH","vector":"I:Q"}]`: "invalid vector",
+	} {
+		if _, err := parseScannerOutput("acme", []byte(out), DefaultWeights()); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", out, want, err)
+		}
+	}
+}
+
+func TestParseScanner(t *testing.T) {
+	s, err := ParseScanner("acme=./bin/acme --strict  --json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Name != "acme" || s.Command != "./bin/acme" || len(s.Args) != 2 || s.Args[1] != "--json" {
+		t.Errorf("unexpected scanner %+v", s)
+	}
+	for _, bad := range []string{"acme", "Acme=x", "acme=", "a/b=x"} {
+		if _, err := ParseScanner(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"sort"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Finding statuses in a history
+const (
+	StatusNew       = "new"
+	StatusRecurring = "recurring"
+	StatusResolved  = "resolved"
+)
+
+// Observation is the findings of one analyzed transaction
+type Observation struct {
+	SessionID string    `json:"session_id"`
+	TxHash    string    `json:"tx_hash"`
+	At        time.Time `json:"at"`
+	Findings  []Finding `json:"-"`
+}
+
+// ObservationSummary is an observation as listed in a history: how many of
+// its findings were seen for the first time
+type ObservationSummary struct {
+	Observation
+	New       []string `json:"new"`
+	Recurring []string `json:"recurring"`
+}
+
+// TrackedFinding follows one finding ID through a history. Status compares
+// the latest observation with the earlier ones: new if it first appeared
+// there, recurring if it appeared before too, resolved if it is gone.
+type TrackedFinding struct {
+	Finding
+	Status       string    `json:"status"`
+	FirstSeen    time.Time `json:"first_seen"`
+	LastSeen     time.Time `json:"last_seen"`
+	FirstSession string    `json:"first_session"`
+	LastSession  string    `json:"last_session"`
+	Occurrences  int       `json:"occurrences"`
+}
+
+// History is the findings of a series of observations, oldest first
+type History struct {
+	Observations []ObservationSummary `json:"observations"`
+	Findings     []TrackedFinding     `json:"findings"`
+}
+
+// BuildHistory orders observations by time and tracks each finding ID
+// across them
+func BuildHistory(observations []Observation) *History {
+	sorted := append([]Observation(nil), observations...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].At.Before(sorted[j].At) })
+
+	h := &History{Observations: []ObservationSummary{}, Findings: []TrackedFinding{}}
+	byID := make(map[string]int)
+	for _, obs := range sorted {
+		summary := ObservationSummary{Observation: obs, New: []string{}, Recurring: []string{}}
+		seen := make(map[string]bool)
+		for _, f := range obs.Findings {
+			id := f.ID
+			if id == "" {
+				id = FindingID(f)
+				f.ID = id
+			}
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if i, ok := byID[id]; ok {
+				t := &h.Findings[i]
+				t.LastSeen, t.LastSession = obs.At, obs.SessionID
+				t.Occurrences++
+				summary.Recurring = append(summary.Recurring, id)
+				continue
+			}
+			byID[id] = len(h.Findings)
+			h.Findings = append(h.Findings, TrackedFinding{
+				Finding:      f,
+				FirstSeen:    obs.At,
+				LastSeen:     obs.At,
+				FirstSession: obs.SessionID,
+				LastSession:  obs.SessionID,
+				Occurrences:  1,
+			})
+			summary.New = append(summary.New, id)
+		}
+		h.Observations = append(h.Observations, summary)
+	}
+
+	if len(sorted) > 0 {
+		latest := sorted[len(sorted)-1].SessionID
+		for i := range h.Findings {
+			t := &h.Findings[i]
+			switch {
+			case t.LastSession != latest:
+				t.Status = StatusResolved
+			case t.Occurrences == 1:
+				t.Status = StatusNew
+			default:
+				t.Status = StatusRecurring
+			}
+		}
+	}
+	sort.SliceStable(h.Findings, func(i, j int) bool {
+		a, b := h.Findings[i], h.Findings[j]
+		if a.Status != b.Status {
+			return statusRank[a.Status] < statusRank[b.Status]
+		}
+		if ra, rb := severityRank[a.Severity], severityRank[b.Severity]; ra != rb {
+			return ra < rb
+		}
+		return a.ID < b.ID
+	})
+	return h
+}
+
+var statusRank = map[string]int{StatusNew: 0, StatusRecurring: 1, StatusResolved: 2}
+
+// Count returns how many tracked findings have status
+func (h *History) Count(status string) int {
+	n := 0
+	for _, f := range h.Findings {
+		if f.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// TouchedContracts returns the contracts a transaction envelope invokes or
+// has in its Soroban footprint, as strkeys
+func TouchedContracts(envelopeXdr string) []string {
+	envelope, err := decodeEnvelope(envelopeXdr)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var contracts []string
+	add := func(addr xdr.ScAddress) {
+		if addr.Type != xdr.ScAddressTypeScAddressTypeContract {
+			return
+		}
+		s, err := addr.String()
+		if err != nil || seen[s] {
+			return
+		}
+		seen[s] = true
+		contracts = append(contracts, s)
+	}
+
+	for _, op := range extractOperations(envelope) {
+		hf, ok := op.Body.GetInvokeHostFunctionOp()
+		if !ok {
+			continue
+		}
+		if args, ok := hf.HostFunction.GetInvokeContract(); ok {
+			add(args.ContractAddress)
+		}
+	}
+
+	var ext xdr.TransactionExt
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		ext = envelope.V1.Tx.Ext
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		ext = envelope.FeeBump.Tx.InnerTx.V1.Tx.Ext
+	}
+	if data, ok := ext.GetSorobanData(); ok {
+		keys := append(append([]xdr.LedgerKey(nil), data.Resources.Footprint.ReadOnly...), data.Resources.Footprint.ReadWrite...)
+		for _, key := range keys {
+			if cd, ok := key.GetContractData(); ok {
+				add(cd.Contract)
+			}
+		}
+	}
+	sort.Strings(contracts)
+	return contracts
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestBuildHistory(t *testing.T) {
+	fixed := Finding{Severity: SeverityMedium, Title: "Reentrancy"}
+	fixed.ID = FindingID(fixed)
+	ongoing := Finding{Severity: SeverityLow, Title: "Panic"}
+	ongoing.ID = FindingID(ongoing)
+	introduced := Finding{Severity: SeverityHigh, Title: "Overflow"}
+	introduced.ID = FindingID(introduced)
+
+	day := func(d int) time.Time { return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC) }
+	// Observations are given out of order; the history sorts them by time
+	h := BuildHistory([]Observation{
+		{SessionID: "s3", At: day(3), Findings: []Finding{ongoing, introduced, introduced}},
+		{SessionID: "s1", At: day(1), Findings: []Finding{fixed, ongoing}},
+		{SessionID: "s2", At: day(2), Findings: []Finding{ongoing}},
+	})
+
+	if len(h.Observations) != 3 || h.Observations[0].SessionID != "s1" || h.Observations[2].SessionID != "s3" {
+		t.Fatalf("unexpected observations %+v", h.Observations)
+	}
+	if last := h.Observations[2]; len(last.New) != 1 || last.New[0] != introduced.ID || len(last.Recurring) != 1 {
+		t.Errorf("unexpected latest observation %+v", last)
+	}
+
+	want := []struct {
+		id, status  string
+		occurrences int
+	}{
+		{introduced.ID, StatusNew, 1},
+		{ongoing.ID, StatusRecurring, 3},
+		{fixed.ID, StatusResolved, 1},
+	}
+	if len(h.Findings) != len(want) {
+		t.Fatalf("expected %d tracked findings, got %d", len(want), len(h.Findings))
+	}
+	for i, w := range want {
+		f := h.Findings[i]
+		if f.ID != w.id || f.Status != w.status || f.Occurrences != w.occurrences {
+			t.Errorf("finding %d: got %s %s x%d, want %s %s x%d", i, f.ID, f.Status, f.Occurrences, w.id, w.status, w.occurrences)
+		}
+	}
+	if f := h.Findings[1]; !f.FirstSeen.Equal(day(1)) || f.LastSession != "s3" {
+		t.Errorf("unexpected span for recurring finding: %+v", f)
+	}
+	if h.Count(StatusResolved) != 1 {
+		t.Errorf("expected 1 resolved finding, got %d", h.Count(StatusResolved))
+	}
+
+	if empty := BuildHistory(nil); len(empty.Observations) != 0 || len(empty.Findings) != 0 {
+		t.Errorf("expected an empty history, got %+v", empty)
+	}
+}
+
+func TestTouchedContracts(t *testing.T) {
+	invoked := xdr.ContractId{1}
+	stored := xdr.ContractId{2}
+	source := xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	contract := func(id *xdr.ContractId) xdr.ScAddress {
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: id}
+	}
+
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: source.ToMuxedAccount(),
+			Fee:           100,
+			SeqNum:        1,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+					Type:           xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+					InvokeContract: &xdr.InvokeContractArgs{ContractAddress: contract(&invoked), FunctionName: "swap"},
+				}},
+			}}},
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{
+					ReadWrite: []xdr.LedgerKey{{
+						Type:         xdr.LedgerEntryTypeContractData,
+						ContractData: &xdr.LedgerKeyContractData{Contract: contract(&stored), Key: xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, Durability: xdr.ContractDataDurabilityPersistent},
+					}},
+				}},
+			}},
+		}},
+	}
+
+	got := TouchedContracts(encodeEnvelope(t, envelope))
+	wantInvoked, _ := contract(&invoked).String()
+	wantStored, _ := contract(&stored).String()
+	if len(got) != 2 || got[0] != wantInvoked || got[1] != wantStored {
+		t.Errorf("expected [%s %s], got %v", wantInvoked, wantStored, got)
+	}
+
+	if got := TouchedContracts("not xdr"); got != nil {
+		t.Errorf("expected no contracts for invalid XDR, got %v", got)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rating grades one scoring factor
+type Rating string
+
+const (
+	RatingNone   Rating = "N"
+	RatingLow    Rating = "L"
+	RatingMedium Rating = "M"
+	RatingHigh   Rating = "H"
+)
+
+var ratingValue = map[Rating]float64{
+	RatingNone:   0,
+	RatingLow:    1.0 / 3,
+	RatingMedium: 2.0 / 3,
+	RatingHigh:   1,
+}
+
+// Vector rates a finding on the three scoring factors, written in the
+// CVSS-like form I:H/L:M/A:H
+type Vector struct {
+	// Impact is the damage if the issue is real and exploited
+	Impact Rating
+	// Likelihood is how confident the evidence is that the issue is real
+	Likelihood Rating
+	// Asset is the value at risk, from the largest amount the transaction
+	// moves
+	Asset Rating
+}
+
+func (v Vector) String() string {
+	return fmt.Sprintf("I:%s/L:%s/A:%s", v.Impact, v.Likelihood, v.Asset)
+}
+
+// ParseVector reads a vector such as I:H/L:M/A:H; the factors may come in
+// any order but all three are required
+func ParseVector(s string) (Vector, error) {
+	var v Vector
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(strings.TrimSpace(s), "/") {
+		key, value, ok := strings.Cut(part, ":")
+		r := Rating(strings.ToUpper(value))
+		if _, known := ratingValue[r]; !ok || !known {
+			return Vector{}, fmt.Errorf("invalid vector component %q in %q", part, s)
+		}
+		switch strings.ToUpper(key) {
+		case "I":
+			v.Impact = r
+		case "L":
+			v.Likelihood = r
+		case "A":
+			v.Asset = r
+		default:
+			return Vector{}, fmt.Errorf("unknown vector factor %q in %q", key, s)
+		}
+		if seen[strings.ToUpper(key)] {
+			return Vector{}, fmt.Errorf("vector factor %s repeated in %q", key, s)
+		}
+		seen[strings.ToUpper(key)] = true
+	}
+	if len(seen) != 3 {
+		return Vector{}, fmt.Errorf("vector %q needs I, L and A", s)
+	}
+	return v, nil
+}
+
+// Score is the structured rating of a finding. Value runs from 0.0 to 10.0
+// and depends on the weights it was computed with; the ratings do not.
+type Score struct {
+	Vector     string  `json:"vector"`
+	Impact     Rating  `json:"impact"`
+	Likelihood Rating  `json:"likelihood"`
+	Asset      Rating  `json:"asset"`
+	Value      float64 `json:"value"`
+}
+
+// Weights sets how much each factor contributes to a score. Only the
+// ratios matter.
+type Weights struct {
+	Impact     float64 `json:"impact"`
+	Likelihood float64 `json:"likelihood"`
+	Asset      float64 `json:"asset"`
+}
+
+// DefaultWeights favors impact, then likelihood, then asset value
+func DefaultWeights() Weights {
+	return Weights{Impact: 0.4, Likelihood: 0.35, Asset: 0.25}
+}
+
+// ParseWeights reads weights written as impact=2,likelihood=1,asset=1.
+// Omitted factors keep their default weight.
+func ParseWeights(s string) (Weights, error) {
+	w := DefaultWeights()
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Weights{}, fmt.Errorf("invalid weight %q (use factor=value)", part)
+		}
+		n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || n < 0 || math.IsInf(n, 0) || math.IsNaN(n) {
+			return Weights{}, fmt.Errorf("invalid weight %q: must be a non-negative number", part)
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "impact", "i":
+			w.Impact = n
+		case "likelihood", "l":
+			w.Likelihood = n
+		case "asset", "a":
+			w.Asset = n
+		default:
+			return Weights{}, fmt.Errorf("unknown weight %q (use impact, likelihood or asset)", key)
+		}
+	}
+	if w.Impact+w.Likelihood+w.Asset == 0 {
+		return Weights{}, fmt.Errorf("at least one weight must be positive")
+	}
+	return w, nil
+}
+
+func (w Weights) String() string {
+	return fmt.Sprintf("impact=%g,likelihood=%g,asset=%g", w.Impact, w.Likelihood, w.Asset)
+}
+
+// Score rates v under w
+func (w Weights) Score(v Vector) *Score {
+	total := w.Impact + w.Likelihood + w.Asset
+	value := 0.0
+	if total > 0 {
+		value = 10 * (w.Impact*ratingValue[v.Impact] + w.Likelihood*ratingValue[v.Likelihood] + w.Asset*ratingValue[v.Asset]) / total
+	}
+	return &Score{
+		Vector:     v.String(),
+		Impact:     v.Impact,
+		Likelihood: v.Likelihood,
+		Asset:      v.Asset,
+		Value:      math.Round(value*10) / 10,
+	}
+}
+
+// Rescore recomputes the score of each finding that has one under w
+func (w Weights) Rescore(findings []Finding) {
+	for i := range findings {
+		if s := findings[i].Score; s != nil {
+			findings[i].Score = w.Score(Vector{Impact: s.Impact, Likelihood: s.Likelihood, Asset: s.Asset})
+		}
+	}
+}
+
+// SortByScore orders findings highest score first. Findings without a score
+// come last; ties keep the canonical order of SortFindings.
+func SortByScore(findings []Finding) {
+	SortFindings(findings)
+	sort.SliceStable(findings, func(i, j int) bool {
+		return scoreValue(findings[i]) > scoreValue(findings[j])
+	})
+}
+
+func scoreValue(f Finding) float64 {
+	if f.Score == nil {
+		return -1
+	}
+	return f.Score.Value
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestParseVector(t *testing.T) {
+	v, err := ParseVector("A:h/I:M/L:L")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v != (Vector{Impact: RatingMedium, Likelihood: RatingLow, Asset: RatingHigh}) {
+		t.Errorf("unexpected vector %+v", v)
+	}
+	if v.String() != "I:M/L:L/A:H" {
+		t.Errorf("unexpected string %q", v.String())
+	}
+
+	for _, bad := range []string{"", "I:H/L:M", "I:H/L:M/A:X", "I:H/L:M/A:H/I:L", "I:H/L:M/B:H", "IH/L:M/A:H"} {
+		if _, err := ParseVector(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
+
+func TestWeightsScore(t *testing.T) {
+	w := DefaultWeights()
+	if got := w.Score(Vector{RatingHigh, RatingHigh, RatingHigh}).Value; got != 10 {
+		t.Errorf("all high: expected 10, got %v", got)
+	}
+	if got := w.Score(Vector{RatingNone, RatingNone, RatingNone}).Value; got != 0 {
+		t.Errorf("all none: expected 0, got %v", got)
+	}
+
+	impactOnly := Weights{Impact: 1}
+	s := impactOnly.Score(Vector{RatingMedium, RatingHigh, RatingHigh})
+	if s.Value != 6.7 || s.Vector != "I:M/L:H/A:H" {
+		t.Errorf("unexpected score %+v", s)
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	w, err := ParseWeights("impact=2, l=1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if w.Impact != 2 || w.Likelihood != 1 || w.Asset != DefaultWeights().Asset {
+		t.Errorf("unexpected weights %+v", w)
+	}
+
+	for input, want := range map[string]string{
+		"impact":                          "factor=value",
+		"impact=-1":                       "non-negative",
+		"asset=NaN":                       "non-negative",
+		"cost=1":                          "unknown weight",
+		"impact=0,likelihood=0,asset=0.0": "must be positive",
+	} {
+		if _, err := ParseWeights(input); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+func TestSortByScore(t *testing.T) {
+	d := NewDetector()
+	findings := d.Analyze("", "", []string{"contract panic"}, []string{"checked_add failed: overflow", "set_admin called"})
+	if len(findings) != 3 {
+		t.Fatalf("expected 3 findings, got %d", len(findings))
+	}
+	for _, f := range findings {
+		if f.Score == nil {
+			t.Fatalf("finding %q has no score", f.Title)
+		}
+	}
+
+	SortByScore(findings)
+	for i := 1; i < len(findings); i++ {
+		if findings[i-1].Score.Value < findings[i].Score.Value {
+			t.Errorf("findings out of order: %v before %v", findings[i-1].Score.Value, findings[i].Score.Value)
+		}
+	}
+	if findings[0].Title != "Integer Overflow/Underflow Detected" {
+		t.Errorf("expected the overflow first, got %q", findings[0].Title)
+	}
+
+	// Weighting likelihood alone ranks the panic level with the overflow,
+	// ahead of the heuristic bypass warning
+	Weights{Likelihood: 1}.Rescore(findings)
+	SortByScore(findings)
+	if last := findings[len(findings)-1]; last.Title != "Potential Authorization Bypass" {
+		t.Errorf("expected the bypass warning last, got %q", last.Title)
+	}
+
+	unscored := []Finding{{Severity: SeverityHigh, Title: "external"}, findings[0]}
+	SortByScore(unscored)
+	if unscored[1].Score != nil {
+		t.Error("expected the unscored finding last")
+	}
+}
+
+func TestAssetRatingScalesWithExposure(t *testing.T) {
+	for _, tc := range []struct {
+		stroops int64
+		want    Rating
+	}{
+		{500 * 10000000, RatingLow},
+		{5000 * 10000000, RatingMedium},
+		{20000000 * 10000000, RatingHigh},
+	} {
+		env := createMockEnvelopeWithLargePayment(t)
+		env.V1.Tx.Operations[0].Body.PaymentOp.Amount = xdr.Int64(tc.stroops)
+
+		findings := NewDetector().Analyze(encodeEnvelope(t, env), "", nil, []string{"checked_add failed: overflow"})
+		var overflow *Finding
+		for i := range findings {
+			if findings[i].Title == "Integer Overflow/Underflow Detected" {
+				overflow = &findings[i]
+			}
+		}
+		if overflow == nil {
+			t.Fatalf("%d stroops: no overflow finding in %+v", tc.stroops, findings)
+		}
+		if overflow.Score.Asset != tc.want {
+			t.Errorf("%d stroops: expected asset rating %s, got %s", tc.stroops, tc.want, overflow.Score.Asset)
+		}
+	}
+
+	if got := NewDetector().Analyze("", "", nil, []string{"checked_add failed: overflow"}); got[0].Score.Asset != RatingLow {
+		t.Errorf("expected a low asset rating when nothing moves, got %s", got[0].Score.Asset)
+	}
+}
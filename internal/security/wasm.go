@@ -0,0 +1,524 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/wasm"
+)
+
+// Thresholds of the static WASM checks
+const (
+	// largeDataSectionBytes flags data sections above 32 KiB; Soroban
+	// charges for every byte of uploaded code
+	largeDataSectionBytes = 32 * 1024
+	// deepLoopNesting flags loops nested this deep, whose cost grows with
+	// the product of their trip counts
+	deepLoopNesting = 3
+)
+
+// AnalyzeWASM inspects the contract code itself: floating-point
+// instructions, loops without an exit condition, panic messages carrying
+// build paths and oversized data sections. name identifies the module in
+// the evidence, e.g. a file name or WASM hash. The returned findings are
+// separate from those of Analyze.
+func (d *Detector) AnalyzeWASM(name string, code []byte) ([]Finding, error) {
+	m, err := parseWASM(code)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	saved := d.findings
+	d.findings = make([]Finding, 0)
+	defer func() { d.findings = saved }()
+
+	d.checkFloatingPoint(name, m)
+	d.checkLoops(name, m)
+	d.checkPanicStrings(name, m)
+	d.checkDataSize(name, m, len(code))
+
+	findings := d.findings
+	SortFindings(findings)
+	return findings, nil
+}
+
+// checkFloatingPoint flags float types and instructions, which the Soroban
+// host rejects when the code is uploaded
+func (d *Detector) checkFloatingPoint(name string, m *wasmModule) {
+	var funcs []string
+	total := 0
+	for _, fn := range m.funcs {
+		if fn.floatOps > 0 || fn.floatLocals {
+			total += fn.floatOps
+			funcs = append(funcs, m.funcName(fn.index))
+		}
+	}
+	if len(funcs) == 0 && !m.floatSignatures {
+		return
+	}
+
+	evidence := fmt.Sprintf("%s: %d floating-point instruction(s)", name, total)
+	if len(funcs) > 0 {
+		evidence += fmt.Sprintf(" in %d function(s): %s", len(funcs), summarizeNames(funcs))
+	} else {
+		evidence += "; float types in function signatures"
+	}
+	d.addFinding(Finding{
+		Type:        FindingVerifiedRisk,
+		Severity:    SeverityHigh,
+		Title:       "Floating-Point Code in WASM",
+		Description: "The module uses f32/f64 types or instructions. The Soroban host rejects floating point, so uploading this code fails; check for dependencies that format or compute with floats.",
+		Evidence:    evidence,
+	}, Vector{Impact: RatingHigh, Likelihood: RatingHigh, Asset: RatingLow})
+}
+
+// checkLoops flags loops with no branch that could leave them, which run
+// until the budget is exhausted, and deeply nested loops
+func (d *Detector) checkLoops(name string, m *wasmModule) {
+	var unbounded, nested []string
+	for _, fn := range m.funcs {
+		if fn.loopsWithoutExit > 0 {
+			unbounded = append(unbounded, m.funcName(fn.index))
+		}
+		if fn.maxLoopDepth >= deepLoopNesting {
+			nested = append(nested, fmt.Sprintf("%s (depth %d)", m.funcName(fn.index), fn.maxLoopDepth))
+		}
+	}
+	if len(unbounded) > 0 {
+		d.addFinding(Finding{
+			Type:        FindingHeuristicWarn,
+			Severity:    SeverityMedium,
+			Title:       "Loop Without Exit Condition",
+			Description: "A loop branches back to its start and contains no conditional branch, return or branch out of it, so it only ends by trapping. Invocations reaching it exhaust the CPU budget.",
+			Evidence:    fmt.Sprintf("%s: %s", name, summarizeNames(unbounded)),
+		}, Vector{Impact: RatingMedium, Likelihood: RatingMedium, Asset: RatingLow})
+	}
+	if len(nested) > 0 {
+		d.addFinding(Finding{
+			Type:        FindingHeuristicWarn,
+			Severity:    SeverityLow,
+			Title:       "Deeply Nested Loops",
+			Description: fmt.Sprintf("Loops nested %d or more deep multiply their iteration counts; with input-dependent bounds they are a common cause of budget exhaustion.", deepLoopNesting),
+			Evidence:    fmt.Sprintf("%s: %s", name, summarizeNames(nested)),
+		}, Vector{Impact: RatingLow, Likelihood: RatingLow, Asset: RatingLow})
+	}
+}
+
+// Markers of panic messages and of the build environment they leak
+var (
+	panicMarkers = []string{"panicked at", "called `Result::unwrap()`", "called `Option::unwrap()`", "index out of bounds", "attempt to "}
+	pathMarkers  = []string{"/home/", "/Users/", "C:\\Users\\", "\\Users\\", "/.cargo/registry/", "/rustc/"}
+)
+
+// checkPanicStrings flags panic messages compiled into the data section,
+// particularly those carrying absolute build paths
+func (d *Detector) checkPanicStrings(name string, m *wasmModule) {
+	var paths, panics []string
+	for _, s := range printableStrings(m.data, 8) {
+		for _, marker := range pathMarkers {
+			if strings.Contains(s, marker) {
+				paths = append(paths, s)
+				break
+			}
+		}
+		for _, marker := range panicMarkers {
+			if strings.Contains(s, marker) {
+				panics = append(panics, s)
+				break
+			}
+		}
+	}
+
+	if len(paths) > 0 {
+		d.addFinding(Finding{
+			Type:        FindingHeuristicWarn,
+			Severity:    SeverityLow,
+			Title:       "Build Paths in WASM Data",
+			Description: "Panic locations compiled into the module include absolute paths from the build machine, which can leak user names and directory layout. Build with --remap-path-prefix or a release profile that strips panic locations.",
+			Evidence:    fmt.Sprintf("%s: %d string(s), e.g. %q", name, len(paths), truncate(paths[0], 120)),
+		}, Vector{Impact: RatingLow, Likelihood: RatingHigh, Asset: RatingNone})
+	}
+	if len(panics) > 0 {
+		d.addFinding(Finding{
+			Type:        FindingHeuristicWarn,
+			Severity:    SeverityInfo,
+			Title:       "Panic Messages in WASM Data",
+			Description: "Formatted panic messages are compiled in. They can expose internal state in diagnostics and add to the code size; panic=abort with panic_immediate_abort or contract errors avoid them.",
+			Evidence:    fmt.Sprintf("%s: %d message(s), e.g. %q", name, len(panics), truncate(panics[0], 120)),
+		}, Vector{Impact: RatingLow, Likelihood: RatingMedium, Asset: RatingNone})
+	}
+}
+
+// checkDataSize flags data sections large enough to dominate upload and
+// rent costs
+func (d *Detector) checkDataSize(name string, m *wasmModule, moduleSize int) {
+	if m.dataBytes < largeDataSectionBytes {
+		return
+	}
+	d.addFinding(Finding{
+		Type:        FindingHeuristicWarn,
+		Severity:    SeverityLow,
+		Title:       "Large WASM Data Section",
+		Description: fmt.Sprintf("The data section exceeds %d KiB. Embedded tables, strings or test fixtures raise upload and rent fees and may hide unexpected content.", largeDataSectionBytes/1024),
+		Evidence:    fmt.Sprintf("%s: %d bytes in %d segment(s), %d%% of the module", name, m.dataBytes, m.dataSegments, m.dataBytes*100/moduleSize),
+	}, Vector{Impact: RatingLow, Likelihood: RatingMedium, Asset: RatingNone})
+}
+
+// summarizeNames lists up to three names and how many more there are
+func summarizeNames(names []string) string {
+	if len(names) <= 3 {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:3], ", "), len(names)-3)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+// printableStrings returns the runs of printable ASCII at least min long
+func printableStrings(data [][]byte, min int) []string {
+	var out []string
+	for _, seg := range data {
+		start := -1
+		for i := 0; i <= len(seg); i++ {
+			if i < len(seg) && seg[i] >= 0x20 && seg[i] < 0x7f {
+				if start < 0 {
+					start = i
+				}
+				continue
+			}
+			if start >= 0 && i-start >= min {
+				out = append(out, string(seg[start:i]))
+			}
+			start = -1
+		}
+	}
+	return out
+}
+
+// wasmFunc is what the checks need from one function body
+type wasmFunc struct {
+	index            uint32
+	floatOps         int
+	floatLocals      bool
+	loopsWithoutExit int
+	maxLoopDepth     int
+}
+
+// wasmModule is the parsed subset of a module the checks use
+type wasmModule struct {
+	importedFuncs   uint32
+	floatSignatures bool
+	names           map[uint32]string
+	funcs           []wasmFunc
+	data            [][]byte
+	dataBytes       int
+	dataSegments    int
+}
+
+// funcName returns the export or debug name of function idx, else its index
+func (m *wasmModule) funcName(idx uint32) string {
+	if name, ok := m.names[idx]; ok {
+		return name
+	}
+	return fmt.Sprintf("func[%d]", idx)
+}
+
+const (
+	valF32 = 0x7d
+	valF64 = 0x7c
+)
+
+func parseWASM(code []byte) (*wasmModule, error) {
+	sections, err := wasm.Sections(code)
+	if err != nil {
+		return nil, err
+	}
+	m := &wasmModule{names: make(map[uint32]string)}
+	exportNames := make(map[uint32]string)
+	for _, sec := range sections {
+		s := wasm.NewReader(sec.Data)
+		switch sec.ID {
+		case wasm.SectionCustom:
+			// A malformed name section only loses names
+			if sec.Name == "name" {
+				m.parseNames(s)
+			}
+			continue
+		case wasm.SectionType:
+			m.parseTypes(s)
+		case wasm.SectionImport:
+			m.parseImports(s)
+		case wasm.SectionExport:
+			for n := s.U32(); n > 0 && s.Err() == nil; n-- {
+				name := s.Name()
+				kind := s.Byte()
+				idx := s.U32()
+				if kind == 0 {
+					exportNames[idx] = name
+				}
+			}
+		case wasm.SectionCode:
+			m.parseCode(s)
+		case wasm.SectionData:
+			m.parseData(s)
+		}
+		if s.Err() != nil {
+			return nil, fmt.Errorf("malformed WASM section %d: %w", sec.ID, s.Err())
+		}
+	}
+	// Export names are what callers see, so they win over debug names
+	for idx, name := range exportNames {
+		m.names[idx] = name
+	}
+	return m, nil
+}
+
+func (m *wasmModule) parseTypes(r *wasm.Reader) {
+	for n := r.U32(); n > 0 && r.Err() == nil; n-- {
+		if r.Byte() != 0x60 {
+			r.Fail("unexpected function type form")
+			return
+		}
+		for vecs := 0; vecs < 2; vecs++ {
+			for k := r.U32(); k > 0 && r.Err() == nil; k-- {
+				if t := r.Byte(); t == valF32 || t == valF64 {
+					m.floatSignatures = true
+				}
+			}
+		}
+	}
+}
+
+func (m *wasmModule) parseImports(r *wasm.Reader) {
+	for n := r.U32(); n > 0 && r.Err() == nil; n-- {
+		r.Name()
+		r.Name()
+		switch r.Byte() {
+		case 0:
+			r.U32()
+			m.importedFuncs++
+		case 1:
+			r.Byte()
+			r.SkipLimits()
+		case 2:
+			r.SkipLimits()
+		case 3:
+			r.Byte()
+			r.Byte()
+		default:
+			r.Fail("unknown import kind")
+		}
+	}
+}
+
+// parseNames reads function names from the name custom section
+func (m *wasmModule) parseNames(r *wasm.Reader) {
+	for r.More() {
+		id := r.Byte()
+		sub := wasm.NewReader(r.Bytes(int(r.U32())))
+		if id != 1 {
+			continue
+		}
+		for n := sub.U32(); n > 0 && sub.Err() == nil; n-- {
+			idx := sub.U32()
+			m.names[idx] = sub.Name()
+		}
+	}
+}
+
+func (m *wasmModule) parseCode(r *wasm.Reader) {
+	count := r.U32()
+	for i := uint32(0); i < count && r.Err() == nil; i++ {
+		body := wasm.NewReader(r.Bytes(int(r.U32())))
+		fn := wasmFunc{index: m.importedFuncs + i}
+		for n := body.U32(); n > 0 && body.Err() == nil; n-- {
+			body.U32()
+			if t := body.Byte(); t == valF32 || t == valF64 {
+				fn.floatLocals = true
+			}
+		}
+		analyzeBody(body, &fn)
+		if body.Err() != nil {
+			r.Fail(fmt.Sprintf("function %d: %v", fn.index, body.Err()))
+			return
+		}
+		m.funcs = append(m.funcs, fn)
+	}
+}
+
+func (m *wasmModule) parseData(r *wasm.Reader) {
+	for n := r.U32(); n > 0 && r.Err() == nil; n-- {
+		switch r.U32() {
+		case 0:
+			walkExpr(r, nil)
+		case 1:
+		case 2:
+			r.U32()
+			walkExpr(r, nil)
+		default:
+			r.Fail("unknown data segment kind")
+			return
+		}
+		seg := r.Bytes(int(r.U32()))
+		m.data = append(m.data, seg)
+		m.dataBytes += len(seg)
+		m.dataSegments++
+	}
+}
+
+// controlFrame is an open block, loop or if while walking a body
+type controlFrame struct {
+	loop bool
+	// repeats is set when the loop branches back to its start; a loop
+	// without such a branch runs once and falls through
+	repeats bool
+	// exits is set when the loop contains an instruction that can leave it
+	exits bool
+}
+
+// analyzeBody walks one function body, counting float instructions and
+// the loops that branch back to themselves with no way out
+func analyzeBody(r *wasm.Reader, fn *wasmFunc) {
+	stack := []controlFrame{{}}
+	loopDepth := 0
+	markExits := func() {
+		for i := range stack {
+			stack[i].exits = true
+		}
+	}
+	walkExpr(r, func(op byte, sub uint32, label uint32) {
+		if isFloatOp(op, sub) {
+			fn.floatOps++
+		}
+		switch op {
+		case 0x02, 0x04: // block, if
+			if op == 0x04 {
+				markExits()
+			}
+			stack = append(stack, controlFrame{})
+		case 0x03: // loop
+			stack = append(stack, controlFrame{loop: true})
+			loopDepth++
+			if loopDepth > fn.maxLoopDepth {
+				fn.maxLoopDepth = loopDepth
+			}
+		case 0x0B: // end
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if top.loop {
+				loopDepth--
+				if top.repeats && !top.exits {
+					fn.loopsWithoutExit++
+				}
+			}
+		case 0x0D, 0x0E, 0x0F: // br_if, br_table, return
+			markExits()
+		case 0x0C: // br leaves every loop nested inside its target
+			target := len(stack) - 1 - int(label)
+			for i := len(stack) - 1; i > target && i >= 0; i-- {
+				stack[i].exits = true
+			}
+			if target >= 0 && stack[target].loop {
+				stack[target].repeats = true
+			}
+		}
+	})
+}
+
+// isFloatOp reports whether an instruction operates on f32 or f64 values
+func isFloatOp(op byte, sub uint32) bool {
+	switch {
+	case op == 0x2A || op == 0x2B || op == 0x38 || op == 0x39 || op == 0x43 || op == 0x44:
+		return true
+	case op >= 0x5B && op <= 0x66:
+		return true
+	case op >= 0x8B && op <= 0xA6:
+		return true
+	case op >= 0xA8 && op <= 0xBF:
+		return op != 0xAC && op != 0xAD
+	case op == 0xFC:
+		return sub <= 7
+	}
+	return false
+}
+
+// walkExpr reads instructions up to and including the end closing the
+// expression, calling visit for each with its 0xFC sub-opcode and, for br,
+// its label. Only the instruction sets Soroban accepts are decoded.
+func walkExpr(r *wasm.Reader, visit func(op byte, sub uint32, label uint32)) {
+	depth := 0
+	for r.Err() == nil {
+		op := r.Byte()
+		var sub, label uint32
+		switch {
+		case op == 0x02 || op == 0x03 || op == 0x04:
+			r.SkipLEB()
+			depth++
+		case op == 0x0B:
+			if depth == 0 {
+				if visit != nil {
+					visit(op, 0, 0)
+				}
+				return
+			}
+			depth--
+		case op == 0x0C || op == 0x0D:
+			label = r.U32()
+		case op == 0x0E:
+			for n := r.U32(); n > 0 && r.Err() == nil; n-- {
+				r.U32()
+			}
+			r.U32()
+		case op == 0x10 || op == 0xD2 || (op >= 0x20 && op <= 0x26):
+			r.U32()
+		case op == 0x11:
+			r.U32()
+			r.U32()
+		case op == 0x1C:
+			for n := r.U32(); n > 0 && r.Err() == nil; n-- {
+				r.Byte()
+			}
+		case op >= 0x28 && op <= 0x3E:
+			r.U32()
+			r.U32()
+		case op == 0x3F || op == 0x40 || op == 0xD0:
+			r.Byte()
+		case op == 0x41 || op == 0x42:
+			r.SkipLEB()
+		case op == 0x43:
+			r.Bytes(4)
+		case op == 0x44:
+			r.Bytes(8)
+		case op == 0xFC:
+			sub = r.U32()
+			switch sub {
+			case 8:
+				r.U32()
+				r.Byte()
+			case 10:
+				r.Byte()
+				r.Byte()
+			case 11:
+				r.Byte()
+			case 9, 13, 15, 16, 17:
+				r.U32()
+			case 12, 14:
+				r.U32()
+				r.U32()
+			}
+		case op == 0xFD:
+			r.Fail("SIMD instructions are not supported")
+		}
+		if visit != nil && r.Err() == nil {
+			visit(op, sub, label)
+		}
+	}
+}
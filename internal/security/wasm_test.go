@@ -0,0 +1,160 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func uleb(n int) []byte {
+	return binary.AppendUvarint(nil, uint64(n))
+}
+
+func wasmSection(id byte, body []byte) []byte {
+	return append(append([]byte{id}, uleb(len(body))...), body...)
+}
+
+// buildWASM assembles a module whose functions all have type () -> (),
+// exporting each under its name, with the given instructions (without the
+// final end) and active data segments
+func buildWASM(funcs map[string][]byte, data ...[]byte) []byte {
+	var names []string
+	for name := range funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+	out = append(out, wasmSection(1, []byte{0x01, 0x60, 0x00, 0x00})...)
+
+	fnSec := uleb(len(names))
+	exports := uleb(len(names))
+	code := uleb(len(names))
+	for i, name := range names {
+		fnSec = append(fnSec, 0x00)
+		exports = append(append(append(exports, uleb(len(name))...), name...), 0x00, byte(i))
+		body := append(append([]byte{0x00}, funcs[name]...), 0x0B)
+		code = append(append(code, uleb(len(body))...), body...)
+	}
+	out = append(out, wasmSection(3, fnSec)...)
+	out = append(out, wasmSection(7, exports)...)
+	out = append(out, wasmSection(10, code)...)
+
+	if len(data) > 0 {
+		sec := uleb(len(data))
+		for _, seg := range data {
+			sec = append(append(append(sec, 0x00, 0x41, 0x00, 0x0B), uleb(len(seg))...), seg...)
+		}
+		out = append(out, wasmSection(11, sec)...)
+	}
+	return out
+}
+
+func findingTitles(findings []Finding) []string {
+	var titles []string
+	for _, f := range findings {
+		titles = append(titles, f.Title)
+	}
+	return titles
+}
+
+func TestAnalyzeWASM_Clean(t *testing.T) {
+	code := buildWASM(map[string][]byte{
+		// i64.const 1; drop; loop { i32.const 0; br_if 0 }
+		"transfer": {0x42, 0x01, 0x1A, 0x03, 0x40, 0x41, 0x00, 0x0D, 0x00, 0x0B},
+	}, []byte("short"))
+
+	findings, err := NewDetector().AnalyzeWASM("token.wasm", code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %v", findingTitles(findings))
+	}
+}
+
+func TestAnalyzeWASM_Findings(t *testing.T) {
+	loop := func(inner ...byte) []byte {
+		return append(append([]byte{0x03, 0x40}, inner...), 0x0B)
+	}
+	exitIf := []byte{0x41, 0x00, 0x0D, 0x00}
+
+	large := bytes.Repeat([]byte{0}, largeDataSectionBytes)
+	code := buildWASM(map[string][]byte{
+		// f64.const 1.5; drop
+		"price": {0x44, 0, 0, 0, 0, 0, 0, 0xF8, 0x3F, 0x1A},
+		// loop { br 0 }
+		"spin": loop(0x0C, 0x00),
+		// loop { i32.const 0; drop }: no branch back, so it runs once
+		"once": loop(0x41, 0x00, 0x1A),
+		// block { loop { br 1 } }: the br leaves the loop
+		"escape": append(append([]byte{0x02, 0x40}, loop(0x0C, 0x01)...), 0x0B),
+		"nested": loop(append(exitIf, loop(append(exitIf, loop(exitIf...)...)...)...)...),
+	}, []byte("panicked at /home/alice/.cargo/registry/src/soroban-sdk/src/env.rs:12"), large)
+
+	findings, err := NewDetector().AnalyzeWASM("vault.wasm", code)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byTitle := make(map[string]Finding)
+	for _, f := range findings {
+		byTitle[f.Title] = f
+		if f.ID == "" || f.Score == nil {
+			t.Errorf("%s: missing ID or score", f.Title)
+		}
+	}
+	want := map[string]string{
+		"Floating-Point Code in WASM": "vault.wasm: 1 floating-point instruction(s) in 1 function(s): price",
+		"Loop Without Exit Condition": "vault.wasm: spin",
+		"Deeply Nested Loops":         "nested (depth 3)",
+		"Build Paths in WASM Data":    "/home/alice",
+		"Panic Messages in WASM Data": "panicked at",
+		"Large WASM Data Section":     "in 2 segment(s)",
+	}
+	for title, evidence := range want {
+		f, ok := byTitle[title]
+		if !ok {
+			t.Errorf("missing finding %q; got %v", title, findingTitles(findings))
+			continue
+		}
+		if !strings.Contains(f.Evidence, evidence) {
+			t.Errorf("%s: evidence %q does not contain %q", title, f.Evidence, evidence)
+		}
+	}
+	if strings.Contains(byTitle["Loop Without Exit Condition"].Evidence, "once") {
+		t.Error("a loop that never branches back is not endless")
+	}
+	if len(findings) != len(want) {
+		t.Errorf("expected %d findings, got %v", len(want), findingTitles(findings))
+	}
+	if findings[0].Title != "Floating-Point Code in WASM" {
+		t.Errorf("expected the float finding first, got %q", findings[0].Title)
+	}
+}
+
+func TestAnalyzeWASM_Invalid(t *testing.T) {
+	if _, err := NewDetector().AnalyzeWASM("x", []byte("not wasm")); err == nil || !strings.Contains(err.Error(), "not a WASM module") {
+		t.Errorf("expected a format error, got %v", err)
+	}
+	truncated := buildWASM(map[string][]byte{"f": {0x41, 0x00, 0x1A}})
+	if _, err := NewDetector().AnalyzeWASM("x", truncated[:len(truncated)-3]); err == nil {
+		t.Error("expected an error for a truncated module")
+	}
+}
+
+func TestAnalyzeWASM_KeepsTransactionFindings(t *testing.T) {
+	d := NewDetector()
+	d.Analyze("", "", []string{"contract panic"}, nil)
+	if _, err := d.AnalyzeWASM("x", buildWASM(map[string][]byte{"f": {0x03, 0x40, 0x0C, 0x00, 0x0B}})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := d.GetFindings(); len(got) != 1 || got[0].Title != "Contract Panic/Trap" {
+		t.Errorf("AnalyzeWASM changed the transaction findings: %v", findingTitles(got))
+	}
+}
@@ -0,0 +1,456 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/storage"
+)
+
+// API key scopes
+const (
+	// ScopeDebug starts debug runs: POST /api/v1/debug and /api/v1/jobs, and
+	// the Debug, DebugStream and Simulate RPCs
+	ScopeDebug = "debug"
+	// ScopeJobs reads job status and events
+	ScopeJobs = "jobs"
+	// ScopeSessions reads stored sessions
+	ScopeSessions = "sessions"
+	// ScopeKeys manages the keys of the key's own tenant and reads their usage
+	ScopeKeys = "keys"
+)
+
+// Scopes lists every API key scope
+var Scopes = []string{ScopeDebug, ScopeJobs, ScopeSessions, ScopeKeys}
+
+// DefaultScopes are granted to keys created without explicit scopes
+var DefaultScopes = []string{ScopeDebug, ScopeJobs, ScopeSessions}
+
+// APIKeyPrefix starts every API key secret, telling keys apart from
+// --token values
+const APIKeyPrefix = "erst_"
+
+// apiKeyCacheTTL bounds how long a replica keeps using a key after another
+// replica revoked it
+const apiKeyCacheTTL = 30 * time.Second
+
+const apiKeyContextKey contextKey = "erst-api-key"
+
+// CreateKeyRequest is the body of POST /api/v1/keys
+type CreateKeyRequest struct {
+	// Tenant defaults to the caller's own tenant for keys with the keys scope
+	Tenant string `json:"tenant,omitempty"`
+	Name   string `json:"name"`
+	// Role defaults to analyst and may not exceed the caller's
+	Role   Role     `json:"role,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// CreatedKey is the body returned by POST /api/v1/keys. The secret is only
+// ever shown here.
+type CreatedKey struct {
+	Key    string         `json:"key"`
+	APIKey storage.APIKey `json:"api_key"`
+}
+
+// KeyUsage is the body of GET /api/v1/keys/{id}/usage
+type KeyUsage struct {
+	APIKey storage.APIKey       `json:"api_key"`
+	Since  string               `json:"since"`
+	Total  storage.Usage        `json:"total"`
+	Days   []storage.DailyUsage `json:"days"`
+}
+
+// CreateAPIKey validates and stores a new key, returning it with its secret
+func CreateAPIKey(ctx context.Context, db *storage.DB, tenant, name string, role Role, scopes []string) (*storage.APIKey, string, error) {
+	tenant = strings.TrimSpace(tenant)
+	if tenant == "" {
+		return nil, "", fmt.Errorf("tenant is required")
+	}
+	if role == "" {
+		role = RoleAnalyst
+	}
+	if _, err := ParseRole(string(role)); err != nil {
+		return nil, "", err
+	}
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(Scopes, scope) {
+			return nil, "", fmt.Errorf("unknown scope: %s (valid: %s)", scope, strings.Join(Scopes, ", "))
+		}
+	}
+
+	id, secret := generateAPIKey()
+	key := &storage.APIKey{ID: id, Tenant: tenant, Name: name, Role: string(role), Scopes: scopes}
+	if err := db.CreateAPIKey(ctx, key, hashAPIKey(secret)); err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// generateAPIKey returns a key ID and a secret embedding it
+func generateAPIKey() (id, secret string) {
+	b := make([]byte, 24)
+	_, _ = rand.Read(b)
+	id = hex.EncodeToString(b[:6])
+	return id, APIKeyPrefix + id + "_" + hex.EncodeToString(b[6:])
+}
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// roleRank orders roles by how much output they see
+func roleRank(role Role) int {
+	switch role {
+	case RoleAdmin:
+		return 3
+	case RoleDeveloper:
+		return 2
+	case RoleAnalyst:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type cachedKey struct {
+	key     *storage.APIKey
+	expires time.Time
+}
+
+// keyCache remembers recent key lookups so requests do not each hit the
+// database
+type keyCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedKey
+}
+
+func (c *keyCache) get(hash string) (*storage.APIKey, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[hash]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.key, true
+}
+
+func (c *keyCache) put(hash string, key *storage.APIKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for h, e := range c.entries {
+		if now.After(e.expires) {
+			delete(c.entries, h)
+		}
+	}
+	c.entries[hash] = cachedKey{key: key, expires: now.Add(apiKeyCacheTTL)}
+}
+
+func (c *keyCache) forget(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for h, e := range c.entries {
+		if e.key != nil && e.key.ID == id {
+			delete(c.entries, h)
+		}
+	}
+}
+
+// lookupAPIKey resolves an API key secret to its key, if it exists and is
+// not revoked
+func (s *Server) lookupAPIKey(ctx context.Context, secret string) (*storage.APIKey, bool) {
+	hash := hashAPIKey(secret)
+	key, ok := s.keys.get(hash)
+	if !ok {
+		var err error
+		key, err = s.storage.APIKeyByHash(ctx, hash)
+		if err != nil {
+			if !errors.Is(err, storage.ErrNotFound) {
+				logger.Logger.Warn("Failed to look up API key", "error", err)
+			}
+			return nil, false
+		}
+		s.keys.put(hash, key)
+	}
+	if key.Revoked() {
+		return nil, false
+	}
+	return key, true
+}
+
+// authenticate resolves a bearer token, either an API key or a --token
+// value, to the caller's role
+func (s *Server) authenticate(ctx context.Context, token string) (Role, *storage.APIKey, bool) {
+	if s.apiKeys && strings.HasPrefix(token, APIKeyPrefix) {
+		key, ok := s.lookupAPIKey(ctx, token)
+		if !ok {
+			return "", nil, false
+		}
+		return Role(key.Role), key, true
+	}
+	if s.apiKeys && !s.auth.Enabled() {
+		return "", nil, false
+	}
+	role, ok := s.auth.AuthenticateToken(token)
+	return role, nil, ok
+}
+
+// authEnabled reports whether callers must present a token or API key
+func (s *Server) authEnabled() bool {
+	return s.auth.Enabled() || s.apiKeys
+}
+
+func apiKeyFromContext(ctx context.Context) *storage.APIKey {
+	key, _ := ctx.Value(apiKeyContextKey).(*storage.APIKey)
+	return key
+}
+
+// callerUser names API callers in the audit log: the API key, or the role
+// of a --token caller
+func callerUser(ctx context.Context) string {
+	if key := apiKeyFromContext(ctx); key != nil {
+		return "key:" + key.Tenant + "/" + key.ID
+	}
+	return roleUser(roleFromContext(ctx))
+}
+
+// callerTenant returns the tenant of an API key caller. Callers without a
+// key, such as --token callers, are not scoped to a tenant.
+func callerTenant(ctx context.Context) (string, bool) {
+	if key := apiKeyFromContext(ctx); key != nil {
+		return key.Tenant, true
+	}
+	return "", false
+}
+
+// sessionsFor returns the stored sessions the caller may see: those of its
+// own tenant for API key callers, or every session
+func (s *Server) sessionsFor(ctx context.Context) *storage.Sessions {
+	sessions := s.storage.Sessions()
+	if tenant, ok := callerTenant(ctx); ok {
+		return sessions.ForTenant(tenant)
+	}
+	return sessions
+}
+
+// jobFor looks up a job the caller may see. Jobs started with an API key
+// belong to its tenant and are hidden from other tenants' keys.
+func (s *Server) jobFor(ctx context.Context, id string) (*Job, bool) {
+	job, ok := s.jobs.Get(id)
+	if !ok {
+		return nil, false
+	}
+	if tenant, scoped := callerTenant(ctx); scoped && tenant != job.tenant {
+		return nil, false
+	}
+	return job, true
+}
+
+// hasScope reports whether the caller may use scope. Only API keys are
+// scoped; --token callers may do everything their role allows.
+func hasScope(ctx context.Context, scope string) bool {
+	key := apiKeyFromContext(ctx)
+	return key == nil || scope == "" || key.HasScope(scope)
+}
+
+// requireScope rejects API keys without scope
+func requireScope(scope string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !hasScope(r.Context(), scope) {
+			writeError(w, http.StatusForbidden, "API key lacks the "+scope+" scope")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// countUsage adds u to the usage of the caller's API key, if any. It
+// outlives a cancelled request so finished work is always billed.
+func (s *Server) countUsage(ctx context.Context, u storage.Usage) {
+	key := apiKeyFromContext(ctx)
+	if key == nil || s.storage == nil {
+		return
+	}
+	if err := s.storage.AddUsage(context.WithoutCancel(ctx), key.ID, u, time.Now()); err != nil {
+		logger.Logger.Warn("Failed to record API key usage", "key", key.ID, "error", err)
+	}
+}
+
+// keyTenant returns the tenant whose keys the caller manages: "" for all
+// tenants (admin --token callers), or the tenant of a keys-scoped API key
+func keyTenant(ctx context.Context) (string, bool) {
+	if key := apiKeyFromContext(ctx); key != nil {
+		return key.Tenant, true
+	}
+	return "", roleFromContext(ctx) == RoleAdmin
+}
+
+// requireKeyStorage answers requests to the key endpoints of a server
+// without API keys
+func (s *Server) requireKeyStorage(w http.ResponseWriter) bool {
+	if !s.apiKeys {
+		writeError(w, http.StatusNotFound, "API keys are only available when the server runs with --storage and --api-keys")
+		return false
+	}
+	return true
+}
+
+// managedKey loads the key in the path if the caller may manage it
+func (s *Server) managedKey(w http.ResponseWriter, r *http.Request) (*storage.APIKey, bool) {
+	if !s.requireKeyStorage(w) {
+		return nil, false
+	}
+	tenant, ok := keyTenant(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "managing API keys needs an admin token or an API key with the keys scope")
+		return nil, false
+	}
+	key, err := s.storage.APIKey(r.Context(), r.PathValue("id"))
+	if errors.Is(err, storage.ErrNotFound) || (err == nil && tenant != "" && key.Tenant != tenant) {
+		writeError(w, http.StatusNotFound, "API key not found")
+		return nil, false
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return key, true
+}
+
+// handleCreateKey creates an API key. Keys with the keys scope create keys
+// for their own tenant, with at most their own role and scopes.
+func (s *Server) handleCreateKey(w http.ResponseWriter, r *http.Request) {
+	if !s.requireKeyStorage(w) {
+		return
+	}
+	tenant, ok := keyTenant(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "managing API keys needs an admin token or an API key with the keys scope")
+		return
+	}
+	var req CreateKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if tenant != "" {
+		if req.Tenant != "" && req.Tenant != tenant {
+			writeError(w, http.StatusForbidden, "API keys can only create keys for their own tenant")
+			return
+		}
+		req.Tenant = tenant
+		if roleRank(req.Role) > roleRank(roleFromContext(r.Context())) {
+			writeError(w, http.StatusForbidden, "a key cannot grant a role above its own")
+			return
+		}
+		for _, scope := range req.Scopes {
+			if !hasScope(r.Context(), scope) {
+				writeError(w, http.StatusForbidden, "a key cannot grant the "+scope+" scope it lacks")
+				return
+			}
+		}
+		if len(req.Scopes) == 0 {
+			req.Scopes = apiKeyFromContext(r.Context()).Scopes
+		}
+	}
+
+	key, secret, err := CreateAPIKey(r.Context(), s.storage, req.Tenant, req.Name, req.Role, req.Scopes)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	logger.Logger.Info("Created API key", "id", key.ID, "tenant", key.Tenant, "by", callerUser(r.Context()))
+	writeJSON(w, http.StatusCreated, CreatedKey{Key: secret, APIKey: *key})
+}
+
+// handleListKeys lists the keys the caller manages, filtered by ?tenant=
+func (s *Server) handleListKeys(w http.ResponseWriter, r *http.Request) {
+	if !s.requireKeyStorage(w) {
+		return
+	}
+	tenant, ok := keyTenant(r.Context())
+	if !ok {
+		writeError(w, http.StatusForbidden, "managing API keys needs an admin token or an API key with the keys scope")
+		return
+	}
+	if tenant == "" {
+		tenant = r.URL.Query().Get("tenant")
+	}
+	keys, err := s.storage.APIKeys(r.Context(), tenant)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if keys == nil {
+		keys = []storage.APIKey{}
+	}
+	s.render(w, r, http.StatusOK, keys)
+}
+
+// handleRevokeKey revokes an API key. Other replicas stop accepting it
+// within apiKeyCacheTTL.
+func (s *Server) handleRevokeKey(w http.ResponseWriter, r *http.Request) {
+	key, ok := s.managedKey(w, r)
+	if !ok {
+		return
+	}
+	key, err := s.storage.RevokeAPIKey(r.Context(), key.ID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.keys.forget(key.ID)
+	logger.Logger.Info("Revoked API key", "id", key.ID, "tenant", key.Tenant, "by", callerUser(r.Context()))
+	s.render(w, r, http.StatusOK, key)
+}
+
+// handleKeyUsage reports the daily usage of a key since ?since=
+// (2006-01-02, default 30 days ago)
+func (s *Server) handleKeyUsage(w http.ResponseWriter, r *http.Request) {
+	key, ok := s.managedKey(w, r)
+	if !ok {
+		return
+	}
+	since := time.Now().UTC().AddDate(0, 0, -30)
+	if v := r.URL.Query().Get("since"); v != "" {
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "since must be a date such as 2025-01-31")
+			return
+		}
+		since = t
+	}
+	days, err := s.storage.UsageSince(r.Context(), key.ID, since)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	usage := KeyUsage{APIKey: *key, Since: since.Format(time.DateOnly), Days: days}
+	if usage.Days == nil {
+		usage.Days = []storage.DailyUsage{}
+	}
+	for _, d := range days {
+		usage.Total = usage.Total.Add(d.Usage)
+	}
+	s.render(w, r, http.StatusOK, usage)
+}
@@ -0,0 +1,239 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+	erstv1 "github.com/dotandev/hintents/proto/erst/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newKeyServer returns a server accepting API keys, with "root" as an admin
+// --token
+func newKeyServer(t *testing.T) *Server {
+	t.Helper()
+	t.Setenv("ERST_SIM_PATH", "/bin/echo")
+	db, err := storage.Open(context.Background(), storage.Config{DSN: filepath.Join(t.TempDir(), "erst.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	srv, err := NewServer(Config{Network: "testnet", Tokens: map[string]Role{"root": RoleAdmin}, Storage: db, APIKeys: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv
+}
+
+func call(srv *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func createKey(t *testing.T, srv *Server, token, body string) CreatedKey {
+	t.Helper()
+	rec := call(srv, "POST", "/api/v1/keys", token, body)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create key: %d %s", rec.Code, rec.Body)
+	}
+	var created CreatedKey
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func TestAPIKeysNeedStorage(t *testing.T) {
+	t.Setenv("ERST_SIM_PATH", "/bin/echo")
+	if _, err := NewServer(Config{Network: "testnet", APIKeys: true}); err == nil {
+		t.Error("expected API keys without storage to be refused")
+	}
+}
+
+func TestAPIKeyAuthAndScopes(t *testing.T) {
+	srv := newKeyServer(t)
+
+	if rec := call(srv, "GET", "/api/v1/sessions", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected anonymous callers to be rejected, got %d", rec.Code)
+	}
+
+	ci := createKey(t, srv, "root", `{"tenant": "payments", "name": "ci", "role": "developer", "scopes": ["debug"]}`)
+	if !strings.HasPrefix(ci.Key, APIKeyPrefix) || ci.APIKey.Tenant != "payments" || ci.APIKey.Role != "developer" {
+		t.Fatalf("unexpected key %+v", ci)
+	}
+	if rec := call(srv, "GET", "/api/v1/sessions", ci.Key, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without the sessions scope, got %d", rec.Code)
+	}
+	if rec := call(srv, "POST", "/api/v1/debug", ci.Key, `{"hash": "nothex"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected the debug scope to pass auth, got %d", rec.Code)
+	}
+
+	readers := createKey(t, srv, "root", `{"tenant": "risk", "scopes": ["sessions"]}`)
+	if rec := call(srv, "GET", "/api/v1/sessions", readers.Key, ""); rec.Code != http.StatusOK {
+		t.Errorf("expected the sessions scope to list sessions, got %d %s", rec.Code, rec.Body)
+	}
+	if readers.APIKey.Role != string(RoleAnalyst) {
+		t.Errorf("expected keys to default to analyst, got %s", readers.APIKey.Role)
+	}
+
+	rec := call(srv, "DELETE", "/api/v1/keys/"+ci.APIKey.ID, "root", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("revoke: %d %s", rec.Code, rec.Body)
+	}
+	if rec := call(srv, "POST", "/api/v1/debug", ci.Key, `{"hash": "nothex"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected a revoked key to be rejected, got %d", rec.Code)
+	}
+	if rec := call(srv, "GET", "/api/v1/sessions", "erst_not_a_key", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected an unknown key to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestAPIKeyTenantAdmin(t *testing.T) {
+	srv := newKeyServer(t)
+	admin := createKey(t, srv, "root", `{"tenant": "payments", "role": "developer", "scopes": ["debug", "keys"]}`)
+	other := createKey(t, srv, "root", `{"tenant": "risk"}`)
+
+	// Tenant admins create keys for their own tenant within their own rights
+	child := createKey(t, srv, admin.Key, `{"name": "worker"}`)
+	if child.APIKey.Tenant != "payments" || strings.Join(child.APIKey.Scopes, ",") != "debug,keys" {
+		t.Errorf("expected the child to inherit tenant and scopes, got %+v", child.APIKey)
+	}
+	for _, body := range []string{
+		`{"tenant": "risk"}`,
+		`{"role": "admin"}`,
+		`{"scopes": ["sessions"]}`,
+	} {
+		if rec := call(srv, "POST", "/api/v1/keys", admin.Key, body); rec.Code != http.StatusForbidden {
+			t.Errorf("%s: expected 403, got %d", body, rec.Code)
+		}
+	}
+
+	rec := call(srv, "GET", "/api/v1/keys", admin.Key, "")
+	var keys []storage.APIKey
+	if err := json.Unmarshal(rec.Body.Bytes(), &keys); err != nil || len(keys) != 2 {
+		t.Errorf("expected only the tenant's keys, got %s", rec.Body)
+	}
+	if rec := call(srv, "DELETE", "/api/v1/keys/"+other.APIKey.ID, admin.Key, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("expected other tenants' keys to be invisible, got %d", rec.Code)
+	}
+	if rec := call(srv, "GET", "/api/v1/keys", other.Key, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("expected 403 without the keys scope, got %d", rec.Code)
+	}
+
+	if rec := call(srv, "GET", "/api/v1/keys?tenant=risk", "root", ""); !strings.Contains(rec.Body.String(), other.APIKey.ID) || strings.Contains(rec.Body.String(), admin.APIKey.ID) {
+		t.Errorf("expected admins to filter by tenant, got %s", rec.Body)
+	}
+}
+
+func TestAPIKeyUsage(t *testing.T) {
+	srv := newKeyServer(t)
+	srv.runner = stubRunner{resp: &simulator.SimulationResponse{Status: "success"}}
+	key := createKey(t, srv, "root", `{"tenant": "payments"}`)
+
+	call(srv, "GET", "/api/v1/sessions", key.Key, "")
+	client := dialGRPC(t, srv)
+	if _, err := client.Simulate(withToken(key.Key), &erstv1.SimulateRequest{EnvelopeXdr: "AAAA"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GetSession(withToken(key.Key), &erstv1.GetSessionRequest{Id: "x"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	readOnly := createKey(t, srv, "root", `{"tenant": "payments", "scopes": ["sessions"]}`)
+	if _, err := client.Simulate(withToken(readOnly.Key), &erstv1.SimulateRequest{EnvelopeXdr: "AAAA"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied without the debug scope, got %v", err)
+	}
+
+	rec := call(srv, "GET", "/api/v1/keys/"+key.APIKey.ID+"/usage?since="+time.Now().UTC().Format(time.DateOnly), "root", "")
+	var usage KeyUsage
+	if err := json.Unmarshal(rec.Body.Bytes(), &usage); err != nil {
+		t.Fatalf("%v: %s", err, rec.Body)
+	}
+	if usage.Total != (storage.Usage{Requests: 3, Simulations: 1}) || len(usage.Days) != 1 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+	if usage.APIKey.LastUsedAt == nil {
+		t.Error("expected last_used_at to be set")
+	}
+	if rec := call(srv, "GET", "/api/v1/keys/"+key.APIKey.ID+"/usage?since=yesterday", "root", ""); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad date, got %d", rec.Code)
+	}
+}
+
+func TestAPIKeyTenantIsolation(t *testing.T) {
+	srv := newKeyServer(t)
+	payments := createKey(t, srv, "root", `{"tenant": "payments"}`)
+	risk := createKey(t, srv, "root", `{"tenant": "risk"}`)
+	stored, _ := srv.storage.APIKey(context.Background(), payments.APIKey.ID)
+	ctx := context.WithValue(context.Background(), apiKeyContextKey, stored)
+
+	srv.record(ctx, "serve debug", callerUser(ctx), "s1", "abc", &DebugResult{Hash: "abc", Network: "testnet"}, nil, time.Now())
+	job := newJob("abc")
+	job.tenant = "payments"
+	srv.jobs.Add(job)
+
+	for _, path := range []string{"/api/v1/sessions/s1", "/api/v1/jobs/" + job.ID, "/api/v1/jobs/" + job.ID + "/events"} {
+		if rec := call(srv, "GET", path, payments.Key, ""); rec.Code != http.StatusOK {
+			t.Errorf("%s: expected the owning tenant to read it, got %d", path, rec.Code)
+		}
+		if rec := call(srv, "GET", path, risk.Key, ""); rec.Code != http.StatusNotFound {
+			t.Errorf("%s: expected 404 for another tenant, got %d", path, rec.Code)
+		}
+		if rec := call(srv, "GET", path, "root", ""); rec.Code != http.StatusOK {
+			t.Errorf("%s: expected --token callers to read it, got %d", path, rec.Code)
+		}
+	}
+	if rec := call(srv, "GET", "/api/v1/sessions", risk.Key, ""); strings.Contains(rec.Body.String(), "s1") {
+		t.Errorf("expected another tenant's listing to hide the session, got %s", rec.Body)
+	}
+
+	client := dialGRPC(t, srv)
+	if _, err := client.GetSession(withToken(risk.Key), &erstv1.GetSessionRequest{Id: "s1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetSession: expected NotFound for another tenant, got %v", err)
+	}
+	if list, err := client.ListSessions(withToken(risk.Key), &erstv1.ListSessionsRequest{}); err != nil || len(list.GetSessions()) != 0 {
+		t.Errorf("ListSessions = %v, %v", list, err)
+	}
+	if list, err := client.ListSessions(withToken(payments.Key), &erstv1.ListSessionsRequest{}); err != nil || len(list.GetSessions()) != 1 {
+		t.Errorf("ListSessions for the owner = %v, %v", list, err)
+	}
+	stream, err := client.WatchJob(withToken(risk.Key), &erstv1.WatchJobRequest{Id: job.ID})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("WatchJob: expected NotFound for another tenant, got %v", err)
+	}
+}
+
+func TestKeyPoolTenant(t *testing.T) {
+	srv := newKeyServer(t)
+	key := createKey(t, srv, "root", `{"tenant": "payments"}`)
+	stored, _ := srv.storage.APIKey(context.Background(), key.APIKey.ID)
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(context.WithValue(req.Context(), apiKeyContextKey, stored))
+	if got := srv.tenantOf(req); got != "tenant:payments" {
+		t.Errorf("tenantOf = %q", got)
+	}
+	if got := callerUser(req.Context()); got != "key:payments/"+stored.ID {
+		t.Errorf("callerUser = %q", got)
+	}
+}
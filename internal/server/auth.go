@@ -0,0 +1,118 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Role identifies the output policy applied to an authenticated caller
+type Role string
+
+const (
+	// RoleAdmin sees complete, unredacted output
+	RoleAdmin Role = "admin"
+	// RoleDeveloper sees full analysis output but no raw XDR or simulator payloads
+	RoleDeveloper Role = "developer"
+	// RoleAnalyst sees analysis output with account and contract addresses redacted
+	RoleAnalyst Role = "analyst"
+)
+
+// ParseRole validates a role name
+func ParseRole(name string) (Role, error) {
+	switch Role(strings.ToLower(strings.TrimSpace(name))) {
+	case RoleAdmin:
+		return RoleAdmin, nil
+	case RoleDeveloper:
+		return RoleDeveloper, nil
+	case RoleAnalyst:
+		return RoleAnalyst, nil
+	default:
+		return "", fmt.Errorf("unknown role: %s (valid: admin, developer, analyst)", name)
+	}
+}
+
+// ParseTokenSpec parses a "<token>:<role>" specification as accepted by --token
+func ParseTokenSpec(spec string) (string, Role, error) {
+	idx := strings.LastIndex(spec, ":")
+	if idx <= 0 || idx == len(spec)-1 {
+		return "", "", fmt.Errorf("invalid token spec %q (expected <token>:<role>)", spec)
+	}
+
+	role, err := ParseRole(spec[idx+1:])
+	if err != nil {
+		return "", "", err
+	}
+
+	return spec[:idx], role, nil
+}
+
+// Authenticator maps bearer tokens to roles
+type Authenticator struct {
+	tokens map[string]Role
+}
+
+// NewAuthenticator creates an authenticator for the given token-to-role table.
+// An empty table disables authentication and grants every caller RoleAdmin,
+// matching the behaviour of the daemon when no auth token is configured.
+func NewAuthenticator(tokens map[string]Role) *Authenticator {
+	copied := make(map[string]Role, len(tokens))
+	for token, role := range tokens {
+		copied[token] = role
+	}
+	return &Authenticator{tokens: copied}
+}
+
+// Enabled reports whether any tokens are configured
+func (a *Authenticator) Enabled() bool {
+	return len(a.tokens) > 0
+}
+
+// Authenticate resolves the role for a request
+func (a *Authenticator) Authenticate(r *http.Request) (Role, bool) {
+	return a.AuthenticateToken(bearerToken(r))
+}
+
+// AuthenticateToken resolves the role for a bearer token
+func (a *Authenticator) AuthenticateToken(token string) (Role, bool) {
+	if !a.Enabled() {
+		return RoleAdmin, true
+	}
+
+	if token == "" {
+		return "", false
+	}
+
+	for candidate, role := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return role, true
+		}
+	}
+
+	return "", false
+}
+
+// bearerToken reads the token from the Authorization header. Browsers cannot
+// set headers on WebSocket handshakes, so upgrade requests may pass it as the
+// access_token query parameter instead.
+func bearerToken(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	if auth == "" && strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return strings.TrimSpace(r.URL.Query().Get("access_token"))
+	}
+	return bearerCredential(auth)
+}
+
+// bearerCredential returns the token of an "Authorization: Bearer <token>"
+// value, or "" for any other scheme
+func bearerCredential(auth string) string {
+	scheme, token, ok := strings.Cut(strings.TrimSpace(auth), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
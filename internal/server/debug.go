@@ -0,0 +1,146 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+	"github.com/dotandev/hintents/internal/tokenflow"
+)
+
+// DebugRequest is the body of POST /api/v1/debug
+type DebugRequest struct {
+	Hash    string `json:"hash"`
+	Network string `json:"network,omitempty"`
+}
+
+// DebugResult is the structured outcome of a debug run
+type DebugResult struct {
+	Hash          string                        `json:"hash"`
+	Network       string                        `json:"network"`
+	Status        string                        `json:"status"`
+	Error         string                        `json:"error,omitempty"`
+	LimitExceeded *simulator.LimitError         `json:"limit_exceeded,omitempty"`
+	EnvelopeXdr   string                        `json:"envelope_xdr,omitempty"`
+	ResultXdr     string                        `json:"result_xdr,omitempty"`
+	ResultMetaXdr string                        `json:"result_meta_xdr,omitempty"`
+	Simulation    *simulator.SimulationResponse `json:"simulation,omitempty"`
+	Findings      []security.Finding            `json:"findings"`
+	TokenFlow     []string                      `json:"token_flow,omitempty"`
+	TokenEdges    []TokenEdge                   `json:"token_edges,omitempty"`
+}
+
+// TokenEdge is one aggregated movement in the token flow graph
+type TokenEdge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Amount   string `json:"amount"`
+	Token    string `json:"token"`
+	Kind     string `json:"kind"`
+	OpIndex  int    `json:"op_index"`
+	Contract string `json:"contract,omitempty"`
+}
+
+// snapshot returns a shallow copy safe to hand to stream subscribers while the
+// run keeps filling in the original
+func (r *DebugResult) snapshot() *DebugResult {
+	c := *r
+	return &c
+}
+
+// runDebug fetches a transaction, replays it and runs the standard analyzers.
+// Progress is reported to job when it is non-nil.
+func (s *Server) runDebug(ctx context.Context, client *rpc.Client, hash string, job *Job) (*DebugResult, error) {
+	if err := rpc.ValidateTransactionHash(hash); err != nil {
+		return nil, fmt.Errorf("invalid transaction hash: %w", err)
+	}
+
+	logger.Logger.Info("Processing debug request", "hash", hash)
+
+	job.emit(JobEvent{Type: EventPhase, Phase: PhaseFetching, Message: "Fetching transaction from " + string(client.Network)})
+
+	txResp, err := client.GetTransaction(ctx, hash)
+	s.countUsage(ctx, storage.Usage{RPCCalls: 1})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction: %w", err)
+	}
+
+	result := &DebugResult{
+		Hash:          hash,
+		Network:       string(client.Network),
+		EnvelopeXdr:   txResp.EnvelopeXdr,
+		ResultXdr:     txResp.ResultXdr,
+		ResultMetaXdr: txResp.ResultMetaXdr,
+		Findings:      []security.Finding{},
+	}
+	job.emit(JobEvent{Type: EventPartial, Phase: PhaseFetching, Data: result.snapshot()})
+
+	entries, err := rpc.ExtractLedgerEntriesFromMeta(txResp.ResultMetaXdr)
+	if err != nil {
+		logger.Logger.Warn("Failed to extract ledger entries from metadata", "error", err)
+		job.emit(JobEvent{Type: EventLog, Message: "Failed to extract ledger entries from metadata: " + err.Error()})
+	}
+
+	job.emit(JobEvent{Type: EventPhase, Phase: PhaseSimulating, Message: fmt.Sprintf("Replaying with %d ledger entries", len(entries))})
+
+	simResp, err := s.runner.Run(&simulator.SimulationRequest{
+		EnvelopeXdr:   txResp.EnvelopeXdr,
+		ResultMetaXdr: txResp.ResultMetaXdr,
+		LedgerEntries: entries,
+	})
+	s.countUsage(ctx, storage.Usage{Simulations: 1})
+	if err != nil {
+		result.Status = "error"
+		result.Error = simulationFailure(err)
+		if le := limitExceeded(err); le != nil {
+			result.LimitExceeded = le
+			logger.Logger.Warn("Simulation stopped at a resource limit", "hash", hash, "resource", le.Resource, "limit", le.Limit)
+			job.emit(JobEvent{Type: EventLog, Message: le.Message})
+		}
+		return result, nil
+	}
+
+	if s.public {
+		if note := memoryQuotaReport(simResp, s.limits.SimMaxMemoryBytes); note != "" {
+			logger.Logger.Warn("Simulation memory above quota", "hash", hash, "note", note)
+			job.emit(JobEvent{Type: EventLog, Message: note})
+		}
+	}
+
+	result.Status = simResp.Status
+	result.Simulation = simResp
+	for _, line := range simResp.Logs {
+		job.emit(JobEvent{Type: EventLog, Message: line.Message})
+	}
+	job.emit(JobEvent{Type: EventPartial, Phase: PhaseSimulating, Data: result.snapshot()})
+
+	job.emit(JobEvent{Type: EventPhase, Phase: PhaseAnalyzing, Message: "Running security and token flow analysis"})
+	result.Findings = security.NewDetector().AnalyzeSimulation(txResp.EnvelopeXdr, txResp.ResultMetaXdr, simResp)
+
+	if report, err := tokenflow.BuildReport(txResp.EnvelopeXdr, txResp.ResultMetaXdr); err == nil {
+		result.TokenFlow = report.SummaryLines()
+		for _, g := range report.ByOp {
+			for _, t := range g.Agg {
+				result.TokenEdges = append(result.TokenEdges, TokenEdge{
+					From:     t.From,
+					To:       t.To,
+					Amount:   t.FormattedAmount(),
+					Token:    t.Token.Display(),
+					Kind:     string(t.Kind),
+					OpIndex:  t.OpIndex,
+					Contract: t.Contract,
+				})
+			}
+		}
+	}
+
+	job.emit(JobEvent{Type: EventPhase, Phase: PhaseDone})
+	return result, nil
+}
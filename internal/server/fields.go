@@ -0,0 +1,127 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Listing page sizes
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// Page is one page of a listing. NextCursor is passed back as ?cursor= to
+// fetch the following page; it is empty on the last one.
+type Page struct {
+	Items      interface{} `json:"items"`
+	NextCursor string      `json:"next_cursor,omitempty"`
+}
+
+// parseFields reads the ?fields= projection: comma-separated JSON field
+// names, with dots selecting nested fields (simulation.status)
+func parseFields(r *http.Request) ([]string, error) {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil, nil
+	}
+	var fields []string
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" || strings.HasPrefix(f, ".") || strings.HasSuffix(f, ".") || strings.Contains(f, "..") {
+			return nil, fmt.Errorf("invalid fields parameter %q", raw)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// parsePageSize reads ?limit=, defaulting to defaultPageSize
+func parsePageSize(r *http.Request) (int, error) {
+	raw := r.URL.Query().Get("limit")
+	if raw == "" {
+		return defaultPageSize, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > maxPageSize {
+		return 0, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+	return n, nil
+}
+
+// project keeps only fields of v, which is the generic JSON form of a
+// response. The items of a Page are projected one by one and the cursor is
+// kept.
+func project(v interface{}, fields []string, page bool) interface{} {
+	if len(fields) == 0 {
+		return v
+	}
+	if page {
+		if m, ok := v.(map[string]interface{}); ok {
+			if items, ok := m["items"].([]interface{}); ok {
+				for i, item := range items {
+					items[i] = selectFields(item, fields)
+				}
+			}
+			return m
+		}
+	}
+	return selectFields(v, fields)
+}
+
+// selectFields returns the fields of a JSON object, descending through
+// dotted paths. Lists of objects are projected element-wise.
+func selectFields(v interface{}, fields []string) interface{} {
+	switch val := v.(type) {
+	case []interface{}:
+		for i, item := range val {
+			val[i] = selectFields(item, fields)
+		}
+		return val
+	case map[string]interface{}:
+		nested := make(map[string][]string)
+		out := make(map[string]interface{})
+		for _, f := range fields {
+			head, rest, ok := strings.Cut(f, ".")
+			child, present := val[head]
+			if !present {
+				continue
+			}
+			if !ok {
+				out[head] = child
+				nested[head] = nil
+				continue
+			}
+			if sub, seen := nested[head]; !seen || sub != nil {
+				nested[head] = append(sub, rest)
+			}
+		}
+		for head, sub := range nested {
+			if sub != nil {
+				out[head] = selectFields(val[head], sub)
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// toGeneric converts v to its generic JSON form
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return generic, nil
+}
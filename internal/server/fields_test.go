@@ -0,0 +1,56 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestParseFields(t *testing.T) {
+	fields, err := parseFields(httptest.NewRequest("GET", "/?fields=status,+findings,simulation.status", nil))
+	if err != nil || !reflect.DeepEqual(fields, []string{"status", "findings", "simulation.status"}) {
+		t.Errorf("parseFields = %v, %v", fields, err)
+	}
+	for _, bad := range []string{"status,,findings", ".status", "simulation..status"} {
+		if _, err := parseFields(httptest.NewRequest("GET", "/?fields="+bad, nil)); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	var v interface{}
+	_ = json.Unmarshal([]byte(`{
+		"hash": "abc",
+		"status": "error",
+		"simulation": {"status": "error", "events": ["e1"], "logs": ["l1"]},
+		"findings": [{"title": "Reentrancy", "severity": "HIGH", "description": "long"}]
+	}`), &v)
+
+	got, _ := json.Marshal(selectFields(v, []string{"status", "simulation.status", "findings.title", "missing"}))
+	want := `{"findings":[{"title":"Reentrancy"}],"simulation":{"status":"error"},"status":"error"}`
+	if string(got) != want {
+		t.Errorf("selectFields = %s\nwant %s", got, want)
+	}
+
+	// A whole object wins over one of its fields
+	got, _ = json.Marshal(selectFields(v, []string{"simulation.status", "simulation"}))
+	if string(got) != `{"simulation":{"events":["e1"],"logs":["l1"],"status":"error"}}` {
+		t.Errorf("selectFields = %s", got)
+	}
+}
+
+func TestProjectPage(t *testing.T) {
+	generic, err := toGeneric(Page{Items: []map[string]string{{"id": "a", "tx_hash": "h"}}, NextCursor: "c"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(project(generic, []string{"id"}, true))
+	if string(got) != `{"items":[{"id":"a"}],"next_cursor":"c"}` {
+		t.Errorf("project = %s", got)
+	}
+}
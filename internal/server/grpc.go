@@ -0,0 +1,377 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+	erstv1 "github.com/dotandev/hintents/proto/erst/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// protoJSON decodes the JSON form of a response into its protobuf message.
+// The messages use the JSON field names of the REST API, so one role policy
+// serves both.
+var protoJSON = protojson.UnmarshalOptions{DiscardUnknown: true}
+
+// GRPCServer returns a gRPC server exposing erst.v1.DebugService. It shares
+// the worker pool, jobs, role policies and storage of the REST API, and
+// callers authenticate with the same bearer tokens in the "authorization"
+// metadata key.
+func (s *Server) GRPCServer() *grpc.Server {
+	gs := grpc.NewServer(
+		grpc.UnaryInterceptor(s.grpcUnaryAuth),
+		grpc.StreamInterceptor(s.grpcStreamAuth),
+	)
+	erstv1.RegisterDebugServiceServer(gs, &grpcService{s: s})
+	return gs
+}
+
+type grpcService struct {
+	erstv1.UnimplementedDebugServiceServer
+	s *Server
+}
+
+func (g *grpcService) Debug(ctx context.Context, req *erstv1.DebugRequest) (*erstv1.DebugResult, error) {
+	client, err := g.s.debugClient(DebugRequest{Hash: req.GetHash(), Network: req.GetNetwork()})
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	result, err := g.s.debugNow(ctx, g.s.grpcTenant(ctx), "grpc debug", client, req.GetHash())
+	if err != nil {
+		return nil, g.s.grpcError(err, codes.Unavailable)
+	}
+	out := &erstv1.DebugResult{}
+	if err := g.s.toProto(ctx, result, out); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return out, nil
+}
+
+func (g *grpcService) DebugStream(req *erstv1.DebugRequest, stream grpc.ServerStreamingServer[erstv1.JobEvent]) error {
+	ctx := stream.Context()
+	client, err := g.s.debugClient(DebugRequest{Hash: req.GetHash(), Network: req.GetNetwork()})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	job, err := g.s.startJob(ctx, g.s.grpcTenant(ctx), "grpc job", client, req.GetHash())
+	if err != nil {
+		return g.s.grpcError(err, codes.Internal)
+	}
+	return g.sendEvents(job, stream)
+}
+
+func (g *grpcService) WatchJob(req *erstv1.WatchJobRequest, stream grpc.ServerStreamingServer[erstv1.JobEvent]) error {
+	job, ok := g.s.jobFor(stream.Context(), req.GetId())
+	if !ok {
+		return status.Error(codes.NotFound, "job not found")
+	}
+	return g.sendEvents(job, stream)
+}
+
+// sendEvents replays the events of job and follows it until it finishes or
+// the client goes away. The job keeps running either way.
+func (g *grpcService) sendEvents(job *Job, stream grpc.ServerStreamingServer[erstv1.JobEvent]) error {
+	ctx := stream.Context()
+	history, ch := job.subscribe()
+	if ch != nil {
+		defer job.unsubscribe(ch)
+	}
+
+	policy := g.s.policyFor(roleFromContext(ctx))
+	send := func(ev JobEvent) error {
+		if !policy.showsEvent(ev) {
+			return nil
+		}
+		out := &erstv1.JobEvent{}
+		in := jobEventMessage{JobID: job.ID, Seq: ev.Seq, Type: ev.Type, Phase: ev.Phase, Message: ev.Message, Result: ev.Data}
+		if err := g.s.toProto(ctx, in, out); err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		return stream.Send(out)
+	}
+
+	for _, ev := range history {
+		if err := send(ev); err != nil {
+			return err
+		}
+	}
+	if ch == nil {
+		return nil
+	}
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := send(ev); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// jobEventMessage is the JSON form of erst.v1.JobEvent
+type jobEventMessage struct {
+	JobID   string      `json:"job_id"`
+	Seq     int         `json:"seq"`
+	Type    string      `json:"type"`
+	Phase   string      `json:"phase,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Result  interface{} `json:"result,omitempty"`
+}
+
+// simulateResult is the JSON form of erst.v1.SimulateResponse
+type simulateResult struct {
+	Status        string                        `json:"status"`
+	Error         string                        `json:"error,omitempty"`
+	LimitExceeded *simulator.LimitError         `json:"limit_exceeded,omitempty"`
+	Simulation    *simulator.SimulationResponse `json:"simulation,omitempty"`
+}
+
+func (g *grpcService) Simulate(ctx context.Context, req *erstv1.SimulateRequest) (*erstv1.SimulateResponse, error) {
+	if req.GetEnvelopeXdr() == "" {
+		return nil, status.Error(codes.InvalidArgument, "envelope_xdr is required")
+	}
+
+	var result simulateResult
+	done := make(chan struct{})
+	err := g.s.pool.Submit(g.s.grpcTenant(ctx), func() {
+		defer close(done)
+		if ctx.Err() != nil {
+			return
+		}
+		resp, err := g.s.runner.Run(&simulator.SimulationRequest{
+			EnvelopeXdr:   req.GetEnvelopeXdr(),
+			ResultMetaXdr: req.GetResultMetaXdr(),
+			LedgerEntries: req.GetLedgerEntries(),
+		})
+		g.s.countUsage(ctx, storage.Usage{Simulations: 1})
+		if err != nil {
+			result = simulateResult{Status: "error", Error: simulationFailure(err), LimitExceeded: limitExceeded(err)}
+			return
+		}
+		result = simulateResult{Status: resp.Status, Error: resp.Error, Simulation: resp}
+	})
+	if err != nil {
+		return nil, g.s.grpcError(err, codes.Internal)
+	}
+	<-done
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	out := &erstv1.SimulateResponse{}
+	if err := g.s.toProto(ctx, result, out); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return out, nil
+}
+
+func (g *grpcService) GetSession(ctx context.Context, req *erstv1.GetSessionRequest) (*erstv1.Session, error) {
+	if g.s.storage == nil {
+		return nil, status.Error(codes.FailedPrecondition, "sessions are only kept when the server runs with --storage")
+	}
+	data, err := g.s.sessionsFor(ctx).Load(ctx, req.GetId())
+	if errors.Is(err, session.ErrNotFound) {
+		if running, ok := g.s.runningSession(ctx, req.GetId()); ok {
+			return g.s.sessionProto(ctx, running)
+		}
+		return nil, status.Error(codes.NotFound, "session not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return g.s.sessionProto(ctx, data)
+}
+
+func (g *grpcService) ListSessions(ctx context.Context, req *erstv1.ListSessionsRequest) (*erstv1.ListSessionsResponse, error) {
+	if g.s.storage == nil {
+		return nil, status.Error(codes.FailedPrecondition, "sessions are only kept when the server runs with --storage")
+	}
+	size := int(req.GetPageSize())
+	if size == 0 {
+		size = defaultPageSize
+	}
+	if size < 0 || size > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be between 1 and %d", maxPageSize)
+	}
+
+	filter := session.Context{Anchor: req.GetAnchor(), SEPFlow: req.GetSepFlow(), CustomerRef: req.GetCustomerRef()}
+	sessions, next, err := g.s.sessionsFor(ctx).Page(ctx, filter, req.GetPageToken(), size)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		return nil, status.Error(codes.InvalidArgument, "invalid page_token")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	out := &erstv1.ListSessionsResponse{NextPageToken: next}
+	for _, data := range sessions {
+		sess, err := g.s.sessionProto(ctx, data)
+		if err != nil {
+			return nil, err
+		}
+		out.Sessions = append(out.Sessions, sess)
+	}
+	return out, nil
+}
+
+// sessionProto converts a session after the caller's role policy. The
+// summary fields are typed; the whole document is kept as a Struct.
+func (s *Server) sessionProto(ctx context.Context, data *session.SessionData) (*erstv1.Session, error) {
+	generic, err := s.policyFor(roleFromContext(ctx)).Apply(data)
+	if err == nil {
+		generic, err = toGeneric(generic)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	doc, ok := generic.(map[string]interface{})
+	if !ok {
+		return nil, status.Error(codes.Internal, "session is not a JSON object")
+	}
+
+	out := &erstv1.Session{}
+	if err := decodeProto(generic, out); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if out.Document, err = structpb.NewStruct(doc); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return out, nil
+}
+
+// toProto applies the caller's role policy to v and decodes the result into
+// out
+func (s *Server) toProto(ctx context.Context, v interface{}, out proto.Message) error {
+	generic, err := s.policyFor(roleFromContext(ctx)).Apply(v)
+	if err != nil {
+		return err
+	}
+	return decodeProto(generic, out)
+}
+
+func decodeProto(v interface{}, out proto.Message) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	if err := protoJSON.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to convert response: %w", err)
+	}
+	return nil
+}
+
+// grpcError maps a debug error to a status. Pool rejections become
+// RESOURCE_EXHAUSTED with a RetryInfo detail; anything else gets code.
+func (s *Server) grpcError(err error, code codes.Code) error {
+	if !isBusy(err) {
+		return status.Error(code, err.Error())
+	}
+	st := status.New(codes.ResourceExhausted, err.Error())
+	if detailed, derr := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(s.pool.RetryAfter())}); derr == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// grpcTenant identifies the caller for pool quotas, like tenantOf
+func (s *Server) grpcTenant(ctx context.Context) string {
+	ip := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	if key := apiKeyFromContext(ctx); key != nil {
+		return keyPoolTenant(key)
+	}
+	return s.tenant(grpcToken(ctx), ip)
+}
+
+// grpcToken reads the bearer token from the "authorization" metadata key
+func grpcToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+	return bearerCredential(values[0])
+}
+
+// grpcScopes maps each RPC to the API key scope it needs
+var grpcScopes = map[string]string{
+	erstv1.DebugService_Debug_FullMethodName:        ScopeDebug,
+	erstv1.DebugService_DebugStream_FullMethodName:  ScopeDebug,
+	erstv1.DebugService_Simulate_FullMethodName:     ScopeDebug,
+	erstv1.DebugService_WatchJob_FullMethodName:     ScopeJobs,
+	erstv1.DebugService_GetSession_FullMethodName:   ScopeSessions,
+	erstv1.DebugService_ListSessions_FullMethodName: ScopeSessions,
+}
+
+// grpcAuthenticate resolves the caller's role, and API key if one was used,
+// and stores them on the context
+func (s *Server) grpcAuthenticate(ctx context.Context, method string) (context.Context, error) {
+	role, key, ok := s.authenticate(ctx, grpcToken(ctx))
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unauthorized")
+	}
+	ctx = context.WithValue(ctx, roleContextKey, role)
+	if key != nil {
+		ctx = context.WithValue(ctx, apiKeyContextKey, key)
+		if scope := grpcScopes[method]; !key.HasScope(scope) {
+			return nil, status.Error(codes.PermissionDenied, "API key lacks the "+scope+" scope")
+		}
+		s.countUsage(ctx, storage.Usage{Requests: 1})
+	}
+	return ctx, nil
+}
+
+func (s *Server) grpcUnaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := s.grpcAuthenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) grpcStreamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.grpcAuthenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+}
+
+// authedStream carries the context with the caller's role to stream
+// handlers
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (a *authedStream) Context() context.Context {
+	return a.ctx
+}
@@ -0,0 +1,231 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+	erstv1 "github.com/dotandev/hintents/proto/erst/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialGRPC serves srv's gRPC API on an in-memory listener
+func dialGRPC(t *testing.T, srv *Server) erstv1.DebugServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := srv.GRPCServer()
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return erstv1.NewDebugServiceClient(conn)
+}
+
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+type stubRunner struct {
+	resp *simulator.SimulationResponse
+	err  error
+}
+
+func (r stubRunner) Run(*simulator.SimulationRequest) (*simulator.SimulationResponse, error) {
+	return r.resp, r.err
+}
+
+func TestGRPCAuth(t *testing.T) {
+	srv := newTestServer(t, map[string]Role{"secret": RoleAdmin})
+	client := dialGRPC(t, srv)
+
+	_, err := client.GetSession(context.Background(), &erstv1.GetSessionRequest{Id: "s1"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without a token, got %v", err)
+	}
+	_, err = client.GetSession(withToken("secret"), &erstv1.GetSessionRequest{Id: "s1"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("expected FailedPrecondition without storage, got %v", err)
+	}
+	_, err = client.Debug(withToken("secret"), &erstv1.DebugRequest{Hash: "nothex"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a bad hash, got %v", err)
+	}
+}
+
+func TestGRPCWatchJob(t *testing.T) {
+	srv := newTestServer(t, map[string]Role{"secret": RoleAnalyst})
+	client := dialGRPC(t, srv)
+
+	job := newJob("abc")
+	srv.jobs.Add(job)
+	job.emit(JobEvent{Type: EventPhase, Phase: PhaseFetching})
+	job.emit(JobEvent{Type: EventLog, Message: "host log line"})
+	job.emit(JobEvent{Type: EventPhase, Phase: PhaseSimulating, Message: "payment from " + testAccount})
+
+	stream, err := client.WatchJob(withToken("secret"), &erstv1.WatchJobRequest{Id: job.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, err := stream.Recv()
+	if err != nil || ev.Phase != PhaseFetching || ev.JobId != job.ID {
+		t.Fatalf("expected replayed phase event, got %v (err=%v)", ev, err)
+	}
+	ev, err = stream.Recv()
+	if err != nil || ev.Type == EventLog || strings.Contains(ev.Message, testAccount) {
+		t.Errorf("expected the analyst stream to be redacted without logs, got %v (err=%v)", ev, err)
+	}
+
+	job.finish(&DebugResult{Hash: "abc", Status: "success", EnvelopeXdr: "AAAA"}, nil)
+	ev, err = stream.Recv()
+	if err != nil || ev.Type != EventResult || ev.Result.GetStatus() != "success" {
+		t.Fatalf("expected result event, got %v (err=%v)", ev, err)
+	}
+	if ev.Result.EnvelopeXdr != "" {
+		t.Error("expected raw XDR to be hidden from analysts")
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Errorf("expected the stream to end after the result, got %v", err)
+	}
+
+	missing, err := client.WatchJob(withToken("secret"), &erstv1.WatchJobRequest{Id: "missing"})
+	if err == nil {
+		_, err = missing.Recv()
+	}
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestGRPCSimulate(t *testing.T) {
+	srv := newTestServer(t, nil)
+	srv.runner = stubRunner{resp: &simulator.SimulationResponse{Status: "success", Events: []simulator.Event{{Index: 0, Type: "contract"}}}}
+	client := dialGRPC(t, srv)
+
+	if _, err := client.Simulate(context.Background(), &erstv1.SimulateRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument without an envelope, got %v", err)
+	}
+
+	resp, err := client.Simulate(context.Background(), &erstv1.SimulateRequest{EnvelopeXdr: "AAAA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != "success" || resp.Simulation.GetFields()["status"].GetStringValue() != "success" {
+		t.Errorf("unexpected response %v", resp)
+	}
+
+	srv.runner = stubRunner{err: &simulator.LimitError{Resource: simulator.ResourceWallTime, Limit: "1s", Message: "stopped"}}
+	resp, err = client.Simulate(context.Background(), &erstv1.SimulateRequest{EnvelopeXdr: "AAAA"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != "error" || resp.LimitExceeded.GetResource() != simulator.ResourceWallTime {
+		t.Errorf("expected the limit to be reported, got %v", resp)
+	}
+}
+
+func TestGRPCSessions(t *testing.T) {
+	srv := newTestServer(t, nil)
+	db, err := storage.Open(context.Background(), storage.Config{DSN: filepath.Join(t.TempDir(), "erst.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	srv.storage = db
+	client := dialGRPC(t, srv)
+
+	base := time.Now().Add(-time.Hour)
+	for i, id := range []string{"s0", "s1", "s2"} {
+		if err := db.Sessions().Save(context.Background(), &session.SessionData{
+			ID:        id,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+			Status:    "saved",
+			TxHash:    "hash-" + id,
+			Context:   session.Context{Anchor: "anchor.example"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := client.ListSessions(context.Background(), &erstv1.ListSessionsRequest{PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Sessions) != 2 || page.Sessions[0].Id != "s2" || page.NextPageToken == "" {
+		t.Fatalf("unexpected first page %v", page)
+	}
+	first := page.Sessions[0]
+	if first.TxHash != "hash-s2" || first.Context.GetAnchor() != "anchor.example" || first.CreatedAt.AsTime().IsZero() {
+		t.Errorf("unexpected session %v", first)
+	}
+	if first.Document.GetFields()["tx_hash"].GetStringValue() != "hash-s2" {
+		t.Errorf("expected the full document, got %v", first.Document)
+	}
+
+	page, err = client.ListSessions(context.Background(), &erstv1.ListSessionsRequest{PageSize: 2, PageToken: page.NextPageToken})
+	if err != nil || len(page.Sessions) != 1 || page.NextPageToken != "" {
+		t.Errorf("unexpected last page %v (err=%v)", page, err)
+	}
+	if _, err := client.ListSessions(context.Background(), &erstv1.ListSessionsRequest{PageToken: "!"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for a bad token, got %v", err)
+	}
+
+	sess, err := client.GetSession(context.Background(), &erstv1.GetSessionRequest{Id: "s1"})
+	if err != nil || sess.Status != "saved" {
+		t.Errorf("unexpected session %v (err=%v)", sess, err)
+	}
+	if _, err := client.GetSession(context.Background(), &erstv1.GetSessionRequest{Id: "nope"}); status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestGRPCErrorBusy(t *testing.T) {
+	srv := newTestServer(t, nil)
+
+	st := status.Convert(srv.grpcError(ErrQueueFull, codes.Internal))
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", st.Code())
+	}
+	var retry *errdetails.RetryInfo
+	for _, d := range st.Details() {
+		if r, ok := d.(*errdetails.RetryInfo); ok {
+			retry = r
+		}
+	}
+	if retry == nil || retry.RetryDelay.AsDuration() < time.Second {
+		t.Errorf("expected a RetryInfo detail, got %v", st.Details())
+	}
+
+	if code := status.Code(srv.grpcError(errors.New("rpc down"), codes.Unavailable)); code != codes.Unavailable {
+		t.Errorf("expected Unavailable, got %v", code)
+	}
+}
+
+func TestGRPCNotInPublicMode(t *testing.T) {
+	t.Setenv("ERST_SIM_PATH", "/bin/echo")
+	if _, err := NewServer(Config{Network: "testnet", Public: true, GRPCPort: "9090"}); err == nil {
+		t.Error("expected gRPC to be refused in public mode")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// readyCheckTimeout bounds each readiness check so a hung dependency fails
+// the probe instead of stalling it
+const readyCheckTimeout = 3 * time.Second
+
+// Drain defaults
+const (
+	// DefaultDrainDelay is how long /readyz reports not ready before the
+	// listener closes, giving load balancers time to stop routing to the pod
+	DefaultDrainDelay = 5 * time.Second
+	// DefaultDrainTimeout bounds the wait for in-flight requests and
+	// background jobs
+	DefaultDrainTimeout = 30 * time.Second
+)
+
+// CheckResult is the outcome of one readiness check
+type CheckResult struct {
+	Name       string `json:"name"`
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Readiness is the body of GET /readyz
+type Readiness struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+	// Queue is the load of the debug worker pool
+	Queue *PoolStats `json:"queue,omitempty"`
+}
+
+// handleHealthz is the liveness probe: the process is up and serving HTTP
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadyz is the readiness probe. It fails while the server drains
+// and when the database, the simulator or the RPC endpoint is unavailable.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if s.draining.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, Readiness{Status: "draining"})
+		return
+	}
+	ready := s.Ready(r.Context())
+	status := http.StatusOK
+	if ready.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(w, status, ready)
+}
+
+type readyCheck struct {
+	name string
+	fn   func(context.Context) error
+}
+
+// Ready runs the readiness checks
+func (s *Server) Ready(ctx context.Context) Readiness {
+	checks := []readyCheck{
+		{"simulator", s.checkSimulator},
+		{"rpc", s.checkRPC},
+	}
+	if s.storage != nil {
+		checks = append(checks, readyCheck{"database", s.storage.Ping})
+	}
+
+	stats := s.pool.Stats()
+	ready := Readiness{Status: "ok", Queue: &stats}
+	for _, c := range checks {
+		cctx, cancel := context.WithTimeout(ctx, readyCheckTimeout)
+		start := time.Now()
+		err := c.fn(cctx)
+		cancel()
+		res := CheckResult{Name: c.name, OK: err == nil, DurationMs: time.Since(start).Milliseconds()}
+		if err != nil {
+			res.Error = err.Error()
+			ready.Status = "unavailable"
+		}
+		ready.Checks = append(ready.Checks, res)
+	}
+	return ready
+}
+
+// checkSimulator verifies the erst-sim binary is still present and
+// executable
+func (s *Server) checkSimulator(ctx context.Context) error {
+	runner, ok := s.runner.(*simulator.Runner)
+	if !ok {
+		return nil
+	}
+	if _, err := exec.LookPath(runner.BinaryPath); err != nil {
+		return fmt.Errorf("simulator binary unavailable: %w", err)
+	}
+	return nil
+}
+
+// checkRPC asks the default network's Soroban RPC for its latest ledger
+func (s *Server) checkRPC(ctx context.Context) error {
+	if _, err := s.rpcClient.GetLatestLedgerSequence(ctx); err != nil {
+		return fmt.Errorf("rpc unreachable: %w", err)
+	}
+	return nil
+}
+
+// drain marks the server not ready, waits delay for load balancers to
+// notice, then shuts srv and the gRPC server down and waits for background
+// jobs, all within timeout
+func (s *Server) drain(srv *http.Server, delay, timeout time.Duration) error {
+	s.draining.Store(true)
+	time.Sleep(delay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		if s.grpc != nil {
+			s.grpc.GracefulStop()
+		}
+		s.background.Wait()
+		close(done)
+	}()
+	err := srv.Shutdown(ctx)
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if s.grpc != nil {
+			s.grpc.Stop()
+		}
+		return fmt.Errorf("drain timed out after %s with debug jobs still running", timeout)
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+)
+
+func newLedgerRPC(t *testing.T) *httptest.Server {
+	t.Helper()
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"sequence":123}}`))
+	}))
+	t.Cleanup(rpc.Close)
+	return rpc
+}
+
+func getReadyz(t *testing.T, srv *Server) (int, Readiness) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	var ready Readiness
+	if err := json.NewDecoder(rec.Body).Decode(&ready); err != nil {
+		t.Fatalf("failed to decode /readyz: %v", err)
+	}
+	return rec.Code, ready
+}
+
+func TestHealthz(t *testing.T) {
+	srv := newTestServer(t, map[string]Role{"t1": RoleAdmin})
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected /healthz to be public and ok, got %d", rec.Code)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	srv := newTestServer(t, nil)
+	srv.rpcClient.SorobanURL = newLedgerRPC(t).URL
+
+	db, err := storage.Open(context.Background(), storage.Config{DSN: filepath.Join(t.TempDir(), "erst.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	srv.storage = db
+
+	code, ready := getReadyz(t, srv)
+	if code != http.StatusOK || ready.Status != "ok" || len(ready.Checks) != 3 {
+		t.Fatalf("expected ready, got %d %+v", code, ready)
+	}
+
+	db.Close()
+	srv.runner.(*simulator.Runner).BinaryPath = filepath.Join(t.TempDir(), "missing-erst-sim")
+	code, ready = getReadyz(t, srv)
+	if code != http.StatusServiceUnavailable || ready.Status != "unavailable" {
+		t.Fatalf("expected unavailable, got %d %+v", code, ready)
+	}
+	for _, c := range ready.Checks {
+		if c.Name != "rpc" && c.OK {
+			t.Errorf("expected the %s check to fail", c.Name)
+		}
+	}
+}
+
+func TestReadyzRPCDown(t *testing.T) {
+	srv := newTestServer(t, nil)
+	rpc := newLedgerRPC(t)
+	srv.rpcClient.SorobanURL = rpc.URL
+	rpc.Close()
+
+	code, ready := getReadyz(t, srv)
+	if code != http.StatusServiceUnavailable || ready.Checks[1].Name != "rpc" || ready.Checks[1].OK {
+		t.Errorf("expected the rpc check to fail, got %d %+v", code, ready)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	srv := newTestServer(t, nil)
+	srv.rpcClient.SorobanURL = newLedgerRPC(t).URL
+	httpSrv := httptest.NewServer(srv.Handler())
+	defer httpSrv.Close()
+
+	release := make(chan struct{})
+	srv.background.Add(1)
+	go func() {
+		defer srv.background.Done()
+		<-release
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- srv.drain(httpSrv.Config, 50*time.Millisecond, 5*time.Second) }()
+
+	// Readiness fails as soon as draining starts
+	time.Sleep(10 * time.Millisecond)
+	if code, ready := getReadyz(t, srv); code != http.StatusServiceUnavailable || ready.Status != "draining" {
+		t.Errorf("expected draining, got %d %+v", code, ready)
+	}
+
+	select {
+	case <-done:
+		t.Fatal("drain returned before the background job finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Errorf("drain: %v", err)
+	}
+}
+
+func TestDrainTimeout(t *testing.T) {
+	srv := newTestServer(t, nil)
+	httpSrv := httptest.NewServer(srv.Handler())
+	defer httpSrv.Close()
+
+	srv.background.Add(1)
+	defer srv.background.Done()
+	if err := srv.drain(httpSrv.Config, 0, 50*time.Millisecond); err == nil {
+		t.Error("expected a timeout with a job still running")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// IdempotencyHeader lets clients retry POST /api/v1/debug without starting a
+// second simulation
+const IdempotencyHeader = "Idempotency-Key"
+
+// ReplayedHeader is set on responses answered from an earlier request with
+// the same idempotency key
+const ReplayedHeader = "Idempotent-Replayed"
+
+// idempotencyRetention is how long a successful result is replayed for its key
+const idempotencyRetention = 24 * time.Hour
+
+// idempotencyCapacity bounds how many keys a server remembers
+const idempotencyCapacity = 10000
+
+// maxIdempotencyKey bounds the length of an Idempotency-Key
+const maxIdempotencyKey = 255
+
+// ErrIdempotencyMismatch is returned when a key is reused with a different request
+var ErrIdempotencyMismatch = errors.New("Idempotency-Key was already used for a different request")
+
+type idempotentCall struct {
+	id       string
+	request  string
+	done     chan struct{}
+	result   *DebugResult
+	err      error
+	finished time.Time
+}
+
+// IdempotencyStore deduplicates debug requests by client-chosen key. Keys are
+// scoped to the tenant so callers cannot read each other's results. The store
+// lives in memory, so replicas behind a load balancer each keep their own and
+// a retry routed to another replica runs again.
+type IdempotencyStore struct {
+	mu        sync.Mutex
+	calls     map[string]*list.Element
+	recent    *list.List // of *idempotentCall, most recently used first
+	retention time.Duration
+	capacity  int
+}
+
+// NewIdempotencyStore creates an empty store keeping results for retention.
+// Past capacity keys it forgets the least recently used finished result.
+func NewIdempotencyStore(retention time.Duration, capacity int) *IdempotencyStore {
+	return &IdempotencyStore{
+		calls:     make(map[string]*list.Element),
+		recent:    list.New(),
+		retention: retention,
+		capacity:  capacity,
+	}
+}
+
+// Do runs fn once per tenant and key. Calls with a key already in flight wait
+// for it and share its outcome, and calls after it succeeded get its result
+// with replayed set. request describes what fn does; reusing a key for a
+// different request returns ErrIdempotencyMismatch. Failures are not kept,
+// so a retry after an error runs again.
+func (s *IdempotencyStore) Do(ctx context.Context, tenant, key, request string, fn func() (*DebugResult, error)) (result *DebugResult, replayed bool, err error) {
+	id := tenant + "\x00" + key
+
+	s.mu.Lock()
+	now := time.Now()
+	for e := s.recent.Front(); e != nil; {
+		next := e.Next()
+		if c := e.Value.(*idempotentCall); !c.finished.IsZero() && c.finished.Before(now.Add(-s.retention)) {
+			s.remove(e)
+		}
+		e = next
+	}
+	if e, ok := s.calls[id]; ok {
+		s.recent.MoveToFront(e)
+		c := e.Value.(*idempotentCall)
+		s.mu.Unlock()
+		if c.request != request {
+			return nil, false, ErrIdempotencyMismatch
+		}
+		select {
+		case <-c.done:
+			return c.result, c.err == nil, c.err
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+	c := &idempotentCall{id: id, request: request, done: make(chan struct{})}
+	s.calls[id] = s.recent.PushFront(c)
+	s.evict()
+	s.mu.Unlock()
+
+	c.result, c.err = fn()
+
+	s.mu.Lock()
+	if c.err != nil {
+		if e, ok := s.calls[id]; ok && e.Value == c {
+			s.remove(e)
+		}
+	} else {
+		c.finished = time.Now()
+	}
+	s.mu.Unlock()
+	close(c.done)
+
+	return c.result, false, c.err
+}
+
+// evict drops the least recently used finished calls until the store is
+// within capacity. Calls in flight stay so their waiters keep sharing them.
+func (s *IdempotencyStore) evict() {
+	for e := s.recent.Back(); e != nil && len(s.calls) > s.capacity; {
+		prev := e.Prev()
+		if !e.Value.(*idempotentCall).finished.IsZero() {
+			s.remove(e)
+		}
+		e = prev
+	}
+}
+
+func (s *IdempotencyStore) remove(e *list.Element) {
+	s.recent.Remove(e)
+	delete(s.calls, e.Value.(*idempotentCall).id)
+}
+
+// validIdempotencyKey reports whether key is 1 to 255 printable ASCII characters
+func validIdempotencyKey(key string) bool {
+	if key == "" || len(key) > maxIdempotencyKey {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
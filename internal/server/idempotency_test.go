@@ -0,0 +1,166 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyStoreSharesInFlight(t *testing.T) {
+	s := NewIdempotencyStore(time.Hour, idempotencyCapacity)
+	var runs atomic.Int32
+	release := make(chan struct{})
+	fn := func() (*DebugResult, error) {
+		runs.Add(1)
+		<-release
+		return &DebugResult{Status: "success"}, nil
+	}
+
+	var wg sync.WaitGroup
+	replays := make([]bool, 3)
+	for i := range replays {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result, replayed, err := s.Do(context.Background(), "t", "k", "req", fn)
+			if err != nil || result.Status != "success" {
+				t.Errorf("Do: %v, %v", result, err)
+			}
+			replays[i] = replayed
+		}(i)
+	}
+	waitFor(t, func() bool { return runs.Load() == 1 })
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if runs.Load() != 1 {
+		t.Errorf("expected one run, got %d", runs.Load())
+	}
+	fresh := 0
+	for _, r := range replays {
+		if !r {
+			fresh++
+		}
+	}
+	if fresh != 1 {
+		t.Errorf("expected exactly one fresh response, got %v", replays)
+	}
+
+	// A later retry replays the stored result
+	_, replayed, _ := s.Do(context.Background(), "t", "k", "req", fn)
+	if !replayed || runs.Load() != 1 {
+		t.Error("expected the completed result to be replayed")
+	}
+}
+
+func TestIdempotencyStoreScopesAndMismatch(t *testing.T) {
+	s := NewIdempotencyStore(time.Hour, idempotencyCapacity)
+	var runs int
+	fn := func() (*DebugResult, error) {
+		runs++
+		return &DebugResult{}, nil
+	}
+
+	_, _, _ = s.Do(context.Background(), "a", "k", "req", fn)
+	if _, _, err := s.Do(context.Background(), "a", "k", "other", fn); !errors.Is(err, ErrIdempotencyMismatch) {
+		t.Errorf("expected ErrIdempotencyMismatch, got %v", err)
+	}
+	// The same key from another tenant is a different request
+	if _, replayed, _ := s.Do(context.Background(), "b", "k", "req", fn); replayed || runs != 2 {
+		t.Errorf("expected tenants not to share keys (runs=%d)", runs)
+	}
+}
+
+func TestIdempotencyStoreForgetsFailuresAndExpired(t *testing.T) {
+	s := NewIdempotencyStore(time.Hour, idempotencyCapacity)
+	var runs int
+	failing := func() (*DebugResult, error) {
+		runs++
+		return nil, errors.New("rpc timeout")
+	}
+
+	_, _, _ = s.Do(context.Background(), "t", "k", "req", failing)
+	if _, replayed, err := s.Do(context.Background(), "t", "k", "req", failing); replayed || err == nil || runs != 2 {
+		t.Errorf("expected a failed call to run again (runs=%d)", runs)
+	}
+
+	s.retention = 0
+	ok := func() (*DebugResult, error) {
+		runs++
+		return &DebugResult{}, nil
+	}
+	_, _, _ = s.Do(context.Background(), "t", "k2", "req", ok)
+	time.Sleep(time.Millisecond)
+	if _, replayed, _ := s.Do(context.Background(), "t", "k2", "req", ok); replayed {
+		t.Error("expected an expired key to run again")
+	}
+}
+
+func TestIdempotencyStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	s := NewIdempotencyStore(time.Hour, 2)
+	var runs int
+	fn := func() (*DebugResult, error) {
+		runs++
+		return &DebugResult{}, nil
+	}
+
+	_, _, _ = s.Do(context.Background(), "t", "a", "req", fn)
+	_, _, _ = s.Do(context.Background(), "t", "b", "req", fn)
+	// Using a makes b the least recently used, so c pushes b out
+	_, _, _ = s.Do(context.Background(), "t", "a", "req", fn)
+	_, _, _ = s.Do(context.Background(), "t", "c", "req", fn)
+
+	if len(s.calls) != 2 || s.recent.Len() != 2 {
+		t.Fatalf("store holds %d keys, want 2", len(s.calls))
+	}
+	if _, replayed, _ := s.Do(context.Background(), "t", "a", "req", fn); !replayed {
+		t.Error("expected a to be kept")
+	}
+	if _, replayed, _ := s.Do(context.Background(), "t", "b", "req", fn); replayed || runs != 4 {
+		t.Errorf("expected b to be evicted and run again (runs=%d)", runs)
+	}
+}
+
+func TestDebugIdempotencyKey(t *testing.T) {
+	srv := newTestServer(t, nil)
+	hash := strings.Repeat("ab", 32)
+	post := func(key, hash string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/debug", strings.NewReader(`{"hash":"`+hash+`"}`))
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.Header.Set(IdempotencyHeader, key)
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := post(strings.Repeat("k", 256), hash); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an overlong key, got %d", rec.Code)
+	}
+
+	// Seed the key as if an earlier request had completed
+	_, _, _ = srv.idempotency.Do(context.Background(), "ip:192.0.2.1", "ci-run-1", "testnet "+hash, func() (*DebugResult, error) {
+		return &DebugResult{Hash: hash, Status: "success"}, nil
+	})
+
+	rec := post("ci-run-1", hash)
+	if rec.Code != http.StatusOK || rec.Header().Get(ReplayedHeader) != "true" {
+		t.Fatalf("expected a replayed 200, got %d %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), `"status":"success"`) {
+		t.Errorf("unexpected body %s", rec.Body)
+	}
+
+	if rec := post("ci-run-1", strings.Repeat("cd", 32)); rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for a reused key, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Job event types streamed to clients
+const (
+	EventPhase   = "phase"
+	EventLog     = "log"
+	EventPartial = "partial"
+	EventResult  = "result"
+	EventError   = "error"
+)
+
+// Debug phases reported while a job runs
+const (
+	PhaseQueued     = "queued"
+	PhaseFetching   = "fetching"
+	PhaseSimulating = "simulating"
+	PhaseAnalyzing  = "analyzing"
+	PhaseDone       = "done"
+)
+
+// jobRetention is how long finished jobs stay available for polling and replay
+const jobRetention = 10 * time.Minute
+
+// slackJobRetention keeps jobs started from Slack longer, since their report
+// links are read later in the channel history
+const slackJobRetention = 24 * time.Hour
+
+// JobEvent is one progress update for an asynchronous debug job
+type JobEvent struct {
+	Seq     int         `json:"seq"`
+	Type    string      `json:"type"`
+	Phase   string      `json:"phase,omitempty"`
+	Message string      `json:"message,omitempty"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// JobStatus is the body of GET /api/v1/jobs/{id}
+type JobStatus struct {
+	ID     string       `json:"id"`
+	Hash   string       `json:"hash"`
+	Phase  string       `json:"phase"`
+	Done   bool         `json:"done"`
+	Error  string       `json:"error,omitempty"`
+	Result *DebugResult `json:"result,omitempty"`
+}
+
+// Job tracks a debug request running in the background. Events are buffered
+// so subscribers that connect late replay the full history.
+type Job struct {
+	ID   string
+	Hash string
+
+	// tenant is the tenant of the API key that started the job, if any
+	tenant string
+
+	mu       sync.Mutex
+	phase    string
+	events   []JobEvent
+	subs     map[chan JobEvent]struct{}
+	done     bool
+	finished time.Time
+	result   *DebugResult
+	err      string
+
+	// slack marks jobs started by a Slack slash command, whose reports are
+	// served without a bearer token
+	slack bool
+}
+
+func newJob(hash string) *Job {
+	return &Job{
+		ID:    newJobID(),
+		Hash:  hash,
+		phase: PhaseQueued,
+		subs:  make(map[chan JobEvent]struct{}),
+	}
+}
+
+func newJobID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// emit records an event and fans it out to live subscribers. It is a no-op on
+// a nil job so synchronous requests can share the same code path.
+func (j *Job) emit(ev JobEvent) {
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.done {
+		return
+	}
+
+	ev.Seq = len(j.events)
+	if ev.Type == EventPhase {
+		j.phase = ev.Phase
+	}
+	j.events = append(j.events, ev)
+
+	for ch := range j.subs {
+		select {
+		case ch <- ev:
+		default:
+			// Drop subscribers that fall too far behind rather than stall the job
+			delete(j.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// finish records the terminal event and closes all subscriptions
+func (j *Job) finish(result *DebugResult, err error) {
+	if err != nil {
+		j.emit(JobEvent{Type: EventError, Message: err.Error()})
+	} else {
+		j.emit(JobEvent{Type: EventResult, Data: result})
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.done = true
+	j.finished = time.Now()
+	j.result = result
+	if err != nil {
+		j.err = err.Error()
+	}
+	for ch := range j.subs {
+		close(ch)
+	}
+	j.subs = nil
+}
+
+// subscribe returns the events emitted so far and a channel for the rest.
+// The channel is closed once the job finishes; it is nil if it already has.
+func (j *Job) subscribe() ([]JobEvent, chan JobEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	history := append([]JobEvent(nil), j.events...)
+	if j.done {
+		return history, nil
+	}
+
+	ch := make(chan JobEvent, 256)
+	j.subs[ch] = struct{}{}
+	return history, ch
+}
+
+// eventsAfter returns up to limit events with a sequence number above
+// after, and whether more follow
+func (j *Job) eventsAfter(after, limit int) ([]JobEvent, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	start := min(max(after+1, 0), len(j.events))
+	end := min(start+limit, len(j.events))
+	return append([]JobEvent(nil), j.events[start:end]...), end < len(j.events)
+}
+
+func (j *Job) unsubscribe(ch chan JobEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, ok := j.subs[ch]; ok {
+		delete(j.subs, ch)
+		close(ch)
+	}
+}
+
+// Status returns a snapshot of the job state
+func (j *Job) Status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return JobStatus{
+		ID:     j.ID,
+		Hash:   j.Hash,
+		Phase:  j.phase,
+		Done:   j.done,
+		Error:  j.err,
+		Result: j.result,
+	}
+}
+
+// JobStore holds in-flight and recently finished jobs
+type JobStore struct {
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewJobStore creates an empty job store
+func NewJobStore() *JobStore {
+	return &JobStore{jobs: make(map[string]*Job)}
+}
+
+// Add registers a job, dropping finished jobs past their retention
+func (s *JobStore) Add(j *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, old := range s.jobs {
+		retention := jobRetention
+		if old.slack {
+			retention = slackJobRetention
+		}
+		old.mu.Lock()
+		expired := old.done && old.finished.Before(now.Add(-retention))
+		old.mu.Unlock()
+		if expired {
+			delete(s.jobs, id)
+		}
+	}
+
+	s.jobs[j.ID] = j
+}
+
+// Get looks up a job by ID
+func (s *JobStore) Get(id string) (*Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	return j, ok
+}
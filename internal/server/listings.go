@@ -0,0 +1,103 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/storage"
+)
+
+// handleJobEvents lists the recorded events of a job, oldest first. The
+// cursor is the sequence number of the last event already seen.
+func (s *Server) handleJobEvents(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobFor(r.Context(), r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	limit, err := parsePageSize(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	after := -1
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		if after, err = strconv.Atoi(cursor); err != nil || after < 0 {
+			writeError(w, http.StatusBadRequest, "invalid cursor")
+			return
+		}
+	}
+
+	events, more := job.eventsAfter(after, limit)
+	// Withheld events still advance the cursor, so a page may come back short
+	policy := s.policyFor(roleFromContext(r.Context()))
+	visible := []JobEvent{}
+	for _, ev := range events {
+		if policy.showsEvent(ev) {
+			visible = append(visible, ev)
+		}
+	}
+	page := Page{Items: visible}
+	if more {
+		page.NextCursor = strconv.Itoa(events[len(events)-1].Seq)
+	}
+	s.render(w, r, http.StatusOK, page)
+}
+
+// handleListSessions lists the stored sessions, newest first, optionally
+// filtered by ?anchor=, ?sep_flow= and ?customer_ref=
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	if s.storage == nil {
+		writeError(w, http.StatusNotFound, "sessions are only kept when the server runs with --storage")
+		return
+	}
+	limit, err := parsePageSize(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q := r.URL.Query()
+	filter := session.Context{Anchor: q.Get("anchor"), SEPFlow: q.Get("sep_flow"), CustomerRef: q.Get("customer_ref")}
+
+	sessions, next, err := s.sessionsFor(r.Context()).Page(r.Context(), filter, q.Get("cursor"), limit)
+	if errors.Is(err, storage.ErrInvalidCursor) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if sessions == nil {
+		sessions = []*session.SessionData{}
+	}
+	s.render(w, r, http.StatusOK, Page{Items: sessions, NextCursor: next})
+}
+
+// handleGetSession returns one stored session, or the active session of
+// a background job of the caller that has not been stored yet
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	if s.storage == nil {
+		writeError(w, http.StatusNotFound, "sessions are only kept when the server runs with --storage")
+		return
+	}
+	data, err := s.sessionsFor(r.Context()).Load(r.Context(), r.PathValue("id"))
+	if errors.Is(err, session.ErrNotFound) {
+		if running, ok := s.runningSession(r.Context(), r.PathValue("id")); ok {
+			s.render(w, r, http.StatusOK, running)
+			return
+		}
+		writeError(w, http.StatusNotFound, "session not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.render(w, r, http.StatusOK, data)
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/storage"
+)
+
+type testPage struct {
+	Items      []map[string]interface{} `json:"items"`
+	NextCursor string                   `json:"next_cursor"`
+}
+
+func getPage(t *testing.T, srv *Server, url string) (int, testPage) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", url, nil))
+	var page testPage
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+			t.Fatalf("failed to decode %s: %v", url, err)
+		}
+	}
+	return rec.Code, page
+}
+
+func TestJobEventsPagination(t *testing.T) {
+	srv := newTestServer(t, nil)
+	job := newJob("abc")
+	for i := 0; i < 5; i++ {
+		job.emit(JobEvent{Type: EventLog, Message: fmt.Sprintf("line %d", i)})
+	}
+	srv.jobs.Add(job)
+
+	var messages []interface{}
+	url := "/api/v1/jobs/" + job.ID + "/events?limit=2&fields=message"
+	for pages := 0; ; pages++ {
+		code, page := getPage(t, srv, url)
+		if code != http.StatusOK || pages > 5 {
+			t.Fatalf("unexpected response %d after %d pages", code, pages)
+		}
+		for _, item := range page.Items {
+			if len(item) != 1 {
+				t.Errorf("expected only the message field, got %v", item)
+			}
+			messages = append(messages, item["message"])
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		url = "/api/v1/jobs/" + job.ID + "/events?limit=2&fields=message&cursor=" + page.NextCursor
+	}
+	if len(messages) != 5 || messages[4] != "line 4" {
+		t.Errorf("unexpected events %v", messages)
+	}
+
+	analyst := newTestServer(t, map[string]Role{"secret": RoleAnalyst})
+	analyst.jobs.Add(job)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/v1/jobs/"+job.ID+"/events", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	analyst.Handler().ServeHTTP(rec, req)
+	var hidden testPage
+	if err := json.NewDecoder(rec.Body).Decode(&hidden); err != nil || rec.Code != http.StatusOK || len(hidden.Items) != 0 {
+		t.Errorf("expected analysts to get no log events, got %d %+v (err=%v)", rec.Code, hidden, err)
+	}
+
+	if code, _ := getPage(t, srv, "/api/v1/jobs/"+job.ID+"/events?cursor=x"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad cursor, got %d", code)
+	}
+	if code, _ := getPage(t, srv, "/api/v1/jobs/"+job.ID+"/events?limit=0"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad limit, got %d", code)
+	}
+}
+
+func TestListSessions(t *testing.T) {
+	srv := newTestServer(t, nil)
+	if code, _ := getPage(t, srv, "/api/v1/sessions"); code != http.StatusNotFound {
+		t.Errorf("expected 404 without storage, got %d", code)
+	}
+
+	db, err := storage.Open(context.Background(), storage.Config{DSN: filepath.Join(t.TempDir(), "erst.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	srv.storage = db
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 3; i++ {
+		if err := db.Sessions().Save(context.Background(), &session.SessionData{
+			ID:        fmt.Sprintf("s%d", i),
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+			Status:    "saved",
+			TxHash:    fmt.Sprintf("hash%d", i),
+			Context:   session.Context{Anchor: "anchor.example"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	code, page := getPage(t, srv, "/api/v1/sessions?limit=2&fields=id,tx_hash")
+	if code != http.StatusOK || len(page.Items) != 2 || page.NextCursor == "" {
+		t.Fatalf("unexpected first page %d %+v", code, page)
+	}
+	if page.Items[0]["id"] != "s2" || len(page.Items[0]) != 2 {
+		t.Errorf("expected the newest session first with two fields, got %v", page.Items[0])
+	}
+	code, page = getPage(t, srv, "/api/v1/sessions?limit=2&cursor="+page.NextCursor)
+	if code != http.StatusOK || len(page.Items) != 1 || page.Items[0]["id"] != "s0" || page.NextCursor != "" {
+		t.Errorf("unexpected last page %d %+v", code, page)
+	}
+
+	if code, page := getPage(t, srv, "/api/v1/sessions?anchor=other"); code != http.StatusOK || len(page.Items) != 0 {
+		t.Errorf("expected no sessions for another anchor, got %d %+v", code, page)
+	}
+	if code, _ := getPage(t, srv, "/api/v1/sessions?cursor=%21"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad cursor, got %d", code)
+	}
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/sessions/s1?fields=status", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "{\"status\":\"saved\"}\n" {
+		t.Errorf("unexpected session response %d %s", rec.Code, rec.Body.String())
+	}
+
+	// Simulator documents are JSON text the policy cannot redact inside
+	raw := &session.SessionData{
+		ID:              "raw",
+		SimResponseJSON: `{"logs":["secret"]}`,
+		Runs:            []session.Run{{Name: "default", SimResponseJSON: `{"logs":["secret"]}`}},
+	}
+	for _, role := range []Role{RoleDeveloper, RoleAnalyst} {
+		out, err := DefaultPolicies()[role].Apply(raw)
+		doc, _ := json.Marshal(out)
+		if err != nil || strings.Contains(string(doc), "secret") {
+			t.Errorf("%s: expected simulator documents to be hidden, got %s (err=%v)", role, doc, err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/schema"
+)
+
+// APIVersion is the version of the REST API in the OpenAPI document. Bump
+// the minor version for additions and the major version, together with the
+// /api/v1 prefix, for breaking changes.
+const APIVersion = "1.0.0"
+
+const componentsPrefix = "#/components/schemas/"
+
+type openAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       openAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components openAPIComponents                       `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Version     string `json:"version"`
+	License     struct {
+		Name       string `json:"name"`
+		Identifier string `json:"identifier"`
+	} `json:"license"`
+}
+
+type openAPIOperation struct {
+	OperationID string                      `json:"operationId"`
+	Summary     string                      `json:"summary"`
+	Parameters  []openAPIParameter          `json:"parameters,omitempty"`
+	RequestBody *openAPIBody                `json:"requestBody,omitempty"`
+	Responses   map[string]*openAPIResponse `json:"responses"`
+	Security    []map[string][]string       `json:"security,omitempty"`
+	// WebSocketFrame is the schema of each text frame of a WebSocket
+	// endpoint, which OpenAPI has no keyword for
+	WebSocketFrame *schema.Schema `json:"x-websocket-frame,omitempty"`
+	// Scope is the API key scope the operation needs
+	Scope string `json:"x-api-key-scope,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *schema.Schema `json:"schema"`
+}
+
+type openAPIBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Headers     map[string]openAPIHeader    `json:"headers,omitempty"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIHeader struct {
+	Description string         `json:"description"`
+	Schema      *schema.Schema `json:"schema"`
+}
+
+type openAPIMediaType struct {
+	Schema *schema.Schema `json:"schema"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*schema.Schema         `json:"schemas"`
+	SecuritySchemes map[string]map[string]interface{} `json:"securitySchemes"`
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// OpenAPI returns the OpenAPI 3.1 document of the REST API as indented JSON.
+// It is generated from the route table and the Go types of the request and
+// response bodies.
+func OpenAPI() ([]byte, error) {
+	openAPIOnce.Do(func() {
+		out, err := json.MarshalIndent(buildOpenAPI(), "", "  ")
+		if err != nil {
+			openAPIErr = fmt.Errorf("failed to encode OpenAPI document: %w", err)
+			return
+		}
+		openAPIData = append(out, '\n')
+	})
+	return openAPIData, openAPIErr
+}
+
+// The document is built on first use; the route table refers back to the
+// handler serving it, so it cannot be a package-level initializer
+var (
+	openAPIOnce sync.Once
+	openAPIData []byte
+	openAPIErr  error
+)
+
+func buildOpenAPI() *openAPIDocument {
+	g := schema.NewPayloadGenerator()
+	g.RefPrefix = componentsPrefix
+
+	doc := &openAPIDocument{
+		OpenAPI: "3.1.0",
+		Paths:   make(map[string]map[string]*openAPIOperation),
+		Components: openAPIComponents{
+			SecuritySchemes: map[string]map[string]interface{}{
+				"bearerAuth": {
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "Token passed to erst serve --token, or a tenant API key (erst_...); it selects the caller's role",
+				},
+			},
+		},
+	}
+	doc.Info.Title = "erst serve"
+	doc.Info.Description = "REST API for replaying and analyzing Stellar transactions"
+	doc.Info.Version = APIVersion
+	doc.Info.License.Name = "Apache-2.0"
+	doc.Info.License.Identifier = "Apache-2.0"
+
+	errorBody := jsonContent(g.Schema(ErrorResponse{}))
+	pages := make(map[string]*schema.Schema)
+
+	for _, rt := range apiRoutes() {
+		op := &openAPIOperation{
+			OperationID: rt.id,
+			Summary:     rt.summary,
+			Scope:       rt.scope,
+			Responses:   make(map[string]*openAPIResponse),
+		}
+		for _, m := range pathParamPattern.FindAllStringSubmatch(rt.path, -1) {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name: m[1], In: "path", Required: true, Schema: &schema.Schema{Type: schema.Types{"string"}},
+			})
+		}
+		for _, q := range rt.query {
+			typ := "string"
+			if q.integer {
+				typ = "integer"
+			}
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name: q.name, In: "query", Description: q.description, Schema: &schema.Schema{Type: schema.Types{typ}},
+			})
+		}
+		for _, h := range rt.headers {
+			op.Parameters = append(op.Parameters, openAPIParameter{
+				Name: h.name, In: "header", Description: h.description, Schema: &schema.Schema{Type: schema.Types{"string"}},
+			})
+		}
+		if rt.request != nil {
+			op.RequestBody = &openAPIBody{Required: true, Content: jsonContent(g.Schema(rt.request))}
+		}
+
+		success := &openAPIResponse{Description: http.StatusText(rt.status)}
+		body := g.Schema(rt.response)
+		switch {
+		case rt.upgrade:
+			success.Description = "WebSocket upgrade; every text frame is one JSON value of x-websocket-frame"
+			op.WebSocketFrame = body
+		case rt.page:
+			name := body.Ref[len(componentsPrefix):] + "Page"
+			pages[name] = pageSchema(body)
+			success.Content = jsonContent(&schema.Schema{Ref: componentsPrefix + name})
+		default:
+			success.Content = jsonContent(body)
+		}
+		op.Responses[strconv.Itoa(rt.status)] = success
+
+		for status, v := range rt.other {
+			op.Responses[strconv.Itoa(status)] = &openAPIResponse{
+				Description: http.StatusText(status),
+				Content:     jsonContent(g.Schema(v)),
+			}
+		}
+		errs := rt.errors
+		if !rt.public {
+			op.Security = []map[string][]string{{"bearerAuth": {}}}
+			errs = append([]int{http.StatusUnauthorized, http.StatusForbidden}, errs...)
+		}
+		for _, status := range errs {
+			resp := &openAPIResponse{Description: http.StatusText(status), Content: errorBody}
+			if status == http.StatusTooManyRequests {
+				resp.Headers = map[string]openAPIHeader{
+					"Retry-After": {
+						Description: "Seconds until the queue is expected to have room",
+						Schema:      &schema.Schema{Type: schema.Types{"integer"}},
+					},
+				}
+			}
+			op.Responses[strconv.Itoa(status)] = resp
+		}
+
+		// Mux patterns and OpenAPI paths share the {name} syntax
+		if doc.Paths[rt.path] == nil {
+			doc.Paths[rt.path] = make(map[string]*openAPIOperation)
+		}
+		doc.Paths[rt.path][strings.ToLower(rt.method)] = op
+	}
+
+	doc.Components.Schemas = g.Defs()
+	for name, s := range pages {
+		doc.Components.Schemas[name] = s
+	}
+	return doc
+}
+
+// pageSchema is the schema of a Page of items
+func pageSchema(items *schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Type: schema.Types{"object"},
+		Properties: map[string]*schema.Schema{
+			"items":       {Type: schema.Types{"array"}, Items: items},
+			"next_cursor": {Type: schema.Types{"string"}, Description: "Cursor of the next page; absent on the last page"},
+		},
+		Required: []string{"items"},
+	}
+}
+
+func jsonContent(s *schema.Schema) map[string]openAPIMediaType {
+	return map[string]openAPIMediaType{"application/json": {Schema: s}}
+}
+
+// handleOpenAPI serves the OpenAPI document
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	data, err := OpenAPI()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
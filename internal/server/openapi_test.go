@@ -0,0 +1,114 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var updateOpenAPI = flag.Bool("update", false, "rewrite docs/openapi.json from the route table")
+
+const publishedOpenAPI = "../../docs/openapi.json"
+
+// TestOpenAPIUpToDate fails when a route or body type changed without the
+// published document being regenerated. Run
+//
+//	go test ./internal/server -run TestOpenAPIUpToDate -update
+//
+// to regenerate it.
+func TestOpenAPIUpToDate(t *testing.T) {
+	generated, err := OpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *updateOpenAPI {
+		if err := os.WriteFile(publishedOpenAPI, generated, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	published, err := os.ReadFile(publishedOpenAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(generated, published) {
+		t.Error("docs/openapi.json is out of date; rerun with -update")
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	data, err := OpenAPI()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		OpenAPI    string                                `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+
+	for _, rt := range apiRoutes() {
+		if _, ok := doc.Paths[rt.path][strings.ToLower(rt.method)]; !ok {
+			t.Errorf("%s %s missing from the document", rt.method, rt.path)
+		}
+	}
+
+	// Every reference resolves to a component
+	for _, m := range regexp.MustCompile(`"#/components/schemas/([^"]+)"`).FindAllSubmatch(data, -1) {
+		if _, ok := doc.Components.Schemas[string(m[1])]; !ok {
+			t.Errorf("dangling reference to %s", m[1])
+		}
+	}
+	for _, name := range []string{"DebugRequest", "DebugResult", "ErrorResponse", "JobEventPage", "SessionDataPage"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("component %s missing", name)
+		}
+	}
+
+	var debug struct {
+		Security  []map[string][]string      `json:"security"`
+		Responses map[string]json.RawMessage `json:"responses"`
+	}
+	if err := json.Unmarshal(doc.Paths["/api/v1/debug"]["post"], &debug); err != nil {
+		t.Fatal(err)
+	}
+	if len(debug.Security) != 1 {
+		t.Errorf("debug security = %v", debug.Security)
+	}
+	for _, status := range []string{"200", "400", "401", "429", "502"} {
+		if _, ok := debug.Responses[status]; !ok {
+			t.Errorf("debug response %s missing", status)
+		}
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	// The document is public even when the API requires a token
+	srv := newTestServer(t, map[string]Role{"secret": RoleAdmin})
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	want, _ := OpenAPI()
+	if !bytes.Equal(rec.Body.Bytes(), want) {
+		t.Error("served document differs from OpenAPI()")
+	}
+}
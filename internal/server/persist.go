@@ -0,0 +1,88 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/dotandev/hintents/internal/audit"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/session"
+)
+
+// track holds the background debug run id as an active session until the
+// returned func is called, so GET /api/v1/sessions/{id} finds it before it
+// is stored. Runs are only tracked when the server has storage.
+func (s *Server) track(id, network, hash string) func() {
+	if s.storage == nil {
+		return func() {}
+	}
+	data := &session.SessionData{ID: id, CreatedAt: time.Now(), Status: "active", Network: network, TxHash: hash}
+	if err := s.sessions.Put(data); err != nil {
+		return func() {}
+	}
+	return func() { _ = s.sessions.Release(id) }
+}
+
+// runningSession returns the tracked run id when its job belongs to the
+// caller
+func (s *Server) runningSession(ctx context.Context, id string) (*session.SessionData, bool) {
+	if _, ok := s.jobFor(ctx, id); !ok {
+		return nil, false
+	}
+	return s.sessions.Get(id)
+}
+
+// record saves a finished debug run as a session and appends it to the
+// audit log under user when the server has shared storage. id names the
+// session; synchronous requests pass "" and get a fresh one. Failures are
+// logged rather than returned, so a storage outage never fails a debug
+// request.
+func (s *Server) record(ctx context.Context, command, user, id, hash string, result *DebugResult, runErr error, started time.Time) {
+	if s.storage == nil {
+		return
+	}
+	if id == "" {
+		id = newJobID()
+	}
+
+	entry := audit.Entry{
+		User:       user,
+		Command:    command,
+		TxHashes:   []string{hash},
+		Outcome:    audit.OutcomeSuccess,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	if runErr != nil {
+		entry.Outcome = audit.OutcomeError
+		entry.Error = runErr.Error()
+	}
+
+	if result != nil {
+		entry.Network = result.Network
+		data := &session.SessionData{
+			ID:            id,
+			Status:        "saved",
+			Network:       result.Network,
+			TxHash:        result.Hash,
+			EnvelopeXdr:   result.EnvelopeXdr,
+			ResultXdr:     result.ResultXdr,
+			ResultMetaXdr: result.ResultMetaXdr,
+		}
+		if result.Simulation != nil {
+			if resp, err := json.Marshal(result.Simulation); err == nil {
+				data.SimResponseJSON = string(resp)
+			}
+		}
+		if err := s.sessionsFor(ctx).Save(ctx, data); err != nil {
+			logger.Logger.Warn("Failed to persist debug session", "id", id, "error", err)
+		}
+	}
+
+	if _, err := s.storage.AppendAudit(ctx, entry); err != nil {
+		logger.Logger.Warn("Failed to append audit entry", "command", command, "error", err)
+	}
+}
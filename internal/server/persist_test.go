@@ -0,0 +1,97 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/audit"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+)
+
+func TestRecordPersistsSessionAndAudit(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.Open(ctx, storage.Config{DSN: filepath.Join(t.TempDir(), "erst.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	srv := newTestServer(t, nil)
+	srv.storage = db
+
+	result := &DebugResult{
+		Hash:       "abc",
+		Network:    "testnet",
+		Status:     "success",
+		Simulation: &simulator.SimulationResponse{Status: "success"},
+	}
+	srv.record(ctx, "serve job", roleUser(RoleAdmin), "job1", "abc", result, nil, time.Now())
+	srv.record(ctx, "serve debug", roleUser(RoleAnalyst), "", "def", nil, errors.New("transaction not found"), time.Now())
+
+	data, err := db.Sessions().Load(ctx, "job1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if data.TxHash != "abc" || data.Network != "testnet" || data.SimResponseJSON == "" {
+		t.Errorf("unexpected session %+v", data)
+	}
+
+	entries, err := db.AuditEntries(ctx, time.Time{})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("AuditEntries = %d, %v", len(entries), err)
+	}
+	if entries[0].User != "role:admin" || entries[0].Outcome != audit.OutcomeSuccess {
+		t.Errorf("unexpected first entry %+v", entries[0])
+	}
+	if entries[1].Outcome != audit.OutcomeError || entries[1].Error != "transaction not found" || entries[1].PrevHash != entries[0].Hash {
+		t.Errorf("unexpected second entry %+v", entries[1])
+	}
+}
+
+func TestRecordWithoutStorage(t *testing.T) {
+	srv := newTestServer(t, nil)
+	// Must not panic
+	srv.record(context.Background(), "serve debug", roleUser(RoleAdmin), "", "abc", &DebugResult{Hash: "abc"}, nil, time.Now())
+}
+
+func TestTrackedRunIsActiveUntilStored(t *testing.T) {
+	ctx := context.Background()
+	db, err := storage.Open(ctx, storage.Config{DSN: filepath.Join(t.TempDir(), "erst.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+
+	srv := newTestServer(t, nil)
+	srv.storage = db
+	job := newJob("abc")
+	srv.jobs.Add(job)
+
+	release := srv.track(job.ID, "testnet", "abc")
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/sessions/"+job.ID, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"active"`) {
+		t.Fatalf("expected the active session, got %d %s", rec.Code, rec.Body)
+	}
+
+	srv.record(ctx, "serve job", roleUser(RoleAdmin), job.ID, "abc", &DebugResult{Hash: "abc", Network: "testnet"}, nil, time.Now())
+	release()
+	if active := srv.sessions.Active(); len(active) != 0 {
+		t.Errorf("expected no active sessions after the run was stored, got %v", active)
+	}
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/sessions/"+job.ID, nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"status":"saved"`) {
+		t.Errorf("expected the stored session, got %d %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// PublicLimits holds the hardening limits applied in public demo mode
+type PublicLimits struct {
+	RequestsPerMinute int
+	Burst             int
+	MaxBodyBytes      int64
+	SimTimeout        time.Duration
+	SimMaxMemoryBytes uint64
+}
+
+// DefaultPublicLimits returns conservative limits suitable for a community-hosted instance
+func DefaultPublicLimits() PublicLimits {
+	return PublicLimits{
+		RequestsPerMinute: 10,
+		Burst:             3,
+		MaxBodyBytes:      4 << 10,
+		SimTimeout:        30 * time.Second,
+		SimMaxMemoryBytes: 256 << 20,
+	}
+}
+
+// limitBody caps request bodies so oversized payloads are rejected before decoding
+func limitBody(maxBytes int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxBytes {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBytes))
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// memoryQuotaReport notes a simulation whose host budget reported more
+// memory than the quota. The quota itself is enforced on the simulator
+// process by the runner's memory limit; the budget counts host allocations
+// only, so exceeding it is reported rather than failed.
+func memoryQuotaReport(resp *simulator.SimulationResponse, maxBytes uint64) string {
+	if maxBytes == 0 || resp == nil || resp.BudgetUsage == nil {
+		return ""
+	}
+	if resp.BudgetUsage.MemoryBytes > maxBytes {
+		return fmt.Sprintf("host budget reported %d bytes of memory, above the %d byte quota", resp.BudgetUsage.MemoryBytes, maxBytes)
+	}
+	return ""
+}
+
+// pruneLoop periodically evicts idle rate limiter buckets until ctx is cancelled
+func (s *Server) pruneLoop(ctx context.Context) {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.limiter.Prune(10 * time.Minute)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net/http"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/storage"
+)
+
+// Queue defaults
+const (
+	DefaultMinWorkers  = 1
+	DefaultQueueSize   = 100
+	DefaultIdleTimeout = time.Minute
+
+	// initialJobEstimate seeds the average job duration used for
+	// Retry-After before any job has finished
+	initialJobEstimate = 5 * time.Second
+	maxRetryAfter      = 5 * time.Minute
+)
+
+var (
+	// ErrQueueFull is returned when every worker is busy and the queue is
+	// at capacity
+	ErrQueueFull = errors.New("debug queue is full")
+	// ErrTenantBusy is returned when a tenant already has its quota of
+	// queued and running jobs
+	ErrTenantBusy = errors.New("too many concurrent debug jobs for this caller")
+)
+
+// PoolConfig sizes the simulator worker pool
+type PoolConfig struct {
+	// MinWorkers are always running; up to MaxWorkers are started while
+	// jobs are waiting and stop again after IdleTimeout without work
+	MinWorkers int
+	MaxWorkers int
+	// QueueSize is how many jobs may wait for a worker before new ones are
+	// rejected
+	QueueSize int
+	// TenantLimit caps the queued and running jobs of one tenant; 0 means
+	// no cap
+	TenantLimit int
+	IdleTimeout time.Duration
+}
+
+// DefaultPoolConfig returns one permanent worker scaling up to one per CPU
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MinWorkers:  DefaultMinWorkers,
+		MaxWorkers:  runtime.NumCPU(),
+		QueueSize:   DefaultQueueSize,
+		IdleTimeout: DefaultIdleTimeout,
+	}
+}
+
+// PoolStats is a snapshot of the worker pool
+type PoolStats struct {
+	Workers int `json:"workers"`
+	Idle    int `json:"idle"`
+	Queued  int `json:"queued"`
+}
+
+type poolTask struct {
+	tenant string
+	run    func()
+}
+
+// WorkerPool runs debug jobs on a bounded, autoscaling set of workers with
+// a bounded queue and per-tenant quotas
+type WorkerPool struct {
+	cfg   PoolConfig
+	tasks chan poolTask
+
+	mu      sync.Mutex
+	workers int
+	idle    int
+	tenants map[string]int
+	// avg is the moving average job duration behind Retry-After
+	avg time.Duration
+}
+
+// NewWorkerPool starts the permanent workers of cfg
+func NewWorkerPool(cfg PoolConfig) *WorkerPool {
+	if cfg.MaxWorkers < 1 {
+		cfg.MaxWorkers = runtime.NumCPU()
+	}
+	if cfg.MinWorkers < 0 {
+		cfg.MinWorkers = 0
+	}
+	if cfg.MinWorkers > cfg.MaxWorkers {
+		cfg.MinWorkers = cfg.MaxWorkers
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = DefaultIdleTimeout
+	}
+
+	p := &WorkerPool{
+		cfg:     cfg,
+		tasks:   make(chan poolTask, cfg.QueueSize),
+		tenants: make(map[string]int),
+		avg:     initialJobEstimate,
+	}
+	p.mu.Lock()
+	for i := 0; i < cfg.MinWorkers; i++ {
+		p.workers++
+		go p.work(true)
+	}
+	p.mu.Unlock()
+	return p
+}
+
+// Submit queues run for tenant, starting another worker when more jobs are
+// waiting than workers are idle. It fails at once when the tenant is over
+// its quota or the queue is full.
+func (p *WorkerPool) Submit(tenant string, run func()) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cfg.TenantLimit > 0 && p.tenants[tenant] >= p.cfg.TenantLimit {
+		return ErrTenantBusy
+	}
+	select {
+	case p.tasks <- poolTask{tenant: tenant, run: run}:
+	default:
+		return ErrQueueFull
+	}
+	p.tenants[tenant]++
+	if len(p.tasks) > p.idle && p.workers < p.cfg.MaxWorkers {
+		p.workers++
+		go p.work(false)
+	}
+	return nil
+}
+
+// work runs tasks until it has been idle for IdleTimeout. Permanent workers
+// never stop.
+func (p *WorkerPool) work(permanent bool) {
+	var timeout <-chan time.Time
+	for {
+		if !permanent {
+			timeout = time.After(p.cfg.IdleTimeout)
+		}
+		p.mu.Lock()
+		p.idle++
+		p.mu.Unlock()
+
+		select {
+		case t := <-p.tasks:
+			p.mu.Lock()
+			p.idle--
+			p.mu.Unlock()
+			p.run(t)
+		case <-timeout:
+			p.mu.Lock()
+			p.idle--
+			if len(p.tasks) == 0 {
+				p.workers--
+				p.mu.Unlock()
+				return
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+func (p *WorkerPool) run(t poolTask) {
+	start := time.Now()
+	defer func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.tenants[t.tenant]--; p.tenants[t.tenant] <= 0 {
+			delete(p.tenants, t.tenant)
+		}
+		p.avg = (4*p.avg + time.Since(start)) / 5
+	}()
+	t.run()
+}
+
+// Stats returns the current number of workers, idle workers and queued jobs
+func (p *WorkerPool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return PoolStats{Workers: p.workers, Idle: p.idle, Queued: len(p.tasks)}
+}
+
+// RetryAfter estimates when a rejected caller should try again: the time
+// for the current workers to clear the queue
+func (p *WorkerPool) RetryAfter() time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	workers := max(p.workers, 1)
+	wait := p.avg * time.Duration(len(p.tasks)+1) / time.Duration(workers)
+	return min(max(wait, time.Second), maxRetryAfter)
+}
+
+// isBusy reports whether err is a rejected pool submission
+func isBusy(err error) bool {
+	return errors.Is(err, ErrQueueFull) || errors.Is(err, ErrTenantBusy)
+}
+
+// writeBusy answers a rejected submission with 429 and a Retry-After header
+func (p *WorkerPool) writeBusy(w http.ResponseWriter, err error) {
+	secs := int(math.Ceil(p.RetryAfter().Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(secs))
+	writeError(w, http.StatusTooManyRequests, err.Error())
+}
+
+// tenantOf identifies the caller that quotas apply to: the API token when
+// authentication is enabled, otherwise the client IP. Tokens are hashed so
+// they never appear in logs or stats.
+func (s *Server) tenantOf(r *http.Request) string {
+	if key := apiKeyFromContext(r.Context()); key != nil {
+		return keyPoolTenant(key)
+	}
+	return s.tenant(bearerToken(r), clientIP(r))
+}
+
+// keyPoolTenant makes every key of a tenant share its quota
+func keyPoolTenant(key *storage.APIKey) string {
+	return "tenant:" + key.Tenant
+}
+
+func (s *Server) tenant(token, ip string) string {
+	if s.authEnabled() {
+		sum := sha256.Sum256([]byte(token))
+		return "token:" + hex.EncodeToString(sum[:6])
+	}
+	return "ip:" + ip
+}
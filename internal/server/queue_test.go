@@ -0,0 +1,136 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockPool fills n worker slots for tenant until the returned func is called
+func blockPool(t *testing.T, p *WorkerPool, tenant string, n int) func() {
+	t.Helper()
+	release := make(chan struct{})
+	var started sync.WaitGroup
+	for i := 0; i < n; i++ {
+		started.Add(1)
+		if err := p.Submit(tenant, func() {
+			started.Done()
+			<-release
+		}); err != nil {
+			t.Fatalf("Submit: %v", err)
+		}
+	}
+	started.Wait()
+	var once sync.Once
+	return func() { once.Do(func() { close(release) }) }
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWorkerPoolScales(t *testing.T) {
+	p := NewWorkerPool(PoolConfig{MinWorkers: 1, MaxWorkers: 3, QueueSize: 10, IdleTimeout: 50 * time.Millisecond})
+	if got := p.Stats().Workers; got != 1 {
+		t.Fatalf("expected 1 permanent worker, got %d", got)
+	}
+
+	release := blockPool(t, p, "a", 3)
+	if got := p.Stats().Workers; got != 3 {
+		t.Errorf("expected the pool to scale to 3 workers, got %d", got)
+	}
+	release()
+
+	// Extra workers stop once idle
+	waitFor(t, func() bool { return p.Stats().Workers == 1 })
+}
+
+func TestWorkerPoolQueueFull(t *testing.T) {
+	p := NewWorkerPool(PoolConfig{MinWorkers: 1, MaxWorkers: 1, QueueSize: 1})
+	release := blockPool(t, p, "a", 1)
+	defer release()
+
+	if err := p.Submit("b", func() {}); err != nil {
+		t.Fatalf("expected the job to queue, got %v", err)
+	}
+	if err := p.Submit("c", func() {}); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+	if d := p.RetryAfter(); d < time.Second {
+		t.Errorf("RetryAfter = %s", d)
+	}
+}
+
+func TestWorkerPoolTenantLimit(t *testing.T) {
+	p := NewWorkerPool(PoolConfig{MinWorkers: 1, MaxWorkers: 4, QueueSize: 10, TenantLimit: 2})
+	release := blockPool(t, p, "ui", 2)
+	defer release()
+
+	if err := p.Submit("ui", func() {}); !errors.Is(err, ErrTenantBusy) {
+		t.Errorf("expected ErrTenantBusy for the busy tenant, got %v", err)
+	}
+	done := make(chan struct{})
+	if err := p.Submit("ci", func() { close(done) }); err != nil {
+		t.Fatalf("expected another tenant to be admitted, got %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the other tenant's job did not run")
+	}
+
+	release()
+	waitFor(t, func() bool { return p.Submit("ui", func() {}) == nil })
+}
+
+func TestCreateJobBackpressure(t *testing.T) {
+	srv := newTestServer(t, nil)
+	srv.pool = NewWorkerPool(PoolConfig{MinWorkers: 1, MaxWorkers: 1, QueueSize: 1, TenantLimit: 1})
+
+	// httptest requests come from 192.0.2.1
+	release := blockPool(t, srv.pool, "ip:192.0.2.1", 1)
+	defer release()
+
+	body := `{"hash": "` + strings.Repeat("ab", 32) + `"}`
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/jobs", strings.NewReader(body)))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/debug", strings.NewReader(body)))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected synchronous debug to be rejected too, got %d", rec.Code)
+	}
+}
+
+func TestTenantOf(t *testing.T) {
+	srv := newTestServer(t, map[string]Role{"ui-token": RoleAnalyst, "ci-token": RoleDeveloper})
+	req := func(token string) *http.Request {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		return r
+	}
+	ui, ci := srv.tenantOf(req("ui-token")), srv.tenantOf(req("ci-token"))
+	if ui == ci || !strings.HasPrefix(ui, "token:") || strings.Contains(ui, "ui-token") {
+		t.Errorf("unexpected tenants %q %q", ui, ci)
+	}
+}
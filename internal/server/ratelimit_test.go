@@ -0,0 +1,113 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	stellarrpc "github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	l := NewRateLimiter(60, 2)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := l.Allow("1.2.3.4"); !ok {
+			t.Fatalf("request %d should be within burst", i)
+		}
+	}
+
+	ok, wait := l.Allow("1.2.3.4")
+	if ok {
+		t.Fatal("expected third request to be limited")
+	}
+	if wait <= 0 || wait > time.Second {
+		t.Errorf("unexpected wait %s", wait)
+	}
+
+	if ok, _ := l.Allow("5.6.7.8"); !ok {
+		t.Error("other clients should have their own bucket")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.Allow("1.2.3.4"); !ok {
+		t.Error("expected a token to be refilled after one second")
+	}
+
+	now = now.Add(time.Hour)
+	l.Prune(time.Minute)
+	if len(l.buckets) != 0 {
+		t.Errorf("expected idle buckets to be pruned, have %d", len(l.buckets))
+	}
+}
+
+func TestPublicMode_LimitsRequests(t *testing.T) {
+	t.Setenv("ERST_SIM_PATH", "/bin/echo")
+
+	limits := DefaultPublicLimits()
+	limits.RequestsPerMinute = 1
+	limits.Burst = 1
+	limits.MaxBodyBytes = 16
+
+	srv, err := NewServer(Config{
+		Network: string(stellarrpc.Testnet),
+		Public:  true,
+		Limits:  limits,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"hash":"` + strings.Repeat("a", 64) + `"}`)
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/debug", body))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413 for oversized body, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("POST", "/api/v1/debug", strings.NewReader(`{}`)))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 once the bucket is empty, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+}
+
+func TestMemoryQuotaReport(t *testing.T) {
+	resp := &simulator.SimulationResponse{BudgetUsage: &simulator.BudgetUsage{MemoryBytes: 2048}}
+	if note := memoryQuotaReport(resp, 1024); note == "" {
+		t.Error("expected quota breach to be reported")
+	}
+	if note := memoryQuotaReport(resp, 4096); note != "" {
+		t.Errorf("unexpected report: %s", note)
+	}
+	if note := memoryQuotaReport(&simulator.SimulationResponse{}, 1); note != "" {
+		t.Errorf("responses without budget data should not be reported: %s", note)
+	}
+}
+
+func TestPublicModeLimitsSimulatorMemory(t *testing.T) {
+	t.Setenv("ERST_SIM_PATH", "/bin/echo")
+	limits := DefaultPublicLimits()
+	srv, err := NewServer(Config{Network: "testnet", Public: true, Limits: limits})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	runner, ok := srv.runner.(*simulator.Runner)
+	if !ok {
+		t.Fatalf("unexpected runner type %T", srv.runner)
+	}
+	if runner.Limits.MemoryBytes != limits.SimMaxMemoryBytes {
+		t.Errorf("expected the simulator process limited to %d bytes, got %d", limits.SimMaxMemoryBytes, runner.Limits.MemoryBytes)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+)
+
+// Policy controls what a role is allowed to see in rendered output
+type Policy struct {
+	// RedactAddresses masks G.../C.../M... strkeys, keeping a short prefix and suffix
+	RedactAddresses bool `json:"redact_addresses"`
+	// HiddenFields lists JSON object keys removed from responses
+	HiddenFields []string `json:"hidden_fields,omitempty"`
+}
+
+// rawFields hold raw XDR, or simulator requests and responses stored as
+// JSON text. A policy cannot see into them, so only admins get them.
+var rawFields = []string{"envelope_xdr", "result_xdr", "result_meta_xdr", "sim_request_json", "sim_response_json"}
+
+// DefaultPolicies returns the built-in policy for each role
+func DefaultPolicies() map[Role]Policy {
+	return map[Role]Policy{
+		RoleAdmin:     {},
+		RoleDeveloper: {HiddenFields: rawFields},
+		RoleAnalyst:   {RedactAddresses: true, HiddenFields: append([]string{"logs"}, rawFields...)},
+	}
+}
+
+// LoadPolicies reads a JSON file of role policies and merges it over the defaults.
+// The file maps role names to Policy objects, e.g. {"analyst": {"redact_addresses": true}}.
+func LoadPolicies(path string) (map[Role]Policy, error) {
+	policies := DefaultPolicies()
+	if path == "" {
+		return policies, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %w", err)
+	}
+
+	var raw map[string]Policy
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file: %w", err)
+	}
+
+	for name, policy := range raw {
+		role, err := ParseRole(name)
+		if err != nil {
+			return nil, err
+		}
+		policies[role] = policy
+	}
+
+	return policies, nil
+}
+
+// strkeyPattern matches Stellar account (G), contract (C) and muxed (M) addresses
+var strkeyPattern = regexp.MustCompile(`\b[GC][A-Z2-7]{55}\b|\bM[A-Z2-7]{68}\b`)
+
+// RedactAddress masks a single strkey, e.g. GABC...WXYZ
+func RedactAddress(addr string) string {
+	if len(addr) <= 8 {
+		return addr
+	}
+	return addr[:4] + "..." + addr[len(addr)-4:]
+}
+
+// Hides reports whether the policy removes field from responses
+func (p Policy) Hides(field string) bool {
+	return slices.Contains(p.HiddenFields, field)
+}
+
+// showsEvent reports whether a job event may be sent under the policy. Log
+// events carry simulator log lines, so they are withheld with the logs field.
+func (p Policy) showsEvent(ev JobEvent) bool {
+	return ev.Type != EventLog || !p.Hides("logs")
+}
+
+// Apply returns a copy of v with the policy enforced. v must be JSON-serializable.
+func (p Policy) Apply(v interface{}) (interface{}, error) {
+	if !p.RedactAddresses && len(p.HiddenFields) == 0 {
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	hidden := make(map[string]bool, len(p.HiddenFields))
+	for _, f := range p.HiddenFields {
+		hidden[f] = true
+	}
+
+	return p.walk(generic, hidden), nil
+}
+
+func (p Policy) walk(v interface{}, hidden map[string]bool) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			if hidden[k] {
+				delete(val, k)
+				continue
+			}
+			val[k] = p.walk(child, hidden)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = p.walk(child, hidden)
+		}
+		return val
+	case string:
+		if p.RedactAddresses {
+			return strkeyPattern.ReplaceAllStringFunc(val, RedactAddress)
+		}
+		return val
+	default:
+		return val
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"net/http"
+
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/storage"
+)
+
+// apiRoute describes one endpoint of the REST API. The table in apiRoutes
+// both registers the handlers and generates the OpenAPI document, so the
+// two cannot drift apart.
+type apiRoute struct {
+	id      string
+	method  string
+	path    string
+	summary string
+	handler func(*Server, http.ResponseWriter, *http.Request)
+	// public routes skip authentication, rate limiting and the body cap
+	public bool
+	// scope is the API key scope the route needs
+	scope string
+
+	query   []queryParam
+	headers []queryParam
+	request interface{}
+	// status and response describe success; page wraps the response items
+	// in a Page
+	status   int
+	response interface{}
+	page     bool
+	// upgrade marks a WebSocket endpoint streaming response values
+	upgrade bool
+	// errors are answered with an ErrorResponse; other holds further
+	// statuses with their own body
+	errors []int
+	other  map[int]interface{}
+}
+
+// queryParam is a query string or request header parameter
+type queryParam struct {
+	name        string
+	description string
+	integer     bool
+}
+
+var (
+	fieldsParam = queryParam{"fields", "Comma-separated JSON fields to return; dots select nested fields (simulation.status)", false}
+	limitParam  = queryParam{"limit", "Page size, 1 to 500 (default 50)", true}
+	cursorParam = queryParam{"cursor", "next_cursor of the previous page", false}
+)
+
+func apiRoutes() []apiRoute {
+	return []apiRoute{
+		{
+			id:       "health",
+			method:   "GET",
+			path:     "/health",
+			summary:  "Report that the server is up",
+			handler:  (*Server).handleHealth,
+			public:   true,
+			status:   http.StatusOK,
+			response: map[string]string{},
+		},
+		{
+			id:       "healthz",
+			method:   "GET",
+			path:     "/healthz",
+			summary:  "Liveness probe",
+			handler:  (*Server).handleHealthz,
+			public:   true,
+			status:   http.StatusOK,
+			response: map[string]string{},
+		},
+		{
+			id:       "readyz",
+			method:   "GET",
+			path:     "/readyz",
+			summary:  "Readiness probe: simulator, RPC and database checks",
+			handler:  (*Server).handleReadyz,
+			public:   true,
+			status:   http.StatusOK,
+			response: Readiness{},
+			other:    map[int]interface{}{http.StatusServiceUnavailable: Readiness{}},
+		},
+		{
+			id:       "openapi",
+			method:   "GET",
+			path:     "/openapi.json",
+			summary:  "This OpenAPI document",
+			handler:  (*Server).handleOpenAPI,
+			public:   true,
+			status:   http.StatusOK,
+			response: map[string]interface{}{},
+		},
+		{
+			id:      "debug",
+			method:  "POST",
+			path:    "/api/v1/debug",
+			summary: "Replay and analyze a transaction, waiting for the result",
+			handler: (*Server).handleDebug,
+			scope:   ScopeDebug,
+			query:   []queryParam{fieldsParam},
+			headers: []queryParam{
+				{IdempotencyHeader, "Client-chosen key; retries with the same key get the in-flight or completed result, marked with Idempotent-Replayed: true, for 24 hours", false},
+			},
+			request:  DebugRequest{},
+			status:   http.StatusOK,
+			response: DebugResult{},
+			errors:   []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusTooManyRequests, http.StatusBadGateway},
+		},
+		{
+			id:       "createJob",
+			method:   "POST",
+			path:     "/api/v1/jobs",
+			summary:  "Start a debug run in the background",
+			handler:  (*Server).handleCreateJob,
+			scope:    ScopeDebug,
+			request:  DebugRequest{},
+			status:   http.StatusAccepted,
+			response: JobCreated{},
+			errors:   []int{http.StatusBadRequest, http.StatusTooManyRequests},
+		},
+		{
+			id:       "getJob",
+			method:   "GET",
+			path:     "/api/v1/jobs/{id}",
+			summary:  "Poll the status of a debug job",
+			handler:  (*Server).handleJobStatus,
+			scope:    ScopeJobs,
+			query:    []queryParam{fieldsParam},
+			status:   http.StatusOK,
+			response: JobStatus{},
+			errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			id:       "streamJob",
+			method:   "GET",
+			path:     "/api/v1/jobs/{id}/stream",
+			summary:  "Stream the events of a debug job over a WebSocket",
+			handler:  (*Server).handleJobStream,
+			scope:    ScopeJobs,
+			status:   http.StatusSwitchingProtocols,
+			response: JobEvent{},
+			upgrade:  true,
+			errors:   []int{http.StatusNotFound},
+		},
+		{
+			id:       "listJobEvents",
+			method:   "GET",
+			path:     "/api/v1/jobs/{id}/events",
+			summary:  "List the recorded events of a debug job, oldest first",
+			handler:  (*Server).handleJobEvents,
+			scope:    ScopeJobs,
+			query:    []queryParam{fieldsParam, limitParam, cursorParam},
+			status:   http.StatusOK,
+			response: JobEvent{},
+			page:     true,
+			errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			id:      "listSessions",
+			method:  "GET",
+			path:    "/api/v1/sessions",
+			summary: "List stored debug sessions, newest first",
+			handler: (*Server).handleListSessions,
+			scope:   ScopeSessions,
+			query: []queryParam{
+				fieldsParam, limitParam, cursorParam,
+				{"anchor", "Only sessions attached to this anchor (case-insensitive)", false},
+				{"sep_flow", "Only sessions of this SEP flow", false},
+				{"customer_ref", "Only sessions with this customer reference", false},
+			},
+			status:   http.StatusOK,
+			response: session.SessionData{},
+			page:     true,
+			errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			id:       "getSession",
+			method:   "GET",
+			path:     "/api/v1/sessions/{id}",
+			summary:  "Fetch one stored debug session, or the active session of a job in flight",
+			handler:  (*Server).handleGetSession,
+			scope:    ScopeSessions,
+			query:    []queryParam{fieldsParam},
+			status:   http.StatusOK,
+			response: session.SessionData{},
+			errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			id:       "createKey",
+			method:   "POST",
+			path:     "/api/v1/keys",
+			summary:  "Create a tenant API key; the secret is only returned here",
+			handler:  (*Server).handleCreateKey,
+			scope:    ScopeKeys,
+			request:  CreateKeyRequest{},
+			status:   http.StatusCreated,
+			response: CreatedKey{},
+			errors:   []int{http.StatusBadRequest, http.StatusNotFound},
+		},
+		{
+			id:       "listKeys",
+			method:   "GET",
+			path:     "/api/v1/keys",
+			summary:  "List API keys, of one tenant with ?tenant=",
+			handler:  (*Server).handleListKeys,
+			scope:    ScopeKeys,
+			query:    []queryParam{fieldsParam, {"tenant", "Only keys of this tenant (admin tokens)", false}},
+			status:   http.StatusOK,
+			response: []storage.APIKey{},
+			errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			id:       "revokeKey",
+			method:   "DELETE",
+			path:     "/api/v1/keys/{id}",
+			summary:  "Revoke an API key",
+			handler:  (*Server).handleRevokeKey,
+			scope:    ScopeKeys,
+			query:    []queryParam{fieldsParam},
+			status:   http.StatusOK,
+			response: storage.APIKey{},
+			errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+		},
+		{
+			id:       "getKeyUsage",
+			method:   "GET",
+			path:     "/api/v1/keys/{id}/usage",
+			summary:  "Daily requests, simulations and RPC calls of an API key",
+			handler:  (*Server).handleKeyUsage,
+			scope:    ScopeKeys,
+			query:    []queryParam{fieldsParam, {"since", "First day to report, as 2006-01-02 (default 30 days ago)", false}},
+			status:   http.StatusOK,
+			response: KeyUsage{},
+			errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+		},
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// DefaultSandboxLimits returns the per-request limits applied to every
+// simulator process in serve mode, so one runaway simulation cannot exhaust
+// the host
+func DefaultSandboxLimits() simulator.Limits {
+	return simulator.Limits{
+		CPUTime:     60 * time.Second,
+		MemoryBytes: 2 << 30,
+		OutputBytes: 64 << 20,
+	}
+}
+
+// limitExceeded returns the resource limit err reports, if any
+func limitExceeded(err error) *simulator.LimitError {
+	var le *simulator.LimitError
+	if errors.As(err, &le) {
+		return le
+	}
+	return nil
+}
+
+// simulationFailure returns the message API clients see for a runner error.
+// Limit and simulation errors describe the transaction; other failures may
+// carry simulator stderr and file paths, so they are only logged.
+func simulationFailure(err error) string {
+	var simErr *simulator.SimulationError
+	if limitExceeded(err) != nil || errors.As(err, &simErr) {
+		return err.Error()
+	}
+	logger.Logger.Error("Simulator failed", "error", err)
+	return "simulator execution failed; see the server log"
+}
@@ -0,0 +1,490 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/session"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+	"google.golang.org/grpc"
+)
+
+// Config holds serve-mode configuration
+type Config struct {
+	Network  string
+	RPCURL   string
+	Tokens   map[string]Role
+	Policies map[Role]Policy
+
+	// Public enables the hardened community demo mode
+	Public bool
+	Limits PublicLimits
+
+	// Sandbox caps the CPU time, memory and output of each simulator
+	// process. Stricter limits from the environment or config file win.
+	Sandbox simulator.Limits
+
+	// Slack enables the /slack/commands slash command endpoint
+	Slack SlackConfig
+
+	// Storage, when set, keeps every debug run as a session and an audit
+	// entry in a database shared by all replicas. It is not available in
+	// public mode.
+	Storage *storage.DB
+
+	// Sessions holds background debug runs as active sessions until they
+	// are stored. Nil gives the server a manager of its own.
+	Sessions *session.Manager
+
+	// DrainDelay and DrainTimeout control shutdown: /readyz fails for
+	// DrainDelay before the listener closes, then in-flight requests and
+	// background jobs get up to DrainTimeout to finish
+	DrainDelay   time.Duration
+	DrainTimeout time.Duration
+
+	// Pool sizes the worker pool debug runs queue for. The zero value
+	// uses DefaultPoolConfig.
+	Pool PoolConfig
+
+	// GRPCPort, when set, also serves the API over gRPC on that port. It
+	// is not available in public mode.
+	GRPCPort string
+
+	// APIKeys accepts the tenant API keys kept in Storage as bearer tokens
+	// and enables the key management endpoints. Callers without a --token
+	// or a key are rejected.
+	APIKeys bool
+}
+
+// Server exposes erst functionality over a REST API
+type Server struct {
+	rpcClient *rpc.Client
+	network   string
+	clientsMu sync.Mutex
+	clients   map[string]*rpc.Client
+	runner    simulator.RunnerInterface
+	auth      *Authenticator
+	policies  map[Role]Policy
+	mux       *http.ServeMux
+	jobs      *JobStore
+	// idempotency replays POST /api/v1/debug results by Idempotency-Key
+	idempotency *IdempotencyStore
+
+	public  bool
+	limits  PublicLimits
+	limiter *RateLimiter
+
+	slack SlackConfig
+
+	storage  *storage.DB
+	sessions *session.Manager
+
+	drainDelay   time.Duration
+	drainTimeout time.Duration
+	draining     atomic.Bool
+	// background tracks debug jobs running after their request returned
+	background sync.WaitGroup
+	pool       *WorkerPool
+
+	grpcPort string
+	grpc     *grpc.Server
+
+	// apiKeys accepts the API keys kept in storage
+	apiKeys bool
+	keys    keyCache
+}
+
+type contextKey string
+
+const roleContextKey contextKey = "erst-role"
+
+// NewServer creates a new REST server
+func NewServer(config Config) (*Server, error) {
+	if config.Public && config.GRPCPort != "" {
+		return nil, fmt.Errorf("the gRPC API is not available in public mode")
+	}
+	if config.Public && config.Storage != nil {
+		return nil, fmt.Errorf("storage is not available in public mode")
+	}
+	if config.APIKeys && config.Storage == nil {
+		return nil, fmt.Errorf("API keys need storage")
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithNetwork(rpc.Network(config.Network)),
+	}
+
+	if config.RPCURL != "" {
+		opts = append(opts, rpc.WithHorizonURL(config.RPCURL))
+	}
+
+	client, err := rpc.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RPC client: %w", err)
+	}
+
+	runner, err := simulator.NewRunner("", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create simulator: %w", err)
+	}
+
+	runner.Limits = runner.Limits.Tighten(config.Sandbox)
+	// Crash bundles hold request data and their paths would reach clients
+	runner.NoCrashReports = true
+
+	if config.Public {
+		// Public instances must not write fetched ledger state to disk
+		client.CacheEnabled = false
+		runner.Timeout = config.Limits.SimTimeout
+		// The memory quota is enforced on the simulator process
+		runner.Limits = runner.Limits.Tighten(simulator.Limits{MemoryBytes: config.Limits.SimMaxMemoryBytes})
+	}
+
+	policies := config.Policies
+	if policies == nil {
+		policies = DefaultPolicies()
+	}
+
+	s := &Server{
+		rpcClient:   client,
+		network:     config.Network,
+		clients:     map[string]*rpc.Client{config.Network: client},
+		runner:      runner,
+		auth:        NewAuthenticator(config.Tokens),
+		policies:    policies,
+		mux:         http.NewServeMux(),
+		jobs:        NewJobStore(),
+		idempotency: NewIdempotencyStore(idempotencyRetention, idempotencyCapacity),
+		public:      config.Public,
+		limits:      config.Limits,
+		slack:       config.Slack,
+		storage:     config.Storage,
+		sessions:    config.Sessions,
+
+		drainDelay:   config.DrainDelay,
+		drainTimeout: config.DrainTimeout,
+		grpcPort:     config.GRPCPort,
+		apiKeys:      config.APIKeys,
+		keys:         keyCache{entries: make(map[string]cachedKey)},
+	}
+	if s.sessions == nil {
+		s.sessions = session.NewManager("")
+	}
+	if config.Pool == (PoolConfig{}) {
+		config.Pool = DefaultPoolConfig()
+	}
+	s.pool = NewWorkerPool(config.Pool)
+	if s.drainTimeout <= 0 {
+		s.drainTimeout = DefaultDrainTimeout
+	}
+	if config.Public {
+		s.limiter = NewRateLimiter(config.Limits.RequestsPerMinute, config.Limits.Burst)
+	}
+	s.routes()
+
+	return s, nil
+}
+
+func (s *Server) routes() {
+	s.mux.Handle("GET /", uiHandler())
+	for _, rt := range apiRoutes() {
+		handler := rt.handler
+		var h http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handler(s, w, r)
+		})
+		if !rt.public {
+			h = s.protect(requireScope(rt.scope, h))
+		}
+		s.mux.Handle(rt.method+" "+rt.path, h)
+	}
+
+	// Slack requests carry a request signature instead of a bearer token
+	if s.slack.SigningSecret != "" {
+		s.mux.HandleFunc("POST /slack/commands", s.handleSlackCommand)
+		s.mux.HandleFunc("GET /slack/reports/{id}", s.handleSlackReport)
+	}
+}
+
+// protect wraps an API handler with authentication and, in public mode, the
+// rate limiter and request size cap
+func (s *Server) protect(h http.Handler) http.Handler {
+	h = s.requireAuth(h)
+	if s.public {
+		if s.limits.MaxBodyBytes > 0 {
+			h = limitBody(s.limits.MaxBodyBytes, h)
+		}
+		h = s.limiter.Middleware(h)
+	}
+	return h
+}
+
+// Handler returns the HTTP handler for the API
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// requireAuth resolves the caller's role, and API key if one was used, and
+// stores them on the request context
+func (s *Server) requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		role, key, ok := s.authenticate(r.Context(), bearerToken(r))
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		if s.public && !s.authEnabled() {
+			role = RoleDeveloper
+		}
+		ctx := context.WithValue(r.Context(), roleContextKey, role)
+		if key != nil {
+			ctx = context.WithValue(ctx, apiKeyContextKey, key)
+			s.countUsage(ctx, storage.Usage{Requests: 1})
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func roleFromContext(ctx context.Context) Role {
+	if role, ok := ctx.Value(roleContextKey).(Role); ok {
+		return role
+	}
+	return RoleAnalyst
+}
+
+// roleUser names API callers in the audit log. Tokens identify a role, not
+// a person.
+func roleUser(role Role) string {
+	return "role:" + string(role)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleDebug(w http.ResponseWriter, r *http.Request) {
+	var req DebugRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	client, err := s.debugClient(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	tenant := s.tenantOf(r)
+	var result *DebugResult
+	if key := r.Header.Get(IdempotencyHeader); key != "" {
+		if !validIdempotencyKey(key) {
+			writeError(w, http.StatusBadRequest, "Idempotency-Key must be 1 to 255 printable ASCII characters")
+			return
+		}
+		// The run outlives a client that times out, so its retry can pick
+		// up the result
+		ctx := context.WithoutCancel(r.Context())
+		var replayed bool
+		result, replayed, err = s.idempotency.Do(r.Context(), tenant, key, string(client.Network)+" "+req.Hash, func() (*DebugResult, error) {
+			return s.debugNow(ctx, tenant, "serve debug", client, req.Hash)
+		})
+		if replayed {
+			w.Header().Set(ReplayedHeader, "true")
+		}
+	} else {
+		result, err = s.debugNow(r.Context(), tenant, "serve debug", client, req.Hash)
+	}
+	if errors.Is(err, ErrIdempotencyMismatch) {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if isBusy(err) {
+		s.pool.writeBusy(w, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	s.render(w, r, http.StatusOK, result)
+}
+
+// debugClient validates a debug request and returns the RPC client for its
+// network
+func (s *Server) debugClient(req DebugRequest) (*rpc.Client, error) {
+	if err := rpc.ValidateTransactionHash(req.Hash); err != nil {
+		return nil, err
+	}
+	return s.clientFor(req.Network)
+}
+
+// debugNow runs a debug on the worker pool and waits for the result. A
+// caller that gives up while queued frees its slot without simulating. A
+// rejected submission returns the pool's error; see isBusy.
+func (s *Server) debugNow(ctx context.Context, tenant, command string, client *rpc.Client, hash string) (*DebugResult, error) {
+	var result *DebugResult
+	var runErr error
+	done := make(chan struct{})
+	err := s.pool.Submit(tenant, func() {
+		defer close(done)
+		if runErr = ctx.Err(); runErr != nil {
+			return
+		}
+		started := time.Now()
+		result, runErr = s.runDebug(ctx, client, hash, nil)
+		s.record(ctx, command, callerUser(ctx), "", hash, result, runErr, started)
+	})
+	if err != nil {
+		return nil, err
+	}
+	<-done
+	return result, runErr
+}
+
+// clientFor returns the RPC client for a network, defaulting to the configured one.
+// Clients for other public networks are created lazily and reused.
+func (s *Server) clientFor(network string) (*rpc.Client, error) {
+	if network == "" {
+		network = s.network
+	}
+
+	switch rpc.Network(network) {
+	case rpc.Testnet, rpc.Mainnet, rpc.Futurenet:
+	default:
+		return nil, fmt.Errorf("invalid network: %s. Must be one of: testnet, mainnet, futurenet", network)
+	}
+
+	s.clientsMu.Lock()
+	defer s.clientsMu.Unlock()
+
+	if client, ok := s.clients[network]; ok {
+		return client, nil
+	}
+
+	client, err := rpc.NewClient(rpc.WithNetwork(rpc.Network(network)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RPC client: %w", err)
+	}
+	client.CacheEnabled = s.rpcClient.CacheEnabled
+	s.clients[network] = client
+	return client, nil
+}
+
+// policyFor returns the output policy for a role, falling back to the most
+// restrictive default
+func (s *Server) policyFor(role Role) Policy {
+	if policy, ok := s.policies[role]; ok {
+		return policy
+	}
+	return DefaultPolicies()[RoleAnalyst]
+}
+
+// render writes v as JSON after enforcing the caller's role policy
+func (s *Server) render(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	fields, err := parseFields(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	out, err := s.policyFor(roleFromContext(r.Context())).Apply(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if len(fields) > 0 {
+		if out, err = toGeneric(out); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		_, isPage := v.(Page)
+		out = project(out, fields, isPage)
+	}
+
+	writeJSON(w, status, out)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Logger.Warn("Failed to write response", "error", err)
+	}
+}
+
+// ErrorResponse is the body of every API error
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, ErrorResponse{Error: msg})
+}
+
+// Start starts the REST server and blocks until ctx is cancelled. It fails
+// at once when a port cannot be bound, and returns any later serve error.
+func (s *Server) Start(ctx context.Context, port string) error {
+	srv := &http.Server{
+		Addr:    ":" + port,
+		Handler: s.Handler(),
+	}
+	if s.public {
+		srv.ReadHeaderTimeout = 10 * time.Second
+		srv.ReadTimeout = 30 * time.Second
+	}
+
+	lis, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on port %s: %w", port, err)
+	}
+	logger.Logger.Info("Starting REST server", "port", port, "auth", s.authEnabled(), "public", s.public)
+
+	if s.limiter != nil {
+		go s.pruneLoop(ctx)
+	}
+
+	if s.grpcPort != "" {
+		grpcLis, err := net.Listen("tcp", ":"+s.grpcPort)
+		if err != nil {
+			lis.Close()
+			return fmt.Errorf("failed to listen for gRPC: %w", err)
+		}
+		s.grpc = s.GRPCServer()
+		logger.Logger.Info("Starting gRPC server", "port", s.grpcPort)
+		go func() {
+			if err := s.grpc.Serve(grpcLis); err != nil {
+				logger.Logger.Error("gRPC server failed", "error", err)
+			}
+		}()
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if err := srv.Serve(lis); err != nil && err != http.ErrServerClosed {
+			serveErr <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err := <-serveErr:
+		if s.grpc != nil {
+			s.grpc.Stop()
+		}
+		return fmt.Errorf("REST server failed: %w", err)
+	}
+	logger.Logger.Info("Draining REST server", "delay", s.drainDelay, "timeout", s.drainTimeout)
+	return s.drain(srv, s.drainDelay, s.drainTimeout)
+}
@@ -0,0 +1,249 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	stellarrpc "github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/storage"
+)
+
+const testAccount = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+
+func newTestServer(t *testing.T, tokens map[string]Role) *Server {
+	t.Helper()
+	t.Setenv("ERST_SIM_PATH", "/bin/echo")
+
+	srv, err := NewServer(Config{
+		Network: string(stellarrpc.Testnet),
+		Tokens:  tokens,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return srv
+}
+
+func TestParseTokenSpec(t *testing.T) {
+	token, role, err := ParseTokenSpec("abc:def:analyst")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token != "abc:def" || role != RoleAnalyst {
+		t.Errorf("got token=%q role=%q", token, role)
+	}
+
+	for _, bad := range []string{"", "token", "token:", ":admin", "token:root"} {
+		if _, _, err := ParseTokenSpec(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	auth := NewAuthenticator(map[string]Role{"t1": RoleAdmin, "t2": RoleAnalyst})
+
+	req := httptest.NewRequest("POST", "/api/v1/debug", nil)
+	if _, ok := auth.Authenticate(req); ok {
+		t.Error("expected missing token to be rejected")
+	}
+
+	req.Header.Set("Authorization", "Bearer t2")
+	if role, ok := auth.Authenticate(req); !ok || role != RoleAnalyst {
+		t.Errorf("expected analyst, got %q (ok=%v)", role, ok)
+	}
+
+	req.Header.Set("Authorization", "bearer t2")
+	if role, ok := auth.Authenticate(req); !ok || role != RoleAnalyst {
+		t.Errorf("expected case-insensitive scheme to be accepted, got %q (ok=%v)", role, ok)
+	}
+
+	req.Header.Set("Authorization", "t2")
+	if _, ok := auth.Authenticate(req); ok {
+		t.Error("expected token without Bearer scheme to be rejected")
+	}
+
+	req.Header.Set("Authorization", "wrong")
+	if _, ok := auth.Authenticate(req); ok {
+		t.Error("expected wrong token to be rejected")
+	}
+
+	open := NewAuthenticator(nil)
+	if role, ok := open.Authenticate(req); !ok || role != RoleAdmin {
+		t.Errorf("expected open access as admin, got %q (ok=%v)", role, ok)
+	}
+}
+
+func TestStartReturnsListenError(t *testing.T) {
+	srv := newTestServer(t, nil)
+
+	busy, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatalf("failed to reserve port: %v", err)
+	}
+	defer busy.Close()
+	port := strconv.Itoa(busy.Addr().(*net.TCPAddr).Port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err = srv.Start(ctx, port)
+	if err == nil || !strings.Contains(err.Error(), "failed to listen") {
+		t.Fatalf("expected listen error, got %v", err)
+	}
+	if ctx.Err() != nil {
+		t.Fatal("expected Start to fail before the context expired")
+	}
+}
+
+func TestPolicyApply(t *testing.T) {
+	result := &DebugResult{
+		Hash:        "abc",
+		EnvelopeXdr: "AAAA",
+		TokenFlow:   []string{"10 XLM " + testAccount + " -> " + testAccount},
+	}
+
+	out, err := DefaultPolicies()[RoleAnalyst].Apply(result)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+
+	m := out.(map[string]interface{})
+	if _, ok := m["envelope_xdr"]; ok {
+		t.Error("expected envelope_xdr to be hidden for analysts")
+	}
+
+	line := m["token_flow"].([]interface{})[0].(string)
+	if strings.Contains(line, testAccount) {
+		t.Errorf("expected address to be redacted, got %q", line)
+	}
+	if !strings.Contains(line, RedactAddress(testAccount)) {
+		t.Errorf("expected masked address in %q", line)
+	}
+
+	same, err := DefaultPolicies()[RoleAdmin].Apply(result)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if same != result {
+		t.Error("expected admin policy to pass output through untouched")
+	}
+}
+
+func TestHandlerRequiresAuth(t *testing.T) {
+	srv := newTestServer(t, map[string]Role{"secret": RoleAdmin})
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/v1/debug", strings.NewReader(`{"hash":"x"}`))
+	srv.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected health to be public, got %d", rec.Code)
+	}
+}
+
+func TestHandlerServesUI(t *testing.T) {
+	srv := newTestServer(t, nil)
+
+	for path, want := range map[string]string{
+		"/":       "<title>erst</title>",
+		"/app.js": "/api/v1/jobs",
+	} {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s: expected 200, got %d", path, rec.Code)
+			continue
+		}
+		if !strings.Contains(rec.Body.String(), want) {
+			t.Errorf("GET %s: expected body to contain %q", path, want)
+		}
+	}
+}
+
+func TestClientFor(t *testing.T) {
+	srv := newTestServer(t, nil)
+
+	def, err := srv.clientFor("")
+	if err != nil || def != srv.rpcClient {
+		t.Fatalf("expected default client, got %v (err=%v)", def, err)
+	}
+
+	main1, err := srv.clientFor("mainnet")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	main2, _ := srv.clientFor("mainnet")
+	if main1 != main2 {
+		t.Error("expected network clients to be reused")
+	}
+
+	if _, err := srv.clientFor("devnet"); err == nil {
+		t.Error("expected error for unknown network")
+	}
+}
+
+func TestNewServer_SandboxLimits(t *testing.T) {
+	t.Setenv("ERST_SIM_PATH", "/bin/echo")
+
+	srv, err := NewServer(Config{Network: string(stellarrpc.Testnet), Sandbox: DefaultSandboxLimits()})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	runner, ok := srv.runner.(*simulator.Runner)
+	if !ok {
+		t.Fatalf("unexpected runner type %T", srv.runner)
+	}
+	if runner.Limits != DefaultSandboxLimits() {
+		t.Errorf("runner limits = %s, want %s", runner.Limits, DefaultSandboxLimits())
+	}
+	if !runner.NoCrashReports {
+		t.Error("the server must not save crash reports")
+	}
+
+	le := &simulator.LimitError{Resource: simulator.ResourceMemory, Limit: "2048 MB", Message: "out of memory"}
+	if got := limitExceeded(fmt.Errorf("simulation failed: %w", le)); got != le {
+		t.Errorf("limitExceeded did not unwrap the limit error, got %v", got)
+	}
+	if limitExceeded(errors.New("boom")) != nil {
+		t.Error("plain errors are not limit breaches")
+	}
+
+	if got := simulationFailure(&simulator.SimulationError{Message: "trapped"}); got != "simulation error: trapped" {
+		t.Errorf("simulation errors are returned as is, got %q", got)
+	}
+	got := simulationFailure(errors.New("simulator crashed: exit status 101; a crash report was saved to /home/erst/crashes/1.json"))
+	if strings.Contains(got, "/home/") {
+		t.Errorf("file paths reach API clients: %q", got)
+	}
+}
+
+func TestStorageNotInPublicMode(t *testing.T) {
+	t.Setenv("ERST_SIM_PATH", "/bin/echo")
+	db, err := storage.Open(context.Background(), storage.Config{DSN: filepath.Join(t.TempDir(), "erst.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := NewServer(Config{Network: "testnet", Public: true, Storage: db}); err == nil {
+		t.Error("expected storage to be refused in public mode")
+	}
+}
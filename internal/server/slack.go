@@ -0,0 +1,420 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/report"
+	"github.com/dotandev/hintents/internal/security"
+)
+
+// slackMaxSkew is how far a request timestamp may be from the local clock
+// before the request is treated as a replay
+const slackMaxSkew = 5 * time.Minute
+
+// slackMaxBody caps the size of a slash command request
+const slackMaxBody = 64 << 10
+
+const slackUsage = "Usage: `/erst debug <tx-hash> [network]`"
+
+// SlackConfig enables the Slack slash command endpoint
+type SlackConfig struct {
+	// SigningSecret verifies that requests come from Slack. The endpoint is
+	// disabled when it is empty.
+	SigningSecret string
+	// PublicURL is the base URL users reach the server at, used for report
+	// links. It defaults to the host of the incoming request.
+	PublicURL string
+	// Role selects the output policy of replies and reports; it defaults to
+	// analyst, since replies are visible to the whole channel
+	Role Role
+}
+
+// slackMessage is a message posted to a Slack response_url or returned as
+// the immediate reply to a slash command
+type slackMessage struct {
+	ResponseType string `json:"response_type,omitempty"`
+	Text         string `json:"text"`
+}
+
+// slackCommand is a parsed "/erst debug <hash> [network]" invocation
+type slackCommand struct {
+	Hash    string
+	Network string
+}
+
+// VerifySlackSignature checks the X-Slack-Signature of a request body
+// against the signing secret, rejecting timestamps outside slackMaxSkew
+func VerifySlackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	ts := header.Get("X-Slack-Request-Timestamp")
+	sig := header.Get("X-Slack-Signature")
+	if ts == "" || sig == "" {
+		return fmt.Errorf("missing Slack signature headers")
+	}
+
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Slack request timestamp: %w", err)
+	}
+	if skew := now.Sub(time.Unix(sec, 0)); skew > slackMaxSkew || skew < -slackMaxSkew {
+		return fmt.Errorf("slack request timestamp is %s from the server clock", skew.Round(time.Second))
+	}
+
+	if !hmac.Equal([]byte(sig), []byte(slackSignature(secret, ts, body))) {
+		return fmt.Errorf("slack signature mismatch")
+	}
+	return nil
+}
+
+// slackSignature computes the v0 signature Slack sends for a request
+func slackSignature(secret, ts string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseSlackCommand parses the text after the slash command, e.g.
+// "debug <hash> testnet" or "debug <hash> --network testnet"
+func parseSlackCommand(text string) (slackCommand, error) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || fields[0] == "help" {
+		return slackCommand{}, fmt.Errorf("%s", slackUsage)
+	}
+	if fields[0] != "debug" {
+		return slackCommand{}, fmt.Errorf("unknown command %q. %s", fields[0], slackUsage)
+	}
+
+	var cmd slackCommand
+	args := fields[1:]
+	for i := 0; i < len(args); i++ {
+		switch a := args[i]; {
+		case a == "--network" || a == "-n":
+			if i+1 == len(args) {
+				return slackCommand{}, fmt.Errorf("%s needs a network. %s", a, slackUsage)
+			}
+			i++
+			cmd.Network = args[i]
+		case strings.HasPrefix(a, "--network="):
+			cmd.Network = strings.TrimPrefix(a, "--network=")
+		case cmd.Hash == "":
+			cmd.Hash = a
+		case cmd.Network == "":
+			cmd.Network = a
+		default:
+			return slackCommand{}, fmt.Errorf("unexpected argument %q. %s", a, slackUsage)
+		}
+	}
+	if cmd.Hash == "" {
+		return slackCommand{}, fmt.Errorf("missing transaction hash. %s", slackUsage)
+	}
+	return cmd, nil
+}
+
+// handleSlackCommand answers a slash command at once and posts the debug
+// summary to the command's response_url when the run finishes
+func (s *Server) handleSlackCommand(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, slackMaxBody+1))
+	if err != nil || len(body) > slackMaxBody {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := VerifySlackSignature(s.slack.SigningSecret, r.Header, body, time.Now()); err != nil {
+		logger.Logger.Warn("Rejected Slack request", "error", err)
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	// Slack retries commands it thinks timed out; the first delivery already
+	// started the run
+	if r.Header.Get("X-Slack-Retry-Num") != "" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form body")
+		return
+	}
+
+	cmd, err := parseSlackCommand(form.Get("text"))
+	if err != nil {
+		writeJSON(w, http.StatusOK, slackMessage{ResponseType: "ephemeral", Text: err.Error()})
+		return
+	}
+	client, err := s.clientFor(cmd.Network)
+	if err != nil {
+		writeJSON(w, http.StatusOK, slackMessage{ResponseType: "ephemeral", Text: err.Error()})
+		return
+	}
+	responseURL := form.Get("response_url")
+	if u, err := url.Parse(responseURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		writeError(w, http.StatusBadRequest, "invalid response_url")
+		return
+	}
+
+	job := newJob(cmd.Hash)
+	job.slack = true
+	reportURL := s.slackBaseURL(r) + "/slack/reports/" + job.ID
+	user := form.Get("user_name")
+
+	release := s.track(job.ID, string(client.Network), cmd.Hash)
+	s.background.Add(1)
+	err = s.pool.Submit("slack:"+form.Get("team_id"), func() {
+		defer s.background.Done()
+		defer release()
+		// The job outlives the request that created it
+		started := time.Now()
+		result, err := s.runDebug(context.Background(), client, cmd.Hash, job)
+		job.finish(result, err)
+		s.record(context.Background(), "slack debug", "slack:"+user, job.ID, cmd.Hash, result, err, started)
+
+		msg := s.slackSummary(cmd.Hash, string(client.Network), user, result, err, reportURL)
+		if err := s.postSlack(context.Background(), responseURL, msg); err != nil {
+			logger.Logger.Warn("Failed to post Slack reply", "job", job.ID, "error", err)
+		}
+	})
+	if err != nil {
+		s.background.Done()
+		release()
+		writeJSON(w, http.StatusOK, slackMessage{
+			ResponseType: "ephemeral",
+			Text:         fmt.Sprintf("erst is busy (%v), try again in %s.", err, s.pool.RetryAfter().Round(time.Second)),
+		})
+		return
+	}
+	s.jobs.Add(job)
+
+	writeJSON(w, http.StatusOK, slackMessage{
+		ResponseType: "ephemeral",
+		Text:         fmt.Sprintf("Debugging `%s` on %s...", shortTxHash(cmd.Hash), client.Network),
+	})
+}
+
+// handleSlackReport renders the HTML report of a debug run started from Slack.
+// The unguessable job ID in the link is the credential.
+func (s *Server) handleSlackReport(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.Get(r.PathValue("id"))
+	if !ok || !job.slack {
+		http.NotFound(w, r)
+		return
+	}
+	status := job.Status()
+	if !status.Done {
+		w.Header().Set("Refresh", "5")
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintf(w, "Debug run is still %s, this page refreshes every 5 seconds\n", status.Phase)
+		return
+	}
+
+	result, err := s.slackRedact(status.Result)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	page, err := buildDebugReport(status.Hash, result, s.slackRedactText(status.Error)).ExportHTML()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(page)
+}
+
+// slackRedact applies the Slack role's policy to a result
+func (s *Server) slackRedact(result *DebugResult) (*DebugResult, error) {
+	if result == nil {
+		return nil, nil
+	}
+	role := s.slack.Role
+	if role == "" {
+		role = RoleAnalyst
+	}
+	out, err := s.policyFor(role).Apply(result)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result: %w", err)
+	}
+	var redacted DebugResult
+	if err := json.Unmarshal(data, &redacted); err != nil {
+		return nil, fmt.Errorf("failed to decode result: %w", err)
+	}
+	return &redacted, nil
+}
+
+// slackSummary formats the channel reply for a finished debug run
+func (s *Server) slackSummary(hash, network, user string, result *DebugResult, runErr error, reportURL string) slackMessage {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*erst debug* `%s` on %s", shortTxHash(hash), network)
+	if user != "" {
+		fmt.Fprintf(&b, " (requested by %s)", user)
+	}
+	b.WriteString("\n")
+
+	result, err := s.slackRedact(result)
+	switch {
+	case runErr != nil:
+		fmt.Fprintf(&b, "Debug run failed: %s\n", s.slackRedactText(runErr.Error()))
+	case err != nil:
+		fmt.Fprintf(&b, "Could not render the result: %s\n", err)
+	default:
+		fmt.Fprintf(&b, "Status: *%s*\n", result.Status)
+		if result.Error != "" {
+			fmt.Fprintf(&b, "> %s\n", firstLine(result.Error))
+		}
+		if len(result.Findings) == 0 {
+			b.WriteString("No security findings\n")
+		} else {
+			fmt.Fprintf(&b, "%d security finding(s)\n", len(result.Findings))
+			for i, f := range result.Findings {
+				if i == 5 {
+					fmt.Fprintf(&b, "• ...and %d more\n", len(result.Findings)-i)
+					break
+				}
+				fmt.Fprintf(&b, "• [%s] %s\n", f.Severity, f.Title)
+			}
+		}
+	}
+	fmt.Fprintf(&b, "<%s|Full report>", reportURL)
+
+	return slackMessage{ResponseType: "in_channel", Text: b.String()}
+}
+
+// slackRedactText applies the Slack role's address redaction to free text
+func (s *Server) slackRedactText(text string) string {
+	out, err := s.slackRedact(&DebugResult{Error: text})
+	if err != nil || out == nil {
+		return text
+	}
+	return out.Error
+}
+
+// postSlack delivers a message to a slash command's response_url
+func (s *Server) postSlack(ctx context.Context, responseURL string, msg slackMessage) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode Slack message: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create Slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to Slack: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("slack returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// slackBaseURL returns the configured public URL, or the one the request
+// reached the server at
+func (s *Server) slackBaseURL(r *http.Request) string {
+	if s.slack.PublicURL != "" {
+		return strings.TrimRight(s.slack.PublicURL, "/")
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// buildDebugReport turns a debug result into the standard erst report
+func buildDebugReport(hash string, result *DebugResult, runErr string) *report.Builder {
+	b := report.NewBuilder("Transaction Debug Report").WithTransactionHash(hash)
+	if result == nil {
+		b.SetSummary("error", "", 0, 1, 0, 0)
+		b.AddKeyFinding("Debug run failed: " + runErr)
+		return b
+	}
+
+	errorCount, successRate := 0, 100.0
+	if result.Status != "success" {
+		errorCount, successRate = 1, 0
+	}
+	events := 0
+	if result.Simulation != nil {
+		events = len(result.Simulation.DiagnosticEvents)
+		for i, ev := range result.Simulation.DiagnosticEvents {
+			contract := ""
+			if ev.ContractID != nil {
+				contract = *ev.ContractID
+			}
+			b.AddExecutionStep(i, ev.EventType, "success", strings.Join(append([]string{contract}, ev.Topics...), " "))
+		}
+	}
+	b.SetSummary(result.Status, "", events, errorCount, 0, successRate)
+	if result.Error != "" {
+		b.AddKeyFinding(result.Error)
+	}
+	for _, line := range result.TokenFlow {
+		b.AddKeyFinding(line)
+	}
+
+	var worst security.Severity
+	for _, f := range result.Findings {
+		b.AddIssue(string(f.Type), string(f.Severity), f.Title+": "+f.Description, "", f.Evidence)
+		if worst == "" || (f.Severity != worst && f.Severity.AtLeast(worst)) {
+			worst = f.Severity
+		}
+	}
+	if score, ok := riskScores[worst]; ok {
+		b.SetRiskAssessment(string(worst), score)
+	}
+	b.SetMetadata("erst serve", "", map[string]string{"network": result.Network})
+	return b
+}
+
+// riskScores maps the worst finding severity to a report risk score
+var riskScores = map[security.Severity]float64{
+	security.SeverityHigh:   75,
+	security.SeverityMedium: 50,
+	security.SeverityLow:    25,
+	security.SeverityInfo:   0,
+}
+
+func shortTxHash(hash string) string {
+	if len(hash) <= 12 {
+		return hash
+	}
+	return hash[:12] + "..."
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if len(s) > 200 {
+		s = s[:200] + "..."
+	}
+	return s
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	stellarrpc "github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/security"
+)
+
+const testSlackSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+func signedSlackRequest(t *testing.T, secret string, form url.Values, ts time.Time) *http.Request {
+	t.Helper()
+	body := form.Encode()
+	stamp := strconv.FormatInt(ts.Unix(), 10)
+	req := httptest.NewRequest("POST", "/slack/commands", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", stamp)
+	req.Header.Set("X-Slack-Signature", slackSignature(secret, stamp, []byte(body)))
+	return req
+}
+
+func newSlackTestServer(t *testing.T) *Server {
+	t.Helper()
+	t.Setenv("ERST_SIM_PATH", "/bin/echo")
+
+	srv, err := NewServer(Config{
+		Network: string(stellarrpc.Testnet),
+		Tokens:  map[string]Role{"t1": RoleAdmin},
+		Slack:   SlackConfig{SigningSecret: testSlackSecret, PublicURL: "https://erst.example.com/"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create server: %v", err)
+	}
+	return srv
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte("text=debug+abc")
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", "1700000000")
+	header.Set("X-Slack-Signature", slackSignature("secret", "1700000000", body))
+
+	if err := VerifySlackSignature("secret", header, body, now); err != nil {
+		t.Errorf("expected valid signature, got %v", err)
+	}
+	if err := VerifySlackSignature("other", header, body, now); err == nil {
+		t.Error("expected a signature from another secret to be rejected")
+	}
+	if err := VerifySlackSignature("secret", header, []byte("text=debug+xyz"), now); err == nil {
+		t.Error("expected a modified body to be rejected")
+	}
+	if err := VerifySlackSignature("secret", header, body, now.Add(6*time.Minute)); err == nil {
+		t.Error("expected a stale timestamp to be rejected")
+	}
+	if err := VerifySlackSignature("secret", http.Header{}, body, now); err == nil {
+		t.Error("expected missing headers to be rejected")
+	}
+}
+
+func TestParseSlackCommand(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    slackCommand
+		wantErr string
+	}{
+		{"debug abc", slackCommand{Hash: "abc"}, ""},
+		{"debug abc testnet", slackCommand{Hash: "abc", Network: "testnet"}, ""},
+		{"debug --network futurenet abc", slackCommand{Hash: "abc", Network: "futurenet"}, ""},
+		{"debug abc --network=mainnet", slackCommand{Hash: "abc", Network: "mainnet"}, ""},
+		{"", slackCommand{}, "Usage"},
+		{"help", slackCommand{}, "Usage"},
+		{"trace abc", slackCommand{}, "unknown command"},
+		{"debug", slackCommand{}, "missing transaction hash"},
+		{"debug abc testnet extra", slackCommand{}, "unexpected argument"},
+	}
+	for _, tt := range tests {
+		got, err := parseSlackCommand(tt.text)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%q: expected error containing %q, got %v", tt.text, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.text, err)
+		} else if got != tt.want {
+			t.Errorf("%q: got %+v, want %+v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSlackEndpointDisabledWithoutSecret(t *testing.T) {
+	srv := newTestServer(t, nil)
+	req := signedSlackRequest(t, "", url.Values{"text": {"debug abc"}}, time.Now())
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	if rec.Code == http.StatusOK {
+		t.Error("expected the Slack endpoint to be disabled")
+	}
+}
+
+func TestSlackCommandRejectsBadSignature(t *testing.T) {
+	srv := newSlackTestServer(t)
+	req := signedSlackRequest(t, "wrong-secret", url.Values{"text": {"debug abc"}}, time.Now())
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+func TestSlackCommandUsage(t *testing.T) {
+	srv := newSlackTestServer(t)
+	req := signedSlackRequest(t, testSlackSecret, url.Values{"text": {"help"}}, time.Now())
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	var msg slackMessage
+	if err := json.NewDecoder(rec.Body).Decode(&msg); err != nil {
+		t.Fatalf("failed to decode reply: %v", err)
+	}
+	if rec.Code != http.StatusOK || msg.ResponseType != "ephemeral" || !strings.Contains(msg.Text, "/erst debug") {
+		t.Errorf("unexpected reply %d %+v", rec.Code, msg)
+	}
+}
+
+func TestSlackCommandPostsSummary(t *testing.T) {
+	posted := make(chan slackMessage, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slackMessage
+		_ = json.NewDecoder(r.Body).Decode(&msg)
+		posted <- msg
+	}))
+	defer hook.Close()
+
+	srv := newSlackTestServer(t)
+	hash := strings.Repeat("ab", 32)
+	form := url.Values{
+		"text":         {"debug " + hash + " not-a-network"},
+		"response_url": {hook.URL},
+		"user_name":    {"alice"},
+	}
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, signedSlackRequest(t, testSlackSecret, form, time.Now()))
+	if !strings.Contains(rec.Body.String(), "invalid network") {
+		t.Errorf("expected an invalid network reply, got %s", rec.Body.String())
+	}
+
+	// Fetching the transaction fails in tests, which still produces a reply
+	form.Set("text", "debug "+hash)
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, signedSlackRequest(t, testSlackSecret, form, time.Now()))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Debugging") {
+		t.Fatalf("unexpected immediate reply %d %s", rec.Code, rec.Body.String())
+	}
+
+	select {
+	case msg := <-posted:
+		if msg.ResponseType != "in_channel" {
+			t.Errorf("expected an in_channel reply, got %q", msg.ResponseType)
+		}
+		if !strings.Contains(msg.Text, "requested by alice") || !strings.Contains(msg.Text, "<https://erst.example.com/slack/reports/") {
+			t.Errorf("unexpected summary: %s", msg.Text)
+		}
+	case <-time.After(30 * time.Second):
+		t.Fatal("timed out waiting for the Slack reply")
+	}
+}
+
+func TestSlackReport(t *testing.T) {
+	srv := newSlackTestServer(t)
+
+	apiJob := newJob("abc")
+	apiJob.finish(&DebugResult{Hash: "abc"}, nil)
+	srv.jobs.Add(apiJob)
+
+	job := newJob("def")
+	job.slack = true
+	srv.jobs.Add(job)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/slack/reports/"+id, nil))
+		return rec
+	}
+
+	if rec := get(apiJob.ID); rec.Code != http.StatusNotFound {
+		t.Errorf("expected API jobs to be hidden, got %d", rec.Code)
+	}
+	if rec := get(job.ID); rec.Header().Get("Refresh") == "" {
+		t.Error("expected a running job to refresh")
+	}
+
+	job.finish(&DebugResult{
+		Hash:    "def",
+		Network: "testnet",
+		Status:  "error",
+		Error:   "trapped by " + testAccount,
+		Findings: []security.Finding{
+			{Type: security.FindingVerifiedRisk, Severity: security.SeverityHigh, Title: "Reentrancy", Description: "called back"},
+		},
+	}, nil)
+	rec := get(job.ID)
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("unexpected report response %d %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(body, "Reentrancy") {
+		t.Error("expected the report to list findings")
+	}
+	if strings.Contains(body, testAccount) {
+		t.Error("expected addresses to be redacted with the analyst policy")
+	}
+}
+
+func TestSlackSummaryRunError(t *testing.T) {
+	srv := newSlackTestServer(t)
+	msg := srv.slackSummary("abc", "testnet", "", nil, errors.New("account "+testAccount+" not found"), "https://x/r")
+	if strings.Contains(msg.Text, testAccount) || !strings.Contains(msg.Text, "Debug run failed") {
+		t.Errorf("unexpected summary: %s", msg.Text)
+	}
+}
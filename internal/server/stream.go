@@ -0,0 +1,148 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/rpc"
+	"golang.org/x/net/websocket"
+)
+
+// JobCreated is the body returned by POST /api/v1/jobs
+type JobCreated struct {
+	ID        string `json:"id"`
+	StatusURL string `json:"status_url"`
+	StreamURL string `json:"stream_url"`
+}
+
+// handleCreateJob starts a debug run in the background and returns immediately
+// with the URLs for polling and streaming its progress
+func (s *Server) handleCreateJob(w http.ResponseWriter, r *http.Request) {
+	var req DebugRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	client, err := s.debugClient(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	job, err := s.startJob(r.Context(), s.tenantOf(r), "serve job", client, req.Hash)
+	if err != nil {
+		s.pool.writeBusy(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, JobCreated{
+		ID:        job.ID,
+		StatusURL: "/api/v1/jobs/" + job.ID,
+		StreamURL: "/api/v1/jobs/" + job.ID + "/stream",
+	})
+}
+
+// startJob queues a debug run in the background and registers its job.
+// The run outlives the request that started it but keeps its caller.
+func (s *Server) startJob(ctx context.Context, tenant, command string, client *rpc.Client, hash string) (*Job, error) {
+	ctx = context.WithoutCancel(ctx)
+	job := newJob(hash)
+	job.tenant, _ = callerTenant(ctx)
+	release := s.track(job.ID, string(client.Network), hash)
+	s.background.Add(1)
+	err := s.pool.Submit(tenant, func() {
+		defer s.background.Done()
+		defer release()
+		started := time.Now()
+		result, err := s.runDebug(ctx, client, hash, job)
+		job.finish(result, err)
+		s.record(ctx, command, callerUser(ctx), job.ID, hash, result, err, started)
+	})
+	if err != nil {
+		s.background.Done()
+		release()
+		return nil, err
+	}
+	s.jobs.Add(job)
+	return job, nil
+}
+
+func (s *Server) handleJobStatus(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobFor(r.Context(), r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+	s.render(w, r, http.StatusOK, job.Status())
+}
+
+// handleJobStream upgrades to a WebSocket and sends every job event as a JSON
+// text frame, replaying history first. The socket closes after the terminal
+// result or error event.
+func (s *Server) handleJobStream(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobFor(r.Context(), r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	policy := s.policyFor(roleFromContext(r.Context()))
+
+	// Authentication is token based, so cross-origin handshakes are accepted
+	ws := websocket.Server{Handler: func(conn *websocket.Conn) {
+		defer conn.Close()
+
+		history, ch := job.subscribe()
+		if ch != nil {
+			defer job.unsubscribe(ch)
+		}
+
+		// Detect clients going away; incoming frames are ignored
+		gone := make(chan struct{})
+		go func() {
+			var discard []byte
+			for websocket.Message.Receive(conn, &discard) == nil {
+			}
+			close(gone)
+		}()
+
+		send := func(ev JobEvent) bool {
+			if !policy.showsEvent(ev) {
+				return true
+			}
+			out, err := policy.Apply(ev)
+			if err != nil {
+				logger.Logger.Warn("Failed to apply output policy", "error", err)
+				return false
+			}
+			return websocket.JSON.Send(conn, out) == nil
+		}
+
+		for _, ev := range history {
+			if !send(ev) {
+				return
+			}
+		}
+		if ch == nil {
+			return
+		}
+
+		for {
+			select {
+			case ev, ok := <-ch:
+				if !ok || !send(ev) {
+					return
+				}
+			case <-gone:
+				return
+			}
+		}
+	}}
+	ws.ServeHTTP(w, r)
+}
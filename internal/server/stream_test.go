@@ -0,0 +1,110 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/websocket"
+)
+
+func TestJob_SubscribeReplaysHistory(t *testing.T) {
+	job := newJob("abc")
+	job.emit(JobEvent{Type: EventPhase, Phase: PhaseFetching})
+
+	history, ch := job.subscribe()
+	if len(history) != 1 || history[0].Phase != PhaseFetching {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+
+	job.emit(JobEvent{Type: EventLog, Message: "hello"})
+	if ev := <-ch; ev.Seq != 1 || ev.Message != "hello" {
+		t.Errorf("unexpected live event: %+v", ev)
+	}
+
+	job.finish(nil, errors.New("boom"))
+	if ev := <-ch; ev.Type != EventError {
+		t.Errorf("expected error event, got %+v", ev)
+	}
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed after finish")
+	}
+
+	status := job.Status()
+	if !status.Done || status.Error != "boom" || status.Phase != PhaseFetching {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	if _, ch := job.subscribe(); ch != nil {
+		t.Error("expected no live channel for a finished job")
+	}
+}
+
+func TestJobStream_WebSocket(t *testing.T) {
+	srv := newTestServer(t, map[string]Role{"secret": RoleAnalyst})
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	job := newJob("abc")
+	srv.jobs.Add(job)
+	job.emit(JobEvent{Type: EventPhase, Phase: PhaseFetching})
+	job.emit(JobEvent{Type: EventLog, Message: "host log line"})
+	job.emit(JobEvent{Type: EventPhase, Phase: PhaseSimulating, Message: "payment from " + testAccount})
+
+	wsURL := "ws" + strings.TrimPrefix(ts.URL, "http") + "/api/v1/jobs/" + job.ID + "/stream"
+
+	if _, err := websocket.Dial(wsURL, "", ts.URL); err == nil {
+		t.Fatal("expected unauthenticated stream to be rejected")
+	}
+
+	conn, err := websocket.Dial(wsURL+"?access_token=secret", "", ts.URL)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	var ev JobEvent
+	if err := websocket.JSON.Receive(conn, &ev); err != nil || ev.Phase != PhaseFetching {
+		t.Fatalf("expected replayed phase event, got %+v (err=%v)", ev, err)
+	}
+
+	// The log event is withheld from analysts, who cannot see logs
+	if err := websocket.JSON.Receive(conn, &ev); err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if ev.Type == EventLog || ev.Phase != PhaseSimulating {
+		t.Fatalf("expected the log event to be withheld, got %+v", ev)
+	}
+	if strings.Contains(ev.Message, testAccount) {
+		t.Errorf("expected analyst stream to be redacted, got %q", ev.Message)
+	}
+
+	job.finish(&DebugResult{Hash: "abc", Status: "success"}, nil)
+
+	var final map[string]interface{}
+	if err := websocket.JSON.Receive(conn, &final); err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if final["type"] != EventResult {
+		t.Errorf("expected result event, got %v", final["type"])
+	}
+
+	if err := websocket.JSON.Receive(conn, &final); err == nil {
+		t.Error("expected stream to close after the result")
+	}
+}
+
+func TestJobStatus_NotFound(t *testing.T) {
+	srv := newTestServer(t, nil)
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/jobs/missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", rec.Code)
+	}
+}
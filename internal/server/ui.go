@@ -0,0 +1,25 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package server
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// webAssets holds the single-page UI served at the root path
+//
+//go:embed web
+var webAssets embed.FS
+
+// uiHandler serves the embedded web UI. The UI talks to the REST endpoints
+// with the caller's bearer token, so it carries no privileges of its own.
+func uiHandler() http.Handler {
+	sub, err := fs.Sub(webAssets, "web")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServer(http.FS(sub))
+}
@@ -0,0 +1,302 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+'use strict';
+
+(function () {
+  const form = document.getElementById('debug-form');
+  const statusEl = document.getElementById('status');
+  const tokenInput = document.getElementById('token');
+
+  tokenInput.value = sessionStorage.getItem('erst-token') || '';
+
+  function el(tag, text, className) {
+    const node = document.createElement(tag);
+    if (text !== undefined) node.textContent = text;
+    if (className) node.className = className;
+    return node;
+  }
+
+  function setStatus(text, isError) {
+    statusEl.textContent = text;
+    statusEl.className = isError ? 'error' : '';
+  }
+
+  function authHeaders() {
+    const headers = { 'Content-Type': 'application/json' };
+    const token = tokenInput.value.trim();
+    if (token) headers.Authorization = 'Bearer ' + token;
+    return headers;
+  }
+
+  // debug starts a background job and follows its progress over a WebSocket,
+  // resolving with the final result.
+  async function debug(hash, network, onEvent) {
+    const resp = await fetch('/api/v1/jobs', {
+      method: 'POST',
+      headers: authHeaders(),
+      body: JSON.stringify({ hash: hash, network: network || undefined }),
+    });
+    const job = await resp.json();
+    if (!resp.ok) throw new Error(job.error || resp.statusText);
+
+    const scheme = location.protocol === 'https:' ? 'wss://' : 'ws://';
+    let url = scheme + location.host + job.stream_url;
+    const token = tokenInput.value.trim();
+    if (token) url += '?access_token=' + encodeURIComponent(token);
+
+    return new Promise(function (resolve, reject) {
+      const ws = new WebSocket(url);
+      let settled = false;
+      ws.onmessage = function (msg) {
+        const ev = JSON.parse(msg.data);
+        onEvent(ev);
+        if (ev.type === 'result') {
+          settled = true;
+          resolve(ev.data);
+        } else if (ev.type === 'error') {
+          settled = true;
+          reject(new Error(ev.message));
+        }
+      };
+      ws.onclose = function () {
+        if (!settled) reject(new Error('progress stream closed before the job finished'));
+      };
+    });
+  }
+
+  function logEvent(label) {
+    const log = document.getElementById('progress-log');
+    return function (ev) {
+      if (ev.type === 'phase') {
+        setStatus(label + ev.phase + (ev.message ? ': ' + ev.message : ''));
+      } else if (ev.type === 'log') {
+        log.append(el('li', label + ev.message));
+        log.hidden = false;
+      } else if (ev.type === 'partial' && ev.data) {
+        renderSummary(ev.data);
+        document.getElementById('results').hidden = false;
+      }
+    };
+  }
+
+  function renderSummary(result) {
+    const dl = document.getElementById('summary');
+    dl.replaceChildren();
+    const sim = result.simulation || {};
+    const budget = sim.budget_usage;
+    const rows = [
+      ['Hash', result.hash],
+      ['Network', result.network],
+      ['Status', result.status],
+    ];
+    if (result.error) rows.push(['Error', result.error]);
+    if (budget) {
+      rows.push(['CPU', budget.cpu_instructions + ' / ' + budget.cpu_limit]);
+      rows.push(['Memory', budget.memory_bytes + ' / ' + budget.memory_limit]);
+    }
+    rows.forEach(function (r) {
+      dl.append(el('dt', r[0]), el('dd', String(r[1])));
+    });
+  }
+
+  function renderFindings(findings) {
+    const ul = document.getElementById('findings');
+    ul.replaceChildren();
+    if (!findings || findings.length === 0) {
+      ul.append(el('li', 'No security issues detected'));
+      return;
+    }
+    findings.forEach(function (f) {
+      const li = el('li', '[' + f.severity + '] ' + f.title + ' - ' + f.description, 'sev-' + f.severity);
+      ul.append(li);
+    });
+  }
+
+  function renderEvents(sim) {
+    const tbody = document.querySelector('#events tbody');
+    tbody.replaceChildren();
+    const events = (sim && sim.diagnostic_events) || [];
+    events.forEach(function (e, i) {
+      const tr = el('tr');
+      tr.append(
+        el('td', String(i + 1)),
+        el('td', e.event_type),
+        el('td', e.contract_id || ''),
+        el('td', (e.topics || []).join(', ')),
+        el('td', e.data || '')
+      );
+      tbody.append(tr);
+    });
+    if (events.length === 0 && sim && sim.events) {
+      sim.events.forEach(function (e, i) {
+        e = normalizeEvent(e);
+        const tr = el('tr');
+        tr.append(
+          el('td', String(i + 1)),
+          el('td', e.type || 'raw'),
+          el('td', e.contract_id || ''),
+          el('td', (e.topics || []).join(', ')),
+          el('td', e.data || e.raw || '')
+        );
+        tbody.append(tr);
+      });
+    }
+  }
+
+  // normalizeEvent accepts the typed events of current servers and the plain
+  // strings stored in older sessions.
+  function normalizeEvent(e) {
+    return typeof e === 'string' ? { raw: e } : (e || {});
+  }
+
+  // eventText is a stable rendering of an event for comparison; the index is
+  // left out so events are compared by content.
+  function eventText(e) {
+    if (!e) return '<missing>';
+    e = normalizeEvent(e);
+    if (!e.type && !e.topics && !e.data) return e.raw || '';
+    return [e.type || '', e.contract_id || '', (e.topics || []).join(', '), e.data || ''].join(' | ');
+  }
+
+  // renderTokenFlow lays nodes out on a circle and draws one arrow per aggregated edge.
+  function renderTokenFlow(edges) {
+    const container = document.getElementById('tokenflow');
+    container.replaceChildren();
+    if (!edges || edges.length === 0) {
+      container.append(el('p', 'No token movements'));
+      return;
+    }
+
+    const ns = 'http://www.w3.org/2000/svg';
+    const names = [];
+    edges.forEach(function (e) {
+      if (names.indexOf(e.from) < 0) names.push(e.from);
+      if (names.indexOf(e.to) < 0) names.push(e.to);
+    });
+
+    const size = 480;
+    const radius = size / 2 - 80;
+    const pos = {};
+    names.forEach(function (n, i) {
+      const angle = (2 * Math.PI * i) / names.length - Math.PI / 2;
+      pos[n] = { x: size / 2 + radius * Math.cos(angle), y: size / 2 + radius * Math.sin(angle) };
+    });
+
+    const svg = document.createElementNS(ns, 'svg');
+    svg.setAttribute('width', size);
+    svg.setAttribute('height', size);
+    svg.setAttribute('role', 'img');
+    svg.setAttribute('aria-label', 'Token flow graph');
+
+    const defs = document.createElementNS(ns, 'defs');
+    defs.innerHTML = '<marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto"><path d="M0,0 L10,5 L0,10 z" fill="#57606a"/></marker>';
+    svg.append(defs);
+
+    edges.forEach(function (e) {
+      const a = pos[e.from];
+      const b = pos[e.to];
+      const line = document.createElementNS(ns, 'line');
+      line.setAttribute('x1', a.x);
+      line.setAttribute('y1', a.y);
+      line.setAttribute('x2', b.x);
+      line.setAttribute('y2', b.y);
+      line.setAttribute('stroke', '#57606a');
+      line.setAttribute('marker-end', 'url(#arrow)');
+      svg.append(line);
+
+      const label = document.createElementNS(ns, 'text');
+      label.setAttribute('x', (a.x + b.x) / 2);
+      label.setAttribute('y', (a.y + b.y) / 2 - 4);
+      label.setAttribute('font-size', '11');
+      label.setAttribute('text-anchor', 'middle');
+      label.textContent = e.amount + ' ' + e.token;
+      svg.append(label);
+    });
+
+    names.forEach(function (n) {
+      const p = pos[n];
+      const circle = document.createElementNS(ns, 'circle');
+      circle.setAttribute('cx', p.x);
+      circle.setAttribute('cy', p.y);
+      circle.setAttribute('r', 6);
+      circle.setAttribute('fill', '#0969da');
+      svg.append(circle);
+
+      const text = document.createElementNS(ns, 'text');
+      text.setAttribute('x', p.x);
+      text.setAttribute('y', p.y + 18);
+      text.setAttribute('font-size', '11');
+      text.setAttribute('text-anchor', 'middle');
+      text.textContent = n.length > 12 ? n.slice(0, 6) + '...' + n.slice(-4) : n;
+      svg.append(text);
+    });
+
+    container.append(svg);
+  }
+
+  function renderDiff(a, b) {
+    const section = document.getElementById('diff-section');
+    const ul = document.getElementById('diff');
+    ul.replaceChildren();
+    if (!b) {
+      section.hidden = true;
+      return;
+    }
+    section.hidden = false;
+
+    const diffs = [];
+    if (a.status !== b.status) diffs.push('Status: ' + a.status + ' (' + a.network + ') vs ' + b.status + ' (' + b.network + ')');
+
+    const evA = (a.simulation && a.simulation.events) || [];
+    const evB = (b.simulation && b.simulation.events) || [];
+    if (evA.length !== evB.length) diffs.push('Event count: ' + evA.length + ' vs ' + evB.length);
+    for (let i = 0; i < Math.max(evA.length, evB.length); i++) {
+      const textA = eventText(evA[i]);
+      const textB = eventText(evB[i]);
+      if (textA !== textB) diffs.push('Event ' + i + ': ' + textA + ' vs ' + textB);
+    }
+
+    const budA = a.simulation && a.simulation.budget_usage;
+    const budB = b.simulation && b.simulation.budget_usage;
+    if (budA && budB && budA.cpu_instructions !== budB.cpu_instructions) {
+      diffs.push('CPU instructions: ' + budA.cpu_instructions + ' vs ' + budB.cpu_instructions);
+    }
+
+    if (diffs.length === 0) diffs.push('No differences');
+    diffs.forEach(function (d) { ul.append(el('li', d)); });
+  }
+
+  form.addEventListener('submit', async function (ev) {
+    ev.preventDefault();
+    sessionStorage.setItem('erst-token', tokenInput.value.trim());
+
+    const hash = document.getElementById('hash').value.trim();
+    const network = document.getElementById('network').value;
+    const compare = document.getElementById('compare').value;
+
+    setStatus('Starting debug job...');
+    document.getElementById('results').hidden = true;
+    const log = document.getElementById('progress-log');
+    log.replaceChildren();
+    log.hidden = true;
+
+    try {
+      const runs = [debug(hash, network, logEvent(''))];
+      if (compare) runs.push(debug(hash, compare, logEvent('[' + compare + '] ')));
+      const results = await Promise.all(runs);
+      const primary = results[0];
+
+      renderSummary(primary);
+      renderFindings(primary.findings);
+      renderEvents(primary.simulation);
+      renderTokenFlow(primary.token_edges);
+      renderDiff(primary, results[1]);
+
+      document.getElementById('results').hidden = false;
+      setStatus('');
+    } catch (err) {
+      setStatus(err.message, true);
+    }
+  });
+})();
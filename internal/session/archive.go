@@ -0,0 +1,291 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
+	"github.com/klauspost/compress/zstd"
+)
+
+// StatusArchived is the status of sessions moved to an archive file
+const StatusArchived = "archived"
+
+// ArchiveDir returns the directory archive files are written to
+func ArchiveDir() (string, error) {
+	dir, err := platform.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "archive"), nil
+}
+
+// archivedFrame locates one session inside an archive file
+type archivedFrame struct {
+	data   *SessionData
+	offset int64
+	length int64
+}
+
+// Archive moves every session created before cutoff into a new compressed
+// archive file in dir and returns its path and the number of sessions it
+// holds. Each session is a separate zstd frame of its serialized form,
+// indexed in the archived_sessions table so it can still be listed and
+// loaded. No file is written when no session is old enough.
+func (s *Store) Archive(ctx context.Context, dir string, cutoff time.Time) (string, int, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM sessions WHERE created_at < ? ORDER BY created_at ASC`, cutoff)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to find sessions to archive: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return "", 0, fmt.Errorf("failed to scan session: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return "", 0, fmt.Errorf("error iterating sessions: %w", err)
+	}
+	if len(ids) == 0 {
+		return "", 0, nil
+	}
+
+	frames := make([]archivedFrame, 0, len(ids))
+	for _, id := range ids {
+		data, err := s.load(ctx, id, false)
+		if err != nil {
+			return "", 0, err
+		}
+		frames = append(frames, archivedFrame{data: data})
+	}
+
+	path, err := writeArchive(dir, frames)
+	if err != nil {
+		return "", 0, err
+	}
+
+	// The index and the removal from the live tables are one transaction;
+	// if it fails the archive file is left unreferenced and removed
+	if err := s.indexArchive(ctx, path, frames); err != nil {
+		os.Remove(path)
+		return "", 0, err
+	}
+	logger.Logger.Debug("Sessions archived", "count", len(frames), "path", path)
+	return path, len(frames), nil
+}
+
+// writeArchive writes frames to a new archive file in dir, recording each
+// frame's position
+func writeArchive(dir string, frames []archivedFrame) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create zstd encoder: %w", err)
+	}
+	defer enc.Close()
+
+	var out []byte
+	for i := range frames {
+		raw, err := Marshal(frames[i].data)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode session %s: %w", frames[i].data.ID, err)
+		}
+		start := len(out)
+		out = enc.EncodeAll(raw, out)
+		frames[i].offset = int64(start)
+		frames[i].length = int64(len(out) - start)
+	}
+
+	name := fmt.Sprintf("sessions-%s.zst", time.Now().UTC().Format("20060102T150405.000000000Z"))
+	path := filepath.Join(dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, out, 0600); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	return path, nil
+}
+
+func (s *Store) indexArchive(ctx context.Context, path string, frames []archivedFrame) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to index archive: %w", err)
+	}
+	defer tx.Rollback()
+
+	archivedAt := time.Now()
+	for _, f := range frames {
+		d := f.data
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR REPLACE INTO archived_sessions (
+				id, archive_path, frame_offset, frame_length, archived_at,
+				created_at, last_access_at, network, tx_hash, anchor, sep_flow, customer_ref
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			d.ID, path, f.offset, f.length, archivedAt,
+			d.CreatedAt, d.LastAccessAt, d.Network, d.TxHash,
+			d.Context.Anchor, d.Context.SEPFlow, d.Context.CustomerRef); err != nil {
+			return fmt.Errorf("failed to index archived session %s: %w", d.ID, err)
+		}
+		for _, table := range []string{"sessions", "token_metadata", "session_runs"} {
+			column := "session_id"
+			if table == "sessions" {
+				column = "id"
+			}
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = ?`, table, column), d.ID); err != nil {
+				return fmt.Errorf("failed to remove archived session %s: %w", d.ID, err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to index archive: %w", err)
+	}
+	return nil
+}
+
+// ListArchived returns archived sessions whose context has every field set
+// in filter, ordered by last_access_at descending. Only the indexed fields
+// are set; LoadArchived returns the full session.
+func (s *Store) ListArchived(ctx context.Context, filter Context, limit int) ([]*SessionData, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	var where []string
+	var args []interface{}
+	if filter.Anchor != "" {
+		where = append(where, "anchor = ? COLLATE NOCASE")
+		args = append(args, filter.Anchor)
+	}
+	if filter.SEPFlow != "" {
+		where = append(where, "sep_flow = ?")
+		args = append(args, filter.SEPFlow)
+	}
+	if filter.CustomerRef != "" {
+		where = append(where, "customer_ref = ?")
+		args = append(args, filter.CustomerRef)
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+	SELECT id, created_at, last_access_at, network, tx_hash, anchor, sep_flow, customer_ref
+	FROM archived_sessions
+	`+clause+`
+	ORDER BY last_access_at DESC
+	LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*SessionData
+	for rows.Next() {
+		data := SessionData{Status: StatusArchived}
+		var createdAt, lastAccessAt string
+		var anchor, sepFlow, customerRef sql.NullString
+		if err := rows.Scan(&data.ID, &createdAt, &lastAccessAt, &data.Network, &data.TxHash, &anchor, &sepFlow, &customerRef); err != nil {
+			return nil, fmt.Errorf("failed to scan archived session: %w", err)
+		}
+		if data.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse created_at: %w", err)
+		}
+		if data.LastAccessAt, err = time.Parse(time.RFC3339, lastAccessAt); err != nil {
+			return nil, fmt.Errorf("failed to parse last_access_at: %w", err)
+		}
+		data.Context = Context{Anchor: anchor.String, SEPFlow: sepFlow.String, CustomerRef: customerRef.String}
+		sessions = append(sessions, &data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating archived sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// LoadArchived reads an archived session from its archive file. The session
+// stays archived until it is saved again.
+func (s *Store) LoadArchived(ctx context.Context, sessionID string) (*SessionData, error) {
+	var path string
+	var offset, length int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT archive_path, frame_offset, frame_length FROM archived_sessions WHERE id = ?`, sessionID).
+		Scan(&path, &offset, &length)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up archived session: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive of session %s: %w", sessionID, err)
+	}
+	defer f.Close()
+	frame := make([]byte, length)
+	if _, err := f.ReadAt(frame, offset); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read archive of session %s: %w", sessionID, err)
+	}
+
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+	}
+	defer dec.Close()
+	raw, err := dec.DecodeAll(frame, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress session %s: %w", sessionID, err)
+	}
+	data, err := Unmarshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	data.Status = StatusArchived
+	return data, nil
+}
+
+// deleteArchived removes the index entry of an archived session, and the
+// archive file once no session in it remains
+func (s *Store) deleteArchived(ctx context.Context, sessionID string) error {
+	var path string
+	err := s.db.QueryRowContext(ctx, `SELECT archive_path FROM archived_sessions WHERE id = ?`, sessionID).Scan(&path)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("%w: %s", ErrNotFound, sessionID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up archived session: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM archived_sessions WHERE id = ?`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete archived session: %w", err)
+	}
+
+	var remaining int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM archived_sessions WHERE archive_path = ?`, path).Scan(&remaining); err != nil {
+		return fmt.Errorf("failed to count archived sessions: %w", err)
+	}
+	if remaining == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			logger.Logger.Warn("Failed to remove empty session archive", "path", path, "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreArchive(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	store, err := NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	old, err := Unmarshal(loadFixture(t, "v7"))
+	require.NoError(t, err)
+	old.CreatedAt = time.Now().Add(-100 * 24 * time.Hour)
+	require.NoError(t, store.Save(ctx, old))
+	recent := &SessionData{ID: "recent", Network: "testnet", TxHash: "abc", Status: "saved"}
+	require.NoError(t, store.Save(ctx, recent))
+
+	dir := filepath.Join(t.TempDir(), "archive")
+	path, n, err := store.Archive(ctx, dir, time.Now().Add(-90*24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, dir, filepath.Dir(path))
+	assert.FileExists(t, path)
+
+	live, err := store.List(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, live, 1)
+	assert.Equal(t, "recent", live[0].ID)
+
+	archived, err := store.ListArchived(ctx, Context{}, 10)
+	require.NoError(t, err)
+	require.Len(t, archived, 1)
+	assert.Equal(t, old.ID, archived[0].ID)
+	assert.Equal(t, StatusArchived, archived[0].Status)
+	assert.Equal(t, old.TxHash, archived[0].TxHash)
+	assert.Equal(t, old.Context, archived[0].Context)
+
+	filtered, err := store.ListArchived(ctx, Context{Anchor: "no-such-anchor"}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, filtered)
+
+	// Load falls back to the archive and returns the whole session
+	got, err := store.Load(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusArchived, got.Status)
+	assert.Equal(t, old.Runs, got.Runs)
+	assert.Equal(t, old.TokenMetadata, got.TokenMetadata)
+	assert.Equal(t, old.Fingerprint, got.Fingerprint)
+
+	// Nothing else is old enough
+	path, n, err = store.Archive(ctx, dir, time.Now().Add(-90*24*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.Empty(t, path)
+}
+
+func TestStoreSaveUnarchivesSession(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	store, err := NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, &SessionData{ID: "s1", Network: "testnet", TxHash: "abc"}))
+	_, n, err := store.Archive(ctx, t.TempDir(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	data, err := store.Load(ctx, "s1")
+	require.NoError(t, err)
+	data.Status = "saved"
+	require.NoError(t, store.Save(ctx, data))
+
+	archived, err := store.ListArchived(ctx, Context{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, archived)
+	live, err := store.List(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, live, 1)
+}
+
+func TestStoreDeleteArchivedSession(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	store, err := NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, &SessionData{ID: "s1", Network: "testnet", TxHash: "abc"}))
+	require.NoError(t, store.Save(ctx, &SessionData{ID: "s2", Network: "testnet", TxHash: "def"}))
+	path, n, err := store.Archive(ctx, t.TempDir(), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	require.NoError(t, store.Delete(ctx, "s1"))
+	assert.FileExists(t, path, "the archive still holds s2")
+	require.NoError(t, store.Delete(ctx, "s2"))
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "an emptied archive is removed")
+
+	_, err = store.Load(ctx, "s1")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, errors.Is(store.Delete(ctx, "s1"), ErrNotFound))
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SEPFlows lists the Stellar Ecosystem Proposal flows a session can be
+// attached to
+var SEPFlows = []string{"sep6", "sep10", "sep12", "sep24", "sep31", "sep38"}
+
+// Context is business context an operator attaches to a session, such as
+// the anchor and SEP flow a withdrawal belongs to, so it stays next to the
+// technical data
+type Context struct {
+	Anchor      string `json:"anchor,omitempty"`
+	SEPFlow     string `json:"sep_flow,omitempty"`
+	CustomerRef string `json:"customer_ref,omitempty"`
+}
+
+// NewContext validates and normalizes the given values. The SEP flow is
+// accepted as "sep24", "SEP-24" or "24".
+func NewContext(anchor, sepFlow, customerRef string) (Context, error) {
+	flow, err := NormalizeSEPFlow(sepFlow)
+	if err != nil {
+		return Context{}, err
+	}
+	return Context{
+		Anchor:      strings.TrimSpace(anchor),
+		SEPFlow:     flow,
+		CustomerRef: strings.TrimSpace(customerRef),
+	}, nil
+}
+
+// NormalizeSEPFlow returns the canonical form of a SEP flow name
+func NormalizeSEPFlow(flow string) (string, error) {
+	f := strings.ToLower(strings.TrimSpace(flow))
+	if f == "" {
+		return "", nil
+	}
+	f = strings.TrimPrefix(strings.ReplaceAll(f, "-", ""), "sep")
+	f = "sep" + strings.TrimLeft(f, "0")
+	for _, known := range SEPFlows {
+		if f == known {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown SEP flow %q (supported: %s)", flow, strings.Join(SEPFlows, ", "))
+}
+
+// IsZero reports whether no context is set
+func (c Context) IsZero() bool {
+	return c == Context{}
+}
+
+// Merge returns c with every field set in o replacing c's
+func (c Context) Merge(o Context) Context {
+	if o.Anchor != "" {
+		c.Anchor = o.Anchor
+	}
+	if o.SEPFlow != "" {
+		c.SEPFlow = o.SEPFlow
+	}
+	if o.CustomerRef != "" {
+		c.CustomerRef = o.CustomerRef
+	}
+	return c
+}
+
+// Matches reports whether c has every field set in filter. Anchors compare
+// case-insensitively; customer references must match exactly.
+func (c Context) Matches(filter Context) bool {
+	if filter.Anchor != "" && !strings.EqualFold(c.Anchor, filter.Anchor) {
+		return false
+	}
+	if filter.SEPFlow != "" && c.SEPFlow != filter.SEPFlow {
+		return false
+	}
+	if filter.CustomerRef != "" && c.CustomerRef != filter.CustomerRef {
+		return false
+	}
+	return true
+}
+
+// String renders the set fields, e.g. "anchor=acme sep=sep24 ref=W-1042"
+func (c Context) String() string {
+	var parts []string
+	if c.Anchor != "" {
+		parts = append(parts, "anchor="+c.Anchor)
+	}
+	if c.SEPFlow != "" {
+		parts = append(parts, "sep="+c.SEPFlow)
+	}
+	if c.CustomerRef != "" {
+		parts = append(parts, "ref="+c.CustomerRef)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ContextSummary aggregates the sessions of one anchor and SEP flow
+type ContextSummary struct {
+	Anchor       string    `json:"anchor"`
+	SEPFlow      string    `json:"sep_flow"`
+	Sessions     int       `json:"sessions"`
+	Failed       int       `json:"failed"`
+	Partial      int       `json:"partial"`
+	Customers    int       `json:"customers"`
+	LastAccessAt time.Time `json:"last_access_at"`
+}
+
+// SummarizeContexts groups sessions by anchor and SEP flow. A session
+// counts as failed when its latest simulation did not succeed. Groups are
+// ordered by anchor, then flow; sessions without context group under
+// empty names.
+func SummarizeContexts(sessions []*SessionData) []ContextSummary {
+	type key struct{ anchor, flow string }
+	groups := make(map[key]*ContextSummary)
+	customers := make(map[key]map[string]bool)
+	for _, s := range sessions {
+		k := key{s.Context.Anchor, s.Context.SEPFlow}
+		g, ok := groups[k]
+		if !ok {
+			g = &ContextSummary{Anchor: k.anchor, SEPFlow: k.flow}
+			groups[k] = g
+			customers[k] = make(map[string]bool)
+		}
+		g.Sessions++
+		if s.Partial {
+			g.Partial++
+		}
+		if resp, err := s.ToSimulationResponse(); err == nil && resp.Status != "success" {
+			g.Failed++
+		}
+		if s.Context.CustomerRef != "" {
+			customers[k][s.Context.CustomerRef] = true
+		}
+		if s.LastAccessAt.After(g.LastAccessAt) {
+			g.LastAccessAt = s.LastAccessAt
+		}
+	}
+
+	out := make([]ContextSummary, 0, len(groups))
+	for k, g := range groups {
+		g.Customers = len(customers[k])
+		out = append(out, *g)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Anchor != out[j].Anchor {
+			return out[i].Anchor < out[j].Anchor
+		}
+		return out[i].SEPFlow < out[j].SEPFlow
+	})
+	return out
+}
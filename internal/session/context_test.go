@@ -0,0 +1,103 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSEPFlow(t *testing.T) {
+	for in, want := range map[string]string{
+		"":       "",
+		"sep24":  "sep24",
+		"SEP-24": "sep24",
+		"24":     "sep24",
+		" sep6 ": "sep6",
+		"SEP-06": "sep6",
+	} {
+		got, err := NormalizeSEPFlow(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := NormalizeSEPFlow("sep99")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown SEP flow")
+}
+
+func TestContextMergeAndMatch(t *testing.T) {
+	c, err := NewContext(" Acme ", "SEP-24", "W-1042")
+	require.NoError(t, err)
+	assert.Equal(t, Context{Anchor: "Acme", SEPFlow: "sep24", CustomerRef: "W-1042"}, c)
+	assert.Equal(t, "anchor=Acme sep=sep24 ref=W-1042", c.String())
+
+	merged := c.Merge(Context{CustomerRef: "W-2000"})
+	assert.Equal(t, "Acme", merged.Anchor)
+	assert.Equal(t, "W-2000", merged.CustomerRef)
+
+	assert.True(t, c.Matches(Context{}))
+	assert.True(t, c.Matches(Context{Anchor: "acme", SEPFlow: "sep24"}))
+	assert.False(t, c.Matches(Context{SEPFlow: "sep6"}))
+	assert.False(t, c.Matches(Context{CustomerRef: "w-1042"}))
+}
+
+func TestSummarizeContexts(t *testing.T) {
+	now := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	sessions := []*SessionData{
+		{Context: Context{Anchor: "acme", SEPFlow: "sep24", CustomerRef: "a"}, SimResponseJSON: `{"status":"error"}`, LastAccessAt: now},
+		{Context: Context{Anchor: "acme", SEPFlow: "sep24", CustomerRef: "a"}, SimResponseJSON: `{"status":"success"}`, LastAccessAt: now.Add(time.Hour)},
+		{Context: Context{Anchor: "acme", SEPFlow: "sep24", CustomerRef: "b"}, Partial: true},
+		{Context: Context{Anchor: "acme", SEPFlow: "sep6"}},
+		{},
+	}
+
+	summary := SummarizeContexts(sessions)
+	require.Len(t, summary, 3)
+	assert.Equal(t, "", summary[0].Anchor)
+	assert.Equal(t, "sep24", summary[1].SEPFlow)
+	assert.Equal(t, 3, summary[1].Sessions)
+	assert.Equal(t, 1, summary[1].Failed)
+	assert.Equal(t, 1, summary[1].Partial)
+	assert.Equal(t, 2, summary[1].Customers)
+	assert.Equal(t, now.Add(time.Hour), summary[1].LastAccessAt)
+	assert.Equal(t, "sep6", summary[2].SEPFlow)
+}
+
+func TestStoreListByContext(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	store, err := NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+
+	ctx := context.Background()
+	for _, s := range []*SessionData{
+		{ID: "w1", TxHash: "aa", Status: "saved", Context: Context{Anchor: "Acme", SEPFlow: "sep24", CustomerRef: "W-1"}},
+		{ID: "w2", TxHash: "bb", Status: "saved", Context: Context{Anchor: "acme", SEPFlow: "sep6"}},
+		{ID: "w3", TxHash: "cc", Status: "saved"},
+	} {
+		require.NoError(t, store.Save(ctx, s))
+	}
+
+	all, err := store.List(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	acme, err := store.ListByContext(ctx, Context{Anchor: "ACME"}, 10)
+	require.NoError(t, err)
+	assert.Len(t, acme, 2)
+
+	sep24, err := store.ListByContext(ctx, Context{Anchor: "acme", SEPFlow: "sep24"}, 10)
+	require.NoError(t, err)
+	require.Len(t, sep24, 1)
+	assert.Equal(t, "W-1", sep24[0].Context.CustomerRef)
+
+	loaded, err := store.Load(ctx, "w1")
+	require.NoError(t, err)
+	assert.Equal(t, Context{Anchor: "Acme", SEPFlow: "sep24", CustomerRef: "W-1"}, loaded.Context)
+}
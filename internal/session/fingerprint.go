@@ -0,0 +1,127 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// Fingerprint identifies everything a replay depends on and what it
+// produced, as SHA-256 hashes. Two machines replaying the same session
+// agree on the result exactly when their fingerprints match.
+type Fingerprint struct {
+	Envelope   string `json:"envelope"`
+	ResultMeta string `json:"result_meta"`
+	// LedgerEntries hashes each ledger entry by its base64 XDR key
+	LedgerEntries map[string]string `json:"ledger_entries,omitempty"`
+	// Simulator hashes the erst-sim binary
+	Simulator string `json:"simulator"`
+	// CostModel hashes the protocol version the simulator ran with and the
+	// contract size and instruction limits of that protocol
+	CostModel string `json:"cost_model"`
+	// Result hashes the simulation response, without the flamegraph whose
+	// layout varies between runs
+	Result string `json:"result"`
+}
+
+// NewFingerprint fingerprints the replay of req by the simulator binary
+// with digest simulatorDigest, which produced resp
+func NewFingerprint(req *simulator.SimulationRequest, resp *simulator.SimulationResponse, simulatorDigest string) (*Fingerprint, error) {
+	f := &Fingerprint{
+		Envelope:   hashString(req.EnvelopeXdr),
+		ResultMeta: hashString(req.ResultMetaXdr),
+		Simulator:  simulatorDigest,
+	}
+	if len(req.LedgerEntries) > 0 {
+		f.LedgerEntries = make(map[string]string, len(req.LedgerEntries))
+		for key, entry := range req.LedgerEntries {
+			f.LedgerEntries[key] = hashString(entry)
+		}
+	}
+
+	// The runner records the protocol it resolved on the response; debug
+	// leaves it unset on the request
+	version := req.ProtocolVersion
+	if resp != nil && resp.ProtocolVersion != nil {
+		version = resp.ProtocolVersion
+	}
+	proto := simulator.GetOrDefault(version)
+	var err error
+	if f.CostModel, err = hashJSON(proto); err != nil {
+		return nil, fmt.Errorf("failed to fingerprint cost model: %w", err)
+	}
+	if resp != nil {
+		result := *resp
+		result.Flamegraph = ""
+		if f.Result, err = hashJSON(&result); err != nil {
+			return nil, fmt.Errorf("failed to fingerprint result: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// Digest combines every hash of the fingerprint into one, for comparing
+// replays across machines
+func (f *Fingerprint) Digest() string {
+	// Maps marshal with sorted keys, so the encoding is canonical
+	raw, _ := json.Marshal(f)
+	return hashString(string(raw))
+}
+
+// Diff names the parts of f that differ from other, in a stable order
+func (f *Fingerprint) Diff(other *Fingerprint) []string {
+	var diffs []string
+	if f.Envelope != other.Envelope {
+		diffs = append(diffs, "envelope")
+	}
+	if f.ResultMeta != other.ResultMeta {
+		diffs = append(diffs, "result meta")
+	}
+
+	keys := make(map[string]bool)
+	for key := range f.LedgerEntries {
+		keys[key] = true
+	}
+	for key := range other.LedgerEntries {
+		keys[key] = true
+	}
+	var changed []string
+	for key := range keys {
+		if f.LedgerEntries[key] != other.LedgerEntries[key] {
+			changed = append(changed, "ledger entry "+key)
+		}
+	}
+	sort.Strings(changed)
+	diffs = append(diffs, changed...)
+
+	if f.Simulator != other.Simulator {
+		diffs = append(diffs, "simulator")
+	}
+	if f.CostModel != other.CostModel {
+		diffs = append(diffs, "cost model")
+	}
+	if f.Result != other.Result {
+		diffs = append(diffs, "result")
+	}
+	return diffs
+}
+
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashJSON(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return hashString(string(raw)), nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fingerprintRequest() *simulator.SimulationRequest {
+	return &simulator.SimulationRequest{
+		EnvelopeXdr:   "AAAAAgAAAAA=",
+		ResultMetaXdr: "AAAAAwAAAAA=",
+		LedgerEntries: map[string]string{"key-a": "entry-a", "key-b": "entry-b"},
+	}
+}
+
+func TestFingerprintIsDeterministic(t *testing.T) {
+	resp := &simulator.SimulationResponse{Status: "success", Flamegraph: "<svg>1</svg>"}
+	a, err := NewFingerprint(fingerprintRequest(), resp, "sim")
+	require.NoError(t, err)
+
+	resp.Flamegraph = "<svg>2</svg>"
+	b, err := NewFingerprint(fingerprintRequest(), resp, "sim")
+	require.NoError(t, err)
+
+	assert.Equal(t, a.Digest(), b.Digest(), "flamegraph layout should not change the fingerprint")
+	assert.Empty(t, a.Diff(b))
+	assert.Len(t, a.LedgerEntries, 2)
+}
+
+func TestFingerprintDiff(t *testing.T) {
+	recorded, err := NewFingerprint(fingerprintRequest(), &simulator.SimulationResponse{Status: "success"}, "sim-v1")
+	require.NoError(t, err)
+
+	req := fingerprintRequest()
+	req.LedgerEntries["key-b"] = "entry-b2"
+	req.LedgerEntries["key-c"] = "entry-c"
+	v21 := uint32(21)
+	req.ProtocolVersion = &v21
+	replayed, err := NewFingerprint(req, &simulator.SimulationResponse{Status: "error", Error: "trapped"}, "sim-v2")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, recorded.Digest(), replayed.Digest())
+	assert.Equal(t, []string{"ledger entry key-b", "ledger entry key-c", "simulator", "cost model", "result"}, recorded.Diff(replayed))
+}
+
+func TestAddRunKeepsFingerprint(t *testing.T) {
+	f := &Fingerprint{Result: "r"}
+	var s SessionData
+	require.NoError(t, s.AddRun(Run{Name: DefaultRunName, Fingerprint: f}))
+	assert.Same(t, f, s.Fingerprint)
+}
+
+func TestFingerprintCostModelUsesResolvedProtocol(t *testing.T) {
+	v21, v22 := uint32(21), uint32(22)
+	a, err := NewFingerprint(fingerprintRequest(), &simulator.SimulationResponse{Status: "success", ProtocolVersion: &v21}, "sim")
+	require.NoError(t, err)
+	b, err := NewFingerprint(fingerprintRequest(), &simulator.SimulationResponse{Status: "success", ProtocolVersion: &v22}, "sim")
+	require.NoError(t, err)
+	assert.NotEqual(t, a.CostModel, b.CostModel, "the protocol the runner ran with is recorded on the response")
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dotandev/hintents/internal/platform"
+)
+
+// ErrNoActiveSession is returned when no session is named and none is current
+var ErrNoActiveSession = errors.New("no active session")
+
+// Loader loads saved sessions; *Store implements it
+type Loader interface {
+	Load(ctx context.Context, sessionID string) (*SessionData, error)
+}
+
+// Manager tracks the sessions an erst invocation is working on: the current
+// one, which commands that do not name a session default to, and any others
+// a caller holds with Put until it releases them. Sessions named explicitly
+// are loaded for the one command and are not kept. It is safe for
+// concurrent use.
+type Manager struct {
+	mu      sync.RWMutex
+	active  map[string]*SessionData
+	current string
+
+	// statePath names the file that records the current session ID across
+	// erst invocations; an empty path keeps it in memory only
+	statePath string
+}
+
+// NewManager returns a manager that records the current session in
+// statePath, or only in memory when statePath is empty. The CLI passes
+// StatePath; servers and batches keep their sessions in memory.
+func NewManager(statePath string) *Manager {
+	return &Manager{active: make(map[string]*SessionData), statePath: statePath}
+}
+
+// StatePath returns the file in the erst data directory that records the
+// CLI's current session, or "" when the directory cannot be resolved
+func StatePath() string {
+	dir, err := platform.DataDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "current_session")
+}
+
+// Put adds data to the active sessions without changing the current one,
+// replacing any session with the same ID
+func (m *Manager) Put(data *SessionData) error {
+	if data == nil || data.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active[data.ID] = data
+	return nil
+}
+
+// Activate adds data and makes it the current session of this process. The
+// session it replaces as current is finished and leaves the active set.
+func (m *Manager) Activate(data *SessionData) error {
+	if err := m.Put(data); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.current != "" && m.current != data.ID {
+		delete(m.active, m.current)
+	}
+	m.current = data.ID
+	return nil
+}
+
+// Select makes the session id current and records it so later erst
+// invocations resolve to it. The session should already be saved.
+func (m *Manager) Select(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.current = id
+	return m.writeState(id)
+}
+
+// Get returns the active session id
+func (m *Manager) Get(id string) (*SessionData, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	data, ok := m.active[id]
+	return data, ok
+}
+
+// Current returns the current session of this process, if it is active
+func (m *Manager) Current() *SessionData {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.active[m.current]
+}
+
+// CurrentID returns the current session ID: the one selected in this
+// process, else the one recorded by an earlier invocation
+func (m *Manager) CurrentID() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.current != "" {
+		return m.current
+	}
+	return m.readState()
+}
+
+// Active returns the IDs of the active sessions, sorted
+func (m *Manager) Active() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make([]string, 0, len(m.active))
+	for id := range m.active {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Release drops the session id from the active set. Releasing the current
+// session clears it, and its record when it was selected.
+func (m *Manager) Release(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.active, id)
+	if m.current == id {
+		m.current = ""
+	}
+	if m.readState() == id {
+		return m.writeState("")
+	}
+	return nil
+}
+
+// Update runs fn on the active session id while holding the manager's lock,
+// so concurrent callers changing the same session do not interleave
+func (m *Manager) Update(id string, fn func(*SessionData) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.active[id]
+	if !ok {
+		return fmt.Errorf("session %s is not active", id)
+	}
+	return fn(data)
+}
+
+// Resolve returns the session a command targets. An explicit id is taken
+// from the active sessions, else loaded from loader without joining them.
+// Without one, the current session is used, loading and activating it when
+// it was recorded by an earlier invocation. It returns ErrNoActiveSession
+// when there is none.
+func (m *Manager) Resolve(ctx context.Context, loader Loader, id string) (*SessionData, error) {
+	recorded := false
+	if id == "" {
+		if data := m.Current(); data != nil {
+			return data, nil
+		}
+		if id = m.CurrentID(); id == "" {
+			return nil, ErrNoActiveSession
+		}
+		recorded = true
+	}
+	if data, ok := m.Get(id); ok {
+		return data, nil
+	}
+	if loader == nil {
+		return nil, fmt.Errorf("session %s is not active", id)
+	}
+	data, err := loader.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if recorded {
+		if err := m.Activate(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+func (m *Manager) readState() string {
+	path := m.statePath
+	if path == "" {
+		return ""
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// writeState records id, or removes the record when id is empty. The file
+// is written to a temporary name and renamed, so a concurrent reader sees
+// either the old or the new ID, never a partial one.
+func (m *Manager) writeState(id string) error {
+	path := m.statePath
+	if path == "" {
+		return nil
+	}
+	if id == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear current session: %w", err)
+		}
+		return nil
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, ".current_session-*")
+	if err != nil {
+		return fmt.Errorf("failed to record current session: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.WriteString(id + "\n"); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to record current session: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to record current session: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to record current session: %w", err)
+	}
+	return nil
+}
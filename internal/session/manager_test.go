@@ -0,0 +1,132 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mapLoader map[string]*SessionData
+
+func (l mapLoader) Load(ctx context.Context, id string) (*SessionData, error) {
+	if data, ok := l[id]; ok {
+		return data, nil
+	}
+	return nil, ErrNotFound
+}
+
+func TestManagerConcurrentSessions(t *testing.T) {
+	m := NewManager("")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprintf("job-%02d", i)
+			assert.NoError(t, m.Put(&SessionData{ID: id}))
+			assert.NoError(t, m.Update(id, func(d *SessionData) error {
+				d.Status = "active"
+				return nil
+			}))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, m.Active(), 20)
+	assert.Nil(t, m.Current(), "Put does not change the current session")
+	data, ok := m.Get("job-07")
+	require.True(t, ok)
+	assert.Equal(t, "active", data.Status)
+	assert.Error(t, m.Update("missing", func(*SessionData) error { return nil }))
+}
+
+func TestManagerResolve(t *testing.T) {
+	ctx := context.Background()
+	state := filepath.Join(t.TempDir(), "current_session")
+	loader := mapLoader{"saved": {ID: "saved", TxHash: "abcd"}}
+
+	m := NewManager(state)
+	_, err := m.Resolve(ctx, loader, "")
+	assert.ErrorIs(t, err, ErrNoActiveSession)
+
+	require.NoError(t, m.Activate(&SessionData{ID: "live"}))
+	data, err := m.Resolve(ctx, loader, "")
+	require.NoError(t, err)
+	assert.Equal(t, "live", data.ID)
+
+	data, err = m.Resolve(ctx, loader, "saved")
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", data.TxHash)
+	_, ok := m.Get("saved")
+	assert.False(t, ok, "sessions named explicitly are not kept")
+
+	_, err = m.Resolve(ctx, loader, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// A later invocation picks up the selected session
+	require.NoError(t, m.Select("saved"))
+	next := NewManager(state)
+	assert.Equal(t, "saved", next.CurrentID())
+	data, err = next.Resolve(ctx, loader, "")
+	require.NoError(t, err)
+	assert.Equal(t, "saved", data.ID)
+	assert.Same(t, data, next.Current(), "the recorded session becomes current")
+
+	require.NoError(t, next.Release("saved"))
+	assert.Empty(t, NewManager(state).CurrentID())
+	assert.NoFileExists(t, state)
+}
+
+func TestManagerActivateReleasesPrevious(t *testing.T) {
+	m := NewManager("")
+	require.NoError(t, m.Put(&SessionData{ID: "job"}))
+	require.NoError(t, m.Activate(&SessionData{ID: "first"}))
+	require.NoError(t, m.Activate(&SessionData{ID: "first", Status: "resumed"}))
+	require.NoError(t, m.Activate(&SessionData{ID: "second"}))
+
+	assert.Equal(t, []string{"job", "second"}, m.Active(), "the replaced current session is finished")
+	assert.Equal(t, "second", m.Current().ID)
+}
+
+func TestStoreConcurrentSaves(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			store, err := NewStore()
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer store.Close()
+			data := &SessionData{ID: fmt.Sprintf("s%d", i), TxHash: "abcd", Network: "testnet"}
+			assert.NoError(t, data.AddRun(Run{Name: DefaultRunName, SimResponseJSON: "{}"}))
+			assert.NoError(t, store.Save(ctx, data))
+		}(i)
+	}
+	wg.Wait()
+
+	store, err := NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+	all, err := store.List(ctx, 100)
+	require.NoError(t, err)
+	assert.Len(t, all, 8)
+	for _, s := range all {
+		loaded, err := store.Load(ctx, s.ID)
+		require.NoError(t, err)
+		assert.Len(t, loaded.Runs, 1, s.ID)
+	}
+}
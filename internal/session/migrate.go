@@ -0,0 +1,481 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/tokenflow"
+)
+
+// schemaMigration moves the database from Version-1 to Version. Migrations
+// must be idempotent: databases created before the migration framework
+// existed report version 0 but may already contain some of the changes.
+type schemaMigration struct {
+	Version     int
+	Description string
+	Apply       func(tx *sql.Tx) error
+}
+
+// schemaMigrations lists every database migration in order. Append new
+// entries here and bump SchemaVersion; never edit a released migration.
+var schemaMigrations = []schemaMigration{
+	{
+		Version:     1,
+		Description: "create sessions and token_metadata tables",
+		Apply: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS sessions (
+				id TEXT PRIMARY KEY,
+				created_at TIMESTAMP NOT NULL,
+				last_access_at TIMESTAMP NOT NULL,
+				status TEXT NOT NULL,
+				network TEXT NOT NULL,
+				horizon_url TEXT NOT NULL,
+				tx_hash TEXT NOT NULL,
+				envelope_xdr TEXT,
+				result_xdr TEXT,
+				result_meta_xdr TEXT,
+				sim_request_json TEXT,
+				sim_response_json TEXT,
+				erst_version TEXT,
+				schema_version INTEGER NOT NULL
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_last_access ON sessions(last_access_at);
+			CREATE INDEX IF NOT EXISTS idx_tx_hash ON sessions(tx_hash);
+
+			CREATE TABLE IF NOT EXISTS token_metadata (
+				session_id TEXT NOT NULL,
+				contract_id TEXT NOT NULL,
+				metadata_json TEXT NOT NULL,
+				PRIMARY KEY (session_id, contract_id)
+			);
+			`)
+			return err
+		},
+	},
+	{
+		Version:     2,
+		Description: "record partial sessions and skipped analyses",
+		Apply: func(tx *sql.Tx) error {
+			return ensureColumns(tx, "sessions", []columnDef{
+				{"partial", "INTEGER NOT NULL DEFAULT 0"},
+				{"skipped_analyses", "TEXT"},
+			})
+		},
+	},
+	{
+		Version:     3,
+		Description: "keep simulation runs as named checkpoints",
+		Apply: func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS session_runs (
+				session_id TEXT NOT NULL,
+				name TEXT NOT NULL,
+				created_at TEXT NOT NULL,
+				description TEXT,
+				sim_request_json TEXT NOT NULL,
+				sim_response_json TEXT NOT NULL,
+				PRIMARY KEY (session_id, name)
+			);`); err != nil {
+				return err
+			}
+			// Each existing session's only simulation becomes its first
+			// checkpoint. Sessions kept created_at in the driver's time
+			// format; checkpoints keep RFC 3339.
+			rows, err := tx.Query(`
+			SELECT id, created_at, COALESCE(sim_request_json, ''), sim_response_json
+			FROM sessions WHERE COALESCE(sim_response_json, '') != ''`)
+			if err != nil {
+				return err
+			}
+			type legacyRun struct {
+				id, request, response string
+				created               time.Time
+			}
+			var runs []legacyRun
+			for rows.Next() {
+				var run legacyRun
+				var created interface{}
+				if err := rows.Scan(&run.id, &created, &run.request, &run.response); err != nil {
+					rows.Close()
+					return err
+				}
+				if run.created, err = legacyTime(created); err != nil {
+					rows.Close()
+					return fmt.Errorf("session %s: %w", run.id, err)
+				}
+				runs = append(runs, run)
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			for _, run := range runs {
+				if _, err := tx.Exec(`
+				INSERT OR IGNORE INTO session_runs (session_id, name, created_at, sim_request_json, sim_response_json)
+				VALUES (?, ?, ?, ?, ?)`,
+					run.id, DefaultRunName, run.created.UTC().Format(time.RFC3339Nano), run.request, run.response); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     4,
+		Description: "record the artifacts each checkpoint produced",
+		Apply: func(tx *sql.Tx) error {
+			return ensureColumns(tx, "session_runs", []columnDef{
+				{"output_dir", "TEXT"},
+				{"artifacts_json", "TEXT"},
+			})
+		},
+	},
+	{
+		Version:     5,
+		Description: "attach anchor, SEP flow and customer reference to sessions",
+		Apply: func(tx *sql.Tx) error {
+			if err := ensureColumns(tx, "sessions", []columnDef{
+				{"anchor", "TEXT"},
+				{"sep_flow", "TEXT"},
+				{"customer_ref", "TEXT"},
+			}); err != nil {
+				return err
+			}
+			_, err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_anchor ON sessions(anchor, sep_flow)`)
+			return err
+		},
+	},
+	{
+		Version:     6,
+		Description: "record replay fingerprints of sessions and checkpoints",
+		Apply: func(tx *sql.Tx) error {
+			if err := ensureColumns(tx, "sessions", []columnDef{{"fingerprint_json", "TEXT"}}); err != nil {
+				return err
+			}
+			return ensureColumns(tx, "session_runs", []columnDef{{"fingerprint_json", "TEXT"}})
+		},
+	},
+	{
+		Version:     7,
+		Description: "index sessions moved to archive files",
+		Apply: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+			CREATE TABLE IF NOT EXISTS archived_sessions (
+				id TEXT PRIMARY KEY,
+				archive_path TEXT NOT NULL,
+				frame_offset INTEGER NOT NULL,
+				frame_length INTEGER NOT NULL,
+				archived_at TIMESTAMP NOT NULL,
+				created_at TIMESTAMP NOT NULL,
+				last_access_at TIMESTAMP NOT NULL,
+				network TEXT NOT NULL,
+				tx_hash TEXT NOT NULL,
+				anchor TEXT,
+				sep_flow TEXT,
+				customer_ref TEXT
+			);
+
+			CREATE INDEX IF NOT EXISTS idx_archived_last_access ON archived_sessions(last_access_at);
+			CREATE INDEX IF NOT EXISTS idx_archived_anchor ON archived_sessions(anchor, sep_flow);
+			`)
+			return err
+		},
+	},
+}
+
+// migrate brings the database schema up to SchemaVersion. The applied
+// version is tracked in SQLite's user_version pragma.
+func migrate(db *sql.DB) error {
+	var current int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if current > SchemaVersion {
+		return fmt.Errorf("session database schema v%d is newer than supported v%d; please upgrade erst", current, SchemaVersion)
+	}
+
+	for _, m := range schemaMigrations {
+		if m.Version <= current {
+			continue
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to start migration %d: %w", m.Version, err)
+		}
+		// Another erst process may have migrated while this one waited for
+		// the write lock
+		var applied int
+		if err := tx.QueryRow("PRAGMA user_version").Scan(&applied); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to read schema version: %w", err)
+		}
+		if applied >= m.Version {
+			tx.Rollback()
+			continue
+		}
+		if err := m.Apply(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+		// PRAGMA does not accept bound parameters
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", m.Version)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record schema version %d: %w", m.Version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", m.Version, err)
+		}
+		logger.Logger.Debug("Applied session schema migration", "version", m.Version, "description", m.Description)
+	}
+	return nil
+}
+
+// legacyTimeFormats are the layouts older releases stored timestamps in:
+// the SQLite driver's default of time.Time.String, and RFC 3339
+var legacyTimeFormats = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// legacyTime reads a stored timestamp column, which the driver returns as a
+// time.Time when it recognizes the format and as text otherwise
+func legacyTime(v interface{}) (time.Time, error) {
+	var s string
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return time.Time{}, fmt.Errorf("unexpected timestamp %v", v)
+	}
+	// Drop the monotonic clock reading time.Time.String appends
+	if i := strings.Index(s, " m="); i >= 0 {
+		s = s[:i]
+	}
+	for _, layout := range legacyTimeFormats {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", s)
+}
+
+type columnDef struct {
+	Name string
+	Def  string
+}
+
+// ensureColumns adds any of the given columns missing from table
+func ensureColumns(tx *sql.Tx, table string, columns []columnDef) error {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid        int
+			name, typ  string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &defaultVal, &pk); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to inspect %s: %w", table, err)
+		}
+		existing[name] = true
+	}
+	rows.Close()
+
+	for _, c := range columns {
+		if existing[c.Name] {
+			continue
+		}
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, c.Name, c.Def)); err != nil {
+			return fmt.Errorf("failed to add column %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// sessionUpgrades convert a session stored at the keyed version to the
+// next one. Every version below SchemaVersion needs an entry.
+var sessionUpgrades = map[int]func(*SessionData) error{
+	// Version 1 had no notion of partial data; sessions saved without
+	// result or meta XDR were partial all the same
+	1: func(s *SessionData) error {
+		s.Partial = s.ResultXdr == "" || s.ResultMetaXdr == ""
+		return nil
+	},
+	// Version 2 kept a single simulation, which becomes the first checkpoint
+	2: func(s *SessionData) error {
+		if len(s.Runs) > 0 || s.SimResponseJSON == "" {
+			return nil
+		}
+		return s.AddRun(Run{
+			Name:            DefaultRunName,
+			CreatedAt:       s.CreatedAt,
+			SimRequestJSON:  s.SimRequestJSON,
+			SimResponseJSON: s.SimResponseJSON,
+		})
+	},
+	// Version 3 runs recorded no artifacts
+	3: func(s *SessionData) error { return nil },
+	// Version 4 sessions had no application context
+	4: func(s *SessionData) error { return nil },
+	// Version 5 sessions recorded no fingerprints; they cannot be verified
+	// until replayed again
+	5: func(s *SessionData) error { return nil },
+	// Version 6 sessions were never archived
+	6: func(s *SessionData) error { return nil },
+}
+
+// Upgrade converts s from the schema version it was stored with to
+// SchemaVersion. Sessions without a version predate versioning and are
+// treated as version 1.
+func Upgrade(s *SessionData) error {
+	if s.SchemaVersion == 0 {
+		s.SchemaVersion = 1
+	}
+	if s.SchemaVersion > SchemaVersion {
+		return fmt.Errorf("session was created with a newer version of erst (schema v%d > v%d)", s.SchemaVersion, SchemaVersion)
+	}
+	for s.SchemaVersion < SchemaVersion {
+		up, ok := sessionUpgrades[s.SchemaVersion]
+		if !ok {
+			return fmt.Errorf("no upgrade from session schema v%d", s.SchemaVersion)
+		}
+		if err := up(s); err != nil {
+			return fmt.Errorf("failed to upgrade session from schema v%d: %w", s.SchemaVersion, err)
+		}
+		s.SchemaVersion++
+	}
+	return nil
+}
+
+// sessionV1 is the serialized form of a schema version 1 session
+type sessionV1 struct {
+	ID              string                         `json:"id"`
+	CreatedAt       time.Time                      `json:"created_at"`
+	LastAccessAt    time.Time                      `json:"last_access_at"`
+	Status          string                         `json:"status"`
+	Network         string                         `json:"network"`
+	HorizonURL      string                         `json:"horizon_url"`
+	TxHash          string                         `json:"tx_hash"`
+	EnvelopeXdr     string                         `json:"envelope_xdr"`
+	ResultXdr       string                         `json:"result_xdr"`
+	ResultMetaXdr   string                         `json:"result_meta_xdr"`
+	SimRequestJSON  string                         `json:"sim_request_json"`
+	SimResponseJSON string                         `json:"sim_response_json"`
+	TokenMetadata   map[string]tokenflow.TokenMeta `json:"token_metadata,omitempty"`
+	ErstVersion     string                         `json:"erst_version"`
+	SchemaVersion   int                            `json:"schema_version"`
+}
+
+func decodeV1(raw []byte) (*SessionData, error) {
+	var v sessionV1
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return &SessionData{
+		ID:              v.ID,
+		CreatedAt:       v.CreatedAt,
+		LastAccessAt:    v.LastAccessAt,
+		Status:          v.Status,
+		Network:         v.Network,
+		HorizonURL:      v.HorizonURL,
+		TxHash:          v.TxHash,
+		EnvelopeXdr:     v.EnvelopeXdr,
+		ResultXdr:       v.ResultXdr,
+		ResultMetaXdr:   v.ResultMetaXdr,
+		SimRequestJSON:  v.SimRequestJSON,
+		SimResponseJSON: v.SimResponseJSON,
+		TokenMetadata:   v.TokenMetadata,
+		ErstVersion:     v.ErstVersion,
+		SchemaVersion:   1,
+	}, nil
+}
+
+// decodeSessionData reads versions whose wire shape is a subset of the
+// current SessionData
+func decodeSessionData(raw []byte) (*SessionData, error) {
+	var data SessionData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// decoders reads each serialized session version
+var decoders = map[int]func([]byte) (*SessionData, error){
+	1: decodeV1,
+	2: decodeSessionData,
+	3: decodeSessionData,
+	4: decodeSessionData,
+	5: decodeSessionData,
+	6: decodeSessionData,
+	7: decodeSessionData,
+}
+
+// Marshal serializes a session at the current schema version
+func Marshal(s *SessionData) ([]byte, error) {
+	out := *s
+	out.SchemaVersion = SchemaVersion
+	return json.MarshalIndent(&out, "", "  ")
+}
+
+// Unmarshal reads a serialized session of any known schema version and
+// upgrades it to the current one
+func Unmarshal(raw []byte) (*SessionData, error) {
+	var tag struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(raw, &tag); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	version := tag.SchemaVersion
+	if version == 0 {
+		version = 1
+	}
+	decode, ok := decoders[version]
+	if !ok {
+		if version > SchemaVersion {
+			return nil, fmt.Errorf("session was created with a newer version of erst (schema v%d > v%d)", version, SchemaVersion)
+		}
+		return nil, fmt.Errorf("unsupported session schema v%d (supported: %s)", version, supportedVersions())
+	}
+	data, err := decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode session schema v%d: %w", version, err)
+	}
+	data.SchemaVersion = version
+	if err := Upgrade(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func supportedVersions() string {
+	var parts []string
+	for v := 1; v <= SchemaVersion; v++ {
+		if _, ok := decoders[v]; ok {
+			parts = append(parts, fmt.Sprintf("v%d", v))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
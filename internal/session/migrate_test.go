@@ -0,0 +1,193 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loadFixture(t *testing.T, version string) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", "sessions", version+".json"))
+	require.NoError(t, err)
+	return raw
+}
+
+func TestUnmarshalFixtures(t *testing.T) {
+	v1, err := Unmarshal(loadFixture(t, "v1"))
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, v1.SchemaVersion)
+	assert.Equal(t, "testnet", v1.Network)
+	assert.True(t, v1.Partial, "v1 session without meta should be upgraded to partial")
+	assert.Empty(t, v1.SkippedAnalyses)
+	assert.Equal(t, 2025, v1.CreatedAt.Year())
+	require.Len(t, v1.Runs, 1)
+	assert.Equal(t, DefaultRunName, v1.Runs[0].Name)
+
+	v2, err := Unmarshal(loadFixture(t, "v2"))
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, v2.SchemaVersion)
+	assert.True(t, v2.Partial)
+	assert.Equal(t, []string{"Token flows (needs result meta)"}, v2.SkippedAnalyses)
+	assert.Equal(t, "USDC", v2.TokenMetadata["CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4"].Symbol)
+	require.Len(t, v2.Runs, 1)
+	assert.Equal(t, v2.SimResponseJSON, v2.Runs[0].SimResponseJSON)
+
+	v3, err := Unmarshal(loadFixture(t, "v3"))
+	require.NoError(t, err)
+	require.Len(t, v3.Runs, 2)
+	assert.Equal(t, "fixed-wasm", v3.Runs[1].Name)
+	assert.Empty(t, v3.Runs[1].Artifacts)
+
+	v4, err := Unmarshal(loadFixture(t, "v4"))
+	require.NoError(t, err)
+	require.Len(t, v4.Runs[1].Artifacts, 2)
+	assert.Equal(t, "trace.json", v4.Runs[1].Artifacts[1].Name)
+	assert.NotEmpty(t, v4.Runs[1].OutputDir)
+	assert.True(t, v4.Context.IsZero())
+
+	v5, err := Unmarshal(loadFixture(t, "v5"))
+	require.NoError(t, err)
+	assert.Equal(t, Context{Anchor: "acme-anchor", SEPFlow: "sep24", CustomerRef: "W-1042"}, v5.Context)
+	assert.Nil(t, v5.Fingerprint)
+
+	v6, err := Unmarshal(loadFixture(t, "v6"))
+	require.NoError(t, err)
+	require.NotNil(t, v6.Fingerprint)
+	assert.Equal(t, v6.Fingerprint, v6.Runs[1].Fingerprint)
+	assert.Len(t, v6.Fingerprint.LedgerEntries, 1)
+
+	v7, err := Unmarshal(loadFixture(t, "v7"))
+	require.NoError(t, err)
+	assert.Equal(t, v6.Runs, v7.Runs)
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	for _, version := range []string{"v1", "v2", "v3", "v4", "v5", "v6", "v7"} {
+		t.Run(version, func(t *testing.T) {
+			first, err := Unmarshal(loadFixture(t, version))
+			require.NoError(t, err)
+
+			raw, err := Marshal(first)
+			require.NoError(t, err)
+			second, err := Unmarshal(raw)
+			require.NoError(t, err)
+
+			assert.Equal(t, first, second)
+		})
+	}
+}
+
+func TestUnmarshalRejectsNewerSchema(t *testing.T) {
+	_, err := Unmarshal([]byte(`{"id":"x","schema_version":99}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "newer version of erst")
+}
+
+func TestUnversionedSessionIsV1(t *testing.T) {
+	data, err := Unmarshal([]byte(`{"id":"x","result_xdr":"AAAA","result_meta_xdr":"AAAA"}`))
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, data.SchemaVersion)
+	assert.False(t, data.Partial)
+}
+
+func TestEveryVersionHasUpgradeAndDecoder(t *testing.T) {
+	for v := 1; v < SchemaVersion; v++ {
+		assert.Contains(t, sessionUpgrades, v, "missing upgrade from v%d", v)
+	}
+	for v := 1; v <= SchemaVersion; v++ {
+		assert.Contains(t, decoders, v, "missing decoder for v%d", v)
+	}
+	assert.Equal(t, SchemaVersion, schemaMigrations[len(schemaMigrations)-1].Version)
+}
+
+// TestStoreMigratesLegacyDatabase opens a database laid out by a release
+// that predates migrations and checks its sessions load in the current
+// schema
+func TestStoreMigratesLegacyDatabase(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("ERST_HOME", dir)
+
+	legacy, err := sql.Open("sqlite", filepath.Join(dir, "sessions.db"))
+	require.NoError(t, err)
+	_, err = legacy.Exec(`
+	CREATE TABLE sessions (
+		id TEXT PRIMARY KEY,
+		created_at TIMESTAMP NOT NULL,
+		last_access_at TIMESTAMP NOT NULL,
+		status TEXT NOT NULL,
+		network TEXT NOT NULL,
+		horizon_url TEXT NOT NULL,
+		tx_hash TEXT NOT NULL,
+		envelope_xdr TEXT,
+		result_xdr TEXT,
+		result_meta_xdr TEXT,
+		sim_request_json TEXT,
+		sim_response_json TEXT,
+		erst_version TEXT,
+		schema_version INTEGER NOT NULL
+	);
+	INSERT INTO sessions VALUES (
+		'legacy-1', '2025-01-01 09:30:00.123456789 +0000 UTC m=+0.004512001',
+		'2025-01-02 00:00:00 +0000 UTC', 'saved',
+		'testnet', 'https://horizon-testnet.stellar.org', 'abcd',
+		'AAAA', 'AAAA', '', '{}', '{}', '0.1.0', 1
+	);`)
+	require.NoError(t, err)
+	require.NoError(t, legacy.Close())
+
+	store, err := NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+
+	var version int
+	require.NoError(t, store.db.QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(t, SchemaVersion, version)
+
+	ctx := context.Background()
+	data, err := store.Load(ctx, "legacy-1")
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, data.SchemaVersion)
+	assert.True(t, data.Partial)
+	require.Len(t, data.Runs, 1, "the legacy simulation should become a checkpoint")
+	assert.Equal(t, DefaultRunName, data.Runs[0].Name)
+	assert.True(t, data.Runs[0].CreatedAt.Equal(time.Date(2025, 1, 1, 9, 30, 0, 123456789, time.UTC)),
+		"checkpoint created_at = %v", data.Runs[0].CreatedAt)
+
+	var stored int
+	require.NoError(t, store.db.QueryRow("SELECT schema_version FROM sessions WHERE id = 'legacy-1'").Scan(&stored))
+	assert.Equal(t, SchemaVersion, stored, "upgraded session should be written back")
+}
+
+func TestStoreRoundTripFixture(t *testing.T) {
+	t.Setenv("ERST_HOME", t.TempDir())
+
+	store, err := NewStore()
+	require.NoError(t, err)
+	defer store.Close()
+
+	want, err := Unmarshal(loadFixture(t, "v7"))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.Save(ctx, want))
+	got, err := store.Load(ctx, want.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, want.TxHash, got.TxHash)
+	assert.Equal(t, want.Partial, got.Partial)
+	assert.Equal(t, want.SkippedAnalyses, got.SkippedAnalyses)
+	assert.Equal(t, want.TokenMetadata, got.TokenMetadata)
+	assert.Equal(t, want.Runs, got.Runs)
+	assert.Equal(t, want.Fingerprint, got.Fingerprint)
+	assert.Equal(t, SchemaVersion, got.SchemaVersion)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/artifact"
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// DefaultRunName names the first simulation recorded for a transaction
+const DefaultRunName = "original"
+
+// Run is a named checkpoint: one simulation of the session's transaction,
+// kept so experiments such as state overrides or a rebuilt WASM can be
+// compared against earlier runs instead of replacing them
+type Run struct {
+	Name            string    `json:"name"`
+	CreatedAt       time.Time `json:"created_at"`
+	Description     string    `json:"description,omitempty"`
+	SimRequestJSON  string    `json:"sim_request_json"`
+	SimResponseJSON string    `json:"sim_response_json"`
+	// OutputDir is the --output-dir directory the run wrote, if any
+	OutputDir string `json:"output_dir,omitempty"`
+	// Artifacts lists every file the run produced
+	Artifacts []artifact.Artifact `json:"artifacts,omitempty"`
+	// Fingerprint identifies the run's inputs and result
+	Fingerprint *Fingerprint `json:"fingerprint,omitempty"`
+}
+
+// AddRun records run as a checkpoint, replacing any earlier run with the
+// same name. The session's current simulation becomes run's.
+func (s *SessionData) AddRun(run Run) error {
+	if run.Name == "" {
+		return fmt.Errorf("checkpoint name is required")
+	}
+	if run.CreatedAt.IsZero() {
+		run.CreatedAt = time.Now()
+	}
+
+	replaced := false
+	for i := range s.Runs {
+		if s.Runs[i].Name == run.Name {
+			s.Runs[i] = run
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		s.Runs = append(s.Runs, run)
+	}
+
+	s.SimRequestJSON = run.SimRequestJSON
+	s.SimResponseJSON = run.SimResponseJSON
+	s.Fingerprint = run.Fingerprint
+	return nil
+}
+
+// FindRun returns the checkpoint with the given name
+func (s *SessionData) FindRun(name string) (*Run, error) {
+	for i := range s.Runs {
+		if s.Runs[i].Name == name {
+			return &s.Runs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("checkpoint %q not found in session %s", name, s.ID)
+}
+
+// ToSimulationResponse converts the run's stored JSON back to a
+// SimulationResponse
+func (r *Run) ToSimulationResponse() (*simulator.SimulationResponse, error) {
+	data := SessionData{SimResponseJSON: r.SimResponseJSON}
+	return data.ToSimulationResponse()
+}
+
+// ToSimulationRequest converts the run's stored JSON back to a
+// SimulationRequest
+func (r *Run) ToSimulationRequest() (*simulator.SimulationRequest, error) {
+	data := SessionData{SimRequestJSON: r.SimRequestJSON}
+	return data.ToSimulationRequest()
+}
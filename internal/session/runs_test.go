@@ -0,0 +1,33 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRun(t *testing.T) {
+	s := &SessionData{ID: "s1"}
+
+	require.NoError(t, s.AddRun(Run{Name: DefaultRunName, SimResponseJSON: `{"status":"error"}`}))
+	require.NoError(t, s.AddRun(Run{Name: "override-a", SimResponseJSON: `{"status":"success"}`}))
+	assert.Len(t, s.Runs, 2)
+	assert.Equal(t, `{"status":"success"}`, s.SimResponseJSON, "latest run becomes current")
+
+	require.NoError(t, s.AddRun(Run{Name: DefaultRunName, SimResponseJSON: `{"status":"success"}`}))
+	assert.Len(t, s.Runs, 2, "same name replaces the checkpoint")
+
+	run, err := s.FindRun("override-a")
+	require.NoError(t, err)
+	resp, err := run.ToSimulationResponse()
+	require.NoError(t, err)
+	assert.Equal(t, "success", resp.Status)
+
+	_, err = s.FindRun("missing")
+	assert.Error(t, err)
+	assert.Error(t, s.AddRun(Run{}))
+}
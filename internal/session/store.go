@@ -7,19 +7,23 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
 	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/tokenflow"
 	_ "modernc.org/sqlite"
 )
 
 const (
 	// SchemaVersion tracks the database schema version for migrations
-	SchemaVersion = 1
+	SchemaVersion = 7
 
 	// DefaultTTL is the default time-to-live for sessions (30 days)
 	DefaultTTL = 30 * 24 * time.Hour
@@ -28,6 +32,9 @@ const (
 	DefaultMaxSessions = 1000
 )
 
+// ErrNotFound is returned when no session has the requested ID
+var ErrNotFound = errors.New("session not found")
+
 // SessionData represents the complete state of a debug session
 type SessionData struct {
 	ID            string    `json:"id"`
@@ -45,6 +52,27 @@ type SessionData struct {
 	SimRequestJSON  string `json:"sim_request_json"`  // JSON sent to erst-sim
 	SimResponseJSON string `json:"sim_response_json"` // JSON received from erst-sim
 
+	// Fingerprint identifies the inputs and result of the current
+	// simulation, for 'erst session verify'
+	Fingerprint *Fingerprint `json:"fingerprint,omitempty"`
+
+	// TokenMetadata caches resolved token metadata by contract ID
+	TokenMetadata map[string]tokenflow.TokenMeta `json:"token_metadata,omitempty"`
+
+	// Partial is set when the RPC response lacked result or meta XDR;
+	// SkippedAnalyses lists what could not run because of it
+	Partial         bool     `json:"partial,omitempty"`
+	SkippedAnalyses []string `json:"skipped_analyses,omitempty"`
+
+	// Runs are the named simulation checkpoints recorded for this
+	// transaction, oldest first. SimRequestJSON and SimResponseJSON hold the
+	// most recent one.
+	Runs []Run `json:"runs,omitempty"`
+
+	// Context is the anchor, SEP flow and customer reference the session
+	// was attached to
+	Context Context `json:"context,omitzero"`
+
 	// Metadata
 	ErstVersion   string `json:"erst_version"`
 	SchemaVersion int    `json:"schema_version"`
@@ -57,20 +85,21 @@ type Store struct {
 
 // NewStore creates or opens the session database
 func NewStore() (*Store, error) {
-	homeDir, err := os.UserHomeDir()
+	erstDir, err := platform.DataDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return nil, err
 	}
-
-	erstDir := filepath.Join(homeDir, ".erst")
 	if err := os.MkdirAll(erstDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create .erst directory: %w", err)
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
 	}
 
 	dbPath := filepath.Join(erstDir, "sessions.db")
 
 	// Open SQLite database
-	db, err := sql.Open("sqlite", dbPath+"?_journal_mode=WAL")
+	// Several erst processes may share the database, so transactions take
+	// the write lock up front and writers wait for each other instead of
+	// failing with SQLITE_BUSY
+	db, err := sql.Open("sqlite", dbPath+"?_journal_mode=WAL&_pragma=busy_timeout(5000)&_txlock=immediate")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -91,35 +120,9 @@ func NewStore() (*Store, error) {
 	return store, nil
 }
 
-// initSchema creates the sessions table if it doesn't exist
+// initSchema creates or migrates the session tables
 func (s *Store) initSchema() error {
-	query := `
-	CREATE TABLE IF NOT EXISTS sessions (
-		id TEXT PRIMARY KEY,
-		created_at TIMESTAMP NOT NULL,
-		last_access_at TIMESTAMP NOT NULL,
-		status TEXT NOT NULL,
-		network TEXT NOT NULL,
-		horizon_url TEXT NOT NULL,
-		tx_hash TEXT NOT NULL,
-		envelope_xdr TEXT,
-		result_xdr TEXT,
-		result_meta_xdr TEXT,
-		sim_request_json TEXT,
-		sim_response_json TEXT,
-		erst_version TEXT,
-		schema_version INTEGER NOT NULL
-	);
-	
-	CREATE INDEX IF NOT EXISTS idx_last_access ON sessions(last_access_at);
-	CREATE INDEX IF NOT EXISTS idx_tx_hash ON sessions(tx_hash);
-	`
-
-	if _, err := s.db.Exec(query); err != nil {
-		return fmt.Errorf("failed to create schema: %w", err)
-	}
-
-	return nil
+	return migrate(s.db)
 }
 
 // Save persists a session to the database
@@ -139,8 +142,9 @@ func (s *Store) Save(ctx context.Context, data *SessionData) error {
 	INSERT INTO sessions (
 		id, created_at, last_access_at, status, network, horizon_url, tx_hash,
 		envelope_xdr, result_xdr, result_meta_xdr,
-		sim_request_json, sim_response_json, erst_version, schema_version
-	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		sim_request_json, sim_response_json, erst_version, schema_version,
+		partial, skipped_analyses, anchor, sep_flow, customer_ref, fingerprint_json
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	ON CONFLICT(id) DO UPDATE SET
 		last_access_at = excluded.last_access_at,
 		status = excluded.status,
@@ -153,37 +157,116 @@ func (s *Store) Save(ctx context.Context, data *SessionData) error {
 		sim_request_json = excluded.sim_request_json,
 		sim_response_json = excluded.sim_response_json,
 		erst_version = excluded.erst_version,
-		schema_version = excluded.schema_version
+		schema_version = excluded.schema_version,
+		partial = excluded.partial,
+		skipped_analyses = excluded.skipped_analyses,
+		anchor = excluded.anchor,
+		sep_flow = excluded.sep_flow,
+		customer_ref = excluded.customer_ref,
+		fingerprint_json = excluded.fingerprint_json
 	`
 
-	_, err := s.db.ExecContext(ctx, query,
+	skipped, err := json.Marshal(data.SkippedAnalyses)
+	if err != nil {
+		return fmt.Errorf("failed to encode skipped analyses: %w", err)
+	}
+	fingerprint, err := encodeFingerprint(data.Fingerprint)
+	if err != nil {
+		return err
+	}
+
+	// The session, its token metadata and its checkpoints are written in one
+	// transaction so concurrent readers never see half a session
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query,
 		data.ID, data.CreatedAt, data.LastAccessAt, data.Status,
 		data.Network, data.HorizonURL, data.TxHash,
 		data.EnvelopeXdr, data.ResultXdr, data.ResultMetaXdr,
 		data.SimRequestJSON, data.SimResponseJSON,
 		data.ErstVersion, data.SchemaVersion,
+		data.Partial, string(skipped),
+		data.Context.Anchor, data.Context.SEPFlow, data.Context.CustomerRef, fingerprint,
 	)
 
 	if err != nil {
 		return fmt.Errorf("failed to save session: %w", err)
 	}
 
+	for contractID, meta := range data.TokenMetadata {
+		metaJSON, err := json.Marshal(meta)
+		if err != nil {
+			return fmt.Errorf("failed to encode token metadata: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT OR REPLACE INTO token_metadata (session_id, contract_id, metadata_json) VALUES (?, ?, ?)`,
+			data.ID, contractID, string(metaJSON)); err != nil {
+			return fmt.Errorf("failed to save token metadata: %w", err)
+		}
+	}
+
+	for _, run := range data.Runs {
+		var artifacts []byte
+		if len(run.Artifacts) > 0 {
+			if artifacts, err = json.Marshal(run.Artifacts); err != nil {
+				return fmt.Errorf("failed to encode artifacts of checkpoint %s: %w", run.Name, err)
+			}
+		}
+		runFingerprint, err := encodeFingerprint(run.Fingerprint)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT OR REPLACE INTO session_runs (session_id, name, created_at, description, sim_request_json, sim_response_json, output_dir, artifacts_json, fingerprint_json)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			data.ID, run.Name, run.CreatedAt.UTC().Format(time.RFC3339Nano), run.Description,
+			run.SimRequestJSON, run.SimResponseJSON, run.OutputDir, string(artifacts), runFingerprint); err != nil {
+			return fmt.Errorf("failed to save checkpoint %s: %w", run.Name, err)
+		}
+	}
+
+	// A saved session is live again; its archived copy is no longer listed
+	if _, err := tx.ExecContext(ctx, `DELETE FROM archived_sessions WHERE id = ?`, data.ID); err != nil {
+		return fmt.Errorf("failed to unarchive session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+
 	logger.Logger.Debug("Session saved", "id", data.ID, "tx_hash", data.TxHash)
 	return nil
 }
 
-// Load retrieves a session by ID
+// Load retrieves a session by ID, reading it from its archive file when
+// the session was archived
 func (s *Store) Load(ctx context.Context, sessionID string) (*SessionData, error) {
+	data, err := s.load(ctx, sessionID, true)
+	if errors.Is(err, ErrNotFound) {
+		return s.LoadArchived(ctx, sessionID)
+	}
+	return data, err
+}
+
+// load reads a live session, updating its last access time when touch is
+// set
+func (s *Store) load(ctx context.Context, sessionID string, touch bool) (*SessionData, error) {
 	query := `
 	SELECT id, created_at, last_access_at, status, network, horizon_url, tx_hash,
 	       envelope_xdr, result_xdr, result_meta_xdr,
-	       sim_request_json, sim_response_json, erst_version, schema_version
+	       sim_request_json, sim_response_json, erst_version, schema_version,
+	       partial, skipped_analyses, anchor, sep_flow, customer_ref, fingerprint_json
 	FROM sessions
 	WHERE id = ?
 	`
 
 	var data SessionData
 	var createdAt, lastAccessAt string
+	var skipped, anchor, sepFlow, customerRef, fingerprint sql.NullString
 
 	err := s.db.QueryRowContext(ctx, query, sessionID).Scan(
 		&data.ID, &createdAt, &lastAccessAt, &data.Status,
@@ -191,10 +274,11 @@ func (s *Store) Load(ctx context.Context, sessionID string) (*SessionData, error
 		&data.EnvelopeXdr, &data.ResultXdr, &data.ResultMetaXdr,
 		&data.SimRequestJSON, &data.SimResponseJSON,
 		&data.ErstVersion, &data.SchemaVersion,
+		&data.Partial, &skipped, &anchor, &sepFlow, &customerRef, &fingerprint,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("session not found: %s", sessionID)
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, sessionID)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to load session: %w", err)
@@ -208,6 +292,36 @@ func (s *Store) Load(ctx context.Context, sessionID string) (*SessionData, error
 		return nil, fmt.Errorf("failed to parse last_access_at: %w", err)
 	}
 
+	data.SkippedAnalyses = decodeSkipped(skipped)
+	data.Context = Context{Anchor: anchor.String, SEPFlow: sepFlow.String, CustomerRef: customerRef.String}
+	if data.Fingerprint, err = decodeFingerprint(fingerprint); err != nil {
+		return nil, err
+	}
+
+	if data.TokenMetadata, err = s.loadTokenMetadata(ctx, sessionID); err != nil {
+		return nil, err
+	}
+	if data.Runs, err = s.loadRuns(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	// Rewrite sessions stored by older releases in the current schema.
+	// Newer sessions are returned as is for the caller to reject.
+	if data.SchemaVersion < SchemaVersion {
+		from := data.SchemaVersion
+		if err := Upgrade(&data); err != nil {
+			return nil, err
+		}
+		if err := s.Save(ctx, &data); err != nil {
+			return nil, fmt.Errorf("failed to store upgraded session: %w", err)
+		}
+		logger.Logger.Debug("Session upgraded", "id", data.ID, "from", from, "to", data.SchemaVersion)
+		return &data, nil
+	}
+	if !touch {
+		return &data, nil
+	}
+
 	// Update last_access_at on load
 	data.LastAccessAt = time.Now()
 	updateQuery := `UPDATE sessions SET last_access_at = ? WHERE id = ?`
@@ -218,22 +332,144 @@ func (s *Store) Load(ctx context.Context, sessionID string) (*SessionData, error
 	return &data, nil
 }
 
+func decodeSkipped(raw sql.NullString) []string {
+	if !raw.Valid || raw.String == "" {
+		return nil
+	}
+	var out []string
+	if err := json.Unmarshal([]byte(raw.String), &out); err != nil {
+		logger.Logger.Warn("Ignoring malformed skipped analyses", "error", err)
+		return nil
+	}
+	return out
+}
+
+func encodeFingerprint(f *Fingerprint) (sql.NullString, error) {
+	if f == nil {
+		return sql.NullString{}, nil
+	}
+	raw, err := json.Marshal(f)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to encode fingerprint: %w", err)
+	}
+	return sql.NullString{String: string(raw), Valid: true}, nil
+}
+
+func decodeFingerprint(raw sql.NullString) (*Fingerprint, error) {
+	if raw.String == "" {
+		return nil, nil
+	}
+	var f Fingerprint
+	if err := json.Unmarshal([]byte(raw.String), &f); err != nil {
+		return nil, fmt.Errorf("failed to parse fingerprint: %w", err)
+	}
+	return &f, nil
+}
+
+func (s *Store) loadTokenMetadata(ctx context.Context, sessionID string) (map[string]tokenflow.TokenMeta, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT contract_id, metadata_json FROM token_metadata WHERE session_id = ?`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token metadata: %w", err)
+	}
+	defer rows.Close()
+
+	var out map[string]tokenflow.TokenMeta
+	for rows.Next() {
+		var contractID, metaJSON string
+		if err := rows.Scan(&contractID, &metaJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan token metadata: %w", err)
+		}
+		var meta tokenflow.TokenMeta
+		if err := json.Unmarshal([]byte(metaJSON), &meta); err != nil {
+			logger.Logger.Warn("Skipping malformed token metadata", "contract", contractID, "error", err)
+			continue
+		}
+		if out == nil {
+			out = make(map[string]tokenflow.TokenMeta)
+		}
+		out[contractID] = meta
+	}
+	return out, rows.Err()
+}
+
+func (s *Store) loadRuns(ctx context.Context, sessionID string) ([]Run, error) {
+	rows, err := s.db.QueryContext(ctx, `
+	SELECT name, created_at, description, sim_request_json, sim_response_json, output_dir, artifacts_json, fingerprint_json
+	FROM session_runs WHERE session_id = ? ORDER BY created_at, name`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Run
+	for rows.Next() {
+		var run Run
+		var createdAt string
+		var description, outputDir, artifacts, fingerprint sql.NullString
+		if err := rows.Scan(&run.Name, &createdAt, &description, &run.SimRequestJSON, &run.SimResponseJSON, &outputDir, &artifacts, &fingerprint); err != nil {
+			return nil, fmt.Errorf("failed to scan checkpoint: %w", err)
+		}
+		if run.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+			return nil, fmt.Errorf("failed to parse checkpoint created_at: %w", err)
+		}
+		run.Description = description.String
+		run.OutputDir = outputDir.String
+		if artifacts.String != "" {
+			if err := json.Unmarshal([]byte(artifacts.String), &run.Artifacts); err != nil {
+				return nil, fmt.Errorf("failed to parse artifacts of checkpoint %s: %w", run.Name, err)
+			}
+		}
+		if run.Fingerprint, err = decodeFingerprint(fingerprint); err != nil {
+			return nil, err
+		}
+		out = append(out, run)
+	}
+	return out, rows.Err()
+}
+
 // List returns recent sessions, ordered by last_access_at descending
 func (s *Store) List(ctx context.Context, limit int) ([]*SessionData, error) {
+	return s.ListByContext(ctx, Context{}, limit)
+}
+
+// ListByContext returns recent sessions whose context has every field set
+// in filter, ordered by last_access_at descending
+func (s *Store) ListByContext(ctx context.Context, filter Context, limit int) ([]*SessionData, error) {
 	if limit <= 0 {
 		limit = 50
 	}
 
+	var where []string
+	var args []interface{}
+	if filter.Anchor != "" {
+		where = append(where, "anchor = ? COLLATE NOCASE")
+		args = append(args, filter.Anchor)
+	}
+	if filter.SEPFlow != "" {
+		where = append(where, "sep_flow = ?")
+		args = append(args, filter.SEPFlow)
+	}
+	if filter.CustomerRef != "" {
+		where = append(where, "customer_ref = ?")
+		args = append(args, filter.CustomerRef)
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = "WHERE " + strings.Join(where, " AND ")
+	}
+
 	query := `
 	SELECT id, created_at, last_access_at, status, network, horizon_url, tx_hash,
 	       envelope_xdr, result_xdr, result_meta_xdr,
-	       sim_request_json, sim_response_json, erst_version, schema_version
+	       sim_request_json, sim_response_json, erst_version, schema_version,
+	       partial, skipped_analyses, anchor, sep_flow, customer_ref, fingerprint_json
 	FROM sessions
+	` + clause + `
 	ORDER BY last_access_at DESC
 	LIMIT ?
 	`
 
-	rows, err := s.db.QueryContext(ctx, query, limit)
+	rows, err := s.db.QueryContext(ctx, query, append(args, limit)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sessions: %w", err)
 	}
@@ -243,6 +479,7 @@ func (s *Store) List(ctx context.Context, limit int) ([]*SessionData, error) {
 	for rows.Next() {
 		var data SessionData
 		var createdAt, lastAccessAt string
+		var skipped, anchor, sepFlow, customerRef, fingerprint sql.NullString
 
 		err := rows.Scan(
 			&data.ID, &createdAt, &lastAccessAt, &data.Status,
@@ -250,10 +487,16 @@ func (s *Store) List(ctx context.Context, limit int) ([]*SessionData, error) {
 			&data.EnvelopeXdr, &data.ResultXdr, &data.ResultMetaXdr,
 			&data.SimRequestJSON, &data.SimResponseJSON,
 			&data.ErstVersion, &data.SchemaVersion,
+			&data.Partial, &skipped, &anchor, &sepFlow, &customerRef, &fingerprint,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan session: %w", err)
 		}
+		data.SkippedAnalyses = decodeSkipped(skipped)
+		data.Context = Context{Anchor: anchor.String, SEPFlow: sepFlow.String, CustomerRef: customerRef.String}
+		if data.Fingerprint, err = decodeFingerprint(fingerprint); err != nil {
+			return nil, err
+		}
 
 		// Parse timestamps
 		if data.CreatedAt, err = time.Parse(time.RFC3339, createdAt); err != nil {
@@ -263,6 +506,12 @@ func (s *Store) List(ctx context.Context, limit int) ([]*SessionData, error) {
 			return nil, fmt.Errorf("failed to parse last_access_at: %w", err)
 		}
 
+		if data.SchemaVersion < SchemaVersion {
+			if err := Upgrade(&data); err != nil {
+				return nil, err
+			}
+		}
+
 		sessions = append(sessions, &data)
 	}
 
@@ -273,7 +522,7 @@ func (s *Store) List(ctx context.Context, limit int) ([]*SessionData, error) {
 	return sessions, nil
 }
 
-// Delete removes a session by ID
+// Delete removes a session by ID, whether live or archived
 func (s *Store) Delete(ctx context.Context, sessionID string) error {
 	query := `DELETE FROM sessions WHERE id = ?`
 	result, err := s.db.ExecContext(ctx, query, sessionID)
@@ -287,7 +536,14 @@ func (s *Store) Delete(ctx context.Context, sessionID string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("session not found: %s", sessionID)
+		return s.deleteArchived(ctx, sessionID)
+	}
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM token_metadata WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete token metadata: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM session_runs WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("failed to delete checkpoints: %w", err)
 	}
 
 	logger.Logger.Debug("Session deleted", "id", sessionID)
@@ -341,6 +597,13 @@ func (s *Store) Cleanup(ctx context.Context, ttl time.Duration, maxSessions int)
 		}
 	}
 
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM token_metadata WHERE session_id NOT IN (SELECT id FROM sessions)`); err != nil {
+		return fmt.Errorf("failed to delete orphaned token metadata: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM session_runs WHERE session_id NOT IN (SELECT id FROM sessions)`); err != nil {
+		return fmt.Errorf("failed to delete orphaned checkpoints: %w", err)
+	}
+
 	return nil
 }
 
@@ -387,6 +650,7 @@ func (s *SessionData) ToSimulationResponse() (*simulator.SimulationResponse, err
 	if err := json.Unmarshal([]byte(s.SimResponseJSON), &resp); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal simulation response: %w", err)
 	}
+	resp.Normalize()
 
 	return &resp, nil
 }
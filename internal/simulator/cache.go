@@ -0,0 +1,199 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/dotandev/hintents/internal/cache"
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
+)
+
+// DefaultCacheMaxBytes bounds the simulation cache of NewCachingRunner
+const DefaultCacheMaxBytes = 256 * 1024 * 1024
+
+// CachingRunner reuses the responses of earlier simulations with identical
+// inputs. Replays of on-chain baselines are deterministic for a given
+// request and simulator build, so iterating on a local WASM fix only pays
+// for the candidate replays.
+//
+// Only completed simulations are cached, including ones whose transaction
+// failed; runner errors such as crashes and timeouts are never cached.
+type CachingRunner struct {
+	Runner RunnerInterface
+	Dir    string
+	// Identity distinguishes simulator builds, so a new build never reuses
+	// results of an old one
+	Identity string
+	// MaxBytes bounds the size of Dir; past it the least recently used
+	// responses are evicted. Zero leaves the cache unbounded.
+	MaxBytes int64
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// Compile-time check to ensure CachingRunner implements RunnerInterface
+var _ RunnerInterface = (*CachingRunner)(nil)
+
+// NewCachingRunner caches the responses of runner under the erst cache
+// directory. The binary path, size and modification time of a *Runner
+// identify its build.
+func NewCachingRunner(runner RunnerInterface) (*CachingRunner, error) {
+	dir, err := platform.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	identity := ""
+	if r, ok := runner.(*Runner); ok {
+		identity = BinaryIdentity(r.BinaryPath)
+	}
+	return &CachingRunner{
+		Runner:   runner,
+		Dir:      filepath.Join(dir, "cache", "simulations"),
+		Identity: identity,
+		MaxBytes: DefaultCacheMaxBytes,
+	}, nil
+}
+
+// BinaryIdentity describes the simulator binary at path by its location,
+// size and modification time
+func BinaryIdentity(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return path
+	}
+	return fmt.Sprintf("%s|%d|%d", path, info.Size(), info.ModTime().UnixNano())
+}
+
+// BinaryDigest returns the SHA-256 of the simulator binary at path. Unlike
+// BinaryIdentity it is the same for every copy of a build.
+func BinaryDigest(path string) (string, error) {
+	return fileSHA256(path)
+}
+
+// Run returns the cached response for req, or runs the simulation and
+// caches its response
+func (c *CachingRunner) Run(req *SimulationRequest) (*SimulationResponse, error) {
+	key, err := c.key(req)
+	if err != nil {
+		c.misses.Add(1)
+		return c.Runner.Run(req)
+	}
+	path := filepath.Join(c.Dir, key+".json")
+
+	if data, err := os.ReadFile(path); err == nil {
+		var resp SimulationResponse
+		if err := json.Unmarshal(data, &resp); err == nil {
+			c.hits.Add(1)
+			// The modification time records the last use for eviction
+			now := time.Now()
+			_ = os.Chtimes(path, now, now)
+			logger.Logger.Debug("Reusing cached simulation", "key", key)
+			return &resp, nil
+		}
+		logger.Logger.Warn("Cached simulation corrupted, running again", "key", key)
+	}
+
+	c.misses.Add(1)
+	resp, err := c.Runner.Run(req)
+	if err != nil {
+		return resp, err
+	}
+	if err := c.store(path, resp); err != nil {
+		logger.Logger.Warn("Failed to cache simulation", "error", err)
+	}
+	c.evict()
+	return resp, nil
+}
+
+// evict removes the least recently used responses until the cache fits in
+// MaxBytes
+func (c *CachingRunner) evict() {
+	if c.MaxBytes <= 0 {
+		return
+	}
+	files, err := cache.NewManager(c.Dir, cache.Config{MaxSizeBytes: c.MaxBytes}).ListCachedFiles()
+	if err != nil {
+		logger.Logger.Warn("Failed to list simulation cache", "error", err)
+		return
+	}
+	var total int64
+	for _, f := range files {
+		total += f.Size
+	}
+	cache.SortFilesByAccessTime(files)
+	for _, f := range files {
+		if total <= c.MaxBytes {
+			return
+		}
+		// Responses other replays are still writing
+		if strings.HasPrefix(filepath.Base(f.Path), ".sim-") {
+			continue
+		}
+		if err := os.Remove(f.Path); err == nil || os.IsNotExist(err) {
+			total -= f.Size
+		}
+	}
+}
+
+// Stats returns the number of simulations served from the cache and run
+func (c *CachingRunner) Stats() (hits, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+// key hashes every input of req together with the simulator identity
+func (c *CachingRunner) key(req *SimulationRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(c.Identity))
+	h.Write([]byte{0})
+	h.Write(data)
+	if req.WasmPath != nil {
+		// The request only names the file; hash what it contains
+		code, err := os.ReadFile(*req.WasmPath)
+		if err != nil {
+			return "", err
+		}
+		h.Write(code)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (c *CachingRunner) store(path string, resp *SimulationResponse) error {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create simulation cache directory: %w", err)
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return err
+	}
+	// Concurrent replays may store the same key; each writes its own file
+	tmp, err := os.CreateTemp(c.Dir, ".sim-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachingRunner(t *testing.T) {
+	calls := 0
+	failNext := false
+	mock := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		calls++
+		if failNext {
+			return nil, errors.New("simulator crashed")
+		}
+		return &SimulationResponse{Status: "success", Error: req.EnvelopeXdr}, nil
+	})
+	c := &CachingRunner{Runner: mock, Dir: t.TempDir(), Identity: "v1"}
+
+	req := &SimulationRequest{EnvelopeXdr: "AAAA", LedgerEntries: map[string]string{"k": "v"}}
+	first, err := c.Run(req)
+	require.NoError(t, err)
+	second, err := c.Run(&SimulationRequest{EnvelopeXdr: "AAAA", LedgerEntries: map[string]string{"k": "v"}})
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, calls, "identical inputs are served from the cache")
+
+	_, err = c.Run(&SimulationRequest{EnvelopeXdr: "AAAA", LedgerEntries: map[string]string{"k": "v2"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "different ledger state runs again")
+
+	other := &CachingRunner{Runner: mock, Dir: c.Dir, Identity: "v2"}
+	_, err = other.Run(req)
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls, "a different simulator build runs again")
+
+	failNext = true
+	_, err = c.Run(&SimulationRequest{EnvelopeXdr: "BBBB"})
+	assert.Error(t, err)
+	failNext = false
+	_, err = c.Run(&SimulationRequest{EnvelopeXdr: "BBBB"})
+	require.NoError(t, err)
+	assert.Equal(t, 5, calls, "runner errors are not cached")
+
+	hits, misses := c.Stats()
+	assert.Equal(t, int64(1), hits)
+	assert.Equal(t, int64(4), misses)
+}
+
+func TestCachingRunner_WasmContent(t *testing.T) {
+	calls := 0
+	mock := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		calls++
+		return &SimulationResponse{Status: "success"}, nil
+	})
+	c := &CachingRunner{Runner: mock, Dir: t.TempDir()}
+
+	wasm := filepath.Join(t.TempDir(), "contract.wasm")
+	require.NoError(t, os.WriteFile(wasm, []byte("v1"), 0600))
+	req := &SimulationRequest{WasmPath: &wasm}
+	_, err := c.Run(req)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(wasm, []byte("v2"), 0600))
+	_, err = c.Run(req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "a rebuilt WASM at the same path runs again")
+}
+
+func TestCachingRunner_EvictsLeastRecentlyUsed(t *testing.T) {
+	calls := 0
+	mock := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		calls++
+		return &SimulationResponse{Status: "success", Error: req.EnvelopeXdr}, nil
+	})
+	c := &CachingRunner{Runner: mock, Dir: t.TempDir()}
+
+	run := func(env string) {
+		_, err := c.Run(&SimulationRequest{EnvelopeXdr: env})
+		require.NoError(t, err)
+	}
+	run("AAAA")
+	files, err := os.ReadDir(c.Dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	info, err := files[0].Info()
+	require.NoError(t, err)
+	// Room for two responses
+	c.MaxBytes = 2*info.Size() + 1
+
+	first := files[0].Name()
+	run("BBBB")
+	// Back-date both so the order does not depend on timestamp resolution
+	files, err = os.ReadDir(c.Dir)
+	require.NoError(t, err)
+	for _, f := range files {
+		old := time.Now().Add(-time.Hour)
+		if f.Name() == first {
+			old = old.Add(-time.Hour)
+		}
+		require.NoError(t, os.Chtimes(filepath.Join(c.Dir, f.Name()), old, old))
+	}
+	run("AAAA") // a hit marks AAAA as recently used
+	run("CCCC") // evicts BBBB
+	assert.Equal(t, 3, calls)
+
+	files, err = os.ReadDir(c.Dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	run("AAAA")
+	assert.Equal(t, 3, calls, "the recently used response is kept")
+	run("BBBB")
+	assert.Equal(t, 4, calls, "the least recently used response is evicted")
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compatDir holds payloads recorded from previous erst-sim releases, one
+// directory per release. See testdata/compat/README.md.
+const compatDir = "testdata/compat"
+
+// TestSchemaCompatibility decodes every recorded payload with the current
+// structs. It fails when a payload no longer decodes, or when a value in it
+// is silently dropped, unless dropped.json lists the path with a reason.
+func TestSchemaCompatibility(t *testing.T) {
+	releases, err := os.ReadDir(compatDir)
+	require.NoError(t, err)
+
+	found := 0
+	for _, release := range releases {
+		if !release.IsDir() {
+			continue
+		}
+		dir := filepath.Join(compatDir, release.Name())
+		allowed := loadDropped(t, dir)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		require.NoError(t, err)
+		for _, path := range files {
+			name := filepath.Base(path)
+			var target any
+			switch {
+			case strings.HasPrefix(name, "request"):
+				target = &SimulationRequest{}
+			case strings.HasPrefix(name, "response"):
+				target = &SimulationResponse{}
+			default:
+				continue
+			}
+			found++
+
+			t.Run(release.Name()+"/"+name, func(t *testing.T) {
+				raw, err := os.ReadFile(path)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(raw, target), "payload no longer decodes")
+
+				reencoded, err := json.Marshal(target)
+				require.NoError(t, err)
+				dropped := droppedValues(t, raw, reencoded)
+
+				for _, p := range dropped {
+					if _, ok := allowed[name][p]; !ok {
+						t.Errorf("%s is dropped when decoding; keep the field or list it in dropped.json", p)
+					}
+				}
+				for p := range allowed[name] {
+					if !contains(dropped, p) {
+						t.Errorf("dropped.json lists %s, which is now decoded; remove the entry", p)
+					}
+				}
+			})
+		}
+	}
+	assert.NotZero(t, found, "no recorded payloads in %s", compatDir)
+}
+
+func TestDroppedValues(t *testing.T) {
+	// Renaming a field tag loses the value of the old name
+	type renamed struct {
+		Status string `json:"state"`
+	}
+	raw := []byte(`{"status":"success","events":["e1"],"budget":{"cpu":0}}`)
+	var r renamed
+	require.NoError(t, json.Unmarshal(raw, &r))
+	out, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/events/*", "/status"}, droppedValues(t, raw, out))
+}
+
+// loadDropped reads dropped.json of a release directory: for each payload
+// file, the paths known to be dropped and why
+func loadDropped(t *testing.T, dir string) map[string]map[string]string {
+	allowed := make(map[string]map[string]string)
+	raw, err := os.ReadFile(filepath.Join(dir, "dropped.json"))
+	if os.IsNotExist(err) {
+		return allowed
+	}
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(raw, &allowed))
+	return allowed
+}
+
+// droppedValues returns the paths of the non-zero values of original that
+// decoded no longer holds under the same top-level property, with array
+// indices written as *. Values are matched anywhere within that property so
+// that reshaping it, such as turning a string into an object holding it,
+// does not count as dropping them.
+func droppedValues(t *testing.T, original, decoded []byte) []string {
+	kept := make(map[string]bool)
+	walkLeaves(t, decode(t, decoded), "", func(path, value string) {
+		kept[topLevel(path)+" "+value] = true
+	})
+
+	seen := make(map[string]bool)
+	var dropped []string
+	walkLeaves(t, decode(t, original), "", func(path, value string) {
+		if !kept[topLevel(path)+" "+value] && !seen[path] {
+			seen[path] = true
+			dropped = append(dropped, path)
+		}
+	})
+	sort.Strings(dropped)
+	return dropped
+}
+
+func topLevel(path string) string {
+	top, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return top
+}
+
+func decode(t *testing.T, data []byte) any {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	require.NoError(t, dec.Decode(&v))
+	return v
+}
+
+// walkLeaves calls fn with the path and JSON encoding of each non-zero
+// scalar in v
+func walkLeaves(t *testing.T, v any, path string, fn func(path, value string)) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			walkLeaves(t, item, path+"/"+k, fn)
+		}
+	case []any:
+		for _, item := range val {
+			walkLeaves(t, item, path+"/*", fn)
+		}
+	case nil, bool:
+		if val == true {
+			fn(path, "true")
+		}
+	default:
+		if val == "" || val == json.Number("0") {
+			return
+		}
+		enc, err := json.Marshal(val)
+		require.NoError(t, err)
+		fn(path, string(enc))
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Event is a contract event emitted during simulation
+type Event struct {
+	Index      int      `json:"index"`
+	ID         string   `json:"id,omitempty"`
+	Type       string   `json:"type,omitempty"` // "contract", "system", "diagnostic"
+	ContractID string   `json:"contract_id,omitempty"`
+	Topics     []string `json:"topics,omitempty"`
+	Data       string   `json:"data,omitempty"`
+	Raw        string   `json:"raw,omitempty"` // Simulator's textual rendering
+}
+
+// UnmarshalJSON accepts both the structured form and the plain strings
+// emitted by older simulators and stored in older sessions
+func (e *Event) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err == nil {
+		*e = Event{Raw: raw}
+		return nil
+	}
+	type plain Event
+	return json.Unmarshal(b, (*plain)(e))
+}
+
+// String renders the event for display and text matching
+func (e Event) String() string {
+	if e.Raw != "" {
+		return e.Raw
+	}
+	return fmt.Sprintf("%s contract:%s topics:[%s] data:%s", e.Type, e.ContractID, strings.Join(e.Topics, ", "), e.Data)
+}
+
+// Typed reports whether the structured fields are populated
+func (e Event) Typed() bool {
+	return e.Type != "" || len(e.Topics) > 0 || e.Data != ""
+}
+
+// Equal reports whether two events carry the same content, ignoring their
+// position and ID
+func (e Event) Equal(o Event) bool {
+	if !e.Typed() || !o.Typed() {
+		return e.String() == o.String()
+	}
+	if e.Type != o.Type || e.ContractID != o.ContractID || e.Data != o.Data || len(e.Topics) != len(o.Topics) {
+		return false
+	}
+	for i := range e.Topics {
+		if e.Topics[i] != o.Topics[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// EventFilter selects events by contract, type and topics. Empty fields
+// match everything; every topic must match one of the event's topics,
+// case-insensitively and as a substring, so "transfer" matches a rendered
+// Symbol(transfer).
+type EventFilter struct {
+	ContractID string
+	Type       string
+	Topics     []string
+}
+
+// Match reports whether e passes the filter. Untyped events are matched
+// against their raw rendering.
+func (f EventFilter) Match(e Event) bool {
+	if f.ContractID != "" && !strings.EqualFold(e.ContractID, f.ContractID) &&
+		!(e.ContractID == "" && strings.Contains(strings.ToLower(e.Raw), strings.ToLower(f.ContractID))) {
+		return false
+	}
+	if f.Type != "" && e.Typed() && !strings.EqualFold(e.Type, f.Type) {
+		return false
+	}
+	for _, want := range f.Topics {
+		want = strings.ToLower(want)
+		found := false
+		if e.Typed() {
+			for _, topic := range e.Topics {
+				if strings.Contains(strings.ToLower(topic), want) {
+					found = true
+					break
+				}
+			}
+		} else {
+			found = strings.Contains(strings.ToLower(e.Raw), want)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the events passing f, in emission order
+func (f EventFilter) Filter(events []Event) []Event {
+	var out []Event
+	for _, e := range events {
+		if f.Match(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// LogEntry is a host debug log line
+type LogEntry struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// UnmarshalJSON accepts both the structured form and plain strings
+func (l *LogEntry) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err == nil {
+		*l = LogEntry{Message: raw}
+		return nil
+	}
+	type plain LogEntry
+	return json.Unmarshal(b, (*plain)(l))
+}
+
+// String returns the log message
+func (l LogEntry) String() string {
+	return l.Message
+}
+
+// NewEvents builds events from raw strings
+func NewEvents(raw ...string) []Event {
+	events := make([]Event, len(raw))
+	for i, r := range raw {
+		events[i] = Event{Index: i, Raw: r}
+	}
+	return events
+}
+
+// NewLogs builds log entries from raw strings
+func NewLogs(raw ...string) []LogEntry {
+	logs := make([]LogEntry, len(raw))
+	for i, r := range raw {
+		logs[i] = LogEntry{Index: i, Message: r}
+	}
+	return logs
+}
+
+// EventStrings returns the textual form of every event
+func (r *SimulationResponse) EventStrings() []string {
+	out := make([]string, len(r.Events))
+	for i, e := range r.Events {
+		out[i] = e.String()
+	}
+	return out
+}
+
+// LogStrings returns every log message
+func (r *SimulationResponse) LogStrings() []string {
+	out := make([]string, len(r.Logs))
+	for i, l := range r.Logs {
+		out[i] = l.Message
+	}
+	return out
+}
+
+// Normalize assigns event IDs and order indexes, fills the typed fields of
+// raw events from the matching structured diagnostic events, and decodes
+// the host's metering events. The simulator emits both lists in the same
+// order.
+func (r *SimulationResponse) Normalize() {
+	r.AssignEventIDs()
+
+	linked := len(r.Events) == len(r.DiagnosticEvents)
+	for i := range r.Events {
+		e := &r.Events[i]
+		e.Index = i
+		if !linked || e.Typed() {
+			continue
+		}
+		d := r.DiagnosticEvents[i]
+		e.ID = d.ID
+		e.Type = d.EventType
+		e.Topics = d.Topics
+		e.Data = d.Data
+		if d.ContractID != nil {
+			e.ContractID = *d.ContractID
+		}
+	}
+	for i := range r.Logs {
+		r.Logs[i].Index = i
+	}
+	r.Metering = DecodeMetering(r.DiagnosticEvents)
+}
+
+// UnmarshalJSON accepts both the flat form and the {"category", "event"}
+// form the Rust simulator emits
+func (c *CategorizedEvent) UnmarshalJSON(b []byte) error {
+	var nested struct {
+		Event *struct {
+			EventType  string   `json:"event_type"`
+			ContractID *string  `json:"contract_id"`
+			Topics     []string `json:"topics"`
+			Data       string   `json:"data"`
+		} `json:"event"`
+	}
+	if err := json.Unmarshal(b, &nested); err == nil && nested.Event != nil {
+		*c = CategorizedEvent{
+			EventType:  nested.Event.EventType,
+			ContractID: nested.Event.ContractID,
+			Topics:     nested.Event.Topics,
+			Data:       nested.Event.Data,
+		}
+		return nil
+	}
+	type plain CategorizedEvent
+	return json.Unmarshal(b, (*plain)(c))
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSimulationResponse_LegacyStringEvents(t *testing.T) {
+	contract := "CABC"
+	raw := `{"status":"success","events":["ev0","ev1"],"logs":["hello"],
+		"diagnostic_events":[
+			{"event_type":"contract","contract_id":"CABC","topics":["transfer"],"data":"100"},
+			{"event_type":"diagnostic","topics":["log"],"data":"x"}]}`
+
+	var resp SimulationResponse
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	resp.Normalize()
+
+	if len(resp.Events) != 2 || resp.Events[1].Index != 1 {
+		t.Fatalf("unexpected events: %+v", resp.Events)
+	}
+	first := resp.Events[0]
+	if first.Raw != "ev0" || first.Type != "contract" || first.ContractID != contract || first.Topics[0] != "transfer" || first.Data != "100" {
+		t.Errorf("event not linked to diagnostic event: %+v", first)
+	}
+	if first.ID == "" || first.ID != resp.DiagnosticEvents[0].ID {
+		t.Errorf("expected event to share the diagnostic event ID, got %q", first.ID)
+	}
+	if got := resp.LogStrings(); len(got) != 1 || got[0] != "hello" {
+		t.Errorf("unexpected logs: %v", got)
+	}
+
+	// Structured output round-trips
+	out, err := json.Marshal(&resp)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var again SimulationResponse
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatalf("unmarshal structured: %v", err)
+	}
+	if !again.Events[0].Equal(first) || again.Logs[0].Message != "hello" {
+		t.Errorf("round trip changed events: %+v", again.Events)
+	}
+}
+
+func TestEvent_Equal(t *testing.T) {
+	a := Event{Index: 0, Type: "contract", Topics: []string{"transfer"}, Data: "100", Raw: "a"}
+	b := Event{Index: 3, Type: "contract", Topics: []string{"transfer"}, Data: "100", Raw: "b"}
+	if !a.Equal(b) {
+		t.Error("typed events with the same content should be equal regardless of position")
+	}
+	b.Data = "200"
+	if a.Equal(b) {
+		t.Error("events with different data should differ")
+	}
+	if !NewEvents("x")[0].Equal(Event{Raw: "x"}) {
+		t.Error("untyped events should compare by text")
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	events := []Event{
+		{Index: 0, Type: "contract", ContractID: "CTOKEN", Topics: []string{"Symbol(transfer)", "Address(GA)"}, Data: "10"},
+		{Index: 1, Type: "contract", ContractID: "CTOKEN", Topics: []string{"Symbol(mint)"}, Data: "5"},
+		{Index: 2, Type: "diagnostic", ContractID: "COTHER", Topics: []string{"fn_call", "transfer"}},
+		{Index: 3, Raw: "legacy CTOKEN transfer event"},
+	}
+
+	got := EventFilter{Topics: []string{"TRANSFER"}}.Filter(events)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 transfer events, got %+v", got)
+	}
+
+	got = EventFilter{Topics: []string{"transfer"}, ContractID: "ctoken"}.Filter(events)
+	if len(got) != 2 || got[0].Index != 0 || got[1].Index != 3 {
+		t.Errorf("expected events 0 and 3, got %+v", got)
+	}
+
+	got = EventFilter{Type: "contract"}.Filter(events)
+	if len(got) != 3 {
+		t.Errorf("untyped events cannot be excluded by type, got %+v", got)
+	}
+
+	if got := (EventFilter{Topics: []string{"transfer", "mint"}}).Filter(events); len(got) != 0 {
+		t.Errorf("all topics must match, got %+v", got)
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// EventID derives a stable identifier for a diagnostic event from its
+// content. occurrence distinguishes identical events emitted more than once
+// in the same run.
+func EventID(ev DiagnosticEvent, occurrence int) string {
+	contract := ""
+	if ev.ContractID != nil {
+		contract = *ev.ContractID
+	}
+	parts := append([]string{ev.EventType, contract, ev.Data}, ev.Topics...)
+	h := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return fmt.Sprintf("E-%s-%d", hex.EncodeToString(h[:6]), occurrence)
+}
+
+// AssignEventIDs sets content-derived IDs on every diagnostic event. Events
+// keep their execution order; only the IDs are added.
+func (r *SimulationResponse) AssignEventIDs() {
+	seen := make(map[string]int)
+	for i := range r.DiagnosticEvents {
+		base := EventID(r.DiagnosticEvents[i], 0)
+		r.DiagnosticEvents[i].ID = EventID(r.DiagnosticEvents[i], seen[base])
+		seen[base]++
+	}
+}
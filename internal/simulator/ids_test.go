@@ -0,0 +1,26 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import "testing"
+
+func TestAssignEventIDs(t *testing.T) {
+	contract := "CABC"
+	ev := DiagnosticEvent{EventType: "contract", ContractID: &contract, Topics: []string{"transfer"}, Data: "100"}
+	other := DiagnosticEvent{EventType: "contract", ContractID: &contract, Topics: []string{"mint"}, Data: "100"}
+
+	resp := &SimulationResponse{DiagnosticEvents: []DiagnosticEvent{ev, other, ev}}
+	resp.AssignEventIDs()
+
+	ids := []string{resp.DiagnosticEvents[0].ID, resp.DiagnosticEvents[1].ID, resp.DiagnosticEvents[2].ID}
+	if ids[0] != EventID(ev, 0) || ids[2] != EventID(ev, 1) {
+		t.Errorf("unexpected IDs for repeated event: %v", ids)
+	}
+	if ids[0] == ids[1] || ids[0] == ids[2] {
+		t.Errorf("expected distinct IDs, got %v", ids)
+	}
+	if resp.DiagnosticEvents[1].Topics[0] != "mint" {
+		t.Error("events must keep execution order")
+	}
+}
@@ -55,6 +55,6 @@ type mockRunnerForTest struct{}
 func (m *mockRunnerForTest) Run(req *SimulationRequest) (*SimulationResponse, error) {
 	return &SimulationResponse{
 		Status: "success",
-		Events: []string{"mock-event"},
+		Events: NewEvents("mock-event"),
 	}, nil
 }
@@ -0,0 +1,86 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// zeroAccount is used as the source of read-only calls when none is given
+const zeroAccount = "GAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAWHF"
+
+// InvokeContract simulates a call of function on contractID with no
+// arguments against the given ledger state and returns the decoded result.
+// It is meant for read-only queries such as token metadata.
+func InvokeContract(runner RunnerInterface, source, contractID, function string, ledger map[string]string) (xdr.ScVal, error) {
+	envelope, err := buildInvokeEnvelope(source, contractID, function)
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+
+	resp, err := runner.Run(&SimulationRequest{EnvelopeXdr: envelope, LedgerEntries: ledger})
+	if err != nil {
+		return xdr.ScVal{}, fmt.Errorf("failed to simulate %s: %w", function, err)
+	}
+	if resp.Status == "error" {
+		return xdr.ScVal{}, fmt.Errorf("%s failed: %s", function, resp.Error)
+	}
+	if resp.ReturnValue == "" {
+		return xdr.ScVal{}, errors.New("simulator returned no value")
+	}
+
+	var val xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(resp.ReturnValue, &val); err != nil {
+		return xdr.ScVal{}, fmt.Errorf("failed to decode return value: %w", err)
+	}
+	return val, nil
+}
+
+func buildInvokeEnvelope(source, contractID, function string) (string, error) {
+	if source == "" {
+		source = zeroAccount
+	}
+	account, err := xdr.AddressToMuxedAccount(source)
+	if err != nil {
+		return "", fmt.Errorf("invalid source account: %w", err)
+	}
+
+	raw, err := strkey.Decode(strkey.VersionByteContract, contractID)
+	if err != nil {
+		return "", fmt.Errorf("invalid contract ID: %w", err)
+	}
+	var id xdr.ContractId
+	copy(id[:], raw)
+
+	op := xdr.Operation{Body: xdr.OperationBody{
+		Type: xdr.OperationTypeInvokeHostFunction,
+		InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+			HostFunction: xdr.HostFunction{
+				Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+				InvokeContract: &xdr.InvokeContractArgs{
+					ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id},
+					FunctionName:    xdr.ScSymbol(function),
+					Args:            []xdr.ScVal{},
+				},
+			},
+		},
+	}}
+
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: account,
+			Fee:           100,
+			SeqNum:        1,
+			Cond:          xdr.Preconditions{Type: xdr.PreconditionTypePrecondNone},
+			Memo:          xdr.Memo{Type: xdr.MemoTypeMemoNone},
+			Operations:    []xdr.Operation{op},
+		}},
+	}
+	return xdr.MarshalBase64(env)
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func TestInvokeContract(t *testing.T) {
+	const contract = "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4"
+	d := xdr.Uint32(7)
+	ret, err := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &d})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
+		var env xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(req.EnvelopeXdr, &env); err != nil {
+			t.Fatalf("invalid envelope: %v", err)
+		}
+		fn := env.V1.Tx.Operations[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract.FunctionName
+		if fn != "decimals" || req.LedgerEntries["k"] != "v" {
+			t.Errorf("unexpected request: fn=%s ledger=%v", fn, req.LedgerEntries)
+		}
+		return &SimulationResponse{Status: "success", ReturnValue: ret}, nil
+	})
+
+	val, err := InvokeContract(runner, "", contract, "decimals", map[string]string{"k": "v"})
+	if err != nil {
+		t.Fatalf("InvokeContract: %v", err)
+	}
+	if val.U32 == nil || *val.U32 != 7 {
+		t.Errorf("unexpected value: %+v", val)
+	}
+
+	if _, err := InvokeContract(runner, "", "not-a-contract", "decimals", nil); err == nil {
+		t.Error("expected an error for an invalid contract ID")
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/errors"
+)
+
+// Limits caps the resources a simulator process may use. Zero values mean
+// unlimited. On Unix CPU time and memory are applied as rlimits, on Windows
+// through a job object; the output cap is enforced by erst on every
+// platform.
+type Limits struct {
+	// CPUTime is the processor time the simulator may consume
+	CPUTime time.Duration
+	// MemoryBytes is the address space (Unix) or committed memory (Windows)
+	// the simulator may use
+	MemoryBytes uint64
+	// OutputBytes caps what the simulator may write to stdout and stderr
+	// together
+	OutputBytes int64
+}
+
+// IsZero reports whether no limit is set
+func (l Limits) IsZero() bool {
+	return l.CPUTime <= 0 && l.MemoryBytes == 0 && l.OutputBytes <= 0
+}
+
+func (l Limits) String() string {
+	var parts []string
+	if l.CPUTime > 0 {
+		parts = append(parts, fmt.Sprintf("cpu %s", l.CPUTime))
+	}
+	if l.MemoryBytes > 0 {
+		parts = append(parts, fmt.Sprintf("memory %d MB", l.MemoryBytes>>20))
+	}
+	if l.OutputBytes > 0 {
+		parts = append(parts, fmt.Sprintf("output %s", formatBytes(l.OutputBytes)))
+	}
+	if len(parts) == 0 {
+		return "unlimited"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Tighten returns l with every limit set in o that is stricter than, or
+// missing from, l
+func (l Limits) Tighten(o Limits) Limits {
+	if o.CPUTime > 0 && (l.CPUTime <= 0 || o.CPUTime < l.CPUTime) {
+		l.CPUTime = o.CPUTime
+	}
+	if o.MemoryBytes > 0 && (l.MemoryBytes == 0 || o.MemoryBytes < l.MemoryBytes) {
+		l.MemoryBytes = o.MemoryBytes
+	}
+	if o.OutputBytes > 0 && (l.OutputBytes <= 0 || o.OutputBytes < l.OutputBytes) {
+		l.OutputBytes = o.OutputBytes
+	}
+	return l
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%d MB", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("%d KB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
+// Resources a simulation can exceed
+const (
+	ResourceCPU      = "cpu"
+	ResourceMemory   = "memory"
+	ResourceOutput   = "output"
+	ResourceWallTime = "wall_time"
+)
+
+// LimitError reports a simulation that was stopped for exceeding a resource
+// limit. It matches errors.ErrResourceLimit.
+type LimitError struct {
+	Resource string `json:"resource"`
+	Limit    string `json:"limit"`
+	Message  string `json:"message"`
+}
+
+func newLimitError(resource, limit string) *LimitError {
+	msg := fmt.Sprintf("simulator exceeded its %s limit of %s and was stopped", strings.ReplaceAll(resource, "_", " "), limit)
+	if resource == ResourceWallTime {
+		msg = fmt.Sprintf("simulator execution exceeded time limit of %s", limit)
+	}
+	return &LimitError{Resource: resource, Limit: limit, Message: msg}
+}
+
+func (e *LimitError) Error() string { return e.Message }
+
+func (e *LimitError) Unwrap() error { return errors.ErrResourceLimit }
+
+// breachedLimit names the limit that stopped the simulator, or returns ""
+// when it failed for another reason. Rust aborts on a failed allocation,
+// which is how the memory limit usually shows.
+func breachedLimit(state *os.ProcessState, stderr string, l Limits) string {
+	if l.MemoryBytes > 0 && strings.Contains(stderr, "memory allocation of") {
+		return ResourceMemory
+	}
+	return killedForLimit(state, l)
+}
+
+// outputCap keeps the simulator's stdout and stderr within a shared byte
+// budget and calls stop once when it is exhausted. Output beyond the cap
+// is discarded so the process is not blocked before it is killed.
+type outputCap struct {
+	mu       sync.Mutex
+	max      int64
+	used     int64
+	exceeded bool
+	stop     func()
+}
+
+func (c *outputCap) writer(buf *bytes.Buffer) io.Writer {
+	return cappedWriter{c, buf}
+}
+
+// Exceeded reports whether the simulator wrote more than the cap
+func (c *outputCap) Exceeded() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.exceeded
+}
+
+type cappedWriter struct {
+	cap *outputCap
+	buf *bytes.Buffer
+}
+
+func (w cappedWriter) Write(p []byte) (int, error) {
+	c := w.cap
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.max <= 0 {
+		return w.buf.Write(p)
+	}
+	room := c.max - c.used
+	if int64(len(p)) > room {
+		if room > 0 {
+			w.buf.Write(p[:room])
+			c.used += room
+		}
+		if !c.exceeded {
+			c.exceeded = true
+			c.stop()
+		}
+		return len(p), nil
+	}
+	c.used += int64(len(p))
+	return w.buf.Write(p)
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// limitedCommand runs bin with args through the shell so rlimits are in
+// place before the simulator starts
+func limitedCommand(ctx context.Context, bin string, l Limits, args ...string) *exec.Cmd {
+	if l.CPUTime <= 0 && l.MemoryBytes == 0 {
+		return exec.CommandContext(ctx, bin, args...)
+	}
+
+	var script []string
+	if l.CPUTime > 0 {
+		secs := int64((l.CPUTime + 999_999_999) / 1_000_000_000)
+		script = append(script, fmt.Sprintf("ulimit -t %d", secs))
+	}
+	if l.MemoryBytes > 0 {
+		script = append(script, fmt.Sprintf("ulimit -v %d", (l.MemoryBytes+1023)/1024))
+	}
+	script = append(script, `exec "$0" "$@"`)
+	return exec.CommandContext(ctx, "/bin/sh", append([]string{"-c", strings.Join(script, " && "), bin}, args...)...)
+}
+
+// runLimited runs cmd; rlimits were already set up by limitedCommand
+func runLimited(cmd *exec.Cmd, _ Limits) error {
+	return cmd.Run()
+}
+
+// startLimited starts cmd. There is nothing to release once it exits.
+func startLimited(cmd *exec.Cmd, _ Limits) (func(), error) {
+	return func() {}, cmd.Start()
+}
+
+// killedForLimit reports which rlimit made the kernel stop the process:
+// SIGXCPU at the soft CPU limit, SIGKILL at the hard one
+func killedForLimit(state *os.ProcessState, l Limits) string {
+	if state == nil {
+		return ""
+	}
+	ws, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return ""
+	}
+	switch ws.Signal() {
+	case syscall.SIGXCPU, syscall.SIGKILL:
+		if l.CPUTime > 0 {
+			return ResourceCPU
+		}
+	case syscall.SIGSEGV, syscall.SIGABRT:
+		if l.MemoryBytes > 0 {
+			return ResourceMemory
+		}
+	}
+	return ""
+}
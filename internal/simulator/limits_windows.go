@@ -0,0 +1,135 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build windows
+
+package simulator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// limitedCommand creates the simulator command, suspended when limits are
+// set so that it cannot run before runLimited has placed it in a job object
+func limitedCommand(ctx context.Context, bin string, l Limits, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, bin, args...)
+	if l.CPUTime > 0 || l.MemoryBytes > 0 {
+		cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: windows.CREATE_SUSPENDED}
+	}
+	return cmd
+}
+
+// runLimited runs cmd under l. The job is closed when the simulator exits,
+// killing anything it spawned.
+func runLimited(cmd *exec.Cmd, l Limits) error {
+	release, err := startLimited(cmd, l)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return cmd.Wait()
+}
+
+// startLimited starts cmd suspended, assigns it to a job object enforcing l
+// and only then resumes it. The returned func closes the job once the
+// simulator has exited.
+func startLimited(cmd *exec.Cmd, l Limits) (func(), error) {
+	if l.CPUTime <= 0 && l.MemoryBytes == 0 {
+		return func() {}, cmd.Start()
+	}
+
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job object: %w", err)
+	}
+	release := func() { windows.CloseHandle(job) }
+
+	var info windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+	info.BasicLimitInformation.LimitFlags = windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE
+	if l.MemoryBytes > 0 {
+		info.BasicLimitInformation.LimitFlags |= windows.JOB_OBJECT_LIMIT_PROCESS_MEMORY
+		info.ProcessMemoryLimit = uintptr(l.MemoryBytes)
+	}
+	if l.CPUTime > 0 {
+		info.BasicLimitInformation.LimitFlags |= windows.JOB_OBJECT_LIMIT_PROCESS_TIME
+		// Expressed in 100-nanosecond ticks
+		info.BasicLimitInformation.PerProcessUserTimeLimit = l.CPUTime.Nanoseconds() / 100
+	}
+	if _, err := windows.SetInformationJobObject(job, windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+		release()
+		return nil, fmt.Errorf("failed to set job object limits: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		release()
+		return nil, err
+	}
+
+	proc, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(cmd.Process.Pid))
+	if err == nil {
+		err = windows.AssignProcessToJobObject(job, proc)
+		windows.CloseHandle(proc)
+	}
+	if err == nil {
+		err = resumeProcess(uint32(cmd.Process.Pid))
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		release()
+		return nil, fmt.Errorf("failed to apply simulator limits: %w", err)
+	}
+	return release, nil
+}
+
+// resumeProcess resumes the threads of a process created suspended
+func resumeProcess(pid uint32) error {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPTHREAD, 0)
+	if err != nil {
+		return fmt.Errorf("failed to list simulator threads: %w", err)
+	}
+	defer windows.CloseHandle(snapshot)
+
+	entry := windows.ThreadEntry32{Size: uint32(unsafe.Sizeof(windows.ThreadEntry32{}))}
+	resumed := false
+	for err = windows.Thread32First(snapshot, &entry); err == nil; err = windows.Thread32Next(snapshot, &entry) {
+		if entry.OwnerProcessID != pid {
+			continue
+		}
+		thread, err := windows.OpenThread(windows.THREAD_SUSPEND_RESUME, false, entry.ThreadID)
+		if err != nil {
+			return fmt.Errorf("failed to open simulator thread: %w", err)
+		}
+		_, err = windows.ResumeThread(thread)
+		windows.CloseHandle(thread)
+		if err != nil {
+			return fmt.Errorf("failed to resume simulator: %w", err)
+		}
+		resumed = true
+	}
+	if !resumed {
+		return fmt.Errorf("failed to resume simulator: no threads found for process %d", pid)
+	}
+	return nil
+}
+
+// killedForLimit reports which job object limit stopped the process.
+// Windows ends a process over its CPU time limit with ERROR_NOT_ENOUGH_QUOTA.
+func killedForLimit(state *os.ProcessState, l Limits) string {
+	if state == nil {
+		return ""
+	}
+	const errorNotEnoughQuota = 1816
+	if l.CPUTime > 0 && state.ExitCode() == errorNotEnoughQuota {
+		return ResourceCPU
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
+)
+
+// PinnedDigests is the expected SHA-256 of the managed erst-sim per
+// platform, as comma-separated platform=digest pairs. Release builds set it
+// with -ldflags "-X github.com/dotandev/hintents/internal/simulator.PinnedDigests=...".
+// Compiled into erst, it cannot be replaced together with the binary.
+var PinnedDigests string
+
+const sourceManaged = "managed directory"
+
+// ErrChecksumMismatch is returned when erst-sim does not match its pinned
+// checksum
+var ErrChecksumMismatch = errors.New("simulator checksum mismatch")
+
+// Manifest pins the expected SHA-256 of erst-sim per platform
+type Manifest struct {
+	Version string `json:"version,omitempty"`
+	// Binaries maps a platform key such as "linux-arm64" to a hex SHA-256
+	// digest
+	Binaries map[string]string `json:"binaries"`
+}
+
+// LoadManifest reads a checksum manifest from path
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read simulator manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to parse simulator manifest %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// Verify checks the binary at path against the digest pinned for the
+// running platform
+func (m *Manifest) Verify(path string) error {
+	key := platform.Current().String()
+	want, ok := m.Binaries[key]
+	if !ok {
+		return fmt.Errorf("%w: manifest has no entry for %s", ErrChecksumMismatch, key)
+	}
+	got, err := fileSHA256(path)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(got, want) {
+		return fmt.Errorf("%w: %s has sha256 %s, expected %s; reinstall erst-sim or update the manifest",
+			ErrChecksumMismatch, path, got, want)
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open simulator binary: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to hash simulator binary: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// pinnedManifest parses PinnedDigests, returning nil when the build pins
+// no digests
+func pinnedManifest(digests string) (*Manifest, error) {
+	if strings.TrimSpace(digests) == "" {
+		return nil, nil
+	}
+	m := &Manifest{Binaries: make(map[string]string)}
+	for _, pair := range strings.Split(digests, ",") {
+		key, digest, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" || digest == "" {
+			return nil, fmt.Errorf("invalid pinned simulator digest %q", pair)
+		}
+		m.Binaries[key] = digest
+	}
+	return m, nil
+}
+
+// verifyBinary checks path against the configured manifest. Without one,
+// binaries from the managed directory are checked against the digests
+// pinned at build time; other sources are the user's explicit choice and
+// are not checked.
+func verifyBinary(path, source, manifestPath string) error {
+	if manifestPath != "" {
+		m, err := LoadManifest(manifestPath)
+		if err != nil {
+			return err
+		}
+		if err := m.Verify(path); err != nil {
+			return err
+		}
+		logger.Logger.Debug("Simulator checksum verified", "path", path, "manifest", manifestPath)
+		return nil
+	}
+	if source != sourceManaged {
+		return nil
+	}
+
+	m, err := pinnedManifest(PinnedDigests)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		logger.Logger.Debug("This build pins no simulator digests; managed simulator not verified", "path", path)
+		return nil
+	}
+	if err := m.Verify(path); err != nil {
+		return err
+	}
+	logger.Logger.Debug("Simulator checksum verified against pinned digests", "path", path)
+	return nil
+}
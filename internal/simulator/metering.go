@@ -0,0 +1,92 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MeterPhase is the resource usage the host reported for one phase of a
+// transaction through its core_metrics diagnostic events
+type MeterPhase struct {
+	// Phase names the top-level contract function the metrics follow, or
+	// "transaction" when no call preceded them
+	Phase    string `json:"phase"`
+	CPUInsns uint64 `json:"cpu_insn"`
+	MemBytes uint64 `json:"mem_bytes"`
+	// Metrics holds every other counter by its host name, such as
+	// read_entry, write_key_byte or emit_event
+	Metrics map[string]uint64 `json:"metrics,omitempty"`
+}
+
+// innerValuePattern pulls the innermost value out of Debug-formatted ScVals
+// such as Symbol(ScSymbol(StringM(cpu_insn))) or U64(1234)
+var innerValuePattern = regexp.MustCompile(`([^()\s]+)\)*$`)
+
+func innerValue(s string) string {
+	if m := innerValuePattern.FindStringSubmatch(strings.TrimSpace(s)); m != nil {
+		return m[1]
+	}
+	return s
+}
+
+// DecodeMetering turns the core_metrics diagnostic events in events into
+// one MeterPhase per reporting phase. A phase ends when a metric repeats or
+// a new top-level contract call starts.
+func DecodeMetering(events []DiagnosticEvent) []MeterPhase {
+	var phases []MeterPhase
+	var current *MeterPhase
+	seen := make(map[string]bool)
+	function := "transaction"
+	depth := 0
+
+	for _, ev := range events {
+		if len(ev.Topics) == 0 {
+			continue
+		}
+		switch innerValue(ev.Topics[0]) {
+		case "fn_call":
+			if depth == 0 {
+				if len(ev.Topics) >= 3 {
+					function = innerValue(ev.Topics[2])
+				}
+				current = nil
+			}
+			depth++
+		case "fn_return":
+			if depth > 0 {
+				depth--
+			}
+		case "core_metrics":
+			if len(ev.Topics) < 2 {
+				continue
+			}
+			value, err := strconv.ParseUint(innerValue(ev.Data), 10, 64)
+			if err != nil {
+				continue
+			}
+			metric := innerValue(ev.Topics[1])
+			if current == nil || seen[metric] {
+				phases = append(phases, MeterPhase{Phase: function})
+				current = &phases[len(phases)-1]
+				seen = make(map[string]bool)
+			}
+			seen[metric] = true
+			switch metric {
+			case "cpu_insn":
+				current.CPUInsns = value
+			case "mem_byte", "mem_bytes":
+				current.MemBytes = value
+			default:
+				if current.Metrics == nil {
+					current.Metrics = make(map[string]uint64)
+				}
+				current.Metrics[metric] = value
+			}
+		}
+	}
+	return phases
+}
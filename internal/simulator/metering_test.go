@@ -0,0 +1,60 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func metric(name, value string) DiagnosticEvent {
+	return DiagnosticEvent{
+		EventType: "diagnostic",
+		Topics:    []string{"Symbol(ScSymbol(StringM(core_metrics)))", "Symbol(ScSymbol(StringM(" + name + ")))"},
+		Data:      "U64(" + value + ")",
+	}
+}
+
+func TestDecodeMetering(t *testing.T) {
+	events := []DiagnosticEvent{
+		{EventType: "diagnostic", Topics: []string{"Symbol(ScSymbol(StringM(fn_call)))", "Bytes(abcd)", "Symbol(ScSymbol(StringM(transfer)))"}},
+		{EventType: "diagnostic", Topics: []string{"Symbol(ScSymbol(StringM(fn_call)))", "Bytes(ef01)", "Symbol(ScSymbol(StringM(balance)))"}},
+		{EventType: "diagnostic", Topics: []string{"Symbol(ScSymbol(StringM(fn_return)))", "Symbol(ScSymbol(StringM(balance)))"}},
+		{EventType: "diagnostic", Topics: []string{"Symbol(ScSymbol(StringM(fn_return)))", "Symbol(ScSymbol(StringM(transfer)))"}},
+		metric("read_entry", "3"),
+		metric("cpu_insn", "1234567"),
+		metric("mem_byte", "89012"),
+		metric("emit_event", "1"),
+		// A repeated counter starts the next phase
+		metric("cpu_insn", "500"),
+		{EventType: "diagnostic", Topics: []string{"Symbol(ScSymbol(StringM(core_metrics)))", "Symbol(ScSymbol(StringM(mem_byte)))"}, Data: "not a number"},
+	}
+
+	got := DecodeMetering(events)
+	want := []MeterPhase{
+		{Phase: "transfer", CPUInsns: 1234567, MemBytes: 89012, Metrics: map[string]uint64{"read_entry": 3, "emit_event": 1}},
+		{Phase: "transfer", CPUInsns: 500},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeMetering = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeMeteringWithoutCalls(t *testing.T) {
+	got := DecodeMetering([]DiagnosticEvent{{Topics: []string{"core_metrics", "cpu_insn"}, Data: "42"}})
+	if len(got) != 1 || got[0].Phase != "transaction" || got[0].CPUInsns != 42 {
+		t.Errorf("unexpected phases %+v", got)
+	}
+	if DecodeMetering(nil) != nil {
+		t.Error("expected no phases without events")
+	}
+}
+
+func TestNormalizeDecodesMetering(t *testing.T) {
+	resp := &SimulationResponse{DiagnosticEvents: []DiagnosticEvent{metric("cpu_insn", "7")}}
+	resp.Normalize()
+	if len(resp.Metering) != 1 || resp.Metering[0].CPUInsns != 7 {
+		t.Errorf("expected metering to be decoded, got %+v", resp.Metering)
+	}
+}
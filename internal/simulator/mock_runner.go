@@ -23,8 +23,8 @@ func NewDefaultMockRunner() *MockRunner {
 		RunFunc: func(req *SimulationRequest) (*SimulationResponse, error) {
 			return &SimulationResponse{
 				Status: "success",
-				Events: []string{},
-				Logs:   []string{},
+				Events: []Event{},
+				Logs:   []LogEntry{},
 			}, nil
 		},
 	}
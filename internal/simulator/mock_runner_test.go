@@ -47,7 +47,7 @@ func TestMockRunnerCustomResponse(t *testing.T) {
 	expectedResp := &SimulationResponse{
 		Status: "failed",
 		Error:  "test error",
-		Events: []string{"event1", "event2"},
+		Events: NewEvents("event1", "event2"),
 	}
 	mock := NewMockRunner(func(req *SimulationRequest) (*SimulationResponse, error) {
 		return expectedResp, nil
@@ -5,19 +5,34 @@ package simulator
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/crash"
+	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
 )
 
 // Runner handles the execution of the Rust simulator binary
 type Runner struct {
 	BinaryPath string
 	Debug      bool
+	// Timeout bounds the wall-clock time of a single simulation (0 = unlimited)
+	Timeout time.Duration
+	// Limits caps the resources of the simulator process
+	Limits Limits
+	// NoCrashReports stops crash bundles from being saved, for callers such
+	// as the server that must not write to disk
+	NoCrashReports bool
 }
 
 // Compile-time check to ensure Runner implements RunnerInterface
@@ -27,15 +42,27 @@ var _ RunnerInterface = (*Runner)(nil)
 // Search order:
 // 1. --sim-path override
 // 2. ENV var
-// 3. Local directory
-// 4. Dev target
-// 5. Global PATH
+// 3. simulator_path in the config file
+// 4. Local directory
+// 5. Dev target
+// 6. Managed directory (~/.erst/bin)
+// 7. Global PATH
+//
+// The resolved binary is verified against a pinned checksum manifest when
+// one is configured, and resource limits from the environment or config
+// file are applied to every run.
 func NewRunner(simPathOverride string, debug bool) (*Runner, error) {
-	path, source, err := findSimBinary(simPathOverride)
+	settings := loadRunnerSettings()
+
+	path, source, err := findSimBinary(simPathOverride, settings.configPath)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := verifyBinary(path, source, settings.manifestPath); err != nil {
+		return nil, err
+	}
+
 	if debug {
 		logger.Logger.Debug(
 			"Simulator binary resolved",
@@ -47,33 +74,93 @@ func NewRunner(simPathOverride string, debug bool) (*Runner, error) {
 	return &Runner{
 		BinaryPath: path,
 		Debug:      debug,
+		Limits:     settings.limits,
 	}, nil
 }
 
 // -------------------- Binary Discovery --------------------
 
-func findSimBinary(simPathOverride string) (string, string, error) {
+// runnerSettings are the runner options read from the environment and the
+// config file
+type runnerSettings struct {
+	configPath   string
+	manifestPath string
+	limits       Limits
+}
+
+func loadRunnerSettings() runnerSettings {
+	var s runnerSettings
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	s.configPath = cfg.SimulatorPath
+	s.manifestPath = os.Getenv("ERST_SIM_MANIFEST")
+	if s.manifestPath == "" {
+		s.manifestPath = cfg.SimulatorManifest
+	}
+
+	s.limits.MemoryBytes = uint64(cfg.SimulatorMaxMemoryMB) << 20
+	s.limits.CPUTime = time.Duration(cfg.SimulatorMaxCPUSeconds) * time.Second
+	s.limits.OutputBytes = int64(cfg.SimulatorMaxOutputMB) << 20
+	if v, err := strconv.ParseUint(os.Getenv("ERST_SIM_MAX_MEMORY_MB"), 10, 64); err == nil {
+		s.limits.MemoryBytes = v << 20
+	}
+	if v, err := strconv.ParseUint(os.Getenv("ERST_SIM_MAX_CPU_SECONDS"), 10, 64); err == nil {
+		s.limits.CPUTime = time.Duration(v) * time.Second
+	}
+	if v, err := strconv.ParseInt(os.Getenv("ERST_SIM_MAX_OUTPUT_MB"), 10, 64); err == nil {
+		s.limits.OutputBytes = v << 20
+	}
+	return s
+}
+
+// ManagedDir is where erst keeps downloaded simulator binaries
+func ManagedDir() (string, error) {
+	dir, err := platform.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "bin"), nil
+}
+
+func findSimBinary(simPathOverride, configPath string) (string, string, error) {
+	binName := platform.Current().ExecutableName("erst-sim")
+
 	// 1. Flag override
 	if simPathOverride != "" {
 		if isExecutable(simPathOverride) {
 			return abs(simPathOverride), "flag --sim-path", nil
 		}
-		return "", "", fmt.Errorf("sim-path provided but not executable: %s", simPathOverride)
+		return "", "", errors.WrapSimulatorNotFound(fmt.Sprintf(
+			"sim-path provided but not executable: %s (check the path and that the file has execute permission)", simPathOverride))
+	}
+
+	// 2. Environment variable (ERST_SIMULATOR_PATH is the documented alias)
+	for _, name := range []string{"ERST_SIM_PATH", "ERST_SIMULATOR_PATH"} {
+		if env := os.Getenv(name); env != "" {
+			if isExecutable(env) {
+				return abs(env), "env " + name, nil
+			}
+			logger.Logger.Warn(name+" is not executable, continuing search", "path", env)
+		}
 	}
 
-	// 2. Environment variable
-	if env := os.Getenv("ERST_SIM_PATH"); env != "" {
-		if isExecutable(env) {
-			return abs(env), "env ERST_SIM_PATH", nil
+	// 3. Config file
+	if configPath != "" {
+		if isExecutable(configPath) {
+			return abs(configPath), "config simulator_path", nil
 		}
+		logger.Logger.Warn("simulator_path from config is not executable, continuing search", "path", configPath)
 	}
 
-	// 3. Local directory
+	// 4. Local directory
 	cwd, err := os.Getwd()
 	if err == nil {
 		localCandidates := []string{
-			filepath.Join(cwd, "erst-sim"),
-			filepath.Join(cwd, "bin", "erst-sim"),
+			filepath.Join(cwd, binName),
+			filepath.Join(cwd, "bin", binName),
 		}
 
 		for _, p := range localCandidates {
@@ -83,10 +170,10 @@ func findSimBinary(simPathOverride string) (string, string, error) {
 		}
 	}
 
-	// 4. Dev target
+	// 5. Dev target
 	devCandidates := []string{
-		filepath.Join("simulator", "target", "debug", "erst-sim"),
-		filepath.Join("simulator", "target", "release", "erst-sim"),
+		filepath.Join("simulator", "target", "debug", binName),
+		filepath.Join("simulator", "target", "release", binName),
 	}
 
 	for _, p := range devCandidates {
@@ -95,22 +182,28 @@ func findSimBinary(simPathOverride string) (string, string, error) {
 		}
 	}
 
-	// 5. Global PATH
+	// 6. Managed directory
+	if dir, err := ManagedDir(); err == nil {
+		if p := filepath.Join(dir, binName); isExecutable(p) {
+			return p, sourceManaged, nil
+		}
+	}
+
+	// 7. Global PATH (LookPath adds .exe on Windows)
 	if p, err := exec.LookPath("erst-sim"); err == nil {
 		return p, "global PATH", nil
 	}
 
-	return "", "", fmt.Errorf(
-		"erst-sim binary not found (use --sim-path or set ERST_SIM_PATH)",
+	return "", "", errors.WrapSimulatorNotFound(
+		"erst-sim binary not found in --sim-path, ERST_SIM_PATH, simulator_path in the config file, " +
+			"the current directory, simulator/target, ~/.erst/bin or PATH. " +
+			"Build it with 'cargo build --release' in the simulator directory, " +
+			"or point ERST_SIM_PATH at an existing binary",
 	)
 }
 
 func isExecutable(path string) bool {
-	info, err := os.Stat(path)
-	if err != nil {
-		return false
-	}
-	return !info.IsDir() && info.Mode()&0111 != 0
+	return platform.IsExecutable(path)
 }
 
 func abs(path string) string {
@@ -122,6 +215,14 @@ func abs(path string) string {
 
 // -------------------- Execution --------------------
 
+// SimulationError is a failure the simulator reported for the transaction,
+// as opposed to an error running the simulator itself
+type SimulationError struct {
+	Message string
+}
+
+func (e *SimulationError) Error() string { return "simulation error: " + e.Message }
+
 func (r *Runner) Run(req *SimulationRequest) (*SimulationResponse, error) {
 	proto := GetOrDefault(req.ProtocolVersion)
 
@@ -141,14 +242,57 @@ func (r *Runner) Run(req *SimulationRequest) (*SimulationResponse, error) {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	cmd := exec.Command(r.BinaryPath)
+	ctx := context.Background()
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	cmd := limitedCommand(ctx, r.BinaryPath, r.Limits)
 	cmd.Stdin = bytes.NewReader(inputBytes)
 
 	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
+	output := &outputCap{max: r.Limits.OutputBytes, stop: stop}
+	cmd.Stdout = output.writer(&stdout)
+	cmd.Stderr = output.writer(&stderr)
+	// Bound the wait for output pipes once a killed simulator is gone
+	cmd.WaitDelay = 2 * time.Second
+
+	err = runLimited(cmd, r.Limits)
+	// A process that wrote too much may exit before it is killed; its
+	// output is truncated either way
+	if output.Exceeded() {
+		logger.Logger.Error("Simulator output limit exceeded", "limit", r.Limits.OutputBytes)
+		return nil, newLimitError(ResourceOutput, formatBytes(r.Limits.OutputBytes))
+	}
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			logger.Logger.Error("Simulator timed out", "timeout", r.Timeout)
+			return nil, newLimitError(ResourceWallTime, r.Timeout.String())
+		}
+		switch breachedLimit(cmd.ProcessState, stderr.String(), r.Limits) {
+		case ResourceCPU:
+			logger.Logger.Error("Simulator CPU limit exceeded", "limit", r.Limits.CPUTime)
+			return nil, newLimitError(ResourceCPU, r.Limits.CPUTime.String())
+		case ResourceMemory:
+			logger.Logger.Error("Simulator memory limit exceeded", "limit_mb", r.Limits.MemoryBytes>>20)
+			return nil, newLimitError(ResourceMemory, fmt.Sprintf("%d MB", r.Limits.MemoryBytes>>20))
+		}
+		if !r.Limits.IsZero() && cmd.ProcessState != nil && cmd.ProcessState.ExitCode() == -1 {
+			logger.Logger.Error("Simulator killed", "limits", r.Limits.String(), "stderr", stderr.String())
+			return nil, fmt.Errorf("simulator was killed, likely for exceeding its resource limits (%s): %w", r.Limits, err)
+		}
+		if !r.NoCrashReports && crashed(cmd.ProcessState, stderr.String()) {
+			b, path, saveErr := crash.SaveSimulatorCrash(err.Error(), stderr.String(), crashMetadata(req, proto, r.BinaryPath))
+			if saveErr == nil {
+				logger.Logger.Error("Simulator crashed", "error", err, "report", path)
+				return nil, fmt.Errorf("simulator crashed: %w; a crash report was saved to %s (send it with 'erst crash report %s')", err, path, b.ID)
+			}
+			logger.Logger.Warn("Failed to save simulator crash report", "error", saveErr)
+		}
 		logger.Logger.Error("Simulator execution failed", "error", err, "stderr", stderr.String())
 		return nil, fmt.Errorf("simulator execution failed: %w, stderr: %s", err, stderr.String())
 	}
@@ -160,14 +304,43 @@ func (r *Runner) Run(req *SimulationRequest) (*SimulationResponse, error) {
 	}
 
 	resp.ProtocolVersion = &proto.Version
+	resp.Normalize()
 
 	if resp.Status == "error" {
-		return nil, fmt.Errorf("simulation error: %s", resp.Error)
+		return nil, &SimulationError{Message: resp.Error}
 	}
 
 	return &resp, nil
 }
 
+// crashed reports whether the simulator died abnormally rather than
+// reporting an error: a Rust panic (exit status 101 or a panic message) or
+// a signal. Resource-limit kills are handled before this is called.
+func crashed(state *os.ProcessState, stderr string) bool {
+	if strings.Contains(stderr, "panicked at") {
+		return true
+	}
+	if state == nil {
+		return false
+	}
+	return state.ExitCode() == 101 || state.ExitCode() == -1
+}
+
+// crashMetadata describes a simulation request for a crash report without
+// including its XDR or ledger entries
+func crashMetadata(req *SimulationRequest, proto *Protocol, binary string) map[string]string {
+	return map[string]string{
+		"protocol_version":  strconv.FormatUint(uint64(proto.Version), 10),
+		"envelope_bytes":    strconv.Itoa(len(req.EnvelopeXdr)),
+		"result_meta_bytes": strconv.Itoa(len(req.ResultMetaXdr)),
+		"ledger_entries":    strconv.Itoa(len(req.LedgerEntries)),
+		"ledger_sequence":   strconv.FormatUint(uint64(req.LedgerSequence), 10),
+		"timestamp":         strconv.FormatInt(req.Timestamp, 10),
+		"local_wasm":        strconv.FormatBool(req.WasmPath != nil),
+		"simulator":         filepath.Base(binary),
+	}
+}
+
 func (r *Runner) applyProtocolConfig(req *SimulationRequest, proto *Protocol) error {
 	if req.CustomAuthCfg == nil {
 		req.CustomAuthCfg = make(map[string]interface{})
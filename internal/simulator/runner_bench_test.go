@@ -85,18 +85,18 @@ func BenchmarkSimulationResponseUnmarshal(b *testing.B) {
 		b.Run(tt.name, func(b *testing.B) {
 			resp := SimulationResponse{
 				Status: "success",
-				Events: make([]string, tt.numEvents),
-				Logs:   make([]string, tt.numEvents/2),
+				Events: make([]Event, tt.numEvents),
+				Logs:   make([]LogEntry, tt.numEvents/2),
 			}
 
 			// Add events
 			for i := 0; i < tt.numEvents; i++ {
-				resp.Events[i] = strings.Repeat("event-data-", 10)
+				resp.Events[i] = Event{Index: i, Raw: strings.Repeat("event-data-", 10)}
 			}
 
 			// Add logs
 			for i := 0; i < len(resp.Logs); i++ {
-				resp.Logs[i] = "log message " + strings.Repeat("x", 50)
+				resp.Logs[i] = LogEntry{Index: i, Message: "log message " + strings.Repeat("x", 50)}
 			}
 
 			// Add budget usage if requested
@@ -474,7 +474,7 @@ func BenchmarkCompleteSimulationWorkflow(b *testing.B) {
 		// Step 3: Simulate response (in real scenario this would be from Rust binary)
 		resp := SimulationResponse{
 			Status: "success",
-			Events: make([]string, 20),
+			Events: make([]Event, 20),
 			BudgetUsage: &BudgetUsage{
 				CPUInstructions: 1000000,
 				MemoryBytes:     5000000,
@@ -483,7 +483,7 @@ func BenchmarkCompleteSimulationWorkflow(b *testing.B) {
 		}
 
 		for j := 0; j < 20; j++ {
-			resp.Events[j] = "event-" + strings.Repeat("e", 50)
+			resp.Events[j] = Event{Index: j, Raw: "event-" + strings.Repeat("e", 50)}
 		}
 
 		// Step 4: Marshal response
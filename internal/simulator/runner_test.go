@@ -0,0 +1,176 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/crash"
+	erstErrors "github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/platform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeExecutable(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0755))
+	return path
+}
+
+func TestFindSimBinary_ConfigAndManaged(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("ERST_HOME", "")
+	t.Setenv("ERST_SIM_PATH", "")
+	t.Setenv("PATH", "")
+	t.Chdir(t.TempDir())
+
+	_, _, err := findSimBinary("", "")
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, erstErrors.ErrSimulatorNotFound))
+	assert.Contains(t, err.Error(), "ERST_SIM_PATH")
+
+	_, _, err = findSimBinary(filepath.Join(home, "missing"), "")
+	assert.True(t, errors.Is(err, erstErrors.ErrSimulatorNotFound))
+
+	configured := writeExecutable(t, t.TempDir(), "erst-sim", "#!/bin/sh\n")
+	path, source, err := findSimBinary("", configured)
+	require.NoError(t, err)
+	assert.Equal(t, configured, path)
+	assert.Equal(t, "config simulator_path", source)
+
+	dir, err := ManagedDir()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	managed := writeExecutable(t, dir, "erst-sim", "#!/bin/sh\n")
+	path, source, err = findSimBinary("", "")
+	require.NoError(t, err)
+	assert.Equal(t, managed, path)
+	assert.Equal(t, sourceManaged, source)
+}
+
+func TestVerifyBinary(t *testing.T) {
+	dir := t.TempDir()
+	bin := writeExecutable(t, dir, "erst-sim", "simulator bytes")
+	sum := sha256.Sum256([]byte("simulator bytes"))
+
+	writeManifest := func(digest string) string {
+		data, err := json.Marshal(Manifest{Binaries: map[string]string{platform.Current().String(): digest}})
+		require.NoError(t, err)
+		path := filepath.Join(dir, "manifest.json")
+		require.NoError(t, os.WriteFile(path, data, 0644))
+		return path
+	}
+
+	assert.NoError(t, verifyBinary(bin, "global PATH", ""), "no manifest means no check")
+
+	manifest := writeManifest(hex.EncodeToString(sum[:]))
+	assert.NoError(t, verifyBinary(bin, "env ERST_SIM_PATH", manifest))
+
+	writeManifest("00")
+	assert.NoError(t, verifyBinary(bin, sourceManaged, ""), "a manifest next to the binary is not trusted")
+
+	defer func(old string) { PinnedDigests = old }(PinnedDigests)
+	PinnedDigests = platform.Current().String() + "=00"
+	err := verifyBinary(bin, sourceManaged, "")
+	assert.ErrorIs(t, err, ErrChecksumMismatch, "managed binaries are checked against the pinned digests")
+	assert.NoError(t, verifyBinary(bin, "dev target", ""), "other sources need an explicit manifest")
+
+	PinnedDigests = "other-os=00, " + platform.Current().String() + "=" + hex.EncodeToString(sum[:])
+	assert.NoError(t, verifyBinary(bin, sourceManaged, ""))
+
+	PinnedDigests = "garbage"
+	assert.Error(t, verifyBinary(bin, sourceManaged, ""))
+}
+
+func TestRunner_Limits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script simulator")
+	}
+	bin := writeExecutable(t, t.TempDir(), "erst-sim", "#!/bin/sh\nulimit -t\n")
+	cmd := limitedCommand(t.Context(), bin, Limits{CPUTime: 1500 * time.Millisecond, MemoryBytes: 512 << 20})
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "2\n", string(out), "CPU time is rounded up to whole seconds")
+
+	assert.Equal(t, "cpu 1.5s, memory 512 MB", Limits{CPUTime: 1500 * time.Millisecond, MemoryBytes: 512 << 20}.String())
+	assert.True(t, Limits{}.IsZero())
+}
+
+func TestRunner_CrashReport(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script simulator")
+	}
+	t.Setenv("ERST_HOME", t.TempDir())
+	bin := writeExecutable(t, t.TempDir(), "erst-sim", "#!/bin/sh\necho \"thread 'main' panicked at src/main.rs:10:5\" >&2\nexit 101\n")
+
+	r := &Runner{BinaryPath: bin}
+	_, err := r.Run(&SimulationRequest{EnvelopeXdr: "AAAA", LedgerEntries: map[string]string{"k": "v"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "simulator crashed")
+	assert.Contains(t, err.Error(), "erst crash report")
+
+	dir, err := crash.Dir()
+	require.NoError(t, err)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	data, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	var b crash.Bundle
+	require.NoError(t, json.Unmarshal(data, &b))
+	assert.Equal(t, crash.KindSimulator, b.Kind)
+	assert.Contains(t, b.Stderr, "panicked at")
+	assert.Equal(t, "1", b.Request["ledger_entries"])
+}
+
+func TestRunner_OutputLimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script simulator")
+	}
+	bin := writeExecutable(t, t.TempDir(), "erst-sim", "#!/bin/sh\nwhile :; do echo xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx; done\n")
+
+	r := &Runner{BinaryPath: bin, Limits: Limits{OutputBytes: 64 << 10}}
+	_, err := r.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, erstErrors.ErrResourceLimit))
+
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr))
+	assert.Equal(t, ResourceOutput, limitErr.Resource)
+	assert.Equal(t, "64 KB", limitErr.Limit)
+}
+
+func TestRunner_CPULimit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script simulator")
+	}
+	bin := writeExecutable(t, t.TempDir(), "erst-sim", "#!/bin/sh\nwhile :; do :; done\n")
+
+	r := &Runner{BinaryPath: bin, Limits: Limits{CPUTime: time.Second}}
+	_, err := r.Run(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	var limitErr *LimitError
+	require.True(t, errors.As(err, &limitErr), "got %v", err)
+	assert.Equal(t, ResourceCPU, limitErr.Resource)
+}
+
+func TestLimits_Tighten(t *testing.T) {
+	base := Limits{CPUTime: time.Minute, OutputBytes: 1 << 20}
+	got := base.Tighten(Limits{CPUTime: 2 * time.Minute, MemoryBytes: 256 << 20, OutputBytes: 512 << 10})
+	assert.Equal(t, Limits{CPUTime: time.Minute, MemoryBytes: 256 << 20, OutputBytes: 512 << 10}, got)
+	assert.Equal(t, base, base.Tighten(Limits{}), "unset limits leave the base alone")
+	assert.Equal(t, "cpu 1m0s, memory 256 MB, output 512 KB", got.String())
+}
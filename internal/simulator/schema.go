@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/dotandev/hintents/internal/authtrace"
+	"github.com/dotandev/hintents/internal/platform"
 	_ "modernc.org/sqlite"
 )
 
@@ -38,7 +39,8 @@ type AuthTraceOptions struct {
 
 // DiagnosticEvent represents a structured diagnostic event from the simulator
 type DiagnosticEvent struct {
-	EventType                string   `json:"event_type"` // "contract", "system", "diagnostic"
+	ID                       string   `json:"id,omitempty"` // Stable content-derived ID
+	EventType                string   `json:"event_type"`   // "contract", "system", "diagnostic"
 	ContractID               *string  `json:"contract_id,omitempty"`
 	Topics                   []string `json:"topics"`
 	Data                     string   `json:"data"`
@@ -59,14 +61,36 @@ type BudgetUsage struct {
 type SimulationResponse struct {
 	Status            string               `json:"status"` // "success" or "error"
 	Error             string               `json:"error,omitempty"`
-	Events            []string             `json:"events,omitempty"`            // Raw event strings (backward compatibility)
+	Events            []Event              `json:"events,omitempty"`            // Typed events in emission order
 	DiagnosticEvents  []DiagnosticEvent    `json:"diagnostic_events,omitempty"` // Structured diagnostic events
-	Logs              []string             `json:"logs,omitempty"`              // Host debug logs
+	Logs              []LogEntry           `json:"logs,omitempty"`              // Host debug logs
 	Flamegraph        string               `json:"flamegraph,omitempty"`        // SVG flamegraph
 	AuthTrace         *authtrace.AuthTrace `json:"auth_trace,omitempty"`
 	BudgetUsage       *BudgetUsage         `json:"budget_usage,omitempty"` // Resource consumption metrics
+	Metering          []MeterPhase         `json:"metering,omitempty"`     // Decoded core_metrics diagnostic events
 	CategorizedEvents []CategorizedEvent   `json:"categorized_events,omitempty"`
 	ProtocolVersion   *uint32              `json:"protocol_version,omitempty"` // Protocol version used
+	SourceLocation    string               `json:"source_location,omitempty"`  // Failing source line when the contract has debug symbols
+	ReturnValue       string               `json:"return_value,omitempty"`     // Base64 XDR ScVal returned by the invocation
+	Footprint         *Footprint           `json:"footprint,omitempty"`        // Ledger keys execution accessed
+	StorageAccesses   []StorageAccess      `json:"storage_accesses,omitempty"` // Contract data accesses in execution order
+}
+
+// Footprint lists the ledger keys the simulator accessed while executing,
+// as base64 XDR, by the access the host needed. It is reported for failed
+// executions too.
+type Footprint struct {
+	ReadOnly  []string `json:"read_only"`
+	ReadWrite []string `json:"read_write"`
+}
+
+// StorageAccess is one contract data access the simulator observed through
+// its storage hooks. A key is reported on its first access and again each
+// time its value changes.
+type StorageAccess struct {
+	Op     string `json:"op"`              // "read", "write" or "delete"
+	KeyXdr string `json:"key_xdr"`         // Base64 XDR ledger key
+	Value  string `json:"value,omitempty"` // Base64 XDR ScVal after the access, empty when the entry does not exist
 }
 
 type CategorizedEvent struct {
@@ -100,11 +124,11 @@ type DB struct {
 }
 
 func OpenDB() (*DB, error) {
-	home, err := os.UserHomeDir()
+	dir, err := platform.DataDir()
 	if err != nil {
 		return nil, err
 	}
-	dbPath := filepath.Join(home, ".erst", "sessions.db")
+	dbPath := filepath.Join(dir, "sessions.db")
 
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
 		return nil, err
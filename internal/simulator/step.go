@@ -0,0 +1,280 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// Step protocol commands sent to the simulator
+const (
+	StepCmdStep     = "step"
+	StepCmdContinue = "continue"
+	StepCmdInspect  = "inspect"
+	StepCmdAbort    = "abort"
+)
+
+// Step protocol event types emitted by the simulator
+const (
+	StepEventPaused = "paused"
+	StepEventState  = "state"
+	StepEventResult = "result"
+)
+
+// Pause reasons
+const (
+	PauseStart  = "start"
+	PauseCall   = "call"
+	PauseReturn = "return"
+)
+
+// ErrStepSessionDone is returned when commanding a session that has finished
+var ErrStepSessionDone = errors.New("step session has finished")
+
+// StepCommand is one line written to the simulator's stdin in step mode
+type StepCommand struct {
+	Cmd string `json:"cmd"`
+}
+
+// StepFrame identifies a contract call on the simulated call stack
+type StepFrame struct {
+	Contract string `json:"contract,omitempty"`
+	Function string `json:"function"`
+	Depth    int    `json:"depth"`
+}
+
+// StorageEntry is a decoded contract storage slot
+type StorageEntry struct {
+	Contract   string `json:"contract,omitempty"`
+	Key        string `json:"key"`
+	Value      string `json:"value"`
+	Durability string `json:"durability,omitempty"`
+}
+
+// StepEvent is one line emitted by the simulator on stdout in step mode
+type StepEvent struct {
+	Type     string              `json:"type"`
+	Reason   string              `json:"reason,omitempty"`
+	Frame    *StepFrame          `json:"frame,omitempty"`
+	Stack    []StepFrame         `json:"stack,omitempty"`
+	Budget   *BudgetUsage        `json:"budget,omitempty"`
+	Storage  []StorageEntry      `json:"storage,omitempty"`
+	Response *SimulationResponse `json:"response,omitempty"`
+}
+
+// StepSession drives a simulator process started in step mode. The simulator
+// pauses at every contract call boundary and waits for the next command.
+// A session is not safe for concurrent use.
+type StepSession struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	events *bufio.Scanner
+
+	// release frees what enforces the runner's limits once the process exits
+	release func()
+
+	mu      sync.Mutex
+	current *StepEvent
+	result  *SimulationResponse
+	closed  bool
+}
+
+// StartStep launches the simulator with a bidirectional transport and returns
+// once it reports its first pause (or finishes outright)
+func (r *Runner) StartStep(req *SimulationRequest) (*StepSession, error) {
+	proto := GetOrDefault(req.ProtocolVersion)
+	if req.ProtocolVersion != nil {
+		if err := Validate(*req.ProtocolVersion); err != nil {
+			return nil, err
+		}
+	}
+	if err := r.applyProtocolConfig(req, proto); err != nil {
+		return nil, err
+	}
+
+	cmd := limitedCommand(context.Background(), r.BinaryPath, r.Limits, "--step")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open simulator stdin: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open simulator stdout: %w", err)
+	}
+
+	release, err := startLimited(cmd, r.Limits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start simulator: %w", err)
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+
+	s := &StepSession{cmd: cmd, stdin: stdin, events: scanner, release: release}
+
+	if err := json.NewEncoder(stdin).Encode(req); err != nil {
+		_ = s.Close()
+		return nil, fmt.Errorf("failed to send simulation request: %w", err)
+	}
+
+	if _, err := s.read(); err != nil {
+		_ = s.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Current returns the most recent pause or result event
+func (s *StepSession) Current() *StepEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current
+}
+
+// Done reports whether execution has finished
+func (s *StepSession) Done() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.result != nil
+}
+
+// Result returns the final simulation response once execution has finished
+func (s *StepSession) Result() *SimulationResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.result
+}
+
+// Step resumes execution until the next call boundary
+func (s *StepSession) Step() (*StepEvent, error) {
+	return s.send(StepCmdStep)
+}
+
+// Continue runs to completion without further pauses
+func (s *StepSession) Continue() (*StepEvent, error) {
+	return s.send(StepCmdContinue)
+}
+
+// ContinueTo steps until entering a call to one of the given functions or
+// until execution finishes
+func (s *StepSession) ContinueTo(breakpoints map[string]bool) (*StepEvent, error) {
+	if len(breakpoints) == 0 {
+		return s.Continue()
+	}
+	for {
+		ev, err := s.Step()
+		if err != nil {
+			return nil, err
+		}
+		if ev.Type == StepEventResult {
+			return ev, nil
+		}
+		if ev.Reason == PauseCall && ev.Frame != nil && breakpoints[ev.Frame.Function] {
+			return ev, nil
+		}
+	}
+}
+
+// Inspect asks the paused simulator for current storage and budget without
+// resuming execution
+func (s *StepSession) Inspect() (*StepEvent, error) {
+	return s.send(StepCmdInspect)
+}
+
+func (s *StepSession) send(cmd string) (*StepEvent, error) {
+	if s.Done() {
+		return nil, ErrStepSessionDone
+	}
+
+	line, err := json.Marshal(StepCommand{Cmd: cmd})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.stdin.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("failed to send %s command: %w", cmd, err)
+	}
+	return s.read()
+}
+
+// read waits for the next event from the simulator
+func (s *StepSession) read() (*StepEvent, error) {
+	if !s.events.Scan() {
+		if err := s.events.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read simulator event: %w", err)
+		}
+		return nil, errors.New("simulator exited before finishing the step session")
+	}
+
+	var ev StepEvent
+	if err := json.Unmarshal(s.events.Bytes(), &ev); err != nil {
+		logger.Logger.Error("Failed to unmarshal step event", "error", err)
+		return nil, fmt.Errorf("failed to unmarshal step event: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch ev.Type {
+	case StepEventPaused:
+		s.current = &ev
+	case StepEventResult:
+		if ev.Response == nil {
+			return nil, errors.New("simulator sent a result without a response")
+		}
+		s.current = &ev
+		ev.Response.Normalize()
+		s.result = ev.Response
+	case StepEventState:
+		// Inspection replies leave the pause position unchanged
+	default:
+		return nil, fmt.Errorf("unknown step event type: %q", ev.Type)
+	}
+
+	return &ev, nil
+}
+
+// Close aborts a running session and waits for the simulator to exit
+func (s *StepSession) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	finished := s.result != nil
+	s.mu.Unlock()
+
+	if !finished {
+		line, _ := json.Marshal(StepCommand{Cmd: StepCmdAbort})
+		_, _ = s.stdin.Write(append(line, '\n'))
+	}
+	_ = s.stdin.Close()
+
+	done := make(chan error, 1)
+	go func() { done <- s.cmd.Wait() }()
+	defer s.release()
+
+	select {
+	case err := <-done:
+		if finished {
+			return err
+		}
+		return nil
+	case <-time.After(2 * time.Second):
+		_ = s.cmd.Process.Kill()
+		<-done
+		return nil
+	}
+}
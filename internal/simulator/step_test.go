@@ -0,0 +1,154 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package simulator
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeStepSim replays a fixed step transcript, echoing each command it
+// receives to a log file so tests can assert on the wire protocol
+const fakeStepSim = `#!/bin/sh
+[ "$1" = "--step" ] || exit 2
+read req
+echo "$req" > "$LOG"
+echo '{"type":"paused","reason":"start"}'
+while read cmd; do
+  echo "$cmd" >> "$LOG"
+  case "$cmd" in
+    *inspect*) echo '{"type":"state","budget":{"cpu_instructions":42},"storage":[{"key":"Balance","value":"100"}]}' ;;
+    *abort*) exit 0 ;;
+    *continue*) echo '{"type":"result","response":{"status":"success"}}'; exit 0 ;;
+    *step*)
+      n=$((n+1))
+      case $n in
+        1) echo '{"type":"paused","reason":"call","frame":{"function":"transfer","depth":1}}' ;;
+        2) echo '{"type":"paused","reason":"call","frame":{"function":"spend_balance","depth":2}}' ;;
+        3) echo '{"type":"paused","reason":"return","frame":{"function":"spend_balance","depth":2}}' ;;
+        *) echo '{"type":"result","response":{"status":"success"}}'; exit 0 ;;
+      esac ;;
+  esac
+done
+`
+
+func newFakeStepRunner(t *testing.T) (*Runner, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake simulator is a shell script")
+	}
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "erst-sim")
+	if err := os.WriteFile(bin, []byte(fakeStepSim), 0755); err != nil {
+		t.Fatal(err)
+	}
+	log := filepath.Join(dir, "commands.log")
+	t.Setenv("LOG", log)
+
+	return &Runner{BinaryPath: bin}, log
+}
+
+func TestStepSession_Breakpoints(t *testing.T) {
+	runner, log := newFakeStepRunner(t)
+
+	s, err := runner.StartStep(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	if err != nil {
+		t.Fatalf("StartStep failed: %v", err)
+	}
+	defer s.Close()
+
+	if ev := s.Current(); ev == nil || ev.Reason != PauseStart {
+		t.Fatalf("expected initial pause, got %+v", ev)
+	}
+
+	ev, err := s.ContinueTo(map[string]bool{"spend_balance": true})
+	if err != nil {
+		t.Fatalf("ContinueTo failed: %v", err)
+	}
+	if ev.Frame == nil || ev.Frame.Function != "spend_balance" || ev.Frame.Depth != 2 {
+		t.Fatalf("expected breakpoint at spend_balance, got %+v", ev.Frame)
+	}
+
+	state, err := s.Inspect()
+	if err != nil {
+		t.Fatalf("Inspect failed: %v", err)
+	}
+	if state.Budget == nil || state.Budget.CPUInstructions != 42 || len(state.Storage) != 1 {
+		t.Errorf("unexpected inspect reply: %+v", state)
+	}
+	if s.Current().Frame.Function != "spend_balance" {
+		t.Error("inspect must not move the pause position")
+	}
+
+	ev, err = s.Continue()
+	if err != nil {
+		t.Fatalf("Continue failed: %v", err)
+	}
+	if ev.Type != StepEventResult || !s.Done() || s.Result().Status != "success" {
+		t.Fatalf("expected final result, got %+v", ev)
+	}
+
+	if _, err := s.Step(); err != ErrStepSessionDone {
+		t.Errorf("expected ErrStepSessionDone, got %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	want := []string{`"envelope_xdr":"AAAA"`, `{"cmd":"step"}`, `{"cmd":"step"}`, `{"cmd":"inspect"}`, `{"cmd":"continue"}`}
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d protocol lines, got %q", len(want), lines)
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], w) {
+			t.Errorf("line %d: expected %s in %q", i, w, lines[i])
+		}
+	}
+}
+
+func TestStepSession_CloseAborts(t *testing.T) {
+	runner, log := newFakeStepRunner(t)
+
+	s, err := runner.StartStep(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	if err != nil {
+		t.Fatalf("StartStep failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(log)
+	if !strings.Contains(string(data), `{"cmd":"abort"}`) {
+		t.Errorf("expected abort command, got %q", data)
+	}
+}
+
+func TestStepSession_Limits(t *testing.T) {
+	runner, _ := newFakeStepRunner(t)
+	script := "#!/bin/sh\n[ \"$1\" = \"--step\" ] || exit 2\nread req\necho \"{\\\"type\\\":\\\"paused\\\",\\\"reason\\\":\\\"cpu $(ulimit -t)\\\"}\"\nread cmd\n"
+	if err := os.WriteFile(runner.BinaryPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	runner.Limits = Limits{CPUTime: 3 * time.Second}
+
+	s, err := runner.StartStep(&SimulationRequest{EnvelopeXdr: "AAAA"})
+	if err != nil {
+		t.Fatalf("StartStep failed: %v", err)
+	}
+	defer s.Close()
+
+	if got := s.Current().Reason; got != "cpu 3" {
+		t.Errorf("expected step mode to run under the CPU limit, got %q", got)
+	}
+}
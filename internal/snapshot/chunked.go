@@ -0,0 +1,370 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// The v2 snapshot format stores entries sorted by key in independently
+// zstd-compressed chunks, followed by a compressed index and a fixed footer:
+//
+//	magic | chunk... | index | index offset (u64) | index length (u64) | magic
+//
+// Each chunk holds uvarint length-prefixed key/value pairs. The index records
+// the key range of every chunk so single entries can be read without
+// decompressing the rest of the file.
+
+// V2Magic starts and ends every v2 snapshot file
+const V2Magic = "ERSTSNP2"
+
+// DefaultChunkSize is the uncompressed size a v2 chunk is filled up to
+const DefaultChunkSize = 1 << 20
+
+const footerSize = 16 + len(V2Magic)
+
+// ErrNotChunked is returned when opening a file that is not a v2 snapshot
+var ErrNotChunked = errors.New("not a v2 snapshot")
+
+type chunkIndex struct {
+	Version int         `json:"version"`
+	Entries int         `json:"entries"`
+	Chunks  []chunkInfo `json:"chunks"`
+}
+
+type chunkInfo struct {
+	Offset   int64  `json:"offset"`
+	Length   int64  `json:"length"`
+	Count    int    `json:"count"`
+	FirstKey string `json:"first_key"`
+	LastKey  string `json:"last_key"`
+}
+
+// IsChunked reports whether data starts with the v2 snapshot magic
+func IsChunked(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(V2Magic))
+}
+
+// WriteChunked encodes snap in the v2 format. Entries are sorted by key and
+// later duplicates win. chunkSize <= 0 uses DefaultChunkSize.
+func WriteChunked(w io.Writer, snap *Snapshot, chunkSize int) error {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	entries := FromMap(snap.ToMap()).LedgerEntries
+
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return fmt.Errorf("failed to create zstd encoder: %w", err)
+	}
+	defer enc.Close()
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.WriteString(V2Magic); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	offset := int64(len(V2Magic))
+	index := chunkIndex{Version: 2, Entries: len(entries)}
+
+	var (
+		raw   []byte
+		chunk chunkInfo
+	)
+	flush := func() error {
+		if chunk.Count == 0 {
+			return nil
+		}
+		compressed := enc.EncodeAll(raw, nil)
+		if _, err := bw.Write(compressed); err != nil {
+			return fmt.Errorf("failed to write snapshot: %w", err)
+		}
+		chunk.Offset = offset
+		chunk.Length = int64(len(compressed))
+		index.Chunks = append(index.Chunks, chunk)
+		offset += chunk.Length
+		raw = raw[:0]
+		chunk = chunkInfo{}
+		return nil
+	}
+
+	for _, e := range entries {
+		if chunk.Count == 0 {
+			chunk.FirstKey = e[0]
+		}
+		raw = binary.AppendUvarint(raw, uint64(len(e[0])))
+		raw = append(raw, e[0]...)
+		raw = binary.AppendUvarint(raw, uint64(len(e[1])))
+		raw = append(raw, e[1]...)
+		chunk.LastKey = e[0]
+		chunk.Count++
+		if len(raw) >= chunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	indexJSON, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot index: %w", err)
+	}
+	compressedIndex := enc.EncodeAll(indexJSON, nil)
+	if _, err := bw.Write(compressedIndex); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	var footer [footerSize]byte
+	binary.BigEndian.PutUint64(footer[0:8], uint64(offset))
+	binary.BigEndian.PutUint64(footer[8:16], uint64(len(compressedIndex)))
+	copy(footer[16:], V2Magic)
+	if _, err := bw.Write(footer[:]); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return bw.Flush()
+}
+
+// SaveChunked writes a snapshot to path in the v2 format
+func SaveChunked(path string, snap *Snapshot) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := WriteChunked(f, snap, 0); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	return nil
+}
+
+// ChunkedReader reads entries from a v2 snapshot on demand, keeping only the
+// index and the most recently used chunk in memory. It is safe for
+// concurrent use.
+type ChunkedReader struct {
+	r      io.ReaderAt
+	closer io.Closer
+	index  chunkIndex
+	dec    *zstd.Decoder
+
+	mu        sync.Mutex
+	cached    int
+	cachedKVs [][2]string
+}
+
+// OpenChunked opens a v2 snapshot file for lazy reads
+func OpenChunked(path string) (*ChunkedReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+	cr, err := NewChunkedReader(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	cr.closer = f
+	return cr, nil
+}
+
+// NewChunkedReader reads the index of the v2 snapshot in r
+func NewChunkedReader(r io.ReaderAt, size int64) (*ChunkedReader, error) {
+	if size < int64(len(V2Magic)+footerSize) {
+		return nil, ErrNotChunked
+	}
+	head := make([]byte, len(V2Magic))
+	if _, err := r.ReadAt(head, 0); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+	if string(head) != V2Magic {
+		return nil, ErrNotChunked
+	}
+
+	footer := make([]byte, footerSize)
+	if _, err := r.ReadAt(footer, size-int64(footerSize)); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot footer: %w", err)
+	}
+	if string(footer[16:]) != V2Magic {
+		return nil, fmt.Errorf("snapshot is truncated: missing footer")
+	}
+	indexOffset := int64(binary.BigEndian.Uint64(footer[0:8]))
+	indexLength := int64(binary.BigEndian.Uint64(footer[8:16]))
+	if indexOffset < int64(len(V2Magic)) || indexOffset+indexLength > size-int64(footerSize) {
+		return nil, fmt.Errorf("snapshot index out of range")
+	}
+
+	dec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+	}
+	cr := &ChunkedReader{r: r, dec: dec, cached: -1}
+
+	raw, err := cr.readBlock(indexOffset, indexLength)
+	if err != nil {
+		dec.Close()
+		return nil, fmt.Errorf("failed to read snapshot index: %w", err)
+	}
+	if err := json.Unmarshal(raw, &cr.index); err != nil {
+		dec.Close()
+		return nil, fmt.Errorf("failed to parse snapshot index: %w", err)
+	}
+	return cr, nil
+}
+
+// Len returns the number of entries in the snapshot
+func (c *ChunkedReader) Len() int {
+	return c.index.Entries
+}
+
+// Get returns the value stored for key
+func (c *ChunkedReader) Get(key string) (string, bool, error) {
+	chunks := c.index.Chunks
+	i := sort.Search(len(chunks), func(i int) bool { return chunks[i].LastKey >= key })
+	if i == len(chunks) || chunks[i].FirstKey > key {
+		return "", false, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	kvs, err := c.chunk(i)
+	if err != nil {
+		return "", false, err
+	}
+	j := sort.Search(len(kvs), func(j int) bool { return kvs[j][0] >= key })
+	if j < len(kvs) && kvs[j][0] == key {
+		return kvs[j][1], true, nil
+	}
+	return "", false, nil
+}
+
+// Lookup returns the entries for the given keys that exist in the snapshot
+func (c *ChunkedReader) Lookup(keys []string) (map[string]string, error) {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+
+	out := make(map[string]string, len(keys))
+	for _, k := range sorted {
+		v, ok, err := c.Get(k)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			out[k] = v
+		}
+	}
+	return out, nil
+}
+
+// Each calls fn for every entry in key order, one chunk at a time
+func (c *ChunkedReader) Each(fn func(key, value string) error) error {
+	for i, info := range c.index.Chunks {
+		raw, err := c.readBlock(info.Offset, info.Length)
+		if err != nil {
+			return fmt.Errorf("failed to read snapshot chunk %d: %w", i, err)
+		}
+		kvs, err := decodeChunk(raw)
+		if err != nil {
+			return fmt.Errorf("snapshot chunk %d: %w", i, err)
+		}
+		for _, kv := range kvs {
+			if err := fn(kv[0], kv[1]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Snapshot decodes every entry into memory
+func (c *ChunkedReader) Snapshot() (*Snapshot, error) {
+	snap := &Snapshot{LedgerEntries: make([]LedgerEntryTuple, 0, c.index.Entries)}
+	err := c.Each(func(k, v string) error {
+		snap.LedgerEntries = append(snap.LedgerEntries, LedgerEntryTuple{k, v})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// Close releases the decoder and the underlying file, if any
+func (c *ChunkedReader) Close() error {
+	c.dec.Close()
+	if c.closer != nil {
+		return c.closer.Close()
+	}
+	return nil
+}
+
+func (c *ChunkedReader) chunk(i int) ([][2]string, error) {
+	if c.cached == i {
+		return c.cachedKVs, nil
+	}
+	info := c.index.Chunks[i]
+	raw, err := c.readBlock(info.Offset, info.Length)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot chunk %d: %w", i, err)
+	}
+	kvs, err := decodeChunk(raw)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot chunk %d: %w", i, err)
+	}
+	c.cached, c.cachedKVs = i, kvs
+	return kvs, nil
+}
+
+func (c *ChunkedReader) readBlock(offset, length int64) ([]byte, error) {
+	compressed := make([]byte, length)
+	if _, err := c.r.ReadAt(compressed, offset); err != nil {
+		return nil, err
+	}
+	return c.dec.DecodeAll(compressed, nil)
+}
+
+func decodeChunk(raw []byte) ([][2]string, error) {
+	var out [][2]string
+	for len(raw) > 0 {
+		key, rest, err := readField(raw)
+		if err != nil {
+			return nil, err
+		}
+		value, rest, err := readField(rest)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, [2]string{key, value})
+		raw = rest
+	}
+	return out, nil
+}
+
+func readField(b []byte) (string, []byte, error) {
+	n, size := binary.Uvarint(b)
+	if size <= 0 || uint64(len(b)-size) < n {
+		return "", nil, fmt.Errorf("corrupt entry")
+	}
+	end := size + int(n)
+	return string(b[size:end]), b[end:], nil
+}
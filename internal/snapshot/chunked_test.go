@@ -0,0 +1,140 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+func testEntries(n int) map[string]string {
+	m := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		m[fmt.Sprintf("key-%05d", i)] = fmt.Sprintf("value-%d", i)
+	}
+	return m
+}
+
+func TestChunkedRoundTrip(t *testing.T) {
+	entries := testEntries(500)
+	var buf bytes.Buffer
+	// Small chunks force the index to span many of them
+	if err := WriteChunked(&buf, FromMap(entries), 256); err != nil {
+		t.Fatalf("WriteChunked: %v", err)
+	}
+	data := buf.Bytes()
+	if !IsChunked(data) {
+		t.Fatal("expected v2 magic")
+	}
+
+	cr, err := NewChunkedReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("NewChunkedReader: %v", err)
+	}
+	defer cr.Close()
+
+	if cr.Len() != 500 {
+		t.Errorf("Len = %d, want 500", cr.Len())
+	}
+	if len(cr.index.Chunks) < 2 {
+		t.Errorf("expected multiple chunks, got %d", len(cr.index.Chunks))
+	}
+
+	for _, k := range []string{"key-00000", "key-00250", "key-00499"} {
+		v, ok, err := cr.Get(k)
+		if err != nil || !ok || v != entries[k] {
+			t.Errorf("Get(%q) = %q, %v, %v", k, v, ok, err)
+		}
+	}
+	for _, k := range []string{"a", "key-00250x", "zzz"} {
+		if _, ok, err := cr.Get(k); ok || err != nil {
+			t.Errorf("Get(%q) found = %v, err = %v", k, ok, err)
+		}
+	}
+
+	got, err := cr.Lookup([]string{"key-00499", "key-00001", "missing"})
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if len(got) != 2 || got["key-00001"] != "value-1" {
+		t.Errorf("Lookup = %v", got)
+	}
+
+	snap, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(snap.LedgerEntries) != 500 || snap.ToMap()["key-00123"] != "value-123" {
+		t.Errorf("Parse returned %d entries", len(snap.LedgerEntries))
+	}
+}
+
+func TestChunkedEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteChunked(&buf, FromMap(nil), 0); err != nil {
+		t.Fatalf("WriteChunked: %v", err)
+	}
+	snap, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(snap.LedgerEntries) != 0 {
+		t.Errorf("got %d entries", len(snap.LedgerEntries))
+	}
+}
+
+func TestChunkedTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteChunked(&buf, FromMap(testEntries(10)), 0); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()[:buf.Len()-4]
+	if _, err := NewChunkedReader(bytes.NewReader(data), int64(len(data))); err == nil {
+		t.Error("expected error for truncated snapshot")
+	}
+	if _, err := NewChunkedReader(bytes.NewReader([]byte(testSnapshotJSON)), int64(len(testSnapshotJSON))); err != ErrNotChunked {
+		t.Errorf("expected ErrNotChunked for JSON, got %v", err)
+	}
+}
+
+func TestLoadEntries(t *testing.T) {
+	dir := t.TempDir()
+	entries := testEntries(50)
+
+	v2 := filepath.Join(dir, "state.snap")
+	if err := SaveChunked(v2, FromMap(entries)); err != nil {
+		t.Fatalf("SaveChunked: %v", err)
+	}
+	got, err := LoadEntries(context.Background(), v2, []string{"key-00003", "key-00049"})
+	if err != nil {
+		t.Fatalf("LoadEntries v2: %v", err)
+	}
+	if len(got) != 2 || got["key-00049"] != "value-49" {
+		t.Errorf("LoadEntries v2 = %v", got)
+	}
+
+	// JSON snapshots are loaded whole regardless of the requested keys
+	js := filepath.Join(dir, "state.json")
+	if err := Save(js, FromMap(entries)); err != nil {
+		t.Fatal(err)
+	}
+	got, err = LoadEntries(context.Background(), js, []string{"key-00003"})
+	if err != nil {
+		t.Fatalf("LoadEntries json: %v", err)
+	}
+	if len(got) != 50 {
+		t.Errorf("LoadEntries json returned %d entries, want 50", len(got))
+	}
+
+	snap, err := Load(v2)
+	if err != nil {
+		t.Fatalf("Load v2: %v", err)
+	}
+	if len(snap.LedgerEntries) != 50 {
+		t.Errorf("Load v2 returned %d entries", len(snap.LedgerEntries))
+	}
+}
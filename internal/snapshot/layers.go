@@ -0,0 +1,90 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Layers is ledger state composed from several snapshots. Layers are applied
+// in order, so an entry in a later snapshot replaces the same key from an
+// earlier one; entries added with Fill never replace anything.
+type Layers struct {
+	Entries map[string]string
+	// Origin names where each entry came from: a snapshot location, or the
+	// origin given to Fill
+	Origin map[string]string
+	// Overridden counts entries replaced by a later layer
+	Overridden int
+}
+
+// NewLayers returns empty layered state
+func NewLayers() *Layers {
+	return &Layers{Entries: make(map[string]string), Origin: make(map[string]string)}
+}
+
+// LoadLayers loads each snapshot location in order and stacks them. keys
+// limits which entries are read from v2 snapshots, as with LoadEntries.
+func LoadLayers(ctx context.Context, locations []string, keys []string) (*Layers, error) {
+	l := NewLayers()
+	for _, loc := range locations {
+		entries, err := LoadEntries(ctx, loc, keys)
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %s: %w", loc, err)
+		}
+		l.Apply(loc, entries)
+	}
+	return l, nil
+}
+
+// Apply stacks entries on top of the current state
+func (l *Layers) Apply(origin string, entries map[string]string) {
+	for k, v := range entries {
+		if _, ok := l.Entries[k]; ok {
+			l.Overridden++
+		}
+		l.Entries[k] = v
+		l.Origin[k] = origin
+	}
+}
+
+// Fill adds entries for keys that no layer provides
+func (l *Layers) Fill(origin string, entries map[string]string) int {
+	added := 0
+	for k, v := range entries {
+		if _, ok := l.Entries[k]; ok {
+			continue
+		}
+		l.Entries[k] = v
+		l.Origin[k] = origin
+		added++
+	}
+	return added
+}
+
+// Missing returns the keys, sorted, that no layer provides
+func (l *Layers) Missing(keys []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if _, ok := l.Entries[k]; ok || seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// CountByOrigin returns how many entries each origin contributed
+func (l *Layers) CountByOrigin() map[string]int {
+	out := make(map[string]int)
+	for _, origin := range l.Origin {
+		out[origin]++
+	}
+	return out
+}
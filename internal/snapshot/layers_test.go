@@ -0,0 +1,62 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadLayers(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.json")
+	overrides := filepath.Join(dir, "overrides.snap")
+	if err := Save(base, FromMap(map[string]string{"a": "base-a", "b": "base-b"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveChunked(overrides, FromMap(map[string]string{"b": "override-b", "c": "override-c"})); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"a", "b", "c", "d"}
+	l, err := LoadLayers(context.Background(), []string{base, overrides}, keys)
+	if err != nil {
+		t.Fatalf("LoadLayers: %v", err)
+	}
+
+	want := map[string]string{"a": "base-a", "b": "override-b", "c": "override-c"}
+	if !reflect.DeepEqual(l.Entries, want) {
+		t.Errorf("Entries = %v, want %v", l.Entries, want)
+	}
+	if l.Overridden != 1 {
+		t.Errorf("Overridden = %d, want 1", l.Overridden)
+	}
+	if l.Origin["b"] != overrides || l.Origin["a"] != base {
+		t.Errorf("Origin = %v", l.Origin)
+	}
+	if got := l.Missing(keys); !reflect.DeepEqual(got, []string{"d"}) {
+		t.Errorf("Missing = %v, want [d]", got)
+	}
+
+	// Fetched entries only fill gaps
+	if n := l.Fill("network:testnet", map[string]string{"a": "live-a", "d": "live-d"}); n != 1 {
+		t.Errorf("Fill added %d, want 1", n)
+	}
+	if l.Entries["a"] != "base-a" || l.Entries["d"] != "live-d" {
+		t.Errorf("Entries after Fill = %v", l.Entries)
+	}
+	counts := l.CountByOrigin()
+	if counts[base] != 1 || counts[overrides] != 2 || counts["network:testnet"] != 1 {
+		t.Errorf("CountByOrigin = %v", counts)
+	}
+}
+
+func TestLoadLayersError(t *testing.T) {
+	_, err := LoadLayers(context.Background(), []string{filepath.Join(t.TempDir(), "missing.json")}, nil)
+	if err == nil {
+		t.Fatal("expected error for missing snapshot")
+	}
+}
@@ -4,6 +4,7 @@
 package snapshot
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -61,6 +62,20 @@ func Load(path string) (*Snapshot, error) {
 		return nil, fmt.Errorf("failed to read snapshot file: %w", err)
 	}
 
+	return Parse(data)
+}
+
+// Parse decodes a snapshot in either the JSON or the v2 chunked format.
+func Parse(data []byte) (*Snapshot, error) {
+	if IsChunked(data) {
+		cr, err := NewChunkedReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		defer cr.Close()
+		return cr.Snapshot()
+	}
+
 	var snap Snapshot
 	if err := json.Unmarshal(data, &snap); err != nil {
 		return nil, fmt.Errorf("failed to parse snapshot JSON: %w", err)
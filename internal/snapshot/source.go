@@ -0,0 +1,211 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source opens snapshots stored behind one URL scheme
+type Source interface {
+	Open(ctx context.Context, location *url.URL) (io.ReadCloser, error)
+}
+
+// SourceFunc adapts a function to a Source
+type SourceFunc func(ctx context.Context, location *url.URL) (io.ReadCloser, error)
+
+// Open calls f
+func (f SourceFunc) Open(ctx context.Context, location *url.URL) (io.ReadCloser, error) {
+	return f(ctx, location)
+}
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = map[string]Source{}
+)
+
+// RegisterSource makes a Source available for locations with the given
+// URL scheme, replacing any previous registration
+func RegisterSource(scheme string, s Source) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+	sources[strings.ToLower(scheme)] = s
+}
+
+func init() {
+	httpSource := &HTTPSource{Client: defaultHTTPClient()}
+	RegisterSource("file", SourceFunc(openFile))
+	RegisterSource("http", httpSource)
+	RegisterSource("https", httpSource)
+	RegisterSource("s3", NewS3Source())
+	RegisterSource("gs", NewGCSSource())
+}
+
+func defaultHTTPClient() *http.Client {
+	return &http.Client{Timeout: 2 * time.Minute}
+}
+
+// Open returns a reader for the snapshot at location, which is a local
+// path or a URL with a registered scheme such as https://, s3:// or gs://
+func Open(ctx context.Context, location string) (io.ReadCloser, error) {
+	u, err := parseLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	sourcesMu.RLock()
+	src, ok := sources[u.Scheme]
+	sourcesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported snapshot location scheme %q", u.Scheme)
+	}
+	return src.Open(ctx, u)
+}
+
+// LoadFrom reads and parses the snapshot at location
+func LoadFrom(ctx context.Context, location string) (*Snapshot, error) {
+	rc, err := Open(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", location, err)
+	}
+	return Parse(data)
+}
+
+// LoadEntries returns the ledger entries of the snapshot at location. JSON
+// snapshots are loaded whole; for v2 snapshots only the given keys are
+// decompressed, so large captures stay out of memory.
+func LoadEntries(ctx context.Context, location string, keys []string) (map[string]string, error) {
+	rc, err := Open(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	if f, ok := rc.(*os.File); ok {
+		if info, err := f.Stat(); err == nil {
+			cr, err := NewChunkedReader(f, info.Size())
+			if err == nil {
+				defer cr.Close()
+				return cr.Lookup(keys)
+			}
+			if !errors.Is(err, ErrNotChunked) {
+				return nil, err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", location, err)
+	}
+	if IsChunked(data) {
+		cr, err := NewChunkedReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, err
+		}
+		defer cr.Close()
+		return cr.Lookup(keys)
+	}
+	snap, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return snap.ToMap(), nil
+}
+
+// parseLocation treats anything without a URL scheme, including Windows
+// drive paths, as a local file
+func parseLocation(location string) (*url.URL, error) {
+	if i := strings.Index(location, "://"); i > 1 {
+		u, err := url.Parse(location)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot location %q: %w", location, err)
+		}
+		u.Scheme = strings.ToLower(u.Scheme)
+		return u, nil
+	}
+	return &url.URL{Scheme: "file", Path: location}, nil
+}
+
+func openFile(_ context.Context, location *url.URL) (io.ReadCloser, error) {
+	path := location.Path
+	if location.Host != "" {
+		path = location.Host + path
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+	return f, nil
+}
+
+// HTTPSource fetches snapshots over HTTP(S). ERST_SNAPSHOT_TOKEN, when set,
+// is sent as a bearer token, but only over HTTPS to the host named by
+// ERST_SNAPSHOT_TOKEN_HOST.
+type HTTPSource struct {
+	Client *http.Client
+}
+
+// Open issues a GET for location
+func (s *HTTPSource) Open(ctx context.Context, location *url.URL) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, location.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create snapshot request: %w", err)
+	}
+	if token := os.Getenv("ERST_SNAPSHOT_TOKEN"); token != "" && tokenHost(location) {
+		if location.Scheme != "https" {
+			return nil, fmt.Errorf("refusing to send ERST_SNAPSHOT_TOKEN to %s over plain HTTP", redact(location))
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return fetch(s.Client, req)
+}
+
+// tokenHost reports whether location is on the host ERST_SNAPSHOT_TOKEN is
+// scoped to. A host without a port matches any port.
+func tokenHost(location *url.URL) bool {
+	host := os.Getenv("ERST_SNAPSHOT_TOKEN_HOST")
+	if host == "" {
+		return false
+	}
+	return strings.EqualFold(host, location.Host) || strings.EqualFold(host, location.Hostname())
+}
+
+// fetch performs req and returns the body of a successful response
+func fetch(client *http.Client, req *http.Request) (io.ReadCloser, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch snapshot %s: %w", redact(req.URL), err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch snapshot %s: %s: %s", redact(req.URL), resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
+
+// redact drops query strings, which may carry presigned credentials, from
+// URLs shown in errors
+func redact(u *url.URL) string {
+	c := *u
+	c.RawQuery = ""
+	c.User = nil
+	return c.String()
+}
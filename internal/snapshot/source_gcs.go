@@ -0,0 +1,56 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// GCSSource fetches gs://bucket/object snapshots through the Cloud Storage
+// JSON API. GOOGLE_OAUTH_ACCESS_TOKEN, for example from
+// `gcloud auth print-access-token`, is sent as a bearer token; without it
+// only public objects can be read. STORAGE_EMULATOR_HOST points it at an
+// emulator.
+type GCSSource struct {
+	Client *http.Client
+	Getenv func(string) string
+}
+
+// NewGCSSource returns a GCSSource reading the process environment
+func NewGCSSource() *GCSSource {
+	return &GCSSource{Client: defaultHTTPClient(), Getenv: os.Getenv}
+}
+
+// Open fetches the object named by location
+func (s *GCSSource) Open(ctx context.Context, location *url.URL) (io.ReadCloser, error) {
+	bucket := location.Host
+	object := strings.TrimPrefix(location.Path, "/")
+	if bucket == "" || object == "" {
+		return nil, fmt.Errorf("invalid GCS location %q: expected gs://bucket/object", location.String())
+	}
+
+	base := "https://storage.googleapis.com"
+	if host := s.Getenv("STORAGE_EMULATOR_HOST"); host != "" {
+		base = strings.TrimSuffix(host, "/")
+		if !strings.Contains(base, "://") {
+			base = "http://" + base
+		}
+	}
+	target := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", base, url.PathEscape(bucket), url.PathEscape(object))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS request: %w", err)
+	}
+	if token := s.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return fetch(s.Client, req)
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/objstore"
+)
+
+// S3Source fetches s3://bucket/key snapshots. Credentials and region come
+// from the standard AWS environment variables; without credentials the
+// request is sent unsigned, which works for public buckets.
+// AWS_ENDPOINT_URL_S3 or AWS_ENDPOINT_URL selects an S3-compatible service
+// and switches to path-style addressing.
+type S3Source struct {
+	Client *http.Client
+	Getenv func(string) string
+	Now    func() time.Time
+}
+
+// NewS3Source returns an S3Source reading the process environment
+func NewS3Source() *S3Source {
+	return &S3Source{Client: defaultHTTPClient(), Getenv: os.Getenv, Now: time.Now}
+}
+
+// Open fetches the object named by location
+func (s *S3Source) Open(ctx context.Context, location *url.URL) (io.ReadCloser, error) {
+	bucket := location.Host
+	key := strings.TrimPrefix(location.Path, "/")
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("invalid S3 location %q: expected s3://bucket/key", location.String())
+	}
+
+	region := firstEnv(s.Getenv, "AWS_REGION", "AWS_DEFAULT_REGION")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	var target string
+	if endpoint := firstEnv(s.Getenv, "AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"); endpoint != "" {
+		target = strings.TrimSuffix(endpoint, "/") + "/" + bucket + "/" + objstore.EscapeKey(key)
+	} else {
+		target = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, region, objstore.EscapeKey(key))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 request: %w", err)
+	}
+
+	accessKey := s.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := s.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey != "" && secretKey != "" {
+		creds := objstore.Credentials{AccessKey: accessKey, SecretKey: secretKey, SessionToken: s.Getenv("AWS_SESSION_TOKEN")}
+		objstore.SignV4(req, creds, region, "s3", objstore.EmptyPayloadHash, s.Now())
+	}
+	return fetch(s.Client, req)
+}
+
+func firstEnv(getenv func(string) string, names ...string) string {
+	for _, name := range names {
+		if v := getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,192 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package snapshot
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSnapshotJSON = `{"ledgerEntries":[["a2V5","dmFs"]]}`
+
+func TestLoadFromLocalPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snap.json")
+	if err := os.WriteFile(path, []byte(testSnapshotJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, loc := range []string{path, "file://" + path} {
+		snap, err := LoadFrom(context.Background(), loc)
+		if err != nil {
+			t.Fatalf("LoadFrom(%q): %v", loc, err)
+		}
+		if got := snap.ToMap()["a2V5"]; got != "dmFs" {
+			t.Errorf("LoadFrom(%q) entry = %q, want dmFs", loc, got)
+		}
+	}
+}
+
+func TestLoadFromHTTP(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if r.URL.Path != "/snap.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testSnapshotJSON))
+	}))
+	defer srv.Close()
+
+	t.Setenv("ERST_SNAPSHOT_TOKEN", "secret")
+	snap, err := LoadFrom(context.Background(), srv.URL+"/snap.json")
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if len(snap.LedgerEntries) != 1 {
+		t.Errorf("got %d entries, want 1", len(snap.LedgerEntries))
+	}
+	if auth != "" {
+		t.Errorf("token sent without ERST_SNAPSHOT_TOKEN_HOST: %q", auth)
+	}
+
+	t.Setenv("ERST_SNAPSHOT_TOKEN_HOST", "127.0.0.1")
+	_, err = LoadFrom(context.Background(), srv.URL+"/snap.json")
+	if err == nil || !strings.Contains(err.Error(), "plain HTTP") {
+		t.Fatalf("expected the token to be refused over HTTP, got %v", err)
+	}
+
+	t.Setenv("ERST_SNAPSHOT_TOKEN_HOST", "snapshots.example.org")
+	_, err = LoadFrom(context.Background(), srv.URL+"/missing.json?sig=abc")
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("expected 404 error, got %v", err)
+	}
+	if strings.Contains(err.Error(), "sig=abc") {
+		t.Errorf("error leaks query string: %v", err)
+	}
+}
+
+func TestHTTPSourceSendsTokenToItsHost(t *testing.T) {
+	var auth string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(testSnapshotJSON))
+	}))
+	defer srv.Close()
+
+	src := &HTTPSource{Client: srv.Client()}
+	location, err := url.Parse(srv.URL + "/snap.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ERST_SNAPSHOT_TOKEN", "secret")
+	for host, want := range map[string]string{location.Hostname(): "Bearer secret", location.Host: "Bearer secret", "other.example.org": ""} {
+		t.Setenv("ERST_SNAPSHOT_TOKEN_HOST", host)
+		rc, err := src.Open(context.Background(), location)
+		if err != nil {
+			t.Fatalf("Open with host %s: %v", host, err)
+		}
+		rc.Close()
+		if auth != want {
+			t.Errorf("host %s: Authorization = %q, want %q", host, auth, want)
+		}
+	}
+}
+
+func TestLoadFromUnknownScheme(t *testing.T) {
+	_, err := LoadFrom(context.Background(), "ftp://host/snap.json")
+	if err == nil || !strings.Contains(err.Error(), "unsupported") {
+		t.Fatalf("expected unsupported scheme error, got %v", err)
+	}
+}
+
+func TestS3Source(t *testing.T) {
+	var path, auth, token string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		auth = r.Header.Get("Authorization")
+		token = r.Header.Get("X-Amz-Security-Token")
+		w.Write([]byte(testSnapshotJSON))
+	}))
+	defer srv.Close()
+
+	env := map[string]string{
+		"AWS_ENDPOINT_URL_S3":   srv.URL,
+		"AWS_REGION":            "eu-west-1",
+		"AWS_ACCESS_KEY_ID":     "AKIDEXAMPLE",
+		"AWS_SECRET_ACCESS_KEY": "secret",
+		"AWS_SESSION_TOKEN":     "session",
+	}
+	src := &S3Source{
+		Client: srv.Client(),
+		Getenv: func(k string) string { return env[k] },
+		Now:    func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}
+	RegisterSource("s3", src)
+	defer RegisterSource("s3", NewS3Source())
+
+	snap, err := LoadFrom(context.Background(), "s3://fixtures/mainnet/my snap.json")
+	if err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if len(snap.LedgerEntries) != 1 {
+		t.Errorf("got %d entries, want 1", len(snap.LedgerEntries))
+	}
+	if path != "/fixtures/mainnet/my%20snap.json" {
+		t.Errorf("path = %q", path)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20250102/eu-west-1/s3/aws4_request") {
+		t.Errorf("Authorization = %q", auth)
+	}
+	if token != "session" {
+		t.Errorf("security token = %q", token)
+	}
+
+	// Anonymous access sends no signature
+	delete(env, "AWS_ACCESS_KEY_ID")
+	if _, err := LoadFrom(context.Background(), "s3://fixtures/snap.json"); err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if auth != "" {
+		t.Errorf("expected unsigned request, got %q", auth)
+	}
+
+	if _, err := LoadFrom(context.Background(), "s3://fixtures"); err == nil {
+		t.Error("expected error for location without key")
+	}
+}
+
+func TestGCSSource(t *testing.T) {
+	var path, auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath() + "?" + r.URL.RawQuery
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(testSnapshotJSON))
+	}))
+	defer srv.Close()
+
+	env := map[string]string{
+		"STORAGE_EMULATOR_HOST":     strings.TrimPrefix(srv.URL, "http://"),
+		"GOOGLE_OAUTH_ACCESS_TOKEN": "ya29.token",
+	}
+	RegisterSource("gs", &GCSSource{Client: srv.Client(), Getenv: func(k string) string { return env[k] }})
+	defer RegisterSource("gs", NewGCSSource())
+
+	if _, err := LoadFrom(context.Background(), "gs://fixtures/mainnet/snap.json"); err != nil {
+		t.Fatalf("LoadFrom: %v", err)
+	}
+	if path != "/storage/v1/b/fixtures/o/mainnet%2Fsnap.json?alt=media" {
+		t.Errorf("path = %q", path)
+	}
+	if auth != "Bearer ya29.token" {
+		t.Errorf("Authorization = %q", auth)
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sourcemap maps WASM code offsets and contract function names back
+// to Rust source locations, using either DWARF debug info embedded in the
+// contract or a JSON source map.
+package sourcemap
+
+import (
+	"debug/dwarf"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/wasm"
+)
+
+// Location is a position in contract source code
+type Location struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column,omitempty"`
+	Function string `json:"function,omitempty"`
+}
+
+func (l Location) String() string {
+	if l.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d", l.File, l.Line, l.Column)
+	}
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+type lineRow struct {
+	addr uint64
+	end  bool
+	loc  Location
+}
+
+// Map resolves code offsets and function names to source locations
+type Map struct {
+	lines []lineRow
+	funcs map[string]Location
+	// codeStart is the file offset of the WASM code section body. DWARF
+	// addresses in WASM are relative to it.
+	codeStart uint64
+}
+
+// jsonMap is the on-disk format of a hand-written or tool-generated map
+type jsonMap struct {
+	Functions map[string]Location `json:"functions"`
+	Lines     []struct {
+		Offset uint64 `json:"offset"`
+		Location
+	} `json:"lines"`
+}
+
+// Load reads a source map from a WASM file with debug symbols or a JSON map
+func Load(path string) (*Map, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source map: %w", err)
+	}
+	if wasm.IsModule(data) {
+		return FromWASM(data)
+	}
+	return FromJSON(data)
+}
+
+// FromJSON parses a JSON source map
+func FromJSON(data []byte) (*Map, error) {
+	var jm jsonMap
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, fmt.Errorf("failed to parse source map: %w", err)
+	}
+
+	m := &Map{funcs: make(map[string]Location)}
+	for name, loc := range jm.Functions {
+		if loc.Function == "" {
+			loc.Function = name
+		}
+		m.funcs[name] = loc
+	}
+	for _, l := range jm.Lines {
+		m.lines = append(m.lines, lineRow{addr: l.Offset, loc: l.Location})
+	}
+	m.sortLines()
+	return m, nil
+}
+
+// FromWASM extracts line tables and function declarations from the DWARF
+// custom sections of a contract built with debug symbols
+func FromWASM(wasm []byte) (*Map, error) {
+	sections, codeStart, err := wasmSections(wasm)
+	if err != nil {
+		return nil, err
+	}
+
+	info, line := sections[".debug_info"], sections[".debug_line"]
+	if info == nil || line == nil {
+		return nil, errors.New("contract has no DWARF debug info; rebuild with debug = true")
+	}
+
+	d, err := dwarf.New(sections[".debug_abbrev"], sections[".debug_aranges"], nil, info, line, nil, sections[".debug_ranges"], sections[".debug_str"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DWARF: %w", err)
+	}
+	for _, name := range []string{".debug_line_str", ".debug_str_offsets", ".debug_addr", ".debug_rnglists", ".debug_loclists"} {
+		if sec := sections[name]; sec != nil {
+			if err := d.AddSection(name, sec); err != nil {
+				return nil, fmt.Errorf("failed to load %s: %w", name, err)
+			}
+		}
+	}
+
+	m := &Map{funcs: make(map[string]Location), codeStart: codeStart}
+	if err := m.loadDWARF(d); err != nil {
+		return nil, err
+	}
+	m.sortLines()
+	return m, nil
+}
+
+func (m *Map) loadDWARF(d *dwarf.Data) error {
+	r := d.Reader()
+	var files []*dwarf.LineFile
+
+	for {
+		entry, err := r.Next()
+		if err != nil {
+			return fmt.Errorf("failed to read DWARF entry: %w", err)
+		}
+		if entry == nil {
+			return nil
+		}
+
+		switch entry.Tag {
+		case dwarf.TagCompileUnit:
+			files = nil
+			lr, err := d.LineReader(entry)
+			if err != nil || lr == nil {
+				continue
+			}
+			files = lr.Files()
+
+			var le dwarf.LineEntry
+			for {
+				if err := lr.Next(&le); err != nil {
+					if err == io.EOF {
+						break
+					}
+					return fmt.Errorf("failed to read line table: %w", err)
+				}
+				row := lineRow{addr: le.Address, end: le.EndSequence}
+				if le.File != nil {
+					row.loc = Location{File: le.File.Name, Line: le.Line, Column: le.Column}
+				}
+				m.lines = append(m.lines, row)
+			}
+
+		case dwarf.TagSubprogram:
+			name, _ := entry.Val(dwarf.AttrName).(string)
+			if name == "" {
+				continue
+			}
+			if _, seen := m.funcs[name]; seen {
+				continue
+			}
+			loc := Location{Function: name}
+			if idx, ok := entry.Val(dwarf.AttrDeclFile).(int64); ok && idx >= 0 && int(idx) < len(files) && files[idx] != nil {
+				loc.File = files[idx].Name
+			}
+			if ln, ok := entry.Val(dwarf.AttrDeclLine).(int64); ok {
+				loc.Line = int(ln)
+			}
+			if loc.File != "" {
+				m.funcs[name] = loc
+			}
+		}
+	}
+}
+
+func (m *Map) sortLines() {
+	sort.SliceStable(m.lines, func(i, j int) bool { return m.lines[i].addr < m.lines[j].addr })
+}
+
+// Lookup maps a code-section-relative offset to the closest preceding line
+func (m *Map) Lookup(offset uint64) (Location, bool) {
+	i := sort.Search(len(m.lines), func(i int) bool { return m.lines[i].addr > offset })
+	if i == 0 {
+		return Location{}, false
+	}
+	row := m.lines[i-1]
+	if row.end || row.loc.File == "" {
+		return Location{}, false
+	}
+	return row.loc, true
+}
+
+// LookupFileOffset maps an offset into the whole WASM file, as printed in
+// wasm backtraces, to a source location
+func (m *Map) LookupFileOffset(offset uint64) (Location, bool) {
+	if offset < m.codeStart {
+		return Location{}, false
+	}
+	return m.Lookup(offset - m.codeStart)
+}
+
+// Function returns the declaration site of a contract function
+func (m *Map) Function(name string) (Location, bool) {
+	loc, ok := m.funcs[name]
+	return loc, ok
+}
+
+// wasmSections returns the custom sections by name and the file offset of
+// the code section body
+func wasmSections(code []byte) (map[string][]byte, uint64, error) {
+	all, err := wasm.Sections(code)
+	if err != nil {
+		return nil, 0, err
+	}
+	sections := make(map[string][]byte)
+	var codeStart uint64
+	for _, s := range all {
+		switch s.ID {
+		case wasm.SectionCustom:
+			sections[s.Name] = s.Data
+		case wasm.SectionCode:
+			codeStart = uint64(s.Offset)
+		}
+	}
+	return sections, codeStart, nil
+}
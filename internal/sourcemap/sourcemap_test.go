@@ -0,0 +1,109 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package sourcemap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMap = `{
+  "functions": {
+    "transfer": {"file": "src/lib.rs", "line": 40},
+    "spend_balance": {"file": "src/balance.rs", "line": 12}
+  },
+  "lines": [
+    {"offset": 16, "file": "src/lib.rs", "line": 45, "column": 9},
+    {"offset": 64, "file": "src/balance.rs", "line": 18}
+  ]
+}`
+
+func TestFromJSON_Lookup(t *testing.T) {
+	m, err := FromJSON([]byte(testMap))
+	require.NoError(t, err)
+
+	loc, ok := m.Lookup(20)
+	require.True(t, ok)
+	assert.Equal(t, "src/lib.rs:45:9", loc.String())
+
+	loc, ok = m.Lookup(100)
+	require.True(t, ok)
+	assert.Equal(t, "src/balance.rs:18", loc.String())
+
+	_, ok = m.Lookup(4)
+	assert.False(t, ok, "offsets before the first row have no location")
+
+	fn, ok := m.Function("transfer")
+	require.True(t, ok)
+	assert.Equal(t, "transfer", fn.Function)
+}
+
+func TestLoad_DetectsFormat(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "map.json")
+	require.NoError(t, os.WriteFile(jsonPath, []byte(testMap), 0644))
+	_, err := Load(jsonPath)
+	assert.NoError(t, err)
+
+	// Minimal module with a non-debug custom section and an empty code section
+	wasm := []byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00}
+	wasm = append(wasm, 0x00, 0x05, 0x04, 'n', 'a', 'm', 'e')
+	wasm = append(wasm, 0x0a, 0x01, 0x00)
+	wasmPath := filepath.Join(dir, "contract.wasm")
+	require.NoError(t, os.WriteFile(wasmPath, wasm, 0644))
+
+	_, err = Load(wasmPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no DWARF debug info")
+
+	sections, codeStart, err := wasmSections(wasm)
+	require.NoError(t, err)
+	assert.Contains(t, sections, "name")
+	assert.Equal(t, uint64(len(wasm)-1), codeStart)
+
+	_, _, err = wasmSections(wasm[:len(wasm)-2])
+	assert.Error(t, err, "truncated sections must be rejected")
+}
+
+func TestStackTrace(t *testing.T) {
+	m, err := FromJSON([]byte(testMap))
+	require.NoError(t, err)
+
+	contract := "CDLZFC3SYJYDZT7K67VZ75HPJVIEUVNIXF47ZG2FB2RMQQVU2HHGCYSC"
+	events := []simulator.DiagnosticEvent{
+		{ContractID: &contract, Topics: []string{"Symbol(ScSymbol(StringM(fn_call)))", "Bytes(..)", "Symbol(ScSymbol(StringM(transfer)))"}},
+		{Topics: []string{"fn_call", "Bytes(..)", "balance"}},
+		{Topics: []string{"fn_return", "balance"}},
+		{Topics: []string{"fn_call", "Bytes(..)", "spend_balance"}},
+		{Topics: []string{"Symbol(ScSymbol(StringM(error)))", "Error(Contract, #10)"}},
+		{Topics: []string{"fn_return", "spend_balance"}},
+	}
+
+	frames := StackTrace(events, m)
+	require.Len(t, frames, 2)
+	assert.Equal(t, "at spend_balance (src/balance.rs:12)", frames[0].String())
+	assert.Equal(t, "at transfer (src/lib.rs:40)", frames[1].String())
+	assert.Equal(t, contract, frames[1].Contract)
+
+	assert.Len(t, StackTrace(events[:2], nil), 2, "unreturned calls form the stack when no error event is present")
+}
+
+func TestTraceFromBacktrace(t *testing.T) {
+	m, err := FromJSON([]byte(testMap))
+	require.NoError(t, err)
+
+	msg := "Wasm Trap: unreachable\nwasm backtrace:\n    0: 0x14 - <unknown>!spend_balance\n    1: 0x999 - <unknown>!<unknown>\n"
+	frames := TraceFromBacktrace(msg, m)
+	require.Len(t, frames, 2)
+	assert.Equal(t, "at spend_balance (src/lib.rs:45:9)", frames[0].String())
+	assert.Equal(t, "at 0x999 (src/balance.rs:18)", frames[1].String())
+
+	assert.Nil(t, TraceFromBacktrace(msg, nil))
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package sourcemap
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+// Frame is one entry of a reconstructed contract call stack
+type Frame struct {
+	Contract string    `json:"contract,omitempty"`
+	Function string    `json:"function"`
+	Location *Location `json:"location,omitempty"`
+}
+
+func (f Frame) String() string {
+	s := "at " + f.Function
+	if f.Location != nil {
+		s += " (" + f.Location.String() + ")"
+	}
+	return s
+}
+
+// symbolPattern pulls the innermost value out of Debug-formatted ScVals such
+// as Symbol(ScSymbol(StringM(transfer)))
+var symbolPattern = regexp.MustCompile(`([^()\s]+)\)*$`)
+
+// backtracePattern matches wasm backtrace lines like "0: 0x1a2b - <unknown>!transfer"
+var backtracePattern = regexp.MustCompile(`(?m)^\s*\d+:\s+0x([0-9a-fA-F]+)\s+-\s+(?:[^!\s]*!)?(\S+)`)
+
+func symbol(topic string) string {
+	if m := symbolPattern.FindStringSubmatch(strings.TrimSpace(topic)); m != nil {
+		return m[1]
+	}
+	return topic
+}
+
+// StackTrace rebuilds the call stack active at the first contract error by
+// replaying fn_call/fn_return diagnostic events. Innermost frame first.
+func StackTrace(events []simulator.DiagnosticEvent, m *Map) []Frame {
+	var stack []Frame
+	var atError []Frame
+
+	for _, ev := range events {
+		if len(ev.Topics) == 0 {
+			continue
+		}
+		switch kind := symbol(ev.Topics[0]); {
+		case kind == "fn_call" && len(ev.Topics) >= 3:
+			frame := Frame{Function: symbol(ev.Topics[2])}
+			if ev.ContractID != nil {
+				frame.Contract = *ev.ContractID
+			}
+			stack = append(stack, frame)
+		case kind == "fn_return":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case strings.Contains(strings.ToLower(kind), "error"):
+			if atError == nil && len(stack) > 0 {
+				atError = append([]Frame(nil), stack...)
+			}
+		}
+	}
+
+	// Without an explicit error event, whatever is still open failed to return
+	if atError == nil {
+		atError = stack
+	}
+
+	out := make([]Frame, 0, len(atError))
+	for i := len(atError) - 1; i >= 0; i-- {
+		f := atError[i]
+		if m != nil {
+			if loc, ok := m.Function(f.Function); ok {
+				f.Location = &loc
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// TraceFromBacktrace maps the wasm backtrace embedded in a simulator error
+// message, if any, to source frames. Innermost frame first.
+func TraceFromBacktrace(msg string, m *Map) []Frame {
+	if m == nil {
+		return nil
+	}
+
+	var frames []Frame
+	for _, match := range backtracePattern.FindAllStringSubmatch(msg, -1) {
+		offset, err := strconv.ParseUint(match[1], 16, 64)
+		if err != nil {
+			continue
+		}
+		frame := Frame{Function: match[2]}
+		if loc, ok := m.LookupFileOffset(offset); ok {
+			frame.Location = &loc
+		} else if loc, ok := m.Function(frame.Function); ok {
+			frame.Location = &loc
+		}
+		if frame.Function == "<unknown>" && frame.Location != nil && frame.Location.Function != "" {
+			frame.Function = frame.Location.Function
+		}
+		if frame.Function == "<unknown>" {
+			frame.Function = fmt.Sprintf("0x%x", offset)
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// APIKey is a serve-mode API key. The secret itself is never stored, only
+// its SHA-256 hash.
+type APIKey struct {
+	ID         string     `json:"id"`
+	Tenant     string     `json:"tenant"`
+	Name       string     `json:"name"`
+	Role       string     `json:"role"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Revoked reports whether the key has been revoked
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Usage counts what a key consumed
+type Usage struct {
+	Requests    int64 `json:"requests"`
+	Simulations int64 `json:"simulations"`
+	RPCCalls    int64 `json:"rpc_calls"`
+}
+
+// Add returns the sum of u and o
+func (u Usage) Add(o Usage) Usage {
+	return Usage{
+		Requests:    u.Requests + o.Requests,
+		Simulations: u.Simulations + o.Simulations,
+		RPCCalls:    u.RPCCalls + o.RPCCalls,
+	}
+}
+
+// DailyUsage is the usage of one key on one UTC day
+type DailyUsage struct {
+	// Day is formatted as 2006-01-02
+	Day string `json:"day"`
+	Usage
+}
+
+const apiKeyColumns = `id, tenant, name, role, scopes, created_at, revoked_at, last_used_at`
+
+// CreateAPIKey stores k with the hash of its secret
+func (s *DB) CreateAPIKey(ctx context.Context, k *APIKey, secretHash string) error {
+	if k.ID == "" || k.Tenant == "" {
+		return fmt.Errorf("API key ID and tenant are required")
+	}
+	if k.CreatedAt.IsZero() {
+		k.CreatedAt = time.Now().UTC()
+	}
+	scopes, err := json.Marshal(k.Scopes)
+	if err != nil {
+		return fmt.Errorf("failed to encode API key scopes: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, s.rebind(`
+	INSERT INTO api_keys (id, tenant, name, role, scopes, key_hash, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)`),
+		k.ID, k.Tenant, k.Name, k.Role, string(scopes), secretHash, millis(k.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to save API key: %w", err)
+	}
+	return nil
+}
+
+// APIKey returns the key with the given ID
+func (s *DB) APIKey(ctx context.Context, id string) (*APIKey, error) {
+	row := s.db.QueryRowContext(ctx, s.rebind(`SELECT `+apiKeyColumns+` FROM api_keys WHERE id = ?`), id)
+	k, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("API key %q: %w", id, ErrNotFound)
+	}
+	return k, err
+}
+
+// APIKeyByHash returns the key whose secret hashes to secretHash, revoked
+// or not
+func (s *DB) APIKeyByHash(ctx context.Context, secretHash string) (*APIKey, error) {
+	row := s.db.QueryRowContext(ctx, s.rebind(`SELECT `+apiKeyColumns+` FROM api_keys WHERE key_hash = ?`), secretHash)
+	k, err := scanAPIKey(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	return k, err
+}
+
+// APIKeys returns the keys of tenant, or of every tenant when it is empty,
+// ordered by tenant and creation time
+func (s *DB) APIKeys(ctx context.Context, tenant string) ([]APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys`
+	var args []interface{}
+	if tenant != "" {
+		query += ` WHERE tenant = ?`
+		args = append(args, tenant)
+	}
+	rows, err := s.db.QueryContext(ctx, s.rebind(query+` ORDER BY tenant, created_at, id`), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating API keys: %w", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes the key with the given ID. Revoking a key twice
+// keeps the first revocation time.
+func (s *DB) RevokeAPIKey(ctx context.Context, id string) (*APIKey, error) {
+	_, err := s.db.ExecContext(ctx, s.rebind(`UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at = 0`), millis(time.Now()), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	return s.APIKey(ctx, id)
+}
+
+// AddUsage adds u to the usage of key id on the UTC day of at. Counts from
+// every replica add up.
+func (s *DB) AddUsage(ctx context.Context, id string, u Usage, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, s.rebind(`
+	INSERT INTO api_key_usage (key_id, day, requests, simulations, rpc_calls) VALUES (?, ?, ?, ?, ?)
+	ON CONFLICT (key_id, day) DO UPDATE SET
+		requests = api_key_usage.requests + excluded.requests,
+		simulations = api_key_usage.simulations + excluded.simulations,
+		rpc_calls = api_key_usage.rpc_calls + excluded.rpc_calls`),
+		id, at.UTC().Format(time.DateOnly), u.Requests, u.Simulations, u.RPCCalls)
+	if err != nil {
+		return fmt.Errorf("failed to record API key usage: %w", err)
+	}
+	if u.Requests > 0 {
+		if _, err := s.db.ExecContext(ctx, s.rebind(`UPDATE api_keys SET last_used_at = ? WHERE id = ?`), millis(at), id); err != nil {
+			return fmt.Errorf("failed to record API key use: %w", err)
+		}
+	}
+	return nil
+}
+
+// UsageSince returns the daily usage of key id from the UTC day of since,
+// oldest first
+func (s *DB) UsageSince(ctx context.Context, id string, since time.Time) ([]DailyUsage, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`
+	SELECT day, requests, simulations, rpc_calls FROM api_key_usage
+	WHERE key_id = ? AND day >= ? ORDER BY day`), id, since.UTC().Format(time.DateOnly))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API key usage: %w", err)
+	}
+	defer rows.Close()
+
+	var days []DailyUsage
+	for rows.Next() {
+		var d DailyUsage
+		if err := rows.Scan(&d.Day, &d.Requests, &d.Simulations, &d.RPCCalls); err != nil {
+			return nil, fmt.Errorf("failed to scan API key usage: %w", err)
+		}
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating API key usage: %w", err)
+	}
+	return days, nil
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row scanner) (*APIKey, error) {
+	var k APIKey
+	var scopes string
+	var created, revoked, used int64
+	if err := row.Scan(&k.ID, &k.Tenant, &k.Name, &k.Role, &scopes, &created, &revoked, &used); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan API key: %w", err)
+	}
+	if err := json.Unmarshal([]byte(scopes), &k.Scopes); err != nil {
+		return nil, fmt.Errorf("failed to decode API key scopes: %w", err)
+	}
+	k.CreatedAt = fromMillis(created)
+	if revoked != 0 {
+		t := fromMillis(revoked)
+		k.RevokedAt = &t
+	}
+	if used != 0 {
+		t := fromMillis(used)
+		k.LastUsedAt = &t
+	}
+	return &k, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAPIKeys(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	for _, k := range []*APIKey{
+		{ID: "k1", Tenant: "payments", Name: "ci", Role: "developer", Scopes: []string{"debug"}},
+		{ID: "k2", Tenant: "risk", Name: "dashboards", Role: "analyst", Scopes: []string{"sessions"}},
+	} {
+		if err := db.CreateAPIKey(ctx, k, "hash-"+k.ID); err != nil {
+			t.Fatalf("CreateAPIKey: %v", err)
+		}
+	}
+	if err := db.CreateAPIKey(ctx, &APIKey{ID: "k3", Tenant: "risk"}, "hash-k1"); err == nil {
+		t.Error("expected a duplicate secret hash to be rejected")
+	}
+
+	k, err := db.APIKeyByHash(ctx, "hash-k1")
+	if err != nil || k.ID != "k1" || !k.HasScope("debug") || k.HasScope("sessions") || k.Revoked() {
+		t.Fatalf("APIKeyByHash = %+v, %v", k, err)
+	}
+	if _, err := db.APIKeyByHash(ctx, "nope"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	if keys, err := db.APIKeys(ctx, "risk"); err != nil || len(keys) != 1 || keys[0].ID != "k2" {
+		t.Errorf("APIKeys(risk) = %+v, %v", keys, err)
+	}
+	if keys, err := db.APIKeys(ctx, ""); err != nil || len(keys) != 2 {
+		t.Errorf("APIKeys = %+v, %v", keys, err)
+	}
+
+	revoked, err := db.RevokeAPIKey(ctx, "k1")
+	if err != nil || !revoked.Revoked() {
+		t.Fatalf("RevokeAPIKey = %+v, %v", revoked, err)
+	}
+	again, _ := db.RevokeAPIKey(ctx, "k1")
+	if !again.RevokedAt.Equal(*revoked.RevokedAt) {
+		t.Error("expected a second revocation to keep the first time")
+	}
+	if _, err := db.RevokeAPIKey(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAPIKeyUsage(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	if err := db.CreateAPIKey(ctx, &APIKey{ID: "k1", Tenant: "payments", Scopes: []string{}}, "h"); err != nil {
+		t.Fatal(err)
+	}
+
+	yesterday := time.Now().Add(-24 * time.Hour)
+	for _, u := range []struct {
+		usage Usage
+		at    time.Time
+	}{
+		{Usage{Requests: 1}, yesterday},
+		{Usage{Requests: 1, Simulations: 1, RPCCalls: 1}, time.Now()},
+		{Usage{Simulations: 1, RPCCalls: 2}, time.Now()},
+	} {
+		if err := db.AddUsage(ctx, "k1", u.usage, u.at); err != nil {
+			t.Fatalf("AddUsage: %v", err)
+		}
+	}
+
+	days, err := db.UsageSince(ctx, "k1", yesterday)
+	if err != nil || len(days) != 2 {
+		t.Fatalf("UsageSince = %+v, %v", days, err)
+	}
+	if days[1].Usage != (Usage{Requests: 1, Simulations: 2, RPCCalls: 3}) {
+		t.Errorf("expected counts to add up, got %+v", days[1])
+	}
+	if days, _ := db.UsageSince(ctx, "k1", time.Now()); len(days) != 1 {
+		t.Errorf("expected only today, got %+v", days)
+	}
+
+	k, _ := db.APIKey(ctx, "k1")
+	if k.LastUsedAt == nil {
+		t.Error("expected last_used_at to be set")
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/audit"
+)
+
+// auditLockID serializes appends from every replica so the hash chain has
+// no forks
+const auditLockID = 7_245_117_002
+
+// AppendAudit fills in the sequence number, time and hash chain of e and
+// stores it. Entries form the same chain as the audit.jsonl files, so
+// they verify the same way.
+func (s *DB) AppendAudit(ctx context.Context, e audit.Entry) (audit.Entry, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return e, fmt.Errorf("failed to append audit entry: %w", err)
+	}
+	defer tx.Rollback()
+
+	if s.dialect == Postgres {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, auditLockID); err != nil {
+			return e, fmt.Errorf("failed to lock audit log: %w", err)
+		}
+	}
+
+	var lastSeq int64
+	var lastHash string
+	err = tx.QueryRowContext(ctx, `SELECT seq, hash FROM audit_log ORDER BY seq DESC LIMIT 1`).Scan(&lastSeq, &lastHash)
+	if err != nil && err != sql.ErrNoRows {
+		return e, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	e.Seq = lastSeq + 1
+	e.PrevHash = lastHash
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	if e.Hash, err = e.ComputeHash(); err != nil {
+		return e, fmt.Errorf("failed to hash audit entry: %w", err)
+	}
+	doc, err := json.Marshal(e)
+	if err != nil {
+		return e, err
+	}
+
+	if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO audit_log (seq, time, username, command, entry, hash) VALUES (?, ?, ?, ?, ?, ?)`),
+		e.Seq, millis(e.Time), e.User, e.Command, string(doc), e.Hash); err != nil {
+		return e, fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return e, fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	return e, nil
+}
+
+// AuditEntries returns the stored audit entries at or after since, oldest
+// first
+func (s *DB) AuditEntries(ctx context.Context, since time.Time) ([]audit.Entry, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(`SELECT entry FROM audit_log WHERE time >= ? ORDER BY seq`), millis(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []audit.Entry
+	for rows.Next() {
+		var doc string
+		var e audit.Entry
+		if err := rows.Scan(&doc); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if err := json.Unmarshal([]byte(doc), &e); err != nil {
+			return nil, fmt.Errorf("failed to decode audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// VerifyAudit checks the hash chain of the whole stored audit log. It
+// returns the number of entries and every break found.
+func (s *DB) VerifyAudit(ctx context.Context) (int, []audit.Problem, error) {
+	entries, err := s.AuditEntries(ctx, time.Time{})
+	if err != nil {
+		return 0, nil, err
+	}
+	return len(entries), audit.VerifyChain("audit_log", entries), nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/audit"
+)
+
+func TestAppendAudit(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	first, err := db.AppendAudit(ctx, audit.Entry{User: "alice", Command: "serve debug", Outcome: audit.OutcomeSuccess})
+	if err != nil {
+		t.Fatalf("AppendAudit: %v", err)
+	}
+	second, err := db.AppendAudit(ctx, audit.Entry{User: "bob", Command: "serve debug", Outcome: audit.OutcomeError})
+	if err != nil {
+		t.Fatalf("AppendAudit: %v", err)
+	}
+	if first.Seq != 1 || second.Seq != 2 || second.PrevHash != first.Hash {
+		t.Errorf("broken chain: %+v %+v", first, second)
+	}
+
+	entries, err := db.AuditEntries(ctx, time.Time{})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("AuditEntries = %d, %v", len(entries), err)
+	}
+	for _, e := range entries {
+		if hash, err := e.ComputeHash(); err != nil || hash != e.Hash {
+			t.Errorf("entry %d does not verify", e.Seq)
+		}
+	}
+}
+
+func TestVerifyAudit(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	for _, user := range []string{"alice", "bob", "carol"} {
+		if _, err := db.AppendAudit(ctx, audit.Entry{User: user, Command: "serve debug", Outcome: audit.OutcomeSuccess}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n, problems, err := db.VerifyAudit(ctx); err != nil || n != 3 || len(problems) != 0 {
+		t.Fatalf("VerifyAudit = %d, %v, %v", n, problems, err)
+	}
+
+	if _, err := db.db.ExecContext(ctx, `UPDATE audit_log SET entry = REPLACE(entry, 'alice', 'mallory') WHERE seq = 1`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.db.ExecContext(ctx, `DELETE FROM audit_log WHERE seq = 2`); err != nil {
+		t.Fatal(err)
+	}
+	_, problems, err := db.VerifyAudit(ctx)
+	if err != nil || len(problems) != 2 || problems[0].Message != "entry was modified" || problems[1].Message != "chain broken after entry 1" {
+		t.Errorf("expected the modified and the removed entry to be reported, got %v, %v", problems, err)
+	}
+}
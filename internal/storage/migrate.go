@@ -0,0 +1,183 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/logger"
+)
+
+// migrationLockID is the Postgres advisory lock that keeps replicas
+// starting together from migrating the same database at once
+const migrationLockID = 7_245_117_001
+
+// migration moves the schema from Version-1 to Version. Statements are
+// plain SQL that both dialects accept: timestamps are Unix milliseconds in
+// BIGINT columns and documents are JSON in TEXT columns.
+type migration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+// migrations lists every schema change in order. Append new entries; never
+// edit a released migration.
+var migrations = []migration{
+	{
+		Version:     1,
+		Description: "create sessions and audit_log tables",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS sessions (
+				id TEXT PRIMARY KEY,
+				tx_hash TEXT NOT NULL,
+				network TEXT NOT NULL,
+				status TEXT NOT NULL,
+				anchor TEXT NOT NULL DEFAULT '',
+				sep_flow TEXT NOT NULL DEFAULT '',
+				customer_ref TEXT NOT NULL DEFAULT '',
+				created_at BIGINT NOT NULL,
+				last_access_at BIGINT NOT NULL,
+				data TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_sessions_last_access ON sessions(last_access_at)`,
+			`CREATE INDEX IF NOT EXISTS idx_sessions_tx_hash ON sessions(tx_hash)`,
+			`CREATE INDEX IF NOT EXISTS idx_sessions_anchor ON sessions(anchor, sep_flow)`,
+			`CREATE TABLE IF NOT EXISTS audit_log (
+				seq BIGINT PRIMARY KEY,
+				time BIGINT NOT NULL,
+				username TEXT NOT NULL,
+				command TEXT NOT NULL,
+				entry TEXT NOT NULL,
+				hash TEXT NOT NULL
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_audit_log_time ON audit_log(time)`,
+		},
+	},
+	{
+		Version:     2,
+		Description: "create api_keys and api_key_usage tables",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS api_keys (
+				id TEXT PRIMARY KEY,
+				tenant TEXT NOT NULL,
+				name TEXT NOT NULL,
+				role TEXT NOT NULL,
+				scopes TEXT NOT NULL,
+				key_hash TEXT NOT NULL UNIQUE,
+				created_at BIGINT NOT NULL,
+				revoked_at BIGINT NOT NULL DEFAULT 0,
+				last_used_at BIGINT NOT NULL DEFAULT 0
+			)`,
+			`CREATE INDEX IF NOT EXISTS idx_api_keys_tenant ON api_keys(tenant)`,
+			`CREATE TABLE IF NOT EXISTS api_key_usage (
+				key_id TEXT NOT NULL,
+				day TEXT NOT NULL,
+				requests BIGINT NOT NULL DEFAULT 0,
+				simulations BIGINT NOT NULL DEFAULT 0,
+				rpc_calls BIGINT NOT NULL DEFAULT 0,
+				PRIMARY KEY (key_id, day)
+			)`,
+		},
+	},
+	{
+		Version:     3,
+		Description: "add the owning tenant to sessions",
+		Statements: []string{
+			`ALTER TABLE sessions ADD COLUMN tenant TEXT NOT NULL DEFAULT ''`,
+			`CREATE INDEX IF NOT EXISTS idx_sessions_tenant ON sessions(tenant, created_at)`,
+		},
+	},
+	{
+		Version:     4,
+		Description: "create corpora and monitor_watches tables",
+		Statements: []string{
+			`CREATE TABLE IF NOT EXISTS corpora (
+				name TEXT PRIMARY KEY,
+				definition TEXT NOT NULL,
+				updated_at BIGINT NOT NULL
+			)`,
+			`CREATE TABLE IF NOT EXISTS monitor_watches (
+				name TEXT PRIMARY KEY,
+				definition TEXT NOT NULL,
+				updated_at BIGINT NOT NULL
+			)`,
+		},
+	},
+}
+
+// SchemaVersion is the schema version this build migrates databases to
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// migrate applies every migration newer than the database, each in its own
+// transaction
+func (s *DB) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		description TEXT NOT NULL,
+		applied_at BIGINT NOT NULL
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := s.apply(ctx, m); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+	}
+	return nil
+}
+
+// apply runs m unless another process already has
+func (s *DB) apply(ctx context.Context, m migration) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if s.dialect == Postgres {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+			return err
+		}
+	}
+
+	var applied int
+	err = tx.QueryRowContext(ctx, s.rebind(`SELECT version FROM schema_migrations WHERE version = ?`), m.Version).Scan(&applied)
+	if err == nil {
+		return nil
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	for _, stmt := range m.Statements {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO schema_migrations (version, description, applied_at) VALUES (?, ?, ?)`),
+		m.Version, m.Description, millis(time.Now())); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	logger.Logger.Info("Applied storage migration", "version", m.Version, "description", m.Description, "dialect", s.dialect)
+	return nil
+}
+
+// Version returns the newest migration applied to the database
+func (s *DB) Version(ctx context.Context) (int, error) {
+	var v sql.NullInt64
+	if err := s.db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&v); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return int(v.Int64), nil
+}
@@ -0,0 +1,9 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build postgres
+
+package storage
+
+// The Postgres driver is only linked into builds made with -tags postgres
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -0,0 +1,129 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/corpus"
+)
+
+// PutCorpus stores the definition of a corpus: its name and pinned
+// entries. Ledger snapshots stay with the corpus files.
+func (s *DB) PutCorpus(ctx context.Context, c *corpus.Corpus) error {
+	doc, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to encode corpus: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, s.rebind(`
+	INSERT INTO corpora (name, definition, updated_at) VALUES (?, ?, ?)
+	ON CONFLICT (name) DO UPDATE SET definition = excluded.definition, updated_at = excluded.updated_at`),
+		c.Name, string(doc), millis(time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to save corpus %q: %w", c.Name, err)
+	}
+	return nil
+}
+
+// Corpus returns the stored definition of the corpus called name
+func (s *DB) Corpus(ctx context.Context, name string) (*corpus.Corpus, error) {
+	var doc string
+	err := s.db.QueryRowContext(ctx, s.rebind(`SELECT definition FROM corpora WHERE name = ?`), name).Scan(&doc)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("corpus %q: %w", name, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load corpus: %w", err)
+	}
+	var c corpus.Corpus
+	if err := json.Unmarshal([]byte(doc), &c); err != nil {
+		return nil, fmt.Errorf("failed to decode corpus %q: %w", name, err)
+	}
+	return &c, nil
+}
+
+// Corpora returns the names of the stored corpora in order
+func (s *DB) Corpora(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT name FROM corpora ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corpora: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan corpus: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating corpora: %w", err)
+	}
+	return names, nil
+}
+
+// ReplaceWatches makes watches the stored set of monitor watches and their
+// schedules, dropping any other
+func (s *DB) ReplaceWatches(ctx context.Context, watches []config.WatchConfig) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to save monitor watches: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM monitor_watches`); err != nil {
+		return fmt.Errorf("failed to save monitor watches: %w", err)
+	}
+	now := millis(time.Now())
+	for _, w := range watches {
+		if w.Name == "" {
+			return fmt.Errorf("watch name is required")
+		}
+		doc, err := json.Marshal(w)
+		if err != nil {
+			return fmt.Errorf("failed to encode watch %q: %w", w.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, s.rebind(`INSERT INTO monitor_watches (name, definition, updated_at) VALUES (?, ?, ?)`),
+			w.Name, string(doc), now); err != nil {
+			return fmt.Errorf("failed to save watch %q: %w", w.Name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to save monitor watches: %w", err)
+	}
+	return nil
+}
+
+// Watches returns every stored monitor watch, ordered by name
+func (s *DB) Watches(ctx context.Context) ([]config.WatchConfig, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT definition FROM monitor_watches ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list monitor watches: %w", err)
+	}
+	defer rows.Close()
+
+	var watches []config.WatchConfig
+	for rows.Next() {
+		var doc string
+		var w config.WatchConfig
+		if err := rows.Scan(&doc); err != nil {
+			return nil, fmt.Errorf("failed to scan monitor watch: %w", err)
+		}
+		if err := json.Unmarshal([]byte(doc), &w); err != nil {
+			return nil, fmt.Errorf("failed to decode monitor watch: %w", err)
+		}
+		watches = append(watches, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating monitor watches: %w", err)
+	}
+	return watches, nil
+}
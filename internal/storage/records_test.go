@@ -0,0 +1,67 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/config"
+	"github.com/dotandev/hintents/internal/corpus"
+)
+
+func TestCorpora(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	c := &corpus.Corpus{Name: "payments", Entries: []corpus.Entry{{Hash: "abc", Network: "testnet", ExpectedStatus: "success"}}}
+	if err := db.PutCorpus(ctx, c); err != nil {
+		t.Fatalf("PutCorpus: %v", err)
+	}
+	c.Entries = append(c.Entries, corpus.Entry{Hash: "def", Network: "testnet", ExpectedStatus: "error"})
+	if err := db.PutCorpus(ctx, c); err != nil {
+		t.Fatalf("PutCorpus again: %v", err)
+	}
+	got, err := db.Corpus(ctx, "payments")
+	if err != nil || len(got.Entries) != 2 || got.Entries[1].Hash != "def" {
+		t.Errorf("Corpus = %+v, %v", got, err)
+	}
+	if names, err := db.Corpora(ctx); err != nil || len(names) != 1 || names[0] != "payments" {
+		t.Errorf("Corpora = %v, %v", names, err)
+	}
+	if _, err := db.Corpus(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestWatches(t *testing.T) {
+	testWatches(t, openTestDB(t))
+}
+
+func testWatches(t *testing.T, db *DB) {
+	ctx := context.Background()
+	if err := db.ReplaceWatches(ctx, []config.WatchConfig{
+		{Name: "old", Session: "s0"},
+		{Name: "canary", Session: "s1", IntervalSeconds: 60},
+	}); err != nil {
+		t.Fatalf("ReplaceWatches: %v", err)
+	}
+	if err := db.ReplaceWatches(ctx, []config.WatchConfig{{Name: "canary", Session: "s1", IntervalSeconds: 300, Alerts: []string{"oncall"}}}); err != nil {
+		t.Fatalf("ReplaceWatches again: %v", err)
+	}
+	watches, err := db.Watches(ctx)
+	if err != nil {
+		t.Fatalf("Watches: %v", err)
+	}
+	if len(watches) != 1 || watches[0].Name != "canary" || watches[0].IntervalSeconds != 300 || len(watches[0].Alerts) != 1 {
+		t.Errorf("expected only the replacement watch, got %+v", watches)
+	}
+	if err := db.ReplaceWatches(ctx, []config.WatchConfig{{Session: "s1"}}); err == nil {
+		t.Error("expected a watch without a name to be rejected")
+	}
+	if watches, _ := db.Watches(ctx); len(watches) != 1 {
+		t.Errorf("expected a rejected replacement to keep the stored watches, got %+v", watches)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dotandev/hintents/internal/session"
+)
+
+// Sessions stores debug sessions in the database. It has the same methods
+// as session.Store, so either can back the MCP session tools.
+type Sessions struct {
+	db *DB
+	// tenant owns the sessions this store saves and, when scoped, is the
+	// only tenant whose sessions it reads
+	tenant string
+	scoped bool
+}
+
+// Sessions returns the session store of the database, across all tenants
+func (s *DB) Sessions() *Sessions {
+	return &Sessions{db: s}
+}
+
+// ForTenant returns a store that saves sessions owned by tenant and only
+// reads and deletes that tenant's sessions
+func (s *Sessions) ForTenant(tenant string) *Sessions {
+	return &Sessions{db: s.db, tenant: tenant, scoped: true}
+}
+
+// tenantWhere returns the condition limiting a query to the store's tenant
+func (s *Sessions) tenantWhere() ([]string, []interface{}) {
+	if !s.scoped {
+		return nil, nil
+	}
+	return []string{"tenant = ?"}, []interface{}{s.tenant}
+}
+
+// Save inserts or replaces a session
+func (s *Sessions) Save(ctx context.Context, data *session.SessionData) error {
+	if data.ID == "" {
+		return fmt.Errorf("session ID is required")
+	}
+	now := time.Now()
+	if data.CreatedAt.IsZero() {
+		data.CreatedAt = now
+	}
+	data.LastAccessAt = now
+	data.SchemaVersion = session.SchemaVersion
+
+	doc, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	_, err = s.db.db.ExecContext(ctx, s.db.rebind(`
+	INSERT INTO sessions (id, tenant, tx_hash, network, status, anchor, sep_flow, customer_ref, created_at, last_access_at, data)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (id) DO UPDATE SET
+		tx_hash = excluded.tx_hash,
+		network = excluded.network,
+		status = excluded.status,
+		anchor = excluded.anchor,
+		sep_flow = excluded.sep_flow,
+		customer_ref = excluded.customer_ref,
+		last_access_at = excluded.last_access_at,
+		data = excluded.data`),
+		data.ID, s.tenant, data.TxHash, data.Network, data.Status,
+		data.Context.Anchor, data.Context.SEPFlow, data.Context.CustomerRef,
+		millis(data.CreatedAt), millis(data.LastAccessAt), string(doc))
+	if err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// Load returns the session with the given ID and marks it accessed
+func (s *Sessions) Load(ctx context.Context, sessionID string) (*session.SessionData, error) {
+	where, args := s.tenantWhere()
+	where = append(where, "id = ?")
+	args = append(args, sessionID)
+	var doc string
+	err := s.db.db.QueryRowContext(ctx, s.db.rebind(`SELECT data FROM sessions WHERE `+strings.Join(where, " AND ")), args...).Scan(&doc)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("%w: %s", session.ErrNotFound, sessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	data, err := decodeSession(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	data.LastAccessAt = time.Now()
+	if _, err := s.db.db.ExecContext(ctx, s.db.rebind(`UPDATE sessions SET last_access_at = ? WHERE id = ?`),
+		millis(data.LastAccessAt), sessionID); err != nil {
+		return nil, fmt.Errorf("failed to update session access time: %w", err)
+	}
+	return data, nil
+}
+
+// List returns recent sessions, most recently accessed first
+func (s *Sessions) List(ctx context.Context, limit int) ([]*session.SessionData, error) {
+	return s.ListByContext(ctx, session.Context{}, limit)
+}
+
+// ListByContext returns recent sessions whose context has every field set
+// in filter, most recently accessed first
+func (s *Sessions) ListByContext(ctx context.Context, filter session.Context, limit int) ([]*session.SessionData, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	where, args := s.contextWhere(filter)
+	clause := ""
+	if len(where) > 0 {
+		clause = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	return s.query(ctx, `SELECT data FROM sessions `+clause+` ORDER BY last_access_at DESC LIMIT ?`, append(args, limit)...)
+}
+
+// Page returns up to limit sessions matching filter, newest first, starting
+// after cursor, and the cursor of the next page ("" on the last page).
+// Pages are ordered by creation time so they stay stable while sessions
+// are read.
+func (s *Sessions) Page(ctx context.Context, filter session.Context, cursor string, limit int) ([]*session.SessionData, string, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	where, args := s.contextWhere(filter)
+	if cursor != "" {
+		created, id, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		where = append(where, "(created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, created, created, id)
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	// One extra row tells whether there is a next page
+	sessions, err := s.query(ctx, `SELECT data FROM sessions `+clause+` ORDER BY created_at DESC, id DESC LIMIT ?`, append(args, limit+1)...)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(sessions) <= limit {
+		return sessions, "", nil
+	}
+	sessions = sessions[:limit]
+	last := sessions[limit-1]
+	return sessions, encodeCursor(millis(last.CreatedAt), last.ID), nil
+}
+
+// contextWhere returns the conditions selecting the store's sessions whose
+// context has every field set in filter
+func (s *Sessions) contextWhere(filter session.Context) ([]string, []interface{}) {
+	where, args := s.tenantWhere()
+	if filter.Anchor != "" {
+		where = append(where, "LOWER(anchor) = LOWER(?)")
+		args = append(args, filter.Anchor)
+	}
+	if filter.SEPFlow != "" {
+		where = append(where, "sep_flow = ?")
+		args = append(args, filter.SEPFlow)
+	}
+	if filter.CustomerRef != "" {
+		where = append(where, "customer_ref = ?")
+		args = append(args, filter.CustomerRef)
+	}
+	return where, args
+}
+
+// encodeCursor makes an opaque page cursor from the sort key of a session
+func encodeCursor(created int64, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(created, 10) + ":" + id))
+}
+
+func decodeCursor(cursor string) (int64, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", ErrInvalidCursor
+	}
+	ms, id, ok := strings.Cut(string(raw), ":")
+	created, err := strconv.ParseInt(ms, 10, 64)
+	if !ok || err != nil {
+		return 0, "", ErrInvalidCursor
+	}
+	return created, id, nil
+}
+
+func (s *Sessions) query(ctx context.Context, query string, args ...interface{}) ([]*session.SessionData, error) {
+	rows, err := s.db.db.QueryContext(ctx, s.db.rebind(query), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*session.SessionData
+	for rows.Next() {
+		var doc string
+		if err := rows.Scan(&doc); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		data, err := decodeSession(doc)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, data)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Delete removes a session by ID
+func (s *Sessions) Delete(ctx context.Context, sessionID string) error {
+	where, args := s.tenantWhere()
+	where = append(where, "id = ?")
+	args = append(args, sessionID)
+	res, err := s.db.db.ExecContext(ctx, s.db.rebind(`DELETE FROM sessions WHERE `+strings.Join(where, " AND ")), args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("%w: %s", session.ErrNotFound, sessionID)
+	}
+	return nil
+}
+
+// Cleanup removes sessions not accessed within ttl
+func (s *Sessions) Cleanup(ctx context.Context, ttl time.Duration) (int64, error) {
+	res, err := s.db.db.ExecContext(ctx, s.db.rebind(`DELETE FROM sessions WHERE last_access_at < ?`), millis(time.Now().Add(-ttl)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to clean up sessions: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+func decodeSession(doc string) (*session.SessionData, error) {
+	var data session.SessionData
+	if err := json.Unmarshal([]byte(doc), &data); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	if data.SchemaVersion < session.SchemaVersion {
+		if err := session.Upgrade(&data); err != nil {
+			return nil, err
+		}
+	}
+	return &data, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dotandev/hintents/internal/session"
+)
+
+func TestSessions(t *testing.T) {
+	testSessions(t, openTestDB(t))
+}
+
+func testSessions(t *testing.T, db *DB) {
+	ctx := context.Background()
+	store := db.Sessions()
+	id := "test-" + time.Now().Format("150405.000000000")
+
+	data := &session.SessionData{
+		ID:      id,
+		Status:  "saved",
+		Network: "testnet",
+		TxHash:  "abc",
+		Context: session.Context{Anchor: "Anchor.example", SEPFlow: "sep24", CustomerRef: "c-1"},
+		Runs:    []session.Run{{Name: "default", SimResponseJSON: `{"status":"success"}`}},
+	}
+	if err := store.Save(ctx, data); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data.Status = "resumed"
+	if err := store.Save(ctx, data); err != nil {
+		t.Fatalf("Save again: %v", err)
+	}
+
+	got, err := store.Load(ctx, id)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got.Status != "resumed" || got.TxHash != "abc" || len(got.Runs) != 1 || got.SchemaVersion != session.SchemaVersion {
+		t.Errorf("unexpected session %+v", got)
+	}
+
+	list, err := store.ListByContext(ctx, session.Context{Anchor: "anchor.EXAMPLE", SEPFlow: "sep24"}, 10)
+	if err != nil || len(list) != 1 || list[0].ID != id {
+		t.Errorf("ListByContext = %v, %v", list, err)
+	}
+	if list, _ := store.ListByContext(ctx, session.Context{CustomerRef: "other"}, 10); len(list) != 0 {
+		t.Errorf("expected no sessions for another customer, got %d", len(list))
+	}
+
+	if err := store.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Load(ctx, id); !errors.Is(err, session.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestSessionsCleanup(t *testing.T) {
+	ctx := context.Background()
+	store := openTestDB(t).Sessions()
+	if err := store.Save(ctx, &session.SessionData{ID: "s1", Status: "saved"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := store.Cleanup(ctx, time.Hour); err != nil || n != 0 {
+		t.Errorf("Cleanup(1h) = %d, %v", n, err)
+	}
+	if n, err := store.Cleanup(ctx, -time.Minute); err != nil || n != 1 {
+		t.Errorf("Cleanup(-1m) = %d, %v", n, err)
+	}
+}
+
+func TestSessionsPage(t *testing.T) {
+	ctx := context.Background()
+	store := openTestDB(t).Sessions()
+	created := time.Now().Add(-time.Hour)
+	// Two sessions share a creation time, so the ID breaks the tie
+	for _, id := range []string{"a", "b", "c", "d"} {
+		at := created
+		if id == "d" {
+			at = created.Add(time.Minute)
+		}
+		if err := store.Save(ctx, &session.SessionData{ID: id, CreatedAt: at, Status: "saved"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ids []string
+	cursor := ""
+	for {
+		page, next, err := store.Page(ctx, session.Context{}, cursor, 3)
+		if err != nil {
+			t.Fatalf("Page: %v", err)
+		}
+		for _, s := range page {
+			ids = append(ids, s.ID)
+		}
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if strings.Join(ids, ",") != "d,c,b,a" {
+		t.Errorf("pages = %v", ids)
+	}
+
+	if _, _, err := store.Page(ctx, session.Context{}, "not-a-cursor", 3); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestSessionsForTenant(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	payments, risk := db.Sessions().ForTenant("payments"), db.Sessions().ForTenant("risk")
+	if err := payments.Save(ctx, &session.SessionData{ID: "p1", Status: "saved"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := risk.Load(ctx, "p1"); !errors.Is(err, session.ErrNotFound) {
+		t.Errorf("expected another tenant's session to be hidden, got %v", err)
+	}
+	if page, _, err := risk.Page(ctx, session.Context{}, "", 10); err != nil || len(page) != 0 {
+		t.Errorf("Page = %v, %v", page, err)
+	}
+	if err := risk.Delete(ctx, "p1"); !errors.Is(err, session.ErrNotFound) {
+		t.Errorf("expected another tenant's delete to fail, got %v", err)
+	}
+	if _, err := payments.Load(ctx, "p1"); err != nil {
+		t.Errorf("Load by the owner: %v", err)
+	}
+	if list, err := db.Sessions().List(ctx, 10); err != nil || len(list) != 1 {
+		t.Errorf("expected the unscoped store to see every tenant, got %v, %v", list, err)
+	}
+}
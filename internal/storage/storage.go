@@ -0,0 +1,202 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package storage keeps serve-mode state in a database shared by every
+// replica: Postgres in production, or a SQLite file for a single instance
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Dialect is the SQL flavor of a database
+type Dialect string
+
+const (
+	SQLite   Dialect = "sqlite"
+	Postgres Dialect = "postgres"
+)
+
+// Pool defaults for Postgres. SQLite serializes writers itself and keeps
+// the database/sql defaults.
+const (
+	DefaultMaxOpenConns    = 10
+	DefaultMaxIdleConns    = 5
+	DefaultConnMaxLifetime = 30 * time.Minute
+)
+
+var (
+	// ErrNotFound is returned when no record has the requested ID
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidCursor is returned for a page cursor this store did not issue
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// postgresDrivers are the database/sql driver names that speak Postgres, in
+// order of preference
+var postgresDrivers = []string{"pgx", "postgres"}
+
+// ErrNoPostgresDriver is returned for a postgres:// DSN when no Postgres
+// driver is linked into the binary
+var ErrNoPostgresDriver = errors.New("this erst binary has no Postgres driver: rebuild it with -tags postgres")
+
+// Config selects and tunes the database
+type Config struct {
+	// DSN is a postgres:// or postgresql:// URL, or a SQLite file path
+	// with an optional sqlite:// prefix
+	DSN             string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// DB is an open, migrated database
+type DB struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// ParseDSN returns the dialect of dsn and the data source name to hand to
+// the driver
+func ParseDSN(dsn string) (Dialect, string, error) {
+	dsn = strings.TrimSpace(dsn)
+	switch {
+	case dsn == "":
+		return "", "", fmt.Errorf("storage DSN is required")
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"):
+		return Postgres, dsn, nil
+	case strings.HasPrefix(dsn, "sqlite://"):
+		return SQLite, strings.TrimPrefix(dsn, "sqlite://"), nil
+	case strings.Contains(dsn, "://"):
+		return "", "", fmt.Errorf("unsupported storage DSN %q: use postgres:// or a SQLite file path", redactDSN(dsn))
+	default:
+		return SQLite, dsn, nil
+	}
+}
+
+// Open connects to the database in cfg, applies the pool settings and runs
+// any pending migrations
+func Open(ctx context.Context, cfg Config) (*DB, error) {
+	dialect, source, err := ParseDSN(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	var db *sql.DB
+	switch dialect {
+	case Postgres:
+		driver, err := postgresDriver()
+		if err != nil {
+			return nil, err
+		}
+		if db, err = sql.Open(driver, source); err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", redactDSN(source), err)
+		}
+		db.SetMaxOpenConns(orDefault(cfg.MaxOpenConns, DefaultMaxOpenConns))
+		db.SetMaxIdleConns(orDefault(cfg.MaxIdleConns, DefaultMaxIdleConns))
+		lifetime := cfg.ConnMaxLifetime
+		if lifetime == 0 {
+			lifetime = DefaultConnMaxLifetime
+		}
+		db.SetConnMaxLifetime(lifetime)
+	default:
+		// Writers wait for each other instead of failing with SQLITE_BUSY
+		if db, err = sql.Open("sqlite", source+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_txlock=immediate"); err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", source, err)
+		}
+	}
+
+	s := &DB{db: db, dialect: dialect}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to %s: %w", redactDSN(source), err)
+	}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Dialect returns the SQL flavor of the database
+func (s *DB) Dialect() Dialect {
+	return s.dialect
+}
+
+// Close closes every pooled connection
+func (s *DB) Close() error {
+	return s.db.Close()
+}
+
+// Ping checks that the database is reachable
+func (s *DB) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// rebind rewrites the ? placeholders of query for the dialect. Queries in
+// this package never contain a literal question mark.
+func (s *DB) rebind(query string) string {
+	return Rebind(s.dialect, query)
+}
+
+// Rebind rewrites ? placeholders as $1, $2, ... for Postgres
+func Rebind(d Dialect, query string) string {
+	if d != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func postgresDriver() (string, error) {
+	drivers := sql.Drivers()
+	for _, name := range postgresDrivers {
+		if slices.Contains(drivers, name) {
+			return name, nil
+		}
+	}
+	return "", ErrNoPostgresDriver
+}
+
+// redactDSN hides the password of a URL DSN in errors and logs
+func redactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.User == nil {
+		return dsn
+	}
+	return u.Redacted()
+}
+
+func orDefault(v, fallback int) int {
+	if v > 0 {
+		return v
+	}
+	return fallback
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixMilli()
+}
+
+func fromMillis(ms int64) time.Time {
+	return time.UnixMilli(ms).UTC()
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(context.Background(), Config{DSN: filepath.Join(t.TempDir(), "erst.db")})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestOpenSQLiteUsesWAL(t *testing.T) {
+	db := openTestDB(t)
+	var mode string
+	if err := db.db.QueryRow(`PRAGMA journal_mode`).Scan(&mode); err != nil {
+		t.Fatalf("journal_mode: %v", err)
+	}
+	if mode != "wal" {
+		t.Errorf("journal_mode = %s, want wal", mode)
+	}
+}
+
+func TestParseDSN(t *testing.T) {
+	tests := []struct {
+		dsn     string
+		dialect Dialect
+		source  string
+		wantErr bool
+	}{
+		{"postgres://erst:pw@db:5432/erst?sslmode=require", Postgres, "postgres://erst:pw@db:5432/erst?sslmode=require", false},
+		{"postgresql://db/erst", Postgres, "postgresql://db/erst", false},
+		{"sqlite:///var/lib/erst/erst.db", SQLite, "/var/lib/erst/erst.db", false},
+		{"erst.db", SQLite, "erst.db", false},
+		{"mysql://db/erst", "", "", true},
+		{"  ", "", "", true},
+	}
+	for _, tt := range tests {
+		dialect, source, err := ParseDSN(tt.dsn)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.dsn)
+			}
+			continue
+		}
+		if err != nil || dialect != tt.dialect || source != tt.source {
+			t.Errorf("%q: got %q %q %v", tt.dsn, dialect, source, err)
+		}
+	}
+}
+
+func TestRebind(t *testing.T) {
+	q := "SELECT a FROM t WHERE b = ? AND c = ? LIMIT ?"
+	if got := Rebind(SQLite, q); got != q {
+		t.Errorf("sqlite query changed: %s", got)
+	}
+	if got := Rebind(Postgres, q); got != "SELECT a FROM t WHERE b = $1 AND c = $2 LIMIT $3" {
+		t.Errorf("postgres query = %s", got)
+	}
+}
+
+func TestRedactDSN(t *testing.T) {
+	got := redactDSN("postgres://erst:s3cret@db/erst")
+	if strings.Contains(got, "s3cret") || !strings.Contains(got, "erst:xxxxx@db") {
+		t.Errorf("redactDSN = %s", got)
+	}
+}
+
+func TestOpenPostgresWithoutDriver(t *testing.T) {
+	if _, err := postgresDriver(); err == nil {
+		t.Skip("a Postgres driver is linked into this build")
+	}
+	_, err := Open(context.Background(), Config{DSN: "postgres://erst@localhost/erst"})
+	if !errors.Is(err, ErrNoPostgresDriver) {
+		t.Errorf("expected ErrNoPostgresDriver, got %v", err)
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "erst.db")
+	for i := 0; i < 2; i++ {
+		db, err := Open(context.Background(), Config{DSN: "sqlite://" + path})
+		if err != nil {
+			t.Fatalf("Open #%d: %v", i+1, err)
+		}
+		v, err := db.Version(context.Background())
+		if err != nil || v != SchemaVersion() {
+			t.Errorf("Version = %d, %v; want %d", v, err, SchemaVersion())
+		}
+		db.Close()
+	}
+}
+
+// TestPostgres runs the store tests against a real database when
+// ERST_TEST_POSTGRES_DSN is set and the build has a Postgres driver
+func TestPostgres(t *testing.T) {
+	dsn := os.Getenv("ERST_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("ERST_TEST_POSTGRES_DSN not set")
+	}
+	db, err := Open(context.Background(), Config{DSN: dsn, MaxOpenConns: 4})
+	if errors.Is(err, ErrNoPostgresDriver) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	if db.Dialect() != Postgres {
+		t.Fatalf("dialect = %s", db.Dialect())
+	}
+	testSessions(t, db)
+	testWatches(t, db)
+}
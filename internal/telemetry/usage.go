@@ -0,0 +1,300 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	erstErrors "github.com/dotandev/hintents/internal/errors"
+	"github.com/dotandev/hintents/internal/platform"
+)
+
+// DefaultUsageEndpoint receives usage reports when no endpoint is configured.
+// It is empty in source builds and may be set at release time with
+// -ldflags "-X github.com/dotandev/hintents/internal/telemetry.DefaultUsageEndpoint=...".
+var DefaultUsageEndpoint = ""
+
+const (
+	// maxQueued caps the local queue; the oldest events are dropped first
+	maxQueued = 500
+	// flushThreshold is the queue length at which Record sends a batch
+	flushThreshold = 25
+	flushTimeout   = 2 * time.Second
+)
+
+// UsageEvent is one anonymized usage report. It never contains
+// transaction hashes, addresses, arguments or file paths.
+type UsageEvent struct {
+	Command    string `json:"command"`
+	DurationMs int64  `json:"duration_ms"`
+	// Outcome is "success" or a coarse failure class from ClassifyError
+	Outcome string `json:"outcome"`
+	Version string `json:"version"`
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	// Day is the UTC date only, so events cannot be correlated by time
+	Day string `json:"day"`
+}
+
+// UsageSettings is the stored opt-in decision
+type UsageSettings struct {
+	Enabled   bool      `json:"enabled"`
+	Endpoint  string    `json:"endpoint,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Usage records usage events in a local queue and sends them in batches
+// when reporting is enabled. It is off unless explicitly turned on.
+type Usage struct {
+	Dir    string
+	Client *http.Client
+	Getenv func(string) string
+	Now    func() time.Time
+}
+
+// NewUsage returns a Usage storing its state under the erst data directory
+func NewUsage() (*Usage, error) {
+	dir, err := platform.DataDir()
+	if err != nil {
+		return nil, err
+	}
+	return &Usage{
+		Dir:    filepath.Join(dir, "telemetry"),
+		Client: &http.Client{Timeout: flushTimeout},
+		Getenv: os.Getenv,
+		Now:    time.Now,
+	}, nil
+}
+
+func (u *Usage) settingsPath() string { return filepath.Join(u.Dir, "settings.json") }
+func (u *Usage) queuePath() string    { return filepath.Join(u.Dir, "queue.jsonl") }
+
+// Settings returns the stored decision; reporting is disabled when none exists
+func (u *Usage) Settings() (UsageSettings, error) {
+	var s UsageSettings
+	data, err := os.ReadFile(u.settingsPath())
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("failed to read telemetry settings: %w", err)
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("failed to parse telemetry settings: %w", err)
+	}
+	return s, nil
+}
+
+// SetEnabled stores the opt-in decision. Turning reporting off also deletes
+// any queued events.
+func (u *Usage) SetEnabled(enabled bool, endpoint string) error {
+	if err := os.MkdirAll(u.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+	s := UsageSettings{Enabled: enabled, Endpoint: endpoint, UpdatedAt: u.Now().UTC()}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(u.settingsPath(), data, 0600); err != nil {
+		return fmt.Errorf("failed to write telemetry settings: %w", err)
+	}
+	if !enabled {
+		if err := os.Remove(u.queuePath()); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear telemetry queue: %w", err)
+		}
+	}
+	return nil
+}
+
+// DisabledByEnv reports whether DO_NOT_TRACK or ERST_TELEMETRY turn
+// reporting off regardless of the stored setting
+func (u *Usage) DisabledByEnv() bool {
+	if v := u.Getenv("DO_NOT_TRACK"); v != "" && v != "0" {
+		return true
+	}
+	switch strings.ToLower(u.Getenv("ERST_TELEMETRY")) {
+	case "0", "off", "false", "no":
+		return true
+	}
+	return false
+}
+
+// Enabled reports whether events are recorded
+func (u *Usage) Enabled() bool {
+	if u.DisabledByEnv() {
+		return false
+	}
+	s, err := u.Settings()
+	return err == nil && s.Enabled
+}
+
+// Endpoint returns where events are sent: ERST_TELEMETRY_ENDPOINT, then the
+// stored endpoint, then DefaultUsageEndpoint. It may be empty.
+func (u *Usage) Endpoint() string {
+	if e := u.Getenv("ERST_TELEMETRY_ENDPOINT"); e != "" {
+		return e
+	}
+	if s, err := u.Settings(); err == nil && s.Endpoint != "" {
+		return s.Endpoint
+	}
+	return DefaultUsageEndpoint
+}
+
+// NewEvent builds the event for one command run
+func (u *Usage) NewEvent(command, version string, duration time.Duration, runErr error) UsageEvent {
+	return UsageEvent{
+		Command:    command,
+		DurationMs: duration.Milliseconds(),
+		Outcome:    ClassifyError(runErr),
+		Version:    version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		Day:        u.Now().UTC().Format("2006-01-02"),
+	}
+}
+
+// Record queues ev when reporting is enabled, and sends the queue once it
+// reaches a full batch. Failures to send leave the queue for a later run.
+func (u *Usage) Record(ctx context.Context, ev UsageEvent) error {
+	if !u.Enabled() {
+		return nil
+	}
+	events, err := u.Queued()
+	if err != nil {
+		return err
+	}
+	events = append(events, ev)
+	if len(events) > maxQueued {
+		events = events[len(events)-maxQueued:]
+	}
+	if err := u.writeQueue(events); err != nil {
+		return err
+	}
+
+	if len(events) >= flushThreshold && u.Endpoint() != "" {
+		ctx, cancel := context.WithTimeout(ctx, flushTimeout)
+		defer cancel()
+		_, err := u.Flush(ctx)
+		return err
+	}
+	return nil
+}
+
+// Queued returns the events waiting to be sent
+func (u *Usage) Queued() ([]UsageEvent, error) {
+	f, err := os.Open(u.queuePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read telemetry queue: %w", err)
+	}
+	defer f.Close()
+
+	var events []UsageEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ev UsageEvent
+		// Skip lines damaged by an interrupted write
+		if json.Unmarshal(scanner.Bytes(), &ev) == nil {
+			events = append(events, ev)
+		}
+	}
+	return events, scanner.Err()
+}
+
+func (u *Usage) writeQueue(events []UsageEvent) error {
+	if err := os.MkdirAll(u.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create telemetry directory: %w", err)
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, ev := range events {
+		if err := enc.Encode(ev); err != nil {
+			return err
+		}
+	}
+	tmp := u.queuePath() + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write telemetry queue: %w", err)
+	}
+	return os.Rename(tmp, u.queuePath())
+}
+
+// Flush sends all queued events to the endpoint in one request and clears
+// the queue on success. It returns the number of events sent.
+func (u *Usage) Flush(ctx context.Context) (int, error) {
+	endpoint := u.Endpoint()
+	if endpoint == "" {
+		return 0, fmt.Errorf("no telemetry endpoint configured")
+	}
+	events, err := u.Queued()
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"events": events})
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("invalid telemetry endpoint: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send usage events: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, fmt.Errorf("telemetry endpoint returned %s", resp.Status)
+	}
+
+	if err := os.Remove(u.queuePath()); err != nil && !os.IsNotExist(err) {
+		return len(events), fmt.Errorf("failed to clear telemetry queue: %w", err)
+	}
+	return len(events), nil
+}
+
+// ClassifyError maps a command error to a coarse failure class. Error
+// messages are never reported because they can contain hashes and
+// addresses.
+func ClassifyError(err error) string {
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.Is(err, erstErrors.ErrTransactionNotFound):
+		return "transaction_not_found"
+	case errors.Is(err, erstErrors.ErrRPCConnectionFailed), errors.As(err, &netErr):
+		return "network"
+	case errors.Is(err, erstErrors.ErrSimulatorNotFound):
+		return "simulator_not_found"
+	case errors.Is(err, erstErrors.ErrSimulationFailed), errors.Is(err, erstErrors.ErrSimulationLogicError):
+		return "simulation_failed"
+	case errors.Is(err, erstErrors.ErrInvalidNetwork):
+		return "invalid_network"
+	case errors.Is(err, erstErrors.ErrMarshalFailed), errors.Is(err, erstErrors.ErrUnmarshalFailed):
+		return "decode"
+	}
+	return "other"
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	erstErrors "github.com/dotandev/hintents/internal/errors"
+)
+
+func newTestUsage(t *testing.T, env map[string]string) *Usage {
+	t.Helper()
+	return &Usage{
+		Dir:    t.TempDir(),
+		Client: http.DefaultClient,
+		Getenv: func(k string) string { return env[k] },
+		Now:    func() time.Time { return time.Date(2025, 6, 1, 13, 45, 0, 0, time.UTC) },
+	}
+}
+
+func TestUsageDefaultsOff(t *testing.T) {
+	u := newTestUsage(t, nil)
+	if u.Enabled() {
+		t.Fatal("usage reporting must default to off")
+	}
+	if err := u.Record(context.Background(), u.NewEvent("erst debug", "v1", time.Second, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := u.Queued(); len(events) != 0 {
+		t.Errorf("recorded %d events while disabled", len(events))
+	}
+}
+
+func TestUsageRecordAndOff(t *testing.T) {
+	u := newTestUsage(t, nil)
+	if err := u.SetEnabled(true, ""); err != nil {
+		t.Fatal(err)
+	}
+	ev := u.NewEvent("erst debug", "v1.2.0", 1500*time.Millisecond, erstErrors.WrapRPCConnectionFailed(fmt.Errorf("dial")))
+	if err := u.Record(context.Background(), ev); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := u.Queued()
+	if err != nil || len(events) != 1 {
+		t.Fatalf("queued = %v, %v", events, err)
+	}
+	got := events[0]
+	if got.Command != "erst debug" || got.DurationMs != 1500 || got.Outcome != "network" || got.Day != "2025-06-01" {
+		t.Errorf("unexpected event: %+v", got)
+	}
+
+	if err := u.SetEnabled(false, ""); err != nil {
+		t.Fatal(err)
+	}
+	if events, _ := u.Queued(); len(events) != 0 {
+		t.Error("turning reporting off must delete the queue")
+	}
+}
+
+func TestUsageDoNotTrack(t *testing.T) {
+	u := newTestUsage(t, map[string]string{"DO_NOT_TRACK": "1"})
+	if err := u.SetEnabled(true, ""); err != nil {
+		t.Fatal(err)
+	}
+	if u.Enabled() {
+		t.Error("DO_NOT_TRACK must override the stored setting")
+	}
+}
+
+func TestUsageFlush(t *testing.T) {
+	var received []UsageEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Events []UsageEvent `json:"events"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received = append(received, body.Events...)
+	}))
+	defer srv.Close()
+
+	u := newTestUsage(t, nil)
+	if err := u.SetEnabled(true, srv.URL); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < flushThreshold; i++ {
+		if err := u.Record(context.Background(), u.NewEvent("erst fees history", "v1", time.Millisecond, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(received) != flushThreshold {
+		t.Fatalf("sent %d events, want %d", len(received), flushThreshold)
+	}
+	if events, _ := u.Queued(); len(events) != 0 {
+		t.Errorf("%d events left after a successful flush", len(events))
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := map[string]error{
+		"success":               nil,
+		"canceled":              fmt.Errorf("wrap: %w", context.Canceled),
+		"timeout":               context.DeadlineExceeded,
+		"transaction_not_found": erstErrors.WrapTransactionNotFound(fmt.Errorf("404")),
+		"simulator_not_found":   erstErrors.WrapSimulatorNotFound("missing"),
+		"other":                 fmt.Errorf("tx abc123 failed for GABC"),
+	}
+	for want, err := range tests {
+		if got := ClassifyError(err); got != want {
+			t.Errorf("ClassifyError(%v) = %q, want %q", err, got, want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// JiraConfig holds the site and credentials for Jira
+type JiraConfig struct {
+	// URL is the site, such as https://example.atlassian.net
+	URL      string
+	Email    string
+	APIToken string
+	Project  string
+	// IssueType defaults to Bug
+	IssueType string
+}
+
+// Jira creates issues with the Jira REST API
+type Jira struct {
+	config JiraConfig
+	client *http.Client
+}
+
+// NewJira returns a Jira provider for config
+func NewJira(config JiraConfig) (*Jira, error) {
+	config.URL = strings.TrimRight(config.URL, "/")
+	switch {
+	case config.URL == "":
+		return nil, fmt.Errorf("jira: the site URL is not configured")
+	case config.Email == "" || config.APIToken == "":
+		return nil, fmt.Errorf("jira: an email and API token are required")
+	case config.Project == "":
+		return nil, fmt.Errorf("jira: the project key is not configured")
+	}
+	if config.IssueType == "" {
+		config.IssueType = "Bug"
+	}
+	return &Jira{config: config, client: &http.Client{Timeout: defaultTimeout}}, nil
+}
+
+// Name returns "jira"
+func (j *Jira) Name() string { return "jira" }
+
+// Create creates issue and uploads the attachments to it
+func (j *Jira) Create(ctx context.Context, issue Issue, attachments []Attachment) (*Created, error) {
+	fields := map[string]any{
+		"project":     map[string]string{"key": j.config.Project},
+		"summary":     issue.Title,
+		"description": jiraMarkup(issue),
+		"issuetype":   map[string]string{"name": j.config.IssueType},
+	}
+	if len(issue.Labels) > 0 {
+		fields["labels"] = issue.Labels
+	}
+	body, err := json.Marshal(map[string]any{"fields": fields})
+	if err != nil {
+		return nil, fmt.Errorf("jira: failed to encode issue: %w", err)
+	}
+
+	req, err := j.newRequest(ctx, "/rest/api/2/issue", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var out struct {
+		Key string `json:"key"`
+	}
+	if err := j.do(req, &out); err != nil {
+		return nil, err
+	}
+	created := &Created{Key: out.Key, URL: j.config.URL + "/browse/" + out.Key}
+
+	for _, a := range attachments {
+		if err := j.attach(ctx, out.Key, a); err != nil {
+			return created, fmt.Errorf("issue %s created, but attaching %s failed: %w", out.Key, a.Name, err)
+		}
+		created.Attached = append(created.Attached, a.Name)
+	}
+	return created, nil
+}
+
+func (j *Jira) attach(ctx context.Context, key string, a Attachment) error {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, a.Name))
+	header.Set("Content-Type", a.ContentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(a.Data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	req, err := j.newRequest(ctx, "/rest/api/2/issue/"+key+"/attachments", &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	// Jira rejects uploads without it as cross-site requests
+	req.Header.Set("X-Atlassian-Token", "no-check")
+	return j.do(req, nil)
+}
+
+func (j *Jira) newRequest(ctx context.Context, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.config.URL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("jira: %w", err)
+	}
+	req.SetBasicAuth(j.config.Email, j.config.APIToken)
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+func (j *Jira) do(req *http.Request, out any) error {
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse("jira", resp); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("jira: invalid response: %w", err)
+	}
+	return nil
+}
+
+// jiraMarkup renders the sections of issue in Jira wiki markup
+func jiraMarkup(issue Issue) string {
+	var b strings.Builder
+	for _, s := range issue.Sections {
+		if s.Heading != "" {
+			fmt.Fprintf(&b, "h3. %s\n\n", s.Heading)
+		}
+		writeBody(&b, s, "* ")
+	}
+	return strings.TrimSpace(b.String())
+}
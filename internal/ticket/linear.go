@@ -0,0 +1,188 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// DefaultLinearEndpoint is the Linear GraphQL API
+const DefaultLinearEndpoint = "https://api.linear.app/graphql"
+
+// LinearConfig holds the team and credentials for Linear
+type LinearConfig struct {
+	APIKey string
+	TeamID string
+	// LabelIDs are applied to every issue; Linear labels are set by ID
+	LabelIDs []string
+	// Endpoint defaults to DefaultLinearEndpoint
+	Endpoint string
+}
+
+// Linear creates issues with the Linear GraphQL API. Attachments are
+// uploaded to Linear's file storage and linked from the description.
+type Linear struct {
+	config LinearConfig
+	client *http.Client
+}
+
+// NewLinear returns a Linear provider for config
+func NewLinear(config LinearConfig) (*Linear, error) {
+	switch {
+	case config.APIKey == "":
+		return nil, fmt.Errorf("linear: an API key is required")
+	case config.TeamID == "":
+		return nil, fmt.Errorf("linear: the team ID is not configured")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = DefaultLinearEndpoint
+	}
+	return &Linear{config: config, client: &http.Client{Timeout: defaultTimeout}}, nil
+}
+
+// Name returns "linear"
+func (l *Linear) Name() string { return "linear" }
+
+const linearUploadMutation = `mutation($contentType: String!, $filename: String!, $size: Int!) {
+  fileUpload(contentType: $contentType, filename: $filename, size: $size) {
+    success
+    uploadFile { uploadUrl assetUrl headers { key value } }
+  }
+}`
+
+const linearCreateMutation = `mutation($input: IssueCreateInput!) {
+  issueCreate(input: $input) {
+    success
+    issue { identifier url }
+  }
+}`
+
+// Create uploads the attachments and creates issue linking to them
+func (l *Linear) Create(ctx context.Context, issue Issue, attachments []Attachment) (*Created, error) {
+	var links []string
+	var attached []string
+	for _, a := range attachments {
+		url, err := l.upload(ctx, a)
+		if err != nil {
+			return nil, fmt.Errorf("linear: failed to upload %s: %w", a.Name, err)
+		}
+		links = append(links, fmt.Sprintf("[%s](%s)", a.Name, url))
+		attached = append(attached, a.Name)
+	}
+	if len(links) > 0 {
+		issue.Sections = append(issue.Sections, Section{Heading: "Attachments", Bullets: links})
+	}
+
+	input := map[string]any{
+		"teamId":      l.config.TeamID,
+		"title":       issue.Title,
+		"description": issue.Markdown(),
+	}
+	if len(l.config.LabelIDs) > 0 {
+		input["labelIds"] = l.config.LabelIDs
+	}
+	var out struct {
+		IssueCreate struct {
+			Success bool `json:"success"`
+			Issue   struct {
+				Identifier string `json:"identifier"`
+				URL        string `json:"url"`
+			} `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	if err := l.query(ctx, linearCreateMutation, map[string]any{"input": input}, &out); err != nil {
+		return nil, err
+	}
+	if !out.IssueCreate.Success {
+		return nil, fmt.Errorf("linear: the issue was not created")
+	}
+	return &Created{Key: out.IssueCreate.Issue.Identifier, URL: out.IssueCreate.Issue.URL, Attached: attached}, nil
+}
+
+// upload stores a in Linear and returns its asset URL
+func (l *Linear) upload(ctx context.Context, a Attachment) (string, error) {
+	var out struct {
+		FileUpload struct {
+			Success    bool `json:"success"`
+			UploadFile struct {
+				UploadURL string `json:"uploadUrl"`
+				AssetURL  string `json:"assetUrl"`
+				Headers   []struct {
+					Key   string `json:"key"`
+					Value string `json:"value"`
+				} `json:"headers"`
+			} `json:"uploadFile"`
+		} `json:"fileUpload"`
+	}
+	vars := map[string]any{"contentType": a.ContentType, "filename": a.Name, "size": len(a.Data)}
+	if err := l.query(ctx, linearUploadMutation, vars, &out); err != nil {
+		return "", err
+	}
+	file := out.FileUpload.UploadFile
+	if !out.FileUpload.Success || file.UploadURL == "" {
+		return "", fmt.Errorf("no upload URL returned")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, file.UploadURL, bytes.NewReader(a.Data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", a.ContentType)
+	req.Header.Set("Cache-Control", "public, max-age=31536000")
+	for _, h := range file.Headers {
+		req.Header.Set(h.Key, h.Value)
+	}
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse("linear upload", resp); err != nil {
+		return "", err
+	}
+	return file.AssetURL, nil
+}
+
+// query runs a GraphQL request and decodes its data into out
+func (l *Linear) query(ctx context.Context, query string, vars map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return fmt.Errorf("linear: failed to encode request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("linear: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", l.config.APIKey)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("linear: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse("linear", resp); err != nil {
+		return err
+	}
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("linear: invalid response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("linear: %s", result.Errors[0].Message)
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("linear: invalid response: %w", err)
+	}
+	return nil
+}
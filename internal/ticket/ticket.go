@@ -0,0 +1,99 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package ticket files issues about failed transactions in issue trackers
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultTimeout bounds each request to a tracker
+const defaultTimeout = 30 * time.Second
+
+// maxErrorBody caps how much of an error response is read
+const maxErrorBody = 4 << 10
+
+// Section is a headed part of an issue description. Each provider renders
+// it in its own markup.
+type Section struct {
+	Heading    string
+	Paragraphs []string
+	Bullets    []string
+}
+
+// Issue is the issue to create
+type Issue struct {
+	Title    string
+	Sections []Section
+	Labels   []string
+}
+
+// Attachment is a file attached to an issue
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Created is an issue a provider created
+type Created struct {
+	Key string `json:"key"`
+	URL string `json:"url"`
+	// Attached lists the attachments uploaded with the issue
+	Attached []string `json:"attached,omitempty"`
+}
+
+// Provider creates issues in a tracker
+type Provider interface {
+	Name() string
+	Create(ctx context.Context, issue Issue, attachments []Attachment) (*Created, error)
+}
+
+// Providers lists the supported provider names
+var Providers = []string{"jira", "linear"}
+
+// Markdown renders the sections of issue as Markdown
+func (issue Issue) Markdown() string {
+	var b strings.Builder
+	for _, s := range issue.Sections {
+		if s.Heading != "" {
+			fmt.Fprintf(&b, "### %s\n\n", s.Heading)
+		}
+		writeBody(&b, s, "- ")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// writeBody writes the paragraphs and bullets of s
+func writeBody(b *strings.Builder, s Section, bullet string) {
+	for _, p := range s.Paragraphs {
+		b.WriteString(p)
+		b.WriteString("\n\n")
+	}
+	for _, item := range s.Bullets {
+		b.WriteString(bullet)
+		b.WriteString(item)
+		b.WriteString("\n")
+	}
+	if len(s.Bullets) > 0 {
+		b.WriteString("\n")
+	}
+}
+
+// checkResponse returns an error describing resp unless it succeeded
+func checkResponse(provider string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if msg := strings.TrimSpace(string(body)); msg != "" {
+		return fmt.Errorf("%s: %s: %s", provider, resp.Status, msg)
+	}
+	return fmt.Errorf("%s: %s", provider, resp.Status)
+}
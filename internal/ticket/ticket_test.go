@@ -0,0 +1,160 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testIssue = Issue{
+	Title: "Transaction abc failed",
+	Sections: []Section{
+		{Heading: "Summary", Bullets: []string{"Network: testnet"}},
+		{Heading: "Diagnosis", Paragraphs: []string{"The contract panicked."}},
+	},
+	Labels: []string{"erst"},
+}
+
+func TestMarkup(t *testing.T) {
+	want := "### Summary\n\n- Network: testnet\n\n### Diagnosis\n\nThe contract panicked."
+	if got := testIssue.Markdown(); got != want {
+		t.Errorf("unexpected Markdown:\n%s", got)
+	}
+	want = "h3. Summary\n\n* Network: testnet\n\nh3. Diagnosis\n\nThe contract panicked."
+	if got := jiraMarkup(testIssue); got != want {
+		t.Errorf("unexpected Jira markup:\n%s", got)
+	}
+}
+
+func TestJiraCreate(t *testing.T) {
+	var fields map[string]any
+	var attached []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "me@example.com" || pass != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/rest/api/2/issue":
+			var body struct {
+				Fields map[string]any `json:"fields"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("invalid issue body: %v", err)
+			}
+			fields = body.Fields
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"10001","key":"OPS-7"}`))
+		case "/rest/api/2/issue/OPS-7/attachments":
+			if r.Header.Get("X-Atlassian-Token") != "no-check" {
+				t.Error("missing X-Atlassian-Token header")
+			}
+			f, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("missing file: %v", err)
+			}
+			data, _ := io.ReadAll(f)
+			attached = append(attached, header.Filename+":"+string(data))
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	j, err := NewJira(JiraConfig{URL: srv.URL + "/", Email: "me@example.com", APIToken: "token", Project: "OPS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := j.Create(context.Background(), testIssue, []Attachment{{Name: "report.html", ContentType: "text/html", Data: []byte("<html>")}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Key != "OPS-7" || created.URL != srv.URL+"/browse/OPS-7" {
+		t.Errorf("unexpected issue %+v", created)
+	}
+	if fields["summary"] != testIssue.Title || fields["issuetype"].(map[string]any)["name"] != "Bug" {
+		t.Errorf("unexpected fields %v", fields)
+	}
+	if len(attached) != 1 || attached[0] != "report.html:<html>" {
+		t.Errorf("unexpected attachments %v", attached)
+	}
+
+	j.config.APIToken = "wrong"
+	if _, err := j.Create(context.Background(), testIssue, nil); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected an authorization error, got %v", err)
+	}
+}
+
+func TestLinearCreate(t *testing.T) {
+	var uploaded, description string
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/upload" {
+			if r.Header.Get("X-Signed") != "yes" {
+				t.Error("missing upload header")
+			}
+			data, _ := io.ReadAll(r.Body)
+			uploaded = string(data)
+			return
+		}
+		if r.Header.Get("Authorization") != "lin_api_key" {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Authentication required"}]}`))
+			return
+		}
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("invalid request: %v", err)
+		}
+		if strings.Contains(req.Query, "fileUpload") {
+			_, _ = w.Write([]byte(`{"data":{"fileUpload":{"success":true,"uploadFile":{"uploadUrl":"` + srv.URL + `/upload","assetUrl":"https://uploads.linear.app/a/snap.json","headers":[{"key":"X-Signed","value":"yes"}]}}}}`))
+			return
+		}
+		input := req.Variables["input"].(map[string]any)
+		description = input["description"].(string)
+		_, _ = w.Write([]byte(`{"data":{"issueCreate":{"success":true,"issue":{"identifier":"ENG-12","url":"https://linear.app/acme/issue/ENG-12"}}}}`))
+	}))
+	defer srv.Close()
+
+	l, err := NewLinear(LinearConfig{APIKey: "lin_api_key", TeamID: "team", Endpoint: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := l.Create(context.Background(), testIssue, []Attachment{{Name: "snap.json", ContentType: "application/json", Data: []byte("{}")}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Key != "ENG-12" || len(created.Attached) != 1 {
+		t.Errorf("unexpected issue %+v", created)
+	}
+	if uploaded != "{}" {
+		t.Errorf("unexpected upload %q", uploaded)
+	}
+	if !strings.Contains(description, "- [snap.json](https://uploads.linear.app/a/snap.json)") {
+		t.Errorf("the description should link the attachment:\n%s", description)
+	}
+
+	l.config.APIKey = "wrong"
+	if _, err := l.Create(context.Background(), testIssue, nil); err == nil || !strings.Contains(err.Error(), "Authentication required") {
+		t.Errorf("expected the GraphQL error, got %v", err)
+	}
+}
+
+func TestNewProviderValidation(t *testing.T) {
+	if _, err := NewJira(JiraConfig{URL: "https://x.atlassian.net", Email: "a", APIToken: "b"}); err == nil {
+		t.Error("expected a missing project to be rejected")
+	}
+	if _, err := NewLinear(LinearConfig{TeamID: "t"}); err == nil {
+		t.Error("expected a missing API key to be rejected")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenflow
+
+import (
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// TokenMeta is the metadata exposed by the Soroban token interface
+type TokenMeta struct {
+	Decimals uint32 `json:"decimals"`
+	Symbol   string `json:"symbol,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Caller invokes a read-only contract function with no arguments
+type Caller func(contractID, function string) (xdr.ScVal, error)
+
+// MetadataResolver looks up token metadata by calling the token's
+// decimals, symbol and name functions, caching the result per contract.
+// Contracts that do not implement the interface are cached as misses.
+type MetadataResolver struct {
+	call  Caller
+	cache map[string]*TokenMeta
+}
+
+// NewMetadataResolver creates a resolver seeded with previously resolved
+// metadata, e.g. from a session
+func NewMetadataResolver(call Caller, cached map[string]TokenMeta) *MetadataResolver {
+	r := &MetadataResolver{call: call, cache: make(map[string]*TokenMeta)}
+	for id, meta := range cached {
+		r.cache[id] = &meta
+	}
+	return r
+}
+
+// Lookup returns the metadata of a token contract. decimals is required;
+// symbol and name are optional.
+func (r *MetadataResolver) Lookup(contractID string) (TokenMeta, bool) {
+	if meta, ok := r.cache[contractID]; ok {
+		if meta == nil {
+			return TokenMeta{}, false
+		}
+		return *meta, true
+	}
+
+	r.cache[contractID] = nil
+	if r.call == nil {
+		return TokenMeta{}, false
+	}
+
+	val, err := r.call(contractID, "decimals")
+	if err != nil || val.Type != xdr.ScValTypeScvU32 || val.U32 == nil {
+		return TokenMeta{}, false
+	}
+	meta := &TokenMeta{Decimals: uint32(*val.U32)}
+	if val, err := r.call(contractID, "symbol"); err == nil {
+		meta.Symbol = scValString(val)
+	}
+	if val, err := r.call(contractID, "name"); err == nil {
+		meta.Name = scValString(val)
+	}
+
+	r.cache[contractID] = meta
+	return *meta, true
+}
+
+// Resolved returns every successfully resolved token, for persisting
+func (r *MetadataResolver) Resolved() map[string]TokenMeta {
+	out := make(map[string]TokenMeta)
+	for id, meta := range r.cache {
+		if meta != nil {
+			out[id] = *meta
+		}
+	}
+	return out
+}
+
+// ApplyMetadata resolves every contract token in the report and updates its
+// symbol and decimals so amounts render in whole units
+func (r *Report) ApplyMetadata(m *MetadataResolver) {
+	apply := func(ts []Transfer) {
+		for i := range ts {
+			t := &ts[i].Token
+			if t.ID == "" {
+				continue
+			}
+			meta, ok := m.Lookup(t.ID)
+			if !ok {
+				continue
+			}
+			if meta.Symbol != "" {
+				t.Symbol = meta.Symbol
+			}
+			t.Decimals = meta.Decimals
+		}
+	}
+
+	apply(r.Raw)
+	apply(r.Agg)
+	for i := range r.ByOp {
+		apply(r.ByOp[i].Agg)
+	}
+}
+
+func scValString(v xdr.ScVal) string {
+	switch v.Type {
+	case xdr.ScValTypeScvString:
+		if v.Str != nil {
+			return string(*v.Str)
+		}
+	case xdr.ScValTypeScvSymbol:
+		if v.Sym != nil {
+			return string(*v.Sym)
+		}
+	}
+	return ""
+}
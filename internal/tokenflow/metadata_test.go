@@ -0,0 +1,52 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenflow
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetadataResolver_AppliesDecimalsAndCaches(t *testing.T) {
+	calls := map[string]int{}
+	call := func(contractID, function string) (xdr.ScVal, error) {
+		calls[contractID+"."+function]++
+		if contractID != "CTOKEN" {
+			return xdr.ScVal{}, errors.New("not a token")
+		}
+		switch function {
+		case "decimals":
+			d := xdr.Uint32(6)
+			return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &d}, nil
+		case "symbol":
+			s := xdr.ScString("USDC")
+			return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &s}, nil
+		}
+		return xdr.ScVal{}, errors.New("unsupported")
+	}
+
+	r := &Report{Agg: []Transfer{
+		{From: "A", To: "B", Token: Token{Symbol: "SAC", ID: "CTOKEN"}, Amount: big.NewInt(1_500_000), Kind: KindTransfer},
+		{From: "A", To: "B", Token: Token{Symbol: "SAC", ID: "COTHER"}, Amount: big.NewInt(42), Kind: KindTransfer},
+	}}
+	resolver := NewMetadataResolver(call, nil)
+	r.ApplyMetadata(resolver)
+	r.ApplyMetadata(resolver)
+
+	require.Equal(t, "1.5", r.Agg[0].FormattedAmount())
+	require.Equal(t, "USDC", r.Agg[0].Token.Symbol)
+	require.Equal(t, "42", r.Agg[1].FormattedAmount(), "unknown tokens keep raw amounts")
+	require.Equal(t, 1, calls["CTOKEN.decimals"])
+	require.Equal(t, 1, calls["COTHER.decimals"], "misses are cached too")
+	require.Equal(t, map[string]TokenMeta{"CTOKEN": {Decimals: 6, Symbol: "USDC"}}, resolver.Resolved())
+
+	seeded := NewMetadataResolver(nil, resolver.Resolved())
+	meta, ok := seeded.Lookup("CTOKEN")
+	require.True(t, ok)
+	require.Equal(t, uint32(6), meta.Decimals)
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenflow
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/require"
+)
+
+func muxedAccount(pk [32]byte, id uint64) xdr.MuxedAccount {
+	return xdr.MuxedAccount{
+		Type:     xdr.CryptoKeyTypeKeyTypeMuxedEd25519,
+		Med25519: &xdr.MuxedAccountMed25519{Id: xdr.Uint64(id), Ed25519: xdr.Uint256(pk)},
+	}
+}
+
+// encodeEnvelopeWithPayments builds an envelope paying each destination
+// 100 stroops of XLM
+func encodeEnvelopeWithPayments(t *testing.T, src [32]byte, dsts ...xdr.MuxedAccount) string {
+	t.Helper()
+	var ops []xdr.Operation
+	for _, dst := range dsts {
+		ops = append(ops, xdr.Operation{Body: xdr.OperationBody{
+			Type:      xdr.OperationTypePayment,
+			PaymentOp: &xdr.PaymentOp{Destination: dst, Asset: xdr.Asset{Type: xdr.AssetTypeAssetTypeNative}, Amount: 100},
+		}})
+	}
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: muxedAccount(src, 9),
+			Fee:           100,
+			SeqNum:        1,
+			Operations:    ops,
+		}},
+	}
+	b, err := env.MarshalBinary()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestBuildReport_AttributesPaymentsToMuxedIDs(t *testing.T) {
+	src, exchange := bytes32(0x10), bytes32(0x20)
+	envB64 := encodeEnvelopeWithPayments(t, src,
+		muxedAccount(exchange, 1001),
+		muxedAccount(exchange, 1002),
+		muxedAccount(exchange, 1001),
+	)
+
+	r, err := BuildReport(envB64, "")
+	require.NoError(t, err)
+	require.Len(t, r.Raw, 3)
+	require.Len(t, r.Agg, 2, "deposits to different muxed IDs stay separate")
+
+	byTo := map[string]*big.Int{}
+	for _, tr := range r.Agg {
+		m, ok := decoder.ParseMuxedAddress(tr.To)
+		require.True(t, ok)
+		require.Equal(t, addrMuxed(exchange), m.Account)
+		byTo[tr.To] = tr.Amount
+		from, ok := decoder.ParseMuxedAddress(tr.From)
+		require.True(t, ok)
+		require.Equal(t, uint64(9), from.ID)
+	}
+	first, err := decoder.MuxAddress(addrMuxed(exchange), 1001)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(200), byTo[first])
+
+	require.Contains(t, strings.Join(r.SummaryLines(), "\n"), first+" ("+addrMuxed(exchange)+" id 1001)")
+}
+
+func TestBuildReport_SACTransferToMuxedID(t *testing.T) {
+	cid := xdr.ContractId(bytes32(0xAA))
+	from := scAddressAccount(bytes32(0x01))
+	to := scAddressAccount(bytes32(0x02))
+
+	data := xdr.ScMap{
+		{Key: scSymbol("amount"), Val: scU128(75)},
+		{Key: scSymbol("to_muxed_id"), Val: scU64(42)},
+	}
+	pm := &data
+	event := diagnosticEvent(cid,
+		[]xdr.ScVal{scSymbol("transfer"), scAddress(from), scAddress(to)},
+		xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &pm},
+		true,
+	)
+
+	r, err := BuildReport("", encodeResultMetaWithDiagnosticEvents(t, []xdr.DiagnosticEvent{event}))
+	require.NoError(t, err)
+	require.Len(t, r.Raw, 1)
+	require.Equal(t, big.NewInt(75), r.Raw[0].Amount)
+
+	m, ok := decoder.ParseMuxedAddress(r.Raw[0].To)
+	require.True(t, ok)
+	require.Equal(t, addrString(to), m.Account)
+	require.Equal(t, uint64(42), m.ID)
+}
+
+func TestVerifyBalances_MuxedHoldersReconcileWithAccounts(t *testing.T) {
+	alice, bob := bytes32(0x01), bytes32(0x02)
+	meta := encodeResultMetaWithOperationChanges(t, xdr.LedgerEntryChanges{
+		stateChange(accountEntry(alice, 1000)),
+		updatedChange(accountEntry(alice, 800)),
+		stateChange(accountEntry(bob, 0)),
+		updatedChange(accountEntry(bob, 200)),
+	})
+	toBob1, err := decoder.MuxAddress(addrMuxed(bob), 1)
+	require.NoError(t, err)
+	toBob2, err := decoder.MuxAddress(addrMuxed(bob), 2)
+	require.NoError(t, err)
+
+	report := &Report{Raw: []Transfer{
+		{From: addrMuxed(alice), To: toBob1, Token: Token{Symbol: "XLM"}, Amount: big.NewInt(150), Kind: KindTransfer},
+		{From: addrMuxed(alice), To: toBob2, Token: Token{Symbol: "XLM"}, Amount: big.NewInt(50), Kind: KindTransfer},
+	}}
+	got, err := VerifyBalances(report, meta, "")
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestReport_WriteCSVSplitsMuxedAddresses(t *testing.T) {
+	to, err := decoder.MuxAddress(addrMuxed(bytes32(0x02)), 7)
+	require.NoError(t, err)
+	r := &Report{Raw: []Transfer{{From: "GA", To: to, Token: Token{Symbol: "XLM"}, Amount: big.NewInt(1), Kind: KindTransfer}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteCSV(&buf))
+	rows := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, rows, 2)
+	require.True(t, strings.HasSuffix(rows[1], ",GA,,"+addrMuxed(bytes32(0x02))+",7"), rows[1])
+}
@@ -4,23 +4,71 @@
 package tokenflow
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"math/big"
 	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/dotandev/hintents/internal/decoder"
 )
 
 // SummaryLines produces human-readable summaries like:
 //
 //	AccountA -> 50 XLM -> AccountB
+//
+// Movements are grouped under an "Operation #N" header when they span
+// several operations or were caused by a contract invocation.
 func (r *Report) SummaryLines() []string {
+	if !r.grouped() {
+		var lines []string
+		for _, t := range r.Agg {
+			lines = append(lines, summaryLine(t))
+		}
+		return lines
+	}
+
 	var lines []string
-	for _, t := range r.Agg {
-		lines = append(lines, fmt.Sprintf("%s -> %s %s -> %s", t.From, formatAmount(t), t.Token.Display(), t.To))
+	for _, g := range r.ByOp {
+		header := fmt.Sprintf("Operation #%d", g.OpIndex)
+		if g.Contract != "" {
+			header += " via " + g.Contract
+		}
+		lines = append(lines, header+":")
+		for _, t := range g.Agg {
+			lines = append(lines, "  "+summaryLine(t))
+		}
 	}
 	return lines
 }
 
+func (r *Report) grouped() bool {
+	return len(r.ByOp) > 1 || (len(r.ByOp) == 1 && r.ByOp[0].Contract != "")
+}
+
+func summaryLine(t Transfer) string {
+	return fmt.Sprintf("%s -> %s -> %s", decoder.FormatAccount(t.From), amountLabel(t), decoder.FormatAccount(t.To))
+}
+
+func amountLabel(t Transfer) string {
+	label := fmt.Sprintf("%s %s", formatAmount(t), t.Token.Display())
+	if t.USD != nil {
+		label += " (~" + FormatUSD(*t.USD) + ")"
+	}
+	return label
+}
+
+// FormatUSD renders an approximate dollar value
+func FormatUSD(v float64) string {
+	if v > 0 && v < 0.01 {
+		return "<$0.01"
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
+
 // MermaidFlowchart renders a Mermaid flowchart (text) that can be pasted into Markdown.
 func (r *Report) MermaidFlowchart() string {
 	var b strings.Builder
@@ -40,15 +88,19 @@ func (r *Report) MermaidFlowchart() string {
 	}
 
 	for _, t := range r.Agg {
-		from := getNode(t.From)
-		to := getNode(t.To)
-		label := fmt.Sprintf("%s %s", formatAmount(t), t.Token.Display())
-		b.WriteString(fmt.Sprintf("  %s -->|\"%s\"| %s\n", from, escapeMermaidLabel(label), to))
+		from := getNode(decoder.FormatAccount(t.From))
+		to := getNode(decoder.FormatAccount(t.To))
+		b.WriteString(fmt.Sprintf("  %s -->|\"%s\"| %s\n", from, escapeMermaidLabel(amountLabel(t)), to))
 	}
 
 	return b.String()
 }
 
+// FormattedAmount returns the transfer amount as displayed in summaries
+func (t Transfer) FormattedAmount() string {
+	return formatAmount(t)
+}
+
 func formatAmount(t Transfer) string {
 	if t.Amount == nil {
 		return "0"
@@ -56,22 +108,31 @@ func formatAmount(t Transfer) string {
 	if t.Token.Symbol == "XLM" && t.Token.ID == "" {
 		return formatStroopsAsXLM(t.Amount)
 	}
-	// For SAC tokens we don't know decimals here; show raw integer.
+	if t.Token.Decimals > 0 {
+		return formatScaled(t.Amount, t.Token.Decimals)
+	}
+	// Decimals unknown; show raw integer.
 	return t.Amount.String()
 }
 
 func formatStroopsAsXLM(stroops *big.Int) string {
-	if stroops == nil {
+	return formatScaled(stroops, 7)
+}
+
+// formatScaled renders an integer amount with the given number of decimals,
+// trimming trailing zeros
+func formatScaled(amount *big.Int, decimals uint32) string {
+	if amount == nil {
 		return "0"
 	}
-	neg := stroops.Sign() < 0
-	n := new(big.Int).Abs(stroops)
+	neg := amount.Sign() < 0
+	n := new(big.Int).Abs(amount)
 
-	scale := big.NewInt(10_000_000) // 7 decimals
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
 	intPart, frac := new(big.Int), new(big.Int)
 	intPart.DivMod(n, scale, frac)
 
-	fracStr := fmt.Sprintf("%07s", frac.String())
+	fracStr := fmt.Sprintf("%0*s", int(decimals), frac.String())
 	fracStr = strings.TrimRight(fracStr, "0")
 	if fracStr == "" {
 		if neg {
@@ -93,3 +154,118 @@ func escapeMermaidLabel(s string) string {
 		return "\\" + m
 	})
 }
+
+// WriteCSV writes every movement, one row each, for spreadsheets and
+// accounting tools. amount is scaled by the token decimals when known and
+// raw_amount is always the integer amount in the smallest unit. Muxed
+// addresses are also split into their account and muxed ID columns.
+func (r *Report) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"op_index", "kind", "from", "to", "token", "token_id", "amount", "raw_amount", "contract", "usd",
+		"from_account", "from_muxed_id", "to_account", "to_muxed_id"}); err != nil {
+		return err
+	}
+	for _, t := range r.Raw {
+		raw := "0"
+		if t.Amount != nil {
+			raw = t.Amount.String()
+		}
+		usd := ""
+		if t.USD != nil {
+			usd = strconv.FormatFloat(*t.USD, 'f', 2, 64)
+		}
+		symbol := t.Token.Symbol
+		if symbol == "" {
+			symbol = t.Token.Display()
+		}
+		fromAccount, fromID := splitMuxed(t.From)
+		toAccount, toID := splitMuxed(t.To)
+		if err := cw.Write([]string{
+			strconv.Itoa(t.OpIndex), string(t.Kind), t.From, t.To, symbol, t.Token.ID,
+			formatAmount(t), raw, t.Contract, usd,
+			fromAccount, fromID, toAccount, toID,
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// splitMuxed returns the account and ID columns of an address; the ID is
+// empty unless the address is muxed
+func splitMuxed(addr string) (string, string) {
+	if m, ok := decoder.ParseMuxedAddress(addr); ok {
+		return m.Account, strconv.FormatUint(m.ID, 10)
+	}
+	return addr, ""
+}
+
+// transferJSON is one movement in WriteJSON output, with the fields of a
+// WriteCSV row
+type transferJSON struct {
+	OpIndex   int      `json:"op_index"`
+	Kind      Kind     `json:"kind"`
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Token     string   `json:"token"`
+	TokenID   string   `json:"token_id,omitempty"`
+	Amount    string   `json:"amount"`
+	RawAmount string   `json:"raw_amount"`
+	Contract  string   `json:"contract,omitempty"`
+	USD       *float64 `json:"usd,omitempty"`
+}
+
+func newTransferJSON(t Transfer) transferJSON {
+	raw := "0"
+	if t.Amount != nil {
+		raw = t.Amount.String()
+	}
+	symbol := t.Token.Symbol
+	if symbol == "" {
+		symbol = t.Token.Display()
+	}
+	return transferJSON{
+		OpIndex: t.OpIndex, Kind: t.Kind, From: t.From, To: t.To,
+		Token: symbol, TokenID: t.Token.ID,
+		Amount: formatAmount(t), RawAmount: raw,
+		Contract: t.Contract, USD: t.USD,
+	}
+}
+
+// unmodeledJSON is an operation whose balance changes the flow omits
+type unmodeledJSON struct {
+	OpIndex int    `json:"op_index"`
+	Type    string `json:"type"`
+}
+
+// WriteJSON writes the JSON document of r, indented
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.JSON())
+}
+
+// JSON returns every movement, the aggregated totals and, when any movement
+// is priced, their approximate USD value as one value to encode as JSON
+func (r *Report) JSON() any {
+	doc := struct {
+		Transfers []transferJSON  `json:"transfers"`
+		Totals    []transferJSON  `json:"totals"`
+		TotalUSD  *float64        `json:"total_usd,omitempty"`
+		Unmodeled []unmodeledJSON `json:"unmodeled,omitempty"`
+	}{Transfers: []transferJSON{}, Totals: []transferJSON{}}
+	for _, op := range r.Unmodeled {
+		doc.Unmodeled = append(doc.Unmodeled, unmodeledJSON{OpIndex: op.OpIndex, Type: op.Type})
+	}
+	for _, t := range r.Raw {
+		doc.Transfers = append(doc.Transfers, newTransferJSON(t))
+	}
+	for _, t := range r.Agg {
+		doc.Totals = append(doc.Totals, newTransferJSON(t))
+	}
+	if total, ok := r.TotalUSD(); ok {
+		doc.TotalUSD = &total
+	}
+	return doc
+}
@@ -10,6 +10,8 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/txmeta"
 	"github.com/stellar/go-stellar-sdk/strkey"
 	"github.com/stellar/go-stellar-sdk/xdr"
 )
@@ -28,6 +30,9 @@ const (
 type Token struct {
 	Symbol string
 	ID     string
+	// Decimals is set once token metadata has been resolved; 0 renders
+	// amounts as raw integers
+	Decimals uint32
 }
 
 func (t Token) Display() string {
@@ -57,43 +62,173 @@ type Transfer struct {
 	Token  Token
 	Amount *big.Int // integer smallest units (XLM: stroops)
 	Kind   Kind
+	// OpIndex is the index of the operation that caused the movement
+	OpIndex int
+	// Contract is the contract that invoked the token, or "" for classic payments
+	Contract string
+	// USD is the approximate value of the movement, or nil when unpriced
+	USD *float64
+}
+
+// OpFlow groups the aggregated movements caused by one operation through
+// one invoking contract.
+type OpFlow struct {
+	OpIndex  int
+	Contract string
+	Agg      []Transfer
 }
 
 // Report is the aggregated “money flow” view.
 type Report struct {
-	Raw []Transfer
-	Agg []Transfer
+	Raw  []Transfer
+	Agg  []Transfer
+	ByOp []OpFlow
+	// Unmodeled are operations that move balances in ways the flow does not
+	// model, such as path payments and offers without per-operation events.
+	// VerifyBalances does not check their ledger changes.
+	Unmodeled []UnmodeledOp
+}
+
+// UnmodeledOp is an operation whose balance changes are missing from the
+// flow
+type UnmodeledOp struct {
+	OpIndex int
+	Type    string
 }
 
 // BuildReport extracts transfers/mints from:
-// - native XLM payments in EnvelopeXdr
-// - Soroban SAC transfer/mint events from ResultMetaXdr diagnostic events
+//   - native XLM payments in EnvelopeXdr
+//   - SAC transfer/mint events in ResultMetaXdr: the diagnostic events when
+//     they carry contract events, else the per-operation events, which V4
+//     meta records for classic operations too
+//
+// Each movement is attributed to the operation that caused it. Movements
+// found in diagnostic events are also attributed to the contract that
+// invoked the token, found by replaying fn_call/fn_return events.
 func BuildReport(envelopeXdrB64, resultMetaXdrB64 string) (*Report, error) {
-	var raw []Transfer
+	var (
+		raw []Transfer
+		tx  *xdr.Transaction
+		err error
+	)
+	inv := invocation{}
 
 	if envelopeXdrB64 != "" {
-		xlm, err := extractNativeXLMPayments(envelopeXdrB64)
-		if err != nil {
+		if tx, err = decodeTransaction(envelopeXdrB64); err != nil {
 			return nil, err
 		}
-		raw = append(raw, xlm...)
+		if tx != nil {
+			inv = findInvocation(*tx)
+		}
 	}
 
+	// covered maps operations whose events were read to whether every
+	// event was modeled
+	var covered map[int]bool
 	if resultMetaXdrB64 != "" {
-		sac, err := extractSACTransfersAndMints(resultMetaXdrB64)
+		var sac []Transfer
+		sac, covered, err = extractSACTransfersAndMints(resultMetaXdrB64, inv)
 		if err != nil {
 			return nil, err
 		}
 		raw = append(raw, sac...)
 	}
 
+	var unmodeled []UnmodeledOp
+	if tx != nil {
+		xlm, err := extractNativeXLMPayments(*tx, covered)
+		if err != nil {
+			return nil, err
+		}
+		raw = append(xlm, raw...)
+		unmodeled = unmodeledOps(*tx, covered)
+	}
+
 	return &Report{
-		Raw: raw,
-		Agg: aggregate(raw),
+		Raw:       raw,
+		Agg:       aggregate(raw),
+		ByOp:      groupByOp(raw),
+		Unmodeled: unmodeled,
 	}, nil
 }
 
-func extractNativeXLMPayments(envelopeXdrB64 string) ([]Transfer, error) {
+// balanceOps are the classic operations that move balances other than
+// native payments
+var balanceOps = map[xdr.OperationType]bool{
+	xdr.OperationTypeCreateAccount:            true,
+	xdr.OperationTypePayment:                  true,
+	xdr.OperationTypePathPaymentStrictReceive: true,
+	xdr.OperationTypePathPaymentStrictSend:    true,
+	xdr.OperationTypeManageSellOffer:          true,
+	xdr.OperationTypeManageBuyOffer:           true,
+	xdr.OperationTypeCreatePassiveSellOffer:   true,
+	xdr.OperationTypeAccountMerge:             true,
+	xdr.OperationTypeInflation:                true,
+	xdr.OperationTypeAllowTrust:               true,
+	xdr.OperationTypeSetTrustLineFlags:        true,
+	xdr.OperationTypeCreateClaimableBalance:   true,
+	xdr.OperationTypeClaimClaimableBalance:    true,
+	xdr.OperationTypeClawback:                 true,
+	xdr.OperationTypeClawbackClaimableBalance: true,
+	xdr.OperationTypeLiquidityPoolDeposit:     true,
+	xdr.OperationTypeLiquidityPoolWithdraw:    true,
+}
+
+// unmodeledOps lists the operations that move balances the flow does not
+// capture: classic balance operations without events, other than native
+// payments, and operations that emitted events the flow does not model
+func unmodeledOps(tx xdr.Transaction, covered map[int]bool) []UnmodeledOp {
+	var out []UnmodeledOp
+	for i, op := range tx.Operations {
+		modeled, hasEvents := covered[i]
+		switch {
+		case hasEvents:
+			if modeled {
+				continue
+			}
+		case !balanceOps[op.Body.Type] || isNativePayment(op):
+			continue
+		}
+		out = append(out, UnmodeledOp{
+			OpIndex: i,
+			Type:    strings.TrimPrefix(op.Body.Type.String(), "OperationType"),
+		})
+	}
+	return out
+}
+
+func isNativePayment(op xdr.Operation) bool {
+	p, ok := op.Body.GetPaymentOp()
+	return ok && p.Asset.Type == xdr.AssetTypeAssetTypeNative
+}
+
+// invocation is the Soroban operation of a transaction. A transaction has
+// at most one, so every contract event belongs to it.
+type invocation struct {
+	opIndex  int
+	contract string
+}
+
+func findInvocation(tx xdr.Transaction) invocation {
+	for i, op := range tx.Operations {
+		hf, ok := op.Body.GetInvokeHostFunctionOp()
+		if !ok {
+			continue
+		}
+		inv := invocation{opIndex: i}
+		if args, ok := hf.HostFunction.GetInvokeContract(); ok {
+			if s, err := args.ContractAddress.String(); err == nil {
+				inv.contract = s
+			}
+		}
+		return inv
+	}
+	return invocation{}
+}
+
+// decodeTransaction returns the transaction of an envelope, or nil for the
+// rare V0 envelopes which are skipped
+func decodeTransaction(envelopeXdrB64 string) (*xdr.Transaction, error) {
 	envBytes, err := base64.StdEncoding.DecodeString(envelopeXdrB64)
 	if err != nil {
 		return nil, fmt.Errorf("decode envelope xdr base64: %w", err)
@@ -120,14 +255,19 @@ func extractNativeXLMPayments(envelopeXdrB64 string) ([]Transfer, error) {
 	default:
 		return nil, fmt.Errorf("unsupported envelope type: %s", env.Type)
 	}
+	return &tx, nil
+}
 
+// extractNativeXLMPayments reads native payments from the envelope, except
+// for operations whose events were read, which already include them
+func extractNativeXLMPayments(tx xdr.Transaction, covered map[int]bool) ([]Transfer, error) {
 	source, err := muxedAccountToAddress(tx.SourceAccount)
 	if err != nil {
 		return nil, err
 	}
 
 	var transfers []Transfer
-	for _, op := range tx.Operations {
+	for i, op := range tx.Operations {
 		opSource := source
 		if op.SourceAccount != nil {
 			if s, err := muxedAccountToAddress(*op.SourceAccount); err == nil {
@@ -135,14 +275,11 @@ func extractNativeXLMPayments(envelopeXdrB64 string) ([]Transfer, error) {
 			}
 		}
 
-		if op.Body.Type != xdr.OperationTypePayment {
+		if _, ok := covered[i]; ok || !isNativePayment(op) {
 			continue
 		}
 
 		p := op.Body.MustPaymentOp()
-		if p.Asset.Type != xdr.AssetTypeAssetTypeNative {
-			continue
-		}
 
 		to, err := muxedAccountToAddress(p.Destination)
 		if err != nil {
@@ -151,120 +288,206 @@ func extractNativeXLMPayments(envelopeXdrB64 string) ([]Transfer, error) {
 
 		amt := new(big.Int).SetInt64(int64(p.Amount))
 		transfers = append(transfers, Transfer{
-			From:   opSource,
-			To:     to,
-			Token:  Token{Symbol: "XLM"},
-			Amount: amt,
-			Kind:   KindTransfer,
+			From:    opSource,
+			To:      to,
+			Token:   Token{Symbol: "XLM"},
+			Amount:  amt,
+			Kind:    KindTransfer,
+			OpIndex: i,
 		})
 	}
 
 	return transfers, nil
 }
 
-func extractSACTransfersAndMints(resultMetaXdrB64 string) ([]Transfer, error) {
+func extractSACTransfersAndMints(resultMetaXdrB64 string, inv invocation) ([]Transfer, map[int]bool, error) {
 	metaBytes, err := base64.StdEncoding.DecodeString(resultMetaXdrB64)
 	if err != nil {
-		return nil, fmt.Errorf("decode result_meta xdr base64: %w", err)
+		return nil, nil, fmt.Errorf("decode result_meta xdr base64: %w", err)
 	}
 
 	var rm xdr.TransactionResultMeta
 	if err := xdr.SafeUnmarshal(metaBytes, &rm); err != nil {
-		return nil, fmt.Errorf("unmarshal TransactionResultMeta: %w", err)
+		return nil, nil, fmt.Errorf("unmarshal TransactionResultMeta: %w", err)
 	}
 
-	diag := extractDiagnosticEvents(rm.TxApplyProcessing)
 	var out []Transfer
-
-	for _, de := range diag {
-		// Avoid counting reverted calls.
-		if !de.InSuccessfulContractCall {
-			continue
-		}
-
-		ce := de.Event
-		if ce.ContractId == nil {
-			continue
-		}
-
-		contractStr, err := strkey.Encode(strkey.VersionByteContract, ce.ContractId[:])
-		if err != nil {
-			continue
-		}
-
-		body, ok := ce.Body.GetV0()
-		if !ok || len(body.Topics) == 0 {
-			continue
-		}
-
-		op, ok := scValSymbol(body.Topics[0])
-		if !ok {
-			continue
-		}
-
-		switch op {
-		case "transfer":
-			// Expected topics: ["transfer", from, to], data: amount
-			if len(body.Topics) < 3 {
+	covered := map[int]bool{}
+	diag := txmeta.DiagnosticEvents(rm.TxApplyProcessing)
+	fromDiag := hasContractEvents(diag)
+	if fromDiag {
+		covered[inv.opIndex] = true
+		var stack []string
+		for _, de := range diag {
+			if callee, ok := fnCallee(de.Event); ok {
+				stack = append(stack, callee)
 				continue
 			}
-			from, ok := scValAddressString(body.Topics[1])
-			if !ok {
+			if isFnReturn(de.Event) {
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
 				continue
 			}
-			to, ok := scValAddressString(body.Topics[2])
-			if !ok {
+
+			// Avoid counting reverted calls.
+			if !de.InSuccessfulContractCall {
 				continue
 			}
-			amt, ok := scValAmount(body.Data)
-			if !ok || amt.Sign() < 0 {
-				continue
+
+			t, modeled, ok := eventTransfer(de.Event, inv.opIndex)
+			if !modeled {
+				covered[inv.opIndex] = false
 			}
-			out = append(out, Transfer{
-				From:   from,
-				To:     to,
-				Token:  Token{Symbol: "SAC", ID: contractStr},
-				Amount: amt,
-				Kind:   KindTransfer,
-			})
-		case "mint":
-			// Expected topics: ["mint", to], data: amount
-			if len(body.Topics) < 2 {
-				continue
+			if ok {
+				token, _ := strkey.Encode(strkey.VersionByteContract, de.Event.ContractId[:])
+				t.Contract = invoker(stack, token, inv.contract)
+				out = append(out, t)
 			}
-			to, ok := scValAddressString(body.Topics[1])
-			if !ok {
-				continue
+		}
+	}
+
+	for i, events := range txmeta.OperationEvents(rm.TxApplyProcessing) {
+		if len(events) == 0 || (fromDiag && i == inv.opIndex) {
+			continue
+		}
+		covered[i] = true
+		for _, ce := range events {
+			t, modeled, ok := eventTransfer(ce, i)
+			if !modeled {
+				covered[i] = false
 			}
-			amt, ok := scValAmount(body.Data)
-			if !ok || amt.Sign() < 0 {
-				continue
+			if ok {
+				if i == inv.opIndex {
+					t.Contract = inv.contract
+				}
+				out = append(out, t)
 			}
-			out = append(out, Transfer{
-				From:   "MINT",
-				To:     to,
-				Token:  Token{Symbol: "SAC", ID: contractStr},
-				Amount: amt,
-				Kind:   KindMint,
-			})
 		}
 	}
 
-	return out, nil
+	return out, covered, nil
 }
 
-func extractDiagnosticEvents(tm xdr.TransactionMeta) []xdr.DiagnosticEvent {
-	switch tm.V {
-	case 3:
-		if tm.V3 != nil && tm.V3.SorobanMeta != nil {
-			return tm.V3.SorobanMeta.DiagnosticEvents
+func hasContractEvents(diag []xdr.DiagnosticEvent) bool {
+	for _, de := range diag {
+		if de.Event.Type == xdr.ContractEventTypeContract {
+			return true
+		}
+	}
+	return false
+}
+
+// eventTransfer reads a SAC transfer or mint event. modeled is false for
+// events that move balances in ways the flow does not represent.
+func eventTransfer(ce xdr.ContractEvent, opIndex int) (t Transfer, modeled, ok bool) {
+	if ce.ContractId == nil {
+		return Transfer{}, true, false
+	}
+	contractStr, err := strkey.Encode(strkey.VersionByteContract, ce.ContractId[:])
+	if err != nil {
+		return Transfer{}, true, false
+	}
+
+	body, ok := ce.Body.GetV0()
+	if !ok || len(body.Topics) == 0 {
+		return Transfer{}, true, false
+	}
+	op, ok := scValSymbol(body.Topics[0])
+	if !ok {
+		return Transfer{}, true, false
+	}
+
+	t = Transfer{Token: eventToken(body.Topics, contractStr), OpIndex: opIndex}
+	switch op {
+	case "transfer":
+		// Expected topics: ["transfer", from, to, asset?], data: amount
+		if len(body.Topics) < 3 {
+			return Transfer{}, true, false
+		}
+		from, ok := scValAddressString(body.Topics[1])
+		if !ok {
+			return Transfer{}, true, false
+		}
+		to, ok := scValAddressString(body.Topics[2])
+		if !ok {
+			return Transfer{}, true, false
 		}
-	case 4:
-		if tm.V4 != nil && len(tm.V4.DiagnosticEvents) > 0 {
-			return tm.V4.DiagnosticEvents
+		amt, ok := transferAmount(body.Data, &to)
+		if !ok || amt.Sign() < 0 {
+			return Transfer{}, true, false
+		}
+		t.From, t.To, t.Amount, t.Kind = from, to, amt, KindTransfer
+	case "mint":
+		// Expected topics: ["mint", to, asset?], data: amount
+		if len(body.Topics) < 2 {
+			return Transfer{}, true, false
+		}
+		to, ok := scValAddressString(body.Topics[1])
+		if !ok {
+			return Transfer{}, true, false
+		}
+		amt, ok := transferAmount(body.Data, &to)
+		if !ok || amt.Sign() < 0 {
+			return Transfer{}, true, false
+		}
+		t.From, t.To, t.Amount, t.Kind = "MINT", to, amt, KindMint
+	case "burn", "clawback":
+		return Transfer{}, false, false
+	default:
+		return Transfer{}, true, false
+	}
+	return t, true, true
+}
+
+// eventToken names the token of a SAC event, whose last topic is the
+// SEP-11 asset string; native XLM is reported as XLM
+func eventToken(topics []xdr.ScVal, contract string) Token {
+	if last := topics[len(topics)-1]; last.Type == xdr.ScValTypeScvString && last.Str != nil && string(*last.Str) == "native" {
+		return Token{Symbol: "XLM"}
+	}
+	return Token{Symbol: "SAC", ID: contract}
+}
+
+// fnCallee returns the called contract of a fn_call diagnostic event, whose
+// topics are ["fn_call", callee id bytes, function]
+func fnCallee(ce xdr.ContractEvent) (string, bool) {
+	body, ok := ce.Body.GetV0()
+	if !ok || len(body.Topics) < 2 {
+		return "", false
+	}
+	if sym, ok := scValSymbol(body.Topics[0]); !ok || sym != "fn_call" {
+		return "", false
+	}
+	b, ok := body.Topics[1].GetBytes()
+	if !ok || len(b) != 32 {
+		return "", true
+	}
+	id, err := strkey.Encode(strkey.VersionByteContract, b)
+	if err != nil {
+		return "", true
+	}
+	return id, true
+}
+
+func isFnReturn(ce xdr.ContractEvent) bool {
+	body, ok := ce.Body.GetV0()
+	if !ok || len(body.Topics) == 0 {
+		return false
+	}
+	sym, ok := scValSymbol(body.Topics[0])
+	return ok && sym == "fn_return"
+}
+
+// invoker returns the innermost contract on the call stack other than the
+// token itself, falling back to the contract invoked by the operation
+func invoker(stack []string, token, fallback string) string {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] != "" && stack[i] != token {
+			return stack[i]
 		}
 	}
-	return nil
+	return fallback
 }
 
 func scValSymbol(v xdr.ScVal) (string, bool) {
@@ -285,6 +508,38 @@ func scValAddressString(v xdr.ScVal) (string, bool) {
 	return s, true
 }
 
+// transferAmount reads the amount of a transfer or mint event. Since
+// protocol 23 (CAP-67), payments to a muxed account carry the data as a map
+// of amount and to_muxed_id; the recipient to is then rewritten as the
+// M... address so deposits are attributed to their muxed ID.
+func transferAmount(data xdr.ScVal, to *string) (*big.Int, bool) {
+	m, ok := data.GetMap()
+	if !ok || m == nil {
+		return scValAmount(data)
+	}
+	var amount *big.Int
+	for _, entry := range *m {
+		key, ok := scValSymbol(entry.Key)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "amount":
+			if amount, ok = scValAmount(entry.Val); !ok {
+				return nil, false
+			}
+		case "to_muxed_id":
+			// IDs of other types are memos for non-account recipients
+			if id, ok := entry.Val.GetU64(); ok {
+				if muxed, err := decoder.MuxAddress(*to, uint64(id)); err == nil {
+					*to = muxed
+				}
+			}
+		}
+	}
+	return amount, amount != nil
+}
+
 func scValAmount(v xdr.ScVal) (*big.Int, bool) {
 	switch v.Type {
 	case xdr.ScValTypeScvU64:
@@ -368,3 +623,38 @@ func aggregate(in []Transfer) []Transfer {
 
 	return out
 }
+
+func groupByOp(in []Transfer) []OpFlow {
+	type key struct {
+		op       int
+		contract string
+	}
+
+	groups := map[key][]Transfer{}
+	var keys []key
+	for _, t := range in {
+		k := key{op: t.OpIndex, contract: t.Contract}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], t)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].op != keys[j].op {
+			return keys[i].op < keys[j].op
+		}
+		return keys[i].contract < keys[j].contract
+	})
+
+	out := make([]OpFlow, 0, len(keys))
+	for _, k := range keys {
+		agg := aggregate(groups[k])
+		for i := range agg {
+			agg[i].OpIndex = k.op
+			agg[i].Contract = k.contract
+		}
+		out = append(out, OpFlow{OpIndex: k.op, Contract: k.contract, Agg: agg})
+	}
+	return out
+}
@@ -64,6 +64,24 @@ func TestBuildReport_SACTransferAndMint_FromResultMeta(t *testing.T) {
 	require.Equal(t, big.NewInt(7), r.Agg[1].Amount)
 }
 
+func TestBuildReport_SkipsEventsOfFailedCalls(t *testing.T) {
+	cid := xdr.ContractId(bytes32(0xAA))
+	from := scAddressAccount(bytes32(0x01))
+	to := scAddressAccount(bytes32(0x02))
+	topics := []xdr.ScVal{scSymbol("transfer"), scAddress(from), scAddress(to)}
+
+	// The reverted transfer of 50 is not a movement; the retried one of 20 is
+	events := []xdr.DiagnosticEvent{
+		diagnosticEvent(cid, topics, scU128(50), false),
+		diagnosticEvent(cid, topics, scU128(20), true),
+	}
+
+	r, err := BuildReport("", encodeResultMetaWithDiagnosticEvents(t, events))
+	require.NoError(t, err)
+	require.Len(t, r.Raw, 1)
+	require.Equal(t, big.NewInt(20), r.Raw[0].Amount)
+}
+
 func TestBuildReport_NativeXLMPayment_FromEnvelope(t *testing.T) {
 	src := bytes32(0x10)
 	dst := bytes32(0x20)
@@ -82,6 +100,61 @@ func TestBuildReport_NativeXLMPayment_FromEnvelope(t *testing.T) {
 	require.Equal(t, big.NewInt(12_345_678), tr.Amount)
 }
 
+func TestBuildReport_AttributesTransfersToInvokingContract(t *testing.T) {
+	token := xdr.ContractId(bytes32(0xAA))
+	router := bytes32(0xBB)
+	routerStr, err := strkey.Encode(strkey.VersionByteContract, router[:])
+	require.NoError(t, err)
+
+	from := scAddressAccount(bytes32(0x01))
+	to := scAddressAccount(bytes32(0x02))
+	events := []xdr.DiagnosticEvent{
+		fnCall(router, "swap"),
+		fnCall(token, "transfer"),
+		diagnosticEvent(token, []xdr.ScVal{scSymbol("transfer"), scAddress(from), scAddress(to)}, scU128(50), true),
+		fnReturn("transfer"),
+		fnReturn("swap"),
+	}
+
+	r, err := BuildReport("", encodeResultMetaWithDiagnosticEvents(t, events))
+	require.NoError(t, err)
+	require.Len(t, r.ByOp, 1)
+	require.Equal(t, 0, r.ByOp[0].OpIndex)
+	require.Equal(t, routerStr, r.ByOp[0].Contract)
+	require.Equal(t, routerStr, r.Raw[0].Contract)
+
+	lines := r.SummaryLines()
+	require.Len(t, lines, 2)
+	require.Equal(t, "Operation #0 via "+routerStr+":", lines[0])
+}
+
+func fnCall(callee [32]byte, fn string) xdr.DiagnosticEvent {
+	id := callee[:]
+	return xdr.DiagnosticEvent{
+		InSuccessfulContractCall: true,
+		Event: xdr.ContractEvent{
+			Type: xdr.ContractEventTypeDiagnostic,
+			Body: xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{
+				Topics: []xdr.ScVal{scSymbol("fn_call"), {Type: xdr.ScValTypeScvBytes, Bytes: (*xdr.ScBytes)(&id)}, scSymbol(fn)},
+				Data:   xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			}},
+		},
+	}
+}
+
+func fnReturn(fn string) xdr.DiagnosticEvent {
+	return xdr.DiagnosticEvent{
+		InSuccessfulContractCall: true,
+		Event: xdr.ContractEvent{
+			Type: xdr.ContractEventTypeDiagnostic,
+			Body: xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{
+				Topics: []xdr.ScVal{scSymbol("fn_return"), scSymbol(fn)},
+				Data:   xdr.ScVal{Type: xdr.ScValTypeScvVoid},
+			}},
+		},
+	}
+}
+
 func encodeResultMetaWithDiagnosticEvents(t *testing.T, events []xdr.DiagnosticEvent) string {
 	t.Helper()
 
@@ -246,3 +319,8 @@ func addrMuxed(pk [32]byte) string {
 	}
 	return s
 }
+
+func scString(s string) xdr.ScVal {
+	v := xdr.ScString(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &v}
+}
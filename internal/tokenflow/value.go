@@ -0,0 +1,68 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenflow
+
+import (
+	"math/big"
+)
+
+// PriceFunc returns the USD price of one whole unit of a token
+type PriceFunc func(t Token) (float64, bool)
+
+// ApplyPrices sets the approximate USD value of every movement whose token
+// has a known price and known decimals. Movements of tokens with unknown
+// decimals stay unpriced, since their whole-unit amount is ambiguous.
+func (r *Report) ApplyPrices(price PriceFunc) {
+	apply := func(ts []Transfer) {
+		for i := range ts {
+			ts[i].USD = nil
+			units, ok := wholeUnits(ts[i])
+			if !ok {
+				continue
+			}
+			p, ok := price(ts[i].Token)
+			if !ok {
+				continue
+			}
+			v := units * p
+			ts[i].USD = &v
+		}
+	}
+
+	apply(r.Raw)
+	apply(r.Agg)
+	for i := range r.ByOp {
+		apply(r.ByOp[i].Agg)
+	}
+}
+
+// TotalUSD returns the summed value of the priced movements and whether any
+// movement was priced
+func (r *Report) TotalUSD() (float64, bool) {
+	var total float64
+	priced := false
+	for _, t := range r.Agg {
+		if t.USD != nil {
+			total += *t.USD
+			priced = true
+		}
+	}
+	return total, priced
+}
+
+func wholeUnits(t Transfer) (float64, bool) {
+	if t.Amount == nil {
+		return 0, false
+	}
+	decimals := t.Token.Decimals
+	if t.Token.Symbol == "XLM" && t.Token.ID == "" {
+		decimals = 7
+	}
+	if decimals == 0 {
+		return 0, false
+	}
+	scale := new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+	units, _ := new(big.Float).Quo(new(big.Float).SetInt(t.Amount), scale).Float64()
+	return units, true
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyPrices(t *testing.T) {
+	r := &Report{Agg: []Transfer{
+		{From: "A", To: "B", Token: Token{Symbol: "XLM"}, Amount: big.NewInt(500_000_000), Kind: KindTransfer},
+		{From: "A", To: "C", Token: Token{Symbol: "USDC", ID: "CUSDC", Decimals: 6}, Amount: big.NewInt(2_500_000), Kind: KindTransfer},
+		{From: "A", To: "D", Token: Token{Symbol: "SAC", ID: "CRAW"}, Amount: big.NewInt(7), Kind: KindTransfer},
+	}}
+
+	prices := map[string]float64{"XLM": 0.1, "CUSDC": 1, "CRAW": 100}
+	r.ApplyPrices(func(tok Token) (float64, bool) {
+		key := tok.ID
+		if key == "" {
+			key = tok.Symbol
+		}
+		p, ok := prices[key]
+		return p, ok
+	})
+
+	require.NotNil(t, r.Agg[0].USD)
+	require.InDelta(t, 5.0, *r.Agg[0].USD, 1e-9)
+	require.NotNil(t, r.Agg[1].USD)
+	require.InDelta(t, 2.5, *r.Agg[1].USD, 1e-9)
+	require.Nil(t, r.Agg[2].USD, "tokens with unknown decimals stay unpriced")
+
+	total, ok := r.TotalUSD()
+	require.True(t, ok)
+	require.InDelta(t, 7.5, total, 1e-9)
+
+	lines := r.SummaryLines()
+	require.Equal(t, "A -> 50 XLM (~$5.00) -> B", lines[0])
+	require.Equal(t, "A -> 7 SAC(CRAW) -> D", lines[2])
+	require.Equal(t, "<$0.01", FormatUSD(0.001))
+}
+
+func TestReport_WriteCSV(t *testing.T) {
+	usd := 12.5
+	r := &Report{Raw: []Transfer{
+		{From: "GA", To: "GB", Token: Token{Symbol: "XLM"}, Amount: big.NewInt(25_000_000), Kind: KindTransfer, USD: &usd},
+		{From: "CA", To: "GC", Token: Token{Symbol: "USDC", ID: "CUSDC", Decimals: 7}, Amount: big.NewInt(1_0000000), Kind: KindMint, OpIndex: 1, Contract: "CPOOL"},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteCSV(&buf))
+	require.Equal(t, "op_index,kind,from,to,token,token_id,amount,raw_amount,contract,usd,from_account,from_muxed_id,to_account,to_muxed_id\n"+
+		"0,transfer,GA,GB,XLM,,2.5,25000000,,12.50,GA,,GB,\n"+
+		"1,mint,CA,GC,USDC,CUSDC,1,10000000,CPOOL,,CA,,GC,\n", buf.String())
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	usd := 12.5
+	transfer := Transfer{From: "GA", To: "GB", Token: Token{Symbol: "XLM"}, Amount: big.NewInt(25_000_000), Kind: KindTransfer, USD: &usd}
+	r := &Report{Raw: []Transfer{transfer}, Agg: []Transfer{transfer}}
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WriteJSON(&buf))
+	var doc struct {
+		Transfers []map[string]any `json:"transfers"`
+		Totals    []map[string]any `json:"totals"`
+		TotalUSD  float64          `json:"total_usd"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Transfers, 1)
+	require.Equal(t, "2.5", doc.Transfers[0]["amount"])
+	require.Equal(t, "25000000", doc.Transfers[0]["raw_amount"])
+	require.Len(t, doc.Totals, 1)
+	require.Equal(t, 12.5, doc.TotalUSD)
+
+	buf.Reset()
+	require.NoError(t, (&Report{}).WriteJSON(&buf))
+	require.JSONEq(t, `{"transfers":[],"totals":[]}`, buf.String())
+}
@@ -0,0 +1,329 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenflow
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/dotandev/hintents/internal/decoder"
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// nativeKey identifies native XLM in balance deltas
+const nativeKey = "XLM"
+
+// Discrepancy is a holder whose balance change in the ledger meta differs
+// from the net change implied by the token flow.
+type Discrepancy struct {
+	Holder   string
+	Token    string // "XLM" or the token contract ID
+	Expected *big.Int
+	Actual   *big.Int
+}
+
+func (d Discrepancy) String() string {
+	return fmt.Sprintf("%s %s: token flow %s, ledger %s", d.Holder, d.Token, d.Expected, d.Actual)
+}
+
+// VerifyBalances cross-checks the net balance deltas implied by the report
+// against the account, trustline and contract balance changes recorded in
+// the operation meta. Fee charges and refunds live outside operation meta
+// and are therefore not counted, and neither are the changes of the
+// report's unmodeled operations.
+//
+// The network passphrase maps classic assets to their Stellar Asset
+// Contract IDs; without it, trustline changes are not checked.
+func VerifyBalances(r *Report, resultMetaXdrB64, passphrase string) ([]Discrepancy, error) {
+	metaBytes, err := base64.StdEncoding.DecodeString(resultMetaXdrB64)
+	if err != nil {
+		return nil, fmt.Errorf("decode result_meta xdr base64: %w", err)
+	}
+	var rm xdr.TransactionResultMeta
+	if err := xdr.SafeUnmarshal(metaBytes, &rm); err != nil {
+		return nil, fmt.Errorf("unmarshal TransactionResultMeta: %w", err)
+	}
+
+	skip := map[int]bool{}
+	for _, op := range r.Unmodeled {
+		skip[op.OpIndex] = true
+	}
+	var changes []xdr.LedgerEntryChange
+	for i, opChanges := range txmeta.OperationChanges(rm.TxApplyProcessing) {
+		if !skip[i] {
+			changes = append(changes, opChanges...)
+		}
+	}
+	var transfers []Transfer
+	for _, t := range r.Raw {
+		if !skip[t.OpIndex] {
+			transfers = append(transfers, t)
+		}
+	}
+
+	v := newVerifier(passphrase)
+	actual := v.ledgerDeltas(changes)
+	expected := v.flowDeltas(transfers)
+
+	keys := map[balanceKey]bool{}
+	for k := range expected {
+		keys[k] = true
+	}
+	for k := range actual {
+		if !v.issuers[k] {
+			keys[k] = true
+		}
+	}
+
+	var out []Discrepancy
+	for k := range keys {
+		exp, act := orZero(expected[k]), orZero(actual[k])
+		if exp.Cmp(act) != 0 {
+			out = append(out, Discrepancy{Holder: k.holder, Token: k.token, Expected: exp, Actual: act})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Token != out[j].Token {
+			return out[i].Token < out[j].Token
+		}
+		return out[i].Holder < out[j].Holder
+	})
+	return out, nil
+}
+
+type balanceKey struct {
+	holder string
+	token  string
+}
+
+type verifier struct {
+	passphrase string
+	nativeSAC  string
+	// issuers are holder/token pairs whose balance is unbounded and never
+	// recorded, so transfers involving them are not checked
+	issuers map[balanceKey]bool
+}
+
+func newVerifier(passphrase string) *verifier {
+	v := &verifier{passphrase: passphrase, issuers: map[balanceKey]bool{}}
+	if passphrase != "" {
+		if id, err := (xdr.Asset{Type: xdr.AssetTypeAssetTypeNative}).ContractID(passphrase); err == nil {
+			v.nativeSAC, _ = strkey.Encode(strkey.VersionByteContract, id[:])
+		}
+	}
+	return v
+}
+
+// tokenKey maps the native asset contract onto native XLM, so SAC
+// transfers of XLM reconcile with account balance changes
+func (v *verifier) tokenKey(t Token) string {
+	if t.ID == "" || t.ID == v.nativeSAC {
+		return nativeKey
+	}
+	return t.ID
+}
+
+func (v *verifier) flowDeltas(transfers []Transfer) map[balanceKey]*big.Int {
+	out := map[balanceKey]*big.Int{}
+	add := func(holder, token string, amt *big.Int) {
+		k := balanceKey{holder: holder, token: token}
+		if out[k] == nil {
+			out[k] = new(big.Int)
+		}
+		out[k].Add(out[k], amt)
+	}
+
+	for _, t := range transfers {
+		if t.Amount == nil {
+			continue
+		}
+		// Ledger entries belong to the account behind a muxed address
+		token := v.tokenKey(t.Token)
+		if t.Kind != KindMint {
+			add(decoder.BaseAccount(t.From), token, new(big.Int).Neg(t.Amount))
+		}
+		add(decoder.BaseAccount(t.To), token, t.Amount)
+	}
+
+	for k, amt := range out {
+		if v.issuers[k] || amt.Sign() == 0 {
+			delete(out, k)
+		}
+	}
+	return out
+}
+
+func (v *verifier) ledgerDeltas(changes []xdr.LedgerEntryChange) map[balanceKey]*big.Int {
+	type span struct {
+		before, after *xdr.LedgerEntry
+		seen          bool
+	}
+	spans := map[string]*span{}
+	var order []string
+
+	for _, c := range changes {
+		key, err := c.LedgerKey()
+		if err != nil {
+			continue
+		}
+		id, err := key.MarshalBinaryBase64()
+		if err != nil {
+			continue
+		}
+		s, ok := spans[id]
+		if !ok {
+			s = &span{}
+			spans[id] = s
+			order = append(order, id)
+		}
+
+		switch c.Type {
+		case xdr.LedgerEntryChangeTypeLedgerEntryState:
+			if !s.seen {
+				e := *c.State
+				s.before, s.after = &e, &e
+			}
+		case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+			e := *c.Created
+			s.after = &e
+		case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+			e := *c.Updated
+			s.after = &e
+		case xdr.LedgerEntryChangeTypeLedgerEntryRemoved:
+			s.after = nil
+		}
+		s.seen = true
+	}
+
+	out := map[balanceKey]*big.Int{}
+	for _, id := range order {
+		s := spans[id]
+		ref := s.after
+		if ref == nil {
+			ref = s.before
+		}
+		if ref == nil {
+			continue
+		}
+		k, ok := v.balanceKeyOf(*ref)
+		if !ok {
+			continue
+		}
+		delta := new(big.Int).Sub(balanceOf(s.after), balanceOf(s.before))
+		if delta.Sign() == 0 {
+			continue
+		}
+		if out[k] == nil {
+			out[k] = new(big.Int)
+		}
+		out[k].Add(out[k], delta)
+	}
+	return out
+}
+
+// balanceKeyOf identifies the holder and token of a balance-carrying entry
+func (v *verifier) balanceKeyOf(e xdr.LedgerEntry) (balanceKey, bool) {
+	switch e.Data.Type {
+	case xdr.LedgerEntryTypeAccount:
+		return balanceKey{holder: e.Data.Account.AccountId.Address(), token: nativeKey}, true
+	case xdr.LedgerEntryTypeTrustline:
+		if v.passphrase == "" {
+			return balanceKey{}, false
+		}
+		tl := e.Data.TrustLine
+		asset, ok := trustLineAsset(tl.Asset)
+		if !ok {
+			return balanceKey{}, false
+		}
+		id, err := asset.ContractID(v.passphrase)
+		if err != nil {
+			return balanceKey{}, false
+		}
+		token, err := strkey.Encode(strkey.VersionByteContract, id[:])
+		if err != nil {
+			return balanceKey{}, false
+		}
+		if issuer := asset.GetIssuer(); issuer != "" {
+			v.issuers[balanceKey{holder: issuer, token: token}] = true
+		}
+		return balanceKey{holder: tl.AccountId.Address(), token: token}, true
+	case xdr.LedgerEntryTypeContractData:
+		cd := e.Data.ContractData
+		holder, ok := contractBalanceHolder(cd.Key)
+		if !ok {
+			return balanceKey{}, false
+		}
+		token, err := cd.Contract.String()
+		if err != nil {
+			return balanceKey{}, false
+		}
+		if token == v.nativeSAC {
+			token = nativeKey
+		}
+		return balanceKey{holder: holder, token: token}, true
+	}
+	return balanceKey{}, false
+}
+
+func trustLineAsset(a xdr.TrustLineAsset) (xdr.Asset, bool) {
+	switch a.Type {
+	case xdr.AssetTypeAssetTypeCreditAlphanum4:
+		return xdr.Asset{Type: a.Type, AlphaNum4: a.AlphaNum4}, true
+	case xdr.AssetTypeAssetTypeCreditAlphanum12:
+		return xdr.Asset{Type: a.Type, AlphaNum12: a.AlphaNum12}, true
+	}
+	return xdr.Asset{}, false
+}
+
+// contractBalanceHolder recognizes the SAC balance key
+// Vec[Symbol("Balance"), Address]
+func contractBalanceHolder(key xdr.ScVal) (string, bool) {
+	vec, ok := key.GetVec()
+	if !ok || vec == nil || len(*vec) != 2 {
+		return "", false
+	}
+	if sym, ok := scValSymbol((*vec)[0]); !ok || sym != "Balance" {
+		return "", false
+	}
+	return scValAddressString((*vec)[1])
+}
+
+func balanceOf(e *xdr.LedgerEntry) *big.Int {
+	if e == nil {
+		return new(big.Int)
+	}
+	switch e.Data.Type {
+	case xdr.LedgerEntryTypeAccount:
+		return big.NewInt(int64(e.Data.Account.Balance))
+	case xdr.LedgerEntryTypeTrustline:
+		return big.NewInt(int64(e.Data.TrustLine.Balance))
+	case xdr.LedgerEntryTypeContractData:
+		val := e.Data.ContractData.Val
+		if m, ok := val.GetMap(); ok && m != nil {
+			for _, entry := range *m {
+				if sym, ok := scValSymbol(entry.Key); ok && sym == "amount" {
+					if amt, ok := scValAmount(entry.Val); ok {
+						return amt
+					}
+				}
+			}
+			return new(big.Int)
+		}
+		if amt, ok := scValAmount(val); ok {
+			return amt
+		}
+	}
+	return new(big.Int)
+}
+
+func orZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
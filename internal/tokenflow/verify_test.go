@@ -0,0 +1,179 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package tokenflow
+
+import (
+	"encoding/base64"
+	"math/big"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/strkey"
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyBalances(t *testing.T) {
+	alice, bob := bytes32(0x01), bytes32(0x02)
+	token := xdr.ContractId(bytes32(0xAA))
+	tokenStr, err := strkey.Encode(strkey.VersionByteContract, token[:])
+	require.NoError(t, err)
+
+	aliceAddr := scAddressAccount(alice)
+	bobAddr := scAddressAccount(bob)
+
+	changes := xdr.LedgerEntryChanges{
+		stateChange(accountEntry(alice, 1000)),
+		updatedChange(accountEntry(alice, 400)),
+		createdChange(accountEntry(bob, 600)),
+		stateChange(balanceEntry(token, aliceAddr, 90)),
+		updatedChange(balanceEntry(token, aliceAddr, 40)),
+		stateChange(balanceEntry(token, bobAddr, 0)),
+		updatedChange(balanceEntry(token, bobAddr, 45)),
+	}
+	meta := encodeResultMetaWithOperationChanges(t, changes)
+
+	report := &Report{Raw: []Transfer{
+		{From: addrMuxed(alice), To: addrMuxed(bob), Token: Token{Symbol: "XLM"}, Amount: big.NewInt(600), Kind: KindTransfer},
+		{From: addrString(aliceAddr), To: addrString(bobAddr), Token: Token{Symbol: "SAC", ID: tokenStr}, Amount: big.NewInt(50), Kind: KindTransfer},
+	}}
+
+	got, err := VerifyBalances(report, meta, "")
+	require.NoError(t, err)
+	require.Len(t, got, 1, "XLM matches; the token contract credited 5 less than it reported")
+	require.Equal(t, addrString(bobAddr), got[0].Holder)
+	require.Equal(t, tokenStr, got[0].Token)
+	require.Equal(t, big.NewInt(50), got[0].Expected)
+	require.Equal(t, big.NewInt(45), got[0].Actual)
+}
+
+func accountEntry(pk [32]byte, balance int64) xdr.LedgerEntry {
+	id, err := xdr.NewAccountId(xdr.PublicKeyTypePublicKeyTypeEd25519, xdr.Uint256(pk))
+	if err != nil {
+		panic(err)
+	}
+	return xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.AccountEntry{AccountId: id, Balance: xdr.Int64(balance)},
+	}}
+}
+
+func balanceEntry(token xdr.ContractId, holder xdr.ScAddress, amount uint64) xdr.LedgerEntry {
+	key := xdr.ScVec{scSymbol("Balance"), scAddress(holder)}
+	keyPtr := &key
+	val := xdr.ScMap{{Key: scSymbol("amount"), Val: scU128(amount)}}
+	valPtr := &val
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &token}
+	return xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.ContractDataEntry{
+			Contract:   contract,
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &keyPtr},
+			Durability: xdr.ContractDataDurabilityPersistent,
+			Val:        xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &valPtr},
+		},
+	}}
+}
+
+func stateChange(e xdr.LedgerEntry) xdr.LedgerEntryChange {
+	return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: &e}
+}
+
+func updatedChange(e xdr.LedgerEntry) xdr.LedgerEntryChange {
+	return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: &e}
+}
+
+func createdChange(e xdr.LedgerEntry) xdr.LedgerEntryChange {
+	return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryCreated, Created: &e}
+}
+
+func encodeResultMetaWithOperationChanges(t *testing.T, changes xdr.LedgerEntryChanges) string {
+	t.Helper()
+
+	tm := xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+		Operations: []xdr.OperationMeta{{Changes: changes}},
+	}}
+	emptyOpResults := []xdr.OperationResult{}
+	rm := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{
+			Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &emptyOpResults},
+		}},
+		TxApplyProcessing: tm,
+	}
+
+	b, err := rm.MarshalBinary()
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestVerifyBalances_V4OperationEventsAndUnmodeledOps(t *testing.T) {
+	alice, bob, carol := bytes32(0x01), bytes32(0x02), bytes32(0x03)
+	nativeSAC := xdr.ContractId(bytes32(0xAA))
+
+	aliceMux, err := xdr.NewMuxedAccount(xdr.CryptoKeyTypeKeyTypeEd25519, xdr.Uint256(alice))
+	require.NoError(t, err)
+	bobMux, err := xdr.NewMuxedAccount(xdr.CryptoKeyTypeKeyTypeEd25519, xdr.Uint256(bob))
+	require.NoError(t, err)
+	carolMux, err := xdr.NewMuxedAccount(xdr.CryptoKeyTypeKeyTypeEd25519, xdr.Uint256(carol))
+	require.NoError(t, err)
+	native := xdr.Asset{Type: xdr.AssetTypeAssetTypeNative}
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: aliceMux,
+			Operations: []xdr.Operation{
+				{Body: xdr.OperationBody{Type: xdr.OperationTypePayment, PaymentOp: &xdr.PaymentOp{
+					Destination: bobMux, Asset: native, Amount: 600,
+				}}},
+				{Body: xdr.OperationBody{Type: xdr.OperationTypePathPaymentStrictSend, PathPaymentStrictSendOp: &xdr.PathPaymentStrictSendOp{
+					SendAsset: native, SendAmount: 100, Destination: carolMux, DestAsset: native, DestMin: 1,
+				}}},
+			},
+		}},
+	}
+	envB64, err := xdr.MarshalBase64(env)
+	require.NoError(t, err)
+
+	// The payment's transfer event names the asset; the path payment
+	// emitted none, as before protocol 23
+	paymentEvent := diagnosticEvent(nativeSAC, []xdr.ScVal{
+		scSymbol("transfer"), scAddress(scAddressAccount(alice)), scAddress(scAddressAccount(bob)), scString("native"),
+	}, scU128(600), true).Event
+	tm := xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{
+		Operations: []xdr.OperationMetaV2{
+			{
+				Changes: xdr.LedgerEntryChanges{
+					stateChange(accountEntry(alice, 1000)),
+					updatedChange(accountEntry(alice, 400)),
+					createdChange(accountEntry(bob, 600)),
+				},
+				Events: []xdr.ContractEvent{paymentEvent},
+			},
+			{
+				Changes: xdr.LedgerEntryChanges{
+					stateChange(accountEntry(alice, 400)),
+					updatedChange(accountEntry(alice, 300)),
+					createdChange(accountEntry(carol, 100)),
+				},
+			},
+		},
+	}}
+	emptyOpResults := []xdr.OperationResult{}
+	metaB64, err := xdr.MarshalBase64(xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{
+			Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &emptyOpResults},
+		}},
+		TxApplyProcessing: tm,
+	})
+	require.NoError(t, err)
+
+	report, err := BuildReport(envB64, metaB64)
+	require.NoError(t, err)
+	require.Len(t, report.Raw, 1, "the payment is read from its event, not also from the envelope")
+	require.Equal(t, "XLM", report.Raw[0].Token.Display())
+	require.Equal(t, []UnmodeledOp{{OpIndex: 1, Type: "PathPaymentStrictSend"}}, report.Unmodeled)
+
+	got, err := VerifyBalances(report, metaB64, "")
+	require.NoError(t, err)
+	require.Empty(t, got, "the path payment's changes are not checked")
+}
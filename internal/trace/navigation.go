@@ -41,6 +41,9 @@ type ExecutionTrace struct {
 	Snapshots        []StateSnapshot  `json:"snapshots"`
 	CurrentStep      int              `json:"current_step"`
 	SnapshotInterval int              `json:"snapshot_interval"`
+	// StorageAccesses records the contract data the transaction loaded and
+	// changed, derived from its footprint and metadata
+	StorageAccesses []StorageAccess `json:"storage_accesses,omitempty"`
 }
 
 // NewExecutionTrace creates a new execution trace
@@ -0,0 +1,224 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/visualizer"
+)
+
+// Stepper is the subset of a live step session the debugger drives
+type Stepper interface {
+	Current() *simulator.StepEvent
+	Done() bool
+	Step() (*simulator.StepEvent, error)
+	Continue() (*simulator.StepEvent, error)
+	ContinueTo(breakpoints map[string]bool) (*simulator.StepEvent, error)
+	Inspect() (*simulator.StepEvent, error)
+}
+
+// StepDebugger is an interactive terminal front-end for a paused simulation.
+// Unlike InteractiveViewer it drives execution live rather than replaying a
+// recorded trace.
+type StepDebugger struct {
+	session     Stepper
+	reader      *bufio.Reader
+	out         io.Writer
+	breakpoints map[string]bool
+}
+
+// NewStepDebugger creates a step debugger reading commands from stdin
+func NewStepDebugger(session Stepper) *StepDebugger {
+	return &StepDebugger{
+		session:     session,
+		reader:      bufio.NewReader(os.Stdin),
+		out:         os.Stdout,
+		breakpoints: make(map[string]bool),
+	}
+}
+
+// Start runs the command loop until execution finishes or the user quits
+func (d *StepDebugger) Start() error {
+	fmt.Fprintf(d.out, "%s ERST Step Debugger\n", visualizer.Symbol("magnify"))
+	visualizer.WriteRule(d.out, "=", 21)
+	d.showHelp()
+	d.showEvent(d.session.Current())
+
+	for !d.session.Done() {
+		fmt.Fprint(d.out, "\n(step) ")
+		input, err := d.reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		quit, err := d.handleCommand(strings.TrimSpace(input))
+		if err != nil {
+			return err
+		}
+		if quit {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// handleCommand processes one command and returns true if exit is requested.
+// Errors are returned only when the simulator connection is lost.
+func (d *StepDebugger) handleCommand(command string) (bool, error) {
+	parts := strings.Fields(command)
+	if len(parts) == 0 {
+		return false, nil
+	}
+
+	var ev *simulator.StepEvent
+	var err error
+
+	switch strings.ToLower(parts[0]) {
+	case "s", "step", "n", "next":
+		ev, err = d.session.Step()
+	case "c", "continue":
+		ev, err = d.session.ContinueTo(d.breakpoints)
+	case "b", "break":
+		if len(parts) < 2 {
+			d.listBreakpoints()
+			return false, nil
+		}
+		d.breakpoints[parts[1]] = true
+		fmt.Fprintf(d.out, "Breakpoint set on %s\n", parts[1])
+		return false, nil
+	case "d", "delete":
+		if len(parts) < 2 {
+			fmt.Fprintln(d.out, "Usage: delete <function>")
+			return false, nil
+		}
+		delete(d.breakpoints, parts[1])
+		return false, nil
+	case "i", "inspect", "storage", "budget":
+		state, err := d.session.Inspect()
+		if err != nil {
+			return false, err
+		}
+		d.showState(state)
+		return false, nil
+	case "bt", "where":
+		d.showStack(d.session.Current())
+		return false, nil
+	case "h", "help":
+		d.showHelp()
+		return false, nil
+	case "q", "quit", "exit":
+		fmt.Fprintf(d.out, "Aborting simulation %s\n", visualizer.Symbol("wave"))
+		return true, nil
+	default:
+		fmt.Fprintf(d.out, "Unknown command: %s. Type 'help' for available commands.\n", parts[0])
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+	d.showEvent(ev)
+	return false, nil
+}
+
+func (d *StepDebugger) showEvent(ev *simulator.StepEvent) {
+	if ev == nil {
+		return
+	}
+	if ev.Type == simulator.StepEventResult {
+		status := "unknown"
+		if ev.Response != nil {
+			status = ev.Response.Status
+		}
+		fmt.Fprintf(d.out, "%s Execution finished: %s\n", visualizer.Symbol("target"), status)
+		return
+	}
+
+	switch ev.Reason {
+	case simulator.PauseStart:
+		fmt.Fprintf(d.out, "%s Paused before execution\n", visualizer.Symbol("pin"))
+	case simulator.PauseCall, simulator.PauseReturn:
+		verb := "Entering"
+		if ev.Reason == simulator.PauseReturn {
+			verb = "Returning from"
+		}
+		if ev.Frame != nil {
+			fmt.Fprintf(d.out, "%s %s %s (depth %d)\n", visualizer.Symbol("arrow_r"), verb, ev.Frame.Function, ev.Frame.Depth)
+			if ev.Frame.Contract != "" {
+				fmt.Fprintf(d.out, "   Contract: %s\n", ev.Frame.Contract)
+			}
+		}
+	default:
+		fmt.Fprintf(d.out, "%s Paused (%s)\n", visualizer.Symbol("pin"), ev.Reason)
+	}
+}
+
+func (d *StepDebugger) showStack(ev *simulator.StepEvent) {
+	if ev == nil || len(ev.Stack) == 0 {
+		fmt.Fprintln(d.out, "No active contract calls")
+		return
+	}
+	for i := len(ev.Stack) - 1; i >= 0; i-- {
+		f := ev.Stack[i]
+		fmt.Fprintf(d.out, "  #%d %s", len(ev.Stack)-1-i, f.Function)
+		if f.Contract != "" {
+			fmt.Fprintf(d.out, " [%s]", f.Contract)
+		}
+		fmt.Fprintln(d.out)
+	}
+}
+
+func (d *StepDebugger) showState(state *simulator.StepEvent) {
+	if state.Budget != nil {
+		fmt.Fprintf(d.out, "\n%s Budget\n", visualizer.Symbol("chart"))
+		fmt.Fprintf(d.out, "  CPU:    %d instructions\n", state.Budget.CPUInstructions)
+		fmt.Fprintf(d.out, "  Memory: %d bytes\n", state.Budget.MemoryBytes)
+	}
+
+	fmt.Fprintf(d.out, "\n%s Storage (%d entries)\n", visualizer.Symbol("list"), len(state.Storage))
+	for _, e := range state.Storage {
+		fmt.Fprintf(d.out, "  %s = %s", e.Key, e.Value)
+		if e.Durability != "" {
+			fmt.Fprintf(d.out, " (%s)", e.Durability)
+		}
+		fmt.Fprintln(d.out)
+	}
+}
+
+func (d *StepDebugger) listBreakpoints() {
+	if len(d.breakpoints) == 0 {
+		fmt.Fprintln(d.out, "No breakpoints set")
+		return
+	}
+	names := make([]string, 0, len(d.breakpoints))
+	for name := range d.breakpoints {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(d.out, "Breakpoints: %s\n", strings.Join(names, ", "))
+}
+
+func (d *StepDebugger) showHelp() {
+	fmt.Fprintf(d.out, "\n%s Available Commands\n", visualizer.Symbol("book"))
+	visualizer.WriteRule(d.out, "=", 21)
+	fmt.Fprintln(d.out, "  s, step              - Run to the next call boundary")
+	fmt.Fprintln(d.out, "  c, continue          - Run to the next breakpoint or the end")
+	fmt.Fprintln(d.out, "  b, break [function]  - Set a breakpoint or list breakpoints")
+	fmt.Fprintln(d.out, "  d, delete <function> - Remove a breakpoint")
+	fmt.Fprintln(d.out, "  i, inspect           - Show current storage and budget")
+	fmt.Fprintln(d.out, "  bt, where            - Show the contract call stack")
+	fmt.Fprintln(d.out, "  h, help              - Show this help")
+	fmt.Fprintln(d.out, "  q, quit              - Abort the simulation")
+}
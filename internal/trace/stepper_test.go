@@ -0,0 +1,81 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+)
+
+type fakeStepper struct {
+	events  []*simulator.StepEvent
+	pos     int
+	inspect int
+}
+
+func (f *fakeStepper) Current() *simulator.StepEvent { return f.events[f.pos] }
+func (f *fakeStepper) Done() bool                    { return f.Current().Type == simulator.StepEventResult }
+
+func (f *fakeStepper) Step() (*simulator.StepEvent, error) {
+	f.pos++
+	return f.Current(), nil
+}
+
+func (f *fakeStepper) Continue() (*simulator.StepEvent, error) {
+	f.pos = len(f.events) - 1
+	return f.Current(), nil
+}
+
+func (f *fakeStepper) ContinueTo(bps map[string]bool) (*simulator.StepEvent, error) {
+	for {
+		ev, _ := f.Step()
+		if ev.Type == simulator.StepEventResult || (ev.Frame != nil && bps[ev.Frame.Function]) {
+			return ev, nil
+		}
+	}
+}
+
+func (f *fakeStepper) Inspect() (*simulator.StepEvent, error) {
+	f.inspect++
+	return &simulator.StepEvent{
+		Type:    simulator.StepEventState,
+		Budget:  &simulator.BudgetUsage{CPUInstructions: 1234},
+		Storage: []simulator.StorageEntry{{Key: "Balance(GABC)", Value: "500"}},
+	}, nil
+}
+
+func TestStepDebugger_Session(t *testing.T) {
+	stepper := &fakeStepper{events: []*simulator.StepEvent{
+		{Type: simulator.StepEventPaused, Reason: simulator.PauseStart},
+		{Type: simulator.StepEventPaused, Reason: simulator.PauseCall, Frame: &simulator.StepFrame{Function: "transfer", Depth: 1}},
+		{Type: simulator.StepEventPaused, Reason: simulator.PauseCall, Frame: &simulator.StepFrame{Function: "spend_balance", Depth: 2}},
+		{Type: simulator.StepEventResult, Response: &simulator.SimulationResponse{Status: "success"}},
+	}}
+
+	var out bytes.Buffer
+	d := NewStepDebugger(stepper)
+	d.out = &out
+	d.reader = bufio.NewReader(strings.NewReader("break spend_balance\ncontinue\ninspect\nstep\n"))
+
+	if err := d.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{"Breakpoint set on spend_balance", "Entering spend_balance (depth 2)", "Balance(GABC) = 500", "1234 instructions", "Execution finished: success"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Entering transfer") {
+		t.Error("continue should not stop at functions without breakpoints")
+	}
+	if stepper.inspect != 1 {
+		t.Errorf("expected one inspect call, got %d", stepper.inspect)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/dotandev/hintents/internal/txmeta"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// Storage operations recorded in a trace
+const (
+	StorageRead   = "read"
+	StorageWrite  = "write"
+	StorageDelete = "delete"
+)
+
+// Where a storage access came from. Simulator accesses were observed during
+// execution, in order. The other two are derived after the fact: footprint
+// reads are entries the host loaded for the transaction, whether or not the
+// contract asked for them.
+const (
+	StorageSourceSimulator = "simulator"
+	StorageSourceFootprint = "footprint"
+	StorageSourceMeta      = "meta"
+)
+
+// StorageAccess is one contract data entry loaded or changed by a
+// transaction
+type StorageAccess struct {
+	Op string `json:"op"`
+	// Source is StorageSourceSimulator when the simulator observed the
+	// access, otherwise StorageSourceFootprint for reads and
+	// StorageSourceMeta for writes and deletes
+	Source     string `json:"source"`
+	ContractID string `json:"contract_id"`
+	// Key is the base64 XDR ScVal of the data key
+	Key string `json:"key"`
+	// KeyXdr is the base64 XDR ledger key
+	KeyXdr     string `json:"key_xdr"`
+	Durability string `json:"durability"`
+	// ValueHash is the hex SHA-256 of the value XDR that was read or
+	// written, empty when the entry did not exist
+	ValueHash string `json:"value_hash,omitempty"`
+	// Value is the base64 XDR ScVal that was read or written
+	Value string `json:"value,omitempty"`
+}
+
+// StorageQuery selects storage accesses by key and contract. Empty fields
+// match everything; Key matches a substring of either key encoding.
+type StorageQuery struct {
+	Key        string
+	ContractID string
+	Op         string
+}
+
+func (q StorageQuery) matches(a StorageAccess) bool {
+	if q.ContractID != "" && !strings.EqualFold(a.ContractID, q.ContractID) {
+		return false
+	}
+	if q.Op != "" && a.Op != q.Op {
+		return false
+	}
+	if q.Key != "" && !strings.Contains(a.Key, q.Key) && !strings.Contains(a.KeyXdr, q.Key) {
+		return false
+	}
+	return true
+}
+
+// RecordStorage appends storage accesses to the trace
+func (t *ExecutionTrace) RecordStorage(accesses ...StorageAccess) {
+	t.StorageAccesses = append(t.StorageAccesses, accesses...)
+}
+
+// FindStorage returns the storage accesses matching q in recorded order
+func (t *ExecutionTrace) FindStorage(q StorageQuery) []StorageAccess {
+	var out []StorageAccess
+	for _, a := range t.StorageAccesses {
+		if q.matches(a) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// StorageFromSimulation converts the contract data accesses the simulator
+// observed during execution, keeping their order
+func StorageFromSimulation(accesses []simulator.StorageAccess) ([]StorageAccess, error) {
+	out := make([]StorageAccess, 0, len(accesses))
+	for _, sa := range accesses {
+		var key xdr.LedgerKey
+		if err := xdr.SafeUnmarshalBase64(sa.KeyXdr, &key); err != nil {
+			return nil, fmt.Errorf("failed to decode ledger key: %w", err)
+		}
+		if key.Type != xdr.LedgerEntryTypeContractData {
+			continue
+		}
+		a := keyAccess(sa.Op, StorageSourceSimulator, key, sa.KeyXdr)
+		if sa.Value != "" {
+			raw, err := base64.StdEncoding.DecodeString(sa.Value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode value: %w", err)
+			}
+			a.setValue(raw)
+		}
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+// StorageFromMeta reconstructs the contract data accesses of a Soroban
+// transaction for simulators that do not report the accesses they make, so
+// this is derived rather than observed: every contract data key in the footprint
+// is recorded as read, with its value from the pre-image in resultMetaXdr or,
+// failing that, from entries, the ledger entries the replay was given keyed
+// by base64 ledger key. Writes and deletes come from the metadata.
+func StorageFromMeta(envelopeXdr, resultMetaXdr string, entries map[string]string) ([]StorageAccess, error) {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &env); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+
+	var changes []xdr.LedgerEntryChange
+	if resultMetaXdr != "" {
+		var rm xdr.TransactionResultMeta
+		if err := xdr.SafeUnmarshalBase64(resultMetaXdr, &rm); err != nil {
+			return nil, fmt.Errorf("failed to decode result meta: %w", err)
+		}
+		for _, opChanges := range txmeta.OperationChanges(rm.TxApplyProcessing) {
+			changes = append(changes, opChanges...)
+		}
+	}
+
+	// Values seen before the transaction, from metadata pre-images
+	before := make(map[string]*xdr.LedgerEntry)
+	for _, c := range changes {
+		if c.Type == xdr.LedgerEntryChangeTypeLedgerEntryState && c.State != nil {
+			if k, err := c.State.LedgerKey(); err == nil {
+				if encoded, err := xdr.MarshalBase64(k); err == nil {
+					before[encoded] = c.State
+				}
+			}
+		}
+	}
+
+	var out []StorageAccess
+	for _, key := range footprintKeys(env) {
+		if key.Type != xdr.LedgerEntryTypeContractData {
+			continue
+		}
+		encoded, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode ledger key: %w", err)
+		}
+		entry := before[encoded]
+		if entry == nil {
+			if raw, ok := entries[encoded]; ok {
+				var e xdr.LedgerEntry
+				if err := xdr.SafeUnmarshalBase64(raw, &e); err == nil {
+					entry = &e
+				}
+			}
+		}
+		out = append(out, newAccess(StorageRead, StorageSourceFootprint, key, encoded, entry))
+	}
+
+	for _, c := range changes {
+		var (
+			op    string
+			entry *xdr.LedgerEntry
+			key   xdr.LedgerKey
+			err   error
+		)
+		switch c.Type {
+		case xdr.LedgerEntryChangeTypeLedgerEntryCreated:
+			op, entry = StorageWrite, c.Created
+		case xdr.LedgerEntryChangeTypeLedgerEntryUpdated:
+			op, entry = StorageWrite, c.Updated
+		case xdr.LedgerEntryChangeTypeLedgerEntryRestored:
+			op, entry = StorageWrite, c.Restored
+		case xdr.LedgerEntryChangeTypeLedgerEntryRemoved:
+			op, key = StorageDelete, *c.Removed
+		default:
+			continue
+		}
+		if entry != nil {
+			if key, err = entry.LedgerKey(); err != nil {
+				continue
+			}
+		}
+		if key.Type != xdr.LedgerEntryTypeContractData {
+			continue
+		}
+		encoded, err := xdr.MarshalBase64(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode ledger key: %w", err)
+		}
+		out = append(out, newAccess(op, StorageSourceMeta, key, encoded, entry))
+	}
+	return out, nil
+}
+
+func newAccess(op, source string, key xdr.LedgerKey, encoded string, entry *xdr.LedgerEntry) StorageAccess {
+	a := keyAccess(op, source, key, encoded)
+	if entry != nil && entry.Data.ContractData != nil {
+		if raw, err := entry.Data.ContractData.Val.MarshalBinary(); err == nil {
+			a.setValue(raw)
+		}
+	}
+	return a
+}
+
+func keyAccess(op, source string, key xdr.LedgerKey, encoded string) StorageAccess {
+	cd := key.ContractData
+	contract, _ := cd.Contract.String()
+	a := StorageAccess{Op: op, Source: source, ContractID: contract, KeyXdr: encoded, Durability: "persistent"}
+	if cd.Durability == xdr.ContractDataDurabilityTemporary {
+		a.Durability = "temporary"
+	}
+	a.Key, _ = xdr.MarshalBase64(cd.Key)
+	return a
+}
+
+// setValue records raw, the XDR ScVal that was read or written
+func (a *StorageAccess) setValue(raw []byte) {
+	sum := sha256.Sum256(raw)
+	a.ValueHash = hex.EncodeToString(sum[:])
+	a.Value = base64.StdEncoding.EncodeToString(raw)
+}
+
+func footprintKeys(env xdr.TransactionEnvelope) []xdr.LedgerKey {
+	var data *xdr.SorobanTransactionData
+	switch {
+	case env.V1 != nil:
+		data = env.V1.Tx.Ext.SorobanData
+	case env.FeeBump != nil && env.FeeBump.Tx.InnerTx.V1 != nil:
+		data = env.FeeBump.Tx.InnerTx.V1.Tx.Ext.SorobanData
+	}
+	if data == nil {
+		return nil
+	}
+	fp := data.Resources.Footprint
+	return append(append([]xdr.LedgerKey{}, fp.ReadOnly...), fp.ReadWrite...)
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package trace
+
+import (
+	"testing"
+
+	"github.com/dotandev/hintents/internal/simulator"
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+const storageTestContract = "CAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABSC4"
+
+func dataKey(t *testing.T, sym string) xdr.LedgerKey {
+	t.Helper()
+	// The all-zero contract ID encodes as storageTestContract
+	var id xdr.ContractId
+	addr := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}
+	s := xdr.ScSymbol(sym)
+	return xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract:   addr,
+		Key:        xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &s},
+		Durability: xdr.ContractDataDurabilityPersistent,
+	}}
+}
+
+func dataEntry(key xdr.LedgerKey, v uint32) *xdr.LedgerEntry {
+	u := xdr.Uint32(v)
+	return &xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.ContractDataEntry{
+			Contract:   key.ContractData.Contract,
+			Key:        key.ContractData.Key,
+			Durability: key.ContractData.Durability,
+			Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u},
+		},
+	}}
+}
+
+func TestStorageFromMeta(t *testing.T) {
+	config, counter := dataKey(t, "config"), dataKey(t, "counter")
+	wasm := []byte{0}
+	env := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"),
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+					Type: xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm, Wasm: &wasm,
+				}},
+			}}},
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{
+					ReadOnly:  []xdr.LedgerKey{config},
+					ReadWrite: []xdr.LedgerKey{counter},
+				}},
+			}},
+		}},
+	}
+	envXdr, err := xdr.MarshalBase64(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	noResults := []xdr.OperationResult{}
+	meta := xdr.TransactionResultMeta{
+		Result: xdr.TransactionResultPair{Result: xdr.TransactionResult{
+			Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &noResults},
+		}},
+		TxApplyProcessing: xdr.TransactionMeta{V: 3, V3: &xdr.TransactionMetaV3{
+			Operations: []xdr.OperationMeta{{Changes: xdr.LedgerEntryChanges{
+				{Type: xdr.LedgerEntryChangeTypeLedgerEntryState, State: dataEntry(counter, 1)},
+				{Type: xdr.LedgerEntryChangeTypeLedgerEntryUpdated, Updated: dataEntry(counter, 2)},
+			}}},
+			SorobanMeta: &xdr.SorobanTransactionMeta{ReturnValue: xdr.ScVal{Type: xdr.ScValTypeScvVoid}},
+		}},
+	}
+	metaXdr, err := xdr.MarshalBase64(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configKey, _ := xdr.MarshalBase64(config)
+	configEntry, _ := xdr.MarshalBase64(dataEntry(config, 7))
+
+	accesses, err := StorageFromMeta(envXdr, metaXdr, map[string]string{configKey: configEntry})
+	if err != nil {
+		t.Fatalf("StorageFromMeta failed: %v", err)
+	}
+	if len(accesses) != 3 {
+		t.Fatalf("expected 2 reads and 1 write, got %d: %+v", len(accesses), accesses)
+	}
+
+	readCounter, write := accesses[1], accesses[2]
+	if accesses[0].Op != StorageRead || accesses[0].ValueHash == "" {
+		t.Errorf("config read should see the fetched value, got %+v", accesses[0])
+	}
+	if readCounter.Op != StorageRead || write.Op != StorageWrite {
+		t.Errorf("unexpected ops: %s, %s", readCounter.Op, write.Op)
+	}
+	if readCounter.ValueHash == write.ValueHash {
+		t.Error("read and written values should differ")
+	}
+	if write.ContractID != storageTestContract {
+		t.Errorf("expected contract %s, got %s", storageTestContract, write.ContractID)
+	}
+
+	if accesses[0].Source != StorageSourceFootprint || write.Source != StorageSourceMeta {
+		t.Errorf("expected footprint read and meta write, got %s, %s", accesses[0].Source, write.Source)
+	}
+
+	tr := NewExecutionTrace("tx", 0)
+	tr.RecordStorage(accesses...)
+
+	if got := tr.FindStorage(StorageQuery{Op: StorageWrite}); len(got) != 1 {
+		t.Errorf("expected 1 write, got %d", len(got))
+	}
+	if got := tr.FindStorage(StorageQuery{Key: readCounter.Key}); len(got) != 2 {
+		t.Errorf("expected read and write of counter, got %d", len(got))
+	}
+	if got := tr.FindStorage(StorageQuery{ContractID: "CBBB"}); len(got) != 0 {
+		t.Errorf("expected no accesses for other contract, got %d", len(got))
+	}
+
+	data, err := tr.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := FromJSON(data)
+	if err != nil || len(loaded.StorageAccesses) != 3 {
+		t.Errorf("storage accesses should round-trip through JSON, got %v %d", err, len(loaded.StorageAccesses))
+	}
+}
+
+func TestStorageFromSimulation(t *testing.T) {
+	counter := dataKey(t, "counter")
+	counterKey, _ := xdr.MarshalBase64(counter)
+	value, _ := xdr.MarshalBase64(dataEntry(counter, 2).Data.ContractData.Val)
+	account, _ := xdr.MarshalBase64(xdr.LedgerKey{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.LedgerKeyAccount{
+		AccountId: xdr.MustAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"),
+	}})
+
+	accesses, err := StorageFromSimulation([]simulator.StorageAccess{
+		{Op: StorageRead, KeyXdr: counterKey},
+		{Op: StorageRead, KeyXdr: account},
+		{Op: StorageWrite, KeyXdr: counterKey, Value: value},
+	})
+	if err != nil {
+		t.Fatalf("StorageFromSimulation failed: %v", err)
+	}
+	if len(accesses) != 2 {
+		t.Fatalf("expected the read and write of counter, got %d: %+v", len(accesses), accesses)
+	}
+
+	read, write := accesses[0], accesses[1]
+	if read.Op != StorageRead || read.ValueHash != "" {
+		t.Errorf("counter did not exist when read, got %+v", read)
+	}
+	if write.Op != StorageWrite || write.Value != value || write.ValueHash == "" {
+		t.Errorf("write should carry the new value, got %+v", write)
+	}
+	if write.Source != StorageSourceSimulator || write.ContractID != storageTestContract {
+		t.Errorf("unexpected source or contract: %s, %s", write.Source, write.ContractID)
+	}
+
+	if _, err := StorageFromSimulation([]simulator.StorageAccess{{Op: StorageRead, KeyXdr: "!"}}); err == nil {
+		t.Error("expected an error for an undecodable key")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -30,7 +31,7 @@ func NewInteractiveViewer(trace *ExecutionTrace) *InteractiveViewer {
 // Start begins the interactive trace viewing session
 func (v *InteractiveViewer) Start() error {
 	fmt.Printf("%s ERST Interactive Trace Viewer\n", visualizer.Symbol("magnify"))
-	fmt.Println("=================================")
+	visualizer.WriteRule(os.Stdout, "=", 33)
 	fmt.Printf("Transaction: %s\n", v.trace.TransactionHash)
 	fmt.Printf("Total Steps: %d\n\n", len(v.trace.States))
 
@@ -156,7 +157,7 @@ func (v *InteractiveViewer) displayCurrentState() {
 	}
 
 	fmt.Printf("\n%s Current State\n", visualizer.Symbol("pin"))
-	fmt.Println("================")
+	visualizer.WriteRule(os.Stdout, "=", 16)
 	fmt.Printf("Step: %d/%d\n", state.Step, len(v.trace.States)-1)
 	fmt.Printf("Time: %s\n", state.Timestamp.Format("15:04:05.000"))
 	fmt.Printf("Operation: %s\n", state.Operation)
@@ -195,7 +196,7 @@ func (v *InteractiveViewer) reconstructCurrentState() {
 	}
 
 	fmt.Printf("\n%s Reconstructed State\n", visualizer.Symbol("wrench"))
-	fmt.Println("======================")
+	visualizer.WriteRule(os.Stdout, "=", 22)
 	v.displayState(state)
 }
 
@@ -214,7 +215,7 @@ func (v *InteractiveViewer) reconstructState(stepStr string) {
 	}
 
 	fmt.Printf("\n%s Reconstructed State at Step %d\n", visualizer.Symbol("wrench"), step)
-	fmt.Println("==================================")
+	visualizer.WriteRule(os.Stdout, "=", 34)
 	v.displayState(state)
 }
 
@@ -233,15 +234,15 @@ func (v *InteractiveViewer) displayState(state *ExecutionState) {
 
 	if len(state.HostState) > 0 {
 		fmt.Println("\nHost State:")
-		for k, v := range state.HostState {
-			fmt.Printf("  %s: %v\n", k, v)
+		for _, k := range sortedKeys(state.HostState) {
+			fmt.Printf("  %s: %v\n", k, state.HostState[k])
 		}
 	}
 
 	if len(state.Memory) > 0 {
 		fmt.Println("\nMemory:")
-		for k, v := range state.Memory {
-			fmt.Printf("  %s: %v\n", k, v)
+		for _, k := range sortedKeys(state.Memory) {
+			fmt.Printf("  %s: %v\n", k, state.Memory[k])
 		}
 	}
 }
@@ -251,7 +252,7 @@ func (v *InteractiveViewer) showNavigationInfo() {
 	info := v.trace.GetNavigationInfo()
 
 	fmt.Printf("\n%s Navigation Info\n", visualizer.Symbol("chart"))
-	fmt.Println("==================")
+	visualizer.WriteRule(os.Stdout, "=", 18)
 	fmt.Printf("Total Steps: %d\n", info["total_steps"])
 	fmt.Printf("Current Step: %d\n", info["current_step"])
 	fmt.Printf("Can Step Back: %t\n", info["can_step_back"])
@@ -271,7 +272,7 @@ func (v *InteractiveViewer) listSteps(countStr string) {
 	end := min(len(v.trace.States)-1, start+count-1)
 
 	fmt.Printf("\n%s Steps %d-%d\n", visualizer.Symbol("list"), start, end)
-	fmt.Println("===============")
+	visualizer.WriteRule(os.Stdout, "=", 15)
 
 	for i := start; i <= end; i++ {
 		state := &v.trace.States[i]
@@ -294,7 +295,7 @@ func (v *InteractiveViewer) listSteps(countStr string) {
 // showHelp displays available commands
 func (v *InteractiveViewer) showHelp() {
 	fmt.Printf("\n%s Available Commands\n", visualizer.Symbol("book"))
-	fmt.Println("=====================")
+	visualizer.WriteRule(os.Stdout, "=", 21)
 	fmt.Println("Navigation:")
 	fmt.Println("  n, next, forward     - Step forward")
 	fmt.Println("  p, prev, back        - Step backward")
@@ -325,3 +326,13 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// sortedKeys returns the keys of m in sorted order for stable display
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package txmeta reads ledger changes, events and return values out of every
+// TransactionMeta version, and Soroban resources out of envelopes, so
+// analyses do not each switch on the version.
+package txmeta
+
+import (
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+// OperationChanges returns the ledger changes of each operation, indexed
+// like the transaction's operations. Transaction-level changes such as fee
+// charges and refunds are not included.
+func OperationChanges(tm xdr.TransactionMeta) [][]xdr.LedgerEntryChange {
+	var out [][]xdr.LedgerEntryChange
+	switch {
+	case tm.V4 != nil:
+		for _, op := range tm.V4.Operations {
+			out = append(out, op.Changes)
+		}
+	case tm.V3 != nil:
+		for _, op := range tm.V3.Operations {
+			out = append(out, op.Changes)
+		}
+	case tm.V2 != nil:
+		for _, op := range tm.V2.Operations {
+			out = append(out, op.Changes)
+		}
+	case tm.V1 != nil:
+		for _, op := range tm.V1.Operations {
+			out = append(out, op.Changes)
+		}
+	case tm.Operations != nil:
+		for _, op := range *tm.Operations {
+			out = append(out, op.Changes)
+		}
+	}
+	return out
+}
+
+// Changes returns the transaction-level changes made before the operations
+// followed by the operation changes, in application order
+func Changes(tm xdr.TransactionMeta) []xdr.LedgerEntryChange {
+	var out []xdr.LedgerEntryChange
+	switch {
+	case tm.V4 != nil:
+		out = append(out, tm.V4.TxChangesBefore...)
+	case tm.V3 != nil:
+		out = append(out, tm.V3.TxChangesBefore...)
+	case tm.V2 != nil:
+		out = append(out, tm.V2.TxChangesBefore...)
+	case tm.V1 != nil:
+		out = append(out, tm.V1.TxChanges...)
+	}
+	for _, changes := range OperationChanges(tm) {
+		out = append(out, changes...)
+	}
+	return out
+}
+
+// DiagnosticEvents returns the diagnostic events of a Soroban transaction,
+// which are only present when the network recorded them
+func DiagnosticEvents(tm xdr.TransactionMeta) []xdr.DiagnosticEvent {
+	switch {
+	case tm.V4 != nil:
+		return tm.V4.DiagnosticEvents
+	case tm.V3 != nil && tm.V3.SorobanMeta != nil:
+		return tm.V3.SorobanMeta.DiagnosticEvents
+	}
+	return nil
+}
+
+// OperationEvents returns the contract events emitted by each operation,
+// indexed like the transaction's operations. V4 meta records them per
+// operation, classic operations included. V3 meta only records the events
+// of the Soroban invocation, which is always the only operation.
+func OperationEvents(tm xdr.TransactionMeta) [][]xdr.ContractEvent {
+	switch {
+	case tm.V4 != nil:
+		out := make([][]xdr.ContractEvent, len(tm.V4.Operations))
+		for i, op := range tm.V4.Operations {
+			out[i] = op.Events
+		}
+		return out
+	case tm.V3 != nil && tm.V3.SorobanMeta != nil:
+		return [][]xdr.ContractEvent{tm.V3.SorobanMeta.Events}
+	}
+	return nil
+}
+
+// ReturnValue returns the value the Soroban invocation returned, which is
+// only recorded for successful transactions
+func ReturnValue(tm xdr.TransactionMeta) (xdr.ScVal, bool) {
+	switch {
+	case tm.V4 != nil && tm.V4.SorobanMeta != nil && tm.V4.SorobanMeta.ReturnValue != nil:
+		return *tm.V4.SorobanMeta.ReturnValue, true
+	case tm.V3 != nil && tm.V3.SorobanMeta != nil:
+		return tm.V3.SorobanMeta.ReturnValue, true
+	}
+	return xdr.ScVal{}, false
+}
+
+// SorobanData returns the Soroban resources of env, unwrapping fee bumps,
+// or nil for classic transactions
+func SorobanData(env xdr.TransactionEnvelope) *xdr.SorobanTransactionData {
+	switch {
+	case env.V1 != nil:
+		return env.V1.Tx.Ext.SorobanData
+	case env.FeeBump != nil && env.FeeBump.Tx.InnerTx.V1 != nil:
+		return env.FeeBump.Tx.InnerTx.V1.Tx.Ext.SorobanData
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package txmeta
+
+import (
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+)
+
+func removed(seq uint32) xdr.LedgerEntryChange {
+	key := xdr.LedgerKey{Type: xdr.LedgerEntryTypeTtl, Ttl: &xdr.LedgerKeyTtl{KeyHash: xdr.Hash{byte(seq)}}}
+	return xdr.LedgerEntryChange{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &key}
+}
+
+func TestChangesAcrossVersions(t *testing.T) {
+	v4 := xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{
+		TxChangesBefore: xdr.LedgerEntryChanges{removed(1)},
+		Operations: []xdr.OperationMetaV2{
+			{Changes: xdr.LedgerEntryChanges{removed(2)}, Events: []xdr.ContractEvent{{}}},
+			{Changes: xdr.LedgerEntryChanges{removed(3)}},
+		},
+		TxChangesAfter: xdr.LedgerEntryChanges{removed(4)},
+	}}
+	ops := []xdr.OperationMeta{{Changes: xdr.LedgerEntryChanges{removed(2)}}, {Changes: xdr.LedgerEntryChanges{removed(3)}}}
+	v0 := xdr.TransactionMeta{V: 0, Operations: &ops}
+
+	for name, tm := range map[string]xdr.TransactionMeta{"v4": v4, "v0": v0} {
+		perOp := OperationChanges(tm)
+		if len(perOp) != 2 || perOp[1][0].Removed.Ttl.KeyHash[0] != 3 {
+			t.Errorf("%s: unexpected operation changes %+v", name, perOp)
+		}
+	}
+
+	all := Changes(v4)
+	if len(all) != 3 || all[0].Removed.Ttl.KeyHash[0] != 1 {
+		t.Errorf("expected changes before the operations then theirs, got %d", len(all))
+	}
+
+	events := OperationEvents(v4)
+	if len(events) != 2 || len(events[0]) != 1 || len(events[1]) != 0 {
+		t.Errorf("expected events indexed by operation, got %+v", events)
+	}
+	if OperationEvents(v0) != nil {
+		t.Error("expected no events from V0 meta")
+	}
+}
+
+func TestReturnValue(t *testing.T) {
+	v := xdr.Uint32(5)
+	val := xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+	got, ok := ReturnValue(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{SorobanMeta: &xdr.SorobanTransactionMetaV2{ReturnValue: &val}}})
+	if !ok || *got.U32 != 5 {
+		t.Errorf("expected the V4 return value, got %+v", got)
+	}
+	if _, ok := ReturnValue(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{}}); ok {
+		t.Error("expected no return value without Soroban meta")
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package visualizer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var accessibleFlag bool
+
+// SetAccessible turns the accessible rendering mode on or off
+func SetAccessible(on bool) {
+	accessibleFlag = on
+}
+
+// Accessible reports whether output should be rendered for screen readers:
+// no color, no box-drawing characters and no wide tables, only linear
+// labeled text. It is enabled by --accessible or ERST_ACCESSIBLE.
+func Accessible() bool {
+	if accessibleFlag {
+		return true
+	}
+	switch strings.ToLower(os.Getenv("ERST_ACCESSIBLE")) {
+	case "", "0", "false", "no":
+		return false
+	}
+	return true
+}
+
+// Heading returns a section title: "=== title ===" normally and
+// "Section: title" in accessible mode
+func Heading(title string) string {
+	if Accessible() {
+		return "Section: " + title
+	}
+	return "=== " + title + " ==="
+}
+
+// WriteRule writes a separator line of width copies of ch to w. Nothing is
+// written in accessible mode, where such lines are only noise.
+func WriteRule(w io.Writer, ch string, width int) {
+	if Accessible() {
+		return
+	}
+	fmt.Fprintln(w, strings.Repeat(ch, width))
+}
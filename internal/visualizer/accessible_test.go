@@ -0,0 +1,52 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package visualizer
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestAccessibleModeDisablesColorAndSymbols(t *testing.T) {
+	os.Setenv("FORCE_COLOR", "1")
+	defer os.Unsetenv("FORCE_COLOR")
+	SetAccessible(true)
+	defer SetAccessible(false)
+
+	if ColorEnabled() {
+		t.Error("accessible mode must disable color even with FORCE_COLOR")
+	}
+	if got := Success(); got != "Success:" {
+		t.Errorf("Success() = %q", got)
+	}
+	if got := Error(); got != "Error:" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := Symbol("cross"); got != "Failed:" {
+		t.Errorf("Symbol(cross) = %q", got)
+	}
+	if got := Symbol("wrench"); got != "" {
+		t.Errorf("decorative symbol should be dropped, got %q", got)
+	}
+	if got := Heading("Fee Breakdown"); got != "Section: Fee Breakdown" {
+		t.Errorf("Heading() = %q", got)
+	}
+
+	var buf bytes.Buffer
+	WriteRule(&buf, "─", 10)
+	if buf.Len() != 0 {
+		t.Errorf("rule written in accessible mode: %q", buf.String())
+	}
+}
+
+func TestAccessibleEnv(t *testing.T) {
+	for value, want := range map[string]bool{"1": true, "true": true, "0": false, "false": false, "": false} {
+		os.Setenv("ERST_ACCESSIBLE", value)
+		if got := Accessible(); got != want {
+			t.Errorf("ERST_ACCESSIBLE=%q: Accessible() = %v, want %v", value, got, want)
+		}
+	}
+	os.Unsetenv("ERST_ACCESSIBLE")
+}
@@ -6,6 +6,7 @@ package visualizer
 import (
 	"os"
 
+	"github.com/dotandev/hintents/internal/platform"
 	"github.com/mattn/go-isatty"
 )
 
@@ -23,20 +24,26 @@ const (
 )
 
 // ColorEnabled reports whether ANSI color output should be used.
-// Check order (NO_COLOR has highest priority):
+// Check order (accessible mode and NO_COLOR have highest priority):
+//   - Accessible mode: color is never used, so no meaning is carried by color alone
 //   - NO_COLOR (https://no-color.org/): if set (any non-empty value), colors are disabled
 //   - FORCE_COLOR: if set (e.g. FORCE_COLOR=1), forces colors even when not a TTY (useful in CI)
+//   - CI: detected CI environments get no colors unless FORCE_COLOR is set
 //   - Non-TTY: when stdout is piped or redirected, colors disabled (no garbage in logs)
 //   - TERM=dumb: minimal terminal, no colors
 func ColorEnabled() bool {
 	// NO_COLOR takes precedence over everything
-	if noColor() {
+	if Accessible() || noColor() {
 		return false
 	}
 	// FORCE_COLOR allows colors in pipes/CI (e.g. GitHub Actions with ANSI support)
 	if forceColor() {
 		return true
 	}
+	// CI logs are often viewed without ANSI support
+	if platform.InCI() {
+		return false
+	}
 	// Not a real terminal (pipe, redirect, log file)
 	if !isatty.IsTerminal(os.Stdout.Fd()) {
 		return false
@@ -97,9 +104,11 @@ func ansiWrap(text, color string) string {
 	return code + text + sgrReset
 }
 
-// Success returns a success indicator: colored checkmark if enabled, "[OK]" otherwise.
 // Success returns a success indicator: colored checkmark if enabled, "[OK]" otherwise.
 func Success() string {
+	if Accessible() {
+		return "Success:"
+	}
 	if ColorEnabled() {
 		return sgrGreen + "[OK]" + sgrReset
 	}
@@ -108,6 +117,9 @@ func Success() string {
 
 // Warning returns a warning indicator: colored warning sign if enabled, "[!]" otherwise.
 func Warning() string {
+	if Accessible() {
+		return "Warning:"
+	}
 	if ColorEnabled() {
 		return sgrYellow + "[!]" + sgrReset
 	}
@@ -116,6 +128,9 @@ func Warning() string {
 
 // Error returns an error indicator: colored X if enabled, "[X]" otherwise.
 func Error() string {
+	if Accessible() {
+		return "Error:"
+	}
 	if ColorEnabled() {
 		return sgrRed + "[X]" + sgrReset
 	}
@@ -126,6 +141,9 @@ func Error() string {
 //
 //nolint:gocyclo
 func Symbol(name string) string {
+	if Accessible() {
+		return accessibleSymbol(name)
+	}
 	if ColorEnabled() {
 		switch name {
 		case "check":
@@ -202,3 +220,30 @@ func Symbol(name string) string {
 		return name
 	}
 }
+
+// accessibleSymbol spells symbols out as words a screen reader can speak;
+// purely decorative ones are dropped
+func accessibleSymbol(name string) string {
+	switch name {
+	case "check":
+		return "OK:"
+	case "cross":
+		return "Failed:"
+	case "warn":
+		return "Warning:"
+	case "arrow_r":
+		return "to"
+	case "arrow_l":
+		return "from"
+	case "target":
+		return "Target:"
+	case "logs":
+		return "Logs:"
+	case "events":
+		return "Events:"
+	case "pin", "wrench", "chart", "list", "play", "book", "wave", "magnify":
+		return ""
+	default:
+		return name
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package visualizer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	quietFlag bool
+
+	porcelainMu  sync.Mutex
+	porcelainOut io.Writer
+)
+
+// SetQuiet turns quiet mode on or off. Quiet mode suppresses spinners and
+// progress chatter; results, warnings and errors are still printed.
+func SetQuiet(on bool) {
+	quietFlag = on
+}
+
+// Quiet reports whether progress output should be suppressed. Porcelain
+// mode is always quiet.
+func Quiet() bool {
+	return quietFlag || Porcelain()
+}
+
+// Infof prints a progress message to stdout unless quiet mode is on
+func Infof(format string, args ...interface{}) {
+	if Quiet() {
+		return
+	}
+	fmt.Fprintf(os.Stdout, format, args...)
+}
+
+// SetPorcelain directs porcelain records to w. A nil writer turns porcelain
+// mode off.
+func SetPorcelain(w io.Writer) {
+	porcelainMu.Lock()
+	defer porcelainMu.Unlock()
+	porcelainOut = w
+}
+
+// Porcelain reports whether stable, line-oriented output was requested
+func Porcelain() bool {
+	porcelainMu.Lock()
+	defer porcelainMu.Unlock()
+	return porcelainOut != nil
+}
+
+// Record writes one porcelain line: fields separated by tabs, with tabs,
+// newlines and backslashes escaped and empty fields written as "-". It does
+// nothing outside porcelain mode.
+func Record(fields ...string) {
+	porcelainMu.Lock()
+	defer porcelainMu.Unlock()
+	if porcelainOut == nil {
+		return
+	}
+	fmt.Fprintln(porcelainOut, porcelainLine(fields))
+}
+
+var porcelainEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+func porcelainLine(fields []string) string {
+	out := make([]string, len(fields))
+	for i, f := range fields {
+		if f == "" {
+			f = "-"
+		}
+		out[i] = porcelainEscaper.Replace(f)
+	}
+	return strings.Join(out, "\t")
+}
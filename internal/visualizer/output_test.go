@@ -0,0 +1,32 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package visualizer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRecordEscapesFields(t *testing.T) {
+	var out bytes.Buffer
+	SetPorcelain(&out)
+	defer SetPorcelain(nil)
+
+	Record("finding", "high", "", "a\tb\nc\\d")
+	want := "finding\thigh\t-\ta\\tb\\nc\\\\d\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+	if !Quiet() {
+		t.Error("porcelain mode should imply quiet")
+	}
+}
+
+func TestRecordOutsidePorcelain(t *testing.T) {
+	// Must not panic or write anywhere
+	Record("status", "success")
+	if Porcelain() || Quiet() {
+		t.Error("porcelain and quiet should be off by default")
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package visualizer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table collects rows and renders them as aligned columns, as labeled
+// lines, one field per line, in accessible mode, or as records in porcelain
+// mode
+type Table struct {
+	headers []string
+	right   []bool
+	rows    [][]string
+	notes   map[int]string
+}
+
+// NewTable creates a table with the given column headers
+func NewTable(headers ...string) *Table {
+	return &Table{headers: headers, right: make([]bool, len(headers)), notes: make(map[int]string)}
+}
+
+// AlignRight right-aligns the given columns, typically numbers
+func (t *Table) AlignRight(cols ...int) *Table {
+	for _, c := range cols {
+		if c >= 0 && c < len(t.right) {
+			t.right[c] = true
+		}
+	}
+	return t
+}
+
+// AddRow appends a row; missing cells are left empty
+func (t *Table) AddRow(cells ...string) {
+	row := make([]string, len(t.headers))
+	copy(row, cells)
+	t.rows = append(t.rows, row)
+}
+
+// AddNote attaches a free-form line, such as an error message, to the last row
+func (t *Table) AddNote(note string) {
+	if len(t.rows) > 0 {
+		t.notes[len(t.rows)-1] = note
+	}
+}
+
+// Len returns the number of rows
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Render writes the table to w. In porcelain mode each row is written as a
+// record instead, without the header or notes.
+func (t *Table) Render(w io.Writer) {
+	if Porcelain() {
+		for _, row := range t.rows {
+			Record(row...)
+		}
+		return
+	}
+	if Accessible() {
+		t.renderLinear(w)
+		return
+	}
+
+	widths := make([]int, len(t.headers))
+	for i, h := range t.headers {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	line := func(cells []string) string {
+		parts := make([]string, len(cells))
+		for i, cell := range cells {
+			pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			switch {
+			case t.right[i]:
+				parts[i] = pad + cell
+			case i == len(cells)-1:
+				parts[i] = cell
+			default:
+				parts[i] = cell + pad
+			}
+		}
+		return strings.TrimRight(strings.Join(parts, "  "), " ")
+	}
+
+	fmt.Fprintln(w, line(t.headers))
+	for i, row := range t.rows {
+		fmt.Fprintln(w, line(row))
+		if note, ok := t.notes[i]; ok {
+			fmt.Fprintf(w, "  %s\n", note)
+		}
+	}
+}
+
+func (t *Table) renderLinear(w io.Writer) {
+	for i, row := range t.rows {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Item %d of %d\n", i+1, len(t.rows))
+		for c, cell := range row {
+			if cell == "" {
+				cell = "none"
+			}
+			fmt.Fprintf(w, "  %s: %s\n", label(t.headers[c]), cell)
+		}
+		if note, ok := t.notes[i]; ok {
+			fmt.Fprintf(w, "  Note: %s\n", note)
+		}
+	}
+}
+
+// acronyms keep their case when headers are turned into labels
+var acronyms = map[string]bool{"URL": true, "ID": true, "XDR": true, "WASM": true}
+
+// label turns an upper-case column header such as "BASE FEE" into "Base fee"
+func label(header string) string {
+	if header == "" {
+		return "Value"
+	}
+	if strings.ToUpper(header) != header || acronyms[header] {
+		return header
+	}
+	lower := strings.ToLower(header)
+	r, size := utf8.DecodeRuneInString(lower)
+	return strings.ToUpper(string(r)) + lower[size:]
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package visualizer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func newTestTable() *Table {
+	table := NewTable("NETWORK", "BASE FEE", "URL").AlignRight(1)
+	table.AddRow("testnet", "100", "https://horizon-testnet.stellar.org")
+	table.AddRow("mainnet", "12345")
+	table.AddNote("connection refused")
+	return table
+}
+
+func TestTableColumns(t *testing.T) {
+	var buf bytes.Buffer
+	newTestTable().Render(&buf)
+
+	want := "NETWORK  BASE FEE  URL\n" +
+		"testnet       100  https://horizon-testnet.stellar.org\n" +
+		"mainnet     12345\n" +
+		"  connection refused\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestTableLinear(t *testing.T) {
+	SetAccessible(true)
+	defer SetAccessible(false)
+
+	var buf bytes.Buffer
+	newTestTable().Render(&buf)
+	out := buf.String()
+
+	for _, line := range []string{
+		"Item 1 of 2",
+		"  Network: testnet",
+		"  Base fee: 100",
+		"  URL: https://horizon-testnet.stellar.org",
+		"  URL: none",
+		"  Note: connection refused",
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "  12345  ") {
+		t.Errorf("accessible output should not be columnar:\n%s", out)
+	}
+}
+
+func TestTablePorcelain(t *testing.T) {
+	var out bytes.Buffer
+	SetPorcelain(&out)
+	defer SetPorcelain(nil)
+
+	var buf bytes.Buffer
+	newTestTable().Render(&buf)
+	if buf.Len() != 0 {
+		t.Errorf("porcelain rows should go to the porcelain writer, got %q", buf.String())
+	}
+	want := "testnet\t100\thttps://horizon-testnet.stellar.org\nmainnet\t12345\t-\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+}
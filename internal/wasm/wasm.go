@@ -0,0 +1,180 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package wasm reads the section layout and primitive encodings of the
+// WebAssembly binary format, for the tools that inspect contract code.
+package wasm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// Section IDs the tools look at
+const (
+	SectionCustom = 0
+	SectionType   = 1
+	SectionImport = 2
+	SectionExport = 7
+	SectionCode   = 10
+	SectionData   = 11
+)
+
+var magic = []byte("\x00asm")
+
+// IsModule reports whether b starts like a WASM module
+func IsModule(b []byte) bool {
+	return bytes.HasPrefix(b, magic)
+}
+
+// Section is one section of a module
+type Section struct {
+	ID byte
+	// Name is the name of a custom section
+	Name string
+	// Data is the section body, after the name for a custom section
+	Data []byte
+	// Offset is the file offset of the section body
+	Offset int
+	// Size is the number of bytes the section takes in the module, header
+	// included
+	Size int
+}
+
+// Sections splits a module into its sections. On a truncated or malformed
+// section it returns the sections before it along with the error.
+func Sections(wasm []byte) ([]Section, error) {
+	if len(wasm) < 8 || !IsModule(wasm) {
+		return nil, errors.New("not a WASM module")
+	}
+	var out []Section
+	r := NewReader(wasm)
+	r.pos = 8
+	for r.More() {
+		start := r.pos
+		id := r.Byte()
+		size := r.U32()
+		offset := r.pos
+		body := r.Bytes(int(size))
+		if r.Err() != nil {
+			return out, errors.New("truncated WASM section")
+		}
+		s := Section{ID: id, Data: body, Offset: offset, Size: r.pos - start}
+		if id == SectionCustom {
+			c := NewReader(body)
+			s.Name = c.Name()
+			if c.Err() != nil {
+				return out, errors.New("malformed WASM custom section")
+			}
+			s.Data = body[c.pos:]
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// CustomSection returns the body of a named custom section, or nil when the
+// module has none
+func CustomSection(wasm []byte, name string) ([]byte, error) {
+	sections, err := Sections(wasm)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sections {
+		if s.ID == SectionCustom && s.Name == name {
+			return s.Data, nil
+		}
+	}
+	return nil, nil
+}
+
+// Reader decodes the primitive encodings of the WASM binary format.
+// The first error sticks and later reads return zero values.
+type Reader struct {
+	b   []byte
+	pos int
+	err error
+}
+
+// NewReader reads b from its start
+func NewReader(b []byte) *Reader {
+	return &Reader{b: b}
+}
+
+// Err returns the first error the reader met
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Fail records msg as the error unless one is already recorded
+func (r *Reader) Fail(msg string) {
+	if r.err == nil {
+		r.err = errors.New(msg)
+	}
+}
+
+// More reports whether there is data left and no error
+func (r *Reader) More() bool {
+	return r.err == nil && r.pos < len(r.b)
+}
+
+// Byte reads one byte
+func (r *Reader) Byte() byte {
+	if r.err != nil || r.pos >= len(r.b) {
+		r.Fail("unexpected end of data")
+		return 0
+	}
+	c := r.b[r.pos]
+	r.pos++
+	return c
+}
+
+// Bytes reads n bytes. n usually comes from a length in the module, so a
+// negative n, from a length that overflowed int, fails like one past the end.
+func (r *Reader) Bytes(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.b)-r.pos {
+		r.Fail("unexpected end of data")
+		return nil
+	}
+	out := r.b[r.pos : r.pos+n]
+	r.pos += n
+	return out
+}
+
+// U32 reads an unsigned LEB128 32-bit integer
+func (r *Reader) U32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.b[r.pos:])
+	if n <= 0 || v > 1<<32-1 {
+		r.Fail("malformed integer")
+		return 0
+	}
+	r.pos += n
+	return uint32(v)
+}
+
+// SkipLEB skips a signed LEB128 value
+func (r *Reader) SkipLEB() {
+	for r.err == nil {
+		if r.Byte()&0x80 == 0 {
+			return
+		}
+	}
+}
+
+// Name reads a length-prefixed UTF-8 name
+func (r *Reader) Name() string {
+	return string(r.Bytes(int(r.U32())))
+}
+
+// SkipLimits skips a minimum and, when the flag says so, a maximum
+func (r *Reader) SkipLimits() {
+	hasMax := r.Byte()&1 != 0
+	r.U32()
+	if hasMax {
+		r.U32()
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package wasm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func module(sections ...[]byte) []byte {
+	out := []byte("\x00asm\x01\x00\x00\x00")
+	for _, s := range sections {
+		out = append(out, s...)
+	}
+	return out
+}
+
+func custom(name, body string) []byte {
+	payload := append([]byte{byte(len(name))}, name...)
+	payload = append(payload, body...)
+	return append([]byte{SectionCustom, byte(len(payload))}, payload...)
+}
+
+func TestSections(t *testing.T) {
+	code := []byte{SectionCode, 2, 0, 0}
+	mod := module(custom("contractspecv0", "spec"), code, custom("name", "xy"))
+
+	sections, err := Sections(mod)
+	require.NoError(t, err)
+	require.Len(t, sections, 3)
+	assert.Equal(t, "contractspecv0", sections[0].Name)
+	assert.Equal(t, []byte("spec"), sections[0].Data)
+	assert.Equal(t, 2+1+len("contractspecv0")+4, sections[0].Size)
+	assert.Equal(t, byte(SectionCode), sections[1].ID)
+	assert.Equal(t, 8+sections[0].Size+2, sections[1].Offset)
+
+	body, err := CustomSection(mod, "name")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("xy"), body)
+	body, err = CustomSection(mod, "producers")
+	require.NoError(t, err)
+	assert.Nil(t, body)
+
+	sections, err = Sections(mod[:len(mod)-1])
+	assert.EqualError(t, err, "truncated WASM section")
+	assert.Len(t, sections, 2, "the sections before the damage are kept")
+
+	_, err = Sections([]byte("not wasm"))
+	assert.Error(t, err)
+	assert.False(t, IsModule([]byte("\x7fELF")))
+}
+
+func TestSectionsHugeNameLength(t *testing.T) {
+	for _, length := range [][]byte{
+		{0xFF, 0xFF, 0xFF, 0xFF, 0x0F},                         // 2^32-1, negative as a 32-bit int
+		{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F}, // past 32 bits
+	} {
+		payload := append(append([]byte{}, length...), 'x')
+		huge := append([]byte{SectionCustom, byte(len(payload))}, payload...)
+		mod := module(custom("name", "xy"), huge, custom("producers", "z"))
+
+		sections, err := Sections(mod)
+		assert.EqualError(t, err, "malformed WASM custom section")
+		require.Len(t, sections, 1)
+		assert.Equal(t, "name", sections[0].Name)
+	}
+}
+
+func TestReader(t *testing.T) {
+	r := NewReader([]byte{0xE5, 0x8E, 0x26, 2, 'h', 'i', 0x01, 1, 2, 0x7F})
+	assert.Equal(t, uint32(624485), r.U32())
+	assert.Equal(t, "hi", r.Name())
+	r.SkipLimits()
+	r.SkipLEB()
+	assert.False(t, r.More())
+	require.NoError(t, r.Err())
+
+	r.Byte()
+	assert.EqualError(t, r.Err(), "unexpected end of data")
+	r.Fail("ignored")
+	assert.EqualError(t, r.Err(), "unexpected end of data", "the first error sticks")
+}
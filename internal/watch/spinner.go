@@ -5,8 +5,13 @@ package watch
 
 import (
 	"fmt"
+	"os"
 	"sync"
 	"time"
+
+	"github.com/dotandev/hintents/internal/platform"
+	"github.com/dotandev/hintents/internal/visualizer"
+	"github.com/mattn/go-isatty"
 )
 
 type Spinner struct {
@@ -15,16 +20,29 @@ type Spinner struct {
 	done      chan struct{}
 	mu        sync.Mutex
 	isRunning bool
+	// animate is false when stdout is not a terminal or erst runs in CI,
+	// where carriage returns would corrupt captured logs, and in quiet mode
+	animate bool
+	quiet   bool
 }
 
 func NewSpinner() *Spinner {
+	quiet := visualizer.Quiet()
 	return &Spinner{
-		frames: []string{"|", "/", "-", "\\"},
-		done:   make(chan struct{}),
+		frames:  []string{"|", "/", "-", "\\"},
+		done:    make(chan struct{}),
+		animate: !quiet && isatty.IsTerminal(os.Stdout.Fd()) && !platform.InCI(),
+		quiet:   quiet,
 	}
 }
 
 func (s *Spinner) Start(message string) {
+	if !s.animate {
+		if !s.quiet {
+			fmt.Println(message)
+		}
+		return
+	}
 	s.mu.Lock()
 	if s.isRunning {
 		s.mu.Unlock()
@@ -71,10 +89,20 @@ func (s *Spinner) Stop() {
 
 func (s *Spinner) StopWithMessage(message string) {
 	s.Stop()
-	fmt.Printf("\r[OK] %s\n", message)
+	if s.quiet {
+		return
+	}
+	fmt.Printf("%s[OK] %s\n", s.lineStart(), message)
 }
 
 func (s *Spinner) StopWithError(message string) {
 	s.Stop()
-	fmt.Printf("\r[ERROR] %s\n", message)
+	fmt.Printf("%s[ERROR] %s\n", s.lineStart(), message)
+}
+
+func (s *Spinner) lineStart() string {
+	if s.animate {
+		return "\r"
+	}
+	return ""
 }
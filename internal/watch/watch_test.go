@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"testing"
 	"time"
+
+	"github.com/dotandev/hintents/internal/visualizer"
 )
 
 func TestNewPollerDefaults(t *testing.T) {
@@ -236,6 +238,21 @@ func TestSpinnerDoubleStart(t *testing.T) {
 	spinner.Stop()
 }
 
+func TestSpinnerQuiet(t *testing.T) {
+	visualizer.SetQuiet(true)
+	defer visualizer.SetQuiet(false)
+
+	spinner := NewSpinner()
+	if spinner.animate || !spinner.quiet {
+		t.Fatal("quiet mode should disable the spinner")
+	}
+	spinner.Start("Testing...")
+	if spinner.isRunning {
+		t.Error("quiet spinner should not start its animation")
+	}
+	spinner.StopWithMessage("done")
+}
+
 func BenchmarkPoller(b *testing.B) {
 	poller := NewPoller(PollerConfig{
 		MaxAttempts:     5,
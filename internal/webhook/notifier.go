@@ -120,7 +120,7 @@ func (sn *SimulatorNotifier) buildReportData(
 		Timestamp:        time.Now(),
 		AuditLogURL:      auditLogURL,
 		DiagnosticEvents: resp.DiagnosticEvents,
-		Logs:             resp.Logs,
+		Logs:             resp.LogStrings(),
 	}
 
 	return report
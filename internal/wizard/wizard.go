@@ -6,11 +6,14 @@ package wizard
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/dotandev/hintents/internal/errors"
 	"github.com/dotandev/hintents/internal/logger"
+	"github.com/dotandev/hintents/internal/platform"
 	"github.com/dotandev/hintents/internal/rpc"
+	"github.com/dotandev/hintents/internal/visualizer"
 )
 
 const defaultLimit = 10
@@ -93,11 +96,15 @@ func selectFromList(txs []rpc.TransactionSummary) *SelectionResult {
 
 func displayTransactions(txs []rpc.TransactionSummary) {
 	fmt.Println("\nRecent Transactions:")
-	fmt.Println("────────────────────────────────────────────────────")
+	visualizer.WriteRule(os.Stdout, "─", 52)
 	for i, tx := range txs {
+		if visualizer.Accessible() {
+			fmt.Printf("Transaction %d: status %s, hash %s, created %s\n", i+1, tx.Status, truncateHash(tx.Hash), tx.CreatedAt)
+			continue
+		}
 		fmt.Printf("[%d] %s | %s | %s\n", i+1, tx.Status, truncateHash(tx.Hash), tx.CreatedAt)
 	}
-	fmt.Println("────────────────────────────────────────────────────")
+	visualizer.WriteRule(os.Stdout, "─", 52)
 }
 
 func truncateHash(hash string) string {
@@ -108,6 +115,9 @@ func truncateHash(hash string) string {
 }
 
 func readUserChoice(max int) (int, error) {
+	if !platform.Interactive() {
+		return 0, fmt.Errorf("the wizard needs an interactive terminal; pass the transaction hash to 'erst debug' instead")
+	}
 	var choice int
 	fmt.Print("Select transaction (number): ")
 	_, err := fmt.Scanln(&choice)
@@ -0,0 +1,148 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package xdrdoc documents the meaning and limits of the XDR fields erst
+// users meet most, from a reference embedded in the binary.
+package xdrdoc
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed reference.yaml
+var referenceYAML []byte
+
+// Field is one documented field of an XDR type
+type Field struct {
+	Name   string `yaml:"name" json:"name"`
+	Type   string `yaml:"type" json:"type"`
+	Doc    string `yaml:"doc" json:"doc"`
+	Limits string `yaml:"limits,omitempty" json:"limits,omitempty"`
+}
+
+// Type is a documented XDR struct or union; union arms are listed as fields
+type Type struct {
+	Name   string  `yaml:"name" json:"name"`
+	Doc    string  `yaml:"doc" json:"doc"`
+	Fields []Field `yaml:"fields" json:"fields"`
+}
+
+// Field returns the field called name, matched like Lookup paths
+func (t *Type) Field(name string) (*Field, bool) {
+	for i := range t.Fields {
+		if normalize(t.Fields[i].Name) == normalize(name) {
+			return &t.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// Entry is the documentation found for a path: a type, or a field of the
+// last type along the path
+type Entry struct {
+	Path  string `json:"path"`
+	Type  *Type  `json:"type"`
+	Field *Field `json:"field,omitempty"`
+}
+
+var (
+	loadOnce sync.Once
+	types    []Type
+	loadErr  error
+)
+
+// Types returns every documented type, by name
+func Types() ([]Type, error) {
+	loadOnce.Do(func() {
+		if loadErr = yaml.Unmarshal(referenceYAML, &types); loadErr != nil {
+			loadErr = fmt.Errorf("failed to parse embedded XDR reference: %w", loadErr)
+			return
+		}
+		sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	})
+	return types, loadErr
+}
+
+// Lookup resolves a dotted path such as
+// SorobanTransactionData.resources.instructions: a type name followed by
+// fields, each field leading into its type. Names are matched ignoring
+// case and underscores, so Go and snake_case spellings work too.
+func Lookup(path string) (*Entry, error) {
+	all, err := Types()
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(strings.TrimSpace(path), ".")
+	t := find(all, parts[0])
+	if t == nil {
+		return nil, notFound(all, parts[0])
+	}
+
+	entry := &Entry{Path: t.Name, Type: t}
+	for i, part := range parts[1:] {
+		if entry.Field != nil {
+			next := find(all, baseType(entry.Field.Type))
+			if next == nil {
+				return nil, fmt.Errorf("%s is a %s, which has no documented fields", entry.Path, entry.Field.Type)
+			}
+			entry.Type, entry.Field = next, nil
+		}
+		f, ok := entry.Type.Field(part)
+		if !ok {
+			return nil, fmt.Errorf("%s has no field %q; fields: %s", strings.Join(parts[:i+1], "."), part, fieldNames(entry.Type))
+		}
+		entry.Field = f
+		entry.Path += "." + f.Name
+	}
+	return entry, nil
+}
+
+func find(all []Type, name string) *Type {
+	for i := range all {
+		if normalize(all[i].Name) == normalize(name) {
+			return &all[i]
+		}
+	}
+	return nil
+}
+
+// notFound suggests the types that have a field called name, for paths
+// that start at a field
+func notFound(all []Type, name string) error {
+	var owners []string
+	for _, t := range all {
+		if f, ok := t.Field(name); ok {
+			owners = append(owners, t.Name+"."+f.Name)
+		}
+	}
+	if len(owners) > 0 {
+		return fmt.Errorf("no documented type %q; did you mean %s?", name, strings.Join(owners, " or "))
+	}
+	return fmt.Errorf("no documented type %q; run 'erst explain xdr-path' to list them", name)
+}
+
+// baseType strips the array and optional markers of a field type
+func baseType(t string) string {
+	if i := strings.IndexAny(t, "<[*"); i >= 0 {
+		return t[:i]
+	}
+	return t
+}
+
+func normalize(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+func fieldNames(t *Type) string {
+	names := make([]string, len(t.Fields))
+	for i, f := range t.Fields {
+		names[i] = f.Name
+	}
+	return strings.Join(names, ", ")
+}
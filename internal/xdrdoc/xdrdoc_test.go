@@ -0,0 +1,94 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+package xdrdoc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stellar/go-stellar-sdk/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goTypes are the SDK types the reference documents, to catch fields that
+// drift from the XDR definitions
+var goTypes = []any{
+	xdr.TransactionEnvelope{}, xdr.TransactionV1Envelope{}, xdr.FeeBumpTransactionEnvelope{},
+	xdr.FeeBumpTransaction{}, xdr.Transaction{}, xdr.TransactionExt{}, xdr.Preconditions{},
+	xdr.PreconditionsV2{}, xdr.TimeBounds{}, xdr.LedgerBounds{}, xdr.Operation{},
+	xdr.InvokeHostFunctionOp{}, xdr.HostFunction{}, xdr.InvokeContractArgs{},
+	xdr.SorobanAuthorizationEntry{}, xdr.SorobanCredentials{}, xdr.SorobanAddressCredentials{},
+	xdr.SorobanAuthorizedInvocation{}, xdr.SorobanTransactionData{}, xdr.SorobanTransactionDataExt{},
+	xdr.SorobanResourcesExtV0{}, xdr.SorobanResources{}, xdr.LedgerFootprint{},
+	xdr.ExtendFootprintTtlOp{}, xdr.RestoreFootprintOp{}, xdr.TransactionResult{},
+	xdr.SorobanTransactionMeta{}, xdr.SorobanTransactionMetaExtV1{}, xdr.LedgerKeyContractData{},
+	xdr.ContractDataEntry{}, xdr.ContractCodeEntry{}, xdr.TtlEntry{},
+}
+
+func TestReferenceMatchesXDR(t *testing.T) {
+	all, err := Types()
+	require.NoError(t, err)
+
+	byName := map[string]reflect.Type{}
+	for _, v := range goTypes {
+		rt := reflect.TypeOf(v)
+		byName[normalize(rt.Name())] = rt
+	}
+	require.Len(t, all, len(goTypes), "every documented type is checked")
+
+	for _, typ := range all {
+		rt, ok := byName[normalize(typ.Name)]
+		if !assert.True(t, ok, "no SDK type for %s", typ.Name) {
+			continue
+		}
+		assert.NotEmpty(t, typ.Doc, typ.Name)
+		for _, f := range typ.Fields {
+			found := false
+			for i := 0; i < rt.NumField(); i++ {
+				if normalize(rt.Field(i).Name) == normalize(f.Name) {
+					found = true
+				}
+			}
+			assert.True(t, found, "%s has no field %s", typ.Name, f.Name)
+			assert.NotEmpty(t, f.Doc, "%s.%s", typ.Name, f.Name)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	e, err := Lookup("SorobanTransactionData.resources.instructions")
+	require.NoError(t, err)
+	assert.Equal(t, "SorobanTransactionData.resources.instructions", e.Path)
+	assert.Equal(t, "SorobanResources", e.Type.Name)
+	assert.Equal(t, "uint32", e.Field.Type)
+	assert.Contains(t, e.Field.Limits, "txMaxInstructions")
+
+	e, err = Lookup("transaction.ext.soroban_data.resource_fee")
+	require.NoError(t, err, "case and underscores are ignored")
+	assert.Equal(t, "Transaction.ext.sorobanData.resourceFee", e.Path)
+
+	e, err = Lookup("PreconditionsV2.timeBounds.maxTime")
+	require.NoError(t, err, "optional fields lead into their type")
+	assert.Equal(t, "maxTime", e.Field.Name)
+
+	e, err = Lookup("LedgerFootprint")
+	require.NoError(t, err)
+	assert.Nil(t, e.Field)
+	assert.Len(t, e.Type.Fields, 2)
+}
+
+func TestLookupErrors(t *testing.T) {
+	_, err := Lookup("resourceFee")
+	assert.ErrorContains(t, err, "did you mean SorobanTransactionData.resourceFee")
+
+	_, err = Lookup("Nope")
+	assert.ErrorContains(t, err, "erst explain xdr-path")
+
+	_, err = Lookup("SorobanResources.cpu")
+	assert.ErrorContains(t, err, "fields: footprint, instructions, diskReadBytes, writeBytes")
+
+	_, err = Lookup("SorobanResources.instructions.value")
+	assert.ErrorContains(t, err, "is a uint32, which has no documented fields")
+}
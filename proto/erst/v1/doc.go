@@ -0,0 +1,8 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Package erstv1 holds the generated protobuf and gRPC code for the erst serve
+// gRPC API
+package erstv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative erst/v1/debug.proto
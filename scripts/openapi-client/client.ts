@@ -0,0 +1,60 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+// Exercises the client generated from docs/openapi.json. It only has to
+// type-check: a renamed field or changed response in the document breaks
+// the build in CI.
+import createClient from "openapi-fetch";
+import type { components, paths } from "./erst-api";
+
+type DebugResult = components["schemas"]["DebugResult"];
+
+const client = createClient<paths>({
+  baseUrl: "http://localhost:8080",
+  headers: { Authorization: "Bearer t0ken" },
+});
+
+export async function debug(hash: string): Promise<DebugResult> {
+  const { data, error } = await client.POST("/api/v1/debug", {
+    body: { hash, network: "testnet" },
+  });
+  if (error) {
+    throw new Error(error.error);
+  }
+  return data;
+}
+
+export async function waitForJob(hash: string): Promise<string> {
+  const created = await client.POST("/api/v1/jobs", { body: { hash } });
+  if (created.error) {
+    throw new Error(created.error.error);
+  }
+  for (;;) {
+    const { data, error } = await client.GET("/api/v1/jobs/{id}", {
+      params: { path: { id: created.data.id } },
+    });
+    if (error) {
+      throw new Error(error.error);
+    }
+    if (data.done) {
+      return data.result?.status ?? data.error ?? "unknown";
+    }
+    await new Promise((resolve) => setTimeout(resolve, 1000));
+  }
+}
+
+export async function sessionsFor(anchor: string): Promise<string[]> {
+  const ids: string[] = [];
+  let cursor: string | undefined;
+  do {
+    const { data, error } = await client.GET("/api/v1/sessions", {
+      params: { query: { anchor, cursor, limit: 100 } },
+    });
+    if (error) {
+      throw new Error(error.error);
+    }
+    ids.push(...data.items.map((s) => s.id));
+    cursor = data.next_cursor ?? undefined;
+  } while (cursor);
+  return ids;
+}
@@ -2,7 +2,7 @@
 // SPDX-License-Identifier: Apache-2.0
 
 use serde::{Deserialize, Serialize};
-use std::collections::HashMap;
+use std::collections::BTreeMap;
 
 // Stellar/Soroban budget limits
 pub const CPU_LIMIT: u64 = 100_000_000; // 100M instructions
@@ -28,7 +28,7 @@ pub struct OptimizationTip {
 pub struct OptimizationReport {
     pub overall_efficiency: f64, // 0-100 score
     pub tips: Vec<OptimizationTip>,
-    pub budget_breakdown: HashMap<String, f64>,
+    pub budget_breakdown: BTreeMap<String, f64>,
     pub comparison_to_baseline: String,
 }
 
@@ -49,7 +49,7 @@ impl GasOptimizationAdvisor {
     /// Analyze budget metrics and generate optimization suggestions
     pub fn analyze(&self, metrics: &BudgetMetrics) -> OptimizationReport {
         let mut tips = Vec::new();
-        let mut budget_breakdown = HashMap::new();
+        let mut budget_breakdown = BTreeMap::new();
 
         let cpu_per_op = if metrics.total_operations > 0 {
             metrics.cpu_instructions / metrics.total_operations as u64
@@ -5,13 +5,15 @@ mod config;
 mod gas_optimizer;
 mod runner;
 mod source_mapper;
+mod step;
+mod storage;
 mod types;
 
 use crate::gas_optimizer::{BudgetMetrics, GasOptimizationAdvisor, CPU_LIMIT, MEMORY_LIMIT};
 use crate::source_mapper::SourceMapper;
 use crate::types::*;
 use base64::Engine;
-use soroban_env_host::xdr::ReadXdr;
+use soroban_env_host::xdr::{ReadXdr, WriteXdr};
 use soroban_env_host::{
     xdr::{HostFunction, Operation, OperationBody, ScVal},
     Host, HostError,
@@ -42,6 +44,15 @@ fn init_logger() {
     }
 }
 
+/// Prints the final response, wrapped as a result event in step mode
+fn emit_response(res: &SimulationResponse) {
+    if step::enabled() {
+        step::emit_result(res);
+    } else {
+        println!("{}", serde_json::to_string(res).unwrap());
+    }
+}
+
 fn send_error(msg: String) {
     let res = SimulationResponse {
         status: "error".to_string(),
@@ -54,13 +65,22 @@ fn send_error(msg: String) {
         optimization_report: None,
         budget_usage: None,
         source_location: None,
+        return_value: None,
+        footprint: None,
+        storage_accesses: vec![],
     };
-    println!("{}", serde_json::to_string(&res).unwrap());
+    emit_response(&res);
     std::process::exit(1);
 }
 
-fn execute_operations(host: &Host, operations: &[Operation]) -> Result<Vec<String>, HostError> {
+/// Runs the operations and returns the logs together with the base64 XDR
+/// return value of the last host function invocation.
+fn execute_operations(
+    host: &Host,
+    operations: &[Operation],
+) -> Result<(Vec<String>, Option<String>), HostError> {
     let mut logs = Vec::new();
+    let mut return_value = None;
     for op in operations {
         match &op.body {
             OperationBody::InvokeHostFunction(invoke_op) => {
@@ -76,6 +96,7 @@ fn execute_operations(host: &Host, operations: &[Operation]) -> Result<Vec<Strin
                 logs.push(format!("Executing InvokeHostFunction..."));
                 let val = host.invoke_function(invoke_op.host_function.clone())?;
                 logs.push(format!("Result: {:?}", val));
+                return_value = val.to_xdr_base64(soroban_env_host::xdr::Limits::none()).ok();
             }
             _ => {
                 logs.push(format!(
@@ -85,7 +106,7 @@ fn execute_operations(host: &Host, operations: &[Operation]) -> Result<Vec<Strin
             }
         }
     }
-    Ok(logs)
+    Ok((logs, return_value))
 }
 
 fn categorize_events(events: &soroban_env_host::events::Events) -> Vec<CategorizedEvent> {
@@ -100,38 +121,41 @@ fn categorize_events(events: &soroban_env_host::events::Events) -> Vec<Categoriz
             }
             .to_string();
 
-            let contract_id = e.event.contract_id.as_ref().map(|id| format!("{:?}", id));
-            let topics = match &e.event.body {
-                soroban_env_host::xdr::ContractEventBody::V0(v0) => {
-                    v0.topics.iter().map(|t| format!("{:?}", t)).collect()
-                }
-            };
-            let data = match &e.event.body {
-                soroban_env_host::xdr::ContractEventBody::V0(v0) => format!("{:?}", v0.data),
-            };
-
             CategorizedEvent {
                 category,
-                event: DiagnosticEvent {
-                    event_type: match e.event.type_ {
-                        soroban_env_host::xdr::ContractEventType::Contract => {
-                            "contract".to_string()
-                        }
-                        soroban_env_host::xdr::ContractEventType::System => "system".to_string(),
-                        soroban_env_host::xdr::ContractEventType::Diagnostic => {
-                            "diagnostic".to_string()
-                        }
-                    },
-                    contract_id,
-                    topics,
-                    data,
-                    in_successful_contract_call: e.failed_call,
-                },
+                event: diagnostic_event(e),
             }
         })
         .collect()
 }
 
+/// Converts a host event. The host flags events emitted by a call that
+/// failed, the opposite of `in_successful_contract_call`.
+fn diagnostic_event(e: &soroban_env_host::events::HostEvent) -> DiagnosticEvent {
+    let event_type = match e.event.type_ {
+        soroban_env_host::xdr::ContractEventType::Contract => "contract",
+        soroban_env_host::xdr::ContractEventType::System => "system",
+        soroban_env_host::xdr::ContractEventType::Diagnostic => "diagnostic",
+    }
+    .to_string();
+
+    let contract_id = e.event.contract_id.as_ref().map(|id| format!("{:?}", id));
+    let (topics, data) = match &e.event.body {
+        soroban_env_host::xdr::ContractEventBody::V0(v0) => (
+            v0.topics.iter().map(|t| format!("{:?}", t)).collect(),
+            format!("{:?}", v0.data),
+        ),
+    };
+
+    DiagnosticEvent {
+        event_type,
+        contract_id,
+        topics,
+        data,
+        in_successful_contract_call: !e.failed_call,
+    }
+}
+
 fn main() {
     // 1. Initialize the logger immediately
     init_logger();
@@ -139,9 +163,18 @@ fn main() {
     // 2. Log that we started
     tracing::info!(event = "simulator_started", "Simulator initializing...");
 
+    // In step mode stdin stays open for commands, so only the first line is
+    // the request
+    step::set_enabled(env::args().any(|a| a == "--step"));
+
     // Read JSON from Stdin
     let mut buffer = String::new();
-    if let Err(e) = std::io::stdin().read_to_string(&mut buffer) {
+    let read = if step::enabled() {
+        step::read_request().map(|line| buffer = line)
+    } else {
+        std::io::stdin().read_to_string(&mut buffer).map(|_| ())
+    };
+    if let Err(e) = read {
         let res = SimulationResponse {
             status: "error".to_string(),
             error: Some(format!("Failed to read stdin: {}", e)),
@@ -153,8 +186,11 @@ fn main() {
             optimization_report: None,
             budget_usage: None,
             source_location: None,
+            return_value: None,
+            footprint: None,
+            storage_accesses: vec![],
         };
-        println!("{}", serde_json::to_string(&res).unwrap());
+        emit_response(&res);
         eprintln!("Failed to read stdin: {}", e);
         return;
     }
@@ -174,8 +210,11 @@ fn main() {
                 optimization_report: None,
                 budget_usage: None,
                 source_location: None,
+                return_value: None,
+                footprint: None,
+                storage_accesses: vec![],
             };
-            println!("{}", serde_json::to_string(&res).unwrap());
+            emit_response(&res);
             return;
         }
     };
@@ -240,16 +279,12 @@ fn main() {
         None
     };
 
-    // Initialize Host
-    let sim_host = runner::SimHost::new(None);
-    let host = sim_host.inner;
-
-    let mut loaded_entries_count = 0;
+    let mut loaded_entries = Vec::new();
 
-    // Populate Host Storage
+    // Collect the ledger state the host loads on demand
     if let Some(entries) = &request.ledger_entries {
         for (key_xdr, entry_xdr) in entries {
-            let _key = match base64::engine::general_purpose::STANDARD.decode(key_xdr) {
+            let key = match base64::engine::general_purpose::STANDARD.decode(key_xdr) {
                 Ok(b) => match soroban_env_host::xdr::LedgerKey::from_xdr(
                     b,
                     soroban_env_host::xdr::Limits::none(),
@@ -260,7 +295,7 @@ fn main() {
                 Err(e) => return send_error(format!("Failed to decode LedgerKey Base64: {}", e)),
             };
 
-            let _entry = match base64::engine::general_purpose::STANDARD.decode(entry_xdr) {
+            let entry = match base64::engine::general_purpose::STANDARD.decode(entry_xdr) {
                 Ok(b) => match soroban_env_host::xdr::LedgerEntry::from_xdr(
                     b,
                     soroban_env_host::xdr::Limits::none(),
@@ -270,9 +305,16 @@ fn main() {
                 },
                 Err(e) => return send_error(format!("Failed to decode LedgerEntry Base64: {}", e)),
             };
-            loaded_entries_count += 1;
+            loaded_entries.push((key, entry));
         }
     }
+    let loaded_entries_count = loaded_entries.len();
+
+    // Initialize Host
+    let sim_host = runner::SimHost::recording(std::rc::Rc::new(storage::LedgerSnapshot::new(
+        loaded_entries,
+    )));
+    let host = sim_host.inner;
 
     // Extract Operations and Simulate
     let operations = match &envelope {
@@ -283,6 +325,14 @@ fn main() {
         },
     };
 
+    if step::enabled() {
+        if let Err(e) = step::install(&host) {
+            return send_error(format!("Failed to enable step mode: {:?}", e));
+        }
+    } else if let Err(e) = storage::install(&host) {
+        return send_error(format!("Failed to record storage accesses: {:?}", e));
+    }
+
     // Wrap the operation execution in panic protection
     let result = std::panic::catch_unwind(std::panic::AssertUnwindSafe(|| {
         execute_operations(&host, operations)
@@ -336,53 +386,15 @@ fn main() {
     }
 
     match result {
-        Ok(Ok(exec_logs)) => {
+        Ok(Ok((exec_logs, return_value))) => {
             // Extract both raw event strings and structured diagnostic events
             let (events, diagnostic_events): (Vec<String>, Vec<DiagnosticEvent>) =
                 match host.get_events() {
                     Ok(evs) => {
                         let raw_events: Vec<String> =
                             evs.0.iter().map(|e| format!("{:?}", e)).collect();
-                        let diag_events: Vec<DiagnosticEvent> = evs
-                            .0
-                            .iter()
-                            .map(|event| {
-                                let event_type = match &event.event.type_ {
-                                    soroban_env_host::xdr::ContractEventType::Contract => {
-                                        "contract".to_string()
-                                    }
-                                    soroban_env_host::xdr::ContractEventType::System => {
-                                        "system".to_string()
-                                    }
-                                    soroban_env_host::xdr::ContractEventType::Diagnostic => {
-                                        "diagnostic".to_string()
-                                    }
-                                };
-
-                                let contract_id = event
-                                    .event
-                                    .contract_id
-                                    .as_ref()
-                                    .map(|contract_id| format!("{:?}", contract_id));
-
-                                let (topics, data) = match &event.event.body {
-                                    soroban_env_host::xdr::ContractEventBody::V0(v0) => {
-                                        let topics: Vec<String> =
-                                            v0.topics.iter().map(|t| format!("{:?}", t)).collect();
-                                        let data = format!("{:?}", v0.data);
-                                        (topics, data)
-                                    }
-                                };
-
-                                DiagnosticEvent {
-                                    event_type,
-                                    contract_id,
-                                    topics,
-                                    data,
-                                    in_successful_contract_call: event.failed_call,
-                                }
-                            })
-                            .collect();
+                        let diag_events: Vec<DiagnosticEvent> =
+                            evs.0.iter().map(diagnostic_event).collect();
                         (raw_events, diag_events)
                     }
                     Err(_) => (
@@ -419,9 +431,12 @@ fn main() {
                 optimization_report,
                 budget_usage: Some(budget_usage),
                 source_location: None,
+                return_value,
+                footprint: Some(storage::footprint(&host)),
+                storage_accesses: storage::take_accesses(),
             };
 
-            println!("{}", serde_json::to_string(&response).unwrap());
+            emit_response(&response);
         }
         Ok(Err(host_error)) => {
             // Host error during execution (e.g., contract trap, validation failure)
@@ -445,8 +460,11 @@ fn main() {
                 optimization_report: None,
                 budget_usage: None,
                 source_location: None,
+                return_value: None,
+                footprint: Some(storage::footprint(&host)),
+                storage_accesses: storage::take_accesses(),
             };
-            println!("{}", serde_json::to_string(&response).unwrap());
+            emit_response(&response);
         }
         Err(panic_info) => {
             let panic_msg = if let Some(s) = panic_info.downcast_ref::<&str>() {
@@ -468,8 +486,11 @@ fn main() {
                 optimization_report: None,
                 budget_usage: None,
                 source_location: None,
+                return_value: None,
+                footprint: None,
+                storage_accesses: vec![],
             };
-            println!("{}", serde_json::to_string(&response).unwrap());
+            emit_response(&response);
         }
     }
 }
@@ -478,6 +499,30 @@ fn main() {
 mod tests {
     use super::*;
 
+    #[test]
+    fn test_diagnostic_event_marks_successful_calls() {
+        use soroban_env_host::events::HostEvent;
+        use soroban_env_host::xdr::{
+            ContractEvent, ContractEventBody, ContractEventType, ContractEventV0, ExtensionPoint,
+        };
+
+        let event = |failed_call| HostEvent {
+            event: ContractEvent {
+                ext: ExtensionPoint::V0,
+                contract_id: None,
+                type_: ContractEventType::Contract,
+                body: ContractEventBody::V0(ContractEventV0 {
+                    topics: Default::default(),
+                    data: ScVal::Void,
+                }),
+            },
+            failed_call,
+        };
+
+        assert!(diagnostic_event(&event(false)).in_successful_contract_call);
+        assert!(!diagnostic_event(&event(true)).in_successful_contract_call);
+    }
+
     #[test]
     fn test_decode_vm_traps() {
         let msg = decode_error("Error: Wasm Trap: out of bounds memory access");
@@ -3,10 +3,11 @@
 
 use soroban_env_host::{
     budget::Budget,
-    storage::Storage,
+    storage::{SnapshotSource, Storage},
     xdr::{Hash, ScErrorCode, ScErrorType},
     DiagnosticLevel, Error as EnvError, Host, HostError, TryIntoVal, Val,
 };
+use std::rc::Rc;
 
 #[allow(dead_code)]
 /// Wrapper around the Soroban Host to manage initialization and execution context.
@@ -27,7 +28,20 @@ impl SimHost {
         }
 
         // Host::with_storage_and_budget is available in recent versions
-        let host = Host::with_storage_and_budget(Storage::default(), budget);
+        Self::with_storage(Storage::default(), budget)
+    }
+
+    /// Initialize a Host that loads entries from snapshot as it needs them
+    /// and records every key it accesses in its footprint
+    pub fn recording(snapshot: Rc<dyn SnapshotSource>) -> Self {
+        Self::with_storage(
+            Storage::with_recording_footprint(snapshot),
+            Budget::default(),
+        )
+    }
+
+    fn with_storage(storage: Storage, budget: Budget) -> Self {
+        let host = Host::with_storage_and_budget(storage, budget);
 
         // Enable debug mode for better diagnostics
         host.set_diagnostic_level(DiagnosticLevel::Debug)
@@ -0,0 +1,268 @@
+// Copyright 2025 Erst Users
+// SPDX-License-Identifier: Apache-2.0
+
+//! Step mode: pause at every contract call boundary and wait for a command
+//! from the Go side before resuming.
+//!
+//! Protocol (newline-delimited JSON):
+//! - stdin, first line: the `SimulationRequest`
+//! - stdin, afterwards: `{"cmd":"step"|"continue"|"inspect"|"abort"}`
+//! - stdout: `{"type":"paused",...}`, `{"type":"state",...}` and finally
+//!   `{"type":"result","response":{...}}`
+
+use crate::gas_optimizer::{CPU_LIMIT, MEMORY_LIMIT};
+use crate::types::{BudgetUsage, SimulationResponse};
+use serde::{Deserialize, Serialize};
+use soroban_env_host::host::TraceEvent;
+use soroban_env_host::xdr::{LedgerEntryData, LedgerKey};
+use soroban_env_host::{Host, HostError};
+use std::cell::{Cell, RefCell};
+use std::io::{BufRead, Write};
+use std::rc::Rc;
+use std::sync::atomic::{AtomicBool, Ordering};
+
+static STEP_MODE: AtomicBool = AtomicBool::new(false);
+
+/// Returns true when the simulator was started with `--step`
+pub fn enabled() -> bool {
+    STEP_MODE.load(Ordering::Relaxed)
+}
+
+pub fn set_enabled(on: bool) {
+    STEP_MODE.store(on, Ordering::Relaxed);
+}
+
+#[derive(Debug, Deserialize)]
+struct Command {
+    cmd: String,
+}
+
+#[derive(Debug, Clone, Serialize)]
+pub struct Frame {
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub contract: Option<String>,
+    pub function: String,
+    pub depth: usize,
+}
+
+#[derive(Debug, Serialize)]
+pub struct StorageEntry {
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub contract: Option<String>,
+    pub key: String,
+    pub value: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub durability: Option<String>,
+}
+
+#[derive(Debug, Serialize)]
+struct Event<'a> {
+    #[serde(rename = "type")]
+    kind: &'a str,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    reason: Option<&'a str>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    frame: Option<Frame>,
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    stack: Vec<Frame>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    budget: Option<BudgetUsage>,
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    storage: Vec<StorageEntry>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    response: Option<&'a SimulationResponse>,
+}
+
+impl<'a> Event<'a> {
+    fn new(kind: &'a str) -> Self {
+        Self {
+            kind,
+            reason: None,
+            frame: None,
+            stack: vec![],
+            budget: None,
+            storage: vec![],
+            response: None,
+        }
+    }
+}
+
+fn emit(ev: &Event) {
+    let mut out = std::io::stdout().lock();
+    let _ = writeln!(out, "{}", serde_json::to_string(ev).unwrap());
+    let _ = out.flush();
+}
+
+/// Reads the request line that starts a step session
+pub fn read_request() -> std::io::Result<String> {
+    let mut line = String::new();
+    std::io::stdin().lock().read_line(&mut line)?;
+    Ok(line)
+}
+
+/// Writes the final response wrapped as a `result` event
+pub fn emit_result(res: &SimulationResponse) {
+    let mut ev = Event::new("result");
+    ev.response = Some(res);
+    emit(&ev);
+}
+
+struct Stepper {
+    stack: RefCell<Vec<Frame>>,
+    running_free: Cell<bool>,
+}
+
+impl Stepper {
+    /// Blocks until the Go side resumes execution
+    fn pause(&self, host: &Host, reason: &str, frame: Option<Frame>) {
+        if self.running_free.get() {
+            return;
+        }
+
+        let mut ev = Event::new("paused");
+        ev.reason = Some(reason);
+        ev.frame = frame;
+        ev.stack = self.stack.borrow().clone();
+        emit(&ev);
+
+        let stdin = std::io::stdin();
+        for line in stdin.lock().lines() {
+            let Ok(line) = line else { break };
+            let cmd = match serde_json::from_str::<Command>(&line) {
+                Ok(c) => c.cmd,
+                Err(_) => continue,
+            };
+            match cmd.as_str() {
+                "step" => return,
+                "continue" => {
+                    self.running_free.set(true);
+                    return;
+                }
+                "inspect" => {
+                    let mut state = Event::new("state");
+                    state.budget = Some(budget_usage(host));
+                    state.storage = storage_entries(host);
+                    emit(&state);
+                }
+                _ => break,
+            }
+        }
+
+        // "abort" or a closed pipe ends the session
+        std::process::exit(0);
+    }
+}
+
+/// Installs the trace hook that pauses on contract frame push/pop and emits
+/// the initial `start` pause
+pub fn install(host: &Host) -> Result<(), HostError> {
+    let stepper = Rc::new(Stepper {
+        stack: RefCell::new(vec![]),
+        running_free: Cell::new(false),
+    });
+
+    let hook_state = stepper.clone();
+    host.set_trace_hook(Some(Rc::new(move |host: &Host, ev: TraceEvent<'_>| {
+        crate::storage::observe(host, &ev);
+        match ev {
+            TraceEvent::PushCtx(_) => {
+                let mut frame = current_call(host);
+                frame.depth = hook_state.stack.borrow().len() + 1;
+                hook_state.stack.borrow_mut().push(frame.clone());
+                hook_state.pause(host, "call", Some(frame));
+            }
+            TraceEvent::PopCtx(_, _) => {
+                let frame = hook_state.stack.borrow().last().cloned();
+                hook_state.pause(host, "return", frame);
+                hook_state.stack.borrow_mut().pop();
+            }
+            _ => {}
+        }
+        Ok(())
+    })))?;
+
+    stepper.pause(host, "start", None);
+    Ok(())
+}
+
+/// Identifies the call being entered from the most recent fn_call
+/// diagnostic event, which the host records just before pushing the frame
+fn current_call(host: &Host) -> Frame {
+    let mut frame = Frame {
+        contract: None,
+        function: "<unknown>".to_string(),
+        depth: 0,
+    };
+    let Ok(events) = host.get_events() else {
+        return frame;
+    };
+    for e in events.0.iter().rev() {
+        let soroban_env_host::xdr::ContractEventBody::V0(v0) = &e.event.body;
+        let topics: Vec<String> = v0.topics.iter().map(|t| format!("{:?}", t)).collect();
+        if topics.len() >= 3 && topics[0].contains("fn_call") {
+            frame.function = innermost(&topics[2]);
+            frame.contract = e.event.contract_id.as_ref().map(|id| format!("{:?}", id));
+            break;
+        }
+    }
+    frame
+}
+
+/// Extracts `transfer` from `Symbol(ScSymbol(StringM(transfer)))`
+fn innermost(debug: &str) -> String {
+    let trimmed = debug.trim_end_matches(')');
+    trimmed.rsplit('(').next().unwrap_or(trimmed).to_string()
+}
+
+fn budget_usage(host: &Host) -> BudgetUsage {
+    let budget = host.budget_cloned();
+    let cpu = budget.get_cpu_insns_consumed().unwrap_or(0);
+    let mem = budget.get_mem_bytes_consumed().unwrap_or(0);
+    BudgetUsage {
+        cpu_instructions: cpu,
+        memory_bytes: mem,
+        operations_count: 0,
+        cpu_limit: CPU_LIMIT,
+        memory_limit: MEMORY_LIMIT,
+        cpu_usage_percent: (cpu as f64 / CPU_LIMIT as f64) * 100.0,
+        memory_usage_percent: (mem as f64 / MEMORY_LIMIT as f64) * 100.0,
+    }
+}
+
+fn storage_entries(host: &Host) -> Vec<StorageEntry> {
+    let budget = host.budget_cloned();
+    let mut out = vec![];
+    let _ = host.with_mut_storage(|storage| {
+        for (key, value) in storage.map.iter(&budget)? {
+            let LedgerKey::ContractData(cd) = key.as_ref() else {
+                continue;
+            };
+            let value = match value {
+                Some((entry, _)) => match &entry.data {
+                    LedgerEntryData::ContractData(d) => format!("{:?}", d.val),
+                    other => format!("{:?}", other),
+                },
+                None => "<deleted>".to_string(),
+            };
+            out.push(StorageEntry {
+                contract: Some(format!("{:?}", cd.contract)),
+                key: format!("{:?}", cd.key),
+                value,
+                durability: Some(format!("{:?}", cd.durability)),
+            });
+        }
+        Ok(())
+    });
+    out
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_innermost() {
+        assert_eq!(innermost("Symbol(ScSymbol(StringM(transfer)))"), "transfer");
+        assert_eq!(innermost("transfer"), "transfer");
+    }
+}
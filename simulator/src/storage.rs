@@ -1,14 +1,162 @@
 // Copyright 2025 Erst Users
 // SPDX-License-Identifier: Apache-2.0
 
-use std::collections::HashMap;
-use soroban_env_host::xdr::{LedgerEntry, LedgerEntryChange};
+use crate::types::{Footprint, StorageAccess};
+use soroban_env_host::host::TraceEvent;
+use soroban_env_host::storage::{AccessType, EntryWithLiveUntil, SnapshotSource};
+use soroban_env_host::xdr::{
+    LedgerEntry, LedgerEntryChange, LedgerEntryData, LedgerKey, Limits, WriteXdr,
+};
+use soroban_env_host::{Host, HostError};
+use std::cell::RefCell;
+use std::collections::BTreeMap;
+use std::rc::Rc;
 
-fn merge_storage_state(
-    before: &[LedgerEntry],
-    changes: &[LedgerEntryChange],
-) -> Vec<LedgerEntry> {
-    let mut state: HashMap<String, LedgerEntry> = HashMap::new();
+/// The ledger entries of the request, served to a recording host on demand
+/// so the footprint ends up holding exactly what execution accessed
+pub struct LedgerSnapshot {
+    entries: BTreeMap<String, EntryWithLiveUntil>,
+}
+
+impl LedgerSnapshot {
+    /// Indexes entries by their base64 key. The request does not carry TTLs
+    /// reliably, so contract data and code are treated as live.
+    pub fn new(entries: Vec<(LedgerKey, LedgerEntry)>) -> Self {
+        let mut indexed = BTreeMap::new();
+        for (key, entry) in entries {
+            let live_until = match key {
+                LedgerKey::ContractData(_) | LedgerKey::ContractCode(_) => Some(u32::MAX),
+                LedgerKey::Ttl(_) => continue,
+                _ => None,
+            };
+            if let Ok(encoded) = key.to_xdr_base64(Limits::none()) {
+                indexed.insert(encoded, (Rc::new(entry), live_until));
+            }
+        }
+        Self { entries: indexed }
+    }
+}
+
+impl SnapshotSource for LedgerSnapshot {
+    fn get(&self, key: &Rc<LedgerKey>) -> Result<Option<EntryWithLiveUntil>, HostError> {
+        let Ok(encoded) = key.to_xdr_base64(Limits::none()) else {
+            return Ok(None);
+        };
+        Ok(self.entries.get(&encoded).cloned())
+    }
+}
+
+/// Returns the ledger keys the host accessed, as base64 XDR, split by the
+/// access it needed
+pub fn footprint(host: &Host) -> Footprint {
+    let budget = host.budget_cloned();
+    let mut fp = Footprint::default();
+    let _ = host.with_mut_storage(|storage| {
+        for (key, access) in storage.footprint.0.iter(&budget)? {
+            let Ok(encoded) = key.to_xdr_base64(Limits::none()) else {
+                continue;
+            };
+            match access {
+                AccessType::ReadOnly => fp.read_only.push(encoded),
+                AccessType::ReadWrite => fp.read_write.push(encoded),
+            }
+        }
+        Ok(())
+    });
+    fp
+}
+
+thread_local! {
+    static RECORDER: RefCell<Recorder> = RefCell::new(Recorder::default());
+}
+
+#[derive(Default)]
+struct Recorder {
+    /// The value of each contract data key after its last recorded access,
+    /// None when it does not exist
+    seen: BTreeMap<String, Option<String>>,
+    accesses: Vec<StorageAccess>,
+}
+
+/// Installs a trace hook that only records storage accesses. Step mode
+/// installs its own hook, which calls `observe` too.
+pub fn install(host: &Host) -> Result<(), HostError> {
+    host.set_trace_hook(Some(Rc::new(|host: &Host, ev: TraceEvent<'_>| {
+        observe(host, &ev);
+        Ok(())
+    })))
+}
+
+/// Records the contract data entry a storage host function touched once it
+/// returns: the first access to each key, then every change to its value.
+/// Repeated reads of a key already recorded are not repeated.
+pub fn observe(host: &Host, ev: &TraceEvent<'_>) {
+    let TraceEvent::EnvRet(name, _) = ev else {
+        return;
+    };
+    let op = match *name {
+        "put_contract_data" => "write",
+        "del_contract_data" => "delete",
+        "get_contract_data" | "has_contract_data" => "read",
+        _ => return,
+    };
+    let current = contract_data(host);
+    RECORDER.with(|r| r.borrow_mut().record(op, current));
+}
+
+impl Recorder {
+    /// Compares the contract data the host holds after an op call with what
+    /// was recorded before
+    fn record(&mut self, op: &str, current: Vec<(String, Option<String>)>) {
+        for (key_xdr, value) in current {
+            let op = match self.seen.get(&key_xdr) {
+                None => op,
+                Some(prev) if *prev == value => continue,
+                Some(_) if value.is_none() => "delete",
+                Some(_) => "write",
+            };
+            self.accesses.push(StorageAccess {
+                op: op.to_string(),
+                key_xdr: key_xdr.clone(),
+                value: value.clone(),
+            });
+            self.seen.insert(key_xdr, value);
+        }
+    }
+}
+
+/// Returns the recorded accesses in execution order
+pub fn take_accesses() -> Vec<StorageAccess> {
+    RECORDER.with(|r| std::mem::take(&mut r.borrow_mut().accesses))
+}
+
+/// Returns every contract data key the host holds, as base64 XDR, with its
+/// base64 XDR value
+fn contract_data(host: &Host) -> Vec<(String, Option<String>)> {
+    let budget = host.budget_cloned();
+    let mut out = vec![];
+    let _ = host.with_mut_storage(|storage| {
+        for (key, value) in storage.map.iter(&budget)? {
+            if !matches!(key.as_ref(), LedgerKey::ContractData(_)) {
+                continue;
+            }
+            let Ok(key_xdr) = key.to_xdr_base64(Limits::none()) else {
+                continue;
+            };
+            let value = value.as_ref().and_then(|(entry, _)| match &entry.data {
+                LedgerEntryData::ContractData(d) => d.val.to_xdr_base64(Limits::none()).ok(),
+                _ => None,
+            });
+            out.push((key_xdr, value));
+        }
+        Ok(())
+    });
+    out
+}
+
+#[allow(dead_code)]
+fn merge_storage_state(before: &[LedgerEntry], changes: &[LedgerEntryChange]) -> Vec<LedgerEntry> {
+    let mut state: BTreeMap<String, LedgerEntry> = BTreeMap::new();
 
     // Load BEFORE state
     for entry in before {
@@ -18,8 +166,7 @@ fn merge_storage_state(
     // Apply ResultMeta changes
     for change in changes {
         match change {
-            LedgerEntryChange::Created(e)
-            | LedgerEntryChange::Updated(e) => {
+            LedgerEntryChange::Created(e) | LedgerEntryChange::Updated(e) => {
                 state.insert(format!("{:?}", e.data), e.clone());
             }
             LedgerEntryChange::Removed(key) => {
@@ -31,3 +178,79 @@ fn merge_storage_state(
 
     state.into_values().collect()
 }
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use soroban_env_host::xdr::{
+        ContractCodeEntry, ContractCodeEntryExt, Hash, LedgerEntryData, LedgerEntryExt,
+        LedgerKeyContractCode, LedgerKeyTtl, TtlEntry,
+    };
+
+    fn code(b: u8) -> (LedgerKey, LedgerEntry) {
+        let hash = Hash([b; 32]);
+        let key = LedgerKey::ContractCode(LedgerKeyContractCode { hash: hash.clone() });
+        let entry = LedgerEntry {
+            last_modified_ledger_seq: 1,
+            data: LedgerEntryData::ContractCode(ContractCodeEntry {
+                ext: ContractCodeEntryExt::V0,
+                hash,
+                code: vec![0u8].try_into().unwrap(),
+            }),
+            ext: LedgerEntryExt::V0,
+        };
+        (key, entry)
+    }
+
+    #[test]
+    fn test_recorder_orders_accesses() {
+        let mut r = Recorder::default();
+        let v = |s: &str| Some(s.to_string());
+
+        r.record("read", vec![("a".into(), v("1"))]);
+        // A repeated read of a, then b written for the first time
+        r.record("read", vec![("a".into(), v("1"))]);
+        r.record("write", vec![("a".into(), v("1")), ("b".into(), v("2"))]);
+        r.record("write", vec![("a".into(), v("3")), ("b".into(), v("2"))]);
+        r.record("delete", vec![("a".into(), None), ("b".into(), v("2"))]);
+
+        let got: Vec<(&str, &str, Option<&str>)> = r
+            .accesses
+            .iter()
+            .map(|a| (a.op.as_str(), a.key_xdr.as_str(), a.value.as_deref()))
+            .collect();
+        assert_eq!(
+            got,
+            vec![
+                ("read", "a", Some("1")),
+                ("write", "b", Some("2")),
+                ("write", "a", Some("3")),
+                ("delete", "a", None),
+            ]
+        );
+    }
+
+    #[test]
+    fn test_snapshot_serves_request_entries() {
+        let (key, entry) = code(1);
+        let ttl_key = LedgerKey::Ttl(LedgerKeyTtl {
+            key_hash: Hash([1; 32]),
+        });
+        let ttl = LedgerEntry {
+            last_modified_ledger_seq: 1,
+            data: LedgerEntryData::Ttl(TtlEntry {
+                key_hash: Hash([1; 32]),
+                live_until_ledger_seq: 100,
+            }),
+            ext: LedgerEntryExt::V0,
+        };
+        let snapshot =
+            LedgerSnapshot::new(vec![(key.clone(), entry.clone()), (ttl_key.clone(), ttl)]);
+
+        let (found, live_until) = snapshot.get(&Rc::new(key)).unwrap().unwrap();
+        assert_eq!(*found, entry);
+        assert_eq!(live_until, Some(u32::MAX));
+        assert!(snapshot.get(&Rc::new(code(2).0)).unwrap().is_none());
+        assert!(snapshot.get(&Rc::new(ttl_key)).unwrap().is_none());
+    }
+}
@@ -3,13 +3,13 @@
 
 use crate::gas_optimizer::OptimizationReport;
 use serde::{Deserialize, Serialize};
-use std::collections::HashMap;
+use std::collections::BTreeMap;
 
 #[derive(Debug, Deserialize)]
 pub struct SimulationRequest {
     pub envelope_xdr: String,
     pub result_meta_xdr: String,
-    pub ledger_entries: Option<HashMap<String, String>>,
+    pub ledger_entries: Option<BTreeMap<String, String>>,
     pub contract_wasm: Option<String>,
     pub enable_optimization_advisor: bool,
     pub profile: Option<bool>,
@@ -29,6 +29,34 @@ pub struct SimulationResponse {
     pub budget_usage: Option<BudgetUsage>,
     #[serde(skip_serializing_if = "Option::is_none")]
     pub source_location: Option<String>,
+    /// Base64 XDR ScVal returned by the last host function invocation
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub return_value: Option<String>,
+    /// Ledger keys execution accessed, also when it failed
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub footprint: Option<Footprint>,
+    /// Contract data accesses in the order execution made them
+    #[serde(skip_serializing_if = "Vec::is_empty")]
+    pub storage_accesses: Vec<StorageAccess>,
+}
+
+/// A contract data access observed while executing
+#[derive(Debug, Serialize)]
+pub struct StorageAccess {
+    /// read, write or delete
+    pub op: String,
+    /// Base64 XDR ledger key
+    pub key_xdr: String,
+    /// Base64 XDR ScVal after the access, absent when the entry does not exist
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub value: Option<String>,
+}
+
+/// Ledger keys as base64 XDR, split by the access the host needed
+#[derive(Debug, Default, Serialize)]
+pub struct Footprint {
+    pub read_only: Vec<String>,
+    pub read_write: Vec<String>,
 }
 
 #[derive(Debug, Serialize)]